@@ -0,0 +1,119 @@
+package gopi
+
+import (
+	"context"
+	"fmt"
+)
+
+/*
+	This file contains definitions for role-based access control, which
+	is shared by the HTTP server, gRPC server and remote shell:
+
+	* Roles (viewer, operator, admin) for identities
+	* Roles required for unit methods, gRPC methods and HTTP routes
+	* Identities of callers carried in a context
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	AccessRole uint // AccessRole is what an identity can do
+)
+
+// AccessIdentity is an authenticated caller, which is named by a session,
+// certificate common name or user name. The role is set by the
+// authenticator, or is ACCESS_ROLE_NONE when it is set by the policy
+type AccessIdentity struct {
+	Name string
+	Role AccessRole
+}
+
+// AccessAuthenticator returns the identity for a bearer token, or
+// ErrNotFound when the token is not recognised
+type AccessAuthenticator func(string) (AccessIdentity, error)
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// AccessControl authorizes calls to resources from a policy. A resource
+// is a unit method "Unit.Method", a gRPC method "/package.Service/Method"
+// or an HTTP route "GET /path"
+type AccessControl interface {
+	// Role returns the role for an identity
+	Role(AccessIdentity) AccessRole
+
+	// Required returns the role required for a resource
+	Required(string) AccessRole
+
+	// Authorize returns ErrPermissionDenied when the identity in the
+	// context does not have the role required for a resource
+	Authorize(context.Context, string) error
+
+	// Authenticate returns a context with the identity for a bearer token
+	Authenticate(context.Context, string) (context.Context, error)
+
+	// RegisterAuthenticator adds a function which returns identities
+	// for bearer tokens
+	RegisterAuthenticator(AccessAuthenticator) error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+type accessIdentityKey struct{}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	ACCESS_ROLE_NONE AccessRole = iota
+	ACCESS_ROLE_VIEWER
+	ACCESS_ROLE_OPERATOR
+	ACCESS_ROLE_ADMIN
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// WithAccessIdentity returns a context with the identity of a caller
+func WithAccessIdentity(ctx context.Context, identity AccessIdentity) context.Context {
+	return context.WithValue(ctx, accessIdentityKey{}, identity)
+}
+
+// AccessIdentityFromContext returns the identity of a caller, or false
+// when the caller is anonymous
+func AccessIdentityFromContext(ctx context.Context) (AccessIdentity, bool) {
+	if ctx == nil {
+		return AccessIdentity{}, false
+	}
+	identity, ok := ctx.Value(accessIdentityKey{}).(AccessIdentity)
+	return identity, ok
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (r AccessRole) String() string {
+	switch r {
+	case ACCESS_ROLE_NONE:
+		return "ACCESS_ROLE_NONE"
+	case ACCESS_ROLE_VIEWER:
+		return "ACCESS_ROLE_VIEWER"
+	case ACCESS_ROLE_OPERATOR:
+		return "ACCESS_ROLE_OPERATOR"
+	case ACCESS_ROLE_ADMIN:
+		return "ACCESS_ROLE_ADMIN"
+	default:
+		return "[?? Invalid AccessRole value]"
+	}
+}
+
+func (i AccessIdentity) String() string {
+	str := "<identity"
+	str += fmt.Sprintf(" name=%q", i.Name)
+	if i.Role != ACCESS_ROLE_NONE {
+		str += fmt.Sprint(" role=", i.Role)
+	}
+	return str + ">"
+}
@@ -0,0 +1,377 @@
+package gopi
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+/*
+	This file contains interface definitions for appliances which are
+	built on other units:
+
+	* Irrigation controller with zone schedules and rain delay
+	* Door and gate controller with position sensing and safety timers
+	* Entry control with RFID cards, PIN codes and a door strike
+	* Doorbell with a camera snapshot, chime and two-way audio
+	* Soil moisture probes which water irrigation zones when dry
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	IrrigationEventType uint
+	DoorState           uint
+	DoorEventType       uint
+	EntryEventType      uint
+	DoorbellEventType   uint
+	SoilEventType       uint
+)
+
+// IrrigationZone is the state of a watering zone
+type IrrigationZone struct {
+	Name     string
+	Running  bool
+	Queued   bool
+	Until    time.Time     // Time watering stops, when running
+	Last     time.Time     // Time watering last started
+	Next     time.Time     // Next scheduled time, or zero
+	Duration time.Duration // Duration of the next scheduled watering
+}
+
+// SoilProbe is the last reading of a soil moisture probe
+type SoilProbe struct {
+	Name     string
+	Zone     string // Irrigation zone watered when the soil is dry, or empty
+	Time     time.Time
+	Voltage  float32 // Voltage from the probe
+	Moisture float32 // Percent between the dry and wet calibration points
+	Low      bool    // Moisture is below the threshold for the probe
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// Irrigation waters zones on schedules or on demand, with each zone
+// switched by a relay channel. Zones are watered one at a time
+type Irrigation interface {
+	// Zones returns the state of all zones
+	Zones() []IrrigationZone
+
+	// Water queues a zone to be watered for a duration, or for the
+	// scheduled duration when zero
+	Water(string, time.Duration) error
+
+	// Stop stops watering a zone and removes it from the queue, or
+	// stops all zones when the name is empty
+	Stop(string) error
+
+	// RainDelay returns the time until which scheduled watering is
+	// suspended, or zero
+	RainDelay() time.Time
+
+	// SetRainDelay suspends scheduled watering until a time, or
+	// resumes scheduled watering when the time is zero
+	SetRainDelay(time.Time)
+}
+
+// SoilMoisture reads capacitive soil moisture probes through gopi.ADC
+// channels, where each probe is calibrated with its voltage in dry and
+// wet soil
+type SoilMoisture interface {
+	// Probes returns the last reading of each probe
+	Probes() []SoilProbe
+
+	// Calibrate reads a probe and stores the voltage as the calibration
+	// point for dry soil, or for wet soil when true
+	Calibrate(context.Context, string, bool) error
+}
+
+// Door opens and closes a garage door or gate by pulsing a relay, and
+// senses when it is fully open or closed with reed switches
+type Door interface {
+	// State returns the state of the door and when it last changed
+	State() (DoorState, time.Time)
+
+	// Open pulses the relay to open the door, unless it is open or
+	// opening. Returns ErrOutOfOrder when the door is closing
+	Open(context.Context) error
+
+	// Close pulses the relay to close the door, unless it is closed
+	// or closing. Returns ErrOutOfOrder when the door is opening
+	Close(context.Context) error
+}
+
+// DoorEvent is emitted when the state of a door changes, and when an
+// alarm is raised because the door has not finished opening or closing
+// in time, or has been open for too long
+type DoorEvent interface {
+	Event
+
+	Type() DoorEventType
+	State() DoorState
+}
+
+// EntryControl releases a door strike when an RFID card or PIN code is
+// presented at a reader within the schedule of its credential. Readers
+// are named, and readers which are exits are used for anti-passback
+type EntryControl interface {
+	// Present checks a card or code read at a reader, and releases the
+	// door strike when entry is granted. Returns ErrPermissionDenied
+	// when entry is denied
+	Present(ctx context.Context, reader, credential string) error
+
+	// Release releases the door strike without a credential, for
+	// example from a request-to-exit button
+	Release(context.Context) error
+}
+
+// EntryEvent is emitted when entry is granted or denied at a reader. The
+// name of the event is the holder of the credential, or empty when the
+// credential is not known
+type EntryEvent interface {
+	Event
+
+	Type() EntryEventType
+	Reader() string
+	Error() error // Reason entry was denied, or nil
+}
+
+// Doorbell rings when a button is pressed, sending a notification with
+// a camera snapshot and playing a chime, and carries two-way audio
+// between the device and a client who answers
+type Doorbell interface {
+	// Ring as if the button had been pressed. Returns ErrOutOfOrder
+	// when the doorbell has rung within the hold-off time
+	Ring(context.Context) error
+
+	// Answer carries audio between the microphone and speaker of the
+	// device and a session, until the context is done or the session
+	// ends. Returns ErrOutOfOrder when already answered
+	Answer(context.Context, IntercomSession) error
+
+	// Answered returns true when audio is being carried to a session
+	Answered() bool
+}
+
+// IntercomSession carries audio to and from a client, such as a web
+// browser, as signed 16-bit mono samples at a sample rate
+type IntercomSession interface {
+	// SampleRate returns the sample rate of the session
+	SampleRate() uint
+
+	// Read blocks until the buffer is filled with samples from the
+	// client, and returns io.EOF when the session ends
+	Read([]int16) error
+
+	// Write sends samples to the client
+	Write([]int16) error
+}
+
+// DoorbellEvent is emitted when the doorbell rings, with the camera
+// snapshot as an attachment, and when it is answered and hung up
+type DoorbellEvent interface {
+	Event
+
+	Type() DoorbellEventType
+}
+
+// IrrigationEvent is emitted when a zone starts or stops watering, when
+// scheduled watering is skipped, and when the rain delay changes. The
+// name of the event is the name of the zone, or empty for rain delay
+type IrrigationEvent interface {
+	Event
+
+	Type() IrrigationEventType
+	Until() time.Time // Time watering stops, or the end of the rain delay
+}
+
+// SoilEvent is emitted when a probe is read, and when the moisture falls
+// below or rises above the threshold for the probe. The name of the
+// event is the name of the probe
+type SoilEvent interface {
+	Event
+
+	Type() SoilEventType
+	Probe() SoilProbe
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	IRRIGATION_EVENT_NONE       IrrigationEventType = iota
+	IRRIGATION_EVENT_START                          // Zone started watering
+	IRRIGATION_EVENT_STOP                           // Zone stopped watering
+	IRRIGATION_EVENT_SKIP                           // Scheduled watering skipped for rain delay
+	IRRIGATION_EVENT_RAIN_DELAY                     // Rain delay set or cleared
+)
+
+const (
+	DOOR_STATE_NONE    DoorState = iota // Position is not known
+	DOOR_STATE_CLOSED                   // Closed sensor is active
+	DOOR_STATE_OPENING                  // Moving towards open
+	DOOR_STATE_OPEN                     // Open sensor is active
+	DOOR_STATE_CLOSING                  // Moving towards closed
+	DOOR_STATE_BLOCKED                  // Stopped between open and closed
+)
+
+const (
+	DOOR_EVENT_NONE  DoorEventType = iota
+	DOOR_EVENT_STATE               // State changed
+	DOOR_EVENT_ALARM               // Travel timeout, or open for too long
+)
+
+const (
+	ENTRY_EVENT_NONE    EntryEventType = iota
+	ENTRY_EVENT_GRANTED                // Credential accepted and strike released
+	ENTRY_EVENT_DENIED                 // Credential unknown, out of schedule or passback
+	ENTRY_EVENT_RELEASE                // Strike released without a credential
+)
+
+const (
+	DOORBELL_EVENT_NONE   DoorbellEventType = iota
+	DOORBELL_EVENT_RING                     // Button pressed
+	DOORBELL_EVENT_ANSWER                   // Audio carried to a session
+	DOORBELL_EVENT_HANGUP                   // Session ended
+)
+
+const (
+	SOIL_EVENT_NONE    SoilEventType = iota
+	SOIL_EVENT_READING               // Probe has been read
+	SOIL_EVENT_LOW                   // Moisture fell below the threshold
+	SOIL_EVENT_OK                    // Moisture rose above the threshold
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (t IrrigationEventType) String() string {
+	switch t {
+	case IRRIGATION_EVENT_NONE:
+		return "IRRIGATION_EVENT_NONE"
+	case IRRIGATION_EVENT_START:
+		return "IRRIGATION_EVENT_START"
+	case IRRIGATION_EVENT_STOP:
+		return "IRRIGATION_EVENT_STOP"
+	case IRRIGATION_EVENT_SKIP:
+		return "IRRIGATION_EVENT_SKIP"
+	case IRRIGATION_EVENT_RAIN_DELAY:
+		return "IRRIGATION_EVENT_RAIN_DELAY"
+	default:
+		return "[?? Invalid IrrigationEventType value]"
+	}
+}
+
+func (z IrrigationZone) String() string {
+	str := "<irrigation.zone"
+	str += " name=" + strconv.Quote(z.Name)
+	if z.Running {
+		str += " running=true until=" + z.Until.Format(time.RFC3339)
+	} else if z.Queued {
+		str += " queued=true"
+	}
+	if z.Last.IsZero() == false {
+		str += " last=" + z.Last.Format(time.RFC3339)
+	}
+	if z.Next.IsZero() == false {
+		str += " next=" + z.Next.Format(time.RFC3339)
+		str += " duration=" + z.Duration.String()
+	}
+	return str + ">"
+}
+
+func (s DoorState) String() string {
+	switch s {
+	case DOOR_STATE_NONE:
+		return "DOOR_STATE_NONE"
+	case DOOR_STATE_CLOSED:
+		return "DOOR_STATE_CLOSED"
+	case DOOR_STATE_OPENING:
+		return "DOOR_STATE_OPENING"
+	case DOOR_STATE_OPEN:
+		return "DOOR_STATE_OPEN"
+	case DOOR_STATE_CLOSING:
+		return "DOOR_STATE_CLOSING"
+	case DOOR_STATE_BLOCKED:
+		return "DOOR_STATE_BLOCKED"
+	default:
+		return "[?? Invalid DoorState value]"
+	}
+}
+
+func (t DoorEventType) String() string {
+	switch t {
+	case DOOR_EVENT_NONE:
+		return "DOOR_EVENT_NONE"
+	case DOOR_EVENT_STATE:
+		return "DOOR_EVENT_STATE"
+	case DOOR_EVENT_ALARM:
+		return "DOOR_EVENT_ALARM"
+	default:
+		return "[?? Invalid DoorEventType value]"
+	}
+}
+
+func (t EntryEventType) String() string {
+	switch t {
+	case ENTRY_EVENT_NONE:
+		return "ENTRY_EVENT_NONE"
+	case ENTRY_EVENT_GRANTED:
+		return "ENTRY_EVENT_GRANTED"
+	case ENTRY_EVENT_DENIED:
+		return "ENTRY_EVENT_DENIED"
+	case ENTRY_EVENT_RELEASE:
+		return "ENTRY_EVENT_RELEASE"
+	default:
+		return "[?? Invalid EntryEventType value]"
+	}
+}
+
+func (t DoorbellEventType) String() string {
+	switch t {
+	case DOORBELL_EVENT_NONE:
+		return "DOORBELL_EVENT_NONE"
+	case DOORBELL_EVENT_RING:
+		return "DOORBELL_EVENT_RING"
+	case DOORBELL_EVENT_ANSWER:
+		return "DOORBELL_EVENT_ANSWER"
+	case DOORBELL_EVENT_HANGUP:
+		return "DOORBELL_EVENT_HANGUP"
+	default:
+		return "[?? Invalid DoorbellEventType value]"
+	}
+}
+
+func (t SoilEventType) String() string {
+	switch t {
+	case SOIL_EVENT_NONE:
+		return "SOIL_EVENT_NONE"
+	case SOIL_EVENT_READING:
+		return "SOIL_EVENT_READING"
+	case SOIL_EVENT_LOW:
+		return "SOIL_EVENT_LOW"
+	case SOIL_EVENT_OK:
+		return "SOIL_EVENT_OK"
+	default:
+		return "[?? Invalid SoilEventType value]"
+	}
+}
+
+func (p SoilProbe) String() string {
+	str := "<soil.probe"
+	str += " name=" + strconv.Quote(p.Name)
+	if p.Zone != "" {
+		str += " zone=" + strconv.Quote(p.Zone)
+	}
+	if p.Time.IsZero() == false {
+		str += " time=" + p.Time.Format(time.RFC3339)
+		str += " voltage=" + strconv.FormatFloat(float64(p.Voltage), 'f', 3, 32)
+		str += " moisture=" + strconv.FormatFloat(float64(p.Moisture), 'f', 1, 32)
+	}
+	if p.Low {
+		str += " low=true"
+	}
+	return str + ">"
+}
@@ -0,0 +1,32 @@
+package gopi
+
+import (
+	"io"
+)
+
+/*
+	This file contains definitions for binary attachments carried by
+	events, such as a snapshot from a camera, an audio clip or a trace
+	file. Attachments are carried by reference and loaded when read.
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// EventAttachment is binary data attached to an event
+type EventAttachment interface {
+	Name() string // File name, such as snapshot.jpg
+	Type() string // MIME type, such as image/jpeg
+	Size() int64  // Size in bytes, or -1 when not known until loaded
+
+	// Open returns a reader for the data, which loads the data when
+	// it is not already loaded
+	Open() (io.ReadCloser, error)
+}
+
+// EventWithAttachments is an event which carries attachments
+type EventWithAttachments interface {
+	Event
+
+	Attachments() []EventAttachment
+}
@@ -5,6 +5,8 @@ package gopi
 
 	* Audio representation
 	* Input and output audio devices
+	* I2S digital audio HATs and their mixer controls
+	* AirPlay receivers, which play audio streamed from other devices
 
 	Resampling of audio is represented in the "media" interfaces
 */
@@ -36,6 +38,33 @@ type AudioContext interface {
 	Write(MediaFrame) error
 }
 
+// AudioDAC represents an I2S digital audio HAT, which is detected
+// from the HAT EEPROM or the sound card loaded by the device tree
+type AudioDAC interface {
+	// Name returns the preset name for the DAC, or empty string
+	// if no DAC was detected
+	Name() string
+
+	// Device returns the ALSA device for audio output, which
+	// is empty if the sound card is not loaded
+	Device() string
+
+	// Volume returns hardware volume between 0.0 and 1.0
+	Volume() (float32, error)
+
+	// SetVolume sets hardware volume between 0.0 and 1.0
+	SetVolume(float32) error
+
+	// Filters returns the names of the digital filters supported
+	Filters() []string
+
+	// Filter returns the current digital filter
+	Filter() (string, error)
+
+	// SetFilter sets the current digital filter
+	SetFilter(string) error
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // CONSTANTS
 
@@ -95,3 +124,60 @@ func (f AudioFormat) String() string {
 		return "[?? Invalid AudioFormat value]"
 	}
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// AIRPLAY RECEIVER
+
+// AirPlayState is the state of a sender connected to the receiver
+type AirPlayState uint
+
+// AirPlayReceiver advertises the device as an AirPlay (RAOP) speaker
+// and plays audio streamed to it
+type AirPlayReceiver interface {
+	// Name returns the speaker name which is advertised
+	Name() string
+
+	// Sender returns the address of the connected sender, or empty
+	// string if no sender is connected
+	Sender() string
+
+	// Volume returns the volume set by the sender between 0.0 and 1.0
+	Volume() float32
+
+	// Disconnect the current sender
+	Disconnect() error
+}
+
+// AirPlayEvent is emitted when the state of a sender changes
+type AirPlayEvent interface {
+	Event
+
+	State() AirPlayState
+	Sender() string
+	Volume() float32
+}
+
+const (
+	AIRPLAY_STATE_DISCONNECTED AirPlayState = iota
+	AIRPLAY_STATE_CONNECTED
+	AIRPLAY_STATE_PLAYING
+	AIRPLAY_STATE_FLUSHED
+	AIRPLAY_STATE_VOLUME
+)
+
+func (s AirPlayState) String() string {
+	switch s {
+	case AIRPLAY_STATE_DISCONNECTED:
+		return "AIRPLAY_STATE_DISCONNECTED"
+	case AIRPLAY_STATE_CONNECTED:
+		return "AIRPLAY_STATE_CONNECTED"
+	case AIRPLAY_STATE_PLAYING:
+		return "AIRPLAY_STATE_PLAYING"
+	case AIRPLAY_STATE_FLUSHED:
+		return "AIRPLAY_STATE_FLUSHED"
+	case AIRPLAY_STATE_VOLUME:
+		return "AIRPLAY_STATE_VOLUME"
+	default:
+		return "[?? Invalid AirPlayState value]"
+	}
+}
@@ -0,0 +1,140 @@
+package gopi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/*
+	This file contains definitions for the audit log, which records
+	actions which change state made through the HTTP server, gRPC server,
+	remote shell and cloud connections:
+
+	* Who performed the action, what was changed and when
+	* The old and new values when the handler knows them
+	* Queries by time, identity, source and action
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// AuditEntry is an action which changes state. The identity is the
+// name of the caller, or the remote address when the caller is anonymous
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Identity string    `json:"identity,omitempty"`
+	Source   string    `json:"source"` // http, grpc, shell or mqtt
+	Action   string    `json:"action"` // Resource, such as "GPIO.WritePin"
+	Old      string    `json:"old,omitempty"`
+	New      string    `json:"new,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// AuditQuery selects entries from the audit log. Empty fields match
+// all entries, the action can contain * for any characters and a zero
+// limit returns all matching entries
+type AuditQuery struct {
+	Since, Until time.Time
+	Identity     string
+	Source       string
+	Action       string
+	Limit        uint
+}
+
+// auditChange holds the old and new values set by a handler
+type auditChange struct {
+	old, new string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// AuditLog is an append-only log of actions which change state
+type AuditLog interface {
+	// Record appends an entry, setting the time, the identity and the
+	// old and new values from the context when they are not set
+	Record(context.Context, AuditEntry) error
+
+	// Query returns entries in time order, the most recent last
+	Query(AuditQuery) ([]AuditEntry, error)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+type auditChangeKey struct{}
+
+var (
+	// Methods with these prefixes do not change state
+	auditReadPrefixes = []string{
+		"Get", "List", "Read", "Query", "Watch", "Stream", "Subscribe",
+		"Ping", "Is", "Has", "Find", "Lookup", "Search", "Status",
+		"String", "Version", "Name",
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// WithAuditChange returns a context where handlers can set the old and
+// new values for an action with SetAuditChange
+func WithAuditChange(ctx context.Context) context.Context {
+	return context.WithValue(ctx, auditChangeKey{}, new(auditChange))
+}
+
+// SetAuditChange sets the old and new values for an action, when the
+// action is being recorded
+func SetAuditChange(ctx context.Context, old, new interface{}) {
+	if change, ok := ctx.Value(auditChangeKey{}).(*auditChange); ok {
+		change.old, change.new = fmt.Sprint(old), fmt.Sprint(new)
+	}
+}
+
+// AuditChangeFromContext returns the old and new values set by a handler
+func AuditChangeFromContext(ctx context.Context) (string, string) {
+	if ctx == nil {
+		return "", ""
+	} else if change, ok := ctx.Value(auditChangeKey{}).(*auditChange); ok {
+		return change.old, change.new
+	} else {
+		return "", ""
+	}
+}
+
+// AuditMethod returns true when a method, such as "GPIO.WritePin" or
+// "/gopi.gpio.GPIO/WritePin", changes state and should be recorded
+func AuditMethod(method string) bool {
+	if i := strings.LastIndexAny(method, "./"); i >= 0 {
+		method = method[i+1:]
+	}
+	if method == "" {
+		return false
+	}
+	for _, prefix := range auditReadPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (e AuditEntry) String() string {
+	str := "<audit"
+	str += " time=" + e.Time.Format(time.RFC3339)
+	if e.Identity != "" {
+		str += fmt.Sprintf(" identity=%q", e.Identity)
+	}
+	str += fmt.Sprintf(" source=%q action=%q", e.Source, e.Action)
+	if e.Old != "" || e.New != "" {
+		str += fmt.Sprintf(" old=%q new=%q", e.Old, e.New)
+	}
+	if e.Error != "" {
+		str += fmt.Sprintf(" error=%q", e.Error)
+	}
+	return str + ">"
+}
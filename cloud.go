@@ -0,0 +1,93 @@
+package gopi
+
+import (
+	"context"
+	"encoding/json"
+)
+
+/*
+	This file contains interface defininitons for IoT cloud connectors:
+
+	* Telemetry and twin state published to AWS IoT, Azure IoT Hub
+	  or Google Cloud IoT
+	* Desired state changes and direct method calls received from
+	  the cloud, which are mapped to unit methods
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	// CloudEventType defines the type of a CloudEvent
+	CloudEventType uint
+
+	// CloudMethod is called with the JSON payload of a direct method
+	// call or desired state, and returns a result which is encoded as
+	// JSON in the response
+	CloudMethod func(context.Context, json.RawMessage) (interface{}, error)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// CloudConnector connects the device to an IoT cloud over MQTT,
+// publishing telemetry from events and the state of the device twin,
+// and calling registered methods for direct method calls and
+// desired state changes
+type CloudConnector interface {
+	// Provider returns the name of the cloud, which is "aws", "azure"
+	// or "google"
+	Provider() string
+
+	// Connected returns true when connected to the cloud
+	Connected() bool
+
+	// RegisterMethod adds a method which can be called from the cloud,
+	// or when a desired property with the same name changes
+	RegisterMethod(string, CloudMethod) error
+
+	// Publish an event as telemetry
+	Publish(Event) error
+}
+
+// CloudEvent is emitted when the connection state changes, when desired
+// state is received and when a method is called from the cloud
+type CloudEvent interface {
+	Event
+
+	Type() CloudEventType
+	Method() string                  // Name of the method called
+	Desired() map[string]interface{} // Desired state
+	Error() error                    // Error returned by the method, or nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	CLOUD_EVENT_NONE         CloudEventType = iota
+	CLOUD_EVENT_CONNECTED                   // Connected to the cloud
+	CLOUD_EVENT_DISCONNECTED                // Connection has been lost
+	CLOUD_EVENT_DESIRED                     // Desired state has changed
+	CLOUD_EVENT_METHOD                      // Method has been called
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (t CloudEventType) String() string {
+	switch t {
+	case CLOUD_EVENT_NONE:
+		return "CLOUD_EVENT_NONE"
+	case CLOUD_EVENT_CONNECTED:
+		return "CLOUD_EVENT_CONNECTED"
+	case CLOUD_EVENT_DISCONNECTED:
+		return "CLOUD_EVENT_DISCONNECTED"
+	case CLOUD_EVENT_DESIRED:
+		return "CLOUD_EVENT_DESIRED"
+	case CLOUD_EVENT_METHOD:
+		return "CLOUD_EVENT_METHOD"
+	default:
+		return "[?? Invalid CloudEventType value]"
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"image/png"
+	"io"
+	"os"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	scene "github.com/djthorpe/gopi/v3/pkg/graphics/scene"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type app struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Command
+	gopi.EPD `unit:",optional"`
+	*bitmap.Bitmaps
+
+	out, color, background, op *string
+	width, height, margin      *uint
+	size                       *float64
+	display                    *bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *app) Define(cfg gopi.Config) error {
+	// Define flags
+	this.out = cfg.FlagString("out", "out.png", "PNG file to write, or - for standard output", "create", "text", "render", "composite")
+	this.display = cfg.FlagBool("display", false, "Draw on the e-paper display rather than writing a file", "create", "text", "render", "composite")
+	this.width = cfg.FlagUint("width", 0, "Width in pixels, or zero for the size of the content", "text")
+	this.height = cfg.FlagUint("height", 0, "Height in pixels, or zero for the size of the content", "text")
+	this.margin = cfg.FlagUint("margin", 4, "Margin around text in pixels", "text")
+	this.size = cfg.FlagFloat("size", 26, "Text size in pixels", "text")
+	this.color = cfg.FlagString("color", "black", "Text or bitmap color", "create", "text")
+	this.background = cfg.FlagString("background", "white", "Background color", "text", "composite")
+	this.op = cfg.FlagString("op", "src_over", "Compositing operator", "composite")
+
+	// Define commands
+	cfg.Command("create", "Create a bitmap of a color: <width> <height>", this.RunCreate)
+	cfg.Command("text", "Draw text on a bitmap: <text>...", this.RunText)
+	cfg.Command("render", "Draw a JSON scene description: <file>", this.RunRender)
+	cfg.Command("composite", "Composite images in order: <file> <file>...", this.RunComposite)
+
+	// Return success
+	return nil
+}
+
+func (this *app) New(cfg gopi.Config) error {
+	this.Require(this.Logger, this.Bitmaps)
+
+	// Set the command to run
+	if cmd, err := cfg.GetCommand(nil); err != nil {
+		return err
+	} else if cmd == nil {
+		return gopi.ErrHelp
+	} else {
+		this.Command = cmd
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *app) Run(ctx context.Context) error {
+	return this.Command.Run(ctx)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// output renders a scene and writes it as a PNG, or draws it on the
+// display
+func (this *app) output(ctx context.Context, s *scene.Scene) error {
+	renderer := scene.New(this.Bitmaps)
+	bitmap, err := renderer.Render(s)
+	if err != nil {
+		return err
+	}
+	defer this.Bitmaps.DisposeBitmap(bitmap)
+
+	// Draw on the display
+	if *this.display {
+		if this.EPD == nil {
+			return gopi.ErrNotFound.WithPrefix("-display: ", "No display")
+		} else {
+			return this.EPD.Draw(ctx, bitmap)
+		}
+	}
+
+	// Write to file or standard output
+	var w io.Writer = os.Stdout
+	if *this.out != "-" {
+		fh, err := os.Create(*this.out)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		w = fh
+	}
+	if err := png.Encode(w, bitmap); err != nil {
+		return err
+	}
+	if *this.out != "-" {
+		this.Debug("Written: ", *this.out, " ", s)
+	}
+
+	// Return success
+	return nil
+}
+
+// displaySize returns the size of the display, or zero
+func (this *app) displaySize() (uint32, uint32) {
+	if *this.display && this.EPD != nil {
+		size := this.EPD.Size()
+		return uint32(size.W), uint32(size.H)
+	} else {
+		return 0, 0
+	}
+}
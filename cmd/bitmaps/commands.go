@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"image"
+	"os"
+	"strconv"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	scene "github.com/djthorpe/gopi/v3/pkg/graphics/scene"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (this *app) RunCreate(ctx context.Context) error {
+	s := &scene.Scene{Background: *this.color}
+	switch args := this.Command.Args(); {
+	case len(args) == 0 && *this.display:
+		s.Width, s.Height = this.displaySize()
+	case len(args) == 2:
+		if w, err := strconv.ParseUint(args[0], 0, 32); err != nil {
+			return gopi.ErrBadParameter.WithPrefix("Width: ", args[0])
+		} else if h, err := strconv.ParseUint(args[1], 0, 32); err != nil {
+			return gopi.ErrBadParameter.WithPrefix("Height: ", args[1])
+		} else {
+			s.Width, s.Height = uint32(w), uint32(h)
+		}
+	default:
+		return gopi.ErrHelp
+	}
+	return this.output(ctx, s)
+}
+
+func (this *app) RunText(ctx context.Context) error {
+	args := this.Command.Args()
+	if len(args) == 0 {
+		return gopi.ErrHelp
+	}
+
+	// Text lines can be separated with \n
+	text := strings.Replace(strings.Join(args, " "), "\\n", "\n", -1)
+
+	// Determine the size of the bitmap from the text, the flags or
+	// the display
+	w, h := scene.MeasureText(text, *this.size)
+	margin := int(*this.margin)
+	s := &scene.Scene{
+		Width:      uint32(w + margin*2),
+		Height:     uint32(h + margin*2),
+		Background: *this.background,
+	}
+	if dw, dh := this.displaySize(); dw != 0 && dh != 0 {
+		s.Width, s.Height = dw, dh
+	}
+	if *this.width != 0 {
+		s.Width = uint32(*this.width)
+	}
+	if *this.height != 0 {
+		s.Height = uint32(*this.height)
+	}
+
+	// Centre the text in the bitmap
+	s.Layers = append(s.Layers, scene.Layer{
+		Type:  scene.LAYER_TEXT,
+		X:     margin,
+		Y:     (int(s.Height) - h) / 2,
+		Width: maxInt(1, int(s.Width)-margin*2),
+		Text:  text,
+		Size:  *this.size,
+		Color: *this.color,
+		Align: "center",
+	})
+
+	return this.output(ctx, s)
+}
+
+func (this *app) RunRender(ctx context.Context) error {
+	args := this.Command.Args()
+	if len(args) != 1 {
+		return gopi.ErrHelp
+	}
+	if s, err := scene.ReadFile(args[0]); err != nil {
+		return err
+	} else {
+		return this.output(ctx, s)
+	}
+}
+
+func (this *app) RunComposite(ctx context.Context) error {
+	args := this.Command.Args()
+	if len(args) == 0 {
+		return gopi.ErrHelp
+	}
+
+	// The bitmap is the size of the first image
+	w, h, err := imageSize(args[0])
+	if err != nil {
+		return err
+	}
+	s := &scene.Scene{Width: w, Height: h, Background: *this.background}
+	for i, path := range args {
+		layer := scene.Layer{Type: scene.LAYER_IMAGE, Path: path, Op: *this.op}
+		if i == 0 {
+			layer.Op = "src_over"
+		}
+		s.Layers = append(s.Layers, layer)
+	}
+
+	return this.output(ctx, s)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// imageSize returns the size of an image file
+func imageSize(path string) (uint32, uint32, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer fh.Close()
+	if cfg, _, err := image.DecodeConfig(fh); err != nil {
+		return 0, 0, gopi.ErrBadParameter.WithPrefix(path, ": ", err)
+	} else {
+		return uint32(cfg.Width), uint32(cfg.Height), nil
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	} else {
+		return b
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+
+	"github.com/djthorpe/gopi/v3/pkg/tool"
+)
+
+func main() {
+	os.Exit(tool.CommandLine("bitmaps", os.Args[1:], new(app)))
+}
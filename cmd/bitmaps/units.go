@@ -0,0 +1,10 @@
+package main
+
+import (
+	_ "github.com/djthorpe/gopi/v3/pkg/dev/waveshare"
+	_ "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32"
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/gpio/broadcom"
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/platform"
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/spi"
+	_ "github.com/djthorpe/gopi/v3/pkg/log"
+)
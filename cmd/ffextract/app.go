@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"image"
 	"image/png"
 	"io"
 	"os"
@@ -10,6 +11,8 @@ import (
 	"strings"
 
 	"github.com/djthorpe/gopi/v3"
+	"github.com/djthorpe/gopi/v3/pkg/pipeline"
+	"golang.org/x/image/draw"
 )
 
 type app struct {
@@ -21,11 +24,26 @@ type app struct {
 
 	// Flags
 	start, end *uint
+	width      *uint
 }
 
+// still is a decoded frame, which is copied from the decoder so that it
+// can be scaled and encoded whilst the next frames are decoded
+type still struct {
+	path  string
+	image image.Image
+}
+
+const (
+	// Number of frames queued for scaling and encoding. Decoding waits
+	// when the queue is full
+	frameQueue = 8
+)
+
 func (this *app) Define(cfg gopi.Config) error {
 	this.start = cfg.FlagUint("start", 0, "Start frame")
 	this.end = cfg.FlagUint("end", 0, "End frame")
+	this.width = cfg.FlagUint("width", 0, "Scale frames to width, keeping the aspect ratio")
 	return nil
 }
 
@@ -66,6 +84,9 @@ func (this *app) PathTemplate() string {
 	return path + "_%04d"
 }
 
+// Decode frames, then scale and encode them as PNG files. Each stage
+// runs in the background with a bounded queue, so decoding waits for
+// encoding rather than using memory without limit
 func (this *app) Decode(ctx context.Context, path string, file gopi.MediaInput) error {
 	// Use the first video stream found
 	streams := file.StreamsForFlag(gopi.MEDIA_FLAG_VIDEO)
@@ -75,35 +96,71 @@ func (this *app) Decode(ctx context.Context, path string, file gopi.MediaInput)
 		this.Print(file.StreamForIndex(streams[0]))
 	}
 
-	// Decode frames
-	f := uint(0)
-	return file.Read(ctx, streams[0:1], func(ctx gopi.MediaDecodeContext, packet gopi.MediaPacket) error {
-		return file.DecodeFrameIterator(ctx, packet, func(frame gopi.MediaFrame) error {
-			f++
-			if *this.start > 0 && f < *this.start {
-				return nil
-			}
-			if *this.end > 0 && f > *this.end {
-				// Quit loop
-				return io.EOF
-			}
-			return this.DecodeFrame(fmt.Sprintf(path, f), frame)
+	queue := pipeline.Queue{Size: frameQueue, Policy: pipeline.BLOCK}
+	p := pipeline.New()
+	p.Source("decode", func(ctx context.Context, emit pipeline.EmitFunc) error {
+		f := uint(0)
+		err := file.Read(ctx, streams[0:1], func(dctx gopi.MediaDecodeContext, packet gopi.MediaPacket) error {
+			return file.DecodeFrameIterator(dctx, packet, func(frame gopi.MediaFrame) error {
+				f++
+				if *this.start > 0 && f < *this.start {
+					return nil
+				}
+				if *this.end > 0 && f > *this.end {
+					// Quit loop
+					return io.EOF
+				}
+				return emit(&still{fmt.Sprintf(path, f), copyFrame(frame)})
+			})
 		})
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}).Transform("scale", queue, func(_ context.Context, item interface{}) (interface{}, error) {
+		return this.ScaleFrame(item.(*still)), nil
+	}).Sink("encode", queue, func(_ context.Context, item interface{}) error {
+		return this.EncodeFrame(item.(*still))
 	})
+
+	err := p.Run(ctx)
+	this.Debug(p)
+	return err
 }
 
-func (this *app) DecodeFrame(path string, frame gopi.MediaFrame) error {
-	// Save frame as a PNG
-	path = path + ".png"
+// ScaleFrame scales a frame to the width, or returns it unchanged when
+// width is not set
+func (this *app) ScaleFrame(frame *still) *still {
+	bounds := frame.image.Bounds()
+	if *this.width == 0 || bounds.Dx() == 0 || int(*this.width) == bounds.Dx() {
+		return frame
+	}
+	w := int(*this.width)
+	h := bounds.Dy() * w / bounds.Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), frame.image, bounds, draw.Src, nil)
+	return &still{frame.path, dst}
+}
+
+// EncodeFrame saves a frame as a PNG
+func (this *app) EncodeFrame(frame *still) error {
+	path := frame.path + ".png"
 	fh, err := os.Create(path)
 	if err != nil {
 		return err
 	}
 	defer fh.Close()
-	if err := png.Encode(fh, frame); err != nil {
+	if err := png.Encode(fh, frame.image); err != nil {
 		return err
 	}
 
-	this.Print("Saved frame:", frame, " => ", path)
+	this.Print("Saved frame:", frame.image.Bounds().Size(), " => ", path)
 	return nil
 }
+
+// copyFrame returns a copy of a frame, as the decoder reuses frames
+func copyFrame(frame gopi.MediaFrame) image.Image {
+	dst := image.NewRGBA(frame.Bounds())
+	draw.Draw(dst, dst.Bounds(), frame, frame.Bounds().Min, draw.Src)
+	return dst
+}
@@ -9,6 +9,7 @@ import (
 type app struct {
 	gopi.Unit
 	gopi.Logger
+	gopi.Locale
 
 	name *string
 	wait *bool
@@ -22,13 +23,13 @@ func (this *app) Define(cfg gopi.Config) error {
 
 func (this *app) Run(ctx context.Context) error {
 	if *this.name != "" {
-		this.Print("Hello, ", *this.name)
+		this.Print(this.Translate("Hello, %v", *this.name))
 	} else {
-		this.Print("Hello, World!")
+		this.Print(this.Translate("Hello, World!"))
 	}
 
 	if *this.wait {
-		this.Print("Waiting for CTRL+C to exit")
+		this.Print(this.Translate("Waiting for CTRL+C to exit"))
 		<-ctx.Done()
 	}
 
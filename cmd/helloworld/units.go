@@ -0,0 +1,5 @@
+package main
+
+import (
+	_ "github.com/djthorpe/gopi/v3/pkg/i18n"
+)
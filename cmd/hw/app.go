@@ -20,8 +20,8 @@ type app struct {
 	gopi.Publisher
 	gopi.Platform
 	gopi.GPIO
-	gopi.I2C
-	gopi.LIRC
+	gopi.I2C  `unit:",optional"`
+	gopi.LIRC `unit:",optional"`
 	gopi.ServiceDiscovery
 	gopi.FontManager
 	gopi.Command
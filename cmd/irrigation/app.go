@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type app struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Command
+	gopi.Publisher
+	gopi.Irrigation
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *app) Define(cfg gopi.Config) error {
+	cfg.Command("zones", "List zones and the next scheduled watering", this.RunZones)
+	cfg.Command("water", "Water a zone now: <zone> [<duration>]", this.RunWater)
+	cfg.Command("daemon", "Water zones on schedule", this.RunDaemon)
+	return nil
+}
+
+func (this *app) New(cfg gopi.Config) error {
+	this.Require(this.Logger, this.Publisher, this.Irrigation)
+
+	if cmd, err := cfg.GetCommand(nil); err != nil {
+		return err
+	} else if cmd == nil {
+		return gopi.ErrHelp
+	} else {
+		this.Command = cmd
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *app) Run(ctx context.Context) error {
+	return this.Command.Run(ctx)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+func (this *app) RunZones(ctx context.Context) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ZONE\tSTATE\tLAST\tNEXT\tDURATION")
+	for _, zone := range this.Irrigation.Zones() {
+		state := "-"
+		if zone.Running {
+			state = "watering until " + zone.Until.Format("15:04")
+		} else if zone.Queued {
+			state = "queued"
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", zone.Name, state, formatTime(zone.Last), formatTime(zone.Next), zone.Duration)
+	}
+	if until := this.Irrigation.RainDelay(); until.IsZero() == false {
+		fmt.Fprintln(w, "\nRain delay until", formatTime(until))
+	}
+	return w.Flush()
+}
+
+func (this *app) RunWater(ctx context.Context) error {
+	args := this.Command.Args()
+	if len(args) < 1 || len(args) > 2 {
+		return gopi.ErrHelp
+	}
+	var duration time.Duration
+	if len(args) == 2 {
+		if d, err := time.ParseDuration(args[1]); err != nil {
+			return gopi.ErrBadParameter.WithPrefix(args[1])
+		} else {
+			duration = d
+		}
+	}
+
+	ch := this.Publisher.Subscribe()
+	defer this.Publisher.Unsubscribe(ch)
+	if err := this.Irrigation.Water(args[0], duration); err != nil {
+		return err
+	}
+
+	// Wait until the zone has been watered, or stop watering on CTRL+C
+	fmt.Println("Press CTRL+C to stop watering")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-ch:
+			if evt, ok := evt.(gopi.IrrigationEvent); ok && evt.Name() == args[0] {
+				fmt.Println(evt)
+				if evt.Type() == gopi.IRRIGATION_EVENT_STOP {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+func (this *app) RunDaemon(ctx context.Context) error {
+	ch := this.Publisher.Subscribe()
+	defer this.Publisher.Unsubscribe(ch)
+
+	fmt.Println("Press CTRL+C to end")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-ch:
+			if evt, ok := evt.(gopi.IrrigationEvent); ok {
+				this.Debug(evt)
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	} else {
+		return t.Format("Mon 2 Jan 15:04")
+	}
+}
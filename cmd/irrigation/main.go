@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+)
+
+func main() {
+	os.Exit(tool.CommandLine("irrigation", os.Args[1:], new(app)))
+}
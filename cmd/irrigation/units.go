@@ -0,0 +1,12 @@
+package main
+
+import (
+	_ "github.com/djthorpe/gopi/v3/pkg/event"            // Publisher
+	_ "github.com/djthorpe/gopi/v3/pkg/feed/weather"     // Rain delay
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/gpio/broadcom" // GPIO relays
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/i2c"           // Port expander relays
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/platform"      // Platform
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/relay"         // Relay channels
+	_ "github.com/djthorpe/gopi/v3/pkg/irrigation"       // Irrigation
+	_ "github.com/djthorpe/gopi/v3/pkg/log"              // Logger
+)
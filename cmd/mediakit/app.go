@@ -3,11 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"regexp"
-	"strings"
+	"runtime"
+	"sync"
+	"time"
 
 	"github.com/djthorpe/gopi/v3"
 )
@@ -19,24 +20,42 @@ type app struct {
 	gopi.MediaManager
 	gopi.Logger
 	gopi.Command
+	gopi.FSWatcher
+	gopi.Publisher
+	sync.Mutex
 
 	offset, limit *uint   // File processing offsets
+	workers, rate *uint   // File processing concurrency and files per second
 	quiet, csv    *bool   // Whether errors should be displayed
+	json          *bool   // Output as JSON
 	match         *string // Regular expression to match
 
+	output, format *string        // Ingest output folder and format
+	pipeline       *string        // Ingest stages
+	settle         *time.Duration // Time without changes before ingest
+
 	regexp *regexp.Regexp // Regular expression for filename
 }
 
 func (this *app) Define(cfg gopi.Config) error {
 	// Set command-line flags
-	this.offset = cfg.FlagUint("offset", 0, "File process offset", "metadata")
-	this.limit = cfg.FlagUint("limit", 0, "File process limit", "metadata")
-	this.quiet = cfg.FlagBool("quiet", false, "Don't display file scan errors", "metadata")
-	this.match = cfg.FlagString("match", "", "Match filenames regular expression", "metadata")
-	this.csv = cfg.FlagBool("csv", false, "Output as CSV format", "metadata")
+	this.offset = cfg.FlagUint("offset", 0, "File process offset", "metadata", "probe")
+	this.limit = cfg.FlagUint("limit", 0, "File process limit", "metadata", "probe")
+	this.workers = cfg.FlagUint("workers", uint(runtime.NumCPU()), "Number of files processed concurrently", "metadata", "probe")
+	this.rate = cfg.FlagUint("rate", 0, "Maximum files processed per second, or zero for no limit", "metadata", "probe")
+	this.quiet = cfg.FlagBool("quiet", false, "Don't display file scan errors", "metadata", "probe")
+	this.match = cfg.FlagString("match", "", "Match filenames regular expression", "metadata", "probe", "watch")
+	this.csv = cfg.FlagBool("csv", false, "Output as CSV format", "metadata", "probe")
+	this.json = cfg.FlagBool("json", false, "Output as JSON format", "probe")
+	this.output = cfg.FlagString("output", "", "Output folder", "watch")
+	this.format = cfg.FlagString("format", "", "Output format for remux stage", "watch")
+	this.pipeline = cfg.FlagString("pipeline", "probe,index", "Comma-separated stages (probe, index, remux, thumbnail)", "watch")
+	this.settle = cfg.FlagDuration("settle", 2*time.Second, "Time without changes before a file is processed", "watch")
 
 	// Define commands
 	cfg.Command("metadata", "Dump metadata information", this.Metadata)
+	cfg.Command("probe", "Report container, chapter and stream information", this.Probe)
+	cfg.Command("watch", "Watch folders and process new media files", this.Watch)
 	cfg.Command("remux", "Remultiplex from source to destination", this.Remux)
 	//cfg.Command("streams", "Dump stream information", this.Streams)
 	//cfg.Command("thumbnails", "Extract thumbnails", this.Thumbnails)
@@ -103,62 +122,3 @@ func GetFileArgs(args []string) ([]string, error) {
 	}
 	return result, nil
 }
-
-// Walk will traverse through files but only process those within offset/limit
-// bounds
-func (this *app) Walk(ctx context.Context, paths []string, count *uint, fn walkfunc) error {
-	// Walk through the files
-	for _, path := range paths {
-		if err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
-			if this.regexp == nil || this.regexp.MatchString(path) {
-				return WalkFunc(ctx, count, this.offset, this.limit, path, info, fn, err)
-			} else {
-				return nil
-			}
-		}); err != nil && err != io.EOF {
-			return err
-		}
-	}
-
-	// Return success
-	return nil
-}
-
-func WalkFunc(ctx context.Context, count, offset, limit *uint, path string, info os.FileInfo, fn walkfunc, err error) error {
-	// Deal with incoming errors
-	if ctx.Err() != nil {
-		return ctx.Err()
-	}
-	if err != nil {
-		return err
-	}
-
-	// Ignore hidden files and folders
-	if strings.HasPrefix(info.Name(), ".") {
-		if info.IsDir() {
-			return filepath.SkipDir
-		} else {
-			return nil
-		}
-	}
-
-	// Ignore anything which isn't a regular file
-	if info.Mode().IsRegular() == false {
-		return nil
-	}
-
-	// If limit has been reached, return io.EOF
-	if *limit > 0 && *count >= *limit {
-		return io.EOF
-	}
-
-	// Increment the count and check
-	if *count++; *count > *offset {
-		if err := fn(path, info); err != nil {
-			return err
-		}
-	}
-
-	// Return success
-	return nil
-}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/djthorpe/gopi/v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+type stage string
+
+type ingest struct {
+	output string
+	format string
+	stages []stage
+	index  map[string]*gopi.MediaProbe
+}
+
+type ingestevent struct {
+	path   string
+	stages []stage
+	err    error
+}
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	stageProbe     stage = "probe"     // Write probe report as JSON
+	stageIndex     stage = "index"     // Add probe report to index
+	stageRemux     stage = "remux"     // Remultiplex into output format
+	stageThumbnail stage = "thumbnail" // Write first video frame as PNG
+)
+
+const (
+	indexFile = "index.json"
+)
+
+/////////////////////////////////////////////////////////////////////
+// WATCH COMMAND
+
+func (this *app) Watch(ctx context.Context) error {
+	this.Require(this.FSWatcher, this.Publisher)
+
+	// Set up the pipeline
+	ingest, err := NewIngest(*this.output, *this.format, *this.pipeline)
+	if err != nil {
+		return err
+	} else if *this.settle <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-settle")
+	}
+
+	// Subscribe to file events before watching
+	ch := this.Publisher.Subscribe()
+	defer this.Publisher.Unsubscribe(ch)
+
+	// Watch folders
+	if paths, err := GetFileArgs(this.Command.Args()); err != nil {
+		return err
+	} else {
+		for _, path := range paths {
+			if err := this.FSWatcher.Watch(path, true); err != nil {
+				return err
+			}
+			this.Logger.Debug("Watch: ", path)
+		}
+	}
+
+	// Files are processed once they have not changed for the settle
+	// period, so that files which are still being written are not read
+	pending := make(map[string]time.Time)
+	ticker := time.NewTicker(*this.settle / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-ch:
+			switch evt := evt.(type) {
+			case gopi.FSEvent:
+				if path := ingest.Accept(evt); path != "" {
+					pending[path] = time.Now()
+				}
+			case *ingestevent:
+				this.Logger.Print(evt)
+			}
+		case now := <-ticker.C:
+			for path, ts := range pending {
+				if now.Sub(ts) < *this.settle {
+					continue
+				}
+				delete(pending, path)
+				if this.regexp != nil && this.regexp.MatchString(path) == false {
+					continue
+				}
+				err := ingest.Process(ctx, this.MediaManager, path)
+				if err := this.Publisher.Emit(&ingestevent{path, ingest.stages, err}, false); err != nil {
+					this.Logger.Print("Emit: ", err)
+				}
+			}
+		}
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// INGEST
+
+// NewIngest returns a pipeline which writes to an output folder, with
+// stages separated by commas
+func NewIngest(output, format, pipeline string) (*ingest, error) {
+	this := new(ingest)
+	this.format = format
+	this.index = make(map[string]*gopi.MediaProbe)
+
+	// Set output folder
+	if output == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("-output")
+	} else if abs, err := filepath.Abs(output); err != nil {
+		return nil, err
+	} else if err := os.MkdirAll(abs, 0755); err != nil {
+		return nil, err
+	} else {
+		this.output = abs
+	}
+
+	// Set stages
+	for _, name := range strings.Split(pipeline, ",") {
+		switch stage := stage(strings.ToLower(strings.TrimSpace(name))); stage {
+		case stageProbe, stageIndex, stageRemux, stageThumbnail:
+			this.stages = append(this.stages, stage)
+		case "":
+			continue
+		default:
+			return nil, gopi.ErrBadParameter.WithPrefix("-pipeline: ", name)
+		}
+	}
+	if len(this.stages) == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("-pipeline")
+	} else if this.has(stageRemux) && this.format == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("-format")
+	}
+
+	// Read any existing index
+	if data, err := ioutil.ReadFile(filepath.Join(this.output, indexFile)); err == nil {
+		if err := json.Unmarshal(data, &this.index); err != nil {
+			return nil, fmt.Errorf("%s: %w", indexFile, err)
+		}
+	}
+
+	// Return success
+	return this, nil
+}
+
+// Accept returns the path for an event which creates or changes a
+// regular file, or an empty string if the event should be ignored
+func (this *ingest) Accept(evt gopi.FSEvent) string {
+	path := evt.Path()
+	if evt.Flags()&gopi.FSEVENT_FLAG_DIR != 0 || evt.Flags()&gopi.FSEVENT_FLAG_DELETED != 0 {
+		return ""
+	} else if strings.HasPrefix(filepath.Base(path), ".") {
+		return ""
+	} else if path == this.output || strings.HasPrefix(path, this.output+string(filepath.Separator)) {
+		// Ignore files written by the pipeline
+		return ""
+	} else {
+		return path
+	}
+}
+
+// Process runs each stage of the pipeline on a file
+func (this *ingest) Process(ctx context.Context, manager gopi.MediaManager, path string) error {
+	if info, err := os.Stat(path); err != nil {
+		return err
+	} else if info.Mode().IsRegular() == false {
+		return gopi.ErrBadParameter.WithPrefix(path)
+	}
+
+	// Probe the file first, which checks it is media
+	probe, err := manager.Probe(path)
+	if err != nil {
+		return err
+	}
+
+	// Run stages
+	for _, stage := range this.stages {
+		var err error
+		switch stage {
+		case stageProbe:
+			err = this.writeJSON(this.outpath(path, ".json"), probe)
+		case stageIndex:
+			this.index[path] = probe
+			err = this.writeJSON(filepath.Join(this.output, indexFile), this.index)
+		case stageRemux:
+			err = this.remux(ctx, manager, path)
+		case stageThumbnail:
+			err = this.thumbnail(ctx, manager, path)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", stage, err)
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *ingest) has(stage stage) bool {
+	for _, s := range this.stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+/////////////////////////////////////////////////////////////////////
+// STAGES
+
+func (this *ingest) remux(ctx context.Context, manager gopi.MediaManager, path string) error {
+	src, err := manager.OpenFile(path)
+	if err != nil {
+		return err
+	}
+	defer manager.Close(src)
+
+	dst, err := manager.CreateFile(this.outpath(path, "."+this.format))
+	if err != nil {
+		return err
+	}
+	if err := src.Read(ctx, nil, func(ctx gopi.MediaDecodeContext, packet gopi.MediaPacket) error {
+		return dst.Write(ctx, packet)
+	}); err != nil {
+		manager.Close(dst)
+		return err
+	}
+
+	// Close writes the trailer
+	return manager.Close(dst)
+}
+
+func (this *ingest) thumbnail(ctx context.Context, manager gopi.MediaManager, path string) error {
+	media, err := manager.OpenFile(path)
+	if err != nil {
+		return err
+	}
+	defer manager.Close(media)
+
+	// Get video stream
+	streams := media.StreamsForFlag(gopi.MEDIA_FLAG_VIDEO)
+	if len(streams) == 0 {
+		return gopi.ErrNotFound.WithPrefix("video stream")
+	}
+
+	// Write the first frame and end
+	written := false
+	if err := media.Read(ctx, streams[:1], func(ctx gopi.MediaDecodeContext, packet gopi.MediaPacket) error {
+		return media.DecodeFrameIterator(ctx, packet, func(frame gopi.MediaFrame) error {
+			w, err := os.Create(this.outpath(path, ".png"))
+			if err != nil {
+				return err
+			}
+			defer w.Close()
+			if err := png.Encode(w, frame); err != nil {
+				return err
+			}
+			written = true
+			return io.EOF
+		})
+	}); err != nil {
+		return err
+	} else if written == false {
+		return gopi.ErrNotFound.WithPrefix("video frame")
+	}
+
+	// Return success
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// outpath returns the path in the output folder for a file, with
+// the extension replaced
+func (this *ingest) outpath(path, ext string) string {
+	name := filepath.Base(path)
+	return filepath.Join(this.output, strings.TrimSuffix(name, filepath.Ext(name))+ext)
+}
+
+// writeJSON writes a value through a temporary file, so that readers
+// never see a partial file
+func (this *ingest) writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+/////////////////////////////////////////////////////////////////////
+// EVENT
+
+func (this *ingestevent) Name() string {
+	return this.path
+}
+
+func (this *ingestevent) String() string {
+	str := "<mediakit.ingest"
+	str += fmt.Sprintf(" path=%q", this.path)
+	str += fmt.Sprint(" stages=", this.stages)
+	if this.err != nil {
+		str += fmt.Sprintf(" err=%q", this.err.Error())
+	}
+	return str + ">"
+}
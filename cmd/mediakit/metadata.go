@@ -23,7 +23,9 @@ func (this *app) Metadata(ctx context.Context) error {
 				this.Logger.Print(filepath.Base(path), ": ", err)
 			}
 		} else {
+			this.Mutex.Lock()
 			files = append(files, media)
+			this.Mutex.Unlock()
 		}
 		return nil
 	}); err != nil {
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/djthorpe/gopi/v3"
+	"github.com/djthorpe/gopi/v3/pkg/table"
+)
+
+/////////////////////////////////////////////////////////////////////
+
+func (this *app) Probe(ctx context.Context) error {
+	count := uint(0)
+	files := []*gopi.MediaProbe{}
+
+	// Process files
+	if paths, err := GetFileArgs(this.Command.Args()); err != nil {
+		return err
+	} else if err := this.Walk(ctx, paths, &count, func(path string, info os.FileInfo) error {
+		if probe, err := this.MediaManager.Probe(path); err != nil {
+			if *this.quiet == false {
+				this.Logger.Print(filepath.Base(path), ": ", err)
+			}
+		} else {
+			this.Mutex.Lock()
+			files = append(files, probe)
+			this.Mutex.Unlock()
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// Output as JSON
+	if *this.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(files)
+	}
+
+	// Print out a row for each stream
+	t := table.New()
+	for _, file := range files {
+		for _, stream := range file.Streams {
+			t.Add(map[string]interface{}{
+				"Name":     name{filepath.Base(file.Path)},
+				"Format":   file.Format,
+				"Duration": time.Duration(file.Duration * float64(time.Second)).Truncate(time.Millisecond),
+				"Stream":   stream.Index,
+				"Type":     stream.Type,
+				"Codec":    stream.Codec,
+				"Details":  probeDetails(stream),
+			})
+		}
+	}
+	if *this.csv {
+		t.RenderCSV(os.Stdout)
+	} else {
+		t.Render(os.Stdout, table.WithFooter(true))
+	}
+
+	// Return success
+	return nil
+}
+
+func probeDetails(stream gopi.MediaProbeStream) string {
+	switch stream.Type {
+	case "video":
+		return fmt.Sprintf("%dx%d %.2f fps", stream.Width, stream.Height, stream.FrameRate)
+	case "audio":
+		return fmt.Sprintf("%d Hz %s", stream.SampleRate, stream.ChannelLayout)
+	default:
+		return ""
+	}
+}
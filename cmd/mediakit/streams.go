@@ -21,7 +21,9 @@ func (this *app) Streams(ctx context.Context) error {
 				this.Logger.Print(filepath.Base(path), ": ", err)
 			}
 		} else {
+			this.Mutex.Lock()
 			files = append(files, media)
+			this.Mutex.Unlock()
 		}
 		return nil
 	}); err != nil {
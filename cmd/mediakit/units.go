@@ -1,5 +1,7 @@
 package main
 
 import (
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/file"
 	_ "github.com/djthorpe/gopi/v3/pkg/media/ffmpeg"
 )
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+type walkfile struct {
+	path string
+	info os.FileInfo
+}
+
+type progressevent struct {
+	done, total uint
+	elapsed     time.Duration
+}
+
+/////////////////////////////////////////////////////////////////////
+// WALK
+
+// Walk will traverse through files but only process those within offset/limit
+// bounds. Files are processed by a pool of workers, so fn can be called
+// concurrently, and a progress event is emitted as each file is done
+func (this *app) Walk(ctx context.Context, paths []string, count *uint, fn walkfunc) error {
+	// Collect the files to process
+	files := []walkfile{}
+	for _, path := range paths {
+		if err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if this.regexp == nil || this.regexp.MatchString(path) {
+				return WalkFunc(ctx, count, this.offset, this.limit, path, info, func(path string, info os.FileInfo) error {
+					files = append(files, walkfile{path, info})
+					return nil
+				}, err)
+			} else {
+				return nil
+			}
+		}); err != nil && err != io.EOF {
+			return err
+		}
+	}
+
+	// Cancel workers on the first error
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Feed files to workers, waiting between files when rate limited
+	queue := make(chan walkfile)
+	go func() {
+		defer close(queue)
+		var tick <-chan time.Time
+		if *this.rate > 0 {
+			ticker := time.NewTicker(time.Second / time.Duration(*this.rate))
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for _, file := range files {
+			if tick != nil {
+				select {
+				case <-tick:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case queue <- file:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Start workers
+	var wg sync.WaitGroup
+	var once sync.Once
+	var result error
+	progress := &progressevent{total: uint(len(files))}
+	start := time.Now()
+	workers := *this.workers
+	if workers == 0 {
+		workers = 1
+	}
+	for i := uint(0); i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range queue {
+				if err := fn(file.path, file.info); err != nil {
+					once.Do(func() {
+						result = err
+						cancel()
+					})
+					return
+				}
+				this.progress(progress, start)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Return any error, or cancellation
+	if result != nil {
+		return result
+	} else if err := ctx.Err(); err != nil && progress.done < progress.total {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+func WalkFunc(ctx context.Context, count, offset, limit *uint, path string, info os.FileInfo, fn walkfunc, err error) error {
+	// Deal with incoming errors
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Ignore hidden files and folders
+	if strings.HasPrefix(info.Name(), ".") {
+		if info.IsDir() {
+			return filepath.SkipDir
+		} else {
+			return nil
+		}
+	}
+
+	// Ignore anything which isn't a regular file
+	if info.Mode().IsRegular() == false {
+		return nil
+	}
+
+	// If limit has been reached, return io.EOF
+	if *limit > 0 && *count >= *limit {
+		return io.EOF
+	}
+
+	// Increment the count and check
+	if *count++; *count > *offset {
+		if err := fn(path, info); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////
+// PROGRESS
+
+// progress increments the number of files done and emits an event
+func (this *app) progress(progress *progressevent, start time.Time) {
+	this.Mutex.Lock()
+	progress.done++
+	progress.elapsed = time.Since(start)
+	evt := *progress
+	this.Mutex.Unlock()
+
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(&evt, false); err != nil {
+			this.Logger.Debug("Emit: ", err)
+		}
+	}
+	this.Logger.Debug(&evt)
+}
+
+func (this *progressevent) Name() string {
+	return "progress"
+}
+
+// ETA returns the estimated time until all files are done
+func (this *progressevent) ETA() time.Duration {
+	if this.done == 0 || this.done >= this.total {
+		return 0
+	} else {
+		return this.elapsed / time.Duration(this.done) * time.Duration(this.total-this.done)
+	}
+}
+
+func (this *progressevent) String() string {
+	str := "<mediakit.progress"
+	str += fmt.Sprint(" done=", this.done, "/", this.total)
+	str += fmt.Sprint(" elapsed=", this.elapsed.Truncate(time.Second))
+	if eta := this.ETA(); eta > 0 {
+		str += fmt.Sprint(" eta=", eta.Truncate(time.Second))
+	}
+	return str + ">"
+}
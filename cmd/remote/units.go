@@ -0,0 +1,8 @@
+package main
+
+import (
+	_ "github.com/djthorpe/gopi/v3/pkg/log"        // gopi.Logger
+	_ "github.com/djthorpe/gopi/v3/pkg/mdns"       // Multicast DNS
+	_ "github.com/djthorpe/gopi/v3/pkg/rpc/client" // gRPC Client
+	_ "github.com/djthorpe/gopi/v3/pkg/rpc/shell"  // Shell Stub
+)
@@ -0,0 +1,77 @@
+package gopi
+
+/*
+	This file contains interface defininitons for event codecs:
+
+	* A registry of marshal and unmarshal functions for event types
+	* JSON, protobuf and msgpack formats
+
+	Sinks which send events outside the process should use the
+	registry rather than encoding events themselves, so that an event
+	is encoded the same way whichever sink it is sent through. Events
+	which implement one of the event interfaces in this package are
+	encoded as JSON or msgpack without registration, and other events
+	return ErrNotImplemented rather than an empty object.
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	EventFormat uint
+
+	// EventMarshalFunc encodes an event in a format
+	EventMarshalFunc func(Event) ([]byte, error)
+
+	// EventUnmarshalFunc decodes an event from a format
+	EventUnmarshalFunc func([]byte) (Event, error)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// EventCodec is a registry of functions which encode and decode events.
+// Events are identified by a type name so they can be decoded at the
+// other end of a sink
+type EventCodec interface {
+	// Register functions for the type of an event in a format, where
+	// either function can be nil
+	Register(Event, EventFormat, EventMarshalFunc, EventUnmarshalFunc) error
+
+	// Marshal an event, returning the type name and encoded event
+	Marshal(Event, EventFormat) (string, []byte, error)
+
+	// Unmarshal an event from a type name and encoded event
+	Unmarshal(string, EventFormat, []byte) (Event, error)
+
+	// TypeName returns the type name for an event
+	TypeName(Event) string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	EVENT_FORMAT_NONE EventFormat = iota
+	EVENT_FORMAT_JSON
+	EVENT_FORMAT_PROTOBUF
+	EVENT_FORMAT_MSGPACK
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (f EventFormat) String() string {
+	switch f {
+	case EVENT_FORMAT_NONE:
+		return "EVENT_FORMAT_NONE"
+	case EVENT_FORMAT_JSON:
+		return "EVENT_FORMAT_JSON"
+	case EVENT_FORMAT_PROTOBUF:
+		return "EVENT_FORMAT_PROTOBUF"
+	case EVENT_FORMAT_MSGPACK:
+		return "EVENT_FORMAT_MSGPACK"
+	default:
+		return "[?? Invalid EventFormat]"
+	}
+}
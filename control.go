@@ -0,0 +1,93 @@
+package gopi
+
+import (
+	"strconv"
+	"time"
+)
+
+/*
+	This file contains interface defininitons for control loops:
+
+	* PID controllers with output clamping and anti-windup
+	* Binding of sensor events to actuators
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	// PIDValueFunc returns the process value from an event, or
+	// false if the event is not a measurement for the controller
+	PIDValueFunc func(Event) (float64, bool)
+
+	// PIDOutputFunc is called with the output of a controller to
+	// drive an actuator
+	PIDOutputFunc func(float64) error
+)
+
+// PIDGains are the proportional, integral and derivative gains
+type PIDGains struct {
+	Kp, Ki, Kd float64
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// PID creates controllers and binds them to sensor events
+type PID interface {
+	// NewController returns a controller with a name, gains, setpoint
+	// and output limits
+	NewController(string, PIDGains, float64, float64, float64) (PIDController, error)
+
+	// Bind updates a controller with process values from events and
+	// calls a function with each output
+	Bind(PIDController, PIDValueFunc, PIDOutputFunc) error
+
+	// Unbind stops updating a controller from events
+	Unbind(PIDController) error
+}
+
+// PIDController computes an output which drives a process value
+// towards a setpoint
+type PIDController interface {
+	Name() string
+
+	// Gains returns the current gains
+	Gains() PIDGains
+
+	// SetGains changes the gains without a step change in output
+	SetGains(PIDGains) error
+
+	// Setpoint returns the target process value
+	Setpoint() float64
+
+	// SetSetpoint changes the target process value
+	SetSetpoint(float64)
+
+	// Limits returns the minimum and maximum output
+	Limits() (float64, float64)
+
+	// SetLimits changes the minimum and maximum output
+	SetLimits(float64, float64) error
+
+	// Update computes the output from a process value and the time
+	// since the last update
+	Update(float64, time.Duration) float64
+
+	// Output returns the last output
+	Output() float64
+
+	// Reset clears the integral and derivative state
+	Reset()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (g PIDGains) String() string {
+	str := "<pid.gains"
+	str += " kp=" + strconv.FormatFloat(g.Kp, 'g', -1, 64)
+	str += " ki=" + strconv.FormatFloat(g.Ki, 'g', -1, 64)
+	str += " kd=" + strconv.FormatFloat(g.Kd, 'g', -1, 64)
+	return str + ">"
+}
@@ -0,0 +1,120 @@
+package gopi
+
+import (
+	"context"
+	"strings"
+)
+
+/*
+	This file contains interface defininitons for D-Bus:
+
+	* Calling methods on the system and session bus
+	* Subscribing to signals, which are emitted as events
+	* Exporting objects with methods
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	DBusBus uint
+
+	// DBusObjectPath and DBusSignature are strings which are
+	// marshalled as object path and signature types
+	DBusObjectPath string
+	DBusSignature  string
+
+	// DBusMethod is called for exported methods, with the sender
+	// and arguments, and returns values for the reply
+	DBusMethod func(string, []interface{}) ([]interface{}, error)
+)
+
+// DBusVariant wraps a value which is marshalled as a variant
+type DBusVariant struct {
+	Value interface{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// DBus communicates with services on the system and session bus
+type DBus interface {
+	// Call a method with destination, object path, method name including
+	// interface and arguments, and return the reply arguments
+	Call(context.Context, DBusBus, string, DBusObjectPath, string, ...interface{}) ([]interface{}, error)
+
+	// Subscribe to signals with interface, member and object path,
+	// where empty values match any signal
+	Subscribe(DBusBus, string, string, DBusObjectPath) error
+
+	// Unsubscribe from signals
+	Unsubscribe(DBusBus, string, string, DBusObjectPath) error
+
+	// Export methods for an interface on an object path
+	Export(DBusBus, DBusObjectPath, string, map[string]DBusMethod) error
+
+	// Unexport an interface on an object path
+	Unexport(DBusBus, DBusObjectPath, string) error
+
+	// RequestName requests a well-known name on the bus
+	RequestName(DBusBus, string) error
+
+	// Name returns the unique name of the connection to the bus
+	Name(DBusBus) (string, error)
+}
+
+// DBusSignal is emitted when a subscribed signal is received
+type DBusSignal interface {
+	Event
+
+	Bus() DBusBus
+	Sender() string
+	Path() DBusObjectPath
+	Interface() string
+	Member() string
+	Args() []interface{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	DBUS_BUS_SYSTEM DBusBus = iota
+	DBUS_BUS_SESSION
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (b DBusBus) String() string {
+	switch b {
+	case DBUS_BUS_SYSTEM:
+		return "DBUS_BUS_SYSTEM"
+	case DBUS_BUS_SESSION:
+		return "DBUS_BUS_SESSION"
+	default:
+		return "[?? Invalid DBusBus value]"
+	}
+}
+
+// IsValid returns true if the object path is well-formed
+func (p DBusObjectPath) IsValid() bool {
+	str := string(p)
+	if str == "/" {
+		return true
+	} else if strings.HasPrefix(str, "/") == false || strings.HasSuffix(str, "/") {
+		return false
+	}
+	for _, elem := range strings.Split(str[1:], "/") {
+		if elem == "" {
+			return false
+		}
+		for _, r := range elem {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+				continue
+			}
+			return false
+		}
+	}
+	return true
+}
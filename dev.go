@@ -5,6 +5,7 @@ import (
 	"image"
 	"net"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -15,8 +16,19 @@ import (
 	* Argon One case for Raspberry Pi (GPIO, Infrared, Fan Control)
 	* eInk Paper Displays (GPIO, SPI, Bitmaps)
 	* Google Chromecast control (mDNS, RPC, Protocol Buffers)
+	* DIAL receiver, so that phones can cast media to the device
 	* Rotel Amplifer control (via RS232)
 	* IKEA Tradfri Zigbee Gateway
+	* Zigbee devices through a zigbee2mqtt bridge (MQTT)
+	* Tasmota and ESPHome smart plugs, lights and sensors (MQTT, mDNS)
+	* ESC/POS thermal receipt printers (via RS232 or USB)
+	* GPS/GNSS receivers (NMEA or UBX via RS232, or gpsd)
+	* Raspberry Pi Sense HAT (LED matrix, joystick and sensors)
+	* 433/868MHz wireless sensors (RTL-SDR)
+	* Battery and UPS HATs (INA219 and MAX17040 fuel gauges)
+	* Ambient light sensors
+	* Air quality sensors (SGP30, SCD40 and PMS5003)
+	* Energy monitoring for smart plugs and meters
 
 	Ultimately these should be split out into separate repos...
 */
@@ -483,3 +495,900 @@ func (f CastFlag) FlagString() string {
 		return "[?? Invalid CastFlag value]"
 	}
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// DIAL RECEIVER
+
+// CastReceiverState is the state of an application on the receiver
+type CastReceiverState uint
+
+// CastReceiver advertises the device as a DIAL cast target and plays
+// media which is cast to it
+type CastReceiver interface {
+	// Name returns the friendly name which is advertised
+	Name() string
+
+	// Apps returns the names of applications which can be launched
+	Apps() []string
+
+	// State returns the state of an application and the URL of
+	// media being played
+	State(string) (CastReceiverState, string)
+
+	// Stop an application
+	Stop(string) error
+}
+
+// CastReceiverEvent is emitted when an application is launched or stopped
+type CastReceiverEvent interface {
+	Event
+
+	App() string
+	State() CastReceiverState
+	URL() string
+}
+
+const (
+	CAST_RECEIVER_STATE_STOPPED CastReceiverState = iota
+	CAST_RECEIVER_STATE_RUNNING
+)
+
+func (s CastReceiverState) String() string {
+	switch s {
+	case CAST_RECEIVER_STATE_STOPPED:
+		return "CAST_RECEIVER_STATE_STOPPED"
+	case CAST_RECEIVER_STATE_RUNNING:
+		return "CAST_RECEIVER_STATE_RUNNING"
+	default:
+		return "[?? Invalid CastReceiverState value]"
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ESC/POS THERMAL RECEIPT PRINTER
+
+// PrinterStyle defines text styles, which can be combined
+type PrinterStyle uint
+
+// PrinterAlign defines the alignment of text, bitmaps and codes
+type PrinterAlign uint
+
+// PrinterStatus defines the paper and cover status of a printer
+type PrinterStatus uint
+
+// Printer prints text, bitmaps and codes on a thermal receipt printer
+type Printer interface {
+	// Print text with styles. Lines are printed when a newline is written
+	Print(string, PrinterStyle) error
+
+	// SetAlign sets alignment for following lines
+	SetAlign(PrinterAlign) error
+
+	// Feed paper a number of lines
+	Feed(uint) error
+
+	// PrintBitmap prints an image dithered to black and white, scaled
+	// to the width of the paper if it is wider
+	PrintBitmap(image.Image) error
+
+	// PrintQRCode prints a QR code with an error correction level and
+	// module size in dots
+	PrintQRCode(string, QRLevel, uint) error
+
+	// PrintBarcode prints a linear barcode with the text below it
+	PrintBarcode(string, BarcodeType) error
+
+	// Cut paper, leaving one point uncut when partial is true
+	Cut(bool) error
+
+	// Status returns the paper and cover status
+	Status() (PrinterStatus, error)
+}
+
+// PrinterEvent is emitted when the printer status changes
+type PrinterEvent interface {
+	Event
+
+	Status() PrinterStatus
+}
+
+const (
+	PRINTER_STYLE_BOLD PrinterStyle = (1 << iota)
+	PRINTER_STYLE_UNDERLINE
+	PRINTER_STYLE_DOUBLE_WIDTH
+	PRINTER_STYLE_DOUBLE_HEIGHT
+	PRINTER_STYLE_INVERT
+	PRINTER_STYLE_NONE PrinterStyle = 0
+	PRINTER_STYLE_MIN               = PRINTER_STYLE_BOLD
+	PRINTER_STYLE_MAX               = PRINTER_STYLE_INVERT
+)
+
+const (
+	PRINTER_ALIGN_LEFT PrinterAlign = iota
+	PRINTER_ALIGN_CENTER
+	PRINTER_ALIGN_RIGHT
+)
+
+const (
+	PRINTER_STATUS_PAPER_LOW PrinterStatus = (1 << iota)
+	PRINTER_STATUS_PAPER_OUT
+	PRINTER_STATUS_COVER_OPEN
+	PRINTER_STATUS_OFFLINE
+	PRINTER_STATUS_NONE PrinterStatus = 0
+	PRINTER_STATUS_MIN                = PRINTER_STATUS_PAPER_LOW
+	PRINTER_STATUS_MAX                = PRINTER_STATUS_OFFLINE
+)
+
+func (f PrinterStyle) String() string {
+	if f == PRINTER_STYLE_NONE {
+		return f.FlagString()
+	}
+	str := ""
+	for v := PRINTER_STYLE_MIN; v <= PRINTER_STYLE_MAX; v <<= 1 {
+		if v&f == v {
+			str += "|" + v.FlagString()
+		}
+	}
+	return strings.TrimPrefix(str, "|")
+}
+
+func (f PrinterStyle) FlagString() string {
+	switch f {
+	case PRINTER_STYLE_NONE:
+		return "PRINTER_STYLE_NONE"
+	case PRINTER_STYLE_BOLD:
+		return "PRINTER_STYLE_BOLD"
+	case PRINTER_STYLE_UNDERLINE:
+		return "PRINTER_STYLE_UNDERLINE"
+	case PRINTER_STYLE_DOUBLE_WIDTH:
+		return "PRINTER_STYLE_DOUBLE_WIDTH"
+	case PRINTER_STYLE_DOUBLE_HEIGHT:
+		return "PRINTER_STYLE_DOUBLE_HEIGHT"
+	case PRINTER_STYLE_INVERT:
+		return "PRINTER_STYLE_INVERT"
+	default:
+		return "[?? Invalid PrinterStyle value]"
+	}
+}
+
+func (a PrinterAlign) String() string {
+	switch a {
+	case PRINTER_ALIGN_LEFT:
+		return "PRINTER_ALIGN_LEFT"
+	case PRINTER_ALIGN_CENTER:
+		return "PRINTER_ALIGN_CENTER"
+	case PRINTER_ALIGN_RIGHT:
+		return "PRINTER_ALIGN_RIGHT"
+	default:
+		return "[?? Invalid PrinterAlign value]"
+	}
+}
+
+func (f PrinterStatus) String() string {
+	if f == PRINTER_STATUS_NONE {
+		return f.FlagString()
+	}
+	str := ""
+	for v := PRINTER_STATUS_MIN; v <= PRINTER_STATUS_MAX; v <<= 1 {
+		if v&f == v {
+			str += "|" + v.FlagString()
+		}
+	}
+	return strings.TrimPrefix(str, "|")
+}
+
+func (f PrinterStatus) FlagString() string {
+	switch f {
+	case PRINTER_STATUS_NONE:
+		return "PRINTER_STATUS_NONE"
+	case PRINTER_STATUS_PAPER_LOW:
+		return "PRINTER_STATUS_PAPER_LOW"
+	case PRINTER_STATUS_PAPER_OUT:
+		return "PRINTER_STATUS_PAPER_OUT"
+	case PRINTER_STATUS_COVER_OPEN:
+		return "PRINTER_STATUS_COVER_OPEN"
+	case PRINTER_STATUS_OFFLINE:
+		return "PRINTER_STATUS_OFFLINE"
+	default:
+		return "[?? Invalid PrinterStatus value]"
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GPS/GNSS RECEIVER
+
+// GPSFix defines the quality of a position fix
+type GPSFix uint
+
+// GPSPosition is the position, velocity and time reported by a receiver
+type GPSPosition struct {
+	Time       time.Time // Time of fix in UTC
+	Fix        GPSFix    // Quality of fix
+	Latitude   float64   // Degrees, negative is south
+	Longitude  float64   // Degrees, negative is west
+	Altitude   float64   // Metres above mean sea level
+	Speed      float64   // Metres per second over ground
+	Course     float64   // Degrees from true north
+	Satellites uint      // Number of satellites used in fix
+	HDOP       float64   // Horizontal dilution of precision
+}
+
+// GPS reads position, velocity and time from a GPS/GNSS receiver
+type GPS interface {
+	// Position returns the most recent position, and false
+	// if no position has been received
+	Position() (GPSPosition, bool)
+}
+
+// GPSEvent is emitted when a position is received
+type GPSEvent interface {
+	Event
+
+	Position() GPSPosition
+}
+
+const (
+	GPS_FIX_NONE GPSFix = iota
+	GPS_FIX_2D
+	GPS_FIX_3D
+	GPS_FIX_DGPS
+	GPS_FIX_RTK
+	GPS_FIX_MAX = GPS_FIX_RTK
+)
+
+func (f GPSFix) String() string {
+	switch f {
+	case GPS_FIX_NONE:
+		return "GPS_FIX_NONE"
+	case GPS_FIX_2D:
+		return "GPS_FIX_2D"
+	case GPS_FIX_3D:
+		return "GPS_FIX_3D"
+	case GPS_FIX_DGPS:
+		return "GPS_FIX_DGPS"
+	case GPS_FIX_RTK:
+		return "GPS_FIX_RTK"
+	default:
+		return "[?? Invalid GPSFix value]"
+	}
+}
+
+func (p GPSPosition) String() string {
+	str := "<gps.position"
+	str += " fix=" + p.Fix.String()
+	if p.Time.IsZero() == false {
+		str += " time=" + p.Time.Format(time.RFC3339Nano)
+	}
+	if p.Fix != GPS_FIX_NONE {
+		str += " lat=" + strconv.FormatFloat(p.Latitude, 'f', 6, 64)
+		str += " lon=" + strconv.FormatFloat(p.Longitude, 'f', 6, 64)
+		if p.Fix != GPS_FIX_2D {
+			str += " alt=" + strconv.FormatFloat(p.Altitude, 'f', 1, 64)
+		}
+		str += " speed=" + strconv.FormatFloat(p.Speed, 'f', 2, 64)
+		str += " course=" + strconv.FormatFloat(p.Course, 'f', 1, 64)
+		str += " satellites=" + strconv.FormatUint(uint64(p.Satellites), 10)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ZIGBEE (ZIGBEE2MQTT BRIDGE)
+
+// ZigbeeEventType defines the type of a Zigbee event
+type ZigbeeEventType uint
+
+// ZigbeeManager controls Zigbee devices paired with a coordinator
+// through a zigbee2mqtt bridge
+type ZigbeeManager interface {
+	// Devices returns all devices paired with the coordinator
+	Devices() []ZigbeeDevice
+
+	// Device returns a device by name or IEEE address, or nil
+	Device(string) ZigbeeDevice
+
+	// PermitJoin allows devices to pair for a duration, or
+	// disables pairing when the duration is zero
+	PermitJoin(time.Duration) error
+
+	// Set sends properties to a device, for example "state" with
+	// a value of "ON" to switch on a light
+	Set(ZigbeeDevice, map[string]interface{}) error
+
+	// Get requests a device to report properties
+	Get(ZigbeeDevice, ...string) error
+
+	// Remove unpairs a device from the coordinator
+	Remove(ZigbeeDevice) error
+}
+
+// ZigbeeDevice is a device paired with the coordinator
+type ZigbeeDevice interface {
+	Name() string        // Friendly name
+	Address() string     // IEEE address
+	Type() string        // Coordinator, Router or EndDevice
+	Vendor() string      // Manufacturer
+	Model() string       // Model
+	Description() string // Description of model
+	Supported() bool     // Supported by zigbee2mqtt
+
+	// State returns the properties last reported by the device
+	State() map[string]interface{}
+}
+
+// ZigbeeEvent is emitted when a device reports state, pairs or leaves
+type ZigbeeEvent interface {
+	Event
+
+	Type() ZigbeeEventType
+	Device() ZigbeeDevice
+
+	// State returns properties reported with a state event
+	State() map[string]interface{}
+}
+
+const (
+	ZIGBEE_EVENT_NONE      ZigbeeEventType = iota
+	ZIGBEE_EVENT_STATE                     // Device reported state
+	ZIGBEE_EVENT_JOINED                    // Device joined the network
+	ZIGBEE_EVENT_INTERVIEW                 // Device interview completed
+	ZIGBEE_EVENT_LEFT                      // Device left the network
+	ZIGBEE_EVENT_MAX       = ZIGBEE_EVENT_LEFT
+)
+
+func (t ZigbeeEventType) String() string {
+	switch t {
+	case ZIGBEE_EVENT_NONE:
+		return "ZIGBEE_EVENT_NONE"
+	case ZIGBEE_EVENT_STATE:
+		return "ZIGBEE_EVENT_STATE"
+	case ZIGBEE_EVENT_JOINED:
+		return "ZIGBEE_EVENT_JOINED"
+	case ZIGBEE_EVENT_INTERVIEW:
+		return "ZIGBEE_EVENT_INTERVIEW"
+	case ZIGBEE_EVENT_LEFT:
+		return "ZIGBEE_EVENT_LEFT"
+	default:
+		return "[?? Invalid ZigbeeEventType value]"
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TASMOTA AND ESPHOME DEVICES
+
+// ESPFirmware defines the firmware of a device
+type ESPFirmware uint
+
+// ESPEntityType defines the type of an entity on a device
+type ESPEntityType uint
+
+// ESPEventType defines the type of an ESPEvent
+type ESPEventType uint
+
+// ESPManager discovers devices running Tasmota or ESPHome firmware,
+// and controls their switches and lights
+type ESPManager interface {
+	// Devices returns all discovered devices
+	Devices() []ESPDevice
+
+	// Device returns a device by identifier or name, or nil
+	Device(string) ESPDevice
+
+	// SetState switches a switch or light on or off
+	SetState(ESPEntity, bool) error
+
+	// SetBrightness sets the brightness of a light between
+	// zero and one
+	SetBrightness(ESPEntity, float32) error
+}
+
+// ESPDevice is a device running Tasmota or ESPHome
+type ESPDevice interface {
+	Id() string            // Unique identifier
+	Name() string          // Name of device
+	Firmware() ESPFirmware // Tasmota or ESPHome
+	Model() string         // Model of device
+	Version() string       // Firmware version
+	Addr() net.IP          // Address, or nil if unknown
+	Online() bool          // Device is connected
+	Entities() []ESPEntity // Switches, lights and sensors
+}
+
+// ESPEntity is a switch, light or sensor on a device
+type ESPEntity interface {
+	Id() string          // Identifier, unique for the device
+	Name() string        // Name of entity
+	Type() ESPEntityType // Type of entity
+	State() bool         // On or off for switches, lights and binary sensors
+	Brightness() float32 // Brightness of a light between zero and one
+	Value() float64      // Value of a sensor
+	Unit() string        // Unit of a sensor value
+}
+
+// ESPEvent is emitted when a device is discovered or goes online or
+// offline, and when the state of an entity changes
+type ESPEvent interface {
+	Event
+
+	Type() ESPEventType
+	Device() ESPDevice
+	Entity() ESPEntity // Entity which has changed, or nil
+}
+
+const (
+	ESP_FIRMWARE_NONE ESPFirmware = iota
+	ESP_FIRMWARE_TASMOTA
+	ESP_FIRMWARE_ESPHOME
+)
+
+const (
+	ESP_ENTITY_NONE ESPEntityType = iota
+	ESP_ENTITY_SWITCH
+	ESP_ENTITY_LIGHT
+	ESP_ENTITY_SENSOR
+	ESP_ENTITY_BINARY_SENSOR
+)
+
+const (
+	ESP_EVENT_NONE ESPEventType = iota
+	ESP_EVENT_ADDED
+	ESP_EVENT_ONLINE
+	ESP_EVENT_OFFLINE
+	ESP_EVENT_CHANGED
+)
+
+func (f ESPFirmware) String() string {
+	switch f {
+	case ESP_FIRMWARE_NONE:
+		return "ESP_FIRMWARE_NONE"
+	case ESP_FIRMWARE_TASMOTA:
+		return "ESP_FIRMWARE_TASMOTA"
+	case ESP_FIRMWARE_ESPHOME:
+		return "ESP_FIRMWARE_ESPHOME"
+	default:
+		return "[?? Invalid ESPFirmware value]"
+	}
+}
+
+func (t ESPEntityType) String() string {
+	switch t {
+	case ESP_ENTITY_NONE:
+		return "ESP_ENTITY_NONE"
+	case ESP_ENTITY_SWITCH:
+		return "ESP_ENTITY_SWITCH"
+	case ESP_ENTITY_LIGHT:
+		return "ESP_ENTITY_LIGHT"
+	case ESP_ENTITY_SENSOR:
+		return "ESP_ENTITY_SENSOR"
+	case ESP_ENTITY_BINARY_SENSOR:
+		return "ESP_ENTITY_BINARY_SENSOR"
+	default:
+		return "[?? Invalid ESPEntityType value]"
+	}
+}
+
+func (t ESPEventType) String() string {
+	switch t {
+	case ESP_EVENT_NONE:
+		return "ESP_EVENT_NONE"
+	case ESP_EVENT_ADDED:
+		return "ESP_EVENT_ADDED"
+	case ESP_EVENT_ONLINE:
+		return "ESP_EVENT_ONLINE"
+	case ESP_EVENT_OFFLINE:
+		return "ESP_EVENT_OFFLINE"
+	case ESP_EVENT_CHANGED:
+		return "ESP_EVENT_CHANGED"
+	default:
+		return "[?? Invalid ESPEventType value]"
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RASPBERRY PI SENSE HAT
+
+// SenseHAT drives the LED matrix and reads the joystick and sensors
+// on a Raspberry Pi Sense HAT
+type SenseHAT interface {
+	// Bitmap returns the LED matrix as an 8x8 bitmap, which is
+	// shown on the matrix when Update is called
+	Bitmap() Bitmap
+
+	// Update shows the bitmap on the LED matrix
+	Update() error
+
+	// Sensors returns the last sensor readings, or false if the
+	// sensors have not been read
+	Sensors() (SenseHATSensors, bool)
+}
+
+// SenseHATSensors are readings from the environment sensors and the
+// inertial measurement unit (IMU)
+type SenseHATSensors struct {
+	Time         time.Time
+	Temperature  float32    // Celcius
+	Humidity     float32    // Relative humidity in percent
+	Pressure     float32    // Hectopascals
+	Acceleration [3]float32 // X, Y and Z in g
+	Gyroscope    [3]float32 // X, Y and Z in degrees per second
+	Magnetometer [3]float32 // X, Y and Z in gauss
+}
+
+// SenseHATEvent is emitted when the sensors are read
+type SenseHATEvent interface {
+	Event
+
+	Sensors() SenseHATSensors
+}
+
+func (s SenseHATSensors) String() string {
+	vector := func(v [3]float32, prec int) string {
+		return strconv.FormatFloat(float64(v[0]), 'f', prec, 32) + "," +
+			strconv.FormatFloat(float64(v[1]), 'f', prec, 32) + "," +
+			strconv.FormatFloat(float64(v[2]), 'f', prec, 32)
+	}
+	str := "<sensehat.sensors"
+	if s.Time.IsZero() == false {
+		str += " time=" + s.Time.Format(time.RFC3339Nano)
+	}
+	str += " temperature=" + strconv.FormatFloat(float64(s.Temperature), 'f', 1, 32)
+	str += " humidity=" + strconv.FormatFloat(float64(s.Humidity), 'f', 1, 32)
+	str += " pressure=" + strconv.FormatFloat(float64(s.Pressure), 'f', 1, 32)
+	str += " acceleration=" + vector(s.Acceleration, 3)
+	str += " gyroscope=" + vector(s.Gyroscope, 1)
+	str += " magnetometer=" + vector(s.Magnetometer, 3)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// 433/868MHz WIRELESS SENSORS
+
+// RFField defines which values are present in a reading
+type RFField uint
+
+// RFSensors receives and decodes readings from wireless weather
+// stations, remote thermometers and tire pressure sensors
+type RFSensors interface {
+	// Frequency returns the receive frequency in Hz
+	Frequency() uint32
+
+	// Protocols returns the names of protocols which are decoded
+	Protocols() []string
+
+	// Readings returns the last reading from each sensor
+	Readings() []RFReading
+}
+
+// RFReading is a decoded transmission from a sensor
+type RFReading struct {
+	Time        time.Time
+	Protocol    string  // Name of protocol
+	Id          uint32  // Sensor identifier
+	Channel     uint    // Channel selected on the sensor
+	Fields      RFField // Values which are present
+	Temperature float32 // Celcius
+	Humidity    float32 // Relative humidity in percent
+	Pressure    float32 // Kilopascals
+	BatteryLow  bool    // Battery is low
+	RSSI        float32 // Signal strength in dB relative to full scale
+}
+
+// RFEvent is emitted when a reading is received
+type RFEvent interface {
+	Event
+
+	Reading() RFReading
+}
+
+const (
+	RF_FIELD_CHANNEL RFField = (1 << iota)
+	RF_FIELD_TEMPERATURE
+	RF_FIELD_HUMIDITY
+	RF_FIELD_PRESSURE
+	RF_FIELD_BATTERY
+	RF_FIELD_NONE RFField = 0
+	RF_FIELD_MIN          = RF_FIELD_CHANNEL
+	RF_FIELD_MAX          = RF_FIELD_BATTERY
+)
+
+// Key returns a unique key for the sensor
+func (r RFReading) Key() string {
+	key := r.Protocol + "/" + strconv.FormatUint(uint64(r.Id), 10)
+	if r.Fields&RF_FIELD_CHANNEL != 0 {
+		key += "/" + strconv.FormatUint(uint64(r.Channel), 10)
+	}
+	return key
+}
+
+func (r RFReading) String() string {
+	str := "<rf.reading"
+	if r.Time.IsZero() == false {
+		str += " time=" + r.Time.Format(time.RFC3339)
+	}
+	str += " protocol=" + strconv.Quote(r.Protocol)
+	str += " id=" + strconv.FormatUint(uint64(r.Id), 10)
+	if r.Fields&RF_FIELD_CHANNEL != 0 {
+		str += " channel=" + strconv.FormatUint(uint64(r.Channel), 10)
+	}
+	if r.Fields&RF_FIELD_TEMPERATURE != 0 {
+		str += " temperature=" + strconv.FormatFloat(float64(r.Temperature), 'f', 1, 32)
+	}
+	if r.Fields&RF_FIELD_HUMIDITY != 0 {
+		str += " humidity=" + strconv.FormatFloat(float64(r.Humidity), 'f', 0, 32)
+	}
+	if r.Fields&RF_FIELD_PRESSURE != 0 {
+		str += " pressure=" + strconv.FormatFloat(float64(r.Pressure), 'f', 1, 32)
+	}
+	if r.Fields&RF_FIELD_BATTERY != 0 {
+		str += " battery_low=" + strconv.FormatBool(r.BatteryLow)
+	}
+	str += " rssi=" + strconv.FormatFloat(float64(r.RSSI), 'f', 1, 32)
+	return str + ">"
+}
+
+func (f RFField) String() string {
+	if f == RF_FIELD_NONE {
+		return f.FlagString()
+	}
+	str := ""
+	for v := RF_FIELD_MIN; v <= RF_FIELD_MAX; v <<= 1 {
+		if v&f == v {
+			str += "|" + v.FlagString()
+		}
+	}
+	return strings.TrimPrefix(str, "|")
+}
+
+func (f RFField) FlagString() string {
+	switch f {
+	case RF_FIELD_NONE:
+		return "RF_FIELD_NONE"
+	case RF_FIELD_CHANNEL:
+		return "RF_FIELD_CHANNEL"
+	case RF_FIELD_TEMPERATURE:
+		return "RF_FIELD_TEMPERATURE"
+	case RF_FIELD_HUMIDITY:
+		return "RF_FIELD_HUMIDITY"
+	case RF_FIELD_PRESSURE:
+		return "RF_FIELD_PRESSURE"
+	case RF_FIELD_BATTERY:
+		return "RF_FIELD_BATTERY"
+	default:
+		return "[?? Invalid RFField value]"
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// BATTERY AND UPS HATS
+
+// UPSPower defines the source of power
+type UPSPower uint
+
+// UPSEventType defines the type of a UPSEvent
+type UPSEventType uint
+
+// UPS reads the battery of an uninterruptible power supply HAT
+type UPS interface {
+	// Status returns the last reading, or false if the fuel gauge
+	// has not been read
+	Status() (UPSStatus, bool)
+}
+
+// UPSStatus is a reading from the fuel gauge
+type UPSStatus struct {
+	Time    time.Time
+	Power   UPSPower // Mains or battery, if known
+	Voltage float32  // Battery voltage in volts
+	Current float32  // Amps, positive when charging and negative when discharging
+	Charge  float32  // State of charge in percent
+}
+
+// UPSEvent is emitted when the fuel gauge is read, when the source of
+// power changes, and before shutdown
+type UPSEvent interface {
+	Event
+
+	Type() UPSEventType
+	Status() UPSStatus
+}
+
+const (
+	UPS_POWER_NONE UPSPower = iota
+	UPS_POWER_MAINS
+	UPS_POWER_BATTERY
+)
+
+const (
+	UPS_EVENT_NONE     UPSEventType = iota
+	UPS_EVENT_STATUS                // Fuel gauge has been read
+	UPS_EVENT_MAINS                 // Power has switched to mains
+	UPS_EVENT_BATTERY               // Power has switched to battery
+	UPS_EVENT_SHUTDOWN              // Charge is below the shutdown threshold
+)
+
+func (p UPSPower) String() string {
+	switch p {
+	case UPS_POWER_NONE:
+		return "UPS_POWER_NONE"
+	case UPS_POWER_MAINS:
+		return "UPS_POWER_MAINS"
+	case UPS_POWER_BATTERY:
+		return "UPS_POWER_BATTERY"
+	default:
+		return "[?? Invalid UPSPower value]"
+	}
+}
+
+func (t UPSEventType) String() string {
+	switch t {
+	case UPS_EVENT_NONE:
+		return "UPS_EVENT_NONE"
+	case UPS_EVENT_STATUS:
+		return "UPS_EVENT_STATUS"
+	case UPS_EVENT_MAINS:
+		return "UPS_EVENT_MAINS"
+	case UPS_EVENT_BATTERY:
+		return "UPS_EVENT_BATTERY"
+	case UPS_EVENT_SHUTDOWN:
+		return "UPS_EVENT_SHUTDOWN"
+	default:
+		return "[?? Invalid UPSEventType value]"
+	}
+}
+
+func (s UPSStatus) String() string {
+	str := "<ups.status"
+	if s.Time.IsZero() == false {
+		str += " time=" + s.Time.Format(time.RFC3339)
+	}
+	str += " power=" + s.Power.String()
+	str += " voltage=" + strconv.FormatFloat(float64(s.Voltage), 'f', 3, 32)
+	str += " current=" + strconv.FormatFloat(float64(s.Current), 'f', 3, 32)
+	str += " charge=" + strconv.FormatFloat(float64(s.Charge), 'f', 1, 32)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// AMBIENT LIGHT SENSORS
+
+// LightSensor reads the illuminance from an ambient light sensor
+type LightSensor interface {
+	// Lux returns the last reading in lux, or false if the sensor has
+	// not been read
+	Lux() (float32, bool)
+}
+
+// LightEvent is emitted when an ambient light sensor is read
+type LightEvent interface {
+	Event
+
+	Lux() float32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// AIR QUALITY SENSORS
+
+// AirQuality reads carbon dioxide, volatile organic compounds and
+// particulate matter from air quality sensors
+type AirQuality interface {
+	// Reading returns the last reading from the sensors, or false if no
+	// sensor has been read after warming up
+	Reading() (AirQualityReading, bool)
+
+	// Calibrate sets the carbon dioxide concentration in ppm of the air
+	// around the sensors, such as 420 for outside air, for sensors which
+	// support forced recalibration
+	Calibrate(context.Context, float32) error
+}
+
+// AirQualityReading is the last reading from the sensors, where values
+// which are not measured are zero
+type AirQualityReading struct {
+	Time        time.Time
+	CO2         float32 // Carbon dioxide in ppm, or the equivalent from VOCs
+	TVOC        float32 // Total volatile organic compounds in ppb
+	PM1         float32 // Particulate matter under 1µm in µg/m³
+	PM25        float32 // Particulate matter under 2.5µm in µg/m³
+	PM10        float32 // Particulate matter under 10µm in µg/m³
+	Temperature float32 // Celsius
+	Humidity    float32 // Relative humidity in percent
+}
+
+// AirQualityEvent is emitted when a sensor is read after warming up
+type AirQualityEvent interface {
+	Event
+
+	Sensor() string // Name of the sensor which was read
+	Reading() AirQualityReading
+}
+
+func (r AirQualityReading) String() string {
+	str := "<airquality.reading"
+	if r.Time.IsZero() == false {
+		str += " time=" + r.Time.Format(time.RFC3339)
+	}
+	if r.CO2 != 0 {
+		str += " co2=" + strconv.FormatFloat(float64(r.CO2), 'f', 0, 32)
+	}
+	if r.TVOC != 0 {
+		str += " tvoc=" + strconv.FormatFloat(float64(r.TVOC), 'f', 0, 32)
+	}
+	if r.PM1 != 0 || r.PM25 != 0 || r.PM10 != 0 {
+		str += " pm1=" + strconv.FormatFloat(float64(r.PM1), 'f', 0, 32)
+		str += " pm25=" + strconv.FormatFloat(float64(r.PM25), 'f', 0, 32)
+		str += " pm10=" + strconv.FormatFloat(float64(r.PM10), 'f', 0, 32)
+	}
+	if r.Temperature != 0 || r.Humidity != 0 {
+		str += " temperature=" + strconv.FormatFloat(float64(r.Temperature), 'f', 1, 32)
+		str += " humidity=" + strconv.FormatFloat(float64(r.Humidity), 'f', 1, 32)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ENERGY MONITORING
+
+// EnergyPeriod defines the period over which energy usage is totalled
+type EnergyPeriod uint
+
+// EnergyMonitor aggregates power and energy readings from smart plugs
+// and meters into cumulative energy usage for each device, with daily
+// and monthly totals and the cost of energy from a tariff
+type EnergyMonitor interface {
+	// RecordPower records power in watts for a device, which is
+	// integrated over time when the device has no energy meter
+	RecordPower(device string, ts time.Time, watts float64) error
+
+	// RecordEnergy records the energy meter of a device in kWh. The
+	// meter may be reset to zero by the device
+	RecordEnergy(device string, ts time.Time, kwh float64) error
+
+	// Devices returns the names of devices which have readings
+	Devices() []string
+
+	// Usage returns energy usage for a device in the day or month which
+	// includes a time, or the total usage for ENERGY_PERIOD_TOTAL
+	Usage(device string, period EnergyPeriod, ts time.Time) EnergyUsage
+}
+
+// EnergyUsage is the energy used by a device over a period
+type EnergyUsage struct {
+	Device string
+	Period EnergyPeriod
+	Start  time.Time // Start of period, or first reading for total usage
+	Power  float64   // Last power reading in watts
+	Energy float64   // Energy in kWh
+	Cost   float64   // Cost of energy from the tariff
+}
+
+const (
+	ENERGY_PERIOD_TOTAL EnergyPeriod = iota
+	ENERGY_PERIOD_DAY
+	ENERGY_PERIOD_MONTH
+)
+
+func (p EnergyPeriod) String() string {
+	switch p {
+	case ENERGY_PERIOD_TOTAL:
+		return "ENERGY_PERIOD_TOTAL"
+	case ENERGY_PERIOD_DAY:
+		return "ENERGY_PERIOD_DAY"
+	case ENERGY_PERIOD_MONTH:
+		return "ENERGY_PERIOD_MONTH"
+	default:
+		return "[?? Invalid EnergyPeriod value]"
+	}
+}
+
+func (u EnergyUsage) String() string {
+	str := "<energy.usage"
+	str += " device=" + strconv.Quote(u.Device)
+	str += " period=" + u.Period.String()
+	if u.Start.IsZero() == false {
+		str += " start=" + u.Start.Format(time.RFC3339)
+	}
+	str += " power=" + strconv.FormatFloat(u.Power, 'f', 1, 64)
+	str += " energy=" + strconv.FormatFloat(u.Energy, 'f', 3, 64)
+	str += " cost=" + strconv.FormatFloat(u.Cost, 'f', 2, 64)
+	return str + ">"
+}
@@ -1,12 +1,28 @@
 package gopi
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
 
 ///////////////////////////////////////////////////////////////////////////////
 // Types
 
 type Error uint
 
+// UnitError wraps an error with the unit, resource and operation
+// where it occurred, so the error can be logged or returned to a
+// client with structured context
+type UnitError struct {
+	Unit     string
+	Resource string
+	Op       string
+	Err      error
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // Globals
 
@@ -21,6 +37,10 @@ const (
 	ErrDuplicateEntry
 	ErrOutOfOrder
 	ErrChannelFull
+	ErrPermissionDenied
+	ErrUnavailable
+	ErrTimeout
+	ErrCancelled
 )
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -48,6 +68,14 @@ func (e Error) Error() string {
 		return "Out of Order"
 	case ErrChannelFull:
 		return "Channel Full"
+	case ErrPermissionDenied:
+		return "Permission Denied"
+	case ErrUnavailable:
+		return "Unavailable"
+	case ErrTimeout:
+		return "Timeout"
+	case ErrCancelled:
+		return "Cancelled"
 	default:
 		return "[?? Invalid Error]"
 	}
@@ -56,3 +84,107 @@ func (e Error) Error() string {
 func (e Error) WithPrefix(p ...interface{}) error {
 	return fmt.Errorf("%v: %w", fmt.Sprint(p...), e)
 }
+
+// WithContext returns the error wrapped with the unit, resource and
+// operation where it occurred. Any of these can be empty
+func (e Error) WithContext(unit, resource, op string) error {
+	return &UnitError{unit, resource, op, e}
+}
+
+// Retryable returns true if the same call may succeed when attempted
+// again later
+func (e Error) Retryable() bool {
+	switch e {
+	case ErrChannelFull, ErrUnavailable, ErrTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// HttpStatus returns the HTTP status code used when the error is
+// returned to a client
+func (e Error) HttpStatus() int {
+	switch e {
+	case ErrNone:
+		return http.StatusOK
+	case ErrBadParameter, ErrHelp, ErrOutOfOrder:
+		return http.StatusBadRequest
+	case ErrNotImplemented:
+		return http.StatusNotImplemented
+	case ErrNotFound:
+		return http.StatusNotFound
+	case ErrUnexpectedResponse:
+		return http.StatusBadGateway
+	case ErrDuplicateEntry:
+		return http.StatusConflict
+	case ErrChannelFull:
+		return http.StatusTooManyRequests
+	case ErrPermissionDenied:
+		return http.StatusForbidden
+	case ErrUnavailable:
+		return http.StatusServiceUnavailable
+	case ErrTimeout:
+		return http.StatusGatewayTimeout
+	case ErrCancelled:
+		// Client Closed Request
+		return 499
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// UnitError
+
+// WrapError returns an error wrapped with the unit, resource and
+// operation where it occurred, or nil if the error is nil
+func WrapError(err error, unit, resource, op string) error {
+	if err == nil {
+		return nil
+	} else {
+		return &UnitError{unit, resource, op, err}
+	}
+}
+
+func (e *UnitError) Error() string {
+	parts := make([]string, 0, 4)
+	if e.Unit != "" {
+		parts = append(parts, e.Unit)
+	}
+	if op := strings.TrimSpace(e.Op + " " + e.Resource); op != "" {
+		parts = append(parts, op)
+	}
+	return strings.Join(append(parts, fmt.Sprint(e.Err)), ": ")
+}
+
+func (e *UnitError) Unwrap() error {
+	return e.Err
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Error codes
+
+// ErrorCode returns the gopi.Error for any error, by unwrapping it.
+// Context errors return ErrTimeout or ErrCancelled, ErrNone is returned
+// for nil and ErrInternalAppError for any other error
+func ErrorCode(err error) Error {
+	var code Error
+	if err == nil {
+		return ErrNone
+	} else if errors.As(err, &code) {
+		return code
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	} else if errors.Is(err, context.Canceled) {
+		return ErrCancelled
+	} else {
+		return ErrInternalAppError
+	}
+}
+
+// IsRetryable returns true if a call which returned an error may
+// succeed when attempted again later
+func IsRetryable(err error) bool {
+	return err != nil && ErrorCode(err).Retryable()
+}
@@ -0,0 +1,134 @@
+package gopi
+
+import (
+	"context"
+	"time"
+)
+
+/*
+	This file contains interface definitions for data feeds which are
+	fetched periodically, for dashboards:
+
+	* Weather conditions and forecasts
+	* Events from iCal calendars
+	* Feeds are cached so they are available when offline
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	WeatherCondition uint
+)
+
+// Weather is the current conditions and the forecast for a location
+type Weather struct {
+	Latitude  float64             `json:"latitude"`
+	Longitude float64             `json:"longitude"`
+	Updated   time.Time           `json:"updated"`
+	Current   WeatherConditions   `json:"current"`
+	Hourly    []WeatherConditions `json:"hourly,omitempty"`
+	Daily     []WeatherConditions `json:"daily,omitempty"`
+}
+
+// WeatherConditions are the conditions at a time. For daily forecasts,
+// Temperature is the maximum and TemperatureMin the minimum
+type WeatherConditions struct {
+	Time           time.Time        `json:"time"`
+	Condition      WeatherCondition `json:"condition"`
+	Temperature    float32          `json:"temperature"`               // Celsius
+	TemperatureMin float32          `json:"temperature_min,omitempty"` // Celsius
+	Humidity       float32          `json:"humidity,omitempty"`        // Percent
+	Precipitation  float32          `json:"precipitation,omitempty"`   // Millimetres
+	WindSpeed      float32          `json:"wind_speed,omitempty"`      // Metres per second
+	WindDirection  float32          `json:"wind_direction,omitempty"`  // Degrees
+}
+
+// CalendarEvent is an event in a calendar. Recurring events are
+// returned once for each occurrence
+type CalendarEvent struct {
+	Calendar    string    `json:"calendar"`
+	Uid         string    `json:"uid"`
+	Summary     string    `json:"summary"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	AllDay      bool      `json:"all_day,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// WeatherFeed fetches weather for a location periodically
+type WeatherFeed interface {
+	// Weather returns the latest weather, and false if weather has
+	// not been fetched or read from the cache
+	Weather() (Weather, bool)
+
+	// Update fetches weather now
+	Update(context.Context) error
+}
+
+// CalendarFeed fetches events from calendars periodically
+type CalendarFeed interface {
+	// Events returns events which overlap a time range, ordered
+	// by start time
+	Events(from, to time.Time) []CalendarEvent
+
+	// Update fetches calendars now
+	Update(context.Context) error
+}
+
+// FeedEvent is emitted when a feed is updated, or fails to update.
+// The name of the event is the name of the feed
+type FeedEvent interface {
+	Event
+
+	Updated() time.Time // Time the feed was updated
+	Error() error       // Set when the update failed
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	WEATHER_CONDITION_NONE WeatherCondition = iota
+	WEATHER_CONDITION_CLEAR
+	WEATHER_CONDITION_PARTLY_CLOUDY
+	WEATHER_CONDITION_CLOUDY
+	WEATHER_CONDITION_FOG
+	WEATHER_CONDITION_DRIZZLE
+	WEATHER_CONDITION_RAIN
+	WEATHER_CONDITION_SNOW
+	WEATHER_CONDITION_THUNDERSTORM
+	WEATHER_CONDITION_MAX = WEATHER_CONDITION_THUNDERSTORM
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (c WeatherCondition) String() string {
+	switch c {
+	case WEATHER_CONDITION_NONE:
+		return "WEATHER_CONDITION_NONE"
+	case WEATHER_CONDITION_CLEAR:
+		return "WEATHER_CONDITION_CLEAR"
+	case WEATHER_CONDITION_PARTLY_CLOUDY:
+		return "WEATHER_CONDITION_PARTLY_CLOUDY"
+	case WEATHER_CONDITION_CLOUDY:
+		return "WEATHER_CONDITION_CLOUDY"
+	case WEATHER_CONDITION_FOG:
+		return "WEATHER_CONDITION_FOG"
+	case WEATHER_CONDITION_DRIZZLE:
+		return "WEATHER_CONDITION_DRIZZLE"
+	case WEATHER_CONDITION_RAIN:
+		return "WEATHER_CONDITION_RAIN"
+	case WEATHER_CONDITION_SNOW:
+		return "WEATHER_CONDITION_SNOW"
+	case WEATHER_CONDITION_THUNDERSTORM:
+		return "WEATHER_CONDITION_THUNDERSTORM"
+	default:
+		return "[?? Invalid WeatherCondition value]"
+	}
+}
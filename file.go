@@ -1,13 +1,17 @@
 package gopi
 
-import "strings"
+import (
+	"os"
+	"strings"
+	"time"
+)
 
 /*
 	This file contains interface defininitons for filesystem
 	interface:
 
-	* Polling files for read and write conitions
-	* Polling filesystems for changes (TODO)
+	* Polling files for read and write conitions, timers and signals
+	* Watching files and directories for changes
 
 */
 
@@ -20,6 +24,12 @@ type (
 
 	// FilePollFunc is the handler for file polling
 	FilePollFunc func(uintptr, FilePollFlags)
+
+	// FilePollSignalFunc is the handler for signals
+	FilePollSignalFunc func(os.Signal)
+
+	// FSEventFlags describe a change to a file or directory
+	FSEventFlags uint
 )
 
 /////////////////////////////////////////////////////////////////////
@@ -29,19 +39,66 @@ type FilePoll interface {
 	// Watch a file descriptor for changes
 	Watch(uintptr, FilePollFlags, FilePollFunc) error
 
-	// Unwatch a file descriptor
+	// Unwatch a file descriptor, timer or signal handler. It waits for
+	// running handlers to return, so cannot be called from a handler
 	Unwatch(uintptr) error
+
+	// Timer calls a function after a duration, or repeatedly when
+	// the second argument is true, and returns a file descriptor
+	// which can be used to unwatch the timer
+	Timer(time.Duration, bool, FilePollFunc) (uintptr, error)
+
+	// Signal calls a function when any of the signals are received,
+	// and returns a file descriptor which can be used to unwatch
+	Signal(FilePollSignalFunc, ...os.Signal) (uintptr, error)
+}
+
+// FSWatcher emits FSEvent events when watched files and
+// directories are created, modified, deleted or renamed
+type FSWatcher interface {
+	// Watch a file or directory, and any subdirectories when
+	// the second argument is true
+	Watch(string, bool) error
+
+	// Unwatch a file or directory
+	Unwatch(string) error
+
+	// Paths returns the watched files and directories
+	Paths() []string
+}
+
+// FSEvent is emitted by the FSWatcher
+type FSEvent interface {
+	Event
+
+	Path() string        // Path to the file or directory
+	OldPath() string     // Previous path when renamed
+	Flags() FSEventFlags // Type of change
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // CONSTANTS
 
 const (
-	FILEPOLL_FLAG_READ  FilePollFlags = (1 << iota) // File descriptor ready for reading
-	FILEPOLL_FLAG_WRITE                             // File descriptor ready for writing
-	FILEPOLL_FLAG_NONE  FilePollFlags = 0
-	FILEPOLL_FLAG_MIN   FilePollFlags = FILEPOLL_FLAG_READ
-	FILEPOLL_FLAG_MAX   FilePollFlags = FILEPOLL_FLAG_WRITE
+	FILEPOLL_FLAG_READ     FilePollFlags = (1 << iota) // File descriptor ready for reading
+	FILEPOLL_FLAG_WRITE                                // File descriptor ready for writing
+	FILEPOLL_FLAG_PRIORITY                             // Priority data available (ie, GPIO edges)
+	FILEPOLL_FLAG_HANGUP                               // File descriptor hang up
+	FILEPOLL_FLAG_EDGE                                 // Edge-triggered rather than level-triggered
+	FILEPOLL_FLAG_NONE     FilePollFlags = 0
+	FILEPOLL_FLAG_MIN      FilePollFlags = FILEPOLL_FLAG_READ
+	FILEPOLL_FLAG_MAX      FilePollFlags = FILEPOLL_FLAG_EDGE
+)
+
+const (
+	FSEVENT_FLAG_CREATED  FSEventFlags = (1 << iota) // File or directory created
+	FSEVENT_FLAG_MODIFIED                            // File written or attributes changed
+	FSEVENT_FLAG_DELETED                             // File or directory deleted
+	FSEVENT_FLAG_RENAMED                             // File or directory renamed within watched paths
+	FSEVENT_FLAG_DIR                                 // Event refers to a directory
+	FSEVENT_FLAG_NONE     FSEventFlags = 0
+	FSEVENT_FLAG_MIN      FSEventFlags = FSEVENT_FLAG_CREATED
+	FSEVENT_FLAG_MAX      FSEventFlags = FSEVENT_FLAG_DIR
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -68,7 +125,45 @@ func (f FilePollFlags) StringFlag() string {
 		return "FILEPOLL_FLAG_READ"
 	case FILEPOLL_FLAG_WRITE:
 		return "FILEPOLL_FLAG_WRITE"
+	case FILEPOLL_FLAG_PRIORITY:
+		return "FILEPOLL_FLAG_PRIORITY"
+	case FILEPOLL_FLAG_HANGUP:
+		return "FILEPOLL_FLAG_HANGUP"
+	case FILEPOLL_FLAG_EDGE:
+		return "FILEPOLL_FLAG_EDGE"
 	default:
 		return "[?? Invalid FilePollFlags value]"
 	}
 }
+
+func (f FSEventFlags) String() string {
+	str := ""
+	if f == FSEVENT_FLAG_NONE {
+		return f.StringFlag()
+	}
+	for v := FSEVENT_FLAG_MIN; v <= FSEVENT_FLAG_MAX; v <<= 1 {
+		if f&v == v {
+			str += v.StringFlag() + "|"
+		}
+	}
+	return strings.TrimSuffix(str, "|")
+}
+
+func (f FSEventFlags) StringFlag() string {
+	switch f {
+	case FSEVENT_FLAG_NONE:
+		return "FSEVENT_FLAG_NONE"
+	case FSEVENT_FLAG_CREATED:
+		return "FSEVENT_FLAG_CREATED"
+	case FSEVENT_FLAG_MODIFIED:
+		return "FSEVENT_FLAG_MODIFIED"
+	case FSEVENT_FLAG_DELETED:
+		return "FSEVENT_FLAG_DELETED"
+	case FSEVENT_FLAG_RENAMED:
+		return "FSEVENT_FLAG_RENAMED"
+	case FSEVENT_FLAG_DIR:
+		return "FSEVENT_FLAG_DIR"
+	default:
+		return "[?? Invalid FSEventFlags value]"
+	}
+}
@@ -14,6 +14,7 @@ require (
 	github.com/niemeyer/pretty v0.0.0-20200227124842-a10e7caefd8e // indirect
 	github.com/olekukonko/tablewriter v0.0.4
 	github.com/pion/dtls/v2 v2.0.8
+	github.com/pion/webrtc/v3 v3.0.11
 	github.com/pkg/term v1.1.0
 	golang.org/x/image v0.0.0-20201208152932-35266b937fa6
 	golang.org/x/net v0.0.0-20210119194325-5f4716e94777
@@ -3,6 +3,7 @@ package gopi
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"testing"
@@ -25,6 +26,12 @@ type Config interface {
 	FlagInt(string, int, string, ...string) *int
 	FlagDuration(string, time.Duration, string, ...string) *time.Duration
 	FlagFloat(string, float64, string, ...string) *float64
+	FlagPath(string, string, string, ...string) *string   // Define a file or folder included in bundles
+	FlagSecret(string, string, string, ...string) *string // Define a password, key or token which is not recorded
+
+	// Define a group of flags with a prefix from the tagged fields of a
+	// pointer to a struct, which are bound to the fields
+	FlagStruct(string, interface{}, ...string) error
 
 	// Define a command with name, description, calling function
 	Command(string, string, CommandFunc) error
@@ -39,6 +46,28 @@ type Config interface {
 	GetInt(string) int
 	GetDuration(string) time.Duration
 	GetFloat(string) float64
+
+	// Export writes the set flags and the files and folders named by
+	// path flags as a bundle signed with a key
+	Export(io.Writer, []byte) error
+
+	// Import verifies a bundle signed with a key, sets flags which have
+	// not been set and writes files which do not exist
+	Import(io.Reader, []byte) error
+
+	// Validate checks the ranges, choices and required fields of flag
+	// groups and returns any errors
+	Validate() error
+
+	// Values returns flag values keyed by name, for flags which have
+	// been set or for all flags when the argument is true
+	Values(bool) map[string]string
+
+	// Set sets the value of a flag
+	Set(string, string) error
+
+	// Secret returns true when a flag holds a password, key or token
+	Secret(string) bool
 }
 
 // CommandFunc is the function signature for running a command
@@ -102,11 +131,56 @@ type Promise interface {
 	Finally(func(interface{}, error) error, bool) error
 }
 
+// UnitManager enables and disables units at runtime. Units tagged
+// `unit:",optional"` are disabled when they cannot be created, and
+// units tagged `unit:",lazy"` are only created when enabled. Fields
+// which refer to a disabled unit are nil
+type UnitManager interface {
+	// Return the names of optional and lazy units
+	Units() []string
+
+	// Return true if a unit is enabled
+	Enabled(string) bool
+
+	// Enable creates and runs a unit, and can only be called while
+	// units are running
+	Enable(string) error
+
+	// Disable stops and disposes a unit
+	Disable(string) error
+
+	// Graph returns the name of every unit with the names of the
+	// units it uses
+	Graph() map[string][]string
+}
+
+// Shell runs interactive sessions which call methods on units
+type Shell interface {
+	// Run a session which reads command lines and writes output until
+	// the session is quit, input ends or the context is cancelled
+	Run(context.Context, io.Reader, io.Writer) error
+
+	// Complete returns completion candidates for a partial line
+	Complete(string) []string
+}
+
 /////////////////////////////////////////////////////////////////////
 // UNITS
 
-// Unit marks an singleton object
-type Unit struct{}
+// Unit marks an singleton object, or one instance of a unit when
+// fields are tagged with an instance name, for example:
+//
+//	type App struct {
+//	  gopi.Unit
+//	  Bus1 gopi.I2C `unit:"1"`
+//	  Bus3 gopi.I2C `unit:"3"`
+//	}
+//
+// Each instance defines its own flags, so that the instance named "1"
+// defines -i2c.1.timeout rather than -i2c.timeout
+type Unit struct {
+	name string
+}
 
 /////////////////////////////////////////////////////////////////////
 // PUBLIC FUNCTIONS
@@ -116,6 +190,38 @@ func (this *Unit) New(Config) error          { /* NOOP */ return nil }
 func (this *Unit) Run(context.Context) error { /* NOOP */ return nil }
 func (this *Unit) Dispose() error            { /* NOOP */ return nil }
 
+/////////////////////////////////////////////////////////////////////
+// PLUGINS
+
+// PluginAPI is the version of the plugin interface. A plugin built
+// for a different version is not loaded
+const PluginAPI = 1
+
+// PluginInfo is exported by a plugin as the variable "Plugin", and
+// identifies the plugin and the plugin interface version it was
+// built for, for example:
+//
+//	var Plugin = gopi.PluginInfo{Name: "vendor.gpio", Version: "v1.0.0", API: gopi.PluginAPI}
+//
+// Units are registered in the init function of the plugin, and
+// replace units registered for the same interface
+type PluginInfo struct {
+	Name    string // Name of the plugin
+	Version string // Version of the plugin
+	API     uint   // Plugin interface version
+}
+
+/////////////////////////////////////////////////////////////////////
+// INSTANCES
+
+// InstanceName returns the name of the unit instance, or an empty
+// string for a singleton
+func (this *Unit) InstanceName() string { return this.name }
+
+// SetInstanceName is called when the unit is created to set the
+// name of the instance
+func (this *Unit) SetInstanceName(name string) { this.name = name }
+
 /////////////////////////////////////////////////////////////////////
 // REQUIRE
 
@@ -1,11 +1,13 @@
 package gopi
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"image/color"
 	"os"
 	"strings"
+	"time"
 )
 
 /*
@@ -13,7 +15,14 @@ import (
 
 	* Graphic surfaces
 	* Pixel Formats, Bitmaps
+	* QR codes and barcodes painted on bitmaps
 	* Fonts
+	* Animation clock for per-frame callbacks
+	* Photo slideshow on a surface, and remote control over RPC
+	* Cache of decoded and scaled images, decoded ahead of use
+	* Clock widget showing the time in one or more timezones
+	* Web pages shown on a surface alongside native surfaces
+	* Light and dark themes of colours for widgets, with automatic brightness
 
 	There is yet to be interfaces for drawable surfaces (3D and 2D)
 */
@@ -30,6 +39,23 @@ type (
 
 	// SurfaceFormat defines the pixel format for a surface
 	SurfaceFormat uint
+
+	// QRLevel is the error correction level for a QR code
+	QRLevel uint
+
+	// BarcodeType defines the symbology for a linear barcode
+	BarcodeType uint
+
+	// SurfaceTransformFlags define rotation and flipping of a surface
+	SurfaceTransformFlags uint
+
+	// ThemeMode defines whether light or dark colours are used
+	ThemeMode uint
+
+	// AnimationFunc is called on each frame with the time elapsed since
+	// the animation started and since the previous frame. It returns
+	// false to end the animation
+	AnimationFunc func(elapsed, delta time.Duration) bool
 )
 
 type FontSize struct {
@@ -37,6 +63,16 @@ type FontSize struct {
 	Unit FontSizeUnit
 }
 
+// SurfaceTransform rotates and flips a surface, and scales the source
+// region of the surface bitmap to the destination region on screen. An
+// empty source is the whole bitmap, and an empty destination is the
+// surface origin and size
+type SurfaceTransform struct {
+	Flags SurfaceTransformFlags
+	Src   image.Rectangle
+	Dest  image.Rectangle
+}
+
 //type SurfaceManagerCallback func(GraphicsContext) error
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -56,6 +92,9 @@ type SurfaceManager interface {
 	// DisposeBitmap discards a bitmap
 	DisposeBitmap(Bitmap) error
 
+	// SetTransform rotates, flips and scales a surface using the GPU
+	SetTransform(Surface, SurfaceTransform) error
+
 	// Do method is used to make graphics updates
 	//Do(SurfaceManagerCallback) error
 }
@@ -78,6 +117,167 @@ type Bitmap interface {
 	Size() Size
 	ClearToColor(color.Color)
 	SetAt(color.Color, int, int) error
+
+	// Lock returns the pixel data for reading and writing directly,
+	// and the stride between rows in bytes. Unlock must be called
+	// to make changes visible before using other methods
+	Lock() ([]byte, uint32, error)
+	Unlock() error
+
+	// PaintQRCode paints a QR code for data with an error correction
+	// level, as large as possible within bounds
+	PaintQRCode(string, QRLevel, image.Rectangle) error
+
+	// PaintBarcode paints a linear barcode for data, filling the
+	// bounds
+	PaintBarcode(string, BarcodeType, image.Rectangle) error
+}
+
+// AnimationClock delivers per-frame callbacks to animations, which share
+// a single frame loop. The clock is driven by the surface manager on each
+// vertical sync, or by its own ticker when no frames are delivered
+type AnimationClock interface {
+	// Animate adds an animation, which is called on each frame until
+	// it returns false
+	Animate(AnimationFunc) error
+
+	// Frame advances the clock and calls animations, and is called
+	// by the surface manager on each vertical sync
+	Frame(time.Time)
+
+	// Pause stops or starts time for all animations
+	Pause(bool)
+	Paused() bool
+
+	// SetScale sets the rate at which time passes for animations,
+	// where 1.0 is real time
+	SetScale(float32) error
+	Scale() float32
+}
+
+// Slideshow shows images from a folder on a surface, with pan and zoom
+// and crossfade transitions
+type Slideshow interface {
+	// Start showing images on a surface
+	Start(Surface) error
+
+	// Stop showing images
+	Stop() error
+
+	// Next and Previous change to another image
+	Next() error
+	Previous() error
+
+	// Pause stops or starts changing images
+	Pause(bool)
+	Paused() bool
+
+	// Current returns the path of the current image, the index of the
+	// image and the number of images
+	Current() (string, uint, uint)
+}
+
+// ImageCache holds images which are decoded, rotated and scaled, so that
+// they can be shown without stutter. Images are decoded ahead of use in
+// the background, and the least recently used images which are not in
+// use are released when the cache exceeds a memory budget
+type ImageCache interface {
+	// Get returns an image from a file, upright using EXIF orientation
+	// and rotated clockwise by a multiple of 90 degrees, and scaled to
+	// cover a size. The image is decoded when it is not in the cache,
+	// and is kept until released
+	Get(path string, format SurfaceFormat, size Size, rotation uint) (Bitmap, error)
+
+	// Release an image returned by Get
+	Release(Bitmap) error
+
+	// Prefetch decodes images in order in the background, replacing any
+	// images which are waiting to be decoded
+	Prefetch(format SurfaceFormat, size Size, rotation uint, paths ...string)
+
+	// Purge releases all images which are not in use
+	Purge()
+}
+
+// ClockWidget draws the time and date in an analog or digital style for
+// one or more timezones, and flags the time when the system clock is not
+// synchronized
+type ClockWidget interface {
+	// Start drawing the clock within bounds of a surface each second,
+	// where empty bounds is the whole surface
+	Start(Surface, image.Rectangle) error
+
+	// Stop drawing the clock
+	Stop() error
+
+	// Draw paints the clock for a time within bounds of a bitmap
+	Draw(Bitmap, image.Rectangle, time.Time) error
+}
+
+// WebView shows a web page, such as a dashboard, within bounds of a
+// surface. The page is rendered by a browser without a window, and each
+// frame the browser paints is drawn on the surface
+type WebView interface {
+	// Start the browser and show the current page within bounds of a
+	// surface, where empty bounds is the whole surface
+	Start(Surface, image.Rectangle) error
+
+	// Stop showing the page and stop the browser
+	Stop() error
+
+	// Navigate to a URL, which is shown when the browser is started
+	Navigate(string) error
+
+	// URL returns the current page
+	URL() string
+}
+
+// Theme provides the colours which widgets draw with, by names such as
+// "background" and "foreground", and switches between light and dark
+// colours. Automatically, dark colours are used when it is dark, and the
+// backlight is dimmed gradually as it gets darker
+type Theme interface {
+	// Mode returns the mode which is set, which is THEME_MODE_AUTO when
+	// switching automatically
+	Mode() ThemeMode
+
+	// SetMode sets light or dark colours, or automatic switching
+	SetMode(ThemeMode) error
+
+	// Dark returns true when dark colours are used
+	Dark() bool
+
+	// Color returns the colour for a name, or an empty string when there
+	// is no colour with the name
+	Color(string) string
+
+	// Names returns the names of colours
+	Names() []string
+}
+
+// ThemeEvent is emitted when switching between light and dark colours
+type ThemeEvent interface {
+	Event
+
+	Dark() bool
+}
+
+// SlideshowService defines an RPC service to control a slideshow
+type SlideshowService interface {
+	Service
+}
+
+// SlideshowStub is an RPC client which connects to the RPC service
+type SlideshowStub interface {
+	ServiceStub
+
+	Next(context.Context) error
+	Previous(context.Context) error
+	Pause(context.Context, bool) error
+
+	// Current returns the path of the current image, the index of the
+	// image, the number of images and whether the slideshow is paused
+	Current(context.Context) (string, uint, uint, bool, error)
 }
 
 // FontManager for font management
@@ -147,6 +347,18 @@ const (
 	SURFACE_FLAG_MAX               = SURFACE_FLAG_OPENVG
 )
 
+const (
+	SURFACE_TRANSFORM_ROTATE_90 SurfaceTransformFlags = (1 << iota)
+	SURFACE_TRANSFORM_ROTATE_180
+	SURFACE_TRANSFORM_ROTATE_270
+	SURFACE_TRANSFORM_FLIP_HORIZONTAL
+	SURFACE_TRANSFORM_FLIP_VERTICAL
+	SURFACE_TRANSFORM_NONE   SurfaceTransformFlags = 0
+	SURFACE_TRANSFORM_ROTATE                       = SURFACE_TRANSFORM_ROTATE_90 | SURFACE_TRANSFORM_ROTATE_180 | SURFACE_TRANSFORM_ROTATE_270
+	SURFACE_TRANSFORM_MIN                          = SURFACE_TRANSFORM_ROTATE_90
+	SURFACE_TRANSFORM_MAX                          = SURFACE_TRANSFORM_FLIP_VERTICAL
+)
+
 const (
 	SURFACE_FMT_NONE   SurfaceFormat = iota
 	SURFACE_FMT_RGBA32               // 4 bytes per pixel with transparency
@@ -157,6 +369,27 @@ const (
 	SURFACE_FMT_MAX    = SURFACE_FMT_1BPP
 )
 
+const (
+	QR_LEVEL_L   QRLevel = iota // Recovers 7% of data
+	QR_LEVEL_M                  // Recovers 15% of data
+	QR_LEVEL_Q                  // Recovers 25% of data
+	QR_LEVEL_H                  // Recovers 30% of data
+	QR_LEVEL_MAX = QR_LEVEL_H
+)
+
+const (
+	THEME_MODE_AUTO  ThemeMode = iota // Switch automatically
+	THEME_MODE_LIGHT                  // Light colours
+	THEME_MODE_DARK                   // Dark colours
+)
+
+const (
+	BARCODE_CODE128 BarcodeType = iota // ASCII text
+	BARCODE_EAN13                      // 12 digits and optional check digit
+	BARCODE_EAN8                       // 7 digits and optional check digit
+	BARCODE_MAX     = BARCODE_EAN8
+)
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -224,6 +457,38 @@ func (f SurfaceFlags) StringFlag() string {
 	}
 }
 
+func (f SurfaceTransformFlags) String() string {
+	if f == SURFACE_TRANSFORM_NONE {
+		return f.StringFlag()
+	}
+	str := ""
+	for v := SURFACE_TRANSFORM_MIN; v <= SURFACE_TRANSFORM_MAX; v <<= 1 {
+		if f&v == v {
+			str += v.StringFlag() + "|"
+		}
+	}
+	return strings.TrimSuffix(str, "|")
+}
+
+func (f SurfaceTransformFlags) StringFlag() string {
+	switch f {
+	case SURFACE_TRANSFORM_NONE:
+		return "SURFACE_TRANSFORM_NONE"
+	case SURFACE_TRANSFORM_ROTATE_90:
+		return "SURFACE_TRANSFORM_ROTATE_90"
+	case SURFACE_TRANSFORM_ROTATE_180:
+		return "SURFACE_TRANSFORM_ROTATE_180"
+	case SURFACE_TRANSFORM_ROTATE_270:
+		return "SURFACE_TRANSFORM_ROTATE_270"
+	case SURFACE_TRANSFORM_FLIP_HORIZONTAL:
+		return "SURFACE_TRANSFORM_FLIP_HORIZONTAL"
+	case SURFACE_TRANSFORM_FLIP_VERTICAL:
+		return "SURFACE_TRANSFORM_FLIP_VERTICAL"
+	default:
+		return "[?? Invalid SurfaceTransformFlags value]"
+	}
+}
+
 func (f SurfaceFormat) String() string {
 	switch f {
 	case SURFACE_FMT_NONE:
@@ -242,3 +507,44 @@ func (f SurfaceFormat) String() string {
 		return "[?? Invalid SurfaceFormat value]"
 	}
 }
+
+func (l QRLevel) String() string {
+	switch l {
+	case QR_LEVEL_L:
+		return "QR_LEVEL_L"
+	case QR_LEVEL_M:
+		return "QR_LEVEL_M"
+	case QR_LEVEL_Q:
+		return "QR_LEVEL_Q"
+	case QR_LEVEL_H:
+		return "QR_LEVEL_H"
+	default:
+		return "[?? Invalid QRLevel value]"
+	}
+}
+
+func (t BarcodeType) String() string {
+	switch t {
+	case BARCODE_CODE128:
+		return "BARCODE_CODE128"
+	case BARCODE_EAN13:
+		return "BARCODE_EAN13"
+	case BARCODE_EAN8:
+		return "BARCODE_EAN8"
+	default:
+		return "[?? Invalid BarcodeType value]"
+	}
+}
+
+func (m ThemeMode) String() string {
+	switch m {
+	case THEME_MODE_AUTO:
+		return "THEME_MODE_AUTO"
+	case THEME_MODE_LIGHT:
+		return "THEME_MODE_LIGHT"
+	case THEME_MODE_DARK:
+		return "THEME_MODE_DARK"
+	default:
+		return "[?? Invalid ThemeMode value]"
+	}
+}
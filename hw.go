@@ -1,7 +1,9 @@
 package gopi
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 )
@@ -12,7 +14,15 @@ import (
 	* Information about the underlying hardware platform
 	* Pixel-based displays
 	* SPI, I2C and GPIO
+	* DMA-paced GPIO waveforms
+	* Sampling GPIO pins as a logic analyzer
 	* Infrared sending and receiving
+	* LED class devices
+	* Display backlights
+	* Console virtual terminals for kiosk mode
+	* HDMI-CEC control of TVs
+	* Relay boards and contactors with interlocks
+	* Analog-to-digital converters (ADS1015 and ADS1115)
 */
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -29,6 +39,7 @@ type (
 	GPIOEdge     uint8  // GPIOEdge is a rising or falling edge
 	LIRCMode     uint32 // LIRCMode is the LIRC Mode
 	LIRCType     uint32 // LIRCType is the LIRC Type
+	CECPower     uint8  // CECPower is the power status of a TV
 )
 
 type SPIBus struct {
@@ -37,6 +48,13 @@ type SPIBus struct {
 
 type I2CBus uint
 
+// GPIOPulse sets pins high and low, and then waits before the next pulse
+type GPIOPulse struct {
+	High  []GPIOPin     // Pins to set high
+	Low   []GPIOPin     // Pins to set low
+	Delay time.Duration // Delay after setting pins, with microsecond resolution
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // INTERFACES
 
@@ -47,6 +65,17 @@ type Platform interface {
 	Uptime() time.Duration                     // Uptime returns uptime for host
 	LoadAverages() (float64, float64, float64) // LoadAverages returns 1, 5 and 15 minute load averages
 	TemperatureZones() map[string]float32      // Return celcius values for zones
+
+	// GPUMemory returns GPU memory values in bytes, keyed by "arm", "gpu",
+	// "reloc", "reloc_total", "malloc" and "malloc_total" or nil if not supported
+	GPUMemory() map[string]uint64
+
+	// Codecs returns hardware codecs and whether they are enabled,
+	// or nil if not supported
+	Codecs() map[string]bool
+
+	// Camera returns whether a camera is supported and detected
+	Camera() (bool, bool)
 }
 
 // DisplayManager manages the connected displays and emits Display objects
@@ -74,6 +103,36 @@ type Display interface {
 	Name() string           // Return name of the display
 	Size() (uint32, uint32) // Return display size for nominated display number, or (0,0) if display does not exist
 	PixelsPerInch() uint32  // Return the PPI (pixels-per-inch) for the display, or return zero if unknown
+
+	// EDID returns the parsed identification data for the display, or
+	// ErrNotFound if the display does not provide it
+	EDID() (DisplayEDID, error)
+}
+
+// DisplayEDID is the parsed Extended Display Identification Data
+// which is read from a connected display
+type DisplayEDID interface {
+	Vendor() string                 // Return three-letter manufacturer code
+	Product() uint16                // Return manufacturer product code
+	Name() string                   // Return monitor name, or empty string
+	Serial() string                 // Return serial number
+	PhysicalSize() (uint32, uint32) // Return physical size in millimetres, or (0,0) if unknown
+	Modes() []DisplayMode           // Return detailed modes, with the preferred mode first
+}
+
+// DisplayMode is a resolution and refresh rate supported by a display
+type DisplayMode struct {
+	W, H       uint32
+	Hz         float32
+	Interlaced bool
+}
+
+// DisplayEvent is emitted when a display is attached or unplugged
+type DisplayEvent interface {
+	Event
+
+	Display() Display   // Return the display, which may be nil if unknown
+	Flags() DisplayFlag // Return DISPLAY_FLAG_ATTACHED or DISPLAY_FLAG_UNPLUGGED
 }
 
 // SPI implements the SPI interface for sensors, etc.
@@ -102,6 +161,10 @@ type SPI interface {
 	// Transfer reads and writes on the SPI bus
 	Transfer(SPIBus, []byte) ([]byte, error)
 
+	// TransferContext reads and writes on the SPI bus, and returns
+	// the context error if the context is done before the transfer
+	TransferContext(context.Context, SPIBus, []byte) ([]byte, error)
+
 	// Read bytes into a buffer
 	Read(SPIBus, []byte) error
 
@@ -139,6 +202,39 @@ type I2C interface {
 	WriteInt8(bus I2CBus, reg uint8, value int8) error
 	WriteUint16(bus I2CBus, reg uint8, value uint16) error
 	WriteInt16(bus I2CBus, reg uint8, value int16) error
+
+	// TransferContext writes data to the current slave and then reads
+	// a number of bytes, and returns the context error if the context
+	// is done before the transfer
+	TransferContext(ctx context.Context, bus I2CBus, data []byte, n int) ([]byte, error)
+
+	// Batch calls a function with the bus locked, so that a sequence
+	// of operations on a slave is not interleaved with other callers
+	Batch(bus I2CBus, fn func(I2CTx) error) error
+}
+
+// I2CTx reads and writes on a bus within a batch
+type I2CTx interface {
+	// Set and get current slave address
+	SetSlave(uint8) error
+	GetSlave() uint8
+
+	// Read and Write data directly
+	Read() ([]byte, error)
+	Write([]byte) (int, error)
+
+	// Read Byte (8-bits), Word (16-bits) & Block ([]byte) from registers
+	ReadUint8(reg uint8) (uint8, error)
+	ReadInt8(reg uint8) (int8, error)
+	ReadUint16(reg uint8) (uint16, error)
+	ReadInt16(reg uint8) (int16, error)
+	ReadBlock(reg, length uint8) ([]byte, error)
+
+	// Write Byte (8-bits) and Word (16-bits)
+	WriteUint8(reg, value uint8) error
+	WriteInt8(reg uint8, value int8) error
+	WriteUint16(reg uint8, value uint16) error
+	WriteInt16(reg uint8, value int16) error
 }
 
 // GPIO implements the GPIO interface for simple input and output
@@ -181,6 +277,20 @@ type GPIO interface {
 	// or stop watching when GPIO_EDGE_NONE is passed.
 	// Will return ErrNotImplemented if not supported
 	Watch(GPIOPin, GPIOEdge) error
+
+	// Batch calls a function with the GPIO locked, so that pins are
+	// read and written together. When the function returns an error,
+	// the modes and states of pins changed in the batch are restored
+	Batch(func(GPIOTx) error) error
+}
+
+// GPIOTx reads and writes pins within a batch
+type GPIOTx interface {
+	ReadPin(GPIOPin) GPIOState
+	WritePin(GPIOPin, GPIOState)
+	GetPinMode(GPIOPin) GPIOMode
+	SetPinMode(GPIOPin, GPIOMode)
+	SetPullMode(GPIOPin, GPIOPull) error
 }
 
 // GPIOEvent happens when a pin is watched and edge is
@@ -191,6 +301,64 @@ type GPIOEvent interface {
 	Edge() GPIOEdge
 }
 
+// GPIOWaveform plays back pre-computed pulse sequences on several pins
+// with microsecond accuracy, paced by hardware rather than the scheduler
+type GPIOWaveform interface {
+	// NewWave returns a wave from a sequence of pulses
+	NewWave([]GPIOPulse) (GPIOWave, error)
+
+	// DeleteWave stops the wave if it is playing and releases it
+	DeleteWave(GPIOWave) error
+
+	// Play starts playing a wave once, or repeatedly until stopped
+	// when the second argument is true. Any playing wave is stopped
+	Play(GPIOWave, bool) error
+
+	// Stop stops playing the current wave
+	Stop() error
+
+	// Busy returns true while a wave is playing
+	Busy() bool
+}
+
+// GPIOWave is a sequence of pulses which can be played
+type GPIOWave interface {
+	Pins() []GPIOPin         // Pins which are changed by the wave
+	Duration() time.Duration // Duration when played once
+}
+
+// GPIOSampler samples pins at a high rate into a ring buffer, for debugging
+// bit-banged protocols
+type GPIOSampler interface {
+	// Start sampling pins at a rate in Hz, or at the default rate when zero
+	Start(uint, ...GPIOPin) error
+
+	// Stop sampling and return a trace of the most recent samples
+	Stop() (GPIOTrace, error)
+
+	// Sampling returns true between Start and Stop
+	Sampling() bool
+}
+
+// GPIOTrace is a capture of pin states from a GPIOSampler
+type GPIOTrace interface {
+	Pins() []GPIOPin         // Sampled pins
+	Rate() uint              // Sample rate in Hz
+	Duration() time.Duration // Duration of the trace
+	Transitions() int        // Number of times pins changed state
+	Overruns() uint          // Number of samples which were late
+
+	// State returns the state of a pin at a time from the start
+	// of the trace
+	State(GPIOPin, time.Duration) GPIOState
+
+	// WriteVCD writes the trace as a Value Change Dump file
+	WriteVCD(io.Writer) error
+
+	// WriteSigrok writes the trace as a sigrok session file
+	WriteSigrok(io.Writer) error
+}
+
 // LIRC implements the IR send & receive interface
 type LIRC interface {
 	// Get receive and send modes
@@ -216,6 +384,10 @@ type LIRC interface {
 
 	// Send Pulse Mode, values are in milliseconds
 	PulseSend([]uint32) error
+
+	// PulseSendContext sends pulses, and returns the context error if
+	// the context is done before the pulses are sent
+	PulseSendContext(context.Context, []uint32) error
 }
 
 // LIRCEvent is a pulse, space or timeout from an IR sensor
@@ -238,6 +410,122 @@ type LIRCKeycodeManager interface {
 	Set(InputDevice, uint32, KeyCode, string) error
 }
 
+// LED controls LED class devices, which include the onboard ACT and
+// PWR LEDs on the Raspberry Pi
+type LED interface {
+	// Return names of all LED devices
+	Devices() []string
+
+	// Return current and maximum brightness for a device
+	Brightness(string) (uint, uint, error)
+
+	// Set brightness for a device, where zero is off
+	SetBrightness(string, uint) error
+
+	// Return available kernel triggers and the currently selected trigger
+	Triggers(string) ([]string, string, error)
+
+	// Set kernel trigger for a device, for example LED_TRIGGER_HEARTBEAT
+	SetTrigger(string, string) error
+
+	// Pattern repeats a sequence of on and off durations on a device
+	// until the pattern is changed. An empty sequence stops the pattern
+	// and switches the device off
+	Pattern(string, ...time.Duration) error
+
+	// BlinkCode repeatedly blinks a device a number of times followed
+	// by a pause, which can be used to signal error states. A code of
+	// zero stops blinking
+	BlinkCode(string, uint) error
+}
+
+// Backlight controls backlight class devices, which include the
+// backlight of the official Raspberry Pi touchscreen
+type Backlight interface {
+	// Return names of all backlight devices
+	Devices() []string
+
+	// Return current and maximum brightness for a device
+	Brightness(string) (uint, uint, error)
+
+	// Set brightness for a device
+	SetBrightness(string, uint) error
+
+	// Switch the backlight for a device on or off
+	SetPower(string, bool) error
+}
+
+// Console prepares a virtual terminal for full-screen graphics, by
+// switching to it, disabling blanking and hiding the cursor, and
+// restores the console when disposed
+type Console interface {
+	// VT returns the active virtual terminal
+	VT() (uint, error)
+
+	// Switch activates a virtual terminal and waits until it is active
+	Switch(uint) error
+
+	// Restore the console as it was before it was prepared. Restoring
+	// more than once has no effect
+	Restore() error
+}
+
+// CEC controls TVs over HDMI-CEC. Keys pressed on the TV remote
+// control are emitted as InputEvent
+type CEC interface {
+	// PowerOn wakes the TV and switches it to this device
+	PowerOn() error
+
+	// Standby puts the TV into standby
+	Standby() error
+
+	// SetInput switches the TV to an HDMI input, or to this device
+	// when the input is zero
+	SetInput(uint) error
+
+	// PowerStatus returns the power status reported by the TV
+	PowerStatus() (CECPower, error)
+
+	// PhysicalAddress returns the HDMI address of this device, such as
+	// 0x1000 for the first input
+	PhysicalAddress() uint16
+}
+
+// Relay switches named channels on relay boards and contactors, which
+// are driven by GPIO pins or I2C port expanders
+type Relay interface {
+	// Return names of all channels
+	Channels() []string
+
+	// Return true if a channel is switched on
+	State(string) (bool, error)
+
+	// Set switches a channel on or off, waiting for the minimum on or
+	// off time of the channel to elapse. Returns an error if switching
+	// on a channel is prevented by an interlock
+	Set(context.Context, string, bool) error
+
+	// Safe switches every channel to its safe state, ignoring the
+	// minimum on and off times
+	Safe() error
+}
+
+// RelayEvent is emitted when a channel is switched on or off
+type RelayEvent interface {
+	Event
+	Channel() string
+	State() bool
+}
+
+// ADC reads voltages from the channels of an analog-to-digital converter
+type ADC interface {
+	// Channels returns the number of single-ended channels
+	Channels() uint
+
+	// Read returns the voltage on a channel
+	Read(context.Context, uint) (float32, error)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // CONSTANTS
 
@@ -336,6 +624,26 @@ const (
 	LIRC_TYPE_TIMEOUT   LIRCType = 0x03000000
 )
 
+const (
+	LED_ACT = "led0" // Raspberry Pi activity LED
+	LED_PWR = "led1" // Raspberry Pi power LED
+)
+
+const (
+	LED_TRIGGER_NONE      = "none"
+	LED_TRIGGER_HEARTBEAT = "heartbeat"
+	LED_TRIGGER_MMC0      = "mmc0"
+	LED_TRIGGER_TIMER     = "timer"
+	LED_TRIGGER_DEFAULTON = "default-on"
+)
+
+const (
+	CEC_POWER_ON            CECPower = 0x00
+	CEC_POWER_STANDBY       CECPower = 0x01
+	CEC_POWER_STANDBY_TO_ON CECPower = 0x02 // In transition from standby to on
+	CEC_POWER_ON_TO_STANDBY CECPower = 0x03 // In transition from on to standby
+)
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -496,6 +804,19 @@ func (t LIRCType) String() string {
 	}
 }
 
+func (m DisplayMode) String() string {
+	str := fmt.Sprintf("%vx%v", m.W, m.H)
+	if m.Interlaced {
+		str += "i"
+	} else {
+		str += "p"
+	}
+	if m.Hz > 0 {
+		str += fmt.Sprintf("%.2f", m.Hz)
+	}
+	return str
+}
+
 func (f DisplayFlag) String() string {
 	if f == DISPLAY_FLAG_NONE {
 		return f.FlagString()
@@ -533,3 +854,18 @@ func (f DisplayFlag) FlagString() string {
 		return "[?? Invalid DisplayFlag value]"
 	}
 }
+
+func (p CECPower) String() string {
+	switch p {
+	case CEC_POWER_ON:
+		return "CEC_POWER_ON"
+	case CEC_POWER_STANDBY:
+		return "CEC_POWER_STANDBY"
+	case CEC_POWER_STANDBY_TO_ON:
+		return "CEC_POWER_STANDBY_TO_ON"
+	case CEC_POWER_ON_TO_STANDBY:
+		return "CEC_POWER_ON_TO_STANDBY"
+	default:
+		return "[?? Invalid CECPower value]"
+	}
+}
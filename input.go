@@ -1,11 +1,16 @@
 package gopi
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 /*
 	This file contains definitions for input devices:
 
 	* Keyboard, Mouse, Joystick and Touchscreen
+	* Mapping of input events to named actions
+	* Idle management when there is no input activity
 */
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -13,6 +18,8 @@ import "strings"
 
 type InputType uint
 type InputDeviceType uint16
+type IdleState uint
+type IdleEventType uint
 
 ////////////////////////////////////////////////////////////////////////////////
 // INTERFACES
@@ -38,6 +45,54 @@ type InputEvent interface {
 	Device() (InputDeviceType, uint32) // Device information
 }
 
+// InputActions maps key, IR scancode and GPIO events to named actions,
+// within a context such as "playback" or "menu"
+type InputActions interface {
+	// Context returns the current context
+	Context() string
+
+	// SetContext sets the current context
+	SetContext(string) error
+
+	// Contexts returns the contexts defined in the mapping
+	Contexts() []string
+
+	// Reload the mapping
+	Reload() error
+}
+
+// InputActionEvent is emitted when an input event maps to an action,
+// where Name() returns the action
+type InputActionEvent interface {
+	Event
+	Context() string // Context in which the action was mapped
+	Source() Event   // Input event which was mapped
+}
+
+// IdleMonitor tracks the time since the last input activity, dims the
+// display backlight and enters a screensaver state after timeouts, and
+// wakes on any input
+type IdleMonitor interface {
+	// Touch records activity which is not an input event, and wakes
+	// when idle
+	Touch()
+
+	// State returns the current idle state
+	State() IdleState
+
+	// Idle returns the time since the last activity
+	Idle() time.Duration
+}
+
+// IdleEvent is emitted when entering an idle state, and when exiting
+// idle states on activity
+type IdleEvent interface {
+	Event
+	Type() IdleEventType // IDLE_EVENT_ENTER or IDLE_EVENT_EXIT
+	State() IdleState    // State entered or exited
+	Idle() time.Duration // Time since last activity
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // CONSTANTS
 
@@ -77,6 +132,18 @@ const (
 	INPUT_EVENT_TOUCHPOSITION InputType = 0x0008
 )
 
+const (
+	IDLE_STATE_ACTIVE      IdleState = iota // Input activity within timeouts
+	IDLE_STATE_DIM                          // Backlight is dimmed
+	IDLE_STATE_SCREENSAVER                  // Screensaver is shown
+)
+
+const (
+	IDLE_EVENT_NONE IdleEventType = iota
+	IDLE_EVENT_ENTER
+	IDLE_EVENT_EXIT
+)
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -150,3 +217,29 @@ func (d InputDeviceType) String() string {
 	}
 	return strings.TrimSuffix(str, "|")
 }
+
+func (s IdleState) String() string {
+	switch s {
+	case IDLE_STATE_ACTIVE:
+		return "IDLE_STATE_ACTIVE"
+	case IDLE_STATE_DIM:
+		return "IDLE_STATE_DIM"
+	case IDLE_STATE_SCREENSAVER:
+		return "IDLE_STATE_SCREENSAVER"
+	default:
+		return "[?? Invalid IdleState value]"
+	}
+}
+
+func (t IdleEventType) String() string {
+	switch t {
+	case IDLE_EVENT_NONE:
+		return "IDLE_EVENT_NONE"
+	case IDLE_EVENT_ENTER:
+		return "IDLE_EVENT_ENTER"
+	case IDLE_EVENT_EXIT:
+		return "IDLE_EVENT_EXIT"
+	default:
+		return "[?? Invalid IdleEventType value]"
+	}
+}
@@ -0,0 +1,58 @@
+package gopi
+
+import (
+	"context"
+	"time"
+)
+
+/*
+	This file contains interface defininitons for listening to speech:
+
+	* Wake-word detection on captured audio
+	* Recording an utterance until silence
+	* Pluggable speech-to-text recognizers
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// Listener captures audio, waits for a wake word and records an
+// utterance which is passed to a SpeechRecognizer. A TranscriptEvent
+// is emitted with the result
+type Listener interface {
+	// Trigger starts recording an utterance without a wake word
+	Trigger() error
+
+	// Mute stops listening when true, for example while speaking
+	Mute(bool)
+}
+
+// WakeWordDetector detects wake words in frames of signed 16-bit
+// mono samples
+type WakeWordDetector interface {
+	// SampleRate returns the sample rate required
+	SampleRate() uint
+
+	// FrameLength returns the number of samples in each frame
+	FrameLength() uint
+
+	// Detect processes a frame and returns the name of the wake word
+	// detected, or empty string
+	Detect([]int16) (string, error)
+}
+
+// SpeechRecognizer converts signed 16-bit mono samples with a sample
+// rate into text
+type SpeechRecognizer interface {
+	Transcribe(context.Context, []int16, uint) (string, error)
+}
+
+// TranscriptEvent is emitted when an utterance has been recognized
+type TranscriptEvent interface {
+	Event
+
+	Keyword() string         // Wake word, or empty when triggered
+	Text() string            // Recognized text
+	Duration() time.Duration // Duration of the utterance
+	Error() error            // Set when recognition failed
+}
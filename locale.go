@@ -0,0 +1,83 @@
+package gopi
+
+import (
+	"time"
+)
+
+/*
+	This file contains interface defininitons for localisation:
+
+	* Message catalogs for each language, with plural forms
+	* Selection of the language by flag or from the environment
+	* Formatting of numbers, dates and times for a language
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type LocaleLayout uint
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// Locale translates messages into a language and formats values for it.
+// Messages are identified by their text in the source language, which
+// is returned when there is no translation
+type Locale interface {
+	// Language returns the language, such as "en-GB"
+	Language() string
+
+	// SetLanguage changes the language
+	SetLanguage(string) error
+
+	// Languages returns the languages which have message catalogs
+	Languages() []string
+
+	// Translate returns a message in the language, formatted with
+	// arguments when there are any
+	Translate(string, ...interface{}) string
+
+	// Plural returns the message in the language for a count, where the
+	// messages for one and other counts are in the source language,
+	// formatted with arguments when there are any
+	Plural(one, other string, n int, args ...interface{}) string
+
+	// FormatNumber returns a number with decimal places, and with the
+	// separators for the language
+	FormatNumber(float64, uint) string
+
+	// FormatTime formats a time with a layout of the time package, where
+	// the names of days and months are in the language
+	FormatTime(time.Time, string) string
+
+	// Layout returns the layout for dates and times in the language
+	Layout(LocaleLayout) string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	LOCALE_LAYOUT_DATE     LocaleLayout = iota // Short date
+	LOCALE_LAYOUT_TIME                         // Hours and minutes
+	LOCALE_LAYOUT_DATETIME                     // Short date and time
+	LOCALE_LAYOUT_LONGDATE                     // Date with the names of the day and month
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (l LocaleLayout) String() string {
+	switch l {
+	case LOCALE_LAYOUT_DATE:
+		return "LOCALE_LAYOUT_DATE"
+	case LOCALE_LAYOUT_TIME:
+		return "LOCALE_LAYOUT_TIME"
+	case LOCALE_LAYOUT_DATETIME:
+		return "LOCALE_LAYOUT_DATETIME"
+	case LOCALE_LAYOUT_LONGDATE:
+		return "LOCALE_LAYOUT_LONGDATE"
+	default:
+		return "[?? Invalid LocaleLayout value]"
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/url"
 	"strings"
+	"time"
 )
 
 /*
@@ -13,6 +14,11 @@ import (
 
 	* Video and Audio encoding and decoding
 	* Input and output media devices
+	* Media players which play from a URL
+	* Media recorders which write segments from an input
+	* WebRTC publishing of live video to web browsers
+	* Media scanners which decode QR codes in frames
+	* Audio and video synchronization during playback
 	* DVB tuning and decoding (experimental)
 
 	There are aditional interfaces for audio and graphics elsewhere
@@ -26,8 +32,126 @@ type (
 	MediaFlag               uint64
 	DecodeIteratorFunc      func(MediaDecodeContext, MediaPacket) error
 	DecodeFrameIteratorFunc func(MediaFrame) error
+	MediaSyncClock          uint
+	MediaSyncAction         uint
 )
 
+////////////////////////////////////////////////////////////////////////////////
+// MEDIA PLAYER
+
+// MediaPlayer plays media from a URL on the local display and audio
+// output
+type MediaPlayer interface {
+	// Play media from a URL, stopping any current media
+	Play(string) error
+
+	// Stop current media
+	Stop() error
+
+	// URL returns the media currently playing, or empty string
+	URL() string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIA RECORDER
+
+// MediaRecorder writes packets from a media input into rolling segment
+// files while recording is scheduled or triggered, and removes old
+// segments according to a retention policy
+type MediaRecorder interface {
+	// Record reads packets from the input until the context is done
+	// or the input ends, writing segments when recording is active
+	Record(context.Context, MediaInput) error
+
+	// Schedule recording between start and stop times
+	Schedule(start, stop time.Time) error
+
+	// Trigger recording from now for a duration, which is extended
+	// if triggered again, for example by motion detection
+	Trigger(time.Duration)
+
+	// Cancel scheduled and triggered recording
+	Cancel()
+
+	// Recording returns true if segments are currently being written
+	Recording() bool
+
+	// Segments returns paths of retained segments, oldest first
+	Segments() []string
+}
+
+// MediaRecorderEvent is emitted when a segment has been written,
+// where the name of the event is the path of the segment
+type MediaRecorderEvent interface {
+	Event
+
+	Start() time.Time        // Time the first packet was written
+	Duration() time.Duration // Length of the segment
+	Size() int64             // Size of the segment in bytes
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WEBRTC
+
+// WebRTC publishes live video from an input to web browsers, which
+// connect by sending an offer to the server
+type WebRTC interface {
+	// Publish sends H.264 video from an input to connected browsers
+	// until the context is done or the input ends
+	Publish(context.Context, MediaInput) error
+
+	// Peers returns the number of connected browsers
+	Peers() int
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIA SCANNER
+
+// MediaScanner detects and decodes QR codes in frames, such as frames
+// decoded from a camera
+type MediaScanner interface {
+	// Scan a frame and emit a ScanEvent for each code decoded. A code
+	// is not emitted again until it has not been seen for a period
+	Scan(image.Image) error
+}
+
+// ScanEvent is emitted when a code is decoded from a frame, where the
+// name of the event is the payload
+type ScanEvent interface {
+	Event
+
+	Payload() []byte   // Decoded payload
+	Corners() [4]Point // Top left, top right, bottom right and bottom left in frame coordinates
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIA SYNC
+
+// MediaSync synchronizes audio and video during playback to a master
+// clock, which is the audio clock by default. Video frames are shown,
+// dropped or the previous frame repeated, and audio drift is corrected
+// by resampling
+type MediaSync interface {
+	// Reset clocks, for example after seeking
+	Reset()
+
+	// Audio updates the audio clock with the presentation time of samples
+	// being played, and returns the number of samples which should be
+	// output by the resampler to correct drift
+	Audio(pts time.Duration, samples, rate uint) uint
+
+	// Video updates the video clock with the presentation time of a
+	// frame, and returns the action for the frame and the time to wait
+	// before it is shown
+	Video(pts time.Duration) (MediaSyncAction, time.Duration)
+
+	// Master returns the master clock
+	Master() MediaSyncClock
+
+	// Offset returns the difference between the video and audio clocks
+	Offset() time.Duration
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // MEDIA MANAGER
 
@@ -42,6 +166,11 @@ type MediaManager interface {
 	// CreateFile creates a local media file for output
 	CreateFile(path string) (MediaOutput, error)
 
+	// CreateWriter creates media output to a writer, such as a buffer
+	// or HTTP response, in a format with a short name such as "mpegts".
+	// Seeking is supported when the writer implements io.Seeker
+	CreateWriter(io.Writer, string) (MediaOutput, error)
+
 	// Close will release resources and close a media object
 	Close(Media) error
 
@@ -52,6 +181,55 @@ type MediaManager interface {
 
 	// Create an audio profile with format, sample rate, channels and layout
 	AudioProfile(AudioFormat, uint, AudioChannelLayout) MediaProfile
+
+	// Create a video profile for encoding with a codec such as "h264"
+	// at a width and height, preferring hardware encoders
+	VideoProfile(string, uint, uint) (MediaVideoProfile, error)
+
+	// Probe returns a report on the container, chapters and streams
+	// of a local media file, which can be encoded as JSON
+	Probe(path string) (*MediaProbe, error)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIA PROBE
+
+// MediaProbe is a report on a media file. Times are in seconds
+type MediaProbe struct {
+	Path        string              `json:"path"`
+	Format      string              `json:"format"`
+	Description string              `json:"description,omitempty"`
+	Duration    float64             `json:"duration,omitempty"`
+	BitRate     uint                `json:"bit_rate,omitempty"`
+	Chapters    []MediaProbeChapter `json:"chapters,omitempty"`
+	Streams     []MediaProbeStream  `json:"streams"`
+	Tags        map[string]string   `json:"tags,omitempty"`
+}
+
+// MediaProbeChapter is a chapter within a media file
+type MediaProbeChapter struct {
+	Id    int64             `json:"id"`
+	Start float64           `json:"start"`
+	End   float64           `json:"end"`
+	Tags  map[string]string `json:"tags,omitempty"`
+}
+
+// MediaProbeStream is a stream within a media file. Dimensions and
+// frame rate are set for video, and sample rate and channels for audio
+type MediaProbeStream struct {
+	Index         int               `json:"index"`
+	Type          string            `json:"type"`
+	Codec         string            `json:"codec"`
+	Description   string            `json:"description,omitempty"`
+	Duration      float64           `json:"duration,omitempty"`
+	BitRate       uint              `json:"bit_rate,omitempty"`
+	Width         uint              `json:"width,omitempty"`
+	Height        uint              `json:"height,omitempty"`
+	FrameRate     float64           `json:"frame_rate,omitempty"`
+	SampleRate    uint              `json:"sample_rate,omitempty"`
+	Channels      uint              `json:"channels,omitempty"`
+	ChannelLayout string            `json:"channel_layout,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty"`
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -107,6 +285,12 @@ type MediaAudioProfile interface {
 
 type MediaVideoProfile interface {
 	MediaProfile
+
+	Codec() MediaCodec // Return encoder selected for the profile
+	Hardware() bool    // Return true if the encoder is hardware accelerated
+	Width() uint
+	Height() uint
+	BitRate() uint // Return bits per second
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -147,6 +331,7 @@ type MediaPacket interface {
 	Size() int
 	Bytes() []byte
 	Stream() int
+	IsKeyFrame() bool // Packet can be decoded without previous packets
 }
 
 // MediaFrame is a decoded audio or video frame
@@ -179,13 +364,28 @@ type DVBManager interface {
 	// Tuners returns all tuners
 	Tuners() []DVBTuner
 
-	// ParseTunerParams returns a list of tuner parameters
+	// ParseTunerParams returns a list of tuner parameters, which
+	// includes channels written by WriteChannels
 	ParseTunerParams(r io.Reader) ([]DVBTunerParams, error)
 
 	// Tune to parameters with context, which can be used to cancel
 	// or timeout the tuning process
 	Tune(context.Context, DVBTuner, DVBTunerParams, DVBTuneCallack) error
 
+	// Scan tunes to each set of parameters in turn and returns
+	// a channel for each service found
+	Scan(context.Context, DVBTuner, []DVBTunerParams) ([]DVBTunerParams, error)
+
+	// WriteChannels writes channels returned by Scan in dvbv5
+	// channel file format
+	WriteChannels(io.Writer, []DVBTunerParams) error
+
+	// Stream selects a service on a tuned tuner, waiting until
+	// the service information has been received, and returns
+	// the path of a device which provides MPEG-TS packets for the
+	// service. The path can be opened with MediaManager
+	Stream(context.Context, DVBTuner, uint16) (string, error)
+
 	// Close tuner
 	Close(DVBTuner) error
 }
@@ -197,13 +397,18 @@ type DVBTuneCallack func(DVBContext)
 // DVBTuneContext represents information obtained during the tuning
 // process
 type DVBContext interface {
-	//	Services() []DVBService
+	// Services returns services on the multiplex
+	Services() []DVBService
 }
 
 // DVBTunerParams represents tune parameters
 type DVBTunerParams interface {
 	// Name returns name for tune parameters
 	Name() string
+
+	// ServiceId returns the service for a channel, or zero if
+	// the parameters tune a multiplex
+	ServiceId() uint16
 }
 
 // DVBTuner represents a tuning device, some hardware
@@ -220,6 +425,21 @@ type DVBTuner interface {
 // DVBService represents a service that can be received
 type DVBService interface {
 	Id() uint16
+	Name() string     // Service name, or empty if not yet received
+	Provider() string // Service provider, or empty if not yet received
+}
+
+// DVBEPGEvent is emitted for each event in the electronic programme
+// guide, where the name of the event is the programme title
+type DVBEPGEvent interface {
+	Event
+
+	Service() uint16         // Service identifier
+	Id() uint16              // Event identifier
+	Start() time.Time        // Start time
+	Duration() time.Duration // Duration
+	Language() string        // ISO 639 language code
+	Description() string     // Programme description
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -246,6 +466,18 @@ const (
 	MEDIA_FLAG_MAX                         = MEDIA_FLAG_DECODER
 )
 
+const (
+	MEDIA_SYNC_CLOCK_AUDIO MediaSyncClock = iota
+	MEDIA_SYNC_CLOCK_VIDEO
+	MEDIA_SYNC_CLOCK_SYSTEM
+)
+
+const (
+	MEDIA_SYNC_SHOW   MediaSyncAction = iota // Show the frame
+	MEDIA_SYNC_DROP                          // Drop the frame, which is late
+	MEDIA_SYNC_REPEAT                        // Repeat the previous frame, as the frame is early
+)
+
 const (
 	MEDIA_KEY_BRAND_MAJOR      MediaKey = "major_brand"       // string
 	MEDIA_KEY_BRAND_COMPATIBLE MediaKey = "compatible_brands" // string
@@ -341,3 +573,29 @@ func (f MediaFlag) FlagString() string {
 		return "[?? Invalid MediaFlag]"
 	}
 }
+
+func (c MediaSyncClock) String() string {
+	switch c {
+	case MEDIA_SYNC_CLOCK_AUDIO:
+		return "MEDIA_SYNC_CLOCK_AUDIO"
+	case MEDIA_SYNC_CLOCK_VIDEO:
+		return "MEDIA_SYNC_CLOCK_VIDEO"
+	case MEDIA_SYNC_CLOCK_SYSTEM:
+		return "MEDIA_SYNC_CLOCK_SYSTEM"
+	default:
+		return "[?? Invalid MediaSyncClock]"
+	}
+}
+
+func (a MediaSyncAction) String() string {
+	switch a {
+	case MEDIA_SYNC_SHOW:
+		return "MEDIA_SYNC_SHOW"
+	case MEDIA_SYNC_DROP:
+		return "MEDIA_SYNC_DROP"
+	case MEDIA_SYNC_REPEAT:
+		return "MEDIA_SYNC_REPEAT"
+	default:
+		return "[?? Invalid MediaSyncAction]"
+	}
+}
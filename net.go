@@ -2,8 +2,10 @@ package gopi
 
 import (
 	"context"
+	"io"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -14,17 +16,33 @@ import (
 
 	* HTTP and gRPC Servers
 	* Services
-	* Service Discovery
+	* Service Discovery and a registry of discovered devices
 	* HTML Templating and content rendering
+	* Web-based administration
+	* Wireless network management
+	* Presence detection of phones and beacons (ARP, ICMP, BLE)
+	* Pairing of controlling clients with per-session permissions
 
-	There are also some example gRPC services (Ping, Input, Metrics)
-	which can be used "out of the box".
+	There are also some example gRPC services (Ping, Input, Metrics,
+	Shell, GPIO Waveform) which can be used "out of the box".
 */
 
 /////////////////////////////////////////////////////////////////////
 // TYPES
 
-type ServiceFlag uint
+type (
+	ServiceFlag uint
+	WiFiState   uint // WiFiState is the connection state of a wireless interface
+
+	PresenceState     uint // PresenceState is whether a person is home or away
+	PresenceMethod    uint // PresenceMethod is a method which detected a person
+	PresenceEventType uint // PresenceEventType is arrival or departure
+
+	SessionPermission uint // SessionPermission is what a paired client can control
+	PairingEventType  uint // PairingEventType is a step in pairing a client
+
+	DeviceEventType uint // DeviceEventType is whether a device was added, changed or removed
+)
 
 /////////////////////////////////////////////////////////////////////
 // INTERFACES
@@ -132,6 +150,48 @@ type ServiceRecord interface {
 	Txt() []string
 }
 
+// ServiceIface is implemented by service records which were discovered on
+// a network interface
+type ServiceIface interface {
+	Iface() string // Iface returns the name of the interface, or empty
+}
+
+// DeviceRegistry collects devices discovered by units, such as cast
+// devices, DIAL receivers and gopi servers, so that they can be queried
+// in one place. Changes are emitted as DeviceEvent
+type DeviceRegistry interface {
+	// Register adds or replaces a device, which is not expired
+	Register(Device) error
+
+	// Unregister removes a device by key
+	Unregister(string) error
+
+	// Devices returns devices of a type, or all devices when the
+	// type is empty, sorted by key
+	Devices(string) []Device
+
+	// Device returns a device by key, or nil
+	Device(string) Device
+}
+
+// Device is a discovered device with the service record which
+// describes how to connect to it
+type Device interface {
+	ServiceRecord
+
+	Key() string     // Key is unique, for example "cast:Chromecast-1234"
+	Type() string    // Type of device, for example "cast" or "rpc"
+	Seen() time.Time // Time last discovered
+}
+
+// DeviceEvent is emitted when a device is added, changed or removed
+type DeviceEvent interface {
+	Event
+
+	Type() DeviceEventType
+	Device() Device
+}
+
 /////////////////////////////////////////////////////////////////////
 // GRPC SERVICES
 
@@ -173,6 +233,81 @@ type MetricsStub interface {
 	Stream(context.Context, string, chan<- Measurement) error
 }
 
+type ShellService interface {
+	Service
+
+	// Serve sets the shell used for remote sessions
+	Serve(Shell) error
+}
+
+type ShellStub interface {
+	ServiceStub
+
+	// Run a remote session which sends lines read from the reader
+	// and writes output to the writer, until the session is quit or
+	// the context is cancelled
+	Run(context.Context, io.Reader, io.Writer) error
+
+	// Complete returns completion candidates for a partial line
+	Complete(context.Context, string) ([]string, error)
+}
+
+type GPIOWaveformService interface {
+	Service
+}
+
+type GPIOWaveformStub interface {
+	ServiceStub
+
+	// Session returns a session which controls a remote waveform until
+	// the session is closed or the context is cancelled, when waves
+	// uploaded in the session are deleted
+	Session(context.Context) (GPIOWaveformSession, error)
+}
+
+// GPIOWaveformSession sends commands to a remote waveform, and each
+// method returns when the command has been acknowledged
+type GPIOWaveformSession interface {
+	// SetPWM repeats a pulse on a pin with a frequency in Hz and a
+	// duty cycle between zero and one, replacing any playing wave
+	SetPWM(GPIOPin, float64, float64) error
+
+	// NewWave uploads a wave from a sequence of pulses
+	NewWave([]GPIOPulse) (GPIOWave, error)
+
+	// DeleteWave stops the wave if it is playing and releases it
+	DeleteWave(GPIOWave) error
+
+	// Play starts playing a wave once, or repeatedly until stopped
+	Play(GPIOWave, bool) error
+
+	// Stop stops playing the current wave
+	Stop() error
+
+	// Busy returns true while a wave is playing
+	Busy() (bool, error)
+
+	// Close ends the session
+	Close() error
+}
+
+type DeviceRegistryService interface {
+	Service
+}
+
+type DeviceRegistryStub interface {
+	ServiceStub
+
+	// Devices returns devices of a type from a remote registry, or all
+	// devices when the type is empty
+	Devices(context.Context, string) ([]Device, error)
+
+	// Watch emits device events of a type, or all device events when
+	// the type is empty, on the provided channel until the context is
+	// cancelled
+	Watch(context.Context, string, chan<- DeviceEvent) error
+}
+
 /////////////////////////////////////////////////////////////////////
 // HTTP SERVICES
 
@@ -237,6 +372,26 @@ type HttpRenderContext struct {
 	Modified time.Time
 }
 
+// HttpAdmin serves a single-page administration interface with the
+// JSON and event stream endpoints it uses, which shows the unit graph,
+// health, metrics, logs, GPIO state and cast devices
+type HttpAdmin interface {
+	// Path returns the root URL for the interface
+	Path() string
+}
+
+// HttpProfiler serves runtime profiles and traces when the debug flag is
+// set, and writes memory and goroutine snapshots when memory grows
+type HttpProfiler interface {
+	// Path returns the root URL for profiles, or empty if profiles are
+	// not served by the HTTP server
+	Path() string
+
+	// Snapshot writes heap and goroutine profiles and returns the paths
+	// of the files written
+	Snapshot() ([]string, error)
+}
+
 // HttpError provides the correct error code to the client which
 // can be returned by the ServeContent method in order to more correctly
 // respond to the client
@@ -251,6 +406,134 @@ type HttpError interface {
 	Path() string
 }
 
+/////////////////////////////////////////////////////////////////////
+// WIRELESS NETWORKING
+
+// WiFi manages wireless network interfaces through wpa_supplicant
+type WiFi interface {
+	// Interfaces returns the names of managed wireless interfaces
+	Interfaces() []string
+
+	// Scan for networks on an interface and return the results
+	Scan(context.Context, string) ([]WiFiNetwork, error)
+
+	// Networks returns the configured networks for an interface
+	Networks(string) ([]WiFiNetwork, error)
+
+	// Status returns the connection state and current network for an interface,
+	// or nil network if not connected
+	Status(string) (WiFiState, WiFiNetwork, error)
+
+	// Connect adds or updates a network with SSID and passphrase and
+	// selects it. An empty passphrase connects to an open network
+	Connect(string, string, string) error
+
+	// Select a configured network by SSID
+	Select(string, string) error
+
+	// Provision enters access point mode on an interface with the
+	// given SSID, in order that a client can configure a network. An
+	// empty SSID leaves access point mode
+	Provision(string, string) error
+}
+
+// WiFiNetwork is a scanned or configured wireless network
+type WiFiNetwork interface {
+	SSID() string    // Network name
+	BSSID() string   // Access point hardware address, or empty
+	Frequency() uint // Frequency in MHz or zero if unknown
+	Signal() int     // Signal strength in dBm or zero if unknown
+	Flags() []string // Security and state flags
+}
+
+// WiFiEvent is emitted when the state of a wireless interface changes
+type WiFiEvent interface {
+	Event
+
+	Interface() string    // Interface name
+	State() WiFiState     // Connection state
+	Network() WiFiNetwork // Current network, or nil
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRESENCE DETECTION
+
+// Presence tracks whether people are home, by detecting their phones
+// and beacons on the local network and over Bluetooth LE
+type Presence interface {
+	// People returns all tracked people
+	People() []PresencePerson
+
+	// Person returns a person by name, or nil
+	Person(string) PresencePerson
+}
+
+// PresencePerson is a tracked person
+type PresencePerson interface {
+	Name() string           // Name of person
+	State() PresenceState   // Home, away or unknown
+	Since() time.Time       // Time of last change of state
+	Seen() time.Time        // Time last detected, or zero
+	Method() PresenceMethod // Methods which detected the person in the last scan
+	RSSI() int              // Last Bluetooth signal strength in dBm, or zero
+}
+
+// PresenceEvent is emitted when a person arrives or leaves
+type PresenceEvent interface {
+	Event
+
+	Type() PresenceEventType
+	Person() PresencePerson
+}
+
+/////////////////////////////////////////////////////////////////////
+// PAIRING
+
+// PairingManager pairs controlling clients, such as a phone app or web
+// UI, with the device. A client requests pairing and a short code is
+// displayed or logged, which the client returns to receive a token
+type PairingManager interface {
+	// Pair starts pairing a client with a name and requested permissions,
+	// and returns an identifier for the request
+	Pair(string, SessionPermission) (string, error)
+
+	// Confirm completes pairing with the request identifier and code,
+	// and returns the session and a token which identifies it
+	Confirm(string, string) (Session, string, error)
+
+	// Authorize returns the session for a token when it has the
+	// permissions, or ErrPermissionDenied
+	Authorize(string, SessionPermission) (Session, error)
+
+	// Sessions returns all paired sessions
+	Sessions() []Session
+
+	// SetPermissions changes the permissions for a session
+	SetPermissions(Session, SessionPermission) error
+
+	// Revoke a session, so that its token is no longer accepted
+	Revoke(Session) error
+}
+
+// Session is a paired client
+type Session interface {
+	Id() string                     // Session identifier
+	Name() string                   // Name of the client
+	Permissions() SessionPermission // What the client can control
+	Created() time.Time             // Time the client was paired
+	Seen() time.Time                // Time the token was last used
+}
+
+// PairingEvent is emitted with a code to display when a client requests
+// pairing, and when a client is paired, fails to pair or is revoked
+type PairingEvent interface {
+	Event
+
+	Type() PairingEventType
+	Session() Session // Session, or nil when requesting pairing
+	Code() string     // Code to display for PAIRING_EVENT_CODE
+}
+
 /////////////////////////////////////////////////////////////////////
 // GLOBALS
 
@@ -267,6 +550,79 @@ const (
 	SERVICE_FLAG_MAX    = SERVICE_FLAG_GRPC
 )
 
+const (
+	WIFI_STATE_NONE WiFiState = iota
+	WIFI_STATE_DISCONNECTED
+	WIFI_STATE_SCANNING
+	WIFI_STATE_ASSOCIATING
+	WIFI_STATE_CONNECTED
+	WIFI_STATE_PROVISIONING
+)
+
+const (
+	PRESENCE_STATE_UNKNOWN PresenceState = iota
+	PRESENCE_STATE_HOME
+	PRESENCE_STATE_AWAY
+)
+
+const (
+	PRESENCE_METHOD_NONE PresenceMethod = 0
+	PRESENCE_METHOD_ARP  PresenceMethod = (1 << iota) // Hardware address in ARP table
+	PRESENCE_METHOD_ICMP                              // Response to ping
+	PRESENCE_METHOD_BLE                               // Bluetooth LE advertisement
+	PRESENCE_METHOD_MIN  = PRESENCE_METHOD_ARP
+	PRESENCE_METHOD_MAX  = PRESENCE_METHOD_BLE
+)
+
+const (
+	PRESENCE_EVENT_NONE PresenceEventType = iota
+	PRESENCE_EVENT_ARRIVE
+	PRESENCE_EVENT_LEAVE
+)
+
+const (
+	DEVICE_EVENT_NONE DeviceEventType = iota
+	DEVICE_EVENT_ADDED
+	DEVICE_EVENT_CHANGED
+	DEVICE_EVENT_REMOVED
+)
+
+const (
+	SESSION_PERM_NONE  SessionPermission = 0
+	SESSION_PERM_VIEW  SessionPermission = (1 << iota) // Read state
+	SESSION_PERM_GPIO                                  // Control GPIO and relays
+	SESSION_PERM_CAST                                  // Control cast devices
+	SESSION_PERM_MEDIA                                 // Control media playback
+	SESSION_PERM_ADMIN                                 // Manage sessions
+	SESSION_PERM_MIN   = SESSION_PERM_VIEW
+	SESSION_PERM_MAX   = SESSION_PERM_ADMIN
+	SESSION_PERM_ALL   = SESSION_PERM_VIEW | SESSION_PERM_GPIO | SESSION_PERM_CAST | SESSION_PERM_MEDIA | SESSION_PERM_ADMIN
+)
+
+const (
+	PAIRING_EVENT_NONE    PairingEventType = iota
+	PAIRING_EVENT_CODE                     // Client has requested pairing
+	PAIRING_EVENT_PAIRED                   // Client has been paired
+	PAIRING_EVENT_FAILED                   // Code was wrong or has expired
+	PAIRING_EVENT_REVOKED                  // Session has been revoked
+)
+
+/////////////////////////////////////////////////////////////////////
+// METHODS
+
+// ServiceAddr returns an address for dialing a service record at an IP,
+// where link-local IPv6 addresses have the zone of the interface on which
+// the record was discovered
+func ServiceAddr(r ServiceRecord, ip net.IP) string {
+	host := ip.String()
+	if ip.To4() == nil && ip.IsLinkLocalUnicast() {
+		if iface, ok := r.(ServiceIface); ok && iface.Iface() != "" {
+			host += "%" + iface.Iface()
+		}
+	}
+	return net.JoinHostPort(host, strconv.FormatUint(uint64(r.Port()), 10))
+}
+
 /////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -305,3 +661,140 @@ func (f ServiceFlag) FlagString() string {
 		return "[?? Invalid ServiceFlag value]"
 	}
 }
+
+func (s WiFiState) String() string {
+	switch s {
+	case WIFI_STATE_NONE:
+		return "WIFI_STATE_NONE"
+	case WIFI_STATE_DISCONNECTED:
+		return "WIFI_STATE_DISCONNECTED"
+	case WIFI_STATE_SCANNING:
+		return "WIFI_STATE_SCANNING"
+	case WIFI_STATE_ASSOCIATING:
+		return "WIFI_STATE_ASSOCIATING"
+	case WIFI_STATE_CONNECTED:
+		return "WIFI_STATE_CONNECTED"
+	case WIFI_STATE_PROVISIONING:
+		return "WIFI_STATE_PROVISIONING"
+	default:
+		return "[?? Invalid WiFiState value]"
+	}
+}
+
+func (s PresenceState) String() string {
+	switch s {
+	case PRESENCE_STATE_UNKNOWN:
+		return "PRESENCE_STATE_UNKNOWN"
+	case PRESENCE_STATE_HOME:
+		return "PRESENCE_STATE_HOME"
+	case PRESENCE_STATE_AWAY:
+		return "PRESENCE_STATE_AWAY"
+	default:
+		return "[?? Invalid PresenceState value]"
+	}
+}
+
+func (f PresenceMethod) String() string {
+	if f == PRESENCE_METHOD_NONE {
+		return f.FlagString()
+	}
+	str := ""
+	for v := PRESENCE_METHOD_MIN; v <= PRESENCE_METHOD_MAX; v <<= 1 {
+		if f&v == v {
+			str += v.FlagString() + "|"
+		}
+	}
+	return strings.Trim(str, "|")
+}
+
+func (f PresenceMethod) FlagString() string {
+	switch f {
+	case PRESENCE_METHOD_NONE:
+		return "PRESENCE_METHOD_NONE"
+	case PRESENCE_METHOD_ARP:
+		return "PRESENCE_METHOD_ARP"
+	case PRESENCE_METHOD_ICMP:
+		return "PRESENCE_METHOD_ICMP"
+	case PRESENCE_METHOD_BLE:
+		return "PRESENCE_METHOD_BLE"
+	default:
+		return "[?? Invalid PresenceMethod value]"
+	}
+}
+
+func (t PresenceEventType) String() string {
+	switch t {
+	case PRESENCE_EVENT_NONE:
+		return "PRESENCE_EVENT_NONE"
+	case PRESENCE_EVENT_ARRIVE:
+		return "PRESENCE_EVENT_ARRIVE"
+	case PRESENCE_EVENT_LEAVE:
+		return "PRESENCE_EVENT_LEAVE"
+	default:
+		return "[?? Invalid PresenceEventType value]"
+	}
+}
+
+func (t DeviceEventType) String() string {
+	switch t {
+	case DEVICE_EVENT_NONE:
+		return "DEVICE_EVENT_NONE"
+	case DEVICE_EVENT_ADDED:
+		return "DEVICE_EVENT_ADDED"
+	case DEVICE_EVENT_CHANGED:
+		return "DEVICE_EVENT_CHANGED"
+	case DEVICE_EVENT_REMOVED:
+		return "DEVICE_EVENT_REMOVED"
+	default:
+		return "[?? Invalid DeviceEventType value]"
+	}
+}
+
+func (f SessionPermission) String() string {
+	if f == SESSION_PERM_NONE {
+		return f.FlagString()
+	}
+	str := ""
+	for v := SESSION_PERM_MIN; v <= SESSION_PERM_MAX; v <<= 1 {
+		if f&v == v {
+			str += v.FlagString() + "|"
+		}
+	}
+	return strings.Trim(str, "|")
+}
+
+func (f SessionPermission) FlagString() string {
+	switch f {
+	case SESSION_PERM_NONE:
+		return "SESSION_PERM_NONE"
+	case SESSION_PERM_VIEW:
+		return "SESSION_PERM_VIEW"
+	case SESSION_PERM_GPIO:
+		return "SESSION_PERM_GPIO"
+	case SESSION_PERM_CAST:
+		return "SESSION_PERM_CAST"
+	case SESSION_PERM_MEDIA:
+		return "SESSION_PERM_MEDIA"
+	case SESSION_PERM_ADMIN:
+		return "SESSION_PERM_ADMIN"
+	default:
+		return "[?? Invalid SessionPermission value]"
+	}
+}
+
+func (t PairingEventType) String() string {
+	switch t {
+	case PAIRING_EVENT_NONE:
+		return "PAIRING_EVENT_NONE"
+	case PAIRING_EVENT_CODE:
+		return "PAIRING_EVENT_CODE"
+	case PAIRING_EVENT_PAIRED:
+		return "PAIRING_EVENT_PAIRED"
+	case PAIRING_EVENT_FAILED:
+		return "PAIRING_EVENT_FAILED"
+	case PAIRING_EVENT_REVOKED:
+		return "PAIRING_EVENT_REVOKED"
+	default:
+		return "[?? Invalid PairingEventType value]"
+	}
+}
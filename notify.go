@@ -0,0 +1,90 @@
+package gopi
+
+import (
+	"context"
+	"fmt"
+)
+
+/*
+	This file contains definitions for notifications, which are sent
+	to people rather than to other devices:
+
+	* Notifications with a message, severity and attachments
+	* Sinks which deliver notifications by email, webhook, Telegram
+	  or Pushover
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	NotifySeverity uint // NotifySeverity is the importance of a notification
+)
+
+// Notification is a message with a severity and optional attachments,
+// such as the attachments of an event
+type Notification struct {
+	Message     string
+	Severity    NotifySeverity
+	Attachments []EventAttachment
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// Notifier sends notifications to sinks
+type Notifier interface {
+	// Notify sends a message with attachments to sinks which accept
+	// the severity, returning any errors from the sinks
+	Notify(context.Context, string, NotifySeverity, ...EventAttachment) error
+
+	// RegisterSink adds a sink for notifications
+	RegisterSink(NotifySink) error
+
+	// Sinks returns the names of sinks
+	Sinks() []string
+}
+
+// NotifySink delivers notifications
+type NotifySink interface {
+	// Name returns the name of the sink, such as "email"
+	Name() string
+
+	// Notify delivers a notification
+	Notify(context.Context, Notification) error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	NOTIFY_INFO NotifySeverity = iota
+	NOTIFY_WARNING
+	NOTIFY_CRITICAL
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s NotifySeverity) String() string {
+	switch s {
+	case NOTIFY_INFO:
+		return "NOTIFY_INFO"
+	case NOTIFY_WARNING:
+		return "NOTIFY_WARNING"
+	case NOTIFY_CRITICAL:
+		return "NOTIFY_CRITICAL"
+	default:
+		return "[?? Invalid NotifySeverity value]"
+	}
+}
+
+func (n Notification) String() string {
+	str := "<notification"
+	str += fmt.Sprintf(" message=%q", n.Message)
+	str += fmt.Sprint(" severity=", n.Severity)
+	for _, a := range n.Attachments {
+		str += fmt.Sprintf(" attachment=%q", a.Name())
+	}
+	return str + ">"
+}
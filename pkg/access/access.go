@@ -0,0 +1,144 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type access struct {
+	gopi.Unit
+	gopi.Logger
+	sync.RWMutex
+
+	// Flags
+	file *string
+
+	policy *policy
+	auth   []gopi.AccessAuthenticator
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *access) Define(cfg gopi.Config) error {
+	this.file = cfg.FlagPath("access.policy", "", "Policy file with roles for identities and resources")
+	return nil
+}
+
+func (this *access) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Without a policy, every identity is an admin
+	if *this.file == "" {
+		this.policy = newPolicy()
+		return nil
+	}
+
+	// Read policy
+	fh, err := os.Open(*this.file)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	if policy, err := readPolicy(fh); err != nil {
+		return fmt.Errorf("%v: %w", *this.file, err)
+	} else {
+		this.policy = policy
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *access) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.auth = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *access) Role(identity gopi.AccessIdentity) gopi.AccessRole {
+	return this.policy.Role(identity)
+}
+
+func (this *access) Required(resource string) gopi.AccessRole {
+	return this.policy.Required(resource)
+}
+
+func (this *access) Authorize(ctx context.Context, resource string) error {
+	identity, _ := gopi.AccessIdentityFromContext(ctx)
+	if role, required := this.Role(identity), this.Required(resource); role < required {
+		this.Debug("Access: Denied ", resource, " for ", identity, " (", role, " < ", required, ")")
+		return gopi.ErrPermissionDenied.WithPrefix(resource)
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *access) Authenticate(ctx context.Context, token string) (context.Context, error) {
+	this.RWMutex.RLock()
+	auth := this.auth
+	this.RWMutex.RUnlock()
+
+	if token == "" {
+		return ctx, gopi.ErrPermissionDenied.WithPrefix("Authenticate")
+	}
+	for _, fn := range auth {
+		if identity, err := fn(token); errors.Is(err, gopi.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return ctx, err
+		} else {
+			return gopi.WithAccessIdentity(ctx, identity), nil
+		}
+	}
+
+	// No authenticator recognised the token
+	return ctx, gopi.ErrPermissionDenied.WithPrefix("Authenticate")
+}
+
+func (this *access) RegisterAuthenticator(fn gopi.AccessAuthenticator) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if fn == nil {
+		return gopi.ErrBadParameter.WithPrefix("RegisterAuthenticator")
+	}
+	this.auth = append(this.auth, fn)
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *access) String() string {
+	str := "<access"
+	if *this.file != "" {
+		str += fmt.Sprintf(" policy=%q", *this.file)
+	}
+	if this.policy != nil {
+		str += fmt.Sprint(" default=", this.policy.role)
+		for _, rule := range this.policy.rules {
+			str += fmt.Sprintf(" %q=%v", rule.pattern, rule.role)
+		}
+	}
+	return str + ">"
+}
@@ -0,0 +1,145 @@
+package access_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	access "github.com/djthorpe/gopi/v3/pkg/access"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.AccessControl
+}
+
+const (
+	policy = `{
+		"default": "viewer",
+		"identities": { "alice": "admin", "kiosk": "none" },
+		"resources": {
+			"GET /*": "viewer",
+			"GET /admin/*": "operator",
+			"GPIO.*": "operator",
+			"GPIO.SetPinMode": "admin",
+			"/gopi.shell.Shell/*": "admin"
+		}
+	}`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Access_001(t *testing.T) {
+	tests := map[string]gopi.AccessRole{
+		"":         gopi.ACCESS_ROLE_NONE,
+		"none":     gopi.ACCESS_ROLE_NONE,
+		"viewer":   gopi.ACCESS_ROLE_VIEWER,
+		"Operator": gopi.ACCESS_ROLE_OPERATOR,
+		" admin ":  gopi.ACCESS_ROLE_ADMIN,
+	}
+	for name, expected := range tests {
+		if role, err := access.ParseRole(name); err != nil {
+			t.Error(err)
+		} else if role != expected {
+			t.Error("Unexpected role", name, role)
+		}
+	}
+	if _, err := access.ParseRole("root"); errors.Is(err, gopi.ErrBadParameter) == false {
+		t.Error("Expected ErrBadParameter")
+	}
+}
+
+func Test_Access_002(t *testing.T) {
+	// Without a policy, everyone is an admin
+	tool.Test(t, nil, new(App), func(app *App) {
+		if err := app.AccessControl.Authorize(context.Background(), "/gopi.shell.Shell/Execute"); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func Test_Access_003(t *testing.T) {
+	dir, err := ioutil.TempDir("", "access")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "policy.json")
+	if err := ioutil.WriteFile(path, []byte(policy), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tool.Test(t, []string{"-access.policy=" + path}, new(App), func(app *App) {
+		// Roles from the policy
+		roles := map[gopi.AccessIdentity]gopi.AccessRole{
+			{}:              gopi.ACCESS_ROLE_VIEWER,
+			{Name: "alice"}: gopi.ACCESS_ROLE_ADMIN,
+			{Name: "kiosk", Role: gopi.ACCESS_ROLE_ADMIN}:  gopi.ACCESS_ROLE_NONE,
+			{Name: "bob", Role: gopi.ACCESS_ROLE_OPERATOR}: gopi.ACCESS_ROLE_OPERATOR,
+		}
+		for identity, expected := range roles {
+			if role := app.AccessControl.Role(identity); role != expected {
+				t.Error("Unexpected role", identity, role)
+			}
+		}
+
+		// The most specific rule is used, and admin is required when no
+		// rule matches
+		required := map[string]gopi.AccessRole{
+			"GET /":                     gopi.ACCESS_ROLE_VIEWER,
+			"GET /admin/api/units":      gopi.ACCESS_ROLE_OPERATOR,
+			"GPIO.WritePin":             gopi.ACCESS_ROLE_OPERATOR,
+			"GPIO.SetPinMode":           gopi.ACCESS_ROLE_ADMIN,
+			"/gopi.shell.Shell/Execute": gopi.ACCESS_ROLE_ADMIN,
+			"POST /admin/api/gpio":      gopi.ACCESS_ROLE_ADMIN,
+		}
+		for resource, expected := range required {
+			if role := app.AccessControl.Required(resource); role != expected {
+				t.Error("Unexpected required role", resource, role)
+			}
+		}
+
+		// Authorize identities in a context
+		ctx := context.Background()
+		if err := app.AccessControl.Authorize(ctx, "GET /"); err != nil {
+			t.Error(err)
+		} else if err := app.AccessControl.Authorize(ctx, "GPIO.WritePin"); errors.Is(err, gopi.ErrPermissionDenied) == false {
+			t.Error("Expected ErrPermissionDenied", err)
+		} else if err := app.AccessControl.Authorize(gopi.WithAccessIdentity(ctx, gopi.AccessIdentity{Name: "alice"}), "GPIO.SetPinMode"); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func Test_Access_004(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		ctx := context.Background()
+		if _, err := app.AccessControl.Authenticate(ctx, "token"); errors.Is(err, gopi.ErrPermissionDenied) == false {
+			t.Error("Expected ErrPermissionDenied", err)
+		}
+		if err := app.AccessControl.RegisterAuthenticator(func(token string) (gopi.AccessIdentity, error) {
+			if token != "token" {
+				return gopi.AccessIdentity{}, gopi.ErrNotFound
+			}
+			return gopi.AccessIdentity{Name: "session", Role: gopi.ACCESS_ROLE_OPERATOR}, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if ctx, err := app.AccessControl.Authenticate(ctx, "token"); err != nil {
+			t.Error(err)
+		} else if identity, ok := gopi.AccessIdentityFromContext(ctx); ok == false || identity.Name != "session" {
+			t.Error("Unexpected identity", identity)
+		} else if _, err := app.AccessControl.Authenticate(ctx, "other"); errors.Is(err, gopi.ErrPermissionDenied) == false {
+			t.Error("Expected ErrPermissionDenied", err)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+// Access package implements gopi.AccessControl, role-based access control
+// which is shared by the HTTP server, the gRPC server and the remote
+// shell. Identities have one of the roles viewer, operator or admin, and
+// each resource requires a role:
+//
+//	HTTP routes are "GET /path", enforced by the HTTP server
+//	gRPC methods are "/package.Service/Method", enforced by an interceptor
+//	unit methods are "Unit.Method", enforced when called from the remote shell
+//
+// The roles are read from the JSON file set with -access.policy, for example:
+//
+//	{
+//	  "default": "viewer",
+//	  "identities": { "alice": "admin", "kiosk": "viewer" },
+//	  "resources": {
+//	    "GET /pair*": "none",
+//	    "POST /pair*": "none",
+//	    "GET *": "viewer",
+//	    "POST /admin/api/gpio": "operator",
+//	    "GPIO.*": "operator",
+//	    "/gopi.shell.Shell/*": "admin"
+//	  }
+//	}
+//
+// Patterns match with * as any characters, and the most specific pattern
+// is used. Resources which match no pattern require admin. Identities
+// are named by the common name of a verified client certificate, or by
+// a bearer token recognised by a registered authenticator, such as the
+// pairing manager which names identities by session. Identities which
+// are not in the policy have the role from the authenticator, or else
+// the default role. Without a policy file, every identity is an admin.
+package access
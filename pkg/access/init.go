@@ -0,0 +1,13 @@
+package access
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.AccessControl
+	graph.RegisterUnit(reflect.TypeOf(&access{}), reflect.TypeOf((*gopi.AccessControl)(nil)))
+}
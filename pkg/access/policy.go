@@ -0,0 +1,178 @@
+package access
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// policy is the roles for identities and the roles required for
+// resources, read from a file such as:
+//
+//	{
+//	  "default": "viewer",
+//	  "identities": { "alice": "admin", "kiosk": "viewer" },
+//	  "resources": {
+//	    "GET /pair*": "none",
+//	    "POST /admin/api/gpio": "operator",
+//	    "GPIO.*": "operator",
+//	    "/gopi.shell.Shell/*": "admin"
+//	  }
+//	}
+type policy struct {
+	role       gopi.AccessRole
+	identities map[string]gopi.AccessRole
+	rules      []rule
+}
+
+// rule is a pattern for resources, where * matches any characters,
+// and the role required
+type rule struct {
+	pattern string
+	role    gopi.AccessRole
+}
+
+type policyFile struct {
+	Default    string            `json:"default"`
+	Identities map[string]string `json:"identities"`
+	Resources  map[string]string `json:"resources"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	roleNames = map[string]gopi.AccessRole{
+		"none":     gopi.ACCESS_ROLE_NONE,
+		"viewer":   gopi.ACCESS_ROLE_VIEWER,
+		"operator": gopi.ACCESS_ROLE_OPERATOR,
+		"admin":    gopi.ACCESS_ROLE_ADMIN,
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// newPolicy returns a policy where every identity is an admin
+func newPolicy() *policy {
+	return &policy{gopi.ACCESS_ROLE_ADMIN, map[string]gopi.AccessRole{}, nil}
+}
+
+// readPolicy returns a policy from JSON. The default role is none
+// when it is not set
+func readPolicy(r io.Reader) (*policy, error) {
+	var file policyFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, err
+	}
+
+	this := &policy{identities: make(map[string]gopi.AccessRole, len(file.Identities))}
+	if role, err := ParseRole(file.Default); err != nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("default: ", err)
+	} else {
+		this.role = role
+	}
+	for name, value := range file.Identities {
+		if role, err := ParseRole(value); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix("identities: ", strconv.Quote(name), ": ", err)
+		} else {
+			this.identities[name] = role
+		}
+	}
+	for pattern, value := range file.Resources {
+		if role, err := ParseRole(value); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix("resources: ", strconv.Quote(pattern), ": ", err)
+		} else if pattern = strings.TrimSpace(pattern); pattern == "" {
+			return nil, gopi.ErrBadParameter.WithPrefix("resources: Empty pattern")
+		} else {
+			this.rules = append(this.rules, rule{pattern, role})
+		}
+	}
+
+	// The most specific pattern, with the most characters which are
+	// not wildcards, is matched first
+	sort.Slice(this.rules, func(i, j int) bool {
+		a, b := specificity(this.rules[i].pattern), specificity(this.rules[j].pattern)
+		if a == b {
+			return this.rules[i].pattern < this.rules[j].pattern
+		}
+		return a > b
+	})
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ParseRole returns a role from a name, which is none, viewer,
+// operator or admin
+func ParseRole(name string) (gopi.AccessRole, error) {
+	if name = strings.ToLower(strings.TrimSpace(name)); name == "" {
+		return gopi.ACCESS_ROLE_NONE, nil
+	} else if role, exists := roleNames[name]; exists == false {
+		return gopi.ACCESS_ROLE_NONE, gopi.ErrBadParameter.WithPrefix(strconv.Quote(name))
+	} else {
+		return role, nil
+	}
+}
+
+// Role returns the role for an identity from the policy, or the role
+// set by the authenticator, or the default role
+func (this *policy) Role(identity gopi.AccessIdentity) gopi.AccessRole {
+	if identity.Name != "" {
+		if role, exists := this.identities[identity.Name]; exists {
+			return role
+		}
+	}
+	if identity.Role != gopi.ACCESS_ROLE_NONE {
+		return identity.Role
+	}
+	return this.role
+}
+
+// Required returns the role for the first rule which matches a
+// resource, or admin when no rule matches
+func (this *policy) Required(resource string) gopi.AccessRole {
+	for _, rule := range this.rules {
+		if match(rule.pattern, resource) {
+			return rule.role
+		}
+	}
+	return gopi.ACCESS_ROLE_ADMIN
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// match returns true when a pattern matches a resource, where * matches
+// any characters including separators
+func match(pattern, resource string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == resource
+	} else if strings.HasPrefix(resource, parts[0]) == false {
+		return false
+	}
+	resource = resource[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		if i := strings.Index(resource, part); i < 0 {
+			return false
+		} else {
+			resource = resource[i+len(part):]
+		}
+	}
+	return strings.HasSuffix(resource, parts[len(parts)-1])
+}
+
+func specificity(pattern string) int {
+	return len(pattern) - strings.Count(pattern, "*")
+}
@@ -0,0 +1,218 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type audit struct {
+	gopi.Unit
+	gopi.Logger
+	sync.Mutex
+
+	// Flags
+	path *string
+	size *uint
+
+	fh      *os.File
+	entries []gopi.AuditEntry
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *audit) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagPath("audit.path", "", "File for appending actions which change state")
+	this.size = cfg.FlagUint("audit.size", 1000, "Number of actions kept in memory when there is no file")
+	return nil
+}
+
+func (this *audit) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Open the log for appending
+	if *this.path != "" {
+		if fh, err := os.OpenFile(*this.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600); err != nil {
+			return err
+		} else {
+			this.fh = fh
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *audit) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	var result error
+	if this.fh != nil {
+		result = this.fh.Close()
+	}
+
+	// Release resources
+	this.fh = nil
+	this.entries = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *audit) Record(ctx context.Context, entry gopi.AuditEntry) error {
+	if entry.Action == "" {
+		return gopi.ErrBadParameter.WithPrefix("Record")
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if entry.Identity == "" {
+		if identity, ok := gopi.AccessIdentityFromContext(ctx); ok {
+			entry.Identity = identity.Name
+		}
+	}
+	if entry.Old == "" && entry.New == "" {
+		entry.Old, entry.New = gopi.AuditChangeFromContext(ctx)
+	}
+	this.Debug("Audit: ", entry)
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Append to the file, or to entries in memory
+	if this.fh != nil {
+		if data, err := json.Marshal(entry); err != nil {
+			return err
+		} else if _, err := this.fh.Write(append(data, '\n')); err != nil {
+			return err
+		} else {
+			return this.fh.Sync()
+		}
+	}
+	this.entries = append(this.entries, entry)
+	if n := len(this.entries) - int(*this.size); *this.size > 0 && n > 0 {
+		this.entries = append([]gopi.AuditEntry{}, this.entries[n:]...)
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *audit) Query(q gopi.AuditQuery) ([]gopi.AuditEntry, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	result := []gopi.AuditEntry{}
+	add := func(entry gopi.AuditEntry) {
+		if match(q, entry) {
+			result = append(result, entry)
+			if q.Limit > 0 && uint(len(result)) > q.Limit {
+				result = result[1:]
+			}
+		}
+	}
+
+	// Read the file, or entries in memory
+	if this.fh != nil {
+		fh, err := os.Open(this.fh.Name())
+		if err != nil {
+			return nil, err
+		}
+		defer fh.Close()
+		if err := read(fh, add); err != nil {
+			return nil, fmt.Errorf("%v: %w", fh.Name(), err)
+		}
+	} else {
+		for _, entry := range this.entries {
+			add(entry)
+		}
+	}
+
+	// Return success
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *audit) String() string {
+	str := "<audit"
+	if *this.path != "" {
+		str += fmt.Sprintf(" path=%q", *this.path)
+	} else {
+		str += fmt.Sprint(" size=", *this.size)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// read calls a function for each entry in a file with one entry per line
+func read(r io.Reader, fn func(gopi.AuditEntry)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		var entry gopi.AuditEntry
+		if data := scanner.Bytes(); len(data) == 0 {
+			continue
+		} else if err := json.Unmarshal(data, &entry); err != nil {
+			return fmt.Errorf("Line %v: %w", line, err)
+		} else {
+			fn(entry)
+		}
+	}
+	return scanner.Err()
+}
+
+// match returns true when an entry matches a query
+func match(q gopi.AuditQuery, entry gopi.AuditEntry) bool {
+	if q.Since.IsZero() == false && entry.Time.Before(q.Since) {
+		return false
+	} else if q.Until.IsZero() == false && entry.Time.After(q.Until) {
+		return false
+	} else if q.Identity != "" && q.Identity != entry.Identity {
+		return false
+	} else if q.Source != "" && q.Source != entry.Source {
+		return false
+	} else if q.Action != "" && matchAction(q.Action, entry.Action) == false {
+		return false
+	}
+	return true
+}
+
+// matchAction returns true when a pattern, where * matches any
+// characters, matches an action
+func matchAction(pattern, action string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == action
+	} else if strings.HasPrefix(action, parts[0]) == false {
+		return false
+	}
+	action = action[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		if i := strings.Index(action, part); i < 0 {
+			return false
+		} else {
+			action = action[i+len(part):]
+		}
+	}
+	return strings.HasSuffix(action, parts[len(parts)-1])
+}
@@ -0,0 +1,124 @@
+package audit_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/audit"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.AuditLog
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Audit_001(t *testing.T) {
+	tool.Test(t, []string{"-audit.size=3"}, new(App), func(app *App) {
+		ctx := gopi.WithAccessIdentity(context.Background(), gopi.AccessIdentity{Name: "alice"})
+		ctx = gopi.WithAuditChange(ctx)
+		gopi.SetAuditChange(ctx, gopi.GPIO_LOW, gopi.GPIO_HIGH)
+		if err := app.AuditLog.Record(ctx, gopi.AuditEntry{Source: "shell", Action: "GPIO.WritePin"}); err != nil {
+			t.Fatal(err)
+		} else if err := app.AuditLog.Record(ctx, gopi.AuditEntry{}); err == nil {
+			t.Error("Expected error for empty action")
+		}
+		if entries, err := app.AuditLog.Query(gopi.AuditQuery{}); err != nil {
+			t.Error(err)
+		} else if len(entries) != 1 {
+			t.Error("Unexpected entries", entries)
+		} else if entry := entries[0]; entry.Identity != "alice" || entry.Old != "GPIO_LOW" || entry.New != "GPIO_HIGH" || entry.Time.IsZero() {
+			t.Error("Unexpected entry", entry)
+		}
+
+		// Only the most recent entries are kept in memory
+		for _, action := range []string{"GPIO.SetPinMode", "Relay.Set", "POST /admin/api/gpio"} {
+			if err := app.AuditLog.Record(context.Background(), gopi.AuditEntry{Source: "http", Action: action}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if entries, err := app.AuditLog.Query(gopi.AuditQuery{}); err != nil {
+			t.Error(err)
+		} else if len(entries) != 3 || entries[0].Action != "GPIO.SetPinMode" {
+			t.Error("Unexpected entries", entries)
+		}
+	})
+}
+
+func Test_Audit_002(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	// Entries are appended to the file
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		tool.Test(t, []string{"-audit.path=" + path}, new(App), func(app *App) {
+			entries := []gopi.AuditEntry{
+				{Time: now.Add(-time.Hour), Identity: "alice", Source: "http", Action: "POST /admin/api/gpio"},
+				{Time: now, Identity: "bob", Source: "grpc", Action: "/gopi.gpio.GPIO/WritePin"},
+				{Time: now, Identity: "bob", Source: "shell", Action: "GPIO.WritePin", New: "17 high"},
+			}
+			for _, entry := range entries {
+				if err := app.AuditLog.Record(context.Background(), entry); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+
+	tool.Test(t, []string{"-audit.path=" + path}, new(App), func(app *App) {
+		tests := []struct {
+			q gopi.AuditQuery
+			n int
+		}{
+			{gopi.AuditQuery{}, 6},
+			{gopi.AuditQuery{Limit: 2}, 2},
+			{gopi.AuditQuery{Identity: "bob"}, 4},
+			{gopi.AuditQuery{Source: "http"}, 2},
+			{gopi.AuditQuery{Action: "*WritePin"}, 4},
+			{gopi.AuditQuery{Action: "POST *"}, 2},
+			{gopi.AuditQuery{Since: now.Add(-time.Minute)}, 4},
+			{gopi.AuditQuery{Until: now.Add(-time.Minute)}, 2},
+		}
+		for _, test := range tests {
+			if entries, err := app.AuditLog.Query(test.q); err != nil {
+				t.Error(err)
+			} else if len(entries) != test.n {
+				t.Error("Unexpected entries for", test.q, entries)
+			}
+		}
+	})
+}
+
+func Test_Audit_003(t *testing.T) {
+	tests := map[string]bool{
+		"GPIO.WritePin":            true,
+		"GPIO.ReadPin":             false,
+		"/gopi.gpio.GPIO/SetMode":  true,
+		"/gopi.gpio.GPIO/GetPins":  false,
+		"/gopi.shell.Shell/Stream": false,
+		"Relay.Set":                true,
+		"":                         false,
+	}
+	for method, expected := range tests {
+		if gopi.AuditMethod(method) != expected {
+			t.Error("Unexpected result for", method)
+		}
+	}
+}
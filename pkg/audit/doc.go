@@ -0,0 +1,14 @@
+// Audit package implements gopi.AuditLog, which records actions that
+// change state: who performed the action, what was changed and when.
+// Entries are appended to the -audit.path file as one JSON object per
+// line, or kept in memory when there is no file:
+//
+//	{"time":"2021-03-01T18:30:00Z","identity":"phone","source":"http","action":"POST /admin/api/gpio","old":"GPIO_LOW","new":"GPIO_HIGH"}
+//
+// When the unit is used, the HTTP server records requests other than
+// GET, HEAD and OPTIONS, the gRPC server and remote shell record calls
+// to methods which change state, and the cloud connector records methods
+// called from the cloud. Handlers which know the old and new values set
+// them with gopi.SetAuditChange. Entries are returned by Query, which is
+// also served by the admin interface.
+package audit
@@ -0,0 +1,13 @@
+package audit
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.AuditLog
+	graph.RegisterUnit(reflect.TypeOf(&audit{}), reflect.TypeOf((*gopi.AuditLog)(nil)))
+}
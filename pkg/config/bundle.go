@@ -0,0 +1,228 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/djthorpe/gopi/v3"
+	"github.com/hashicorp/go-multierror"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// bundle contains flags and files for provisioning
+type bundle struct {
+	Name    string            `json:"name"`
+	Created time.Time         `json:"created"`
+	Flags   map[string]string `json:"flags"`
+	Files   []*bundleFile     `json:"files,omitempty"`
+}
+
+// bundleFile is a file for a path flag, where the path is relative
+// to a folder or empty for a file
+type bundleFile struct {
+	Flag string      `json:"flag"`
+	Path string      `json:"path,omitempty"`
+	Mode os.FileMode `json:"mode"`
+	Data []byte      `json:"data"`
+}
+
+// signedBundle contains an encoded bundle and the HMAC-SHA256 signature
+// of the encoded bundle
+type signedBundle struct {
+	Bundle    json.RawMessage `json:"bundle"`
+	Signature string          `json:"signature"`
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Flags with this name or prefix are not included in bundles
+	bundleExclude = "provision"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Export writes the set flags and the files and folders named by
+// path flags as a bundle signed with a key
+func (this *config) Export(w io.Writer, key []byte) error {
+	if len(key) == 0 {
+		return gopi.ErrBadParameter.WithPrefix("Export")
+	}
+
+	// Set flags
+	b := &bundle{
+		Name:    this.FlagSet.Name(),
+		Created: time.Now().UTC(),
+		Flags:   make(map[string]string),
+	}
+	this.FlagSet.Visit(func(f *flag.Flag) {
+		if isBundleFlag(f.Name) {
+			b.Flags[f.Name] = f.Value.String()
+		}
+	})
+
+	// Files for path flags
+	var result error
+	this.FlagSet.VisitAll(func(f *flag.Flag) {
+		if this.paths[f.Name] == false || isBundleFlag(f.Name) == false {
+			return
+		} else if path := f.Value.String(); path == "" {
+			return
+		} else if files, err := readBundleFiles(f.Name, path); err != nil {
+			result = multierror.Append(result, err)
+		} else {
+			b.Files = append(b.Files, files...)
+		}
+	})
+	if result != nil {
+		return result
+	}
+
+	// Sign and write
+	if data, err := json.Marshal(b); err != nil {
+		return err
+	} else {
+		return json.NewEncoder(w).Encode(&signedBundle{data, signBundle(key, data)})
+	}
+}
+
+// Import verifies a bundle signed with a key, sets flags which have
+// not been set on the command line and writes files which do not
+// exist. It should be called after Parse
+func (this *config) Import(r io.Reader, key []byte) error {
+	if len(key) == 0 {
+		return gopi.ErrBadParameter.WithPrefix("Import")
+	}
+
+	// Decode and verify signature
+	var s signedBundle
+	var b bundle
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return err
+	} else if hmac.Equal([]byte(s.Signature), []byte(signBundle(key, s.Bundle))) == false {
+		return gopi.ErrUnexpectedResponse.WithPrefix("Import: Invalid signature")
+	} else if err := json.Unmarshal(s.Bundle, &b); err != nil {
+		return err
+	} else if b.Name != this.FlagSet.Name() {
+		return gopi.ErrBadParameter.WithPrefix("Import: ", b.Name)
+	}
+
+	// Set flags which have not been set, ignoring flags which
+	// are not defined
+	set := make(map[string]bool)
+	this.FlagSet.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	var result error
+	for name, value := range b.Flags {
+		if isBundleFlag(name) == false || set[name] {
+			continue
+		} else if this.FlagSet.Lookup(name) == nil {
+			continue
+		} else if err := this.FlagSet.Set(name, value); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Write files which do not exist
+	for _, file := range b.Files {
+		if this.paths[file.Flag] == false {
+			continue
+		} else if root := this.GetString(file.Flag); root == "" {
+			continue
+		} else if err := writeBundleFile(root, file); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Return any errors
+	return result
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func isBundleFlag(name string) bool {
+	return name != bundleExclude && strings.HasPrefix(name, bundleExclude+".") == false
+}
+
+func signBundle(key, data []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// readBundleFiles returns a file, or the regular files within a
+// folder. A path which does not exist returns no files
+func readBundleFiles(name, path string) ([]*bundleFile, error) {
+	stat, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	} else if stat.Mode().IsRegular() {
+		if data, err := ioutil.ReadFile(path); err != nil {
+			return nil, err
+		} else {
+			return []*bundleFile{{name, "", stat.Mode().Perm(), data}}, nil
+		}
+	} else if stat.IsDir() == false {
+		return nil, gopi.ErrBadParameter.WithPrefix("-", name)
+	}
+
+	var files []*bundleFile
+	if err := filepath.Walk(path, func(child string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if info.Mode().IsRegular() == false {
+			return nil
+		} else if rel, err := filepath.Rel(path, child); err != nil {
+			return err
+		} else if data, err := ioutil.ReadFile(child); err != nil {
+			return err
+		} else {
+			files = append(files, &bundleFile{name, filepath.ToSlash(rel), info.Mode().Perm(), data})
+			return nil
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// writeBundleFile writes a file relative to a root path, unless the
+// file already exists
+func writeBundleFile(root string, file *bundleFile) error {
+	path := root
+	if file.Path != "" {
+		rel := filepath.Clean(filepath.FromSlash(file.Path))
+		if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return gopi.ErrBadParameter.WithPrefix("Import: ", file.Path)
+		}
+		path = filepath.Join(root, rel)
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	} else if os.IsNotExist(err) == false {
+		return err
+	} else if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	} else if mode := file.Mode.Perm(); mode == 0 {
+		return ioutil.WriteFile(path, file.Data, 0644)
+	} else {
+		return ioutil.WriteFile(path, file.Data, mode)
+	}
+}
@@ -0,0 +1,105 @@
+package config_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/djthorpe/gopi/v3/pkg/config"
+)
+
+func Test_Bundle_001(t *testing.T) {
+	src, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dst)
+
+	// Files in a folder
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	} else if err := ioutil.WriteFile(filepath.Join(src, "a.keycode"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	} else if err := ioutil.WriteFile(filepath.Join(src, "sub", "b.keycode"), []byte("b"), 0600); err != nil {
+		t.Fatal(err)
+	} else if err := ioutil.WriteFile(filepath.Join(dst, "a.keycode"), []byte("existing"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Export
+	var buf bytes.Buffer
+	key := []byte("secret")
+	cfg := config.New(t.Name(), []string{"-db", src, "-volume", "11", "-name", "device", "-provision.key", "key"})
+	cfg.FlagPath("db", "", "Folder")
+	cfg.FlagUint("volume", 0, "Volume")
+	cfg.FlagString("name", "", "Name")
+	cfg.FlagString("provision.key", "", "Key")
+	if err := cfg.Parse(); err != nil {
+		t.Fatal(err)
+	} else if err := cfg.Export(&buf, key); err != nil {
+		t.Fatal(err)
+	} else if strings.Contains(buf.String(), "provision.key") {
+		t.Error("Unexpected provision flag in bundle")
+	}
+
+	// Import, where flags set on the command line are retained
+	cfg = config.New(t.Name(), []string{"-db", dst, "-name", "other"})
+	db := cfg.FlagPath("db", "", "Folder")
+	volume := cfg.FlagUint("volume", 0, "Volume")
+	name := cfg.FlagString("name", "", "Name")
+	if err := cfg.Parse(); err != nil {
+		t.Fatal(err)
+	} else if err := cfg.Import(bytes.NewReader(buf.Bytes()), key); err != nil {
+		t.Fatal(err)
+	} else if *db != dst || *volume != 11 || *name != "other" {
+		t.Error("Unexpected flags", *db, *volume, *name)
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(dst, "a.keycode")); err != nil {
+		t.Error(err)
+	} else if string(data) != "existing" {
+		t.Error("Unexpected overwrite of existing file")
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(dst, "sub", "b.keycode")); err != nil {
+		t.Error(err)
+	} else if string(data) != "b" {
+		t.Error("Unexpected file contents", string(data))
+	}
+}
+
+func Test_Bundle_002(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := config.New(t.Name(), []string{"-volume", "11"})
+	cfg.FlagUint("volume", 0, "Volume")
+	if err := cfg.Parse(); err != nil {
+		t.Fatal(err)
+	} else if err := cfg.Export(&buf, nil); err == nil {
+		t.Error("Expected error for empty key")
+	} else if err := cfg.Export(&buf, []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wrong key, modified bundle and wrong name are rejected
+	cfg = config.New(t.Name(), nil)
+	volume := cfg.FlagUint("volume", 0, "Volume")
+	if err := cfg.Parse(); err != nil {
+		t.Fatal(err)
+	} else if err := cfg.Import(bytes.NewReader(buf.Bytes()), []byte("other")); err == nil {
+		t.Error("Expected error for wrong key")
+	} else if err := cfg.Import(strings.NewReader(strings.Replace(buf.String(), "11", "12", 1)), []byte("secret")); err == nil {
+		t.Error("Expected error for modified bundle")
+	} else if *volume != 0 {
+		t.Error("Unexpected volume", *volume)
+	}
+	cfg = config.New("other", nil)
+	if err := cfg.Import(bytes.NewReader(buf.Bytes()), []byte("secret")); err == nil {
+		t.Error("Expected error for wrong name")
+	}
+}
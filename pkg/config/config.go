@@ -20,6 +20,9 @@ type config struct {
 	args     []string
 	commands *command
 	flags    map[string][]string
+	paths    map[string]bool
+	secrets  map[string]bool
+	fields   []*field
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -37,6 +40,8 @@ func New(name string, args []string) gopi.Config {
 	}
 	this.args = args
 	this.flags = make(map[string][]string)
+	this.paths = make(map[string]bool)
+	this.secrets = make(map[string]bool)
 	this.commands = NewCommand(name, "", "", args, nil)
 	return this
 }
@@ -61,6 +66,11 @@ func (this *config) Parse() error {
 		return err
 	}
 
+	// Set flag groups from the environment and file
+	if err := this.bind(); err != nil {
+		return err
+	}
+
 	// Return success
 	return nil
 }
@@ -185,9 +195,30 @@ func (this *config) FlagFloat(name string, value float64, usage string, cmds ...
 	return this.FlagSet.Float64(name, value, usage)
 }
 
+// FlagPath defines a flag for a file or folder, which is included
+// in exported bundles
+func (this *config) FlagPath(name, value, usage string, cmds ...string) *string {
+	this.flags[name] = cmds
+	this.paths[name] = true
+	return this.FlagSet.String(name, value, usage)
+}
+
+// FlagSecret defines a flag for a password, key or token, which is
+// not recorded or shown
+func (this *config) FlagSecret(name, value, usage string, cmds ...string) *string {
+	this.flags[name] = cmds
+	this.secrets[name] = true
+	return this.FlagSet.String(name, value, usage)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // GET PROPERTIES
 
+// Secret returns true when a flag was defined with FlagSecret
+func (this *config) Secret(name string) bool {
+	return this.secrets[name]
+}
+
 func (this *config) GetString(name string) string {
 	if flag := this.FlagSet.Lookup(name); flag == nil {
 		return ""
@@ -246,6 +277,21 @@ func (this *config) GetDuration(name string) time.Duration {
 	}
 }
 
+// Values returns flag values keyed by name, for flags which have been
+// set or for all flags when the argument is true
+func (this *config) Values(all bool) map[string]string {
+	result := make(map[string]string)
+	visit := func(f *flag.Flag) {
+		result[f.Name] = f.Value.String()
+	}
+	if all {
+		this.FlagSet.VisitAll(visit)
+	} else {
+		this.FlagSet.Visit(visit)
+	}
+	return result
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -253,7 +299,11 @@ func (this *config) String() string {
 	str := "<config"
 	str += " name=" + strconv.Quote(this.FlagSet.Name())
 	this.FlagSet.Visit(func(f *flag.Flag) {
-		str += fmt.Sprintf(" %v=%q", f.Name, f.Value.String())
+		if this.secrets[f.Name] {
+			str += fmt.Sprintf(" %v=<secret>", f.Name)
+		} else {
+			str += fmt.Sprintf(" %v=%q", f.Name, f.Value.String())
+		}
 	})
 	return str + ">"
 }
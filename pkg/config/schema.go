@@ -0,0 +1,353 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/djthorpe/gopi/v3"
+	"github.com/hashicorp/go-multierror"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// field is a flag bound to the field of a struct, with the tags:
+//
+//	config:"name"          The flag name, appended to the prefix of the group
+//	usage:"text"           Description of the flag
+//	default:"value"        Default value
+//	min:"n" max:"n"        Range for numbers and durations
+//	enum:"a,b,c"           Choices for strings
+//	required:"true"        The value cannot be empty or zero
+//	required:"name=value"  Required when another field in the group has a value
+type field struct {
+	name     string
+	key      string
+	value    reflect.Value
+	min, max string
+	enum     []string
+	required string
+}
+
+// fieldValue sets a struct field from a flag
+type fieldValue struct {
+	reflect.Value
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Flag for a JSON file with values for flag groups
+	flagConfig = "config"
+
+	// Prefix for environment variables with values for flag groups
+	envPrefix = "GOPI_"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// FlagStruct defines a flag for each field of a struct which has a
+// config tag. The flag "speed" in group "spi" is named "spi.speed" and
+// is set from the command line, then the environment variable
+// GOPI_SPI_SPEED, then the file named by the -config flag
+func (this *config) FlagStruct(prefix string, v interface{}, cmds ...string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return gopi.ErrBadParameter.WithPrefix("FlagStruct: ", prefix)
+	}
+
+	// Define the file flag once
+	if this.FlagSet.Lookup(flagConfig) == nil {
+		this.FlagPath(flagConfig, "", "JSON file with values for flags")
+	}
+
+	// Define a flag for each tagged field
+	rv = rv.Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		tag := rv.Type().Field(i).Tag
+		name := tag.Get("config")
+		if name == "" || name == "-" || rv.Field(i).CanSet() == false {
+			continue
+		}
+		f := &field{
+			name:     strings.Trim(prefix+"."+name, "."),
+			key:      name,
+			value:    rv.Field(i),
+			min:      tag.Get("min"),
+			max:      tag.Get("max"),
+			required: tag.Get("required"),
+		}
+		if enum := tag.Get("enum"); enum != "" {
+			f.enum = strings.Split(enum, ",")
+		}
+		value := &fieldValue{f.value}
+		if value.kind() == reflect.Invalid {
+			return gopi.ErrBadParameter.WithPrefix("FlagStruct: -", f.name, ": Unsupported type ", f.value.Type())
+		} else if def := tag.Get("default"); def != "" {
+			if err := value.Set(def); err != nil {
+				return gopi.ErrBadParameter.WithPrefix("FlagStruct: -", f.name, ": ", err)
+			}
+		}
+		if this.FlagSet.Lookup(f.name) != nil {
+			return gopi.ErrDuplicateEntry.WithPrefix("FlagStruct: -", f.name)
+		}
+		this.flags[f.name] = cmds
+		this.FlagSet.Var(value, f.name, f.usage(tag.Get("usage")))
+		this.fields = append(this.fields, f)
+	}
+
+	// Return success
+	return nil
+}
+
+// Validate returns errors for fields of flag groups which are out of
+// range, not one of the choices or required but not set
+func (this *config) Validate() error {
+	var result error
+	groups := make(map[string][]*field)
+	for _, f := range this.fields {
+		prefix := strings.TrimSuffix(f.name, f.key)
+		groups[prefix] = append(groups[prefix], f)
+	}
+	for _, f := range this.fields {
+		if err := f.validate(groups[strings.TrimSuffix(f.name, f.key)]); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%w: -%v: %v", gopi.ErrBadParameter, f.name, err))
+		}
+	}
+	return multierror.Flatten(result)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// bind sets flags for fields which were not set on the command line
+// from the environment, then from the file named by the -config flag
+func (this *config) bind() error {
+	if len(this.fields) == 0 {
+		return nil
+	}
+
+	// Read the file
+	values := make(map[string]interface{})
+	if path := this.GetString(flagConfig); path != "" {
+		if data, err := ioutil.ReadFile(path); err != nil {
+			return err
+		} else if err := json.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+	}
+
+	// Set flags from the environment or file
+	set := make(map[string]bool)
+	this.FlagSet.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	var result error
+	for _, f := range this.fields {
+		if set[f.name] {
+			continue
+		}
+		if value, exists := os.LookupEnv(f.env()); exists {
+			if err := this.FlagSet.Set(f.name, value); err != nil {
+				result = multierror.Append(result, fmt.Errorf("%v: %w", f.env(), err))
+			}
+		} else if value, exists := values[f.name]; exists {
+			if err := this.FlagSet.Set(f.name, fmt.Sprint(value)); err != nil {
+				result = multierror.Append(result, fmt.Errorf("-%v: %w", f.name, err))
+			}
+		}
+	}
+	return multierror.Flatten(result)
+}
+
+// usage returns the description with the range and choices
+func (this *field) usage(usage string) string {
+	if len(this.enum) > 0 {
+		usage += fmt.Sprintf(" (%v)", strings.Join(this.enum, ", "))
+	}
+	if this.min != "" || this.max != "" {
+		usage += fmt.Sprintf(" (%v..%v)", this.min, this.max)
+	}
+	if this.required == "true" {
+		usage += " (required)"
+	} else if this.required != "" {
+		usage += fmt.Sprintf(" (required when %v)", this.required)
+	}
+	return strings.TrimSpace(usage)
+}
+
+// env returns the name of the environment variable for the field
+func (this *field) env() string {
+	return envPrefix + strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(this.name))
+}
+
+// validate checks a field, where group contains the other fields in
+// the same group
+func (this *field) validate(group []*field) error {
+	value := &fieldValue{this.value}
+
+	// Check required fields
+	if this.required == "true" && this.value.IsZero() {
+		return fmt.Errorf("Required")
+	} else if kv := strings.SplitN(this.required, "=", 2); len(kv) == 2 && this.value.IsZero() {
+		for _, other := range group {
+			if other.key == kv[0] && (&fieldValue{other.value}).String() == kv[1] {
+				return fmt.Errorf("Required when %v=%v", other.name, kv[1])
+			}
+		}
+	}
+
+	// Check choices
+	if len(this.enum) > 0 && this.value.IsZero() == false {
+		str := value.String()
+		found := false
+		for _, choice := range this.enum {
+			if strings.TrimSpace(choice) == str {
+				found = true
+				break
+			}
+		}
+		if found == false {
+			return fmt.Errorf("%q is not one of %v", str, strings.Join(this.enum, ", "))
+		}
+	}
+
+	// Check range
+	if this.min != "" || this.max != "" {
+		n, err := value.number()
+		if err != nil {
+			return err
+		}
+		if this.min != "" {
+			if min, err := (&fieldValue{reflect.New(this.value.Type()).Elem()}).parse(this.min); err != nil {
+				return err
+			} else if n < min {
+				return fmt.Errorf("%v is less than %v", value, this.min)
+			}
+		}
+		if this.max != "" {
+			if max, err := (&fieldValue{reflect.New(this.value.Type()).Elem()}).parse(this.max); err != nil {
+				return err
+			} else if n > max {
+				return fmt.Errorf("%v is greater than %v", value, this.max)
+			}
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// kind returns the kind of value, or Invalid for unsupported types
+func (this *fieldValue) kind() reflect.Kind {
+	if this.Type() == durationType {
+		return reflect.Int64
+	}
+	switch k := this.Kind(); k {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64:
+		return k
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.Uint
+	default:
+		return reflect.Invalid
+	}
+}
+
+// number returns a numeric value for range checks
+func (this *fieldValue) number() (float64, error) {
+	switch this.kind() {
+	case reflect.Int, reflect.Int64:
+		return float64(this.Int()), nil
+	case reflect.Uint:
+		return float64(this.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return this.Float(), nil
+	default:
+		return 0, gopi.ErrBadParameter.WithPrefix("Range for ", this.Type())
+	}
+}
+
+// parse sets the value from a string and returns the numeric value
+func (this *fieldValue) parse(str string) (float64, error) {
+	if err := this.Set(str); err != nil {
+		return 0, err
+	}
+	return this.number()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// FLAG VALUE
+
+func (this *fieldValue) String() string {
+	if this.Value.IsValid() == false {
+		return ""
+	} else if this.Type() == durationType {
+		return time.Duration(this.Int()).String()
+	} else {
+		return fmt.Sprint(this.Interface())
+	}
+}
+
+func (this *fieldValue) Set(str string) error {
+	switch this.kind() {
+	case reflect.String:
+		this.SetString(str)
+	case reflect.Bool:
+		if v, err := strconv.ParseBool(str); err != nil {
+			return err
+		} else {
+			this.SetBool(v)
+		}
+	case reflect.Int64:
+		if v, err := time.ParseDuration(str); err != nil {
+			return err
+		} else {
+			this.SetInt(int64(v))
+		}
+	case reflect.Int:
+		if v, err := strconv.ParseInt(str, 0, this.Type().Bits()); err != nil {
+			return err
+		} else {
+			this.SetInt(v)
+		}
+	case reflect.Uint:
+		if v, err := strconv.ParseUint(str, 0, this.Type().Bits()); err != nil {
+			return err
+		} else {
+			this.SetUint(v)
+		}
+	case reflect.Float32, reflect.Float64:
+		if v, err := strconv.ParseFloat(str, this.Type().Bits()); err != nil {
+			return err
+		} else {
+			this.SetFloat(v)
+		}
+	default:
+		return gopi.ErrBadParameter.WithPrefix(this.Type())
+	}
+	return nil
+}
+
+func (this *fieldValue) IsBoolFlag() bool {
+	return this.kind() == reflect.Bool
+}
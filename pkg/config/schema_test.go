@@ -0,0 +1,113 @@
+package config_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/djthorpe/gopi/v3"
+	"github.com/djthorpe/gopi/v3/pkg/config"
+)
+
+type Options struct {
+	Mode    string        `config:"mode" default:"spi" enum:"spi,i2c" usage:"Bus type"`
+	Speed   uint          `config:"speed" default:"1000" min:"100" max:"10000" usage:"Bus speed"`
+	Bus     uint          `config:"bus" required:"mode=i2c" usage:"I2C bus"`
+	Timeout time.Duration `config:"timeout" default:"1s" max:"10s"`
+	Name    string        `config:"name" required:"true"`
+	Offset  float64       `config:"offset" min:"-1"`
+	Debug   bool          `config:"debug"`
+	Ignored string
+}
+
+func Test_Schema_001(t *testing.T) {
+	var opts Options
+	cfg := config.New(t.Name(), []string{"-dev.name=test", "-dev.speed=2000", "-dev.debug"})
+	if err := cfg.FlagStruct("dev", &opts); err != nil {
+		t.Fatal(err)
+	} else if opts.Mode != "spi" || opts.Speed != 1000 || opts.Timeout != time.Second {
+		t.Error("Unexpected defaults", opts)
+	} else if err := cfg.Parse(); err != nil {
+		t.Fatal(err)
+	} else if err := cfg.Validate(); err != nil {
+		t.Error(err)
+	} else if opts.Name != "test" || opts.Speed != 2000 || opts.Debug != true {
+		t.Error("Unexpected values", opts)
+	} else if cfg.GetUint("dev.speed") != 2000 || cfg.GetDuration("dev.timeout") != time.Second {
+		t.Error("Unexpected flag values")
+	}
+}
+
+func Test_Schema_002(t *testing.T) {
+	tests := [][]string{
+		{"-dev.speed=20000"},
+		{"-dev.name=test", "-dev.speed=20000"},
+		{"-dev.name=test", "-dev.mode=uart"},
+		{"-dev.name=test", "-dev.mode=i2c"},
+		{"-dev.name=test", "-dev.timeout=1m"},
+		{"-dev.name=test", "-dev.offset=-2"},
+	}
+	for _, args := range tests {
+		var opts Options
+		cfg := config.New(t.Name(), args)
+		if err := cfg.FlagStruct("dev", &opts); err != nil {
+			t.Fatal(err)
+		} else if err := cfg.Parse(); err != nil {
+			t.Fatal(err)
+		} else if err := cfg.Validate(); errors.Is(err, gopi.ErrBadParameter) == false {
+			t.Error("Expected ErrBadParameter for", args, err)
+		} else {
+			t.Log(err)
+		}
+	}
+
+	// Required when the mode is i2c
+	var opts Options
+	cfg := config.New(t.Name(), []string{"-dev.name=test", "-dev.mode=i2c", "-dev.bus=1"})
+	if err := cfg.FlagStruct("dev", &opts); err != nil {
+		t.Fatal(err)
+	} else if err := cfg.Parse(); err != nil {
+		t.Fatal(err)
+	} else if err := cfg.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_Schema_003(t *testing.T) {
+	dir, err := ioutil.TempDir("", "schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, []byte(`{ "dev.name": "file", "dev.speed": 500, "dev.bus": 2 }`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("GOPI_DEV_SPEED", "600")
+	defer os.Unsetenv("GOPI_DEV_SPEED")
+
+	// Command line, then environment, then file
+	var opts Options
+	cfg := config.New(t.Name(), []string{"-config", path, "-dev.bus=3"})
+	if err := cfg.FlagStruct("dev", &opts); err != nil {
+		t.Fatal(err)
+	} else if err := cfg.Parse(); err != nil {
+		t.Fatal(err)
+	} else if opts.Name != "file" || opts.Speed != 600 || opts.Bus != 3 {
+		t.Error("Unexpected values", opts)
+	}
+}
+
+func Test_Schema_004(t *testing.T) {
+	cfg := config.New(t.Name(), nil)
+	if err := cfg.FlagStruct("dev", Options{}); errors.Is(err, gopi.ErrBadParameter) == false {
+		t.Error("Expected ErrBadParameter", err)
+	} else if err := cfg.FlagStruct("dev", &Options{}); err != nil {
+		t.Error(err)
+	} else if err := cfg.FlagStruct("dev", &Options{}); errors.Is(err, gopi.ErrDuplicateEntry) == false {
+		t.Error("Expected ErrDuplicateEntry", err)
+	}
+}
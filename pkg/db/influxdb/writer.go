@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/djthorpe/gopi/v3"
+	retry "github.com/djthorpe/gopi/v3/pkg/retry"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -33,10 +34,12 @@ type Writer struct {
 	url        *string
 	skipverify *bool
 	timeout    *time.Duration
+	retry      *retry.Flags
 
 	// Instance variables
 	endpoint
 	version string
+	backoff *retry.Backoff
 }
 
 type endpoint struct {
@@ -58,6 +61,9 @@ const (
 
 	EnvUsername = "INFLUX_USERNAME"
 	EnvPassword = "INFLUX_PASSWORD"
+
+	// Maximum number of measurements held while retrying
+	maxPending = 1000
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -67,6 +73,7 @@ func (this *Writer) Define(cfg gopi.Config) error {
 	this.url = cfg.FlagString("influxdb.url", "", "Database URL")
 	this.skipverify = cfg.FlagBool("influxdb.skipverify", false, "Skip SSL certificate verification")
 	this.timeout = cfg.FlagDuration("influxdb.timeout", 15*time.Second, "Database connection timeout")
+	this.retry = retry.Define(cfg, "influxdb", 5*time.Second, 5*time.Minute, 10)
 	return nil
 }
 
@@ -78,6 +85,13 @@ func (this *Writer) New(cfg gopi.Config) error {
 		this.endpoint = endpoint
 	}
 
+	// Backoff for failed writes
+	if backoff, err := this.retry.New(); err != nil {
+		return err
+	} else {
+		this.backoff = backoff
+	}
+
 	// Create transport
 	this.Client = &http.Client{
 		Timeout: *this.timeout,
@@ -113,13 +127,33 @@ func (this *Writer) Run(ctx context.Context) error {
 	ch := this.Publisher.Subscribe()
 	defer this.Publisher.Unsubscribe(ch)
 
+	// After a failed write, measurements are held until the retry
+	// delay has passed and then written together
+	var pending []gopi.Measurement
+	var after <-chan time.Time
+
 	for {
 		select {
 		case evt := <-ch:
-			if m, ok := evt.(gopi.Measurement); ok {
-				if err := this.Write(m); err != nil {
-					this.Print(err)
+			if m, ok := evt.(gopi.Measurement); ok == false {
+				continue
+			} else if after != nil {
+				if pending = append(pending, m); len(pending) > maxPending {
+					pending = pending[1:]
 				}
+			} else if err := this.Write(m); err != nil {
+				pending = append(pending, m)
+				after = this.retryAfter(err, len(pending))
+			}
+		case <-after:
+			if err := this.Write(pending...); err != nil {
+				after = this.retryAfter(err, len(pending))
+			} else {
+				this.backoff.Reset()
+				after = nil
+			}
+			if after == nil {
+				pending = nil
 			}
 		case <-ctx.Done():
 			return nil
@@ -127,6 +161,20 @@ func (this *Writer) Run(ctx context.Context) error {
 	}
 }
 
+// retryAfter returns a channel which fires when a failed write should
+// be retried, or nil when the retry budget is exhausted and pending
+// measurements should be dropped
+func (this *Writer) retryAfter(err error, pending int) <-chan time.Time {
+	if delay, ok := this.backoff.Next(); ok {
+		this.Debug("Write: ", err, " (retry in ", delay.Truncate(time.Millisecond), ")")
+		return time.After(delay)
+	} else {
+		this.Print("Write: ", err, " (dropped ", pending, " measurements)")
+		this.backoff.Reset()
+		return nil
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PROPERTIES
 
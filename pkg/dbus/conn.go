@@ -0,0 +1,289 @@
+package dbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// conn is a connection to a message bus
+type conn struct {
+	sync.Mutex
+	sync.WaitGroup
+
+	bus     gopi.DBusBus
+	conn    net.Conn
+	reader  *bufio.Reader
+	serial  uint32
+	name    string
+	replies map[uint32]chan *message
+	handler func(*conn, *message)
+	err     error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	busName      = "org.freedesktop.DBus"
+	busPath      = gopi.DBusObjectPath("/org/freedesktop/DBus")
+	busInterface = "org.freedesktop.DBus"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// newConn connects to a bus address, authenticates and says hello
+// to the bus. The handler is called for signals and method calls
+func newConn(bus gopi.DBusBus, address string, handler func(*conn, *message)) (*conn, error) {
+	this := new(conn)
+	this.bus = bus
+	this.replies = make(map[uint32]chan *message)
+	this.handler = handler
+
+	// Connect and authenticate
+	if network, addr, err := parseAddress(address); err != nil {
+		return nil, err
+	} else if c, err := net.Dial(network, addr); err != nil {
+		return nil, err
+	} else {
+		this.conn = c
+		this.reader = bufio.NewReader(c)
+	}
+	if err := this.auth(); err != nil {
+		this.conn.Close()
+		return nil, err
+	}
+
+	// Receive messages in the background
+	this.WaitGroup.Add(1)
+	go this.recv()
+
+	// Say hello to obtain a unique name
+	if reply, err := this.Call(context.Background(), busName, busPath, busInterface+".Hello"); err != nil {
+		this.Close()
+		return nil, err
+	} else if len(reply) != 1 {
+		this.Close()
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("Hello")
+	} else if name, ok := reply[0].(string); ok == false {
+		this.Close()
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("Hello")
+	} else {
+		this.name = name
+	}
+
+	// Return success
+	return this, nil
+}
+
+func (this *conn) Close() error {
+	err := this.conn.Close()
+	this.WaitGroup.Wait()
+	return err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *conn) Name() string {
+	return this.name
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Call a method and wait for the reply
+func (this *conn) Call(ctx context.Context, dest string, path gopi.DBusObjectPath, method string, args ...interface{}) ([]interface{}, error) {
+	msg := &message{
+		Type:        MESSAGE_TYPE_METHOD_CALL,
+		Destination: dest,
+		Path:        path,
+		Body:        args,
+	}
+	if i := strings.LastIndexByte(method, '.'); i <= 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("Call: ", method)
+	} else {
+		msg.Interface, msg.Member = method[:i], method[i+1:]
+	}
+
+	// Register for the reply before sending
+	reply := make(chan *message, 1)
+	serial, err := this.send(msg, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait for reply or cancel
+	select {
+	case msg := <-reply:
+		if msg == nil {
+			return nil, this.closed()
+		} else if err := msg.Err(); err != nil {
+			return nil, err
+		} else {
+			return msg.Body, nil
+		}
+	case <-ctx.Done():
+		this.Mutex.Lock()
+		delete(this.replies, serial)
+		this.Mutex.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// Send a message without waiting for a reply
+func (this *conn) Send(msg *message) error {
+	_, err := this.send(msg, nil)
+	return err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *conn) send(msg *message, reply chan *message) (uint32, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.err != nil {
+		return 0, this.err
+	}
+
+	// Allocate a serial number, which is never zero
+	if this.serial++; this.serial == 0 {
+		this.serial++
+	}
+	msg.Serial = this.serial
+	if reply == nil && msg.Type == MESSAGE_TYPE_METHOD_CALL {
+		msg.Flags |= MESSAGE_FLAG_NO_REPLY_EXPECTED
+	}
+
+	// Marshal and write message
+	if data, err := msg.Marshal(); err != nil {
+		return 0, err
+	} else if _, err := this.conn.Write(data); err != nil {
+		return 0, err
+	} else if reply != nil {
+		this.replies[msg.Serial] = reply
+	}
+
+	// Return success
+	return msg.Serial, nil
+}
+
+func (this *conn) recv() {
+	defer this.WaitGroup.Done()
+	for {
+		msg, err := readMessage(this.reader)
+		if err != nil {
+			this.close(err)
+			return
+		}
+		switch msg.Type {
+		case MESSAGE_TYPE_METHOD_RETURN, MESSAGE_TYPE_ERROR:
+			this.Mutex.Lock()
+			if reply, exists := this.replies[msg.ReplySerial]; exists {
+				delete(this.replies, msg.ReplySerial)
+				reply <- msg
+			}
+			this.Mutex.Unlock()
+		case MESSAGE_TYPE_SIGNAL, MESSAGE_TYPE_METHOD_CALL:
+			if this.handler != nil {
+				this.handler(this, msg)
+			}
+		}
+	}
+}
+
+// close marks the connection as closed and releases any callers
+// waiting for a reply
+func (this *conn) close(err error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.err = gopi.ErrOutOfOrder.WithPrefix("Connection closed: ", err)
+	for serial, reply := range this.replies {
+		close(reply)
+		delete(this.replies, serial)
+	}
+}
+
+func (this *conn) closed() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.err
+}
+
+// auth authenticates using the EXTERNAL mechanism, which
+// uses the credentials of the process
+func (this *conn) auth() error {
+	uid := hex.EncodeToString([]byte(fmt.Sprint(os.Getuid())))
+	if _, err := this.conn.Write([]byte("\x00AUTH EXTERNAL " + uid + "\r\n")); err != nil {
+		return err
+	} else if line, err := this.reader.ReadString('\n'); err != nil {
+		return err
+	} else if strings.HasPrefix(line, "OK ") == false {
+		return gopi.ErrUnexpectedResponse.WithPrefix("AUTH: ", strings.TrimSpace(line))
+	} else if _, err := this.conn.Write([]byte("BEGIN\r\n")); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+// parseAddress returns the network and address from a bus address
+// of the form unix:path=/path or unix:abstract=name, where several
+// addresses can be separated by semicolons
+func parseAddress(address string) (string, string, error) {
+	for _, addr := range strings.Split(address, ";") {
+		if strings.HasPrefix(addr, "unix:") == false {
+			continue
+		}
+		for _, kv := range strings.Split(strings.TrimPrefix(addr, "unix:"), ",") {
+			if strings.HasPrefix(kv, "path=") {
+				return "unix", unescape(strings.TrimPrefix(kv, "path=")), nil
+			} else if strings.HasPrefix(kv, "abstract=") {
+				return "unix", "@" + unescape(strings.TrimPrefix(kv, "abstract=")), nil
+			}
+		}
+	}
+	return "", "", gopi.ErrBadParameter.WithPrefix("Unsupported bus address: ", address)
+}
+
+// unescape decodes %xx escapes in an address value
+func unescape(value string) string {
+	result := ""
+	for i := 0; i < len(value); i++ {
+		if value[i] == '%' && i+2 < len(value) {
+			if b, err := hex.DecodeString(value[i+1 : i+3]); err == nil {
+				result += string(b)
+				i += 2
+				continue
+			}
+		}
+		result += string(value[i])
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *conn) String() string {
+	str := "<dbus.conn"
+	str += " bus=" + fmt.Sprint(this.bus)
+	if this.name != "" {
+		str += fmt.Sprintf(" name=%q", this.name)
+	}
+	return str + ">"
+}
@@ -0,0 +1,317 @@
+package dbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type dbus struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	sync.Mutex
+
+	address map[gopi.DBusBus]*string
+	conns   map[gopi.DBusBus]*conn
+	objects map[object]map[string]gopi.DBusMethod
+}
+
+type object struct {
+	bus   gopi.DBusBus
+	path  gopi.DBusObjectPath
+	iface string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	defaultSystemBus = "unix:path=/var/run/dbus/system_bus_socket"
+)
+
+const (
+	errUnknownMethod = "org.freedesktop.DBus.Error.UnknownMethod"
+	errFailed        = "org.freedesktop.DBus.Error.Failed"
+	peerInterface    = "org.freedesktop.DBus.Peer"
+)
+
+const (
+	// RequestName flags and replies
+	nameFlagDoNotQueue   = 4
+	nameReplyPrimary     = 1
+	nameReplyAlreadyOwns = 4
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *dbus) Define(cfg gopi.Config) error {
+	system := defaultSystemBus
+	if addr := os.Getenv("DBUS_SYSTEM_BUS_ADDRESS"); addr != "" {
+		system = addr
+	}
+	this.address = map[gopi.DBusBus]*string{
+		gopi.DBUS_BUS_SYSTEM:  cfg.FlagString("dbus.system", system, "System bus address"),
+		gopi.DBUS_BUS_SESSION: cfg.FlagString("dbus.session", os.Getenv("DBUS_SESSION_BUS_ADDRESS"), "Session bus address"),
+	}
+	return nil
+}
+
+func (this *dbus) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	this.conns = make(map[gopi.DBusBus]*conn)
+	this.objects = make(map[object]map[string]gopi.DBusMethod)
+
+	// Return success
+	return nil
+}
+
+func (this *dbus) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Close connections
+	var result error
+	for bus, conn := range this.conns {
+		if err := conn.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+		delete(this.conns, bus)
+	}
+
+	// Release resources
+	this.objects = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *dbus) String() string {
+	str := "<dbus"
+	for bus, conn := range this.conns {
+		str += " " + fmt.Sprint(bus) + "=" + fmt.Sprint(conn)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *dbus) Call(ctx context.Context, bus gopi.DBusBus, dest string, path gopi.DBusObjectPath, method string, args ...interface{}) ([]interface{}, error) {
+	if path.IsValid() == false {
+		return nil, gopi.ErrBadParameter.WithPrefix("Call: ", path)
+	} else if conn, err := this.conn(bus); err != nil {
+		return nil, err
+	} else {
+		return conn.Call(ctx, dest, path, method, args...)
+	}
+}
+
+func (this *dbus) Subscribe(bus gopi.DBusBus, iface, member string, path gopi.DBusObjectPath) error {
+	if path != "" && path.IsValid() == false {
+		return gopi.ErrBadParameter.WithPrefix("Subscribe: ", path)
+	} else if conn, err := this.conn(bus); err != nil {
+		return err
+	} else if _, err := conn.Call(context.Background(), busName, busPath, busInterface+".AddMatch", matchRule(iface, member, path)); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *dbus) Unsubscribe(bus gopi.DBusBus, iface, member string, path gopi.DBusObjectPath) error {
+	if conn, err := this.conn(bus); err != nil {
+		return err
+	} else if _, err := conn.Call(context.Background(), busName, busPath, busInterface+".RemoveMatch", matchRule(iface, member, path)); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *dbus) Export(bus gopi.DBusBus, path gopi.DBusObjectPath, iface string, methods map[string]gopi.DBusMethod) error {
+	// Check parameters
+	if path.IsValid() == false || iface == "" || len(methods) == 0 {
+		return gopi.ErrBadParameter.WithPrefix("Export")
+	}
+
+	// Connect to bus so that method calls are received
+	if _, err := this.conn(bus); err != nil {
+		return err
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	key := object{bus, path, iface}
+	if _, exists := this.objects[key]; exists {
+		return gopi.ErrDuplicateEntry.WithPrefix("Export: ", path, " ", iface)
+	} else {
+		this.objects[key] = methods
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *dbus) Unexport(bus gopi.DBusBus, path gopi.DBusObjectPath, iface string) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	key := object{bus, path, iface}
+	if _, exists := this.objects[key]; exists == false {
+		return gopi.ErrNotFound.WithPrefix("Unexport: ", path, " ", iface)
+	} else {
+		delete(this.objects, key)
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *dbus) RequestName(bus gopi.DBusBus, name string) error {
+	if name == "" {
+		return gopi.ErrBadParameter.WithPrefix("RequestName")
+	} else if conn, err := this.conn(bus); err != nil {
+		return err
+	} else if reply, err := conn.Call(context.Background(), busName, busPath, busInterface+".RequestName", name, uint32(nameFlagDoNotQueue)); err != nil {
+		return err
+	} else if len(reply) != 1 {
+		return gopi.ErrUnexpectedResponse.WithPrefix("RequestName")
+	} else if code, _ := reply[0].(uint32); code != nameReplyPrimary && code != nameReplyAlreadyOwns {
+		return gopi.ErrDuplicateEntry.WithPrefix("RequestName: ", name)
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *dbus) Name(bus gopi.DBusBus) (string, error) {
+	if conn, err := this.conn(bus); err != nil {
+		return "", err
+	} else {
+		return conn.Name(), nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// conn returns a connection to a bus, connecting on first use
+func (this *dbus) conn(bus gopi.DBusBus) (*conn, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if conn, exists := this.conns[bus]; exists {
+		return conn, nil
+	} else if address, exists := this.address[bus]; exists == false {
+		return nil, gopi.ErrBadParameter.WithPrefix("Bus: ", bus)
+	} else if *address == "" {
+		return nil, gopi.ErrNotFound.WithPrefix("No address for ", bus)
+	} else if conn, err := newConn(bus, *address, this.handler); err != nil {
+		return nil, err
+	} else {
+		this.Debug("DBus: connected ", conn)
+		this.conns[bus] = conn
+		return conn, nil
+	}
+}
+
+// handler is called on receipt of signals and method calls
+func (this *dbus) handler(conn *conn, msg *message) {
+	switch msg.Type {
+	case MESSAGE_TYPE_SIGNAL:
+		if this.Publisher != nil {
+			if err := this.Publisher.Emit(&signal{conn.bus, msg}, false); err != nil {
+				this.Debug("DBus: ", err)
+			}
+		}
+	case MESSAGE_TYPE_METHOD_CALL:
+		go this.call(conn, msg)
+	}
+}
+
+// call an exported method and send the reply
+func (this *dbus) call(conn *conn, msg *message) {
+	var result []interface{}
+	var err error
+
+	if msg.Interface == peerInterface && msg.Member == "Ping" {
+		// Respond to ping with an empty reply
+	} else if fn := this.method(conn.bus, msg); fn == nil {
+		err = gopi.ErrNotFound.WithPrefix(msg.Interface, ".", msg.Member)
+	} else {
+		result, err = fn(msg.Sender, msg.Body)
+	}
+
+	// Send reply
+	if msg.Flags&MESSAGE_FLAG_NO_REPLY_EXPECTED != 0 {
+		return
+	}
+	reply := &message{
+		Type:        MESSAGE_TYPE_METHOD_RETURN,
+		ReplySerial: msg.Serial,
+		Destination: msg.Sender,
+		Body:        result,
+	}
+	if err != nil {
+		reply.Type = MESSAGE_TYPE_ERROR
+		reply.Body = []interface{}{err.Error()}
+		if errors.Is(err, gopi.ErrNotFound) {
+			reply.ErrorName = errUnknownMethod
+		} else {
+			reply.ErrorName = errFailed
+		}
+	}
+	if err := conn.Send(reply); err != nil {
+		this.Print("DBus: ", err)
+	}
+}
+
+// method returns the exported method for a method call, searching
+// all interfaces on the object when no interface is given
+func (this *dbus) method(bus gopi.DBusBus, msg *message) gopi.DBusMethod {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	for key, methods := range this.objects {
+		if key.bus != bus || key.path != msg.Path {
+			continue
+		} else if msg.Interface != "" && key.iface != msg.Interface {
+			continue
+		} else if fn, exists := methods[msg.Member]; exists {
+			return fn
+		}
+	}
+	return nil
+}
+
+func matchRule(iface, member string, path gopi.DBusObjectPath) string {
+	rule := []string{"type='signal'"}
+	if iface != "" {
+		rule = append(rule, "interface='"+iface+"'")
+	}
+	if member != "" {
+		rule = append(rule, "member='"+member+"'")
+	}
+	if path != "" {
+		rule = append(rule, "path='"+string(path)+"'")
+	}
+	return strings.Join(rule, ",")
+}
@@ -0,0 +1,79 @@
+package dbus_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/dbus"
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+)
+
+type App struct {
+	gopi.Unit
+	gopi.DBus
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func Test_DBus_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.DBus == nil {
+			t.Error("nil DBus unit")
+		} else {
+			t.Log(app.DBus)
+		}
+	})
+}
+
+func Test_DBus_002(t *testing.T) {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		t.Skip("No session bus")
+	}
+	tool.Test(t, nil, new(App), func(app *App) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if name, err := app.DBus.Name(gopi.DBUS_BUS_SESSION); err != nil {
+			t.Error(err)
+		} else if reply, err := app.DBus.Call(ctx, gopi.DBUS_BUS_SESSION, "org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus.GetNameOwner", "org.freedesktop.DBus"); err != nil {
+			t.Error(err)
+		} else {
+			t.Log(name, reply)
+		}
+	})
+}
+
+func Test_DBus_003(t *testing.T) {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		t.Skip("No session bus")
+	}
+	tool.Test(t, nil, new(App), func(app *App) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		// Export a method and call it
+		if err := app.DBus.Export(gopi.DBUS_BUS_SESSION, "/test", "com.example.Test", map[string]gopi.DBusMethod{
+			"Echo": func(sender string, args []interface{}) ([]interface{}, error) {
+				return args, nil
+			},
+		}); err != nil {
+			t.Error(err)
+		} else if name, err := app.DBus.Name(gopi.DBUS_BUS_SESSION); err != nil {
+			t.Error(err)
+		} else if reply, err := app.DBus.Call(ctx, gopi.DBUS_BUS_SESSION, name, "/test", "com.example.Test.Echo", "hello", uint32(42)); err != nil {
+			t.Error(err)
+		} else if len(reply) != 2 || reply[0] != "hello" || reply[1] != uint32(42) {
+			t.Error("Unexpected reply", reply)
+		} else if _, err := app.DBus.Call(ctx, gopi.DBUS_BUS_SESSION, name, "/test", "com.example.Test.Missing"); err == nil {
+			t.Error("Expected error for unknown method")
+		}
+	})
+}
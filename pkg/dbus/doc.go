@@ -0,0 +1,10 @@
+// DBus package implements a client for the D-Bus message bus, without
+// any external dependencies. It can call methods, subscribe to signals
+// and export objects on the system and session bus.
+//
+// Signals are emitted as gopi.DBusSignal events when a gopi.Publisher
+// is available. The bus addresses are taken from the environment or
+// can be set with the -dbus.system and -dbus.session flags. Only unix
+// socket addresses and the EXTERNAL authentication mechanism are
+// supported.
+package dbus
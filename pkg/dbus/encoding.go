@@ -0,0 +1,538 @@
+package dbus
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type encoder struct {
+	order binary.ByteOrder
+	buf   []byte
+}
+
+type decoder struct {
+	order binary.ByteOrder
+	buf   []byte
+	pos   int
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	typeObjectPath = reflect.TypeOf(gopi.DBusObjectPath(""))
+	typeSignature  = reflect.TypeOf(gopi.DBusSignature(""))
+	typeVariant    = reflect.TypeOf(gopi.DBusVariant{})
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// SIGNATURES
+
+// Signature returns the D-Bus signature for a set of values
+func Signature(values ...interface{}) (gopi.DBusSignature, error) {
+	sig := ""
+	for _, value := range values {
+		if value == nil {
+			return "", gopi.ErrBadParameter.WithPrefix("Signature: nil value")
+		} else if s, err := signatureOf(reflect.TypeOf(value)); err != nil {
+			return "", err
+		} else {
+			sig += s
+		}
+	}
+	return gopi.DBusSignature(sig), nil
+}
+
+func signatureOf(t reflect.Type) (string, error) {
+	switch t {
+	case typeObjectPath:
+		return "o", nil
+	case typeSignature:
+		return "g", nil
+	case typeVariant:
+		return "v", nil
+	}
+	switch t.Kind() {
+	case reflect.Uint8:
+		return "y", nil
+	case reflect.Bool:
+		return "b", nil
+	case reflect.Int16:
+		return "n", nil
+	case reflect.Uint16:
+		return "q", nil
+	case reflect.Int32, reflect.Int:
+		return "i", nil
+	case reflect.Uint32, reflect.Uint:
+		return "u", nil
+	case reflect.Int64:
+		return "x", nil
+	case reflect.Uint64:
+		return "t", nil
+	case reflect.Float64:
+		return "d", nil
+	case reflect.String:
+		return "s", nil
+	case reflect.Interface:
+		return "v", nil
+	case reflect.Slice, reflect.Array:
+		if elem, err := signatureOf(t.Elem()); err != nil {
+			return "", err
+		} else {
+			return "a" + elem, nil
+		}
+	case reflect.Map:
+		if key, err := signatureOf(t.Key()); err != nil {
+			return "", err
+		} else if isBasic(key[0]) == false {
+			return "", gopi.ErrBadParameter.WithPrefix("Signature: ", t)
+		} else if elem, err := signatureOf(t.Elem()); err != nil {
+			return "", err
+		} else {
+			return "a{" + key + elem + "}", nil
+		}
+	case reflect.Struct:
+		sig := ""
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue
+			} else if elem, err := signatureOf(t.Field(i).Type); err != nil {
+				return "", err
+			} else {
+				sig += elem
+			}
+		}
+		if sig == "" {
+			return "", gopi.ErrBadParameter.WithPrefix("Signature: ", t)
+		}
+		return "(" + sig + ")", nil
+	default:
+		return "", gopi.ErrBadParameter.WithPrefix("Signature: ", t)
+	}
+}
+
+// splitSignature returns the first complete type in a signature
+// and the remainder
+func splitSignature(sig string) (string, string, error) {
+	if sig == "" {
+		return "", "", gopi.ErrBadParameter.WithPrefix("signature")
+	}
+	switch sig[0] {
+	case 'a':
+		if elem, rest, err := splitSignature(sig[1:]); err != nil {
+			return "", "", err
+		} else {
+			return "a" + elem, rest, nil
+		}
+	case '(', '{':
+		end := byte(')')
+		if sig[0] == '{' {
+			end = '}'
+		}
+		rest := sig[1:]
+		for len(rest) > 0 && rest[0] != end {
+			if _, r, err := splitSignature(rest); err != nil {
+				return "", "", err
+			} else {
+				rest = r
+			}
+		}
+		if len(rest) == 0 {
+			return "", "", gopi.ErrBadParameter.WithPrefix("signature: ", sig)
+		}
+		n := len(sig) - len(rest) + 1
+		return sig[:n], sig[n:], nil
+	default:
+		if isBasic(sig[0]) || sig[0] == 'v' {
+			return sig[:1], sig[1:], nil
+		} else {
+			return "", "", gopi.ErrBadParameter.WithPrefix("signature: ", sig)
+		}
+	}
+}
+
+func isBasic(c byte) bool {
+	switch c {
+	case 'y', 'b', 'n', 'q', 'i', 'u', 'x', 't', 'd', 's', 'o', 'g', 'h':
+		return true
+	default:
+		return false
+	}
+}
+
+func alignment(c byte) int {
+	switch c {
+	case 'y', 'g', 'v':
+		return 1
+	case 'n', 'q':
+		return 2
+	case 'b', 'i', 'u', 's', 'o', 'a', 'h':
+		return 4
+	default:
+		return 8
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ENCODER
+
+func newEncoder(order binary.ByteOrder) *encoder {
+	return &encoder{order: order}
+}
+
+func (this *encoder) Bytes() []byte {
+	return this.buf
+}
+
+func (this *encoder) align(n int) {
+	for len(this.buf)%n != 0 {
+		this.buf = append(this.buf, 0)
+	}
+}
+
+func (this *encoder) uint32(v uint32) {
+	this.align(4)
+	b := make([]byte, 4)
+	this.order.PutUint32(b, v)
+	this.buf = append(this.buf, b...)
+}
+
+func (this *encoder) string(v string) {
+	this.uint32(uint32(len(v)))
+	this.buf = append(this.buf, v...)
+	this.buf = append(this.buf, 0)
+}
+
+func (this *encoder) signature(v string) {
+	this.buf = append(this.buf, byte(len(v)))
+	this.buf = append(this.buf, v...)
+	this.buf = append(this.buf, 0)
+}
+
+// Encode values in sequence
+func (this *encoder) Encode(values ...interface{}) error {
+	for _, value := range values {
+		if value == nil {
+			return gopi.ErrBadParameter.WithPrefix("Encode: nil value")
+		} else if sig, err := signatureOf(reflect.TypeOf(value)); err != nil {
+			return err
+		} else if err := this.encode(sig, reflect.ValueOf(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *encoder) encode(sig string, v reflect.Value) error {
+	// Unwrap interface values
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	this.align(alignment(sig[0]))
+	switch sig[0] {
+	case 'y':
+		this.buf = append(this.buf, byte(v.Uint()))
+	case 'b':
+		if v.Bool() {
+			this.uint32(1)
+		} else {
+			this.uint32(0)
+		}
+	case 'n', 'q':
+		b := make([]byte, 2)
+		if sig[0] == 'n' {
+			this.order.PutUint16(b, uint16(v.Int()))
+		} else {
+			this.order.PutUint16(b, uint16(v.Uint()))
+		}
+		this.buf = append(this.buf, b...)
+	case 'i':
+		this.uint32(uint32(v.Int()))
+	case 'u', 'h':
+		this.uint32(uint32(v.Uint()))
+	case 'x', 't', 'd':
+		b := make([]byte, 8)
+		switch sig[0] {
+		case 'x':
+			this.order.PutUint64(b, uint64(v.Int()))
+		case 't':
+			this.order.PutUint64(b, v.Uint())
+		case 'd':
+			this.order.PutUint64(b, math.Float64bits(v.Float()))
+		}
+		this.buf = append(this.buf, b...)
+	case 's', 'o':
+		this.string(v.String())
+	case 'g':
+		this.signature(v.String())
+	case 'v':
+		if v.Type() == typeVariant {
+			v = v.Field(0)
+		}
+		if v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		if v.IsValid() == false {
+			return gopi.ErrBadParameter.WithPrefix("Encode: nil variant")
+		} else if elem, err := signatureOf(v.Type()); err != nil {
+			return err
+		} else {
+			this.signature(elem)
+			return this.encode(elem, v)
+		}
+	case 'a':
+		return this.encodeArray(sig[1:], v)
+	case '(':
+		fields := sig[1 : len(sig)-1]
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if elem, rest, err := splitSignature(fields); err != nil {
+				return err
+			} else if err := this.encode(elem, v.Field(i)); err != nil {
+				return err
+			} else {
+				fields = rest
+			}
+		}
+	default:
+		return gopi.ErrBadParameter.WithPrefix("Encode: ", sig)
+	}
+	return nil
+}
+
+func (this *encoder) encodeArray(elem string, v reflect.Value) error {
+	// Reserve space for the length, then align to the first element
+	this.uint32(0)
+	offset := len(this.buf) - 4
+	this.align(alignment(elem[0]))
+	start := len(this.buf)
+
+	if elem[0] == '{' {
+		key, rest, err := splitSignature(elem[1 : len(elem)-1])
+		if err != nil {
+			return err
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			this.align(8)
+			if err := this.encode(key, iter.Key()); err != nil {
+				return err
+			} else if err := this.encode(rest, iter.Value()); err != nil {
+				return err
+			}
+		}
+	} else {
+		for i := 0; i < v.Len(); i++ {
+			if err := this.encode(elem, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Write the length in bytes
+	this.order.PutUint32(this.buf[offset:], uint32(len(this.buf)-start))
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// DECODER
+
+func newDecoder(order binary.ByteOrder, buf []byte, pos int) *decoder {
+	return &decoder{order, buf, pos}
+}
+
+func (this *decoder) align(n int) error {
+	for this.pos%n != 0 {
+		this.pos++
+	}
+	if this.pos > len(this.buf) {
+		return gopi.ErrUnexpectedResponse.WithPrefix("Decode: short buffer")
+	}
+	return nil
+}
+
+func (this *decoder) read(n int) ([]byte, error) {
+	if this.pos+n > len(this.buf) {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("Decode: short buffer")
+	}
+	b := this.buf[this.pos : this.pos+n]
+	this.pos += n
+	return b, nil
+}
+
+func (this *decoder) uint32() (uint32, error) {
+	if err := this.align(4); err != nil {
+		return 0, err
+	} else if b, err := this.read(4); err != nil {
+		return 0, err
+	} else {
+		return this.order.Uint32(b), nil
+	}
+}
+
+func (this *decoder) string(n int) (string, error) {
+	if b, err := this.read(n + 1); err != nil {
+		return "", err
+	} else {
+		return string(b[:n]), nil
+	}
+}
+
+func (this *decoder) signature() (string, error) {
+	if b, err := this.read(1); err != nil {
+		return "", err
+	} else {
+		return this.string(int(b[0]))
+	}
+}
+
+// Decode values for a signature
+func (this *decoder) Decode(sig string) ([]interface{}, error) {
+	result := []interface{}{}
+	for sig != "" {
+		if elem, rest, err := splitSignature(sig); err != nil {
+			return nil, err
+		} else if value, err := this.decode(elem); err != nil {
+			return nil, err
+		} else {
+			result = append(result, value)
+			sig = rest
+		}
+	}
+	return result, nil
+}
+
+func (this *decoder) decode(sig string) (interface{}, error) {
+	if err := this.align(alignment(sig[0])); err != nil {
+		return nil, err
+	}
+	switch sig[0] {
+	case 'y':
+		if b, err := this.read(1); err != nil {
+			return nil, err
+		} else {
+			return b[0], nil
+		}
+	case 'b':
+		if v, err := this.uint32(); err != nil {
+			return nil, err
+		} else {
+			return v != 0, nil
+		}
+	case 'n', 'q':
+		if b, err := this.read(2); err != nil {
+			return nil, err
+		} else if sig[0] == 'n' {
+			return int16(this.order.Uint16(b)), nil
+		} else {
+			return this.order.Uint16(b), nil
+		}
+	case 'i':
+		if v, err := this.uint32(); err != nil {
+			return nil, err
+		} else {
+			return int32(v), nil
+		}
+	case 'u', 'h':
+		return this.uint32()
+	case 'x', 't', 'd':
+		if b, err := this.read(8); err != nil {
+			return nil, err
+		} else if sig[0] == 'x' {
+			return int64(this.order.Uint64(b)), nil
+		} else if sig[0] == 't' {
+			return this.order.Uint64(b), nil
+		} else {
+			return math.Float64frombits(this.order.Uint64(b)), nil
+		}
+	case 's', 'o':
+		if n, err := this.uint32(); err != nil {
+			return nil, err
+		} else if str, err := this.string(int(n)); err != nil {
+			return nil, err
+		} else if sig[0] == 'o' {
+			return gopi.DBusObjectPath(str), nil
+		} else {
+			return str, nil
+		}
+	case 'g':
+		if str, err := this.signature(); err != nil {
+			return nil, err
+		} else {
+			return gopi.DBusSignature(str), nil
+		}
+	case 'v':
+		if elem, err := this.signature(); err != nil {
+			return nil, err
+		} else if _, rest, err := splitSignature(elem); err != nil || rest != "" {
+			return nil, gopi.ErrUnexpectedResponse.WithPrefix("Decode: variant ", elem)
+		} else {
+			return this.decode(elem)
+		}
+	case 'a':
+		return this.decodeArray(sig[1:])
+	case '(':
+		return this.Decode(sig[1 : len(sig)-1])
+	default:
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("Decode: ", sig)
+	}
+}
+
+func (this *decoder) decodeArray(elem string) (interface{}, error) {
+	n, err := this.uint32()
+	if err != nil {
+		return nil, err
+	} else if err := this.align(alignment(elem[0])); err != nil {
+		return nil, err
+	}
+	end := this.pos + int(n)
+	if end > len(this.buf) {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("Decode: short buffer")
+	}
+
+	// Byte arrays
+	if elem == "y" {
+		b, _ := this.read(int(n))
+		return append([]byte{}, b...), nil
+	}
+
+	// Dictionaries
+	if elem[0] == '{' {
+		key, value, err := splitSignature(elem[1 : len(elem)-1])
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[interface{}]interface{})
+		for this.pos < end {
+			if err := this.align(8); err != nil {
+				return nil, err
+			} else if k, err := this.decode(key); err != nil {
+				return nil, err
+			} else if v, err := this.decode(value); err != nil {
+				return nil, err
+			} else {
+				result[k] = v
+			}
+		}
+		return result, nil
+	}
+
+	// Other arrays
+	result := []interface{}{}
+	for this.pos < end {
+		if v, err := this.decode(elem); err != nil {
+			return nil, err
+		} else {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,13 @@
+package dbus
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register dbus
+	graph.RegisterUnit(reflect.TypeOf(&dbus{}), reflect.TypeOf((*gopi.DBus)(nil)))
+}
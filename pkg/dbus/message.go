@@ -0,0 +1,290 @@
+package dbus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type MessageType uint8
+type MessageFlag uint8
+
+type message struct {
+	Type        MessageType
+	Flags       MessageFlag
+	Serial      uint32
+	ReplySerial uint32
+	Path        gopi.DBusObjectPath
+	Interface   string
+	Member      string
+	ErrorName   string
+	Destination string
+	Sender      string
+	Signature   gopi.DBusSignature
+	Body        []interface{}
+}
+
+// field is a header field, encoded as a struct of byte and variant
+type field struct {
+	Code  uint8
+	Value gopi.DBusVariant
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	MESSAGE_TYPE_INVALID MessageType = iota
+	MESSAGE_TYPE_METHOD_CALL
+	MESSAGE_TYPE_METHOD_RETURN
+	MESSAGE_TYPE_ERROR
+	MESSAGE_TYPE_SIGNAL
+)
+
+const (
+	MESSAGE_FLAG_NO_REPLY_EXPECTED MessageFlag = 0x01
+	MESSAGE_FLAG_NO_AUTO_START     MessageFlag = 0x02
+)
+
+const (
+	fieldPath        = 1
+	fieldInterface   = 2
+	fieldMember      = 3
+	fieldErrorName   = 4
+	fieldReplySerial = 5
+	fieldDestination = 6
+	fieldSender      = 7
+	fieldSignature   = 8
+)
+
+const (
+	protocolVersion = 1
+	maxMessageSize  = 128 * 1024 * 1024
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// ENCODE
+
+// Marshal returns the message in wire format
+func (this *message) Marshal() ([]byte, error) {
+	order := binary.LittleEndian
+
+	// Encode body first, since the header contains the body length
+	// and signature
+	body := newEncoder(order)
+	if sig, err := Signature(this.Body...); err != nil {
+		return nil, err
+	} else if err := body.Encode(this.Body...); err != nil {
+		return nil, err
+	} else {
+		this.Signature = sig
+	}
+
+	// Header fields
+	fields := []field{}
+	if this.Path != "" {
+		fields = append(fields, field{fieldPath, gopi.DBusVariant{Value: this.Path}})
+	}
+	if this.Interface != "" {
+		fields = append(fields, field{fieldInterface, gopi.DBusVariant{Value: this.Interface}})
+	}
+	if this.Member != "" {
+		fields = append(fields, field{fieldMember, gopi.DBusVariant{Value: this.Member}})
+	}
+	if this.ErrorName != "" {
+		fields = append(fields, field{fieldErrorName, gopi.DBusVariant{Value: this.ErrorName}})
+	}
+	if this.ReplySerial != 0 {
+		fields = append(fields, field{fieldReplySerial, gopi.DBusVariant{Value: this.ReplySerial}})
+	}
+	if this.Destination != "" {
+		fields = append(fields, field{fieldDestination, gopi.DBusVariant{Value: this.Destination}})
+	}
+	if this.Sender != "" {
+		fields = append(fields, field{fieldSender, gopi.DBusVariant{Value: this.Sender}})
+	}
+	if this.Signature != "" {
+		fields = append(fields, field{fieldSignature, gopi.DBusVariant{Value: this.Signature}})
+	}
+
+	// Encode header
+	header := newEncoder(order)
+	header.buf = append(header.buf, 'l', byte(this.Type), byte(this.Flags), protocolVersion)
+	header.uint32(uint32(len(body.Bytes())))
+	header.uint32(this.Serial)
+	if err := header.Encode(fields); err != nil {
+		return nil, err
+	}
+	header.align(8)
+
+	// Return header and body
+	return append(header.Bytes(), body.Bytes()...), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// DECODE
+
+// readMessage reads a message in wire format
+func readMessage(r io.Reader) (*message, error) {
+	// Read fixed part of header including length of header fields
+	fixed := make([]byte, 16)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return nil, err
+	}
+	var order binary.ByteOrder
+	switch fixed[0] {
+	case 'l':
+		order = binary.LittleEndian
+	case 'B':
+		order = binary.BigEndian
+	default:
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("readMessage: endianness")
+	}
+	if fixed[3] != protocolVersion {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("readMessage: version ", fixed[3])
+	}
+
+	// Read remainder of header and body
+	bodylen := order.Uint32(fixed[4:])
+	fieldslen := order.Uint32(fixed[12:])
+	headerlen := 16 + fieldslen
+	if headerlen%8 != 0 {
+		headerlen += 8 - headerlen%8
+	}
+	if uint64(headerlen)+uint64(bodylen) > maxMessageSize {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("readMessage: message too large")
+	}
+	buf := make([]byte, headerlen+bodylen)
+	copy(buf, fixed)
+	if _, err := io.ReadFull(r, buf[16:]); err != nil {
+		return nil, err
+	}
+
+	// Decode header fields
+	this := &message{
+		Type:   MessageType(fixed[1]),
+		Flags:  MessageFlag(fixed[2]),
+		Serial: order.Uint32(fixed[8:]),
+	}
+	dec := newDecoder(order, buf[:16+fieldslen], 12)
+	if fields, err := dec.Decode("a(yv)"); err != nil {
+		return nil, err
+	} else if err := this.setFields(fields[0].([]interface{})); err != nil {
+		return nil, err
+	}
+
+	// Decode body, which is aligned relative to the start of the body
+	if this.Signature != "" {
+		dec := newDecoder(order, buf[headerlen:], 0)
+		if body, err := dec.Decode(string(this.Signature)); err != nil {
+			return nil, err
+		} else {
+			this.Body = body
+		}
+	}
+
+	// Return success
+	return this, nil
+}
+
+func (this *message) setFields(fields []interface{}) error {
+	for _, f := range fields {
+		f, ok := f.([]interface{})
+		if ok == false || len(f) != 2 {
+			return gopi.ErrUnexpectedResponse.WithPrefix("readMessage: header field")
+		}
+		code, _ := f[0].(uint8)
+		switch code {
+		case fieldPath:
+			this.Path, _ = f[1].(gopi.DBusObjectPath)
+		case fieldInterface:
+			this.Interface, _ = f[1].(string)
+		case fieldMember:
+			this.Member, _ = f[1].(string)
+		case fieldErrorName:
+			this.ErrorName, _ = f[1].(string)
+		case fieldReplySerial:
+			this.ReplySerial, _ = f[1].(uint32)
+		case fieldDestination:
+			this.Destination, _ = f[1].(string)
+		case fieldSender:
+			this.Sender, _ = f[1].(string)
+		case fieldSignature:
+			this.Signature, _ = f[1].(gopi.DBusSignature)
+		}
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+// Err returns an error for an error message, using the first
+// argument as the error description
+func (this *message) Err() error {
+	if this.Type != MESSAGE_TYPE_ERROR {
+		return nil
+	}
+	if len(this.Body) > 0 {
+		if str, ok := this.Body[0].(string); ok {
+			return gopi.ErrUnexpectedResponse.WithPrefix(this.ErrorName, ": ", str)
+		}
+	}
+	return gopi.ErrUnexpectedResponse.WithPrefix(this.ErrorName)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *message) String() string {
+	str := "<dbus.message"
+	str += " type=" + fmt.Sprint(this.Type)
+	str += " serial=" + fmt.Sprint(this.Serial)
+	if this.ReplySerial != 0 {
+		str += " reply_serial=" + fmt.Sprint(this.ReplySerial)
+	}
+	if this.Path != "" {
+		str += fmt.Sprintf(" path=%q", this.Path)
+	}
+	if this.Interface != "" {
+		str += fmt.Sprintf(" interface=%q", this.Interface)
+	}
+	if this.Member != "" {
+		str += fmt.Sprintf(" member=%q", this.Member)
+	}
+	if this.ErrorName != "" {
+		str += fmt.Sprintf(" error=%q", this.ErrorName)
+	}
+	if this.Sender != "" {
+		str += fmt.Sprintf(" sender=%q", this.Sender)
+	}
+	if this.Destination != "" {
+		str += fmt.Sprintf(" destination=%q", this.Destination)
+	}
+	if this.Signature != "" {
+		str += fmt.Sprintf(" signature=%q body=%v", this.Signature, this.Body)
+	}
+	return str + ">"
+}
+
+func (t MessageType) String() string {
+	switch t {
+	case MESSAGE_TYPE_INVALID:
+		return "MESSAGE_TYPE_INVALID"
+	case MESSAGE_TYPE_METHOD_CALL:
+		return "MESSAGE_TYPE_METHOD_CALL"
+	case MESSAGE_TYPE_METHOD_RETURN:
+		return "MESSAGE_TYPE_METHOD_RETURN"
+	case MESSAGE_TYPE_ERROR:
+		return "MESSAGE_TYPE_ERROR"
+	case MESSAGE_TYPE_SIGNAL:
+		return "MESSAGE_TYPE_SIGNAL"
+	default:
+		return "[?? Invalid MessageType value]"
+	}
+}
@@ -0,0 +1,96 @@
+package dbus
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+func Test_Message_001(t *testing.T) {
+	tests := []struct {
+		values []interface{}
+		sig    gopi.DBusSignature
+	}{
+		{[]interface{}{uint8(1), true, int16(-2), uint16(3)}, "ybnq"},
+		{[]interface{}{int32(-4), uint32(5), int64(-6), uint64(7), float64(8.5)}, "iuxtd"},
+		{[]interface{}{"hello", gopi.DBusObjectPath("/a/b"), gopi.DBusSignature("a{sv}")}, "sog"},
+		{[]interface{}{[]string{"a", "b"}, []byte{1, 2, 3}}, "asay"},
+		{[]interface{}{map[string]interface{}{"key": uint32(1)}}, "a{sv}"},
+		{[]interface{}{struct {
+			A uint8
+			B string
+		}{1, "two"}}, "(ys)"},
+		{[]interface{}{gopi.DBusVariant{Value: int64(9)}}, "v"},
+	}
+	for _, test := range tests {
+		if sig, err := Signature(test.values...); err != nil {
+			t.Error(err)
+		} else if sig != test.sig {
+			t.Errorf("Unexpected signature %q, expected %q", sig, test.sig)
+		}
+	}
+}
+
+func Test_Message_002(t *testing.T) {
+	msg := &message{
+		Type:        MESSAGE_TYPE_METHOD_CALL,
+		Serial:      42,
+		Path:        "/org/freedesktop/DBus",
+		Interface:   "org.freedesktop.DBus",
+		Member:      "Test",
+		Destination: "org.freedesktop.DBus",
+		Body: []interface{}{
+			"hello", uint32(1), []string{"a", "b"},
+			map[string]interface{}{"key": "value"},
+			struct {
+				A int64
+				B bool
+			}{-1, true},
+		},
+	}
+	data, err := msg.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg2, err := readMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(msg2)
+	if msg2.Type != msg.Type || msg2.Serial != msg.Serial || msg2.Path != msg.Path || msg2.Member != msg.Member {
+		t.Error("Unexpected header", msg2)
+	}
+	if msg2.Signature != "suasa{sv}(xb)" {
+		t.Error("Unexpected signature", msg2.Signature)
+	}
+	expected := []interface{}{
+		"hello", uint32(1), []interface{}{"a", "b"},
+		map[interface{}]interface{}{"key": "value"},
+		[]interface{}{int64(-1), true},
+	}
+	if reflect.DeepEqual(msg2.Body, expected) == false {
+		t.Error("Unexpected body", msg2.Body)
+	}
+}
+
+func Test_Message_003(t *testing.T) {
+	tests := []struct {
+		address, network, addr string
+	}{
+		{"unix:path=/var/run/dbus/system_bus_socket", "unix", "/var/run/dbus/system_bus_socket"},
+		{"unix:abstract=/tmp/dbus-XXX,guid=123", "unix", "@/tmp/dbus-XXX"},
+		{"tcp:host=localhost;unix:path=/tmp/a%20b", "unix", "/tmp/a b"},
+	}
+	for _, test := range tests {
+		if network, addr, err := parseAddress(test.address); err != nil {
+			t.Error(err)
+		} else if network != test.network || addr != test.addr {
+			t.Error("Unexpected address", network, addr)
+		}
+	}
+	if _, _, err := parseAddress("tcp:host=localhost"); err == nil {
+		t.Error("Expected error for tcp address")
+	}
+}
@@ -0,0 +1,79 @@
+package dbus
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type signal struct {
+	bus gopi.DBusBus
+	msg *message
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewSignal(bus gopi.DBusBus, sender string, path gopi.DBusObjectPath, iface, member string, args ...interface{}) gopi.DBusSignal {
+	return &signal{bus, &message{
+		Type:      MESSAGE_TYPE_SIGNAL,
+		Sender:    sender,
+		Path:      path,
+		Interface: iface,
+		Member:    member,
+		Body:      args,
+	}}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *signal) Name() string {
+	return this.msg.Interface + "." + this.msg.Member
+}
+
+func (this *signal) Bus() gopi.DBusBus {
+	return this.bus
+}
+
+func (this *signal) Sender() string {
+	return this.msg.Sender
+}
+
+func (this *signal) Path() gopi.DBusObjectPath {
+	return this.msg.Path
+}
+
+func (this *signal) Interface() string {
+	return this.msg.Interface
+}
+
+func (this *signal) Member() string {
+	return this.msg.Member
+}
+
+func (this *signal) Args() []interface{} {
+	return this.msg.Body
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *signal) String() string {
+	str := "<event.dbus"
+	str += " bus=" + fmt.Sprint(this.bus)
+	str += fmt.Sprintf(" name=%q", this.Name())
+	if this.msg.Sender != "" {
+		str += fmt.Sprintf(" sender=%q", this.msg.Sender)
+	}
+	if this.msg.Path != "" {
+		str += fmt.Sprintf(" path=%q", this.msg.Path)
+	}
+	if len(this.msg.Body) > 0 {
+		str += fmt.Sprint(" args=", this.msg.Body)
+	}
+	return str + ">"
+}
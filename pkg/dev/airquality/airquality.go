@@ -0,0 +1,374 @@
+package airquality
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	term "github.com/pkg/term"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type airquality struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Metrics
+	gopi.I2C
+	sync.RWMutex
+
+	// Flags
+	names    *string
+	bus      *uint
+	device   *string
+	interval *time.Duration
+	file     *string
+
+	lock         sync.Mutex // Serializes access to sensors
+	sensors      []sensor
+	measurements map[string]string
+	calibrations calibrations
+	store        map[string]time.Time
+	started      time.Time
+	humidity     float32
+	reading      gopi.AirQualityReading
+	valid        bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Time to wait for commands when starting and stopping sensors
+	commandTimeout = 5 * time.Second
+
+	// Serial port for the PMS5003
+	pmsBaud     = 9600
+	readTimeout = 100 * time.Millisecond
+)
+
+var (
+	// Sensors in the order they are read, so that carbon dioxide measured
+	// by the SCD40 replaces the equivalent from the SGP30
+	sensorNames = []string{"sgp30", "scd40", "pms5003"}
+
+	// clock returns the time, and is replaced in tests
+	clock = time.Now
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *airquality) Define(cfg gopi.Config) error {
+	this.names = cfg.FlagString("airquality.sensors", "scd40", "Comma-separated sensors (sgp30, scd40 or pms5003)")
+	this.bus = cfg.FlagUint("airquality.bus", 1, "I2C bus")
+	this.device = cfg.FlagString("airquality.device", "/dev/serial0", "Serial port for the PMS5003")
+	this.interval = cfg.FlagDuration("airquality.interval", time.Second, "Interval between readings")
+	this.file = cfg.FlagPath("airquality.file", "", "File to store calibration baselines")
+	cfg.FlagString("airquality.measurement", "airquality", "Measurement name prefix")
+	return nil
+}
+
+func (this *airquality) New(cfg gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	names := make(map[string]bool)
+	for _, name := range strings.Split(*this.names, ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			names[name] = true
+		}
+	}
+	for name := range names {
+		if contains(sensorNames, name) == false {
+			return gopi.ErrBadParameter.WithPrefix("-airquality.sensors")
+		}
+	}
+	if len(names) == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-airquality.sensors")
+	} else if *this.interval <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-airquality.interval")
+	} else if (names["sgp30"] || names["scd40"]) && this.I2C == nil {
+		return gopi.ErrInternalAppError.WithPrefix("Missing gopi.I2C for -airquality.sensors")
+	}
+
+	// Read calibrations
+	if calibrations, err := readCalibrations(*this.file); err != nil {
+		return fmt.Errorf("%v: %w", *this.file, err)
+	} else {
+		this.calibrations = calibrations
+	}
+
+	// Open sensors
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	for _, name := range sensorNames {
+		if names[name] {
+			if sensor, err := this.open(ctx, name); err != nil {
+				this.close(ctx)
+				return fmt.Errorf("%v: %w", name, err)
+			} else {
+				this.sensors = append(this.sensors, sensor)
+			}
+		}
+	}
+	this.started = clock()
+
+	// Restore baselines
+	this.store = make(map[string]time.Time)
+	for _, sensor := range this.sensors {
+		if b, ok := sensor.(baseline); ok {
+			at, err := this.calibrations.Restore(ctx, sensor.Name(), b, this.started)
+			if err != nil {
+				this.Print("AirQuality: ", sensor.Name(), ": ", err)
+			}
+			this.store[sensor.Name()] = at
+		}
+	}
+
+	// Define measurements
+	this.measurements = make(map[string]string)
+	if measurement := cfg.GetString("airquality.measurement"); measurement != "" && this.Metrics != nil {
+		for _, sensor := range this.sensors {
+			if m, err := this.Metrics.NewMeasurement(measurement+"_"+sensor.Name(), sensor.Fields(), this.Metrics.HostTag()); err != nil {
+				this.close(ctx)
+				return err
+			} else {
+				this.measurements[sensor.Name()] = m.Name()
+			}
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *airquality) Dispose() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	result := this.close(ctx)
+
+	// Release resources
+	this.sensors = nil
+	this.measurements = nil
+	this.calibrations = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *airquality) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			this.read(ctx)
+			timer.Reset(*this.interval)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *airquality) Reading() (gopi.AirQualityReading, bool) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.reading, this.valid
+}
+
+func (this *airquality) Calibrate(ctx context.Context, ppm float32) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	found := false
+	for _, sensor := range this.sensors {
+		if r, ok := sensor.(recalibrator); ok {
+			found = true
+			if correction, err := r.Recalibrate(ctx, ppm); err != nil {
+				return fmt.Errorf("%v: %w", sensor.Name(), err)
+			} else {
+				this.Print("AirQuality: ", sensor.Name(), ": Corrected by ", correction, "ppm")
+			}
+		}
+	}
+	if found == false {
+		return gopi.ErrNotImplemented.WithPrefix("Calibrate")
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *airquality) String() string {
+	str := "<airquality"
+	names := []string{}
+	for _, sensor := range this.sensors {
+		names = append(names, sensor.Name())
+	}
+	str += fmt.Sprintf(" sensors=%q", strings.Join(names, ","))
+	if *this.file != "" {
+		str += fmt.Sprintf(" file=%q", *this.file)
+	}
+	if reading, valid := this.Reading(); valid {
+		str += fmt.Sprint(" ", reading)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// open starts a sensor at the default address
+func (this *airquality) open(ctx context.Context, name string) (sensor, error) {
+	bus := gopi.I2CBus(*this.bus)
+	switch name {
+	case "sgp30":
+		return newSGP30(ctx, this.I2C, bus, 0x58)
+	case "scd40":
+		return newSCD40(ctx, this.I2C, bus, 0x62)
+	case "pms5003":
+		if _, err := os.Stat(*this.device); os.IsNotExist(err) {
+			return nil, gopi.ErrBadParameter.WithPrefix("-airquality.device")
+		} else if port, err := term.Open(*this.device, term.Speed(pmsBaud), term.RawMode); err != nil {
+			return nil, err
+		} else if err := port.SetReadTimeout(readTimeout); err != nil {
+			port.Close()
+			return nil, err
+		} else if sensor, err := newPMS5003(port); err != nil {
+			port.Close()
+			return nil, err
+		} else {
+			return sensor, nil
+		}
+	default:
+		return nil, gopi.ErrBadParameter.WithPrefix(name)
+	}
+}
+
+// close stops the sensors
+func (this *airquality) close(ctx context.Context) error {
+	var result error
+	for _, sensor := range this.sensors {
+		if err := sensor.Close(ctx); err != nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// read reads the sensors which have warmed up, emits events and
+// measurements, compensates for humidity and stores baselines
+func (this *airquality) read(ctx context.Context) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	now := clock()
+	reading, _ := this.Reading()
+	for _, sensor := range this.sensors {
+		if now.Sub(this.started) < sensor.Warmup() {
+			continue
+		}
+		values, err := sensor.Read(ctx, &reading)
+		if err != nil {
+			this.Print("AirQuality: ", sensor.Name(), ": ", err)
+			continue
+		} else if values == nil {
+			continue
+		}
+
+		// Set reading
+		reading.Time = now
+		this.RWMutex.Lock()
+		this.reading, this.valid = reading, true
+		this.RWMutex.Unlock()
+
+		// Emit event and measurement
+		this.emit(sensor, reading, values)
+	}
+
+	// Compensate for a change in humidity
+	if reading.Humidity != 0 && reading.Humidity != this.humidity {
+		this.humidity = reading.Humidity
+		for _, sensor := range this.sensors {
+			if c, ok := sensor.(compensator); ok {
+				if err := c.Compensate(ctx, reading.Temperature, reading.Humidity); err != nil {
+					this.Print("AirQuality: ", sensor.Name(), ": ", err)
+				}
+			}
+		}
+	}
+
+	// Store baselines
+	if *this.file != "" {
+		this.storeBaselines(ctx, now)
+	}
+}
+
+// storeBaselines reads the baselines which are due, and writes them to
+// the file
+func (this *airquality) storeBaselines(ctx context.Context, now time.Time) {
+	changed := false
+	for _, sensor := range this.sensors {
+		b, ok := sensor.(baseline)
+		if ok == false || now.Before(this.store[sensor.Name()]) {
+			continue
+		}
+		at, err := this.calibrations.Store(ctx, sensor.Name(), b, now)
+		this.store[sensor.Name()] = at
+		if err != nil {
+			this.Print("AirQuality: ", sensor.Name(), ": ", err)
+		} else {
+			changed = true
+		}
+	}
+	if changed {
+		if err := this.calibrations.Write(*this.file); err != nil {
+			this.Print("AirQuality: ", err)
+		}
+	}
+}
+
+func (this *airquality) emit(sensor sensor, reading gopi.AirQualityReading, values []interface{}) {
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(NewEvent(sensor.Name(), reading), false); err != nil {
+			this.Debug("AirQuality: ", err)
+		}
+	}
+	if measurement, exists := this.measurements[sensor.Name()]; exists {
+		if err := this.Metrics.Emit(measurement, nil, values...); err != nil {
+			this.Debug("AirQuality: ", err)
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
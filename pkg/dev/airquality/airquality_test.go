@@ -0,0 +1,382 @@
+package airquality
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// chip emulates Sensirion sensors, which reply to commands with words
+type chip struct {
+	*testutil.I2C
+	cmd      uint16
+	replies  map[uint8]map[uint16][]uint16
+	checksum bool // Corrupt checksums when true
+}
+
+// port emulates a serial port, returning io.EOF when there is no data
+type port struct {
+	bytes.Buffer
+	written bytes.Buffer
+	closed  bool
+}
+
+type App struct {
+	gopi.Unit
+	gopi.AirQuality
+	gopi.Publisher
+}
+
+// i2c is a bus with an SCD40 which has a measurement ready
+type i2c struct {
+	gopi.Unit
+	*chip
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// Time added to the clock, to warm up sensors
+	offset int64
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CHIP
+
+func newChip(replies map[uint8]map[uint16][]uint16) *chip {
+	slaves := make([]uint8, 0, len(replies))
+	for slave := range replies {
+		slaves = append(slaves, slave)
+	}
+	this := &chip{I2C: testutil.NewI2C(slaves...), replies: replies}
+	this.SetTransfer(this.transfer)
+	return this
+}
+
+// transfer sets the command, and replies to it with words and checksums
+func (this *chip) transfer(slave uint8, data []byte, n int) ([]byte, error) {
+	if n == 0 {
+		this.cmd = binary.BigEndian.Uint16(data)
+		return nil, nil
+	}
+	result := []byte{}
+	for _, word := range this.replies[slave][this.cmd] {
+		data := []byte{byte(word >> 8), byte(word)}
+		if this.checksum {
+			result = append(result, data[0], data[1], 0)
+		} else {
+			result = append(result, data[0], data[1], crc8(data))
+		}
+	}
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SERIAL PORT
+
+func (this *port) Write(data []byte) (int, error) {
+	return this.written.Write(data)
+}
+
+func (this *port) Close() error {
+	this.closed = true
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// UNITS
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (this *i2c) New(gopi.Config) error {
+	this.chip = newChip(map[uint8]map[uint16][]uint16{
+		0x62: {
+			scd40SerialNumber: {0x1234, 0x5678, 0x9ABC},
+			scd40DataReady:    {0x8006},
+			scd40Measure:      {1000, 0x6667, 0x5EB9},
+		},
+	})
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func init() {
+	sleep = func(ctx context.Context, _ time.Duration) error {
+		return ctx.Err()
+	}
+	clock = func() time.Time {
+		return time.Now().Add(time.Duration(atomic.LoadInt64(&offset)))
+	}
+	graph.RegisterUnit(reflect.TypeOf(&i2c{}), reflect.TypeOf((*gopi.I2C)(nil)))
+}
+
+func Test_AirQuality_001(t *testing.T) {
+	// Checksum example from the datasheet
+	if crc := crc8([]byte{0xBE, 0xEF}); crc != 0x92 {
+		t.Errorf("Unexpected checksum 0x%02X", crc)
+	}
+	if ah := absoluteHumidity(25, 50); math.Abs(ah-11.48) > 0.01 {
+		t.Error("Unexpected absolute humidity", ah)
+	}
+}
+
+func Test_AirQuality_002(t *testing.T) {
+	bus := newChip(map[uint8]map[uint16][]uint16{
+		0x58: {
+			sgp30FeatureSet:  {0x0022},
+			sgp30Measure:     {450, 12},
+			sgp30GetBaseline: {0x8E68, 0x8F41},
+		},
+	})
+	ctx := context.Background()
+	sensor, err := newSGP30(ctx, bus, 1, 0x58)
+	if err != nil {
+		t.Fatal(err)
+	} else if cmd := bus.Last(); bytes.Equal(cmd, []byte{0x20, 0x03}) == false {
+		t.Errorf("Unexpected command %X", cmd)
+	}
+
+	var r gopi.AirQualityReading
+	if values, err := sensor.Read(ctx, &r); err != nil {
+		t.Error(err)
+	} else if len(values) != 2 || r.CO2 != 450 || r.TVOC != 12 {
+		t.Error("Unexpected reading", r)
+	}
+
+	// Baseline is written in reverse order with checksums
+	if baseline, err := sensor.Baseline(ctx); err != nil {
+		t.Error(err)
+	} else if err := sensor.SetBaseline(ctx, baseline); err != nil {
+		t.Error(err)
+	} else if cmd := bus.Last(); bytes.Equal(cmd, []byte{0x20, 0x1E, 0x8F, 0x41, crc8([]byte{0x8F, 0x41}), 0x8E, 0x68, crc8([]byte{0x8E, 0x68})}) == false {
+		t.Errorf("Unexpected command %X", cmd)
+	}
+
+	// Humidity in g/m³ as 8.8 fixed point
+	if err := sensor.Compensate(ctx, 25, 50); err != nil {
+		t.Error(err)
+	} else if cmd := bus.Last(); binary.BigEndian.Uint16(cmd[2:]) != 2939 {
+		t.Errorf("Unexpected command %X", cmd)
+	}
+
+	// Corrupt checksum is an error
+	bus.checksum = true
+	if _, err := sensor.Read(ctx, &r); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func Test_AirQuality_003(t *testing.T) {
+	bus := newChip(map[uint8]map[uint16][]uint16{
+		0x62: {
+			scd40SerialNumber: {0x1234, 0x5678, 0x9ABC},
+			scd40DataReady:    {0x8000},
+			scd40Measure:      {1000, 0x6667, 0x5EB9},
+			scd40Recalibrate:  {0x8000 + 15},
+		},
+	})
+	ctx := context.Background()
+	sensor, err := newSCD40(ctx, bus, 1, 0x62)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No reading until data is ready
+	var r gopi.AirQualityReading
+	if values, err := sensor.Read(ctx, &r); err != nil {
+		t.Error(err)
+	} else if values != nil {
+		t.Error("Unexpected values", values)
+	}
+	bus.replies[0x62][scd40DataReady] = []uint16{0x8006}
+	if values, err := sensor.Read(ctx, &r); err != nil {
+		t.Error(err)
+	} else if len(values) != 3 || r.CO2 != 1000 || math.Abs(float64(r.Temperature)-25) > 0.01 || math.Abs(float64(r.Humidity)-37) > 0.01 {
+		t.Error("Unexpected reading", r)
+	}
+
+	// Recalibration restarts measuring
+	if correction, err := sensor.Recalibrate(ctx, 420); err != nil {
+		t.Error(err)
+	} else if correction != 15 {
+		t.Error("Unexpected correction", correction)
+	} else if cmd := bus.Last(); bytes.Equal(cmd, []byte{0x21, 0xB1}) == false {
+		t.Errorf("Unexpected command %X", cmd)
+	}
+	bus.replies[0x62][scd40Recalibrate] = []uint16{0xFFFF}
+	if _, err := sensor.Recalibrate(ctx, 420); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func Test_AirQuality_004(t *testing.T) {
+	port := new(port)
+	sensor, err := newPMS5003(port)
+	if err != nil {
+		t.Fatal(err)
+	} else if cmd := port.written.Bytes(); bytes.Equal(cmd[:7], []byte{0x42, 0x4D, 0xE4, 0x00, 0x01, 0x01, 0x74}) == false {
+		t.Errorf("Unexpected command %X", cmd)
+	}
+
+	// No frames
+	ctx := context.Background()
+	var r gopi.AirQualityReading
+	if values, err := sensor.Read(ctx, &r); err != nil {
+		t.Error(err)
+	} else if values != nil {
+		t.Error("Unexpected values", values)
+	}
+
+	// Frames after noise and with a bad checksum, and a partial frame
+	bad := frame(9, 9, 9)
+	bad[31]++
+	port.Buffer.Write([]byte{0x00, 0x42, 0x11})
+	port.Buffer.Write(frame(5, 8, 10))
+	port.Buffer.Write(bad)
+	port.Buffer.Write(frame(6, 9, 12)[:20])
+	if values, err := sensor.Read(ctx, &r); err != nil {
+		t.Error(err)
+	} else if len(values) != 3 || r.PM1 != 5 || r.PM25 != 8 || r.PM10 != 10 {
+		t.Error("Unexpected reading", r)
+	}
+	port.Buffer.Write(frame(6, 9, 12)[20:])
+	if _, err := sensor.Read(ctx, &r); err != nil {
+		t.Error(err)
+	} else if r.PM1 != 6 || r.PM25 != 9 || r.PM10 != 12 {
+		t.Error("Unexpected reading", r)
+	}
+
+	// Sleeps on close
+	if err := sensor.Close(ctx); err != nil {
+		t.Error(err)
+	} else if port.closed == false {
+		t.Error("Expected port to be closed")
+	}
+}
+
+func Test_AirQuality_005(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airquality")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "calibration.json")
+
+	bus := newChip(map[uint8]map[uint16][]uint16{
+		0x58: {
+			sgp30FeatureSet:  {0x0022},
+			sgp30GetBaseline: {0x8E68, 0x8F41},
+		},
+	})
+	ctx := context.Background()
+	sensor, err := newSGP30(ctx, bus, 1, 0x58)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a baseline, the baseline is first stored after 12 hours
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	c, err := readCalibrations(path)
+	if err != nil {
+		t.Fatal(err)
+	} else if at, err := c.Restore(ctx, "sgp30", sensor, now); err != nil {
+		t.Error(err)
+	} else if at.Sub(now) != baselineFirst {
+		t.Error("Unexpected time", at)
+	}
+
+	// Store and read back
+	if _, err := c.Store(ctx, "sgp30", sensor, now); err != nil {
+		t.Error(err)
+	} else if err := c.Write(path); err != nil {
+		t.Error(err)
+	} else if c, err = readCalibrations(path); err != nil {
+		t.Error(err)
+	} else if baseline := c["sgp30"].Baseline; len(baseline) != 2 || baseline[0] != 0x8E68 || baseline[1] != 0x8F41 {
+		t.Error("Unexpected baseline", baseline)
+	}
+
+	// Restore a recent baseline, but not one older than a week
+	if at, err := c.Restore(ctx, "sgp30", sensor, now.Add(24*time.Hour)); err != nil {
+		t.Error(err)
+	} else if at.Sub(now) != 24*time.Hour+baselineInterval {
+		t.Error("Unexpected time", at)
+	} else if cmd := bus.Last(); binary.BigEndian.Uint16(cmd) != sgp30SetBaseline {
+		t.Errorf("Unexpected command %X", cmd)
+	}
+	writes := len(bus.Writes())
+	if _, err := c.Restore(ctx, "sgp30", sensor, now.Add(8*24*time.Hour)); err != nil {
+		t.Error(err)
+	} else if len(bus.Writes()) != writes {
+		t.Errorf("Unexpected command %X", bus.Last())
+	}
+}
+
+func Test_AirQuality_006(t *testing.T) {
+	// Readings are emitted as events once the sensor has warmed up
+	args := []string{"-airquality.sensors", "scd40", "-airquality.interval", "50ms"}
+	tool.Test(t, args, new(App), func(app *App) {
+		defer atomic.StoreInt64(&offset, 0)
+		scenario := tool.NewScenario().
+			At(200*time.Millisecond, "sensor warms up", func() error {
+				atomic.StoreInt64(&offset, int64(scd40Warmup))
+				return nil
+			}).
+			ExpectEvent(500*time.Millisecond, "1000ppm carbon dioxide", func(evt gopi.Event) bool {
+				if evt, ok := evt.(gopi.AirQualityEvent); ok == false {
+					return false
+				} else {
+					return evt.Sensor() == "scd40" && evt.Reading().CO2 == 1000
+				}
+			})
+		scenario.Test(t, app.Publisher)
+		for _, evt := range scenario.Events() {
+			if evt.At < 200*time.Millisecond {
+				t.Error("Unexpected event before warm up", evt)
+			}
+		}
+		if reading, valid := app.AirQuality.Reading(); valid == false || math.Abs(float64(reading.Temperature)-25) > 0.01 {
+			t.Error("Unexpected reading", reading)
+		}
+		t.Log(app.AirQuality)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// frame returns a PMS5003 frame with atmospheric particulate matter
+func frame(pm1, pm25, pm10 uint16) []byte {
+	data := make([]byte, pmsFrameLength)
+	data[0], data[1] = pmsStart1, pmsStart2
+	binary.BigEndian.PutUint16(data[2:], pmsLength)
+	binary.BigEndian.PutUint16(data[10:], pm1)
+	binary.BigEndian.PutUint16(data[12:], pm25)
+	binary.BigEndian.PutUint16(data[14:], pm10)
+	binary.BigEndian.PutUint16(data[pmsFrameLength-2:], checksum(data[:pmsFrameLength-2]))
+	return data
+}
@@ -0,0 +1,100 @@
+package airquality
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// calibrations are the baselines of sensors, keyed by sensor name, which
+// are stored in a file
+type calibrations map[string]calibration
+
+type calibration struct {
+	Baseline []uint16  `json:"baseline"`
+	Time     time.Time `json:"time"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// A baseline is restored when it is more recent than this
+	baselineAge = 7 * 24 * time.Hour
+
+	// A baseline is first stored after this time when there is no
+	// baseline to restore, and then every interval
+	baselineFirst    = 12 * time.Hour
+	baselineInterval = time.Hour
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// readCalibrations reads baselines from a file, which may not exist
+func readCalibrations(path string) (calibrations, error) {
+	result := make(calibrations)
+	if path == "" {
+		return result, nil
+	} else if _, err := os.Stat(path); os.IsNotExist(err) {
+		return result, nil
+	} else if fh, err := os.Open(path); err != nil {
+		return nil, err
+	} else {
+		defer fh.Close()
+		if err := json.NewDecoder(fh).Decode(&result); err != nil {
+			return nil, err
+		}
+	}
+
+	// Return success
+	return result, nil
+}
+
+// Write stores baselines in a file, replacing it atomically
+func (this calibrations) Write(path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if fh, err := os.Create(tmp); err != nil {
+		return err
+	} else if err := json.NewEncoder(fh).Encode(this); err != nil {
+		fh.Close()
+		return err
+	} else if err := fh.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Restore sets the baseline of a sensor when it is recent, and returns
+// the time at which to store the baseline
+func (this calibrations) Restore(ctx context.Context, name string, sensor baseline, now time.Time) (time.Time, error) {
+	if c, exists := this[name]; exists == false || now.Sub(c.Time) > baselineAge {
+		return now.Add(baselineFirst), nil
+	} else if err := sensor.SetBaseline(ctx, c.Baseline); err != nil {
+		return now.Add(baselineFirst), err
+	} else {
+		return now.Add(baselineInterval), nil
+	}
+}
+
+// Store reads the baseline of a sensor, and returns the time at which
+// to store it again
+func (this calibrations) Store(ctx context.Context, name string, sensor baseline, now time.Time) (time.Time, error) {
+	if values, err := sensor.Baseline(ctx); err != nil {
+		return now.Add(baselineInterval), err
+	} else {
+		this[name] = calibration{values, now}
+		return now.Add(baselineInterval), nil
+	}
+}
@@ -0,0 +1,36 @@
+// Air quality package implements gopi.AirQuality, which reads carbon
+// dioxide, volatile organic compounds and particulate matter from air
+// quality sensors. Sensors are set with the -airquality.sensors flag:
+//
+//	sgp30    SGP30 gas sensor (I2C address 0x58), for volatile organic
+//	         compounds in ppb and the equivalent carbon dioxide in ppm
+//	scd40    SCD40 or SCD41 sensor (I2C address 0x62), for carbon dioxide
+//	         in ppm, temperature and humidity
+//	pms5003  Plantower PMS5003 particulate matter sensor on the
+//	         -airquality.device serial port, for PM1, PM2.5 and PM10
+//	         in µg/m³
+//
+// Readings are ignored while each sensor warms up, which is 15s for the
+// SGP30, 5s for the SCD40 and 30s for the PMS5003 fan. The SGP30 is read
+// every second, which its baseline compensation requires. When there is
+// also an SCD40, carbon dioxide is measured by the SCD40, and the SGP30
+// compensates for the humidity it measures.
+//
+// The SGP30 baseline is stored every hour in the -airquality.file, and
+// is restored when it is less than a week old. Without a baseline, it is
+// first stored after twelve hours. The SCD40 recalibrates itself, or is
+// recalibrated in air with a known concentration of carbon dioxide with
+// Calibrate.
+//
+// A gopi.AirQualityEvent is emitted for each reading, and when there is
+// a gopi.Metrics unit a measurement is emitted for each sensor, named
+// with the -airquality.measurement prefix, such as "airquality_scd40".
+// The measurements are charted on the admin dashboard, and rules can
+// alert on them, for example:
+//
+//	alert "stuffy"
+//	  when airquality_scd40 co2 > 1500 for 10m
+//	  severity warning
+//	  do notify(severity, alert, state, value)
+//	end
+package airquality
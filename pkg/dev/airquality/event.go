@@ -0,0 +1,44 @@
+package airquality
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	sensor  string
+	reading gopi.AirQualityReading
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(sensor string, reading gopi.AirQualityReading) gopi.AirQualityEvent {
+	return &event{sensor, reading}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "airquality"
+}
+
+func (this *event) Sensor() string {
+	return this.sensor
+}
+
+func (this *event) Reading() gopi.AirQualityReading {
+	return this.reading
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	return fmt.Sprintf("<airquality.event sensor=%q %v>", this.sensor, this.reading)
+}
@@ -0,0 +1,13 @@
+package airquality
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.AirQuality
+	graph.RegisterUnit(reflect.TypeOf(&airquality{}), reflect.TypeOf((*gopi.AirQuality)(nil)))
+}
@@ -0,0 +1,161 @@
+package airquality
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://www.aqmd.gov/docs/default-source/aq-spec/resources-page/plantower-pms5003-manual_v2-3.pdf
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// pms5003 reads frames sent by the sensor every second over a serial port
+type pms5003 struct {
+	port io.ReadWriteCloser
+	buf  []byte
+}
+
+// pmsFrame is the particulate matter in µg/m³ for atmospheric conditions
+type pmsFrame struct {
+	pm1, pm25, pm10 uint16
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	pmsStart1      = 0x42
+	pmsStart2      = 0x4D
+	pmsLength      = 28 // Length of data and checksum
+	pmsFrameLength = 4 + pmsLength
+	pmsCmdMode     = 0xE1
+	pmsCmdSleep    = 0xE4
+	pmsActive      = 0x01
+	pmsWake        = 0x01
+	pmsSleep       = 0x00
+
+	// The fan runs for 30s after waking before readings are stable
+	pmsWarmup = 30 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newPMS5003(port io.ReadWriteCloser) (*pms5003, error) {
+	this := &pms5003{port: port}
+
+	// Wake the sensor, and send frames without being asked
+	if err := this.command(pmsCmdSleep, pmsWake); err != nil {
+		return nil, err
+	} else if err := this.command(pmsCmdMode, pmsActive); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return this, nil
+}
+
+// Close puts the sensor to sleep, which stops the fan
+func (this *pms5003) Close(context.Context) error {
+	var result error
+	if err := this.command(pmsCmdSleep, pmsSleep); err != nil {
+		result = err
+	}
+	if err := this.port.Close(); err != nil {
+		result = err
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *pms5003) Name() string {
+	return "pms5003"
+}
+
+func (this *pms5003) Fields() string {
+	return "pm1 float32, pm25 float32, pm10 float32"
+}
+
+func (this *pms5003) Warmup() time.Duration {
+	return pmsWarmup
+}
+
+// Read decodes the frames received since the last reading, and returns
+// the particulate matter from the last frame
+func (this *pms5003) Read(ctx context.Context, r *gopi.AirQualityReading) ([]interface{}, error) {
+	data := make([]byte, 256)
+	for ctx.Err() == nil {
+		n, err := this.port.Read(data)
+		this.buf = append(this.buf, data[:n]...)
+		if err == io.EOF || n == 0 {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	frame, ok := this.decode()
+	if ok == false {
+		return nil, ctx.Err()
+	}
+	r.PM1, r.PM25, r.PM10 = float32(frame.pm1), float32(frame.pm25), float32(frame.pm10)
+	return []interface{}{r.PM1, r.PM25, r.PM10}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// command sends a command with a checksum
+func (this *pms5003) command(cmd, value byte) error {
+	data := []byte{pmsStart1, pmsStart2, cmd, 0x00, value, 0x00, 0x00}
+	binary.BigEndian.PutUint16(data[5:], checksum(data[:5]))
+	_, err := this.port.Write(data)
+	return err
+}
+
+// decode returns the last valid frame in the buffer, skipping data
+// until the start of a frame, and keeps any partial frame
+func (this *pms5003) decode() (pmsFrame, bool) {
+	var frame pmsFrame
+	var ok bool
+	for len(this.buf) >= pmsFrameLength {
+		if this.buf[0] != pmsStart1 || this.buf[1] != pmsStart2 || binary.BigEndian.Uint16(this.buf[2:]) != pmsLength {
+			this.buf = this.buf[1:]
+			continue
+		}
+		data := this.buf[:pmsFrameLength]
+		if checksum(data[:pmsFrameLength-2]) != binary.BigEndian.Uint16(data[pmsFrameLength-2:]) {
+			this.buf = this.buf[1:]
+			continue
+		}
+
+		// Atmospheric values follow the standard particle values
+		frame = pmsFrame{
+			pm1:  binary.BigEndian.Uint16(data[10:]),
+			pm25: binary.BigEndian.Uint16(data[12:]),
+			pm10: binary.BigEndian.Uint16(data[14:]),
+		}
+		ok = true
+		this.buf = this.buf[pmsFrameLength:]
+	}
+
+	// Keep a partial frame, but not the underlying array
+	this.buf = append([]byte(nil), this.buf...)
+	return frame, ok
+}
+
+// checksum returns the sum of bytes
+func checksum(data []byte) uint16 {
+	sum := uint16(0)
+	for _, b := range data {
+		sum += uint16(b)
+	}
+	return sum
+}
@@ -0,0 +1,119 @@
+package airquality
+
+import (
+	"context"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://sensirion.com/media/documents/E0F04247/631EF271/CD_DS_SCD40_SCD41_Datasheet_D1.pdf
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type scd40 struct {
+	sensirion
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	scd40Start         = 0x21B1
+	scd40Stop          = 0x3F86
+	scd40DataReady     = 0xE4B8
+	scd40Measure       = 0xEC05
+	scd40Recalibrate   = 0x362F
+	scd40SerialNumber  = 0x3682
+	scd40StopTime      = 500 * time.Millisecond
+	scd40RecalibrateAt = 400 * time.Millisecond
+
+	// The first measurement is five seconds after starting
+	scd40Warmup = 5 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newSCD40(ctx context.Context, i2c gopi.I2C, bus gopi.I2CBus, slave uint8) (*scd40, error) {
+	this := &scd40{sensirion{i2c, bus, slave}}
+	if err := this.detect(); err != nil {
+		return nil, err
+	}
+
+	// Stop measuring, which may have been started before, so that the
+	// serial number can be read
+	if err := this.command(ctx, scd40Stop, scd40StopTime); err != nil {
+		return nil, err
+	} else if _, err := this.read(ctx, scd40SerialNumber, time.Millisecond, 3); err != nil {
+		return nil, err
+	} else if err := this.command(ctx, scd40Start, 0); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return this, nil
+}
+
+func (this *scd40) Close(ctx context.Context) error {
+	return this.command(ctx, scd40Stop, scd40StopTime)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *scd40) Name() string {
+	return "scd40"
+}
+
+func (this *scd40) Fields() string {
+	return "co2 float32, temperature float32, humidity float32"
+}
+
+func (this *scd40) Warmup() time.Duration {
+	return scd40Warmup
+}
+
+// Read returns carbon dioxide, temperature and humidity when there is a
+// new measurement, which is every five seconds
+func (this *scd40) Read(ctx context.Context, r *gopi.AirQualityReading) ([]interface{}, error) {
+	if status, err := this.read(ctx, scd40DataReady, time.Millisecond, 1); err != nil {
+		return nil, err
+	} else if status[0]&0x07FF == 0 {
+		return nil, nil
+	}
+	words, err := this.read(ctx, scd40Measure, time.Millisecond, 3)
+	if err != nil {
+		return nil, err
+	}
+	r.CO2 = float32(words[0])
+	r.Temperature = -45 + 175*float32(words[1])/0xFFFF
+	r.Humidity = 100 * float32(words[2]) / 0xFFFF
+	return []interface{}{r.CO2, r.Temperature, r.Humidity}, nil
+}
+
+// Recalibrate sets the carbon dioxide concentration in ppm, after the
+// sensor has been measuring in air with that concentration for a few
+// minutes, and returns the correction
+func (this *scd40) Recalibrate(ctx context.Context, ppm float32) (float32, error) {
+	if ppm < 0 || ppm > 0xFFFF {
+		return 0, gopi.ErrBadParameter.WithPrefix("SCD40 concentration")
+	}
+	if err := this.command(ctx, scd40Stop, scd40StopTime); err != nil {
+		return 0, err
+	}
+	words, err := this.read(ctx, scd40Recalibrate, scd40RecalibrateAt, 1, uint16(ppm))
+
+	// Restart measuring, even when recalibration failed
+	if err_ := this.command(ctx, scd40Start, 0); err_ != nil {
+		return 0, err_
+	} else if err != nil {
+		return 0, err
+	} else if words[0] == 0xFFFF {
+		return 0, gopi.ErrUnexpectedResponse.WithPrefix("SCD40 recalibration failed")
+	}
+
+	// Return the correction
+	return float32(int(words[0]) - 0x8000), nil
+}
@@ -0,0 +1,145 @@
+package airquality
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// sensor measures air quality
+type sensor interface {
+	// Name returns the name of the sensor
+	Name() string
+
+	// Fields returns the fields of the measurement
+	Fields() string
+
+	// Warmup returns the time after starting before readings are valid
+	Warmup() time.Duration
+
+	// Read sets the values measured in a reading, and returns the values
+	// of the fields, or nil when there is no new reading
+	Read(context.Context, *gopi.AirQualityReading) ([]interface{}, error)
+
+	// Close stops measuring
+	Close(context.Context) error
+}
+
+// baseline is implemented by sensors with a calibration baseline, which
+// is restored when the sensor starts
+type baseline interface {
+	Baseline(context.Context) ([]uint16, error)
+	SetBaseline(context.Context, []uint16) error
+}
+
+// compensator is implemented by sensors which compensate for the
+// temperature and humidity measured by another sensor
+type compensator interface {
+	Compensate(ctx context.Context, temperature, humidity float32) error
+}
+
+// recalibrator is implemented by sensors which support forced
+// recalibration, and returns the correction in ppm
+type recalibrator interface {
+	Recalibrate(context.Context, float32) (float32, error)
+}
+
+// sensirion is a Sensirion sensor on an I2C bus, which has 16-bit
+// commands and words followed by a checksum
+type sensirion struct {
+	gopi.I2C
+	bus   gopi.I2CBus
+	slave uint8
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// sleep waits for a command, and is replaced in tests
+	sleep = func(ctx context.Context, d time.Duration) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *sensirion) detect() error {
+	if detected, err := this.I2C.DetectSlave(this.bus, this.slave); err != nil {
+		return err
+	} else if detected == false {
+		return gopi.ErrNotFound.WithPrefix("I2C slave ", this.slave)
+	} else {
+		return nil
+	}
+}
+
+// command sends a command with arguments, and waits for it to complete
+func (this *sensirion) command(ctx context.Context, cmd uint16, delay time.Duration, args ...uint16) error {
+	data := make([]byte, 2, 2+len(args)*3)
+	binary.BigEndian.PutUint16(data, cmd)
+	for _, arg := range args {
+		data = append(data, byte(arg>>8), byte(arg))
+		data = append(data, crc8(data[len(data)-2:]))
+	}
+	if err := this.I2C.SetSlave(this.bus, this.slave); err != nil {
+		return err
+	} else if _, err := this.I2C.TransferContext(ctx, this.bus, data, 0); err != nil {
+		return err
+	} else {
+		return sleep(ctx, delay)
+	}
+}
+
+// read sends a command with arguments, waits for it to complete and
+// reads a number of words, checking the checksum of each word
+func (this *sensirion) read(ctx context.Context, cmd uint16, delay time.Duration, n int, args ...uint16) ([]uint16, error) {
+	if err := this.command(ctx, cmd, delay, args...); err != nil {
+		return nil, err
+	}
+	data, err := this.I2C.TransferContext(ctx, this.bus, nil, n*3)
+	if err != nil {
+		return nil, err
+	} else if len(data) != n*3 {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("I2C slave ", this.slave)
+	}
+	result := make([]uint16, n)
+	for i := range result {
+		word := data[i*3 : i*3+3]
+		if crc8(word[:2]) != word[2] {
+			return nil, gopi.ErrUnexpectedResponse.WithPrefix("Checksum from I2C slave ", this.slave)
+		}
+		result[i] = binary.BigEndian.Uint16(word)
+	}
+	return result, nil
+}
+
+// crc8 returns the checksum of a word, with the polynomial 0x31 and
+// initial value 0xFF
+func crc8(data []byte) byte {
+	crc := byte(0xFF)
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x31
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
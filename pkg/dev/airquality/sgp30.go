@@ -0,0 +1,123 @@
+package airquality
+
+import (
+	"context"
+	"math"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://sensirion.com/media/documents/984E0DD5/61644B8B/Sensirion_Gas_Sensors_Datasheet_SGP30.pdf
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type sgp30 struct {
+	sensirion
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	sgp30Init        = 0x2003
+	sgp30Measure     = 0x2008
+	sgp30GetBaseline = 0x2015
+	sgp30SetBaseline = 0x201E
+	sgp30SetHumidity = 0x2061
+	sgp30FeatureSet  = 0x202F
+
+	// Readings are fixed at 400ppm and 0ppb for 15s after initialization
+	sgp30Warmup = 15 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newSGP30(ctx context.Context, i2c gopi.I2C, bus gopi.I2CBus, slave uint8) (*sgp30, error) {
+	this := &sgp30{sensirion{i2c, bus, slave}}
+	if err := this.detect(); err != nil {
+		return nil, err
+	}
+
+	// Check the product type is zero for the SGP30
+	if features, err := this.read(ctx, sgp30FeatureSet, 10*time.Millisecond, 1); err != nil {
+		return nil, err
+	} else if product := features[0] >> 12; product != 0 {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("SGP30 product type ", product)
+	}
+
+	// Start measuring, with the dynamic baseline compensation algorithm
+	if err := this.command(ctx, sgp30Init, 10*time.Millisecond); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return this, nil
+}
+
+func (this *sgp30) Close(context.Context) error {
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *sgp30) Name() string {
+	return "sgp30"
+}
+
+func (this *sgp30) Fields() string {
+	return "co2 float32, tvoc float32"
+}
+
+func (this *sgp30) Warmup() time.Duration {
+	return sgp30Warmup
+}
+
+// Read measures the equivalent carbon dioxide and volatile organic
+// compounds, and should be called every second for the baseline
+// compensation
+func (this *sgp30) Read(ctx context.Context, r *gopi.AirQualityReading) ([]interface{}, error) {
+	words, err := this.read(ctx, sgp30Measure, 12*time.Millisecond, 2)
+	if err != nil {
+		return nil, err
+	}
+	r.CO2, r.TVOC = float32(words[0]), float32(words[1])
+	return []interface{}{r.CO2, r.TVOC}, nil
+}
+
+// Baseline returns the baseline for carbon dioxide and volatile
+// organic compounds
+func (this *sgp30) Baseline(ctx context.Context) ([]uint16, error) {
+	return this.read(ctx, sgp30GetBaseline, 10*time.Millisecond, 2)
+}
+
+// SetBaseline restores the baseline, which is written in reverse order
+func (this *sgp30) SetBaseline(ctx context.Context, baseline []uint16) error {
+	if len(baseline) != 2 {
+		return gopi.ErrBadParameter.WithPrefix("SGP30 baseline")
+	}
+	return this.command(ctx, sgp30SetBaseline, 10*time.Millisecond, baseline[1], baseline[0])
+}
+
+// Compensate sets the absolute humidity from the temperature and
+// relative humidity
+func (this *sgp30) Compensate(ctx context.Context, temperature, humidity float32) error {
+	if humidity <= 0 {
+		return nil
+	}
+	value := math.Min(absoluteHumidity(float64(temperature), float64(humidity))*256, 0xFFFF)
+	return this.command(ctx, sgp30SetHumidity, 10*time.Millisecond, uint16(value))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// absoluteHumidity returns g/m³ from the temperature in Celsius and the
+// relative humidity in percent
+func absoluteHumidity(temperature, humidity float64) float64 {
+	pressure := 6.112 * math.Exp(17.62*temperature/(243.12+temperature)) * humidity / 100
+	return 216.7 * pressure / (273.15 + temperature)
+}
@@ -1,6 +1,7 @@
 package chromecast
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"strconv"
@@ -202,7 +203,7 @@ func (this *Cast) Equals(other *Cast) gopi.CastFlag {
 	return flags
 }
 
-func (this *Cast) ConnectWithTimeout(ch gopi.Publisher, timeout time.Duration) (*Conn, error) {
+func (this *Cast) ConnectWithContext(ctx context.Context, ch gopi.Publisher, timeout time.Duration) (*Conn, error) {
 	// Use hostname to connect
 	addr := fmt.Sprintf("%v:%v", this.host, this.port)
 
@@ -211,7 +212,7 @@ func (this *Cast) ConnectWithTimeout(ch gopi.Publisher, timeout time.Duration) (
 	this.app = nil
 
 	// Perform the connection
-	return NewConnWithTimeout(this.id, addr, timeout, ch)
+	return NewConnWithContext(ctx, this.id, addr, timeout, ch)
 }
 
 func (this *Cast) Disconnect(conn *Conn) error {
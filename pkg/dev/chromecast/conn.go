@@ -24,25 +24,36 @@ type Conn struct {
 	Channel
 	*tls.Conn
 
-	cancel context.CancelFunc
+	timeout time.Duration
+	cancel  context.CancelFunc
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
-func NewConnWithTimeout(key string, addr string, timeout time.Duration, ch gopi.Publisher) (*Conn, error) {
+// NewConnWithContext connects to a chromecast and returns the connection.
+// Connecting fails when the timeout expires or the context is done
+func NewConnWithContext(ctx context.Context, key string, addr string, timeout time.Duration, ch gopi.Publisher) (*Conn, error) {
 	this := new(Conn)
+	this.timeout = timeout
 
 	// Connect
-	if conn, err := tls.DialWithDialer(&net.Dialer{
+	dialer := &net.Dialer{
 		Timeout:   timeout,
 		KeepAlive: timeout,
-	}, "tcp", addr, &tls.Config{
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	tlsconn := tls.Client(conn, &tls.Config{
 		InsecureSkipVerify: true,
-	}); err != nil {
+	})
+	if err := handshake(ctx, tlsconn, timeout); err != nil {
+		tlsconn.Close()
 		return nil, err
 	} else {
-		this.Conn = conn
+		this.Conn = tlsconn
 		this.Channel.Init(ch, key)
 	}
 
@@ -127,6 +138,8 @@ func (this *Conn) send(data []byte) error {
 		return nil
 	} else if this.Addr() == nil {
 		return gopi.ErrOutOfOrder
+	} else if err := this.Conn.SetWriteDeadline(time.Now().Add(this.timeout)); err != nil {
+		return err
 	} else if err := binary.Write(this.Conn, binary.BigEndian, uint32(len(data))); err != nil {
 		return err
 	} else if _, err := this.Conn.Write(data); err != nil {
@@ -161,6 +174,40 @@ func (this *Conn) recv(ctx context.Context, timeout time.Duration) {
 	}
 }
 
+// handshake performs the TLS handshake, which fails when the timeout
+// expires or the context is done
+func handshake(ctx context.Context, conn *tls.Conn, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	// Interrupt the handshake when the context is done
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Now())
+		case <-stop:
+		}
+	}()
+	err := conn.Handshake()
+	close(stop)
+	<-stopped
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	} else if err != nil {
+		return err
+	} else {
+		return conn.SetDeadline(time.Time{})
+	}
+}
+
 func (this *Conn) recvdata(length uint32) error {
 	payload := make([]byte, length)
 
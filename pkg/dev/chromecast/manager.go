@@ -183,7 +183,7 @@ func (this *Manager) Connect(ctx context.Context, cast gopi.Cast) error {
 		return gopi.ErrNotFound.WithPrefix("Connect")
 	} else if conn := this.getConnForId(cast.id); conn != nil {
 		return gopi.ErrOutOfOrder.WithPrefix("Connect")
-	} else if conn, err := cast.ConnectWithTimeout(this.Publisher, serviceConnectTimeout); err != nil {
+	} else if conn, err := cast.ConnectWithContext(ctx, this.Publisher, serviceConnectTimeout); err != nil {
 		return err
 	} else {
 		this.setConnForId(cast.id, conn)
@@ -392,7 +392,9 @@ func (this *Manager) LoadMedia(ctx context.Context, cast gopi.Cast, url *url.URL
 			InsecureSkipVerify: true,
 		}
 	}
-	if response, err := client.Head(url.String()); err != nil {
+	if req, err := http.NewRequestWithContext(ctx, http.MethodHead, url.String(), nil); err != nil {
+		return err
+	} else if response, err := client.Do(req); err != nil {
 		return err
 	} else if response.StatusCode != http.StatusOK {
 		return gopi.ErrUnexpectedResponse.WithPrefix(response.Status)
@@ -2,6 +2,8 @@ package chromecast_test
 
 import (
 	"context"
+	"errors"
+	"net"
 	"testing"
 	"time"
 
@@ -40,3 +42,34 @@ func Test_Cast_001(t *testing.T) {
 		}
 	})
 }
+
+func Test_Cast_002(t *testing.T) {
+	// Accept connections but never complete the handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			if conn, err := listener.Accept(); err != nil {
+				return
+			} else {
+				defer conn.Close()
+			}
+		}
+	}()
+
+	// Connecting should end when the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if conn, err := cast.NewConnWithContext(ctx, "test", listener.Addr().String(), 10*time.Second, nil); err == nil {
+		conn.Close()
+		t.Error("Expected error")
+	} else if errors.Is(err, context.DeadlineExceeded) == false {
+		t.Error("Unexpected error", err)
+	} else if time.Since(start) > time.Second {
+		t.Error("Connect did not return on timeout")
+	}
+}
@@ -0,0 +1,13 @@
+// Dial package makes the device a DIAL (DIscovery And Launch) receiver,
+// so that phones and other senders can cast media to it. The device is
+// advertised using SSDP and, when a gopi.ServiceDiscovery unit is
+// available, mDNS. Launched applications are played using the
+// gopi.MediaPlayer unit.
+//
+// The applications are set with the -dial.apps flag. The YouTube
+// application accepts a video id as the "v" parameter of the launch
+// request. Other applications accept a media URL either as the "url"
+// parameter or as the body of the launch request.
+//
+// Ref: http://www.dial-multiscreen.org/dial-protocol-specification
+package dial
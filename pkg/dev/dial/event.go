@@ -0,0 +1,55 @@
+package dial
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	app   string
+	state gopi.CastReceiverState
+	url   string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(app string, state gopi.CastReceiverState, url string) gopi.CastReceiverEvent {
+	return &event{app, state, url}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.app
+}
+
+func (this *event) App() string {
+	return this.app
+}
+
+func (this *event) State() gopi.CastReceiverState {
+	return this.state
+}
+
+func (this *event) URL() string {
+	return this.url
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<event.dial"
+	str += fmt.Sprintf(" app=%q", this.app)
+	str += " state=" + fmt.Sprint(this.state)
+	if this.url != "" {
+		str += fmt.Sprintf(" url=%q", this.url)
+	}
+	return str + ">"
+}
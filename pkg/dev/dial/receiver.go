@@ -0,0 +1,368 @@
+package dial
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type receiver struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.MediaPlayer
+	gopi.ServiceDiscovery
+	sync.Mutex
+	sync.WaitGroup
+
+	name     *string
+	port     *uint
+	apps     []string
+	uuid     string
+	listener net.Listener
+	app, url string // Running application and media URL
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	descriptionPath = "/dd.xml"
+	appsPath        = "/apps/"
+	runPath         = "run"
+	youTubeApp      = "YouTube"
+	maxBodySize     = 4096
+	mdnsService     = "_dial._tcp"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *receiver) Define(cfg gopi.Config) error {
+	this.name = cfg.FlagString("dial.name", "", "Friendly name, defaults to hostname")
+	this.port = cfg.FlagUint("dial.port", 56790, "Port for DIAL requests")
+	cfg.FlagString("dial.apps", "YouTube,Media", "Comma-separated application names")
+	return nil
+}
+
+func (this *receiver) New(cfg gopi.Config) error {
+	this.Require(this.Logger, this.MediaPlayer)
+
+	// Set name and unique identifier from hostname
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	if *this.name = strings.TrimSpace(*this.name); *this.name == "" {
+		*this.name = hostname
+	}
+	this.uuid = newUUID(hostname + "/" + *this.name)
+
+	// Set applications
+	for _, app := range strings.Split(cfg.GetString("dial.apps"), ",") {
+		if app = strings.TrimSpace(app); app != "" {
+			this.apps = append(this.apps, app)
+		}
+	}
+	if len(this.apps) == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-dial.apps")
+	}
+
+	// Listen for requests
+	if listener, err := net.Listen("tcp4", fmt.Sprint(":", *this.port)); err != nil {
+		return err
+	} else {
+		this.listener = listener
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *receiver) Run(ctx context.Context) error {
+	// Serve DIAL requests
+	server := &http.Server{Handler: this}
+	this.WaitGroup.Add(1)
+	go func() {
+		defer this.WaitGroup.Done()
+		if err := server.Serve(this.listener); err != nil && err != http.ErrServerClosed {
+			this.Print("DIAL: ", err)
+		}
+	}()
+
+	// Respond to SSDP discovery
+	if ssdp, err := newSSDP(this.uuid, this.location); err != nil {
+		this.Print("DIAL: SSDP: ", err)
+	} else {
+		this.WaitGroup.Add(1)
+		go func() {
+			defer this.WaitGroup.Done()
+			ssdp.Run(ctx, func(err error) {
+				this.Debug("DIAL: SSDP: ", err)
+			})
+		}()
+	}
+
+	// Register with mDNS when service discovery is available
+	if this.ServiceDiscovery != nil {
+		if record, err := this.ServiceDiscovery.NewServiceRecord(mdnsService, *this.name, uint16(this.Port()), []string{"path=" + descriptionPath}, gopi.SERVICE_FLAG_IP4); err != nil {
+			this.Print("DIAL: mDNS: ", err)
+		} else {
+			this.WaitGroup.Add(1)
+			go func() {
+				defer this.WaitGroup.Done()
+				if err := this.ServiceDiscovery.Serve(ctx, []gopi.ServiceRecord{record}); err != nil {
+					this.Print("DIAL: mDNS: ", err)
+				}
+			}()
+		}
+	}
+
+	// Wait for end of run, then stop serving and playing
+	<-ctx.Done()
+	server.Close()
+	this.WaitGroup.Wait()
+	if app, _ := this.running(); app != "" {
+		if err := this.Stop(app); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *receiver) Dispose() error {
+	// Close listener, which may not have been served
+	this.listener.Close()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *receiver) Name() string {
+	return *this.name
+}
+
+func (this *receiver) Apps() []string {
+	return this.apps
+}
+
+func (this *receiver) Port() uint {
+	return uint(this.listener.Addr().(*net.TCPAddr).Port)
+}
+
+func (this *receiver) State(app string) (gopi.CastReceiverState, string) {
+	if running, url := this.running(); running == app && this.MediaPlayer.URL() != "" {
+		return gopi.CAST_RECEIVER_STATE_RUNNING, url
+	} else {
+		return gopi.CAST_RECEIVER_STATE_STOPPED, ""
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *receiver) Stop(app string) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if app == "" || app != this.app {
+		return gopi.ErrNotFound.WithPrefix("Stop: ", app)
+	} else if err := this.MediaPlayer.Stop(); err != nil {
+		return err
+	} else {
+		this.app, this.url = "", ""
+		this.emit(NewEvent(app, gopi.CAST_RECEIVER_STATE_STOPPED, ""))
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// HTTP HANDLER
+
+func (this *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.URL.Path == descriptionPath:
+		this.serveDescription(w, req)
+	case strings.HasPrefix(req.URL.Path, appsPath):
+		path := strings.Split(strings.TrimPrefix(req.URL.Path, appsPath), "/")
+		if this.hasApp(path[0]) == false {
+			http.NotFound(w, req)
+		} else if len(path) == 1 {
+			this.serveApp(w, req, path[0])
+		} else if len(path) == 2 && path[1] == runPath {
+			this.serveRun(w, req, path[0])
+		} else {
+			http.NotFound(w, req)
+		}
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (this *receiver) serveDescription(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Application-URL", "http://"+req.Host+appsPath)
+	writeXML(w, http.StatusOK, newDeviceDescription(*this.name, this.uuid))
+}
+
+func (this *receiver) serveApp(w http.ResponseWriter, req *http.Request, app string) {
+	switch req.Method {
+	case http.MethodGet:
+		state, _ := this.State(app)
+		writeXML(w, http.StatusOK, newAppStatus(app, state == gopi.CAST_RECEIVER_STATE_RUNNING))
+	case http.MethodPost:
+		body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxBodySize+1))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		} else if len(body) > maxBodySize {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		} else if media, err := mediaURL(app, string(body)); err != nil {
+			this.Debugf("DIAL: %v: %v", app, err)
+			w.WriteHeader(http.StatusBadRequest)
+		} else if err := this.launch(app, media); err != nil {
+			this.Printf("DIAL: %v: %v", app, err)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.Header().Set("Location", "http://"+req.Host+appsPath+app+"/"+runPath)
+			w.WriteHeader(http.StatusCreated)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (this *receiver) serveRun(w http.ResponseWriter, req *http.Request, app string) {
+	if req.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	} else if err := this.Stop(app); err != nil {
+		http.NotFound(w, req)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *receiver) String() string {
+	str := "<dial"
+	str += fmt.Sprintf(" name=%q", *this.name)
+	str += " uuid=" + this.uuid
+	str += " port=" + fmt.Sprint(this.Port())
+	str += fmt.Sprintf(" apps=%q", this.apps)
+	if app, url := this.running(); app != "" {
+		str += fmt.Sprintf(" running=%q url=%q", app, url)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// launch plays media for an application, replacing any running
+// application
+func (this *receiver) launch(app, media string) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if err := this.MediaPlayer.Play(media); err != nil {
+		return err
+	}
+	if this.app != "" && this.app != app {
+		this.emit(NewEvent(this.app, gopi.CAST_RECEIVER_STATE_STOPPED, ""))
+	}
+	this.app, this.url = app, media
+	this.emit(NewEvent(app, gopi.CAST_RECEIVER_STATE_RUNNING, media))
+
+	// Return success
+	return nil
+}
+
+func (this *receiver) running() (string, string) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.app, this.url
+}
+
+func (this *receiver) hasApp(app string) bool {
+	for _, name := range this.apps {
+		if name == app {
+			return true
+		}
+	}
+	return false
+}
+
+// location returns the URL of the device description for an address
+func (this *receiver) location(ip net.IP) string {
+	return "http://" + net.JoinHostPort(ip.String(), fmt.Sprint(this.Port())) + descriptionPath
+}
+
+func (this *receiver) emit(evt gopi.CastReceiverEvent) {
+	if this.Publisher == nil {
+		return
+	} else if err := this.Publisher.Emit(evt, false); err != nil {
+		this.Debug("DIAL: ", err)
+	}
+}
+
+// mediaURL returns the media URL from the body of a launch request
+func mediaURL(app, body string) (string, error) {
+	body = strings.TrimSpace(body)
+	values, _ := url.ParseQuery(body)
+	if app == youTubeApp {
+		if v := values.Get("v"); v == "" {
+			return "", gopi.ErrBadParameter.WithPrefix("Missing video id")
+		} else {
+			return "https://www.youtube.com/watch?v=" + url.QueryEscape(v), nil
+		}
+	}
+	if u := values.Get("url"); u != "" {
+		body = u
+	}
+	if u, err := url.Parse(body); err != nil {
+		return "", err
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return "", gopi.ErrBadParameter.WithPrefix("Invalid URL: ", body)
+	} else {
+		return u.String(), nil
+	}
+}
+
+// newUUID returns a name-based identifier which does not change
+// between runs
+func newUUID(name string) string {
+	h := md5.Sum([]byte(name))
+	h[6] = (h[6] & 0x0F) | 0x30
+	h[8] = (h[8] & 0x3F) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
+
+func writeXML(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(code)
+	io.WriteString(w, xml.Header)
+	xml.NewEncoder(w).Encode(v)
+}
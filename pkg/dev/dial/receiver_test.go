@@ -0,0 +1,208 @@
+package dial_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/dev/dial"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.CastReceiver
+}
+
+// player is a media player which records the URL played
+type player struct {
+	gopi.Unit
+	sync.Mutex
+	url string
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&player{}), reflect.TypeOf((*gopi.MediaPlayer)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PLAYER
+
+func (this *player) Play(url string) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.url = url
+	return nil
+}
+
+func (this *player) Stop() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.url = ""
+	return nil
+}
+
+func (this *player) URL() string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.url
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Receiver_001(t *testing.T) {
+	port := freePort(t)
+	tool.Test(t, []string{"-dial.port=" + fmt.Sprint(port), "-dial.name=test"}, new(App), func(app *App) {
+		if app.CastReceiver == nil {
+			t.Error("nil CastReceiver unit")
+		} else if app.CastReceiver.Name() != "test" {
+			t.Error("Unexpected name", app.CastReceiver.Name())
+		} else if apps := app.CastReceiver.Apps(); len(apps) != 2 {
+			t.Error("Unexpected apps", apps)
+		} else {
+			t.Log(app.CastReceiver)
+		}
+	})
+}
+
+func Test_Receiver_002(t *testing.T) {
+	port := freePort(t)
+	base := fmt.Sprint("http://127.0.0.1:", port)
+	tool.Test(t, []string{"-dial.port=" + fmt.Sprint(port), "-dial.name=test"}, new(App), func(app *App) {
+		// Device description
+		if resp, err := http.Get(base + "/dd.xml"); err != nil {
+			t.Error(err)
+			return
+		} else if resp.StatusCode != http.StatusOK {
+			t.Error("Unexpected status", resp.Status)
+		} else if url := resp.Header.Get("Application-URL"); url != base+"/apps/" {
+			t.Error("Unexpected Application-URL", url)
+		} else if body, _ := ioutil.ReadAll(resp.Body); strings.Contains(string(body), "<friendlyName>test</friendlyName>") == false {
+			t.Error("Unexpected description", string(body))
+		}
+
+		// Application status before launch
+		if status := get(t, base+"/apps/YouTube"); strings.Contains(status, "<state>stopped</state>") == false {
+			t.Error("Unexpected status", status)
+		}
+
+		// Unknown application
+		if resp, err := http.Get(base + "/apps/Netflix"); err != nil {
+			t.Error(err)
+		} else if resp.StatusCode != http.StatusNotFound {
+			t.Error("Unexpected status", resp.Status)
+		}
+
+		// Launch with a bad body
+		if resp, err := http.Post(base+"/apps/YouTube", "text/plain", strings.NewReader("t=0")); err != nil {
+			t.Error(err)
+		} else if resp.StatusCode != http.StatusBadRequest {
+			t.Error("Unexpected status", resp.Status)
+		}
+
+		// Launch
+		if resp, err := http.Post(base+"/apps/YouTube", "text/plain", strings.NewReader("v=abc123")); err != nil {
+			t.Error(err)
+		} else if resp.StatusCode != http.StatusCreated {
+			t.Error("Unexpected status", resp.Status)
+		} else if location := resp.Header.Get("Location"); location != base+"/apps/YouTube/run" {
+			t.Error("Unexpected location", location)
+		}
+		if state, url := app.CastReceiver.State("YouTube"); state != gopi.CAST_RECEIVER_STATE_RUNNING {
+			t.Error("Unexpected state", state)
+		} else if url != "https://www.youtube.com/watch?v=abc123" {
+			t.Error("Unexpected url", url)
+		}
+		if status := get(t, base+"/apps/YouTube"); strings.Contains(status, "<state>running</state>") == false {
+			t.Error("Unexpected status", status)
+		}
+
+		// Stop
+		req, _ := http.NewRequest(http.MethodDelete, base+"/apps/YouTube/run", nil)
+		if resp, err := http.DefaultClient.Do(req); err != nil {
+			t.Error(err)
+		} else if resp.StatusCode != http.StatusOK {
+			t.Error("Unexpected status", resp.Status)
+		}
+		if state, _ := app.CastReceiver.State("YouTube"); state != gopi.CAST_RECEIVER_STATE_STOPPED {
+			t.Error("Unexpected state", state)
+		}
+		if resp, err := http.DefaultClient.Do(req); err != nil {
+			t.Error(err)
+		} else if resp.StatusCode != http.StatusNotFound {
+			t.Error("Unexpected status", resp.Status)
+		}
+	})
+}
+
+func Test_Receiver_003(t *testing.T) {
+	port := freePort(t)
+	base := fmt.Sprint("http://127.0.0.1:", port)
+	tool.Test(t, []string{"-dial.port=" + fmt.Sprint(port)}, new(App), func(app *App) {
+		// Launch media by URL, then replace with another
+		for _, body := range []string{"http://localhost/a.mp4", "url=http%3A%2F%2Flocalhost%2Fb.mp4"} {
+			if resp, err := http.Post(base+"/apps/Media", "text/plain", strings.NewReader(body)); err != nil {
+				t.Error(err)
+			} else if resp.StatusCode != http.StatusCreated {
+				t.Error("Unexpected status", resp.Status)
+			}
+		}
+		if _, url := app.CastReceiver.State("Media"); url != "http://localhost/b.mp4" {
+			t.Error("Unexpected url", url)
+		}
+
+		// Launch with an invalid URL
+		if resp, err := http.Post(base+"/apps/Media", "text/plain", strings.NewReader("file:///etc/passwd")); err != nil {
+			t.Error(err)
+		} else if resp.StatusCode != http.StatusBadRequest {
+			t.Error("Unexpected status", resp.Status)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	if listener, err := net.Listen("tcp4", "127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+		return 0
+	} else {
+		defer listener.Close()
+		return listener.Addr().(*net.TCPAddr).Port
+	}
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+	if resp, err := http.Get(url); err != nil {
+		t.Error(err)
+		return ""
+	} else {
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return string(body)
+	}
+}
@@ -0,0 +1,164 @@
+package dial
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ssdp responds to discovery requests for the DIAL service and
+// announces the service when starting and ending
+type ssdp struct {
+	conn     *net.UDPConn
+	usn      string
+	location func(net.IP) string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	ssdpMaxAge   = 1800
+	ssdpInterval = 10 * time.Minute
+	ssdpAll      = "ssdp:all"
+)
+
+var (
+	ssdpAddr = &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newSSDP(uuid string, location func(net.IP) string) (*ssdp, error) {
+	this := new(ssdp)
+	this.usn = "uuid:" + uuid + "::" + dialServiceType
+	this.location = location
+
+	if conn, err := net.ListenMulticastUDP("udp4", nil, ssdpAddr); err != nil {
+		return nil, err
+	} else {
+		this.conn = conn
+	}
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Run responds to requests and announces the service until the
+// context is cancelled, calling a function on error
+func (this *ssdp) Run(ctx context.Context, fn func(error)) {
+	// Close connection when done to end the receive loop
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		this.notify(ctx, fn)
+		this.conn.Close()
+	}()
+
+	buf := make([]byte, 2048)
+	for {
+		n, addr, err := this.conn.ReadFromUDP(buf)
+		if err != nil {
+			break
+		}
+		if st := searchTarget(buf[:n]); st == "" {
+			continue
+		} else if err := this.respond(addr); err != nil {
+			fn(err)
+		}
+	}
+
+	// Wait for notifications to end
+	<-done
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// notify announces the service periodically, and sends byebye when
+// the context is cancelled
+func (this *ssdp) notify(ctx context.Context, fn func(error)) {
+	ticker := time.NewTicker(ssdpInterval)
+	defer ticker.Stop()
+
+	nts := "ssdp:alive"
+	for {
+		if err := this.send(nts); err != nil {
+			fn(err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if err := this.send("ssdp:byebye"); err != nil {
+				fn(err)
+			}
+			return
+		}
+	}
+}
+
+func (this *ssdp) send(nts string) error {
+	msg := "NOTIFY * HTTP/1.1\r\n"
+	msg += "HOST: " + ssdpAddr.String() + "\r\n"
+	msg += fmt.Sprint("CACHE-CONTROL: max-age=", ssdpMaxAge, "\r\n")
+	if nts == "ssdp:alive" {
+		msg += "LOCATION: " + this.location(localIP(ssdpAddr)) + "\r\n"
+	}
+	msg += "NT: " + dialServiceType + "\r\n"
+	msg += "NTS: " + nts + "\r\n"
+	msg += "USN: " + this.usn + "\r\n"
+	msg += "\r\n"
+	_, err := this.conn.WriteToUDP([]byte(msg), ssdpAddr)
+	return err
+}
+
+func (this *ssdp) respond(addr *net.UDPAddr) error {
+	msg := "HTTP/1.1 200 OK\r\n"
+	msg += fmt.Sprint("CACHE-CONTROL: max-age=", ssdpMaxAge, "\r\n")
+	msg += "DATE: " + time.Now().UTC().Format(http.TimeFormat) + "\r\n"
+	msg += "EXT:\r\n"
+	msg += "LOCATION: " + this.location(localIP(addr)) + "\r\n"
+	msg += "SERVER: Linux/1.0 UPnP/1.1 gopi/3\r\n"
+	msg += "ST: " + dialServiceType + "\r\n"
+	msg += "USN: " + this.usn + "\r\n"
+	msg += "\r\n"
+	_, err := this.conn.WriteToUDP([]byte(msg), addr)
+	return err
+}
+
+// searchTarget returns the search target for an M-SEARCH request for
+// the DIAL service, or empty string
+func searchTarget(data []byte) string {
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil || req.Method != "M-SEARCH" {
+		return ""
+	} else if strings.Trim(req.Header.Get("MAN"), `"`) != "ssdp:discover" {
+		return ""
+	} else if st := req.Header.Get("ST"); st == dialServiceType || st == ssdpAll {
+		return st
+	} else {
+		return ""
+	}
+}
+
+// localIP returns the local address used to reach a remote address
+func localIP(addr *net.UDPAddr) net.IP {
+	if conn, err := net.DialUDP("udp4", nil, addr); err != nil {
+		return net.IPv4zero
+	} else {
+		defer conn.Close()
+		return conn.LocalAddr().(*net.UDPAddr).IP
+	}
+}
@@ -0,0 +1,95 @@
+package dial
+
+import (
+	"encoding/xml"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type deviceDescription struct {
+	XMLName     xml.Name `xml:"urn:schemas-upnp-org:device-1-0 root"`
+	SpecVersion struct {
+		Major int `xml:"major"`
+		Minor int `xml:"minor"`
+	} `xml:"specVersion"`
+	Device struct {
+		DeviceType   string `xml:"deviceType"`
+		FriendlyName string `xml:"friendlyName"`
+		Manufacturer string `xml:"manufacturer"`
+		ModelName    string `xml:"modelName"`
+		UDN          string `xml:"UDN"`
+		Services     []struct {
+			ServiceType string `xml:"serviceType"`
+			ServiceId   string `xml:"serviceId"`
+			ControlURL  string `xml:"controlURL"`
+			EventSubURL string `xml:"eventSubURL"`
+			SCPDURL     string `xml:"SCPDURL"`
+		} `xml:"serviceList>service"`
+	} `xml:"device"`
+}
+
+type appStatus struct {
+	XMLName xml.Name `xml:"urn:dial-multiscreen-org:schemas:dial service"`
+	Version string   `xml:"dialVer,attr"`
+	Name    string   `xml:"name"`
+	Options struct {
+		AllowStop bool `xml:"allowStop,attr"`
+	} `xml:"options"`
+	State string   `xml:"state"`
+	Link  *appLink `xml:"link,omitempty"`
+}
+
+type appLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	dialDeviceType  = "urn:dial-multiscreen-org:device:dial:1"
+	dialServiceType = "urn:dial-multiscreen-org:service:dial:1"
+	dialServiceId   = "urn:dial-multiscreen-org:serviceId:dial"
+	dialVersion     = "2.1"
+	notFoundPath    = "/ssdp/notfound"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// newDeviceDescription returns the UPnP device description
+func newDeviceDescription(name, uuid string) *deviceDescription {
+	this := new(deviceDescription)
+	this.SpecVersion.Major = 1
+	this.Device.DeviceType = dialDeviceType
+	this.Device.FriendlyName = name
+	this.Device.Manufacturer = "gopi"
+	this.Device.ModelName = "gopi"
+	this.Device.UDN = "uuid:" + uuid
+	this.Device.Services = append(this.Device.Services, struct {
+		ServiceType string `xml:"serviceType"`
+		ServiceId   string `xml:"serviceId"`
+		ControlURL  string `xml:"controlURL"`
+		EventSubURL string `xml:"eventSubURL"`
+		SCPDURL     string `xml:"SCPDURL"`
+	}{dialServiceType, dialServiceId, notFoundPath, notFoundPath, notFoundPath})
+	return this
+}
+
+// newAppStatus returns the status of an application, with a link to
+// the running instance
+func newAppStatus(name string, running bool) *appStatus {
+	this := new(appStatus)
+	this.Version = dialVersion
+	this.Name = name
+	this.Options.AllowStop = true
+	if running {
+		this.State = "running"
+		this.Link = &appLink{"run", runPath}
+	} else {
+		this.State = "stopped"
+	}
+	return this
+}
@@ -0,0 +1,190 @@
+package escpos
+
+import (
+	"bytes"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://reference.epson-biz.com/modules/ref_escpos/index.php
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	esc = 0x1B
+	gs  = 0x1D
+	dle = 0x10
+	eot = 0x04
+	lf  = 0x0A
+)
+
+const (
+	// Rows of a bitmap sent in each raster command, so that the
+	// printer buffer is not exceeded
+	rasterBand = 128
+
+	// Height of barcodes in dots
+	barcodeHeight = 80
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+
+// cmdInit resets the printer
+func cmdInit() []byte {
+	return []byte{esc, '@'}
+}
+
+// cmdText returns text with styles set before and reset after. Characters
+// which are not ASCII are replaced with a question mark
+func cmdText(text string, style gopi.PrinterStyle) []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(cmdStyle(style))
+	for _, r := range strings.ReplaceAll(text, "\r\n", "\n") {
+		if r == '\n' {
+			buf.WriteByte(lf)
+		} else if r < 0x20 || r > 0x7E {
+			buf.WriteByte('?')
+		} else {
+			buf.WriteByte(byte(r))
+		}
+	}
+	if style != gopi.PRINTER_STYLE_NONE {
+		buf.Write(cmdStyle(gopi.PRINTER_STYLE_NONE))
+	}
+	return buf.Bytes()
+}
+
+// cmdStyle sets bold, underline, size and inverted text
+func cmdStyle(style gopi.PrinterStyle) []byte {
+	var size byte
+	if style&gopi.PRINTER_STYLE_DOUBLE_WIDTH != 0 {
+		size |= 0x10
+	}
+	if style&gopi.PRINTER_STYLE_DOUBLE_HEIGHT != 0 {
+		size |= 0x01
+	}
+	return []byte{
+		esc, 'E', flag(style&gopi.PRINTER_STYLE_BOLD != 0),
+		esc, '-', flag(style&gopi.PRINTER_STYLE_UNDERLINE != 0),
+		gs, 'B', flag(style&gopi.PRINTER_STYLE_INVERT != 0),
+		gs, '!', size,
+	}
+}
+
+// cmdAlign sets alignment of following lines
+func cmdAlign(align gopi.PrinterAlign) []byte {
+	return []byte{esc, 'a', byte(align)}
+}
+
+// cmdFeed feeds paper a number of lines
+func cmdFeed(lines uint) []byte {
+	buf := []byte{}
+	for ; lines > 0xFF; lines -= 0xFF {
+		buf = append(buf, esc, 'd', 0xFF)
+	}
+	return append(buf, esc, 'd', byte(lines))
+}
+
+// cmdCut cuts paper, leaving one point uncut when partial
+func cmdCut(partial bool) []byte {
+	return []byte{gs, 'V', flag(partial)}
+}
+
+// cmdQRCode stores and prints a model 2 QR code
+func cmdQRCode(data string, level gopi.QRLevel, size uint) []byte {
+	n := len(data) + 3
+	buf := []byte{
+		gs, '(', 'k', 4, 0, '1', 'A', '2', 0, // Model 2
+		gs, '(', 'k', 3, 0, '1', 'C', byte(size), // Module size
+		gs, '(', 'k', 3, 0, '1', 'E', '0' + byte(level), // Error correction
+		gs, '(', 'k', byte(n), byte(n >> 8), '1', 'P', '0', // Store data
+	}
+	buf = append(buf, data...)
+	return append(buf, gs, '(', 'k', 3, 0, '1', 'Q', '0') // Print
+}
+
+// cmdBarcode prints a barcode with text below it
+func cmdBarcode(data string, t gopi.BarcodeType) ([]byte, error) {
+	var m byte
+	switch t {
+	case gopi.BARCODE_CODE128:
+		for _, r := range data {
+			if r < 0x20 || r > 0x7E {
+				return nil, gopi.ErrBadParameter.WithPrefix("PrintBarcode: ", data)
+			}
+		}
+		// Code set B
+		m, data = 73, "{B"+strings.ReplaceAll(data, "{", "{{")
+	case gopi.BARCODE_EAN13:
+		if isDigits(data, 12, 13) == false {
+			return nil, gopi.ErrBadParameter.WithPrefix("PrintBarcode: ", data)
+		}
+		m = 67
+	case gopi.BARCODE_EAN8:
+		if isDigits(data, 7, 8) == false {
+			return nil, gopi.ErrBadParameter.WithPrefix("PrintBarcode: ", data)
+		}
+		m = 68
+	default:
+		return nil, gopi.ErrBadParameter.WithPrefix("PrintBarcode: ", t)
+	}
+	if len(data) > 0xFF {
+		return nil, gopi.ErrBadParameter.WithPrefix("PrintBarcode: ", data)
+	}
+	buf := []byte{
+		gs, 'H', 2, // Text below
+		gs, 'h', barcodeHeight,
+		gs, 'w', 2, // Module width
+		gs, 'k', m, byte(len(data)),
+	}
+	return append(buf, data...), nil
+}
+
+// cmdRaster prints rows of a one bit per pixel image in bands, where
+// width is in bytes
+func cmdRaster(rows []byte, width int) []byte {
+	buf := []byte{}
+	for y := 0; y*width < len(rows); y += rasterBand {
+		band := rows[y*width:]
+		if len(band) > rasterBand*width {
+			band = band[:rasterBand*width]
+		}
+		h := len(band) / width
+		buf = append(buf, gs, 'v', '0', 0, byte(width), byte(width>>8), byte(h), byte(h>>8))
+		buf = append(buf, band...)
+	}
+	return buf
+}
+
+// cmdStatus requests printer (1), offline cause (2) or paper (4) status
+func cmdStatus(n byte) []byte {
+	return []byte{dle, eot, n}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func flag(value bool) byte {
+	if value {
+		return 1
+	} else {
+		return 0
+	}
+}
+
+func isDigits(data string, lengths ...int) bool {
+	for _, r := range data {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	for _, length := range lengths {
+		if len(data) == length {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package escpos
+
+import (
+	"image"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// dither returns an image as rows of one bit per pixel, where a set bit
+// prints a dot, and the width of rows in bytes. The image is scaled to
+// a maximum width in dots, composited on white and dithered with
+// Floyd-Steinberg error diffusion
+func dither(src image.Image, dots int) ([]byte, int) {
+	lum, w, h := luminance(src, dots)
+	if w == 0 || h == 0 {
+		return nil, 0
+	}
+	stride := (w + 7) / 8
+	rows := make([]byte, stride*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			v := lum[i]
+			out := float32(1)
+			if v < 0.5 {
+				out = 0
+				rows[y*stride+x/8] |= 0x80 >> uint(x%8)
+			}
+			e := v - out
+			if x+1 < w {
+				lum[i+1] += e * 7 / 16
+			}
+			if y+1 < h {
+				if x > 0 {
+					lum[i+w-1] += e * 3 / 16
+				}
+				lum[i+w] += e * 5 / 16
+				if x+1 < w {
+					lum[i+w+1] += e * 1 / 16
+				}
+			}
+		}
+	}
+	return rows, stride
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// luminance returns the brightness of pixels between zero and one on
+// a white background, averaging pixels when reducing to a width
+func luminance(src image.Image, dots int) ([]float32, int, int) {
+	r := src.Bounds()
+	w, h := r.Dx(), r.Dy()
+	dw, dh := w, h
+	if dots > 0 && w > dots {
+		dw, dh = dots, h*dots/w
+	}
+	lum := make([]float32, dw*dh)
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, (y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, (x+1)*w/dw
+			var sum float32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(r.Min.X+sx, r.Min.Y+sy).RGBA()
+					// Premultiplied colour on white
+					sum += (0.299*float32(cr) + 0.587*float32(cg) + 0.114*float32(cb) + float32(0xFFFF-ca)) / 0xFFFF
+				}
+			}
+			lum[y*dw+x] = sum / float32((x1-x0)*(y1-y0))
+		}
+	}
+	return lum, dw, dh
+}
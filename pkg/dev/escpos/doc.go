@@ -0,0 +1,9 @@
+// Escpos package implements gopi.Printer for ESC/POS thermal receipt
+// printers connected by USB or RS232. It prints styled text, images
+// dithered to black and white, QR codes and barcodes, and controls
+// the cutter.
+//
+// The paper and cover status is read at the interval set by the
+// -printer.poll flag, and a gopi.PrinterEvent is emitted when it
+// changes. Set -printer.baud for printers connected by RS232.
+package escpos
@@ -0,0 +1,39 @@
+package escpos
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	status gopi.PrinterStatus
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(status gopi.PrinterStatus) gopi.PrinterEvent {
+	return &event{status}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "printer"
+}
+
+func (this *event) Status() gopi.PrinterStatus {
+	return this.status
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	return fmt.Sprint("<printer.event status=", this.status, ">")
+}
@@ -0,0 +1,13 @@
+package escpos
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.Printer
+	graph.RegisterUnit(reflect.TypeOf(&printer{}), reflect.TypeOf((*gopi.Printer)(nil)))
+}
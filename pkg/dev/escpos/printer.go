@@ -0,0 +1,314 @@
+package escpos
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	term "github.com/pkg/term"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type printer struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	sync.Mutex
+
+	// Flags
+	device *string
+	baud   *uint
+	dots   *uint
+	poll   *time.Duration
+
+	fd     io.ReadWriteCloser
+	read   chan byte
+	status gopi.PrinterStatus
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	defaultDots   = 384 // 58mm paper
+	statusTimeout = 500 * time.Millisecond
+	ttyTimeout    = 100 * time.Millisecond
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *printer) Define(cfg gopi.Config) error {
+	this.device = cfg.FlagString("printer.device", "/dev/usb/lp0", "Printer device")
+	this.baud = cfg.FlagUint("printer.baud", 0, "RS232 speed, or zero for a USB printer")
+	this.dots = cfg.FlagUint("printer.dots", defaultDots, "Width of paper in dots")
+	this.poll = cfg.FlagDuration("printer.poll", 5*time.Second, "Interval between reading paper status, or zero to disable")
+	return nil
+}
+
+func (this *printer) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if _, err := os.Stat(*this.device); os.IsNotExist(err) {
+		return gopi.ErrBadParameter.WithPrefix("-printer.device")
+	} else if *this.dots == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-printer.dots")
+	} else if *this.poll < 0 {
+		return gopi.ErrBadParameter.WithPrefix("-printer.poll")
+	}
+
+	// Open printer
+	if *this.baud == 0 {
+		if fd, err := os.OpenFile(*this.device, os.O_RDWR, 0); err != nil {
+			return err
+		} else {
+			this.fd = fd
+		}
+	} else if fd, err := term.Open(*this.device, term.Speed(int(*this.baud)), term.RawMode); err != nil {
+		return err
+	} else if err := fd.SetReadTimeout(ttyTimeout); err != nil {
+		fd.Close()
+		return err
+	} else {
+		this.fd = fd
+	}
+
+	// Read status responses in the background
+	this.read = make(chan byte, 16)
+	go this.reader(this.fd, this.read)
+
+	// Reset printer
+	if _, err := this.fd.Write(cmdInit()); err != nil {
+		this.fd.Close()
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *printer) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Close device, which ends the reader
+	var result error
+	if this.fd != nil {
+		result = this.fd.Close()
+	}
+
+	// Release resources
+	this.fd = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *printer) Run(ctx context.Context) error {
+	if *this.poll == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(*this.poll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := this.Status(); err != nil {
+				this.Debug("Printer: ", err)
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *printer) Print(text string, style gopi.PrinterStyle) error {
+	return this.write(cmdText(text, style))
+}
+
+func (this *printer) SetAlign(align gopi.PrinterAlign) error {
+	if align > gopi.PRINTER_ALIGN_RIGHT {
+		return gopi.ErrBadParameter.WithPrefix("SetAlign")
+	}
+	return this.write(cmdAlign(align))
+}
+
+func (this *printer) Feed(lines uint) error {
+	return this.write(cmdFeed(lines))
+}
+
+func (this *printer) PrintBitmap(src image.Image) error {
+	if src == nil {
+		return gopi.ErrBadParameter.WithPrefix("PrintBitmap")
+	} else if rows, width := dither(src, int(*this.dots)); width == 0 {
+		return gopi.ErrBadParameter.WithPrefix("PrintBitmap")
+	} else {
+		return this.write(cmdRaster(rows, width))
+	}
+}
+
+func (this *printer) PrintQRCode(data string, level gopi.QRLevel, size uint) error {
+	if data == "" || len(data) > 7089 || level > gopi.QR_LEVEL_MAX {
+		return gopi.ErrBadParameter.WithPrefix("PrintQRCode")
+	} else if size < 1 || size > 16 {
+		return gopi.ErrBadParameter.WithPrefix("PrintQRCode: ", size)
+	}
+	return this.write(cmdQRCode(data, level, size))
+}
+
+func (this *printer) PrintBarcode(data string, t gopi.BarcodeType) error {
+	if buf, err := cmdBarcode(data, t); err != nil {
+		return err
+	} else {
+		return this.write(buf)
+	}
+}
+
+func (this *printer) Cut(partial bool) error {
+	return this.write(cmdCut(partial))
+}
+
+// Status requests printer, offline cause and paper status, and emits
+// an event when the status has changed
+func (this *printer) Status() (gopi.PrinterStatus, error) {
+	this.Mutex.Lock()
+	status := gopi.PRINTER_STATUS_NONE
+	for _, n := range []byte{1, 2, 4} {
+		value, err := this.request(n)
+		if err != nil {
+			this.Mutex.Unlock()
+			return status, err
+		}
+		switch n {
+		case 1:
+			if value&0x08 != 0 {
+				status |= gopi.PRINTER_STATUS_OFFLINE
+			}
+		case 2:
+			if value&0x04 != 0 {
+				status |= gopi.PRINTER_STATUS_COVER_OPEN
+			}
+		case 4:
+			if value&0x0C != 0 {
+				status |= gopi.PRINTER_STATUS_PAPER_LOW
+			}
+			if value&0x60 != 0 {
+				status |= gopi.PRINTER_STATUS_PAPER_OUT
+			}
+		}
+	}
+	changed := status != this.status
+	this.status = status
+	this.Mutex.Unlock()
+
+	// Emit event on change
+	if changed && this.Publisher != nil {
+		if err := this.Publisher.Emit(NewEvent(status), false); err != nil {
+			this.Debug("Printer: ", err)
+		}
+	}
+
+	// Return success
+	return status, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *printer) String() string {
+	str := "<printer.escpos"
+	str += fmt.Sprintf(" device=%q", *this.device)
+	if *this.baud != 0 {
+		str += fmt.Sprint(" baud=", *this.baud)
+	}
+	str += fmt.Sprint(" dots=", *this.dots)
+	this.Mutex.Lock()
+	str += fmt.Sprint(" status=", this.status)
+	this.Mutex.Unlock()
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *printer) write(data []byte) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.fd == nil {
+		return gopi.ErrOutOfOrder.WithPrefix("Printer")
+	} else if _, err := this.fd.Write(data); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+// request sends a real-time status request and returns the response,
+// and is called with the lock held
+func (this *printer) request(n byte) (byte, error) {
+	if this.fd == nil {
+		return 0, gopi.ErrOutOfOrder.WithPrefix("Printer")
+	}
+
+	// Discard previous responses
+	for len(this.read) > 0 {
+		<-this.read
+	}
+
+	// Request status and wait for response
+	if _, err := this.fd.Write(cmdStatus(n)); err != nil {
+		return 0, err
+	}
+	timer := time.NewTimer(statusTimeout)
+	defer timer.Stop()
+	select {
+	case value, ok := <-this.read:
+		if ok == false {
+			return 0, gopi.ErrUnexpectedResponse.WithPrefix("Status: closed")
+		}
+		return value, nil
+	case <-timer.C:
+		return 0, gopi.ErrUnexpectedResponse.WithPrefix("Status: timeout")
+	}
+}
+
+// reader reads responses from the printer until the device is closed.
+// End of file is returned when there is no response
+func (this *printer) reader(fd io.Reader, ch chan<- byte) {
+	defer close(ch)
+	buf := make([]byte, 16)
+	for {
+		n, err := fd.Read(buf)
+		for _, value := range buf[:n] {
+			select {
+			case ch <- value:
+			default:
+				// Discard when full
+			}
+		}
+		if err == io.EOF {
+			time.Sleep(ttyTimeout)
+		} else if err != nil {
+			return
+		}
+	}
+}
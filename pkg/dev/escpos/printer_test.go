@@ -0,0 +1,150 @@
+package escpos_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/dev/escpos"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Printer
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Printer_001(t *testing.T) {
+	device := tempDevice(t)
+	defer os.Remove(device)
+
+	tool.Test(t, []string{"-printer.device", device, "-printer.poll", "0"}, new(App), func(app *App) {
+		if err := app.Printer.SetAlign(gopi.PRINTER_ALIGN_CENTER); err != nil {
+			t.Error(err)
+		}
+		if err := app.Printer.Print("Hello\n", gopi.PRINTER_STYLE_BOLD|gopi.PRINTER_STYLE_DOUBLE_HEIGHT); err != nil {
+			t.Error(err)
+		}
+		if err := app.Printer.Feed(3); err != nil {
+			t.Error(err)
+		}
+		if err := app.Printer.Cut(true); err != nil {
+			t.Error(err)
+		}
+		t.Log(app.Printer)
+
+		data := readDevice(t, device)
+		for _, expected := range [][]byte{
+			{0x1B, '@'},                     // Initialize
+			{0x1B, 'a', 1},                  // Centre
+			{0x1B, 'E', 1},                  // Bold
+			{0x1D, '!', 0x01},               // Double height
+			{'H', 'e', 'l', 'l', 'o', 0x0A}, // Text
+			{0x1B, 'E', 0},                  // Reset bold
+			{0x1B, 'd', 3},                  // Feed
+			{0x1D, 'V', 1},                  // Partial cut
+		} {
+			if bytes.Contains(data, expected) == false {
+				t.Errorf("Expected % X in output", expected)
+			}
+		}
+	})
+}
+
+func Test_Printer_002(t *testing.T) {
+	device := tempDevice(t)
+	defer os.Remove(device)
+
+	tool.Test(t, []string{"-printer.device", device, "-printer.poll", "0"}, new(App), func(app *App) {
+		if err := app.Printer.PrintBarcode("123456789012", gopi.BARCODE_EAN13); err != nil {
+			t.Error(err)
+		}
+		if err := app.Printer.PrintBarcode("12345", gopi.BARCODE_EAN13); err == nil {
+			t.Error("Expected error for invalid EAN13")
+		}
+		if err := app.Printer.PrintBarcode("ABC", gopi.BARCODE_CODE128); err != nil {
+			t.Error(err)
+		}
+		if err := app.Printer.PrintQRCode("https://example.com/", gopi.QR_LEVEL_M, 6); err != nil {
+			t.Error(err)
+		}
+		if err := app.Printer.PrintQRCode("data", gopi.QR_LEVEL_M, 0); err == nil {
+			t.Error("Expected error for zero module size")
+		}
+
+		data := readDevice(t, device)
+		for _, expected := range [][]byte{
+			append([]byte{0x1D, 'k', 67, 12}, "123456789012"...),
+			append([]byte{0x1D, 'k', 73, 5}, "{BABC"...),
+			{0x1D, '(', 'k', 3, 0, '1', 'C', 6},
+			{0x1D, '(', 'k', 3, 0, '1', 'E', '1'},
+			append([]byte{0x1D, '(', 'k', 23, 0, '1', 'P', '0'}, "https://example.com/"...),
+			{0x1D, '(', 'k', 3, 0, '1', 'Q', '0'},
+		} {
+			if bytes.Contains(data, expected) == false {
+				t.Errorf("Expected % X in output", expected)
+			}
+		}
+	})
+}
+
+func Test_Printer_003(t *testing.T) {
+	device := tempDevice(t)
+	defer os.Remove(device)
+
+	tool.Test(t, []string{"-printer.device", device, "-printer.poll", "0", "-printer.dots", "8"}, new(App), func(app *App) {
+		// Sixteen pixels wide, black on the left and transparent on the
+		// right, which is scaled to eight dots
+		img := image.NewRGBA(image.Rect(0, 0, 16, 4))
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 8; x++ {
+				img.Set(x, y, color.Black)
+			}
+		}
+		if err := app.Printer.PrintBitmap(img); err != nil {
+			t.Fatal(err)
+		}
+
+		// One byte wide and two rows high
+		data := readDevice(t, device)
+		expected := []byte{0x1D, 'v', '0', 0, 1, 0, 2, 0, 0xF0, 0xF0}
+		if bytes.Contains(data, expected) == false {
+			t.Errorf("Expected % X in output % X", expected, data)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func tempDevice(t *testing.T) string {
+	t.Helper()
+	if fh, err := ioutil.TempFile("", "printer"); err != nil {
+		t.Fatal(err)
+		return ""
+	} else {
+		defer fh.Close()
+		return fh.Name()
+	}
+}
+
+func readDevice(t *testing.T, path string) []byte {
+	t.Helper()
+	if data, err := ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+		return nil
+	} else {
+		return data
+	}
+}
@@ -0,0 +1,290 @@
+package esp
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type device struct {
+	sync.RWMutex
+
+	id       string
+	name     string
+	firmware gopi.ESPFirmware
+	model    string
+	version  string
+	addr     net.IP
+	online   bool
+	entities []*entity
+
+	// Topics are prefixes for Tasmota, and availability for ESPHome
+	cmnd, stat, tele string
+	lwtOnline        string
+	avty             string
+}
+
+type entity struct {
+	sync.RWMutex
+
+	id         string
+	name       string
+	t          gopi.ESPEntityType
+	state      bool
+	brightness float32
+	value      float64
+	unit       string
+
+	// Topics and schema for ESPHome
+	stateTopic string
+	cmdTopic   string
+	json       bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newDevice(id, name string, firmware gopi.ESPFirmware) *device {
+	return &device{id: id, name: name, firmware: firmware}
+}
+
+func newEntity(id, name string, t gopi.ESPEntityType) *entity {
+	return &entity{id: id, name: name, t: t}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// DEVICE PROPERTIES
+
+func (this *device) Id() string {
+	return this.id
+}
+
+func (this *device) Name() string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.name
+}
+
+func (this *device) Firmware() gopi.ESPFirmware {
+	return this.firmware
+}
+
+func (this *device) Model() string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.model
+}
+
+func (this *device) Version() string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.version
+}
+
+func (this *device) Addr() net.IP {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.addr
+}
+
+func (this *device) Online() bool {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.online
+}
+
+func (this *device) Entities() []gopi.ESPEntity {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	result := make([]gopi.ESPEntity, 0, len(this.entities))
+	for _, entity := range this.entities {
+		result = append(result, entity)
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ENTITY PROPERTIES
+
+func (this *entity) Id() string {
+	return this.id
+}
+
+func (this *entity) Name() string {
+	return this.name
+}
+
+func (this *entity) Type() gopi.ESPEntityType {
+	return this.t
+}
+
+func (this *entity) State() bool {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.state
+}
+
+func (this *entity) Brightness() float32 {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.brightness
+}
+
+func (this *entity) Value() float64 {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.value
+}
+
+func (this *entity) Unit() string {
+	return this.unit
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *device) String() string {
+	str := "<esp.device"
+	str += fmt.Sprintf(" id=%q name=%q", this.id, this.Name())
+	str += fmt.Sprint(" firmware=", this.firmware)
+	if model := this.Model(); model != "" {
+		str += fmt.Sprintf(" model=%q", model)
+	}
+	if version := this.Version(); version != "" {
+		str += fmt.Sprintf(" version=%q", version)
+	}
+	if addr := this.Addr(); addr != nil {
+		str += fmt.Sprint(" addr=", addr)
+	}
+	if this.Online() {
+		str += " online"
+	}
+	for _, entity := range this.Entities() {
+		str += fmt.Sprint(" ", entity)
+	}
+	return str + ">"
+}
+
+func (this *entity) String() string {
+	str := "<esp.entity"
+	str += fmt.Sprintf(" id=%q name=%q", this.id, this.name)
+	str += fmt.Sprint(" type=", this.t)
+	switch this.t {
+	case gopi.ESP_ENTITY_SENSOR:
+		str += " value=" + strconv.FormatFloat(this.Value(), 'f', -1, 64)
+		if this.unit != "" {
+			str += fmt.Sprintf(" unit=%q", this.unit)
+		}
+	case gopi.ESP_ENTITY_LIGHT:
+		str += fmt.Sprint(" state=", this.State(), " brightness=", this.Brightness())
+	default:
+		str += fmt.Sprint(" state=", this.State())
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// entity returns an entity by identifier, or nil
+func (this *device) entity(id string) *entity {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	for _, entity := range this.entities {
+		if entity.id == id {
+			return entity
+		}
+	}
+	return nil
+}
+
+// snapshot returns all entities
+func (this *device) snapshot() []*entity {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return append([]*entity{}, this.entities...)
+}
+
+// ofType returns entities of a type
+func (this *device) ofType(t gopi.ESPEntityType) []*entity {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	result := []*entity{}
+	for _, entity := range this.entities {
+		if entity.t == t {
+			result = append(result, entity)
+		}
+	}
+	return result
+}
+
+// addEntity adds or replaces an entity, retaining any state
+func (this *device) addEntity(e *entity) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	for i, other := range this.entities {
+		if other.id == e.id {
+			e.state, e.brightness, e.value = other.State(), other.Brightness(), other.Value()
+			this.entities[i] = e
+			return
+		}
+	}
+	this.entities = append(this.entities, e)
+}
+
+// removeEntity removes an entity by identifier
+func (this *device) removeEntity(id string) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	for i, entity := range this.entities {
+		if entity.id == id {
+			this.entities = append(this.entities[:i], this.entities[i+1:]...)
+			return
+		}
+	}
+}
+
+// setOnline sets the device online or offline, and returns true
+// if changed
+func (this *device) setOnline(online bool) bool {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	changed := this.online != online
+	this.online = online
+	return changed
+}
+
+// setState sets the state of a switch, light or binary sensor,
+// and returns true if changed
+func (this *entity) setState(state bool) bool {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	changed := this.state != state
+	this.state = state
+	return changed
+}
+
+// setBrightness sets the brightness of a light and returns true
+// if changed
+func (this *entity) setBrightness(brightness float32) bool {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	changed := this.brightness != brightness
+	this.brightness = brightness
+	return changed
+}
+
+// setValue sets the value of a sensor and returns true if changed
+func (this *entity) setValue(value float64) bool {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	changed := this.value != value
+	this.value = value
+	return changed
+}
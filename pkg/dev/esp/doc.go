@@ -0,0 +1,11 @@
+// Esp package implements gopi.ESPManager, which bridges to smart plugs,
+// lights and sensors running Tasmota or ESPHome firmware through an
+// MQTT broker.
+//
+// Tasmota devices are discovered from the tasmota/discovery topic,
+// and ESPHome devices from Home Assistant discovery messages. Devices
+// advertising ESPHome with mDNS are added when gopi.ServiceDiscovery
+// is available, so devices which are not using MQTT are also listed.
+// A gopi.ESPEvent is emitted when a device is discovered, goes online
+// or offline, or when a switch, light or sensor changes state.
+package esp
@@ -0,0 +1,219 @@
+package esp
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://esphome.io/components/mqtt.html
+// Ref: https://www.home-assistant.io/integrations/mqtt/#discovery-messages
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// haConfig is a Home Assistant discovery message, where keys may
+// be abbreviated
+type haConfig map[string]interface{}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	esphomeComponents = map[string]gopi.ESPEntityType{
+		"switch":        gopi.ESP_ENTITY_SWITCH,
+		"light":         gopi.ESP_ENTITY_LIGHT,
+		"sensor":        gopi.ESP_ENTITY_SENSOR,
+		"binary_sensor": gopi.ESP_ENTITY_BINARY_SENSOR,
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// esphomeDiscovery adds an entity from a discovery message published
+// to <discovery>/<component>/<node>/<object>/config. Messages from
+// other firmware are ignored
+func (this *manager) esphomeDiscovery(component, node, object string, data []byte) error {
+	t, exists := esphomeComponents[component]
+	if exists == false {
+		return nil
+	}
+
+	// Remove entity when the configuration is empty
+	id := component + "/" + object
+	if len(data) == 0 {
+		if device := this.device(node); device != nil && device.firmware == gopi.ESP_FIRMWARE_ESPHOME {
+			device.removeEntity(id)
+		}
+		return nil
+	}
+
+	// Decode configuration
+	var config haConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+	dev, _ := config.value("dev", "device").(map[string]interface{})
+	if version, _ := dev["sw"].(string); strings.HasPrefix(strings.ToLower(version), "esphome") == false {
+		return nil
+	}
+
+	// Add device, where the node is the same as the mDNS instance name
+	device, added := this.addDevice(node, node, gopi.ESP_FIRMWARE_ESPHOME)
+	device.RWMutex.Lock()
+	if name, _ := dev["name"].(string); name != "" {
+		device.name = name
+	}
+	if model, _ := dev["mdl"].(string); model != "" {
+		device.model = model
+	}
+	if version, _ := dev["sw"].(string); version != "" {
+		device.version = strings.TrimPrefix(strings.TrimPrefix(version, "esphome "), "v")
+	}
+	if avty := config.topic("avty_t", "availability_topic"); avty != "" {
+		device.avty = avty
+	}
+	avty := device.avty
+	device.RWMutex.Unlock()
+
+	// Add entity
+	name := config.string("name")
+	if name == "" {
+		name = object
+	}
+	entity := newEntity(id, name, t)
+	entity.stateTopic = config.topic("stat_t", "state_topic")
+	entity.cmdTopic = config.topic("cmd_t", "command_topic")
+	entity.unit = config.string("unit_of_meas", "unit_of_measurement")
+	entity.json = config.string("schema") == "json"
+	device.addEntity(entity)
+
+	// Subscribe to state and availability
+	topics := []string{}
+	if entity.stateTopic != "" {
+		topics = append(topics, entity.stateTopic)
+	}
+	if avty != "" {
+		topics = append(topics, avty)
+	}
+	if len(topics) > 0 {
+		if err := this.subscribe(topics...); err != nil {
+			return err
+		}
+	}
+
+	// Emit event when added
+	if added {
+		return this.emit(NewEvent(gopi.ESP_EVENT_ADDED, device, nil))
+	} else {
+		return nil
+	}
+}
+
+// esphomeMessage handles state and availability, and returns false
+// if the topic is not for the device
+func (this *manager) esphomeMessage(device *device, topic string, data []byte) (bool, error) {
+	device.RWMutex.RLock()
+	avty := device.avty
+	device.RWMutex.RUnlock()
+
+	if avty != "" && topic == avty {
+		return true, this.setOnline(device, string(data) == "online")
+	}
+	for _, entity := range device.snapshot() {
+		if entity.stateTopic != topic {
+			continue
+		}
+		changed := false
+		switch {
+		case entity.t == gopi.ESP_ENTITY_SENSOR:
+			if value, err := strconv.ParseFloat(string(data), 64); err != nil {
+				return true, gopi.ErrUnexpectedResponse.WithPrefix(topic, ": ", strconv.Quote(string(data)))
+			} else {
+				changed = entity.setValue(value)
+			}
+		case entity.json:
+			var state struct {
+				State      string   `json:"state"`
+				Brightness *float32 `json:"brightness"`
+			}
+			if err := json.Unmarshal(data, &state); err != nil {
+				return true, err
+			}
+			changed = entity.setState(state.State == "ON")
+			if state.Brightness != nil && entity.setBrightness(*state.Brightness/255) {
+				changed = true
+			}
+		default:
+			changed = entity.setState(string(data) == "ON")
+		}
+		if changed {
+			return true, this.emit(NewEvent(gopi.ESP_EVENT_CHANGED, device, entity))
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// esphomeCommand publishes a command to an entity, with a JSON payload
+// for lights which use the JSON schema
+func (this *manager) esphomeCommand(entity *entity, state bool, brightness float32) error {
+	if entity.cmdTopic == "" {
+		return gopi.ErrNotImplemented.WithPrefix(entity.id)
+	}
+	value := "OFF"
+	if state {
+		value = "ON"
+	}
+	if entity.json == false {
+		if brightness >= 0 {
+			return gopi.ErrNotImplemented.WithPrefix(entity.id, ": Brightness")
+		}
+		return this.publish(entity.cmdTopic, []byte(value))
+	}
+	command := map[string]interface{}{
+		"state": value,
+	}
+	if brightness >= 0 {
+		command["brightness"] = int(brightness*255 + 0.5)
+	}
+	if data, err := json.Marshal(command); err != nil {
+		return err
+	} else {
+		return this.publish(entity.cmdTopic, data)
+	}
+}
+
+// value returns the value for the first key which exists
+func (this haConfig) value(keys ...string) interface{} {
+	for _, key := range keys {
+		if value, exists := this[key]; exists {
+			return value
+		}
+	}
+	return nil
+}
+
+// string returns the string value for the first key which exists
+func (this haConfig) string(keys ...string) string {
+	value, _ := this.value(keys...).(string)
+	return value
+}
+
+// topic returns a topic for the first key which exists, where a
+// tilde at the start or end is replaced by the base topic
+func (this haConfig) topic(keys ...string) string {
+	topic := this.string(keys...)
+	if base := this.string("~"); base == "" {
+		return topic
+	} else if strings.HasPrefix(topic, "~") {
+		return base + strings.TrimPrefix(topic, "~")
+	} else if strings.HasSuffix(topic, "~") {
+		return strings.TrimSuffix(topic, "~") + base
+	} else {
+		return topic
+	}
+}
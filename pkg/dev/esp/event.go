@@ -0,0 +1,59 @@
+package esp
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t      gopi.ESPEventType
+	device *device
+	entity *entity
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(t gopi.ESPEventType, device *device, entity *entity) gopi.ESPEvent {
+	return &event{t, device, entity}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.device.Name()
+}
+
+func (this *event) Type() gopi.ESPEventType {
+	return this.t
+}
+
+func (this *event) Device() gopi.ESPDevice {
+	return this.device
+}
+
+func (this *event) Entity() gopi.ESPEntity {
+	if this.entity == nil {
+		return nil
+	} else {
+		return this.entity
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<esp.event"
+	str += fmt.Sprint(" type=", this.t)
+	str += fmt.Sprintf(" name=%q", this.Name())
+	if this.entity != nil {
+		str += fmt.Sprint(" ", this.entity)
+	}
+	return str + ">"
+}
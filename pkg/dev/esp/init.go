@@ -0,0 +1,13 @@
+package esp
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.ESPManager
+	graph.RegisterUnit(reflect.TypeOf(&manager{}), reflect.TypeOf((*gopi.ESPManager)(nil)))
+}
@@ -0,0 +1,467 @@
+package esp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	mqtt "github.com/djthorpe/gopi/v3/pkg/dev/internal/mqtt"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type manager struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.ServiceDiscovery
+	sync.RWMutex
+
+	// Flags
+	broker   *string
+	user     *string
+	password *string
+	timeout  *time.Duration
+	tasmota  *string
+	esphome  *string
+	lookup   *time.Duration
+
+	client  *mqtt.Client
+	devices map[string]*device // Devices keyed by identifier
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	serviceTypeESPHome = "_esphomelib._tcp"
+	keepAlive          = 60 * time.Second
+	reconnectDelta     = 10 * time.Second
+	lookupTimeout      = 5 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *manager) Define(cfg gopi.Config) error {
+	this.broker = cfg.FlagString("esp.broker", "localhost:1883", "MQTT broker address")
+	this.user = cfg.FlagString("esp.user", "", "MQTT username")
+	this.password = cfg.FlagSecret("esp.password", "", "MQTT password")
+	this.timeout = cfg.FlagDuration("esp.timeout", 10*time.Second, "MQTT connection timeout")
+	this.tasmota = cfg.FlagString("esp.tasmota", "tasmota/discovery", "Tasmota discovery topic, or empty to disable")
+	this.esphome = cfg.FlagString("esp.esphome", "homeassistant", "ESPHome discovery topic, or empty to disable")
+	this.lookup = cfg.FlagDuration("esp.lookup", 5*time.Minute, "Interval between mDNS lookups, or zero to disable")
+	return nil
+}
+
+func (this *manager) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	*this.tasmota = strings.Trim(*this.tasmota, "/")
+	*this.esphome = strings.Trim(*this.esphome, "/")
+	if *this.broker == "" {
+		return gopi.ErrBadParameter.WithPrefix("-esp.broker")
+	} else if *this.timeout <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-esp.timeout")
+	} else if *this.lookup < 0 {
+		return gopi.ErrBadParameter.WithPrefix("-esp.lookup")
+	} else if *this.tasmota == "" && *this.esphome == "" {
+		return gopi.ErrBadParameter.WithPrefix("-esp.tasmota")
+	}
+
+	// Connect to broker
+	this.devices = make(map[string]*device)
+	if err := this.connect(); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *manager) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	var result error
+	if this.client != nil {
+		result = this.client.Close()
+	}
+
+	// Release resources
+	this.client = nil
+	this.devices = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *manager) Run(ctx context.Context) error {
+	// Look up devices with mDNS in the background
+	var wg sync.WaitGroup
+	if this.ServiceDiscovery != nil && *this.lookup > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			this.runLookup(ctx)
+		}()
+	}
+	defer wg.Wait()
+
+	ticker := time.NewTicker(keepAlive / 2)
+	defer ticker.Stop()
+
+	for {
+		// Reconnect after the connection is lost
+		client := this.conn()
+		if client == nil {
+			if err := this.connect(); err != nil {
+				this.Debug("ESP: ", err)
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(reconnectDelta):
+				}
+			}
+			continue
+		}
+
+		// Receive messages in the background
+		errs := make(chan error, 1)
+		go func() {
+			errs <- this.receive(client)
+		}()
+
+		// Ping broker until the connection is lost or done
+	FOR_LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if err := client.Ping(); err != nil {
+					this.Debug("ESP: ", err)
+				}
+			case err := <-errs:
+				this.Print("ESP: ", err)
+				this.RWMutex.Lock()
+				if this.client == client {
+					this.client.Close()
+					this.client = nil
+				}
+				this.RWMutex.Unlock()
+				break FOR_LOOP
+			}
+		}
+	}
+}
+
+func (this *manager) runLookup(ctx context.Context) {
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			if err := this.lookupDevices(ctx); err != nil {
+				this.Debug("ESP: ", err)
+			}
+			timer.Reset(*this.lookup)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *manager) Devices() []gopi.ESPDevice {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	result := make([]gopi.ESPDevice, 0, len(this.devices))
+	for _, device := range this.devices {
+		result = append(result, device)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
+
+func (this *manager) Device(name string) gopi.ESPDevice {
+	if device := this.device(name); device != nil {
+		return device
+	} else {
+		return nil
+	}
+}
+
+func (this *manager) SetState(e gopi.ESPEntity, state bool) error {
+	device, entity := this.entity(e)
+	if entity == nil || (entity.t != gopi.ESP_ENTITY_SWITCH && entity.t != gopi.ESP_ENTITY_LIGHT) {
+		return gopi.ErrBadParameter.WithPrefix("SetState")
+	}
+	switch device.firmware {
+	case gopi.ESP_FIRMWARE_TASMOTA:
+		if state {
+			return this.tasmotaCommand(device, entity.id, "ON")
+		} else {
+			return this.tasmotaCommand(device, entity.id, "OFF")
+		}
+	case gopi.ESP_FIRMWARE_ESPHOME:
+		return this.esphomeCommand(entity, state, -1)
+	default:
+		return gopi.ErrNotImplemented.WithPrefix("SetState")
+	}
+}
+
+func (this *manager) SetBrightness(e gopi.ESPEntity, brightness float32) error {
+	device, entity := this.entity(e)
+	if entity == nil || entity.t != gopi.ESP_ENTITY_LIGHT {
+		return gopi.ErrBadParameter.WithPrefix("SetBrightness")
+	} else if brightness < 0 || brightness > 1 {
+		return gopi.ErrBadParameter.WithPrefix("SetBrightness: ", brightness)
+	}
+	switch device.firmware {
+	case gopi.ESP_FIRMWARE_TASMOTA:
+		return this.tasmotaCommand(device, "Dimmer", strconv.Itoa(int(brightness*100+0.5)))
+	case gopi.ESP_FIRMWARE_ESPHOME:
+		return this.esphomeCommand(entity, brightness > 0, brightness)
+	default:
+		return gopi.ErrNotImplemented.WithPrefix("SetBrightness")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *manager) String() string {
+	str := "<esp.manager"
+	str += fmt.Sprintf(" broker=%q", *this.broker)
+	if this.conn() == nil {
+		str += " disconnected"
+	}
+	for _, device := range this.Devices() {
+		str += fmt.Sprint(" ", device)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// connect to the broker and subscribe to discovery topics
+func (this *manager) connect() error {
+	topics := []string{}
+	if *this.tasmota != "" {
+		topics = append(topics, *this.tasmota+"/#")
+	}
+	if *this.esphome != "" {
+		topics = append(topics, *this.esphome+"/#")
+	}
+
+	hostname, _ := os.Hostname()
+	client := fmt.Sprint("gopi-", hostname, "-", os.Getpid())
+	if client, err := mqtt.Dial(*this.broker, client, *this.user, *this.password, keepAlive, *this.timeout); err != nil {
+		return err
+	} else if err := client.Subscribe(topics...); err != nil {
+		client.Close()
+		return err
+	} else {
+		this.RWMutex.Lock()
+		this.client = client
+		this.RWMutex.Unlock()
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *manager) conn() *mqtt.Client {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.client
+}
+
+// subscribe to device topics
+func (this *manager) subscribe(topics ...string) error {
+	if client := this.conn(); client == nil {
+		return gopi.ErrOutOfOrder.WithPrefix("ESP: Not connected")
+	} else {
+		return client.Subscribe(topics...)
+	}
+}
+
+// publish a command
+func (this *manager) publish(topic string, data []byte) error {
+	if client := this.conn(); client == nil {
+		return gopi.ErrOutOfOrder.WithPrefix("ESP: Not connected")
+	} else {
+		return client.Publish(topic, data, false)
+	}
+}
+
+// receive messages until the connection is lost
+func (this *manager) receive(client *mqtt.Client) error {
+	for {
+		if topic, data, err := client.Receive(); err != nil {
+			return err
+		} else if err := this.message(topic, data); err != nil {
+			this.Debug("ESP: ", topic, ": ", err)
+		}
+	}
+}
+
+// message handles discovery, state and telemetry
+func (this *manager) message(topic string, data []byte) error {
+	// Tasmota discovery is <prefix>/<mac>/config or sensors
+	if *this.tasmota != "" && strings.HasPrefix(topic, *this.tasmota+"/") {
+		if path := strings.Split(strings.TrimPrefix(topic, *this.tasmota+"/"), "/"); len(path) == 2 {
+			return this.tasmotaDiscovery(path[0], path[1], data)
+		}
+		return nil
+	}
+
+	// ESPHome discovery is <prefix>/<component>/<node>/<object>/config
+	if *this.esphome != "" && strings.HasPrefix(topic, *this.esphome+"/") {
+		if path := strings.Split(strings.TrimPrefix(topic, *this.esphome+"/"), "/"); len(path) == 4 && path[3] == "config" {
+			return this.esphomeDiscovery(path[0], path[1], path[2], data)
+		}
+		return nil
+	}
+
+	// State and telemetry
+	this.RWMutex.RLock()
+	devices := make([]*device, 0, len(this.devices))
+	for _, device := range this.devices {
+		devices = append(devices, device)
+	}
+	this.RWMutex.RUnlock()
+	for _, device := range devices {
+		switch device.firmware {
+		case gopi.ESP_FIRMWARE_TASMOTA:
+			if handled, err := this.tasmotaMessage(device, topic, data); handled {
+				return err
+			}
+		case gopi.ESP_FIRMWARE_ESPHOME:
+			if handled, err := this.esphomeMessage(device, topic, data); handled {
+				return err
+			}
+		}
+	}
+
+	// Ignore other messages
+	return nil
+}
+
+// lookupDevices sets addresses of ESPHome devices from mDNS, and adds
+// devices which are not connected to the broker
+func (this *manager) lookupDevices(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	records, err := this.ServiceDiscovery.Lookup(ctx, serviceTypeESPHome)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		addrs := record.Addrs()
+		if record.Name() == "" || len(addrs) == 0 {
+			continue
+		}
+		device, added := this.addDevice(record.Name(), record.Name(), gopi.ESP_FIRMWARE_ESPHOME)
+		device.RWMutex.Lock()
+		device.addr = addrs[0]
+		device.RWMutex.Unlock()
+		if added {
+			if err := this.emit(NewEvent(gopi.ESP_EVENT_ADDED, device, nil)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// device returns a device by identifier or name
+func (this *manager) device(name string) *device {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if device, exists := this.devices[name]; exists {
+		return device
+	}
+	for _, device := range this.devices {
+		if device.Name() == name {
+			return device
+		}
+	}
+	return nil
+}
+
+// entity returns the device and entity for an entity
+func (this *manager) entity(e gopi.ESPEntity) (*device, *entity) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	for _, device := range this.devices {
+		for _, entity := range device.snapshot() {
+			if gopi.ESPEntity(entity) == e {
+				return device, entity
+			}
+		}
+	}
+	return nil, nil
+}
+
+// addDevice returns an existing device or adds a device, and returns
+// true if the device was added
+func (this *manager) addDevice(id, name string, firmware gopi.ESPFirmware) (*device, bool) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if device, exists := this.devices[id]; exists {
+		return device, false
+	}
+	device := newDevice(id, name, firmware)
+	this.devices[id] = device
+	return device, true
+}
+
+func (this *manager) removeDevice(id string) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	delete(this.devices, id)
+}
+
+// setOnline emits an event when a device goes online or offline
+func (this *manager) setOnline(device *device, online bool) error {
+	if device.setOnline(online) == false {
+		return nil
+	} else if online {
+		return this.emit(NewEvent(gopi.ESP_EVENT_ONLINE, device, nil))
+	} else {
+		return this.emit(NewEvent(gopi.ESP_EVENT_OFFLINE, device, nil))
+	}
+}
+
+func (this *manager) emit(evt gopi.ESPEvent) error {
+	if this.Publisher != nil {
+		return this.Publisher.Emit(evt, false)
+	} else {
+		return nil
+	}
+}
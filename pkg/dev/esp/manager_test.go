@@ -0,0 +1,236 @@
+package esp_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/dev/esp"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.ESPManager
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// message is published by the manager to the broker
+type message struct {
+	topic   string
+	payload string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	tasmotaConfig = `{"ip":"192.168.1.20","dn":"Kitchen Plug","fn":["Kettle",null],"hn":"tasmota-ABCDEF-1234",` +
+		`"mac":"AABBCCABCDEF","md":"Sonoff S26","sw":"12.1.1","t":"tasmota_ABCDEF","ft":"%prefix%/%topic%/",` +
+		`"tp":["cmnd","stat","tele"],"rl":[1,0,0,0],"onln":"Online","ofln":"Offline"}`
+	tasmotaSensor  = `{"Time":"2024-01-01T12:00:00","ENERGY":{"Power":1850,"Voltage":231},"AM2301":{"Temperature":21.5,"Humidity":40.1},"TempUnit":"C"}`
+	esphomeSwitch  = `{"name":"Relay","stat_t":"porch/switch/relay/state","cmd_t":"porch/switch/relay/command","avty_t":"porch/status","dev":{"ids":"aabbccddeeff","name":"porch","sw":"esphome v2023.12.5","mdl":"esp01_1m","mf":"espressif"}}`
+	esphomeLight   = `{"name":"Lamp","schema":"json","~":"porch/light/lamp","stat_t":"~/state","cmd_t":"~/command","brightness":true,"dev":{"ids":"aabbccddeeff","name":"porch","sw":"esphome v2023.12.5"}}`
+	homeAssistant  = `{"name":"Other","stat_t":"other/state","dev":{"ids":"1234","sw":"Other 1.0"}}`
+	esphomeSensor  = `{"name":"Temperature","stat_t":"porch/sensor/temperature/state","unit_of_meas":"°C","dev":{"ids":"aabbccddeeff","name":"porch","sw":"esphome v2023.12.5"}}`
+	esphomeInvalid = `{"name":"Missing","dev":{"sw":"esphome v2023.12.5"}}`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_ESP_001(t *testing.T) {
+	addr, messages := broker(t, func(w io.Writer) {
+		publish(w, "tasmota/discovery/AABBCCABCDEF/config", tasmotaConfig)
+		publish(w, "tele/tasmota_ABCDEF/LWT", "Online")
+		publish(w, "tele/tasmota_ABCDEF/STATE", `{"POWER":"ON"}`)
+		publish(w, "tele/tasmota_ABCDEF/SENSOR", tasmotaSensor)
+		publish(w, "homeassistant/switch/porch/relay/config", esphomeSwitch)
+		publish(w, "homeassistant/light/porch/lamp/config", esphomeLight)
+		publish(w, "homeassistant/sensor/porch/temperature/config", esphomeSensor)
+		publish(w, "homeassistant/sensor/other/value/config", homeAssistant)
+		publish(w, "homeassistant/binary_sensor/porch/missing/config", esphomeInvalid)
+		publish(w, "porch/status", "online")
+		publish(w, "porch/switch/relay/state", "ON")
+		publish(w, "porch/light/lamp/state", `{"state":"ON","brightness":128}`)
+		publish(w, "porch/sensor/temperature/state", "18.25")
+	})
+
+	tool.Test(t, []string{"-esp.broker", addr}, new(App), func(app *App) {
+		// Wait for the last state to be received
+		var porch gopi.ESPDevice
+		for i := 0; i < 20; i++ {
+			if porch = app.ESPManager.Device("porch"); porch != nil && entity(porch, "sensor/temperature") != nil && entity(porch, "sensor/temperature").Value() != 0 {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Log(app.ESPManager)
+		if devices := app.ESPManager.Devices(); len(devices) != 2 {
+			t.Error("Unexpected devices", devices)
+		}
+
+		// Tasmota
+		plug := app.ESPManager.Device("Kitchen Plug")
+		if plug == nil || plug.Firmware() != gopi.ESP_FIRMWARE_TASMOTA || plug.Online() == false || plug.Addr().String() != "192.168.1.20" {
+			t.Fatal("Unexpected device", plug)
+		}
+		if kettle := entity(plug, "POWER1"); kettle == nil || kettle.Name() != "Kettle" || kettle.Type() != gopi.ESP_ENTITY_SWITCH || kettle.State() == false {
+			t.Error("Unexpected relay", kettle)
+		}
+		if power := entity(plug, "ENERGY/Power"); power == nil || power.Value() != 1850 || power.Unit() != "W" {
+			t.Error("Unexpected sensor", power)
+		}
+		if temperature := entity(plug, "AM2301/Temperature"); temperature == nil || temperature.Value() != 21.5 || temperature.Unit() != "°C" {
+			t.Error("Unexpected sensor", temperature)
+		}
+
+		// ESPHome
+		if porch == nil || porch.Firmware() != gopi.ESP_FIRMWARE_ESPHOME || porch.Online() == false || porch.Version() != "2023.12.5" {
+			t.Fatal("Unexpected device", porch)
+		}
+		if len(porch.Entities()) != 4 {
+			t.Error("Unexpected entities", porch.Entities())
+		}
+		if relay := entity(porch, "switch/relay"); relay == nil || relay.State() == false {
+			t.Error("Unexpected switch", relay)
+		}
+		if lamp := entity(porch, "light/lamp"); lamp == nil || lamp.State() == false || lamp.Brightness() != float32(128)/255 {
+			t.Error("Unexpected light", lamp)
+		}
+		if sensor := entity(porch, "sensor/temperature"); sensor == nil || sensor.Value() != 18.25 || sensor.Unit() != "°C" {
+			t.Error("Unexpected sensor", sensor)
+		}
+
+		// Send commands
+		if err := app.ESPManager.SetState(entity(plug, "POWER1"), false); err != nil {
+			t.Error(err)
+		}
+		if err := app.ESPManager.SetState(entity(porch, "switch/relay"), false); err != nil {
+			t.Error(err)
+		}
+		if err := app.ESPManager.SetBrightness(entity(porch, "light/lamp"), 0.5); err != nil {
+			t.Error(err)
+		}
+		if err := app.ESPManager.SetBrightness(entity(porch, "switch/relay"), 0.5); err == nil {
+			t.Error("Expected error setting brightness of a switch")
+		}
+		if err := app.ESPManager.SetState(entity(porch, "sensor/temperature"), true); err == nil {
+			t.Error("Expected error setting state of a sensor")
+		}
+		for _, expected := range []message{
+			{"cmnd/tasmota_ABCDEF/POWER1", "OFF"},
+			{"porch/switch/relay/command", "OFF"},
+			{"porch/light/lamp/command", `{"brightness":128,"state":"ON"}`},
+		} {
+			select {
+			case msg := <-messages:
+				if msg != expected {
+					t.Error("Unexpected message", msg, "expected", expected)
+				}
+			case <-time.After(time.Second):
+				t.Error("Timeout waiting for", expected)
+			}
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func entity(device gopi.ESPDevice, id string) gopi.ESPEntity {
+	for _, entity := range device.Entities() {
+		if entity.Id() == id {
+			return entity
+		}
+	}
+	return nil
+}
+
+// broker accepts a connection, calls a function to publish messages
+// and returns messages published by the client
+func broker(t *testing.T, fn func(io.Writer)) (string, <-chan message) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make(chan message, 10)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		if packet, _ := readPacket(r); packet != 0x10 {
+			return
+		}
+		conn.Write([]byte{0x20, 2, 0, 0})
+		fn(conn)
+		for {
+			packet, data := readPacket(r)
+			switch packet & 0xF0 {
+			case 0x00:
+				return
+			case 0x30:
+				n := int(binary.BigEndian.Uint16(data))
+				messages <- message{string(data[2 : 2+n]), string(data[2+n:])}
+			}
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func readPacket(r *bufio.Reader) (byte, []byte) {
+	packet, err := r.ReadByte()
+	if err != nil {
+		return 0, nil
+	}
+	length := 0
+	for shift := uint(0); ; shift += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil
+		}
+		length |= int(b&0x7F) << shift
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil
+	}
+	return packet, data
+}
+
+func publish(w io.Writer, topic, payload string) {
+	data := append([]byte{byte(len(topic) >> 8), byte(len(topic))}, topic...)
+	data = append(data, payload...)
+	header := []byte{0x30}
+	for n := len(data); ; {
+		b := byte(n & 0x7F)
+		if n >>= 7; n > 0 {
+			header = append(header, b|0x80)
+		} else {
+			header = append(header, b)
+			break
+		}
+	}
+	w.Write(append(header, data...))
+}
@@ -0,0 +1,297 @@
+package esp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://tasmota.github.io/docs/MQTT/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// tasmotaConfig is published to <discovery>/<mac>/config
+type tasmotaConfig struct {
+	Addr      string    `json:"ip"`
+	Name      string    `json:"dn"`
+	Names     []*string `json:"fn"`
+	Hostname  string    `json:"hn"`
+	Mac       string    `json:"mac"`
+	Model     string    `json:"md"`
+	Version   string    `json:"sw"`
+	Topic     string    `json:"t"`
+	FullTopic string    `json:"ft"`
+	Prefixes  []string  `json:"tp"`
+	Relays    []int     `json:"rl"`
+	Online    string    `json:"onln"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	tasmotaRelay = 1
+	tasmotaLight = 2
+)
+
+var (
+	// Units for sensor values, where temperature and pressure
+	// units are reported with the values
+	tasmotaUnits = map[string]string{
+		"Humidity":      "%",
+		"Illuminance":   "lx",
+		"Power":         "W",
+		"ApparentPower": "VA",
+		"ReactivePower": "var",
+		"Voltage":       "V",
+		"Current":       "A",
+		"Total":         "kWh",
+		"Today":         "kWh",
+		"Yesterday":     "kWh",
+		"CarbonDioxide": "ppm",
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// tasmotaDiscovery handles configuration and sensors published
+// by a device
+func (this *manager) tasmotaDiscovery(mac, kind string, data []byte) error {
+	switch kind {
+	case "config":
+		if len(data) == 0 {
+			this.removeDevice(mac)
+			return nil
+		} else {
+			return this.tasmotaConfig(mac, data)
+		}
+	case "sensors":
+		var sensors struct {
+			Sensors map[string]interface{} `json:"sn"`
+		}
+		if device := this.device(mac); device == nil || len(data) == 0 {
+			return nil
+		} else if err := json.Unmarshal(data, &sensors); err != nil {
+			return err
+		} else {
+			return this.tasmotaSensors(device, sensors.Sensors)
+		}
+	default:
+		return nil
+	}
+}
+
+// tasmotaConfig adds or updates a device and subscribes to its topics
+func (this *manager) tasmotaConfig(mac string, data []byte) error {
+	var config tasmotaConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	} else if len(config.Prefixes) < 3 || config.FullTopic == "" || config.Topic == "" {
+		return gopi.ErrUnexpectedResponse.WithPrefix("Tasmota: ", mac)
+	}
+
+	// Add device
+	device, added := this.addDevice(mac, config.Name, gopi.ESP_FIRMWARE_TASMOTA)
+
+	// Set properties and topics
+	topic := func(prefix string) string {
+		id := config.Mac
+		if len(id) > 6 {
+			id = id[len(id)-6:]
+		}
+		topic := strings.NewReplacer("%prefix%", prefix, "%topic%", config.Topic, "%hostname%", config.Hostname, "%id%", id).Replace(config.FullTopic)
+		if strings.HasSuffix(topic, "/") == false {
+			topic += "/"
+		}
+		return topic
+	}
+	device.RWMutex.Lock()
+	device.name, device.model, device.version = config.Name, config.Model, config.Version
+	device.addr = net.ParseIP(config.Addr)
+	device.cmnd, device.stat, device.tele = topic(config.Prefixes[0]), topic(config.Prefixes[1]), topic(config.Prefixes[2])
+	device.lwtOnline = config.Online
+	if device.lwtOnline == "" {
+		device.lwtOnline = "Online"
+	}
+	device.RWMutex.Unlock()
+
+	// Add relays and lights, which are numbered from one
+	for i, relay := range config.Relays {
+		id := fmt.Sprint("POWER", i+1)
+		name := id
+		if i < len(config.Names) && config.Names[i] != nil && *config.Names[i] != "" {
+			name = *config.Names[i]
+		}
+		switch relay {
+		case tasmotaRelay:
+			device.addEntity(newEntity(id, name, gopi.ESP_ENTITY_SWITCH))
+		case tasmotaLight:
+			device.addEntity(newEntity(id, name, gopi.ESP_ENTITY_LIGHT))
+		}
+	}
+
+	// Subscribe to state and telemetry
+	if err := this.subscribe(device.stat+"#", device.tele+"#"); err != nil {
+		return err
+	}
+
+	// Emit event when added
+	if added {
+		return this.emit(NewEvent(gopi.ESP_EVENT_ADDED, device, nil))
+	} else {
+		return nil
+	}
+}
+
+// tasmotaMessage handles state and telemetry, and returns false if
+// the topic is not for the device
+func (this *manager) tasmotaMessage(device *device, topic string, data []byte) (bool, error) {
+	device.RWMutex.RLock()
+	stat, tele, online := device.stat, device.tele, device.lwtOnline
+	device.RWMutex.RUnlock()
+
+	switch {
+	case stat != "" && strings.HasPrefix(topic, stat):
+		topic = strings.TrimPrefix(topic, stat)
+		if topic == "RESULT" {
+			return true, this.tasmotaState(device, data)
+		} else if strings.HasPrefix(topic, "POWER") {
+			return true, this.tasmotaPower(device, topic, string(data))
+		}
+		return true, nil
+	case tele != "" && strings.HasPrefix(topic, tele):
+		switch strings.TrimPrefix(topic, tele) {
+		case "LWT":
+			return true, this.setOnline(device, string(data) == online)
+		case "STATE":
+			return true, this.tasmotaState(device, data)
+		case "SENSOR":
+			var sensors map[string]interface{}
+			if err := json.Unmarshal(data, &sensors); err != nil {
+				return true, err
+			} else {
+				return true, this.tasmotaSensors(device, sensors)
+			}
+		}
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// tasmotaState sets power and dimmer from a JSON object
+func (this *manager) tasmotaState(device *device, data []byte) error {
+	var state map[string]interface{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	for key, value := range state {
+		if strings.HasPrefix(key, "POWER") {
+			if value, ok := value.(string); ok {
+				if err := this.tasmotaPower(device, key, value); err != nil {
+					return err
+				}
+			}
+		} else if key == "Dimmer" {
+			if value, ok := value.(float64); ok {
+				for _, light := range device.ofType(gopi.ESP_ENTITY_LIGHT) {
+					if light.setBrightness(float32(value / 100)) {
+						if err := this.emit(NewEvent(gopi.ESP_EVENT_CHANGED, device, light)); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// tasmotaPower sets the state of a relay or light, where POWER is
+// the same as POWER1
+func (this *manager) tasmotaPower(device *device, key, value string) error {
+	if key == "POWER" {
+		key = "POWER1"
+	}
+	if entity := device.entity(key); entity == nil {
+		return nil
+	} else if entity.setState(value == "ON") {
+		return this.emit(NewEvent(gopi.ESP_EVENT_CHANGED, device, entity))
+	} else {
+		return nil
+	}
+}
+
+// tasmotaSensors sets sensor values, adding sensors which have not
+// been reported before. Values are nested under the name of a sensor
+func (this *manager) tasmotaSensors(device *device, sensors map[string]interface{}) error {
+	// Sort sensor names so entities are added in order
+	keys := make([]string, 0, len(sensors))
+	for key := range sensors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, sensor := range keys {
+		values, ok := sensors[sensor].(map[string]interface{})
+		if ok == false {
+			continue
+		}
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			value, ok := values[name].(float64)
+			if ok == false {
+				continue
+			}
+			id := sensor + "/" + name
+			entity := device.entity(id)
+			if entity == nil {
+				entity = newEntity(id, sensor+" "+name, gopi.ESP_ENTITY_SENSOR)
+				entity.unit = tasmotaUnit(name, sensors)
+				device.addEntity(entity)
+			}
+			if entity.setValue(value) {
+				if err := this.emit(NewEvent(gopi.ESP_EVENT_CHANGED, device, entity)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// tasmotaCommand publishes a command to a device
+func (this *manager) tasmotaCommand(device *device, command, value string) error {
+	device.RWMutex.RLock()
+	cmnd := device.cmnd
+	device.RWMutex.RUnlock()
+	return this.publish(cmnd+command, []byte(value))
+}
+
+func tasmotaUnit(name string, sensors map[string]interface{}) string {
+	switch name {
+	case "Temperature", "DewPoint":
+		if unit, ok := sensors["TempUnit"].(string); ok {
+			return "°" + unit
+		}
+	case "Pressure", "SeaPressure":
+		if unit, ok := sensors["PressureUnit"].(string); ok {
+			return unit
+		}
+	}
+	return tasmotaUnits[name]
+}
@@ -27,6 +27,7 @@ type Cast struct {
 	st     uint
 	ips    []net.IP
 	port   uint16
+	iface  string
 
 	// State information
 	volume *Volume
@@ -36,6 +37,11 @@ type Cast struct {
 
 	// Promises
 	callbacks map[int]promise
+
+	// Volume ramp in progress, and whether volume has been restored
+	// since connection
+	ramp     chan struct{}
+	restored bool
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -44,6 +50,7 @@ type Cast struct {
 const (
 	promiseTimeout = 2 * time.Second
 	pingTimeout    = 20 * time.Second
+	rampInterval   = 100 * time.Millisecond
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -63,6 +70,9 @@ func NewCastFromRecord(r gopi.ServiceRecord) *Cast {
 	} else {
 		this.ips = ips
 	}
+	if r, ok := r.(gopi.ServiceIface); ok {
+		this.iface = r.Iface()
+	}
 
 	// Set properties
 	tuples := txtToMap(r.Txt())
@@ -98,11 +108,21 @@ func (this *Cast) ConnectWithTimeout(timeout time.Duration, state chan<- state)
 		return gopi.ErrNotFound.WithPrefix("ConnectWithTimeout", "No Address")
 	}
 
-	// Use first IP
-	// TODO: Use a random IP and retry with other IP's if not working
-	addr := fmt.Sprintf("%v:%v", this.ips[0], this.port)
-	if err := this.connection.Connect(this.Id(), addr, timeout, state); err != nil {
-		return err
+	// Try each address in turn until one connects
+	var result error
+	for _, ip := range this.ips {
+		host := ip.String()
+		if ip.To4() == nil && ip.IsLinkLocalUnicast() && this.iface != "" {
+			host += "%" + this.iface
+		}
+		addr := net.JoinHostPort(host, fmt.Sprint(this.port))
+		if result = this.connection.Connect(this.Id(), addr, timeout, state); result == nil {
+			break
+		}
+		this.connection.Disconnect()
+	}
+	if result != nil {
+		return result
 	}
 
 	// Lock for setting state
@@ -114,6 +134,7 @@ func (this *Cast) ConnectWithTimeout(timeout time.Duration, state chan<- state)
 	this.app = nil
 	this.media = nil
 	this.player = nil
+	this.restored = false
 
 	// Init promises
 	this.promises.InitWithTimeout(promiseTimeout)
@@ -128,6 +149,9 @@ func (this *Cast) Disconnect() error {
 
 	var result error
 
+	// Stop any volume ramp
+	this.stopRamp()
+
 	// Disconnect
 	if err := this.connection.Disconnect(); err != nil {
 		result = multierror.Append(result, err)
@@ -191,31 +215,43 @@ func (this *Cast) State() uint {
 // STATE
 
 func (this *Cast) UpdateState() error {
-	// Update status for volume and app
+	// Make requests for volume, app and media status
+	var requests [][]byte
+	var result error
 	this.RWMutex.RLock()
 	if this.volume == nil || this.app == nil {
 		this.Debugf("Requesting Volume and App State")
 		if _, data, err := this.channel.GetStatus(); err != nil {
-			return err
-		} else if err := this.send(data); err != nil {
-			return err
+			result = err
+		} else {
+			requests = append(requests, data)
 		}
 	} else if this.player == nil && this.app.TransportId != "" && this.app.IsIdleScreen == false {
 		this.Debugf("Connecting Media")
 		if _, data, err := this.channel.ConnectMedia(this.app.TransportId); err != nil {
-			return err
-		} else if err := this.send(data); err != nil {
-			return err
+			result = err
+		} else {
+			requests = append(requests, data)
 		}
 
 		this.Debugf("Get Media Status")
 		if _, data, err := this.channel.GetMediaStatus(this.app.TransportId); err != nil {
-			return err
-		} else if err := this.send(data); err != nil {
-			return err
+			result = err
+		} else {
+			requests = append(requests, data)
 		}
 	}
 	this.RWMutex.RUnlock()
+	if result != nil {
+		return result
+	}
+
+	// Send requests without holding the lock
+	for _, data := range requests {
+		if err := this.send(data); err != nil {
+			return err
+		}
+	}
 
 	// If no ping/pong has been done recently then disconnect
 	if this.channel.ping.IsZero() == false && time.Since(this.channel.ping) > pingTimeout {
@@ -355,6 +391,63 @@ func (this *Cast) ReqVolumeLevel(level float32) error {
 	return nil
 }
 
+// ReqVolumeRamp steps the volume from the current level to a target level
+// over a duration, replacing any ramp in progress. When the duration is
+// too short to ramp, the volume is set and any error is returned, otherwise
+// the ramp continues in the background and the first error ends the ramp
+func (this *Cast) ReqVolumeRamp(target float32, duration time.Duration) error {
+	this.RWMutex.Lock()
+
+	// Clamp value between 0.0 and 1.0
+	if target < 0.0 {
+		target = 0.0
+	} else if target > 1.0 {
+		target = 1.0
+	}
+
+	// Stop any existing ramp
+	this.stopRamp()
+
+	// Start from the current level, or unmuted at zero
+	from := float32(0)
+	if this.volume != nil && this.volume.Muted == false {
+		from = this.volume.Level
+	}
+
+	// Set volume directly when there is no duration, without holding
+	// the lock
+	steps := int(duration / rampInterval)
+	if steps <= 1 || from == target {
+		this.RWMutex.Unlock()
+		return this.ReqVolumeLevel(target)
+	}
+
+	// Ramp in the background
+	stop := make(chan struct{})
+	this.ramp = stop
+	this.RWMutex.Unlock()
+	go func() {
+		if err := rampVolume(stop, rampInterval, from, target, steps, this.ReqVolumeLevel); err != nil {
+			this.Debugf("ReqVolumeRamp: %v", err)
+		}
+		this.RWMutex.Lock()
+		if this.ramp == stop {
+			this.ramp = nil
+		}
+		this.RWMutex.Unlock()
+	}()
+
+	// Return success
+	return nil
+}
+
+// IsRamping returns true if a volume ramp is in progress
+func (this *Cast) IsRamping() bool {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.ramp != nil
+}
+
 func (this *Cast) ReqMuted(muted bool) error {
 	this.RWMutex.RLock()
 	defer this.RWMutex.RUnlock()
@@ -575,6 +668,34 @@ func (this *Cast) String() string {
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
+// stopRamp ends any volume ramp in progress, and should be called
+// while holding the lock
+// rampVolume sets the volume in steps from one level to another, with an
+// interval between each step, until the last step or the stop channel is
+// closed. The first error ends the ramp and is returned
+func rampVolume(stop <-chan struct{}, interval time.Duration, from, target float32, steps int, fn func(float32) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for step := 1; step <= steps; step++ {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			if err := fn(from + (target-from)*float32(step)/float32(steps)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (this *Cast) stopRamp() {
+	if this.ramp != nil {
+		close(this.ramp)
+		this.ramp = nil
+	}
+}
+
 func txtToMap(txt []string) map[string]string {
 	result := make(map[string]string, len(txt))
 	for _, r := range txt {
@@ -0,0 +1,97 @@
+package googlecast
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+const (
+	testInterval = 10 * time.Millisecond
+)
+
+func Test_Ramp_001(t *testing.T) {
+	// Each step moves the level towards the target, ending at the target
+	var levels []float32
+	if err := rampVolume(nil, testInterval, 0.2, 0.6, 4, func(level float32) error {
+		levels = append(levels, level)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []float32{0.3, 0.4, 0.5, 0.6}
+	if len(levels) != len(expected) {
+		t.Fatal("Unexpected levels", levels)
+	}
+	for i := range levels {
+		if diff := levels[i] - expected[i]; diff > 0.0001 || diff < -0.0001 {
+			t.Error("Unexpected levels", levels)
+		}
+	}
+}
+
+func Test_Ramp_002(t *testing.T) {
+	// Closing the stop channel ends the ramp without error
+	stop := make(chan struct{})
+	var levels []float32
+	if err := rampVolume(stop, testInterval, 1, 0, 100, func(level float32) error {
+		if levels = append(levels, level); len(levels) == 3 {
+			close(stop)
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	} else if len(levels) != 3 || levels[2] >= levels[0] {
+		t.Error("Unexpected levels", levels)
+	}
+}
+
+func Test_Ramp_003(t *testing.T) {
+	// The first error ends the ramp and is returned
+	calls := 0
+	if err := rampVolume(nil, testInterval, 0, 1, 10, func(level float32) error {
+		if calls++; calls == 2 {
+			return errors.New("failed")
+		}
+		return nil
+	}); err == nil || calls != 2 {
+		t.Error("Expected error on second step", calls, err)
+	}
+}
+
+func Test_Ramp_004(t *testing.T) {
+	// Without a duration the volume is set directly, and an error is
+	// returned when the cast is not connected
+	cast := new(Cast)
+	if err := cast.ReqVolumeRamp(0.5, 0); errors.Is(err, gopi.ErrOutOfOrder) == false {
+		t.Error("Expected ErrOutOfOrder, got", err)
+	} else if cast.IsRamping() {
+		t.Error("Unexpected ramp")
+	}
+
+	// A ramp runs in the background, replaces an existing ramp, and
+	// ends on the first error
+	if err := cast.ReqVolumeRamp(0.5, 10*rampInterval); err != nil {
+		t.Error(err)
+	} else if cast.IsRamping() == false {
+		t.Error("Expected ramp")
+	}
+	cast.RWMutex.RLock()
+	first := cast.ramp
+	cast.RWMutex.RUnlock()
+	if err := cast.ReqVolumeRamp(0.8, 10*rampInterval); err != nil {
+		t.Error(err)
+	}
+	select {
+	case <-first:
+		break
+	default:
+		t.Error("Expected first ramp to be stopped")
+	}
+	time.Sleep(3 * rampInterval)
+	if cast.IsRamping() {
+		t.Error("Expected ramp to end on error")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	gopi "github.com/djthorpe/gopi/v3"
+	retry "github.com/djthorpe/gopi/v3/pkg/retry"
 	multierror "github.com/hashicorp/go-multierror"
 )
 
@@ -23,11 +24,20 @@ type Manager struct {
 	sync.RWMutex
 	gopi.Unit
 	gopi.ServiceDiscovery
+	gopi.DeviceRegistry
 	gopi.Publisher
 	gopi.Logger
 
-	// Connected Cast Devices
-	dev map[string]*Cast
+	// Connected Cast Devices, and devices which are connecting
+	dev        map[string]*Cast
+	connecting map[string]bool
+
+	// Volume persistence and ramp duration on reconnect
+	volumes volumes
+	path    *string
+	ramp    *time.Duration
+	retry   *retry.Flags
+	backoff *retry.Backoff
 
 	// Channels for communication
 	state chan state
@@ -44,13 +54,33 @@ const (
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
+func (this *Manager) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagPath("googlecast.volumes", "", "Path to file storing device volumes")
+	this.ramp = cfg.FlagDuration("googlecast.ramp", 2*time.Second, "Duration of volume ramp when restoring volume")
+	this.retry = retry.Define(cfg, "googlecast", 500*time.Millisecond, 10*time.Second, 3)
+	return nil
+}
+
 func (this *Manager) New(gopi.Config) error {
 	if this.ServiceDiscovery == nil {
 		return gopi.ErrInternalAppError.WithPrefix("ServiceDiscovery")
 	}
 
+	// Read device volumes
+	if err := this.volumes.Read(*this.path); err != nil {
+		return err
+	}
+
+	// Backoff for connecting to devices
+	if backoff, err := this.retry.New(); err != nil {
+		return err
+	} else {
+		this.backoff = backoff
+	}
+
 	// Make map of devices and error channel
 	this.dev = make(map[string]*Cast)
+	this.connecting = make(map[string]bool)
 	this.state = make(chan state)
 
 	// Return success
@@ -74,6 +104,7 @@ func (this *Manager) Dispose() error {
 
 	// Release resources
 	this.dev = nil
+	this.connecting = nil
 	this.state = nil
 
 	// Return any errors
@@ -109,8 +140,8 @@ func (this *Manager) Devices(ctx context.Context) ([]gopi.Cast, error) {
 	this.RWMutex.RLock()
 	defer this.RWMutex.RUnlock()
 
-	// Perform the lookup
-	records, err := this.ServiceDiscovery.Lookup(ctx, serviceTypeCast)
+	// Perform the lookup, or use devices from the registry
+	records, err := this.lookup(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -131,33 +162,40 @@ func (this *Manager) Devices(ctx context.Context) ([]gopi.Cast, error) {
 }
 
 func (this *Manager) Connect(device gopi.Cast) error {
-	this.RWMutex.Lock()
-	defer this.RWMutex.Unlock()
-
 	// Check for bad parameters
 	if device == nil {
 		return gopi.ErrBadParameter.WithPrefix("Connect")
 	}
+	device_, ok := device.(*Cast)
+	if ok == false {
+		return gopi.ErrInternalAppError.WithPrefix("Connect")
+	}
 
-	// Check for already connected
+	// Reserve the device, so that only one caller connects to it
 	key := device.Id()
-	if _, exists := this.dev[key]; exists {
+	this.RWMutex.Lock()
+	if _, exists := this.dev[key]; exists || this.connecting[key] {
+		this.RWMutex.Unlock()
 		return gopi.ErrDuplicateEntry.WithPrefix("Connect")
 	}
+	this.connecting[key] = true
+	this.RWMutex.Unlock()
 
-	// Do the connection
-	if device_, ok := device.(*Cast); ok == false {
-		return gopi.ErrInternalAppError.WithPrefix("Connect")
-	} else if err := this.connect(device_); err != nil {
-		return err
-	} else {
+	// Do the connection without holding the lock, then add the device
+	err := this.connect(device_)
+	this.RWMutex.Lock()
+	delete(this.connecting, key)
+	if err == nil {
 		this.dev[key] = device_
 	}
+	this.RWMutex.Unlock()
+	if err != nil {
+		return err
+	}
 
 	// Emit connect
 	if this.Publisher != nil {
-		cast := this.dev[key]
-		this.Publisher.Emit(NewEvent(cast, nil, nil, gopi.CAST_FLAG_CONNECT, 0), true)
+		this.Publisher.Emit(NewEvent(device_, nil, nil, gopi.CAST_FLAG_CONNECT, 0), true)
 	}
 
 	// Return success
@@ -165,29 +203,31 @@ func (this *Manager) Connect(device gopi.Cast) error {
 }
 
 func (this *Manager) Disconnect(device gopi.Cast) error {
-	this.RWMutex.Lock()
-	defer this.RWMutex.Unlock()
-
-	var result error
 	if device == nil {
 		return gopi.ErrBadParameter.WithPrefix("Disconnect")
 	}
 
+	// Remove device from list of devices
 	key := device.Id()
-	if connected, exists := this.dev[key]; exists == false {
+	this.RWMutex.Lock()
+	connected, exists := this.dev[key]
+	delete(this.dev, key)
+	this.RWMutex.Unlock()
+	if exists == false {
 		return gopi.ErrNotFound.WithPrefix("Disconnect")
-	} else if err := this.disconnect(connected); err != nil {
+	}
+
+	// Disconnect without holding the lock
+	var result error
+	if err := this.disconnect(connected); err != nil {
 		result = multierror.Append(result, err)
 	}
 
 	// Emit disconnect
 	if this.Publisher != nil {
-		this.Publisher.Emit(NewEvent(this.dev[key], nil, nil, gopi.CAST_FLAG_DISCONNECT, 0), true)
+		this.Publisher.Emit(NewEvent(connected, nil, nil, gopi.CAST_FLAG_DISCONNECT, 0), true)
 	}
 
-	// Remove device from list of devices
-	delete(this.dev, key)
-
 	// Return any errors
 	return result
 }
@@ -275,6 +315,35 @@ func (this *Manager) SetVolume(cast gopi.Cast, value float32) error {
 	}
 }
 
+// SetVolumeRamp steps the volume for a device to a level over a duration
+func (this *Manager) SetVolumeRamp(cast gopi.Cast, value float32, duration time.Duration) error {
+	if cast == nil {
+		return gopi.ErrBadParameter.WithPrefix("SetVolumeRamp")
+	}
+
+	if device := this.getConnectedDevice(cast); device == nil {
+		if err := this.Connect(cast); err != nil {
+			return err
+		}
+	}
+
+	if device := this.getConnectedDevice(cast); device == nil {
+		return gopi.ErrNotFound.WithPrefix("SetVolumeRamp")
+	} else {
+		return device.ReqVolumeRamp(value, duration)
+	}
+}
+
+// SetDefaultVolume sets the volume which is restored when a device
+// is connected, or zero to restore the last volume instead
+func (this *Manager) SetDefaultVolume(cast gopi.Cast, value float32) error {
+	if cast == nil || value < 0.0 || value > 1.0 {
+		return gopi.ErrBadParameter.WithPrefix("SetDefaultVolume")
+	} else {
+		return this.volumes.SetDefault(cast.Id(), value)
+	}
+}
+
 func (this *Manager) SetMuted(cast gopi.Cast, value bool) error {
 	if cast == nil {
 		return gopi.ErrBadParameter.WithPrefix("SetMuted")
@@ -451,8 +520,15 @@ func (this *Manager) disconnect(device *Cast) error {
 	return device.Disconnect()
 }
 
+// connect to a device, retrying when the connection fails
 func (this *Manager) connect(device *Cast) error {
-	return device.ConnectWithTimeout(serviceConnectTimeout, this.state)
+	return retry.Do(context.Background(), this.backoff.Clone(), func(context.Context) error {
+		if err := device.ConnectWithTimeout(serviceConnectTimeout, this.state); errors.Is(err, gopi.ErrNotFound) {
+			return retry.Permanent(err)
+		} else {
+			return err
+		}
+	})
 }
 
 func (this *Manager) getConnectedDevice(cast gopi.Cast) *Cast {
@@ -466,12 +542,11 @@ func (this *Manager) getConnectedDevice(cast gopi.Cast) *Cast {
 	}
 }
 
+// updateStatus requests status from connected devices, without holding
+// the lock while writing to the network
 func (this *Manager) updateStatus() error {
-	this.RWMutex.RLock()
-	defer this.RWMutex.RUnlock()
-
 	var result error
-	for _, device := range this.dev {
+	for _, device := range this.connectedDevices() {
 		if device.isConnected() == false {
 			// ignore device
 		} else if err := device.UpdateState(); err != nil {
@@ -481,14 +556,26 @@ func (this *Manager) updateStatus() error {
 	return result
 }
 
-func (this *Manager) setState(s state) error {
+// connectedDevices returns the devices which have been connected
+func (this *Manager) connectedDevices() []*Cast {
 	this.RWMutex.RLock()
 	defer this.RWMutex.RUnlock()
+	devices := make([]*Cast, 0, len(this.dev))
+	for _, device := range this.dev {
+		devices = append(devices, device)
+	}
+	return devices
+}
 
+// setState sets state on a device and emits any change, without holding
+// the lock while emitting
+func (this *Manager) setState(s state) error {
 	// Find device to change state on
+	this.RWMutex.RLock()
 	device, exists := this.dev[s.key]
+	this.RWMutex.RUnlock()
 	if exists == false {
-		this.Debug("Debug: ", device.Id(), ": ", gopi.ErrNotFound)
+		this.Debug("Debug: ", s.key, ": ", gopi.ErrNotFound)
 		return nil
 	}
 
@@ -515,9 +602,20 @@ func (this *Manager) setState(s state) error {
 	}
 
 	// Set state in device
-	if flags, err := device.SetState(s); err != nil {
+	flags, err := device.SetState(s)
+	if err != nil {
 		return err
-	} else if flags != gopi.CAST_FLAG_NONE && this.Publisher != nil {
+	}
+
+	// Restore preferred volume on connection, and then store changes
+	if flags&gopi.CAST_FLAG_VOLUME != 0 {
+		if err := this.setVolume(device); err != nil {
+			this.Print("Volume: ", device.Id(), ": ", err)
+		}
+	}
+
+	// Emit changes
+	if flags != gopi.CAST_FLAG_NONE && this.Publisher != nil {
 		this.Publisher.Emit(NewEvent(device, device.app, device.volume, flags, s.req), true)
 
 		if flags&gopi.CAST_FLAG_APP != 0 {
@@ -535,6 +633,29 @@ func (this *Manager) setState(s state) error {
 	return nil
 }
 
+// setVolume ramps to the preferred volume on the first volume state after
+// connection, and otherwise stores the volume as the last volume
+func (this *Manager) setVolume(device *Cast) error {
+	device.RWMutex.Lock()
+	key, restored, volume := device.id, device.restored, device.volume
+	device.restored = true
+	device.RWMutex.Unlock()
+
+	if volume == nil {
+		return nil
+	} else if restored == false {
+		if level, exists := this.volumes.Preferred(key); exists && (volume.Muted || level != volume.Level) {
+			this.Debug("Vol:", key, "=> restore ", level)
+			return device.ReqVolumeRamp(level, *this.ramp)
+		}
+	} else if device.IsRamping() == false && volume.Muted == false && volume.Level > 0 {
+		return this.volumes.SetLast(key, volume.Level)
+	}
+
+	// Return success
+	return nil
+}
+
 func isDroppedConnection(err error) bool {
 	if errors.Is(err, syscall.ECONNABORTED) {
 		return true
@@ -545,3 +666,20 @@ func isDroppedConnection(err error) bool {
 	}
 	return false
 }
+
+// lookup returns cast service records from the device registry when it
+// has any, or else from service discovery
+func (this *Manager) lookup(ctx context.Context) ([]gopi.ServiceRecord, error) {
+	if this.DeviceRegistry != nil {
+		var records []gopi.ServiceRecord
+		for _, device := range this.DeviceRegistry.Devices("") {
+			if device.Service() == serviceTypeCast {
+				records = append(records, device)
+			}
+		}
+		if len(records) > 0 {
+			return records, nil
+		}
+	}
+	return this.ServiceDiscovery.Lookup(ctx, serviceTypeCast)
+}
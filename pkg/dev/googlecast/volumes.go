@@ -0,0 +1,128 @@
+package googlecast
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// volumes stores the last and default volume for each device, so that
+// the preferred volume is restored when a device is reconnected
+type volumes struct {
+	sync.Mutex
+
+	path    string
+	Devices map[string]*volume `json:"devices"`
+}
+
+type volume struct {
+	Last    float32 `json:"last"`
+	Default float32 `json:"default,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Read volumes from a file, which is created when volumes are written.
+// When path is empty, volumes are not persisted
+func (this *volumes) Read(path string) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	this.path = path
+	this.Devices = make(map[string]*volume)
+
+	if path == "" {
+		return nil
+	} else if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	} else if fh, err := os.Open(path); err != nil {
+		return err
+	} else {
+		defer fh.Close()
+		if err := json.NewDecoder(fh).Decode(this); err != nil {
+			return err
+		}
+	}
+
+	// Success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// Preferred returns the default volume for a device, or else the
+// last volume, and false if neither are known
+func (this *volumes) Preferred(key string) (float32, bool) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if v, exists := this.Devices[key]; exists == false {
+		return 0, false
+	} else if v.Default > 0 {
+		return v.Default, true
+	} else if v.Last > 0 {
+		return v.Last, true
+	} else {
+		return 0, false
+	}
+}
+
+// SetLast sets the last volume for a device and writes the file
+// when it has changed
+func (this *volumes) SetLast(key string, level float32) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if v, exists := this.Devices[key]; exists && v.Last == level {
+		return nil
+	} else if exists {
+		v.Last = level
+	} else {
+		this.Devices[key] = &volume{Last: level}
+	}
+	return this.write()
+}
+
+// SetDefault sets the default volume for a device, or removes
+// it when level is zero, and writes the file
+func (this *volumes) SetDefault(key string, level float32) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if v, exists := this.Devices[key]; exists {
+		v.Default = level
+	} else {
+		this.Devices[key] = &volume{Default: level}
+	}
+	return this.write()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// write volumes through a temporary file so the file is
+// never partially written
+func (this *volumes) write() error {
+	if this.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(this.path), 0700); err != nil {
+		return err
+	}
+	tmp := this.path + ".tmp"
+	if fh, err := os.Create(tmp); err != nil {
+		return err
+	} else if err := json.NewEncoder(fh).Encode(this); err != nil {
+		fh.Close()
+		return err
+	} else if err := fh.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, this.path)
+}
@@ -0,0 +1,91 @@
+package googlecast
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Volumes_001(t *testing.T) {
+	// Volumes are not persisted without a path
+	var v volumes
+	if err := v.Read(""); err != nil {
+		t.Fatal(err)
+	} else if _, exists := v.Preferred("a"); exists {
+		t.Error("Unexpected volume")
+	} else if err := v.SetLast("a", 0.5); err != nil {
+		t.Error(err)
+	} else if level, exists := v.Preferred("a"); exists == false || level != 0.5 {
+		t.Error("Unexpected volume", level)
+	}
+}
+
+func Test_Volumes_002(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "volumes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	path := filepath.Join(tmp, "cast", "volumes.json")
+
+	// A missing file is created when volumes are written
+	var v volumes
+	if err := v.Read(path); err != nil {
+		t.Fatal(err)
+	} else if err := v.SetLast("a", 0.4); err != nil {
+		t.Fatal(err)
+	} else if err := v.SetLast("b", 0.3); err != nil {
+		t.Fatal(err)
+	} else if err := v.SetDefault("b", 0.6); err != nil {
+		t.Fatal(err)
+	} else if _, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if _, err := os.Stat(path + ".tmp"); os.IsNotExist(err) == false {
+		t.Error("Unexpected temporary file")
+	}
+
+	// Volumes are read back, and the default is preferred over the last volume
+	var v2 volumes
+	if err := v2.Read(path); err != nil {
+		t.Fatal(err)
+	} else if level, exists := v2.Preferred("a"); exists == false || level != 0.4 {
+		t.Error("Unexpected volume for a", level)
+	} else if level, exists := v2.Preferred("b"); exists == false || level != 0.6 {
+		t.Error("Unexpected volume for b", level)
+	}
+
+	// Removing the default returns the last volume
+	if err := v2.SetDefault("b", 0); err != nil {
+		t.Error(err)
+	} else if level, _ := v2.Preferred("b"); level != 0.3 {
+		t.Error("Unexpected volume for b", level)
+	}
+
+	// An unchanged last volume does not write the file
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	} else if err := v2.SetLast("a", 0.4); err != nil {
+		t.Error(err)
+	} else if _, err := os.Stat(path); os.IsNotExist(err) == false {
+		t.Error("Unexpected write for unchanged volume")
+	}
+}
+
+func Test_Volumes_003(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "volumes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	path := filepath.Join(tmp, "volumes.json")
+
+	// An invalid file returns an error
+	if err := ioutil.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var v volumes
+	if err := v.Read(path); err == nil {
+		t.Error("Expected error for invalid file")
+	}
+}
@@ -0,0 +1,114 @@
+package gps
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// decoder splits data from a receiver into NMEA sentences and UBX
+// frames, which can be mixed on the same port
+type decoder struct {
+	nmea
+	buf []byte
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	nmeaMaxLength = 128
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Decode appends data and calls a function for each complete position.
+// Incomplete data is retained until the next call, and data which cannot
+// be decoded is discarded and returned as an error
+func (this *decoder) Decode(data []byte, fn func(gopi.GPSPosition)) error {
+	var result error
+
+	this.buf = append(this.buf, data...)
+	for {
+		// Discard data until the start of a sentence or frame
+		if i := start(this.buf); i < 0 {
+			this.buf = this.buf[:0]
+			return result
+		} else {
+			this.buf = this.buf[i:]
+		}
+
+		// Decode sentence or frame, or wait for more data
+		n, err := this.decode(fn)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+		if n == 0 {
+			return result
+		} else {
+			this.buf = this.buf[n:]
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// decode returns the number of bytes consumed, which is zero when
+// more data is required
+func (this *decoder) decode(fn func(gopi.GPSPosition)) (int, error) {
+	if this.buf[0] == '$' {
+		i := bytes.IndexByte(this.buf, '\n')
+		if i < 0 && len(this.buf) > nmeaMaxLength {
+			return 1, gopi.ErrUnexpectedResponse.WithPrefix("NMEA: Sentence too long")
+		} else if i < 0 {
+			return 0, nil
+		} else if complete, err := this.nmea.Parse(string(this.buf[:i])); err != nil {
+			return i + 1, err
+		} else if complete {
+			fn(this.nmea.Position())
+		}
+		return i + 1, nil
+	}
+
+	// UBX frame
+	if len(this.buf) < ubxHeader {
+		return 0, nil
+	} else if this.buf[1] != ubxSync2 {
+		return 1, nil
+	}
+	length := int(binary.LittleEndian.Uint16(this.buf[4:]))
+	if length > ubxMaxLength {
+		return 1, gopi.ErrUnexpectedResponse.WithPrefix("UBX: Frame too long")
+	} else if len(this.buf) < ubxHeader+length+2 {
+		return 0, nil
+	}
+	n := ubxHeader + length + 2
+	frame := this.buf[2 : ubxHeader+length]
+	if a, b := ubxChecksum(frame); a != this.buf[n-2] || b != this.buf[n-1] {
+		return 1, gopi.ErrUnexpectedResponse.WithPrefix("UBX: Checksum")
+	} else if frame[0] == ubxClassNav && frame[1] == ubxIdNavPvt {
+		if pos, err := ubxNavPvt(frame[4:]); err != nil {
+			return n, err
+		} else {
+			fn(pos)
+		}
+	}
+	return n, nil
+}
+
+// start returns the index of the start of a sentence or frame, or -1
+func start(data []byte) int {
+	for i, v := range data {
+		if v == '$' || v == ubxSync1 {
+			return i
+		}
+	}
+	return -1
+}
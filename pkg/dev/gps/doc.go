@@ -0,0 +1,11 @@
+// Gps package implements gopi.GPS for GPS/GNSS receivers. NMEA sentences
+// and u-blox UBX NAV-PVT frames are read from a receiver connected by
+// RS232 or USB, or reports are read from gpsd when the -gps.gpsd flag
+// is set.
+//
+// A gopi.GPSEvent is emitted for each position, and positions with a
+// fix are emitted as a measurement when gopi.Metrics is available.
+// When the -gps.settime flag is set, the system clock is set from the
+// receiver when it drifts, and the kernel then keeps the real-time
+// clock in step.
+package gps
@@ -0,0 +1,39 @@
+package gps
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	pos gopi.GPSPosition
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(pos gopi.GPSPosition) gopi.GPSEvent {
+	return &event{pos}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "gps"
+}
+
+func (this *event) Position() gopi.GPSPosition {
+	return this.pos
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	return fmt.Sprint("<gps.event ", this.pos, ">")
+}
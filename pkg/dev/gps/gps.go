@@ -0,0 +1,283 @@
+package gps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	term "github.com/pkg/term"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type receiver struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Metrics
+	sync.RWMutex
+
+	// Flags
+	device  *string
+	baud    *uint
+	addr    *string
+	settime *bool
+	drift   *time.Duration
+
+	measurement string
+	fd          io.ReadWriteCloser
+	decoder     streamDecoder
+	pos         gopi.GPSPosition
+	valid       bool
+}
+
+// streamDecoder decodes data from a receiver or gpsd
+type streamDecoder interface {
+	Decode([]byte, func(gopi.GPSPosition)) error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	defaultBaud     = 9600
+	defaultGpsdPort = "2947"
+	readTimeout     = 100 * time.Millisecond
+	dialTimeout     = 5 * time.Second
+	reconnectDelta  = 10 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *receiver) Define(cfg gopi.Config) error {
+	this.device = cfg.FlagString("gps.device", "/dev/serial0", "GPS receiver device")
+	this.baud = cfg.FlagUint("gps.baud", defaultBaud, "RS232 speed, or zero for a USB receiver")
+	this.addr = cfg.FlagString("gps.gpsd", "", "Address of gpsd, which is used instead of the device")
+	this.settime = cfg.FlagBool("gps.settime", false, "Set system clock from the receiver")
+	this.drift = cfg.FlagDuration("gps.drift", time.Second, "Difference between system clock and receiver before the clock is set")
+	cfg.FlagString("gps.measurement", "gps", "Measurement name")
+	return nil
+}
+
+func (this *receiver) New(cfg gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if *this.drift <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-gps.drift")
+	}
+
+	// Connect to gpsd or open device
+	if *this.addr != "" {
+		if _, _, err := net.SplitHostPort(*this.addr); err != nil {
+			*this.addr = net.JoinHostPort(*this.addr, defaultGpsdPort)
+		}
+		if err := this.connect(); err != nil {
+			return err
+		}
+	} else if _, err := os.Stat(*this.device); os.IsNotExist(err) {
+		return gopi.ErrBadParameter.WithPrefix("-gps.device")
+	} else if *this.baud == 0 {
+		if fd, err := os.Open(*this.device); err != nil {
+			return err
+		} else {
+			this.fd = fd
+			this.decoder = new(decoder)
+		}
+	} else if fd, err := term.Open(*this.device, term.Speed(int(*this.baud)), term.RawMode); err != nil {
+		return err
+	} else if err := fd.SetReadTimeout(readTimeout); err != nil {
+		fd.Close()
+		return err
+	} else {
+		this.fd = fd
+		this.decoder = new(decoder)
+	}
+
+	// Define measurement
+	if measurement := cfg.GetString("gps.measurement"); measurement != "" && this.Metrics != nil {
+		if m, err := this.Metrics.NewMeasurement(measurement, "fix string, latitude float64, longitude float64, altitude float64, speed float64, satellites uint32", this.Metrics.HostTag()); err != nil {
+			return err
+		} else {
+			this.measurement = m.Name()
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *receiver) Dispose() error {
+	var result error
+	if this.fd != nil {
+		result = this.fd.Close()
+	}
+
+	// Release resources
+	this.fd = nil
+	this.decoder = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *receiver) Run(ctx context.Context) error {
+	buf := make([]byte, 1024)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+			// Reconnect to gpsd after the connection is lost
+			if this.fd == nil {
+				if err := this.connect(); err != nil {
+					this.Debug("GPS: ", err)
+					select {
+					case <-ctx.Done():
+					case <-time.After(reconnectDelta):
+					}
+				}
+				continue
+			}
+
+			// Read and decode data
+			n, err := this.read(buf)
+			received := time.Now()
+			if n > 0 {
+				if err := this.decoder.Decode(buf[:n], func(pos gopi.GPSPosition) {
+					this.position(pos, received)
+				}); err != nil {
+					this.Debug("GPS: ", err)
+				}
+			}
+			if err == nil {
+				continue
+			} else if *this.addr == "" {
+				return err
+			} else {
+				this.Print("GPS: ", err)
+				this.fd.Close()
+				this.fd = nil
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *receiver) Position() (gopi.GPSPosition, bool) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.pos, this.valid
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *receiver) String() string {
+	str := "<gps.receiver"
+	if *this.addr != "" {
+		str += fmt.Sprintf(" gpsd=%q", *this.addr)
+	} else {
+		str += fmt.Sprintf(" device=%q", *this.device)
+		if *this.baud != 0 {
+			str += fmt.Sprint(" baud=", *this.baud)
+		}
+	}
+	if pos, valid := this.Position(); valid {
+		str += fmt.Sprint(" ", pos)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// connect to gpsd and enable reports
+func (this *receiver) connect() error {
+	if conn, err := net.DialTimeout("tcp", *this.addr, dialTimeout); err != nil {
+		return err
+	} else if _, err := conn.Write([]byte(gpsdWatch)); err != nil {
+		conn.Close()
+		return err
+	} else {
+		this.fd = conn
+		this.decoder = new(gpsd)
+	}
+
+	// Return success
+	return nil
+}
+
+// read returns data from the device or gpsd, or zero bytes
+// when no data is available
+func (this *receiver) read(buf []byte) (int, error) {
+	if conn, ok := this.fd.(net.Conn); ok {
+		if err := conn.SetReadDeadline(time.Now().Add(readTimeout)); err != nil {
+			return 0, err
+		}
+		n, err := conn.Read(buf)
+		if err, ok := err.(net.Error); ok && err.Timeout() {
+			return n, nil
+		}
+		return n, err
+	}
+
+	n, err := this.fd.Read(buf)
+	if err == io.EOF {
+		if n == 0 {
+			time.Sleep(readTimeout)
+		}
+		return n, nil
+	}
+	return n, err
+}
+
+// position stores a position, emits it as an event and measurement,
+// and sets the system clock
+func (this *receiver) position(pos gopi.GPSPosition, received time.Time) {
+	this.RWMutex.Lock()
+	this.pos, this.valid = pos, true
+	this.RWMutex.Unlock()
+
+	// Emit event
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(NewEvent(pos), false); err != nil {
+			this.Debug("GPS: ", err)
+		}
+	}
+
+	// Ignore positions without a fix
+	if pos.Fix == gopi.GPS_FIX_NONE {
+		return
+	}
+
+	// Emit measurement
+	if this.measurement != "" {
+		if err := this.Metrics.Emit(this.measurement, nil, pos.Fix.String(), pos.Latitude, pos.Longitude, pos.Altitude, pos.Speed, uint32(pos.Satellites)); err != nil {
+			this.Debug("GPS: ", err)
+		}
+	}
+
+	// Set system clock when it has drifted
+	if *this.settime && pos.Time.IsZero() == false {
+		if offset := pos.Time.Sub(received); offset > *this.drift || offset < -*this.drift {
+			if err := settime(time.Now().Add(offset)); err != nil {
+				this.Print("GPS: Set clock: ", err)
+			} else {
+				this.Print("GPS: Set clock with offset ", offset.Truncate(time.Millisecond))
+			}
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package gps_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/dev/gps"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.GPS
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_GPS_001(t *testing.T) {
+	device := tempDevice(t,
+		[]byte("$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*00\r\n"), // Bad checksum
+		sentence("GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,"),
+		sentence("GNGSA,A,3,04,05,,09,12,,,24,,,,,2.5,1.3,2.1"),
+		sentence("GPRMC,123519.50,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W"),
+	)
+	defer os.Remove(device)
+
+	tool.Test(t, []string{"-gps.device", device, "-gps.baud", "0"}, new(App), func(app *App) {
+		pos := waitPosition(t, app)
+		t.Log(app.GPS)
+		if pos.Fix != gopi.GPS_FIX_3D {
+			t.Error("Unexpected fix", pos.Fix)
+		}
+		if pos.Time.Equal(time.Date(1994, 3, 23, 12, 35, 19, 500*1e6, time.UTC)) == false {
+			t.Error("Unexpected time", pos.Time)
+		}
+		if equals(pos.Latitude, 48.1173) == false || equals(pos.Longitude, 11.516667) == false {
+			t.Error("Unexpected position", pos)
+		}
+		if equals(pos.Altitude, 545.4) == false || pos.Satellites != 8 || equals(pos.HDOP, 0.9) == false {
+			t.Error("Unexpected altitude or satellites", pos)
+		}
+		if equals(pos.Speed, 11.523556) == false || equals(pos.Course, 84.4) == false {
+			t.Error("Unexpected velocity", pos)
+		}
+	})
+}
+
+func Test_GPS_002(t *testing.T) {
+	payload := make([]byte, 92)
+	binary.LittleEndian.PutUint16(payload[4:], 2024)
+	copy(payload[6:], []byte{5, 6, 7, 8, 9, 0x07})
+	payload[20], payload[21], payload[23] = 3, 0x03, 12 // 3D with corrections
+	lon := int32(-1234567890)
+	binary.LittleEndian.PutUint32(payload[24:], uint32(lon))
+	binary.LittleEndian.PutUint32(payload[28:], 512345678)
+	binary.LittleEndian.PutUint32(payload[36:], 12345)
+	binary.LittleEndian.PutUint32(payload[60:], 1500)
+	binary.LittleEndian.PutUint32(payload[64:], 9000000)
+
+	// Frame is preceded by noise and a GSV sentence, which is ignored
+	device := tempDevice(t,
+		[]byte{0x00, 0xB5, 0x00},
+		sentence("GPGSV,1,1,01,04,77,048,42"),
+		ubx(0x01, 0x07, payload),
+	)
+	defer os.Remove(device)
+
+	tool.Test(t, []string{"-gps.device", device, "-gps.baud", "0"}, new(App), func(app *App) {
+		pos := waitPosition(t, app)
+		t.Log(app.GPS)
+		if pos.Fix != gopi.GPS_FIX_DGPS {
+			t.Error("Unexpected fix", pos.Fix)
+		}
+		if pos.Time.Equal(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)) == false {
+			t.Error("Unexpected time", pos.Time)
+		}
+		if equals(pos.Latitude, 51.2345678) == false || equals(pos.Longitude, -123.456789) == false {
+			t.Error("Unexpected position", pos)
+		}
+		if equals(pos.Altitude, 12.345) == false || pos.Satellites != 12 {
+			t.Error("Unexpected altitude or satellites", pos)
+		}
+		if equals(pos.Speed, 1.5) == false || equals(pos.Course, 90) == false {
+			t.Error("Unexpected velocity", pos)
+		}
+	})
+}
+
+func Test_GPS_003(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// Serve reports after the watch command is received
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+			return
+		}
+		fmt.Fprintln(conn, `{"class":"VERSION","release":"3.22"}`)
+		fmt.Fprintln(conn, `{"class":"SKY","hdop":1.2,"satellites":[{"used":true},{"used":false},{"used":true}]}`)
+		fmt.Fprintln(conn, `{"class":"TPV","mode":3,"status":2,"time":"2024-05-06T07:08:09.250Z","lat":51.5,"lon":-0.12,"alt":95.1,"altMSL":50.2,"speed":2.5,"track":180.5}`)
+		time.Sleep(time.Second)
+	}()
+
+	tool.Test(t, []string{"-gps.gpsd", listener.Addr().String()}, new(App), func(app *App) {
+		pos := waitPosition(t, app)
+		t.Log(app.GPS)
+		if pos.Fix != gopi.GPS_FIX_DGPS {
+			t.Error("Unexpected fix", pos.Fix)
+		}
+		if pos.Time.Equal(time.Date(2024, 5, 6, 7, 8, 9, 250*1e6, time.UTC)) == false {
+			t.Error("Unexpected time", pos.Time)
+		}
+		if pos.Latitude != 51.5 || pos.Longitude != -0.12 || pos.Altitude != 50.2 {
+			t.Error("Unexpected position", pos)
+		}
+		if pos.Satellites != 2 || pos.HDOP != 1.2 || pos.Speed != 2.5 || pos.Course != 180.5 {
+			t.Error("Unexpected position", pos)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// sentence returns an NMEA sentence with checksum
+func sentence(body string) []byte {
+	var sum byte
+	for i := 0; i < len(body); i++ {
+		sum ^= body[i]
+	}
+	return []byte(fmt.Sprintf("$%s*%02X\r\n", body, sum))
+}
+
+// ubx returns a UBX frame with checksum
+func ubx(class, id byte, payload []byte) []byte {
+	frame := []byte{class, id, byte(len(payload)), byte(len(payload) >> 8)}
+	frame = append(frame, payload...)
+	var a, b byte
+	for _, v := range frame {
+		a += v
+		b += a
+	}
+	return append(append([]byte{0xB5, 0x62}, frame...), a, b)
+}
+
+func tempDevice(t *testing.T, data ...[]byte) string {
+	t.Helper()
+	fh, err := ioutil.TempFile("", "gps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+	for _, data := range data {
+		if _, err := fh.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return fh.Name()
+}
+
+func waitPosition(t *testing.T, app *App) gopi.GPSPosition {
+	t.Helper()
+	for i := 0; i < 20; i++ {
+		if pos, valid := app.GPS.Position(); valid {
+			return pos
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Error("No position received")
+	return gopi.GPSPosition{}
+}
+
+func equals(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
@@ -0,0 +1,157 @@
+package gps
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// Ref: https://gpsd.gitlab.io/gpsd/gpsd_json.html
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// gpsd decodes reports from the gpsd daemon. A position is complete
+// when a TPV report is received, and satellites and dilution are from
+// the most recent SKY report
+type gpsd struct {
+	buf        []byte
+	satellites uint
+	hdop       float64
+}
+
+type gpsdReport struct {
+	Class      string          `json:"class"`
+	Mode       uint            `json:"mode"`
+	Status     uint            `json:"status"`
+	Time       string          `json:"time"`
+	Lat        float64         `json:"lat"`
+	Lon        float64         `json:"lon"`
+	Alt        float64         `json:"alt"`
+	AltMSL     *float64        `json:"altMSL"`
+	Speed      float64         `json:"speed"`
+	Track      float64         `json:"track"`
+	HDOP       float64         `json:"hdop"`
+	USat       *uint           `json:"uSat"`
+	Satellites []gpsdSatellite `json:"satellites"`
+}
+
+type gpsdSatellite struct {
+	Used bool `json:"used"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	gpsdWatch     = "?WATCH={\"enable\":true,\"json\":true}\n"
+	gpsdMaxLength = 8192
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Decode appends data and calls a function for each complete position
+func (this *gpsd) Decode(data []byte, fn func(gopi.GPSPosition)) error {
+	var result error
+
+	this.buf = append(this.buf, data...)
+	for {
+		i := bytes.IndexByte(this.buf, '\n')
+		if i < 0 {
+			if len(this.buf) > gpsdMaxLength {
+				this.buf = this.buf[:0]
+				result = multierror.Append(result, gopi.ErrUnexpectedResponse.WithPrefix("gpsd: Report too long"))
+			}
+			return result
+		}
+		line := this.buf[:i]
+		this.buf = this.buf[i+1:]
+		if err := this.report(line, fn); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *gpsd) report(line []byte, fn func(gopi.GPSPosition)) error {
+	var report gpsdReport
+	if len(bytes.TrimSpace(line)) == 0 {
+		return nil
+	} else if err := json.Unmarshal(line, &report); err != nil {
+		return gopi.ErrUnexpectedResponse.WithPrefix("gpsd: ", err)
+	}
+
+	switch report.Class {
+	case "SKY":
+		if report.USat != nil {
+			this.satellites = *report.USat
+		} else if report.Satellites != nil {
+			this.satellites = 0
+			for _, sat := range report.Satellites {
+				if sat.Used {
+					this.satellites++
+				}
+			}
+		}
+		this.hdop = report.HDOP
+	case "TPV":
+		if pos, err := this.tpv(report); err != nil {
+			return err
+		} else {
+			fn(pos)
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// tpv returns a position from a TPV report
+func (this *gpsd) tpv(report gpsdReport) (gopi.GPSPosition, error) {
+	pos := gopi.GPSPosition{
+		Satellites: this.satellites,
+		HDOP:       this.hdop,
+	}
+	if report.Time != "" {
+		if ts, err := time.Parse(time.RFC3339Nano, report.Time); err != nil {
+			return pos, gopi.ErrUnexpectedResponse.WithPrefix("gpsd: ", report.Time)
+		} else {
+			pos.Time = ts.UTC()
+		}
+	}
+
+	// Mode is 2 for 2D and 3 for 3D, and status is 2 for DGPS
+	// and 3 or 4 for RTK
+	switch report.Mode {
+	case 2:
+		pos.Fix = gopi.GPS_FIX_2D
+	case 3:
+		pos.Fix = gopi.GPS_FIX_3D
+		if report.Status == 2 {
+			pos.Fix = gopi.GPS_FIX_DGPS
+		} else if report.Status == 3 || report.Status == 4 {
+			pos.Fix = gopi.GPS_FIX_RTK
+		}
+	default:
+		return pos, nil
+	}
+
+	// Position and velocity. Older versions of gpsd report altitude
+	// above mean sea level as alt
+	pos.Latitude, pos.Longitude = report.Lat, report.Lon
+	if report.AltMSL != nil {
+		pos.Altitude = *report.AltMSL
+	} else {
+		pos.Altitude = report.Alt
+	}
+	pos.Speed, pos.Course = report.Speed, report.Track
+
+	// Return success
+	return pos, nil
+}
@@ -0,0 +1,13 @@
+package gps
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.GPS
+	graph.RegisterUnit(reflect.TypeOf(&receiver{}), reflect.TypeOf((*gopi.GPS)(nil)))
+}
@@ -0,0 +1,254 @@
+package gps
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://gpsd.gitlab.io/gpsd/NMEA.html
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// nmea retains state between sentences. A position is complete when
+// an RMC sentence is received, and altitude, satellites and dilution
+// are from the most recent GGA sentence
+type nmea struct {
+	pos     gopi.GPSPosition
+	mode    uint // From GSA: 1 is no fix, 2 is 2D and 3 is 3D
+	quality uint // From GGA: 2 is DGPS, 4 and 5 are RTK
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	knots = 1852.0 / 3600.0 // Metres per second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Parse a sentence, and return true when a position is complete
+func (this *nmea) Parse(line string) (bool, error) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "$") == false {
+		return false, gopi.ErrUnexpectedResponse.WithPrefix("NMEA: ", strconv.Quote(line))
+	}
+
+	// Verify checksum when present
+	line = line[1:]
+	if i := strings.LastIndexByte(line, '*'); i >= 0 {
+		if sum, err := strconv.ParseUint(line[i+1:], 16, 8); err != nil {
+			return false, gopi.ErrUnexpectedResponse.WithPrefix("NMEA: ", strconv.Quote(line))
+		} else if byte(sum) != checksum(line[:i]) {
+			return false, gopi.ErrUnexpectedResponse.WithPrefix("NMEA: Checksum: ", strconv.Quote(line))
+		} else {
+			line = line[:i]
+		}
+	}
+
+	// Ignore proprietary sentences, and those from other talkers
+	fields := strings.Split(line, ",")
+	if len(fields[0]) != 5 || fields[0][0] == 'P' {
+		return false, nil
+	}
+	switch fields[0][2:] {
+	case "RMC":
+		return this.rmc(fields)
+	case "GGA":
+		return false, this.gga(fields)
+	case "GSA":
+		return false, this.gsa(fields)
+	case "VTG":
+		return false, this.vtg(fields)
+	default:
+		return false, nil
+	}
+}
+
+// Position returns the most recent position
+func (this *nmea) Position() gopi.GPSPosition {
+	return this.pos
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// rmc parses time, date, position, speed and course
+func (this *nmea) rmc(fields []string) (bool, error) {
+	if len(fields) < 10 {
+		return false, gopi.ErrUnexpectedResponse.WithPrefix("RMC")
+	}
+
+	// Time and date
+	if ts, err := parseDateTime(fields[9], fields[1]); err != nil {
+		return false, err
+	} else {
+		this.pos.Time = ts
+	}
+
+	// Status is A when valid, and a mode indicator of N is no fix
+	valid := fields[2] == "A"
+	if len(fields) > 12 && fields[12] == "N" {
+		valid = false
+	}
+	if valid == false {
+		this.pos.Fix = gopi.GPS_FIX_NONE
+		return true, nil
+	}
+
+	// Position
+	if lat, err := parseCoord(fields[3], fields[4]); err != nil {
+		return false, err
+	} else if lon, err := parseCoord(fields[5], fields[6]); err != nil {
+		return false, err
+	} else {
+		this.pos.Latitude, this.pos.Longitude = lat, lon
+	}
+
+	// Speed and course, which are empty when stationary
+	if speed, err := parseFloat(fields[7]); err != nil {
+		return false, err
+	} else if course, err := parseFloat(fields[8]); err != nil {
+		return false, err
+	} else {
+		this.pos.Speed, this.pos.Course = speed*knots, course
+	}
+
+	// Set quality of fix
+	this.pos.Fix = this.fix()
+
+	// Return success
+	return true, nil
+}
+
+// gga parses fix quality, satellites, dilution and altitude
+func (this *nmea) gga(fields []string) error {
+	if len(fields) < 10 {
+		return gopi.ErrUnexpectedResponse.WithPrefix("GGA")
+	}
+	if quality, err := strconv.ParseUint("0"+fields[6], 10, 8); err != nil {
+		return gopi.ErrUnexpectedResponse.WithPrefix("GGA: ", fields[6])
+	} else if satellites, err := strconv.ParseUint("0"+fields[7], 10, 8); err != nil {
+		return gopi.ErrUnexpectedResponse.WithPrefix("GGA: ", fields[7])
+	} else if hdop, err := parseFloat(fields[8]); err != nil {
+		return err
+	} else if altitude, err := parseFloat(fields[9]); err != nil {
+		return err
+	} else {
+		this.quality = uint(quality)
+		this.pos.Satellites = uint(satellites)
+		this.pos.HDOP = hdop
+		this.pos.Altitude = altitude
+	}
+
+	// Return success
+	return nil
+}
+
+// gsa parses 2D or 3D fix mode
+func (this *nmea) gsa(fields []string) error {
+	if len(fields) < 3 {
+		return gopi.ErrUnexpectedResponse.WithPrefix("GSA")
+	} else if mode, err := strconv.ParseUint("0"+fields[2], 10, 8); err != nil {
+		return gopi.ErrUnexpectedResponse.WithPrefix("GSA: ", fields[2])
+	} else {
+		this.mode = uint(mode)
+	}
+
+	// Return success
+	return nil
+}
+
+// vtg parses speed and course
+func (this *nmea) vtg(fields []string) error {
+	if len(fields) < 8 {
+		return gopi.ErrUnexpectedResponse.WithPrefix("VTG")
+	} else if course, err := parseFloat(fields[1]); err != nil {
+		return err
+	} else if kmh, err := parseFloat(fields[7]); err != nil {
+		return err
+	} else {
+		this.pos.Course, this.pos.Speed = course, kmh*1000/3600
+	}
+
+	// Return success
+	return nil
+}
+
+// fix returns the quality of a valid fix. When no GSA sentence has been
+// received, the fix is 2D unless GGA reports differential corrections
+func (this *nmea) fix() gopi.GPSFix {
+	switch this.quality {
+	case 2:
+		return gopi.GPS_FIX_DGPS
+	case 4, 5:
+		return gopi.GPS_FIX_RTK
+	}
+	if this.mode == 3 {
+		return gopi.GPS_FIX_3D
+	} else {
+		return gopi.GPS_FIX_2D
+	}
+}
+
+func checksum(data string) byte {
+	var sum byte
+	for i := 0; i < len(data); i++ {
+		sum ^= data[i]
+	}
+	return sum
+}
+
+// parseCoord parses [d]ddmm.mmmm with a hemisphere
+func parseCoord(value, hemisphere string) (float64, error) {
+	i := strings.IndexByte(value, '.')
+	if i < 0 {
+		i = len(value)
+	}
+	if i < 3 {
+		return 0, gopi.ErrUnexpectedResponse.WithPrefix("NMEA: ", strconv.Quote(value))
+	} else if deg, err := strconv.ParseUint(value[:i-2], 10, 8); err != nil {
+		return 0, gopi.ErrUnexpectedResponse.WithPrefix("NMEA: ", strconv.Quote(value))
+	} else if min, err := strconv.ParseFloat(value[i-2:], 64); err != nil {
+		return 0, gopi.ErrUnexpectedResponse.WithPrefix("NMEA: ", strconv.Quote(value))
+	} else {
+		coord := float64(deg) + min/60
+		switch hemisphere {
+		case "N", "E":
+			return coord, nil
+		case "S", "W":
+			return -coord, nil
+		default:
+			return 0, gopi.ErrUnexpectedResponse.WithPrefix("NMEA: ", strconv.Quote(hemisphere))
+		}
+	}
+}
+
+// parseDateTime parses ddmmyy and hhmmss.ss in UTC
+func parseDateTime(date, tod string) (time.Time, error) {
+	if len(date) != 6 || len(tod) < 6 {
+		return time.Time{}, gopi.ErrUnexpectedResponse.WithPrefix("NMEA: ", strconv.Quote(date+" "+tod))
+	} else if ts, err := time.Parse("020106 150405", date+" "+tod[:6]); err != nil {
+		return time.Time{}, gopi.ErrUnexpectedResponse.WithPrefix("NMEA: ", strconv.Quote(date+" "+tod))
+	} else if frac, err := strconv.ParseFloat("0"+tod[6:], 64); err != nil {
+		return time.Time{}, gopi.ErrUnexpectedResponse.WithPrefix("NMEA: ", strconv.Quote(tod))
+	} else {
+		return ts.Add(time.Duration(frac * float64(time.Second)).Round(time.Millisecond)), nil
+	}
+}
+
+// parseFloat returns zero for an empty field
+func parseFloat(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	} else if f, err := strconv.ParseFloat(value, 64); err != nil {
+		return 0, gopi.ErrUnexpectedResponse.WithPrefix("NMEA: ", strconv.Quote(value))
+	} else {
+		return f, nil
+	}
+}
@@ -0,0 +1,17 @@
+// +build linux
+
+package gps
+
+import (
+	"syscall"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// settime sets the system clock, which requires CAP_SYS_TIME
+func settime(ts time.Time) error {
+	tv := syscall.NsecToTimeval(ts.UnixNano())
+	return syscall.Settimeofday(&tv)
+}
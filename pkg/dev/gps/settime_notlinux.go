@@ -0,0 +1,16 @@
+// +build !linux
+
+package gps
+
+import (
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func settime(time.Time) error {
+	return gopi.ErrNotImplemented.WithPrefix("settime")
+}
@@ -0,0 +1,81 @@
+package gps
+
+import (
+	"encoding/binary"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://www.u-blox.com/en/docs/UBX-18010854 (UBX-NAV-PVT)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	ubxSync1     = 0xB5
+	ubxSync2     = 0x62
+	ubxHeader    = 6 // Sync, class, id and length
+	ubxMaxLength = 1024
+	ubxClassNav  = 0x01
+	ubxIdNavPvt  = 0x07
+	ubxNavPvtLen = 92
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ubxChecksum returns the Fletcher checksum over class, id, length
+// and payload
+func ubxChecksum(data []byte) (byte, byte) {
+	var a, b byte
+	for _, v := range data {
+		a += v
+		b += a
+	}
+	return a, b
+}
+
+// ubxNavPvt returns the position, velocity and time from a NAV-PVT
+// payload. Dilution of precision is not included
+func ubxNavPvt(payload []byte) (gopi.GPSPosition, error) {
+	var pos gopi.GPSPosition
+	if len(payload) < ubxNavPvtLen {
+		return pos, gopi.ErrUnexpectedResponse.WithPrefix("NAV-PVT")
+	}
+
+	// Time is set when date and time are valid and fully resolved
+	if valid := payload[11]; valid&0x07 == 0x07 {
+		pos.Time = time.Date(
+			int(binary.LittleEndian.Uint16(payload[4:])), time.Month(payload[6]), int(payload[7]),
+			int(payload[8]), int(payload[9]), int(payload[10]),
+			int(int32(binary.LittleEndian.Uint32(payload[16:]))), time.UTC)
+	}
+
+	// Fix type, where corrections improve a 3D fix
+	fixType, flags := payload[20], payload[21]
+	if flags&0x01 == 0 {
+		pos.Fix = gopi.GPS_FIX_NONE
+	} else if fixType == 2 {
+		pos.Fix = gopi.GPS_FIX_2D
+	} else if fixType != 3 && fixType != 4 {
+		pos.Fix = gopi.GPS_FIX_NONE
+	} else if flags&0xC0 != 0 {
+		pos.Fix = gopi.GPS_FIX_RTK
+	} else if flags&0x02 != 0 {
+		pos.Fix = gopi.GPS_FIX_DGPS
+	} else {
+		pos.Fix = gopi.GPS_FIX_3D
+	}
+
+	// Position and velocity
+	pos.Satellites = uint(payload[23])
+	pos.Longitude = float64(int32(binary.LittleEndian.Uint32(payload[24:]))) * 1e-7
+	pos.Latitude = float64(int32(binary.LittleEndian.Uint32(payload[28:]))) * 1e-7
+	pos.Altitude = float64(int32(binary.LittleEndian.Uint32(payload[36:]))) / 1000
+	pos.Speed = float64(int32(binary.LittleEndian.Uint32(payload[60:]))) / 1000
+	pos.Course = float64(int32(binary.LittleEndian.Uint32(payload[64:]))) * 1e-5
+
+	// Return success
+	return pos, nil
+}
@@ -0,0 +1,240 @@
+package mqtt
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: http://docs.oasis-open.org/mqtt/mqtt/v3.1.1/os/mqtt-v3.1.1-os.html
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Client is a minimal MQTT 3.1.1 client which subscribes and publishes
+// with at most once delivery
+type Client struct {
+	sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+	id   uint16
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	packetConnect    = 0x10
+	packetConnAck    = 0x20
+	packetPublish    = 0x30
+	packetPubAck     = 0x40
+	packetSubscribe  = 0x82
+	packetSubAck     = 0x90
+	packetPingReq    = 0xC0
+	packetPingResp   = 0xD0
+	packetDisconnect = 0xE0
+)
+
+const (
+	maxLength = 256 * 1024
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Dial connects to a broker with a client identifier, and optional
+// username and password, and waits for the connection to be acknowledged
+func Dial(addr, client, user, password string, keepalive, timeout time.Duration) (*Client, error) {
+	return DialTLS(addr, client, user, password, keepalive, timeout, nil)
+}
+
+// DialTLS connects to a broker over TLS when the configuration is not nil,
+// which can include a client certificate for authentication
+func DialTLS(addr, client, user, password string, keepalive, timeout time.Duration, config *tls.Config) (*Client, error) {
+	var conn net.Conn
+	var err error
+	if config == nil {
+		conn, err = net.DialTimeout("tcp", addr, timeout)
+	} else {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", addr, config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	this := &Client{conn: conn, r: bufio.NewReader(conn)}
+
+	// Clean session, with username and password when set
+	flags := byte(0x02)
+	payload := encodeString(client)
+	if user != "" {
+		flags |= 0x80
+		payload = append(payload, encodeString(user)...)
+		if password != "" {
+			flags |= 0x40
+			payload = append(payload, encodeString(password)...)
+		}
+	}
+	header := append(encodeString("MQTT"), 0x04, flags, 0, 0)
+	binary.BigEndian.PutUint16(header[len(header)-2:], uint16(keepalive/time.Second))
+
+	// Send CONNECT and wait for CONNACK
+	conn.SetDeadline(time.Now().Add(timeout))
+	if err := this.write(packetConnect, append(header, payload...)); err != nil {
+		conn.Close()
+		return nil, err
+	} else if packet, data, err := this.read(); err != nil {
+		conn.Close()
+		return nil, err
+	} else if packet != packetConnAck || len(data) != 2 {
+		conn.Close()
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("MQTT: CONNACK")
+	} else if data[1] != 0 {
+		conn.Close()
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("MQTT: Connection refused with code ", data[1])
+	}
+	conn.SetDeadline(time.Time{})
+
+	// Return success
+	return this, nil
+}
+
+// Close sends DISCONNECT and closes the connection
+func (this *Client) Close() error {
+	this.write(packetDisconnect, nil)
+	return this.conn.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Subscribe to one or more topic filters
+func (this *Client) Subscribe(topics ...string) error {
+	if len(topics) == 0 {
+		return gopi.ErrBadParameter.WithPrefix("Subscribe")
+	}
+
+	this.Mutex.Lock()
+	this.id++
+	id := this.id
+	this.Mutex.Unlock()
+
+	data := []byte{byte(id >> 8), byte(id)}
+	for _, topic := range topics {
+		data = append(data, encodeString(topic)...)
+		data = append(data, 0)
+	}
+	return this.write(packetSubscribe, data)
+}
+
+// Publish a message
+func (this *Client) Publish(topic string, payload []byte, retain bool) error {
+	packet := byte(packetPublish)
+	if retain {
+		packet |= 0x01
+	}
+	return this.write(packet, append(encodeString(topic), payload...))
+}
+
+// Ping the broker to keep the connection alive
+func (this *Client) Ping() error {
+	return this.write(packetPingReq, nil)
+}
+
+// Receive waits for a published message. Messages published with at
+// least once delivery are acknowledged
+func (this *Client) Receive() (string, []byte, error) {
+	for {
+		packet, data, err := this.read()
+		if err != nil {
+			return "", nil, err
+		} else if packet&0xF0 != packetPublish {
+			continue
+		}
+
+		// Decode topic and packet identifier
+		if len(data) < 2 {
+			return "", nil, gopi.ErrUnexpectedResponse.WithPrefix("MQTT: PUBLISH")
+		}
+		n := int(binary.BigEndian.Uint16(data))
+		if len(data) < 2+n {
+			return "", nil, gopi.ErrUnexpectedResponse.WithPrefix("MQTT: PUBLISH")
+		}
+		topic, data := string(data[2:2+n]), data[2+n:]
+		if qos := (packet >> 1) & 0x03; qos > 0 {
+			if len(data) < 2 {
+				return "", nil, gopi.ErrUnexpectedResponse.WithPrefix("MQTT: PUBLISH")
+			} else if qos == 1 {
+				if err := this.write(packetPubAck, data[:2]); err != nil {
+					return "", nil, err
+				}
+			}
+			data = data[2:]
+		}
+		return topic, data, nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *Client) write(packet byte, data []byte) error {
+	buf := []byte{packet}
+	for n := len(data); ; {
+		b := byte(n & 0x7F)
+		if n >>= 7; n > 0 {
+			buf = append(buf, b|0x80)
+		} else {
+			buf = append(buf, b)
+			break
+		}
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	_, err := this.conn.Write(append(buf, data...))
+	return err
+}
+
+func (this *Client) read() (byte, []byte, error) {
+	packet, err := this.r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Remaining length is up to four bytes
+	length := 0
+	for i := uint(0); ; i += 7 {
+		if b, err := this.r.ReadByte(); err != nil {
+			return 0, nil, err
+		} else if i > 21 {
+			return 0, nil, gopi.ErrUnexpectedResponse.WithPrefix("MQTT: Length")
+		} else {
+			length |= int(b&0x7F) << i
+			if b&0x80 == 0 {
+				break
+			}
+		}
+	}
+	if length > maxLength {
+		return 0, nil, gopi.ErrUnexpectedResponse.WithPrefix("MQTT: Packet too long")
+	}
+
+	// Read data
+	data := make([]byte, length)
+	if _, err := io.ReadFull(this.r, data); err != nil {
+		return 0, nil, err
+	}
+
+	// Return success
+	return packet, data, nil
+}
+
+func encodeString(value string) []byte {
+	return append([]byte{byte(len(value) >> 8), byte(len(value))}, value...)
+}
@@ -0,0 +1,97 @@
+package iot
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://docs.aws.amazon.com/iot/latest/developerguide/device-shadow-mqtt.html
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// aws authenticates with an X.509 client certificate, reports state to
+// the classic shadow of a thing and receives methods on command topics
+type aws struct {
+	thing string
+}
+
+type awsDelta struct {
+	State map[string]interface{} `json:"state"`
+}
+
+type awsShadow struct {
+	State struct {
+		Reported map[string]interface{} `json:"reported"`
+	} `json:"state"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newAWS(cfg config) (provider, error) {
+	if cfg.endpoint == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.endpoint")
+	} else if cfg.device == "" || strings.ContainsAny(cfg.device, "/#+") {
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.device")
+	} else if len(cfg.tls.Certificates) == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.cert")
+	}
+	return &aws{cfg.device}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROVIDER
+
+func (this *aws) Name() string {
+	return "aws"
+}
+
+func (this *aws) Endpoint() string {
+	return ""
+}
+
+func (this *aws) Credentials(time.Time) (string, string, string, time.Time, error) {
+	return this.thing, "", "", time.Time{}, nil
+}
+
+func (this *aws) Subscriptions() []string {
+	return []string{
+		"$aws/things/" + this.thing + "/shadow/update/delta",
+		"cmd/gopi/" + this.thing + "/+",
+	}
+}
+
+func (this *aws) Telemetry(name string, payload interface{}) (string, interface{}) {
+	return "dt/gopi/" + this.thing + "/" + name, payload
+}
+
+func (this *aws) Reported(state map[string]interface{}) (string, interface{}) {
+	shadow := awsShadow{}
+	shadow.State.Reported = state
+	return "$aws/things/" + this.thing + "/shadow/update", shadow
+}
+
+func (this *aws) Message(topic string, data []byte) (*message, error) {
+	if topic == "$aws/things/"+this.thing+"/shadow/update/delta" {
+		var delta awsDelta
+		if err := json.Unmarshal(data, &delta); err != nil {
+			return nil, err
+		}
+		return &message{desired: delta.State}, nil
+	}
+	if method := strings.TrimPrefix(topic, "cmd/gopi/"+this.thing+"/"); method != topic && method != "" {
+		return &message{method: method, payload: json.RawMessage(data)}, nil
+	}
+	return nil, nil
+}
+
+func (this *aws) Response(msg *message, status int, result interface{}) (string, interface{}) {
+	return "cmd/gopi/" + this.thing + "/" + msg.method + "/response", map[string]interface{}{
+		"status": status,
+		"result": result,
+	}
+}
@@ -0,0 +1,140 @@
+package iot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://docs.microsoft.com/en-us/azure/iot-hub/iot-hub-mqtt-support
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// azure authenticates with a shared access signature or an X.509 client
+// certificate, reports state to the device twin and receives direct
+// methods
+type azure struct {
+	hub, device string
+	key         []byte
+	ttl         time.Duration
+	rid         uint64
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	azureAPIVersion = "2021-04-12"
+	azureDesired    = "$iothub/twin/PATCH/properties/desired/"
+	azureMethods    = "$iothub/methods/POST/"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newAzure(cfg config) (provider, error) {
+	if cfg.endpoint == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.endpoint")
+	} else if cfg.device == "" || strings.ContainsAny(cfg.device, "/#+") {
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.device")
+	} else if len(cfg.sas) == 0 && len(cfg.tls.Certificates) == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.sas or -iot.cert")
+	}
+	return &azure{
+		hub:    hostname(cfg.endpoint),
+		device: cfg.device,
+		key:    cfg.sas,
+		ttl:    cfg.ttl,
+	}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROVIDER
+
+func (this *azure) Name() string {
+	return "azure"
+}
+
+func (this *azure) Endpoint() string {
+	return ""
+}
+
+// Credentials returns a shared access signature as the password when
+// a key is set, or no password when authenticating with a certificate
+func (this *azure) Credentials(now time.Time) (string, string, string, time.Time, error) {
+	user := this.hub + "/" + this.device + "/?api-version=" + azureAPIVersion
+	if len(this.key) == 0 {
+		return this.device, user, "", time.Time{}, nil
+	}
+	expires := now.Add(this.ttl).Truncate(time.Second)
+	return this.device, user, sas(this.hub+"/devices/"+this.device, this.key, expires), expires, nil
+}
+
+func (this *azure) Subscriptions() []string {
+	return []string{
+		azureDesired + "#",
+		azureMethods + "#",
+	}
+}
+
+func (this *azure) Telemetry(name string, payload interface{}) (string, interface{}) {
+	return "devices/" + this.device + "/messages/events/" + url.Values{"name": []string{name}}.Encode(), payload
+}
+
+func (this *azure) Reported(state map[string]interface{}) (string, interface{}) {
+	rid := atomic.AddUint64(&this.rid, 1)
+	return fmt.Sprint("$iothub/twin/PATCH/properties/reported/?$rid=", rid), state
+}
+
+func (this *azure) Message(topic string, data []byte) (*message, error) {
+	switch {
+	case strings.HasPrefix(topic, azureDesired):
+		var desired map[string]interface{}
+		if err := json.Unmarshal(data, &desired); err != nil {
+			return nil, err
+		}
+		delete(desired, "$version")
+		return &message{desired: desired}, nil
+	case strings.HasPrefix(topic, azureMethods):
+		// Topic is $iothub/methods/POST/{method}/?$rid={rid}
+		path := strings.TrimPrefix(topic, azureMethods)
+		i := strings.Index(path, "/?")
+		if i <= 0 {
+			return nil, gopi.ErrUnexpectedResponse.WithPrefix(topic)
+		}
+		query, err := url.ParseQuery(path[i+2:])
+		if err != nil || query.Get("$rid") == "" {
+			return nil, gopi.ErrUnexpectedResponse.WithPrefix(topic)
+		}
+		return &message{method: path[:i], id: query.Get("$rid"), payload: json.RawMessage(data)}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (this *azure) Response(msg *message, status int, result interface{}) (string, interface{}) {
+	return fmt.Sprint("$iothub/methods/res/", status, "/?$rid=", msg.id), result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// sas returns a shared access signature for a resource which expires
+// at a time
+func sas(resource string, key []byte, expires time.Time) string {
+	resource = url.QueryEscape(resource)
+	se := fmt.Sprint(expires.Unix())
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(resource + "\n" + se))
+	sig := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return "SharedAccessSignature sr=" + resource + "&sig=" + url.QueryEscape(sig) + "&se=" + se
+}
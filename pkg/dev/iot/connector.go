@@ -0,0 +1,622 @@
+package iot
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	mqtt "github.com/djthorpe/gopi/v3/pkg/dev/internal/mqtt"
+	attachment "github.com/djthorpe/gopi/v3/pkg/event/attachment"
+	retry "github.com/djthorpe/gopi/v3/pkg/retry"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type connector struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.EventCodec
+	gopi.Twin
+	gopi.GPIO
+	gopi.Relay
+	gopi.UnitManager
+	gopi.AuditLog
+	sync.RWMutex
+
+	// Flags
+	name      *string
+	endpoint  *string
+	device    *string
+	registry  *string
+	cert      *string
+	key       *string
+	ca        *string
+	sas       *string
+	telemetry *string
+	attach    *uint
+	ttl       *time.Duration
+	timeout   *time.Duration
+	retry     *retry.Flags
+
+	provider provider
+	tls      *tls.Config
+	globs    []string
+	ch       <-chan gopi.Event
+	client   *mqtt.Client
+	expires  time.Time
+	methods  map[string]gopi.CloudMethod
+	backoff  *retry.Backoff
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	keepAlive   = 60 * time.Second
+	defaultPort = "8883"
+)
+
+var (
+	reMethodName = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*(\\.[A-Za-z_][A-Za-z0-9_]*)*$")
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *connector) Define(cfg gopi.Config) error {
+	this.name = cfg.FlagString("iot.provider", "", "IoT cloud (aws, azure, google)")
+	this.endpoint = cfg.FlagString("iot.endpoint", "", "MQTT broker address, if not the default for the cloud")
+	this.device = cfg.FlagString("iot.device", "", "Device identifier or thing name")
+	this.registry = cfg.FlagString("iot.registry", "", "Google Cloud IoT registry (projects/<project>/locations/<region>/registries/<registry>)")
+	this.cert = cfg.FlagString("iot.cert", "", "X.509 client certificate file")
+	this.key = cfg.FlagSecret("iot.key", "", "Private key file for the certificate or signing tokens")
+	this.ca = cfg.FlagString("iot.ca", "", "Certificate authority file for verifying the broker")
+	this.sas = cfg.FlagSecret("iot.sas", "", "Azure IoT Hub device shared access key")
+	this.telemetry = cfg.FlagString("iot.telemetry", "", "Comma-separated patterns of event names to publish as telemetry")
+	this.attach = cfg.FlagUint("iot.attachments", 128*1024, "Largest event attachment in bytes published with telemetry, or zero for none")
+	this.ttl = cfg.FlagDuration("iot.ttl", time.Hour, "Lifetime of access tokens")
+	this.timeout = cfg.FlagDuration("iot.timeout", 10*time.Second, "Connection and method timeout")
+	this.retry = retry.Define(cfg, "iot", time.Second, 5*time.Minute, 0)
+	return nil
+}
+
+func (this *connector) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if *this.timeout <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-iot.timeout")
+	} else if *this.ttl < time.Minute {
+		return gopi.ErrBadParameter.WithPrefix("-iot.ttl")
+	} else if backoff, err := this.retry.New(); err != nil {
+		return err
+	} else {
+		this.backoff = backoff
+	}
+	for _, glob := range strings.Split(*this.telemetry, ",") {
+		if glob = strings.TrimSpace(glob); glob == "" {
+			continue
+		} else if _, err := path.Match(glob, ""); err != nil {
+			return gopi.ErrBadParameter.WithPrefix("-iot.telemetry: ", glob)
+		} else {
+			this.globs = append(this.globs, glob)
+		}
+	}
+
+	// Read credentials
+	cfg := config{
+		device:   *this.device,
+		registry: *this.registry,
+		ttl:      *this.ttl,
+		tls:      &tls.Config{},
+	}
+	if err := this.credentials(&cfg); err != nil {
+		return err
+	}
+
+	// Create the provider, and add the default port to the endpoint
+	if *this.endpoint != "" {
+		if _, _, err := net.SplitHostPort(*this.endpoint); err != nil {
+			*this.endpoint = net.JoinHostPort(*this.endpoint, defaultPort)
+		}
+		cfg.endpoint = *this.endpoint
+	}
+	if provider, err := newProvider(*this.name, cfg); err != nil {
+		return err
+	} else {
+		this.provider = provider
+		this.tls = cfg.tls
+	}
+	if *this.endpoint == "" {
+		*this.endpoint = this.provider.Endpoint()
+	}
+	this.tls.ServerName = hostname(*this.endpoint)
+
+	// Register methods for available units
+	this.methods = make(map[string]gopi.CloudMethod)
+	for name, fn := range this.builtins() {
+		if err := this.RegisterMethod(name, fn); err != nil {
+			return err
+		}
+	}
+
+	// Subscribe to events for telemetry and twin state
+	if this.Publisher != nil {
+		this.ch = this.Publisher.Subscribe()
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *connector) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	var result error
+	if this.ch != nil {
+		this.Publisher.Unsubscribe(this.ch)
+	}
+	if this.client != nil {
+		result = this.client.Close()
+	}
+
+	// Release resources
+	this.ch = nil
+	this.client = nil
+	this.methods = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *connector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(keepAlive / 2)
+	defer ticker.Stop()
+
+	for {
+		// Reconnect after the connection is lost, dropping events
+		client := this.conn()
+		if client == nil {
+			if err := this.connect(); err != nil {
+				this.Debug("IoT: ", err)
+				delay, ok := this.backoff.Next()
+				if ok == false {
+					return fmt.Errorf("%w (%v)", err, retry.ErrBudget)
+				}
+				timer := time.NewTimer(delay)
+			WAIT_LOOP:
+				for {
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return nil
+					case <-this.ch:
+						continue
+					case <-timer.C:
+						break WAIT_LOOP
+					}
+				}
+			} else {
+				this.backoff.Reset()
+			}
+			continue
+		}
+
+		// Receive messages in the background
+		errs := make(chan error, 1)
+		go func() {
+			errs <- this.receive(ctx, client)
+		}()
+
+		// Publish events and ping the broker until the connection is lost,
+		// or reconnect when the token expires
+	FOR_LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case evt := <-this.ch:
+				if err := this.event(evt); err != nil {
+					this.Debug("IoT: ", err)
+				}
+			case <-ticker.C:
+				if this.expired() {
+					this.disconnect(client)
+					<-errs
+					break FOR_LOOP
+				} else if err := client.Ping(); err != nil {
+					this.Debug("IoT: ", err)
+				}
+			case err := <-errs:
+				this.Print("IoT: ", err)
+				this.disconnect(client)
+				break FOR_LOOP
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *connector) Provider() string {
+	return this.provider.Name()
+}
+
+func (this *connector) Connected() bool {
+	return this.conn() != nil
+}
+
+func (this *connector) RegisterMethod(name string, fn gopi.CloudMethod) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if reMethodName.MatchString(name) == false || fn == nil {
+		return gopi.ErrBadParameter.WithPrefix("RegisterMethod: ", name)
+	} else if _, exists := this.methods[name]; exists {
+		return gopi.ErrDuplicateEntry.WithPrefix("RegisterMethod: ", name)
+	} else {
+		this.methods[name] = fn
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *connector) Publish(evt gopi.Event) error {
+	if evt == nil {
+		return gopi.ErrBadParameter.WithPrefix("Publish")
+	} else if payload, err := this.payload(evt); err != nil {
+		return err
+	} else {
+		return this.publish(this.provider.Telemetry(topicName(evt.Name()), payload))
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *connector) String() string {
+	str := "<iot.connector"
+	str += fmt.Sprintf(" provider=%q", this.provider.Name())
+	str += fmt.Sprintf(" endpoint=%q", *this.endpoint)
+	if this.Connected() == false {
+		str += " disconnected"
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// credentials reads the client certificate, certificate authority and
+// keys for signing tokens
+func (this *connector) credentials(cfg *config) error {
+	if *this.cert != "" {
+		if cert, err := tls.LoadX509KeyPair(*this.cert, *this.key); err != nil {
+			return err
+		} else {
+			cfg.tls.Certificates = []tls.Certificate{cert}
+		}
+	}
+	if *this.key != "" {
+		if key, err := readKey(*this.key); err != nil {
+			return err
+		} else {
+			cfg.key = key
+		}
+	}
+	if *this.ca != "" {
+		if data, err := ioutil.ReadFile(*this.ca); err != nil {
+			return err
+		} else if pool := x509.NewCertPool(); pool.AppendCertsFromPEM(data) == false {
+			return gopi.ErrBadParameter.WithPrefix("-iot.ca")
+		} else {
+			cfg.tls.RootCAs = pool
+		}
+	}
+	if *this.sas != "" {
+		if key, err := base64.StdEncoding.DecodeString(*this.sas); err != nil {
+			return gopi.ErrBadParameter.WithPrefix("-iot.sas")
+		} else {
+			cfg.sas = key
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// connect to the broker, subscribe to desired state and methods, and
+// report the state of the twin
+func (this *connector) connect() error {
+	id, user, password, expires, err := this.provider.Credentials(time.Now())
+	if err != nil {
+		return err
+	}
+	client, err := mqtt.DialTLS(*this.endpoint, id, user, password, keepAlive, *this.timeout, this.tls)
+	if err != nil {
+		return err
+	} else if err := client.Subscribe(this.provider.Subscriptions()...); err != nil {
+		client.Close()
+		return err
+	}
+
+	this.RWMutex.Lock()
+	this.client = client
+	this.expires = expires
+	this.RWMutex.Unlock()
+	this.emit(NewEvent(gopi.CLOUD_EVENT_CONNECTED, this.provider.Name(), "", nil, nil))
+
+	// Report the state of the twin
+	if this.Twin != nil {
+		if state, _ := this.Twin.State(); len(state) > 0 {
+			return this.publish(this.provider.Reported(state))
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// disconnect closes the connection to the broker
+func (this *connector) disconnect(client *mqtt.Client) {
+	this.RWMutex.Lock()
+	if this.client == client {
+		this.client.Close()
+		this.client = nil
+	}
+	this.RWMutex.Unlock()
+	this.emit(NewEvent(gopi.CLOUD_EVENT_DISCONNECTED, this.provider.Name(), "", nil, nil))
+}
+
+func (this *connector) conn() *mqtt.Client {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.client
+}
+
+// expired returns true when the token expires before the next ping
+func (this *connector) expired() bool {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.expires.IsZero() == false && time.Until(this.expires) < keepAlive
+}
+
+// publish a payload as JSON to a topic
+func (this *connector) publish(topic string, payload interface{}) error {
+	if client := this.conn(); client == nil {
+		return gopi.ErrOutOfOrder.WithPrefix("IoT: Not connected")
+	} else if data, err := json.Marshal(payload); err != nil {
+		return err
+	} else {
+		return client.Publish(topic, data, false)
+	}
+}
+
+// event reports changes to the twin, and publishes events with
+// matching names as telemetry
+func (this *connector) event(evt gopi.Event) error {
+	switch evt := evt.(type) {
+	case nil, gopi.CloudEvent:
+		return nil
+	case gopi.TwinEvent:
+		return this.publish(this.provider.Reported(evt.Changes()))
+	}
+	for _, glob := range this.globs {
+		if match, _ := path.Match(glob, evt.Name()); match {
+			return this.Publish(evt)
+		}
+	}
+	return nil
+}
+
+// payload returns the telemetry for an event, with the name, type and
+// size of any attachments, and the data encoded as base64 when it is
+// no larger than -iot.attachments
+func (this *connector) payload(evt gopi.Event) (interface{}, error) {
+	result, err := this.fields(evt)
+	if err != nil {
+		return nil, err
+	}
+	attachments := attachment.Attachments(evt)
+	if len(attachments) == 0 {
+		return result, nil
+	}
+
+	// Attachments are added to the fields of the event
+	fields, ok := result.(map[string]interface{})
+	if raw, isRaw := result.(json.RawMessage); isRaw {
+		ok = json.Unmarshal(raw, &fields) == nil && fields != nil
+	}
+	if ok == false {
+		return nil, gopi.ErrNotImplemented.WithPrefix("Publish: ", evt.Name(), ": Attachments")
+	}
+	type attachmentPayload struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+		Size int64  `json:"size,omitempty"`
+		Data []byte `json:"data,omitempty"`
+	}
+	payload := make([]attachmentPayload, 0, len(attachments))
+	for _, a := range attachments {
+		p := attachmentPayload{Name: a.Name(), Type: a.Type(), Size: a.Size()}
+		if *this.attach > 0 {
+			if data, err := attachment.Read(a, int64(*this.attach)); err == nil {
+				p.Data, p.Size = data, int64(len(data))
+			} else {
+				this.Debug("IoT: ", evt.Name(), ": ", err)
+			}
+		}
+		payload = append(payload, p)
+	}
+	fields["attachments"] = payload
+
+	// Return success
+	return fields, nil
+}
+
+// fields returns the fields of a measurement, or the event encoded
+// as JSON by the event codec
+func (this *connector) fields(evt gopi.Event) (interface{}, error) {
+	if measurement, ok := evt.(gopi.Measurement); ok {
+		result := make(map[string]interface{})
+		for _, field := range append(measurement.Tags(), measurement.Metrics()...) {
+			result[field.Name()] = field.Value()
+		}
+		if ts := measurement.Time(); ts.IsZero() == false {
+			result["time"] = ts
+		}
+		return result, nil
+	} else if this.EventCodec == nil {
+		return nil, gopi.ErrNotImplemented.WithPrefix("Publish: ", evt.Name())
+	} else if _, data, err := this.EventCodec.Marshal(evt, gopi.EVENT_FORMAT_JSON); err != nil {
+		return nil, err
+	} else {
+		return json.RawMessage(data), nil
+	}
+}
+
+// receive messages until the connection is lost
+func (this *connector) receive(ctx context.Context, client *mqtt.Client) error {
+	for {
+		if topic, data, err := client.Receive(); err != nil {
+			return err
+		} else if msg, err := this.provider.Message(topic, data); err != nil {
+			this.Debug("IoT: ", topic, ": ", err)
+		} else if msg != nil {
+			this.message(ctx, msg)
+		}
+	}
+}
+
+// message calls a method, or the methods with the same name as desired
+// properties, and emits an event
+func (this *connector) message(ctx context.Context, msg *message) {
+	if msg.method == "" {
+		for key, value := range msg.desired {
+			if fn := this.method(key); fn == nil {
+				continue
+			} else if data, err := json.Marshal(value); err != nil {
+				this.Debug("IoT: ", key, ": ", err)
+			} else if _, err := this.call(ctx, key, fn, data); err != nil {
+				this.Print("IoT: ", key, ": ", err)
+			}
+		}
+		this.emit(NewEvent(gopi.CLOUD_EVENT_DESIRED, this.provider.Name(), "", msg.desired, nil))
+		return
+	}
+
+	// Call method and respond with the result, or the error
+	status := http.StatusOK
+	result, err := interface{}(nil), error(nil)
+	if fn := this.method(msg.method); fn == nil {
+		err = gopi.ErrNotFound.WithPrefix(msg.method)
+	} else {
+		result, err = this.call(ctx, msg.method, fn, msg.payload)
+	}
+	if err != nil {
+		status = gopi.ErrorCode(err).HttpStatus()
+		result = map[string]string{"error": err.Error()}
+	}
+	if topic, payload := this.provider.Response(msg, status, result); topic != "" {
+		if err := this.publish(topic, payload); err != nil {
+			this.Debug("IoT: ", msg.method, ": ", err)
+		}
+	}
+	this.emit(NewEvent(gopi.CLOUD_EVENT_METHOD, this.provider.Name(), msg.method, nil, err))
+}
+
+func (this *connector) method(name string) gopi.CloudMethod {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.methods[name]
+}
+
+// call a method with a timeout, and record the call in the audit log
+// with the payload as the new value
+func (this *connector) call(ctx context.Context, name string, fn gopi.CloudMethod, payload json.RawMessage) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(gopi.WithAuditChange(ctx), *this.timeout)
+	defer cancel()
+	result, err := fn(ctx, payload)
+	if this.AuditLog != nil {
+		entry := gopi.AuditEntry{
+			Identity: this.provider.Name(),
+			Source:   "mqtt",
+			Action:   name,
+		}
+		if _, new := gopi.AuditChangeFromContext(ctx); new == "" {
+			entry.New = string(payload)
+		}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		if err := this.AuditLog.Record(ctx, entry); err != nil {
+			this.Debug("IoT: ", err)
+		}
+	}
+	return result, err
+}
+
+func (this *connector) emit(evt gopi.Event) {
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(evt, false); err != nil {
+			this.Debug("IoT: ", err)
+		}
+	}
+}
+
+// readKey reads a PEM-encoded RSA or ECDSA private key
+func readKey(path string) (crypto.Signer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	} else if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	} else if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		return nil, err
+	} else if signer, ok := key.(crypto.Signer); ok {
+		return signer, nil
+	} else {
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.key")
+	}
+}
+
+// topicName returns an event name which can be used as a topic level
+func topicName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '#', '+', ' ':
+			return '_'
+		default:
+			return r
+		}
+	}, name)
+}
@@ -0,0 +1,34 @@
+// IoT package implements gopi.CloudConnector, which connects a device
+// to AWS IoT, Azure IoT Hub or Google Cloud IoT over MQTT with TLS. Set
+// -iot.provider to the cloud and -iot.device to the device identifier,
+// or thing name for AWS IoT:
+//
+//   - AWS IoT authenticates with the -iot.cert client certificate and
+//     -iot.key, and requires the -iot.endpoint of the account
+//   - Azure IoT Hub authenticates with the -iot.sas device key, or a
+//     client certificate, and requires the hub as the -iot.endpoint
+//   - Google Cloud IoT authenticates with a token signed with the
+//     -iot.key RSA or ECDSA key, and requires the -iot.registry path
+//
+// Changes to the state of gopi.Twin are reported to the device shadow,
+// device twin or device state, and events with names matching the
+// -iot.telemetry patterns are published as telemetry. Measurements are
+// published with their fields, and other events are encoded with
+// gopi.EventCodec. Attachments of events, such as snapshots, are
+// published in an attachments field with the data encoded as base64
+// when it is no larger than -iot.attachments bytes.
+//
+// Direct method calls, or commands for Google Cloud IoT, call the
+// method registered with the same name, and the result is returned as
+// JSON where the cloud expects a response. When desired state is
+// received, the methods with the same name as a property are called
+// with the value. The methods gpio.read, gpio.write, relay.set,
+// unit.enable and unit.disable are registered when the units are
+// available, for example:
+//
+//	gpio.write {"pin":17,"state":true}
+//	relay.set {"name":"pump","state":false}
+//
+// A gopi.CloudEvent is emitted when the connection changes, desired
+// state is received and a method is called.
+package iot
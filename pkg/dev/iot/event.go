@@ -0,0 +1,67 @@
+package iot
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t        gopi.CloudEventType
+	provider string
+	method   string
+	desired  map[string]interface{}
+	err      error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(t gopi.CloudEventType, provider, method string, desired map[string]interface{}, err error) gopi.CloudEvent {
+	return &event{t, provider, method, desired, err}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.provider
+}
+
+func (this *event) Type() gopi.CloudEventType {
+	return this.t
+}
+
+func (this *event) Method() string {
+	return this.method
+}
+
+func (this *event) Desired() map[string]interface{} {
+	return this.desired
+}
+
+func (this *event) Error() error {
+	return this.err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<iot.event"
+	str += fmt.Sprint(" type=", this.t)
+	str += fmt.Sprintf(" provider=%q", this.provider)
+	if this.method != "" {
+		str += fmt.Sprintf(" method=%q", this.method)
+	}
+	if len(this.desired) > 0 {
+		str += fmt.Sprint(" desired=", this.desired)
+	}
+	if this.err != nil {
+		str += fmt.Sprint(" err=", this.err)
+	}
+	return str + ">"
+}
@@ -0,0 +1,200 @@
+package iot
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://cloud.google.com/iot/docs/how-tos/mqtt-bridge
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// google authenticates with a JSON web token signed with the private key
+// of the device, publishes the whole state of the device and receives
+// configuration as desired state and commands as methods
+type google struct {
+	sync.Mutex
+	project, client, device string
+	key                     crypto.Signer
+	ttl                     time.Duration
+	state                   map[string]interface{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	googleEndpoint = "mqtt.googleapis.com:8883"
+	googleMaxTTL   = 24 * time.Hour
+)
+
+var (
+	reGoogleRegistry = regexp.MustCompile("^projects/([^/]+)/locations/[^/]+/registries/[^/]+$")
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newGoogle(cfg config) (provider, error) {
+	if cfg.device == "" || strings.ContainsAny(cfg.device, "/#+") {
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.device")
+	} else if cfg.key == nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.key")
+	} else if cfg.ttl > googleMaxTTL {
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.ttl")
+	}
+	registry := reGoogleRegistry.FindStringSubmatch(cfg.registry)
+	if registry == nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.registry: ", cfg.registry)
+	}
+	return &google{
+		project: registry[1],
+		client:  cfg.registry + "/devices/" + cfg.device,
+		device:  cfg.device,
+		key:     cfg.key,
+		ttl:     cfg.ttl,
+		state:   make(map[string]interface{}),
+	}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROVIDER
+
+func (this *google) Name() string {
+	return "google"
+}
+
+func (this *google) Endpoint() string {
+	return googleEndpoint
+}
+
+// Credentials returns a token as the password, where the username is
+// ignored
+func (this *google) Credentials(now time.Time) (string, string, string, time.Time, error) {
+	expires := now.Add(this.ttl).Truncate(time.Second)
+	if token, err := jwt(this.key, this.project, now, expires); err != nil {
+		return "", "", "", time.Time{}, err
+	} else {
+		return this.client, "unused", token, expires, nil
+	}
+}
+
+func (this *google) Subscriptions() []string {
+	return []string{
+		"/devices/" + this.device + "/config",
+		"/devices/" + this.device + "/commands/#",
+	}
+}
+
+func (this *google) Telemetry(name string, payload interface{}) (string, interface{}) {
+	return "/devices/" + this.device + "/events/" + name, payload
+}
+
+// Reported merges changes into the state, as the whole state of the
+// device is published
+func (this *google) Reported(changes map[string]interface{}) (string, interface{}) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	state := make(map[string]interface{}, len(this.state)+len(changes))
+	for key, value := range this.state {
+		state[key] = value
+	}
+	for key, value := range changes {
+		state[key] = value
+	}
+	this.state = state
+	return "/devices/" + this.device + "/state", state
+}
+
+func (this *google) Message(topic string, data []byte) (*message, error) {
+	if topic == "/devices/"+this.device+"/config" {
+		var desired map[string]interface{}
+		if len(data) == 0 {
+			return nil, nil
+		} else if err := json.Unmarshal(data, &desired); err != nil {
+			return nil, err
+		}
+		return &message{desired: desired}, nil
+	}
+	if method := strings.TrimPrefix(topic, "/devices/"+this.device+"/commands/"); method != topic && method != "" {
+		return &message{method: method, payload: json.RawMessage(data)}, nil
+	}
+	return nil, nil
+}
+
+// Response returns no topic, as commands are not acknowledged
+func (this *google) Response(*message, int, interface{}) (string, interface{}) {
+	return "", nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// jwt returns a token for an audience signed with RS256 or ES256
+func jwt(key crypto.Signer, audience string, issued, expires time.Time) (string, error) {
+	header := map[string]string{"typ": "JWT"}
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		header["alg"] = "RS256"
+	case *ecdsa.PrivateKey:
+		header["alg"] = "ES256"
+	default:
+		return "", gopi.ErrNotImplemented.WithPrefix("jwt: Unsupported key")
+	}
+	claims := map[string]interface{}{
+		"aud": audience,
+		"iat": issued.Unix(),
+		"exp": expires.Unix(),
+	}
+
+	// Encode header and claims
+	token := make([]string, 0, 3)
+	for _, part := range []interface{}{header, claims} {
+		if data, err := json.Marshal(part); err != nil {
+			return "", err
+		} else {
+			token = append(token, base64.RawURLEncoding.EncodeToString(data))
+		}
+	}
+
+	// Sign, where ECDSA signatures are the concatenated r and s values
+	digest := sha256.Sum256([]byte(strings.Join(token, ".")))
+	var sig []byte
+	if ec, ok := key.(*ecdsa.PrivateKey); ok {
+		if r, s, err := ecdsa.Sign(rand.Reader, ec, digest[:]); err != nil {
+			return "", err
+		} else {
+			sig = append(pad(r, 32), pad(s, 32)...)
+		}
+	} else if data, err := key.Sign(rand.Reader, digest[:], crypto.SHA256); err != nil {
+		return "", err
+	} else {
+		sig = data
+	}
+
+	// Return the token
+	return strings.Join(append(token, base64.RawURLEncoding.EncodeToString(sig)), "."), nil
+}
+
+// pad returns a big-endian integer padded to a number of bytes
+func pad(v *big.Int, n int) []byte {
+	data := v.Bytes()
+	if len(data) >= n {
+		return data
+	}
+	return append(make([]byte, n-len(data)), data...)
+}
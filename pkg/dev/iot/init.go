@@ -0,0 +1,13 @@
+package iot
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.CloudConnector
+	graph.RegisterUnit(reflect.TypeOf(&connector{}), reflect.TypeOf((*gopi.CloudConnector)(nil)))
+}
@@ -0,0 +1,98 @@
+package iot
+
+import (
+	"context"
+	"encoding/json"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type gpioRequest struct {
+	Pin   *uint8 `json:"pin"`
+	State bool   `json:"state"`
+}
+
+type relayRequest struct {
+	Name  string `json:"name"`
+	State bool   `json:"state"`
+}
+
+type unitRequest struct {
+	Name string `json:"name"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// BUILT-IN METHODS
+
+// builtins returns methods for available units
+func (this *connector) builtins() map[string]gopi.CloudMethod {
+	result := make(map[string]gopi.CloudMethod)
+	if this.GPIO != nil {
+		result["gpio.read"] = this.methodGPIORead
+		result["gpio.write"] = this.methodGPIOWrite
+	}
+	if this.Relay != nil {
+		result["relay.set"] = this.methodRelaySet
+	}
+	if this.UnitManager != nil {
+		result["unit.enable"] = this.methodUnitEnable
+		result["unit.disable"] = this.methodUnitDisable
+	}
+	return result
+}
+
+// gpio.read {"pin":17} returns the state of a pin
+func (this *connector) methodGPIORead(_ context.Context, payload json.RawMessage) (interface{}, error) {
+	var req gpioRequest
+	if err := json.Unmarshal(payload, &req); err != nil || req.Pin == nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("gpio.read")
+	}
+	state := this.GPIO.ReadPin(gopi.GPIOPin(*req.Pin))
+	return map[string]interface{}{"pin": *req.Pin, "state": state == gopi.GPIO_HIGH}, nil
+}
+
+// gpio.write {"pin":17,"state":true} sets a pin as output and writes
+// high when state is true
+func (this *connector) methodGPIOWrite(_ context.Context, payload json.RawMessage) (interface{}, error) {
+	var req gpioRequest
+	if err := json.Unmarshal(payload, &req); err != nil || req.Pin == nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("gpio.write")
+	}
+	state := gopi.GPIO_LOW
+	if req.State {
+		state = gopi.GPIO_HIGH
+	}
+	this.GPIO.SetPinMode(gopi.GPIOPin(*req.Pin), gopi.GPIO_OUTPUT)
+	this.GPIO.WritePin(gopi.GPIOPin(*req.Pin), state)
+	return nil, nil
+}
+
+// relay.set {"name":"pump","state":true} switches a relay channel
+func (this *connector) methodRelaySet(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+	var req relayRequest
+	if err := json.Unmarshal(payload, &req); err != nil || req.Name == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("relay.set")
+	}
+	return nil, this.Relay.Set(ctx, req.Name, req.State)
+}
+
+// unit.enable {"name":"cast"} creates and runs an optional or lazy unit
+func (this *connector) methodUnitEnable(_ context.Context, payload json.RawMessage) (interface{}, error) {
+	var req unitRequest
+	if err := json.Unmarshal(payload, &req); err != nil || req.Name == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("unit.enable")
+	}
+	return nil, this.UnitManager.Enable(req.Name)
+}
+
+// unit.disable {"name":"cast"} stops and disposes an optional or lazy unit
+func (this *connector) methodUnitDisable(_ context.Context, payload json.RawMessage) (interface{}, error) {
+	var req unitRequest
+	if err := json.Unmarshal(payload, &req); err != nil || req.Name == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("unit.disable")
+	}
+	return nil, this.UnitManager.Disable(req.Name)
+}
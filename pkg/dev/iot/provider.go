@@ -0,0 +1,96 @@
+package iot
+
+import (
+	"crypto"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// provider maps the connector onto the MQTT topics and authentication
+// of a cloud
+type provider interface {
+	// Name returns the name of the cloud
+	Name() string
+
+	// Endpoint returns the default broker address, or an empty string
+	// if the address must be set
+	Endpoint() string
+
+	// Credentials returns the client identifier, username and password,
+	// and the time when the password expires, or zero if it does not
+	Credentials(time.Time) (string, string, string, time.Time, error)
+
+	// Subscriptions returns the topics for desired state and methods
+	Subscriptions() []string
+
+	// Telemetry returns the topic and payload for telemetry
+	Telemetry(string, interface{}) (string, interface{})
+
+	// Reported returns the topic and payload for changes to the state
+	// of the device twin
+	Reported(map[string]interface{}) (string, interface{})
+
+	// Message decodes desired state or a method call, or returns nil
+	// if the message is ignored
+	Message(string, []byte) (*message, error)
+
+	// Response returns the topic and payload for the response to a
+	// method call, or an empty topic when the cloud does not expect
+	// a response
+	Response(*message, int, interface{}) (string, interface{})
+}
+
+// message is desired state or a method call received from the cloud
+type message struct {
+	desired map[string]interface{}
+	method  string
+	id      string
+	payload json.RawMessage
+}
+
+// config is the configuration for a provider
+type config struct {
+	endpoint string // Broker address
+	device   string // Device identifier or thing name
+	registry string // Registry path for Google Cloud IoT
+	tls      *tls.Config
+	key      crypto.Signer // Private key for signing tokens
+	sas      []byte        // Azure shared access key
+	ttl      time.Duration // Lifetime of tokens
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newProvider(name string, cfg config) (provider, error) {
+	switch strings.ToLower(name) {
+	case "aws":
+		return newAWS(cfg)
+	case "azure":
+		return newAzure(cfg)
+	case "google":
+		return newGoogle(cfg)
+	default:
+		return nil, gopi.ErrBadParameter.WithPrefix("-iot.provider: ", name)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// hostname returns the host part of an address
+func hostname(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	} else {
+		return addr
+	}
+}
@@ -0,0 +1,136 @@
+package iot
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Provider_001(t *testing.T) {
+	// Azure shared access signature and method calls
+	p, err := newProvider("azure", config{
+		endpoint: "hub.azure-devices.net:8883",
+		device:   "pi",
+		sas:      []byte("secret"),
+		ttl:      time.Hour,
+		tls:      &tls.Config{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, user, password, expires, err := p.Credentials(time.Unix(1600000000, 0))
+	if err != nil {
+		t.Fatal(err)
+	} else if client != "pi" || user != "hub.azure-devices.net/pi/?api-version="+azureAPIVersion {
+		t.Error("Unexpected client or user", client, user)
+	} else if expires.Unix() != 1600003600 {
+		t.Error("Unexpected expiry", expires)
+	} else if strings.HasPrefix(password, "SharedAccessSignature sr=hub.azure-devices.net%2Fdevices%2Fpi&sig=") == false || strings.HasSuffix(password, "&se=1600003600") == false {
+		t.Error("Unexpected password", password)
+	}
+
+	msg, err := p.Message("$iothub/methods/POST/gpio.write/?$rid=42", []byte(`{"pin":17}`))
+	if err != nil {
+		t.Fatal(err)
+	} else if msg == nil || msg.method != "gpio.write" || msg.id != "42" || string(msg.payload) != `{"pin":17}` {
+		t.Error("Unexpected message", msg)
+	} else if topic, _ := p.Response(msg, 200, nil); topic != "$iothub/methods/res/200/?$rid=42" {
+		t.Error("Unexpected response topic", topic)
+	}
+
+	msg, err = p.Message("$iothub/twin/PATCH/properties/desired/?$version=3", []byte(`{"relay.set":{"name":"pump"},"$version":3}`))
+	if err != nil {
+		t.Fatal(err)
+	} else if msg == nil || len(msg.desired) != 1 || msg.desired["relay.set"] == nil {
+		t.Error("Unexpected desired state", msg)
+	}
+}
+
+func Test_Provider_002(t *testing.T) {
+	// AWS requires a client certificate
+	if _, err := newProvider("aws", config{endpoint: "example.iot.eu-west-1.amazonaws.com:8883", device: "pi", tls: &tls.Config{}}); err == nil {
+		t.Error("Expected error without certificate")
+	}
+	p, err := newProvider("aws", config{
+		endpoint: "example.iot.eu-west-1.amazonaws.com:8883",
+		device:   "pi",
+		tls:      &tls.Config{Certificates: []tls.Certificate{{}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, err := p.Message("$aws/things/pi/shadow/update/delta", []byte(`{"version":2,"state":{"gpio.write":{"pin":4,"state":true}}}`)); err != nil {
+		t.Error(err)
+	} else if msg == nil || msg.desired["gpio.write"] == nil {
+		t.Error("Unexpected delta", msg)
+	}
+	if msg, err := p.Message("cmd/gopi/pi/relay.set", []byte(`{}`)); err != nil {
+		t.Error(err)
+	} else if msg == nil || msg.method != "relay.set" {
+		t.Error("Unexpected method", msg)
+	}
+	if msg, err := p.Message("cmd/gopi/other/relay.set", []byte(`{}`)); err != nil || msg != nil {
+		t.Error("Expected message to be ignored", msg, err)
+	}
+}
+
+func Test_Provider_003(t *testing.T) {
+	// Google token is signed with ES256
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := newProvider("google", config{
+		device:   "pi",
+		registry: "projects/gopi/locations/europe-west1/registries/home",
+		key:      key,
+		ttl:      time.Hour,
+		tls:      &tls.Config{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, _, password, _, err := p.Credentials(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	} else if client != "projects/gopi/locations/europe-west1/registries/home/devices/pi" {
+		t.Error("Unexpected client", client)
+	}
+
+	// Check claims and signature
+	parts := strings.Split(password, ".")
+	if len(parts) != 3 {
+		t.Fatal("Unexpected token", password)
+	}
+	var claims map[string]interface{}
+	if data, err := base64.RawURLEncoding.DecodeString(parts[1]); err != nil {
+		t.Error(err)
+	} else if err := json.Unmarshal(data, &claims); err != nil {
+		t.Error(err)
+	} else if claims["aud"] != "gopi" {
+		t.Error("Unexpected claims", claims)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if sig, err := base64.RawURLEncoding.DecodeString(parts[2]); err != nil || len(sig) != 64 {
+		t.Error("Unexpected signature", parts[2])
+	} else if r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:]); ecdsa.Verify(&key.PublicKey, digest[:], r, s) == false {
+		t.Error("Signature does not verify")
+	}
+
+	// State is merged
+	p.Reported(map[string]interface{}{"a": 1})
+	if _, state := p.Reported(map[string]interface{}{"b": 2}); len(state.(map[string]interface{})) != 2 {
+		t.Error("Unexpected state", state)
+	}
+}
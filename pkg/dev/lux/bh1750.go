@@ -0,0 +1,113 @@
+package lux
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://www.mouser.com/datasheet/2/348/bh1750fvi-e-186247.pdf
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type bh1750 struct {
+	device
+	setting int
+}
+
+// bh1750Setting is a resolution mode and measurement time
+type bh1750Setting struct {
+	mode uint8   // One time measurement mode
+	mt   uint8   // Measurement time register
+	res  float64 // Counts per lux at the default measurement time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	bh1750PowerOn  = 0x01
+	bh1750OnceH    = 0x20 // One time measurement at 1 lx resolution
+	bh1750OnceH2   = 0x21 // One time measurement at 0.5 lx resolution
+	bh1750MTHigh   = 0x40
+	bh1750MTLow    = 0x60
+	bh1750MTNormal = 69
+
+	// Maximum measurement time at the default measurement time
+	bh1750Time = 180 * time.Millisecond
+)
+
+var (
+	// Settings from the most sensitive, where a longer measurement time
+	// increases the counts per lux
+	bh1750Settings = []bh1750Setting{
+		{bh1750OnceH2, 254, 2.4},
+		{bh1750OnceH, bh1750MTNormal, 1.2},
+		{bh1750OnceH, 31, 1.2},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newBH1750(ctx context.Context, i2c gopi.I2C, bus gopi.I2CBus, slave uint8) (*bh1750, error) {
+	this := &bh1750{device{i2c, bus, slave}, -1}
+	if err := this.detect(); err != nil {
+		return nil, err
+	} else if err := this.write(ctx, bh1750PowerOn); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *bh1750) Sensitivity() []float64 {
+	result := make([]float64, len(bh1750Settings))
+	for i, setting := range bh1750Settings {
+		result[i] = setting.counts()
+	}
+	return result
+}
+
+// Read starts a measurement and waits for it to complete. The sensor
+// powers down after each measurement
+func (this *bh1750) Read(ctx context.Context, setting int) (float32, float64, error) {
+	s := bh1750Settings[setting]
+	if setting != this.setting {
+		if err := this.write(ctx, bh1750MTHigh|s.mt>>5); err != nil {
+			return 0, 0, err
+		} else if err := this.write(ctx, bh1750MTLow|s.mt&0x1F); err != nil {
+			return 0, 0, err
+		}
+		this.setting = setting
+	}
+	if err := this.write(ctx, bh1750PowerOn); err != nil {
+		return 0, 0, err
+	} else if err := this.write(ctx, s.mode); err != nil {
+		return 0, 0, err
+	} else if err := sleep(ctx, bh1750Time*time.Duration(s.mt)/bh1750MTNormal); err != nil {
+		return 0, 0, err
+	}
+
+	data, err := this.read(ctx, nil, 2)
+	if err != nil {
+		return 0, 0, err
+	}
+	count := float64(binary.BigEndian.Uint16(data))
+	return float32(count / s.counts()), count / 0xFFFF, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// counts returns the counts per lux
+func (s bh1750Setting) counts() float64 {
+	return s.res * float64(s.mt) / bh1750MTNormal
+}
@@ -0,0 +1,19 @@
+// Lux package implements gopi.LightSensor, which reads the illuminance
+// from an ambient light sensor. The sensor is set with the -lux.chip flag:
+//
+//	tsl2561   TSL2560 or TSL2561 light-to-digital converter (address 0x39),
+//	          which corrects for infrared light
+//	bh1750    BH1750 ambient light sensor (address 0x23)
+//	veml7700  VEML7700 ambient light sensor (address 0x10), which
+//	          corrects its response above 1000 lux
+//
+// The gain and integration time are changed automatically, so that dim
+// light is read with the most sensitive setting and bright light does
+// not saturate the sensor. A saturated reading is repeated with a less
+// sensitive setting. A gopi.I2C unit is required.
+//
+// A gopi.LightEvent is emitted on each reading, every -lux.interval, and
+// a measurement is emitted when there is a gopi.Metrics unit. The theme
+// unit switches to dark colours and dims the backlight when the room is
+// dark, and the presence unit scans when the lights are switched on.
+package lux
@@ -0,0 +1,39 @@
+package lux
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	lux float32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(lux float32) gopi.LightEvent {
+	return &event{lux}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "lux"
+}
+
+func (this *event) Lux() float32 {
+	return this.lux
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	return fmt.Sprint("<lux.event lux=", this.lux, ">")
+}
@@ -0,0 +1,13 @@
+package lux
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.LightSensor
+	graph.RegisterUnit(reflect.TypeOf(&lux{}), reflect.TypeOf((*gopi.LightSensor)(nil)))
+}
@@ -0,0 +1,202 @@
+package lux
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type lux struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Metrics
+	gopi.I2C
+	sync.RWMutex
+
+	// Flags
+	chip     *string
+	bus      *uint
+	slave    *uint
+	interval *time.Duration
+
+	measurement string
+	sensor      sensor
+	setting     int
+	lux         float32
+	valid       bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Maximum time to read the sensor, including changes of setting
+	readTimeout = 5 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *lux) Define(cfg gopi.Config) error {
+	this.chip = cfg.FlagString("lux.chip", "tsl2561", "Light sensor (tsl2561, bh1750 or veml7700)")
+	this.bus = cfg.FlagUint("lux.bus", 1, "I2C bus")
+	this.slave = cfg.FlagUint("lux.addr", 0, "I2C address, or zero for the default address")
+	this.interval = cfg.FlagDuration("lux.interval", time.Second, "Interval between readings")
+	cfg.FlagString("lux.measurement", "lux", "Measurement name")
+	return nil
+}
+
+func (this *lux) New(cfg gopi.Config) error {
+	this.Require(this.Logger, this.I2C)
+
+	// Check parameters
+	if *this.interval <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-lux.interval")
+	} else if *this.slave > 0x7F {
+		return gopi.ErrBadParameter.WithPrefix("-lux.addr")
+	}
+
+	// Open sensor
+	ctx, cancel := context.WithTimeout(context.Background(), readTimeout)
+	defer cancel()
+	bus := gopi.I2CBus(*this.bus)
+	switch strings.ToLower(*this.chip) {
+	case "tsl2561":
+		if sensor, err := newTSL2561(ctx, this.I2C, bus, this.addr(0x39)); err != nil {
+			return err
+		} else {
+			this.sensor = sensor
+		}
+	case "bh1750":
+		if sensor, err := newBH1750(ctx, this.I2C, bus, this.addr(0x23)); err != nil {
+			return err
+		} else {
+			this.sensor = sensor
+		}
+	case "veml7700":
+		if sensor, err := newVEML7700(ctx, this.I2C, bus, this.addr(0x10)); err != nil {
+			return err
+		} else {
+			this.sensor = sensor
+		}
+	default:
+		return gopi.ErrBadParameter.WithPrefix("-lux.chip")
+	}
+
+	// Define measurement
+	if measurement := cfg.GetString("lux.measurement"); measurement != "" && this.Metrics != nil {
+		if m, err := this.Metrics.NewMeasurement(measurement, "lux float32", this.Metrics.HostTag()); err != nil {
+			return err
+		} else {
+			this.measurement = m.Name()
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *lux) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.sensor = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *lux) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			if err := this.read(ctx); err != nil {
+				this.Print("Lux: ", err)
+			}
+			timer.Reset(*this.interval)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *lux) Lux() (float32, bool) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.lux, this.valid
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *lux) String() string {
+	str := "<lux"
+	str += fmt.Sprintf(" chip=%q", strings.ToLower(*this.chip))
+	if value, valid := this.Lux(); valid {
+		str += fmt.Sprint(" lux=", value)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// addr returns the I2C address, or a default address for the chip
+func (this *lux) addr(value uint8) uint8 {
+	if *this.slave != 0 {
+		return uint8(*this.slave)
+	}
+	return value
+}
+
+// read measures the illuminance, changing the gain and integration time
+// for the next reading, and emits an event and a measurement
+func (this *lux) read(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+	value, setting, err := measure(ctx, this.sensor, this.setting)
+	this.setting = setting
+	if err != nil {
+		return err
+	}
+
+	// Set value
+	this.RWMutex.Lock()
+	this.lux, this.valid = value, true
+	this.RWMutex.Unlock()
+
+	// Emit event
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(NewEvent(value), false); err != nil {
+			this.Debug("Lux: ", err)
+		}
+	}
+
+	// Emit measurement
+	if this.measurement != "" {
+		if err := this.Metrics.Emit(this.measurement, nil, value); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
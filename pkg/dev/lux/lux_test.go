@@ -0,0 +1,279 @@
+package lux
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// meter emulates a light sensor at a slave address, which reads the light
+// with the gain and integration time last written to it
+type meter struct {
+	*testutil.I2C
+	lock  sync.Mutex
+	chip  string
+	light float64 // Lux, or broadband counts at a gain of 16 and 402ms
+	ir    float64 // Infrared counts at a gain of 16 and 402ms
+
+	timing uint8
+	mode   uint8
+	mt     uint8
+}
+
+type App struct {
+	gopi.Unit
+	gopi.LightSensor
+	gopi.I2C
+	gopi.Publisher
+}
+
+// i2c is a bus with a BH1750 at the default address
+type i2c struct {
+	gopi.Unit
+	*meter
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METER
+
+func newMeter(chip string, addr uint8, light, ir float64) *meter {
+	this := &meter{I2C: testutil.NewI2C(addr), chip: chip, light: light, ir: ir}
+	this.SetTransfer(this.transfer)
+	return this
+}
+
+// SetLight sets the light, while the sensor is being read
+func (this *meter) SetLight(light float64) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.light = light
+}
+
+func (this *meter) transfer(slave uint8, data []byte, n int) ([]byte, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	switch this.chip {
+	case "tsl2561":
+		return this.tsl2561(data, n)
+	case "bh1750":
+		return this.bh1750(data, n)
+	case "veml7700":
+		return this.veml7700(slave, n)
+	default:
+		return nil, gopi.ErrNotImplemented
+	}
+}
+
+func (this *meter) tsl2561(data []byte, n int) ([]byte, error) {
+	reg := data[0] & 0x0F
+	switch {
+	case n == 0 && reg == regTSL2561Timing:
+		this.timing = data[1]
+		return nil, nil
+	case n == 0:
+		return nil, nil
+	case reg == regTSL2561ID:
+		return []byte{0x50}, nil
+	}
+	for _, s := range tsl2561Settings {
+		if s.timing == this.timing {
+			scale := s.gain / 16 * float64(s.integration) / float64(402*time.Millisecond)
+			value := this.light
+			if reg == regTSL2561Data1 {
+				value = this.ir
+			}
+			return le(math.Min(value*scale, s.full)), nil
+		}
+	}
+	return nil, gopi.ErrUnexpectedResponse
+}
+
+func (this *meter) bh1750(data []byte, n int) ([]byte, error) {
+	if n == 0 {
+		switch c := data[0]; {
+		case c&0xF8 == bh1750MTHigh:
+			this.mt = this.mt&0x1F | c&0x07<<5
+		case c&0xE0 == bh1750MTLow:
+			this.mt = this.mt&0xE0 | c&0x1F
+		case c == bh1750OnceH || c == bh1750OnceH2:
+			this.mode = c
+		}
+		return nil, nil
+	}
+	counts := this.light * 1.2 * float64(this.mt) / bh1750MTNormal
+	if this.mode == bh1750OnceH2 {
+		counts *= 2
+	}
+	value := uint16(math.Min(counts, 0xFFFF))
+	return []byte{byte(value >> 8), byte(value)}, nil
+}
+
+// veml7700 writes the configuration register, and reads the light with
+// the configuration
+func (this *meter) veml7700(slave uint8, n int) ([]byte, error) {
+	if n == 0 {
+		return nil, gopi.ErrNotImplemented
+	}
+	for _, s := range veml7700Settings {
+		if s.config == this.config(slave) {
+			return le(math.Min(this.light/s.resolution(), 0xFFFF)), nil
+		}
+	}
+	return nil, gopi.ErrUnexpectedResponse
+}
+
+// config returns the VEML7700 configuration register
+func (this *meter) config(slave uint8) uint16 {
+	data := append(this.Get(slave, regVEML7700Config), 0, 0)
+	return binary.LittleEndian.Uint16(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// UNITS
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (this *i2c) New(gopi.Config) error {
+	this.meter = newMeter("bh1750", 0x23, 100, 0)
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func init() {
+	sleep = func(ctx context.Context, _ time.Duration) error {
+		return ctx.Err()
+	}
+	graph.RegisterUnit(reflect.TypeOf(&i2c{}), reflect.TypeOf((*gopi.I2C)(nil)))
+}
+
+func Test_Lux_001(t *testing.T) {
+	// TSL2561 with infrared at a fifth of the broadband count
+	bus := newMeter("tsl2561", 0x39, 1000, 200)
+	sensor, err := newTSL2561(context.Background(), bus, 1, 0x39)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read(t, sensor, 0, 23.88, 0)
+
+	// Saturates at the most sensitive setting
+	bus.light, bus.ir = 60000, 12000
+	read(t, sensor, 0, 1432.8, 1)
+}
+
+func Test_Lux_002(t *testing.T) {
+	bus := newMeter("bh1750", 0x23, 100, 0)
+	sensor, err := newBH1750(context.Background(), bus, 1, 0x23)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read(t, sensor, 0, 100, 0)
+
+	// Bright light uses a shorter measurement time, and dim light
+	// returns to the most sensitive setting
+	bus.light = 20000
+	read(t, sensor, 0, 20000, 1)
+	bus.light = 100
+	read(t, sensor, 1, 100, 0)
+}
+
+func Test_Lux_003(t *testing.T) {
+	bus := newMeter("veml7700", 0x10, 50, 0)
+	sensor, err := newVEML7700(context.Background(), bus, 1, 0x10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read(t, sensor, 0, 50, 0)
+
+	bus.light = 800
+	read(t, sensor, 0, 800, 1)
+	if config := bus.config(0x10); config != veml7700Settings[1].config {
+		t.Errorf("Unexpected configuration 0x%04X", config)
+	}
+}
+
+func Test_Lux_004(t *testing.T) {
+	// Response is corrected above 1000 lux
+	if lux := veml7700Lux(500); lux != 500 {
+		t.Error("Unexpected value", lux)
+	} else if lux := veml7700Lux(10000); math.Abs(lux-14792.9) > 1 {
+		t.Error("Unexpected value", lux)
+	}
+
+	// Sensor is not found at another address
+	bus := newMeter("bh1750", 0x23, 0, 0)
+	if _, err := newBH1750(context.Background(), bus, 1, 0x5C); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func Test_Lux_005(t *testing.T) {
+	// Readings are emitted as events, and the setting changes when the
+	// lights are switched on
+	args := []string{"-lux.chip", "bh1750", "-lux.interval", "50ms"}
+	tool.Test(t, args, new(App), func(app *App) {
+		meter := app.I2C.(*i2c).meter
+		tool.NewScenario().
+			ExpectEvent(200*time.Millisecond, "100 lux", isLux(100)).
+			At(200*time.Millisecond, "lights on", func() error {
+				meter.SetLight(20000)
+				return nil
+			}).
+			ExpectEvent(500*time.Millisecond, "20000 lux", isLux(20000)).
+			Test(t, app.Publisher)
+		if value, valid := app.LightSensor.Lux(); valid == false || math.Abs(float64(value)-20000) > 200 {
+			t.Error("Unexpected value", value)
+		}
+		t.Log(app.LightSensor)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// isLux returns a function which returns true for a light event with a
+// value
+func isLux(expected float64) func(gopi.Event) bool {
+	return func(evt gopi.Event) bool {
+		if evt, ok := evt.(gopi.LightEvent); ok == false {
+			return false
+		} else {
+			return math.Abs(float64(evt.Lux())-expected) <= expected/100
+		}
+	}
+}
+
+func read(t *testing.T, s sensor, setting int, expected float64, next int) {
+	t.Helper()
+	if value, setting, err := measure(context.Background(), s, setting); err != nil {
+		t.Error(err)
+	} else if math.Abs(float64(value)-expected) > expected/100 {
+		t.Errorf("Unexpected value %v, expected %v", value, expected)
+	} else if setting != next {
+		t.Errorf("Unexpected setting %v, expected %v", setting, next)
+	}
+}
+
+func le(value float64) []byte {
+	data := make([]byte, 2)
+	binary.LittleEndian.PutUint16(data, uint16(value))
+	return data
+}
@@ -0,0 +1,115 @@
+package lux
+
+import (
+	"context"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// sensor measures illuminance with a number of gain and integration time
+// settings, from the most sensitive to the least sensitive
+type sensor interface {
+	// Sensitivity returns the relative sensitivity of each setting
+	Sensitivity() []float64
+
+	// Read measures at a setting, and returns the illuminance in lux and
+	// the largest count as a fraction of full scale
+	Read(context.Context, int) (float32, float64, error)
+}
+
+// device is a slave on an I2C bus
+type device struct {
+	gopi.I2C
+	bus   gopi.I2CBus
+	slave uint8
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Fraction of full scale at which a reading is saturated, and a less
+	// sensitive setting is used
+	saturated = 0.9
+
+	// Fraction of full scale below which a more sensitive setting is used
+	// when it would not be saturated
+	headroom = 0.8
+)
+
+var (
+	// sleep waits for a measurement, and is replaced in tests
+	sleep = func(ctx context.Context, d time.Duration) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// measure reads a sensor at a setting, and changes the setting when the
+// reading is saturated or would fit a more sensitive setting. Saturated
+// readings are repeated at the less sensitive setting, and the next
+// setting is returned with the illuminance
+func measure(ctx context.Context, s sensor, setting int) (float32, int, error) {
+	sensitivity := s.Sensitivity()
+	for {
+		value, scale, err := s.Read(ctx, setting)
+		if err != nil {
+			return 0, setting, err
+		}
+		switch {
+		case scale >= saturated && setting < len(sensitivity)-1:
+			setting++
+			continue
+		case setting > 0 && scale*sensitivity[setting-1]/sensitivity[setting] < headroom:
+			setting--
+		}
+		return value, setting, nil
+	}
+}
+
+func (this *device) detect() error {
+	if detected, err := this.I2C.DetectSlave(this.bus, this.slave); err != nil {
+		return err
+	} else if detected == false {
+		return gopi.ErrNotFound.WithPrefix("I2C slave ", this.slave)
+	} else {
+		return nil
+	}
+}
+
+// write sends bytes to the slave
+func (this *device) write(ctx context.Context, data ...byte) error {
+	if err := this.I2C.SetSlave(this.bus, this.slave); err != nil {
+		return err
+	} else if _, err := this.I2C.TransferContext(ctx, this.bus, data, 0); err != nil {
+		return err
+	} else {
+		return nil
+	}
+}
+
+// read sends bytes to the slave and then reads a number of bytes
+func (this *device) read(ctx context.Context, data []byte, n int) ([]byte, error) {
+	if err := this.I2C.SetSlave(this.bus, this.slave); err != nil {
+		return nil, err
+	} else if result, err := this.I2C.TransferContext(ctx, this.bus, data, n); err != nil {
+		return nil, err
+	} else if len(result) != n {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("I2C slave ", this.slave)
+	} else {
+		return result, nil
+	}
+}
@@ -0,0 +1,148 @@
+package lux
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://cdn-shop.adafruit.com/datasheets/TSL2561.pdf
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type tsl2561 struct {
+	device
+	setting int
+}
+
+// tsl2561Setting is a gain and integration time
+type tsl2561Setting struct {
+	timing      uint8         // Value of the timing register
+	gain        float64       // Gain of 16 or 1
+	integration time.Duration // Integration time
+	full        float64       // Count at full scale
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	tsl2561Command = 0x80
+	tsl2561Word    = 0x20
+
+	regTSL2561Control = 0x00
+	regTSL2561Timing  = 0x01
+	regTSL2561ID      = 0x0A
+	regTSL2561Data0   = 0x0C // Visible and infrared
+	regTSL2561Data1   = 0x0E // Infrared
+
+	tsl2561PowerOn = 0x03
+	tsl2561Gain16  = 0x10
+)
+
+var (
+	// Settings from the most sensitive, where the count at full scale is
+	// limited by the integration time
+	tsl2561Settings = []tsl2561Setting{
+		{tsl2561Gain16 | 0x02, 16, 402 * time.Millisecond, 65535},
+		{tsl2561Gain16 | 0x01, 16, 101 * time.Millisecond, 37177},
+		{0x02, 1, 402 * time.Millisecond, 65535},
+		{0x01, 1, 101 * time.Millisecond, 37177},
+		{0x00, 1, 13700 * time.Microsecond, 5047},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newTSL2561(ctx context.Context, i2c gopi.I2C, bus gopi.I2CBus, slave uint8) (*tsl2561, error) {
+	this := &tsl2561{device{i2c, bus, slave}, -1}
+	if err := this.detect(); err != nil {
+		return nil, err
+	}
+
+	// Check the part number, which is zero or one for the TSL2560 and
+	// four or five for the TSL2561
+	if id, err := this.read(ctx, []byte{tsl2561Command | regTSL2561ID}, 1); err != nil {
+		return nil, err
+	} else if part := id[0] >> 4; part != 0x0 && part != 0x1 && part != 0x4 && part != 0x5 {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("TSL2561 part number ", part)
+	}
+
+	// Power on, so that the sensor integrates continuously
+	if err := this.write(ctx, tsl2561Command|regTSL2561Control, tsl2561PowerOn); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *tsl2561) Sensitivity() []float64 {
+	result := make([]float64, len(tsl2561Settings))
+	for i, setting := range tsl2561Settings {
+		result[i] = setting.gain * float64(setting.integration) / float64(402*time.Millisecond)
+	}
+	return result
+}
+
+// Read returns the illuminance from the visible and infrared channels,
+// waiting for an integration when the setting is changed
+func (this *tsl2561) Read(ctx context.Context, setting int) (float32, float64, error) {
+	s := tsl2561Settings[setting]
+	if setting != this.setting {
+		if err := this.write(ctx, tsl2561Command|regTSL2561Timing, s.timing); err != nil {
+			return 0, 0, err
+		} else if err := sleep(ctx, s.integration+s.integration/10); err != nil {
+			return 0, 0, err
+		}
+		this.setting = setting
+	}
+
+	ch0, err := this.read(ctx, []byte{tsl2561Command | tsl2561Word | regTSL2561Data0}, 2)
+	if err != nil {
+		return 0, 0, err
+	}
+	ch1, err := this.read(ctx, []byte{tsl2561Command | tsl2561Word | regTSL2561Data1}, 2)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	broadband, infrared := float64(binary.LittleEndian.Uint16(ch0)), float64(binary.LittleEndian.Uint16(ch1))
+	scale := math.Max(broadband, infrared) / s.full
+	return tsl2561Lux(broadband, infrared, s), scale, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// tsl2561Lux returns the illuminance from the counts of each channel,
+// with the empirical formula for the T, FN and CL packages, after scaling
+// the counts to a gain of 16 and integration time of 402ms
+func tsl2561Lux(broadband, infrared float64, s tsl2561Setting) float32 {
+	if broadband == 0 {
+		return 0
+	}
+	scale := 16 / s.gain * float64(402*time.Millisecond) / float64(s.integration)
+	ch0, ch1 := broadband*scale, infrared*scale
+
+	var lux float64
+	switch ratio := ch1 / ch0; {
+	case ratio <= 0.50:
+		lux = 0.0304*ch0 - 0.062*ch0*math.Pow(ratio, 1.4)
+	case ratio <= 0.61:
+		lux = 0.0224*ch0 - 0.031*ch1
+	case ratio <= 0.80:
+		lux = 0.0128*ch0 - 0.0153*ch1
+	case ratio <= 1.30:
+		lux = 0.00146*ch0 - 0.00112*ch1
+	}
+	return float32(math.Max(lux, 0))
+}
@@ -0,0 +1,123 @@
+package lux
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://www.vishay.com/docs/84286/veml7700.pdf
+// Ref: https://www.vishay.com/docs/84323/designingveml7700.pdf
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type veml7700 struct {
+	device
+	setting int
+}
+
+// veml7700Setting is a gain and integration time
+type veml7700Setting struct {
+	config      uint16        // Gain and integration time bits of the configuration
+	gain        float64       // Gain of 1/8, 1/4, 1 or 2
+	integration time.Duration // Integration time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	regVEML7700Config = 0x00
+	regVEML7700PSM    = 0x03
+	regVEML7700ALS    = 0x04
+
+	veml7700Gain1   = 0x00 << 11
+	veml7700Gain2   = 0x01 << 11
+	veml7700Gain8th = 0x02 << 11
+	veml7700Gain4th = 0x03 << 11
+
+	veml7700Resolution = 0.0036 // Lux per count at a gain of 2 and 800ms
+	veml7700Linear     = 1000   // Lux above which the response is corrected
+	veml7700Time       = 800 * time.Millisecond
+)
+
+var (
+	// Settings from the most sensitive
+	veml7700Settings = []veml7700Setting{
+		{veml7700Gain2 | 0x03<<6, 2, 800 * time.Millisecond},
+		{veml7700Gain2 | 0x01<<6, 2, 200 * time.Millisecond},
+		{veml7700Gain1 | 0x00<<6, 1, 100 * time.Millisecond},
+		{veml7700Gain4th | 0x00<<6, 0.25, 100 * time.Millisecond},
+		{veml7700Gain8th | 0x0C<<6, 0.125, 25 * time.Millisecond},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newVEML7700(ctx context.Context, i2c gopi.I2C, bus gopi.I2CBus, slave uint8) (*veml7700, error) {
+	this := &veml7700{device{i2c, bus, slave}, -1}
+	if err := this.detect(); err != nil {
+		return nil, err
+	} else if err := this.writeRegister(ctx, regVEML7700PSM, 0); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *veml7700) Sensitivity() []float64 {
+	result := make([]float64, len(veml7700Settings))
+	for i, setting := range veml7700Settings {
+		result[i] = 1 / setting.resolution()
+	}
+	return result
+}
+
+// Read returns the illuminance from the ambient light channel, waiting
+// for an integration when the setting is changed
+func (this *veml7700) Read(ctx context.Context, setting int) (float32, float64, error) {
+	s := veml7700Settings[setting]
+	if setting != this.setting {
+		if err := this.writeRegister(ctx, regVEML7700Config, s.config); err != nil {
+			return 0, 0, err
+		} else if err := sleep(ctx, s.integration+s.integration/10); err != nil {
+			return 0, 0, err
+		}
+		this.setting = setting
+	}
+
+	data, err := this.read(ctx, []byte{regVEML7700ALS}, 2)
+	if err != nil {
+		return 0, 0, err
+	}
+	count := float64(binary.LittleEndian.Uint16(data))
+	return float32(veml7700Lux(count * s.resolution())), count / 0xFFFF, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *veml7700) writeRegister(ctx context.Context, reg uint8, value uint16) error {
+	return this.write(ctx, reg, byte(value), byte(value>>8))
+}
+
+// resolution returns the lux per count
+func (s veml7700Setting) resolution() float64 {
+	return veml7700Resolution * 2 / s.gain * float64(veml7700Time) / float64(s.integration)
+}
+
+// veml7700Lux corrects the response above 1000 lux, which is not linear
+func veml7700Lux(lux float64) float64 {
+	if lux <= veml7700Linear {
+		return lux
+	}
+	return ((6.0135e-13*lux-9.3924e-9)*lux+8.1488e-5)*lux*lux + 1.0023*lux
+}
@@ -0,0 +1,132 @@
+package rtl433
+
+import (
+	"math"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// pulse is the width of a pulse of carrier and the gap which follows
+// it, in microseconds
+type pulse struct {
+	width, gap int
+}
+
+// packet is a train of pulses which ends with a long gap
+type packet struct {
+	pulses []pulse
+	rssi   float32
+}
+
+// demod detects on-off keyed pulses in 8-bit unsigned I/Q samples
+type demod struct {
+	rate  float64 // Samples per microsecond
+	reset int     // Samples of gap which end a packet
+
+	noise   float64
+	high    bool
+	samples int
+	sum     float64
+	count   int
+	pulses  []pulse
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Power of a full scale sample
+	fullScale = 127.5 * 127.5
+
+	// Minimum power of a pulse, so that noise is ignored when there is
+	// no signal
+	minLevel = 16 * 16
+
+	// Ratio of pulse power to noise power (9dB)
+	noiseRatio = 8
+
+	// Number of samples over which the noise level is averaged
+	noiseWindow = 256
+
+	// Gap which ends a packet, in microseconds
+	resetGap = 10000
+
+	// Maximum number of pulses in a packet
+	maxPulses = 1200
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newDemod(rate uint32) *demod {
+	this := new(demod)
+	this.rate = float64(rate) / 1e6
+	this.reset = int(resetGap * this.rate)
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// process detects pulses in interleaved I and Q samples, and calls a
+// function with each packet
+func (this *demod) process(data []byte, fn func(*packet)) {
+	for i := 0; i+1 < len(data); i += 2 {
+		di, dq := float64(data[i])-127.5, float64(data[i+1])-127.5
+		power := di*di + dq*dq
+		threshold := math.Max(this.noise*noiseRatio, minLevel)
+		if this.high {
+			if power < threshold/2 {
+				// Falling edge
+				this.pulses = append(this.pulses, pulse{width: this.us(this.samples)})
+				this.high, this.samples = false, 0
+			} else if this.samples > this.reset {
+				// A continuous carrier raises the noise level
+				this.noise = power
+				this.high, this.samples, this.pulses = false, 0, nil
+				this.sum, this.count = 0, 0
+			} else {
+				this.sum, this.count = this.sum+power, this.count+1
+			}
+		} else if power > threshold {
+			// Rising edge
+			if n := len(this.pulses); n > 0 {
+				this.pulses[n-1].gap = this.us(this.samples)
+			}
+			this.high, this.samples = true, 0
+			this.sum, this.count = this.sum+power, this.count+1
+		} else {
+			this.noise += (power - this.noise) / noiseWindow
+			if len(this.pulses) > 0 && this.samples >= this.reset {
+				this.flush(fn)
+			}
+		}
+		if len(this.pulses) >= maxPulses {
+			this.flush(fn)
+		}
+		this.samples++
+	}
+}
+
+// flush calls a function with any pulses which have been detected
+func (this *demod) flush(fn func(*packet)) {
+	if len(this.pulses) == 0 {
+		return
+	}
+	p := &packet{pulses: this.pulses}
+	p.pulses[len(p.pulses)-1].gap = this.us(this.samples)
+	if this.count > 0 {
+		p.rssi = float32(10 * math.Log10(this.sum/float64(this.count)/fullScale))
+	}
+	this.pulses, this.sum, this.count = nil, 0, 0
+	fn(p)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// us returns a number of samples in microseconds
+func (this *demod) us(samples int) int {
+	return int(float64(samples)/this.rate + 0.5)
+}
@@ -0,0 +1,18 @@
+// +build !rtlsdr
+
+package rtl433
+
+import (
+	"io"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// openDevice returns an error as librtlsdr support is not compiled in,
+// use -tags rtlsdr
+func openDevice(uint, uint32, uint32, float64, int) (io.ReadCloser, error) {
+	return nil, gopi.ErrNotImplemented.WithPrefix("RTL-SDR (use -tags rtlsdr)")
+}
@@ -0,0 +1,58 @@
+// +build rtlsdr
+
+package rtl433
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	rtlsdr "github.com/djthorpe/gopi/v3/pkg/sys/rtlsdr"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type device struct {
+	*rtlsdr.Device
+	index uint
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// openDevice opens and tunes a receiver, with gain in dB or zero for
+// automatic gain
+func openDevice(index uint, freq, rate uint32, gain float64, ppm int) (io.ReadCloser, error) {
+	if index >= rtlsdr.DeviceCount() {
+		return nil, fmt.Errorf("RTL-SDR device %v not found", index)
+	}
+	dev, err := rtlsdr.Open(index)
+	if err != nil {
+		return nil, err
+	}
+	if err := dev.SetSampleRate(rate); err != nil {
+		dev.Close()
+		return nil, err
+	} else if err := dev.SetFreqCorrection(ppm); err != nil {
+		dev.Close()
+		return nil, err
+	} else if err := dev.SetCenterFreq(freq); err != nil {
+		dev.Close()
+		return nil, err
+	} else if err := dev.SetTunerGain(int(math.Round(gain * 10))); err != nil {
+		dev.Close()
+		return nil, err
+	} else if err := dev.ResetBuffer(); err != nil {
+		dev.Close()
+		return nil, err
+	}
+	return &device{dev, index}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *device) String() string {
+	return fmt.Sprintf("<rtlsdr index=%v name=%q>", this.index, rtlsdr.DeviceName(this.index))
+}
@@ -0,0 +1,25 @@
+// RTL433 package implements gopi.RFSensors, which receives wireless
+// weather stations, remote thermometers and tire pressure sensors on
+// 433MHz or 868MHz with an RTL-SDR receiver, in the same way as rtl_433.
+//
+// Samples are read from an RTL-SDR device, which requires librtlsdr and
+// -tags rtlsdr when building, or from a file of 8-bit unsigned I/Q
+// samples set with the -rtl433.file flag, such as a file recorded with
+// rtl_sdr. On-off keyed pulses are detected above the noise level and
+// decoded by each protocol in turn:
+//
+//	prologue          Prologue temperature and humidity sensors
+//	nexus             Nexus temperature and humidity sensors
+//	lacrosse-tx141th  LaCrosse TX141TH-Bv2 temperature and humidity sensors
+//	schrader          Schrader tire pressure sensors
+//
+// The protocols can be restricted with the -rtl433.protocols flag.
+// Sensors repeat each transmission, so a gopi.RFEvent is emitted when
+// a reading is received which differs from the last reading from the
+// same sensor, or when the last reading is older than a few seconds.
+//
+// Samples are dropped when they are not read quickly enough, which happens
+// under system load. Set the -rtl433.priority flag to read samples with
+// realtime scheduling, which requires the gopi.Scheduler unit in the
+// process package.
+package rtl433
@@ -0,0 +1,39 @@
+package rtl433
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	reading gopi.RFReading
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(reading gopi.RFReading) gopi.RFEvent {
+	return &event{reading}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.reading.Key()
+}
+
+func (this *event) Reading() gopi.RFReading {
+	return this.reading
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	return fmt.Sprint("<rtl433.event ", this.reading, ">")
+}
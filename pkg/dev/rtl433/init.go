@@ -0,0 +1,13 @@
+package rtl433
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.RFSensors
+	graph.RegisterUnit(reflect.TypeOf(&rtl433{}), reflect.TypeOf((*gopi.RFSensors)(nil)))
+}
@@ -0,0 +1,50 @@
+package rtl433
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// LaCrosse TX141TH pulses are 208us for a one and 417us for a
+	// zero, and rows are separated by four 833us sync pulses
+	lacrosseShort = 208
+	lacrosseLong  = 417
+	lacrosseBits  = 40
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// DECODE
+
+// decodeLaCrosseTX141TH decodes LaCrosse TX141TH-Bv2 temperature and
+// humidity sensors. There are 40 bits in each row:
+//
+//	IIIIIIII BTCC TTTTTTTTTTTT HHHHHHHH DDDDDDDD
+//
+// which are the identifier, battery low, test button, channel,
+// temperature in tenths of a degree offset by 50 degrees, humidity and
+// an LFSR digest of the first four bytes
+func decodeLaCrosseTX141TH(p *packet) (gopi.RFReading, bool) {
+	row := repeatedRow(slicePWM(p, lacrosseShort, lacrosseLong), 2, lacrosseBits)
+	if row == nil {
+		return gopi.RFReading{}, false
+	}
+	data := row.bytes(0, 5)
+	if lfsrDigest8Reflect(data[:4], 0x31, 0xF4) != data[4] {
+		return gopi.RFReading{}, false
+	}
+	humidity := row.uint(24, 8)
+	if humidity > 100 {
+		return gopi.RFReading{}, false
+	}
+	return gopi.RFReading{
+		Id:          row.uint(0, 8),
+		Channel:     uint(row.uint(10, 2)) + 1,
+		Temperature: (float32(row.uint(12, 12)) - 500) / 10,
+		Humidity:    float32(humidity),
+		BatteryLow:  row[8] != 0,
+		Fields:      gopi.RF_FIELD_CHANNEL | gopi.RF_FIELD_TEMPERATURE | gopi.RF_FIELD_HUMIDITY | gopi.RF_FIELD_BATTERY,
+	}, true
+}
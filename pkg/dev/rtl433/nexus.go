@@ -0,0 +1,84 @@
+package rtl433
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Nexus and Prologue pulses are 500us, followed by a 1000us gap
+	// for a zero, a 2000us gap for a one and a 4000us gap between rows
+	nexusShort = 1000
+	nexusLong  = 2000
+	nexusBits  = 36
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// DECODE
+
+// decodeNexus decodes Nexus temperature and humidity sensors, which
+// are sold under many brands. There are 36 bits in each row:
+//
+//	IIIIIIII BxCC TTTTTTTTTTTT 1111 HHHHHHHH
+//
+// which are the identifier, battery ok, channel, temperature in tenths
+// of a degree, a constant and humidity, which is zero when there is no
+// humidity sensor
+func decodeNexus(p *packet) (gopi.RFReading, bool) {
+	row := repeatedRow(slicePPM(p, nexusShort, nexusLong), 2, nexusBits)
+	if row == nil || row.uint(24, 4) != 0xF {
+		return gopi.RFReading{}, false
+	}
+	humidity := row.uint(28, 8)
+	if humidity > 100 {
+		return gopi.RFReading{}, false
+	}
+	reading := gopi.RFReading{
+		Id:          row.uint(0, 8),
+		Channel:     uint(row.uint(10, 2)) + 1,
+		Temperature: float32(row.int(12, 12)) / 10,
+		BatteryLow:  row[8] == 0,
+		Fields:      gopi.RF_FIELD_CHANNEL | gopi.RF_FIELD_TEMPERATURE | gopi.RF_FIELD_BATTERY,
+	}
+	if humidity > 0 {
+		reading.Humidity = float32(humidity)
+		reading.Fields |= gopi.RF_FIELD_HUMIDITY
+	}
+	return reading, true
+}
+
+// decodePrologue decodes Prologue temperature and humidity sensors,
+// which use the same timing as Nexus sensors. There are 36 bits in
+// each row:
+//
+//	TTTT IIIIIIII BxCC TTTTTTTTTTTT HHHHHHHH
+//
+// which are the type (5 or 9), the identifier, battery ok, channel,
+// temperature in tenths of a degree and humidity, which is 0xCC when
+// there is no humidity sensor
+func decodePrologue(p *packet) (gopi.RFReading, bool) {
+	row := repeatedRow(slicePPM(p, nexusShort, nexusLong), 2, nexusBits)
+	if row == nil {
+		return gopi.RFReading{}, false
+	} else if t := row.uint(0, 4); t != 0x5 && t != 0x9 {
+		return gopi.RFReading{}, false
+	}
+	humidity := row.uint(28, 8)
+	if humidity > 100 && humidity != 0xCC {
+		return gopi.RFReading{}, false
+	}
+	reading := gopi.RFReading{
+		Id:          row.uint(4, 8),
+		Channel:     uint(row.uint(14, 2)) + 1,
+		Temperature: float32(row.int(16, 12)) / 10,
+		BatteryLow:  row[12] == 0,
+		Fields:      gopi.RF_FIELD_CHANNEL | gopi.RF_FIELD_TEMPERATURE | gopi.RF_FIELD_BATTERY,
+	}
+	if humidity != 0xCC {
+		reading.Humidity = float32(humidity)
+		reading.Fields |= gopi.RF_FIELD_HUMIDITY
+	}
+	return reading, true
+}
@@ -0,0 +1,63 @@
+package rtl433
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// protocol decodes a reading from a packet, or returns false
+type protocol struct {
+	name   string
+	decode func(*packet) (gopi.RFReading, bool)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+// protocols are tried in order until a packet is decoded
+var protocols = []protocol{
+	{"prologue", decodePrologue},
+	{"nexus", decodeNexus},
+	{"lacrosse-tx141th", decodeLaCrosseTX141TH},
+	{"schrader", decodeSchrader},
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CHECKSUMS
+
+// crc8 returns the CRC of bytes, most significant bit first
+func crc8(data []byte, poly, init uint8) uint8 {
+	crc := init
+	for _, b := range data {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ poly
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// lfsrDigest8Reflect returns the LFSR based digest of bytes, processed
+// from the last byte to the first and least significant bit first
+func lfsrDigest8Reflect(data []byte, gen, key uint8) uint8 {
+	sum := uint8(0)
+	for k := len(data) - 1; k >= 0; k-- {
+		for i := uint(0); i < 8; i++ {
+			if data[k]>>i&1 != 0 {
+				sum ^= key
+			}
+			if key&0x80 != 0 {
+				key = key<<1 ^ gen
+			} else {
+				key <<= 1
+			}
+		}
+	}
+	return sum
+}
@@ -0,0 +1,179 @@
+package rtl433
+
+import (
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Protocol_001(t *testing.T) {
+	// Nexus id=0x2A battery=ok channel=2 temperature=-5.3 humidity=45
+	row := toBits(0x2A, 8, 0x9, 4, 0x1000-53, 12, 0xF, 4, 45, 8)
+	reading, ok := decodeNexus(ppm(row, 3))
+	if ok == false {
+		t.Fatal("Expected reading")
+	} else if reading.Id != 0x2A || reading.Channel != 2 || reading.Temperature != -5.3 || reading.Humidity != 45 || reading.BatteryLow {
+		t.Error("Unexpected reading", reading)
+	} else if reading.Fields != gopi.RF_FIELD_CHANNEL|gopi.RF_FIELD_TEMPERATURE|gopi.RF_FIELD_HUMIDITY|gopi.RF_FIELD_BATTERY {
+		t.Error("Unexpected fields", reading.Fields)
+	}
+
+	// A single row is not enough
+	if _, ok := decodeNexus(ppm(row, 1)); ok {
+		t.Error("Unexpected reading from one row")
+	}
+
+	// Prologue does not decode a Nexus packet
+	if _, ok := decodePrologue(ppm(row, 3)); ok {
+		t.Error("Unexpected prologue reading")
+	}
+}
+
+func Test_Protocol_002(t *testing.T) {
+	// Prologue type=9 id=0xB3 battery=low channel=3 temperature=21.7 no humidity
+	row := toBits(0x9, 4, 0xB3, 8, 0x2, 4, 217, 12, 0xCC, 8)
+	reading, ok := decodePrologue(ppm(row, 4))
+	if ok == false {
+		t.Fatal("Expected reading")
+	} else if reading.Id != 0xB3 || reading.Channel != 3 || reading.Temperature != 21.7 || reading.BatteryLow == false {
+		t.Error("Unexpected reading", reading)
+	} else if reading.Fields&gopi.RF_FIELD_HUMIDITY != 0 {
+		t.Error("Unexpected fields", reading.Fields)
+	}
+}
+
+func Test_Protocol_003(t *testing.T) {
+	// LaCrosse id=0x7C battery=ok channel=1 temperature=18.4 humidity=62
+	data := []byte{0x7C, 0x02, 0xAC, 62}
+	data = append(data, lfsrDigest8Reflect(data, 0x31, 0xF4))
+	reading, ok := decodeLaCrosseTX141TH(pwm(data, 3))
+	if ok == false {
+		t.Fatal("Expected reading")
+	} else if reading.Id != 0x7C || reading.Channel != 1 || reading.Temperature != 18.4 || reading.Humidity != 62 || reading.BatteryLow {
+		t.Error("Unexpected reading", reading)
+	}
+
+	// Bad digest
+	data[4] ^= 0x01
+	if _, ok := decodeLaCrosseTX141TH(pwm(data, 3)); ok {
+		t.Error("Unexpected reading with bad digest")
+	}
+}
+
+func Test_Protocol_004(t *testing.T) {
+	// Schrader id=0x1234567 pressure=230kPa temperature=25
+	data := []byte{0xF0, 0x01, 0x23, 0x45, 0x67, 92, 75}
+	data = append(data, crc8(data, 0x07, 0xF0))
+	row := toBits(0x7, 4)
+	for _, b := range data {
+		row = append(row, toBits(uint32(b), 8)...)
+	}
+	reading, ok := decodeSchrader(manchester(row))
+	if ok == false {
+		t.Fatal("Expected reading")
+	} else if reading.Id != 0x1234567 || reading.Pressure != 230 || reading.Temperature != 25 {
+		t.Error("Unexpected reading", reading)
+	}
+
+	// Bad CRC
+	data[7] ^= 0xFF
+	row = toBits(0x7, 4)
+	for _, b := range data {
+		row = append(row, toBits(uint32(b), 8)...)
+	}
+	if _, ok := decodeSchrader(manchester(row)); ok {
+		t.Error("Unexpected reading with bad CRC")
+	}
+}
+
+func Test_Protocol_005(t *testing.T) {
+	if row := (bits{1, 1, 1, 1, 0, 1, 1, 0}); row.int(0, 4) != -1 || row.int(4, 4) != 6 || row.uint(0, 8) != 0xF6 {
+		t.Error("Unexpected values", row)
+	}
+	if crc := crc8([]byte("123456789"), 0x07, 0x00); crc != 0xF4 {
+		t.Errorf("Unexpected CRC 0x%02X", crc)
+	}
+	if protocols, err := decoders(" nexus, Schrader "); err != nil || len(protocols) != 2 {
+		t.Error("Unexpected protocols", protocols, err)
+	} else if _, err := decoders("nexus,unknown"); err == nil {
+		t.Error("Expected error for unknown protocol")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// toBits returns bits from pairs of values and lengths
+func toBits(values ...uint32) bits {
+	row := bits{}
+	for i := 0; i+1 < len(values); i += 2 {
+		for n := int(values[i+1]) - 1; n >= 0; n-- {
+			row = append(row, uint8(values[i]>>uint(n)&1))
+		}
+	}
+	return row
+}
+
+// ppm returns a packet with rows repeated with Nexus timing
+func ppm(row bits, repeat int) *packet {
+	p := new(packet)
+	for i := 0; i < repeat; i++ {
+		for _, bit := range row {
+			gap := nexusShort
+			if bit == 1 {
+				gap = nexusLong
+			}
+			p.pulses = append(p.pulses, pulse{500, gap})
+		}
+		p.pulses = append(p.pulses, pulse{500, 4000})
+	}
+	p.pulses[len(p.pulses)-1].gap = resetGap
+	return p
+}
+
+// pwm returns a packet with bytes repeated with LaCrosse timing
+func pwm(data []byte, repeat int) *packet {
+	p := new(packet)
+	for i := 0; i < repeat; i++ {
+		for j := 0; j < 4; j++ {
+			p.pulses = append(p.pulses, pulse{833, 833})
+		}
+		for _, b := range data {
+			for _, bit := range toBits(uint32(b), 8) {
+				if bit == 1 {
+					p.pulses = append(p.pulses, pulse{lacrosseShort, lacrosseLong})
+				} else {
+					p.pulses = append(p.pulses, pulse{lacrosseLong, lacrosseShort})
+				}
+			}
+		}
+	}
+	p.pulses[len(p.pulses)-1].gap = resetGap
+	return p
+}
+
+// manchester returns a packet with a Manchester encoded row which
+// starts with a zero bit
+func manchester(row bits) *packet {
+	levels := []uint8{}
+	for _, bit := range row {
+		levels = append(levels, bit, 1-bit)
+	}
+	p := new(packet)
+	for i := 1; i < len(levels); {
+		n := 0
+		for level := levels[i]; i < len(levels) && levels[i] == level; i++ {
+			n++
+		}
+		if levels[i-1] == 1 {
+			p.pulses = append(p.pulses, pulse{width: n * schraderHalf})
+		} else {
+			p.pulses[len(p.pulses)-1].gap = n * schraderHalf
+		}
+	}
+	p.pulses[len(p.pulses)-1].gap = resetGap
+	return p
+}
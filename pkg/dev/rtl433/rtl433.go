@@ -0,0 +1,271 @@
+package rtl433
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type rtl433 struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Scheduler
+	sync.RWMutex
+
+	// Flags
+	index     *uint
+	file      *string
+	freq      *uint
+	rate      *uint
+	gain      *float64
+	ppm       *int
+	protocols *string
+	priority  *uint
+
+	source   io.ReadCloser
+	demod    *demod
+	decoders []protocol
+	readings map[string]gopi.RFReading
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Bytes read from the source at once
+	bufferSize = 16 * 16384
+
+	// Readings which are the same as the last reading within this
+	// duration are repeats
+	repeatDelta = 3 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *rtl433) Define(cfg gopi.Config) error {
+	this.index = cfg.FlagUint("rtl433.device", 0, "RTL-SDR device index")
+	this.file = cfg.FlagPath("rtl433.file", "", "File of 8-bit unsigned I/Q samples to read instead of a device")
+	this.freq = cfg.FlagUint("rtl433.freq", 433920000, "Receive frequency in Hz")
+	this.rate = cfg.FlagUint("rtl433.rate", 250000, "Sample rate in Hz")
+	this.gain = cfg.FlagFloat("rtl433.gain", 0, "Tuner gain in dB, or zero for automatic gain")
+	this.ppm = cfg.FlagInt("rtl433.ppm", 0, "Frequency correction in parts per million")
+	this.protocols = cfg.FlagString("rtl433.protocols", "", "Comma-separated protocols to decode, or empty for all")
+	this.priority = cfg.FlagUint("rtl433.priority", 0, "Realtime priority between 1 and 99 for reading samples, or zero for normal scheduling")
+	return nil
+}
+
+func (this *rtl433) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if *this.rate == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-rtl433.rate")
+	} else if *this.freq == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-rtl433.freq")
+	} else if *this.gain < 0 {
+		return gopi.ErrBadParameter.WithPrefix("-rtl433.gain")
+	} else if *this.priority > 99 {
+		return gopi.ErrBadParameter.WithPrefix("-rtl433.priority")
+	} else if *this.priority > 0 && this.Scheduler == nil {
+		return gopi.ErrInternalAppError.WithPrefix("Missing gopi.Scheduler for -rtl433.priority")
+	}
+
+	// Set protocols
+	if decoders, err := decoders(*this.protocols); err != nil {
+		return err
+	} else {
+		this.decoders = decoders
+	}
+
+	// Open source
+	if *this.file != "" {
+		if fh, err := os.Open(*this.file); err != nil {
+			return err
+		} else {
+			this.source = fh
+		}
+	} else if source, err := openDevice(*this.index, uint32(*this.freq), uint32(*this.rate), *this.gain, *this.ppm); err != nil {
+		return err
+	} else {
+		this.source = source
+	}
+
+	this.demod = newDemod(uint32(*this.rate))
+	this.readings = make(map[string]gopi.RFReading)
+
+	// Return success
+	return nil
+}
+
+func (this *rtl433) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	var result error
+	if this.source != nil {
+		result = this.source.Close()
+	}
+
+	// Release resources
+	this.source = nil
+	this.demod = nil
+	this.decoders = nil
+	this.readings = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *rtl433) Run(ctx context.Context) error {
+	// Read samples with elevated scheduling so they are not dropped under load
+	if *this.priority > 0 {
+		flags, restore := this.Scheduler.Realtime(int(*this.priority))
+		defer restore()
+		this.Debug("RTL433: Scheduling ", flags)
+	}
+
+	buf := make([]byte, bufferSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+			n, err := this.source.Read(buf)
+			this.demod.process(buf[:n&^1], this.decode)
+			if err == io.EOF {
+				// End of file
+				this.demod.flush(this.decode)
+				<-ctx.Done()
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *rtl433) Frequency() uint32 {
+	return uint32(*this.freq)
+}
+
+func (this *rtl433) Protocols() []string {
+	result := make([]string, len(this.decoders))
+	for i, decoder := range this.decoders {
+		result[i] = decoder.name
+	}
+	return result
+}
+
+func (this *rtl433) Readings() []gopi.RFReading {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	keys := make([]string, 0, len(this.readings))
+	for key := range this.readings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]gopi.RFReading, len(keys))
+	for i, key := range keys {
+		result[i] = this.readings[key]
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *rtl433) String() string {
+	str := "<rtl433"
+	if *this.file != "" {
+		str += fmt.Sprintf(" file=%q", *this.file)
+	} else {
+		str += fmt.Sprint(" device=", *this.index)
+	}
+	str += fmt.Sprint(" freq=", this.Frequency())
+	str += fmt.Sprintf(" protocols=%q", this.Protocols())
+	for _, reading := range this.Readings() {
+		str += fmt.Sprint(" ", reading)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// decoders returns protocols from a comma-separated list of names, or
+// all protocols when the list is empty
+func decoders(names string) ([]protocol, error) {
+	if strings.TrimSpace(names) == "" {
+		return protocols, nil
+	}
+	result := []protocol{}
+FOR_LOOP:
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for _, protocol := range protocols {
+			if protocol.name == name {
+				result = append(result, protocol)
+				continue FOR_LOOP
+			}
+		}
+		return nil, gopi.ErrBadParameter.WithPrefix("-rtl433.protocols: ", name)
+	}
+	return result, nil
+}
+
+// decode decodes a packet and emits an event unless the reading repeats
+// the last reading from the sensor
+func (this *rtl433) decode(p *packet) {
+	for _, decoder := range this.decoders {
+		reading, ok := decoder.decode(p)
+		if ok == false {
+			continue
+		}
+		reading.Protocol = decoder.name
+		reading.Time = time.Now()
+		reading.RSSI = p.rssi
+		if this.update(reading) {
+			this.Debug("RTL433: ", reading)
+			if this.Publisher != nil {
+				if err := this.Publisher.Emit(NewEvent(reading), false); err != nil {
+					this.Debug("RTL433: ", err)
+				}
+			}
+		}
+		return
+	}
+}
+
+// update records a reading and returns false if it is a repeat
+func (this *rtl433) update(reading gopi.RFReading) bool {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	key := reading.Key()
+	last, exists := this.readings[key]
+	this.readings[key] = reading
+	if exists && reading.Time.Sub(last.Time) < repeatDelta {
+		last.Time, last.RSSI = reading.Time, reading.RSSI
+		return last != reading
+	}
+	return true
+}
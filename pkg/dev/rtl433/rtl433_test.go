@@ -0,0 +1,125 @@
+package rtl433_test
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/dev/rtl433"
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/process"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.RFSensors
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	sampleRate = 250000
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_RTL433_001(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rtl433")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Two transmissions from a Nexus sensor id=0x2A channel=1
+	// temperature=22.5 humidity=51 with noise before, between and after
+	path := filepath.Join(dir, "samples.cu8")
+	samples := gap(nil, 50000)
+	for i := 0; i < 2; i++ {
+		samples = nexus(samples, "00101010"+"1000"+"000011100001"+"1111"+"00110011")
+		samples = gap(samples, 50000)
+	}
+	if err := ioutil.WriteFile(path, samples, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{"-rtl433.file", path, "-rtl433.protocols", "nexus", "-rtl433.priority", "10", "-sched.mlock=false"}
+	tool.Test(t, args, new(App), func(app *App) {
+		if protocols := app.RFSensors.Protocols(); len(protocols) != 1 || protocols[0] != "nexus" {
+			t.Error("Unexpected protocols", protocols)
+		}
+
+		// Wait for the file to be read
+		var readings []gopi.RFReading
+		for i := 0; i < 100 && len(readings) == 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+			readings = app.RFSensors.Readings()
+		}
+		if len(readings) != 1 {
+			t.Error("Unexpected readings", readings)
+		} else if reading := readings[0]; reading.Key() != "nexus/42/1" {
+			t.Error("Unexpected key", reading.Key())
+		} else if reading.Temperature != 22.5 || reading.Humidity != 51 || reading.BatteryLow {
+			t.Error("Unexpected reading", reading)
+		} else if reading.RSSI > 0 || reading.RSSI < -10 {
+			t.Error("Unexpected RSSI", reading.RSSI)
+		} else {
+			t.Log(app.RFSensors)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// nexus appends a row repeated three times with Nexus timing
+func nexus(samples []byte, row string) []byte {
+	for i := 0; i < 3; i++ {
+		for _, bit := range row {
+			samples = carrier(samples, 500)
+			if bit == '1' {
+				samples = gap(samples, 2000)
+			} else {
+				samples = gap(samples, 1000)
+			}
+		}
+		samples = carrier(samples, 500)
+		samples = gap(samples, 4000)
+	}
+	return samples
+}
+
+// carrier appends I/Q samples of a carrier offset from the center
+// frequency by 10kHz
+func carrier(samples []byte, us int) []byte {
+	for i := 0; i < us*sampleRate/1e6; i++ {
+		phase := 2 * math.Pi * 10000 * float64(len(samples)/2) / sampleRate
+		samples = append(samples, byte(127.5+100*math.Cos(phase)), byte(127.5+100*math.Sin(phase)))
+	}
+	return samples
+}
+
+// gap appends I/Q samples of low level noise
+func gap(samples []byte, us int) []byte {
+	for i := 0; i < us*sampleRate/1e6; i++ {
+		samples = append(samples, byte(127+i%2), byte(128-i%3))
+	}
+	return samples
+}
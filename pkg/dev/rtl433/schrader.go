@@ -0,0 +1,48 @@
+package rtl433
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Schrader tire pressure sensors are Manchester encoded with 120us
+	// half bits
+	schraderHalf = 120
+	schraderBits = 68
+	schraderSync = 0x7F
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// DECODE
+
+// decodeSchrader decodes Schrader tire pressure sensors, which are
+// fitted to many cars. There are 68 bits in a row:
+//
+//	0111 1111 FFFFFFFF IIII...IIII PPPPPPPP TTTTTTTT CCCCCCCC
+//
+// which are a sync nibble, a constant, flags, a 28-bit identifier,
+// pressure in units of 2.5 kPa, temperature offset by 50 degrees and
+// a CRC of all bytes after the sync nibble
+func decodeSchrader(p *packet) (gopi.RFReading, bool) {
+	for _, row := range sliceManchester(p, schraderHalf) {
+		for pos := 0; pos+schraderBits <= len(row); pos++ {
+			if row.uint(pos, 8) != schraderSync {
+				continue
+			}
+			data := row.bytes(pos+4, 8)
+			if crc8(data[:7], 0x07, 0xF0) != data[7] {
+				continue
+			}
+			return gopi.RFReading{
+				Id:          row.uint(pos+16, 28),
+				Pressure:    float32(data[5]) * 2.5,
+				Temperature: float32(data[6]) - 50,
+				Fields:      gopi.RF_FIELD_PRESSURE | gopi.RF_FIELD_TEMPERATURE,
+			}, true
+		}
+	}
+	return gopi.RFReading{}, false
+}
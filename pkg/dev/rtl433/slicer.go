@@ -0,0 +1,170 @@
+package rtl433
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// bits is a row of bits, one bit in each byte
+type bits []uint8
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// uint returns n bits from a position, most significant bit first
+func (row bits) uint(pos, n int) uint32 {
+	value := uint32(0)
+	for _, bit := range row[pos : pos+n] {
+		value = value<<1 | uint32(bit)
+	}
+	return value
+}
+
+// int returns n bits from a position as a signed value
+func (row bits) int(pos, n int) int32 {
+	value := row.uint(pos, n)
+	if row[pos] != 0 {
+		return int32(value) - int32(1)<<uint(n)
+	}
+	return int32(value)
+}
+
+// bytes returns n bytes from a position
+func (row bits) bytes(pos, n int) []byte {
+	result := make([]byte, n)
+	for i := range result {
+		result[i] = byte(row.uint(pos+i*8, 8))
+	}
+	return result
+}
+
+// equal returns true if two rows are the same
+func (row bits) equal(other bits) bool {
+	return string(row) == string(other)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SLICERS
+
+// slicePPM returns rows of bits from the gaps between pulses, where a
+// short gap is a zero and a long gap is a one. Any other gap ends a row
+func slicePPM(p *packet, short, long int) []bits {
+	rows := []bits{}
+	row := bits{}
+	tolerance := (long - short) / 2
+	for _, pulse := range p.pulses {
+		if abs(pulse.gap-short) < tolerance {
+			row = append(row, 0)
+		} else if abs(pulse.gap-long) < tolerance {
+			row = append(row, 1)
+		} else if len(row) > 0 {
+			rows, row = append(rows, row), bits{}
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// slicePWM returns rows of bits from the widths of pulses, where a
+// short pulse is a one and a long pulse is a zero. Any other width, or
+// a gap longer than twice the long width, ends a row
+func slicePWM(p *packet, short, long int) []bits {
+	rows := []bits{}
+	row := bits{}
+	tolerance := (long - short) / 2
+	for _, pulse := range p.pulses {
+		if abs(pulse.width-short) < tolerance {
+			row = append(row, 1)
+		} else if abs(pulse.width-long) < tolerance {
+			row = append(row, 0)
+		} else if len(row) > 0 {
+			rows, row = append(rows, row), bits{}
+			continue
+		}
+		if pulse.gap > long*2 && len(row) > 0 {
+			rows, row = append(rows, row), bits{}
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// sliceManchester returns rows of Manchester encoded bits, where a
+// zero is low then high and a one is high then low. Each row starts
+// with a zero bit, the first half of which is before the first pulse.
+// Pulses and gaps which are not one or two half bits long end a row
+func sliceManchester(p *packet, half int) []bits {
+	rows := []bits{}
+	levels := []uint8{0}
+	end := func() {
+		levels = append(levels, 0)
+		row := bits{}
+		for i := 0; i+1 < len(levels); i += 2 {
+			if levels[i] == 0 && levels[i+1] == 1 {
+				row = append(row, 0)
+			} else if levels[i] == 1 && levels[i+1] == 0 {
+				row = append(row, 1)
+			} else {
+				break
+			}
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+		levels = []uint8{0}
+	}
+	for _, pulse := range p.pulses {
+		n := (pulse.width + half/2) / half
+		if n < 1 || n > 2 {
+			end()
+			continue
+		}
+		for ; n > 0; n-- {
+			levels = append(levels, 1)
+		}
+		n = (pulse.gap + half/2) / half
+		if n < 1 || n > 2 {
+			end()
+			continue
+		}
+		for ; n > 0; n-- {
+			levels = append(levels, 0)
+		}
+	}
+	if len(levels) > 1 {
+		end()
+	}
+	return rows
+}
+
+// repeatedRow returns the first row of n bits which appears at least
+// count times, ignoring a trailing bit, or nil
+func repeatedRow(rows []bits, count, n int) bits {
+	for i, row := range rows {
+		if len(row) != n && len(row) != n+1 {
+			continue
+		}
+		matches := 0
+		for _, other := range rows[i:] {
+			if (len(other) == n || len(other) == n+1) && other[:n].equal(row[:n]) {
+				matches++
+			}
+		}
+		if matches >= count {
+			return row[:n]
+		}
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
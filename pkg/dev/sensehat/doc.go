@@ -0,0 +1,14 @@
+// Sensehat package implements gopi.SenseHAT for the Raspberry Pi Sense
+// HAT. The LED matrix is an 8x8 gopi.Bitmap which is written to the
+// rpisense-fb framebuffer when Update is called, so a bitmap factory
+// for SURFACE_FMT_RGBA32 must be imported. The -sensehat.rotation flag
+// rotates both the matrix and the joystick directions.
+//
+// Joystick presses are emitted as gopi.InputEvent with device type
+// INPUT_DEVICE_JOYSTICK, and the humidity, pressure and inertial
+// sensors are read over I2C and emitted as gopi.SenseHATEvent, and as
+// a measurement when gopi.Metrics is available.
+//
+// The framebuffer and joystick are detected by name, or can be set
+// with the -sensehat.fb and -sensehat.joystick flags.
+package sensehat
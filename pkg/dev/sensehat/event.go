@@ -0,0 +1,73 @@
+package sensehat
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	sensors gopi.SenseHATSensors
+}
+
+type inputevent struct {
+	key gopi.KeyCode
+	t   gopi.InputType
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(sensors gopi.SenseHATSensors) gopi.SenseHATEvent {
+	return &event{sensors}
+}
+
+func NewInputEvent(key gopi.KeyCode, t gopi.InputType) gopi.InputEvent {
+	return &inputevent{key, t}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "sensehat"
+}
+
+func (this *event) Sensors() gopi.SenseHATSensors {
+	return this.sensors
+}
+
+func (this *inputevent) Name() string {
+	return "sensehat"
+}
+
+func (this *inputevent) Key() gopi.KeyCode {
+	return this.key
+}
+
+func (this *inputevent) Type() gopi.InputType {
+	return this.t
+}
+
+// Device returns the joystick and the key code
+func (this *inputevent) Device() (gopi.InputDeviceType, uint32) {
+	return gopi.INPUT_DEVICE_JOYSTICK, uint32(this.key)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	return fmt.Sprint("<sensehat.event ", this.sensors, ">")
+}
+
+func (this *inputevent) String() string {
+	str := "<event.input"
+	str += fmt.Sprintf(" name=%q", this.Name())
+	str += " type=" + fmt.Sprint(this.t)
+	str += " key=" + fmt.Sprint(this.key)
+	return str + ">"
+}
@@ -0,0 +1,13 @@
+package sensehat
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.SenseHAT
+	graph.RegisterUnit(reflect.TypeOf(&sensehat{}), reflect.TypeOf((*gopi.SenseHAT)(nil)))
+}
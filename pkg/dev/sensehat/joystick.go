@@ -0,0 +1,89 @@
+package sensehat
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://www.kernel.org/doc/html/latest/input/input.html#event-interface
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	joystickName  = "Raspberry Pi Sense HAT Joystick"
+	joystickClass = "/sys/class/input"
+)
+
+const (
+	evKey         = 0x01
+	evValueUp     = 0
+	evValueDown   = 1
+	evValueRepeat = 2
+)
+
+var (
+	// Size of struct input_event, which starts with a timeval
+	evSize = int(unsafe.Sizeof(syscall.Timeval{})) + 8
+
+	// Joystick directions in clockwise order, so that they can be
+	// rotated with the matrix
+	directions = []gopi.KeyCode{
+		gopi.KEYCODE_UP, gopi.KEYCODE_RIGHT, gopi.KEYCODE_DOWN, gopi.KEYCODE_LEFT,
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// findJoystick returns the event device for the joystick
+func findJoystick() (string, error) {
+	return findDevice(joystickClass, "event*", "device/name", joystickName)
+}
+
+// readJoystick reads key events until the device is closed, and calls
+// a function for each event
+func readJoystick(r io.Reader, rotation uint, fn func(gopi.Event)) error {
+	buf := make([]byte, evSize)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+		t := binary.LittleEndian.Uint16(buf[evSize-8:])
+		code := binary.LittleEndian.Uint16(buf[evSize-6:])
+		value := int32(binary.LittleEndian.Uint32(buf[evSize-4:]))
+		if t != evKey {
+			continue
+		}
+		key := rotateKey(gopi.KeyCode(code), rotation)
+		switch value {
+		case evValueDown:
+			fn(NewInputEvent(key, gopi.INPUT_EVENT_KEYPRESS))
+		case evValueUp:
+			fn(NewInputEvent(key, gopi.INPUT_EVENT_KEYRELEASE))
+		case evValueRepeat:
+			fn(NewInputEvent(key, gopi.INPUT_EVENT_KEYREPEAT))
+		}
+	}
+}
+
+// rotateKey returns the direction relative to the matrix when it is
+// rotated clockwise
+func rotateKey(key gopi.KeyCode, rotation uint) gopi.KeyCode {
+	for i, direction := range directions {
+		if direction == key {
+			return directions[(i+len(directions)-int(rotation/90))%len(directions)]
+		}
+	}
+	return key
+}
+
+// openJoystick opens the event device for reading
+func openJoystick(path string) (*os.File, error) {
+	return os.Open(path)
+}
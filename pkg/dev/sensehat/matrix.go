@@ -0,0 +1,85 @@
+package sensehat
+
+import (
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://github.com/raspberrypi/linux/blob/rpi-5.10.y/drivers/video/fbdev/rpisense-fb.c
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	matrixSize = 8
+	fbName     = "RPi-Sense FB"
+	fbClass    = "/sys/class/graphics"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// findFramebuffer returns the device for the LED matrix framebuffer
+func findFramebuffer() (string, error) {
+	return findDevice(fbClass, "fb*", "name", fbName)
+}
+
+// findDevice returns the device node for a device class entry with
+// a name, or ErrNotFound
+func findDevice(class, pattern, file, name string) (string, error) {
+	entries, err := filepath.Glob(filepath.Join(class, pattern))
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if data, err := ioutil.ReadFile(filepath.Join(entry, file)); err != nil {
+			continue
+		} else if strings.TrimSpace(string(data)) == name {
+			return filepath.Join("/dev", filepath.Base(entry)), nil
+		}
+	}
+	return "", gopi.ErrNotFound.WithPrefix(name)
+}
+
+// rgb565 returns the framebuffer data for an image, which is rotated
+// clockwise in multiples of ninety degrees. Pixels are two bytes in
+// little-endian order
+func rgb565(img image.Image, rotation uint) []byte {
+	data := make([]byte, matrixSize*matrixSize*2)
+	bounds := img.Bounds()
+	for y := 0; y < matrixSize; y++ {
+		for x := 0; x < matrixSize; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			pixel := uint16(r>>11)<<11 | uint16(g>>10)<<5 | uint16(b>>11)
+			mx, my := rotate(x, y, rotation)
+			i := (my*matrixSize + mx) * 2
+			data[i], data[i+1] = byte(pixel), byte(pixel>>8)
+		}
+	}
+	return data
+}
+
+// rotate returns the position on the matrix for a position on the
+// bitmap
+func rotate(x, y int, rotation uint) (int, int) {
+	switch rotation {
+	case 90:
+		return matrixSize - 1 - y, x
+	case 180:
+		return matrixSize - 1 - x, matrixSize - 1 - y
+	case 270:
+		return y, matrixSize - 1 - x
+	default:
+		return x, y
+	}
+}
+
+// openFramebuffer opens the framebuffer for writing
+func openFramebuffer(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY, 0)
+}
@@ -0,0 +1,268 @@
+package sensehat
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	multierror "github.com/hashicorp/go-multierror"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/i2c"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type sensehat struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Metrics
+	gopi.I2C
+	*bitmap.Bitmaps
+	sync.RWMutex
+
+	// Flags
+	fbpath   *string
+	jspath   *string
+	rotation *uint
+	bus      *uint
+	interval *time.Duration
+
+	measurement string
+	fb          *os.File
+	joystick    *os.File
+	bitmap      gopi.Bitmap
+	sensors     *sensors
+	values      gopi.SenseHATSensors
+	valid       bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *sensehat) Define(cfg gopi.Config) error {
+	this.fbpath = cfg.FlagString("sensehat.fb", "", "LED matrix framebuffer, or empty to detect")
+	this.jspath = cfg.FlagString("sensehat.joystick", "", "Joystick event device, or empty to detect")
+	this.rotation = cfg.FlagUint("sensehat.rotation", 0, "Rotation of LED matrix and joystick (0, 90, 180 or 270)")
+	this.bus = cfg.FlagUint("sensehat.bus", 1, "I2C bus for sensors")
+	this.interval = cfg.FlagDuration("sensehat.interval", time.Second, "Interval between sensor readings, or zero to disable")
+	cfg.FlagString("sensehat.measurement", "sensehat", "Measurement name")
+	return nil
+}
+
+func (this *sensehat) New(cfg gopi.Config) error {
+	this.Require(this.Logger, this.Bitmaps)
+
+	// Check parameters
+	switch *this.rotation {
+	case 0, 90, 180, 270:
+		break
+	default:
+		return gopi.ErrBadParameter.WithPrefix("-sensehat.rotation")
+	}
+
+	// Open LED matrix
+	if *this.fbpath == "" {
+		if path, err := findFramebuffer(); err != nil {
+			return err
+		} else {
+			*this.fbpath = path
+		}
+	}
+	if fb, err := openFramebuffer(*this.fbpath); err != nil {
+		return err
+	} else {
+		this.fb = fb
+	}
+	if bitmap, err := this.Bitmaps.NewBitmap(gopi.SURFACE_FMT_RGBA32, matrixSize, matrixSize); err != nil {
+		return err
+	} else {
+		this.bitmap = bitmap
+	}
+
+	// Open joystick, which is optional
+	if *this.jspath == "" {
+		if path, err := findJoystick(); err != nil {
+			this.Debug("SenseHAT: ", err)
+		} else {
+			*this.jspath = path
+		}
+	}
+	if *this.jspath != "" {
+		if joystick, err := openJoystick(*this.jspath); err != nil {
+			return err
+		} else {
+			this.joystick = joystick
+		}
+	}
+
+	// Power on sensors, which are optional
+	if *this.interval > 0 && this.I2C != nil {
+		if sensors, err := newSensors(this.I2C, gopi.I2CBus(*this.bus)); err != nil {
+			this.Print("SenseHAT: Sensors disabled: ", err)
+		} else {
+			this.sensors = sensors
+		}
+	}
+
+	// Define measurement
+	if measurement := cfg.GetString("sensehat.measurement"); measurement != "" && this.Metrics != nil && this.sensors != nil {
+		if m, err := this.Metrics.NewMeasurement(measurement, "temperature float32, humidity float32, pressure float32", this.Metrics.HostTag()); err != nil {
+			return err
+		} else {
+			this.measurement = m.Name()
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *sensehat) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Clear LED matrix
+	var result error
+	if this.fb != nil {
+		if _, err := this.fb.WriteAt(make([]byte, matrixSize*matrixSize*2), 0); err != nil {
+			result = multierror.Append(result, err)
+		}
+		if err := this.fb.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	if this.joystick != nil {
+		if err := this.joystick.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Release resources
+	this.fb = nil
+	this.joystick = nil
+	this.bitmap = nil
+	this.sensors = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *sensehat) Run(ctx context.Context) error {
+	// Read joystick in the background, until the device is closed
+	if this.joystick != nil {
+		go func(joystick *os.File) {
+			if err := readJoystick(joystick, *this.rotation, this.emit); err != nil && ctx.Err() == nil {
+				this.Print("SenseHAT: ", err)
+			}
+		}(this.joystick)
+	}
+
+	// Read sensors
+	var ticker <-chan time.Time
+	if this.sensors != nil {
+		t := time.NewTicker(*this.interval)
+		defer t.Stop()
+		ticker = t.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker:
+			if err := this.read(); err != nil {
+				this.Debug("SenseHAT: ", err)
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *sensehat) Bitmap() gopi.Bitmap {
+	return this.bitmap
+}
+
+func (this *sensehat) Update() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if this.fb == nil {
+		return gopi.ErrOutOfOrder.WithPrefix("Update")
+	} else if _, err := this.fb.WriteAt(rgb565(this.bitmap, *this.rotation), 0); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *sensehat) Sensors() (gopi.SenseHATSensors, bool) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.values, this.valid
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *sensehat) String() string {
+	str := "<sensehat"
+	str += fmt.Sprintf(" fb=%q", *this.fbpath)
+	if this.joystick != nil {
+		str += fmt.Sprintf(" joystick=%q", *this.jspath)
+	}
+	if *this.rotation != 0 {
+		str += fmt.Sprint(" rotation=", *this.rotation)
+	}
+	if values, valid := this.Sensors(); valid {
+		str += fmt.Sprint(" ", values)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// read reads the sensors, then emits an event and a measurement
+func (this *sensehat) read() error {
+	values, err := this.sensors.Read()
+	if err != nil {
+		return err
+	}
+
+	this.RWMutex.Lock()
+	this.values, this.valid = values, true
+	this.RWMutex.Unlock()
+
+	// Emit event
+	this.emit(NewEvent(values))
+
+	// Emit measurement
+	if this.measurement != "" {
+		if err := this.Metrics.Emit(this.measurement, nil, values.Temperature, values.Humidity, values.Pressure); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *sensehat) emit(evt gopi.Event) {
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(evt, false); err != nil {
+			this.Debug("SenseHAT: ", err)
+		}
+	}
+}
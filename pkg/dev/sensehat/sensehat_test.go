@@ -0,0 +1,178 @@
+// +build linux
+
+package sensehat_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/dev/sensehat"
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32"
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/platform"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.SenseHAT
+	gopi.Publisher
+	*bitmap.Bitmaps
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_SenseHAT_001(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	fb := filepath.Join(dir, "fb")
+	if err := ioutil.WriteFile(fb, make([]byte, 128), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{"-sensehat.fb", fb, "-sensehat.interval", "0"}
+	tool.Test(t, args, new(App), func(app *App) {
+		t.Log(app.SenseHAT)
+		bitmap := app.SenseHAT.Bitmap()
+		if size := bitmap.Size(); size.W != 8 || size.H != 8 {
+			t.Error("Unexpected size", size)
+		}
+		bitmap.ClearToColor(color.Black)
+		bitmap.SetAt(color.RGBA{0xFF, 0, 0, 0xFF}, 0, 0)
+		bitmap.SetAt(color.RGBA{0, 0, 0xFF, 0xFF}, 7, 0)
+		bitmap.SetAt(color.RGBA{0, 0xFF, 0, 0xFF}, 0, 7)
+		if err := app.SenseHAT.Update(); err != nil {
+			t.Error(err)
+		}
+		data, _ := ioutil.ReadFile(fb)
+		if pixel(data, 0, 0) != 0xF800 || pixel(data, 7, 0) != 0x001F || pixel(data, 0, 7) != 0x07E0 || pixel(data, 1, 1) != 0 {
+			t.Errorf("Unexpected data % X", data)
+		}
+		if _, valid := app.SenseHAT.Sensors(); valid {
+			t.Error("Unexpected sensor readings")
+		}
+	})
+
+	// Matrix is cleared when disposed
+	if data, _ := ioutil.ReadFile(fb); bytes.Equal(data, make([]byte, 128)) == false {
+		t.Errorf("Unexpected data % X", data)
+	}
+}
+
+func Test_SenseHAT_002(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	fb := filepath.Join(dir, "fb")
+	if err := ioutil.WriteFile(fb, make([]byte, 128), 0644); err != nil {
+		t.Fatal(err)
+	}
+	joystick := fifo(t, dir)
+
+	// Open for writing first, so that opening for reading does not block
+	w, err := os.OpenFile(joystick, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	args := []string{"-sensehat.fb", fb, "-sensehat.joystick", joystick, "-sensehat.interval", "0", "-sensehat.rotation", "90"}
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Top left of bitmap is top right of matrix
+		bitmap := app.SenseHAT.Bitmap()
+		bitmap.ClearToColor(color.Black)
+		bitmap.SetAt(color.White, 0, 0)
+		if err := app.SenseHAT.Update(); err != nil {
+			t.Error(err)
+		}
+		if data, _ := ioutil.ReadFile(fb); pixel(data, 7, 0) != 0xFFFF || pixel(data, 0, 0) != 0 {
+			t.Errorf("Unexpected data % X", data)
+		}
+
+		// Joystick right is up when rotated, and ignore sync events
+		w.Write(inputEvent(0x00, 0x00, 0))
+		w.Write(inputEvent(0x01, uint16(gopi.KEYCODE_RIGHT), 1))
+		w.Write(inputEvent(0x01, uint16(gopi.KEYCODE_ENTER), 0))
+		for _, expected := range []struct {
+			key gopi.KeyCode
+			t   gopi.InputType
+		}{
+			{gopi.KEYCODE_UP, gopi.INPUT_EVENT_KEYPRESS},
+			{gopi.KEYCODE_ENTER, gopi.INPUT_EVENT_KEYRELEASE},
+		} {
+			select {
+			case evt := <-ch:
+				if evt, ok := evt.(gopi.InputEvent); ok == false {
+					t.Error("Unexpected event", evt)
+				} else if device, _ := evt.Device(); evt.Key() != expected.key || evt.Type() != expected.t || device != gopi.INPUT_DEVICE_JOYSTICK {
+					t.Error("Unexpected event", evt)
+				}
+			case <-time.After(time.Second):
+				t.Error("Timeout waiting for", expected.key)
+			}
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "sensehat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// fifo creates a named pipe to stand in for the joystick
+func fifo(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "event0")
+	if err := syscall.Mkfifo(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// pixel returns the RGB565 value on the matrix
+func pixel(data []byte, x, y int) uint16 {
+	if len(data) != 128 {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(data[(y*8+x)*2:])
+}
+
+// inputEvent returns a struct input_event
+func inputEvent(t, code uint16, value int32) []byte {
+	data := make([]byte, int(unsafe.Sizeof(syscall.Timeval{}))+8)
+	n := len(data) - 8
+	binary.LittleEndian.PutUint16(data[n:], t)
+	binary.LittleEndian.PutUint16(data[n+2:], code)
+	binary.LittleEndian.PutUint32(data[n+4:], uint32(value))
+	return data
+}
@@ -0,0 +1,236 @@
+package sensehat
+
+import (
+	"encoding/binary"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://www.st.com/resource/en/datasheet/hts221.pdf
+// Ref: https://www.st.com/resource/en/datasheet/lps25h.pdf
+// Ref: https://www.st.com/resource/en/datasheet/lsm9ds1.pdf
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type sensors struct {
+	gopi.I2C
+	bus gopi.I2CBus
+
+	// HTS221 calibration
+	h0, h1       float32 // Relative humidity at calibration points
+	h0out, h1out float32
+	t0, t1       float32 // Temperature at calibration points
+	t0out, t1out float32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	slaveHTS221 = 0x5F // Humidity and temperature
+	slaveLPS25H = 0x5C // Pressure
+	slaveAG     = 0x6A // LSM9DS1 accelerometer and gyroscope
+	slaveM      = 0x1C // LSM9DS1 magnetometer
+)
+
+const (
+	// Set the most significant bit of a register to read multiple
+	// bytes from the HTS221, LPS25H and magnetometer
+	regAutoIncrement = 0x80
+
+	// HTS221 registers
+	regHTS221Ctrl1  = 0x20
+	regHTS221Out    = 0x28
+	regHTS221Calib  = 0x30
+	hts221PowerOn1s = 0x85 // Power on, block data update, 1Hz
+
+	// LPS25H registers
+	regLPS25HCtrl1  = 0x20
+	regLPS25HOut    = 0x28
+	lps25hPowerOn1s = 0x90 // Power on, 1Hz
+
+	// LSM9DS1 accelerometer and gyroscope registers
+	regGyroCtrl1  = 0x10
+	regGyroOut    = 0x18
+	regAccelCtrl6 = 0x20
+	regAccelOut   = 0x28
+	gyroODR119    = 0x60 // 119Hz, 245 degrees per second
+	accelODR119   = 0x60 // 119Hz, 2g
+
+	// LSM9DS1 magnetometer registers
+	regMagCtrl1   = 0x20
+	regMagCtrl2   = 0x21
+	regMagCtrl3   = 0x22
+	regMagOut     = 0x28
+	magODR10      = 0x10 // 10Hz
+	magScale4     = 0x00 // 4 gauss
+	magContinuous = 0x00
+)
+
+const (
+	numCalibration = 16      // Number of HTS221 calibration registers
+	pressureScale  = 4096.0  // Counts per hectopascal
+	gyroScale      = 0.00875 // Degrees per second per count
+	accelScale     = 0.000061
+	magScale       = 0.00014
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// newSensors powers on the sensors and reads calibration
+func newSensors(i2c gopi.I2C, bus gopi.I2CBus) (*sensors, error) {
+	this := &sensors{I2C: i2c, bus: bus}
+
+	// Check for sensors
+	for _, slave := range []uint8{slaveHTS221, slaveLPS25H, slaveAG, slaveM} {
+		if detected, err := this.I2C.DetectSlave(bus, slave); err != nil {
+			return nil, err
+		} else if detected == false {
+			return nil, gopi.ErrNotFound.WithPrefix("I2C slave ", slave)
+		}
+	}
+
+	// Power on
+	if err := this.write(slaveHTS221, regHTS221Ctrl1, hts221PowerOn1s); err != nil {
+		return nil, err
+	} else if err := this.write(slaveLPS25H, regLPS25HCtrl1, lps25hPowerOn1s); err != nil {
+		return nil, err
+	} else if err := this.write(slaveAG, regGyroCtrl1, gyroODR119); err != nil {
+		return nil, err
+	} else if err := this.write(slaveAG, regAccelCtrl6, accelODR119); err != nil {
+		return nil, err
+	} else if err := this.write(slaveM, regMagCtrl1, magODR10); err != nil {
+		return nil, err
+	} else if err := this.write(slaveM, regMagCtrl2, magScale4); err != nil {
+		return nil, err
+	} else if err := this.write(slaveM, regMagCtrl3, magContinuous); err != nil {
+		return nil, err
+	}
+
+	// Read humidity calibration
+	if calib, err := this.read(slaveHTS221, regHTS221Calib|regAutoIncrement, numCalibration); err != nil {
+		return nil, err
+	} else {
+		this.calibrate(calib)
+	}
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Read returns sensor values
+func (this *sensors) Read() (gopi.SenseHATSensors, error) {
+	result := gopi.SenseHATSensors{Time: time.Now()}
+
+	// Humidity and temperature
+	if data, err := this.read(slaveHTS221, regHTS221Out|regAutoIncrement, 4); err != nil {
+		return result, err
+	} else {
+		result.Humidity, result.Temperature = this.humidity(data)
+	}
+
+	// Pressure
+	if data, err := this.read(slaveLPS25H, regLPS25HOut|regAutoIncrement, 3); err != nil {
+		return result, err
+	} else {
+		result.Pressure = pressure(data)
+	}
+
+	// Acceleration, rotation and magnetic field
+	if data, err := this.read(slaveAG, regAccelOut, 6); err != nil {
+		return result, err
+	} else {
+		result.Acceleration = vector(data, accelScale)
+	}
+	if data, err := this.read(slaveAG, regGyroOut, 6); err != nil {
+		return result, err
+	} else {
+		result.Gyroscope = vector(data, gyroScale)
+	}
+	if data, err := this.read(slaveM, regMagOut|regAutoIncrement, 6); err != nil {
+		return result, err
+	} else {
+		result.Magnetometer = vector(data, magScale)
+	}
+
+	// Return success
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *sensors) write(slave, reg, value uint8) error {
+	if err := this.I2C.SetSlave(this.bus, slave); err != nil {
+		return err
+	} else {
+		return this.I2C.WriteUint8(this.bus, reg, value)
+	}
+}
+
+func (this *sensors) read(slave, reg, length uint8) ([]byte, error) {
+	if err := this.I2C.SetSlave(this.bus, slave); err != nil {
+		return nil, err
+	} else if data, err := this.I2C.ReadBlock(this.bus, reg, length); err != nil {
+		return nil, err
+	} else if len(data) != int(length) {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("I2C slave ", slave)
+	} else {
+		return data, nil
+	}
+}
+
+// calibrate sets humidity and temperature calibration from registers
+// 0x30 to 0x3F
+func (this *sensors) calibrate(calib []byte) {
+	msb := uint16(calib[0x05])
+	this.h0 = float32(calib[0x00]) / 2
+	this.h1 = float32(calib[0x01]) / 2
+	this.t0 = float32(uint16(calib[0x02])|(msb&0x03)<<8) / 8
+	this.t1 = float32(uint16(calib[0x03])|(msb&0x0C)<<6) / 8
+	this.h0out = float32(int16(binary.LittleEndian.Uint16(calib[0x06:])))
+	this.h1out = float32(int16(binary.LittleEndian.Uint16(calib[0x0A:])))
+	this.t0out = float32(int16(binary.LittleEndian.Uint16(calib[0x0C:])))
+	this.t1out = float32(int16(binary.LittleEndian.Uint16(calib[0x0E:])))
+}
+
+// humidity returns relative humidity and temperature by linear
+// interpolation between calibration points
+func (this *sensors) humidity(data []byte) (float32, float32) {
+	h := float32(int16(binary.LittleEndian.Uint16(data[0:])))
+	t := float32(int16(binary.LittleEndian.Uint16(data[2:])))
+	humidity, temperature := float32(0), float32(0)
+	if this.h1out != this.h0out {
+		humidity = this.h0 + (h-this.h0out)*(this.h1-this.h0)/(this.h1out-this.h0out)
+	}
+	if this.t1out != this.t0out {
+		temperature = this.t0 + (t-this.t0out)*(this.t1-this.t0)/(this.t1out-this.t0out)
+	}
+	if humidity < 0 {
+		humidity = 0
+	} else if humidity > 100 {
+		humidity = 100
+	}
+	return humidity, temperature
+}
+
+// pressure returns hectopascals from a 24-bit signed value
+func pressure(data []byte) float32 {
+	value := int32(uint32(data[0])|uint32(data[1])<<8|uint32(data[2])<<16) << 8 >> 8
+	return float32(value) / pressureScale
+}
+
+// vector returns X, Y and Z from 16-bit signed values
+func vector(data []byte, scale float32) [3]float32 {
+	var result [3]float32
+	for i := range result {
+		result[i] = float32(int16(binary.LittleEndian.Uint16(data[i*2:]))) * scale
+	}
+	return result
+}
@@ -48,9 +48,9 @@ const (
 // LIFECYCLE
 
 func (this *Manager) Define(cfg gopi.Config) error {
-	this.key = cfg.FlagString("tradfri.key", "", "Tradfri Gateway Key (Security Code)")
+	this.key = cfg.FlagSecret("tradfri.key", "", "Tradfri Gateway Key (Security Code)")
 	this.timeout = cfg.FlagDuration("tradfri.timeout", DEFAULT_TIMEOUT, "Connection Timeout")
-	this.path = cfg.FlagString("tradfri.path", "", "Path to configuration")
+	this.path = cfg.FlagPath("tradfri.path", "", "Path to configuration")
 	return nil
 }
 
@@ -0,0 +1,21 @@
+// UPS package implements gopi.UPS, which reads the fuel gauge on
+// uninterruptible power supply HATs for the Raspberry Pi. The fuel gauge
+// is set with the -ups.chip flag:
+//
+//	ina219    INA219 current and voltage monitor, as on the Waveshare UPS
+//	          HAT (address 0x42). Charge is estimated from the voltage of
+//	          the cells in series set with -ups.cells
+//	max17040  MAX17040 or MAX17043 fuel gauge, as on the Geekworm X728
+//	          and UPS-Lite (address 0x36)
+//
+// The INA219 measures current, so the unit is on battery when the
+// battery is discharging. Otherwise, the power source is read from a
+// GPIO pin set with -ups.gpio which is high when mains power is lost,
+// or low when -ups.gpio.invert is set. A gopi.I2C unit is required,
+// and a gopi.GPIO unit when a pin is set.
+//
+// A gopi.UPSEvent is emitted on each reading and when the source of
+// power changes. When the -ups.shutdown flag is set, a shutdown event
+// is emitted and the -ups.command is run when the charge on battery
+// falls to the threshold, so that the Raspberry Pi shuts down cleanly.
+package ups
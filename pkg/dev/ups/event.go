@@ -0,0 +1,44 @@
+package ups
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t      gopi.UPSEventType
+	status gopi.UPSStatus
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(t gopi.UPSEventType, status gopi.UPSStatus) gopi.UPSEvent {
+	return &event{t, status}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "ups"
+}
+
+func (this *event) Type() gopi.UPSEventType {
+	return this.t
+}
+
+func (this *event) Status() gopi.UPSStatus {
+	return this.status
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	return fmt.Sprint("<ups.event type=", this.t, " ", this.status, ">")
+}
@@ -0,0 +1,78 @@
+package ups
+
+import (
+	"context"
+	"encoding/binary"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// gauge reads voltage, current and state of charge, and returns
+// false for current when it is not measured
+type gauge interface {
+	Read(context.Context) (float32, float32, float32, bool, error)
+}
+
+// device is a slave on an I2C bus with 16-bit big-endian registers
+type device struct {
+	gopi.I2C
+	bus   gopi.I2CBus
+	slave uint8
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *device) detect() error {
+	if detected, err := this.I2C.DetectSlave(this.bus, this.slave); err != nil {
+		return err
+	} else if detected == false {
+		return gopi.ErrNotFound.WithPrefix("I2C slave ", this.slave)
+	} else {
+		return nil
+	}
+}
+
+func (this *device) write(reg uint8, value uint16) error {
+	return this.I2C.Batch(this.bus, func(tx gopi.I2CTx) error {
+		if err := tx.SetSlave(this.slave); err != nil {
+			return err
+		} else if _, err := tx.Write([]byte{reg, byte(value >> 8), byte(value)}); err != nil {
+			return err
+		} else {
+			return nil
+		}
+	})
+}
+
+func (this *device) read(ctx context.Context, reg uint8) (uint16, error) {
+	if err := this.I2C.SetSlave(this.bus, this.slave); err != nil {
+		return 0, err
+	} else if data, err := this.I2C.TransferContext(ctx, this.bus, []byte{reg}, 2); err != nil {
+		return 0, err
+	} else if len(data) != 2 {
+		return 0, gopi.ErrUnexpectedResponse.WithPrefix("I2C slave ", this.slave)
+	} else {
+		return binary.BigEndian.Uint16(data), nil
+	}
+}
+
+// charge returns the state of charge in percent estimated from cell
+// voltage
+func charge(voltage float32, cells uint) float32 {
+	const (
+		empty = 3.0 // Volts per cell
+		full  = 4.2
+	)
+	value := (voltage/float32(cells) - empty) / (full - empty) * 100
+	if value < 0 {
+		return 0
+	} else if value > 100 {
+		return 100
+	} else {
+		return value
+	}
+}
@@ -0,0 +1,63 @@
+package ups
+
+import (
+	"context"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://www.ti.com/lit/ds/symlink/ina219.pdf
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type ina219 struct {
+	device
+	shunt float32 // Shunt resistor in ohms
+	cells uint    // Number of cells in series
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	regINA219Config = 0x00
+	regINA219Shunt  = 0x01
+	regINA219Bus    = 0x02
+
+	// 32V bus range, 320mV shunt range, 12-bit continuous conversion
+	ina219Config = 0x399F
+
+	ina219ShuntLSB = 10e-6 // Volts per count
+	ina219BusLSB   = 4e-3  // Volts per count
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newINA219(i2c gopi.I2C, bus gopi.I2CBus, slave uint8, shunt float32, cells uint) (*ina219, error) {
+	this := &ina219{device{i2c, bus, slave}, shunt, cells}
+	if err := this.detect(); err != nil {
+		return nil, err
+	} else if err := this.write(regINA219Config, ina219Config); err != nil {
+		return nil, err
+	}
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *ina219) Read(ctx context.Context) (float32, float32, float32, bool, error) {
+	shunt, err := this.read(ctx, regINA219Shunt)
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	bus, err := this.read(ctx, regINA219Bus)
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	voltage := float32(bus>>3) * ina219BusLSB
+	current := float32(int16(shunt)) * ina219ShuntLSB / this.shunt
+	return voltage, current, charge(voltage, this.cells), true, nil
+}
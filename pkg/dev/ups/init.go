@@ -0,0 +1,13 @@
+package ups
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.UPS
+	graph.RegisterUnit(reflect.TypeOf(&ups{}), reflect.TypeOf((*gopi.UPS)(nil)))
+}
@@ -0,0 +1,58 @@
+package ups
+
+import (
+	"context"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://datasheets.maximintegrated.com/en/ds/MAX17040-MAX17041.pdf
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type max17040 struct {
+	device
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	regMAX17040VCell = 0x02
+	regMAX17040SOC   = 0x04
+
+	max17040VCellLSB = 1.25e-3 // Volts per count
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newMAX17040(i2c gopi.I2C, bus gopi.I2CBus, slave uint8) (*max17040, error) {
+	this := &max17040{device{i2c, bus, slave}}
+	if err := this.detect(); err != nil {
+		return nil, err
+	}
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Read returns cell voltage and state of charge from the ModelGauge
+// algorithm. Current is not measured
+func (this *max17040) Read(ctx context.Context) (float32, float32, float32, bool, error) {
+	vcell, err := this.read(ctx, regMAX17040VCell)
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	soc, err := this.read(ctx, regMAX17040SOC)
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	charge := float32(soc) / 256
+	if charge > 100 {
+		charge = 100
+	}
+	return float32(vcell>>4) * max17040VCellLSB, 0, charge, false, nil
+}
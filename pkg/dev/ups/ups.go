@@ -0,0 +1,279 @@
+package ups
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type ups struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Metrics
+	gopi.I2C
+	gopi.GPIO
+	sync.RWMutex
+
+	// Flags
+	chip     *string
+	bus      *uint
+	slave    *uint
+	shunt    *float64
+	cells    *uint
+	pin      *int
+	invert   *bool
+	interval *time.Duration
+	shutdown *float64
+	command  *string
+
+	measurement string
+	gauge       gauge
+	status      gopi.UPSStatus
+	valid       bool
+	halted      bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Current below which power is from the battery, when there is no
+	// power loss pin
+	batteryCurrent = -0.05
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *ups) Define(cfg gopi.Config) error {
+	this.chip = cfg.FlagString("ups.chip", "ina219", "Fuel gauge (ina219 or max17040)")
+	this.bus = cfg.FlagUint("ups.bus", 1, "I2C bus")
+	this.slave = cfg.FlagUint("ups.addr", 0, "I2C address, or zero for the default address")
+	this.shunt = cfg.FlagFloat("ups.shunt", 0.1, "INA219 shunt resistor in ohms")
+	this.cells = cfg.FlagUint("ups.cells", 2, "INA219 number of cells in series")
+	this.pin = cfg.FlagInt("ups.gpio", -1, "GPIO pin which is high on power loss, or -1")
+	this.invert = cfg.FlagBool("ups.gpio.invert", false, "GPIO pin is low on power loss")
+	this.interval = cfg.FlagDuration("ups.interval", 5*time.Second, "Interval between readings")
+	this.shutdown = cfg.FlagFloat("ups.shutdown", 0, "Charge in percent on battery at which to shut down, or zero to disable")
+	this.command = cfg.FlagString("ups.command", "shutdown -h now", "Command to shut down")
+	cfg.FlagString("ups.measurement", "ups", "Measurement name")
+	return nil
+}
+
+func (this *ups) New(cfg gopi.Config) error {
+	this.Require(this.Logger, this.I2C)
+
+	// Check parameters
+	if *this.interval <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-ups.interval")
+	} else if *this.shutdown < 0 || *this.shutdown > 100 {
+		return gopi.ErrBadParameter.WithPrefix("-ups.shutdown")
+	} else if *this.shutdown > 0 && strings.TrimSpace(*this.command) == "" {
+		return gopi.ErrBadParameter.WithPrefix("-ups.command")
+	} else if *this.pin >= int(gopi.GPIO_PIN_NONE) {
+		return gopi.ErrBadParameter.WithPrefix("-ups.gpio")
+	} else if *this.pin >= 0 && this.GPIO == nil {
+		return gopi.ErrInternalAppError.WithPrefix("Missing gopi.GPIO for -ups.gpio")
+	} else if *this.slave > 0x7F {
+		return gopi.ErrBadParameter.WithPrefix("-ups.addr")
+	}
+
+	// Open fuel gauge
+	bus := gopi.I2CBus(*this.bus)
+	switch strings.ToLower(*this.chip) {
+	case "ina219":
+		if *this.shunt <= 0 {
+			return gopi.ErrBadParameter.WithPrefix("-ups.shunt")
+		} else if *this.cells == 0 {
+			return gopi.ErrBadParameter.WithPrefix("-ups.cells")
+		} else if gauge, err := newINA219(this.I2C, bus, this.addr(0x42), float32(*this.shunt), *this.cells); err != nil {
+			return err
+		} else {
+			this.gauge = gauge
+		}
+	case "max17040":
+		if gauge, err := newMAX17040(this.I2C, bus, this.addr(0x36)); err != nil {
+			return err
+		} else {
+			this.gauge = gauge
+		}
+	default:
+		return gopi.ErrBadParameter.WithPrefix("-ups.chip")
+	}
+
+	// Set power loss pin as input
+	if *this.pin >= 0 {
+		this.GPIO.SetPinMode(gopi.GPIOPin(*this.pin), gopi.GPIO_INPUT)
+	}
+
+	// Define measurement
+	if measurement := cfg.GetString("ups.measurement"); measurement != "" && this.Metrics != nil {
+		if m, err := this.Metrics.NewMeasurement(measurement, "voltage float32, current float32, charge float32, battery bool", this.Metrics.HostTag()); err != nil {
+			return err
+		} else {
+			this.measurement = m.Name()
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *ups) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.gauge = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *ups) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			if err := this.read(ctx); err != nil {
+				this.Print("UPS: ", err)
+			}
+			timer.Reset(*this.interval)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *ups) Status() (gopi.UPSStatus, bool) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.status, this.valid
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *ups) String() string {
+	str := "<ups"
+	str += fmt.Sprintf(" chip=%q", strings.ToLower(*this.chip))
+	if *this.pin >= 0 {
+		str += fmt.Sprint(" gpio=", *this.pin)
+	}
+	if *this.shutdown > 0 {
+		str += fmt.Sprint(" shutdown=", *this.shutdown)
+	}
+	if status, valid := this.Status(); valid {
+		str += fmt.Sprint(" ", status)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// addr returns the I2C address, or a default address for the chip
+func (this *ups) addr(value uint8) uint8 {
+	if *this.slave != 0 {
+		return uint8(*this.slave)
+	}
+	return value
+}
+
+// read reads the fuel gauge, emits events and a measurement, and shuts
+// down when the charge is below the threshold on battery. Reading the
+// fuel gauge fails when it takes longer than the interval
+func (this *ups) read(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, *this.interval)
+	defer cancel()
+	voltage, current, charge, measured, err := this.gauge.Read(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Determine source of power from the power loss pin or current
+	status := gopi.UPSStatus{Time: time.Now(), Voltage: voltage, Current: current, Charge: charge}
+	if *this.pin >= 0 {
+		if (this.GPIO.ReadPin(gopi.GPIOPin(*this.pin)) == gopi.GPIO_HIGH) != *this.invert {
+			status.Power = gopi.UPS_POWER_BATTERY
+		} else {
+			status.Power = gopi.UPS_POWER_MAINS
+		}
+	} else if measured {
+		if current < batteryCurrent {
+			status.Power = gopi.UPS_POWER_BATTERY
+		} else {
+			status.Power = gopi.UPS_POWER_MAINS
+		}
+	}
+
+	// Set status
+	this.RWMutex.Lock()
+	last, valid := this.status, this.valid
+	this.status, this.valid = status, true
+	halt := false
+	if *this.shutdown > 0 && status.Power == gopi.UPS_POWER_BATTERY && status.Charge <= float32(*this.shutdown) && this.halted == false {
+		halt, this.halted = true, true
+	} else if status.Power == gopi.UPS_POWER_MAINS {
+		this.halted = false
+	}
+	this.RWMutex.Unlock()
+
+	// Emit events
+	this.emit(gopi.UPS_EVENT_STATUS, status)
+	if valid && last.Power != status.Power {
+		switch status.Power {
+		case gopi.UPS_POWER_MAINS:
+			this.Print("UPS: On mains")
+			this.emit(gopi.UPS_EVENT_MAINS, status)
+		case gopi.UPS_POWER_BATTERY:
+			this.Print("UPS: On battery")
+			this.emit(gopi.UPS_EVENT_BATTERY, status)
+		}
+	}
+
+	// Emit measurement
+	if this.measurement != "" {
+		if err := this.Metrics.Emit(this.measurement, nil, voltage, current, charge, status.Power == gopi.UPS_POWER_BATTERY); err != nil {
+			return err
+		}
+	}
+
+	// Shut down
+	if halt {
+		this.Print("UPS: Shutting down with charge ", status.Charge, "%")
+		this.emit(gopi.UPS_EVENT_SHUTDOWN, status)
+		args := strings.Fields(*this.command)
+		if err := exec.Command(args[0], args[1:]...).Run(); err != nil {
+			return fmt.Errorf("%v: %w", *this.command, err)
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *ups) emit(t gopi.UPSEventType, status gopi.UPSStatus) {
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(NewEvent(t, status), false); err != nil {
+			this.Debug("UPS: ", err)
+		}
+	}
+}
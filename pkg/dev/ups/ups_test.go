@@ -0,0 +1,146 @@
+package ups_test
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/dev/ups"
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.UPS
+	gopi.I2C
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// i2c is a bus with an INA219 and a MAX17040
+type i2c struct {
+	gopi.Unit
+	*testutil.I2C
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&i2c{}), reflect.TypeOf((*gopi.I2C)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// I2C
+
+func (this *i2c) New(gopi.Config) error {
+	this.I2C = testutil.NewI2C(0x42, 0x36)
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_UPS_001(t *testing.T) {
+	// MAX17040 cell=3.7V charge=75.5%
+	args := []string{"-ups.chip", "max17040", "-ups.interval", "10ms"}
+	tool.Test(t, args, new(App), func(app *App) {
+		app.I2C.(*i2c).SetUint16(0x36, 0x02, 2960<<4)
+		app.I2C.(*i2c).SetUint16(0x36, 0x04, 0x4B80)
+
+		status := wait(app.UPS, func(status gopi.UPSStatus) bool { return status.Charge != 0 })
+		if math.Abs(float64(status.Voltage)-3.7) > 0.001 || status.Charge != 75.5 || status.Power != gopi.UPS_POWER_NONE {
+			t.Error("Unexpected status", status)
+		} else {
+			t.Log(app.UPS)
+		}
+	})
+}
+
+func Test_UPS_002(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "shutdown")
+
+	// INA219 on a 2S battery shuts down at 20% by creating a file
+	args := []string{"-ups.interval", "10ms", "-ups.shutdown", "20", "-ups.command", "touch " + path}
+	tool.Test(t, args, new(App), func(app *App) {
+		bus := app.I2C.(*i2c)
+		if config := bus.Uint16(0x42, 0x00); config != 0x399F {
+			t.Errorf("Unexpected configuration 0x%04X", config)
+		}
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Charging at 0.5A and 8.0V
+		bus.SetUint16(0x42, 0x01, 5000)
+		bus.SetUint16(0x42, 0x02, 2000<<3)
+		status := wait(app.UPS, func(status gopi.UPSStatus) bool { return status.Voltage > 7.9 })
+		if status.Power != gopi.UPS_POWER_MAINS || math.Abs(float64(status.Current)-0.5) > 0.001 || math.Abs(float64(status.Charge)-83.3) > 0.1 {
+			t.Error("Unexpected status", status)
+		}
+
+		// Discharging at 1A and 6.44V
+		bus.SetUint16(0x42, 0x01, 0x10000-10000)
+		bus.SetUint16(0x42, 0x02, 1610<<3)
+		for _, expected := range []gopi.UPSEventType{gopi.UPS_EVENT_BATTERY, gopi.UPS_EVENT_SHUTDOWN} {
+			if evt, _ := testutil.NextType(ch, expected).(gopi.UPSEvent); evt == nil {
+				t.Error("Timeout waiting for", expected)
+			} else if status := evt.Status(); status.Power != gopi.UPS_POWER_BATTERY || math.Abs(float64(status.Current)+1) > 0.001 || math.Abs(float64(status.Charge)-18.3) > 0.1 {
+				t.Error("Unexpected status", status)
+			}
+		}
+
+		// Wait for command
+		for i := 0; i < 100; i++ {
+			if _, err := os.Stat(path); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Error(err)
+		}
+
+		// Back on mains
+		bus.SetUint16(0x42, 0x01, 1000)
+		if evt, _ := testutil.NextType(ch, gopi.UPS_EVENT_MAINS).(gopi.UPSEvent); evt == nil {
+			t.Error("Timeout waiting for", gopi.UPS_EVENT_MAINS)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// wait returns the status when a condition is true
+func wait(ups gopi.UPS, fn func(gopi.UPSStatus) bool) gopi.UPSStatus {
+	for i := 0; i < 100; i++ {
+		if status, valid := ups.Status(); valid && fn(status) {
+			return status
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	status, _ := ups.Status()
+	return status
+}
@@ -0,0 +1,127 @@
+package zigbee
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// device is decoded from the bridge/devices topic
+type device struct {
+	sync.RWMutex
+
+	Address_    string `json:"ieee_address"`
+	Name_       string `json:"friendly_name"`
+	Type_       string `json:"type"`
+	Supported_  bool   `json:"supported"`
+	Definition_ *struct {
+		Vendor      string `json:"vendor"`
+		Model       string `json:"model"`
+		Description string `json:"description"`
+	} `json:"definition"`
+
+	state map[string]interface{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newDevice(name, address string) *device {
+	return &device{Name_: name, Address_: address}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *device) Name() string {
+	return this.Name_
+}
+
+func (this *device) Address() string {
+	return this.Address_
+}
+
+func (this *device) Type() string {
+	return this.Type_
+}
+
+func (this *device) Vendor() string {
+	if this.Definition_ == nil {
+		return ""
+	}
+	return this.Definition_.Vendor
+}
+
+func (this *device) Model() string {
+	if this.Definition_ == nil {
+		return ""
+	}
+	return this.Definition_.Model
+}
+
+func (this *device) Description() string {
+	if this.Definition_ == nil {
+		return ""
+	}
+	return this.Definition_.Description
+}
+
+func (this *device) Supported() bool {
+	return this.Supported_
+}
+
+// State returns a copy of the properties last reported
+func (this *device) State() map[string]interface{} {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	state := make(map[string]interface{}, len(this.state))
+	for k, v := range this.state {
+		state[k] = v
+	}
+	return state
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *device) String() string {
+	str := "<zigbee.device"
+	str += fmt.Sprintf(" name=%q", this.Name_)
+	if this.Address_ != "" {
+		str += " address=" + this.Address_
+	}
+	if this.Type_ != "" {
+		str += " type=" + this.Type_
+	}
+	if vendor := this.Vendor(); vendor != "" {
+		str += " vendor=" + strconv.Quote(vendor)
+	}
+	if model := this.Model(); model != "" {
+		str += " model=" + strconv.Quote(model)
+	}
+	if this.Supported_ == false {
+		str += " unsupported"
+	}
+	if state := this.State(); len(state) > 0 {
+		str += fmt.Sprint(" state=", state)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// setState merges reported properties into the state
+func (this *device) setState(state map[string]interface{}) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	if this.state == nil {
+		this.state = make(map[string]interface{}, len(state))
+	}
+	for k, v := range state {
+		this.state[k] = v
+	}
+}
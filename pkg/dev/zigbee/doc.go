@@ -0,0 +1,11 @@
+// Zigbee package implements gopi.ZigbeeManager, which controls Zigbee
+// devices through a zigbee2mqtt bridge. The bridge talks to the
+// coordinator dongle, and this package connects to the same MQTT
+// broker and follows the zigbee2mqtt topic structure.
+//
+// Devices are read from the retained bridge/devices topic. A
+// gopi.ZigbeeEvent is emitted when a device reports state, and
+// when a device joins, completes its interview or leaves the network.
+// Set the -zigbee.broker and -zigbee.topic flags to match the
+// zigbee2mqtt configuration.
+package zigbee
@@ -0,0 +1,55 @@
+package zigbee
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t      gopi.ZigbeeEventType
+	device *device
+	state  map[string]interface{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(t gopi.ZigbeeEventType, device *device, state map[string]interface{}) gopi.ZigbeeEvent {
+	return &event{t, device, state}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.device.Name()
+}
+
+func (this *event) Type() gopi.ZigbeeEventType {
+	return this.t
+}
+
+func (this *event) Device() gopi.ZigbeeDevice {
+	return this.device
+}
+
+func (this *event) State() map[string]interface{} {
+	return this.state
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<zigbee.event"
+	str += fmt.Sprint(" type=", this.t)
+	str += fmt.Sprintf(" name=%q", this.Name())
+	if len(this.state) > 0 {
+		str += fmt.Sprint(" state=", this.state)
+	}
+	return str + ">"
+}
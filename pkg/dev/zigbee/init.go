@@ -0,0 +1,13 @@
+package zigbee
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.ZigbeeManager
+	graph.RegisterUnit(reflect.TypeOf(&manager{}), reflect.TypeOf((*gopi.ZigbeeManager)(nil)))
+}
@@ -0,0 +1,415 @@
+package zigbee
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	mqtt "github.com/djthorpe/gopi/v3/pkg/dev/internal/mqtt"
+)
+
+// Ref: https://www.zigbee2mqtt.io/guide/usage/mqtt_topics_and_messages.html
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type manager struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	sync.RWMutex
+
+	// Flags
+	broker   *string
+	topic    *string
+	user     *string
+	password *string
+	timeout  *time.Duration
+
+	client  *mqtt.Client
+	devices map[string]*device // Devices keyed by IEEE address
+}
+
+type bridgeEvent struct {
+	Type string `json:"type"`
+	Data struct {
+		Name    string `json:"friendly_name"`
+		Address string `json:"ieee_address"`
+		Status  string `json:"status"`
+	} `json:"data"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	keepAlive      = 60 * time.Second
+	reconnectDelta = 10 * time.Second
+	maxPermitJoin  = 254 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *manager) Define(cfg gopi.Config) error {
+	this.broker = cfg.FlagString("zigbee.broker", "localhost:1883", "MQTT broker address")
+	this.topic = cfg.FlagString("zigbee.topic", "zigbee2mqtt", "zigbee2mqtt base topic")
+	this.user = cfg.FlagString("zigbee.user", "", "MQTT username")
+	this.password = cfg.FlagSecret("zigbee.password", "", "MQTT password")
+	this.timeout = cfg.FlagDuration("zigbee.timeout", 10*time.Second, "MQTT connection timeout")
+	return nil
+}
+
+func (this *manager) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	*this.topic = strings.Trim(*this.topic, "/")
+	if *this.broker == "" {
+		return gopi.ErrBadParameter.WithPrefix("-zigbee.broker")
+	} else if *this.topic == "" || strings.ContainsAny(*this.topic, "#+") {
+		return gopi.ErrBadParameter.WithPrefix("-zigbee.topic")
+	} else if *this.timeout <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-zigbee.timeout")
+	}
+
+	// Connect to broker
+	this.devices = make(map[string]*device)
+	if err := this.connect(); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *manager) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	var result error
+	if this.client != nil {
+		result = this.client.Close()
+	}
+
+	// Release resources
+	this.client = nil
+	this.devices = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *manager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(keepAlive / 2)
+	defer ticker.Stop()
+
+	for {
+		// Reconnect after the connection is lost
+		client := this.conn()
+		if client == nil {
+			if err := this.connect(); err != nil {
+				this.Debug("Zigbee: ", err)
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(reconnectDelta):
+				}
+			}
+			continue
+		}
+
+		// Receive messages in the background
+		errs := make(chan error, 1)
+		go func() {
+			errs <- this.receive(client)
+		}()
+
+		// Ping broker until the connection is lost or done
+	FOR_LOOP:
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if err := client.Ping(); err != nil {
+					this.Debug("Zigbee: ", err)
+				}
+			case err := <-errs:
+				this.Print("Zigbee: ", err)
+				this.RWMutex.Lock()
+				if this.client == client {
+					this.client.Close()
+					this.client = nil
+				}
+				this.RWMutex.Unlock()
+				break FOR_LOOP
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *manager) Devices() []gopi.ZigbeeDevice {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	result := make([]gopi.ZigbeeDevice, 0, len(this.devices))
+	for _, device := range this.devices {
+		result = append(result, device)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
+
+func (this *manager) Device(name string) gopi.ZigbeeDevice {
+	if device := this.device(name); device != nil {
+		return device
+	} else {
+		return nil
+	}
+}
+
+func (this *manager) PermitJoin(duration time.Duration) error {
+	if duration < 0 || duration > maxPermitJoin {
+		return gopi.ErrBadParameter.WithPrefix("PermitJoin: ", duration)
+	}
+	request := map[string]interface{}{
+		"value": duration > 0,
+	}
+	if duration > 0 {
+		request["time"] = int(duration.Seconds())
+	}
+	return this.publish("bridge/request/permit_join", request)
+}
+
+func (this *manager) Set(device gopi.ZigbeeDevice, state map[string]interface{}) error {
+	if device == nil || len(state) == 0 {
+		return gopi.ErrBadParameter.WithPrefix("Set")
+	}
+	return this.publish(device.Name()+"/set", state)
+}
+
+func (this *manager) Get(device gopi.ZigbeeDevice, properties ...string) error {
+	if device == nil {
+		return gopi.ErrBadParameter.WithPrefix("Get")
+	}
+	if len(properties) == 0 {
+		properties = []string{"state"}
+	}
+	request := make(map[string]interface{}, len(properties))
+	for _, property := range properties {
+		request[property] = ""
+	}
+	return this.publish(device.Name()+"/get", request)
+}
+
+func (this *manager) Remove(device gopi.ZigbeeDevice) error {
+	if device == nil {
+		return gopi.ErrBadParameter.WithPrefix("Remove")
+	}
+	return this.publish("bridge/request/device/remove", map[string]interface{}{
+		"id": device.Name(),
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *manager) String() string {
+	str := "<zigbee.manager"
+	str += fmt.Sprintf(" broker=%q", *this.broker)
+	str += fmt.Sprintf(" topic=%q", *this.topic)
+	if this.conn() == nil {
+		str += " disconnected"
+	}
+	for _, device := range this.Devices() {
+		str += fmt.Sprint(" ", device)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// connect to the broker and subscribe to all bridge topics
+func (this *manager) connect() error {
+	hostname, _ := os.Hostname()
+	client := fmt.Sprint("gopi-", hostname, "-", os.Getpid())
+	if client, err := mqtt.Dial(*this.broker, client, *this.user, *this.password, keepAlive, *this.timeout); err != nil {
+		return err
+	} else if err := client.Subscribe(*this.topic + "/#"); err != nil {
+		client.Close()
+		return err
+	} else {
+		this.RWMutex.Lock()
+		this.client = client
+		this.RWMutex.Unlock()
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *manager) conn() *mqtt.Client {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.client
+}
+
+// device returns a device by name or address
+func (this *manager) device(name string) *device {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if device, exists := this.devices[name]; exists {
+		return device
+	}
+	for _, device := range this.devices {
+		if device.Name() == name {
+			return device
+		}
+	}
+	return nil
+}
+
+// publish a request to a topic relative to the base topic
+func (this *manager) publish(topic string, request interface{}) error {
+	if client := this.conn(); client == nil {
+		return gopi.ErrOutOfOrder.WithPrefix("Zigbee: Not connected")
+	} else if data, err := json.Marshal(request); err != nil {
+		return err
+	} else {
+		return client.Publish(*this.topic+"/"+topic, data, false)
+	}
+}
+
+// receive messages until the connection is lost
+func (this *manager) receive(client *mqtt.Client) error {
+	for {
+		if topic, data, err := client.Receive(); err != nil {
+			return err
+		} else if err := this.message(topic, data); err != nil {
+			this.Debug("Zigbee: ", topic, ": ", err)
+		}
+	}
+}
+
+// message updates devices and emits events from a message
+func (this *manager) message(topic string, data []byte) error {
+	if strings.HasPrefix(topic, *this.topic+"/") == false {
+		return nil
+	} else {
+		topic = strings.TrimPrefix(topic, *this.topic+"/")
+	}
+
+	switch {
+	case topic == "bridge/devices":
+		return this.setDevices(data)
+	case topic == "bridge/event":
+		return this.bridgeEvent(data)
+	case strings.HasPrefix(topic, "bridge/"):
+		return nil
+	}
+
+	// Device state, where other topics such as availability are ignored
+	device := this.device(topic)
+	if device == nil || device.Name() != topic {
+		return nil
+	}
+	var state map[string]interface{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	} else {
+		device.setState(state)
+	}
+
+	// Emit state
+	return this.emit(NewEvent(gopi.ZIGBEE_EVENT_STATE, device, state))
+}
+
+// setDevices replaces devices, retaining state for existing devices
+func (this *manager) setDevices(data []byte) error {
+	var devices []*device
+	if err := json.Unmarshal(data, &devices); err != nil {
+		return err
+	}
+
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	result := make(map[string]*device, len(devices))
+	for _, device := range devices {
+		if device.Address_ == "" {
+			continue
+		} else if other, exists := this.devices[device.Address_]; exists {
+			device.state = other.State()
+		}
+		result[device.Address_] = device
+	}
+	this.devices = result
+
+	// Return success
+	return nil
+}
+
+// bridgeEvent emits an event when a device joins, is interviewed
+// or leaves
+func (this *manager) bridgeEvent(data []byte) error {
+	var evt bridgeEvent
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return err
+	}
+
+	// Determine event type
+	t := gopi.ZIGBEE_EVENT_NONE
+	switch evt.Type {
+	case "device_joined":
+		t = gopi.ZIGBEE_EVENT_JOINED
+	case "device_interview":
+		if evt.Data.Status == "successful" {
+			t = gopi.ZIGBEE_EVENT_INTERVIEW
+		}
+	case "device_leave":
+		t = gopi.ZIGBEE_EVENT_LEFT
+	}
+	if t == gopi.ZIGBEE_EVENT_NONE {
+		return nil
+	}
+
+	// Use an existing device, or create one until devices are updated
+	this.RWMutex.Lock()
+	device, exists := this.devices[evt.Data.Address]
+	if exists == false {
+		device = newDevice(evt.Data.Name, evt.Data.Address)
+	}
+	if t == gopi.ZIGBEE_EVENT_LEFT {
+		delete(this.devices, evt.Data.Address)
+	} else if evt.Data.Address != "" {
+		this.devices[evt.Data.Address] = device
+	}
+	this.RWMutex.Unlock()
+
+	// Emit event
+	return this.emit(NewEvent(t, device, nil))
+}
+
+func (this *manager) emit(evt gopi.ZigbeeEvent) error {
+	if this.Publisher != nil {
+		return this.Publisher.Emit(evt, false)
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,204 @@
+package zigbee_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/dev/zigbee"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.ZigbeeManager
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// message is published by the manager to the broker
+type message struct {
+	topic   string
+	payload string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	devices = `[
+		{"ieee_address":"0x0000000000000001","friendly_name":"Coordinator","type":"Coordinator","supported":false},
+		{"ieee_address":"0x00158d0001a2b3c4","friendly_name":"lamp","type":"Router","supported":true,
+			"definition":{"vendor":"IKEA","model":"LED1836G9","description":"TRADFRI bulb"}}
+	]`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Zigbee_001(t *testing.T) {
+	addr, messages := broker(t, func(w io.Writer) {
+		publish(w, "zigbee2mqtt/bridge/devices", devices, false)
+		publish(w, "zigbee2mqtt/lamp", `{"state":"OFF","brightness":254,"linkquality":120}`, true)
+		publish(w, "zigbee2mqtt/lamp/availability", `{"state":"online"}`, false)
+		publish(w, "zigbee2mqtt/bridge/event", `{"type":"device_joined","data":{"friendly_name":"0x00158d0009999999","ieee_address":"0x00158d0009999999"}}`, false)
+	})
+
+	tool.Test(t, []string{"-zigbee.broker", addr}, new(App), func(app *App) {
+		// Wait for devices and state
+		var lamp gopi.ZigbeeDevice
+		for i := 0; i < 20; i++ {
+			if lamp = app.ZigbeeManager.Device("lamp"); lamp != nil && len(lamp.State()) > 0 && len(app.ZigbeeManager.Devices()) == 3 {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Log(app.ZigbeeManager)
+		if lamp == nil {
+			t.Error("Device not found")
+			return
+		}
+		if lamp.Address() != "0x00158d0001a2b3c4" || lamp.Vendor() != "IKEA" || lamp.Supported() == false {
+			t.Error("Unexpected device", lamp)
+		}
+		if state := lamp.State(); state["state"] != "OFF" || state["brightness"] != float64(254) {
+			t.Error("Unexpected state", state)
+		}
+		if app.ZigbeeManager.Device("0x00158d0009999999") == nil {
+			t.Error("Joined device not found")
+		}
+		if app.ZigbeeManager.Device("missing") != nil {
+			t.Error("Unexpected device")
+		}
+
+		// Send commands
+		if err := app.ZigbeeManager.Set(lamp, map[string]interface{}{"state": "ON"}); err != nil {
+			t.Error(err)
+		}
+		if err := app.ZigbeeManager.PermitJoin(time.Minute); err != nil {
+			t.Error(err)
+		}
+		if err := app.ZigbeeManager.PermitJoin(time.Hour); err == nil {
+			t.Error("Expected error for permit join duration")
+		}
+		for _, expected := range []message{
+			{"zigbee2mqtt/lamp/set", `{"state":"ON"}`},
+			{"zigbee2mqtt/bridge/request/permit_join", `{"time":60,"value":true}`},
+		} {
+			select {
+			case msg := <-messages:
+				if msg != expected {
+					t.Error("Unexpected message", msg, "expected", expected)
+				}
+			case <-time.After(time.Second):
+				t.Error("Timeout waiting for", expected)
+			}
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// broker accepts a connection and subscription, calls a function to
+// publish messages and returns messages published by the client
+func broker(t *testing.T, fn func(io.Writer)) (string, <-chan message) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make(chan message, 10)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		if packet, _ := readPacket(r); packet != 0x10 {
+			t.Error("Expected CONNECT")
+			return
+		} else {
+			conn.Write([]byte{0x20, 2, 0, 0})
+		}
+		if packet, data := readPacket(r); packet != 0x82 {
+			t.Error("Expected SUBSCRIBE")
+			return
+		} else if topic := string(data[4 : len(data)-1]); topic != "zigbee2mqtt/#" {
+			t.Error("Unexpected subscription", topic)
+		} else {
+			conn.Write([]byte{0x90, 3, data[0], data[1], 0})
+		}
+		fn(conn)
+		for {
+			packet, data := readPacket(r)
+			switch packet & 0xF0 {
+			case 0x00:
+				return
+			case 0x30:
+				n := int(binary.BigEndian.Uint16(data))
+				messages <- message{string(data[2 : 2+n]), string(data[2+n:])}
+			}
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func readPacket(r *bufio.Reader) (byte, []byte) {
+	packet, err := r.ReadByte()
+	if err != nil {
+		return 0, nil
+	}
+	length := 0
+	for shift := uint(0); ; shift += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil
+		}
+		length |= int(b&0x7F) << shift
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil
+	}
+	return packet, data
+}
+
+// publish writes a message, with a packet identifier when qos is true
+func publish(w io.Writer, topic, payload string, qos bool) {
+	data := append([]byte{byte(len(topic) >> 8), byte(len(topic))}, topic...)
+	packet := byte(0x30)
+	if qos {
+		packet |= 0x02
+		data = append(data, 0, 1)
+	}
+	data = append(data, payload...)
+	header := []byte{packet}
+	for n := len(data); ; {
+		b := byte(n & 0x7F)
+		if n >>= 7; n > 0 {
+			header = append(header, b|0x80)
+		} else {
+			header = append(header, b)
+			break
+		}
+	}
+	w.Write(append(header, data...))
+}
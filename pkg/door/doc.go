@@ -0,0 +1,26 @@
+// Door package implements gopi.Door, which controls a garage door or
+// gate. The opener is triggered by pulsing the gopi.Relay channel set
+// with -door.relay for -door.pulse, and the position is sensed with reed
+// switches on the GPIO pins set with -door.open and -door.closed. At
+// least one sensor is required, and sensors switch to ground unless
+// -door.high is set.
+//
+// The door is closed or open when a sensor is active, and opening or
+// closing when it has left an end or has been commanded to move. When
+// the door does not reach the other end within -door.travel it is
+// blocked, or returns to the end it did not leave, and an alarm is
+// raised. An end without a sensor is assumed to be reached after the
+// travel time. An alarm is also raised when the door has been open for
+// longer than -door.alarm. A gopi.DoorEvent is emitted on each change
+// of state and alarm.
+//
+// When -door.snapshot is the URL of a camera snapshot, such as the JPEG
+// endpoint of an IP camera, a snapshot is written to the -door.snapshots
+// folder on each change of state and alarm.
+//
+// When there is a gopi.Server, the state is served as JSON at -door.path
+// and the door is opened or closed with a POST to "open" or "close" under
+// that path. Routes are authorized by the server when there is a
+// gopi.AccessControl unit, or else a paired session with GPIO permission
+// is required. Without either, the door cannot be controlled remotely.
+package door
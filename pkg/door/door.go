@@ -0,0 +1,391 @@
+package door
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type door struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Relay
+	gopi.GPIO
+	gopi.Server
+	gopi.AccessControl
+	gopi.PairingManager
+	sync.Mutex
+
+	// Flags
+	relay     *string
+	pulse     *time.Duration
+	travel    *time.Duration
+	alarm     *time.Duration
+	open      *int
+	closed    *int
+	high      *bool
+	snapshot  *string
+	snapshots *string
+	path      *string
+
+	machine *machine
+	alarmed bool
+	ch      <-chan gopi.Event
+	client  *http.Client
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Interval between reading sensors, when edges are not watched
+	pollInterval = 250 * time.Millisecond
+
+	// Time allowed to fetch a camera snapshot
+	snapshotTimeout = 10 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *door) Define(cfg gopi.Config) error {
+	this.relay = cfg.FlagString("door.relay", "", "Relay channel which is pulsed to open or close the door")
+	this.pulse = cfg.FlagDuration("door.pulse", 500*time.Millisecond, "Duration of relay pulse")
+	this.travel = cfg.FlagDuration("door.travel", 30*time.Second, "Time allowed for the door to open or close")
+	this.alarm = cfg.FlagDuration("door.alarm", 10*time.Minute, "Raise an alarm when open for longer than this, or zero to disable")
+	this.open = cfg.FlagInt("door.open", -1, "GPIO pin for the sensor which is active when fully open, or -1")
+	this.closed = cfg.FlagInt("door.closed", -1, "GPIO pin for the sensor which is active when fully closed, or -1")
+	this.high = cfg.FlagBool("door.high", false, "Sensors are active high, rather than switching to ground")
+	this.snapshot = cfg.FlagString("door.snapshot", "", "URL of camera snapshot taken when the door moves, or empty")
+	this.snapshots = cfg.FlagPath("door.snapshots", "", "Folder to store camera snapshots")
+	this.path = cfg.FlagString("door.path", "/door", "Path to serve state and control, or empty to disable")
+	return nil
+}
+
+func (this *door) New(gopi.Config) error {
+	this.Require(this.Logger, this.Relay)
+
+	// Check parameters
+	if *this.pulse <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-door.pulse")
+	} else if *this.travel <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-door.travel")
+	} else if *this.alarm < 0 {
+		return gopi.ErrBadParameter.WithPrefix("-door.alarm")
+	} else if *this.open < 0 && *this.closed < 0 {
+		return gopi.ErrBadParameter.WithPrefix("-door.open or -door.closed required")
+	} else if *this.open >= int(gopi.GPIO_PIN_NONE) || (*this.open >= 0 && *this.open == *this.closed) {
+		return gopi.ErrBadParameter.WithPrefix("-door.open")
+	} else if *this.closed >= int(gopi.GPIO_PIN_NONE) {
+		return gopi.ErrBadParameter.WithPrefix("-door.closed")
+	} else if this.GPIO == nil {
+		return gopi.ErrInternalAppError.WithPrefix("Missing gopi.GPIO for sensors")
+	} else if *this.snapshot != "" && *this.snapshots == "" {
+		return gopi.ErrBadParameter.WithPrefix("-door.snapshots")
+	}
+
+	// Check relay channel exists
+	*this.relay = strings.TrimSpace(*this.relay)
+	if *this.relay == "" {
+		return gopi.ErrBadParameter.WithPrefix("-door.relay")
+	} else if exists := func() bool {
+		for _, name := range this.Relay.Channels() {
+			if name == *this.relay {
+				return true
+			}
+		}
+		return false
+	}(); exists == false {
+		return gopi.ErrNotFound.WithPrefix("-door.relay: ", strconv.Quote(*this.relay))
+	}
+
+	// Set sensors as inputs, with a pull-up for switches to ground, and
+	// watch for edges when supported
+	pull := gopi.GPIO_PULL_UP
+	if *this.high {
+		pull = gopi.GPIO_PULL_DOWN
+	}
+	for _, pin := range []int{*this.open, *this.closed} {
+		if pin < 0 {
+			continue
+		}
+		this.GPIO.SetPinMode(gopi.GPIOPin(pin), gopi.GPIO_INPUT)
+		if err := this.GPIO.SetPullMode(gopi.GPIOPin(pin), pull); err != nil {
+			this.Debug("Door: ", err)
+		}
+		if err := this.GPIO.Watch(gopi.GPIOPin(pin), gopi.GPIO_EDGE_BOTH); err != nil {
+			this.Debug("Door: ", err)
+		}
+	}
+
+	// Read the initial state
+	this.machine = newMachine(*this.open >= 0, *this.closed >= 0, *this.travel)
+	this.machine.Sense(this.sensor(*this.open), this.sensor(*this.closed), time.Now())
+
+	// Subscribe to sensor events before units run
+	if this.Publisher != nil {
+		this.ch = this.Publisher.Subscribe()
+	}
+
+	// Client for camera snapshots
+	this.client = &http.Client{Timeout: snapshotTimeout}
+
+	// Serve state and control
+	if this.Server != nil && *this.path != "" {
+		if err := this.Server.RegisterService(*this.path, NewHandler(this, *this.path, this.authorizer())); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *door) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Unsubscribe from events
+	if this.ch != nil {
+		this.Publisher.Unsubscribe(this.ch)
+	}
+
+	// Release resources
+	this.ch = nil
+	this.client = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *door) Run(ctx context.Context) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			this.update(now)
+		case evt := <-this.ch:
+			if evt, ok := evt.(gopi.GPIOEvent); ok && this.isSensor(evt.Pin()) {
+				this.update(time.Now())
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *door) State() (gopi.DoorState, time.Time) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.machine.State()
+}
+
+func (this *door) Open(ctx context.Context) error {
+	return this.command(ctx, gopi.DOOR_STATE_OPEN)
+}
+
+func (this *door) Close(ctx context.Context) error {
+	return this.command(ctx, gopi.DOOR_STATE_CLOSED)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *door) String() string {
+	str := "<door"
+	str += fmt.Sprintf(" relay=%q", *this.relay)
+	if *this.open >= 0 {
+		str += fmt.Sprint(" open=", gopi.GPIOPin(*this.open))
+	}
+	if *this.closed >= 0 {
+		str += fmt.Sprint(" closed=", gopi.GPIOPin(*this.closed))
+	}
+	state, changed := this.State()
+	str += fmt.Sprint(" state=", state)
+	if changed.IsZero() == false {
+		str += " changed=" + changed.Format(time.RFC3339)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// command moves the door towards an end, and pulses the relay when
+// the door is not already there or moving there
+func (this *door) command(ctx context.Context, target gopi.DoorState) error {
+	this.Mutex.Lock()
+	pulse, err := this.machine.Command(target, time.Now())
+	state, _ := this.machine.State()
+	if pulse {
+		this.alarmed = false
+	}
+	this.Mutex.Unlock()
+
+	if err != nil || pulse == false {
+		return err
+	}
+
+	// Emit the change of state, and then pulse the relay. If the door
+	// does not move, an alarm is raised after the travel time
+	this.changed(gopi.DOOR_EVENT_STATE, state)
+	if err := this.Relay.Set(ctx, *this.relay, true); err != nil {
+		return err
+	}
+	timer := time.NewTimer(*this.pulse)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+
+	// Always switch off the relay, even when the context is cancelled
+	off, cancel := context.WithTimeout(context.Background(), *this.travel)
+	defer cancel()
+	return this.Relay.Set(off, *this.relay, false)
+}
+
+// update reads the sensors, checks the travel time and raises an alarm
+// when the door has been open for too long
+func (this *door) update(now time.Time) {
+	this.Mutex.Lock()
+	sensed := this.machine.Sense(this.sensor(*this.open), this.sensor(*this.closed), now)
+	ticked, alarm := this.machine.Tick(now)
+	state, since := this.machine.State()
+	if sensed || ticked {
+		this.alarmed = false
+	}
+	if *this.alarm > 0 && this.alarmed == false && (state == gopi.DOOR_STATE_OPEN || state == gopi.DOOR_STATE_BLOCKED) && now.Sub(since) >= *this.alarm {
+		alarm = true
+	}
+	if alarm {
+		this.alarmed = true
+	}
+	this.Mutex.Unlock()
+
+	if sensed || ticked {
+		this.changed(gopi.DOOR_EVENT_STATE, state)
+	}
+	if alarm {
+		this.changed(gopi.DOOR_EVENT_ALARM, state)
+	}
+}
+
+// changed logs and emits an event, and takes a camera snapshot
+func (this *door) changed(t gopi.DoorEventType, state gopi.DoorState) {
+	if t == gopi.DOOR_EVENT_ALARM {
+		this.Print("Door: Alarm, ", stateName(state))
+	} else {
+		this.Print("Door: ", stateName(state))
+	}
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(NewEvent(t, state), false); err != nil {
+			this.Debug("Door: ", err)
+		}
+	}
+	if *this.snapshot != "" {
+		go func(name string) {
+			if path, err := this.capture(name); err != nil {
+				this.Print("Door: Snapshot: ", err)
+			} else {
+				this.Debug("Door: Snapshot: ", path)
+			}
+		}(strings.ToLower(strings.TrimPrefix(t.String(), "DOOR_EVENT_")) + "-" + stateName(state))
+	}
+}
+
+// capture fetches a camera snapshot and writes it to the snapshots
+// folder, returning the path to the file
+func (this *door) capture(name string) (string, error) {
+	this.Mutex.Lock()
+	client := this.client
+	this.Mutex.Unlock()
+	if client == nil {
+		return "", gopi.ErrOutOfOrder.WithPrefix("capture")
+	}
+
+	response, err := client.Get(*this.snapshot)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", gopi.ErrUnexpectedResponse.WithPrefix(response.Status)
+	}
+
+	ext := ".jpg"
+	if strings.HasPrefix(response.Header.Get("Content-Type"), "image/png") {
+		ext = ".png"
+	}
+	if err := os.MkdirAll(*this.snapshots, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(*this.snapshots, "door-"+time.Now().Format("20060102-150405")+"-"+name+ext)
+	fh, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer fh.Close()
+	if _, err := io.Copy(fh, response.Body); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// authorizer returns a function which authorizes control requests. When
+// there is access control, the server authorizes routes. Otherwise a
+// paired session with GPIO permission is required, and without pairing
+// the door cannot be controlled remotely
+func (this *door) authorizer() func(*http.Request) error {
+	if this.AccessControl != nil {
+		return nil
+	} else if this.PairingManager != nil {
+		return func(req *http.Request) error {
+			_, err := this.PairingManager.Authorize(bearer(req), gopi.SESSION_PERM_GPIO)
+			return err
+		}
+	} else {
+		return func(*http.Request) error {
+			return gopi.ErrPermissionDenied.WithPrefix("Remote control requires authentication")
+		}
+	}
+}
+
+// sensor returns true if the sensor on a pin is active
+func (this *door) sensor(pin int) bool {
+	if pin < 0 {
+		return false
+	}
+	return (this.GPIO.ReadPin(gopi.GPIOPin(pin)) == gopi.GPIO_HIGH) == *this.high
+}
+
+// isSensor returns true if a pin is one of the sensors
+func (this *door) isSensor(pin gopi.GPIOPin) bool {
+	return (*this.open >= 0 && pin == gopi.GPIOPin(*this.open)) || (*this.closed >= 0 && pin == gopi.GPIOPin(*this.closed))
+}
+
+// stateName returns the state without the prefix, in lowercase
+func stateName(state gopi.DoorState) string {
+	return strings.ToLower(strings.TrimPrefix(state.String(), "DOOR_STATE_"))
+}
@@ -0,0 +1,180 @@
+package door_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	door "github.com/djthorpe/gopi/v3/pkg/door"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/relay"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Door
+	gopi.GPIO
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&testutil.GPIO{}), reflect.TypeOf((*gopi.GPIO)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+const channels = `{ "opener": { "gpio": 5 } }`
+
+func Test_Door_001(t *testing.T) {
+	args := []string{
+		"-relay.channels", testutil.TempFile(t, "channels.json", channels),
+		"-door.relay", "opener", "-door.pulse", "50ms", "-door.travel", "300ms", "-door.alarm", "0",
+		"-door.closed", "17", "-door.open", "27", "-door.high",
+	}
+	tool.Test(t, args, new(App), func(app *App) {
+		pins := app.GPIO.(*testutil.GPIO)
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Neither sensor is active, so the door is blocked
+		if state, _ := app.Door.State(); state != gopi.DOOR_STATE_BLOCKED {
+			t.Error("Unexpected state", state)
+		}
+
+		// Closed
+		pins.WritePin(17, gopi.GPIO_HIGH)
+		if evt, _ := testutil.NextType(ch, gopi.DOOR_EVENT_STATE).(gopi.DoorEvent); evt == nil || evt.State() != gopi.DOOR_STATE_CLOSED {
+			t.Fatal("Unexpected event", evt)
+		}
+
+		// Open pulses the relay, and the door is opening until the open
+		// sensor is active
+		if err := app.Door.Open(context.Background()); err != nil {
+			t.Fatal(err)
+		} else if pins.Pulses(5) != 1 || pins.ReadPin(5) != gopi.GPIO_LOW {
+			t.Error("Expected relay pulse")
+		}
+		if state, _ := app.Door.State(); state != gopi.DOOR_STATE_OPENING {
+			t.Error("Unexpected state", state)
+		}
+		if err := app.Door.Close(context.Background()); err == nil {
+			t.Error("Expected error when closing an opening door")
+		}
+		pins.WritePin(17, gopi.GPIO_LOW)
+		pins.WritePin(27, gopi.GPIO_HIGH)
+		if evt, _ := testutil.NextType(ch, gopi.DOOR_EVENT_STATE).(gopi.DoorEvent); evt == nil || evt.State() != gopi.DOOR_STATE_OPENING {
+			t.Fatal("Unexpected event", evt)
+		} else if evt, _ := testutil.NextType(ch, gopi.DOOR_EVENT_STATE).(gopi.DoorEvent); evt == nil || evt.State() != gopi.DOOR_STATE_OPEN {
+			t.Fatal("Unexpected event", evt)
+		}
+
+		// Opening an open door does nothing
+		if err := app.Door.Open(context.Background()); err != nil {
+			t.Error(err)
+		} else if pins.Pulses(5) != 1 {
+			t.Error("Unexpected relay pulse")
+		}
+
+		// When the door does not move, an alarm is raised and it is open
+		if err := app.Door.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		} else if evt, _ := testutil.NextType(ch, gopi.DOOR_EVENT_ALARM).(gopi.DoorEvent); evt == nil || evt.State() != gopi.DOOR_STATE_OPEN {
+			t.Fatal("Unexpected event", evt)
+		}
+
+		// When the door stops between ends, an alarm is raised and it
+		// is blocked
+		pins.WritePin(27, gopi.GPIO_LOW)
+		if evt, _ := testutil.NextType(ch, gopi.DOOR_EVENT_STATE).(gopi.DoorEvent); evt == nil || evt.State() != gopi.DOOR_STATE_CLOSING {
+			t.Fatal("Unexpected event", evt)
+		} else if evt, _ := testutil.NextType(ch, gopi.DOOR_EVENT_ALARM).(gopi.DoorEvent); evt == nil || evt.State() != gopi.DOOR_STATE_BLOCKED {
+			t.Fatal("Unexpected event", evt)
+		}
+		t.Log(app.Door)
+	})
+}
+
+func Test_Door_002(t *testing.T) {
+	args := []string{
+		"-relay.channels", testutil.TempFile(t, "channels.json", channels),
+		"-door.relay", "opener", "-door.travel", "100ms", "-door.alarm", "200ms", "-door.closed", "17",
+	}
+	tool.Test(t, args, new(App), func(app *App) {
+		// The closed sensor is active low, so the door is closed until
+		// the pin is high. Without an open sensor, the door is open after
+		// the travel time, and an alarm is raised when it has been open
+		// for too long. Sensors are polled every 250ms
+		if state, _ := app.Door.State(); state != gopi.DOOR_STATE_CLOSED {
+			t.Error("Unexpected state", state)
+		}
+		tool.NewScenario().
+			At(0, "closed sensor is inactive", func() error {
+				app.GPIO.WritePin(17, gopi.GPIO_HIGH)
+				return nil
+			}).
+			ExpectEvent(500*time.Millisecond, "door opening", isDoor(gopi.DOOR_EVENT_STATE, gopi.DOOR_STATE_OPENING)).
+			ExpectEvent(time.Second, "door open", isDoor(gopi.DOOR_EVENT_STATE, gopi.DOOR_STATE_OPEN)).
+			ExpectEvent(1500*time.Millisecond, "door open alarm", isDoor(gopi.DOOR_EVENT_ALARM, gopi.DOOR_STATE_OPEN)).
+			Test(t, app.Publisher)
+
+		// Serve state, and refuse control without authorization
+		h := door.NewHandler(app.Door, "/door", func(*http.Request) error {
+			return gopi.ErrPermissionDenied
+		})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/door", nil))
+		var state struct {
+			State string `json:"state"`
+		}
+		if w.Code != http.StatusOK {
+			t.Error("Unexpected status", w.Code)
+		} else if err := json.NewDecoder(w.Body).Decode(&state); err != nil {
+			t.Error(err)
+		} else if state.State != "open" {
+			t.Error("Unexpected state", state)
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/door/close", nil))
+		if w.Code != http.StatusForbidden {
+			t.Error("Unexpected status", w.Code)
+		}
+		if state, _ := app.Door.State(); state != gopi.DOOR_STATE_OPEN {
+			t.Error("Unexpected state", state)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// isDoor returns a function which returns true for a door event with a
+// type and state
+func isDoor(t gopi.DoorEventType, state gopi.DoorState) func(gopi.Event) bool {
+	return func(evt gopi.Event) bool {
+		if testutil.IsType(t)(evt) == false {
+			return false
+		}
+		return evt.(gopi.DoorEvent).State() == state
+	}
+}
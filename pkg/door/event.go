@@ -0,0 +1,44 @@
+package door
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t     gopi.DoorEventType
+	state gopi.DoorState
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(t gopi.DoorEventType, state gopi.DoorState) gopi.DoorEvent {
+	return &event{t, state}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "door"
+}
+
+func (this *event) Type() gopi.DoorEventType {
+	return this.t
+}
+
+func (this *event) State() gopi.DoorState {
+	return this.state
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	return fmt.Sprintf("<door.event type=%v state=%v>", this.t, this.state)
+}
@@ -0,0 +1,89 @@
+package door
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// handler serves the state of the door, and opens or closes it on POST
+// to "open" or "close" when the request is authorized
+type handler struct {
+	gopi.Door
+	path      string
+	authorize func(*http.Request) error
+}
+
+type state struct {
+	State   string    `json:"state"`
+	Changed time.Time `json:"changed,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewHandler(door gopi.Door, path string, authorize func(*http.Request) error) http.Handler {
+	return &handler{door, strings.TrimSuffix(path, "/"), authorize}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// HANDLER
+
+func (this *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch action := strings.Trim(strings.TrimPrefix(req.URL.Path, this.path), "/"); action {
+	case "":
+		if req.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		} else {
+			this.serveState(w, http.StatusOK)
+		}
+	case "open", "close":
+		if req.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		} else if err := this.control(req, action); err != nil {
+			http.Error(w, err.Error(), gopi.ErrorCode(err).HttpStatus())
+		} else {
+			this.serveState(w, http.StatusAccepted)
+		}
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *handler) control(req *http.Request, action string) error {
+	if this.authorize != nil {
+		if err := this.authorize(req); err != nil {
+			return err
+		}
+	}
+	if action == "open" {
+		return this.Door.Open(req.Context())
+	} else {
+		return this.Door.Close(req.Context())
+	}
+}
+
+func (this *handler) serveState(w http.ResponseWriter, code int) {
+	value, changed := this.Door.State()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(state{stateName(value), changed})
+}
+
+func bearer(req *http.Request) string {
+	const prefix = "Bearer "
+	if value := req.Header.Get("Authorization"); strings.HasPrefix(value, prefix) {
+		return strings.TrimSpace(strings.TrimPrefix(value, prefix))
+	} else {
+		return ""
+	}
+}
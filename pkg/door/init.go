@@ -0,0 +1,13 @@
+package door
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.Door
+	graph.RegisterUnit(reflect.TypeOf(&door{}), reflect.TypeOf((*gopi.Door)(nil)))
+}
@@ -0,0 +1,137 @@
+package door
+
+import (
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// machine is the state of a door from its open and closed sensors, the
+// commands sent to it and the time it takes to open or close. A door
+// without a sensor for an end is assumed to reach that end when the
+// travel time has elapsed
+type machine struct {
+	hasOpen, hasClosed bool
+	travel             time.Duration
+
+	atOpen, atClosed bool
+	state            gopi.DoorState
+	changed          time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newMachine(hasOpen, hasClosed bool, travel time.Duration) *machine {
+	return &machine{hasOpen: hasOpen, hasClosed: hasClosed, travel: travel}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// Sense updates the state from the sensors, and returns true if the
+// state changed
+func (this *machine) Sense(open, closed bool, now time.Time) bool {
+	this.atOpen, this.atClosed = this.hasOpen && open, this.hasClosed && closed
+
+	next := this.state
+	switch {
+	case this.atClosed && this.state == gopi.DOOR_STATE_OPENING:
+		// Door has not left the closed end yet
+	case this.atOpen && this.state == gopi.DOOR_STATE_CLOSING:
+		// Door has not left the open end yet
+	case this.atClosed:
+		next = gopi.DOOR_STATE_CLOSED
+	case this.atOpen:
+		next = gopi.DOOR_STATE_OPEN
+	default:
+		// Between open and closed, so the door has started moving when
+		// it has left an end with a sensor, which may be by another
+		// remote control
+		switch this.state {
+		case gopi.DOOR_STATE_CLOSED:
+			if this.hasClosed {
+				next = gopi.DOOR_STATE_OPENING
+			}
+		case gopi.DOOR_STATE_OPEN:
+			if this.hasOpen {
+				next = gopi.DOOR_STATE_CLOSING
+			}
+		case gopi.DOOR_STATE_NONE:
+			if this.hasOpen == false {
+				next = gopi.DOOR_STATE_OPEN
+			} else if this.hasClosed == false {
+				next = gopi.DOOR_STATE_CLOSED
+			} else {
+				next = gopi.DOOR_STATE_BLOCKED
+			}
+		}
+	}
+	return this.set(next, now)
+}
+
+// Tick checks whether a moving door has reached the end it is moving
+// towards in the travel time. It returns true if the state changed, and
+// true for the alarm when the door did not leave the end it started
+// from or did not reach an end with a sensor
+func (this *machine) Tick(now time.Time) (bool, bool) {
+	var from, target gopi.DoorState
+	var atFrom, sensed bool
+	switch this.state {
+	case gopi.DOOR_STATE_OPENING:
+		from, target = gopi.DOOR_STATE_CLOSED, gopi.DOOR_STATE_OPEN
+		atFrom, sensed = this.atClosed, this.hasOpen
+	case gopi.DOOR_STATE_CLOSING:
+		from, target = gopi.DOOR_STATE_OPEN, gopi.DOOR_STATE_CLOSED
+		atFrom, sensed = this.atOpen, this.hasClosed
+	default:
+		return false, false
+	}
+	if now.Sub(this.changed) < this.travel {
+		return false, false
+	} else if atFrom {
+		return this.set(from, now), true
+	} else if sensed {
+		return this.set(gopi.DOOR_STATE_BLOCKED, now), true
+	} else {
+		return this.set(target, now), false
+	}
+}
+
+// Command moves the door towards an end, and returns true if the relay
+// should be pulsed. It returns ErrOutOfOrder when the door is moving
+// towards the other end
+func (this *machine) Command(target gopi.DoorState, now time.Time) (bool, error) {
+	moving, reverse := gopi.DOOR_STATE_OPENING, gopi.DOOR_STATE_CLOSING
+	if target == gopi.DOOR_STATE_CLOSED {
+		moving, reverse = gopi.DOOR_STATE_CLOSING, gopi.DOOR_STATE_OPENING
+	}
+	switch this.state {
+	case target, moving:
+		return false, nil
+	case reverse:
+		return false, gopi.ErrOutOfOrder.WithPrefix("Door is ", this.state)
+	default:
+		this.set(moving, now)
+		return true, nil
+	}
+}
+
+// State returns the state and when it changed
+func (this *machine) State() (gopi.DoorState, time.Time) {
+	return this.state, this.changed
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *machine) set(state gopi.DoorState, now time.Time) bool {
+	if state == this.state {
+		return false
+	}
+	this.state, this.changed = state, now
+	return true
+}
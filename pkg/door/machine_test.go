@@ -0,0 +1,75 @@
+package door
+
+import (
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+func Test_Machine_001(t *testing.T) {
+	m := newMachine(true, true, time.Minute)
+	now := time.Now()
+
+	// Closed, then commanded to open
+	if m.Sense(false, true, now) == false {
+		t.Error("Expected state change")
+	} else if state, _ := m.State(); state != gopi.DOOR_STATE_CLOSED {
+		t.Error("Unexpected state", state)
+	}
+	if pulse, err := m.Command(gopi.DOOR_STATE_OPEN, now); err != nil || pulse == false {
+		t.Error("Expected pulse", err)
+	} else if pulse, err := m.Command(gopi.DOOR_STATE_OPEN, now); err != nil || pulse {
+		t.Error("Unexpected pulse", err)
+	} else if _, err := m.Command(gopi.DOOR_STATE_CLOSED, now); err == nil {
+		t.Error("Expected error")
+	}
+
+	// Still at the closed end, so does not revert to closed until the
+	// travel time, when an alarm is raised
+	if m.Sense(false, true, now.Add(time.Second)) {
+		t.Error("Unexpected state change")
+	} else if changed, alarm := m.Tick(now.Add(time.Second)); changed || alarm {
+		t.Error("Unexpected tick")
+	} else if changed, alarm := m.Tick(now.Add(time.Minute)); changed == false || alarm == false {
+		t.Error("Expected alarm")
+	} else if state, _ := m.State(); state != gopi.DOOR_STATE_CLOSED {
+		t.Error("Unexpected state", state)
+	}
+
+	// Opened by another remote control, and stops between ends
+	now = now.Add(2 * time.Minute)
+	if m.Sense(false, false, now) == false {
+		t.Error("Expected state change")
+	} else if state, _ := m.State(); state != gopi.DOOR_STATE_OPENING {
+		t.Error("Unexpected state", state)
+	} else if changed, alarm := m.Tick(now.Add(time.Minute)); changed == false || alarm == false {
+		t.Error("Expected alarm")
+	} else if state, _ := m.State(); state != gopi.DOOR_STATE_BLOCKED {
+		t.Error("Unexpected state", state)
+	}
+}
+
+func Test_Machine_002(t *testing.T) {
+	// Only a closed sensor, so the door is assumed open after the
+	// travel time
+	m := newMachine(false, true, time.Minute)
+	now := time.Now()
+	if m.Sense(false, false, now); m.state != gopi.DOOR_STATE_OPEN {
+		t.Error("Unexpected state", m.state)
+	} else if m.Sense(true, false, now) {
+		t.Error("Unexpected state change from open sensor")
+	}
+	if pulse, err := m.Command(gopi.DOOR_STATE_CLOSED, now); err != nil || pulse == false {
+		t.Error("Expected pulse", err)
+	} else if m.Sense(false, true, now.Add(time.Second)) == false || m.state != gopi.DOOR_STATE_CLOSED {
+		t.Error("Unexpected state", m.state)
+	}
+	if m.Sense(false, false, now.Add(2*time.Second)) == false || m.state != gopi.DOOR_STATE_OPENING {
+		t.Error("Unexpected state", m.state)
+	} else if changed, alarm := m.Tick(now.Add(2 * time.Minute)); changed == false || alarm {
+		t.Error("Unexpected tick")
+	} else if m.state != gopi.DOOR_STATE_OPEN {
+		t.Error("Unexpected state", m.state)
+	}
+}
@@ -0,0 +1,82 @@
+package doorbell
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// audio captures signed 16-bit mono samples from a microphone and
+// plays them on a speaker
+type audio interface {
+	// Read blocks until the buffer is filled from the microphone
+	Read([]int16) error
+
+	// Write plays samples on the speaker
+	Write([]int16) error
+
+	// Close stops any current reads and releases resources
+	Close() error
+}
+
+// nullAudio captures silence in real time and discards samples played
+type nullAudio struct {
+	sync.Once
+	rate uint
+	done chan struct{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	defaultDevice = "hw:0"
+	nullDevice    = "null"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newAudio(input, output string, rate uint) (audio, error) {
+	if input == nullDevice && output == nullDevice {
+		return &nullAudio{rate: rate, done: make(chan struct{})}, nil
+	} else {
+		return newDeviceAudio(input, output, rate)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NULL AUDIO
+
+func (this *nullAudio) Read(buf []int16) error {
+	timer := time.NewTimer(time.Duration(len(buf)) * time.Second / time.Duration(this.rate))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		for i := range buf {
+			buf[i] = 0
+		}
+		return nil
+	case <-this.done:
+		return io.EOF
+	}
+}
+
+func (this *nullAudio) Write([]int16) error {
+	select {
+	case <-this.done:
+		return io.EOF
+	default:
+		return nil
+	}
+}
+
+func (this *nullAudio) Close() error {
+	this.Once.Do(func() {
+		close(this.done)
+	})
+	return nil
+}
@@ -0,0 +1,150 @@
+// +build linux
+
+package doorbell
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	linux "github.com/djthorpe/gopi/v3/pkg/sys/linux"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// deviceAudio captures from and plays to ALSA devices in mono or
+// stereo at the sample rate. Either device can be null
+type deviceAudio struct {
+	sync.Mutex
+	*nullAudio
+	in, out     *os.File
+	inChannels  uint
+	outChannels uint
+	buf         []int16
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newDeviceAudio(input, output string, rate uint) (audio, error) {
+	this := &deviceAudio{nullAudio: &nullAudio{rate: rate, done: make(chan struct{})}}
+	if input != nullDevice {
+		if fh, channels, err := openDevice(input, rate, linux.PCMOpenCapture); err != nil {
+			return nil, err
+		} else {
+			this.in, this.inChannels = fh, channels
+		}
+	}
+	if output != nullDevice {
+		if fh, channels, err := openDevice(output, rate, linux.PCMOpen); err != nil {
+			this.Close()
+			return nil, err
+		} else {
+			this.out, this.outChannels = fh, channels
+		}
+	}
+	return this, nil
+}
+
+// openDevice opens a device and sets mono or stereo at the sample rate
+func openDevice(device string, rate uint, open func(uint, uint) (*os.File, error)) (*os.File, uint, error) {
+	card, dev, err := linux.PCMParseDevice(device)
+	if err != nil {
+		return nil, 0, err
+	}
+	fh, err := open(card, dev)
+	if err != nil {
+		return nil, 0, err
+	}
+	for channels := uint(1); channels <= 2; channels++ {
+		if err := linux.PCMSetParams(fh.Fd(), rate, channels); err == nil {
+			return fh, channels, nil
+		}
+	}
+
+	// No supported format
+	fh.Close()
+	return nil, 0, gopi.ErrNotImplemented.WithPrefix(device, ": Sample rate ", rate)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *deviceAudio) Read(buf []int16) error {
+	if this.in == nil {
+		return this.nullAudio.Read(buf)
+	}
+
+	// Read native samples and mix down channels
+	n := uint(len(buf)) * this.inChannels
+	if uint(len(this.buf)) != n {
+		this.buf = make([]int16, n)
+	}
+	if err := linux.PCMRead(this.in.Fd(), this.buf, this.inChannels); err != nil {
+		return this.closed(err)
+	}
+	for i := range buf {
+		sum := int32(0)
+		for _, s := range this.buf[uint(i)*this.inChannels : uint(i+1)*this.inChannels] {
+			sum += int32(s)
+		}
+		buf[i] = int16(sum / int32(this.inChannels))
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *deviceAudio) Write(samples []int16) error {
+	if this.out == nil {
+		return this.nullAudio.Write(samples)
+	}
+	if this.outChannels > 1 {
+		stereo := make([]int16, 0, len(samples)*int(this.outChannels))
+		for _, s := range samples {
+			for c := uint(0); c < this.outChannels; c++ {
+				stereo = append(stereo, s)
+			}
+		}
+		samples = stereo
+	}
+	if err := linux.PCMWrite(this.out.Fd(), samples, this.outChannels); err != nil {
+		return this.closed(err)
+	}
+	return nil
+}
+
+// Close stops capture and playback, after which reads and writes
+// return io.EOF
+func (this *deviceAudio) Close() error {
+	this.nullAudio.Close()
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	for _, fh := range []*os.File{this.in, this.out} {
+		if fh != nil {
+			linux.PCMDrop(fh.Fd())
+		}
+	}
+	if this.in != nil {
+		this.in.Close()
+	}
+	if this.out != nil {
+		this.out.Close()
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// closed returns io.EOF when the device has been closed, or the error
+func (this *deviceAudio) closed(err error) error {
+	select {
+	case <-this.done:
+		return io.EOF
+	default:
+		return err
+	}
+}
@@ -0,0 +1,14 @@
+// +build !linux
+
+package doorbell
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newDeviceAudio(string, string, uint) (audio, error) {
+	return nil, gopi.ErrNotImplemented
+}
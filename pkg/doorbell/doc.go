@@ -0,0 +1,19 @@
+// Doorbell package implements gopi.Doorbell, which rings when the button
+// on the GPIO pin set with -doorbell.button is pressed. The button
+// switches to ground unless -doorbell.high is set, and presses within
+// -doorbell.holdoff of ringing are ignored.
+//
+// When the doorbell rings, the chime at the URL set with -doorbell.chime
+// is played through gopi.MediaPlayer, and the camera snapshot at the URL
+// set with -doorbell.snapshot is fetched. A gopi.DoorbellEvent is emitted
+// with the snapshot as an attachment, and when there is a gopi.Notifier
+// the -doorbell.message is sent with the snapshot. The doorbell rings
+// without a snapshot when it cannot be fetched.
+//
+// Answer carries two-way audio between the device and a
+// gopi.IntercomSession, such as a web browser connected over WebRTC.
+// Audio is captured from the ALSA device set with -doorbell.input and
+// played on -doorbell.output at the sample rate of the session, where
+// "null" captures silence or discards audio. One session is answered
+// at a time, and events are emitted when it is answered and hung up.
+package doorbell
@@ -0,0 +1,348 @@
+package doorbell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	attachment "github.com/djthorpe/gopi/v3/pkg/event/attachment"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type doorbell struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.GPIO
+	gopi.Notifier
+	gopi.MediaPlayer
+	sync.Mutex
+
+	// Flags
+	button   *int
+	high     *bool
+	holdoff  *time.Duration
+	snapshot *string
+	chime    *string
+	message  *string
+	input    *string
+	output   *string
+
+	rung     time.Time
+	answered bool
+	ch       <-chan gopi.Event
+	client   *http.Client
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Time allowed to fetch a camera snapshot
+	snapshotTimeout = 10 * time.Second
+
+	// Maximum size of a camera snapshot
+	snapshotMaxSize = 8 * 1024 * 1024
+
+	// Duration of audio in each read and write
+	frameDuration = 20 * time.Millisecond
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *doorbell) Define(cfg gopi.Config) error {
+	this.button = cfg.FlagInt("doorbell.button", -1, "GPIO pin for the button, or -1")
+	this.high = cfg.FlagBool("doorbell.high", false, "Button is active high, rather than switching to ground")
+	this.holdoff = cfg.FlagDuration("doorbell.holdoff", 5*time.Second, "Time after ringing when the button is ignored")
+	this.snapshot = cfg.FlagString("doorbell.snapshot", "", "URL of camera snapshot sent when the doorbell rings, or empty")
+	this.chime = cfg.FlagString("doorbell.chime", "", "URL of chime played when the doorbell rings, or empty")
+	this.message = cfg.FlagString("doorbell.message", "Someone is at the door", "Notification sent when the doorbell rings")
+	this.input = cfg.FlagString("doorbell.input", defaultDevice, "Audio capture device for the intercom, null for silence")
+	this.output = cfg.FlagString("doorbell.output", defaultDevice, "Audio output device for the intercom, null for no output")
+	return nil
+}
+
+func (this *doorbell) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if *this.holdoff < 0 {
+		return gopi.ErrBadParameter.WithPrefix("-doorbell.holdoff")
+	} else if *this.button >= int(gopi.GPIO_PIN_NONE) {
+		return gopi.ErrBadParameter.WithPrefix("-doorbell.button")
+	} else if *this.button >= 0 && this.GPIO == nil {
+		return gopi.ErrInternalAppError.WithPrefix("Missing gopi.GPIO for button")
+	} else if *this.chime != "" && this.MediaPlayer == nil {
+		return gopi.ErrInternalAppError.WithPrefix("Missing gopi.MediaPlayer for chime")
+	}
+
+	// Set button as input, with a pull-up for a switch to ground, and
+	// watch for presses
+	if pin := gopi.GPIOPin(*this.button); *this.button >= 0 {
+		pull, edge := gopi.GPIO_PULL_UP, gopi.GPIO_EDGE_FALLING
+		if *this.high {
+			pull, edge = gopi.GPIO_PULL_DOWN, gopi.GPIO_EDGE_RISING
+		}
+		this.GPIO.SetPinMode(pin, gopi.GPIO_INPUT)
+		if err := this.GPIO.SetPullMode(pin, pull); err != nil {
+			this.Debug("Doorbell: ", err)
+		}
+		if err := this.GPIO.Watch(pin, edge); err != nil {
+			return err
+		}
+	}
+
+	// Subscribe to button events before units run
+	if this.Publisher != nil && *this.button >= 0 {
+		this.ch = this.Publisher.Subscribe()
+	}
+
+	// Client for camera snapshots
+	this.client = &http.Client{Timeout: snapshotTimeout}
+
+	// Return success
+	return nil
+}
+
+func (this *doorbell) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Unsubscribe from events
+	if this.ch != nil {
+		this.Publisher.Unsubscribe(this.ch)
+	}
+
+	// Release resources
+	this.ch = nil
+	this.client = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *doorbell) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-this.ch:
+			if evt, ok := evt.(gopi.GPIOEvent); ok && evt.Pin() == gopi.GPIOPin(*this.button) && this.pressed(evt.Edge()) {
+				go func() {
+					if err := this.Ring(ctx); errors.Is(err, gopi.ErrOutOfOrder) {
+						this.Debug("Doorbell: ", err)
+					} else if err != nil {
+						this.Print("Doorbell: ", err)
+					}
+				}()
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *doorbell) Ring(ctx context.Context) error {
+	this.Mutex.Lock()
+	now := time.Now()
+	if this.rung.IsZero() == false && now.Sub(this.rung) < *this.holdoff {
+		this.Mutex.Unlock()
+		return gopi.ErrOutOfOrder.WithPrefix("Ring: Hold-off")
+	}
+	this.rung = now
+	this.Mutex.Unlock()
+
+	// Play the chime
+	this.Print("Doorbell: Ring")
+	if *this.chime != "" {
+		if err := this.MediaPlayer.Play(*this.chime); err != nil {
+			this.Print("Doorbell: Chime: ", err)
+		}
+	}
+
+	// Take a snapshot, and ring without one when it cannot be taken
+	var attachments []gopi.EventAttachment
+	if *this.snapshot != "" {
+		if snapshot, err := this.capture(ctx, now); err != nil {
+			this.Print("Doorbell: Snapshot: ", err)
+		} else {
+			attachments = append(attachments, snapshot)
+		}
+	}
+
+	// Emit an event and send a notification with the snapshot
+	this.emit(NewEvent(gopi.DOORBELL_EVENT_RING, attachments...))
+	if this.Notifier != nil {
+		return this.Notifier.Notify(ctx, *this.message, gopi.NOTIFY_INFO, attachments...)
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *doorbell) Answer(ctx context.Context, session gopi.IntercomSession) error {
+	if session == nil || session.SampleRate() == 0 {
+		return gopi.ErrBadParameter.WithPrefix("Answer")
+	}
+
+	this.Mutex.Lock()
+	if this.answered {
+		this.Mutex.Unlock()
+		return gopi.ErrOutOfOrder.WithPrefix("Answer: Already answered")
+	}
+	this.answered = true
+	this.Mutex.Unlock()
+
+	defer func() {
+		this.Mutex.Lock()
+		this.answered = false
+		this.Mutex.Unlock()
+	}()
+
+	// Open the microphone and speaker
+	audio, err := newAudio(*this.input, *this.output, session.SampleRate())
+	if err != nil {
+		return err
+	}
+	defer audio.Close()
+
+	this.Print("Doorbell: Answered")
+	this.emit(NewEvent(gopi.DOORBELL_EVENT_ANSWER))
+	defer func() {
+		this.Print("Doorbell: Hung up")
+		this.emit(NewEvent(gopi.DOORBELL_EVENT_HANGUP))
+	}()
+
+	// Carry audio in both directions until the session ends or the
+	// context is done. Reading from the session ends when it returns
+	// io.EOF, and writing to the speaker fails after it is closed
+	size := int(session.SampleRate() * uint(frameDuration) / uint(time.Second))
+	errs := make(chan error, 2)
+	go func() {
+		errs <- pump(audio.Read, session.Write, size)
+	}()
+	go func() {
+		errs <- pump(session.Read, audio.Write, size)
+	}()
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-errs:
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+}
+
+func (this *doorbell) Answered() bool {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.answered
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *doorbell) String() string {
+	str := "<doorbell"
+	if *this.button >= 0 {
+		str += fmt.Sprint(" button=", gopi.GPIOPin(*this.button))
+	}
+	if *this.chime != "" {
+		str += fmt.Sprintf(" chime=%q", *this.chime)
+	}
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.rung.IsZero() == false {
+		str += " rung=" + this.rung.Format(time.RFC3339)
+	}
+	if this.answered {
+		str += " answered=true"
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// pressed returns true if an edge is the button being pressed
+func (this *doorbell) pressed(edge gopi.GPIOEdge) bool {
+	if *this.high {
+		return edge == gopi.GPIO_EDGE_RISING
+	} else {
+		return edge == gopi.GPIO_EDGE_FALLING
+	}
+}
+
+// emit an event when there is a publisher
+func (this *doorbell) emit(evt gopi.Event) {
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(evt, false); err != nil {
+			this.Debug("Doorbell: ", err)
+		}
+	}
+}
+
+// capture fetches a camera snapshot and returns it as an attachment
+func (this *doorbell) capture(ctx context.Context, now time.Time) (gopi.EventAttachment, error) {
+	this.Mutex.Lock()
+	client := this.client
+	this.Mutex.Unlock()
+	if client == nil {
+		return nil, gopi.ErrOutOfOrder.WithPrefix("capture")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *this.snapshot, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix(response.Status)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(response.Body, snapshotMaxSize+1))
+	if err != nil {
+		return nil, err
+	} else if len(data) > snapshotMaxSize {
+		return nil, gopi.ErrBadParameter.WithPrefix("Snapshot exceeds ", snapshotMaxSize, " bytes")
+	}
+
+	name, mimetype := "doorbell-"+now.Format("20060102-150405"), "image/jpeg"
+	if strings.HasPrefix(response.Header.Get("Content-Type"), "image/png") {
+		name, mimetype = name+".png", "image/png"
+	} else {
+		name += ".jpg"
+	}
+	return attachment.New(name, mimetype, data), nil
+}
+
+// pump reads frames of samples and writes them until an error
+func pump(read, write func([]int16) error, size int) error {
+	buf := make([]int16, size)
+	for {
+		if err := read(buf); err != nil {
+			return err
+		} else if err := write(buf); err != nil {
+			return err
+		}
+	}
+}
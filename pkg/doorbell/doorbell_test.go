@@ -0,0 +1,271 @@
+package doorbell_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/doorbell"
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Doorbell
+	gopi.Notifier
+	gopi.MediaPlayer
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// notifier records notifications, and records calls in a scenario
+// when one is set
+type notifier struct {
+	gopi.Unit
+	sync.Mutex
+	notifications []gopi.Notification
+	scenario      *tool.Scenario
+}
+
+// player records the URL played, and records calls in a scenario when
+// one is set
+type player struct {
+	gopi.Unit
+	sync.Mutex
+	url      string
+	scenario *tool.Scenario
+}
+
+// press is a GPIO edge
+type press struct {
+	gopi.Event
+	pin  gopi.GPIOPin
+	edge gopi.GPIOEdge
+}
+
+// session sends a number of frames of a sample, one each millisecond,
+// and records the samples received
+type session struct {
+	sync.Mutex
+	frames   int
+	received int
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&testutil.GPIO{}), reflect.TypeOf((*gopi.GPIO)(nil)))
+	graph.RegisterUnit(reflect.TypeOf(&notifier{}), reflect.TypeOf((*gopi.Notifier)(nil)))
+	graph.RegisterUnit(reflect.TypeOf(&player{}), reflect.TypeOf((*gopi.MediaPlayer)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NOTIFIER AND PLAYER
+
+func (this *notifier) Notify(_ context.Context, message string, severity gopi.NotifySeverity, attachments ...gopi.EventAttachment) error {
+	this.Mutex.Lock()
+	this.notifications = append(this.notifications, gopi.Notification{Message: message, Severity: severity, Attachments: attachments})
+	scenario := this.scenario
+	this.Mutex.Unlock()
+
+	if scenario != nil {
+		scenario.Record("Notifier.Notify", message)
+	}
+	return nil
+}
+
+func (this *notifier) RegisterSink(gopi.NotifySink) error { return gopi.ErrNotImplemented }
+func (this *notifier) Sinks() []string                    { return nil }
+
+func (this *notifier) Notifications() []gopi.Notification {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return append([]gopi.Notification{}, this.notifications...)
+}
+
+func (this *notifier) SetScenario(scenario *tool.Scenario) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.scenario = scenario
+}
+
+func (this *player) Play(url string) error {
+	this.Mutex.Lock()
+	this.url = url
+	scenario := this.scenario
+	this.Mutex.Unlock()
+
+	if scenario != nil {
+		scenario.Record("MediaPlayer.Play", url)
+	}
+	return nil
+}
+
+func (this *player) Stop() error {
+	return this.Play("")
+}
+
+func (this *player) URL() string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.url
+}
+
+func (this *player) SetScenario(scenario *tool.Scenario) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.scenario = scenario
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// EVENTS AND SESSIONS
+
+func (this *press) Name() string        { return "gpio" }
+func (this *press) Pin() gopi.GPIOPin   { return this.pin }
+func (this *press) Edge() gopi.GPIOEdge { return this.edge }
+func (this *session) SampleRate() uint  { return 8000 }
+
+func (this *session) Read(buf []int16) error {
+	time.Sleep(time.Millisecond)
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.frames == 0 {
+		return io.EOF
+	}
+	this.frames--
+	for i := range buf {
+		buf[i] = 100
+	}
+	return nil
+}
+
+func (this *session) Write(buf []int16) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.received += len(buf)
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Doorbell_001(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		fmt.Fprint(w, "JPEG")
+	}))
+	defer server.Close()
+
+	args := []string{
+		"-doorbell.button", "22", "-doorbell.holdoff", "500ms",
+		"-doorbell.snapshot", server.URL, "-doorbell.chime", "file:///chime.mp3",
+	}
+	tool.Test(t, args, new(App), func(app *App) {
+		ctx := context.Background()
+
+		// Button release and other pins are ignored, and pressing the
+		// button rings with the snapshot, sends a notification and plays
+		// the chime. Ringing within the hold-off time is refused
+		scenario := tool.NewScenario().
+			At(0, "release button and press another", func() error {
+				app.Publisher.Emit(&press{pin: 22, edge: gopi.GPIO_EDGE_RISING}, false)
+				return app.Publisher.Emit(&press{pin: 23, edge: gopi.GPIO_EDGE_FALLING}, false)
+			}).
+			At(50*time.Millisecond, "press button", func() error {
+				return app.Publisher.Emit(&press{pin: 22, edge: gopi.GPIO_EDGE_FALLING}, false)
+			}).
+			ExpectEvent(300*time.Millisecond, "ring with snapshot", func(evt gopi.Event) bool {
+				if testutil.IsType(gopi.DOORBELL_EVENT_RING)(evt) == false {
+					return false
+				}
+				attachments := evt.(gopi.EventWithAttachments).Attachments()
+				return len(attachments) == 1 && attachments[0].Type() == "image/jpeg" && attachments[0].Size() == 4
+			}).
+			ExpectCall(300*time.Millisecond, "Notifier.Notify", "Someone is at the door").
+			ExpectCall(300*time.Millisecond, "MediaPlayer.Play", "file:///chime.mp3").
+			At(300*time.Millisecond, "ring within hold-off", func() error {
+				if err := app.Doorbell.Ring(ctx); errors.Is(err, gopi.ErrOutOfOrder) == false {
+					return fmt.Errorf("Expected hold-off, got %v", err)
+				}
+				return nil
+			}).
+			At(700*time.Millisecond, "ring after hold-off", func() error {
+				return app.Doorbell.Ring(ctx)
+			})
+		app.Notifier.(*notifier).SetScenario(scenario)
+		app.MediaPlayer.(*player).SetScenario(scenario)
+		scenario.Test(t, app.Publisher)
+
+		// The notification carries the snapshot
+		if notifications := app.Notifier.(*notifier).Notifications(); len(notifications) == 0 {
+			t.Error("Unexpected notifications", notifications)
+		} else if len(notifications[0].Attachments) != 1 {
+			t.Error("Unexpected notification", notifications[0])
+		}
+		t.Log(app.Doorbell)
+	})
+}
+
+func Test_Doorbell_002(t *testing.T) {
+	args := []string{"-doorbell.input", "null", "-doorbell.output", "null"}
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Ringing without a snapshot has no attachments
+		if err := app.Doorbell.Ring(context.Background()); err != nil {
+			t.Error(err)
+		} else if evt, _ := testutil.NextType(ch, gopi.DOORBELL_EVENT_RING).(gopi.DoorbellEvent); evt == nil {
+			t.Error("Expected ring event")
+		} else if attachments := evt.(gopi.EventWithAttachments).Attachments(); len(attachments) != 0 {
+			t.Error("Unexpected attachments", attachments)
+		}
+
+		// Audio is carried until the session ends
+		s := &session{frames: 10}
+		if err := app.Doorbell.Answer(context.Background(), s); err != nil {
+			t.Error(err)
+		} else if testutil.NextType(ch, gopi.DOORBELL_EVENT_ANSWER) == nil || testutil.NextType(ch, gopi.DOORBELL_EVENT_HANGUP) == nil {
+			t.Error("Expected answer and hang up events")
+		} else if app.Doorbell.Answered() {
+			t.Error("Unexpected answered")
+		}
+
+		// Only one session is answered, until the context is done
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		errs := make(chan error)
+		go func() {
+			errs <- app.Doorbell.Answer(ctx, &session{frames: 1000})
+		}()
+		time.Sleep(100 * time.Millisecond)
+		if app.Doorbell.Answered() == false {
+			t.Error("Expected answered")
+		} else if err := app.Doorbell.Answer(context.Background(), &session{}); errors.Is(err, gopi.ErrOutOfOrder) == false {
+			t.Error("Expected error, got", err)
+		}
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package doorbell
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t           gopi.DoorbellEventType
+	attachments []gopi.EventAttachment
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(t gopi.DoorbellEventType, attachments ...gopi.EventAttachment) gopi.DoorbellEvent {
+	return &event{t, attachments}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "doorbell"
+}
+
+func (this *event) Type() gopi.DoorbellEventType {
+	return this.t
+}
+
+func (this *event) Attachments() []gopi.EventAttachment {
+	return this.attachments
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := fmt.Sprint("<doorbell.event type=", this.t)
+	for _, a := range this.attachments {
+		str += " " + fmt.Sprint(a)
+	}
+	return str + ">"
+}
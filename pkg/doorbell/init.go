@@ -0,0 +1,13 @@
+package doorbell
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.Doorbell
+	graph.RegisterUnit(reflect.TypeOf(&doorbell{}), reflect.TypeOf((*gopi.Doorbell)(nil)))
+}
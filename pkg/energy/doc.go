@@ -0,0 +1,21 @@
+// Energy package implements gopi.EnergyMonitor, which aggregates power
+// and energy readings from smart plugs and meters into cumulative energy
+// usage in kWh for each device, with daily and monthly totals.
+//
+// Readings from Tasmota and ESPHome smart plugs are recorded from the
+// gopi.ESPEvent events emitted by the pkg/dev/esp unit. Other sources,
+// such as Energenie MiHome sockets or Modbus meters, record readings
+// with the RecordPower and RecordEnergy methods. When a device has an
+// energy meter, the difference between meter readings is used, and a
+// meter which is reset to zero is handled. Otherwise, power readings
+// are integrated over time.
+//
+// The cost of energy is calculated from the -energy.tariff flag, which
+// is a price per kWh with optional prices for times of day. For example,
+// "0.28,00:30-04:30=0.075" is a price of 0.075 between 00:30 and 04:30
+// local time and 0.28 at other times.
+//
+// Usage is written to the file set with -energy.file and a measurement
+// emitted for each device every -energy.interval. Daily totals are kept
+// for -energy.days days, and monthly totals are kept indefinitely.
+package energy
@@ -0,0 +1,207 @@
+package energy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type energy struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Metrics
+
+	// Flags
+	file     *string
+	tariff   *string
+	interval *time.Duration
+	days     *uint
+
+	measurement string
+	prices      *tariff
+	ch          <-chan gopi.Event
+	store       store
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *energy) Define(cfg gopi.Config) error {
+	this.file = cfg.FlagPath("energy.file", "", "File to store energy usage")
+	this.tariff = cfg.FlagString("energy.tariff", "0", "Price per kWh, with optional prices for times of day (eg, 0.28,00:30-04:30=0.075)")
+	this.interval = cfg.FlagDuration("energy.interval", time.Minute, "Interval between writing usage and emitting measurements")
+	this.days = cfg.FlagUint("energy.days", 93, "Number of days of daily totals to keep")
+	cfg.FlagString("energy.measurement", "energy", "Measurement name")
+	return nil
+}
+
+func (this *energy) New(cfg gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if *this.interval <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-energy.interval")
+	} else if *this.days == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-energy.days")
+	} else if prices, err := parseTariff(*this.tariff); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-energy.tariff: ", err)
+	} else {
+		this.prices = prices
+	}
+
+	// Read stored usage
+	if err := this.store.Read(*this.file); err != nil {
+		return fmt.Errorf("%v: %w", *this.file, err)
+	}
+
+	// Define measurement
+	if measurement := cfg.GetString("energy.measurement"); measurement != "" && this.Metrics != nil {
+		if m, err := this.Metrics.NewMeasurement(measurement, "device string, power float64, energy float64, today float64, cost float64", this.Metrics.HostTag()); err != nil {
+			return err
+		} else {
+			this.measurement = m.Name()
+		}
+	}
+
+	// Subscribe to events before units run, so that readings are not missed
+	if this.Publisher != nil {
+		this.ch = this.Publisher.Subscribe()
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *energy) Dispose() error {
+	if this.ch != nil {
+		this.Publisher.Unsubscribe(this.ch)
+	}
+	this.ch = nil
+
+	// Write usage
+	return this.store.Write()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *energy) Run(ctx context.Context) error {
+	ticker := time.NewTicker(*this.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := this.flush(time.Now()); err != nil {
+				this.Print("Energy: ", err)
+			}
+		case evt := <-this.ch:
+			if evt, ok := evt.(gopi.ESPEvent); ok && evt.Type() == gopi.ESP_EVENT_CHANGED {
+				if err := this.recordESP(evt.Device(), evt.Entity()); err != nil {
+					this.Debug("Energy: ", err)
+				}
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *energy) RecordPower(device string, ts time.Time, watts float64) error {
+	if device == "" || watts < 0 || math.IsNaN(watts) || math.IsInf(watts, 0) {
+		return gopi.ErrBadParameter.WithPrefix("RecordPower")
+	} else if delta := this.store.Power(device, ts, watts, this.prices); delta > 0 {
+		this.Debug("Energy: ", device, " ", watts, "W +", delta, "kWh")
+	}
+	return nil
+}
+
+func (this *energy) RecordEnergy(device string, ts time.Time, kwh float64) error {
+	if device == "" || kwh < 0 || math.IsNaN(kwh) || math.IsInf(kwh, 0) {
+		return gopi.ErrBadParameter.WithPrefix("RecordEnergy")
+	} else if delta := this.store.Meter(device, ts, kwh, this.prices); delta > 0 {
+		this.Debug("Energy: ", device, " ", kwh, "kWh +", delta, "kWh")
+	}
+	return nil
+}
+
+func (this *energy) Devices() []string {
+	return this.store.Keys()
+}
+
+func (this *energy) Usage(device string, period gopi.EnergyPeriod, ts time.Time) gopi.EnergyUsage {
+	return this.store.Usage(device, period, ts)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *energy) String() string {
+	str := "<energy"
+	if *this.file != "" {
+		str += fmt.Sprintf(" file=%q", *this.file)
+	}
+	str += fmt.Sprintf(" tariff=%q", this.prices)
+	now := time.Now()
+	for _, device := range this.Devices() {
+		str += fmt.Sprint(" ", this.Usage(device, gopi.ENERGY_PERIOD_TOTAL, now))
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// recordESP records power and energy meter readings from Tasmota and
+// ESPHome smart plugs. Energy for today and yesterday is ignored, as
+// the meter total is used instead
+func (this *energy) recordESP(device gopi.ESPDevice, entity gopi.ESPEntity) error {
+	if device == nil || entity == nil || entity.Type() != gopi.ESP_ENTITY_SENSOR {
+		return nil
+	}
+	id := entity.Id()
+	switch entity.Unit() {
+	case "W":
+		return this.RecordPower(device.Name(), time.Now(), entity.Value())
+	case "kW":
+		return this.RecordPower(device.Name(), time.Now(), entity.Value()*1000)
+	case "Wh", "kWh":
+		if strings.HasSuffix(id, "/Today") || strings.HasSuffix(id, "/Yesterday") {
+			return nil
+		} else if entity.Unit() == "Wh" {
+			return this.RecordEnergy(device.Name(), time.Now(), entity.Value()/1000)
+		} else {
+			return this.RecordEnergy(device.Name(), time.Now(), entity.Value())
+		}
+	}
+	return nil
+}
+
+// flush prunes daily totals, emits a measurement for each device and
+// writes usage to the file
+func (this *energy) flush(now time.Time) error {
+	this.store.Prune(now.AddDate(0, 0, -int(*this.days)))
+
+	if this.measurement != "" {
+		for _, device := range this.Devices() {
+			total := this.Usage(device, gopi.ENERGY_PERIOD_TOTAL, now)
+			today := this.Usage(device, gopi.ENERGY_PERIOD_DAY, now)
+			if err := this.Metrics.Emit(this.measurement, nil, device, total.Power, total.Energy, today.Energy, total.Cost); err != nil {
+				return err
+			}
+		}
+	}
+
+	return this.store.Write()
+}
@@ -0,0 +1,175 @@
+package energy_test
+
+import (
+	"context"
+	"math"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/energy"
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.EnergyMonitor
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+type espEvent struct {
+	device espDevice
+	entity espEntity
+}
+
+type espDevice struct{ name string }
+
+type espEntity struct {
+	id, unit string
+	value    float64
+}
+
+func (e espEvent) Name() string                { return e.device.name }
+func (e espEvent) Type() gopi.ESPEventType     { return gopi.ESP_EVENT_CHANGED }
+func (e espEvent) Device() gopi.ESPDevice      { return e.device }
+func (e espEvent) Entity() gopi.ESPEntity      { return e.entity }
+func (d espDevice) Id() string                 { return d.name }
+func (d espDevice) Name() string               { return d.name }
+func (d espDevice) Firmware() gopi.ESPFirmware { return gopi.ESP_FIRMWARE_TASMOTA }
+func (d espDevice) Model() string              { return "" }
+func (d espDevice) Version() string            { return "" }
+func (d espDevice) Addr() net.IP               { return nil }
+func (d espDevice) Online() bool               { return true }
+func (d espDevice) Entities() []gopi.ESPEntity { return nil }
+func (e espEntity) Id() string                 { return e.id }
+func (e espEntity) Name() string               { return e.id }
+func (e espEntity) Type() gopi.ESPEntityType   { return gopi.ESP_ENTITY_SENSOR }
+func (e espEntity) State() bool                { return false }
+func (e espEntity) Brightness() float32        { return 0 }
+func (e espEntity) Value() float64             { return e.value }
+func (e espEntity) Unit() string               { return e.unit }
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Energy_001(t *testing.T) {
+	args := []string{"-energy.tariff", "0.2"}
+	tool.Test(t, args, new(App), func(app *App) {
+		// 1kW for 30 minutes is 0.5kWh
+		ts := time.Date(2021, 3, 1, 12, 0, 0, 0, time.Local)
+		for i := 0; i <= 3; i++ {
+			if err := app.EnergyMonitor.RecordPower("heater", ts.Add(time.Duration(i)*10*time.Minute), 1000); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Power is not integrated over a long gap
+		if err := app.EnergyMonitor.RecordPower("heater", ts.Add(3*time.Hour), 500); err != nil {
+			t.Fatal(err)
+		}
+		usage := app.EnergyMonitor.Usage("heater", gopi.ENERGY_PERIOD_TOTAL, ts)
+		if math.Abs(usage.Energy-0.5) > 1e-9 || math.Abs(usage.Cost-0.1) > 1e-9 || usage.Power != 500 || usage.Start.Equal(ts) == false {
+			t.Error("Unexpected usage", usage)
+		}
+		if day := app.EnergyMonitor.Usage("heater", gopi.ENERGY_PERIOD_DAY, ts); math.Abs(day.Energy-0.5) > 1e-9 {
+			t.Error("Unexpected daily usage", day)
+		}
+		if day := app.EnergyMonitor.Usage("heater", gopi.ENERGY_PERIOD_DAY, ts.AddDate(0, 0, 1)); day.Energy != 0 {
+			t.Error("Unexpected daily usage", day)
+		}
+		if err := app.EnergyMonitor.RecordPower("heater", ts, -1); err == nil {
+			t.Error("Expected error for negative power")
+		}
+		t.Log(app.EnergyMonitor)
+	})
+}
+
+func Test_Energy_002(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "energy.json")
+	args := []string{"-energy.file", file, "-energy.tariff", "0.3,00:30-04:30=0.1"}
+	day1 := time.Date(2021, 3, 31, 0, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+
+	tool.Test(t, args, new(App), func(app *App) {
+		// Meter readings, where the meter is reset on the second day
+		for _, reading := range []struct {
+			ts  time.Time
+			kwh float64
+		}{
+			{day1.Add(1 * time.Hour), 100},
+			{day1.Add(2 * time.Hour), 101},
+			{day1.Add(12 * time.Hour), 102},
+			{day2.Add(12 * time.Hour), 0.5},
+		} {
+			if err := app.EnergyMonitor.RecordEnergy("plug", reading.ts, reading.kwh); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Power readings are not integrated for metered devices
+		app.EnergyMonitor.RecordPower("plug", day2.Add(12*time.Hour), 1000)
+		app.EnergyMonitor.RecordPower("plug", day2.Add(12*time.Hour+10*time.Minute), 1000)
+	})
+
+	// Read usage back from the file
+	tool.Test(t, args, new(App), func(app *App) {
+		if devices := app.EnergyMonitor.Devices(); len(devices) != 1 || devices[0] != "plug" {
+			t.Fatal("Unexpected devices", devices)
+		}
+		total := app.EnergyMonitor.Usage("plug", gopi.ENERGY_PERIOD_TOTAL, day2)
+		if math.Abs(total.Energy-2.5) > 1e-9 || math.Abs(total.Cost-0.55) > 1e-9 || total.Power != 1000 {
+			t.Error("Unexpected usage", total)
+		}
+		for _, expected := range []struct {
+			period gopi.EnergyPeriod
+			ts     time.Time
+			energy float64
+		}{
+			{gopi.ENERGY_PERIOD_DAY, day1, 2},
+			{gopi.ENERGY_PERIOD_DAY, day2, 0.5},
+			{gopi.ENERGY_PERIOD_MONTH, day1, 2},
+			{gopi.ENERGY_PERIOD_MONTH, day2, 0.5},
+		} {
+			if usage := app.EnergyMonitor.Usage("plug", expected.period, expected.ts); math.Abs(usage.Energy-expected.energy) > 1e-9 {
+				t.Error("Unexpected usage", usage)
+			}
+		}
+	})
+}
+
+func Test_Energy_003(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		// Tasmota plug power and meter readings
+		for _, entity := range []espEntity{
+			{"ENERGY/Total", "kWh", 10},
+			{"ENERGY/Today", "kWh", 1},
+			{"ENERGY/Power", "W", 60},
+			{"ENERGY/Total", "kWh", 10.25},
+		} {
+			if err := app.Publisher.Emit(espEvent{espDevice{"lamp"}, entity}, true); err != nil {
+				t.Fatal(err)
+			}
+		}
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			if usage := app.EnergyMonitor.Usage("lamp", gopi.ENERGY_PERIOD_TOTAL, time.Now()); usage.Energy > 0 {
+				if math.Abs(usage.Energy-0.25) > 1e-9 || usage.Power != 60 {
+					t.Error("Unexpected usage", usage)
+				}
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Error("Timeout waiting for usage")
+	})
+}
@@ -0,0 +1,13 @@
+package energy
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.EnergyMonitor
+	graph.RegisterUnit(reflect.TypeOf(&energy{}), reflect.TypeOf((*gopi.EnergyMonitor)(nil)))
+}
@@ -0,0 +1,260 @@
+package energy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// store holds cumulative energy usage for each device, with daily and
+// monthly totals, and is persisted to a file so that totals survive
+// restarts
+type store struct {
+	sync.Mutex
+
+	path    string
+	Devices map[string]*usage `json:"devices"`
+}
+
+type usage struct {
+	Since     time.Time          `json:"since"`
+	Power     float64            `json:"power"`
+	PowerTime time.Time          `json:"power_time"`
+	Meter     float64            `json:"meter,omitempty"`
+	Metered   bool               `json:"metered,omitempty"`
+	Energy    float64            `json:"energy"`
+	Cost      float64            `json:"cost"`
+	Days      map[string]*period `json:"days"`
+	Months    map[string]*period `json:"months"`
+}
+
+type period struct {
+	Energy float64 `json:"energy"`
+	Cost   float64 `json:"cost"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	dayFormat   = "2006-01-02"
+	monthFormat = "2006-01"
+
+	// Power is not integrated over gaps between readings longer than
+	// this, as the device is assumed to have been offline
+	maxGap = 15 * time.Minute
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// Read usage from a file, which is created when usage is written.
+// When path is empty, usage is not persisted
+func (this *store) Read(path string) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	this.path = path
+	this.Devices = make(map[string]*usage)
+
+	if path == "" {
+		return nil
+	} else if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	} else if fh, err := os.Open(path); err != nil {
+		return err
+	} else {
+		defer fh.Close()
+		if err := json.NewDecoder(fh).Decode(this); err != nil {
+			return err
+		}
+	}
+
+	// Set missing rollups
+	for _, u := range this.Devices {
+		if u.Days == nil {
+			u.Days = make(map[string]*period)
+		}
+		if u.Months == nil {
+			u.Months = make(map[string]*period)
+		}
+	}
+
+	// Success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// Power records power for a device, and integrates power over time
+// when the device has no meter. It returns the energy added in kWh
+func (this *store) Power(key string, ts time.Time, watts float64, t *tariff) float64 {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	u := this.usage(key, ts)
+	delta := 0.0
+	if u.Metered == false && u.PowerTime.IsZero() == false {
+		if dt := ts.Sub(u.PowerTime); dt > 0 && dt <= maxGap {
+			delta = (u.Power + watts) / 2 * dt.Hours() / 1000
+		}
+	}
+	if ts.After(u.PowerTime) || u.PowerTime.IsZero() {
+		u.Power, u.PowerTime = watts, ts
+	}
+	u.add(delta, ts, t)
+	return delta
+}
+
+// Meter records the energy meter for a device, and returns the energy
+// added in kWh since the last reading. When the meter has been reset,
+// the reading is the energy used since the reset
+func (this *store) Meter(key string, ts time.Time, kwh float64, t *tariff) float64 {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	u := this.usage(key, ts)
+	delta := 0.0
+	if u.Metered {
+		if delta = kwh - u.Meter; delta < 0 {
+			delta = kwh
+		}
+	}
+	u.Meter, u.Metered = kwh, true
+	u.add(delta, ts, t)
+	return delta
+}
+
+// Keys returns the devices in alphabetical order
+func (this *store) Keys() []string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	keys := make([]string, 0, len(this.Devices))
+	for key := range this.Devices {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Usage returns usage for a device over a period including a time
+func (this *store) Usage(key string, p gopi.EnergyPeriod, ts time.Time) gopi.EnergyUsage {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	result := gopi.EnergyUsage{Device: key, Period: p}
+	u, exists := this.Devices[key]
+	if exists == false {
+		return result
+	}
+	result.Power = u.Power
+
+	ts = ts.Local()
+	switch p {
+	case gopi.ENERGY_PERIOD_TOTAL:
+		result.Start, result.Energy, result.Cost = u.Since, u.Energy, u.Cost
+	case gopi.ENERGY_PERIOD_DAY:
+		result.Start = time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.Local)
+		if day, exists := u.Days[ts.Format(dayFormat)]; exists {
+			result.Energy, result.Cost = day.Energy, day.Cost
+		}
+	case gopi.ENERGY_PERIOD_MONTH:
+		result.Start = time.Date(ts.Year(), ts.Month(), 1, 0, 0, 0, 0, time.Local)
+		if month, exists := u.Months[ts.Format(monthFormat)]; exists {
+			result.Energy, result.Cost = month.Energy, month.Cost
+		}
+	}
+	return result
+}
+
+// Prune removes daily totals before a time
+func (this *store) Prune(before time.Time) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	key := before.Local().Format(dayFormat)
+	for _, u := range this.Devices {
+		for day := range u.Days {
+			if day < key {
+				delete(u.Days, day)
+			}
+		}
+	}
+}
+
+// Write usage through a temporary file so the file is
+// never partially written
+func (this *store) Write() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(this.path), 0700); err != nil {
+		return err
+	}
+	tmp := this.path + ".tmp"
+	if fh, err := os.Create(tmp); err != nil {
+		return err
+	} else if err := json.NewEncoder(fh).Encode(this); err != nil {
+		fh.Close()
+		return err
+	} else if err := fh.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, this.path)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// usage returns usage for a device, creating it if necessary
+func (this *store) usage(key string, ts time.Time) *usage {
+	if u, exists := this.Devices[key]; exists {
+		return u
+	}
+	u := &usage{
+		Since:  ts,
+		Days:   make(map[string]*period),
+		Months: make(map[string]*period),
+	}
+	this.Devices[key] = u
+	return u
+}
+
+// add energy used at a time to the totals, at the tariff price
+func (u *usage) add(kwh float64, ts time.Time, t *tariff) {
+	if kwh <= 0 {
+		return
+	}
+	cost := kwh * t.Price(ts)
+	u.Energy += kwh
+	u.Cost += cost
+
+	ts = ts.Local()
+	for _, rollup := range []struct {
+		periods map[string]*period
+		key     string
+	}{
+		{u.Days, ts.Format(dayFormat)},
+		{u.Months, ts.Format(monthFormat)},
+	} {
+		if p, exists := rollup.periods[rollup.key]; exists {
+			p.Energy += kwh
+			p.Cost += cost
+		} else {
+			rollup.periods[rollup.key] = &period{kwh, cost}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package energy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// tariff is a price per kWh, with optional prices for times of day
+type tariff struct {
+	price float64
+	rates []rate
+}
+
+// rate is a price between two times of day. When start is after end,
+// the rate spans midnight
+type rate struct {
+	start, end time.Duration
+	price      float64
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// parseTariff returns a tariff from comma-separated prices, where one
+// price has no times and is used outside of the times of the other
+// prices. For example, "0.28,00:30-04:30=0.075" is a price of 0.075
+// between 00:30 and 04:30 and 0.28 at other times
+func parseTariff(value string) (*tariff, error) {
+	t := new(tariff)
+	base := false
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		times, price := "", field
+		if i := strings.Index(field, "="); i >= 0 {
+			times, price = strings.TrimSpace(field[:i]), strings.TrimSpace(field[i+1:])
+		}
+		p, err := strconv.ParseFloat(price, 64)
+		if err != nil || p < 0 {
+			return nil, fmt.Errorf("Invalid price %q", price)
+		}
+		if times == "" {
+			if base {
+				return nil, fmt.Errorf("More than one price without times")
+			}
+			t.price, base = p, true
+			continue
+		}
+		r := rate{price: p}
+		if i := strings.Index(times, "-"); i < 0 {
+			return nil, fmt.Errorf("Invalid times %q", times)
+		} else if r.start, err = parseTimeOfDay(times[:i]); err != nil {
+			return nil, err
+		} else if r.end, err = parseTimeOfDay(times[i+1:]); err != nil {
+			return nil, err
+		} else if r.start == r.end {
+			return nil, fmt.Errorf("Invalid times %q", times)
+		}
+		t.rates = append(t.rates, r)
+	}
+	return t, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// Price returns the price per kWh at a time, in local time
+func (this *tariff) Price(ts time.Time) float64 {
+	if this == nil {
+		return 0
+	}
+	ts = ts.Local()
+	tod := time.Duration(ts.Hour())*time.Hour + time.Duration(ts.Minute())*time.Minute + time.Duration(ts.Second())*time.Second
+	for _, r := range this.rates {
+		if r.start < r.end && tod >= r.start && tod < r.end {
+			return r.price
+		} else if r.start > r.end && (tod >= r.start || tod < r.end) {
+			return r.price
+		}
+	}
+	return this.price
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *tariff) String() string {
+	str := strconv.FormatFloat(this.price, 'f', -1, 64)
+	for _, r := range this.rates {
+		str += fmt.Sprintf(",%s-%s=%v", formatTimeOfDay(r.start), formatTimeOfDay(r.end), r.price)
+	}
+	return str
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// parseTimeOfDay returns the duration since midnight from HH:MM
+func parseTimeOfDay(value string) (time.Duration, error) {
+	if t, err := time.Parse("15:04", strings.TrimSpace(value)); err != nil {
+		return 0, fmt.Errorf("Invalid time %q", value)
+	} else {
+		return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+	}
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,243 @@
+package entry
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type credential struct {
+	name    string
+	cards   []string
+	pins    []string
+	days    [7]bool  // Days of the week when entry is allowed
+	windows []window // Times of day when entry is allowed, or all day
+	expires time.Time
+}
+
+// window is a time of day from start until end, which ends the next
+// day when end is before start
+type window struct {
+	start, end time.Duration
+}
+
+// config is the entry for a holder in the credentials file
+type config struct {
+	Cards   []string `json:"cards"`
+	Pins    []string `json:"pins"`
+	Days    []string `json:"days"`
+	Times   []string `json:"times"`
+	Expires string   `json:"expires"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Minimum number of digits in a PIN code
+	minPinLength = 4
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newCredential(name string, cfg config) (*credential, error) {
+	this := &credential{name: name}
+	for _, value := range cfg.Cards {
+		if card := normalize(value); card == "" {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": card ", strconv.Quote(value))
+		} else {
+			this.cards = append(this.cards, card)
+		}
+	}
+	for _, value := range cfg.Pins {
+		if pin := normalize(value); len(pin) < minPinLength || strings.Trim(pin, "0123456789") != "" {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": pin requires at least ", minPinLength, " digits")
+		} else {
+			this.pins = append(this.pins, pin)
+		}
+	}
+	if len(this.cards) == 0 && len(this.pins) == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix(name, ": Missing cards or pins")
+	}
+	if len(cfg.Days) == 0 {
+		for i := range this.days {
+			this.days[i] = true
+		}
+	}
+	for _, value := range cfg.Days {
+		if day, err := parseWeekday(value); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": day ", strconv.Quote(value))
+		} else {
+			this.days[day] = true
+		}
+	}
+	for _, value := range cfg.Times {
+		if w, err := parseWindow(value); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": time ", strconv.Quote(value))
+		} else {
+			this.windows = append(this.windows, w)
+		}
+	}
+	if cfg.Expires != "" {
+		if t, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(cfg.Expires), time.Local); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": expires ", strconv.Quote(cfg.Expires))
+		} else {
+			// Expires at the end of the day
+			this.expires = t.AddDate(0, 0, 1)
+		}
+	}
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+// Matches returns true if a card or code is one of the cards or pins
+// of the holder. Every value is compared, in constant time
+func (this *credential) Matches(value string) bool {
+	match := 0
+	for _, card := range this.cards {
+		match |= subtle.ConstantTimeCompare([]byte(card), []byte(value))
+	}
+	for _, pin := range this.pins {
+		match |= subtle.ConstantTimeCompare([]byte(pin), []byte(value))
+	}
+	return match == 1
+}
+
+// Allowed returns nil if entry is allowed at a time, or the reason
+// entry is not allowed
+func (this *credential) Allowed(t time.Time) error {
+	if this.expires.IsZero() == false && t.Before(this.expires) == false {
+		return fmt.Errorf("Credential expired")
+	}
+	if len(this.windows) == 0 {
+		if this.days[t.Weekday()] == false {
+			return fmt.Errorf("Outside schedule")
+		}
+		return nil
+	}
+
+	// A window which ends the next day is allowed on the day it starts
+	tod := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	yesterday := (t.Weekday() + 6) % 7
+	for _, w := range this.windows {
+		if w.start <= w.end {
+			if this.days[t.Weekday()] && tod >= w.start && tod < w.end {
+				return nil
+			}
+		} else if this.days[t.Weekday()] && tod >= w.start {
+			return nil
+		} else if this.days[yesterday] && tod < w.end {
+			return nil
+		}
+	}
+	return fmt.Errorf("Outside schedule")
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *credential) String() string {
+	str := "<entry.credential"
+	str += fmt.Sprintf(" name=%q cards=%d pins=%d", this.name, len(this.cards), len(this.pins))
+	for _, w := range this.windows {
+		str += " time=" + w.String()
+	}
+	if this.expires.IsZero() == false {
+		str += " expires=" + this.expires.Format(time.RFC3339)
+	}
+	return str + ">"
+}
+
+func (w window) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", int(w.start.Hours()), int(w.start.Minutes())%60, int(w.end.Hours()), int(w.end.Minutes())%60)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// readCredentials returns credentials from a JSON file, keyed by the
+// name of the holder
+func readCredentials(path string) (map[string]*credential, error) {
+	var holders map[string]config
+	if data, err := ioutil.ReadFile(path); err != nil {
+		return nil, err
+	} else if err := json.Unmarshal(data, &holders); err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	result := make(map[string]*credential, len(holders))
+	values := make(map[string]string)
+	for name, cfg := range holders {
+		if name = strings.TrimSpace(name); name == "" {
+			return nil, gopi.ErrBadParameter.WithPrefix(path, ": Missing name")
+		}
+		credential, err := newCredential(name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		// A card or pin identifies one holder
+		for _, value := range append(append([]string{}, credential.cards...), credential.pins...) {
+			if other, exists := values[value]; exists {
+				return nil, gopi.ErrDuplicateEntry.WithPrefix(path, ": ", name, " and ", other)
+			}
+			values[value] = name
+		}
+		result[name] = credential
+	}
+	return result, nil
+}
+
+// normalize returns a card or code without spaces, in uppercase
+func normalize(value string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(value), ""))
+}
+
+// parseWindow returns a window from start and end times, such as
+// "08:00-18:00" or "22:00-06:00"
+func parseWindow(value string) (window, error) {
+	var w window
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return w, fmt.Errorf("Invalid time %q", value)
+	}
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return w, err
+		}
+		d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+		if i == 0 {
+			w.start = d
+		} else {
+			w.end = d
+		}
+	}
+	if w.start == w.end {
+		return w, fmt.Errorf("Invalid time %q", value)
+	}
+	return w, nil
+}
+
+// parseWeekday returns a day of the week from its name or abbreviation
+func parseWeekday(value string) (time.Weekday, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if len(value) >= 3 {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.HasPrefix(strings.ToLower(day.String()), value) {
+				return day, nil
+			}
+		}
+	}
+	return time.Sunday, fmt.Errorf("Invalid day %q", value)
+}
@@ -0,0 +1,174 @@
+package entry
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type key struct {
+	gopi.Event
+	code gopi.KeyCode
+	t    gopi.InputType
+	id   uint32
+}
+
+func (this *key) Key() gopi.KeyCode    { return this.code }
+func (this *key) Type() gopi.InputType { return this.t }
+func (this *key) Device() (gopi.InputDeviceType, uint32) {
+	return gopi.INPUT_DEVICE_KEYBOARD, this.id
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Credential_001(t *testing.T) {
+	credential, err := newCredential("alice", config{Cards: []string{"04 a1 b2 c3"}, Pins: []string{"1234"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{"04A1B2C3", "1234"} {
+		if credential.Matches(value) == false {
+			t.Error("Expected match for", value)
+		}
+	}
+	for _, value := range []string{"04A1B2", "12345", ""} {
+		if credential.Matches(value) {
+			t.Error("Unexpected match for", value)
+		}
+	}
+	if err := credential.Allowed(time.Now()); err != nil {
+		t.Error(err)
+	}
+
+	// Cards or pins are required, and pins are at least four digits
+	for _, cfg := range []config{
+		{},
+		{Pins: []string{"123"}},
+		{Pins: []string{"12a4"}},
+		{Cards: []string{" "}},
+		{Cards: []string{"1"}, Days: []string{"fu"}},
+		{Cards: []string{"1"}, Times: []string{"08:00"}},
+		{Cards: []string{"1"}, Times: []string{"08:00-08:00"}},
+		{Cards: []string{"1"}, Expires: "tomorrow"},
+	} {
+		if _, err := newCredential("bob", cfg); err == nil {
+			t.Error("Expected error for", cfg)
+		}
+	}
+}
+
+func Test_Credential_002(t *testing.T) {
+	// Weekdays in the day, and overnight from Friday
+	credential, err := newCredential("bob", config{
+		Cards: []string{"1"},
+		Days:  []string{"mon", "tuesday", "Wed", "thu", "fri"},
+		Times: []string{"08:00-18:00", "22:00-02:00"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		time    string
+		allowed bool
+	}{
+		{"2021-03-01 08:00", true},  // Monday
+		{"2021-03-01 07:59", false}, // Monday
+		{"2021-03-01 18:00", false}, // Monday
+		{"2021-03-05 23:00", true},  // Friday
+		{"2021-03-06 01:59", true},  // Saturday, from Friday
+		{"2021-03-06 12:00", false}, // Saturday
+		{"2021-03-07 23:00", false}, // Sunday
+		{"2021-03-01 01:00", false}, // Monday, from Sunday
+	}
+	for _, test := range tests {
+		now, _ := time.ParseInLocation("2006-01-02 15:04", test.time, time.Local)
+		if err := credential.Allowed(now); test.allowed && err != nil {
+			t.Error(test.time, err)
+		} else if test.allowed == false && err == nil {
+			t.Error("Expected denied at", test.time)
+		}
+	}
+
+	// Expires at the end of the day
+	credential, _ = newCredential("carol", config{Pins: []string{"4321"}, Expires: "2021-03-01"})
+	for _, test := range tests[:2] {
+		now, _ := time.ParseInLocation("2006-01-02 15:04", test.time, time.Local)
+		if err := credential.Allowed(now); err != nil {
+			t.Error(err)
+		}
+	}
+	if err := credential.Allowed(time.Date(2021, 3, 2, 0, 0, 0, 0, time.Local)); err == nil {
+		t.Error("Expected credential to have expired")
+	}
+}
+
+func Test_Credential_003(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	write := func(data string) {
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{ "alice": { "cards": [ "04a1" ] }, "bob": { "pins": [ "1234" ] } }`)
+	if holders, err := readCredentials(path); err != nil {
+		t.Error(err)
+	} else if len(holders) != 2 || holders["alice"].Matches("04A1") == false {
+		t.Error("Unexpected holders", holders)
+	}
+
+	// A card or pin cannot be shared
+	write(`{ "alice": { "cards": [ "1234" ] }, "bob": { "pins": [ "1234" ] } }`)
+	if _, err := readCredentials(path); err == nil {
+		t.Error("Expected error for shared card")
+	}
+	write(`{ "alice": { "cards": [ "04a1" ] `)
+	if _, err := readCredentials(path); err == nil {
+		t.Error("Expected error for invalid file")
+	}
+}
+
+func Test_Keypad_001(t *testing.T) {
+	keypad := newKeypad()
+	now := time.Now()
+	press := func(id uint32, codes ...gopi.KeyCode) string {
+		var value string
+		for _, code := range codes {
+			value = keypad.Key(&key{code: code, t: gopi.INPUT_EVENT_KEYPRESS, id: id}, now)
+		}
+		return value
+	}
+
+	// Keys are collected until enter, and releases are ignored
+	if value := press(1, gopi.KEYCODE_1, gopi.KEYCODE_KP2, gopi.KEYCODE_A, gopi.KEYCODE_Z, gopi.KEYCODE_ENTER); value != "12A" {
+		t.Error("Unexpected value", value)
+	}
+	keypad.Key(&key{code: gopi.KEYCODE_9, t: gopi.INPUT_EVENT_KEYRELEASE, id: 1}, now)
+	if value := press(1, gopi.KEYCODE_KPENTER); value != "" {
+		t.Error("Unexpected value", value)
+	}
+
+	// Devices are collected separately, and escape discards keys
+	press(1, gopi.KEYCODE_1, gopi.KEYCODE_2)
+	press(2, gopi.KEYCODE_3)
+	if value := press(1, gopi.KEYCODE_ESC, gopi.KEYCODE_4, gopi.KEYCODE_ENTER); value != "4" {
+		t.Error("Unexpected value", value)
+	}
+	if value := press(2, gopi.KEYCODE_ENTER); value != "3" {
+		t.Error("Unexpected value", value)
+	}
+
+	// Keys are discarded after the timeout
+	press(1, gopi.KEYCODE_5)
+	now = now.Add(keypadTimeout + time.Second)
+	if value := press(1, gopi.KEYCODE_6, gopi.KEYCODE_ENTER); value != "6" {
+		t.Error("Unexpected value", value)
+	}
+}
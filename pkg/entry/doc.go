@@ -0,0 +1,39 @@
+// Entry package implements gopi.EntryControl, which releases a door
+// strike on the gopi.Relay channel set with -entry.relay for
+// -entry.release when a known card or PIN code is presented at a reader.
+// Presenting again while the strike is released extends the time.
+//
+// Credentials are read from the JSON file set with -entry.credentials,
+// keyed by holder name:
+//
+//   {
+//     "alice": { "cards": [ "04A1B2C3" ], "pins": [ "1234" ] },
+//     "bob": {
+//       "cards": [ "04D4E5F6" ], "days": [ "mon", "tue", "wed" ],
+//       "times": [ "08:00-18:00" ], "expires": "2021-12-31"
+//     }
+//   }
+//
+// Each holder is allowed on the days and within the times set, or at any
+// time when they are not set, until the credential expires. Card numbers
+// are compared without case or whitespace, and PIN codes need at least
+// four digits. The file is read again when it changes, so that it can
+// be synced from elsewhere, and the current credentials are kept when it
+// cannot be read. Entry continues without a network.
+//
+// Card readers which act as keyboards, and keypads, are read as
+// gopi.InputEvent where the code is ended with the enter key and cleared
+// with escape or backspace. These are presented at the reader named with
+// -entry.keypad. Other readers call Present with their own name. After
+// five unknown credentials a reader is locked for a minute.
+//
+// Readers in the comma-separated -entry.exits are exits, and the rest
+// are entrances. With -entry.passback, a holder who has entered cannot
+// enter again until they have exited, and the reverse.
+//
+// A gopi.EntryEvent is emitted when entry is granted or denied and when
+// the strike is released. When there is a gopi.AuditLog, each is recorded
+// with the holder as the identity, the reader as the old value and
+// "granted", "denied" or "release" as the new value. Entries which cannot
+// be recorded are kept and recorded later.
+package entry
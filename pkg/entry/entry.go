@@ -0,0 +1,405 @@
+package entry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type entry struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Relay
+	gopi.AuditLog
+	sync.Mutex
+
+	// Flags
+	path     *string
+	relay    *string
+	release  *time.Duration
+	exits    *string
+	passback *bool
+	keypad   *string
+
+	holders  map[string]*credential
+	modified time.Time
+	exit     map[string]bool
+	inside   map[string]bool
+	readers  map[string]*reader
+	pending  []gopi.AuditEntry
+	strike   *time.Timer
+	keys     *keypad
+	ch       <-chan gopi.Event
+	audit    sync.Mutex // Serializes recording in the audit log
+}
+
+// reader counts consecutive failures, and is locked after too many
+type reader struct {
+	failures uint
+	locked   time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Interval between checking for changes to the credentials file and
+	// recording entries which could not be recorded in the audit log
+	syncInterval = 10 * time.Second
+
+	// A reader is locked for lockoutTime after maxFailures unknown
+	// credentials, so that codes cannot be guessed
+	maxFailures = 5
+	lockoutTime = time.Minute
+
+	// Maximum number of entries waiting to be recorded in the audit log
+	maxPending = 1000
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *entry) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagPath("entry.credentials", "", "JSON file of credentials, reloaded when it changes")
+	this.relay = cfg.FlagString("entry.relay", "", "Relay channel for the door strike")
+	this.release = cfg.FlagDuration("entry.release", 5*time.Second, "Time the door strike is released")
+	this.exits = cfg.FlagString("entry.exits", "", "Comma-separated readers which are exits")
+	this.passback = cfg.FlagBool("entry.passback", false, "Deny entry when inside and exit when outside")
+	this.keypad = cfg.FlagString("entry.keypad", "keypad", "Reader name for keypads and card readers which are input devices, or empty to disable")
+	return nil
+}
+
+func (this *entry) New(gopi.Config) error {
+	this.Require(this.Logger, this.Relay)
+
+	// Check parameters
+	if *this.release <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-entry.release")
+	} else if *this.path == "" {
+		return gopi.ErrBadParameter.WithPrefix("-entry.credentials")
+	}
+
+	// Check relay channel exists
+	*this.relay = strings.TrimSpace(*this.relay)
+	if *this.relay == "" {
+		return gopi.ErrBadParameter.WithPrefix("-entry.relay")
+	} else if exists := func() bool {
+		for _, name := range this.Relay.Channels() {
+			if name == *this.relay {
+				return true
+			}
+		}
+		return false
+	}(); exists == false {
+		return gopi.ErrNotFound.WithPrefix("-entry.relay: ", strconv.Quote(*this.relay))
+	}
+
+	// Read credentials
+	if err := this.reload(); err != nil {
+		return err
+	}
+
+	// Set exits
+	this.exit = make(map[string]bool)
+	for _, name := range strings.Split(*this.exits, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			this.exit[name] = true
+		}
+	}
+	this.inside = make(map[string]bool)
+	this.readers = make(map[string]*reader)
+
+	// Subscribe to input events before units run
+	*this.keypad = strings.TrimSpace(*this.keypad)
+	if this.Publisher != nil && *this.keypad != "" {
+		this.keys = newKeypad()
+		this.ch = this.Publisher.Subscribe()
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *entry) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Unsubscribe from events
+	if this.ch != nil {
+		this.Publisher.Unsubscribe(this.ch)
+	}
+
+	// Lock the door
+	var result error
+	if this.strike != nil {
+		this.strike.Stop()
+		ctx, cancel := context.WithTimeout(context.Background(), *this.release)
+		defer cancel()
+		result = this.Relay.Set(ctx, *this.relay, false)
+	}
+
+	// Release resources
+	this.ch = nil
+	this.strike = nil
+	this.holders = nil
+	this.readers = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *entry) Run(ctx context.Context) error {
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := this.reload(); err != nil {
+				this.Print("Entry: ", err)
+			}
+			this.record(ctx, nil)
+		case evt := <-this.ch:
+			if evt, ok := evt.(gopi.InputEvent); ok {
+				this.Mutex.Lock()
+				value := this.keys.Key(evt, time.Now())
+				this.Mutex.Unlock()
+				if value != "" {
+					// Denied entry is logged and emitted
+					this.Present(ctx, *this.keypad, value)
+				}
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *entry) Present(ctx context.Context, reader, value string) error {
+	now := time.Now()
+	this.Mutex.Lock()
+	name, err := this.check(reader, normalize(value), now)
+	this.Mutex.Unlock()
+
+	if err != nil {
+		this.changed(ctx, gopi.ENTRY_EVENT_DENIED, name, reader, err)
+		return err
+	} else if err := this.unlock(ctx); err != nil {
+		return err
+	}
+
+	// Record whether the holder is inside once the door is unlocked, so
+	// that a failure to unlock does not deny their next entry
+	if *this.passback {
+		this.Mutex.Lock()
+		this.inside[name] = this.exit[reader] == false
+		this.Mutex.Unlock()
+	}
+
+	this.changed(ctx, gopi.ENTRY_EVENT_GRANTED, name, reader, nil)
+	return nil
+}
+
+func (this *entry) Release(ctx context.Context) error {
+	if err := this.unlock(ctx); err != nil {
+		return err
+	}
+	this.changed(ctx, gopi.ENTRY_EVENT_RELEASE, "", "", nil)
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *entry) String() string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	str := "<entry"
+	str += fmt.Sprintf(" relay=%q", *this.relay)
+	str += fmt.Sprint(" holders=", len(this.holders))
+	if *this.passback {
+		str += fmt.Sprint(" inside=", len(this.inside))
+	}
+	if len(this.pending) > 0 {
+		str += fmt.Sprint(" pending=", len(this.pending))
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// check returns the holder of a credential presented at a reader, and an
+// error when entry is denied, and should be called with the lock held
+func (this *entry) check(name, value string, now time.Time) (string, error) {
+	r, exists := this.readers[name]
+	if exists == false {
+		r = new(reader)
+		this.readers[name] = r
+	}
+	if now.Before(r.locked) {
+		return "", gopi.ErrPermissionDenied.WithPrefix(name, ": Reader locked")
+	}
+
+	// Find the holder of the credential
+	var holder *credential
+	if value != "" {
+		for _, credential := range this.holders {
+			if credential.Matches(value) {
+				holder = credential
+			}
+		}
+	}
+	if holder == nil {
+		if r.failures++; r.failures >= maxFailures {
+			r.failures, r.locked = 0, now.Add(lockoutTime)
+		}
+		return "", gopi.ErrPermissionDenied.WithPrefix(name, ": Unknown credential")
+	}
+	r.failures = 0
+
+	// Check schedule and passback
+	if err := holder.Allowed(now); err != nil {
+		return holder.name, gopi.ErrPermissionDenied.WithPrefix(name, ": ", err)
+	}
+	exit := this.exit[name]
+	if *this.passback {
+		if inside, exists := this.inside[holder.name]; exists && inside == (exit == false) {
+			return holder.name, gopi.ErrPermissionDenied.WithPrefix(name, ": Passback")
+		}
+	}
+
+	// Return success
+	return holder.name, nil
+}
+
+// unlock releases the door strike, and locks it again after the
+// release time, extending the time when it is already released
+func (this *entry) unlock(ctx context.Context) error {
+	if err := this.Relay.Set(ctx, *this.relay, true); err != nil {
+		return err
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.strike != nil {
+		this.strike.Stop()
+	}
+	this.strike = time.AfterFunc(*this.release, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), *this.release)
+		defer cancel()
+		if err := this.Relay.Set(ctx, *this.relay, false); err != nil {
+			this.Print("Entry: ", err)
+		}
+	})
+	return nil
+}
+
+// reload reads the credentials file when it has changed, keeping the
+// current credentials when it cannot be read
+func (this *entry) reload() error {
+	info, err := os.Stat(*this.path)
+	if err != nil {
+		return err
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if info.ModTime().Equal(this.modified) {
+		return nil
+	} else if holders, err := readCredentials(*this.path); err != nil {
+		return err
+	} else {
+		this.holders = holders
+		this.modified = info.ModTime()
+		this.Debug("Entry: Read ", len(holders), " credentials from ", *this.path)
+	}
+
+	// Return success
+	return nil
+}
+
+// changed logs and emits an event, and records it in the audit log
+func (this *entry) changed(ctx context.Context, t gopi.EntryEventType, name, reader string, err error) {
+	evt := NewEvent(t, name, reader, err)
+	this.Print("Entry: ", evt)
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(evt, false); err != nil {
+			this.Debug("Entry: ", err)
+		}
+	}
+	entry := gopi.AuditEntry{
+		Time:     time.Now(),
+		Identity: name,
+		Source:   "entry",
+		Action:   "Entry.Present",
+		New:      strings.ToLower(strings.TrimPrefix(t.String(), "ENTRY_EVENT_")),
+	}
+	if t == gopi.ENTRY_EVENT_RELEASE {
+		entry.Action = "Entry.Release"
+	} else {
+		entry.Old = reader
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	this.record(ctx, &entry)
+}
+
+// record appends an entry to the audit log, after any entries which
+// could not be recorded before. Entries are kept until they can be
+// recorded, so that entry works when the audit log is unavailable.
+// The lock is not held while recording, so that credentials can be
+// checked while the audit log is slow
+func (this *entry) record(ctx context.Context, entry *gopi.AuditEntry) {
+	if this.AuditLog == nil {
+		return
+	}
+
+	this.audit.Lock()
+	defer this.audit.Unlock()
+
+	// Take the pending entries
+	this.Mutex.Lock()
+	if entry != nil {
+		this.pending = append(this.pending, *entry)
+	}
+	pending := this.pending
+	this.pending = nil
+	this.Mutex.Unlock()
+
+	// Record entries in order until one fails
+	i := 0
+	for ; i < len(pending); i++ {
+		if err := this.AuditLog.Record(ctx, pending[i]); err != nil {
+			this.Debug("Entry: ", err)
+			break
+		}
+	}
+
+	// Keep entries which were not recorded, before those which were
+	// added while recording
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.pending = append(append([]gopi.AuditEntry{}, pending[i:]...), this.pending...)
+	if len(this.pending) > maxPending {
+		this.pending = this.pending[len(this.pending)-maxPending:]
+	}
+}
@@ -0,0 +1,235 @@
+package entry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/entry"
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/relay"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.EntryControl
+	gopi.GPIO
+	gopi.AuditLog
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// audit records entries, or returns an error when unavailable
+type audit struct {
+	gopi.Unit
+	sync.Mutex
+	entries     []gopi.AuditEntry
+	unavailable bool
+}
+
+// key is a key pressed on a keypad
+type key struct {
+	gopi.Event
+	code gopi.KeyCode
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&testutil.GPIO{}), reflect.TypeOf((*gopi.GPIO)(nil)))
+	graph.RegisterUnit(reflect.TypeOf(&audit{}), reflect.TypeOf((*gopi.AuditLog)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// AUDIT LOG
+
+func (this *audit) Record(_ context.Context, entry gopi.AuditEntry) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.unavailable {
+		return gopi.ErrUnexpectedResponse
+	}
+	this.entries = append(this.entries, entry)
+	return nil
+}
+
+func (this *audit) Query(gopi.AuditQuery) ([]gopi.AuditEntry, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return append([]gopi.AuditEntry{}, this.entries...), nil
+}
+
+func (this *audit) SetUnavailable(value bool) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.unavailable = value
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// KEY
+
+func (this *key) Name() string                           { return "key" }
+func (this *key) Key() gopi.KeyCode                      { return this.code }
+func (this *key) Type() gopi.InputType                   { return gopi.INPUT_EVENT_KEYPRESS }
+func (this *key) Device() (gopi.InputDeviceType, uint32) { return gopi.INPUT_DEVICE_KEYBOARD, 1 }
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+const (
+	channels    = `{ "strike": { "gpio": 5 } }`
+	credentials = `{
+		"alice": { "cards": [ "04A1B2C3" ] },
+		"bob": { "pins": [ "1234" ] }
+	}`
+)
+
+func Test_Entry_001(t *testing.T) {
+	args := []string{
+		"-relay.channels", testutil.TempFile(t, "channels.json", channels),
+		"-entry.credentials", testutil.TempFile(t, "credentials.json", credentials), "-entry.relay", "strike", "-entry.release", "100ms",
+	}
+	tool.Test(t, args, new(App), func(app *App) {
+		ctx := context.Background()
+		pins := app.GPIO.(*testutil.GPIO)
+
+		// A known card releases the strike, which is locked again after
+		// the release time, and an unknown card is denied
+		scenario := tool.NewScenario().
+			At(0, "present card", func() error {
+				return app.EntryControl.Present(ctx, "front", "04 a1 b2 c3")
+			}).
+			ExpectCall(50*time.Millisecond, "GPIO.WritePin", gopi.GPIOPin(5), gopi.GPIO_HIGH).
+			ExpectEvent(500*time.Millisecond, "entry granted to alice", func(evt gopi.Event) bool {
+				return testutil.IsType(gopi.ENTRY_EVENT_GRANTED)(evt) && evt.Name() == "alice" && evt.(gopi.EntryEvent).Reader() == "front"
+			}).
+			ExpectCall(500*time.Millisecond, "GPIO.WritePin", gopi.GPIOPin(5), gopi.GPIO_LOW).
+			At(300*time.Millisecond, "present unknown card", func() error {
+				if err := app.EntryControl.Present(ctx, "front", "ffff"); errors.Is(err, gopi.ErrPermissionDenied) == false {
+					return fmt.Errorf("Expected entry to be denied, got %v", err)
+				}
+				return nil
+			}).
+			ExpectEvent(800*time.Millisecond, "entry denied", func(evt gopi.Event) bool {
+				return testutil.IsType(gopi.ENTRY_EVENT_DENIED)(evt) && evt.(gopi.EntryEvent).Error() != nil
+			})
+		pins.SetScenario(scenario)
+		scenario.Test(t, app.Publisher)
+		if pulses := pins.Pulses(5); pulses != 1 {
+			t.Error("Unexpected strike releases", pulses)
+		}
+
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// A pin typed on a keypad releases the strike
+		for _, code := range []gopi.KeyCode{gopi.KEYCODE_KP1, gopi.KEYCODE_KP2, gopi.KEYCODE_KP3, gopi.KEYCODE_KP4, gopi.KEYCODE_KPENTER} {
+			app.Publisher.Emit(&key{code: code}, false)
+		}
+		if evt, _ := testutil.NextType(ch, gopi.ENTRY_EVENT_GRANTED).(gopi.EntryEvent); evt == nil || evt.Name() != "bob" || evt.Reader() != "keypad" {
+			t.Error("Unexpected event", evt)
+		}
+
+		// Entries are recorded in the audit log
+		entries, _ := app.AuditLog.Query(gopi.AuditQuery{})
+		if len(entries) != 3 {
+			t.Fatal("Unexpected entries", entries)
+		} else if entries[0].Identity != "alice" || entries[0].Old != "front" || entries[0].New != "granted" {
+			t.Error("Unexpected entry", entries[0])
+		} else if entries[1].New != "denied" || entries[1].Error == "" {
+			t.Error("Unexpected entry", entries[1])
+		}
+		t.Log(app.EntryControl)
+	})
+}
+
+func Test_Entry_002(t *testing.T) {
+	args := []string{
+		"-relay.channels", testutil.TempFile(t, "channels.json", channels),
+		"-entry.credentials", testutil.TempFile(t, "credentials.json", credentials), "-entry.relay", "strike",
+		"-entry.exits", "inside", "-entry.passback", "-entry.keypad", "",
+	}
+	tool.Test(t, args, new(App), func(app *App) {
+		ctx := context.Background()
+		log := app.AuditLog.(*audit)
+
+		// Entering twice is denied until the holder has exited
+		if err := app.EntryControl.Present(ctx, "front", "1234"); err != nil {
+			t.Error(err)
+		} else if err := app.EntryControl.Present(ctx, "front", "1234"); err == nil {
+			t.Error("Expected passback to be denied")
+		} else if err := app.EntryControl.Present(ctx, "inside", "1234"); err != nil {
+			t.Error(err)
+		} else if err := app.EntryControl.Present(ctx, "inside", "1234"); err == nil {
+			t.Error("Expected passback to be denied")
+		}
+
+		// Entries are kept when the audit log is unavailable, and recorded
+		// in order when it is available again
+		log.SetUnavailable(true)
+		if err := app.EntryControl.Release(ctx); err != nil {
+			t.Error(err)
+		}
+		log.SetUnavailable(false)
+		if err := app.EntryControl.Present(ctx, "front", "1234"); err != nil {
+			t.Error(err)
+		}
+		if entries, _ := log.Query(gopi.AuditQuery{}); len(entries) != 6 {
+			t.Error("Unexpected entries", entries)
+		} else if entries[4].Action != "Entry.Release" || entries[5].Action != "Entry.Present" {
+			t.Error("Unexpected entries", entries[4:])
+		}
+
+		// A reader is locked after too many unknown credentials
+		for i := 0; i < 5; i++ {
+			app.EntryControl.Present(ctx, "back", "0000")
+		}
+		if err := app.EntryControl.Present(ctx, "back", "04A1B2C3"); err == nil {
+			t.Error("Expected reader to be locked")
+		}
+	})
+}
+
+func Test_Entry_003(t *testing.T) {
+	args := []string{
+		"-relay.channels", testutil.TempFile(t, "channels.json", channels),
+		"-entry.credentials", testutil.TempFile(t, "credentials.json", credentials), "-entry.relay", "strike",
+		"-entry.passback", "-entry.keypad", "",
+	}
+	tool.Test(t, args, new(App), func(app *App) {
+		ctx := context.Background()
+		pins := app.GPIO.(*testutil.GPIO)
+
+		// When the strike cannot be released, the holder is not inside
+		// and can present their credential again
+		pins.SetFailing(true)
+		if err := app.EntryControl.Present(ctx, "front", "1234"); err == nil {
+			t.Error("Expected error when the relay fails")
+		}
+		pins.SetFailing(false)
+		if err := app.EntryControl.Present(ctx, "front", "1234"); err != nil {
+			t.Error(err)
+		} else if pins.ReadPin(5) != gopi.GPIO_HIGH {
+			t.Error("Expected strike to be released")
+		} else if err := app.EntryControl.Present(ctx, "front", "1234"); err == nil {
+			t.Error("Expected passback to be denied")
+		}
+	})
+}
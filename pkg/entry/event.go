@@ -0,0 +1,60 @@
+package entry
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t      gopi.EntryEventType
+	name   string
+	reader string
+	err    error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(t gopi.EntryEventType, name, reader string, err error) gopi.EntryEvent {
+	return &event{t, name, reader, err}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.name
+}
+
+func (this *event) Type() gopi.EntryEventType {
+	return this.t
+}
+
+func (this *event) Reader() string {
+	return this.reader
+}
+
+func (this *event) Error() error {
+	return this.err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := fmt.Sprintf("<entry.event type=%v", this.t)
+	if this.name != "" {
+		str += fmt.Sprintf(" name=%q", this.name)
+	}
+	if this.reader != "" {
+		str += fmt.Sprintf(" reader=%q", this.reader)
+	}
+	if this.err != nil {
+		str += fmt.Sprintf(" err=%q", this.err.Error())
+	}
+	return str + ">"
+}
@@ -0,0 +1,13 @@
+package entry
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.EntryControl
+	graph.RegisterUnit(reflect.TypeOf(&entry{}), reflect.TypeOf((*gopi.EntryControl)(nil)))
+}
@@ -0,0 +1,92 @@
+package entry
+
+import (
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// keypad collects the keys typed on each input device, until enter is
+// pressed, so that keypads and card readers which type the card number
+// are read at the same time
+type keypad struct {
+	buffers map[keypadDevice]*buffer
+}
+
+type keypadDevice struct {
+	t  gopi.InputDeviceType
+	id uint32
+}
+
+type buffer struct {
+	strings.Builder
+	last time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Keys typed before the timeout are discarded
+	keypadTimeout = 10 * time.Second
+
+	// Maximum number of keys before enter
+	keypadMaxKeys = 32
+)
+
+var (
+	keypadChars = map[gopi.KeyCode]byte{
+		gopi.KEYCODE_0: '0', gopi.KEYCODE_1: '1', gopi.KEYCODE_2: '2', gopi.KEYCODE_3: '3', gopi.KEYCODE_4: '4',
+		gopi.KEYCODE_5: '5', gopi.KEYCODE_6: '6', gopi.KEYCODE_7: '7', gopi.KEYCODE_8: '8', gopi.KEYCODE_9: '9',
+		gopi.KEYCODE_KP0: '0', gopi.KEYCODE_KP1: '1', gopi.KEYCODE_KP2: '2', gopi.KEYCODE_KP3: '3', gopi.KEYCODE_KP4: '4',
+		gopi.KEYCODE_KP5: '5', gopi.KEYCODE_KP6: '6', gopi.KEYCODE_KP7: '7', gopi.KEYCODE_KP8: '8', gopi.KEYCODE_KP9: '9',
+		gopi.KEYCODE_A: 'A', gopi.KEYCODE_B: 'B', gopi.KEYCODE_C: 'C', gopi.KEYCODE_D: 'D', gopi.KEYCODE_E: 'E', gopi.KEYCODE_F: 'F',
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newKeypad() *keypad {
+	return &keypad{buffers: make(map[keypadDevice]*buffer)}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// Key adds a key pressed on a device, and returns the keys typed when
+// enter is pressed, or an empty string. Hexadecimal digits are kept,
+// escape and backspace discard the keys typed, and other keys are ignored
+func (this *keypad) Key(evt gopi.InputEvent, now time.Time) string {
+	if evt.Type() != gopi.INPUT_EVENT_KEYPRESS {
+		return ""
+	}
+	t, id := evt.Device()
+	device := keypadDevice{t, id}
+	keys, exists := this.buffers[device]
+	if exists == false {
+		keys = new(buffer)
+		this.buffers[device] = keys
+	} else if now.Sub(keys.last) > keypadTimeout {
+		keys.Reset()
+	}
+	keys.last = now
+
+	switch key := evt.Key(); key {
+	case gopi.KEYCODE_ENTER, gopi.KEYCODE_KPENTER:
+		value := keys.String()
+		keys.Reset()
+		return value
+	case gopi.KEYCODE_ESC, gopi.KEYCODE_BACKSPACE:
+		keys.Reset()
+	default:
+		if ch, exists := keypadChars[key]; exists && keys.Len() < keypadMaxKeys {
+			keys.WriteByte(ch)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+package attachment
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"os"
+	"path/filepath"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type attachment struct {
+	sync.Mutex
+
+	name string
+	kind string
+	path string
+	fn   func() ([]byte, error)
+	data []byte
+	err  error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// New returns an attachment for data in memory
+func New(name, mimetype string, data []byte) gopi.EventAttachment {
+	return &attachment{name: name, kind: mimetype, data: data}
+}
+
+// NewFile returns an attachment for a file, which is read each time the
+// attachment is opened. When the MIME type is empty, it is determined
+// from the file extension
+func NewFile(path, mimetype string) gopi.EventAttachment {
+	if mimetype == "" {
+		mimetype = mime.TypeByExtension(filepath.Ext(path))
+	}
+	return &attachment{name: filepath.Base(path), kind: mimetype, path: path}
+}
+
+// NewFunc returns an attachment which calls a function to load the data
+// the first time it is opened, for example to encode a frame as JPEG
+// only when it is needed
+func NewFunc(name, mimetype string, fn func() ([]byte, error)) gopi.EventAttachment {
+	return &attachment{name: name, kind: mimetype, fn: fn}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *attachment) Name() string {
+	return this.name
+}
+
+func (this *attachment) Type() string {
+	if this.kind == "" {
+		return "application/octet-stream"
+	}
+	return this.kind
+}
+
+func (this *attachment) Size() int64 {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	switch {
+	case this.path != "":
+		if stat, err := os.Stat(this.path); err == nil {
+			return stat.Size()
+		}
+		return -1
+	case this.fn != nil:
+		return -1
+	default:
+		return int64(len(this.data))
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *attachment) Open() (io.ReadCloser, error) {
+	if this.path != "" {
+		return os.Open(this.path)
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Load data once
+	if this.fn != nil {
+		this.data, this.err = this.fn()
+		this.fn = nil
+	}
+	if this.err != nil {
+		return nil, this.err
+	}
+	return ioutil.NopCloser(bytes.NewReader(this.data)), nil
+}
+
+// Read returns the data for an attachment, or ErrBadParameter when it
+// is larger than the limit in bytes. A limit of zero is no limit
+func Read(a gopi.EventAttachment, limit int64) ([]byte, error) {
+	if a == nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("Read")
+	} else if size := a.Size(); limit > 0 && size > limit {
+		return nil, gopi.ErrBadParameter.WithPrefix(a.Name(), ": Size ", size, " exceeds ", limit)
+	}
+	r, err := a.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	if limit <= 0 {
+		return ioutil.ReadAll(r)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	} else if int64(len(data)) > limit {
+		return nil, gopi.ErrBadParameter.WithPrefix(a.Name(), ": Size exceeds ", limit)
+	}
+	return data, nil
+}
+
+// Attachments returns the attachments for an event, or nil
+func Attachments(evt gopi.Event) []gopi.EventAttachment {
+	if evt, ok := evt.(gopi.EventWithAttachments); ok {
+		return evt.Attachments()
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *attachment) String() string {
+	str := "<attachment"
+	str += fmt.Sprintf(" name=%q", this.name)
+	str += fmt.Sprintf(" type=%q", this.Type())
+	if size := this.Size(); size >= 0 {
+		str += fmt.Sprint(" size=", size)
+	}
+	return str + ">"
+}
@@ -0,0 +1,76 @@
+package attachment_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	attachment "github.com/djthorpe/gopi/v3/pkg/event/attachment"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Attachment_001(t *testing.T) {
+	a := attachment.New("clip.wav", "", []byte("RIFF"))
+	if a.Name() != "clip.wav" || a.Type() != "application/octet-stream" || a.Size() != 4 {
+		t.Error("Unexpected attachment", a)
+	} else if data, err := attachment.Read(a, 4); err != nil || string(data) != "RIFF" {
+		t.Error("Unexpected data", data, err)
+	} else if _, err := attachment.Read(a, 3); errors.Is(err, gopi.ErrBadParameter) == false {
+		t.Error("Expected error for size", err)
+	}
+}
+
+func Test_Attachment_002(t *testing.T) {
+	// Data is loaded once, when first opened
+	calls := 0
+	a := attachment.NewFunc("snapshot.jpg", "image/jpeg", func() ([]byte, error) {
+		calls++
+		return []byte{0xFF, 0xD8}, nil
+	})
+	if calls != 0 || a.Size() != -1 {
+		t.Error("Unexpected load", a)
+	}
+	for i := 0; i < 2; i++ {
+		if data, err := attachment.Read(a, 0); err != nil || len(data) != 2 {
+			t.Error("Unexpected data", data, err)
+		}
+	}
+	if calls != 1 || a.Size() != 2 {
+		t.Error("Expected one load", calls, a)
+	}
+}
+
+func Test_Attachment_003(t *testing.T) {
+	dir, err := ioutil.TempDir("", "attachment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "trace.json")
+	if err := ioutil.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a := attachment.NewFile(path, "")
+	if a.Name() != "trace.json" || a.Type() != "application/json" || a.Size() != 2 {
+		t.Error("Unexpected attachment", a)
+	} else if data, err := attachment.Read(a, 0); err != nil || string(data) != "{}" {
+		t.Error("Unexpected data", data, err)
+	} else if attachments := attachment.Attachments(&event{[]gopi.EventAttachment{a}}); len(attachments) != 1 {
+		t.Error("Unexpected attachments", attachments)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// EVENT
+
+type event struct {
+	attachments []gopi.EventAttachment
+}
+
+func (*event) Name() string                             { return "event" }
+func (this *event) Attachments() []gopi.EventAttachment { return this.attachments }
@@ -0,0 +1,14 @@
+// Attachment package implements gopi.EventAttachment for data in memory,
+// files and data which is loaded the first time it is read, so that
+// events such as motion and scan events can carry their evidence by
+// reference:
+//
+//	snapshot := attachment.NewFunc("snapshot.jpg", "image/jpeg", func() ([]byte, error) {
+//	  buf := new(bytes.Buffer)
+//	  err := jpeg.Encode(buf, frame, nil)
+//	  return buf.Bytes(), err
+//	})
+//
+// Events carry attachments by implementing gopi.EventWithAttachments.
+// Read returns the data for an attachment up to a size limit.
+package attachment
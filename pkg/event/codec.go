@@ -0,0 +1,172 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/djthorpe/gopi/v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+type codec struct {
+	gopi.Unit
+	sync.RWMutex
+
+	funcs map[string]*codecfuncs
+}
+
+// codecfuncs are the functions registered for an event type
+type codecfuncs struct {
+	marshal   map[gopi.EventFormat]gopi.EventMarshalFunc
+	unmarshal map[gopi.EventFormat]gopi.EventUnmarshalFunc
+}
+
+/////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *codec) New(gopi.Config) error {
+	this.funcs = make(map[string]*codecfuncs)
+	return nil
+}
+
+func (this *codec) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.funcs = nil
+
+	// Return success
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *codec) Register(evt gopi.Event, format gopi.EventFormat, marshal gopi.EventMarshalFunc, unmarshal gopi.EventUnmarshalFunc) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if evt == nil {
+		return gopi.ErrBadParameter.WithPrefix("Register")
+	} else if format == gopi.EVENT_FORMAT_NONE {
+		return gopi.ErrBadParameter.WithPrefix("Register: ", format)
+	} else if marshal == nil && unmarshal == nil {
+		return gopi.ErrBadParameter.WithPrefix("Register")
+	}
+
+	// Get or create functions for the type
+	name := this.TypeName(evt)
+	funcs, exists := this.funcs[name]
+	if exists == false {
+		funcs = &codecfuncs{
+			make(map[gopi.EventFormat]gopi.EventMarshalFunc),
+			make(map[gopi.EventFormat]gopi.EventUnmarshalFunc),
+		}
+		this.funcs[name] = funcs
+	}
+
+	// Functions can only be registered once for each format
+	if marshal != nil {
+		if _, exists := funcs.marshal[format]; exists {
+			return gopi.ErrDuplicateEntry.WithPrefix("Register: ", name, " ", format)
+		}
+		funcs.marshal[format] = marshal
+	}
+	if unmarshal != nil {
+		if _, exists := funcs.unmarshal[format]; exists {
+			return gopi.ErrDuplicateEntry.WithPrefix("Register: ", name, " ", format)
+		}
+		funcs.unmarshal[format] = unmarshal
+	}
+
+	// Return success
+	return nil
+}
+
+// Marshal an event. When no function is registered for the type of
+// event, events which implement json.Marshaler or a gopi event
+// interface are encoded as JSON or MessagePack. Protobuf requires a
+// registered function, since it needs a generated message type
+func (this *codec) Marshal(evt gopi.Event, format gopi.EventFormat) (string, []byte, error) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if evt == nil {
+		return "", nil, gopi.ErrBadParameter.WithPrefix("Marshal")
+	}
+
+	name := this.TypeName(evt)
+	if funcs, exists := this.funcs[name]; exists && funcs.marshal[format] != nil {
+		data, err := funcs.marshal[format](evt)
+		return name, data, err
+	}
+
+	// Encode with json.Marshaler or the fields of the event interface
+	var v interface{}
+	if _, ok := evt.(json.Marshaler); ok {
+		v = evt
+	} else if fields := eventFields(evt); fields != nil {
+		v = fields
+	} else {
+		return "", nil, gopi.ErrNotImplemented.WithPrefix("Marshal: ", name)
+	}
+	switch format {
+	case gopi.EVENT_FORMAT_JSON:
+		data, err := json.Marshal(v)
+		return name, data, err
+	case gopi.EVENT_FORMAT_MSGPACK:
+		data, err := marshalMsgPack(v)
+		return name, data, err
+	default:
+		return "", nil, gopi.ErrNotImplemented.WithPrefix("Marshal: ", name, " ", format)
+	}
+}
+
+func (this *codec) Unmarshal(name string, format gopi.EventFormat, data []byte) (gopi.Event, error) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if funcs, exists := this.funcs[name]; exists && funcs.unmarshal[format] != nil {
+		return funcs.unmarshal[format](data)
+	} else {
+		return nil, gopi.ErrNotImplemented.WithPrefix("Unmarshal: ", name, " ", format)
+	}
+}
+
+// TypeName returns the type name of an event, which includes the
+// package name, for example "*event.nullevent"
+func (this *codec) TypeName(evt gopi.Event) string {
+	if evt == nil {
+		return ""
+	} else {
+		return reflect.TypeOf(evt).String()
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *codec) String() string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	str := "<codec"
+	for name, funcs := range this.funcs {
+		formats := []gopi.EventFormat{}
+		for format := range funcs.marshal {
+			formats = append(formats, format)
+		}
+		for format := range funcs.unmarshal {
+			if _, exists := funcs.marshal[format]; exists == false {
+				formats = append(formats, format)
+			}
+		}
+		str += fmt.Sprintf(" %v=%v", name, formats)
+	}
+	return str + ">"
+}
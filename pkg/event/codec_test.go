@@ -0,0 +1,87 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/djthorpe/gopi/v3"
+	"github.com/djthorpe/gopi/v3/pkg/door"
+	"github.com/djthorpe/gopi/v3/pkg/hw/relay"
+	"github.com/djthorpe/gopi/v3/pkg/tool"
+)
+
+type CodecApp struct {
+	gopi.Unit
+	gopi.EventCodec
+}
+
+type codecevent struct {
+	Value string `json:"value"`
+}
+
+func (*codecevent) Name() string {
+	return "codec"
+}
+
+func Test_Codec_001(t *testing.T) {
+	tool.Test(t, nil, new(CodecApp), func(app *CodecApp) {
+		if app.EventCodec == nil {
+			t.Fatal("Unexpected nil value for codec")
+		}
+		// Events which do not implement an event interface are not
+		// encoded without registration
+		if _, _, err := app.EventCodec.Marshal(&codecevent{"a"}, gopi.EVENT_FORMAT_JSON); errors.Is(err, gopi.ErrNotImplemented) == false {
+			t.Error("Expected ErrNotImplemented, got", err)
+		}
+		// Events which implement an event interface are encoded as JSON,
+		// but cannot be decoded
+		if name, data, err := app.EventCodec.Marshal(door.NewEvent(gopi.DOOR_EVENT_ALARM, gopi.DOOR_STATE_OPEN), gopi.EVENT_FORMAT_JSON); err != nil {
+			t.Error(err)
+		} else if name != "*door.event" {
+			t.Error("Unexpected name", name)
+		} else if string(data) != `{"name":"door","state":"DOOR_STATE_OPEN","type":"DOOR_EVENT_ALARM"}` {
+			t.Error("Unexpected data", string(data))
+		} else if _, err := app.EventCodec.Unmarshal(name, gopi.EVENT_FORMAT_JSON, data); err == nil {
+			t.Error("Expected error for unregistered type")
+		}
+		// and as MessagePack
+		if _, data, err := app.EventCodec.Marshal(relay.NewEvent("pump", true), gopi.EVENT_FORMAT_MSGPACK); err != nil {
+			t.Error(err)
+		} else if expected := "\x83\xa7channel\xa4pump\xa4name\xa4pump\xa5state\xc3"; string(data) != expected {
+			t.Errorf("Unexpected data %q", data)
+		}
+		// Protobuf requires registration
+		if _, _, err := app.EventCodec.Marshal(relay.NewEvent("pump", true), gopi.EVENT_FORMAT_PROTOBUF); err == nil {
+			t.Error("Expected error for unregistered format")
+		}
+	})
+}
+
+func Test_Codec_002(t *testing.T) {
+	tool.Test(t, nil, new(CodecApp), func(app *CodecApp) {
+		marshal := func(evt gopi.Event) ([]byte, error) {
+			return json.Marshal(evt)
+		}
+		unmarshal := func(data []byte) (gopi.Event, error) {
+			evt := new(codecevent)
+			if err := json.Unmarshal(data, evt); err != nil {
+				return nil, err
+			}
+			return evt, nil
+		}
+		if err := app.EventCodec.Register(&codecevent{}, gopi.EVENT_FORMAT_JSON, marshal, unmarshal); err != nil {
+			t.Fatal(err)
+		} else if err := app.EventCodec.Register(&codecevent{}, gopi.EVENT_FORMAT_JSON, nil, unmarshal); err == nil {
+			t.Error("Expected error for duplicate registration")
+		}
+		if name, data, err := app.EventCodec.Marshal(&codecevent{"b"}, gopi.EVENT_FORMAT_JSON); err != nil {
+			t.Error(err)
+		} else if evt, err := app.EventCodec.Unmarshal(name, gopi.EVENT_FORMAT_JSON, data); err != nil {
+			t.Error(err)
+		} else if evt.(*codecevent).Value != "b" {
+			t.Error("Unexpected event", evt)
+		}
+		t.Log(app.EventCodec)
+	})
+}
@@ -0,0 +1,229 @@
+package event
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/djthorpe/gopi/v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// fields are the properties of an event, which are encoded when no
+// function is registered for the type of event
+type fields map[string]interface{}
+
+/////////////////////////////////////////////////////////////////////
+// METHODS
+
+// eventFields returns the properties of an event from the gopi event
+// interface it implements, or nil when the event does not implement
+// a known interface. Enumerations are returned as strings and times
+// are omitted when zero
+func eventFields(evt gopi.Event) fields {
+	f := fields{"name": evt.Name()}
+	switch evt := evt.(type) {
+	case gopi.Measurement:
+		for _, field := range append(evt.Tags(), evt.Metrics()...) {
+			f[field.Name()] = field.Value()
+		}
+		f.time("time", evt.Time())
+	case gopi.GPIOEvent:
+		f["pin"] = fmt.Sprint(evt.Pin())
+		f["edge"] = fmt.Sprint(evt.Edge())
+	case gopi.RelayEvent:
+		f["channel"] = evt.Channel()
+		f["state"] = evt.State()
+	case gopi.FSEvent:
+		f["path"] = evt.Path()
+		f.str("old_path", evt.OldPath())
+		f["flags"] = fmt.Sprint(evt.Flags())
+	case gopi.DoorEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		f["state"] = fmt.Sprint(evt.State())
+	case gopi.EntryEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		f.str("reader", evt.Reader())
+		f.err(evt.Error())
+	case gopi.DoorbellEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+	case gopi.IrrigationEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		f.time("until", evt.Until())
+	case gopi.SoilEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		f["probe"] = evt.Probe()
+	case gopi.FeedEvent:
+		f.time("updated", evt.Updated())
+		f.err(evt.Error())
+	case gopi.TimerEvent:
+		f["id"] = uint(evt.Id())
+		f["ticks"] = evt.Ticks()
+		f["missed"] = evt.Missed()
+		f.time("time", evt.Time())
+	case gopi.TimeSyncEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		f["status"] = evt.Status()
+		f.duration("step", evt.Step())
+	case gopi.TwinEvent:
+		f["version"] = evt.Version()
+		f["changes"] = evt.Changes()
+	case gopi.AlertEvent:
+		f["trigger"] = evt.Trigger()
+		f["state"] = fmt.Sprint(evt.State())
+		f["severity"] = evt.Severity()
+		f.err(evt.Error())
+	case gopi.RuleEvent:
+		f["trigger"] = evt.Trigger()
+		f.err(evt.Error())
+	case gopi.SpeechEvent:
+		f["id"] = uint(evt.Id())
+		f["text"] = evt.Text()
+		f["state"] = fmt.Sprint(evt.State())
+		f.err(evt.Error())
+	case gopi.TranscriptEvent:
+		f.str("keyword", evt.Keyword())
+		f["text"] = evt.Text()
+		f.duration("duration", evt.Duration())
+		f.err(evt.Error())
+	case gopi.UPSEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		f["status"] = evt.Status()
+	case gopi.LightEvent:
+		f["lux"] = evt.Lux()
+	case gopi.ThemeEvent:
+		f["dark"] = evt.Dark()
+	case gopi.AirQualityEvent:
+		f["sensor"] = evt.Sensor()
+		f["reading"] = evt.Reading()
+	case gopi.IdleEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		f["state"] = fmt.Sprint(evt.State())
+		f.duration("idle", evt.Idle())
+	case gopi.WiFiEvent:
+		f["interface"] = evt.Interface()
+		f["state"] = fmt.Sprint(evt.State())
+		if network := evt.Network(); network != nil {
+			f["ssid"] = network.SSID()
+			f.str("bssid", network.BSSID())
+		}
+	case gopi.PresenceEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		if person := evt.Person(); person != nil {
+			f["person"] = person.Name()
+			f["state"] = fmt.Sprint(person.State())
+		}
+	case gopi.PairingEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		if session := evt.Session(); session != nil {
+			f["session"] = session.Id()
+			f["client"] = session.Name()
+		}
+	case gopi.ProcessEvent:
+		f["state"] = fmt.Sprint(evt.State())
+		if process := evt.Process(); process != nil {
+			f["pid"] = process.Pid()
+			f["restarts"] = process.Restarts()
+			f["exit_code"] = process.ExitCode()
+		}
+	case gopi.MediaRecorderEvent:
+		f.time("start", evt.Start())
+		f.duration("duration", evt.Duration())
+		f["size"] = evt.Size()
+	case gopi.CloudEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		f.str("method", evt.Method())
+		if desired := evt.Desired(); len(desired) > 0 {
+			f["desired"] = desired
+		}
+		f.err(evt.Error())
+	case gopi.ZigbeeEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		if device := evt.Device(); device != nil {
+			f["device"] = device.Name()
+			f["address"] = device.Address()
+		}
+		if state := evt.State(); len(state) > 0 {
+			f["state"] = state
+		}
+	case gopi.ESPEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		if device := evt.Device(); device != nil {
+			f["device"] = device.Id()
+		}
+		if entity := evt.Entity(); entity != nil {
+			f["entity"] = entity.Id()
+			f["state"] = entity.State()
+			f["value"] = entity.Value()
+			f.str("unit", entity.Unit())
+		}
+	case gopi.DeviceEvent:
+		f["type"] = fmt.Sprint(evt.Type())
+		if device := evt.Device(); device != nil {
+			f["key"] = device.Key()
+			f["device_type"] = device.Type()
+		}
+	case gopi.GPSEvent:
+		f["position"] = evt.Position()
+	case gopi.RFEvent:
+		f["reading"] = evt.Reading()
+	case gopi.SenseHATEvent:
+		f["sensors"] = evt.Sensors()
+	case gopi.InputEvent:
+		f["key"] = fmt.Sprint(evt.Key())
+		f["type"] = fmt.Sprint(evt.Type())
+	case gopi.InputActionEvent:
+		f["context"] = evt.Context()
+		if source := evt.Source(); source != nil {
+			f["source"] = source.Name()
+		}
+	case gopi.AirPlayEvent:
+		f["state"] = fmt.Sprint(evt.State())
+		f.str("sender", evt.Sender())
+		f["volume"] = evt.Volume()
+	case gopi.CastReceiverEvent:
+		f.str("app", evt.App())
+		f["state"] = fmt.Sprint(evt.State())
+		f.str("url", evt.URL())
+	case gopi.DisplayEvent:
+		f["flags"] = fmt.Sprint(evt.Flags())
+		if display := evt.Display(); display != nil {
+			f["display"] = display.Id()
+		}
+	case gopi.PrinterEvent:
+		f["status"] = fmt.Sprint(evt.Status())
+	case gopi.RotelEvent:
+		f["flags"] = fmt.Sprint(evt.Flags())
+	default:
+		return nil
+	}
+	return f
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (f fields) str(key, value string) {
+	if value != "" {
+		f[key] = value
+	}
+}
+
+func (f fields) time(key string, value time.Time) {
+	if value.IsZero() == false {
+		f[key] = value
+	}
+}
+
+func (f fields) duration(key string, value time.Duration) {
+	if value != 0 {
+		f[key] = value.Seconds()
+	}
+}
+
+func (f fields) err(err error) {
+	if err != nil {
+		f["error"] = err.Error()
+	}
+}
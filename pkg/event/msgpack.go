@@ -0,0 +1,122 @@
+package event
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"sort"
+
+	"github.com/djthorpe/gopi/v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// METHODS
+
+// marshalMsgPack encodes a value as MessagePack. The value is first
+// encoded as JSON, so that values are encoded the same way in both
+// formats, with map keys in sorted order
+func marshalMsgPack(v interface{}) ([]byte, error) {
+	var value interface{}
+	if data, err := json.Marshal(v); err != nil {
+		return nil, err
+	} else if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	if err := writeMsgPack(buf, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// writeMsgPack writes a value decoded from JSON
+func writeMsgPack(buf *bytes.Buffer, value interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		buf.WriteByte(0xC0)
+	case bool:
+		if value {
+			buf.WriteByte(0xC3)
+		} else {
+			buf.WriteByte(0xC2)
+		}
+	case float64:
+		if value == math.Trunc(value) && value >= math.MinInt64 && value < math.MaxInt64 {
+			writeMsgPackInt(buf, int64(value))
+		} else {
+			buf.WriteByte(0xCB)
+			binary.Write(buf, binary.BigEndian, value)
+		}
+	case string:
+		writeMsgPackLen(buf, len(value), 0xA0, 0xD9, 0xDA, 0xDB, 32)
+		buf.WriteString(value)
+	case []interface{}:
+		writeMsgPackLen(buf, len(value), 0x90, 0, 0xDC, 0xDD, 16)
+		for _, elem := range value {
+			if err := writeMsgPack(buf, elem); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		writeMsgPackLen(buf, len(value), 0x80, 0, 0xDE, 0xDF, 16)
+		for _, key := range keys {
+			writeMsgPack(buf, key)
+			if err := writeMsgPack(buf, value[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return gopi.ErrBadParameter.WithPrefix("MessagePack: ", value)
+	}
+	return nil
+}
+
+// writeMsgPackInt writes an integer in the smallest encoding
+func writeMsgPackInt(buf *bytes.Buffer, value int64) {
+	switch {
+	case value >= 0 && value < 128:
+		buf.WriteByte(byte(value))
+	case value < 0 && value >= -32:
+		buf.WriteByte(byte(int8(value)))
+	case value >= math.MinInt8 && value <= math.MaxInt8:
+		buf.WriteByte(0xD0)
+		buf.WriteByte(byte(int8(value)))
+	case value >= math.MinInt16 && value <= math.MaxInt16:
+		buf.WriteByte(0xD1)
+		binary.Write(buf, binary.BigEndian, int16(value))
+	case value >= math.MinInt32 && value <= math.MaxInt32:
+		buf.WriteByte(0xD2)
+		binary.Write(buf, binary.BigEndian, int32(value))
+	default:
+		buf.WriteByte(0xD3)
+		binary.Write(buf, binary.BigEndian, value)
+	}
+}
+
+// writeMsgPackLen writes the length of a string, array or map, where
+// short lengths are in the fixed type, and an 8-bit length is only
+// available for strings
+func writeMsgPackLen(buf *bytes.Buffer, n int, fixed, b8, b16, b32 byte, fixmax int) {
+	switch {
+	case n < fixmax:
+		buf.WriteByte(fixed | byte(n))
+	case b8 != 0 && n <= math.MaxUint8:
+		buf.WriteByte(b8)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(b16)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(b32)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
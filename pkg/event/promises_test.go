@@ -44,12 +44,13 @@ func (this *PromiseApp) Delay(ctx context.Context, v interface{}) (interface{},
 	return v, nil
 }
 
-func (this *PromiseApp) Finally(v interface{}, err error) {
+func (this *PromiseApp) Finally(v interface{}, err error) error {
 	if err != nil {
 		fmt.Println("ERROR", err)
 	} else {
 		fmt.Println("SUCCESS", string(v.([]byte)))
 	}
+	return err
 }
 
 func Test_Promise_000(t *testing.T) {
@@ -4,16 +4,30 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/djthorpe/gopi/v3"
 )
 
 type publisher struct {
 	gopi.Unit
+	gopi.Logger
 	sync.RWMutex
 
-	q  chan gopi.Event
-	ch []chan gopi.Event
+	q       chan gopi.Event
+	sub     []*subscriber
+	dropped uint64
+}
+
+// subscriber has a bounded queue of events, which are delivered
+// to the subscriber channel in the background, so that a slow
+// subscriber does not block other subscribers or emitters
+type subscriber struct {
+	ch      chan gopi.Event
+	q       chan gopi.Event
+	done    chan struct{}
+	stalled bool
+	dropped uint64
 }
 
 const (
@@ -31,35 +45,34 @@ func (this *publisher) Dispose() error {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
 
-	// Close queue
-	close(this.q)
-
 	// Unsubscribe channels
-	for _, ch := range this.ch {
-		if ch != nil {
-			close(ch)
-		}
+	for _, sub := range this.sub {
+		close(sub.done)
 	}
 
 	// Dispose
-	this.q = nil
-	this.ch = nil
+	this.sub = nil
 
 	// Return success
 	return nil
 }
 
+// Run dispatches events to subscriber queues. The lock is not held
+// while dispatching, so that subscribing and unsubscribing never
+// wait for subscribers to receive events
 func (this *publisher) Run(ctx context.Context) error {
 	for {
 		select {
 		case evt := <-this.q:
 			this.RWMutex.RLock()
-			for _, ch := range this.ch {
-				if ch != nil {
-					ch <- evt
+			subs := make([]*subscriber, len(this.sub))
+			copy(subs, this.sub)
+			this.RWMutex.RUnlock()
+			for _, sub := range subs {
+				if this.deliver(sub, evt) == false {
+					atomic.AddUint64(&this.dropped, 1)
 				}
 			}
-			this.RWMutex.RUnlock()
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -70,19 +83,28 @@ func (this *publisher) Subscribe() <-chan gopi.Event {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
 
-	ch := make(chan gopi.Event)
-	this.ch = append(this.ch, ch)
-	return ch
+	sub := &subscriber{
+		ch:   make(chan gopi.Event),
+		q:    make(chan gopi.Event, queuesize),
+		done: make(chan struct{}),
+	}
+	this.sub = append(this.sub, sub)
+	go sub.run()
+	return sub.ch
 }
 
+// Unsubscribe removes a subscriber and returns immediately. The
+// channel is closed in the background, and any queued events are
+// discarded
 func (this *publisher) Unsubscribe(ch <-chan gopi.Event) {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
 
-	for i, other := range this.ch {
-		if other == ch {
-			close(other)
-			this.ch[i] = nil
+	for i, sub := range this.sub {
+		if sub.ch == ch {
+			close(sub.done)
+			this.sub = append(this.sub[:i], this.sub[i+1:]...)
+			return
 		}
 	}
 }
@@ -116,7 +138,64 @@ func (this *publisher) String() string {
 	if this == nil {
 		str += " nil"
 	} else {
-		str += " ch=" + fmt.Sprint(this.ch)
+		this.RWMutex.RLock()
+		defer this.RWMutex.RUnlock()
+		str += " subscribers=" + fmt.Sprint(len(this.sub))
+		if dropped := atomic.LoadUint64(&this.dropped); dropped > 0 {
+			str += " dropped=" + fmt.Sprint(dropped)
+		}
 	}
 	return str + ">"
 }
+
+///////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// deliver an event to a subscriber queue without blocking, and return
+// false if the event was dropped. When the queue is full the subscriber
+// is stalled, and events are dropped until there is space in the queue.
+// Stalling and recovery are logged, rather than each dropped event
+func (this *publisher) deliver(sub *subscriber, evt gopi.Event) bool {
+	select {
+	case sub.q <- evt:
+		if sub.stalled {
+			this.log("Publisher: Subscriber resumed after dropping ", sub.dropped, " events")
+			sub.stalled, sub.dropped = false, 0
+		}
+		return true
+	case <-sub.done:
+		return true
+	default:
+		if sub.stalled == false {
+			this.log("Publisher: Subscriber queue full, dropping ", evt.Name(), " events")
+			sub.stalled = true
+		}
+		sub.dropped++
+		return false
+	}
+}
+
+// log prints a message when there is a logger
+func (this *publisher) log(args ...interface{}) {
+	if this.Logger != nil {
+		this.Logger.Print(args...)
+	}
+}
+
+// run delivers queued events to the subscriber channel until
+// unsubscribed, then closes the channel
+func (this *subscriber) run() {
+	defer close(this.ch)
+	for {
+		select {
+		case evt := <-this.q:
+			select {
+			case this.ch <- evt:
+			case <-this.done:
+				return
+			}
+		case <-this.done:
+			return
+		}
+	}
+}
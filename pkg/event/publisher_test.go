@@ -1,8 +1,10 @@
 package event
 
 import (
+	"context"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -15,6 +17,12 @@ type App struct {
 	gopi.Publisher
 }
 
+// Run until the test ends, so that the publisher dispatches events
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
 func Test_Event_000(t *testing.T) {
 	pub := &publisher{}
 	if ch := pub.Subscribe(); ch == nil {
@@ -107,3 +115,83 @@ func Test_Event_002(t *testing.T) {
 		wg.Wait()
 	})
 }
+
+func Test_Event_003(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		// A subscriber which does not receive events
+		stalled := app.Subscribe()
+
+		// A subscriber which receives events
+		ch := app.Subscribe()
+
+		// Emit events, which are received even when the stalled
+		// subscriber queue is full
+		for i := 0; i < queuesize*3; i++ {
+			if err := app.Emit(nil, true); err != nil {
+				t.Error(err)
+			}
+			select {
+			case <-ch:
+				break
+			case <-time.After(time.Second):
+				t.Error("Timeout waiting for event", i)
+				return
+			}
+		}
+
+		// Unsubscribe does not block, and the channels are closed
+		unsubscribed := make(chan struct{})
+		go func() {
+			app.Unsubscribe(stalled)
+			app.Unsubscribe(ch)
+			close(unsubscribed)
+		}()
+		select {
+		case <-unsubscribed:
+			break
+		case <-time.After(time.Second):
+			t.Error("Timeout waiting for unsubscribe")
+		}
+		for range stalled {
+		}
+		for range ch {
+		}
+	})
+}
+
+func Test_Event_004(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		// A slow subscriber, which receives an event every 10ms
+		slow := app.Subscribe()
+		go func() {
+			for range slow {
+				time.Sleep(10 * time.Millisecond)
+			}
+		}()
+
+		// A subscriber which receives events without waiting for
+		// the slow subscriber
+		ch := app.Subscribe()
+		for i := 0; i < queuesize*3; i++ {
+			if err := app.Emit(nil, true); err != nil {
+				t.Error(err)
+			}
+			select {
+			case <-ch:
+				break
+			case <-time.After(100 * time.Millisecond):
+				t.Error("Timeout waiting for event", i)
+				return
+			}
+		}
+
+		// Events for the slow subscriber are dropped
+		if pub := app.Publisher.(*publisher); atomic.LoadUint64(&pub.dropped) == 0 {
+			t.Error("Expected dropped events")
+		} else {
+			t.Log(pub)
+		}
+		app.Unsubscribe(slow)
+		app.Unsubscribe(ch)
+	})
+}
@@ -0,0 +1,51 @@
+package feed
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ReadCache decodes a cache file into v, and returns false when path
+// is empty or the file does not exist
+func ReadCache(path string, v interface{}) (bool, error) {
+	if path == "" {
+		return false, nil
+	} else if _, err := os.Stat(path); os.IsNotExist(err) {
+		return false, nil
+	} else if fh, err := os.Open(path); err != nil {
+		return false, err
+	} else {
+		defer fh.Close()
+		if err := json.NewDecoder(fh).Decode(v); err != nil {
+			return false, err
+		}
+	}
+
+	// Success
+	return true, nil
+}
+
+// WriteCache encodes v to a cache file through a temporary file so the
+// file is never partially written. When path is empty, nothing is written
+func WriteCache(path string, v interface{}) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if fh, err := os.Create(tmp); err != nil {
+		return err
+	} else if err := json.NewEncoder(fh).Encode(v); err != nil {
+		fh.Close()
+		return err
+	} else if err := fh.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,304 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	feed "github.com/djthorpe/gopi/v3/pkg/feed"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type calendar struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	sync.RWMutex
+
+	interval *time.Duration
+	timeout  *time.Duration
+	cache    *string
+	sources  []string
+	client   *http.Client
+	events   map[string][]*vevent
+	updated  time.Time
+}
+
+// cached are the events for each source and when they were updated
+type cached struct {
+	Updated time.Time            `json:"updated"`
+	Events  map[string][]*vevent `json:"events"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	feedName = "calendar"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *calendar) Define(cfg gopi.Config) error {
+	cfg.FlagString("calendar.url", "", "Comma-separated list of iCal calendar URLs or files")
+	this.interval = cfg.FlagDuration("calendar.interval", 15*time.Minute, "Interval between fetching calendars")
+	this.timeout = cfg.FlagDuration("calendar.timeout", 15*time.Second, "Calendar request timeout")
+	this.cache = cfg.FlagPath("calendar.cache", "", "Path to file caching calendars when offline")
+	return nil
+}
+
+func (this *calendar) New(cfg gopi.Config) error {
+	this.Require(this.Logger)
+
+	if *this.interval <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-calendar.interval")
+	}
+
+	// Set sources
+	for _, source := range strings.Split(cfg.GetString("calendar.url"), ",") {
+		if source = strings.TrimSpace(source); source != "" {
+			this.sources = append(this.sources, source)
+		}
+	}
+	this.client = &http.Client{Timeout: *this.timeout}
+	this.events = make(map[string][]*vevent)
+
+	// Read cached events for sources
+	var c cached
+	if exists, err := feed.ReadCache(*this.cache, &c); err != nil {
+		this.Print("Calendar: ", err)
+	} else if exists {
+		for _, source := range this.sources {
+			if events, exists := c.Events[source]; exists {
+				this.events[source] = events
+			}
+		}
+		this.updated = c.Updated
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *calendar) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.events = nil
+	this.client = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *calendar) Run(ctx context.Context) error {
+	// Fetch immediately unless the cache is recent
+	delay := time.Duration(0)
+	this.RWMutex.RLock()
+	if since := time.Since(this.updated); since < *this.interval && len(this.events) == len(this.sources) {
+		delay = *this.interval - since
+	}
+	this.RWMutex.RUnlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			if err := this.Update(ctx); err != nil && ctx.Err() == nil {
+				this.Print("Calendar: ", err)
+			}
+			timer.Reset(*this.interval)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *calendar) Events(from, to time.Time) []gopi.CalendarEvent {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	// Index occurrences which are replaced
+	overrides := make(map[string]bool)
+	for _, events := range this.events {
+		for _, evt := range events {
+			if evt.RecurrenceId.IsZero() == false {
+				overrides[occurrence(evt.Uid, evt.RecurrenceId)] = true
+			}
+		}
+	}
+
+	// Return events and occurrences of recurring events in time range
+	result := []gopi.CalendarEvent{}
+	for _, events := range this.events {
+		for _, evt := range events {
+			if evt.Rule == nil || evt.RecurrenceId.IsZero() == false {
+				if overlaps(evt.Start, evt.End, from, to) {
+					result = append(result, evt.CalendarEvent)
+				}
+				continue
+			}
+			duration := evt.End.Sub(evt.Start)
+			for _, start := range evt.Rule.Occurrences(evt.Start, to) {
+				if overlaps(start, start.Add(duration), from, to) == false {
+					continue
+				} else if overrides[occurrence(evt.Uid, start)] || evt.excluded(start) {
+					continue
+				}
+				occurrence := evt.CalendarEvent
+				occurrence.Start, occurrence.End = start, start.Add(duration)
+				result = append(result, occurrence)
+			}
+		}
+	}
+
+	// Order by start time
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+func (this *calendar) Update(ctx context.Context) error {
+	var result error
+
+	// Fetch each calendar, keeping previous events on error
+	events := make(map[string][]*vevent, len(this.sources))
+	for _, source := range this.sources {
+		if evts, err := this.fetch(ctx, source); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%v: %w", source, err))
+		} else {
+			events[source] = evts
+		}
+	}
+
+	// Set events and write to the cache
+	this.RWMutex.Lock()
+	for source, evts := range events {
+		this.events[source] = evts
+	}
+	if len(events) > 0 {
+		this.updated = time.Now()
+	}
+	c := cached{this.updated, this.events}
+	err := feed.WriteCache(*this.cache, c)
+	this.RWMutex.Unlock()
+	if err != nil {
+		this.Print("Calendar: ", err)
+	}
+
+	// Emit event
+	if result != nil {
+		this.emit(feed.NewEvent(feedName, time.Time{}, result))
+	} else {
+		this.emit(feed.NewEvent(feedName, c.Updated, nil))
+	}
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *calendar) String() string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	str := "<feed.calendar"
+	for _, source := range this.sources {
+		str += fmt.Sprintf(" %q=%v", source, len(this.events[source]))
+	}
+	if this.updated.IsZero() == false {
+		str += " updated=" + this.updated.Format(time.RFC3339)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// fetch reads events from a URL or file
+func (this *calendar) fetch(ctx context.Context, source string) ([]*vevent, error) {
+	var r io.ReadCloser
+	if u, err := url.Parse(source); err == nil && (u.Scheme == "http" || u.Scheme == "https" || u.Scheme == "webcal") {
+		if u.Scheme == "webcal" {
+			u.Scheme = "https"
+		}
+		req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "text/calendar")
+		response, err := this.client.Do(req.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		} else if response.StatusCode != http.StatusOK {
+			response.Body.Close()
+			return nil, gopi.ErrUnexpectedResponse.WithPrefix(response.Status)
+		}
+		r = response.Body
+	} else if fh, err := os.Open(strings.TrimPrefix(source, "file://")); err != nil {
+		return nil, err
+	} else {
+		r = fh
+	}
+	defer r.Close()
+	return parseCalendar(r, source)
+}
+
+func (this *calendar) emit(evt gopi.Event) {
+	if this.Publisher == nil {
+		return
+	} else if err := this.Publisher.Emit(evt, false); err != nil {
+		this.Debug("Calendar: ", err)
+	}
+}
+
+// excluded returns true if an occurrence is an exception date
+func (this *vevent) excluded(start time.Time) bool {
+	for _, t := range this.Exdates {
+		if t.Equal(start) {
+			return true
+		}
+	}
+	return false
+}
+
+// occurrence returns a key for an occurrence of an event
+func occurrence(uid string, start time.Time) string {
+	return fmt.Sprint(uid, "@", start.Unix())
+}
+
+// overlaps returns true if an event overlaps a time range. Events
+// without duration overlap when they start in the range
+func overlaps(start, end, from, to time.Time) bool {
+	if start.Before(to) == false {
+		return false
+	} else if end.Equal(start) {
+		return start.Before(from) == false
+	} else {
+		return end.After(from)
+	}
+}
@@ -0,0 +1,153 @@
+package calendar_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/feed/calendar"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.CalendarFeed
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	ics = "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:single@test\r\n" +
+		"SUMMARY:Dentist\\, checkup\r\n" +
+		"DTSTART:20240105T090000Z\r\n" +
+		"DTEND:20240105T100000Z\r\n" +
+		"LOCATION:High\r\n" +
+		"  Street\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"DESCRIPTION:Reminder\r\n" +
+		"TRIGGER:-PT15M\r\n" +
+		"END:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:weekly@test\r\n" +
+		"SUMMARY:Standup\r\n" +
+		"DTSTART:20240101T083000Z\r\n" +
+		"DURATION:PT15M\r\n" +
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=6\r\n" +
+		"EXDATE:20240103T083000Z\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:weekly@test\r\n" +
+		"SUMMARY:Standup moved\r\n" +
+		"RECURRENCE-ID:20240108T083000Z\r\n" +
+		"DTSTART:20240108T110000Z\r\n" +
+		"DTEND:20240108T111500Z\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:cancelled@test\r\n" +
+		"SUMMARY:Cancelled\r\n" +
+		"STATUS:CANCELLED\r\n" +
+		"DTSTART:20240104T090000Z\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:allday@test\r\n" +
+		"SUMMARY:Holiday\r\n" +
+		"DTSTART;VALUE=DATE:20240112\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Calendar_001(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write([]byte(ics))
+	}))
+	defer server.Close()
+
+	tool.Test(t, []string{"-calendar.url", server.URL}, new(App), func(app *App) {
+		if err := app.CalendarFeed.Update(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		events := app.CalendarFeed.Events(from, from.AddDate(0, 0, 14))
+		summaries := []string{}
+		for _, evt := range events {
+			summaries = append(summaries, evt.Summary)
+		}
+
+		// Standup on the 1st, 3rd (excluded), 8th (moved), 10th, 15th and 17th
+		expected := []string{"Standup", "Dentist, checkup", "Standup moved", "Standup", "Holiday"}
+		if len(summaries) != len(expected) {
+			t.Fatal("Unexpected events", summaries)
+		}
+		for i := range expected {
+			if summaries[i] != expected[i] {
+				t.Error("Unexpected event", i, summaries[i], "expected", expected[i])
+			}
+		}
+		if evt := events[1]; evt.Location != "High Street" || evt.Description != "" {
+			t.Error("Unexpected event", evt)
+		}
+		if evt := events[3]; evt.Start.Day() != 10 || evt.End.Sub(evt.Start) != 15*time.Minute {
+			t.Error("Unexpected occurrence", evt)
+		}
+		if evt := events[4]; evt.AllDay == false || evt.End.Sub(evt.Start) != 24*time.Hour {
+			t.Error("Unexpected all day event", evt)
+		}
+		t.Log(app.CalendarFeed)
+	})
+}
+
+func Test_Calendar_002(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "calendar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	path := filepath.Join(tmp, "test.ics")
+	cache := filepath.Join(tmp, "cache.json")
+	if err := ioutil.WriteFile(path, []byte(ics), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read calendar from a file, which is written to the cache
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	tool.Test(t, []string{"-calendar.url", path, "-calendar.cache", cache}, new(App), func(app *App) {
+		if err := app.CalendarFeed.Update(context.Background()); err != nil {
+			t.Fatal(err)
+		} else if events := app.CalendarFeed.Events(from, from.AddDate(0, 0, 7)); len(events) != 2 {
+			t.Error("Unexpected events", events)
+		}
+	})
+
+	// Events are read from the cache when the calendar is unavailable
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	tool.Test(t, []string{"-calendar.url", path, "-calendar.cache", cache}, new(App), func(app *App) {
+		if err := app.CalendarFeed.Update(context.Background()); err == nil {
+			t.Error("Expected error when calendar is unavailable")
+		}
+		if events := app.CalendarFeed.Events(from, from.AddDate(0, 0, 7)); len(events) != 2 {
+			t.Error("Unexpected cached events", events)
+		}
+	})
+}
@@ -0,0 +1,10 @@
+// Calendar package implements gopi.CalendarFeed, which fetches iCal
+// calendars from URLs or files at an interval, and emits a
+// gopi.FeedEvent named "calendar" on each update.
+//
+// Recurring events are expanded into occurrences when events are
+// requested. Daily, weekly, monthly and yearly rules are supported,
+// with exception dates and replaced occurrences. The last calendars
+// fetched are written to the file set by -calendar.cache and read on
+// startup, so events are available when offline.
+package calendar
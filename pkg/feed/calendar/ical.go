@@ -0,0 +1,255 @@
+package calendar
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// vevent is an event read from a calendar. An event with a rule recurs,
+// and an event with a recurrence id replaces one occurrence
+type vevent struct {
+	gopi.CalendarEvent
+	Rule         *rrule      `json:"rule,omitempty"`
+	Exdates      []time.Time `json:"exdates,omitempty"`
+	RecurrenceId time.Time   `json:"recurrence_id,omitempty"`
+}
+
+// property is a content line as name, parameters and value
+type property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	reDuration = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// parseCalendar reads events from iCalendar data. Cancelled events
+// and events with properties which cannot be read are not returned
+func parseCalendar(r io.Reader, calendar string) ([]*vevent, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*vevent{}
+	var evt *vevent
+	var duration time.Duration
+	var skip bool
+	var nested int
+	for _, line := range lines {
+		prop := parseProperty(line)
+		if prop == nil {
+			continue
+		}
+		switch {
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VEVENT"):
+			evt = &vevent{CalendarEvent: gopi.CalendarEvent{Calendar: calendar}}
+			duration, skip, nested = 0, false, 0
+		case evt == nil:
+			// Ignore properties outside events
+		case prop.Name == "BEGIN":
+			// Alarms and other components within events
+			nested++
+		case prop.Name == "END" && nested > 0:
+			nested--
+		case nested > 0:
+			// Ignore properties of components within events
+		case prop.Name == "END" && strings.EqualFold(prop.Value, "VEVENT"):
+			if evt.End.IsZero() {
+				if duration != 0 {
+					evt.End = evt.Start.Add(duration)
+				} else if evt.AllDay {
+					evt.End = evt.Start.AddDate(0, 0, 1)
+				} else {
+					evt.End = evt.Start
+				}
+			}
+			if evt.Start.IsZero() == false && skip == false {
+				result = append(result, evt)
+			}
+			evt = nil
+		default:
+			if err := evt.set(prop, &duration); err != nil {
+				skip = true
+			} else if prop.Name == "STATUS" && strings.EqualFold(prop.Value, "CANCELLED") {
+				skip = true
+			}
+		}
+	}
+
+	// Return success
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// set a property of the event
+func (this *vevent) set(prop *property, duration *time.Duration) error {
+	switch prop.Name {
+	case "UID":
+		this.Uid = prop.Value
+	case "SUMMARY":
+		this.Summary = unescape(prop.Value)
+	case "DESCRIPTION":
+		this.Description = unescape(prop.Value)
+	case "LOCATION":
+		this.Location = unescape(prop.Value)
+	case "DTSTART":
+		if t, allday, err := parseTime(prop.Value, prop.Params); err != nil {
+			return err
+		} else {
+			this.Start, this.AllDay = t, allday
+		}
+	case "DTEND":
+		if t, _, err := parseTime(prop.Value, prop.Params); err != nil {
+			return err
+		} else {
+			this.End = t
+		}
+	case "DURATION":
+		if d, err := parseDuration(prop.Value); err != nil {
+			return err
+		} else {
+			*duration = d
+		}
+	case "RRULE":
+		if rule, err := parseRule(prop.Value); err != nil {
+			return err
+		} else {
+			this.Rule = rule
+		}
+	case "EXDATE":
+		for _, value := range strings.Split(prop.Value, ",") {
+			if t, _, err := parseTime(value, prop.Params); err != nil {
+				return err
+			} else {
+				this.Exdates = append(this.Exdates, t)
+			}
+		}
+	case "RECURRENCE-ID":
+		if t, _, err := parseTime(prop.Value, prop.Params); err != nil {
+			return err
+		} else {
+			this.RecurrenceId = t
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// unfold returns content lines, joining lines which continue with
+// a space or tab
+func unfold(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if n := len(lines); n > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[n-1] += line[1:]
+		} else if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseProperty returns a content line as name, parameters and value,
+// or nil if the line is not valid. Colons and semicolons within quoted
+// parameter values are ignored
+func parseProperty(line string) *property {
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && quoted == false {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return nil
+	}
+	fields := strings.Split(line[:colon], ";")
+	prop := &property{
+		Name:   strings.ToUpper(fields[0]),
+		Params: make(map[string]string, len(fields)-1),
+		Value:  line[colon+1:],
+	}
+	for _, param := range fields[1:] {
+		if kv := strings.SplitN(param, "=", 2); len(kv) == 2 {
+			prop.Params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], "\"")
+		}
+	}
+	return prop
+}
+
+// parseTime returns a date or date and time, and true for dates.
+// Times without a time zone are in the TZID parameter or local time
+func parseTime(value string, params map[string]string) (time.Time, bool, error) {
+	loc := time.Local
+	if tzid, exists := params["TZID"]; exists {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	value = strings.TrimSpace(value)
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		return t, true, err
+	} else if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	} else {
+		t, err := time.ParseInLocation("20060102T150405", value, loc)
+		return t, false, err
+	}
+}
+
+// parseDuration returns a duration such as P1D or PT1H30M
+func parseDuration(value string) (time.Duration, error) {
+	match := reDuration.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, gopi.ErrBadParameter.WithPrefix(value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	result := time.Duration(0)
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		} else if n, err := strconv.Atoi(match[i+2]); err != nil {
+			return 0, err
+		} else {
+			result += time.Duration(n) * unit
+		}
+	}
+	if match[1] == "-" {
+		result = -result
+	}
+	return result, nil
+}
+
+// unescape returns text with escaped characters replaced
+func unescape(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
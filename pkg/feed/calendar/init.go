@@ -0,0 +1,13 @@
+package calendar
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.CalendarFeed
+	graph.RegisterUnit(reflect.TypeOf(&calendar{}), reflect.TypeOf((*gopi.CalendarFeed)(nil)))
+}
@@ -0,0 +1,151 @@
+package calendar
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// rrule is a recurrence rule. The frequencies DAILY, WEEKLY, MONTHLY
+// and YEARLY are supported with INTERVAL, COUNT, UNTIL and, for weekly
+// rules, BYDAY
+type rrule struct {
+	Freq     string         `json:"freq"`
+	Interval int            `json:"interval,omitempty"`
+	Count    int            `json:"count,omitempty"`
+	Until    time.Time      `json:"until,omitempty"`
+	ByDay    []time.Weekday `json:"by_day,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Maximum number of occurrences to consider for a rule
+	maxOccurrences = 10000
+)
+
+var (
+	weekdays = map[string]time.Weekday{
+		"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+		"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func parseRule(value string) (*rrule, error) {
+	this := &rrule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch strings.ToUpper(kv[0]) {
+		case "FREQ":
+			this.Freq = strings.ToUpper(kv[1])
+		case "INTERVAL":
+			if n, err := strconv.Atoi(kv[1]); err != nil || n < 1 {
+				return nil, gopi.ErrBadParameter.WithPrefix(part)
+			} else {
+				this.Interval = n
+			}
+		case "COUNT":
+			if n, err := strconv.Atoi(kv[1]); err != nil || n < 1 {
+				return nil, gopi.ErrBadParameter.WithPrefix(part)
+			} else {
+				this.Count = n
+			}
+		case "UNTIL":
+			if t, _, err := parseTime(kv[1], nil); err != nil {
+				return nil, gopi.ErrBadParameter.WithPrefix(part)
+			} else {
+				this.Until = t
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(kv[1], ",") {
+				// Ordinals such as 1MO are ignored
+				day = strings.ToUpper(day)
+				if len(day) > 2 {
+					day = day[len(day)-2:]
+				}
+				if weekday, exists := weekdays[day]; exists {
+					this.ByDay = append(this.ByDay, weekday)
+				}
+			}
+		}
+	}
+	switch this.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+		return this, nil
+	default:
+		return nil, gopi.ErrNotImplemented.WithPrefix("FREQ=", this.Freq)
+	}
+}
+
+// Occurrences returns the start of occurrences from start which begin
+// before a time
+func (this *rrule) Occurrences(start, before time.Time) []time.Time {
+	result := []time.Time{}
+	count := 0
+	for n := 0; n < maxOccurrences; n++ {
+		for _, t := range this.period(start, n) {
+			if t.Before(start) {
+				continue
+			} else if this.Until.IsZero() == false && t.After(this.Until) {
+				return result
+			} else if this.Count > 0 && count >= this.Count {
+				return result
+			} else if t.Before(before) == false {
+				return result
+			}
+			result = append(result, t)
+			count++
+		}
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// period returns occurrences in the nth period from start, which
+// are skipped for days which do not exist in a month
+func (this *rrule) period(start time.Time, n int) []time.Time {
+	n *= this.Interval
+	switch this.Freq {
+	case "DAILY":
+		return []time.Time{start.AddDate(0, 0, n)}
+	case "WEEKLY":
+		if len(this.ByDay) == 0 {
+			return []time.Time{start.AddDate(0, 0, 7*n)}
+		}
+		// Weeks start on a Monday
+		monday := start.AddDate(0, 0, 7*n-(int(start.Weekday())+6)%7)
+		result := make([]time.Time, 0, len(this.ByDay))
+		for day := 0; day < 7; day++ {
+			t := monday.AddDate(0, 0, day)
+			for _, weekday := range this.ByDay {
+				if t.Weekday() == weekday {
+					result = append(result, t)
+				}
+			}
+		}
+		return result
+	case "MONTHLY":
+		if t := start.AddDate(0, n, 0); t.Day() == start.Day() {
+			return []time.Time{t}
+		}
+	case "YEARLY":
+		if t := start.AddDate(n, 0, 0); t.Day() == start.Day() {
+			return []time.Time{t}
+		}
+	}
+	return nil
+}
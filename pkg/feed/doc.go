@@ -0,0 +1,5 @@
+// Feed package contains the event emitted when data feeds are updated,
+// and the cache which keeps the last data fetched so that it is
+// available when offline. The weather and calendar packages implement
+// the feeds.
+package feed
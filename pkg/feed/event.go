@@ -0,0 +1,54 @@
+package feed
+
+import (
+	"fmt"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	name    string
+	updated time.Time
+	err     error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(name string, updated time.Time, err error) gopi.FeedEvent {
+	return &event{name, updated, err}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.name
+}
+
+func (this *event) Updated() time.Time {
+	return this.updated
+}
+
+func (this *event) Error() error {
+	return this.err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<feed.event"
+	str += fmt.Sprintf(" name=%q", this.name)
+	if this.updated.IsZero() == false {
+		str += " updated=" + this.updated.Format(time.RFC3339)
+	}
+	if this.err != nil {
+		str += fmt.Sprintf(" err=%q", this.err.Error())
+	}
+	return str + ">"
+}
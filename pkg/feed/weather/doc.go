@@ -0,0 +1,9 @@
+// Weather package implements gopi.WeatherFeed, which fetches current
+// conditions and the forecast for a location from Open-Meteo or
+// OpenWeatherMap at an interval, and emits a gopi.FeedEvent named
+// "weather" on each update.
+//
+// Set the location with the -weather.lat and -weather.lon flags. The
+// last weather fetched is written to the file set by -weather.cache
+// and read on startup, so weather is available when offline.
+package weather
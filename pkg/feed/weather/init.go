@@ -0,0 +1,13 @@
+package weather
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.WeatherFeed
+	graph.RegisterUnit(reflect.TypeOf(&weather{}), reflect.TypeOf((*gopi.WeatherFeed)(nil)))
+}
@@ -0,0 +1,201 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// openmeteo fetches weather from Open-Meteo, which does not require
+// an API key
+type openmeteo struct {
+	url string
+}
+
+type openmeteoResponse struct {
+	Current struct {
+		Time          int64   `json:"time"`
+		Temperature   float32 `json:"temperature_2m"`
+		Humidity      float32 `json:"relative_humidity_2m"`
+		Precipitation float32 `json:"precipitation"`
+		Code          int     `json:"weather_code"`
+		WindSpeed     float32 `json:"wind_speed_10m"`
+		WindDirection float32 `json:"wind_direction_10m"`
+	} `json:"current"`
+	Hourly struct {
+		Time          []int64   `json:"time"`
+		Temperature   []float32 `json:"temperature_2m"`
+		Humidity      []float32 `json:"relative_humidity_2m"`
+		Precipitation []float32 `json:"precipitation"`
+		Code          []int     `json:"weather_code"`
+		WindSpeed     []float32 `json:"wind_speed_10m"`
+		WindDirection []float32 `json:"wind_direction_10m"`
+	} `json:"hourly"`
+	Daily struct {
+		Time           []int64   `json:"time"`
+		TemperatureMax []float32 `json:"temperature_2m_max"`
+		TemperatureMin []float32 `json:"temperature_2m_min"`
+		Precipitation  []float32 `json:"precipitation_sum"`
+		Code           []int     `json:"weather_code"`
+		WindSpeed      []float32 `json:"wind_speed_10m_max"`
+		WindDirection  []float32 `json:"wind_direction_10m_dominant"`
+	} `json:"daily"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	openmeteoUrl       = "https://api.open-meteo.com/v1/forecast"
+	openmeteoVariables = "temperature_2m,relative_humidity_2m,precipitation,weather_code,wind_speed_10m,wind_direction_10m"
+	openmeteoDaily     = "weather_code,temperature_2m_max,temperature_2m_min,precipitation_sum,wind_speed_10m_max,wind_direction_10m_dominant"
+	openmeteoHours     = 48
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *openmeteo) Fetch(ctx context.Context, client *http.Client, lat, lon float64) (gopi.Weather, error) {
+	weather := gopi.Weather{Latitude: lat, Longitude: lon}
+
+	// Make the request
+	u, err := url.Parse(this.url)
+	if err != nil {
+		return weather, err
+	}
+	q := u.Query()
+	q.Set("latitude", fmt.Sprint(lat))
+	q.Set("longitude", fmt.Sprint(lon))
+	q.Set("current", openmeteoVariables)
+	q.Set("hourly", openmeteoVariables)
+	q.Set("daily", openmeteoDaily)
+	q.Set("wind_speed_unit", "ms")
+	q.Set("timeformat", "unixtime")
+	q.Set("timezone", "auto")
+	u.RawQuery = q.Encode()
+
+	var response openmeteoResponse
+	if err := get(ctx, client, u.String(), &response); err != nil {
+		return weather, err
+	}
+
+	// Current conditions
+	weather.Current = gopi.WeatherConditions{
+		Time:          time.Unix(response.Current.Time, 0),
+		Condition:     wmoCondition(response.Current.Code),
+		Temperature:   response.Current.Temperature,
+		Humidity:      response.Current.Humidity,
+		Precipitation: response.Current.Precipitation,
+		WindSpeed:     response.Current.WindSpeed,
+		WindDirection: response.Current.WindDirection,
+	}
+
+	// Hourly forecast from the current hour
+	hourly := response.Hourly
+	for i, t := range hourly.Time {
+		if len(weather.Hourly) >= openmeteoHours {
+			break
+		} else if t+3600 <= response.Current.Time {
+			continue
+		}
+		weather.Hourly = append(weather.Hourly, gopi.WeatherConditions{
+			Time:          time.Unix(t, 0),
+			Condition:     wmoCondition(intAt(hourly.Code, i)),
+			Temperature:   floatAt(hourly.Temperature, i),
+			Humidity:      floatAt(hourly.Humidity, i),
+			Precipitation: floatAt(hourly.Precipitation, i),
+			WindSpeed:     floatAt(hourly.WindSpeed, i),
+			WindDirection: floatAt(hourly.WindDirection, i),
+		})
+	}
+
+	// Daily forecast
+	daily := response.Daily
+	for i, t := range daily.Time {
+		weather.Daily = append(weather.Daily, gopi.WeatherConditions{
+			Time:           time.Unix(t, 0),
+			Condition:      wmoCondition(intAt(daily.Code, i)),
+			Temperature:    floatAt(daily.TemperatureMax, i),
+			TemperatureMin: floatAt(daily.TemperatureMin, i),
+			Precipitation:  floatAt(daily.Precipitation, i),
+			WindSpeed:      floatAt(daily.WindSpeed, i),
+			WindDirection:  floatAt(daily.WindDirection, i),
+		})
+	}
+
+	// Return success
+	return weather, nil
+}
+
+func (this *openmeteo) String() string {
+	return "openmeteo"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// get performs a request and decodes a JSON response
+func get(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	response, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return gopi.ErrUnexpectedResponse.WithPrefix(response.Status)
+	}
+	return json.NewDecoder(response.Body).Decode(v)
+}
+
+// wmoCondition returns the condition for a WMO weather code
+func wmoCondition(code int) gopi.WeatherCondition {
+	switch {
+	case code == 0:
+		return gopi.WEATHER_CONDITION_CLEAR
+	case code == 1 || code == 2:
+		return gopi.WEATHER_CONDITION_PARTLY_CLOUDY
+	case code == 3:
+		return gopi.WEATHER_CONDITION_CLOUDY
+	case code == 45 || code == 48:
+		return gopi.WEATHER_CONDITION_FOG
+	case code >= 51 && code <= 57:
+		return gopi.WEATHER_CONDITION_DRIZZLE
+	case code >= 61 && code <= 67, code >= 80 && code <= 82:
+		return gopi.WEATHER_CONDITION_RAIN
+	case code >= 71 && code <= 77, code == 85 || code == 86:
+		return gopi.WEATHER_CONDITION_SNOW
+	case code >= 95 && code <= 99:
+		return gopi.WEATHER_CONDITION_THUNDERSTORM
+	default:
+		return gopi.WEATHER_CONDITION_NONE
+	}
+}
+
+func floatAt(values []float32, i int) float32 {
+	if i < len(values) {
+		return values[i]
+	} else {
+		return 0
+	}
+}
+
+func intAt(values []int, i int) int {
+	if i < len(values) {
+		return values[i]
+	} else {
+		return -1
+	}
+}
@@ -0,0 +1,167 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// openweathermap fetches current weather and the three-hourly forecast
+// from OpenWeatherMap, which requires an API key
+type openweathermap struct {
+	url, key string
+}
+
+type owmConditions struct {
+	Time    int64 `json:"dt"`
+	Weather []struct {
+		Id int `json:"id"`
+	} `json:"weather"`
+	Main struct {
+		Temperature float32 `json:"temp"`
+		Humidity    float32 `json:"humidity"`
+	} `json:"main"`
+	Wind struct {
+		Speed     float32 `json:"speed"`
+		Direction float32 `json:"deg"`
+	} `json:"wind"`
+	Rain map[string]float32 `json:"rain"`
+	Snow map[string]float32 `json:"snow"`
+}
+
+type owmForecast struct {
+	List []owmConditions `json:"list"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	openweathermapUrl = "https://api.openweathermap.org/data/2.5"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *openweathermap) Fetch(ctx context.Context, client *http.Client, lat, lon float64) (gopi.Weather, error) {
+	weather := gopi.Weather{Latitude: lat, Longitude: lon}
+
+	// Fetch current weather and forecast
+	var current owmConditions
+	var forecast owmForecast
+	if err := get(ctx, client, this.endpoint("weather", lat, lon), &current); err != nil {
+		return weather, err
+	} else if err := get(ctx, client, this.endpoint("forecast", lat, lon), &forecast); err != nil {
+		return weather, err
+	}
+
+	// Set current, hourly and daily conditions
+	weather.Current = current.conditions("1h")
+	for _, item := range forecast.List {
+		weather.Hourly = append(weather.Hourly, item.conditions("3h"))
+	}
+	weather.Daily = daily(weather.Hourly)
+
+	// Return success
+	return weather, nil
+}
+
+func (this *openweathermap) String() string {
+	return "openweathermap"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *openweathermap) endpoint(path string, lat, lon float64) string {
+	v := url.Values{}
+	v.Set("lat", fmt.Sprint(lat))
+	v.Set("lon", fmt.Sprint(lon))
+	v.Set("appid", this.key)
+	v.Set("units", "metric")
+	return strings.TrimSuffix(this.url, "/") + "/" + path + "?" + v.Encode()
+}
+
+// conditions returns conditions, with precipitation over a period
+func (this owmConditions) conditions(period string) gopi.WeatherConditions {
+	c := gopi.WeatherConditions{
+		Time:          time.Unix(this.Time, 0),
+		Temperature:   this.Main.Temperature,
+		Humidity:      this.Main.Humidity,
+		Precipitation: this.Rain[period] + this.Snow[period],
+		WindSpeed:     this.Wind.Speed,
+		WindDirection: this.Wind.Direction,
+	}
+	if len(this.Weather) > 0 {
+		c.Condition = owmCondition(this.Weather[0].Id)
+	}
+	return c
+}
+
+// daily returns conditions for each day from a forecast, with the
+// minimum and maximum temperature, total precipitation and the most
+// severe condition
+func daily(forecast []gopi.WeatherConditions) []gopi.WeatherConditions {
+	result := []gopi.WeatherConditions{}
+	for _, c := range forecast {
+		y, m, d := c.Time.Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, c.Time.Location())
+		if n := len(result); n == 0 || result[n-1].Time.Equal(day) == false {
+			result = append(result, gopi.WeatherConditions{
+				Time:           day,
+				Condition:      c.Condition,
+				Temperature:    c.Temperature,
+				TemperatureMin: c.Temperature,
+				WindSpeed:      c.WindSpeed,
+				WindDirection:  c.WindDirection,
+			})
+		}
+		today := &result[len(result)-1]
+		if c.Condition > today.Condition {
+			today.Condition = c.Condition
+		}
+		if c.Temperature > today.Temperature {
+			today.Temperature = c.Temperature
+		}
+		if c.Temperature < today.TemperatureMin {
+			today.TemperatureMin = c.Temperature
+		}
+		if c.WindSpeed > today.WindSpeed {
+			today.WindSpeed, today.WindDirection = c.WindSpeed, c.WindDirection
+		}
+		today.Precipitation += c.Precipitation
+	}
+	return result
+}
+
+// owmCondition returns the condition for an OpenWeatherMap condition id
+func owmCondition(id int) gopi.WeatherCondition {
+	switch {
+	case id >= 200 && id < 300:
+		return gopi.WEATHER_CONDITION_THUNDERSTORM
+	case id >= 300 && id < 400:
+		return gopi.WEATHER_CONDITION_DRIZZLE
+	case id >= 500 && id < 600:
+		return gopi.WEATHER_CONDITION_RAIN
+	case id >= 600 && id < 700:
+		return gopi.WEATHER_CONDITION_SNOW
+	case id >= 700 && id < 800:
+		return gopi.WEATHER_CONDITION_FOG
+	case id == 800:
+		return gopi.WEATHER_CONDITION_CLEAR
+	case id == 801 || id == 802:
+		return gopi.WEATHER_CONDITION_PARTLY_CLOUDY
+	case id == 803 || id == 804:
+		return gopi.WEATHER_CONDITION_CLOUDY
+	default:
+		return gopi.WEATHER_CONDITION_NONE
+	}
+}
@@ -0,0 +1,200 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	feed "github.com/djthorpe/gopi/v3/pkg/feed"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type weather struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	sync.RWMutex
+
+	lat, lon *float64
+	interval *time.Duration
+	timeout  *time.Duration
+	cache    *string
+	client   *http.Client
+	provider provider
+	weather  gopi.Weather
+	valid    bool
+}
+
+// provider fetches weather for a location
+type provider interface {
+	Fetch(context.Context, *http.Client, float64, float64) (gopi.Weather, error)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	feedName = "weather"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *weather) Define(cfg gopi.Config) error {
+	cfg.FlagString("weather.provider", "openmeteo", "Weather provider (openmeteo, openweathermap)")
+	cfg.FlagString("weather.url", "", "Weather provider endpoint, or empty for the default")
+	cfg.FlagSecret("weather.key", "", "API key for openweathermap")
+	this.lat = cfg.FlagFloat("weather.lat", 0, "Latitude of location")
+	this.lon = cfg.FlagFloat("weather.lon", 0, "Longitude of location")
+	this.interval = cfg.FlagDuration("weather.interval", 15*time.Minute, "Interval between fetching weather")
+	this.timeout = cfg.FlagDuration("weather.timeout", 15*time.Second, "Weather request timeout")
+	this.cache = cfg.FlagPath("weather.cache", "", "Path to file caching weather when offline")
+	return nil
+}
+
+func (this *weather) New(cfg gopi.Config) error {
+	this.Require(this.Logger)
+
+	if *this.lat < -90 || *this.lat > 90 {
+		return gopi.ErrBadParameter.WithPrefix("-weather.lat")
+	} else if *this.lon < -180 || *this.lon > 180 {
+		return gopi.ErrBadParameter.WithPrefix("-weather.lon")
+	} else if *this.interval <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-weather.interval")
+	}
+
+	// Set provider
+	endpoint := cfg.GetString("weather.url")
+	switch name := cfg.GetString("weather.provider"); name {
+	case "openmeteo":
+		if endpoint == "" {
+			endpoint = openmeteoUrl
+		}
+		this.provider = &openmeteo{endpoint}
+	case "openweathermap":
+		if endpoint == "" {
+			endpoint = openweathermapUrl
+		}
+		if key := cfg.GetString("weather.key"); key == "" {
+			return gopi.ErrBadParameter.WithPrefix("-weather.key")
+		} else {
+			this.provider = &openweathermap{endpoint, key}
+		}
+	default:
+		return gopi.ErrBadParameter.WithPrefix("-weather.provider: ", name)
+	}
+	this.client = &http.Client{Timeout: *this.timeout}
+
+	// Read cached weather for the same location
+	var cached gopi.Weather
+	if exists, err := feed.ReadCache(*this.cache, &cached); err != nil {
+		this.Print("Weather: ", err)
+	} else if exists && cached.Latitude == *this.lat && cached.Longitude == *this.lon {
+		this.weather, this.valid = cached, true
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *weather) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.client = nil
+	this.provider = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *weather) Run(ctx context.Context) error {
+	// Fetch immediately unless the cache is recent
+	delay := time.Duration(0)
+	if weather, valid := this.Weather(); valid {
+		if since := time.Since(weather.Updated); since < *this.interval {
+			delay = *this.interval - since
+		}
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			if err := this.Update(ctx); err != nil && ctx.Err() == nil {
+				this.Print("Weather: ", err)
+			}
+			timer.Reset(*this.interval)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *weather) Weather() (gopi.Weather, bool) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.weather, this.valid
+}
+
+func (this *weather) Update(ctx context.Context) error {
+	weather, err := this.provider.Fetch(ctx, this.client, *this.lat, *this.lon)
+	if err != nil {
+		this.emit(feed.NewEvent(feedName, time.Time{}, err))
+		return err
+	}
+	weather.Updated = time.Now()
+
+	// Set weather and write to the cache
+	this.RWMutex.Lock()
+	this.weather, this.valid = weather, true
+	this.RWMutex.Unlock()
+	if err := feed.WriteCache(*this.cache, weather); err != nil {
+		this.Print("Weather: ", err)
+	}
+
+	// Emit event
+	this.emit(feed.NewEvent(feedName, weather.Updated, nil))
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *weather) String() string {
+	str := "<feed.weather"
+	str += fmt.Sprint(" provider=", this.provider)
+	str += fmt.Sprint(" lat=", *this.lat, " lon=", *this.lon)
+	if weather, valid := this.Weather(); valid {
+		str += fmt.Sprint(" condition=", weather.Current.Condition)
+		str += fmt.Sprintf(" temperature=%.1f", weather.Current.Temperature)
+		str += " updated=" + weather.Updated.Format(time.RFC3339)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *weather) emit(evt gopi.Event) {
+	if this.Publisher == nil {
+		return
+	} else if err := this.Publisher.Emit(evt, false); err != nil {
+		this.Debug("Weather: ", err)
+	}
+}
@@ -0,0 +1,137 @@
+package weather_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/feed/weather"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.WeatherFeed
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	openmeteoResponse = `{
+		"current": { "time": 1700000000, "temperature_2m": 12.5, "relative_humidity_2m": 80, "precipitation": 0.2, "weather_code": 61, "wind_speed_10m": 3.1, "wind_direction_10m": 270 },
+		"hourly": { "time": [1699996400, 1700000000, 1700003600], "temperature_2m": [11, 12, 13], "weather_code": [3, 61, 0] },
+		"daily": { "time": [1699920000, 1700006400], "temperature_2m_max": [14, 15], "temperature_2m_min": [8, 9], "weather_code": [95, 1] }
+	}`
+	owmWeatherResponse  = `{ "dt": 1700000000, "weather": [{ "id": 801 }], "main": { "temp": 10, "humidity": 70 }, "wind": { "speed": 2, "deg": 90 } }`
+	owmForecastResponse = `{ "list": [
+		{ "dt": 1700000000, "weather": [{ "id": 800 }], "main": { "temp": 10 } },
+		{ "dt": 1700010800, "weather": [{ "id": 500 }], "main": { "temp": 6 }, "rain": { "3h": 1.5 } }
+	] }`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Weather_001(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("latitude") != "51.5" || req.URL.Query().Get("longitude") != "-0.1" {
+			http.Error(w, "Bad location", http.StatusBadRequest)
+		} else {
+			w.Write([]byte(openmeteoResponse))
+		}
+	}))
+	defer server.Close()
+
+	tool.Test(t, []string{"-weather.url", server.URL, "-weather.lat", "51.5", "-weather.lon", "-0.1"}, new(App), func(app *App) {
+		if err := app.WeatherFeed.Update(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		weather, valid := app.WeatherFeed.Weather()
+		if valid == false {
+			t.Fatal("Expected valid weather")
+		} else if weather.Current.Condition != gopi.WEATHER_CONDITION_RAIN || weather.Current.Temperature != 12.5 {
+			t.Error("Unexpected current weather", weather.Current)
+		} else if len(weather.Hourly) != 2 || weather.Hourly[1].Condition != gopi.WEATHER_CONDITION_CLEAR {
+			t.Error("Unexpected hourly forecast", weather.Hourly)
+		} else if len(weather.Daily) != 2 || weather.Daily[0].Condition != gopi.WEATHER_CONDITION_THUNDERSTORM || weather.Daily[0].TemperatureMin != 8 {
+			t.Error("Unexpected daily forecast", weather.Daily)
+		}
+		t.Log(app.WeatherFeed)
+	})
+}
+
+func Test_Weather_002(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("appid") != "key" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		} else if req.URL.Path == "/weather" {
+			w.Write([]byte(owmWeatherResponse))
+		} else if req.URL.Path == "/forecast" {
+			w.Write([]byte(owmForecastResponse))
+		} else {
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+
+	args := []string{"-weather.provider", "openweathermap", "-weather.url", server.URL, "-weather.key", "key"}
+	tool.Test(t, args, new(App), func(app *App) {
+		if err := app.WeatherFeed.Update(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		weather, _ := app.WeatherFeed.Weather()
+		if weather.Current.Condition != gopi.WEATHER_CONDITION_PARTLY_CLOUDY || weather.Current.Humidity != 70 {
+			t.Error("Unexpected current weather", weather.Current)
+		} else if len(weather.Hourly) != 2 || weather.Hourly[1].Precipitation != 1.5 {
+			t.Error("Unexpected hourly forecast", weather.Hourly)
+		}
+		for _, day := range weather.Daily {
+			if day.TemperatureMin > day.Temperature {
+				t.Error("Unexpected daily forecast", day)
+			}
+		}
+	})
+}
+
+func Test_Weather_003(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "weather")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	cache := filepath.Join(tmp, "weather.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(openmeteoResponse))
+	}))
+
+	// Fetch weather, which is written to the cache
+	tool.Test(t, []string{"-weather.url", server.URL, "-weather.cache", cache}, new(App), func(app *App) {
+		if err := app.WeatherFeed.Update(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	})
+	server.Close()
+
+	// Weather is read from the cache when offline
+	tool.Test(t, []string{"-weather.url", server.URL, "-weather.cache", cache}, new(App), func(app *App) {
+		if err := app.WeatherFeed.Update(context.Background()); err == nil {
+			t.Error("Expected error when offline")
+		}
+		if weather, valid := app.WeatherFeed.Weather(); valid == false {
+			t.Error("Expected cached weather")
+		} else if weather.Current.Temperature != 12.5 {
+			t.Error("Unexpected cached weather", weather.Current)
+		}
+	})
+}
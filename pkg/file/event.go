@@ -0,0 +1,56 @@
+package file
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	path, oldpath string
+	flags         gopi.FSEventFlags
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(path, oldpath string, flags gopi.FSEventFlags) gopi.FSEvent {
+	return &event{path, oldpath, flags}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.path
+}
+
+func (this *event) Path() string {
+	return this.path
+}
+
+func (this *event) OldPath() string {
+	return this.oldpath
+}
+
+func (this *event) Flags() gopi.FSEventFlags {
+	return this.flags
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<event.fs"
+	str += fmt.Sprintf(" path=%q", this.path)
+	if this.oldpath != "" {
+		str += fmt.Sprintf(" oldpath=%q", this.oldpath)
+	}
+	if this.flags != gopi.FSEVENT_FLAG_NONE {
+		str += " flags=" + fmt.Sprint(this.flags)
+	}
+	return str + ">"
+}
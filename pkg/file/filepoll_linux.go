@@ -5,7 +5,11 @@ package file
 import (
 	"context"
 	"fmt"
+	"os"
+	"runtime"
+	"sort"
 	"sync"
+	"time"
 
 	gopi "github.com/djthorpe/gopi/v3"
 	linux "github.com/djthorpe/gopi/v3/pkg/sys/linux"
@@ -15,33 +19,62 @@ type filepoll struct {
 	gopi.Unit
 	gopi.Logger
 	sync.RWMutex
+	sync.WaitGroup
 	pipe
 
-	handle uintptr
-	cap    uint
-	funcs  map[uintptr]gopi.FilePollFunc
+	workers *uint
+	handle  uintptr
+	cap     uint
+	watch   map[uintptr]*watch
+	queue   []chan pollevent
+	leaks   []string
+}
+
+type watch struct {
+	mode    linux.EpollMode
+	fn      gopi.FilePollFunc
+	timer   bool
+	signals *signals
+	caller  string
+}
+
+type pollevent struct {
+	fd    uintptr
+	flags gopi.FilePollFlags
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // CONSTANTS
 
 const (
-	epollDefaultCapacity = 10
+	epollDefaultCapacity = 64
+	epollDefaultWorkers  = 4
+	epollQueueSize       = 16
 )
 
 ////////////////////////////////////////////////////////////////////////////////
 // INIT
 
+func (this *filepoll) Define(cfg gopi.Config) error {
+	this.workers = cfg.FlagUint("filepoll.workers", epollDefaultWorkers, "Number of workers for file polling callbacks")
+	return nil
+}
+
 func (this *filepoll) New(gopi.Config) error {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
 
+	// Check parameters
+	if *this.workers == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-filepoll.workers")
+	}
+
 	if handle, err := linux.EpollCreate(); err != nil {
 		return err
 	} else {
 		this.handle = handle
 		this.cap = epollDefaultCapacity
-		this.funcs = make(map[uintptr]gopi.FilePollFunc)
+		this.watch = make(map[uintptr]*watch)
 	}
 
 	// Initialize pipe
@@ -53,6 +86,12 @@ func (this *filepoll) New(gopi.Config) error {
 		return err
 	}
 
+	// Create a queue for each worker
+	this.queue = make([]chan pollevent, *this.workers)
+	for i := range this.queue {
+		this.queue[i] = make(chan pollevent, epollQueueSize)
+	}
+
 	// Return success
 	return nil
 }
@@ -66,6 +105,20 @@ func (this *filepoll) Dispose() error {
 		return err
 	}
 
+	// Keep watches which were not removed, to report as leaks
+	this.leaks = nil
+	for fd, w := range this.watch {
+		this.leaks = append(this.leaks, fmt.Sprint("*file.filepoll: fd ", fd, " watched by ", w.caller))
+	}
+	sort.Strings(this.leaks)
+
+	// Close timers and signals
+	for fd, w := range this.watch {
+		if err := w.Close(fd); err != nil {
+			return err
+		}
+	}
+
 	// Close polling
 	if this.handle != 0 {
 		if err := linux.EpollClose(this.handle); err != nil {
@@ -80,6 +133,7 @@ func (this *filepoll) Dispose() error {
 
 	// Release resources
 	this.handle = 0
+	this.watch = nil
 
 	// Return success
 	return nil
@@ -93,6 +147,8 @@ func (this *filepoll) String() string {
 	if this.handle != 0 {
 		str += " handle=" + fmt.Sprint(this.handle)
 	}
+	str += " workers=" + fmt.Sprint(len(this.queue))
+	str += " watching=" + fmt.Sprint(len(this.watch))
 	return str + ">"
 }
 
@@ -104,85 +160,228 @@ func (this *filepoll) Watch(fd uintptr, mode gopi.FilePollFlags, handler gopi.Fi
 	defer this.RWMutex.Unlock()
 
 	// Convert flags
-	flags := linux.EpollMode(0)
-	if mode&gopi.FILEPOLL_FLAG_READ == gopi.FILEPOLL_FLAG_READ {
-		flags |= linux.EPOLL_MODE_READ
-	}
-	if mode&gopi.FILEPOLL_FLAG_WRITE == gopi.FILEPOLL_FLAG_WRITE {
-		flags |= linux.EPOLL_MODE_WRITE
-	}
+	flags := flagsToMask(mode)
 
-	// Add watcher and start background goroutine
-	if _, exists := this.funcs[fd]; exists || fd == 0 || handler == nil || flags == 0 {
+	// Add watcher
+	if _, exists := this.watch[fd]; exists || fd == 0 || handler == nil || flags&(linux.EPOLL_MODE_READ|linux.EPOLL_MODE_WRITE|linux.EPOLL_MODE_PRIORITY) == 0 {
 		return gopi.ErrBadParameter.WithPrefix("Watch")
 	} else if err := linux.EpollAdd(this.handle, fd, flags); err != nil {
 		return err
 	} else {
-		this.funcs[fd] = handler
+		this.watch[fd] = &watch{mode: flags, fn: handler, caller: caller()}
 	}
 
 	// Success
 	return nil
 }
 
+func (this *filepoll) Timer(d time.Duration, repeat bool, handler gopi.FilePollFunc) (uintptr, error) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Check parameters
+	if d <= 0 || handler == nil {
+		return 0, gopi.ErrBadParameter.WithPrefix("Timer")
+	}
+
+	// Create and arm timer
+	flags := linux.EPOLL_MODE_READ | linux.EPOLL_MODE_ONESHOT
+	fd, err := linux.TimerCreate()
+	if err != nil {
+		return 0, err
+	} else if err := linux.TimerSet(fd, d, repeat); err != nil {
+		linux.TimerClose(fd)
+		return 0, err
+	} else if err := linux.EpollAdd(this.handle, fd, flags); err != nil {
+		linux.TimerClose(fd)
+		return 0, err
+	} else {
+		this.watch[fd] = &watch{mode: flags, fn: handler, timer: true, caller: caller()}
+	}
+
+	// Return success
+	return fd, nil
+}
+
+func (this *filepoll) Signal(handler gopi.FilePollSignalFunc, sig ...os.Signal) (uintptr, error) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Create signal pipe
+	flags := linux.EPOLL_MODE_READ | linux.EPOLL_MODE_ONESHOT
+	signals, err := newSignals(handler, sig...)
+	if err != nil {
+		return 0, err
+	}
+	fd := signals.ReadFd()
+	if err := linux.EpollAdd(this.handle, fd, flags); err != nil {
+		signals.Close()
+		return 0, err
+	} else {
+		this.watch[fd] = &watch{mode: flags, signals: signals, caller: caller()}
+	}
+
+	// Return success
+	return fd, nil
+}
+
 func (this *filepoll) Unwatch(fd uintptr) error {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
 
-	if _, exists := this.funcs[fd]; exists == false {
+	if w, exists := this.watch[fd]; exists == false {
 		return gopi.ErrBadParameter.WithPrefix("fd")
 	} else if err := linux.EpollDelete(this.handle, fd); err != nil {
 		return err
+	} else if err := w.Close(fd); err != nil {
+		return err
 	} else {
-		delete(this.funcs, fd)
+		delete(this.watch, fd)
 	}
 
 	// Success
 	return nil
 }
 
+// Leaks returns file descriptors which were still watched when disposed
+func (this *filepoll) Leaks() []string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.leaks
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // RUN
 
 func (this *filepoll) Run(ctx context.Context) error {
-	stop := false
+	// Start workers
+	for _, queue := range this.queue {
+		this.WaitGroup.Add(1)
+		go this.worker(queue)
+	}
 
 	go func() {
 		<-ctx.Done()
-		stop = true
 		this.pipe.Wake()
 	}()
 
-	for stop == false {
+	for ctx.Err() == nil {
 		evts, err := linux.EpollWait(this.handle, 0, this.cap)
 		if err != nil {
 			this.Print("FilePoll: ", err)
-		} else {
-			for _, evt := range evts {
-				// events need to be called in the right order
-				this.call(uintptr(evt.Fd), maskToFlags(evt.Flags()))
+			continue
+		}
+		for _, evt := range evts {
+			fd := uintptr(evt.Fd)
+			if fd == this.pipe.ReadFd() {
+				if err := this.pipe.Clear(); err != nil {
+					this.Print("FilePoll: ", err)
+				}
+				continue
 			}
+			// Events for the same file descriptor are always sent to
+			// the same worker so they are handled in the right order
+			this.queue[fd%uintptr(len(this.queue))] <- pollevent{fd, maskToFlags(evt.Flags())}
 		}
 	}
 
+	// Stop workers
+	for _, queue := range this.queue {
+		close(queue)
+	}
+	this.WaitGroup.Wait()
+
 	return nil
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// caller returns the function which called Watch, Timer or Signal
+func caller() string {
+	if pc, _, _, ok := runtime.Caller(2); ok {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			return fn.Name()
+		}
+	}
+	return "<unknown>"
+}
+
+func (this *filepoll) worker(queue <-chan pollevent) {
+	defer this.WaitGroup.Done()
+	for evt := range queue {
+		this.call(evt.fd, evt.flags)
+	}
+}
+
 func (this *filepoll) call(fd uintptr, flags gopi.FilePollFlags) {
+	// Hold the read lock whilst the handler is called, so that Unwatch
+	// does not close the timer or signal file descriptor underneath the
+	// worker, and handlers are not called once Unwatch has returned
 	this.RWMutex.RLock()
 	defer this.RWMutex.RUnlock()
 
-	if fd == this.pipe.ReadFd() && flags == gopi.FILEPOLL_FLAG_READ {
-		if err := this.pipe.Clear(); err != nil {
+	// Ignore events for file descriptors no longer watched
+	w, exists := this.watch[fd]
+	if exists == false {
+		return
+	}
+
+	// Call the handler
+	if w.timer {
+		if n, err := linux.TimerRead(fd); err != nil {
+			this.Print("FilePoll: ", err)
+		} else if n > 0 {
+			w.fn(fd, flags)
+		}
+	} else if w.signals != nil {
+		if err := w.signals.dispatch(); err != nil {
+			this.Print("FilePoll: ", err)
+		}
+	} else {
+		w.fn(fd, flags)
+	}
+
+	// Re-arm level-triggered file descriptors once the handler has
+	// returned
+	if w.mode&linux.EPOLL_MODE_ONESHOT == linux.EPOLL_MODE_ONESHOT {
+		if err := linux.EpollModify(this.handle, fd, w.mode); err != nil {
 			this.Print("FilePoll: ", err)
 		}
-	} else if handler, exists := this.funcs[fd]; exists {
-		handler(fd, flags)
 	}
 }
 
-////////////////////////////////////////////////////////////////////////////////
-// PRIVATE METHODS
+func (this *watch) Close(fd uintptr) error {
+	if this.timer {
+		return linux.TimerClose(fd)
+	} else if this.signals != nil {
+		return this.signals.Close()
+	} else {
+		return nil
+	}
+}
+
+func flagsToMask(flags gopi.FilePollFlags) linux.EpollMode {
+	mask := linux.EpollMode(0)
+	if flags&gopi.FILEPOLL_FLAG_READ == gopi.FILEPOLL_FLAG_READ {
+		mask |= linux.EPOLL_MODE_READ
+	}
+	if flags&gopi.FILEPOLL_FLAG_WRITE == gopi.FILEPOLL_FLAG_WRITE {
+		mask |= linux.EPOLL_MODE_WRITE
+	}
+	if flags&gopi.FILEPOLL_FLAG_PRIORITY == gopi.FILEPOLL_FLAG_PRIORITY {
+		mask |= linux.EPOLL_MODE_PRIORITY
+	}
+	// Level-triggered descriptors are disabled after each event and
+	// re-armed once the handler returns, so that events are not
+	// repeated whilst waiting for a worker
+	if flags&gopi.FILEPOLL_FLAG_EDGE == gopi.FILEPOLL_FLAG_EDGE {
+		mask |= linux.EPOLL_MODE_EDGE
+	} else {
+		mask |= linux.EPOLL_MODE_ONESHOT
+	}
+	return mask
+}
 
 func maskToFlags(mask linux.EpollMode) gopi.FilePollFlags {
 	flags := gopi.FILEPOLL_FLAG_NONE
@@ -192,5 +391,11 @@ func maskToFlags(mask linux.EpollMode) gopi.FilePollFlags {
 	if mask&linux.EPOLL_MODE_WRITE == linux.EPOLL_MODE_WRITE {
 		flags |= gopi.FILEPOLL_FLAG_WRITE
 	}
+	if mask&linux.EPOLL_MODE_PRIORITY == linux.EPOLL_MODE_PRIORITY {
+		flags |= gopi.FILEPOLL_FLAG_PRIORITY
+	}
+	if mask&(linux.EPOLL_MODE_HANGUP|linux.EPOLL_MODE_ERROR) != 0 {
+		flags |= gopi.FILEPOLL_FLAG_HANGUP
+	}
 	return flags
 }
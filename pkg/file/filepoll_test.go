@@ -3,7 +3,12 @@
 package file_test
 
 import (
+	"context"
+	"os"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/djthorpe/gopi/v3"
 	"github.com/djthorpe/gopi/v3/pkg/tool"
@@ -17,6 +22,11 @@ type App struct {
 	gopi.FilePoll
 }
 
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // TESTS
 
@@ -29,3 +39,88 @@ func Test_FilePoll_001(t *testing.T) {
 		}
 	})
 }
+
+func Test_FilePoll_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		fired := make(chan struct{})
+		if fd, err := app.FilePoll.Timer(100*time.Millisecond, false, func(uintptr, gopi.FilePollFlags) {
+			close(fired)
+		}); err != nil {
+			t.Error(err)
+		} else {
+			select {
+			case <-fired:
+				t.Log("Timer fired")
+			case <-time.After(time.Second):
+				t.Error("Timer did not fire")
+			}
+			if err := app.FilePoll.Unwatch(fd); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+}
+
+func Test_FilePoll_003(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		received := make(chan os.Signal, 1)
+		if fd, err := app.FilePoll.Signal(func(sig os.Signal) {
+			received <- sig
+		}, syscall.SIGUSR1); err != nil {
+			t.Error(err)
+		} else if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+			t.Error(err)
+		} else {
+			select {
+			case sig := <-received:
+				if sig != syscall.SIGUSR1 {
+					t.Error("Unexpected signal", sig)
+				}
+			case <-time.After(time.Second):
+				t.Error("Signal not received")
+			}
+			if err := app.FilePoll.Unwatch(fd); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+}
+
+func Test_FilePoll_004(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		// Slow repeating timer handler, which is not called once
+		// Unwatch has returned
+		var mu sync.Mutex
+		calls, running := 0, false
+		fd, err := app.FilePoll.Timer(5*time.Millisecond, true, func(uintptr, gopi.FilePollFlags) {
+			mu.Lock()
+			calls, running = calls+1, true
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			running = false
+			mu.Unlock()
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(100 * time.Millisecond)
+		if err := app.FilePoll.Unwatch(fd); err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		n, r := calls, running
+		mu.Unlock()
+		if n == 0 {
+			t.Error("Timer did not fire")
+		} else if r {
+			t.Error("Handler running after Unwatch")
+		}
+		time.Sleep(100 * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		if calls != n {
+			t.Error("Handler called after Unwatch")
+		}
+	})
+}
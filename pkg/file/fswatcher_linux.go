@@ -0,0 +1,321 @@
+// +build linux
+
+package file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	linux "github.com/djthorpe/gopi/v3/pkg/sys/linux"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type fswatcher struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.FilePoll
+	gopi.Publisher
+	sync.Mutex
+
+	handle uintptr
+	root   map[string]bool  // Watched paths and whether they are recursive
+	wd     map[int]*fswatch // Watch descriptors
+	moved  map[uint32]fsmove
+}
+
+type fswatch struct {
+	path      string
+	root      string
+	recursive bool
+}
+
+type fsmove struct {
+	path  string
+	flags gopi.FSEventFlags
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	fswatchMask = linux.INOTIFY_MASK_ATTRIB | linux.INOTIFY_MASK_CLOSE_WRITE |
+		linux.INOTIFY_MASK_CREATE | linux.INOTIFY_MASK_DELETE | linux.INOTIFY_MASK_DELETE_SELF |
+		linux.INOTIFY_MASK_MOVE_SELF | linux.INOTIFY_MASK_MOVED_FROM | linux.INOTIFY_MASK_MOVED_TO
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *fswatcher) New(gopi.Config) error {
+	this.Require(this.Logger, this.FilePoll, this.Publisher)
+
+	this.root = make(map[string]bool)
+	this.wd = make(map[int]*fswatch)
+	this.moved = make(map[uint32]fsmove)
+
+	if handle, err := linux.InotifyInit(); err != nil {
+		return err
+	} else if err := this.FilePoll.Watch(handle, gopi.FILEPOLL_FLAG_READ, this.read); err != nil {
+		linux.InotifyClose(handle)
+		return err
+	} else {
+		this.handle = handle
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *fswatcher) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Stop polling and close inotify, which removes all watches
+	if this.handle != 0 {
+		if err := this.FilePoll.Unwatch(this.handle); err != nil {
+			return err
+		} else if err := linux.InotifyClose(this.handle); err != nil {
+			return err
+		}
+	}
+
+	// Release resources
+	this.handle = 0
+	this.root = nil
+	this.wd = nil
+	this.moved = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *fswatcher) String() string {
+	str := "<fswatcher"
+	for _, path := range this.Paths() {
+		str += fmt.Sprintf(" path=%q", path)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *fswatcher) Watch(path string, recursive bool) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Check parameters
+	if abspath, err := filepath.Abs(path); err != nil {
+		return err
+	} else if _, exists := this.root[abspath]; exists {
+		return gopi.ErrDuplicateEntry.WithPrefix("Watch: ", path)
+	} else if err := this.add(abspath, abspath, recursive); err != nil {
+		this.remove(abspath)
+		return err
+	} else {
+		this.root[abspath] = recursive
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *fswatcher) Unwatch(path string) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if abspath, err := filepath.Abs(path); err != nil {
+		return err
+	} else if _, exists := this.root[abspath]; exists == false {
+		return gopi.ErrNotFound.WithPrefix("Unwatch: ", path)
+	} else {
+		delete(this.root, abspath)
+		return this.remove(abspath)
+	}
+}
+
+func (this *fswatcher) Paths() []string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	paths := make([]string, 0, len(this.root))
+	for path := range this.root {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// add watches a file or directory, and any subdirectories
+// when recursive is true
+func (this *fswatcher) add(path, root string, recursive bool) error {
+	if info, err := os.Stat(path); err != nil {
+		return err
+	} else if info.IsDir() == false || recursive == false {
+		return this.addWatch(path, root, recursive)
+	}
+	return filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if info.IsDir() {
+			return this.addWatch(path, root, recursive)
+		} else {
+			return nil
+		}
+	})
+}
+
+func (this *fswatcher) addWatch(path, root string, recursive bool) error {
+	if wd, err := linux.InotifyAdd(this.handle, path, fswatchMask); err != nil {
+		return err
+	} else {
+		this.wd[wd] = &fswatch{path, root, recursive}
+	}
+
+	// Return success
+	return nil
+}
+
+// remove stops watching all files and directories under a root path
+func (this *fswatcher) remove(root string) error {
+	var result error
+	for wd, w := range this.wd {
+		if w.root == root {
+			if err := linux.InotifyRemove(this.handle, wd); err != nil {
+				result = err
+			}
+			delete(this.wd, wd)
+		}
+	}
+	return result
+}
+
+// removeTree stops watching a directory and any subdirectories
+// after it has been moved out of the watched paths
+func (this *fswatcher) removeTree(path string) {
+	for wd, w := range this.wd {
+		if w.path == path || strings.HasPrefix(w.path, path+string(filepath.Separator)) {
+			linux.InotifyRemove(this.handle, wd)
+			delete(this.wd, wd)
+		}
+	}
+}
+
+// renameTree updates the paths of a renamed directory and any
+// subdirectories
+func (this *fswatcher) renameTree(oldpath, path string) {
+	for _, w := range this.wd {
+		if w.path == oldpath {
+			w.path = path
+		} else if strings.HasPrefix(w.path, oldpath+string(filepath.Separator)) {
+			w.path = path + strings.TrimPrefix(w.path, oldpath)
+		}
+	}
+}
+
+func (this *fswatcher) read(uintptr, gopi.FilePollFlags) {
+	evts, err := linux.InotifyRead(this.handle)
+	if err != nil {
+		this.Print("FSWatcher: ", err)
+		return
+	}
+
+	// Convert inotify events into FSEvent
+	this.Mutex.Lock()
+	events := []gopi.FSEvent{}
+	for _, evt := range evts {
+		if evt := this.process(evt); evt != nil {
+			events = append(events, evt)
+		}
+	}
+
+	// Files moved out of the watched paths are deleted
+	for cookie, move := range this.moved {
+		if move.flags&gopi.FSEVENT_FLAG_DIR != 0 {
+			this.removeTree(move.path)
+		}
+		events = append(events, NewEvent(move.path, "", move.flags|gopi.FSEVENT_FLAG_DELETED))
+		delete(this.moved, cookie)
+	}
+	this.Mutex.Unlock()
+
+	// Emit events
+	for _, evt := range events {
+		if err := this.Publisher.Emit(evt, true); err != nil {
+			this.Print("FSWatcher: ", err)
+		}
+	}
+}
+
+func (this *fswatcher) process(evt linux.InotifyEvt) gopi.FSEvent {
+	if evt.Mask&linux.INOTIFY_MASK_OVERFLOW != 0 {
+		this.Print("FSWatcher: event queue overflow")
+		return nil
+	}
+
+	w, exists := this.wd[evt.Wd]
+	if exists == false {
+		return nil
+	}
+
+	path := w.path
+	if evt.Name != "" {
+		path = filepath.Join(w.path, evt.Name)
+	}
+	flags := gopi.FSEVENT_FLAG_NONE
+	if evt.Mask&linux.INOTIFY_MASK_ISDIR != 0 {
+		flags |= gopi.FSEVENT_FLAG_DIR
+	}
+
+	switch {
+	case evt.Mask&linux.INOTIFY_MASK_IGNORED != 0:
+		delete(this.wd, evt.Wd)
+	case evt.Mask&linux.INOTIFY_MASK_CREATE != 0:
+		if flags&gopi.FSEVENT_FLAG_DIR != 0 && w.recursive {
+			if err := this.add(path, w.root, true); err != nil {
+				this.Print("FSWatcher: ", err)
+			}
+		}
+		return NewEvent(path, "", flags|gopi.FSEVENT_FLAG_CREATED)
+	case evt.Mask&linux.INOTIFY_MASK_MOVED_FROM != 0:
+		this.moved[evt.Cookie] = fsmove{path, flags}
+	case evt.Mask&linux.INOTIFY_MASK_MOVED_TO != 0:
+		if move, exists := this.moved[evt.Cookie]; exists {
+			delete(this.moved, evt.Cookie)
+			if flags&gopi.FSEVENT_FLAG_DIR != 0 {
+				this.renameTree(move.path, path)
+			}
+			return NewEvent(path, move.path, flags|gopi.FSEVENT_FLAG_RENAMED)
+		} else if flags&gopi.FSEVENT_FLAG_DIR != 0 && w.recursive {
+			if err := this.add(path, w.root, true); err != nil {
+				this.Print("FSWatcher: ", err)
+			}
+		}
+		return NewEvent(path, "", flags|gopi.FSEVENT_FLAG_CREATED)
+	case evt.Mask&linux.INOTIFY_MASK_DELETE != 0:
+		return NewEvent(path, "", flags|gopi.FSEVENT_FLAG_DELETED)
+	case evt.Mask&(linux.INOTIFY_MASK_DELETE_SELF|linux.INOTIFY_MASK_MOVE_SELF) != 0:
+		// Subdirectories are reported by their parent directory
+		if w.path == w.root {
+			return NewEvent(path, "", flags|gopi.FSEVENT_FLAG_DELETED)
+		}
+	case evt.Mask&(linux.INOTIFY_MASK_CLOSE_WRITE|linux.INOTIFY_MASK_ATTRIB) != 0:
+		return NewEvent(path, "", flags|gopi.FSEVENT_FLAG_MODIFIED)
+	}
+
+	// No event emitted
+	return nil
+}
@@ -0,0 +1,108 @@
+// +build linux
+
+package file_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/djthorpe/gopi/v3"
+	"github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type FSWatcherApp struct {
+	gopi.Unit
+	gopi.FSWatcher
+	gopi.Publisher
+}
+
+func (this *FSWatcherApp) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_FSWatcher_001(t *testing.T) {
+	tool.Test(t, nil, new(FSWatcherApp), func(app *FSWatcherApp) {
+		if app.FSWatcher == nil {
+			t.Error("nil FSWatcher unit")
+		} else {
+			t.Log(app.FSWatcher)
+		}
+	})
+}
+
+func Test_FSWatcher_002(t *testing.T) {
+	tool.Test(t, nil, new(FSWatcherApp), func(app *FSWatcherApp) {
+		dir, err := ioutil.TempDir("", "fswatcher")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		if err := app.FSWatcher.Watch(dir, true); err != nil {
+			t.Fatal(err)
+		}
+
+		// Create a subdirectory, then a file within it, then rename the file
+		sub := filepath.Join(dir, "sub")
+		if err := os.Mkdir(sub, 0755); err != nil {
+			t.Fatal(err)
+		}
+		expectEvent(t, ch, sub, gopi.FSEVENT_FLAG_CREATED|gopi.FSEVENT_FLAG_DIR)
+		file := filepath.Join(sub, "file")
+		if err := ioutil.WriteFile(file, []byte("test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		expectEvent(t, ch, file, gopi.FSEVENT_FLAG_CREATED)
+		expectEvent(t, ch, file, gopi.FSEVENT_FLAG_MODIFIED)
+		if err := os.Rename(file, file+".new"); err != nil {
+			t.Fatal(err)
+		}
+		if evt := expectEvent(t, ch, file+".new", gopi.FSEVENT_FLAG_RENAMED); evt != nil && evt.OldPath() != file {
+			t.Error("Unexpected old path", evt.OldPath())
+		}
+		if err := os.Remove(file + ".new"); err != nil {
+			t.Fatal(err)
+		}
+		expectEvent(t, ch, file+".new", gopi.FSEVENT_FLAG_DELETED)
+
+		if err := app.FSWatcher.Unwatch(dir); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func expectEvent(t *testing.T, ch <-chan gopi.Event, path string, flags gopi.FSEventFlags) gopi.FSEvent {
+	t.Helper()
+	select {
+	case evt := <-ch:
+		if evt, ok := evt.(gopi.FSEvent); ok == false {
+			t.Error("Unexpected event", evt)
+		} else if evt.Path() != path || evt.Flags() != flags {
+			t.Error("Unexpected event", evt)
+		} else {
+			return evt
+		}
+	case <-time.After(time.Second):
+		t.Error("Timeout waiting for", path, flags)
+	}
+	return nil
+}
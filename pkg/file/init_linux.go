@@ -10,6 +10,7 @@ import (
 )
 
 func init() {
-	// Register argonone
+	// Register filepoll and fswatcher
 	graph.RegisterUnit(reflect.TypeOf(&filepoll{}), reflect.TypeOf((*gopi.FilePoll)(nil)))
+	graph.RegisterUnit(reflect.TypeOf(&fswatcher{}), reflect.TypeOf((*gopi.FSWatcher)(nil)))
 }
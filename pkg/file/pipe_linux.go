@@ -54,6 +54,33 @@ func (this *pipe) Wake() error {
 	}
 }
 
+func (this *pipe) Send(b byte) error {
+	buf := []byte{b}
+	if n, err := unix.Write(this.fd[1], buf); n == -1 {
+		return err
+	} else {
+		return nil
+	}
+}
+
+func (this *pipe) Recv() ([]byte, error) {
+	var result []byte
+	buf := make([]byte, 100)
+	for {
+		if n, err := unix.Read(this.fd[0], buf); n == -1 {
+			if err == unix.EAGAIN {
+				return result, nil
+			} else {
+				return result, err
+			}
+		} else if n == 0 {
+			return result, nil
+		} else {
+			result = append(result, buf[:n]...)
+		}
+	}
+}
+
 func (this *pipe) Clear() error {
 	buf := make([]byte, 100)
 FOR_LOOP:
@@ -0,0 +1,103 @@
+// +build linux
+
+package file
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// signals are delivered through a pipe rather than signalfd, since the
+// go runtime handles signals on any thread and so signals cannot be
+// blocked reliably for signalfd to receive them
+type signals struct {
+	sync.WaitGroup
+	pipe
+
+	fn   gopi.FilePollSignalFunc
+	ch   chan os.Signal
+	done chan struct{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	signalQueueSize = 10
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func newSignals(fn gopi.FilePollSignalFunc, sig ...os.Signal) (*signals, error) {
+	this := new(signals)
+
+	// Check parameters
+	if fn == nil || len(sig) == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("Signal")
+	}
+	for _, s := range sig {
+		if _, ok := s.(syscall.Signal); ok == false {
+			return nil, gopi.ErrBadParameter.WithPrefix("Signal: ", s)
+		}
+	}
+
+	// Create pipe
+	if err := this.pipe.Init(); err != nil {
+		return nil, err
+	}
+
+	// Forward signals into pipe
+	this.fn = fn
+	this.ch = make(chan os.Signal, signalQueueSize)
+	this.done = make(chan struct{})
+	signal.Notify(this.ch, sig...)
+	this.WaitGroup.Add(1)
+	go this.forward()
+
+	// Return success
+	return this, nil
+}
+
+func (this *signals) Close() error {
+	signal.Stop(this.ch)
+	close(this.done)
+	this.WaitGroup.Wait()
+	return this.pipe.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *signals) forward() {
+	defer this.WaitGroup.Done()
+	for {
+		select {
+		case s := <-this.ch:
+			this.pipe.Send(byte(s.(syscall.Signal)))
+		case <-this.done:
+			return
+		}
+	}
+}
+
+func (this *signals) dispatch() error {
+	if buf, err := this.pipe.Recv(); err != nil {
+		return err
+	} else {
+		for _, b := range buf {
+			this.fn(syscall.Signal(b))
+		}
+	}
+
+	// Return success
+	return nil
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// config qualifies flag names with the name of a unit instance, so
+// that each instance defines and reads its own flags
+type config struct {
+	gopi.Config
+	name string
+}
+
+/////////////////////////////////////////////////////////////////////
+// CONSTRUCTOR
+
+// NewConfig returns a configuration for a named instance of a unit.
+// The flag "i2c.timeout" for the instance "1" becomes "i2c.1.timeout"
+func NewConfig(cfg gopi.Config, name string) gopi.Config {
+	return &config{cfg, name}
+}
+
+/////////////////////////////////////////////////////////////////////
+// DEFINE FLAGS
+
+func (this *config) FlagString(name, value, usage string, cmds ...string) *string {
+	return this.Config.FlagString(this.flag(name), value, usage, cmds...)
+}
+
+func (this *config) FlagBool(name string, value bool, usage string, cmds ...string) *bool {
+	return this.Config.FlagBool(this.flag(name), value, usage, cmds...)
+}
+
+func (this *config) FlagUint(name string, value uint, usage string, cmds ...string) *uint {
+	return this.Config.FlagUint(this.flag(name), value, usage, cmds...)
+}
+
+func (this *config) FlagInt(name string, value int, usage string, cmds ...string) *int {
+	return this.Config.FlagInt(this.flag(name), value, usage, cmds...)
+}
+
+func (this *config) FlagDuration(name string, value time.Duration, usage string, cmds ...string) *time.Duration {
+	return this.Config.FlagDuration(this.flag(name), value, usage, cmds...)
+}
+
+func (this *config) FlagFloat(name string, value float64, usage string, cmds ...string) *float64 {
+	return this.Config.FlagFloat(this.flag(name), value, usage, cmds...)
+}
+
+func (this *config) FlagPath(name, value, usage string, cmds ...string) *string {
+	return this.Config.FlagPath(this.flag(name), value, usage, cmds...)
+}
+
+func (this *config) FlagSecret(name, value, usage string, cmds ...string) *string {
+	return this.Config.FlagSecret(this.flag(name), value, usage, cmds...)
+}
+
+func (this *config) FlagStruct(prefix string, v interface{}, cmds ...string) error {
+	return this.Config.FlagStruct(this.flag(prefix), v, cmds...)
+}
+
+/////////////////////////////////////////////////////////////////////
+// GET FLAGS
+
+func (this *config) GetString(name string) string {
+	return this.Config.GetString(this.flag(name))
+}
+
+func (this *config) GetBool(name string) bool {
+	return this.Config.GetBool(this.flag(name))
+}
+
+func (this *config) GetUint(name string) uint {
+	return this.Config.GetUint(this.flag(name))
+}
+
+func (this *config) GetInt(name string) int {
+	return this.Config.GetInt(this.flag(name))
+}
+
+func (this *config) GetDuration(name string) time.Duration {
+	return this.Config.GetDuration(this.flag(name))
+}
+
+func (this *config) GetFloat(name string) float64 {
+	return this.Config.GetFloat(this.flag(name))
+}
+
+func (this *config) Secret(name string) bool {
+	return this.Config.Secret(this.flag(name))
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// flag inserts the instance name after the first part of a flag name
+func (this *config) flag(name string) string {
+	if i := strings.Index(name, "."); i >= 0 {
+		return name[:i] + "." + this.name + name[i:]
+	} else {
+		return name + "." + this.name
+	}
+}
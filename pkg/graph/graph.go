@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"reflect"
+	"runtime/debug"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	gopi "github.com/djthorpe/gopi/v3"
 	multierror "github.com/hashicorp/go-multierror"
@@ -19,11 +23,34 @@ type graph struct {
 	sync.RWMutex
 	sync.WaitGroup
 
-	units   map[reflect.Type]reflect.Value
+	units   map[key]reflect.Value
 	objs    []reflect.Value
 	Logfn   func(...interface{})
 	errs    chan error
 	cancels []context.CancelFunc
+
+	// Optional and lazy units, which are enabled and disabled
+	// while the graph is running
+	lazy    map[key]*lazy
+	fields  map[key][]reflect.Value
+	cfg     gopi.Config
+	seen    map[string]map[key]bool
+	running bool
+	stopped bool
+	lock    sync.Mutex
+
+	// Open file descriptors before New, for reporting leaks
+	fds map[string]string
+
+	// Results of calling New for each unit
+	startup []Startup
+}
+
+// key identifies a unit by type and instance name, which is empty
+// for singletons
+type key struct {
+	t    reflect.Type
+	name string
 }
 
 /////////////////////////////////////////////////////////////////////
@@ -41,7 +68,10 @@ var (
 // Construct empty graph
 func NewGraph(fn func(...interface{})) *graph {
 	this := new(graph)
-	this.units = make(map[reflect.Type]reflect.Value)
+	this.units = make(map[key]reflect.Value)
+	this.lazy = make(map[key]*lazy)
+	this.fields = make(map[key][]reflect.Value)
+	this.seen = make(map[string]map[key]bool)
 	this.Logfn = fn
 	return this
 }
@@ -77,7 +107,10 @@ func registerUnit(t, i reflect.Type) error {
 	if t.Implements(i) == false {
 		return fmt.Errorf("%v does not implement interface %v", t, i)
 	}
-	if _, exists := iface[i]; exists {
+	if loading {
+		// Units in a plugin are registered after the handshake
+		pending = append(pending, [2]reflect.Type{t, i})
+	} else if _, exists := iface[i]; exists {
 		return gopi.ErrDuplicateEntry.WithPrefix(i)
 	} else {
 		iface[i] = t
@@ -132,6 +165,14 @@ func (this *graph) Create(objs ...interface{}) error {
 			this.objs = append(this.objs, obj_)
 		}
 	}
+
+	// Lazy units are not set until enabled
+	for k, lazy := range this.lazy {
+		if lazy.enabled == false {
+			this.setFields(k, false)
+		}
+	}
+
 	return unwrap(result)
 }
 
@@ -141,7 +182,7 @@ func (this *graph) Define(cfg gopi.Config) error {
 	defer this.RWMutex.RUnlock()
 
 	var result error
-	seen := make(map[reflect.Type]bool, len(this.units))
+	seen := make(map[key]bool, len(this.units))
 	for _, obj := range this.objs {
 		if err := this.do("Define", obj, []reflect.Value{reflect.ValueOf(cfg)}, seen, 0); err != nil {
 			result = multierror.Append(result, err)
@@ -155,7 +196,14 @@ func (this *graph) New(cfg gopi.Config) error {
 	this.RWMutex.RLock()
 	defer this.RWMutex.RUnlock()
 
-	seen := make(map[reflect.Type]bool, len(this.units))
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	// Keep the configuration and the units which have been called
+	// for enabling lazy units later
+	seen := make(map[key]bool, len(this.units))
+	this.cfg, this.seen["New"] = cfg, seen
+	this.fds = openFiles()
 	for _, obj := range this.objs {
 		if err := this.do("New", obj, []reflect.Value{reflect.ValueOf(cfg)}, seen, 0); err != nil {
 			return err
@@ -173,7 +221,7 @@ func (this *graph) Dispose() error {
 	defer this.RWMutex.RUnlock()
 
 	var result error
-	seen := make(map[reflect.Type]bool, len(this.units))
+	seen := make(map[key]bool, len(this.units))
 	for _, obj := range this.objs {
 		if err := this.do("Dispose", obj, []reflect.Value{}, seen, 0); err != nil {
 			result = multierror.Append(result, err)
@@ -203,18 +251,27 @@ func (this *graph) Run(ctx context.Context, done bool) error {
 
 	// Make new context with cancel
 	ctx2, cancel := context.WithCancel(ctx)
+	this.lock.Lock()
 	this.cancels = append(this.cancels, cancel)
+	this.lock.Unlock()
 
 	// Call Run functions
-	seen := make(map[reflect.Type]bool, len(this.units))
+	this.lock.Lock()
+	seen := make(map[key]bool, len(this.units))
+	this.seen["Run"], this.running = seen, true
 	for _, obj := range this.objs {
 		if err := this.do("Run", obj, nil, seen, 0); err != nil {
 			result = multierror.Append(result, err)
 		}
 	}
+	this.lock.Unlock()
 
-	// Wait for ctx.Done, then send cancels
+	// Wait for ctx.Done, then send cancels. Lazy units cannot be
+	// enabled after this
 	<-ctx2.Done()
+	this.lock.Lock()
+	this.running, this.stopped = false, true
+	this.lock.Unlock()
 	this.cancelWithError(nil)
 
 	// Wait for all Run functions to complete, then finish
@@ -232,7 +289,12 @@ func (this *graph) cancelWithError(err error) {
 			logger.Debug("Cancelling with error: ", err)
 		}
 	}
-	for _, cancel := range this.cancels {
+
+	// Run functions are added when lazy units are enabled
+	this.lock.Lock()
+	cancels := append([]context.CancelFunc{}, this.cancels...)
+	this.lock.Unlock()
+	for _, cancel := range cancels {
 		cancel()
 	}
 }
@@ -280,7 +342,7 @@ func (this *graph) GetLogger() gopi.Logger {
 
 	if t, exists := iface[loggerType]; exists == false {
 		return nil
-	} else if unit, exists := this.units[t]; exists == false {
+	} else if unit, exists := this.units[key{t, ""}]; exists == false {
 		return nil
 	} else if isLoggerType(t) == false {
 		return nil
@@ -289,6 +351,47 @@ func (this *graph) GetLogger() gopi.Logger {
 	}
 }
 
+// GetPlatform returns a platform object if used, or nil
+func (this *graph) GetPlatform() gopi.Platform {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if t, exists := iface[platformType]; exists == false {
+		return nil
+	} else if unit, exists := this.units[key{t, ""}]; exists == false {
+		return nil
+	} else if platform, ok := unit.Interface().(gopi.Platform); ok == false {
+		return nil
+	} else {
+		return platform
+	}
+}
+
+// Graph returns the name of each unit and application object with the
+// names of the units it uses, including disabled units
+func (this *graph) Graph() map[string][]string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	result := make(map[string][]string, len(this.units)+len(this.objs))
+	units := append([]reflect.Value{}, this.objs...)
+	for _, unit := range this.units {
+		units = append(units, unit)
+	}
+	for _, unit := range units {
+		uses := []string{}
+		forEachField(unit, false, func(f reflect.StructField, i int) error {
+			if k := this.unitKeyForField(f); k.t != nil {
+				uses = append(uses, k.String())
+			}
+			return nil
+		})
+		sort.Strings(uses)
+		result[keyForUnit(unit).String()] = uses
+	}
+	return result
+}
+
 /////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
@@ -301,22 +404,43 @@ func (this *graph) graph(unit reflect.Value) error {
 	// For each field, initialise either by mapping an interface to
 	// a registered unit type or directly
 	return forEachField(unit, false, func(f reflect.StructField, i int) error {
-		t := this.unitTypeForField(f)
-		if t == nil {
+		// Set the unit manager to the graph
+		if f.Type == unitManagerType {
+			unit.Elem().Field(i).Set(reflect.ValueOf(this))
 			return nil
 		}
 
-		// Create a unit
-		if _, exists := this.units[t]; exists == false {
-			this.units[t] = reflect.New(t.Elem())
-			if err := this.graph(this.units[t]); err != nil {
+		k := this.unitKeyForField(f)
+		if k.t == nil {
+			return nil
+		}
+
+		// Optional and lazy units are recorded with a name
+		if option, err := optionForField(f); err != nil {
+			return fmt.Errorf("%w (in %v)", err, k)
+		} else if _, exists := this.lazy[k]; exists == false && option != "" {
+			this.lazy[k] = &lazy{
+				name:     nameForField(f, k.name),
+				optional: option == optionOptional,
+				enabled:  option == optionOptional,
+			}
+		}
+
+		// Create a unit, and set the instance name
+		if _, exists := this.units[k]; exists == false {
+			this.units[k] = reflect.New(k.t.Elem())
+			if k.name != "" {
+				unitForValue(this.units[k]).SetInstanceName(k.name)
+			}
+			if err := this.graph(this.units[k]); err != nil {
 				return err
 			}
 		}
 
 		// Set field to unit
 		field := unit.Elem().Field(i)
-		field.Set(this.units[t])
+		field.Set(this.units[k])
+		this.fields[k] = append(this.fields[k], field)
 
 		// Return success
 		return nil
@@ -344,33 +468,53 @@ func (this *graph) unitTypeForField(f reflect.StructField) reflect.Type {
 	return nil
 }
 
-func (this *graph) do(fn string, unit reflect.Value, args []reflect.Value, seen map[reflect.Type]bool, indent int) error {
+// unitKeyForField returns the unit type and the instance name from the
+// field tag, or a key with nil type if the field is not a unit
+func (this *graph) unitKeyForField(f reflect.StructField) key {
+	if t := this.unitTypeForField(f); t == nil {
+		return key{}
+	} else {
+		return key{t, instanceForField(f)}
+	}
+}
+
+func (this *graph) do(fn string, unit reflect.Value, args []reflect.Value, seen map[key]bool, indent int) error {
 	// Check incoming parameter
 	if isUnitType(unit.Type()) == false {
 		return gopi.ErrBadParameter.WithPrefix(unit.Type().String())
 	}
 
+	// Flags are defined and read for each instance of a unit
+	unitargs := argsForUnit(unit, args)
+
 	var result error
 	if fn == "Dispose" {
 		if this.Logfn != nil {
-			this.Logfn(strings.Repeat(" ", indent*2), fn, "=>", unit.Type())
+			this.Logfn(strings.Repeat(" ", indent*2), fn, "=>", keyForUnit(unit))
 		}
-		if err := callFn(fn, unit, args); err != nil {
+		if err := callFn(fn, unit, unitargs); err != nil {
 			result = multierror.Append(result, err)
 		}
 	}
 
 	// For each field, call function
 	if err := forEachField(unit, fn == "New", func(f reflect.StructField, i int) error {
-		if t := this.unitTypeForField(f); t == nil {
+		if k := this.unitKeyForField(f); k.t == nil {
 			return nil
-		} else if _, exists := seen[t]; exists {
+		} else if _, exists := seen[k]; exists {
 			return nil
-		} else if err := this.do(fn, this.units[t], args, seen, indent+1); err != nil {
-			seen[t] = true
-			return fmt.Errorf("%w (in %v)", err, t)
+		} else if this.isDisabled(fn, k) {
+			return nil
+		} else if err := this.do(fn, this.units[k], args, seen, indent+1); err != nil {
+			seen[k] = true
+			if fn == "New" && this.isOptional(k) {
+				// Disable optional units which fail
+				this.disable(k, err)
+				return nil
+			}
+			return fmt.Errorf("%w (in %v)", err, k)
 		} else {
-			seen[t] = true
+			seen[k] = true
 		}
 		return nil
 	}); err != nil {
@@ -379,14 +523,19 @@ func (this *graph) do(fn string, unit reflect.Value, args []reflect.Value, seen
 
 	if fn == "Run" {
 		if this.Logfn != nil {
-			this.Logfn(strings.Repeat(" ", indent*2), fn, "=>", unit.Type())
+			this.Logfn(strings.Repeat(" ", indent*2), fn, "=>", keyForUnit(unit))
 		}
 		this.callRun(unit)
 	} else if fn != "Dispose" {
 		if this.Logfn != nil {
-			this.Logfn(strings.Repeat(" ", indent*2), fn, "=>", unit.Type())
+			this.Logfn(strings.Repeat(" ", indent*2), fn, "=>", keyForUnit(unit))
+		}
+		start := time.Now()
+		err := callWithLabel(fn, unit, unitargs)
+		if fn == "New" {
+			this.started(unit, time.Since(start), err)
 		}
-		if err := callFn(fn, unit, args); err != nil {
+		if err != nil {
 			result = multierror.Append(result, err)
 		}
 	}
@@ -398,14 +547,19 @@ func (this *graph) callRun(unit reflect.Value) {
 	this.WaitGroup.Add(1)
 	ctx, cancel := context.WithCancel(context.Background())
 	this.cancels = append(this.cancels, cancel)
+	done := make(chan struct{})
+	if lazy, exists := this.lazy[keyForUnit(unit)]; exists {
+		lazy.cancel, lazy.done = cancel, done
+	}
 	go func() {
-		err := callFn("Run", unit, []reflect.Value{reflect.ValueOf(ctx)})
+		err := recoverRun(unit, ctx)
+		close(done)
 		if this.isAppObject(unit) {
 			// Run ends when any application Run function ends
 			this.cancelWithError(err)
 		}
 		if this.Logfn != nil {
-			this.Logfn("Run", "<=", unit.Type())
+			this.Logfn("Run", "<=", keyForUnit(unit))
 		}
 		this.errs <- err
 		this.WaitGroup.Done()
@@ -413,9 +567,30 @@ func (this *graph) callRun(unit reflect.Value) {
 	}()
 }
 
+// recoverRun calls Run for a unit and returns a panic as an error, with
+// the stack written to stderr, so that units are disposed and can
+// restore hardware such as the console before the application exits
+func recoverRun(unit reflect.Value, ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "panic: %v\n\n%s\n", r, debug.Stack())
+			err = gopi.ErrInternalAppError.WithPrefix("Run: ", keyForUnit(unit), ": Panic: ", r)
+		}
+	}()
+	return callWithLabel("Run", unit, []reflect.Value{reflect.ValueOf(ctx)})
+}
+
 /////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
+func (k key) String() string {
+	if k.name == "" {
+		return fmt.Sprint(k.t)
+	} else {
+		return fmt.Sprintf("%v(%q)", k.t, k.name)
+	}
+}
+
 func (this *graph) String() string {
 	str := "<graph"
 	for k, v := range this.objs {
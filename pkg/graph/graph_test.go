@@ -0,0 +1,199 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	config "github.com/djthorpe/gopi/v3/pkg/config"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type Bus interface {
+	Name() string
+	Speed() uint
+}
+
+type bus struct {
+	gopi.Unit
+	speed *uint
+}
+
+type App struct {
+	gopi.Unit
+	Bus
+	Bus1  Bus `unit:"1"`
+	Bus3  Bus `unit:"3"`
+	Other *Other
+}
+
+type Other struct {
+	gopi.Unit
+	Bus `unit:"1"`
+}
+
+type Drivers struct {
+	gopi.Unit
+	gopi.UnitManager
+	Camera Bus `unit:"camera,optional"`
+	Cast   Bus `unit:"cast,lazy"`
+}
+
+// Panic panics when run, and records when it is disposed
+type Panic struct {
+	gopi.Unit
+	disposed bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&bus{}), reflect.TypeOf((*Bus)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// DRIVERS
+
+func (this *Drivers) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PANIC
+
+func (this *Panic) Run(context.Context) error {
+	panic("run")
+}
+
+func (this *Panic) Dispose() error {
+	this.disposed = true
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// BUS
+
+func (this *bus) Define(cfg gopi.Config) error {
+	this.speed = cfg.FlagUint("bus.speed", 100, "Bus speed")
+	return nil
+}
+
+func (this *bus) New(gopi.Config) error {
+	if *this.speed == 0 {
+		return gopi.ErrNotFound.WithPrefix("Bus ", this.InstanceName())
+	}
+	return nil
+}
+
+func (this *bus) Name() string {
+	return this.InstanceName()
+}
+
+func (this *bus) Speed() uint {
+	return *this.speed
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Graph_001(t *testing.T) {
+	args := []string{"-bus.speed=200", "-bus.3.speed=400"}
+	tool.Test(t, args, new(App), func(app *App) {
+		if app.Bus == app.Bus1 || app.Bus1 == app.Bus3 {
+			t.Error("Expected separate instances")
+		} else if app.Bus1 != app.Other.Bus {
+			t.Error("Expected shared instance")
+		}
+		if app.Bus.Name() != "" || app.Bus1.Name() != "1" || app.Bus3.Name() != "3" {
+			t.Error("Unexpected names", app.Bus.Name(), app.Bus1.Name(), app.Bus3.Name())
+		}
+		if app.Bus.Speed() != 200 || app.Bus1.Speed() != 100 || app.Bus3.Speed() != 400 {
+			t.Error("Unexpected flags", app.Bus.Speed(), app.Bus1.Speed(), app.Bus3.Speed())
+		}
+	})
+}
+
+func Test_Graph_002(t *testing.T) {
+	args := []string{"-bus.camera.speed=0"}
+	tool.Test(t, args, new(Drivers), func(app *Drivers) {
+		if app.UnitManager == nil {
+			t.Fatal("Expected unit manager")
+		} else if units := app.UnitManager.Units(); reflect.DeepEqual(units, []string{"graph_test.Bus/camera", "graph_test.Bus/cast"}) == false {
+			t.Error("Unexpected units", units)
+		}
+
+		// Camera is disabled as New fails, and cast is not enabled
+		if app.Camera != nil || app.UnitManager.Enabled("graph_test.Bus/camera") {
+			t.Error("Expected camera disabled")
+		} else if app.Cast != nil || app.UnitManager.Enabled("graph_test.Bus/cast") {
+			t.Error("Expected cast disabled")
+		}
+
+		// Enable and disable cast
+		if err := app.UnitManager.Enable("graph_test.Bus/cast"); err != nil {
+			t.Error(err)
+		} else if app.Cast == nil || app.Cast.Name() != "cast" {
+			t.Error("Expected cast enabled")
+		} else if err := app.UnitManager.Disable("graph_test.Bus/cast"); err != nil {
+			t.Error(err)
+		} else if app.Cast != nil {
+			t.Error("Expected cast disabled")
+		}
+
+		// Camera cannot be enabled
+		if err := app.UnitManager.Enable("graph_test.Bus/camera"); err == nil {
+			t.Error("Expected error")
+		} else if app.Camera != nil {
+			t.Error("Expected camera disabled")
+		}
+		if err := app.UnitManager.Enable("other"); err == nil {
+			t.Error("Expected error")
+		}
+	})
+}
+
+func Test_Graph_003(t *testing.T) {
+	tool.Test(t, nil, new(Drivers), func(app *Drivers) {
+		graph := app.UnitManager.Graph()
+		if uses, exists := graph["*graph_test.Drivers"]; exists == false {
+			t.Error("Expected application object", graph)
+		} else if reflect.DeepEqual(uses, []string{`*graph_test.bus("camera")`, `*graph_test.bus("cast")`}) == false {
+			t.Error("Unexpected uses", uses)
+		}
+		if uses, exists := graph[`*graph_test.bus("cast")`]; exists == false || len(uses) != 0 {
+			t.Error("Unexpected graph", graph)
+		}
+	})
+}
+
+func Test_Graph_004(t *testing.T) {
+	app := new(Panic)
+	cfg := config.New(t.Name(), nil)
+	g := graph.NewGraph(t.Log)
+	if err := g.Create(app); err != nil {
+		t.Fatal(err)
+	} else if err := g.Define(cfg); err != nil {
+		t.Fatal(err)
+	} else if err := cfg.Parse(); err != nil {
+		t.Fatal(err)
+	} else if err := g.New(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// A panic in Run is returned as an error, so that units are disposed
+	if err := g.Run(context.Background(), true); errors.Is(err, gopi.ErrInternalAppError) == false {
+		t.Error("Unexpected error", err)
+	} else if err := g.Dispose(); err != nil {
+		t.Error(err)
+	} else if app.disposed == false {
+		t.Error("Expected unit to be disposed")
+	}
+}
@@ -0,0 +1,181 @@
+package graph
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// lazy is an optional unit, which is disabled when New fails, or a
+// lazy unit, which is only created when enabled
+type lazy struct {
+	name     string
+	optional bool
+	enabled  bool
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Units returns the names of optional and lazy units
+func (this *graph) Units() []string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	names := make([]string, 0, len(this.lazy))
+	for _, lazy := range this.lazy {
+		names = append(names, lazy.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Enabled returns true if an optional or lazy unit is enabled
+func (this *graph) Enabled(name string) bool {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if _, lazy := this.lazyForName(name); lazy == nil {
+		return false
+	} else {
+		return lazy.enabled
+	}
+}
+
+// Enable calls New and Run for an optional or lazy unit and any units
+// it uses which are not yet created, then sets the fields which refer
+// to it. When called before the graph is running, Run is called with
+// the other units. It cannot be called before New or after the graph
+// has stopped
+func (this *graph) Enable(name string) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	k, lazy := this.lazyForName(name)
+	if lazy == nil {
+		return gopi.ErrNotFound.WithPrefix(name)
+	} else if lazy.enabled {
+		return nil
+	} else if this.cfg == nil || this.stopped {
+		return gopi.ErrOutOfOrder.WithPrefix(name)
+	}
+
+	// Call New and then Run for the unit
+	unit := this.units[k]
+	lazy.enabled = true
+	if err := this.do("New", unit, []reflect.Value{reflect.ValueOf(this.cfg)}, this.seen["New"], 0); err != nil {
+		this.disable(k, err)
+		return err
+	} else {
+		this.seen["New"][k] = true
+	}
+	if this.running {
+		if err := this.do("Run", unit, nil, this.seen["Run"], 0); err != nil {
+			return err
+		} else {
+			this.seen["Run"][k] = true
+		}
+	}
+
+	// Set fields
+	this.setFields(k, true)
+
+	// Return success
+	return nil
+}
+
+// Disable clears the fields which refer to an optional or lazy unit,
+// then cancels Run and calls Dispose for the unit. Units it uses
+// continue to run
+func (this *graph) Disable(name string) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	k, lazy := this.lazyForName(name)
+	if lazy == nil {
+		return gopi.ErrNotFound.WithPrefix(name)
+	} else if lazy.enabled == false {
+		return nil
+	}
+
+	// Cancel Run and wait for it to end
+	this.setFields(k, false)
+	if lazy.cancel != nil {
+		lazy.cancel()
+		<-lazy.done
+		lazy.cancel, lazy.done = nil, nil
+	}
+
+	// Call Dispose
+	lazy.enabled = false
+	for _, seen := range this.seen {
+		delete(seen, k)
+	}
+	return callFn("Dispose", this.units[k], nil)
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *graph) lazyForName(name string) (key, *lazy) {
+	for k, lazy := range this.lazy {
+		if lazy.name == name {
+			return k, lazy
+		}
+	}
+	return key{}, nil
+}
+
+// isDisabled returns true if a function should not be called for a
+// unit which is not enabled. Define is always called so that flags
+// are defined for lazy units
+func (this *graph) isDisabled(fn string, k key) bool {
+	if fn == "Define" {
+		return false
+	} else if lazy, exists := this.lazy[k]; exists == false {
+		return false
+	} else {
+		return lazy.enabled == false
+	}
+}
+
+// isOptional returns true if a unit is disabled when New fails
+func (this *graph) isOptional(k key) bool {
+	if lazy, exists := this.lazy[k]; exists {
+		return lazy.optional
+	} else {
+		return false
+	}
+}
+
+// disable is called when New fails for a unit, to call Dispose
+// and clear the fields which refer to it
+func (this *graph) disable(k key, err error) {
+	lazy := this.lazy[k]
+	if this.Logfn != nil {
+		this.Logfn("Disabled", "=>", lazy.name, ": ", err)
+	}
+	lazy.enabled = false
+	this.setFields(k, false)
+	if err := callFn("Dispose", this.units[k], nil); err != nil && this.Logfn != nil {
+		this.Logfn("Dispose", "=>", lazy.name, ": ", err)
+	}
+}
+
+// setFields sets or clears the fields which refer to a unit
+func (this *graph) setFields(k key, enabled bool) {
+	for _, field := range this.fields[k] {
+		if enabled {
+			field.Set(this.units[k])
+		} else {
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// leaker is implemented by units which report resources which were
+// not released by other units, such as file descriptors which are
+// still watched
+type leaker interface {
+	Leaks() []string
+}
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// unitLabel is the goroutine label for the unit which started
+	// a goroutine
+	unitLabel = "unit"
+
+	// leakWait is how long to wait for goroutines to end after Dispose
+	leakWait = 100 * time.Millisecond
+
+	// procFiles lists open file descriptors on linux
+	procFiles = "/proc/self/fd"
+)
+
+var (
+	reLabel = regexp.MustCompile(`"` + unitLabel + `":("(?:[^"\\]|\\.)*")`)
+)
+
+// Frames which are skipped when reporting where a goroutine is waiting
+var skipFrames = []string{"runtime.", "runtime/", "sync.", "internal/", "time."}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Leaks returns goroutines started by units in New or Run which are
+// still running, file descriptors opened since New which are still open
+// and any resources reported by units. It should be called after Dispose
+func (this *graph) Leaks() []string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	// Wait for goroutines to end
+	result := []string{}
+	deadline := time.Now().Add(leakWait)
+	for {
+		result = goroutines()
+		if len(result) == 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(leakWait / 10)
+	}
+
+	// Report file descriptors which were opened since New
+	files := []string{}
+	for fd, target := range openFiles() {
+		if _, exists := this.fds[fd]; exists == false {
+			files = append(files, fmt.Sprintf("file descriptor %v (%v)", fd, target))
+		}
+	}
+	sort.Strings(files)
+	result = append(result, files...)
+
+	// Report resources from units
+	for _, k := range this.sortedKeys() {
+		if unit, ok := this.units[k].Interface().(leaker); ok {
+			result = append(result, unit.Leaks()...)
+		}
+	}
+
+	// Return leaks
+	return result
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// callWithLabel calls a unit function with the unit as a goroutine
+// label, so that goroutines started by the function have the label
+func callWithLabel(fn string, unit reflect.Value, args []reflect.Value) error {
+	var result error
+	pprof.Do(context.Background(), pprof.Labels(unitLabel, keyForUnit(unit).String()), func(context.Context) {
+		result = callFn(fn, unit, args)
+	})
+	return result
+}
+
+// sortedKeys returns unit keys in a consistent order
+func (this *graph) sortedKeys() []key {
+	keys := make([]key, 0, len(this.units))
+	for k := range this.units {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	return keys
+}
+
+// goroutines returns running goroutines which have a unit label, and
+// the function where each is waiting
+func goroutines() []string {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil
+	}
+
+	// Each stack is a count, labels and frames, separated by a blank line
+	result := []string{}
+	for _, block := range strings.Split(buf.String(), "\n\n") {
+		var unit, frame string
+		lines := strings.Split(block, "\n")
+		for _, line := range lines[1:] {
+			if strings.HasPrefix(line, "# labels: ") {
+				if match := reLabel.FindStringSubmatch(line); match != nil {
+					unit, _ = strconv.Unquote(match[1])
+				}
+			} else if fields := strings.Split(line, "\t"); frame == "" && len(fields) == 4 && skipFrame(fields[2]) == false {
+				frame = strings.SplitN(fields[2], "+", 2)[0] + " (" + filepath.Base(strings.TrimSpace(fields[3])) + ")"
+			}
+		}
+		if unit == "" {
+			continue
+		}
+		count := strings.SplitN(lines[0], " ", 2)[0]
+		result = append(result, fmt.Sprintf("%v: %v goroutines in %v", unit, count, frame))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// skipFrame returns true if a function is part of the runtime or
+// standard library synchronization
+func skipFrame(fn string) bool {
+	for _, prefix := range skipFrames {
+		if strings.HasPrefix(fn, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// openFiles returns open file descriptors and what they refer to, or
+// nil where /proc is not available. The descriptor for reading the
+// directory is not included
+func openFiles() map[string]string {
+	dir, err := os.Open(procFiles)
+	if err != nil {
+		return nil
+	}
+	defer dir.Close()
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return nil
+	}
+	result := make(map[string]string, len(names))
+	for _, name := range names {
+		if name == fmt.Sprint(dir.Fd()) {
+			continue
+		} else if target, err := os.Readlink(filepath.Join(procFiles, name)); err == nil {
+			result[name] = target
+		}
+	}
+	return result
+}
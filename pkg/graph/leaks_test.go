@@ -0,0 +1,76 @@
+package graph_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	config "github.com/djthorpe/gopi/v3/pkg/config"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Leaky starts a goroutine and opens a file in New, and releases
+// neither
+type Leaky struct {
+	gopi.Unit
+	stop chan struct{}
+	file *os.File
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LEAKY
+
+func (this *Leaky) New(gopi.Config) error {
+	this.stop = make(chan struct{})
+	go func() {
+		<-this.stop
+	}()
+	if file, err := os.Open(os.Args[0]); err != nil {
+		return err
+	} else {
+		this.file = file
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Leaks_001(t *testing.T) {
+	app := new(Leaky)
+	g, err := graph.Create(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.New(t.Name(), nil)
+	if err := g.Define(cfg); err != nil {
+		t.Fatal(err)
+	} else if err := cfg.Parse(); err != nil {
+		t.Fatal(err)
+	} else if err := g.New(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Dispose(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The goroutine and file are reported
+	leaks := g.Leaks()
+	if len(leaks) == 0 || strings.HasPrefix(leaks[0], "*graph_test.Leaky: 1 goroutines") == false {
+		t.Error("Unexpected leaks", leaks)
+	} else if _, err := os.Stat("/proc/self/fd"); err == nil && (len(leaks) != 2 || strings.Contains(leaks[1], os.Args[0]) == false) {
+		t.Error("Unexpected leaks", leaks)
+	}
+	t.Log(leaks)
+
+	// Release the goroutine and file
+	close(app.stop)
+	app.file.Close()
+	if leaks := g.Leaks(); len(leaks) != 0 {
+		t.Error("Unexpected leaks", leaks)
+	}
+}
@@ -0,0 +1,130 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"plugin"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Environment variable with a list of plugins or directories of
+	// plugins, separated by the path list separator
+	EnvPlugins = "GOPI_PLUGINS"
+
+	// Symbol exported by a plugin with the plugin information
+	pluginSymbol = "Plugin"
+
+	// File extension for plugins in a directory
+	pluginExt = ".so"
+)
+
+var (
+	// Units registered while a plugin is loading
+	loading    bool
+	pending    [][2]reflect.Type
+	pluginLock sync.Mutex
+)
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// LoadPlugin opens a plugin and checks it was built for this version
+// of the plugin interface, then registers the units in the plugin,
+// replacing any units already registered for the same interface
+func LoadPlugin(path string) (*gopi.PluginInfo, error) {
+	pluginLock.Lock()
+	defer pluginLock.Unlock()
+
+	// Units are registered in the init function of the plugin
+	loading, pending = true, nil
+	p, err := plugin.Open(path)
+	loading = false
+	if err != nil {
+		return nil, err
+	}
+
+	// Check plugin information before registering units
+	sym, err := p.Lookup(pluginSymbol)
+	if err != nil {
+		return nil, gopi.ErrNotFound.WithPrefix(path, ": ", pluginSymbol)
+	}
+	info, err := checkPlugin(path, sym)
+	if err != nil {
+		return nil, err
+	}
+	for _, unit := range pending {
+		iface[unit[1]] = unit[0]
+	}
+
+	// Return success
+	return info, nil
+}
+
+// LoadPlugins loads plugins from a list of files and directories
+// separated by the path list separator. All plugins with a .so
+// extension are loaded from a directory
+func LoadPlugins(paths string) ([]*gopi.PluginInfo, error) {
+	var result error
+	var plugins []*gopi.PluginInfo
+
+	for _, path := range filepath.SplitList(paths) {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		files, err := pluginFiles(path)
+		if err != nil {
+			result = multierror.Append(result, err)
+			continue
+		}
+		for _, file := range files {
+			if info, err := LoadPlugin(file); err != nil {
+				result = multierror.Append(result, err)
+			} else {
+				plugins = append(plugins, info)
+			}
+		}
+	}
+
+	// Return plugins and any errors
+	return plugins, result
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// checkPlugin returns the plugin information, or an error if the
+// plugin was built for a different version of the plugin interface
+func checkPlugin(path string, sym plugin.Symbol) (*gopi.PluginInfo, error) {
+	if info, ok := sym.(*gopi.PluginInfo); ok == false {
+		return nil, gopi.ErrBadParameter.WithPrefix(path, ": ", pluginSymbol)
+	} else if info.API != gopi.PluginAPI {
+		return nil, gopi.ErrBadParameter.WithPrefix(path, ": Plugin API version ", info.API, " (expected ", gopi.PluginAPI, ")")
+	} else {
+		return info, nil
+	}
+}
+
+// pluginFiles returns a file, or the plugins in a directory sorted
+// by name
+func pluginFiles(path string) ([]string, error) {
+	if stat, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if stat.IsDir() == false {
+		return []string{path}, nil
+	} else if files, err := filepath.Glob(filepath.Join(path, "*"+pluginExt)); err != nil {
+		return nil, err
+	} else {
+		sort.Strings(files)
+		return files, nil
+	}
+}
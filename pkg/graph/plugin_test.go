@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+func Test_Plugin_001(t *testing.T) {
+	if _, err := checkPlugin("test", "plugin"); err == nil {
+		t.Error("Expected error for wrong type")
+	}
+	if _, err := checkPlugin("test", &gopi.PluginInfo{Name: "test", API: gopi.PluginAPI + 1}); err == nil {
+		t.Error("Expected error for wrong API version")
+	}
+	if info, err := checkPlugin("test", &gopi.PluginInfo{Name: "test", API: gopi.PluginAPI}); err != nil {
+		t.Error(err)
+	} else if info.Name != "test" {
+		t.Error("Unexpected plugin", info)
+	}
+}
+
+func Test_Plugin_002(t *testing.T) {
+	dir, err := ioutil.TempDir("", "plugin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// No plugins
+	if plugins, err := LoadPlugins(""); err != nil || len(plugins) != 0 {
+		t.Error("Unexpected", plugins, err)
+	}
+	if plugins, err := LoadPlugins(dir); err != nil || len(plugins) != 0 {
+		t.Error("Unexpected", plugins, err)
+	}
+
+	// Missing path and file which is not a plugin
+	file := filepath.Join(dir, "test.so")
+	if err := ioutil.WriteFile(file, []byte("test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPlugins(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected error for missing path")
+	}
+	if _, err := LoadPlugins(dir); err == nil {
+		t.Error("Expected error for invalid plugin")
+	}
+}
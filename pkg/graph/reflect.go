@@ -2,6 +2,8 @@ package graph
 
 import (
 	"reflect"
+	"strconv"
+	"strings"
 
 	gopi "github.com/djthorpe/gopi/v3"
 	multierror "github.com/hashicorp/go-multierror"
@@ -11,9 +13,24 @@ import (
 // GLOBALS
 
 var (
-	unitType   = reflect.TypeOf((*gopi.Unit)(nil)).Elem()
-	stubType   = reflect.TypeOf((*gopi.ServiceStub)(nil)).Elem()
-	loggerType = reflect.TypeOf((*gopi.Logger)(nil)).Elem()
+	unitType        = reflect.TypeOf((*gopi.Unit)(nil)).Elem()
+	stubType        = reflect.TypeOf((*gopi.ServiceStub)(nil)).Elem()
+	loggerType      = reflect.TypeOf((*gopi.Logger)(nil)).Elem()
+	configType      = reflect.TypeOf((*gopi.Config)(nil)).Elem()
+	unitManagerType = reflect.TypeOf((*gopi.UnitManager)(nil)).Elem()
+	platformType    = reflect.TypeOf((*gopi.Platform)(nil)).Elem()
+)
+
+const (
+	// Field tag which names an instance of a unit, optionally
+	// followed by an option, for example `unit:"1,optional"`
+	instanceTag = "unit"
+
+	// Option for a unit which is disabled when New fails
+	optionOptional = "optional"
+
+	// Option for a unit which is created when enabled
+	optionLazy = "lazy"
 )
 
 /////////////////////////////////////////////////////////////////////
@@ -39,6 +56,39 @@ func forEachField(unit reflect.Value, immediate bool, fn func(reflect.StructFiel
 	return result
 }
 
+// instanceForField returns the instance name from a field tag
+func instanceForField(f reflect.StructField) string {
+	tag := f.Tag.Get(instanceTag)
+	if i := strings.Index(tag, ","); i >= 0 {
+		return tag[:i]
+	} else {
+		return tag
+	}
+}
+
+// optionForField returns the option from a field tag, or an empty
+// string if there is no option
+func optionForField(f reflect.StructField) (string, error) {
+	tag := f.Tag.Get(instanceTag)
+	if i := strings.Index(tag, ","); i < 0 {
+		return "", nil
+	} else if option := tag[i+1:]; option == optionOptional || option == optionLazy {
+		return option, nil
+	} else {
+		return "", gopi.ErrBadParameter.WithPrefix("Invalid option: ", strconv.Quote(option))
+	}
+}
+
+// nameForField returns the name of a unit from the field type and
+// instance name, for example "gopi.I2C" or "gopi.I2C/1"
+func nameForField(f reflect.StructField, instance string) string {
+	if instance == "" {
+		return f.Type.String()
+	} else {
+		return f.Type.String() + "/" + instance
+	}
+}
+
 // isStructPtr returns true if the type is a pointer to a struct
 func isStructPtr(t reflect.Type) bool {
 	return t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct
@@ -60,6 +110,43 @@ func isUnitType(t reflect.Type) bool {
 	return false
 }
 
+// unitForValue returns the embedded gopi.Unit for a struct ptr,
+// or nil if there is no embedded gopi.Unit
+func unitForValue(unit reflect.Value) *gopi.Unit {
+	if isStructPtr(unit.Type()) == false {
+		return nil
+	}
+	t := unit.Elem().Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && equalsType(f.Type, unitType) {
+			return unit.Elem().Field(i).Addr().Interface().(*gopi.Unit)
+		}
+	}
+	return nil
+}
+
+// keyForUnit returns the type and instance name of a unit
+func keyForUnit(unit reflect.Value) key {
+	if u := unitForValue(unit); u == nil {
+		return key{unit.Type(), ""}
+	} else {
+		return key{unit.Type(), u.InstanceName()}
+	}
+}
+
+// argsForUnit returns arguments for calling a function on a unit,
+// replacing a configuration argument with one which qualifies flag
+// names with the instance name
+func argsForUnit(unit reflect.Value, args []reflect.Value) []reflect.Value {
+	name := keyForUnit(unit).name
+	if name == "" || len(args) != 1 || args[0].Type().Implements(configType) == false {
+		return args
+	} else {
+		return []reflect.Value{reflect.ValueOf(NewConfig(args[0].Interface().(gopi.Config), name))}
+	}
+}
+
 // isLoggerType returns true if a struct ptr is a gopi.Logger
 func isLoggerType(t reflect.Type) bool {
 	if isStructPtr(t) == false {
@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"reflect"
+	"time"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Startup is the result of calling New for a unit
+type Startup struct {
+	Unit     string        `json:"unit"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Startup returns the result of calling New for each unit, in the
+// order they were called
+func (this *graph) Startup() []Startup {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return append([]Startup{}, this.startup...)
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// started records the result of calling New for a unit, and is called
+// with the lock held
+func (this *graph) started(unit reflect.Value, d time.Duration, err error) {
+	result := Startup{Unit: keyForUnit(unit).String(), Duration: d}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	this.startup = append(this.startup, result)
+}
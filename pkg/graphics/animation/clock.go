@@ -0,0 +1,221 @@
+package animation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type clock struct {
+	gopi.Unit
+	sync.Mutex
+
+	fps    *uint
+	busy   sync.Mutex
+	anim   []*animation
+	paused bool
+	scale  float32
+	now    time.Duration
+	last   time.Time
+	vsync  time.Time
+}
+
+type animation struct {
+	fn          gopi.AnimationFunc
+	start, last time.Duration
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// The number of frame periods without vertical sync before
+	// the ticker is used
+	vsyncFrames = 2
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *clock) Define(cfg gopi.Config) error {
+	this.fps = cfg.FlagUint("animation.fps", 60, "Frame rate without vertical sync, or zero to disable")
+	return nil
+}
+
+func (this *clock) New(gopi.Config) error {
+	this.scale = 1.0
+	return nil
+}
+
+func (this *clock) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Release resources
+	this.anim = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *clock) Run(ctx context.Context) error {
+	if *this.fps == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	period := time.Second / time.Duration(*this.fps)
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			// Only use the ticker when there is no vertical sync
+			this.Mutex.Lock()
+			vsync := this.vsync
+			this.Mutex.Unlock()
+			if now.Sub(vsync) > period*vsyncFrames {
+				this.frame(now)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *clock) String() string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	str := "<animation.clock"
+	if this.fps != nil {
+		str += fmt.Sprint(" fps=", *this.fps)
+	}
+	str += fmt.Sprint(" animations=", len(this.anim))
+	str += fmt.Sprint(" scale=", this.scale)
+	if this.paused {
+		str += " paused"
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *clock) Animate(fn gopi.AnimationFunc) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if fn == nil {
+		return gopi.ErrBadParameter.WithPrefix("Animate")
+	}
+
+	// Animation starts at the current time
+	this.anim = append(this.anim, &animation{fn, this.now, this.now})
+
+	// Return success
+	return nil
+}
+
+func (this *clock) Frame(t time.Time) {
+	this.Mutex.Lock()
+	this.vsync = t
+	this.Mutex.Unlock()
+
+	this.frame(t)
+}
+
+func (this *clock) Pause(paused bool) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.paused = paused
+}
+
+func (this *clock) Paused() bool {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.paused
+}
+
+func (this *clock) SetScale(scale float32) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if scale <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("SetScale")
+	} else {
+		this.scale = scale
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *clock) Scale() float32 {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.scale
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// frame advances animation time and calls each animation, removing
+// those which have ended. Animations are called without holding the
+// lock, so that they can add further animations
+func (this *clock) frame(t time.Time) {
+	this.busy.Lock()
+	defer this.busy.Unlock()
+
+	// Advance time unless paused
+	this.Mutex.Lock()
+	delta := time.Duration(0)
+	if this.last.IsZero() == false && t.After(this.last) {
+		delta = t.Sub(this.last)
+	}
+	this.last = t
+	if this.paused {
+		this.Mutex.Unlock()
+		return
+	}
+	this.now += time.Duration(float64(delta) * float64(this.scale))
+	now := this.now
+	anim := make([]*animation, len(this.anim))
+	copy(anim, this.anim)
+	this.Mutex.Unlock()
+
+	// Call animations
+	ended := make(map[*animation]bool)
+	for _, a := range anim {
+		if a.fn(now-a.start, now-a.last) == false {
+			ended[a] = true
+		}
+		a.last = now
+	}
+
+	// Remove animations which have ended
+	if len(ended) > 0 {
+		this.Mutex.Lock()
+		defer this.Mutex.Unlock()
+		for i := 0; i < len(this.anim); i++ {
+			if ended[this.anim[i]] {
+				this.anim = append(this.anim[:i], this.anim[i+1:]...)
+				i--
+			}
+		}
+	}
+}
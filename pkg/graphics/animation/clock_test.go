@@ -0,0 +1,80 @@
+package animation_test
+
+import (
+	"testing"
+	"time"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	// Units
+	_ "github.com/djthorpe/gopi/v3/pkg/graphics/animation"
+)
+
+type App struct {
+	gopi.Unit
+	gopi.AnimationClock
+}
+
+func Test_Clock_001(t *testing.T) {
+	tool.Test(t, []string{"-animation.fps=0"}, new(App), func(app *App) {
+		if app.AnimationClock == nil {
+			t.Error("Unexpected nil AnimationClock")
+		} else {
+			t.Log(app.AnimationClock)
+		}
+	})
+}
+
+func Test_Clock_002(t *testing.T) {
+	tool.Test(t, []string{"-animation.fps=0"}, new(App), func(app *App) {
+		var elapsed, delta []time.Duration
+		if err := app.Animate(func(e, d time.Duration) bool {
+			elapsed, delta = append(elapsed, e), append(delta, d)
+			return len(elapsed) < 3
+		}); err != nil {
+			t.Error(err)
+		}
+
+		// Call four frames, animation ends after the third
+		ts := time.Now()
+		for i := 0; i < 4; i++ {
+			app.Frame(ts.Add(time.Duration(i) * 10 * time.Millisecond))
+		}
+		if len(elapsed) != 3 {
+			t.Error("Unexpected number of frames", len(elapsed))
+		} else if elapsed[0] != 0 || elapsed[1] != 10*time.Millisecond || elapsed[2] != 20*time.Millisecond {
+			t.Error("Unexpected elapsed times", elapsed)
+		} else if delta[0] != 0 || delta[1] != 10*time.Millisecond || delta[2] != 10*time.Millisecond {
+			t.Error("Unexpected delta times", delta)
+		}
+	})
+}
+
+func Test_Clock_003(t *testing.T) {
+	tool.Test(t, []string{"-animation.fps=0"}, new(App), func(app *App) {
+		var elapsed time.Duration
+		app.Animate(func(e, d time.Duration) bool {
+			elapsed = e
+			return true
+		})
+
+		// Paused time does not pass, and scaled time passes twice as fast
+		ts := time.Now()
+		app.Frame(ts)
+		app.Pause(true)
+		app.Frame(ts.Add(time.Second))
+		app.Pause(false)
+		if err := app.SetScale(2); err != nil {
+			t.Error(err)
+		}
+		app.Frame(ts.Add(time.Second + 10*time.Millisecond))
+		if elapsed != 20*time.Millisecond {
+			t.Error("Unexpected elapsed time", elapsed)
+		}
+		if err := app.SetScale(0); err == nil {
+			t.Error("Expected error for zero scale")
+		}
+	})
+}
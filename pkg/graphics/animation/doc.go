@@ -0,0 +1,9 @@
+// Animation package implements gopi.AnimationClock, which calls animations
+// once per frame with the elapsed time and the time since the previous
+// frame, so that animating components share a single frame loop.
+//
+// The surface manager calls Frame on each vertical sync. When no frames
+// are delivered, the clock calls animations using its own ticker at the
+// rate set by the -animation.fps flag. Time for all animations can be
+// paused or scaled.
+package animation
@@ -0,0 +1,12 @@
+package animation
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&clock{}), reflect.TypeOf((*gopi.AnimationClock)(nil)))
+}
@@ -0,0 +1,314 @@
+package barcode
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	errTooManyErrors = gopi.ErrUnexpectedResponse.WithPrefix("Too many errors")
+	errFormat        = gopi.ErrUnexpectedResponse.WithPrefix("Invalid format")
+	errData          = gopi.ErrUnexpectedResponse.WithPrefix("Invalid data")
+)
+
+const (
+	// Maximum number of bit errors in format information
+	qrMaxFormatErrors = 3
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// DecodeQRCode decodes a grid of modules, where true is dark and the grid
+// is indexed by row and then column. Errors are corrected and the
+// payload returned with the version and error correction level
+func DecodeQRCode(modules [][]bool) ([]byte, *QRCode, error) {
+	size := len(modules)
+	if size < 21 || size > 177 || (size-17)%4 != 0 {
+		return nil, nil, gopi.ErrBadParameter.WithPrefix("DecodeQRCode: Invalid size")
+	}
+	for _, row := range modules {
+		if len(row) != size {
+			return nil, nil, gopi.ErrBadParameter.WithPrefix("DecodeQRCode: Invalid size")
+		}
+	}
+
+	// Create symbol with sampled modules
+	this := new(QRCode)
+	this.version = (size - 17) / 4
+	this.size = size
+	this.modules = make([]bool, size*size)
+	this.fn = make([]bool, size*size)
+	for y, row := range modules {
+		for x, dark := range row {
+			this.set(x, y, dark)
+		}
+	}
+
+	// Read format information, then mark function modules. Marking
+	// function modules overwrites them, so restore modules afterwards
+	level, mask, err := this.readFormat()
+	if err != nil {
+		return nil, nil, fmt.Errorf("DecodeQRCode: %w", err)
+	}
+	this.level = level
+	sampled := append([]bool{}, this.modules...)
+	this.drawFunctionPatterns()
+	copy(this.modules, sampled)
+
+	// Unmask, read and correct codewords
+	this.applyMask(mask)
+	data, err := qrCorrectEcc(this.readCodewords(), this.version, level)
+	if err != nil {
+		return nil, nil, fmt.Errorf("DecodeQRCode: %w", err)
+	}
+	this.applyMask(mask)
+
+	// Decode segments
+	payload, err := qrDecodeData(data, this.version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("DecodeQRCode: %w", err)
+	}
+
+	// Return success
+	return payload, this, nil
+}
+
+// Level returns the error correction level
+func (this *QRCode) Level() gopi.QRLevel {
+	return this.level
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// readFormat reads both copies of the format information and returns
+// the level and mask for the closest valid format
+func (this *QRCode) readFormat() (gopi.QRLevel, int, error) {
+	var a, b uint32
+	for i := 0; i <= 5; i++ {
+		a |= this.bit(8, i) << i
+	}
+	a |= this.bit(8, 7) << 6
+	a |= this.bit(8, 8) << 7
+	a |= this.bit(7, 8) << 8
+	for i := 9; i < 15; i++ {
+		a |= this.bit(14-i, 8) << i
+	}
+	for i := 0; i < 8; i++ {
+		b |= this.bit(this.size-1-i, 8) << i
+	}
+	for i := 8; i < 15; i++ {
+		b |= this.bit(8, this.size-15+i) << i
+	}
+
+	// Find the closest format
+	best, level, mask := qrMaxFormatErrors+1, gopi.QRLevel(0), 0
+	for l := gopi.QRLevel(0); l <= gopi.QR_LEVEL_MAX; l++ {
+		for m := 0; m < 8; m++ {
+			format := qrFormat(l, m)
+			for _, bits := range []uint32{a, b} {
+				if d := bitCount(format ^ bits); d < best {
+					best, level, mask = d, l, m
+				}
+			}
+		}
+	}
+	if best > qrMaxFormatErrors {
+		return 0, 0, errFormat
+	}
+	return level, mask, nil
+}
+
+func (this *QRCode) bit(x, y int) uint32 {
+	if this.modules[y*this.size+x] {
+		return 1
+	} else {
+		return 0
+	}
+}
+
+// readCodewords reads codewords in the same order as drawCodewords
+func (this *QRCode) readCodewords() []byte {
+	result := make([]byte, qrNumRawDataModules(this.version)/8)
+	i := 0
+	for right := this.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < this.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = this.size - 1 - vert
+				}
+				if this.fn[y*this.size+x] == false && i < len(result)*8 {
+					if this.modules[y*this.size+x] {
+						result[i>>3] |= 1 << uint(7-(i&7))
+					}
+					i++
+				}
+			}
+		}
+	}
+	return result
+}
+
+// qrCorrectEcc de-interleaves codewords into blocks, corrects errors in
+// each block and returns the data codewords
+func qrCorrectEcc(codewords []byte, version int, level gopi.QRLevel) ([]byte, error) {
+	numBlocks := qrEccBlocks[level][version]
+	eccLen := qrEccCodewords[level][version]
+	raw := qrNumRawDataModules(version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	// De-interleave, where short blocks have a placeholder byte
+	blocks := make([][]byte, numBlocks)
+	for i := range blocks {
+		blocks[i] = make([]byte, shortLen+1)
+	}
+	k := 0
+	for i := 0; i <= shortLen; i++ {
+		for j := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				blocks[j][i] = codewords[k]
+				k++
+			}
+		}
+	}
+
+	// Correct each block and append data codewords
+	result := make([]byte, 0, raw-numBlocks*eccLen)
+	for i, block := range blocks {
+		if i < numShort {
+			block = append(block[:shortLen-eccLen], block[shortLen-eccLen+1:]...)
+		}
+		if _, err := rsCorrect(block, eccLen); err != nil {
+			return nil, err
+		}
+		result = append(result, block[:len(block)-eccLen]...)
+	}
+
+	// Return success
+	return result, nil
+}
+
+// qrDecodeData decodes numeric, alphanumeric, byte and kanji segments.
+// Extended channel interpretation and structured append headers are
+// skipped, and kanji is returned as Shift JIS bytes
+func qrDecodeData(data []byte, version int) ([]byte, error) {
+	r := &bitreader{data: data}
+	result := []byte{}
+	for r.remaining() >= 4 {
+		switch r.read(4) {
+		case 0x0:
+			return result, nil
+		case qrModeNumeric.indicator:
+			for n := r.read(qrModeNumeric.countBits(version)); n > 0; {
+				digits := minInt(int(n), 3)
+				v := r.read(digits*3 + 1)
+				if r.eof {
+					return nil, errData
+				}
+				result = append(result, fmt.Sprintf("%0*d", digits, v)...)
+				n -= uint32(digits)
+			}
+		case qrModeAlphanum.indicator:
+			for n := r.read(qrModeAlphanum.countBits(version)); n > 0; {
+				if n >= 2 {
+					v := r.read(11)
+					if v >= 45*45 {
+						return nil, errData
+					}
+					result = append(result, qrAlphanum[v/45], qrAlphanum[v%45])
+					n -= 2
+				} else {
+					v := r.read(6)
+					if v >= 45 {
+						return nil, errData
+					}
+					result = append(result, qrAlphanum[v])
+					n--
+				}
+			}
+		case qrModeByte.indicator:
+			for n := r.read(qrModeByte.countBits(version)); n > 0; n-- {
+				result = append(result, byte(r.read(8)))
+			}
+		case qrModeKanji.indicator:
+			for n := r.read(qrModeKanji.countBits(version)); n > 0; n-- {
+				v := r.read(13)
+				v = (v/0xC0)<<8 | v%0xC0
+				if v < 0x1F00 {
+					v += 0x8140
+				} else {
+					v += 0xC140
+				}
+				result = append(result, byte(v>>8), byte(v))
+			}
+		case qrModeECI:
+			if r.read(1) == 1 {
+				if r.read(1) == 0 {
+					r.read(14)
+				} else {
+					r.read(22)
+				}
+			} else {
+				r.read(7)
+			}
+		case qrModeAppend:
+			r.read(16)
+		default:
+			return nil, errData
+		}
+		if r.eof {
+			return nil, errData
+		}
+	}
+
+	// Return success
+	return result, nil
+}
+
+func bitCount(v uint32) int {
+	n := 0
+	for ; v != 0; v &= v - 1 {
+		n++
+	}
+	return n
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// BIT READER
+
+type bitreader struct {
+	data []byte
+	pos  int
+	eof  bool
+}
+
+func (this *bitreader) remaining() int {
+	return len(this.data)*8 - this.pos
+}
+
+// read returns n bits, most significant bit first, and sets eof when
+// there are not enough bits remaining
+func (this *bitreader) read(n int) uint32 {
+	if n > this.remaining() {
+		this.pos, this.eof = len(this.data)*8, true
+		return 0
+	}
+	v := uint32(0)
+	for i := 0; i < n; i++ {
+		b := this.data[this.pos>>3] >> uint(7-(this.pos&7)) & 1
+		v = v<<1 | uint32(b)
+		this.pos++
+	}
+	return v
+}
@@ -0,0 +1,95 @@
+package barcode
+
+import (
+	"bytes"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Decode_001(t *testing.T) {
+	// Correct errors up to half the error correction codewords
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236}
+	block := append(append([]byte{}, data...), rsRemainder(data, rsDivisor(13))...)
+	for errors := 0; errors <= 6; errors++ {
+		corrupt := append([]byte{}, block...)
+		for i := 0; i < errors; i++ {
+			corrupt[i*4] ^= byte(0x5A + i)
+		}
+		if n, err := rsCorrect(corrupt, 13); err != nil {
+			t.Error(errors, err)
+		} else if n != errors {
+			t.Error("Expected", errors, "errors, got", n)
+		} else if bytes.Equal(corrupt, block) == false {
+			t.Error("Unexpected block", corrupt)
+		}
+	}
+	corrupt := append([]byte{}, block...)
+	for i := 0; i < 8; i++ {
+		corrupt[i] ^= 0xFF
+	}
+	if _, err := rsCorrect(corrupt, 13); err == nil {
+		t.Error("Expected error for too many errors")
+	}
+}
+
+func Test_Decode_002(t *testing.T) {
+	tests := []struct {
+		data  string
+		level gopi.QRLevel
+	}{
+		{"HELLO WORLD", gopi.QR_LEVEL_Q},
+		{"0123456789012", gopi.QR_LEVEL_L},
+		{"https://github.com/djthorpe/gopi", gopi.QR_LEVEL_M},
+		{string(bytes.Repeat([]byte("Provisioning "), 20)), gopi.QR_LEVEL_H},
+	}
+	for _, test := range tests {
+		code, err := NewQRCode(test.data, test.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		modules := modules(code)
+
+		// Flip modules in the data region
+		for i := 0; i < 3; i++ {
+			modules[code.Size()-1-i][code.Size()-1] = !modules[code.Size()-1-i][code.Size()-1]
+		}
+		if payload, decoded, err := DecodeQRCode(modules); err != nil {
+			t.Error(test.data, err)
+		} else if string(payload) != test.data {
+			t.Errorf("Expected %q, got %q", test.data, payload)
+		} else if decoded.Version() != code.Version() || decoded.Level() != test.level {
+			t.Error("Unexpected version or level", decoded)
+		}
+	}
+}
+
+func Test_Decode_003(t *testing.T) {
+	if _, _, err := DecodeQRCode(make([][]bool, 22)); err == nil {
+		t.Error("Expected error for invalid size")
+	}
+	blank := make([][]bool, 21)
+	for i := range blank {
+		blank[i] = make([]bool, 21)
+	}
+	if _, _, err := DecodeQRCode(blank); err == nil {
+		t.Error("Expected error for blank modules")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func modules(code *QRCode) [][]bool {
+	result := make([][]bool, code.Size())
+	for y := range result {
+		result[y] = make([]bool, code.Size())
+		for x := range result[y] {
+			result[y][x] = code.At(x, y)
+		}
+	}
+	return result
+}
@@ -0,0 +1,8 @@
+// Barcode package encodes QR codes and linear barcodes (Code 128,
+// EAN-13 and EAN-8) and paints them onto bitmaps, so that devices can
+// show pairing URLs and asset labels on attached displays. Bitmaps
+// implement PaintQRCode and PaintBarcode using this package.
+//
+// QR codes can also be detected and decoded in images such as camera
+// frames using ScanQRCodes, with errors corrected.
+package barcode
@@ -0,0 +1,190 @@
+package barcode
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// Code 128 bar and space widths for each value, where values 103,
+	// 104 and 105 are the start codes for code sets A, B and C
+	code128Widths = [106]string{
+		"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+		"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+		"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+		"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+		"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+		"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+		"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+		"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+		"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+		"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+		"114131", "311141", "411131", "211412", "211214", "211232",
+	}
+	code128Stop = "2331112"
+
+	// EAN digit patterns for left odd parity, where right patterns are
+	// the inverse and left even parity patterns are the reversed right
+	// patterns
+	eanLeft = [10]string{
+		"0001101", "0011001", "0010011", "0111101", "0100011",
+		"0110001", "0101111", "0111011", "0110111", "0001011",
+	}
+
+	// EAN-13 parity of the left digits for the first digit, where one
+	// is even parity
+	ean13Parity = [10]string{
+		"000000", "001011", "001101", "001110", "010011",
+		"011001", "011100", "010101", "010110", "011010",
+	}
+)
+
+const (
+	code128StartB = 104
+	code128StartC = 105
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// EncodeBarcode returns the modules for a linear barcode, where true is
+// a bar. Quiet zones are not included
+func EncodeBarcode(data string, t gopi.BarcodeType) ([]bool, error) {
+	switch t {
+	case gopi.BARCODE_CODE128:
+		return encodeCode128(data)
+	case gopi.BARCODE_EAN13:
+		return encodeEAN(data, 12)
+	case gopi.BARCODE_EAN8:
+		return encodeEAN(data, 7)
+	default:
+		return nil, gopi.ErrBadParameter.WithPrefix("EncodeBarcode")
+	}
+}
+
+// EANCheckDigit returns the check digit for EAN digits, where the
+// rightmost digit is weighted by three
+func EANCheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		weight := 1
+		if (len(digits)-i)%2 == 1 {
+			weight = 3
+		}
+		sum += int(digits[i]-'0') * weight
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// encodeCode128 uses code set C for an even number of digits, and code
+// set B otherwise
+func encodeCode128(data string) ([]bool, error) {
+	if data == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("Code128")
+	}
+	values := []int{}
+	if isNumeric(data) && len(data)%2 == 0 {
+		values = append(values, code128StartC)
+		for i := 0; i < len(data); i += 2 {
+			values = append(values, int(data[i]-'0')*10+int(data[i+1]-'0'))
+		}
+	} else {
+		values = append(values, code128StartB)
+		for i := 0; i < len(data); i++ {
+			if data[i] < 32 || data[i] > 127 {
+				return nil, gopi.ErrBadParameter.WithPrefix("Code128: ", data)
+			}
+			values = append(values, int(data[i])-32)
+		}
+	}
+
+	// Append checksum
+	sum := values[0]
+	for i, v := range values[1:] {
+		sum += v * (i + 1)
+	}
+	values = append(values, sum%103)
+
+	// Return bars and spaces
+	result := []bool{}
+	for _, v := range values {
+		result = appendWidths(result, code128Widths[v])
+	}
+	return appendWidths(result, code128Stop), nil
+}
+
+// encodeEAN encodes digits with an optional check digit, which is
+// verified if present
+func encodeEAN(data string, n int) ([]bool, error) {
+	if isNumeric(data) == false || (len(data) != n && len(data) != n+1) {
+		return nil, gopi.ErrBadParameter.WithPrefix("EAN: ", data)
+	}
+	check := EANCheckDigit(data[:n])
+	if len(data) == n {
+		data += string(check)
+	} else if data[n] != check {
+		return nil, gopi.ErrBadParameter.WithPrefix("EAN: Invalid check digit: ", data)
+	}
+
+	// EAN-13 encodes the first digit in the parity of the left digits
+	parity := "0000"
+	if n == 12 {
+		parity = ean13Parity[data[0]-'0']
+		data = data[1:]
+	}
+	half := len(data) / 2
+
+	// Guards and digits
+	result := appendModules(nil, "101")
+	for i := 0; i < half; i++ {
+		pattern := eanLeft[data[i]-'0']
+		if parity[i] == '1' {
+			pattern = reverse(invert(pattern))
+		}
+		result = appendModules(result, pattern)
+	}
+	result = appendModules(result, "01010")
+	for i := half; i < len(data); i++ {
+		result = appendModules(result, invert(eanLeft[data[i]-'0']))
+	}
+	return appendModules(result, "101"), nil
+}
+
+// appendWidths appends alternating bars and spaces, starting with a bar
+func appendWidths(modules []bool, widths string) []bool {
+	for i := 0; i < len(widths); i++ {
+		for j := byte(0); j < widths[i]-'0'; j++ {
+			modules = append(modules, i%2 == 0)
+		}
+	}
+	return modules
+}
+
+// appendModules appends bars for ones and spaces for zeros
+func appendModules(modules []bool, pattern string) []bool {
+	for i := 0; i < len(pattern); i++ {
+		modules = append(modules, pattern[i] == '1')
+	}
+	return modules
+}
+
+func invert(pattern string) string {
+	result := []byte(pattern)
+	for i := range result {
+		result[i] ^= 1
+	}
+	return string(result)
+}
+
+func reverse(pattern string) string {
+	result := []byte(pattern)
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return string(result)
+}
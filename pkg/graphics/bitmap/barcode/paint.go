@@ -0,0 +1,101 @@
+package barcode
+
+import (
+	"image"
+	"image/color"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Quiet zone around a QR code and either side of a barcode, in modules
+	qrQuietZone      = 4
+	barcodeQuietZone = 10
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// PaintQRCode paints a QR code in black on white, centred within
+// bounds with a quiet zone. Each module is painted as a square of whole
+// pixels, so the bounds need to be at least the size of the QR code
+// including quiet zone
+func PaintQRCode(dst gopi.Bitmap, data string, level gopi.QRLevel, bounds image.Rectangle) error {
+	code, err := NewQRCode(data, level)
+	if err != nil {
+		return err
+	}
+	n := code.Size() + qrQuietZone*2
+	scale := minInt(bounds.Dx(), bounds.Dy()) / n
+	if scale == 0 {
+		return gopi.ErrBadParameter.WithPrefix("PaintQRCode: Bounds too small")
+	}
+
+	// Paint modules, including quiet zone
+	origin := bounds.Min.Add(image.Pt(bounds.Dx()-n*scale, bounds.Dy()-n*scale).Div(2))
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			c := color.White
+			if code.At(x-qrQuietZone, y-qrQuietZone) {
+				c = color.Black
+			}
+			r := image.Rect(x*scale, y*scale, (x+1)*scale, (y+1)*scale).Add(origin)
+			if err := fill(dst, r, c); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// PaintBarcode paints a linear barcode in black on white, centred
+// horizontally within bounds with quiet zones and filling the height of
+// the bounds. Each module is painted with a whole number of pixels
+func PaintBarcode(dst gopi.Bitmap, data string, t gopi.BarcodeType, bounds image.Rectangle) error {
+	modules, err := EncodeBarcode(data, t)
+	if err != nil {
+		return err
+	}
+	n := len(modules) + barcodeQuietZone*2
+	scale := bounds.Dx() / n
+	if scale == 0 || bounds.Dy() <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("PaintBarcode: Bounds too small")
+	}
+
+	// Paint background and then bars
+	origin := bounds.Min.Add(image.Pt((bounds.Dx()-n*scale)/2, 0))
+	if err := fill(dst, image.Rect(0, 0, n*scale, bounds.Dy()).Add(origin), color.White); err != nil {
+		return err
+	}
+	for x, bar := range modules {
+		if bar == false {
+			continue
+		}
+		r := image.Rect((x+barcodeQuietZone)*scale, 0, (x+barcodeQuietZone+1)*scale, bounds.Dy()).Add(origin)
+		if err := fill(dst, r, color.Black); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func fill(dst gopi.Bitmap, r image.Rectangle, c color.Color) error {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if err := dst.SetAt(c, x, y); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
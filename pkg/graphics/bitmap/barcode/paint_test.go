@@ -0,0 +1,62 @@
+package barcode_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	rgba32 "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Paint_001(t *testing.T) {
+	dst, err := new(rgba32.Factory).New(bitmap.GetColorModel(gopi.SURFACE_FMT_RGBA32), 100, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Version 1 is 29 modules with quiet zone, so three pixels per module
+	if err := dst.PaintQRCode("HELLO WORLD", gopi.QR_LEVEL_Q, image.Rect(0, 0, 100, 100)); err != nil {
+		t.Fatal(err)
+	}
+	// Top left finder pattern starts after quiet zone and margin
+	if c := Gray(dst, 6+12, 6+12); c != 0 {
+		t.Error("Expected dark module, got", c)
+	} else if c := Gray(dst, 6+11, 6+11); c != 0xFF {
+		t.Error("Expected light module, got", c)
+	} else if c := Gray(dst, 0, 0); c != 0 {
+		t.Error("Expected pixel outside bounds to be unchanged")
+	}
+	if err := dst.PaintQRCode("HELLO WORLD", gopi.QR_LEVEL_Q, image.Rect(0, 0, 28, 28)); err == nil {
+		t.Error("Expected error for small bounds")
+	}
+}
+
+func Test_Paint_002(t *testing.T) {
+	dst, err := new(rgba32.Factory).New(bitmap.GetColorModel(gopi.SURFACE_FMT_RGBA32), 120, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// EAN-8 is 67 modules with 20 modules of quiet zone, one pixel per module
+	if err := dst.PaintBarcode("9638507", gopi.BARCODE_EAN8, image.Rect(0, 10, 120, 20)); err != nil {
+		t.Fatal(err)
+	}
+	left := (120 - 87) / 2
+	if c := Gray(dst, left+10, 15); c != 0 {
+		t.Error("Expected guard bar, got", c)
+	} else if c := Gray(dst, left+11, 15); c != 0xFF {
+		t.Error("Expected guard space, got", c)
+	} else if c := Gray(dst, left+10, 5); c != 0 {
+		t.Error("Expected pixel outside bounds to be unchanged")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func Gray(bitmap gopi.Bitmap, x, y int) uint8 {
+	return color.GrayModel.Convert(bitmap.At(x, y)).(color.Gray).Y
+}
@@ -0,0 +1,541 @@
+package barcode
+
+import (
+	"fmt"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// QRCode is a square grid of modules, where true is dark
+type QRCode struct {
+	version int
+	level   gopi.QRLevel
+	size    int
+	modules []bool
+	fn      []bool
+}
+
+type qrmode struct {
+	indicator uint32
+	bits      [3]int // Character count bits for versions 1-9, 10-26, 27-40
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	qrMinVersion = 1
+	qrMaxVersion = 40
+	qrAlphanum   = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+	qrModeECI    = 0x7
+	qrModeAppend = 0x3
+)
+
+var (
+	qrModeNumeric  = qrmode{0x1, [3]int{10, 12, 14}}
+	qrModeAlphanum = qrmode{0x2, [3]int{9, 11, 13}}
+	qrModeByte     = qrmode{0x4, [3]int{8, 16, 16}}
+	qrModeKanji    = qrmode{0x8, [3]int{8, 10, 12}}
+)
+
+var (
+	// Error correction codewords per block, indexed by level and version
+	qrEccCodewords = [4][41]int{
+		{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+		{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	}
+	// Error correction blocks, indexed by level and version
+	qrEccBlocks = [4][41]int{
+		{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+		{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+		{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+		{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+	}
+	// Format bits for each level
+	qrFormatBits = [4]uint32{1, 0, 3, 2}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// NewQRCode encodes data as a QR code using the smallest version for the
+// error correction level. Numeric or alphanumeric encoding is used when
+// all characters allow it, otherwise data is encoded as bytes
+func NewQRCode(data string, level gopi.QRLevel) (*QRCode, error) {
+	if level > gopi.QR_LEVEL_MAX {
+		return nil, gopi.ErrBadParameter.WithPrefix("NewQRCode")
+	}
+
+	// Choose mode and encode data
+	mode, bits := qrEncodeData(data)
+
+	// Choose the smallest version which fits the data
+	version := 0
+	for v := qrMinVersion; v <= qrMaxVersion; v++ {
+		if 4+mode.countBits(v)+len(bits) <= qrNumDataCodewords(v, level)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("NewQRCode: Data too long")
+	}
+
+	// Segment header, data, terminator and padding
+	var buf bitbuf
+	buf.append(mode.indicator, 4)
+	buf.append(uint32(len(data)), mode.countBits(version))
+	buf = append(buf, bits...)
+	capacity := qrNumDataCodewords(version, level) * 8
+	buf.append(0, minInt(4, capacity-len(buf)))
+	buf.append(0, (8-len(buf)%8)%8)
+	for pad := uint32(0xEC); len(buf) < capacity; pad ^= 0xEC ^ 0x11 {
+		buf.append(pad, 8)
+	}
+
+	// Create the symbol
+	this := new(QRCode)
+	this.version = version
+	this.level = level
+	this.size = version*4 + 17
+	this.modules = make([]bool, this.size*this.size)
+	this.fn = make([]bool, this.size*this.size)
+	this.drawFunctionPatterns()
+	this.drawCodewords(qrAddEcc(buf.bytes(), version, level))
+
+	// Choose the mask with the lowest penalty
+	mask, penalty := 0, -1
+	for m := 0; m < 8; m++ {
+		this.applyMask(m)
+		this.drawFormatBits(m)
+		if p := this.penalty(); penalty < 0 || p < penalty {
+			mask, penalty = m, p
+		}
+		this.applyMask(m)
+	}
+	this.applyMask(mask)
+	this.drawFormatBits(mask)
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+// Size returns the number of modules in each row and column
+func (this *QRCode) Size() int {
+	return this.size
+}
+
+// Version returns the QR code version between 1 and 40
+func (this *QRCode) Version() int {
+	return this.version
+}
+
+// At returns true if a module is dark, or false if it is light or
+// outside the symbol
+func (this *QRCode) At(x, y int) bool {
+	if x < 0 || y < 0 || x >= this.size || y >= this.size {
+		return false
+	}
+	return this.modules[y*this.size+x]
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *QRCode) String() string {
+	str := "<barcode.qrcode"
+	str += fmt.Sprint(" version=", this.version)
+	str += fmt.Sprint(" level=", this.level)
+	str += fmt.Sprint(" size=", this.size)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS - DATA
+
+func qrEncodeData(data string) (qrmode, bitbuf) {
+	var buf bitbuf
+	if isNumeric(data) {
+		for i := 0; i < len(data); i += 3 {
+			n := minInt(3, len(data)-i)
+			v := uint32(0)
+			for _, ch := range data[i : i+n] {
+				v = v*10 + uint32(ch-'0')
+			}
+			buf.append(v, n*3+1)
+		}
+		return qrModeNumeric, buf
+	} else if isAlphanumeric(data) {
+		for i := 0; i < len(data); i += 2 {
+			v := uint32(strings.IndexByte(qrAlphanum, data[i]))
+			if i+1 < len(data) {
+				buf.append(v*45+uint32(strings.IndexByte(qrAlphanum, data[i+1])), 11)
+			} else {
+				buf.append(v, 6)
+			}
+		}
+		return qrModeAlphanum, buf
+	} else {
+		for i := 0; i < len(data); i++ {
+			buf.append(uint32(data[i]), 8)
+		}
+		return qrModeByte, buf
+	}
+}
+
+func (m qrmode) countBits(version int) int {
+	switch {
+	case version <= 9:
+		return m.bits[0]
+	case version <= 26:
+		return m.bits[1]
+	default:
+		return m.bits[2]
+	}
+}
+
+func isNumeric(data string) bool {
+	for i := 0; i < len(data); i++ {
+		if data[i] < '0' || data[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(data string) bool {
+	for i := 0; i < len(data); i++ {
+		if strings.IndexByte(qrAlphanum, data[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// qrNumRawDataModules returns the number of modules available for data
+// and error correction in a version
+func qrNumRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		result -= (25*align-10)*align - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func qrNumDataCodewords(version int, level gopi.QRLevel) int {
+	return qrNumRawDataModules(version)/8 - qrEccCodewords[level][version]*qrEccBlocks[level][version]
+}
+
+// qrAddEcc splits data into blocks, appends error correction codewords
+// to each block and interleaves the blocks
+func qrAddEcc(data []byte, version int, level gopi.QRLevel) []byte {
+	numBlocks := qrEccBlocks[level][version]
+	eccLen := qrEccCodewords[level][version]
+	raw := qrNumRawDataModules(version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	// Compute blocks, where short blocks have a placeholder byte
+	divisor := rsDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		block := append([]byte{}, data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(block, divisor)
+		if i < numShort {
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	// Interleave, skipping placeholders
+	result := make([]byte, 0, raw)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS - MODULES
+
+func (this *QRCode) set(x, y int, dark bool) {
+	this.modules[y*this.size+x] = dark
+}
+
+func (this *QRCode) setFunction(x, y int, dark bool) {
+	this.modules[y*this.size+x] = dark
+	this.fn[y*this.size+x] = true
+}
+
+func (this *QRCode) drawFunctionPatterns() {
+	// Timing patterns
+	for i := 0; i < this.size; i++ {
+		this.setFunction(6, i, i%2 == 0)
+		this.setFunction(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns with separators
+	for _, c := range [][2]int{{3, 3}, {this.size - 4, 3}, {3, this.size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x < 0 || y < 0 || x >= this.size || y >= this.size {
+					continue
+				}
+				dist := maxInt(absInt(dx), absInt(dy))
+				this.setFunction(x, y, dist != 2 && dist != 4)
+			}
+		}
+	}
+
+	// Alignment patterns, except where they overlap finder patterns
+	pos := this.alignmentPositions()
+	for i := range pos {
+		for j := range pos {
+			if (i == 0 && j == 0) || (i == 0 && j == len(pos)-1) || (i == len(pos)-1 && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					this.setFunction(pos[i]+dx, pos[j]+dy, maxInt(absInt(dx), absInt(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve format bits and draw version
+	this.drawFormatBits(0)
+	this.drawVersion()
+}
+
+func (this *QRCode) alignmentPositions() []int {
+	if this.version == 1 {
+		return nil
+	}
+	num := this.version/7 + 2
+	step := (this.version*4 + num*2 + 1) / (num*2 - 2) * 2
+	if this.version == 32 {
+		step = 26
+	}
+	result := make([]int, num)
+	result[0] = 6
+	for i, pos := num-1, this.size-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+func (this *QRCode) drawFormatBits(mask int) {
+	bits := qrFormat(this.level, mask)
+
+	// First copy
+	for i := 0; i <= 5; i++ {
+		this.setFunction(8, i, bit(bits, i))
+	}
+	this.setFunction(8, 7, bit(bits, 6))
+	this.setFunction(8, 8, bit(bits, 7))
+	this.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		this.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	// Second copy and dark module
+	for i := 0; i < 8; i++ {
+		this.setFunction(this.size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		this.setFunction(8, this.size-15+i, bit(bits, i))
+	}
+	this.setFunction(8, this.size-8, true)
+}
+
+func (this *QRCode) drawVersion() {
+	if this.version < 7 {
+		return
+	}
+	bits := qrVersion(this.version)
+	for i := 0; i < 18; i++ {
+		a, b := this.size-11+i%3, i/3
+		this.setFunction(a, b, bit(bits, i))
+		this.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// qrFormat returns the fifteen format bits for level and mask, with
+// error correction
+func qrFormat(level gopi.QRLevel, mask int) uint32 {
+	data := qrFormatBits[level]<<3 | uint32(mask)
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// qrVersion returns the eighteen version bits, with error correction
+func qrVersion(version int) uint32 {
+	rem := uint32(version)
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	return uint32(version)<<12 | rem
+}
+
+// drawCodewords places data in a zig-zag of two module columns from
+// the bottom right, skipping function modules
+func (this *QRCode) drawCodewords(data []byte) {
+	i := 0
+	for right := this.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < this.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = this.size - 1 - vert
+				}
+				if this.fn[y*this.size+x] == false && i < len(data)*8 {
+					this.set(x, y, bit(uint32(data[i>>3]), 7-(i&7)))
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask inverts data modules according to a mask pattern. Applying
+// the same mask twice restores the modules
+func (this *QRCode) applyMask(mask int) {
+	for y := 0; y < this.size; y++ {
+		for x := 0; x < this.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && this.fn[y*this.size+x] == false {
+				this.modules[y*this.size+x] = !this.modules[y*this.size+x]
+			}
+		}
+	}
+}
+
+// penalty returns the score used to choose a mask, where lower is better
+func (this *QRCode) penalty() int {
+	result := 0
+	dark := 0
+
+	// Runs and finder-like patterns in rows and columns
+	for i := 0; i < this.size; i++ {
+		row := make([]bool, this.size)
+		col := make([]bool, this.size)
+		for j := 0; j < this.size; j++ {
+			row[j], col[j] = this.At(j, i), this.At(i, j)
+			if row[j] {
+				dark++
+			}
+		}
+		result += qrPenaltyLine(row) + qrPenaltyLine(col)
+	}
+
+	// Blocks of two by two modules of the same color
+	for y := 0; y < this.size-1; y++ {
+		for x := 0; x < this.size-1; x++ {
+			c := this.At(x, y)
+			if c == this.At(x+1, y) && c == this.At(x, y+1) && c == this.At(x+1, y+1) {
+				result += 3
+			}
+		}
+	}
+
+	// Balance of dark and light modules
+	total := this.size * this.size
+	k := (absInt(dark*20-total*10)+total-1)/total - 1
+	return result + k*10
+}
+
+func qrPenaltyLine(line []bool) int {
+	result := 0
+	for i := 0; i < len(line); {
+		j := i
+		for j < len(line) && line[j] == line[i] {
+			j++
+		}
+		if n := j - i; n >= 5 {
+			result += n - 2
+		}
+		i = j
+	}
+	finder := []bool{true, false, true, true, true, false, true}
+	for i := 0; i+len(finder) <= len(line); i++ {
+		if matches(line[i:], finder) == false {
+			continue
+		}
+		if light(line, i-4, i) || light(line, i+7, i+11) {
+			result += 40
+		}
+	}
+	return result
+}
+
+func matches(line, pattern []bool) bool {
+	for i := range pattern {
+		if line[i] != pattern[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// light returns true if modules between start and end are light, where
+// modules outside the line are light
+func light(line []bool, start, end int) bool {
+	for i := start; i < end; i++ {
+		if i >= 0 && i < len(line) && line[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func bit(v uint32, i int) bool {
+	return (v>>uint(i))&1 != 0
+}
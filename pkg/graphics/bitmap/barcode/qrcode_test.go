@@ -0,0 +1,138 @@
+package barcode
+
+import (
+	"reflect"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_QRCode_001(t *testing.T) {
+	// Error correction for HELLO WORLD at version 1-Q
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236}
+	ecc := []byte{168, 72, 22, 82, 217, 54, 156, 0, 46, 15, 180, 122, 16}
+	if mode, bits := qrEncodeData("HELLO WORLD"); mode != qrModeAlphanum {
+		t.Error("Unexpected mode")
+	} else if len(bits) != 61 {
+		t.Error("Unexpected bits", len(bits))
+	}
+	if remainder := rsRemainder(data, rsDivisor(len(ecc))); reflect.DeepEqual(remainder, ecc) == false {
+		t.Error("Unexpected error correction", remainder)
+	}
+	if code, err := NewQRCode("HELLO WORLD", gopi.QR_LEVEL_Q); err != nil {
+		t.Error(err)
+	} else if code.Version() != 1 || code.Size() != 21 {
+		t.Error("Unexpected code", code)
+	} else if code.At(8, code.Size()-8) == false {
+		t.Error("Expected dark module")
+	}
+}
+
+func Test_QRCode_002(t *testing.T) {
+	for _, test := range []struct {
+		level gopi.QRLevel
+		mask  int
+		bits  uint32
+	}{
+		{gopi.QR_LEVEL_L, 0, 0x77C4},
+		{gopi.QR_LEVEL_M, 0, 0x5412},
+		{gopi.QR_LEVEL_Q, 0, 0x355F},
+		{gopi.QR_LEVEL_H, 0, 0x1689},
+		{gopi.QR_LEVEL_L, 4, 0x662F},
+	} {
+		if bits := qrFormat(test.level, test.mask); bits != test.bits {
+			t.Errorf("Unexpected format bits for %v mask %v: %015b", test.level, test.mask, bits)
+		}
+	}
+	if bits := qrVersion(7); bits != 0x07C94 {
+		t.Errorf("Unexpected version bits %018b", bits)
+	}
+}
+
+func Test_QRCode_003(t *testing.T) {
+	for _, test := range []struct {
+		version   int
+		positions []int
+	}{
+		{1, nil},
+		{2, []int{6, 18}},
+		{7, []int{6, 22, 38}},
+		{32, []int{6, 34, 60, 86, 112, 138}},
+		{40, []int{6, 30, 58, 86, 114, 142, 170}},
+	} {
+		code := &QRCode{version: test.version, size: test.version*4 + 17}
+		if positions := code.alignmentPositions(); reflect.DeepEqual(positions, test.positions) == false {
+			t.Error("Unexpected positions for version", test.version, positions)
+		}
+	}
+}
+
+func Test_QRCode_004(t *testing.T) {
+	// Versions increase with data length and error correction
+	for _, test := range []struct {
+		data    string
+		level   gopi.QRLevel
+		version int
+	}{
+		{"01234567", gopi.QR_LEVEL_M, 1},
+		{"https://github.com/djthorpe/gopi", gopi.QR_LEVEL_L, 2},
+		{"https://github.com/djthorpe/gopi", gopi.QR_LEVEL_H, 4},
+		{string(make([]byte, 2953)), gopi.QR_LEVEL_L, 40},
+	} {
+		if code, err := NewQRCode(test.data, test.level); err != nil {
+			t.Error(err)
+		} else if code.Version() != test.version {
+			t.Error("Unexpected version", code)
+		}
+	}
+	if _, err := NewQRCode(string(make([]byte, 2954)), gopi.QR_LEVEL_L); err == nil {
+		t.Error("Expected error for data too long")
+	}
+}
+
+func Test_Barcode_001(t *testing.T) {
+	if modules, err := EncodeBarcode("Wikipedia", gopi.BARCODE_CODE128); err != nil {
+		t.Error(err)
+	} else if len(modules) != 11*11+13 {
+		t.Error("Unexpected length", len(modules))
+	}
+	if modules, err := EncodeBarcode("123456", gopi.BARCODE_CODE128); err != nil {
+		t.Error(err)
+	} else if len(modules) != 11*5+13 {
+		t.Error("Unexpected length", len(modules))
+	}
+	if _, err := EncodeBarcode("\n", gopi.BARCODE_CODE128); err == nil {
+		t.Error("Expected error")
+	}
+	for _, widths := range code128Widths {
+		if n := len(appendWidths(nil, widths)); n != 11 {
+			t.Error("Unexpected width", widths)
+		}
+	}
+}
+
+func Test_Barcode_002(t *testing.T) {
+	if check := EANCheckDigit("400638133393"); check != '1' {
+		t.Error("Unexpected check digit", string(check))
+	} else if check := EANCheckDigit("9638507"); check != '4' {
+		t.Error("Unexpected check digit", string(check))
+	}
+	if modules, err := EncodeBarcode("400638133393", gopi.BARCODE_EAN13); err != nil {
+		t.Error(err)
+	} else if len(modules) != 95 {
+		t.Error("Unexpected length", len(modules))
+	} else if modules, err := EncodeBarcode("4006381333931", gopi.BARCODE_EAN13); err != nil || len(modules) != 95 {
+		t.Error("Unexpected result", err)
+	}
+	if modules, err := EncodeBarcode("96385074", gopi.BARCODE_EAN8); err != nil {
+		t.Error(err)
+	} else if len(modules) != 67 {
+		t.Error("Unexpected length", len(modules))
+	}
+	if _, err := EncodeBarcode("4006381333932", gopi.BARCODE_EAN13); err == nil {
+		t.Error("Expected error for check digit")
+	}
+}
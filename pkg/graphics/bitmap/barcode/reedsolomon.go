@@ -0,0 +1,261 @@
+package barcode
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// bitbuf is a sequence of bits, most significant bit first
+type bitbuf []bool
+
+////////////////////////////////////////////////////////////////////////////////
+// BIT BUFFER
+
+// append the lowest n bits of a value
+func (this *bitbuf) append(v uint32, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*this = append(*this, bit(v, i))
+	}
+}
+
+// bytes returns the bits packed into bytes
+func (this bitbuf) bytes() []byte {
+	result := make([]byte, (len(this)+7)/8)
+	for i, b := range this {
+		if b {
+			result[i>>3] |= 0x80 >> uint(i&7)
+		}
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// REED-SOLOMON
+
+// rsDivisor returns the generator polynomial for a degree, with the
+// leading term omitted
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// rsRemainder returns the error correction codewords for data
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMultiply(d, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	z := uint32(0)
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= uint32((y>>uint(i))&1) * uint32(x)
+	}
+	return byte(z)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INTEGERS
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// REED-SOLOMON DECODING
+
+var (
+	gfExp [512]byte
+	gfLog [256]int
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = i
+		if x <<= 1; x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	for i := 255; i < len(gfExp); i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+// gfPow returns 2^n
+func gfPow(n int) byte {
+	return gfExp[((n%255)+255)%255]
+}
+
+func gfInverse(x byte) byte {
+	return gfExp[255-gfLog[x]]
+}
+
+// rsCorrect corrects errors in a block of data followed by error
+// correction codewords in place, and returns the number of errors
+// corrected or an error if the block cannot be corrected
+func rsCorrect(block []byte, eccLen int) (int, error) {
+	n := len(block)
+
+	// Syndromes are the block evaluated at the roots of the generator
+	syndromes := make([]byte, eccLen)
+	errors := false
+	for i := range syndromes {
+		s := byte(0)
+		for _, b := range block {
+			s = gfMultiply(s, gfPow(i)) ^ b
+		}
+		if syndromes[i] = s; s != 0 {
+			errors = true
+		}
+	}
+	if errors == false {
+		return 0, nil
+	}
+
+	// Berlekamp-Massey determines the error locator polynomial, with
+	// coefficients in increasing order of degree
+	c, b := []byte{1}, []byte{1}
+	l, m, bd := 0, 1, byte(1)
+	for i := 0; i < eccLen; i++ {
+		d := syndromes[i]
+		for j := 1; j <= l && j < len(c); j++ {
+			d ^= gfMultiply(c[j], syndromes[i-j])
+		}
+		if d == 0 {
+			m++
+			continue
+		}
+		t := append([]byte{}, c...)
+		coef := gfMultiply(d, gfInverse(bd))
+		for len(c) < len(b)+m {
+			c = append(c, 0)
+		}
+		for j := range b {
+			c[j+m] ^= gfMultiply(coef, b[j])
+		}
+		if 2*l <= i {
+			l, b, bd, m = i+1-l, t, d, 1
+		} else {
+			m++
+		}
+	}
+	if 2*l > eccLen {
+		return 0, errTooManyErrors
+	}
+
+	// Chien search finds positions where the locator has a root at the
+	// inverse of the position
+	positions := []int{}
+	locators := []byte{}
+	for pos := 0; pos < n; pos++ {
+		x := gfPow(n - 1 - pos)
+		xinv := gfInverse(x)
+		v := byte(0)
+		for j := len(c) - 1; j >= 0; j-- {
+			v = gfMultiply(v, xinv) ^ c[j]
+		}
+		if v == 0 {
+			positions = append(positions, pos)
+			locators = append(locators, x)
+		}
+	}
+	if len(positions) != l {
+		return 0, errTooManyErrors
+	}
+
+	// Solve for error magnitudes from syndromes, where each syndrome is
+	// the sum of magnitudes multiplied by locators to the power of the
+	// syndrome index
+	magnitudes, err := gfSolve(locators, syndromes[:l])
+	if err != nil {
+		return 0, err
+	}
+	for i, pos := range positions {
+		block[pos] ^= magnitudes[i]
+	}
+
+	// Return number of errors corrected
+	return l, nil
+}
+
+// gfSolve solves the Vandermonde system sum(e[k] * x[k]^i) = s[i]
+// using Gaussian elimination
+func gfSolve(x, s []byte) ([]byte, error) {
+	n := len(x)
+	a := make([][]byte, n)
+	for i := range a {
+		a[i] = make([]byte, n+1)
+		for k := range x {
+			a[i][k] = gfExp[(gfLog[x[k]]*i)%255]
+		}
+		a[i][n] = s[i]
+	}
+	for col := 0; col < n; col++ {
+		pivot := -1
+		for row := col; row < n; row++ {
+			if a[row][col] != 0 {
+				pivot = row
+				break
+			}
+		}
+		if pivot < 0 {
+			return nil, errTooManyErrors
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		inv := gfInverse(a[col][col])
+		for j := col; j <= n; j++ {
+			a[col][j] = gfMultiply(a[col][j], inv)
+		}
+		for row := 0; row < n; row++ {
+			if row == col || a[row][col] == 0 {
+				continue
+			}
+			f := a[row][col]
+			for j := col; j <= n; j++ {
+				a[row][j] ^= gfMultiply(f, a[col][j])
+			}
+		}
+	}
+	result := make([]byte, n)
+	for i := range result {
+		result[i] = a[i][n]
+	}
+	return result, nil
+}
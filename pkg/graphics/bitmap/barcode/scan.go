@@ -0,0 +1,474 @@
+package barcode
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Symbol is a QR code decoded from an image
+type Symbol struct {
+	Data    []byte
+	Version int
+	Level   gopi.QRLevel
+
+	// Corners are the top left, top right, bottom right and bottom left
+	// corners of the symbol in image coordinates, excluding quiet zone
+	Corners [4]gopi.Point
+}
+
+// bitmatrix is a binarized image where true is dark
+type bitmatrix struct {
+	w, h int
+	bits []bool
+}
+
+// finder is a candidate finder pattern centre
+type finder struct {
+	x, y   float64
+	module float64
+	count  int
+}
+
+// transform maps module coordinates to image coordinates
+type transform [9]float64
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Maximum number of finder candidates to consider in combination
+	scanMaxFinders = 12
+
+	// Minimum alignment pattern score out of 25 modules
+	scanMinAlignment = 22
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ScanQRCodes detects and decodes QR codes in an image, such as a camera
+// frame. Codes need to be dark on light and not mirrored, but may be
+// rotated or viewed in perspective
+func ScanQRCodes(img image.Image) []*Symbol {
+	m := binarize(img)
+	finders := m.finders()
+	result := []*Symbol{}
+
+	// Try combinations of three finders, removing finders which are used
+	// by a decoded symbol
+	for len(finders) >= 3 {
+		symbol, used := m.decode(finders)
+		if symbol == nil {
+			break
+		}
+		result = append(result, symbol)
+		remain := finders[:0]
+		for i, f := range finders {
+			if i != used[0] && i != used[1] && i != used[2] {
+				remain = append(remain, f)
+			}
+		}
+		finders = remain
+	}
+
+	// Return symbols
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS - BINARIZE
+
+// binarize converts an image to luminance and thresholds each pixel
+// against the mean of a surrounding window, which copes with uneven
+// lighting across the frame
+func binarize(img image.Image) *bitmatrix {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	// Summed area table of luminance
+	sum := make([]int64, (w+1)*(h+1))
+	lum := make([]int32, w*h)
+	for y := 0; y < h; y++ {
+		row := int64(0)
+		for x := 0; x < w; x++ {
+			v := int32(color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray).Y)
+			lum[y*w+x] = v
+			row += int64(v)
+			sum[(y+1)*(w+1)+x+1] = sum[y*(w+1)+x+1] + row
+		}
+	}
+
+	// Threshold against the local mean
+	r := maxInt(minInt(w, h)/8, 8)
+	m := &bitmatrix{w, h, make([]bool, w*h)}
+	for y := 0; y < h; y++ {
+		y0, y1 := maxInt(y-r, 0), minInt(y+r+1, h)
+		for x := 0; x < w; x++ {
+			x0, x1 := maxInt(x-r, 0), minInt(x+r+1, w)
+			total := sum[y1*(w+1)+x1] - sum[y0*(w+1)+x1] - sum[y1*(w+1)+x0] + sum[y0*(w+1)+x0]
+			n := int64((x1 - x0) * (y1 - y0))
+			m.bits[y*w+x] = int64(lum[y*w+x])*n*10 < total*9
+		}
+	}
+	return m
+}
+
+func (this *bitmatrix) at(x, y int) bool {
+	if x < 0 || y < 0 || x >= this.w || y >= this.h {
+		return false
+	}
+	return this.bits[y*this.w+x]
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS - FINDER PATTERNS
+
+// finders scans rows for runs of dark and light in the ratio 1:1:3:1:1,
+// cross-checks each vertically and horizontally, and merges nearby
+// candidates. Candidates are returned in order of most rows matched
+func (this *bitmatrix) finders() []*finder {
+	result := []*finder{}
+	for y := 0; y < this.h; y++ {
+		var runs [5]int
+		for x, i := 0, 0; x <= this.w; x++ {
+			dark := x < this.w && this.at(x, y)
+			if (i&1 == 0) == dark {
+				runs[i]++
+				continue
+			}
+			if i < 4 {
+				// Light run before first dark run is ignored
+				if i > 0 || runs[0] > 0 {
+					i++
+					runs[i] = 1
+				}
+				continue
+			}
+			if ratio(runs) {
+				cx := float64(x) - float64(runs[4]+runs[3]) - float64(runs[2])/2
+				if f := this.crossCheck(cx, float64(y), runs); f != nil {
+					result = merge(result, f)
+				}
+			}
+			// Shift by two runs, to start at the next dark run
+			runs = [5]int{runs[2], runs[3], runs[4], 1, 0}
+			i = 3
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].count > result[j].count
+	})
+	if len(result) > scanMaxFinders {
+		result = result[:scanMaxFinders]
+	}
+	return result
+}
+
+// ratio returns true if runs are in the ratio 1:1:3:1:1
+func ratio(runs [5]int) bool {
+	total := 0
+	for _, n := range runs {
+		if n == 0 {
+			return false
+		}
+		total += n
+	}
+	if total < 7 {
+		return false
+	}
+	module := float64(total) / 7
+	tolerance := module / 2
+	return math.Abs(module-float64(runs[0])) < tolerance &&
+		math.Abs(module-float64(runs[1])) < tolerance &&
+		math.Abs(3*module-float64(runs[2])) < 3*tolerance &&
+		math.Abs(module-float64(runs[3])) < tolerance &&
+		math.Abs(module-float64(runs[4])) < tolerance
+}
+
+// crossCheck measures runs vertically and then horizontally through a
+// candidate centre, returning the refined centre or nil
+func (this *bitmatrix) crossCheck(x, y float64, runs [5]int) *finder {
+	htotal := runs[0] + runs[1] + runs[2] + runs[3] + runs[4]
+	cy, vtotal := this.measure(int(x), int(y), 0, 1, htotal)
+	if vtotal == 0 {
+		return nil
+	}
+	cx, htotal := this.measure(int(x), int(cy), 1, 0, htotal)
+	if htotal == 0 {
+		return nil
+	}
+	return &finder{cx, cy, float64(htotal+vtotal) / 14, 1}
+}
+
+// measure counts runs either side of a dark centre in direction dx,dy
+// and returns the centre along that direction and the total length, or
+// zero if the runs are not a finder pattern of similar size
+func (this *bitmatrix) measure(x, y, dx, dy, expected int) (float64, int) {
+	if this.at(x, y) == false {
+		return 0, 0
+	}
+	var runs [5]int
+	max := expected * 2
+
+	// Count backwards through centre, light and dark runs
+	p, q := x, y
+	for i, dark := 2, true; i >= 0; i, dark = i-1, !dark {
+		for this.at(p, q) == dark && (p >= 0 && q >= 0) && runs[i] <= max {
+			runs[i]++
+			p, q = p-dx, q-dy
+		}
+	}
+	start := p*dx + q*dy + 1
+
+	// Count forwards
+	p, q = x+dx, y+dy
+	for i, dark := 2, true; i <= 4; i, dark = i+1, !dark {
+		for this.at(p, q) == dark && p < this.w && q < this.h && runs[i] <= max {
+			runs[i]++
+			p, q = p+dx, q+dy
+		}
+	}
+
+	total := runs[0] + runs[1] + runs[2] + runs[3] + runs[4]
+	if ratio(runs) == false || absInt(total-expected)*5 >= expected*2 {
+		return 0, 0
+	}
+	return float64(start+runs[0]+runs[1]) + float64(runs[2])/2, total
+}
+
+// merge adds a candidate or averages it with an existing candidate at
+// the same position with a similar module size
+func merge(finders []*finder, f *finder) []*finder {
+	for _, g := range finders {
+		if math.Abs(f.x-g.x) <= g.module && math.Abs(f.y-g.y) <= g.module && math.Abs(f.module-g.module) <= g.module {
+			n := float64(g.count)
+			g.x = (g.x*n + f.x) / (n + 1)
+			g.y = (g.y*n + f.y) / (n + 1)
+			g.module = (g.module*n + f.module) / (n + 1)
+			g.count++
+			return finders
+		}
+	}
+	return append(finders, f)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS - DECODE
+
+// decode tries combinations of three finders which could be the corners
+// of a symbol, and returns the first symbol decoded with the indexes of
+// the finders used
+func (this *bitmatrix) decode(finders []*finder) (*Symbol, [3]int) {
+	for i := range finders {
+		for j := i + 1; j < len(finders); j++ {
+			for k := j + 1; k < len(finders); k++ {
+				if symbol := this.decodeFinders(finders[i], finders[j], finders[k]); symbol != nil {
+					return symbol, [3]int{i, j, k}
+				}
+			}
+		}
+	}
+	return nil, [3]int{}
+}
+
+func (this *bitmatrix) decodeFinders(a, b, c *finder) *Symbol {
+	// Finders need similar module sizes
+	lo := math.Min(a.module, math.Min(b.module, c.module))
+	hi := math.Max(a.module, math.Max(b.module, c.module))
+	if hi > lo*1.5 {
+		return nil
+	}
+
+	// The top left finder is opposite the longest side, and the others
+	// are ordered clockwise
+	ab, bc, ac := dist(a, b), dist(b, c), dist(a, c)
+	if bc < ab || bc < ac {
+		if ab > ac {
+			a, c = c, a
+		} else {
+			a, b = b, a
+		}
+		ab, bc, ac = dist(a, b), dist(b, c), dist(a, c)
+	}
+	if (b.x-a.x)*(c.y-a.y)-(b.y-a.y)*(c.x-a.x) < 0 {
+		b, c = c, b
+	}
+
+	// Sides need to be similar length and at right angles, allowing for
+	// perspective
+	if math.Max(ab, ac) > math.Min(ab, ac)*1.5 || bc < math.Max(ab, ac)*1.2 || bc > math.Min(ab, ac)*1.7 {
+		return nil
+	}
+
+	// Estimate dimension from the distance between finders, and try
+	// nearby valid dimensions
+	// Runs are measured horizontally and vertically, so are longer than
+	// the module size when the symbol is rotated
+	theta := math.Atan2(b.y-a.y, b.x-a.x)
+	module := (a.module + b.module + c.module) / 3 * math.Max(math.Abs(math.Cos(theta)), math.Abs(math.Sin(theta)))
+	estimate := int(math.Round((ab+ac)/(2*module))) + 7
+	for _, delta := range []int{0, 4, -4} {
+		size := estimate + delta
+		switch size & 3 {
+		case 0:
+			size++
+		case 2:
+			size--
+		case 3:
+			size -= 2
+		}
+		if size < 21 || size > 177 {
+			continue
+		}
+		t := this.transform(a, b, c, size, module)
+		if symbol := this.sample(t, size); symbol != nil {
+			return symbol
+		}
+	}
+
+	// Not decoded
+	return nil
+}
+
+// transform returns a transform from module coordinates to image
+// coordinates, using the alignment pattern nearest the bottom right for
+// perspective when it can be found
+func (this *bitmatrix) transform(a, b, c *finder, size int, module float64) transform {
+	src := [4][2]float64{{3.5, 3.5}, {float64(size) - 3.5, 3.5}, {3.5, float64(size) - 3.5}}
+	dst := [4][2]float64{{a.x, a.y}, {b.x, b.y}, {c.x, c.y}}
+
+	// Without an alignment pattern, use the parallelogram of the finders
+	src[3] = [2]float64{float64(size) - 3.5, float64(size) - 3.5}
+	dst[3] = [2]float64{b.x + c.x - a.x, b.y + c.y - a.y}
+	t := newTransform(src, dst)
+	if size == 21 {
+		return t
+	}
+
+	// Search for the alignment pattern around its estimated position
+	ax, ay := float64(size)-6.5, float64(size)-6.5
+	ex, ey := t.apply(ax, ay)
+	best, n, bx, by := 0, 0, 0.0, 0.0
+	radius := int(module * 4)
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			x, y := ex+float64(dx), ey+float64(dy)
+			// Average the positions with the best score
+			if score := this.alignment(t, x, y); score > best {
+				best, n, bx, by = score, 1, x, y
+			} else if score == best {
+				n, bx, by = n+1, bx+x, by+y
+			}
+		}
+	}
+	if best < scanMinAlignment {
+		return t
+	}
+	src[3] = [2]float64{ax, ay}
+	dst[3] = [2]float64{bx / float64(n), by / float64(n)}
+	return newTransform(src, dst)
+}
+
+// alignment returns how many of the 5x5 modules of an alignment pattern
+// match when centred on an image point, using the module steps of a
+// transform
+func (this *bitmatrix) alignment(t transform, x, y float64) int {
+	ox, oy := t.apply(0, 0)
+	xx, xy := t.apply(1, 0)
+	yx, yy := t.apply(0, 1)
+	score := 0
+	for j := -2; j <= 2; j++ {
+		for i := -2; i <= 2; i++ {
+			px := x + float64(i)*(xx-ox) + float64(j)*(yx-ox)
+			py := y + float64(i)*(xy-oy) + float64(j)*(yy-oy)
+			dark := maxInt(absInt(i), absInt(j)) != 1
+			if this.at(int(px), int(py)) == dark {
+				score++
+			}
+		}
+	}
+	return score
+}
+
+// sample reads modules through a transform and decodes them
+func (this *bitmatrix) sample(t transform, size int) *Symbol {
+	modules := make([][]bool, size)
+	for y := range modules {
+		modules[y] = make([]bool, size)
+		for x := range modules[y] {
+			px, py := t.apply(float64(x)+0.5, float64(y)+0.5)
+			modules[y][x] = this.at(int(math.Floor(px)), int(math.Floor(py)))
+		}
+	}
+	data, code, err := DecodeQRCode(modules)
+	if err != nil {
+		return nil
+	}
+	symbol := &Symbol{Data: data, Version: code.Version(), Level: code.Level()}
+	for i, corner := range [4][2]float64{{0, 0}, {float64(size), 0}, {float64(size), float64(size)}, {0, float64(size)}} {
+		x, y := t.apply(corner[0], corner[1])
+		symbol.Corners[i] = gopi.Point{X: float32(x), Y: float32(y)}
+	}
+	return symbol
+}
+
+func dist(a, b *finder) float64 {
+	return math.Hypot(a.x-b.x, a.y-b.y)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS - TRANSFORM
+
+// newTransform returns the perspective transform which maps four source
+// points to four destination points
+func newTransform(src, dst [4][2]float64) transform {
+	// Solve the eight equations for the eight unknowns, with the last
+	// coefficient being one
+	var a [8][9]float64
+	for i := 0; i < 4; i++ {
+		x, y, u, v := src[i][0], src[i][1], dst[i][0], dst[i][1]
+		a[i*2] = [9]float64{x, y, 1, 0, 0, 0, -u * x, -u * y, u}
+		a[i*2+1] = [9]float64{0, 0, 0, x, y, 1, -v * x, -v * y, v}
+	}
+	for col := 0; col < 8; col++ {
+		pivot := col
+		for row := col + 1; row < 8; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		if a[col][col] == 0 {
+			continue
+		}
+		for row := 0; row < 8; row++ {
+			if row == col {
+				continue
+			}
+			f := a[row][col] / a[col][col]
+			for j := col; j < 9; j++ {
+				a[row][j] -= f * a[col][j]
+			}
+		}
+	}
+	var t transform
+	for i := 0; i < 8; i++ {
+		if a[i][i] != 0 {
+			t[i] = a[i][8] / a[i][i]
+		}
+	}
+	t[8] = 1
+	return t
+}
+
+func (t transform) apply(x, y float64) (float64, float64) {
+	d := t[6]*x + t[7]*y + t[8]
+	return (t[0]*x + t[1]*y + t[2]) / d, (t[3]*x + t[4]*y + t[5]) / d
+}
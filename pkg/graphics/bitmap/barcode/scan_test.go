@@ -0,0 +1,136 @@
+package barcode_test
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	barcode "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/barcode"
+	rgba32 "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Scan_001(t *testing.T) {
+	dst, err := new(rgba32.Factory).New(bitmap.GetColorModel(gopi.SURFACE_FMT_RGBA32), 200, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.PaintQRCode("HELLO WORLD", gopi.QR_LEVEL_Q, image.Rect(0, 0, 200, 200)); err != nil {
+		t.Fatal(err)
+	}
+	// Version 1 is 29 modules with quiet zone, so six pixels per module
+	// and the symbol starts after a margin of 13 pixels and quiet zone
+	symbols := barcode.ScanQRCodes(dst)
+	if len(symbols) != 1 {
+		t.Fatal("Expected one symbol, got", len(symbols))
+	} else if string(symbols[0].Data) != "HELLO WORLD" {
+		t.Error("Unexpected data", symbols[0].Data)
+	} else if symbols[0].Version != 1 || symbols[0].Level != gopi.QR_LEVEL_Q {
+		t.Error("Unexpected version or level", symbols[0])
+	} else if near(symbols[0].Corners[0], 13+24, 13+24) == false {
+		t.Error("Unexpected corner", symbols[0].Corners[0])
+	} else if near(symbols[0].Corners[2], 13+24+126, 13+24+126) == false {
+		t.Error("Unexpected corner", symbols[0].Corners[2])
+	}
+}
+
+func Test_Scan_002(t *testing.T) {
+	// Rotated and scaled symbols
+	for _, angle := range []float64{30, 45, 90, 180, 225, 300} {
+		img := project("https://github.com/djthorpe/gopi", gopi.QR_LEVEL_M, 400, func(x, y float64) (float64, float64) {
+			r := angle * math.Pi / 180
+			x, y = 0.7*(x-0.5), 0.7*(y-0.5)
+			return 0.5 + x*math.Cos(r) - y*math.Sin(r), 0.5 + x*math.Sin(r) + y*math.Cos(r)
+		})
+		if symbols := barcode.ScanQRCodes(img); len(symbols) != 1 {
+			t.Error(angle, "Expected one symbol, got", len(symbols))
+		} else if string(symbols[0].Data) != "https://github.com/djthorpe/gopi" {
+			t.Error(angle, "Unexpected data", string(symbols[0].Data))
+		}
+	}
+}
+
+func Test_Scan_003(t *testing.T) {
+	// Symbol in perspective, with a larger version
+	data := "WIFI:S:gopi;T:WPA;P:0123456789abcdef;;WIFI:S:gopi;T:WPA;P:0123456789abcdef;;"
+	img := project(data, gopi.QR_LEVEL_L, 480, func(x, y float64) (float64, float64) {
+		d := 1 + 0.3*x
+		return 0.15 + 0.91*x/d, 0.15 + (0.7*y+0.105*x)/d
+	})
+	if symbols := barcode.ScanQRCodes(img); len(symbols) != 1 {
+		t.Error("Expected one symbol, got", len(symbols))
+	} else if string(symbols[0].Data) != data {
+		t.Error("Unexpected data", string(symbols[0].Data))
+	} else if symbols[0].Version < 2 {
+		t.Error("Unexpected version", symbols[0].Version)
+	}
+}
+
+func Test_Scan_004(t *testing.T) {
+	// Blank image and two symbols in one image
+	if symbols := barcode.ScanQRCodes(image.NewGray(image.Rect(0, 0, 64, 64))); len(symbols) != 0 {
+		t.Error("Expected no symbols")
+	}
+	dst, err := new(rgba32.Factory).New(bitmap.GetColorModel(gopi.SURFACE_FMT_RGBA32), 400, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.PaintQRCode("LEFT", gopi.QR_LEVEL_M, image.Rect(0, 0, 200, 200)); err != nil {
+		t.Fatal(err)
+	} else if err := dst.PaintQRCode("RIGHT", gopi.QR_LEVEL_H, image.Rect(200, 0, 400, 200)); err != nil {
+		t.Fatal(err)
+	}
+	symbols := barcode.ScanQRCodes(dst)
+	found := map[string]bool{}
+	for _, symbol := range symbols {
+		found[string(symbol.Data)] = true
+	}
+	if len(symbols) != 2 || found["LEFT"] == false || found["RIGHT"] == false {
+		t.Error("Unexpected symbols", found)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func near(pt gopi.Point, x, y float32) bool {
+	return math.Abs(float64(pt.X-x)) < 2 && math.Abs(float64(pt.Y-y)) < 2
+}
+
+// project draws a symbol with quiet zone into a square image, where fn
+// maps symbol coordinates in the range 0 to 1 to image coordinates in
+// the range 0 to 1. Pixels are sampled by inverting fn numerically
+func project(data string, level gopi.QRLevel, size int, fn func(x, y float64) (float64, float64)) image.Image {
+	code, err := barcode.NewQRCode(data, level)
+	if err != nil {
+		panic(err)
+	}
+	n := code.Size() + 8
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+
+	// Forward map each subdivided module to the image
+	const sub = 8
+	for y := 0; y < n*sub; y++ {
+		for x := 0; x < n*sub; x++ {
+			if code.At(x/sub-4, y/sub-4) == false {
+				continue
+			}
+			u, v := fn((float64(x)+0.5)/float64(n*sub), (float64(y)+0.5)/float64(n*sub))
+			px, py := int(u*float64(size)), int(v*float64(size))
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					img.SetGray(px+dx, py+dy, color.Gray{0x20})
+				}
+			}
+		}
+	}
+	return img
+}
@@ -2,10 +2,13 @@ package bitmap
 
 import (
 	"fmt"
+	"reflect"
 	"sync"
+	"unsafe"
 
 	// Modules
 	gopi "github.com/djthorpe/gopi/v3"
+	pool "github.com/djthorpe/gopi/v3/pkg/pool"
 	multierror "github.com/hashicorp/go-multierror"
 )
 
@@ -20,6 +23,7 @@ type BitmapFactory interface {
 type Bitmaps struct {
 	gopi.Unit
 	gopi.Platform
+	gopi.Logger
 	sync.Mutex
 
 	bitmaps []gopi.Bitmap
@@ -33,11 +37,24 @@ var (
 	colormodels = make(map[gopi.SurfaceFormat]ColorModel)
 )
 
+var (
+	// Pixels keeps pixel buffers for in-memory bitmaps, with up to two
+	// idle buffers of each size
+	Pixels = pool.NewBytes("pixels", 2)
+)
+
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
 func (this *Bitmaps) New(gopi.Config) error {
 	this.Require(this.Platform)
+
+	// Record where pixel buffers are taken in debug mode
+	if this.Logger != nil && this.Logger.IsDebug() {
+		Pixels.SetDebug(true)
+	}
+
+	// Return success
 	return nil
 }
 
@@ -55,6 +72,13 @@ func (this *Bitmaps) Dispose() error {
 		}
 	}
 
+	// Report pixel buffers which have not been released
+	if this.Logger != nil {
+		for _, leak := range Pixels.Leaks() {
+			this.Debug("Bitmaps: Not released: ", leak)
+		}
+	}
+
 	// Release resources
 	this.bitmaps = nil
 
@@ -153,3 +177,17 @@ func AlignUp(v uint32, a uint32) uint32 {
 	// Align a value on a byte-bounrary
 	return ((v - 1) & ^(a - 1)) + a
 }
+
+// Uint32 returns pixel data from Lock as uint32 values, sharing the
+// same memory. Any trailing bytes are ignored
+func Uint32(data []byte) []uint32 {
+	var result []uint32
+	if len(data) < 4 {
+		return result
+	}
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&result))
+	hdr.Data = uintptr(unsafe.Pointer(&data[0]))
+	hdr.Len = len(data) >> 2
+	hdr.Cap = hdr.Len
+	return result
+}
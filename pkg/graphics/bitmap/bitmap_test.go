@@ -93,3 +93,77 @@ func Test_Bitmap_003(t *testing.T) {
 		}
 	})
 }
+
+func Test_Bitmap_004(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		app.Require(app.Bitmaps)
+
+		dest, err := app.NewBitmap(gopi.SURFACE_FMT_RGBA32, 10, 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		// Write pixels directly
+		data, stride, err := dest.Lock()
+		if err != nil {
+			t.Error(err)
+			return
+		} else if _, _, err := dest.Lock(); err == nil {
+			t.Error("Expected error when locking twice")
+		}
+		pixels := bitmap.Uint32(data)
+		for y := uint32(0); y < 10; y++ {
+			row := pixels[y*(stride>>2):]
+			for x := uint32(0); x < 10; x++ {
+				row[x] = uint32(bitmap.RGBA32(x<<24 | y<<16 | 0xFF))
+			}
+		}
+		if err := dest.Unlock(); err != nil {
+			t.Error(err)
+		} else if err := dest.Unlock(); err == nil {
+			t.Error("Expected error when unlocking twice")
+		}
+
+		// Read pixels
+		for y := 0; y < 10; y++ {
+			for x := 0; x < 10; x++ {
+				if r, g, _, a := dest.At(x, y).RGBA(); r>>8 != uint32(x) || g>>8 != uint32(y) || a>>8 != 0xFF {
+					t.Error("Unexpected pixel at", x, y, dest.At(x, y))
+				}
+			}
+		}
+	})
+}
+
+func Test_Bitmap_005(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		app.Require(app.Bitmaps)
+
+		dest, err := app.NewBitmap(gopi.SURFACE_FMT_RGBA32, 10, 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		dirty, ok := dest.(bitmap.DirtyBitmap)
+		if ok == false {
+			t.Error("Expected bitmap to track changed region")
+			return
+		}
+
+		// Changed region is the union of pixels set
+		dest.SetAt(color.White, 2, 3)
+		dest.SetAt(color.White, 5, 1)
+		if r := dirty.TakeDirty(); r != image.Rect(2, 1, 6, 4) {
+			t.Error("Unexpected changed region", r)
+		} else if r := dirty.TakeDirty(); r.Empty() == false {
+			t.Error("Expected empty region", r)
+		}
+
+		// Whole bitmap changes when cleared
+		dest.ClearToColor(color.Black)
+		if r := dirty.TakeDirty(); r != image.Rect(0, 0, 10, 10) {
+			t.Error("Unexpected changed region", r)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package bitmap
+
+import (
+	"image"
+	"sync"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACE
+
+// DirtyBitmap is implemented by bitmaps which track the region changed
+// since it was last taken, so that only that region needs to be updated
+// on screen
+type DirtyBitmap interface {
+	gopi.Bitmap
+
+	// TakeDirty returns the changed region and clears it, or returns
+	// an empty rectangle when nothing has changed
+	TakeDirty() image.Rectangle
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Dirty is the union of changed rectangles
+type Dirty struct {
+	sync.Mutex
+	r image.Rectangle
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// Mark adds a changed rectangle to the region
+func (this *Dirty) Mark(r image.Rectangle) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.r = this.r.Union(r)
+}
+
+// Take returns the changed region and clears it
+func (this *Dirty) Take() image.Rectangle {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	r := this.r
+	this.r = image.ZR
+	return r
+}
@@ -0,0 +1,43 @@
+package ops
+
+import (
+	"image"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Adjust draws the source image into the destination bitmap with
+// brightness and contrast changed. Both are between -1 and 1, where
+// zero leaves the image unchanged. The destination and source must be
+// the same size
+func Adjust(dst gopi.Bitmap, src image.Image, brightness, contrast float32) error {
+	in, err := loadSameSize(dst, src)
+	if err != nil || brightness < -1 || brightness > 1 || contrast < -1 || contrast > 1 {
+		return gopi.ErrBadParameter.WithPrefix("Adjust")
+	}
+
+	// Contrast of one makes every value either zero or one
+	factor := float32(1e6)
+	if contrast < 1 {
+		factor = (1 + contrast) / (1 - contrast)
+	}
+
+	// Adjust colour values without alpha, then premultiply
+	for i := 0; i < len(in.pix); i += 4 {
+		p := in.pix[i : i+4 : i+4]
+		a := p[3]
+		if a == 0 {
+			continue
+		}
+		for j := 0; j < 3; j++ {
+			v := (p[j]/a-0.5)*factor + 0.5 + brightness
+			p[j] = clamp(v, 1) * a
+		}
+	}
+
+	// Write destination
+	return in.store(dst)
+}
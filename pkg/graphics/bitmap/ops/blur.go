@@ -0,0 +1,101 @@
+package ops
+
+import (
+	"image"
+	"math"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// BoxBlur draws the source image into the destination bitmap, averaging
+// pixels within a radius. The destination and source must be the same
+// size
+func BoxBlur(dst gopi.Bitmap, src image.Image, radius uint) error {
+	in, err := loadSameSize(dst, src)
+	if err != nil {
+		return gopi.ErrBadParameter.WithPrefix("BoxBlur")
+	}
+	weight := make([]float32, 2*radius+1)
+	for i := range weight {
+		weight[i] = 1 / float32(len(weight))
+	}
+	return convolve(in, weight).store(dst)
+}
+
+// GaussianBlur draws the source image into the destination bitmap,
+// blurred with a standard deviation in pixels. The destination and
+// source must be the same size
+func GaussianBlur(dst gopi.Bitmap, src image.Image, sigma float64) error {
+	in, err := loadSameSize(dst, src)
+	if err != nil || sigma < 0 {
+		return gopi.ErrBadParameter.WithPrefix("GaussianBlur")
+	}
+	radius := int(math.Ceil(sigma * 3))
+	weight := make([]float32, 2*radius+1)
+	sum := float32(0)
+	for i := range weight {
+		x := float64(i - radius)
+		if sigma == 0 {
+			weight[i] = 1
+		} else {
+			weight[i] = float32(math.Exp(-x * x / (2 * sigma * sigma)))
+		}
+		sum += weight[i]
+	}
+	for i := range weight {
+		weight[i] /= sum
+	}
+	return convolve(in, weight).store(dst)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// loadSameSize returns source pixels, or an error if the destination
+// is a different size
+func loadSameSize(dst gopi.Bitmap, src image.Image) (*pixbuf, error) {
+	if dst == nil || src == nil {
+		return nil, gopi.ErrBadParameter
+	}
+	in := load(src)
+	if w, h := size(dst); w != in.w || h != in.h {
+		return nil, gopi.ErrBadParameter
+	}
+	return in, nil
+}
+
+// convolve applies a symmetric kernel horizontally and then vertically,
+// where edge pixels are repeated
+func convolve(in *pixbuf, weight []float32) *pixbuf {
+	tmp := newPixbuf(in.w, in.h)
+	for y := 0; y < in.h; y++ {
+		convolveRow(tmp.row(y), in.row(y), weight, in.w, 4)
+	}
+	out := newPixbuf(in.w, in.h)
+	for x := 0; x < in.w; x++ {
+		convolveRow(out.pix[x*4:], tmp.pix[x*4:], weight, in.h, in.w*4)
+	}
+	return out
+}
+
+// convolveRow convolves n pixels, where stride is the distance
+// between pixels
+func convolveRow(dst, src []float32, weight []float32, n, stride int) {
+	radius := len(weight) / 2
+	for i := 0; i < n; i++ {
+		var r, g, b, a float32
+		for k, w := range weight {
+			j := clampInt(i+k-radius, n-1) * stride
+			p := src[j : j+4 : j+4]
+			r += p[0] * w
+			g += p[1] * w
+			b += p[2] * w
+			a += p[3] * w
+		}
+		p := dst[i*stride : i*stride+4 : i*stride+4]
+		p[0], p[1], p[2], p[3] = r, g, b, a
+	}
+}
@@ -0,0 +1,144 @@
+package ops
+
+import (
+	"image"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Operator is a Porter-Duff compositing operator
+type Operator uint
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	OP_CLEAR Operator = iota
+	OP_SRC
+	OP_DST
+	OP_SRC_OVER
+	OP_DST_OVER
+	OP_SRC_IN
+	OP_DST_IN
+	OP_SRC_OUT
+	OP_DST_OUT
+	OP_SRC_ATOP
+	OP_DST_ATOP
+	OP_XOR
+	OP_MAX = OP_XOR
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Composite combines the source image with the destination bitmap using
+// a Porter-Duff operator, with the top left of the source at a point
+// in the destination. Destination pixels outside the source are not
+// changed
+func Composite(dst gopi.Bitmap, src image.Image, op Operator, pt image.Point) error {
+	if dst == nil || src == nil || op > OP_MAX {
+		return gopi.ErrBadParameter.WithPrefix("Composite")
+	}
+	out := load(dst)
+	in := load(src)
+
+	// Determine the area of the destination to composite
+	r := image.Rect(0, 0, in.w, in.h).Add(pt).Intersect(image.Rect(0, 0, out.w, out.h))
+	if r.Empty() {
+		return nil
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		srow := in.row(y - pt.Y)
+		drow := out.row(y)
+		compositeRow(drow[r.Min.X*4:r.Max.X*4], srow[(r.Min.X-pt.X)*4:(r.Max.X-pt.X)*4], op)
+	}
+
+	// Write destination
+	return out.store(dst)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (o Operator) String() string {
+	switch o {
+	case OP_CLEAR:
+		return "OP_CLEAR"
+	case OP_SRC:
+		return "OP_SRC"
+	case OP_DST:
+		return "OP_DST"
+	case OP_SRC_OVER:
+		return "OP_SRC_OVER"
+	case OP_DST_OVER:
+		return "OP_DST_OVER"
+	case OP_SRC_IN:
+		return "OP_SRC_IN"
+	case OP_DST_IN:
+		return "OP_DST_IN"
+	case OP_SRC_OUT:
+		return "OP_SRC_OUT"
+	case OP_DST_OUT:
+		return "OP_DST_OUT"
+	case OP_SRC_ATOP:
+		return "OP_SRC_ATOP"
+	case OP_DST_ATOP:
+		return "OP_DST_ATOP"
+	case OP_XOR:
+		return "OP_XOR"
+	default:
+		return "[?? Invalid Operator value]"
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// compositeRow combines premultiplied source and destination pixels as
+// dst = src * fa + dst * fb, where the fractions depend on the operator
+// and the alpha values of source and destination
+func compositeRow(dst, src []float32, op Operator) {
+	for i := 0; i+4 <= len(dst) && i+4 <= len(src); i += 4 {
+		s := src[i : i+4 : i+4]
+		d := dst[i : i+4 : i+4]
+		fa, fb := fractions(op, s[3], d[3])
+		d[0] = s[0]*fa + d[0]*fb
+		d[1] = s[1]*fa + d[1]*fb
+		d[2] = s[2]*fa + d[2]*fb
+		d[3] = s[3]*fa + d[3]*fb
+	}
+}
+
+// fractions returns the source and destination fractions for an
+// operator
+func fractions(op Operator, as, ad float32) (float32, float32) {
+	switch op {
+	case OP_SRC:
+		return 1, 0
+	case OP_DST:
+		return 0, 1
+	case OP_SRC_OVER:
+		return 1, 1 - as
+	case OP_DST_OVER:
+		return 1 - ad, 1
+	case OP_SRC_IN:
+		return ad, 0
+	case OP_DST_IN:
+		return 0, as
+	case OP_SRC_OUT:
+		return 1 - ad, 0
+	case OP_DST_OUT:
+		return 0, 1 - as
+	case OP_SRC_ATOP:
+		return ad, 1 - as
+	case OP_DST_ATOP:
+		return 1 - ad, as
+	case OP_XOR:
+		return 1 - ad, 1 - as
+	default:
+		return 0, 0
+	}
+}
@@ -0,0 +1,10 @@
+// Ops package implements image processing operations on bitmaps: scaling
+// with nearest neighbour, bilinear or Lanczos filters, rotation, box and
+// gaussian blur, brightness and contrast adjustment, and Porter-Duff
+// compositing. Operations read from any image and write to a bitmap,
+// so they can be used where there is no GPU.
+//
+// Pixels are converted to packed rows of premultiplied values before
+// processing, so that the inner loops run over contiguous memory without
+// calls through the color or bitmap interfaces.
+package ops
@@ -0,0 +1,148 @@
+package ops_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	ops "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/ops"
+	rgba32 "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Ops_001(t *testing.T) {
+	// Scaling a solid colour results in the same colour for all filters
+	src := NewBitmap(t, 10, 5)
+	src.ClearToColor(color.RGBA{0xFF, 0x00, 0x00, 0xFF})
+	for filter := ops.FILTER_NEAREST; filter <= ops.FILTER_MAX; filter++ {
+		for _, size := range [][2]uint32{{10, 5}, {3, 2}, {27, 14}} {
+			dst := NewBitmap(t, size[0], size[1])
+			if err := ops.Scale(dst, src, filter); err != nil {
+				t.Error(filter, err)
+			} else if c := Pixel(dst, int(size[0]-1), int(size[1]-1)); c != (color.RGBA{0xFF, 0x00, 0x00, 0xFF}) {
+				t.Error(filter, "Unexpected color", c)
+			}
+		}
+	}
+}
+
+func Test_Ops_002(t *testing.T) {
+	// Nearest neighbour doubles pixels
+	src := NewBitmap(t, 2, 1)
+	src.SetAt(color.RGBA{0xFF, 0x00, 0x00, 0xFF}, 0, 0)
+	src.SetAt(color.RGBA{0x00, 0x00, 0xFF, 0xFF}, 1, 0)
+	dst := NewBitmap(t, 4, 2)
+	if err := ops.Scale(dst, src, ops.FILTER_NEAREST); err != nil {
+		t.Fatal(err)
+	}
+	for x, c := range []color.RGBA{{0xFF, 0, 0, 0xFF}, {0xFF, 0, 0, 0xFF}, {0, 0, 0xFF, 0xFF}, {0, 0, 0xFF, 0xFF}} {
+		if c_ := Pixel(dst, x, 1); c_ != c {
+			t.Error("Unexpected color at", x, c_)
+		}
+	}
+}
+
+func Test_Ops_003(t *testing.T) {
+	// Rotate by ninety degrees moves top left to top right
+	src := NewBitmap(t, 4, 2)
+	src.SetAt(color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}, 0, 0)
+	dst := NewBitmap(t, 2, 4)
+	if err := ops.Rotate(dst, src, 90); err != nil {
+		t.Fatal(err)
+	} else if c := Pixel(dst, 1, 0); c != (color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}) {
+		t.Error("Unexpected color", c)
+	} else if c := Pixel(dst, 0, 0); c != (color.RGBA{}) {
+		t.Error("Unexpected color", c)
+	}
+}
+
+func Test_Ops_004(t *testing.T) {
+	// Blur spreads a pixel, and the total is unchanged
+	src := NewBitmap(t, 5, 5)
+	src.SetAt(color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}, 2, 2)
+	dst := NewBitmap(t, 5, 5)
+	if err := ops.BoxBlur(dst, src, 1); err != nil {
+		t.Fatal(err)
+	} else if c := Pixel(dst, 1, 1); c.A != 0xFF/9 {
+		t.Error("Unexpected color", c)
+	} else if c := Pixel(dst, 0, 0); c.A != 0 {
+		t.Error("Unexpected color", c)
+	}
+	if err := ops.GaussianBlur(dst, src, 1); err != nil {
+		t.Fatal(err)
+	} else if c, c_ := Pixel(dst, 2, 2), Pixel(dst, 2, 1); c.A <= c_.A || c_.A == 0 {
+		t.Error("Unexpected colors", c, c_)
+	}
+	if err := ops.BoxBlur(NewBitmap(t, 4, 4), src, 1); err == nil {
+		t.Error("Expected error for different sizes")
+	}
+}
+
+func Test_Ops_005(t *testing.T) {
+	src := NewBitmap(t, 1, 1)
+	src.ClearToColor(color.RGBA{0x80, 0x80, 0x80, 0xFF})
+	dst := NewBitmap(t, 1, 1)
+	if err := ops.Adjust(dst, src, 1, 0); err != nil {
+		t.Error(err)
+	} else if c := Pixel(dst, 0, 0); c != (color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}) {
+		t.Error("Unexpected color", c)
+	}
+	if err := ops.Adjust(dst, src, 0, 1); err != nil {
+		t.Error(err)
+	} else if c := Pixel(dst, 0, 0); c != (color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}) {
+		t.Error("Unexpected color", c)
+	}
+	if err := ops.Adjust(dst, src, 0, 0); err != nil {
+		t.Error(err)
+	} else if c := Pixel(dst, 0, 0); c != (color.RGBA{0x80, 0x80, 0x80, 0xFF}) {
+		t.Error("Unexpected color", c)
+	}
+	if err := ops.Adjust(dst, src, 2, 0); err == nil {
+		t.Error("Expected error for brightness")
+	}
+}
+
+func Test_Ops_006(t *testing.T) {
+	dst := NewBitmap(t, 2, 2)
+	dst.ClearToColor(color.RGBA{0x00, 0x00, 0xFF, 0xFF})
+	src := NewBitmap(t, 1, 1)
+	src.ClearToColor(color.RGBA{0x80, 0x00, 0x00, 0x80})
+	if err := ops.Composite(dst, src, ops.OP_SRC_OVER, image.Pt(1, 1)); err != nil {
+		t.Fatal(err)
+	} else if c := Pixel(dst, 1, 1); c != (color.RGBA{0x80, 0x00, 0x7F, 0xFF}) {
+		t.Error("Unexpected color", c)
+	} else if c := Pixel(dst, 0, 0); c != (color.RGBA{0x00, 0x00, 0xFF, 0xFF}) {
+		t.Error("Unexpected color", c)
+	}
+	if err := ops.Composite(dst, src, ops.OP_CLEAR, image.Pt(-1, -1)); err != nil {
+		t.Fatal(err)
+	} else if c := Pixel(dst, 0, 0); c != (color.RGBA{0x00, 0x00, 0xFF, 0xFF}) {
+		t.Error("Unexpected color", c)
+	}
+	if err := ops.Composite(dst, src, ops.OP_SRC_IN, image.Pt(0, 0)); err != nil {
+		t.Fatal(err)
+	} else if c := Pixel(dst, 0, 0); c != (color.RGBA{0x80, 0x00, 0x00, 0x80}) {
+		t.Error("Unexpected color", c)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func NewBitmap(t *testing.T, w, h uint32) gopi.Bitmap {
+	t.Helper()
+	if bitmap, err := new(rgba32.Factory).New(bitmap.GetColorModel(gopi.SURFACE_FMT_RGBA32), w, h); err != nil {
+		t.Fatal(err)
+		return nil
+	} else {
+		return bitmap
+	}
+}
+
+func Pixel(bitmap gopi.Bitmap, x, y int) color.RGBA {
+	return color.RGBAModel.Convert(bitmap.At(x, y)).(color.RGBA)
+}
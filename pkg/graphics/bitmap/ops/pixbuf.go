@@ -0,0 +1,120 @@
+package ops
+
+import (
+	"image"
+	"image/color"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// pixbuf contains premultiplied RGBA pixels with values between zero
+// and one, four values per pixel and w*4 values per row
+type pixbuf struct {
+	w, h int
+	pix  []float32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	maxValue = float32(0xFFFF)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newPixbuf(w, h int) *pixbuf {
+	return &pixbuf{w, h, make([]float32, w*h*4)}
+}
+
+// load returns pixels from an image. For bitmaps the size is used
+// rather than the bounds
+func load(src image.Image) *pixbuf {
+	r := rect(src)
+	buf := newPixbuf(r.Dx(), r.Dy())
+	for y := 0; y < buf.h; y++ {
+		row := buf.row(y)
+		for x := 0; x < buf.w; x++ {
+			r, g, b, a := src.At(r.Min.X+x, r.Min.Y+y).RGBA()
+			p := row[x*4 : x*4+4 : x*4+4]
+			p[0], p[1], p[2], p[3] = float32(r)/maxValue, float32(g)/maxValue, float32(b)/maxValue, float32(a)/maxValue
+		}
+	}
+	return buf
+}
+
+// store writes pixels to a bitmap, clamping values
+func (this *pixbuf) store(dst gopi.Bitmap) error {
+	for y := 0; y < this.h; y++ {
+		row := this.row(y)
+		for x := 0; x < this.w; x++ {
+			p := row[x*4 : x*4+4 : x*4+4]
+			a := clamp(p[3], 1)
+			c := color.RGBA64{
+				uint16(clamp(p[0], a)*maxValue + 0.5),
+				uint16(clamp(p[1], a)*maxValue + 0.5),
+				uint16(clamp(p[2], a)*maxValue + 0.5),
+				uint16(a*maxValue + 0.5),
+			}
+			if err := dst.SetAt(c, x, y); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// row returns pixels for a row
+func (this *pixbuf) row(y int) []float32 {
+	i := y * this.w * 4
+	return this.pix[i : i+this.w*4 : i+this.w*4]
+}
+
+// at returns a pixel, or transparent pixel when outside the buffer
+func (this *pixbuf) at(x, y int) [4]float32 {
+	if x < 0 || y < 0 || x >= this.w || y >= this.h {
+		return [4]float32{}
+	}
+	i := (y*this.w + x) * 4
+	return [4]float32{this.pix[i], this.pix[i+1], this.pix[i+2], this.pix[i+3]}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// rect returns the rectangle for an image, where bitmaps use the
+// size rather than bounds
+func rect(img image.Image) image.Rectangle {
+	if bitmap, ok := img.(gopi.Bitmap); ok {
+		size := bitmap.Size()
+		return image.Rect(0, 0, int(size.W), int(size.H))
+	} else {
+		return img.Bounds()
+	}
+}
+
+// size returns the width and height for a bitmap
+func size(bitmap gopi.Bitmap) (int, int) {
+	r := rect(bitmap)
+	return r.Dx(), r.Dy()
+}
+
+// clamp returns a value between zero and max
+func clamp(v, max float32) float32 {
+	if v < 0 {
+		return 0
+	} else if v > max {
+		return max
+	} else {
+		return v
+	}
+}
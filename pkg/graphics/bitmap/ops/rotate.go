@@ -0,0 +1,68 @@
+package ops
+
+import (
+	"image"
+	"math"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Rotate draws the source image into the destination bitmap, rotated
+// clockwise by an angle in degrees around the centre of each. Pixels
+// outside the source image are transparent. Rotations by multiples of
+// ninety degrees copy pixels without interpolation
+func Rotate(dst gopi.Bitmap, src image.Image, degrees float64) error {
+	if dst == nil || src == nil {
+		return gopi.ErrBadParameter.WithPrefix("Rotate")
+	}
+	w, h := size(dst)
+	in := load(src)
+	out := newPixbuf(w, h)
+
+	// Inverse transform maps destination pixel centres to source
+	theta := degrees * math.Pi / 180
+	sin, cos := math.Sincos(theta)
+	if math.Mod(degrees, 90) == 0 {
+		sin, cos = math.Round(sin), math.Round(cos)
+	}
+	cx, cy := float64(w)/2, float64(h)/2
+	sx, sy := float64(in.w)/2, float64(in.h)/2
+	for y := 0; y < h; y++ {
+		row := out.row(y)
+		dy := float64(y) + 0.5 - cy
+		for x := 0; x < w; x++ {
+			dx := float64(x) + 0.5 - cx
+			u := dx*cos + dy*sin + sx - 0.5
+			v := -dx*sin + dy*cos + sy - 0.5
+			pixel := in.bilinear(u, v)
+			copy(row[x*4:x*4+4], pixel[:])
+		}
+	}
+
+	// Write destination
+	return out.store(dst)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// bilinear returns an interpolated pixel, where integer coordinates
+// are pixel centres
+func (this *pixbuf) bilinear(u, v float64) [4]float32 {
+	x0, y0 := math.Floor(u), math.Floor(v)
+	fx, fy := float32(u-x0), float32(v-y0)
+	x, y := int(x0), int(y0)
+	p00, p10 := this.at(x, y), this.at(x+1, y)
+	p01, p11 := this.at(x, y+1), this.at(x+1, y+1)
+
+	var result [4]float32
+	for i := range result {
+		top := p00[i] + (p10[i]-p00[i])*fx
+		bottom := p01[i] + (p11[i]-p01[i])*fx
+		result[i] = top + (bottom-top)*fy
+	}
+	return result
+}
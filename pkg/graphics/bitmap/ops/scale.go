@@ -0,0 +1,188 @@
+package ops
+
+import (
+	"image"
+	"math"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Filter is the interpolation used when scaling
+type Filter uint
+
+type kernel struct {
+	support float64
+	fn      func(float64) float64
+}
+
+// contrib are the weights of source pixels for one destination pixel
+type contrib struct {
+	start  int
+	weight []float32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	FILTER_NEAREST Filter = iota
+	FILTER_BILINEAR
+	FILTER_LANCZOS
+	FILTER_MAX = FILTER_LANCZOS
+)
+
+var (
+	kernels = map[Filter]kernel{
+		FILTER_NEAREST:  {0.5, box},
+		FILTER_BILINEAR: {1, triangle},
+		FILTER_LANCZOS:  {3, lanczos3},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Scale draws the source image into the destination bitmap, scaling to
+// the size of the destination. When reducing in size, the bilinear and
+// Lanczos filters average all source pixels
+func Scale(dst gopi.Bitmap, src image.Image, filter Filter) error {
+	k, exists := kernels[filter]
+	if dst == nil || src == nil || exists == false {
+		return gopi.ErrBadParameter.WithPrefix("Scale")
+	}
+	w, h := size(dst)
+	in := load(src)
+	if in.w == 0 || in.h == 0 || w == 0 || h == 0 {
+		return gopi.ErrBadParameter.WithPrefix("Scale")
+	}
+
+	// Resample horizontally and then vertically
+	tmp := newPixbuf(w, in.h)
+	for y := 0; y < in.h; y++ {
+		resample(tmp.row(y), in.row(y), weights(in.w, w, k, filter == FILTER_NEAREST), 4)
+	}
+	out := newPixbuf(w, h)
+	cols := weights(in.h, h, k, filter == FILTER_NEAREST)
+	for x := 0; x < w; x++ {
+		resample(out.pix[x*4:], tmp.pix[x*4:], cols, w*4)
+	}
+
+	// Write destination
+	return out.store(dst)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (f Filter) String() string {
+	switch f {
+	case FILTER_NEAREST:
+		return "FILTER_NEAREST"
+	case FILTER_BILINEAR:
+		return "FILTER_BILINEAR"
+	case FILTER_LANCZOS:
+		return "FILTER_LANCZOS"
+	default:
+		return "[?? Invalid Filter value]"
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// weights returns the contributions of source pixels for each
+// destination pixel. The kernel is widened when reducing in size unless
+// the filter is fixed
+func weights(in, out int, k kernel, fixed bool) []contrib {
+	scale := float64(in) / float64(out)
+	fscale := math.Max(scale, 1)
+	if fixed {
+		fscale = 1
+	}
+	support := k.support * fscale
+
+	result := make([]contrib, out)
+	for i := range result {
+		center := (float64(i) + 0.5) * scale
+		start := int(math.Max(math.Floor(center-support+0.5), 0))
+		end := int(math.Min(math.Ceil(center+support-0.5), float64(in-1)))
+		if end < start {
+			start, end = clampInt(int(center), in-1), clampInt(int(center), in-1)
+		}
+		weight := make([]float32, end-start+1)
+		sum := float32(0)
+		for j := range weight {
+			weight[j] = float32(k.fn((float64(start+j) + 0.5 - center) / fscale))
+			sum += weight[j]
+		}
+		if sum == 0 {
+			weight[len(weight)/2] = 1
+		} else {
+			for j := range weight {
+				weight[j] /= sum
+			}
+		}
+		result[i] = contrib{start, weight}
+	}
+	return result
+}
+
+// resample writes each destination pixel as the weighted sum of source
+// pixels, where stride is the distance between pixels
+func resample(dst, src []float32, contribs []contrib, stride int) {
+	for i, c := range contribs {
+		var r, g, b, a float32
+		j := c.start * stride
+		for _, w := range c.weight {
+			p := src[j : j+4 : j+4]
+			r += p[0] * w
+			g += p[1] * w
+			b += p[2] * w
+			a += p[3] * w
+			j += stride
+		}
+		p := dst[i*stride : i*stride+4 : i*stride+4]
+		p[0], p[1], p[2], p[3] = r, g, b, a
+	}
+}
+
+func box(x float64) float64 {
+	if x >= -0.5 && x < 0.5 {
+		return 1
+	}
+	return 0
+}
+
+func triangle(x float64) float64 {
+	if x = math.Abs(x); x < 1 {
+		return 1 - x
+	}
+	return 0
+}
+
+func lanczos3(x float64) float64 {
+	if x == 0 {
+		return 1
+	} else if x > -3 && x < 3 {
+		return sinc(x) * sinc(x/3)
+	}
+	return 0
+}
+
+func sinc(x float64) float64 {
+	x *= math.Pi
+	return math.Sin(x) / x
+}
+
+func clampInt(v, max int) int {
+	if v < 0 {
+		return 0
+	} else if v > max {
+		return max
+	} else {
+		return v
+	}
+}
@@ -6,10 +6,14 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"reflect"
+	"unsafe"
 
 	// Modules
 	gopi "github.com/djthorpe/gopi/v3"
 	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	barcode "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/barcode"
+	pool "github.com/djthorpe/gopi/v3/pkg/pool"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -29,6 +33,9 @@ type RGBA32 struct {
 	w, h   uint32
 	stride uint32
 	buf    []bitmap.RGBA32
+	mem    *pool.Buffer
+	locked bool
+	dirty  bitmap.Dirty
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -46,9 +53,15 @@ func (this *Factory) New(model bitmap.ColorModel, w, h uint32) (gopi.Bitmap, err
 		handle.model = model
 	}
 
-	// The stride is on 16-byte boundaries
+	// The stride is on 16-byte boundaries, and the pixels are taken
+	// from the pool of pixel buffers
 	handle.stride = bitmap.AlignUp(handle.w<<2, 16)
-	handle.buf = make([]bitmap.RGBA32, handle.h*handle.stride)
+	if mem, err := bitmap.Pixels.Get(int(handle.h * handle.stride)); err != nil {
+		return nil, err
+	} else {
+		handle.mem = mem
+		handle.buf = pixels(mem.Bytes())
+	}
 
 	// Return success
 	return handle, nil
@@ -58,6 +71,14 @@ func (this *Factory) Dispose(bitmap gopi.Bitmap) error {
 	handle := bitmap.(*RGBA32)
 	handle.w, handle.h = 0, 0
 	handle.buf = nil
+	handle.locked = false
+
+	// Return the pixels to the pool
+	if handle.mem != nil {
+		mem := handle.mem
+		handle.mem = nil
+		return mem.Release()
+	}
 	return nil
 }
 
@@ -77,12 +98,12 @@ func (this *RGBA32) String() string {
 ////////////////////////////////////////////////////////////////////////////////
 // METHODS
 
-func (RGBA32) Format() gopi.SurfaceFormat {
+func (*RGBA32) Format() gopi.SurfaceFormat {
 	return gopi.SURFACE_FMT_RGBA32
 }
 
 func (this *RGBA32) Size() gopi.Size {
-	return gopi.Size{float32(this.w), float32(this.h)}
+	return gopi.Size{W: float32(this.w), H: float32(this.h)}
 }
 
 func (this *RGBA32) ClearToColor(c color.Color) {
@@ -90,6 +111,7 @@ func (this *RGBA32) ClearToColor(c color.Color) {
 	for i := range this.buf {
 		this.buf[i] = pixel
 	}
+	this.dirty.Mark(this.rect())
 }
 
 func (this *RGBA32) At(x, y int) color.Color {
@@ -108,9 +130,53 @@ func (this *RGBA32) SetAt(c color.Color, x, y int) error {
 	pixel := this.ColorModel().Convert(c).(bitmap.RGBA32)
 	i := uint32(x) + uint32(y)*(this.stride>>2)
 	this.buf[i] = pixel
+	this.dirty.Mark(image.Rect(x, y, x+1, y+1))
+	return nil
+}
+
+func (this *RGBA32) PaintQRCode(data string, level gopi.QRLevel, bounds image.Rectangle) error {
+	return barcode.PaintQRCode(this, data, level, bounds)
+}
+
+func (this *RGBA32) PaintBarcode(data string, t gopi.BarcodeType, bounds image.Rectangle) error {
+	return barcode.PaintBarcode(this, data, t, bounds)
+}
+
+// Lock returns the pixel buffer, which is shared with the bitmap
+func (this *RGBA32) Lock() ([]byte, uint32, error) {
+	if this.buf == nil {
+		return nil, 0, gopi.ErrOutOfOrder.WithPrefix("Lock")
+	} else if this.locked {
+		return nil, 0, gopi.ErrOutOfOrder.WithPrefix("Lock")
+	} else {
+		this.locked = true
+	}
+
+	var data []byte
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&data))
+	hdr.Data = uintptr(unsafe.Pointer(&this.buf[0]))
+	hdr.Len = int(this.h * this.stride)
+	hdr.Cap = hdr.Len
+	return data, this.stride, nil
+}
+
+// Unlock does nothing more than release the lock, as the pixel buffer
+// is in memory
+func (this *RGBA32) Unlock() error {
+	if this.locked == false {
+		return gopi.ErrOutOfOrder.WithPrefix("Unlock")
+	} else {
+		this.locked = false
+	}
+	this.dirty.Mark(this.rect())
 	return nil
 }
 
+// TakeDirty returns the region changed since it was last taken
+func (this *RGBA32) TakeDirty() image.Rectangle {
+	return this.dirty.Take()
+}
+
 func (this *RGBA32) ColorModel() color.Model {
 	return this.model
 }
@@ -119,3 +185,18 @@ func (this *RGBA32) Bounds() image.Rectangle {
 	return image.Rectangle{image.Point{0, 0}, image.Point{int(this.w) - 1, int(this.h) - 1}}
 
 }
+
+// rect returns the whole bitmap as a rectangle
+func (this *RGBA32) rect() image.Rectangle {
+	return image.Rect(0, 0, int(this.w), int(this.h))
+}
+
+// pixels returns the bytes as pixels, sharing the same memory
+func pixels(data []byte) []bitmap.RGBA32 {
+	var result []bitmap.RGBA32
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&result))
+	hdr.Data = uintptr(unsafe.Pointer(&data[0]))
+	hdr.Len = len(data) >> 2
+	hdr.Cap = hdr.Len
+	return result
+}
@@ -109,6 +109,26 @@ func (this *Buffer) ReadRow(resource dx.Resource, y uint32) error {
 	}
 }
 
+// Flush writes the row to GPU memory if it has been changed
+func (this *Buffer) Flush(resource dx.Resource) error {
+	if this.data == nil {
+		return gopi.ErrInternalAppError
+	} else if this.dirty == false {
+		return nil
+	} else if err := this.write(resource, this.y, 1); err != nil {
+		return err
+	} else {
+		this.dirty = false
+		return nil
+	}
+}
+
+// Invalidate discards the row, so that it is read again from GPU memory
+func (this *Buffer) Invalidate() {
+	this.y = ^uint32(0)
+	this.dirty = false
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
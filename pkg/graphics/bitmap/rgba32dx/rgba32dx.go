@@ -11,6 +11,7 @@ import (
 	// Modules
 	gopi "github.com/djthorpe/gopi/v3"
 	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	barcode "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/barcode"
 	dx "github.com/djthorpe/gopi/v3/pkg/sys/dispmanx"
 	multierror "github.com/hashicorp/go-multierror"
 )
@@ -32,8 +33,11 @@ type RGBA32 struct {
 	dx.Resource
 	Buffer
 
-	model bitmap.ColorModel
-	w, h  uint32
+	model  bitmap.ColorModel
+	w, h   uint32
+	frame  *dx.Data
+	locked bool
+	dirty  bitmap.Dirty
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -81,8 +85,13 @@ func (this *Factory) Dispose(bitmap gopi.Bitmap) error {
 		handle.w, handle.h = 0, 0
 	}
 
-	// Dispose of buffer
+	// Dispose of buffer and frame
 	handle.Buffer.Dispose()
+	if handle.frame != nil {
+		handle.frame.Dispose()
+		handle.frame = nil
+	}
+	handle.locked = false
 
 	// Return any errors
 	return result
@@ -105,7 +114,7 @@ func (this *RGBA32) String() string {
 ////////////////////////////////////////////////////////////////////////////////
 // METHODS
 
-func (RGBA32) Format() gopi.SurfaceFormat {
+func (*RGBA32) Format() gopi.SurfaceFormat {
 	return gopi.SURFACE_FMT_RGBA32
 }
 
@@ -125,6 +134,7 @@ func (this *RGBA32) ClearToColor(c color.Color) {
 	for y := uint32(0); y < this.h; y++ {
 		this.Buffer.WriteRow(this.Resource, y)
 	}
+	this.dirty.Mark(this.rect())
 }
 
 func (this *RGBA32) At(x, y int) color.Color {
@@ -145,9 +155,74 @@ func (this *RGBA32) SetAt(c color.Color, x, y int) error {
 	}
 	pixel := this.model.Convert(c).(bitmap.RGBA32)
 	this.Buffer.SetAt(uint32(x), uint32(pixel))
+	this.dirty.Mark(image.Rect(x, y, x+1, y+1))
 	return this.Buffer.WriteRow(this.Resource, uint32(y))
 }
 
+func (this *RGBA32) PaintQRCode(data string, level gopi.QRLevel, bounds image.Rectangle) error {
+	return barcode.PaintQRCode(this, data, level, bounds)
+}
+
+func (this *RGBA32) PaintBarcode(data string, t gopi.BarcodeType, bounds image.Rectangle) error {
+	return barcode.PaintBarcode(this, data, t, bounds)
+}
+
+// Lock reads the bitmap from GPU memory into a frame buffer in a single
+// transfer and returns it. The frame buffer is allocated on first use
+// and retained until the bitmap is disposed
+func (this *RGBA32) Lock() ([]byte, uint32, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.Resource == 0 || this.locked {
+		return nil, 0, gopi.ErrOutOfOrder.WithPrefix("Lock")
+	}
+
+	// Allocate frame buffer
+	if this.frame == nil {
+		if frame := dx.NewData(this.stride * this.h); frame == nil {
+			return nil, 0, gopi.ErrInternalAppError.WithPrefix("Lock")
+		} else {
+			this.frame = frame
+		}
+	}
+
+	// Write any changed row and then read all rows
+	if err := this.Buffer.Flush(this.Resource); err != nil {
+		return nil, 0, err
+	} else if err := dx.ResourceRead(this.Resource, dx.NewRect(0, 0, this.w, this.h), this.frame.Ptr(), this.stride); err != nil {
+		return nil, 0, err
+	}
+
+	// Return success
+	this.locked = true
+	return this.frame.Byte(0)[:this.stride*this.h], this.stride, nil
+}
+
+// Unlock writes the frame buffer to GPU memory in a single transfer
+func (this *RGBA32) Unlock() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.Resource == 0 || this.locked == false {
+		return gopi.ErrOutOfOrder.WithPrefix("Unlock")
+	} else {
+		this.locked = false
+	}
+
+	// Discard the row buffer, which may now be out of date
+	this.Buffer.Invalidate()
+	this.dirty.Mark(this.rect())
+
+	// Write all rows
+	return dx.ResourceWrite(this.Resource, 0, this.stride, this.frame.Ptr(), dx.NewRect(0, 0, this.w, this.h))
+}
+
+// TakeDirty returns the region changed since it was last taken
+func (this *RGBA32) TakeDirty() image.Rectangle {
+	return this.dirty.Take()
+}
+
 func (this *RGBA32) ColorModel() color.Model {
 	return this.model
 }
@@ -155,3 +230,8 @@ func (this *RGBA32) ColorModel() color.Model {
 func (this *RGBA32) Bounds() image.Rectangle {
 	return image.Rectangle{image.Point{0, 0}, image.Point{int(this.w) - 1, int(this.h) - 1}}
 }
+
+// rect returns the whole bitmap as a rectangle
+func (this *RGBA32) rect() image.Rectangle {
+	return image.Rect(0, 0, int(this.w), int(this.h))
+}
@@ -0,0 +1,274 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+	"time"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	ops "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/ops"
+	scene "github.com/djthorpe/gopi/v3/pkg/graphics/scene"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type clock struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.TimeSync
+	gopi.Locale
+	gopi.Theme
+	*bitmap.Bitmaps
+	sync.Mutex
+
+	// Flags
+	style, zones, format, date *string
+	fg, bg, warn               *string
+
+	cfg       style
+	locations []*time.Location
+	renderer  *scene.Renderer
+	surface   gopi.Surface
+	bounds    image.Rectangle
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *clock) Define(cfg gopi.Config) error {
+	this.style = cfg.FlagString("clock.style", "digital", "Clock style (digital, analog)")
+	this.zones = cfg.FlagString("clock.zones", "", "Comma-separated timezones, or empty for local time")
+	this.format = cfg.FlagString("clock.format", "15:04", "Format of the time for digital clocks")
+	this.date = cfg.FlagString("clock.date", "Mon 2 Jan", "Format of the date, or empty to hide")
+	this.fg = cfg.FlagString("clock.color", "", "Color of the clock, or empty for the theme color")
+	this.bg = cfg.FlagString("clock.background", "", "Background color of the clock, or empty for the theme color")
+	this.warn = cfg.FlagString("clock.warn", "", "Color of the time when the system clock is not synchronized, or empty for the theme color")
+	return nil
+}
+
+func (this *clock) New(cfg gopi.Config) error {
+	this.Require(this.Logger, this.Bitmaps)
+
+	// Check style and colors
+	switch strings.ToLower(*this.style) {
+	case "digital":
+		this.cfg.analog = false
+	case "analog":
+		this.cfg.analog = true
+	default:
+		return gopi.ErrBadParameter.WithPrefix("-clock.style")
+	}
+	if strings.TrimSpace(*this.format) == "" {
+		return gopi.ErrBadParameter.WithPrefix("-clock.format")
+	}
+	for flag, value := range map[string]string{"color": *this.fg, "background": *this.bg, "warn": *this.warn} {
+		if value == "" {
+			continue
+		} else if _, err := scene.ParseColor(value); err != nil {
+			return gopi.ErrBadParameter.WithPrefix("-clock.", flag, ": ", err)
+		}
+	}
+	this.cfg.format, this.cfg.date = *this.format, *this.date
+	this.cfg.fg, this.cfg.bg, this.cfg.warn = *this.fg, *this.bg, *this.warn
+	this.cfg.locale = this.Locale
+
+	// Load timezones
+	if locations, err := loadLocations(*this.zones); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-clock.zones: ", err)
+	} else {
+		this.locations = locations
+	}
+
+	// Create renderer
+	this.renderer = scene.New(this.Bitmaps)
+
+	// Return success
+	return nil
+}
+
+func (this *clock) Dispose() error {
+	return this.Stop()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *clock) Run(ctx context.Context) error {
+	var ch <-chan gopi.Event
+	if this.Publisher != nil {
+		ch = this.Publisher.Subscribe()
+		defer this.Publisher.Unsubscribe(ch)
+	}
+
+	// Draw just after each second
+	timer := time.NewTimer(untilNextSecond(time.Now()))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-timer.C:
+			this.tick(now)
+			timer.Reset(untilNextSecond(time.Now()))
+		case evt := <-ch:
+			// Redraw when the synchronization status or theme changes
+			switch evt := evt.(type) {
+			case gopi.TimeSyncEvent:
+				if evt.Type() != gopi.TIMESYNC_EVENT_STATUS {
+					this.tick(time.Now())
+				}
+			case gopi.ThemeEvent:
+				this.tick(time.Now())
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *clock) Start(surface gopi.Surface, bounds image.Rectangle) error {
+	if surface == nil || surface.Bitmap() == nil {
+		return gopi.ErrBadParameter.WithPrefix("Start")
+	}
+
+	this.Mutex.Lock()
+	if this.surface != nil {
+		this.Mutex.Unlock()
+		return gopi.ErrOutOfOrder.WithPrefix("Start")
+	}
+	this.surface = surface
+	this.bounds = bounds
+	this.Mutex.Unlock()
+
+	// Draw immediately
+	this.tick(time.Now())
+
+	// Return success
+	return nil
+}
+
+func (this *clock) Stop() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.surface = nil
+	return nil
+}
+
+func (this *clock) Draw(dst gopi.Bitmap, bounds image.Rectangle, t time.Time) error {
+	if dst == nil {
+		return gopi.ErrBadParameter.WithPrefix("Draw")
+	}
+	if bounds.Empty() {
+		size := dst.Size()
+		bounds = image.Rect(0, 0, int(size.W), int(size.H))
+	}
+
+	// Render the clock and then copy it to the destination
+	bitmap, err := this.renderer.Render(newScene(this.colors(), this.locations, t, this.synchronized(), bounds.Dx(), bounds.Dy()))
+	if err != nil {
+		return err
+	}
+	defer this.Bitmaps.DisposeBitmap(bitmap)
+	return ops.Composite(dst, bitmap, ops.OP_SRC, bounds.Min)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *clock) String() string {
+	str := "<clock"
+	if this.cfg.analog {
+		str += " style=analog"
+	} else {
+		str += " style=digital"
+	}
+	for _, location := range this.locations {
+		str += fmt.Sprintf(" zone=%q", location)
+	}
+	if this.TimeSync != nil {
+		str += fmt.Sprint(" synchronized=", this.synchronized())
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// tick draws the clock on the surface
+func (this *clock) tick(now time.Time) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.surface == nil {
+		return
+	}
+	if err := this.Draw(this.surface.Bitmap(), this.bounds, now); err != nil {
+		this.Debug("Clock: ", err)
+	}
+}
+
+// colors returns the style with the colours from the flags, or otherwise
+// from the theme, or otherwise white on black with red warnings
+func (this *clock) colors() style {
+	cfg := this.cfg
+	cfg.fg = this.color(cfg.fg, "foreground", "white")
+	cfg.bg = this.color(cfg.bg, "background", "black")
+	cfg.warn = this.color(cfg.warn, "warning", "red")
+	return cfg
+}
+
+func (this *clock) color(value, name, fallback string) string {
+	if value != "" {
+		return value
+	} else if this.Theme == nil {
+		return fallback
+	} else if value := this.Theme.Color(name); value != "" {
+		return value
+	} else {
+		return fallback
+	}
+}
+
+// synchronized returns false when the system clock is known not to be
+// synchronized, or the status has not been read
+func (this *clock) synchronized() bool {
+	if this.TimeSync == nil {
+		return true
+	} else if status, ok := this.TimeSync.Status(); ok == false {
+		return false
+	} else {
+		return status.Synchronized
+	}
+}
+
+// loadLocations returns timezones from comma-separated names, or the
+// local timezone when there are no names
+func loadLocations(names string) ([]*time.Location, error) {
+	var result []*time.Location
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		} else if location, err := time.LoadLocation(name); err != nil {
+			return nil, err
+		} else {
+			result = append(result, location)
+		}
+	}
+	if len(result) == 0 {
+		result = append(result, time.Local)
+	}
+	return result, nil
+}
+
+// untilNextSecond returns the duration until just after the next second
+func untilNextSecond(now time.Time) time.Duration {
+	return now.Truncate(time.Second).Add(time.Second + 10*time.Millisecond).Sub(now)
+}
@@ -0,0 +1,96 @@
+package clock_test
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	// Dependencies
+	_ "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32"
+	_ "github.com/djthorpe/gopi/v3/pkg/graphics/clock"
+	_ "github.com/djthorpe/gopi/v3/pkg/graphics/theme"
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/platform"
+)
+
+type App struct {
+	gopi.Unit
+	gopi.ClockWidget
+	gopi.Theme
+	*bitmap.Bitmaps
+}
+
+type surface struct {
+	bitmap gopi.Bitmap
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (this *surface) Origin() gopi.Point  { return gopi.Point{} }
+func (this *surface) Size() gopi.Size     { return this.bitmap.Size() }
+func (this *surface) Bitmap() gopi.Bitmap { return this.bitmap }
+
+func Test_Clock_001(t *testing.T) {
+	args := []string{"-clock.zones", "UTC,Asia/Tokyo", "-clock.background", "#000000"}
+	tool.Test(t, args, new(App), func(app *App) {
+		dst, err := app.Bitmaps.NewBitmap(gopi.SURFACE_FMT_RGBA32, 160, 120)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dst.ClearToColor(image.White)
+
+		// Draw in the right half of the bitmap
+		if err := app.ClockWidget.Draw(dst, image.Rect(80, 0, 160, 120), time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		if r, _, _, _ := dst.At(10, 10).RGBA(); r != 0xFFFF {
+			t.Error("Unexpected pixel outside bounds")
+		}
+		if r, _, _, _ := dst.At(81, 1).RGBA(); r != 0 {
+			t.Error("Expected background inside bounds")
+		}
+
+		// Start drawing on a surface
+		if err := app.ClockWidget.Start(&surface{dst}, image.Rectangle{}); err != nil {
+			t.Error(err)
+		} else if err := app.ClockWidget.Start(&surface{dst}, image.Rectangle{}); err == nil {
+			t.Error("Expected error when started twice")
+		} else if err := app.ClockWidget.Stop(); err != nil {
+			t.Error(err)
+		} else if r, _, _, _ := dst.At(10, 10).RGBA(); r != 0 {
+			t.Error("Expected clock to be drawn on the surface")
+		}
+		t.Log(app.ClockWidget)
+	})
+}
+
+func Test_Clock_002(t *testing.T) {
+	tool.Test(t, []string{"-theme.mode", "dark"}, new(App), func(app *App) {
+		dst, err := app.Bitmaps.NewBitmap(gopi.SURFACE_FMT_RGBA32, 160, 120)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The background is the theme colour, which changes with the theme
+		if err := app.ClockWidget.Draw(dst, image.Rectangle{}, time.Now()); err != nil {
+			t.Fatal(err)
+		} else if r, _, _, _ := dst.At(1, 1).RGBA(); r != 0 {
+			t.Error("Expected dark background")
+		}
+		if err := app.Theme.SetMode(gopi.THEME_MODE_LIGHT); err != nil {
+			t.Fatal(err)
+		} else if err := app.ClockWidget.Draw(dst, image.Rectangle{}, time.Now()); err != nil {
+			t.Fatal(err)
+		} else if r, _, _, _ := dst.At(1, 1).RGBA(); r != 0xFFFF {
+			t.Error("Expected light background")
+		}
+	})
+}
@@ -0,0 +1,19 @@
+// Clock package implements gopi.ClockWidget, which draws the time and
+// date on a surface each second. The clock is drawn in a digital style
+// with a row for each timezone, or an analog style with a face for each
+// timezone, using the scene package to draw text and shapes.
+//
+// When the gopi.TimeSync unit is used and the system clock is not
+// synchronized, the time is drawn in the warning colour with a notice
+// underneath. The clock is redrawn as soon as the synchronization status
+// changes.
+//
+// When the gopi.Locale unit is used, the date is formatted with the names
+// of days and months in the language of the locale, and the notice is
+// translated.
+//
+// Colours which are not set with flags are the "foreground", "background"
+// and "warning" colours of the gopi.Theme unit when it is used, and the
+// clock is redrawn as soon as the theme switches between light and dark.
+// Otherwise the clock is white on black, with warnings in red.
+package clock
@@ -0,0 +1,243 @@
+package clock
+
+import (
+	"image"
+	"math"
+	"strings"
+	"time"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+	scene "github.com/djthorpe/gopi/v3/pkg/graphics/scene"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// style defines how the clock is drawn
+type style struct {
+	analog       bool
+	format, date string
+	fg, bg, warn string
+	locale       gopi.Locale
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Notice shown when the system clock is not synchronized
+	unsyncNotice = "Time not synchronized"
+
+	// Size of the face used for text
+	faceHeight = 13
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// newScene returns a scene for the clock at a time, with a row for each
+// timezone for digital clocks, or a column for analog clocks
+func newScene(cfg style, locations []*time.Location, t time.Time, synchronized bool, w, h int) *scene.Scene {
+	s := &scene.Scene{Width: uint32(w), Height: uint32(h), Background: cfg.bg}
+
+	// When not synchronized, the clock is drawn in the warning color
+	// and a notice is shown underneath
+	fg := cfg.fg
+	if synchronized == false {
+		fg = cfg.warn
+		notice := cfg.translate(unsyncNotice)
+		size := fit(notice, w, maxInt(faceHeight, h/8))
+		_, th := scene.MeasureText(notice, size)
+		h -= th
+		s.Layers = append(s.Layers, scene.Layer{
+			Type: scene.LAYER_TEXT, Y: h, Width: w,
+			Text: notice, Size: size, Color: cfg.warn, Align: "center",
+		})
+	}
+
+	// Draw the clock for each timezone
+	n := len(locations)
+	for i, location := range locations {
+		label := ""
+		if n > 1 {
+			label = zoneName(location)
+		}
+		if cfg.analog {
+			cell := image.Rect(i*w/n, 0, (i+1)*w/n, h)
+			s.Layers = append(s.Layers, analog(cfg, cell, t.In(location), label, fg)...)
+		} else {
+			cell := image.Rect(0, i*h/n, w, (i+1)*h/n)
+			s.Layers = append(s.Layers, digital(cfg, cell, t.In(location), label, fg)...)
+		}
+	}
+
+	// Return the scene
+	return s
+}
+
+// digital returns layers for the time, with the date and label
+// underneath, centred in a cell
+func digital(cfg style, cell image.Rectangle, t time.Time, label, color string) []scene.Layer {
+	text := cfg.formatTime(t, cfg.format)
+	info := infoLine(cfg, t, label)
+
+	// The time takes two thirds of the height when there is information
+	// underneath
+	th := cell.Dy()
+	if info != "" {
+		th = cell.Dy() * 2 / 3
+	}
+	size := fit(text, cell.Dx(), th)
+	_, h1 := scene.MeasureText(text, size)
+	layers := []scene.Layer{{
+		Type: scene.LAYER_TEXT, X: cell.Min.X, Width: cell.Dx(),
+		Text: text, Size: size, Color: color, Align: "center",
+	}}
+	h2 := 0
+	if info != "" {
+		size := fit(info, cell.Dx(), maxInt(faceHeight, cell.Dy()-h1))
+		_, h2 = scene.MeasureText(info, size)
+		layers = append(layers, scene.Layer{
+			Type: scene.LAYER_TEXT, X: cell.Min.X, Width: cell.Dx(),
+			Text: info, Size: size, Color: color, Align: "center",
+		})
+	}
+
+	// Centre vertically
+	y := cell.Min.Y + (cell.Dy()-h1-h2)/2
+	layers[0].Y = y
+	if len(layers) > 1 {
+		layers[1].Y = y + h1
+	}
+	return layers
+}
+
+// analog returns layers for a clock face with hands, with the date and
+// label underneath, centred in a cell
+func analog(cfg style, cell image.Rectangle, t time.Time, label, color string) []scene.Layer {
+	var layers []scene.Layer
+
+	// Information underneath the face
+	info := infoLine(cfg, t, label)
+	fh := cell.Dy()
+	if info != "" {
+		size := fit(info, cell.Dx(), maxInt(faceHeight, cell.Dy()/8))
+		_, th := scene.MeasureText(info, size)
+		fh -= th
+		layers = append(layers, scene.Layer{
+			Type: scene.LAYER_TEXT, X: cell.Min.X, Y: cell.Min.Y + fh, Width: cell.Dx(),
+			Text: info, Size: size, Color: color, Align: "center",
+		})
+	}
+
+	// Face
+	r := minInt(cell.Dx(), fh)/2 - 2
+	if r < 8 {
+		return layers
+	}
+	cx, cy := cell.Min.X+cell.Dx()/2, cell.Min.Y+fh/2
+	layers = append(layers, scene.Layer{
+		Type: scene.LAYER_ELLIPSE, X: cx - r, Y: cy - r, Width: r * 2, Height: r * 2,
+		Stroke: maxInt(1, r/30), Color: color,
+	})
+
+	// Hour marks
+	for i := 0; i < 12; i++ {
+		layers = append(layers, hand(cx, cy, float64(i)/12, float64(r)*0.8, float64(r)*0.92, maxInt(1, r/40), color))
+	}
+
+	// Hands
+	hours := (float64(t.Hour()%12) + float64(t.Minute())/60) / 12
+	minutes := (float64(t.Minute()) + float64(t.Second())/60) / 60
+	seconds := float64(t.Second()) / 60
+	layers = append(layers,
+		hand(cx, cy, hours, 0, float64(r)*0.5, maxInt(1, r/12), color),
+		hand(cx, cy, minutes, 0, float64(r)*0.75, maxInt(1, r/20), color),
+		hand(cx, cy, seconds, 0, float64(r)*0.85, 1, color),
+	)
+
+	return layers
+}
+
+// hand returns a line from the centre at a fraction of a turn clockwise
+// from twelve o'clock, between two distances from the centre
+func hand(cx, cy int, turn, from, to float64, stroke int, color string) scene.Layer {
+	a := turn * 2 * math.Pi
+	sin, cos := math.Sin(a), -math.Cos(a)
+	return scene.Layer{
+		Type:   scene.LAYER_LINE,
+		X:      cx + int(math.Round(from*sin)),
+		Y:      cy + int(math.Round(from*cos)),
+		X2:     cx + int(math.Round(to*sin)),
+		Y2:     cy + int(math.Round(to*cos)),
+		Stroke: stroke,
+		Color:  color,
+	}
+}
+
+// infoLine returns the date and label to show under the time
+func infoLine(cfg style, t time.Time, label string) string {
+	var parts []string
+	if cfg.date != "" {
+		parts = append(parts, cfg.formatTime(t, cfg.date))
+	}
+	if label != "" {
+		parts = append(parts, label)
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatTime formats a time in the language of the locale, or in English
+// when there is no locale
+func (cfg style) formatTime(t time.Time, layout string) string {
+	if cfg.locale == nil {
+		return t.Format(layout)
+	}
+	return cfg.locale.FormatTime(t, layout)
+}
+
+// translate returns a message in the language of the locale, or in English
+// when there is no locale
+func (cfg style) translate(msg string) string {
+	if cfg.locale == nil {
+		return msg
+	}
+	return cfg.locale.Translate(msg)
+}
+
+// fit returns the largest text size which fits within a width and height
+func fit(text string, w, h int) float64 {
+	for size := (h / faceHeight) * faceHeight; size > faceHeight; size -= faceHeight {
+		if tw, th := scene.MeasureText(text, float64(size)); tw <= w && th <= h {
+			return float64(size)
+		}
+	}
+	return faceHeight
+}
+
+// zoneName returns a short name for a timezone, such as "New York"
+// for "America/New_York"
+func zoneName(location *time.Location) string {
+	name := location.String()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.Replace(name, "_", " ", -1)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	} else {
+		return b
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	} else {
+		return b
+	}
+}
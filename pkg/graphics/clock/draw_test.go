@@ -0,0 +1,185 @@
+package clock
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+	"time"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	rgba32 "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32"
+	scene "github.com/djthorpe/gopi/v3/pkg/graphics/scene"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type bitmaps struct{}
+
+func (bitmaps) NewBitmap(format gopi.SurfaceFormat, w, h uint32) (gopi.Bitmap, error) {
+	return new(rgba32.Factory).New(bitmap.GetColorModel(format), w, h)
+}
+
+func (bitmaps) DisposeBitmap(bitmap gopi.Bitmap) error {
+	return new(rgba32.Factory).Dispose(bitmap)
+}
+
+// locale translates the notice and names of days into French
+type locale struct{}
+
+func (locale) Language() string                                       { return "fr" }
+func (locale) SetLanguage(string) error                               { return nil }
+func (locale) Languages() []string                                    { return []string{"fr"} }
+func (locale) FormatNumber(float64, uint) string                      { return "" }
+func (locale) Layout(gopi.LocaleLayout) string                        { return "" }
+func (locale) Plural(_, other string, _ int, _ ...interface{}) string { return other }
+
+func (locale) Translate(msg string, _ ...interface{}) string {
+	if msg == unsyncNotice {
+		return "Heure non synchronisée"
+	}
+	return msg
+}
+
+func (locale) FormatTime(t time.Time, layout string) string {
+	return strings.Replace(t.Format(layout), "Mon", "lun.", 1)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Draw_001(t *testing.T) {
+	locations, err := loadLocations("UTC, America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := style{format: "15:04", date: "Mon 2 Jan", fg: "white", bg: "black", warn: "red"}
+	now := time.Date(2021, 1, 4, 12, 0, 0, 0, time.UTC)
+
+	s := newScene(cfg, locations, now, true, 320, 240)
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	texts := []string{}
+	for _, layer := range s.Layers {
+		if layer.Color != "white" {
+			t.Error("Unexpected color", layer)
+		}
+		texts = append(texts, layer.Text)
+	}
+	if len(texts) != 4 || texts[0] != "12:00" || texts[1] != "Mon 4 Jan UTC" || texts[2] != "07:00" || texts[3] != "Mon 4 Jan New York" {
+		t.Error("Unexpected text", texts)
+	}
+	if s.Layers[2].Y < 120 || s.Layers[0].Size <= s.Layers[1].Size {
+		t.Error("Unexpected layout", s.Layers)
+	}
+}
+
+func Test_Draw_002(t *testing.T) {
+	locations, _ := loadLocations("")
+	cfg := style{format: "15:04:05", fg: "white", bg: "black", warn: "red"}
+	now := time.Now()
+
+	// When not synchronized, a notice is shown and the time is in the
+	// warning color
+	s := newScene(cfg, locations, now, false, 200, 100)
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	} else if len(s.Layers) != 2 || s.Layers[0].Text != unsyncNotice {
+		t.Fatal("Unexpected layers", s.Layers)
+	}
+	for _, layer := range s.Layers {
+		if layer.Color != "red" {
+			t.Error("Unexpected color", layer)
+		}
+	}
+	if s.Layers[1].Y+int(s.Layers[1].Size) > s.Layers[0].Y {
+		t.Error("Expected notice under the time", s.Layers)
+	}
+
+	// Render and check for the warning color
+	renderer := scene.New(bitmaps{})
+	dst, err := renderer.Render(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer renderer.DisposeBitmap(dst)
+	found := false
+	for y := 0; y < 100 && found == false; y++ {
+		for x := 0; x < 200 && found == false; x++ {
+			found = color.RGBAModel.Convert(dst.At(x, y)) == color.RGBA{0xFF, 0x00, 0x00, 0xFF}
+		}
+	}
+	if found == false {
+		t.Error("Expected pixels in the warning color")
+	}
+}
+
+func Test_Draw_003(t *testing.T) {
+	locations, _ := loadLocations("UTC")
+	cfg := style{analog: true, fg: "white", bg: "black", warn: "red"}
+	now := time.Date(2021, 1, 4, 3, 0, 0, 0, time.UTC)
+
+	s := newScene(cfg, locations, now, true, 100, 100)
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	ellipses, lines := 0, 0
+	for _, layer := range s.Layers {
+		switch layer.Type {
+		case scene.LAYER_ELLIPSE:
+			ellipses++
+		case scene.LAYER_LINE:
+			lines++
+		}
+	}
+	if ellipses != 1 || lines != 15 {
+		t.Error("Unexpected layers", s.Layers)
+	}
+
+	// At three o'clock the hour hand points right and the minute hand
+	// points up
+	hour, minute := s.Layers[len(s.Layers)-3], s.Layers[len(s.Layers)-2]
+	if hour.X2 <= hour.X || hour.Y2 != hour.Y {
+		t.Error("Unexpected hour hand", hour)
+	}
+	if minute.Y2 >= minute.Y || minute.X2 != minute.X {
+		t.Error("Unexpected minute hand", minute)
+	}
+
+	// Small clocks still have a valid scene
+	for _, size := range [][2]int{{1, 1}, {20, 10}, {10, 300}} {
+		if err := newScene(cfg, locations, now, false, size[0], size[1]).Validate(); err != nil {
+			t.Error(size, err)
+		}
+	}
+}
+
+func Test_Draw_004(t *testing.T) {
+	if _, err := loadLocations("Not/AZone"); err == nil {
+		t.Error("Expected error for unknown timezone")
+	}
+	if d := untilNextSecond(time.Date(2021, 1, 1, 0, 0, 0, 500*int(time.Millisecond), time.UTC)); d != 510*time.Millisecond {
+		t.Error("Unexpected duration", d)
+	}
+}
+
+func Test_Draw_005(t *testing.T) {
+	locations, _ := loadLocations("UTC")
+	cfg := style{format: "15:04", date: "Mon 2", fg: "white", bg: "black", warn: "red", locale: locale{}}
+	now := time.Date(2021, 1, 4, 3, 0, 0, 0, time.UTC)
+
+	// The notice and date are in the language of the locale
+	s := newScene(cfg, locations, now, false, 320, 240)
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	} else if len(s.Layers) != 3 {
+		t.Fatal("Unexpected layers", s.Layers)
+	} else if s.Layers[0].Text != "Heure non synchronisée" {
+		t.Error("Unexpected notice", s.Layers[0].Text)
+	} else if s.Layers[2].Text != "lun. 4" {
+		t.Error("Unexpected date", s.Layers[2].Text)
+	}
+}
@@ -0,0 +1,13 @@
+package clock
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.ClockWidget
+	graph.RegisterUnit(reflect.TypeOf(&clock{}), reflect.TypeOf((*gopi.ClockWidget)(nil)))
+}
@@ -0,0 +1,101 @@
+package imagecache
+
+import (
+	"bytes"
+	"image"
+	"io/ioutil"
+	"math"
+
+	slideshow "github.com/djthorpe/gopi/v3/pkg/graphics/slideshow"
+
+	// Image formats
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// window is part of an image. It does not implement gopi.Bitmap so that
+// bounds are used when scaling
+type window struct {
+	image.Image
+	r image.Rectangle
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// EXIF orientation for clockwise rotations
+	rotations = map[uint]int{0: 1, 90: 6, 180: 3, 270: 8}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this window) Bounds() image.Rectangle {
+	return this.r
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// decode reads an image, makes it upright and rotates it, and returns
+// the centre of the image with the aspect ratio of a size
+func decode(path string, w, h int, rotation uint) (image.Image, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// Reduce large images before orientation, which is slower and uses
+	// memory for every pixel
+	orientation := slideshow.Orientation(data)
+	if sideways := (orientation >= 5) != (rotation == 90 || rotation == 270); sideways {
+		src = reduce(src, h*2, w*2)
+	} else {
+		src = reduce(src, w*2, h*2)
+	}
+	src = slideshow.Orient(slideshow.Orient(src, orientation), rotations[rotation])
+
+	// Return the centre of the image
+	return window{src, cover(src.Bounds(), w, h)}, nil
+}
+
+// cover returns the largest centred rectangle within bounds with the
+// aspect ratio of w and h
+func cover(r image.Rectangle, w, h int) image.Rectangle {
+	rw, rh := r.Dx(), r.Dy()
+	if rw*h > rh*w {
+		cw := rh * w / h
+		return image.Rect(r.Min.X+(rw-cw)/2, r.Min.Y, r.Min.X+(rw-cw)/2+cw, r.Max.Y)
+	} else {
+		ch := rw * h / w
+		return image.Rect(r.Min.X, r.Min.Y+(rh-ch)/2, r.Max.X, r.Min.Y+(rh-ch)/2+ch)
+	}
+}
+
+// reduce returns an image no larger than needed to cover w and h,
+// sampling the nearest pixels, or the image when it is already smaller
+func reduce(src image.Image, w, h int) image.Image {
+	r := src.Bounds()
+	scale := math.Max(float64(w)/float64(r.Dx()), float64(h)/float64(r.Dy()))
+	if scale >= 1 {
+		return src
+	}
+	dw, dh := int(float64(r.Dx())*scale+0.5), int(float64(r.Dy())*scale+0.5)
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		sy := r.Min.Y + int(float64(y)/scale)
+		for x := 0; x < dw; x++ {
+			dst.Set(x, y, src.At(r.Min.X+int(float64(x)/scale), sy))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,16 @@
+// Imagecache package implements gopi.ImageCache, which keeps images
+// decoded and scaled to the size they are shown at, so that changing
+// image in a slideshow or user interface does not wait for decoding.
+//
+// Images are keyed by path, pixel format, size and rotation. Each image
+// is made upright using EXIF orientation, rotated, and scaled to cover
+// the size, with the centre of the image kept when the aspect ratio is
+// different. Images are reference counted between Get and Release, and
+// the least recently used images which are not in use are released when
+// the memory used exceeds -imagecache.budget megabytes.
+//
+// Prefetch queues images to be decoded one at a time in the background,
+// in the order they will be shown. Decoding ahead stops when the cache
+// is full, so that images which are about to be shown are not released
+// by images which are shown later.
+package imagecache
@@ -0,0 +1,355 @@
+package imagecache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	ops "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/ops"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type imagecache struct {
+	gopi.Unit
+	gopi.Logger
+	*bitmap.Bitmaps
+	sync.Mutex
+
+	// Flags
+	budget *uint
+
+	entries map[key]*entry
+	bitmaps map[gopi.Bitmap]*entry
+	lru     *list.List // Most recently used at the front
+	used    uint64
+	loading map[key]chan struct{}
+	queue   []key
+	wake    chan struct{}
+	hits    uint
+	misses  uint
+}
+
+// key identifies an image at a size and rotation
+type key struct {
+	path     string
+	format   gopi.SurfaceFormat
+	w, h     uint32
+	rotation uint
+}
+
+// entry is an image in the cache, which is in use when it has references
+type entry struct {
+	key
+	bitmap gopi.Bitmap
+	bytes  uint64
+	refs   uint
+	elem   *list.Element
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	megabyte = 1024 * 1024
+
+	// Estimate of the memory used by each pixel of an image which is
+	// not yet decoded
+	bytesPerPixel = 4
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *imagecache) Define(cfg gopi.Config) error {
+	this.budget = cfg.FlagUint("imagecache.budget", 64, "Memory for cached images in megabytes")
+	return nil
+}
+
+func (this *imagecache) New(gopi.Config) error {
+	this.Require(this.Logger, this.Bitmaps)
+
+	if *this.budget == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-imagecache.budget")
+	}
+
+	this.entries = make(map[key]*entry)
+	this.bitmaps = make(map[gopi.Bitmap]*entry)
+	this.lru = list.New()
+	this.loading = make(map[key]chan struct{})
+	this.wake = make(chan struct{}, 1)
+
+	// Return success
+	return nil
+}
+
+func (this *imagecache) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Release all images, including those in use
+	for _, entry := range this.entries {
+		this.disposeBitmap(entry.bitmap)
+	}
+
+	// Release resources
+	this.entries, this.bitmaps, this.lru = nil, nil, nil
+	this.queue = nil
+	this.used = 0
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *imagecache) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-this.wake:
+			// Decode queued images in order until the queue is empty or
+			// the cache is full
+			for k, ok := this.next(); ok && ctx.Err() == nil; k, ok = this.next() {
+				if _, err := this.fetch(k, false); err != nil {
+					this.Debug("ImageCache: ", filepath.Base(k.path), ": ", err)
+				}
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *imagecache) Get(path string, format gopi.SurfaceFormat, size gopi.Size, rotation uint) (gopi.Bitmap, error) {
+	if k, err := newKey(path, format, size, rotation); err != nil {
+		return nil, err
+	} else {
+		return this.fetch(k, true)
+	}
+}
+
+func (this *imagecache) Release(bitmap gopi.Bitmap) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if entry, exists := this.bitmaps[bitmap]; exists == false || entry.refs == 0 {
+		return gopi.ErrNotFound.WithPrefix("Release")
+	} else {
+		entry.refs--
+	}
+
+	// Release images when over budget
+	this.evict(this.limit())
+
+	// Return success
+	return nil
+}
+
+func (this *imagecache) Prefetch(format gopi.SurfaceFormat, size gopi.Size, rotation uint, paths ...string) {
+	queue := make([]key, 0, len(paths))
+	for _, path := range paths {
+		if k, err := newKey(path, format, size, rotation); err != nil {
+			this.Debug("ImageCache: Prefetch: ", err)
+		} else {
+			queue = append(queue, k)
+		}
+	}
+
+	// Replace the queue and wake the decoder
+	this.Mutex.Lock()
+	this.queue = queue
+	this.Mutex.Unlock()
+	select {
+	case this.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (this *imagecache) Purge() {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.evict(0)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *imagecache) String() string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	str := "<imagecache"
+	str += fmt.Sprint(" images=", len(this.entries))
+	str += fmt.Sprintf(" used=%.1fMB", float64(this.used)/megabyte)
+	str += fmt.Sprint(" budget=", *this.budget, "MB")
+	str += fmt.Sprint(" hits=", this.hits, " misses=", this.misses)
+	if len(this.queue) > 0 {
+		str += fmt.Sprint(" queued=", len(this.queue))
+	}
+	return str + ">"
+}
+
+func (k key) String() string {
+	return fmt.Sprintf("%q %vx%v %v rotation=%v", k.path, k.w, k.h, k.format, k.rotation)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// newKey returns the key for an image, and checks the size and rotation
+func newKey(path string, format gopi.SurfaceFormat, size gopi.Size, rotation uint) (key, error) {
+	w, h := uint32(size.W), uint32(size.H)
+	if path == "" {
+		return key{}, gopi.ErrBadParameter.WithPrefix("path")
+	} else if w == 0 || h == 0 {
+		return key{}, gopi.ErrBadParameter.WithPrefix("size: ", size)
+	} else if _, exists := rotations[rotation%360]; exists == false {
+		return key{}, gopi.ErrBadParameter.WithPrefix("rotation: ", rotation)
+	}
+	return key{path, format, w, h, rotation % 360}, nil
+}
+
+// fetch returns an image from the cache, or decodes and adds it, adding
+// a reference when ref is true. When another goroutine is decoding the
+// same image, fetch waits for it rather than decoding it again
+func (this *imagecache) fetch(k key, ref bool) (gopi.Bitmap, error) {
+	for {
+		this.Mutex.Lock()
+		if this.entries == nil {
+			this.Mutex.Unlock()
+			return nil, gopi.ErrOutOfOrder.WithPrefix("ImageCache")
+		} else if entry, exists := this.entries[k]; exists {
+			if ref {
+				entry.refs++
+				this.hits++
+			}
+			this.lru.MoveToFront(entry.elem)
+			this.Mutex.Unlock()
+			return entry.bitmap, nil
+		} else if ch, exists := this.loading[k]; exists {
+			this.Mutex.Unlock()
+			<-ch
+			continue
+		}
+		if ref {
+			this.misses++
+		}
+		ch := make(chan struct{})
+		this.loading[k] = ch
+		this.Mutex.Unlock()
+
+		// Decode without the lock held
+		bitmap, err := this.decode(k)
+
+		this.Mutex.Lock()
+		defer this.Mutex.Unlock()
+		delete(this.loading, k)
+		close(ch)
+		if err != nil {
+			return nil, err
+		} else if this.entries == nil {
+			this.disposeBitmap(bitmap)
+			return nil, gopi.ErrOutOfOrder.WithPrefix("ImageCache")
+		}
+
+		// Add to the cache, and release other images when over budget
+		entry := &entry{key: k, bitmap: bitmap, bytes: sizeOf(bitmap)}
+		if ref {
+			entry.refs = 1
+		}
+		entry.elem = this.lru.PushFront(entry)
+		this.entries[k], this.bitmaps[bitmap] = entry, entry
+		this.used += entry.bytes
+		this.evict(this.limit())
+		return bitmap, nil
+	}
+}
+
+// decode reads an image into a new bitmap
+func (this *imagecache) decode(k key) (gopi.Bitmap, error) {
+	src, err := decode(k.path, int(k.w), int(k.h), k.rotation)
+	if err != nil {
+		return nil, err
+	}
+	bitmap, err := this.Bitmaps.NewBitmap(k.format, k.w, k.h)
+	if err != nil {
+		return nil, err
+	} else if err := ops.Scale(bitmap, src, ops.FILTER_BILINEAR); err != nil {
+		this.disposeBitmap(bitmap)
+		return nil, err
+	}
+	return bitmap, nil
+}
+
+// next returns the next image to decode ahead of use, or false when
+// there are no images queued or the image would not fit in the cache
+// without releasing other images
+func (this *imagecache) next() (key, bool) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	for len(this.queue) > 0 {
+		k := this.queue[0]
+		if _, exists := this.entries[k]; exists {
+			this.queue = this.queue[1:]
+		} else if this.used+uint64(k.w)*uint64(k.h)*bytesPerPixel > this.limit() {
+			break
+		} else {
+			this.queue = this.queue[1:]
+			return k, true
+		}
+	}
+	this.queue = nil
+	return key{}, false
+}
+
+// evict releases the least recently used images which are not in use
+// until the memory used is within a limit, and is called with the
+// lock held
+func (this *imagecache) evict(limit uint64) {
+	if this.lru == nil {
+		return
+	}
+	for elem := this.lru.Back(); elem != nil && this.used > limit; {
+		entry := elem.Value.(*entry)
+		prev := elem.Prev()
+		if entry.refs == 0 {
+			this.lru.Remove(elem)
+			delete(this.entries, entry.key)
+			delete(this.bitmaps, entry.bitmap)
+			this.used -= entry.bytes
+			this.disposeBitmap(entry.bitmap)
+		}
+		elem = prev
+	}
+}
+
+// limit returns the memory budget in bytes
+func (this *imagecache) limit() uint64 {
+	return uint64(*this.budget) * megabyte
+}
+
+func (this *imagecache) disposeBitmap(bitmap gopi.Bitmap) {
+	if err := this.Bitmaps.DisposeBitmap(bitmap); err != nil {
+		this.Debug("ImageCache: ", err)
+	}
+}
+
+// sizeOf returns the memory used by the pixels of a bitmap
+func sizeOf(bitmap gopi.Bitmap) uint64 {
+	if data, _, err := bitmap.Lock(); err == nil {
+		defer bitmap.Unlock()
+		return uint64(len(data))
+	}
+	size := bitmap.Size()
+	return uint64(size.W) * uint64(size.H) * bytesPerPixel
+}
@@ -0,0 +1,241 @@
+package imagecache_test
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	// Dependencies
+	_ "github.com/djthorpe/gopi/v3/pkg/graphics/animation"
+	_ "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32"
+	_ "github.com/djthorpe/gopi/v3/pkg/graphics/imagecache"
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/platform"
+)
+
+type App struct {
+	gopi.Unit
+	gopi.ImageCache
+	*bitmap.Bitmaps
+}
+
+type SlideshowApp struct {
+	gopi.Unit
+	gopi.Slideshow
+	gopi.ImageCache
+	*bitmap.Bitmaps
+}
+
+type surface struct {
+	bitmap gopi.Bitmap
+}
+
+var (
+	red  = color.RGBA{0xFF, 0, 0, 0xFF}
+	blue = color.RGBA{0, 0, 0xFF, 0xFF}
+)
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (this *SlideshowApp) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (this *surface) Origin() gopi.Point  { return gopi.Point{} }
+func (this *surface) Size() gopi.Size     { return this.bitmap.Size() }
+func (this *surface) Bitmap() gopi.Bitmap { return this.bitmap }
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_ImageCache_001(t *testing.T) {
+	path := tempDir(t, "a.png", "b.png")
+	defer os.RemoveAll(path)
+
+	tool.Test(t, nil, new(App), func(app *App) {
+		size := gopi.Size{W: 32, H: 24}
+		a, err := app.ImageCache.Get(filepath.Join(path, "a.png"), gopi.SURFACE_FMT_RGBA32, size, 0)
+		if err != nil {
+			t.Fatal(err)
+		} else if a.Size() != size {
+			t.Error("Unexpected size", a.Size())
+		}
+
+		// The same image is returned from the cache, and different sizes
+		// and rotations are different images
+		if b, err := app.ImageCache.Get(filepath.Join(path, "a.png"), gopi.SURFACE_FMT_RGBA32, size, 360); err != nil {
+			t.Error(err)
+		} else if b != a {
+			t.Error("Expected image from cache")
+		} else if c, err := app.ImageCache.Get(filepath.Join(path, "a.png"), gopi.SURFACE_FMT_RGBA32, size, 90); err != nil {
+			t.Error(err)
+		} else if c == a {
+			t.Error("Expected different image for rotation")
+		} else if err := app.ImageCache.Release(c); err != nil {
+			t.Error(err)
+		}
+
+		// Each Get is released once
+		for i := 0; i < 2; i++ {
+			if err := app.ImageCache.Release(a); err != nil {
+				t.Error(err)
+			}
+		}
+		if err := app.ImageCache.Release(a); err == nil {
+			t.Error("Expected error when released too often")
+		}
+
+		// Bad parameters and missing files return errors
+		if _, err := app.ImageCache.Get(filepath.Join(path, "a.png"), gopi.SURFACE_FMT_RGBA32, size, 45); err == nil {
+			t.Error("Expected error for rotation")
+		} else if _, err := app.ImageCache.Get(filepath.Join(path, "a.png"), gopi.SURFACE_FMT_RGBA32, gopi.Size{}, 0); err == nil {
+			t.Error("Expected error for size")
+		} else if _, err := app.ImageCache.Get(filepath.Join(path, "c.png"), gopi.SURFACE_FMT_RGBA32, size, 0); err == nil {
+			t.Error("Expected error for missing file")
+		}
+
+		// Purge releases images which are not in use
+		app.ImageCache.Purge()
+		if str := fmt.Sprint(app.ImageCache); strings.Contains(str, " images=0 ") == false {
+			t.Error("Expected no images", str)
+		}
+	})
+}
+
+func Test_ImageCache_002(t *testing.T) {
+	path := tempDir(t, "a.png")
+	defer os.RemoveAll(path)
+
+	tool.Test(t, nil, new(App), func(app *App) {
+		// The left half of the image is red, and is at the top when
+		// rotated clockwise
+		img, err := app.ImageCache.Get(filepath.Join(path, "a.png"), gopi.SURFACE_FMT_RGBA32, gopi.Size{W: 24, H: 32}, 90)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer app.ImageCache.Release(img)
+		if c := color.RGBAModel.Convert(img.At(12, 2)); c != red {
+			t.Error("Expected red at top", c)
+		} else if c := color.RGBAModel.Convert(img.At(12, 29)); c != blue {
+			t.Error("Expected blue at bottom", c)
+		}
+	})
+}
+
+func Test_ImageCache_003(t *testing.T) {
+	path := tempDir(t, "a.png", "b.png", "c.png")
+	defer os.RemoveAll(path)
+
+	// Budget is one megabyte, for two images of 384KB
+	args := []string{"-imagecache.budget", "1"}
+	tool.Test(t, args, new(App), func(app *App) {
+		size := gopi.Size{W: 384, H: 256}
+		paths := []string{filepath.Join(path, "a.png"), filepath.Join(path, "b.png"), filepath.Join(path, "c.png")}
+
+		// Images are decoded ahead until the cache is full
+		app.ImageCache.Prefetch(gopi.SURFACE_FMT_RGBA32, size, 0, paths...)
+		if waitForString(app.ImageCache, " images=2 ") == false {
+			t.Fatal("Expected two images", app.ImageCache)
+		}
+
+		// Prefetched images are hits, and the least recently used image
+		// which is not in use is released for the third image
+		for i, path := range paths {
+			if img, err := app.ImageCache.Get(path, gopi.SURFACE_FMT_RGBA32, size, 0); err != nil {
+				t.Error(err)
+			} else if i != 1 {
+				app.ImageCache.Release(img)
+			}
+		}
+		str := fmt.Sprint(app.ImageCache)
+		if strings.Contains(str, " images=2 ") == false || strings.Contains(str, " hits=2 misses=1") == false {
+			t.Error("Unexpected cache", str)
+		}
+	})
+}
+
+func Test_ImageCache_004(t *testing.T) {
+	path := tempDir(t, "a.png", "b.png", "c.png")
+	defer os.RemoveAll(path)
+
+	// Slideshow uses the cache, and images after the next image are
+	// decoded ahead
+	args := []string{"-slideshow.path", path, "-slideshow.fade", "0", "-slideshow.kenburns=false"}
+	tool.Test(t, args, new(SlideshowApp), func(app *SlideshowApp) {
+		dst, err := app.Bitmaps.NewBitmap(gopi.SURFACE_FMT_RGBA32, 32, 24)
+		if err != nil {
+			t.Fatal(err)
+		} else if err := app.Slideshow.Start(&surface{dst}); err != nil {
+			t.Fatal(err)
+		}
+		if waitForString(app.ImageCache, " images=3 ") == false {
+			t.Error("Expected three images", app.ImageCache)
+		}
+		if err := app.Slideshow.Stop(); err != nil {
+			t.Error(err)
+		}
+		app.ImageCache.Purge()
+		if str := fmt.Sprint(app.ImageCache); strings.Contains(str, " images=0 ") == false {
+			t.Error("Expected images to be released", str)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// tempDir returns a folder of images, with the left half red and the
+// right half blue
+func tempDir(t *testing.T, names ...string) string {
+	t.Helper()
+	path, err := ioutil.TempDir("", "imagecache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			if x < 32 {
+				img.Set(x, y, red)
+			} else {
+				img.Set(x, y, blue)
+			}
+		}
+	}
+	for _, name := range names {
+		if fh, err := os.Create(filepath.Join(path, name)); err != nil {
+			t.Fatal(err)
+		} else if err := png.Encode(fh, img); err != nil {
+			t.Fatal(err)
+		} else {
+			fh.Close()
+		}
+	}
+	return path
+}
+
+func waitForString(cache gopi.ImageCache, str string) bool {
+	for i := 0; i < 50; i++ {
+		if strings.Contains(fmt.Sprint(cache), str) {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return false
+}
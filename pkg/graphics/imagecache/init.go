@@ -0,0 +1,13 @@
+package imagecache
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.ImageCache
+	graph.RegisterUnit(reflect.TypeOf(&imagecache{}), reflect.TypeOf((*gopi.ImageCache)(nil)))
+}
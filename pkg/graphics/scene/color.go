@@ -0,0 +1,63 @@
+package scene
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	colors = map[string]color.Color{
+		"transparent": color.Transparent,
+		"black":       color.Black,
+		"white":       color.White,
+		"red":         color.RGBA{0xFF, 0x00, 0x00, 0xFF},
+		"green":       color.RGBA{0x00, 0x80, 0x00, 0xFF},
+		"blue":        color.RGBA{0x00, 0x00, 0xFF, 0xFF},
+		"yellow":      color.RGBA{0xFF, 0xFF, 0x00, 0xFF},
+		"cyan":        color.RGBA{0x00, 0xFF, 0xFF, 0xFF},
+		"magenta":     color.RGBA{0xFF, 0x00, 0xFF, 0xFF},
+		"orange":      color.RGBA{0xFF, 0xA5, 0x00, 0xFF},
+		"grey":        color.RGBA{0x80, 0x80, 0x80, 0xFF},
+		"gray":        color.RGBA{0x80, 0x80, 0x80, 0xFF},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ParseColor returns a colour from a name or from hexadecimal #RGB,
+// #RRGGBB or #RRGGBBAA values. An empty value returns nil
+func ParseColor(value string) (color.Color, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return nil, nil
+	} else if c, exists := colors[value]; exists {
+		return c, nil
+	} else if strings.HasPrefix(value, "#") == false {
+		return nil, fmt.Errorf("Invalid color %q", value)
+	}
+
+	// Expand #RGB to #RRGGBB
+	hex := value[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return nil, fmt.Errorf("Invalid color %q", value)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid color %q", value)
+	}
+
+	// Return non-premultiplied colour
+	return color.NRGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
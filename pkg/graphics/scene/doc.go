@@ -0,0 +1,22 @@
+// Scene package renders a scene description onto a bitmap, so that
+// signage content can be generated from scripts. A scene is read from
+// JSON and has a size, a background colour and a list of layers which
+// are drawn in order: rectangles, ellipses, lines, text, images, QR
+// codes and barcodes.
+//
+// Shapes, text and images are composited onto the bitmap with a
+// Porter-Duff operator (the default is "src_over"). Text is drawn with
+// a fixed-width face scaled by whole pixels, which suits small displays
+// and LED matrices, and images are read from PNG, JPEG or GIF files and
+// scaled to size with the bitmap ops package.
+//
+// Text layers with a "font" are drawn with TrueType or OpenType fonts
+// at the size in pixels instead, using the text package. The font is a
+// comma-separated list of font files, where each character is drawn
+// with the first font which has a glyph for it, so that text in other
+// scripts and emoji can follow a text font. Text is shaped and drawn
+// in the direction of its script, for example:
+//
+//   { "type": "text", "text": "مرحبا 👋", "size": 24,
+//     "font": "NotoSans-Regular.ttf,NotoSansArabic-Regular.ttf,NotoColorEmoji.ttf" }
+package scene
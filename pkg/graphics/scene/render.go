@@ -0,0 +1,201 @@
+package scene
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	ops "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/ops"
+	text "github.com/djthorpe/gopi/v3/pkg/graphics/text"
+
+	// Image formats
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Bitmaps creates and disposes bitmaps for the renderer, and is
+// implemented by bitmap.Bitmaps
+type Bitmaps interface {
+	NewBitmap(gopi.SurfaceFormat, uint32, uint32) (gopi.Bitmap, error)
+	DisposeBitmap(gopi.Bitmap) error
+}
+
+// Renderer draws scenes onto bitmaps
+type Renderer struct {
+	Bitmaps
+	sync.Mutex
+
+	// Faces read from font files, by path
+	faces map[string]*text.Face
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// New returns a renderer which creates bitmaps for scenes and scaled
+// images
+func New(bitmaps Bitmaps) *Renderer {
+	if bitmaps == nil {
+		return nil
+	}
+	return &Renderer{Bitmaps: bitmaps, faces: make(map[string]*text.Face)}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Render returns a new bitmap of the scene size with the scene drawn on
+// it. The bitmap should be disposed by the caller
+func (this *Renderer) Render(scene *Scene) (gopi.Bitmap, error) {
+	if scene == nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("Render")
+	} else if err := scene.Validate(); err != nil {
+		return nil, err
+	}
+
+	bitmap, err := this.NewBitmap(gopi.SURFACE_FMT_RGBA32, scene.Width, scene.Height)
+	if err != nil {
+		return nil, err
+	}
+	bitmap.ClearToColor(color.Transparent)
+	if err := this.draw(bitmap, scene); err != nil {
+		this.DisposeBitmap(bitmap)
+		return nil, err
+	}
+
+	// Return success
+	return bitmap, nil
+}
+
+// Draw draws a scene on an existing bitmap, such as a surface on the
+// display. The background is only painted if the scene has one
+func (this *Renderer) Draw(dst gopi.Bitmap, scene *Scene) error {
+	if dst == nil || scene == nil {
+		return gopi.ErrBadParameter.WithPrefix("Draw")
+	} else if err := scene.Validate(); err != nil {
+		return err
+	} else {
+		return this.draw(dst, scene)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// draw paints the background and each layer in turn. The scene should
+// have been validated
+func (this *Renderer) draw(dst gopi.Bitmap, scene *Scene) error {
+	if bg, _ := ParseColor(scene.Background); bg != nil {
+		dst.ClearToColor(bg)
+	}
+	for i, layer := range scene.Layers {
+		if err := this.drawLayer(dst, scene, layer); err != nil {
+			return fmt.Errorf("Layer %d: %w", i, err)
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *Renderer) drawLayer(dst gopi.Bitmap, scene *Scene, layer Layer) error {
+	c, _ := ParseColor(layer.Color)
+	if c == nil {
+		c = color.Black
+	}
+	op, _ := parseOp(layer.Op)
+	pt := image.Pt(layer.X, layer.Y)
+	bounds := image.Rect(0, 0, layer.Width, layer.Height).Add(pt)
+
+	switch layer.Type {
+	case LAYER_RECT:
+		return ops.Composite(dst, rect(layer.Width, layer.Height, layer.Stroke, c), op, pt)
+	case LAYER_ELLIPSE:
+		return ops.Composite(dst, ellipse(layer.Width, layer.Height, layer.Stroke, c), op, pt)
+	case LAYER_LINE:
+		src, pt := line(pt, image.Pt(layer.X2, layer.Y2), layer.Stroke, c)
+		return ops.Composite(dst, src, op, pt)
+	case LAYER_TEXT:
+		if src, err := this.text(scene, layer, c); err != nil {
+			return err
+		} else if src != nil {
+			defer this.DisposeBitmap(src)
+			return ops.Composite(dst, src, op, pt)
+		}
+	case LAYER_IMAGE:
+		return this.image(dst, scene, layer, op)
+	case LAYER_QRCODE:
+		level, _ := parseLevel(layer.Level)
+		return dst.PaintQRCode(layer.Text, level, bounds)
+	case LAYER_BARCODE:
+		t, _ := parseSymbology(layer.Symbology)
+		return dst.PaintBarcode(layer.Text, t, bounds)
+	}
+
+	// Return success
+	return nil
+}
+
+// image draws an image file, scaled when the layer has a width or
+// height. When only one is set the aspect ratio is kept
+func (this *Renderer) image(dst gopi.Bitmap, scene *Scene, layer Layer, op ops.Operator) error {
+	src, err := readImage(scene.resolve(layer.Path))
+	if err != nil {
+		return err
+	}
+
+	// Composite without scaling
+	pt := image.Pt(layer.X, layer.Y)
+	w, h, r := layer.Width, layer.Height, src.Bounds()
+	if w == 0 && h == 0 {
+		return ops.Composite(dst, src, op, pt)
+	} else if r.Empty() {
+		return nil
+	} else if w == 0 {
+		w = maxInt(1, r.Dx()*h/r.Dy())
+	} else if h == 0 {
+		h = maxInt(1, r.Dy()*w/r.Dx())
+	}
+
+	// Scale and then composite
+	filter, _ := parseFilter(layer.Filter)
+	scaled, err := this.NewBitmap(gopi.SURFACE_FMT_RGBA32, uint32(w), uint32(h))
+	if err != nil {
+		return err
+	}
+	defer this.DisposeBitmap(scaled)
+	if err := ops.Scale(scaled, src, filter); err != nil {
+		return err
+	} else {
+		return ops.Composite(dst, scaled, op, pt)
+	}
+}
+
+// readImage decodes an image file
+func readImage(path string) (image.Image, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	if img, _, err := image.Decode(fh); err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	} else {
+		return img, nil
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	} else {
+		return b
+	}
+}
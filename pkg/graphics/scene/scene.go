@@ -0,0 +1,234 @@
+package scene
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	ops "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/ops"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Scene describes a bitmap with a background and layers drawn in order
+type Scene struct {
+	Width      uint32  `json:"width"`
+	Height     uint32  `json:"height"`
+	Background string  `json:"background,omitempty"`
+	Layers     []Layer `json:"layers"`
+
+	// Dir is the folder used for relative image paths
+	Dir string `json:"-"`
+}
+
+// Layer is a shape, text, image or code drawn on the scene. Which
+// fields are used depends on the type
+type Layer struct {
+	Type      string  `json:"type"`
+	X         int     `json:"x"`
+	Y         int     `json:"y"`
+	X2        int     `json:"x2,omitempty"`
+	Y2        int     `json:"y2,omitempty"`
+	Width     int     `json:"width,omitempty"`
+	Height    int     `json:"height,omitempty"`
+	Color     string  `json:"color,omitempty"`
+	Stroke    int     `json:"stroke,omitempty"`
+	Op        string  `json:"op,omitempty"`
+	Text      string  `json:"text,omitempty"`
+	Size      float64 `json:"size,omitempty"`
+	Align     string  `json:"align,omitempty"`
+	Font      string  `json:"font,omitempty"`
+	Path      string  `json:"path,omitempty"`
+	Filter    string  `json:"filter,omitempty"`
+	Level     string  `json:"level,omitempty"`
+	Symbology string  `json:"symbology,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	LAYER_RECT    = "rect"
+	LAYER_ELLIPSE = "ellipse"
+	LAYER_LINE    = "line"
+	LAYER_TEXT    = "text"
+	LAYER_IMAGE   = "image"
+	LAYER_QRCODE  = "qrcode"
+	LAYER_BARCODE = "barcode"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// READ
+
+// Read returns a scene from JSON, checking the layers
+func Read(r io.Reader) (*Scene, error) {
+	this := new(Scene)
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(this); err != nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("Scene: ", err)
+	} else if err := this.Validate(); err != nil {
+		return nil, err
+	}
+	return this, nil
+}
+
+// ReadFile returns a scene from a JSON file, where relative paths in
+// the scene are relative to the file
+func ReadFile(path string) (*Scene, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	if this, err := Read(fh); err != nil {
+		return nil, fmt.Errorf("%v: %w", filepath.Base(path), err)
+	} else {
+		this.Dir = filepath.Dir(path)
+		return this, nil
+	}
+}
+
+// Validate returns an error if the scene cannot be drawn
+func (this *Scene) Validate() error {
+	if this.Width == 0 || this.Height == 0 {
+		return gopi.ErrBadParameter.WithPrefix("Scene: width and height")
+	} else if _, err := ParseColor(this.Background); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("Scene: background: ", err)
+	}
+	for i, layer := range this.Layers {
+		if err := layer.validate(); err != nil {
+			return gopi.ErrBadParameter.WithPrefix(fmt.Sprintf("Layer %d: ", i), err)
+		}
+	}
+	return nil
+}
+
+func (this Layer) validate() error {
+	if _, err := ParseColor(this.Color); err != nil {
+		return err
+	} else if _, err := parseOp(this.Op); err != nil {
+		return err
+	} else if this.Stroke < 0 {
+		return fmt.Errorf("Invalid stroke %v", this.Stroke)
+	}
+	switch this.Type {
+	case LAYER_RECT, LAYER_ELLIPSE:
+		if this.Width <= 0 || this.Height <= 0 {
+			return fmt.Errorf("%v: width and height are required", this.Type)
+		}
+	case LAYER_LINE:
+		// NOOP
+	case LAYER_TEXT:
+		if this.Size < 0 {
+			return fmt.Errorf("%v: invalid size %v", this.Type, this.Size)
+		} else if _, err := parseAlign(this.Align); err != nil {
+			return err
+		}
+	case LAYER_IMAGE:
+		if this.Path == "" {
+			return fmt.Errorf("%v: path is required", this.Type)
+		} else if this.Width < 0 || this.Height < 0 {
+			return fmt.Errorf("%v: invalid width or height", this.Type)
+		} else if _, err := parseFilter(this.Filter); err != nil {
+			return err
+		}
+	case LAYER_QRCODE:
+		if this.Width <= 0 || this.Height <= 0 {
+			return fmt.Errorf("%v: width and height are required", this.Type)
+		} else if _, err := parseLevel(this.Level); err != nil {
+			return err
+		}
+	case LAYER_BARCODE:
+		if this.Width <= 0 || this.Height <= 0 {
+			return fmt.Errorf("%v: width and height are required", this.Type)
+		} else if _, err := parseSymbology(this.Symbology); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Invalid type %q", this.Type)
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Scene) String() string {
+	str := "<scene"
+	str += fmt.Sprintf(" size={%v,%v}", this.Width, this.Height)
+	if this.Background != "" {
+		str += fmt.Sprintf(" background=%q", this.Background)
+	}
+	str += fmt.Sprint(" layers=", len(this.Layers))
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// parseOp returns a compositing operator from a name such as "src_over"
+func parseOp(value string) (ops.Operator, error) {
+	if value == "" {
+		return ops.OP_SRC_OVER, nil
+	}
+	for op := ops.Operator(0); op <= ops.OP_MAX; op++ {
+		if op.String() == "OP_"+strings.ToUpper(value) {
+			return op, nil
+		}
+	}
+	return 0, fmt.Errorf("Invalid op %q", value)
+}
+
+// parseFilter returns a scaling filter from a name such as "lanczos"
+func parseFilter(value string) (ops.Filter, error) {
+	if value == "" {
+		return ops.FILTER_BILINEAR, nil
+	}
+	for filter := ops.Filter(0); filter <= ops.FILTER_MAX; filter++ {
+		if filter.String() == "FILTER_"+strings.ToUpper(value) {
+			return filter, nil
+		}
+	}
+	return 0, fmt.Errorf("Invalid filter %q", value)
+}
+
+// parseLevel returns a QR code error correction level from L, M, Q or H
+func parseLevel(value string) (gopi.QRLevel, error) {
+	if value == "" {
+		return gopi.QR_LEVEL_M, nil
+	}
+	for level := gopi.QRLevel(0); level <= gopi.QR_LEVEL_MAX; level++ {
+		if level.String() == "QR_LEVEL_"+strings.ToUpper(value) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("Invalid level %q", value)
+}
+
+// parseSymbology returns a barcode type from a name such as "ean13"
+func parseSymbology(value string) (gopi.BarcodeType, error) {
+	if value == "" {
+		return gopi.BARCODE_CODE128, nil
+	}
+	for t := gopi.BarcodeType(0); t <= gopi.BARCODE_MAX; t++ {
+		if t.String() == "BARCODE_"+strings.ToUpper(value) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("Invalid symbology %q", value)
+}
+
+// resolve returns a path relative to the scene folder
+func (this *Scene) resolve(path string) string {
+	if path == "" || filepath.IsAbs(path) || this.Dir == "" {
+		return path
+	} else {
+		return filepath.Join(this.Dir, path)
+	}
+}
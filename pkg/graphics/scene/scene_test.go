@@ -0,0 +1,272 @@
+package scene_test
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	rgba32 "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32"
+	scene "github.com/djthorpe/gopi/v3/pkg/graphics/scene"
+	goregular "golang.org/x/image/font/gofont/goregular"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// bitmaps creates in-memory bitmaps and counts those not disposed
+type bitmaps struct {
+	count int
+}
+
+func (this *bitmaps) NewBitmap(format gopi.SurfaceFormat, w, h uint32) (gopi.Bitmap, error) {
+	this.count++
+	return new(rgba32.Factory).New(bitmap.GetColorModel(format), w, h)
+}
+
+func (this *bitmaps) DisposeBitmap(bitmap gopi.Bitmap) error {
+	this.count--
+	return new(rgba32.Factory).Dispose(bitmap)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Scene_001(t *testing.T) {
+	tests := []struct {
+		value string
+		color color.Color
+		err   bool
+	}{
+		{"", nil, false},
+		{"red", color.RGBA{0xFF, 0x00, 0x00, 0xFF}, false},
+		{"#0F0", color.NRGBA{0x00, 0xFF, 0x00, 0xFF}, false},
+		{"#112233", color.NRGBA{0x11, 0x22, 0x33, 0xFF}, false},
+		{"#11223380", color.NRGBA{0x11, 0x22, 0x33, 0x80}, false},
+		{"#1122", nil, true},
+		{"purple-ish", nil, true},
+	}
+	for _, test := range tests {
+		if c, err := scene.ParseColor(test.value); test.err && err == nil {
+			t.Error("Expected error for", test.value)
+		} else if test.err == false && err != nil {
+			t.Error(test.value, err)
+		} else if c != test.color {
+			t.Error("Unexpected color for", test.value, c)
+		}
+	}
+}
+
+func Test_Scene_002(t *testing.T) {
+	bad := []string{
+		`{}`,
+		`{"width":10,"height":10,"unknown":1}`,
+		`{"width":10,"height":10,"layers":[{"type":"circle"}]}`,
+		`{"width":10,"height":10,"layers":[{"type":"rect"}]}`,
+		`{"width":10,"height":10,"layers":[{"type":"rect","width":1,"height":1,"op":"over"}]}`,
+		`{"width":10,"height":10,"layers":[{"type":"qrcode","width":1,"height":1,"level":"Z"}]}`,
+		`{"width":10,"height":10,"layers":[{"type":"text","align":"justify"}]}`,
+	}
+	for _, json := range bad {
+		if _, err := scene.Read(strings.NewReader(json)); err == nil {
+			t.Error("Expected error for", json)
+		}
+	}
+	if s, err := scene.Read(strings.NewReader(`{"width":10,"height":10,"background":"white","layers":[{"type":"rect","width":1,"height":1,"op":"src_over"}]}`)); err != nil {
+		t.Error(err)
+	} else {
+		t.Log(s)
+	}
+}
+
+func Test_Scene_003(t *testing.T) {
+	factory := new(bitmaps)
+	renderer := scene.New(factory)
+	s, err := scene.Read(strings.NewReader(`{
+		"width": 40, "height": 30, "background": "white",
+		"layers": [
+			{ "type": "rect", "x": 2, "y": 2, "width": 10, "height": 10, "color": "red" },
+			{ "type": "rect", "x": 20, "y": 2, "width": 10, "height": 10, "color": "blue", "stroke": 2 },
+			{ "type": "ellipse", "x": 2, "y": 14, "width": 10, "height": 10, "color": "#00FF00" },
+			{ "type": "line", "x": 0, "y": 28, "x2": 39, "y2": 28, "color": "black" },
+			{ "type": "rect", "x": 34, "y": 0, "width": 6, "height": 6, "color": "#0000FF80" }
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst, err := renderer.Render(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer factory.DisposeBitmap(dst)
+
+	tests := []struct {
+		x, y int
+		c    color.RGBA
+	}{
+		{0, 0, color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}},
+		{5, 5, color.RGBA{0xFF, 0x00, 0x00, 0xFF}},
+		{20, 2, color.RGBA{0x00, 0x00, 0xFF, 0xFF}},
+		{25, 7, color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}},
+		{7, 19, color.RGBA{0x00, 0xFF, 0x00, 0xFF}},
+		{2, 14, color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}},
+		{10, 28, color.RGBA{0x00, 0x00, 0x00, 0xFF}},
+		{10, 27, color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}},
+		{36, 2, color.RGBA{0x7F, 0x7F, 0xFF, 0xFF}},
+	}
+	for _, test := range tests {
+		if c := pixel(dst, test.x, test.y); distance(c, test.c) > 1 {
+			t.Errorf("At %v,%v: expected %v, got %v", test.x, test.y, test.c, c)
+		}
+	}
+	if factory.count != 1 {
+		t.Error("Unexpected number of bitmaps", factory.count)
+	}
+}
+
+func Test_Scene_004(t *testing.T) {
+	factory := new(bitmaps)
+	renderer := scene.New(factory)
+
+	// Text is scaled by whole pixels, so text of size 26 is twice the
+	// height of the face
+	s := &scene.Scene{Width: 100, Height: 40, Layers: []scene.Layer{
+		{Type: scene.LAYER_TEXT, Text: "Hi", Size: 26, Color: "black"},
+	}}
+	dst, err := renderer.Render(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer factory.DisposeBitmap(dst)
+	bounds := image.Rectangle{}
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 100; x++ {
+			if _, _, _, a := dst.At(x, y).RGBA(); a != 0 {
+				bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	if bounds.Empty() {
+		t.Error("Expected text to be drawn")
+	} else if bounds.Max.X > 28 || bounds.Max.Y > 26 || bounds.Dy() < 10 {
+		t.Error("Unexpected text bounds", bounds)
+	}
+	if factory.count != 1 {
+		t.Error("Unexpected number of bitmaps", factory.count)
+	}
+}
+
+func Test_Scene_005(t *testing.T) {
+	// Write a 2x1 image, and a scene which scales it relative to the
+	// scene file
+	dir := t.TempDir()
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{0xFF, 0x00, 0x00, 0xFF})
+	img.Set(1, 0, color.RGBA{0x00, 0x00, 0xFF, 0xFF})
+	if fh, err := os.Create(filepath.Join(dir, "image.png")); err != nil {
+		t.Fatal(err)
+	} else if err := png.Encode(fh, img); err != nil {
+		t.Fatal(err)
+	} else {
+		fh.Close()
+	}
+	path := filepath.Join(dir, "scene.json")
+	json := `{ "width": 40, "height": 40, "layers": [
+		{ "type": "image", "path": "image.png", "width": 8, "filter": "nearest" },
+		{ "type": "qrcode", "y": 4, "width": 30, "height": 30, "text": "ABC", "level": "L" }
+	]}`
+	if err := ioutil.WriteFile(path, []byte(json), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	factory := new(bitmaps)
+	renderer := scene.New(factory)
+	if s, err := scene.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if dst, err := renderer.Render(s); err != nil {
+		t.Fatal(err)
+	} else {
+		defer factory.DisposeBitmap(dst)
+		if c := pixel(dst, 1, 1); c != (color.RGBA{0xFF, 0x00, 0x00, 0xFF}) {
+			t.Error("Unexpected color", c)
+		} else if c := pixel(dst, 6, 3); c != (color.RGBA{0x00, 0x00, 0xFF, 0xFF}) {
+			t.Error("Unexpected color", c)
+		} else if c := pixel(dst, 9, 1); c.A != 0 {
+			t.Error("Unexpected color", c)
+		} else if c := pixel(dst, 0, 4); c != (color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}) {
+			t.Error("Expected quiet zone of QR code", c)
+		}
+	}
+	if factory.count != 1 {
+		t.Error("Unexpected number of bitmaps", factory.count)
+	}
+}
+
+func Test_Scene_006(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "regular.ttf"), goregular.TTF, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Text with a font is drawn at the size in pixels, and right to left
+	// text is aligned in the same way
+	factory := new(bitmaps)
+	renderer := scene.New(factory)
+	s := &scene.Scene{Width: 100, Height: 40, Dir: dir, Layers: []scene.Layer{
+		{Type: scene.LAYER_TEXT, Text: "Hi", Size: 20, Color: "black", Font: "regular.ttf"},
+		{Type: scene.LAYER_TEXT, Y: 20, Width: 100, Text: "שלום Hi", Size: 20, Color: "black", Font: "missing.ttf, regular.ttf", Align: "right"},
+	}}
+	if _, err := renderer.Render(s); err == nil {
+		t.Error("Expected error for missing font")
+	}
+	s.Layers[1].Font = "regular.ttf"
+	dst, err := renderer.Render(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer factory.DisposeBitmap(dst)
+	top, bottom := image.Rectangle{}, image.Rectangle{}
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 100; x++ {
+			if _, _, _, a := dst.At(x, y).RGBA(); a != 0 && y < 20 {
+				top = top.Union(image.Rect(x, y, x+1, y+1))
+			} else if a != 0 {
+				bottom = bottom.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	if top.Empty() || top.Min.X > 2 || top.Max.X > 30 {
+		t.Error("Unexpected text bounds", top)
+	} else if bottom.Empty() || bottom.Max.X < 95 {
+		t.Error("Unexpected text bounds", bottom)
+	}
+	if factory.count != 1 {
+		t.Error("Unexpected number of bitmaps", factory.count)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func pixel(bitmap gopi.Bitmap, x, y int) color.RGBA {
+	return color.RGBAModel.Convert(bitmap.At(x, y)).(color.RGBA)
+}
+
+func distance(a, b color.RGBA) int {
+	d := 0
+	for _, v := range [][2]uint8{{a.R, b.R}, {a.G, b.G}, {a.B, b.B}, {a.A, b.A}} {
+		if v[0] > v[1] {
+			d += int(v[0] - v[1])
+		} else {
+			d += int(v[1] - v[0])
+		}
+	}
+	return d
+}
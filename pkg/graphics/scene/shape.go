@@ -0,0 +1,86 @@
+package scene
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// rect returns a filled rectangle, or an outline when stroke is not zero
+func rect(w, h, stroke int, c color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if stroke == 0 || x < stroke || y < stroke || x >= w-stroke || y >= h-stroke {
+				img.Set(x, y, c)
+			}
+		}
+	}
+	return img
+}
+
+// ellipse returns a filled ellipse within a rectangle, or an outline
+// when stroke is not zero
+func ellipse(w, h, stroke int, c color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	rx, ry := float64(w)/2, float64(h)/2
+	s := float64(stroke)
+	for y := 0; y < h; y++ {
+		dy := float64(y) + 0.5 - ry
+		for x := 0; x < w; x++ {
+			dx := float64(x) + 0.5 - rx
+			if inEllipse(dx, dy, rx, ry) && (stroke == 0 || inEllipse(dx, dy, rx-s, ry-s) == false) {
+				img.Set(x, y, c)
+			}
+		}
+	}
+	return img
+}
+
+// line returns a line between the centres of two pixels with a width
+// of stroke pixels, and the point at which to draw it
+func line(p1, p2 image.Point, stroke int, c color.Color) (image.Image, image.Point) {
+	if stroke == 0 {
+		stroke = 1
+	}
+	pad := image.Pt(stroke, stroke)
+	r := image.Rectangle{p1, p2}.Canon()
+	r = image.Rectangle{r.Min.Sub(pad), r.Max.Add(pad).Add(image.Pt(1, 1))}
+
+	img := image.NewNRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	x1, y1 := float64(p1.X)+0.5, float64(p1.Y)+0.5
+	x2, y2 := float64(p2.X)+0.5, float64(p2.Y)+0.5
+	half := float64(stroke) / 2
+	for y := 0; y < r.Dy(); y++ {
+		for x := 0; x < r.Dx(); x++ {
+			px, py := float64(r.Min.X+x)+0.5, float64(r.Min.Y+y)+0.5
+			if distance(px, py, x1, y1, x2, y2) <= half {
+				img.Set(x, y, c)
+			}
+		}
+	}
+	return img, r.Min
+}
+
+// inEllipse returns true if a point relative to the centre is within
+// an ellipse with radii rx and ry
+func inEllipse(dx, dy, rx, ry float64) bool {
+	if rx <= 0 || ry <= 0 {
+		return false
+	}
+	return (dx*dx)/(rx*rx)+(dy*dy)/(ry*ry) <= 1
+}
+
+// distance returns the distance from a point to a line segment
+func distance(px, py, x1, y1, x2, y2 float64) float64 {
+	dx, dy := x2-x1, y2-y1
+	if dx == 0 && dy == 0 {
+		return math.Hypot(px-x1, py-y1)
+	}
+	t := ((px-x1)*dx + (py-y1)*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(px-(x1+t*dx), py-(y1+t*dy))
+}
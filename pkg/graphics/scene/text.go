@@ -0,0 +1,223 @@
+package scene
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	ops "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/ops"
+	text "github.com/djthorpe/gopi/v3/pkg/graphics/text"
+	font "golang.org/x/image/font"
+	basicfont "golang.org/x/image/font/basicfont"
+	fixed "golang.org/x/image/math/fixed"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type align uint
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	alignLeft align = iota
+	alignCenter
+	alignRight
+)
+
+var (
+	// Face is the fixed-width face used for text, which is scaled by
+	// whole pixels to the text size
+	Face = basicfont.Face7x13
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// MeasureText returns the width and height in pixels of one or more lines
+// of text drawn at a size
+func MeasureText(text string, size float64) (int, int) {
+	scale := textScale(size)
+	lines := strings.Split(text, "\n")
+	w := 0
+	for _, line := range lines {
+		w = maxInt(w, font.MeasureString(Face, line).Ceil())
+	}
+	return w * scale, Face.Height * len(lines) * scale
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// text returns a bitmap of the layer text, which is one or more lines
+// aligned within the layer width. It returns nil if there is no text,
+// and otherwise the bitmap should be disposed by the caller
+func (this *Renderer) text(scene *Scene, layer Layer, c color.Color) (gopi.Bitmap, error) {
+	if layer.Font != "" {
+		return this.fontText(scene, layer, c)
+	}
+	scale := textScale(layer.Size)
+
+	// Measure lines, at the size of the face
+	lines := strings.Split(layer.Text, "\n")
+	widths := make([]int, len(lines))
+	w := layer.Width / scale
+	for i, line := range lines {
+		widths[i] = font.MeasureString(Face, line).Ceil()
+		if layer.Width == 0 {
+			w = maxInt(w, widths[i])
+		}
+	}
+	h := Face.Height * len(lines)
+	if layer.Height != 0 {
+		h = layer.Height / scale
+	}
+	if w <= 0 || h <= 0 {
+		return nil, nil
+	}
+
+	// Draw lines
+	align, _ := parseAlign(layer.Align)
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	drawer := font.Drawer{Dst: img, Src: image.NewUniform(c), Face: Face}
+	for i, line := range lines {
+		drawer.Dot = fixed.P(alignText(align, w, widths[i]), Face.Ascent+i*Face.Height)
+		drawer.DrawString(line)
+	}
+
+	// Scale to text size
+	return this.textBitmap(img, w*scale, h*scale)
+}
+
+// fontText returns a bitmap of the layer text drawn with the faces in
+// the layer font files, where each character is drawn with the first
+// face which has a glyph for it. Lines are shaped and laid out in the
+// direction of their first strong character
+func (this *Renderer) fontText(scene *Scene, layer Layer, c color.Color) (gopi.Bitmap, error) {
+	chain, err := this.chain(scene, layer.Font)
+	if err != nil {
+		return nil, err
+	}
+	size := layer.Size
+	if size == 0 {
+		size = float64(Face.Height)
+	}
+
+	// Lay out lines
+	lines := strings.Split(layer.Text, "\n")
+	laid := make([]*text.Line, len(lines))
+	w := layer.Width
+	for i, line := range lines {
+		laid[i] = chain.Layout(line, size, text.DIRECTION_AUTO)
+		if layer.Width == 0 {
+			w = maxInt(w, laid[i].Width())
+		}
+	}
+	lineHeight := laid[0].Height()
+	h := lineHeight * len(lines)
+	if layer.Height != 0 {
+		h = layer.Height
+	}
+	if w <= 0 || h <= 0 {
+		return nil, nil
+	}
+
+	// Draw lines
+	align, _ := parseAlign(layer.Align)
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i, line := range laid {
+		pt := image.Pt(alignText(align, w, line.Width()), line.Ascent()+i*lineHeight)
+		if err := line.Draw(img, pt, c); err != nil {
+			return nil, err
+		}
+	}
+
+	// Return success
+	return this.textBitmap(img, w, h)
+}
+
+// chain returns the faces for a comma-separated list of font files,
+// which are read once and kept by the renderer
+func (this *Renderer) chain(scene *Scene, fonts string) (text.Chain, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.faces == nil {
+		this.faces = make(map[string]*text.Face)
+	}
+	chain := text.Chain{}
+	for _, path := range strings.Split(fonts, ",") {
+		path = scene.resolve(strings.TrimSpace(path))
+		if path == "" {
+			continue
+		} else if face, exists := this.faces[path]; exists {
+			chain = append(chain, face)
+		} else if face, err := text.Open(path); err != nil {
+			return nil, err
+		} else {
+			this.faces[path] = face
+			chain = append(chain, face)
+		}
+	}
+	if len(chain) == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("font: ", fonts)
+	}
+
+	// Return success
+	return chain, nil
+}
+
+// textBitmap returns a bitmap with text drawn on an image, scaled to
+// a width and height
+func (this *Renderer) textBitmap(img image.Image, w, h int) (gopi.Bitmap, error) {
+	bitmap, err := this.NewBitmap(gopi.SURFACE_FMT_RGBA32, uint32(w), uint32(h))
+	if err != nil {
+		return nil, err
+	} else if err := ops.Scale(bitmap, img, ops.FILTER_NEAREST); err != nil {
+		this.DisposeBitmap(bitmap)
+		return nil, err
+	}
+
+	// Return success
+	return bitmap, nil
+}
+
+// alignText returns the left of a line of text within a width
+func alignText(align align, w, width int) int {
+	switch align {
+	case alignCenter:
+		return (w - width) / 2
+	case alignRight:
+		return w - width
+	default:
+		return 0
+	}
+}
+
+// textScale returns the number of pixels for each pixel of the face
+// for a text size, which is the height of a line in pixels
+func textScale(size float64) int {
+	if size <= 0 {
+		return 1
+	} else {
+		return maxInt(1, int(math.Round(size/float64(Face.Height))))
+	}
+}
+
+// parseAlign returns text alignment from left, center or right
+func parseAlign(value string) (align, error) {
+	switch strings.ToLower(value) {
+	case "", "left":
+		return alignLeft, nil
+	case "center", "centre":
+		return alignCenter, nil
+	case "right":
+		return alignRight, nil
+	default:
+		return alignLeft, fmt.Errorf("Invalid align %q", value)
+	}
+}
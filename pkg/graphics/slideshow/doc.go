@@ -0,0 +1,13 @@
+// Slideshow package implements gopi.Slideshow, which shows images from
+// a folder on a surface. Images are rotated upright using EXIF
+// orientation, slowly panned and zoomed on each frame of the animation
+// clock, and crossfaded into the next image.
+//
+// The slideshow changes image on the input actions "next" and "previous"
+// and pauses on "pause", and can be controlled remotely with the
+// gopi.SlideshowService and gopi.SlideshowStub units.
+//
+// When the gopi.ImageCache unit is used, images are kept in the cache
+// and the images after the next image are decoded ahead of use, so that
+// changing image does not wait for decoding.
+package slideshow
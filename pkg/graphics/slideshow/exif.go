@@ -0,0 +1,119 @@
+package slideshow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	exifOrientationTag = 0x0112
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Orientation returns the EXIF orientation of JPEG data between one and
+// eight, or one when there is no orientation
+func Orientation(data []byte) int {
+	// Check for JPEG start of image
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	// Find the APP1 segment with EXIF data before the image data
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xDA || length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		if segment := data[i+4 : i+2+length]; marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// Orient returns an image transformed by an EXIF orientation so that
+// it is upright
+func Orient(src image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	r := src.Bounds()
+	w, h := r.Dx(), r.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if orientation >= 5 {
+		dst = image.NewRGBA(image.Rect(0, 0, h, w))
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Flip horizontal
+				dx, dy = w-1-x, y
+			case 3: // Rotate 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // Flip vertical
+				dx, dy = x, h-1-y
+			case 5: // Transpose
+				dx, dy = y, x
+			case 6: // Rotate 90 clockwise
+				dx, dy = h-1-y, x
+			case 7: // Transverse
+				dx, dy = h-1-y, w-1-x
+			case 8: // Rotate 90 anti-clockwise
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, src.At(r.Min.X+x, r.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// tiffOrientation returns the orientation tag from the first IFD of
+// TIFF data, or one
+func tiffOrientation(data []byte) int {
+	var order binary.ByteOrder
+	if len(data) < 8 {
+		return 1
+	} else if data[0] == 'I' && data[1] == 'I' {
+		order = binary.LittleEndian
+	} else if data[0] == 'M' && data[1] == 'M' {
+		order = binary.BigEndian
+	} else {
+		return 1
+	}
+	if order.Uint16(data[2:]) != 42 {
+		return 1
+	}
+	ifd := int(order.Uint32(data[4:]))
+	if ifd+2 > len(data) {
+		return 1
+	}
+	count := int(order.Uint16(data[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(data) {
+			return 1
+		}
+		if order.Uint16(data[entry:]) == exifOrientationTag {
+			if value := int(order.Uint16(data[entry+8:])); value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
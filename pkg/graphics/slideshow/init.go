@@ -0,0 +1,13 @@
+package slideshow
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.Slideshow
+	graph.RegisterUnit(reflect.TypeOf(&slideshow{}), reflect.TypeOf((*gopi.Slideshow)(nil)))
+}
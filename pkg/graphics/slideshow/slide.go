@@ -0,0 +1,187 @@
+package slideshow
+
+import (
+	"bytes"
+	"image"
+	"io/ioutil"
+	"math"
+	"math/rand"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	ops "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/ops"
+
+	// Image formats
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// slide is an image scaled to cover the screen, with the start and end
+// zoom and centre of the Ken Burns pan and zoom
+type slide struct {
+	path   string
+	image  gopi.Bitmap
+	z0, z1 float64
+	c0, c1 [2]float64
+}
+
+// window is part of an image. It does not implement gopi.Bitmap so that
+// bounds are used when scaling
+type window struct {
+	image.Image
+	r image.Rectangle
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Maximum zoom for pan and zoom, where the image is scaled so that
+	// pixels are not magnified at the maximum zoom
+	zoomMax = 1.2
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// newSlide reads an image, applies EXIF orientation and scales it to
+// cover a size, using a bitmap created by fn
+func newSlide(path string, size gopi.Size, kenburns bool, fn func(gopi.Size) (gopi.Bitmap, error)) (*slide, error) {
+	this := newPanZoom(path, kenburns)
+
+	// Decode the image
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// Reduce large images before orientation and scaling, which are
+	// slower and use memory for every pixel
+	size = imageSize(size, kenburns)
+	w, h := int(size.W), int(size.H)
+	orientation := Orientation(data)
+	if orientation >= 5 {
+		src = reduce(src, h*2, w*2)
+	} else {
+		src = reduce(src, w*2, h*2)
+	}
+	src = Orient(src, orientation)
+
+	// Scale the centre of the image to cover the size
+	if this.image, err = fn(gopi.Size{W: float32(w), H: float32(h)}); err != nil {
+		return nil, err
+	} else if err := ops.Scale(this.image, window{src, cover(src.Bounds(), w, h)}, ops.FILTER_BILINEAR); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return this, nil
+}
+
+// newCachedSlide returns a slide with an image from a cache, which is
+// released when the slide is disposed
+func newCachedSlide(cache gopi.ImageCache, path string, format gopi.SurfaceFormat, size gopi.Size, kenburns bool) (*slide, error) {
+	this := newPanZoom(path, kenburns)
+	if image, err := cache.Get(path, format, imageSize(size, kenburns), 0); err != nil {
+		return nil, err
+	} else {
+		this.image = image
+	}
+
+	// Return success
+	return this, nil
+}
+
+// newPanZoom returns a slide with a random pan and zoom, or no pan and
+// zoom when kenburns is false
+func newPanZoom(path string, kenburns bool) *slide {
+	this := &slide{path: path, z0: 1, z1: 1, c0: [2]float64{0.5, 0.5}, c1: [2]float64{0.5, 0.5}}
+	if kenburns {
+		this.z0, this.z1 = 1, zoomMax
+		if rand.Intn(2) == 0 {
+			this.z0, this.z1 = this.z1, this.z0
+		}
+		this.c0 = [2]float64{0.3 + rand.Float64()*0.4, 0.3 + rand.Float64()*0.4}
+		this.c1 = [2]float64{0.3 + rand.Float64()*0.4, 0.3 + rand.Float64()*0.4}
+	}
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this window) Bounds() image.Rectangle {
+	return this.r
+}
+
+// Window returns the part of the image shown at progress between zero
+// and one
+func (this *slide) Window(progress float64) image.Image {
+	progress = math.Max(0, math.Min(1, progress))
+	size := this.image.Size()
+	w, h := float64(size.W), float64(size.H)
+
+	// Window size at zoom, where a zoom of one shows the whole image
+	zoom := this.z0 + (this.z1-this.z0)*progress
+	ww, wh := w/zoom, h/zoom
+
+	// Centre the window, keeping it within the image
+	cx := (this.c0[0] + (this.c1[0]-this.c0[0])*progress) * w
+	cy := (this.c0[1] + (this.c1[1]-this.c0[1])*progress) * h
+	x := math.Max(0, math.Min(w-ww, cx-ww/2))
+	y := math.Max(0, math.Min(h-wh, cy-wh/2))
+	return window{this.image, image.Rect(int(x), int(y), int(x+ww), int(y+wh))}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// imageSize returns the size of the image for a slide shown at a size,
+// which is larger when panned and zoomed so that pixels are not
+// magnified at the maximum zoom
+func imageSize(size gopi.Size, kenburns bool) gopi.Size {
+	if kenburns {
+		return gopi.Size{W: float32(int(size.W * zoomMax)), H: float32(int(size.H * zoomMax))}
+	} else {
+		return gopi.Size{W: float32(int(size.W)), H: float32(int(size.H))}
+	}
+}
+
+// cover returns the largest centred rectangle within bounds with the
+// aspect ratio of w and h
+func cover(r image.Rectangle, w, h int) image.Rectangle {
+	rw, rh := r.Dx(), r.Dy()
+	if rw*h > rh*w {
+		cw := rh * w / h
+		return image.Rect(r.Min.X+(rw-cw)/2, r.Min.Y, r.Min.X+(rw-cw)/2+cw, r.Max.Y)
+	} else {
+		ch := rw * h / w
+		return image.Rect(r.Min.X, r.Min.Y+(rh-ch)/2, r.Max.X, r.Min.Y+(rh-ch)/2+ch)
+	}
+}
+
+// reduce returns an image no larger than needed to cover w and h,
+// sampling the nearest pixels, or the image when it is already smaller
+func reduce(src image.Image, w, h int) image.Image {
+	r := src.Bounds()
+	scale := math.Max(float64(w)/float64(r.Dx()), float64(h)/float64(r.Dy()))
+	if scale >= 1 {
+		return src
+	}
+	dw, dh := int(float64(r.Dx())*scale+0.5), int(float64(r.Dy())*scale+0.5)
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		sy := r.Min.Y + int(float64(y)/scale)
+		for x := 0; x < dw; x++ {
+			dst.Set(x, y, src.At(r.Min.X+int(float64(x)/scale), sy))
+		}
+	}
+	return dst
+}
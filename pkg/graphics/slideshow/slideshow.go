@@ -0,0 +1,500 @@
+package slideshow
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	ops "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/ops"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type slideshow struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.AnimationClock
+	gopi.ImageCache
+	*bitmap.Bitmaps
+	sync.Mutex
+
+	path              *string
+	interval, fade    *time.Duration
+	kenburns, shuffle *bool
+	fps               *uint
+
+	surface   gopi.Surface
+	work      gopi.Bitmap
+	files     []string
+	index     int
+	cur, prev *slide
+	preload   *slide
+	loading   bool
+	paused    bool
+	gen       uint
+	now       time.Duration
+	start     time.Duration
+	rendered  time.Duration
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Number of images decoded ahead of the next image when there is
+	// an image cache
+	prefetchCount = 2
+)
+
+var (
+	// File extensions for images which can be shown
+	extensions = map[string]bool{
+		".jpg": true, ".jpeg": true, ".png": true, ".gif": true,
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *slideshow) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagPath("slideshow.path", "", "Folder of images to show")
+	this.interval = cfg.FlagDuration("slideshow.interval", 10*time.Second, "Time each image is shown")
+	this.fade = cfg.FlagDuration("slideshow.fade", time.Second, "Crossfade time between images, or zero to disable")
+	this.kenburns = cfg.FlagBool("slideshow.kenburns", true, "Pan and zoom images")
+	this.shuffle = cfg.FlagBool("slideshow.shuffle", false, "Show images in random order")
+	this.fps = cfg.FlagUint("slideshow.fps", 15, "Maximum frame rate when drawing")
+	return nil
+}
+
+func (this *slideshow) New(cfg gopi.Config) error {
+	this.Require(this.Logger, this.AnimationClock, this.Bitmaps)
+
+	if *this.interval <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-slideshow.interval")
+	} else if *this.fade < 0 || *this.fade >= *this.interval {
+		return gopi.ErrBadParameter.WithPrefix("-slideshow.fade")
+	} else if *this.fps == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-slideshow.fps")
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *slideshow) Dispose() error {
+	return this.Stop()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *slideshow) Run(ctx context.Context) error {
+	if this.Publisher == nil {
+		<-ctx.Done()
+		return nil
+	}
+
+	ch := this.Publisher.Subscribe()
+	defer this.Publisher.Unsubscribe(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-ch:
+			if action, ok := evt.(gopi.InputActionEvent); ok {
+				this.action(action.Name())
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *slideshow) Start(surface gopi.Surface) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if surface == nil || surface.Bitmap() == nil {
+		return gopi.ErrBadParameter.WithPrefix("Start")
+	} else if this.surface != nil {
+		return gopi.ErrOutOfOrder.WithPrefix("Start")
+	}
+
+	// Read images in folder
+	files, err := this.readFolder(*this.path)
+	if err != nil {
+		return err
+	} else if len(files) == 0 {
+		return gopi.ErrNotFound.WithPrefix("Start: ", *this.path)
+	}
+
+	// Create bitmap for drawing the next image in crossfades
+	dst := surface.Bitmap()
+	size := dst.Size()
+	if *this.fade > 0 {
+		if this.work, err = this.Bitmaps.NewBitmap(dst.Format(), uint32(size.W), uint32(size.H)); err != nil {
+			return err
+		}
+	}
+
+	// Start animation which loads the first image
+	this.surface = surface
+	this.files = files
+	this.index = 0
+	this.gen++
+	gen := this.gen
+	this.load(gen, 0)
+	return this.AnimationClock.Animate(func(elapsed, delta time.Duration) bool {
+		return this.frame(gen, elapsed, delta)
+	})
+}
+
+func (this *slideshow) Stop() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Animation ends on the next frame
+	this.gen++
+	this.surface = nil
+	this.files = nil
+	this.loading = false
+
+	// Release bitmaps
+	for _, slide := range []*slide{this.cur, this.prev, this.preload} {
+		this.disposeSlide(slide)
+	}
+	this.cur, this.prev, this.preload = nil, nil, nil
+	if this.work != nil {
+		this.disposeBitmap(this.work)
+		this.work = nil
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *slideshow) Next() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.advance(1)
+}
+
+func (this *slideshow) Previous() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.advance(-1)
+}
+
+func (this *slideshow) Pause(paused bool) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.paused = paused
+}
+
+func (this *slideshow) Paused() bool {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.paused
+}
+
+func (this *slideshow) Current() (string, uint, uint) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if len(this.files) == 0 {
+		return "", 0, 0
+	} else {
+		return this.files[this.index], uint(this.index), uint(len(this.files))
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *slideshow) String() string {
+	str := "<slideshow"
+	if path, index, count := this.Current(); count > 0 {
+		str += fmt.Sprintf(" current=%q", filepath.Base(path))
+		str += fmt.Sprint(" index=", index, " count=", count)
+	} else if this.path != nil {
+		str += fmt.Sprintf(" path=%q", *this.path)
+	}
+	if this.Paused() {
+		str += " paused"
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// action changes image or pauses the slideshow for an input action
+func (this *slideshow) action(name string) {
+	var err error
+	switch name {
+	case "next":
+		err = this.Next()
+	case "previous":
+		err = this.Previous()
+	case "pause":
+		this.Pause(this.Paused() == false)
+	}
+	if err != nil && this.Logger != nil {
+		this.Debug("Slideshow: ", err)
+	}
+}
+
+// readFolder returns paths of images in a folder, sorted by name or
+// shuffled
+func (this *slideshow) readFolder(path string) ([]string, error) {
+	infos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if info.Mode().IsRegular() == false || strings.HasPrefix(info.Name(), ".") {
+			continue
+		} else if extensions[strings.ToLower(filepath.Ext(info.Name()))] {
+			files = append(files, filepath.Join(path, info.Name()))
+		}
+	}
+	if *this.shuffle {
+		rand.Shuffle(len(files), func(i, j int) { files[i], files[j] = files[j], files[i] })
+	} else {
+		sort.Strings(files)
+	}
+	return files, nil
+}
+
+// advance changes the index of the current image and loads it, and
+// is called with the lock held
+func (this *slideshow) advance(n int) error {
+	if this.surface == nil {
+		return gopi.ErrOutOfOrder.WithPrefix("Slideshow")
+	} else if this.loading {
+		return nil
+	}
+	count := len(this.files)
+	this.index = ((this.index+n)%count + count) % count
+	this.load(this.gen, this.index)
+	return nil
+}
+
+// load makes an image current in the background, using the preloaded
+// image when it is the same file. It is called with the lock held
+func (this *slideshow) load(gen uint, index int) {
+	path := this.files[index]
+	size := this.surface.Bitmap().Size()
+	format := this.surface.Bitmap().Format()
+	this.loading = true
+
+	// Use the preloaded image
+	if preload := this.preload; preload != nil && preload.path == path {
+		this.preload = nil
+		this.show(preload)
+		this.loading = false
+		this.preloadNext(gen, size, format)
+		return
+	}
+
+	go func() {
+		slide, err := this.newSlide(path, size, format)
+		this.Mutex.Lock()
+		defer this.Mutex.Unlock()
+		if err != nil {
+			this.Print("Slideshow: ", filepath.Base(path), ": ", err)
+		}
+		if gen != this.gen {
+			this.disposeSlide(slide)
+			return
+		}
+		if slide != nil {
+			this.show(slide)
+		} else {
+			// Skip images which cannot be read
+			this.start = this.now
+		}
+		this.loading = false
+		this.preloadNext(gen, size, format)
+	}()
+}
+
+// preloadNext reads the following image in the background
+func (this *slideshow) preloadNext(gen uint, size gopi.Size, format gopi.SurfaceFormat) {
+	if len(this.files) < 2 {
+		return
+	}
+	path := this.files[(this.index+1)%len(this.files)]
+	if this.preload != nil && this.preload.path == path {
+		return
+	}
+	this.disposeSlide(this.preload)
+	this.preload = nil
+	this.prefetch(format, size)
+	go func() {
+		slide, err := this.newSlide(path, size, format)
+		this.Mutex.Lock()
+		defer this.Mutex.Unlock()
+		if err != nil {
+			this.Debug("Slideshow: ", filepath.Base(path), ": ", err)
+		} else if gen != this.gen || this.preload != nil {
+			this.disposeSlide(slide)
+		} else {
+			this.preload = slide
+		}
+	}()
+}
+
+// show makes a slide current and starts the crossfade from the
+// previous slide
+func (this *slideshow) show(slide *slide) {
+	this.disposeSlide(this.prev)
+	this.prev, this.cur = this.cur, slide
+	this.start = this.now
+	this.rendered = 0
+}
+
+// prefetch decodes the images after the next image ahead of use when
+// there is an image cache
+func (this *slideshow) prefetch(format gopi.SurfaceFormat, size gopi.Size) {
+	if this.ImageCache == nil {
+		return
+	}
+	paths := []string{}
+	for i := 2; i < 2+prefetchCount && i < len(this.files); i++ {
+		paths = append(paths, this.files[(this.index+i)%len(this.files)])
+	}
+	this.ImageCache.Prefetch(format, imageSize(size, *this.kenburns), 0, paths...)
+}
+
+func (this *slideshow) newSlide(path string, size gopi.Size, format gopi.SurfaceFormat) (*slide, error) {
+	if this.ImageCache != nil {
+		return newCachedSlide(this.ImageCache, path, format, size, *this.kenburns)
+	}
+	return newSlide(path, size, *this.kenburns, func(size gopi.Size) (gopi.Bitmap, error) {
+		return this.Bitmaps.NewBitmap(format, uint32(size.W), uint32(size.H))
+	})
+}
+
+func (this *slideshow) disposeSlide(slide *slide) {
+	if slide == nil || slide.image == nil {
+		return
+	} else if this.ImageCache != nil {
+		if err := this.ImageCache.Release(slide.image); err != nil {
+			this.Debug("Slideshow: ", err)
+		}
+	} else {
+		this.disposeBitmap(slide.image)
+	}
+}
+
+func (this *slideshow) disposeBitmap(bitmap gopi.Bitmap) {
+	if err := this.Bitmaps.DisposeBitmap(bitmap); err != nil {
+		this.Debug("Slideshow: ", err)
+	}
+}
+
+// frame is called by the animation clock, and draws the current image
+// at the frame rate. It returns false when the slideshow is stopped
+func (this *slideshow) frame(gen uint, elapsed, delta time.Duration) bool {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if gen != this.gen {
+		return false
+	}
+
+	// Time does not pass for the current image when paused
+	this.now = elapsed
+	if this.paused || this.cur == nil {
+		this.start += delta
+	}
+	if this.cur == nil {
+		return true
+	}
+
+	// Change to the next image after the interval
+	shown := this.now - this.start
+	if shown >= *this.interval {
+		if err := this.advance(1); err != nil {
+			this.Debug("Slideshow: ", err)
+		}
+		return true
+	}
+
+	// Limit the frame rate
+	if this.rendered != 0 && this.now-this.rendered < time.Second/time.Duration(*this.fps) {
+		return true
+	}
+	this.rendered = this.now
+	if err := this.draw(shown); err != nil {
+		this.Debug("Slideshow: ", err)
+	}
+
+	// Continue animation
+	return true
+}
+
+// draw the current image, crossfading from the previous image
+func (this *slideshow) draw(shown time.Duration) error {
+	dst := this.surface.Bitmap()
+	total := float64(*this.interval + *this.fade)
+	progress := float64(shown+*this.fade) / total
+
+	// Draw the current image when there is no crossfade
+	if this.prev == nil || this.work == nil || shown >= *this.fade {
+		this.disposeSlide(this.prev)
+		this.prev = nil
+		return ops.Scale(dst, this.cur.Window(progress), ops.FILTER_BILINEAR)
+	}
+
+	// Draw the previous image at the end of its pan and zoom, and the
+	// current image, and blend them
+	if err := ops.Scale(dst, this.prev.Window(float64(*this.interval+shown)/total), ops.FILTER_BILINEAR); err != nil {
+		return err
+	} else if err := ops.Scale(this.work, this.cur.Window(progress), ops.FILTER_BILINEAR); err != nil {
+		return err
+	} else {
+		return blend(dst, this.work, float64(shown)/float64(*this.fade))
+	}
+}
+
+// blend mixes the pixels of src into dst by a fraction between zero
+// and one. Formats with components which are not bytes are not blended
+func blend(dst, src gopi.Bitmap, fraction float64) error {
+	if dst.Format() == gopi.SURFACE_FMT_RGB565 || dst.Format() == gopi.SURFACE_FMT_1BPP {
+		return ops.Scale(dst, src, ops.FILTER_NEAREST)
+	}
+	d, dstride, err := dst.Lock()
+	if err != nil {
+		return err
+	}
+	defer dst.Unlock()
+	s, sstride, err := src.Lock()
+	if err != nil {
+		return err
+	}
+	defer src.Unlock()
+	if len(d) != len(s) || dstride != sstride {
+		return gopi.ErrInternalAppError.WithPrefix("blend")
+	}
+	a := uint32(fraction * 256)
+	for i := range d {
+		d[i] = uint8((uint32(d[i])*(256-a) + uint32(s[i])*a) >> 8)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package slideshow_test
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	slideshow "github.com/djthorpe/gopi/v3/pkg/graphics/slideshow"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	// Dependencies
+	_ "github.com/djthorpe/gopi/v3/pkg/graphics/animation"
+	_ "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32"
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/platform"
+)
+
+type App struct {
+	gopi.Unit
+	gopi.Slideshow
+	*bitmap.Bitmaps
+}
+
+type surface struct {
+	bitmap gopi.Bitmap
+}
+
+// pixels is a bitmap which can be read while the slideshow draws
+type pixels struct {
+	gopi.Bitmap
+	mutex sync.Mutex
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (this *surface) Origin() gopi.Point  { return gopi.Point{} }
+func (this *surface) Size() gopi.Size     { return this.bitmap.Size() }
+func (this *surface) Bitmap() gopi.Bitmap { return this.bitmap }
+
+func (this *pixels) At(x, y int) color.Color {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.Bitmap.At(x, y)
+}
+
+func (this *pixels) SetAt(c color.Color, x, y int) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.Bitmap.SetAt(c, x, y)
+}
+
+func Test_Slideshow_001(t *testing.T) {
+	// JPEG with an EXIF orientation of six, in big endian byte order
+	data := []byte{
+		0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x1E,
+		'E', 'x', 'i', 'f', 0x00, 0x00,
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08,
+		0x00, 0x01, 0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x06, 0x00, 0x00,
+		0xFF, 0xDA,
+	}
+	if orientation := slideshow.Orientation(data); orientation != 6 {
+		t.Error("Unexpected orientation", orientation)
+	}
+	if orientation := slideshow.Orientation(data[:4]); orientation != 1 {
+		t.Error("Unexpected orientation", orientation)
+	}
+	if orientation := slideshow.Orientation([]byte("not a jpeg")); orientation != 1 {
+		t.Error("Unexpected orientation", orientation)
+	}
+}
+
+func Test_Slideshow_002(t *testing.T) {
+	// Two pixels wide and one high, red on the left
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{0xFF, 0, 0, 0xFF})
+	src.Set(1, 0, color.RGBA{0, 0, 0xFF, 0xFF})
+
+	tests := []struct {
+		orientation int
+		size        image.Point
+		red         image.Point
+	}{
+		{1, image.Pt(2, 1), image.Pt(0, 0)},
+		{2, image.Pt(2, 1), image.Pt(1, 0)},
+		{3, image.Pt(2, 1), image.Pt(1, 0)},
+		{6, image.Pt(1, 2), image.Pt(0, 0)},
+		{8, image.Pt(1, 2), image.Pt(0, 1)},
+	}
+	for _, test := range tests {
+		dst := slideshow.Orient(src, test.orientation)
+		if size := dst.Bounds().Size(); size != test.size {
+			t.Error(test.orientation, "Unexpected size", size)
+		} else if r, _, _, _ := dst.At(test.red.X, test.red.Y).RGBA(); r != 0xFFFF {
+			t.Error(test.orientation, "Unexpected pixel at", test.red)
+		}
+	}
+}
+
+func Test_Slideshow_003(t *testing.T) {
+	path, err := ioutil.TempDir("", "slideshow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+	for name, c := range map[string]color.RGBA{
+		"a.png": {0xFF, 0, 0, 0xFF},
+		"b.png": {0, 0, 0xFF, 0xFF},
+	} {
+		if err := writePNG(filepath.Join(path, name), c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	args := []string{"-slideshow.path", path, "-slideshow.fade", "0", "-slideshow.kenburns=false"}
+	tool.Test(t, args, new(App), func(app *App) {
+		bitmap, err := app.Bitmaps.NewBitmap(gopi.SURFACE_FMT_RGBA32, 32, 24)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dst := &pixels{Bitmap: bitmap}
+		if err := app.Slideshow.Start(&surface{dst}); err != nil {
+			t.Fatal(err)
+		}
+		defer app.Slideshow.Stop()
+
+		if _, _, count := app.Slideshow.Current(); count != 2 {
+			t.Error("Unexpected count", count)
+		}
+		if waitForColor(dst, color.RGBA{0xFF, 0, 0, 0xFF}) == false {
+			t.Error("Expected first image")
+		}
+		if err := app.Slideshow.Next(); err != nil {
+			t.Error(err)
+		} else if waitForColor(dst, color.RGBA{0, 0, 0xFF, 0xFF}) == false {
+			t.Error("Expected second image")
+		} else if path, index, _ := app.Slideshow.Current(); index != 1 || filepath.Base(path) != "b.png" {
+			t.Error("Unexpected current image", path, index)
+		}
+		t.Log(app.Slideshow)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func writePNG(path string, c color.Color) error {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	fh, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	return png.Encode(fh, img)
+}
+
+func waitForColor(bitmap gopi.Bitmap, c color.RGBA) bool {
+	for i := 0; i < 50; i++ {
+		if color.RGBAModel.Convert(bitmap.At(16, 12)) == c {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return false
+}
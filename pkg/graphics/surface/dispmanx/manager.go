@@ -3,8 +3,12 @@
 package dispmanx
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"sync"
+	"time"
 
 	// Modules
 	gopi "github.com/djthorpe/gopi/v3"
@@ -20,20 +24,32 @@ type Manager struct {
 	gopi.Unit
 	gopi.Logger
 	gopi.Platform
+	gopi.Metrics
 	sync.RWMutex
 	*Surfaces
 
-	display *uint
-	handle  dx.Display
-	egl     egl.EGLDisplay
-	info    dx.DisplayInfo
+	display     *uint
+	handle      dx.Display
+	egl         egl.EGLDisplay
+	info        dx.DisplayInfo
+	measurement string
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// The period for measuring GPU memory
+	measureDelta = 30 * time.Second
+)
+
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
 func (this *Manager) Define(cfg gopi.Config) error {
-	this.display = cfg.FlagUint("display", 0, "Graphics Display Number")
+	// An instance named with a number uses that display by default
+	display, _ := strconv.ParseUint(this.InstanceName(), 10, 32)
+	this.display = cfg.FlagUint("display", uint(display), "Graphics Display Number")
 	return nil
 }
 
@@ -64,6 +80,15 @@ func (this *Manager) New(gopi.Config) error {
 		return err
 	}
 
+	// Define GPU memory measurement when supported
+	if this.Metrics != nil && this.Platform.GPUMemory() != nil {
+		if m, err := this.Metrics.NewMeasurement("gpumem", "gpu uint64, reloc uint64, reloc_total uint64, malloc uint64, malloc_total uint64", this.Metrics.HostTag()); err != nil {
+			return err
+		} else {
+			this.measurement = m.Name()
+		}
+	}
+
 	// Return success
 	return nil
 }
@@ -72,6 +97,8 @@ func (this *Manager) Dispose() error {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
 
+	var result error
+
 	// Terminate EGL
 	if err := egl.EGLTerminate(this.egl); err != nil {
 		result = multierror.Append(result, err)
@@ -90,14 +117,49 @@ func (this *Manager) Dispose() error {
 	return result
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *Manager) Run(ctx context.Context) error {
+	if this.measurement == "" {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Nanosecond)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if memory := this.Platform.GPUMemory(); memory != nil {
+				if err := this.Metrics.Emit(this.measurement, nil, memory["gpu"], memory["reloc"], memory["reloc_total"], memory["malloc"], memory["malloc_total"]); err != nil {
+					this.Print("GPUMemory: ", err)
+				}
+			}
+			timer.Reset(measureDelta)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
 func (this *Manager) String() string {
 	str := "<dispmanx.surfacemanager"
+	if name := this.InstanceName(); name != "" {
+		str += " instance=" + strconv.Quote(name)
+	}
 	if size := this.Size(); size != gopi.ZeroSize {
 		str += fmt.Sprint(" size=", size)
 	}
+	if memory := this.Platform.GPUMemory(); memory != nil {
+		str += fmt.Sprintf(" gpu_mem={ gpu=%vM reloc=%vM/%vM malloc=%vM/%vM }", memory["gpu"]>>20, memory["reloc"]>>20, memory["reloc_total"]>>20, memory["malloc"]>>20, memory["malloc_total"]>>20)
+	}
+	if codecs := this.Platform.Codecs(); codecs != nil {
+		str += fmt.Sprint(" codecs=", enabledCodecs(codecs))
+	}
 	str += fmt.Sprint(" surfaces=", this.Surfaces)
 	return str + ">"
 }
@@ -215,21 +277,84 @@ func (this *Manager) DisposeSurface(ctx gopi.GraphicsContext, surface gopi.Surfa
 */
 
 func (this *Manager) CreateBitmap(fmt gopi.SurfaceFormat, size gopi.Size) (gopi.Bitmap, error) {
-	return this.Surfaces.NewBitmap(fmt, uint32(size.W), uint32(size.H))
+	bitmap, err := this.Surfaces.NewBitmap(fmt, uint32(size.W), uint32(size.H))
+	if err != nil {
+		// Report free GPU memory, which is the usual cause of failure
+		if memory := this.Platform.GPUMemory(); memory != nil {
+			this.Debugf("CreateBitmap: %v (reloc=%vM/%vM)", err, memory["reloc"]>>20, memory["reloc_total"]>>20)
+		}
+		return nil, err
+	}
+	return bitmap, nil
+}
+
+// enabledCodecs returns the names of the enabled hardware codecs
+func enabledCodecs(codecs map[string]bool) []string {
+	result := make([]string, 0, len(codecs))
+	for codec, enabled := range codecs {
+		if enabled {
+			result = append(result, codec)
+		}
+	}
+	sort.Strings(result)
+	return result
 }
 
 func (this *Manager) DisposeBitmap(bitmap gopi.Bitmap) error {
 	return this.Surfaces.DisposeBitmap(bitmap)
 }
 
+// SetTransform rotates, flips and scales a surface using the GPU
+func (this *Manager) SetTransform(surface gopi.Surface, t gopi.SurfaceTransform) error {
+	surface_, ok := surface.(*Surface)
+	if ok == false {
+		return gopi.ErrBadParameter.WithPrefix("SetTransform")
+	}
+	transform, err := toTransform(t.Flags)
+	if err != nil {
+		return err
+	}
+	return this.Do(func(ctx *Context) error {
+		return surface_.SetTransform(ctx.Update, transform, t.Src, t.Dest)
+	})
+}
+
+// toTransform returns the dispmanx transform for transform flags, which
+// can include only one rotation
+func toTransform(flags gopi.SurfaceTransformFlags) (dx.Transform, error) {
+	transform := dx.DISPMANX_NO_ROTATE
+	switch flags & gopi.SURFACE_TRANSFORM_ROTATE {
+	case gopi.SURFACE_TRANSFORM_NONE:
+		break
+	case gopi.SURFACE_TRANSFORM_ROTATE_90:
+		transform = dx.DISPMANX_ROTATE_90
+	case gopi.SURFACE_TRANSFORM_ROTATE_180:
+		transform = dx.DISPMANX_ROTATE_180
+	case gopi.SURFACE_TRANSFORM_ROTATE_270:
+		transform = dx.DISPMANX_ROTATE_270
+	default:
+		return 0, gopi.ErrBadParameter.WithPrefix("SetTransform: ", flags)
+	}
+	if flags&gopi.SURFACE_TRANSFORM_FLIP_HORIZONTAL != 0 {
+		transform |= dx.DISPMANX_FLIP_HRIZ
+	}
+	if flags&gopi.SURFACE_TRANSFORM_FLIP_VERTICAL != 0 {
+		transform |= dx.DISPMANX_FLIP_VERT
+	}
+	return transform, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // DO
-/*
-func (this *Manager) Do(cb gopi.SurfaceManagerCallback) error {
+
+// Do calls a function to make graphics updates and then submits the
+// changed regions of all surfaces in a single update, so that several
+// changes are shown together
+func (this *Manager) Do(cb func(*Context) error) error {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
 
-	ctx, err := NewContext(this.handle, 0)
+	ctx, err := NewContext(this.handle, DEFAULT_UPDATE_PRIORITY)
 	if err != nil {
 		return err
 	}
@@ -241,7 +366,14 @@ func (this *Manager) Do(cb gopi.SurfaceManagerCallback) error {
 		}
 	}
 
-	// Swap buffers by disposing of context
+	// Mark changed regions of surfaces
+	if n, err := this.Surfaces.Modified(ctx); err != nil {
+		result = multierror.Append(result, err)
+	} else if n > 0 {
+		this.Debugf("Do: %v modified surfaces", n)
+	}
+
+	// Submit the update by disposing of context
 	if err := ctx.Dispose(); err != nil {
 		result = multierror.Append(result, err)
 	}
@@ -250,6 +382,7 @@ func (this *Manager) Do(cb gopi.SurfaceManagerCallback) error {
 	return result
 }
 
+/*
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
@@ -4,9 +4,11 @@ package dispmanx
 
 import (
 	"fmt"
+	"image"
 	"sync"
 
 	gopi "github.com/djthorpe/gopi/v3"
+	rgba32dx "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32dx"
 	dx "github.com/djthorpe/gopi/v3/pkg/sys/dispmanx"
 	multierror "github.com/hashicorp/go-multierror"
 )
@@ -18,17 +20,18 @@ type Surface struct {
 	sync.RWMutex
 	dx.Element
 
-	x, y    int32
-	w, h    uint32
-	opacity uint8
-	layer   uint16
-	bitmap  *Bitmap
+	x, y      int32
+	w, h      uint32
+	opacity   uint8
+	layer     uint16
+	bitmap    *rgba32dx.RGBA32
+	transform dx.Transform
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
-func NewSurfaceWithBitmap(update dx.Update, display dx.Display, bitmap *Bitmap, x, y int32, w, h uint32, layer uint16, opacity uint8) (*Surface, error) {
+func NewSurfaceWithBitmap(update dx.Update, display dx.Display, bitmap *rgba32dx.RGBA32, x, y int32, w, h uint32, layer uint16, opacity uint8) (*Surface, error) {
 	this := new(Surface)
 
 	// Check parameters
@@ -43,12 +46,14 @@ func NewSurfaceWithBitmap(update dx.Update, display dx.Display, bitmap *Bitmap,
 	// Set src to bitmap size
 	var resource dx.Resource
 	if bitmap != nil {
+		size := bitmap.Size()
 		resource = bitmap.Resource
-		src = dx.NewRect(0, 0, bitmap.w<<16, bitmap.h<<16)
+		src = dx.NewRect(0, 0, uint32(size.W)<<16, uint32(size.H)<<16)
+		bitmap.TakeDirty()
 	}
 
 	// Create native surface
-	if element, err := dx.ElementAdd(ctx, display, layer, dest, resource, src, 0, dx.NewAlphaFromSource(opacity), nil, dx.DISPMANX_NO_ROTATE); err != nil {
+	if element, err := dx.ElementAdd(update, display, layer, dest, resource, src, 0, dx.NewAlphaFromSource(opacity), nil, dx.DISPMANX_NO_ROTATE); err != nil {
 		dx.ResourceDelete(resource)
 		return nil, err
 	} else {
@@ -86,6 +91,7 @@ func (this *Surface) Dispose(ctx dx.Update) error {
 	this.bitmap = nil
 	this.x, this.y, this.w, this.h = 0, 0, 0, 0
 	this.layer, this.opacity = 0, 0
+	this.transform = dx.DISPMANX_NO_ROTATE
 
 	// Return any errors
 	return result
@@ -117,6 +123,77 @@ func (this *Surface) Bitmap() gopi.Bitmap {
 	return this.bitmap
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// Modified adds the region of the bitmap which has changed to the update,
+// and returns false if there were no changes
+func (this *Surface) Modified(update dx.Update) (bool, error) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	// Check state
+	if this.Element == 0 || update == 0 {
+		return false, gopi.ErrOutOfOrder.WithPrefix("Modified")
+	} else if this.bitmap == nil {
+		return false, nil
+	}
+
+	// Mark the changed region
+	if r := this.bitmap.TakeDirty(); r.Empty() {
+		return false, nil
+	} else if err := dx.ElementModified(update, this.Element, dx.NewRect(int32(r.Min.X), int32(r.Min.Y), uint32(r.Dx()), uint32(r.Dy()))); err != nil {
+		return false, err
+	}
+
+	// Return success
+	return true, nil
+}
+
+// SetTransform rotates and flips the surface, and scales the source region
+// of the bitmap to the destination region. Empty regions are the whole
+// bitmap and the surface bounds
+func (this *Surface) SetTransform(update dx.Update, transform dx.Transform, src, dest image.Rectangle) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Check state
+	if this.Element == 0 || update == 0 {
+		return gopi.ErrOutOfOrder.WithPrefix("SetTransform")
+	}
+
+	// Set source region, which is in 16.16 fixed point
+	if src.Empty() {
+		if this.bitmap != nil {
+			size := this.bitmap.Size()
+			src = image.Rect(0, 0, int(size.W), int(size.H))
+		} else {
+			src = image.Rect(0, 0, int(this.w), int(this.h))
+		}
+	}
+	srcrect := dx.NewRect(int32(src.Min.X)<<16, int32(src.Min.Y)<<16, uint32(src.Dx())<<16, uint32(src.Dy())<<16)
+
+	// Set destination region
+	if dest.Empty() {
+		dest = image.Rect(int(this.x), int(this.y), int(this.x)+int(this.w), int(this.y)+int(this.h))
+	}
+	destrect := dx.NewRect(int32(dest.Min.X), int32(dest.Min.Y), uint32(dest.Dx()), uint32(dest.Dy()))
+
+	// Change attributes
+	flags := dx.ELEMENT_CHANGE_SRC_RECT | dx.ELEMENT_CHANGE_DEST_RECT | dx.ELEMENT_CHANGE_TRANSFORM
+	if err := dx.ElementChangeAttributes(update, this.Element, flags, this.layer, this.opacity, destrect, srcrect, transform); err != nil {
+		return err
+	}
+
+	// Set surface parameters
+	this.transform = transform
+	this.x, this.y = int32(dest.Min.X), int32(dest.Min.Y)
+	this.w, this.h = uint32(dest.Dx()), uint32(dest.Dy())
+
+	// Return success
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -127,6 +204,9 @@ func (this *Surface) String() string {
 	str := "<surface"
 	str += fmt.Sprintf(" origin={%d,%d} size={%d,%d}", this.x, this.y, this.w, this.h)
 	str += fmt.Sprint(" layer=", this.layer)
+	if this.transform != dx.DISPMANX_NO_ROTATE {
+		str += fmt.Sprint(" transform=", this.transform)
+	}
 	if this.bitmap != nil {
 		str += fmt.Sprint(" bitmap=", this.bitmap)
 	}
@@ -54,7 +54,7 @@ func (this *Surfaces) Dispose(gopi.Config) error {
 		result = multierror.Append(result, err)
 	} else {
 		for surface := range this.surface {
-			if dispose(update, surface); err != nil {
+			if err := dispose(update, surface); err != nil {
 				result = multierror.Append(result, err)
 			}
 		}
@@ -119,11 +119,37 @@ func (this *Surfaces) DisposeSurface(ctx *Context, surface *Surface) error {
 	return result
 }
 
+// Modified adds the changed regions of all surfaces to a single update,
+// and returns the number of surfaces which were changed
+func (this *Surfaces) Modified(ctx *Context) (int, error) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	// Check arguments
+	if ctx == nil || ctx.Valid() == false {
+		return 0, gopi.ErrOutOfOrder.WithPrefix("Modified")
+	}
+
+	// Mark changed regions
+	var result error
+	var n int
+	for surface := range this.surface {
+		if modified, err := surface.Modified(ctx.Update); err != nil {
+			result = multierror.Append(result, err)
+		} else if modified {
+			n++
+		}
+	}
+
+	// Return any errors
+	return n, result
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
-func dispose(dx.Update, *Surface) error {
-
+func dispose(update dx.Update, surface *Surface) error {
+	return surface.Dispose(update)
 }
 
 /*
@@ -22,6 +22,7 @@ type Manager struct {
 	gopi.Unit
 	gopi.Logger
 	gopi.Metrics
+	gopi.AnimationClock
 
 	drm *drm.DRM
 	gbm *gbmegl.GBM
@@ -154,6 +155,11 @@ FOR_LOOP:
 			// Don't set modeset a second time
 			modeset = false
 
+			// Advance animations on page flip
+			if this.AnimationClock != nil {
+				this.AnimationClock.Frame(time.Now())
+			}
+
 			// Send framerate metrics
 			framerate := float64(1.0e9) / float64(time.Since(now).Nanoseconds())
 			if err := this.Metrics.Emit("vrefresh", framerate); err != nil {
@@ -233,6 +239,10 @@ func (this *Manager) DisposeBitmap(gopi.Bitmap) error {
 	return gopi.ErrNotImplemented
 }
 
+func (this *Manager) SetTransform(gopi.Surface, gopi.SurfaceTransform) error {
+	return gopi.ErrNotImplemented
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -0,0 +1,161 @@
+package text
+
+import (
+	"unicode"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// joining is how an Arabic letter joins to the letters around it
+type joining uint8
+
+// forms are the presentation forms of a letter, where the final,
+// initial and medial forms follow the isolated form
+type forms struct {
+	isolated rune
+	joining
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	joinNone    joining = iota
+	joinRight           // Joins to the letter before
+	joinDual            // Joins to the letters before and after
+	joinCausing         // Tatweel and joiners, which join without changing form
+)
+
+var (
+	arabic = map[rune]forms{
+		0x0621: {0xFE80, joinNone}, 0x0622: {0xFE81, joinRight}, 0x0623: {0xFE83, joinRight},
+		0x0624: {0xFE85, joinRight}, 0x0625: {0xFE87, joinRight}, 0x0626: {0xFE89, joinDual},
+		0x0627: {0xFE8D, joinRight}, 0x0628: {0xFE8F, joinDual}, 0x0629: {0xFE93, joinRight},
+		0x062A: {0xFE95, joinDual}, 0x062B: {0xFE99, joinDual}, 0x062C: {0xFE9D, joinDual},
+		0x062D: {0xFEA1, joinDual}, 0x062E: {0xFEA5, joinDual}, 0x062F: {0xFEA9, joinRight},
+		0x0630: {0xFEAB, joinRight}, 0x0631: {0xFEAD, joinRight}, 0x0632: {0xFEAF, joinRight},
+		0x0633: {0xFEB1, joinDual}, 0x0634: {0xFEB5, joinDual}, 0x0635: {0xFEB9, joinDual},
+		0x0636: {0xFEBD, joinDual}, 0x0637: {0xFEC1, joinDual}, 0x0638: {0xFEC5, joinDual},
+		0x0639: {0xFEC9, joinDual}, 0x063A: {0xFECD, joinDual}, 0x0641: {0xFED1, joinDual},
+		0x0642: {0xFED5, joinDual}, 0x0643: {0xFED9, joinDual}, 0x0644: {0xFEDD, joinDual},
+		0x0645: {0xFEE1, joinDual}, 0x0646: {0xFEE5, joinDual}, 0x0647: {0xFEE9, joinDual},
+		0x0648: {0xFEED, joinRight}, 0x0649: {0xFEEF, joinRight}, 0x064A: {0xFEF1, joinDual},
+		0x0671: {0xFB50, joinRight}, 0x067E: {0xFB56, joinDual}, 0x0686: {0xFB7A, joinDual},
+		0x0698: {0xFB8A, joinRight}, 0x06A9: {0xFB8E, joinDual}, 0x06AF: {0xFB92, joinDual},
+		0x06CC: {0xFBFC, joinDual},
+	}
+
+	// lamAlef are the isolated ligatures of lam with each form of alef,
+	// where the final form follows
+	lamAlef = map[rune]rune{
+		0x0622: 0xFEF5, 0x0623: 0xFEF7, 0x0625: 0xFEF9, 0x0627: 0xFEFB,
+	}
+
+	// presentation returns the letter for each presentation form, so
+	// that the letter can be drawn when a face has no presentation forms
+	presentation = make(map[rune]rune)
+)
+
+const (
+	lam = 0x0644
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	for r, f := range arabic {
+		n := rune(2)
+		if f.joining == joinDual {
+			n = 4
+		} else if f.joining == joinNone {
+			n = 1
+		}
+		for i := rune(0); i < n; i++ {
+			presentation[f.isolated+i] = r
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// shapeArabic returns text with Arabic letters replaced by the forms
+// which join them to the letters around them
+func shapeArabic(runes []rune) []rune {
+	result := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		f, exists := arabic[r]
+		if exists == false {
+			result = append(result, r)
+			continue
+		}
+
+		// Lam followed by alef is a ligature, which only joins before
+		before := joinsBefore(runes, i)
+		if r == lam && i+1 < len(runes) {
+			if lig, exists := lamAlef[runes[i+1]]; exists {
+				if before {
+					lig++
+				}
+				result = append(result, lig)
+				i++
+				continue
+			}
+		}
+
+		// Choose the form from the joining of the letters around
+		after := f.joining == joinDual && joinsAfter(runes, i)
+		switch {
+		case f.joining == joinNone:
+			result = append(result, f.isolated)
+		case before && after:
+			result = append(result, f.isolated+3)
+		case after:
+			result = append(result, f.isolated+2)
+		case before:
+			result = append(result, f.isolated+1)
+		default:
+			result = append(result, f.isolated)
+		}
+	}
+	return result
+}
+
+// joinsBefore returns true if the letter before a character, ignoring
+// marks, joins to the letter after it
+func joinsBefore(runes []rune, i int) bool {
+	for i--; i >= 0; i-- {
+		if j := joiningOf(runes[i]); j == joinDual || j == joinCausing {
+			return true
+		} else if j != joinNone || unicode.Is(unicode.Mn, runes[i]) == false {
+			return false
+		}
+	}
+	return false
+}
+
+// joinsAfter returns true if the letter after a character, ignoring
+// marks, joins to the letter before it
+func joinsAfter(runes []rune, i int) bool {
+	for i++; i < len(runes); i++ {
+		if j := joiningOf(runes[i]); j != joinNone {
+			return true
+		} else if unicode.Is(unicode.Mn, runes[i]) == false {
+			return false
+		}
+	}
+	return false
+}
+
+func joiningOf(r rune) joining {
+	if r == 0x0640 || r == 0x200D {
+		return joinCausing
+	} else if f, exists := arabic[r]; exists {
+		return f.joining
+	} else {
+		return joinNone
+	}
+}
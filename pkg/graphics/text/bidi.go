@@ -0,0 +1,305 @@
+package text
+
+import (
+	"unicode"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Direction is the base direction of a line of text
+type Direction uint
+
+// class is the bidirectional character type of a character
+type class uint8
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	DIRECTION_AUTO Direction = iota // From the first strong character
+	DIRECTION_LTR                   // Left to right
+	DIRECTION_RTL                   // Right to left
+)
+
+const (
+	classL   class = iota // Left to right
+	classR                // Right to left
+	classAL               // Arabic letter
+	classEN               // European number
+	classES               // European number separator
+	classET               // European number terminator
+	classAN               // Arabic number
+	classCS               // Common number separator
+	classNSM              // Non-spacing mark
+	classWS               // Whitespace
+	classON               // Other neutral
+)
+
+var (
+	// mirrors are characters drawn mirrored in right-to-left text
+	mirrors = map[rune]rune{
+		'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{', '<': '>', '>': '<',
+		'«': '»', '»': '«', '‹': '›', '›': '‹', '⁅': '⁆', '⁆': '⁅', '≤': '≥', '≥': '≤',
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (d Direction) String() string {
+	switch d {
+	case DIRECTION_AUTO:
+		return "DIRECTION_AUTO"
+	case DIRECTION_LTR:
+		return "DIRECTION_LTR"
+	case DIRECTION_RTL:
+		return "DIRECTION_RTL"
+	default:
+		return "[?? Invalid Direction value]"
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// classOf returns the bidirectional type of a character, for the
+// scripts which are most often used
+func classOf(r rune) class {
+	switch {
+	case r >= '0' && r <= '9', r >= 0x06F0 && r <= 0x06F9:
+		return classEN
+	case r >= 0x0660 && r <= 0x0669, r == 0x066B || r == 0x066C:
+		return classAN
+	case r == '+' || r == '-':
+		return classES
+	case r == ',' || r == '.' || r == ':' || r == '/' || r == 0x00A0:
+		return classCS
+	case r == '#' || r == '$' || r == '%' || r == 0x00B0 || unicode.Is(unicode.Sc, r):
+		return classET
+	case r == 0x200E:
+		return classL
+	case r == 0x200F:
+		return classR
+	case r == 0x061C:
+		return classAL
+	case unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r):
+		return classNSM
+	case unicode.IsSpace(r):
+		return classWS
+	case r >= 0x0590 && r <= 0x05FF, r >= 0x07C0 && r <= 0x085F, r >= 0xFB1D && r <= 0xFB4F:
+		return classR
+	case r >= 0x0600 && r <= 0x07BF, r >= 0x0860 && r <= 0x08FF, r >= 0xFB50 && r <= 0xFDFF, r >= 0xFE70 && r <= 0xFEFF:
+		return classAL
+	case r >= 0x10800 && r <= 0x10FFF, r >= 0x1E800 && r <= 0x1EFFF:
+		return classR
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mc, r):
+		return classL
+	default:
+		return classON
+	}
+}
+
+// levels returns the embedding level of each character of a line, and
+// the level of the line
+func levels(runes []rune, dir Direction) ([]uint8, uint8) {
+	n := len(runes)
+	orig := make([]class, n)
+	for i, r := range runes {
+		orig[i] = classOf(r)
+	}
+
+	// Level of the line is from the first strong character
+	base := uint8(0)
+	switch dir {
+	case DIRECTION_RTL:
+		base = 1
+	case DIRECTION_AUTO:
+		for _, c := range orig {
+			if c == classL {
+				break
+			} else if c == classR || c == classAL {
+				base = 1
+				break
+			}
+		}
+	}
+	sos := classL
+	if base == 1 {
+		sos = classR
+	}
+
+	// W1: Marks take the type of the previous character
+	cls := make([]class, n)
+	copy(cls, orig)
+	for i := range cls {
+		if cls[i] == classNSM {
+			if i == 0 {
+				cls[i] = sos
+			} else {
+				cls[i] = cls[i-1]
+			}
+		}
+	}
+
+	// W2, W3: Numbers after Arabic letters are Arabic numbers, and Arabic
+	// letters are right to left
+	strong := sos
+	for i, c := range cls {
+		switch c {
+		case classL, classR, classAL:
+			strong = c
+		case classEN:
+			if strong == classAL {
+				cls[i] = classAN
+			}
+		}
+	}
+	for i, c := range cls {
+		if c == classAL {
+			cls[i] = classR
+		}
+	}
+
+	// W4: A single separator between numbers of the same type
+	for i := 1; i < n-1; i++ {
+		if cls[i] == classES && cls[i-1] == classEN && cls[i+1] == classEN {
+			cls[i] = classEN
+		} else if cls[i] == classCS && cls[i-1] == cls[i+1] && (cls[i-1] == classEN || cls[i-1] == classAN) {
+			cls[i] = cls[i-1]
+		}
+	}
+
+	// W5: Terminators next to European numbers
+	for i := 0; i < n; i++ {
+		if cls[i] != classET {
+			continue
+		}
+		j := i
+		for j < n && cls[j] == classET {
+			j++
+		}
+		if (i > 0 && cls[i-1] == classEN) || (j < n && cls[j] == classEN) {
+			for k := i; k < j; k++ {
+				cls[k] = classEN
+			}
+		}
+		i = j
+	}
+
+	// W6, W7: Remaining separators are neutral, and European numbers
+	// after left to right text are left to right
+	strong = sos
+	for i, c := range cls {
+		switch c {
+		case classES, classET, classCS:
+			cls[i] = classON
+		case classL, classR:
+			strong = c
+		case classEN:
+			if strong == classL {
+				cls[i] = classL
+			}
+		}
+	}
+
+	// N1, N2: Neutrals between characters of the same direction take that
+	// direction, and otherwise the direction of the line
+	for i := 0; i < n; i++ {
+		if cls[i] != classWS && cls[i] != classON {
+			continue
+		}
+		j := i
+		for j < n && (cls[j] == classWS || cls[j] == classON) {
+			j++
+		}
+		before, after := sos, sos
+		if i > 0 {
+			before = direction(cls[i-1])
+		}
+		if j < n {
+			after = direction(cls[j])
+		}
+		c := sos
+		if before == after {
+			c = before
+		}
+		for k := i; k < j; k++ {
+			cls[k] = c
+		}
+		i = j
+	}
+
+	// I1, I2: Resolve levels
+	result := make([]uint8, n)
+	for i, c := range cls {
+		result[i] = base
+		switch {
+		case base == 0 && c == classR:
+			result[i] = 1
+		case base == 0 && (c == classEN || c == classAN):
+			result[i] = 2
+		case base == 1 && (c == classL || c == classEN || c == classAN):
+			result[i] = 2
+		}
+	}
+
+	// L1: Trailing whitespace is at the level of the line
+	for i := n - 1; i >= 0 && orig[i] == classWS; i-- {
+		result[i] = base
+	}
+
+	// Return success
+	return result, base
+}
+
+// direction returns left or right to left for a resolved type, where
+// numbers are right to left
+func direction(c class) class {
+	if c == classL {
+		return classL
+	} else {
+		return classR
+	}
+}
+
+// reorder returns the order in which items at embedding levels are
+// displayed, reversing sequences at each odd level and above
+func reorder(levels []uint8) []int {
+	order := make([]int, len(levels))
+	max, min := uint8(0), uint8(0xFF)
+	for i, level := range levels {
+		order[i] = i
+		if level > max {
+			max = level
+		}
+		if level&1 == 1 && level < min {
+			min = level
+		}
+	}
+	for level := max; level >= min && level > 0; level-- {
+		for i := 0; i < len(order); i++ {
+			if levels[order[i]] < level {
+				continue
+			}
+			j := i
+			for j < len(order) && levels[order[j]] >= level {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+			}
+			i = j
+		}
+	}
+	return order
+}
+
+// mirror returns the mirrored form of a character
+func mirror(r rune) rune {
+	if m, exists := mirrors[r]; exists {
+		return m
+	} else {
+		return r
+	}
+}
@@ -0,0 +1,82 @@
+package text
+
+import (
+	"unicode"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// cluster is a base character and the characters drawn with it, from
+// start up to end
+type cluster struct {
+	start, end int
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	zwj  = 0x200D // Zero width joiner
+	vs16 = 0xFE0F // Emoji presentation selector
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// clusters splits text into clusters of a base character followed by
+// marks, joiners, variation selectors, emoji modifiers and tags, with
+// pairs of regional indicators joined into flags
+func clusters(runes []rune) []cluster {
+	result := make([]cluster, 0, len(runes))
+	for i := 0; i < len(runes); {
+		j := i + 1
+		if isRegional(runes[i]) && j < len(runes) && isRegional(runes[j]) {
+			j++
+		}
+		for j < len(runes) {
+			if runes[j] == zwj && j+1 < len(runes) {
+				j += 2
+			} else if isExtend(runes[j]) {
+				j++
+			} else {
+				break
+			}
+		}
+		result = append(result, cluster{i, j})
+		i = j
+	}
+	return result
+}
+
+// isExtend returns true for characters which are drawn with the
+// character before them
+func isExtend(r rune) bool {
+	switch {
+	case r == zwj, isIgnorable(r):
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF:
+		return true
+	default:
+		return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc)
+	}
+}
+
+// isIgnorable returns true for joiners, variation selectors and tags,
+// which are not drawn when a face has no glyph for them
+func isIgnorable(r rune) bool {
+	switch {
+	case r == zwj || r == 0x200C:
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F, r >= 0xE0100 && r <= 0xE01EF:
+		return true
+	case r >= 0xE0020 && r <= 0xE007F:
+		return true
+	default:
+		return false
+	}
+}
+
+func isRegional(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
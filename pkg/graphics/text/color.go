@@ -0,0 +1,257 @@
+package text
+
+import (
+	"bytes"
+	"image"
+
+	sfnt "golang.org/x/image/font/sfnt"
+
+	// Image formats
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// bitmapGlyph is a colour glyph image from a strike, with the bounds
+// of the image in pixels of the strike relative to the glyph origin
+type bitmapGlyph struct {
+	image.Image
+	ppem   int
+	bounds image.Rectangle
+}
+
+// colorGlyphs returns the image for a glyph from the strike nearest to
+// a size in pixels, or nil if the glyph has no image
+type colorGlyphs interface {
+	glyph(sfnt.GlyphIndex, int) (*bitmapGlyph, error)
+}
+
+// cbdt reads glyphs from the CBLC and CBDT tables
+type cbdt struct {
+	loc, data *table
+	strikes   []strike
+}
+
+// sbix reads glyphs from the sbix table
+type sbix struct {
+	*table
+	strikes []strike
+}
+
+// strike is the offset of a set of glyph images at a size
+type strike struct {
+	offset     int
+	ppem       int
+	start, end sfnt.GlyphIndex
+	subtables  int
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	sizeBitmapSize = 48
+	sizeBigMetrics = 8
+	dupeDepth      = 4
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CBDT
+
+// newCBDT returns colour glyphs from CBLC and CBDT tables
+func newCBDT(loc, data []byte) (*cbdt, error) {
+	this := &cbdt{loc: &table{data: loc}, data: &table{data: data}}
+	for i, n := 0, this.loc.u32(4); i < n && this.loc.err == nil; i++ {
+		size := 8 + i*sizeBitmapSize
+		this.strikes = append(this.strikes, strike{
+			offset:    this.loc.u32(size),
+			subtables: this.loc.u32(size + 8),
+			start:     sfnt.GlyphIndex(this.loc.u16(size + 40)),
+			end:       sfnt.GlyphIndex(this.loc.u16(size + 42)),
+			ppem:      this.loc.u8(size + 45),
+		})
+	}
+	return this, this.loc.err
+}
+
+func (this *cbdt) glyph(index sfnt.GlyphIndex, ppem int) (*bitmapGlyph, error) {
+	s := nearest(this.strikes, index, ppem)
+	if s == nil {
+		return nil, nil
+	}
+
+	// Find the index subtable for the glyph
+	loc := &table{data: this.loc.data}
+	for i := 0; i < s.subtables; i++ {
+		record := s.offset + i*8
+		first, last := sfnt.GlyphIndex(loc.u16(record)), sfnt.GlyphIndex(loc.u16(record+2))
+		if loc.err != nil {
+			return nil, loc.err
+		} else if index < first || index > last {
+			continue
+		}
+		subtable := s.offset + loc.u32(record+4)
+		offset, length, metrics := loc.location(subtable, int(index-first), index)
+		if loc.err != nil {
+			return nil, loc.err
+		} else if length <= 0 {
+			return nil, nil
+		}
+		return this.image(loc.u16(subtable+2), offset, metrics, s.ppem)
+	}
+
+	// Glyph has no image in the strike
+	return nil, nil
+}
+
+// location returns the offset and length of glyph data in the CBDT table
+// from an index subtable, and the offset of big metrics in the CBLC
+// table when all the glyphs have the same metrics
+func (t *table) location(subtable, i int, index sfnt.GlyphIndex) (int, int, int) {
+	data := t.u32(subtable + 4)
+	switch t.u16(subtable) {
+	case 1:
+		start, end := t.u32(subtable+8+i*4), t.u32(subtable+12+i*4)
+		return data + start, end - start, -1
+	case 2:
+		size := t.u32(subtable + 8)
+		return data + i*size, size, subtable + 12
+	case 3:
+		start, end := t.u16(subtable+8+i*2), t.u16(subtable+10+i*2)
+		return data + start, end - start, -1
+	case 4:
+		for j, n := 0, t.u32(subtable+8); j < n && t.err == nil; j++ {
+			pair := subtable + 12 + j*4
+			if sfnt.GlyphIndex(t.u16(pair)) == index {
+				start, end := t.u16(pair+2), t.u16(pair+6)
+				return data + start, end - start, -1
+			}
+		}
+	case 5:
+		size, metrics := t.u32(subtable+8), subtable+12
+		glyphs := metrics + sizeBigMetrics
+		for j, n := 0, t.u32(glyphs); j < n && t.err == nil; j++ {
+			if sfnt.GlyphIndex(t.u16(glyphs+4+j*2)) == index {
+				return data + j*size, size, metrics
+			}
+		}
+	}
+	return 0, 0, -1
+}
+
+// image decodes a PNG glyph image with small, big or shared metrics
+func (this *cbdt) image(format, offset, metrics, ppem int) (*bitmapGlyph, error) {
+	t := &table{data: this.data.data}
+	var w, h, x, y, data int
+	switch format {
+	case 17:
+		h, w, x, y = t.u8(offset), t.u8(offset+1), t.i8(offset+2), t.i8(offset+3)
+		data = offset + 5
+	case 18:
+		h, w, x, y = t.u8(offset), t.u8(offset+1), t.i8(offset+2), t.i8(offset+3)
+		data = offset + sizeBigMetrics
+	case 19:
+		if metrics < 0 {
+			return nil, errTable
+		}
+		loc := &table{data: this.loc.data}
+		h, w, x, y = loc.u8(metrics), loc.u8(metrics+1), loc.i8(metrics+2), loc.i8(metrics+3)
+		data = offset
+	default:
+		return nil, nil
+	}
+	img, err := decodeImage(t.bytes(data+4, t.u32(data)))
+	if t.err != nil {
+		return nil, t.err
+	} else if err != nil {
+		return nil, err
+	}
+	return &bitmapGlyph{img, ppem, image.Rect(x, -y, x+w, h-y)}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SBIX
+
+// newSBIX returns colour glyphs from an sbix table
+func newSBIX(data []byte, numGlyphs int) (*sbix, error) {
+	this := &sbix{table: &table{data: data}}
+	for i, n := 0, this.u32(4); i < n && this.err == nil; i++ {
+		offset := this.u32(8 + i*4)
+		this.strikes = append(this.strikes, strike{
+			offset: offset,
+			ppem:   this.u16(offset),
+			end:    sfnt.GlyphIndex(numGlyphs - 1),
+		})
+	}
+	return this, this.err
+}
+
+func (this *sbix) glyph(index sfnt.GlyphIndex, ppem int) (*bitmapGlyph, error) {
+	s := nearest(this.strikes, index, ppem)
+	if s == nil {
+		return nil, nil
+	}
+	t := &table{data: this.data}
+	for i := 0; i < dupeDepth; i++ {
+		start, end := t.u32(s.offset+4+int(index)*4), t.u32(s.offset+8+int(index)*4)
+		if t.err != nil {
+			return nil, t.err
+		} else if end-start < 8 {
+			return nil, nil
+		}
+		data := s.offset + start
+		x, y, kind := t.i16(data), t.i16(data+2), string(t.bytes(data+4, 4))
+		switch kind {
+		case "dupe":
+			index = sfnt.GlyphIndex(t.u16(data + 8))
+		case "png ", "jpg ":
+			img, err := decodeImage(t.bytes(data+8, end-start-8))
+			if t.err != nil {
+				return nil, t.err
+			} else if err != nil {
+				return nil, err
+			}
+			size := img.Bounds().Size()
+			return &bitmapGlyph{img, s.ppem, image.Rect(x, -y-size.Y, x+size.X, -y)}, nil
+		default:
+			return nil, nil
+		}
+	}
+
+	// Too many duplicates
+	return nil, errTable
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// nearest returns the smallest strike with a glyph at or above a size,
+// or the largest strike with the glyph when all are smaller
+func nearest(strikes []strike, index sfnt.GlyphIndex, ppem int) *strike {
+	var result *strike
+	for i := range strikes {
+		s := &strikes[i]
+		if index < s.start || index > s.end || s.ppem == 0 {
+			continue
+		}
+		switch {
+		case result == nil:
+			result = s
+		case result.ppem < ppem && s.ppem > result.ppem:
+			result = s
+		case s.ppem >= ppem && s.ppem < result.ppem:
+			result = s
+		}
+	}
+	return result
+}
+
+func decodeImage(data []byte) (image.Image, error) {
+	if data == nil {
+		return nil, errTable
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
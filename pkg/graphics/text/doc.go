@@ -0,0 +1,36 @@
+// Text package lays out and draws lines of Unicode text with TrueType
+// and OpenType fonts, in pure Go so that it can be used without
+// freetype or harfbuzz. Text is drawn with a chain of faces, where each
+// character is drawn with the first face which has a glyph for it, so
+// that a text face can be followed by faces for other scripts and
+// for emoji.
+//
+// Lines are laid out in these steps:
+//
+//   * Arabic letters are joined using their presentation forms, including
+//     the lam-alef ligatures
+//   * Characters are split into clusters of a base character with its
+//     combining marks, joiners, variation selectors and emoji modifiers,
+//     and regional indicators are paired into flags
+//   * Embedding levels are resolved with the Unicode bidirectional
+//     algorithm for a single paragraph without explicit embeddings,
+//     and mirrored brackets are used in right-to-left text
+//   * Runs of clusters with the same face and level are mapped to glyphs,
+//     and the ligatures in the ccmp, liga and rlig features of the face
+//     are applied, which joins emoji sequences and flags
+//   * Runs are reordered for display, and glyphs are positioned with
+//     their advances and kerning
+//
+// Glyph outlines are rasterized with anti-aliasing. Colour glyphs are
+// read from CBDT and sbix tables as PNG or JPEG images, and scaled
+// from the nearest strike to the size of the text.
+//
+// For example,
+//
+//   face, err := text.Open("/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf")
+//   if err != nil {
+//     return err
+//   }
+//   line := text.Chain{face}.Layout("שלום world", 24, text.DIRECTION_AUTO)
+//   line.Draw(img, image.Pt(0, line.Ascent()), color.White)
+package text
@@ -0,0 +1,270 @@
+package text
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+
+	font "golang.org/x/image/font"
+	sfnt "golang.org/x/image/font/sfnt"
+	fixed "golang.org/x/image/math/fixed"
+	vector "golang.org/x/image/vector"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Face is a TrueType or OpenType font, which can have colour glyphs
+type Face struct {
+	sync.Mutex
+
+	name   string
+	font   *sfnt.Font
+	buf    sfnt.Buffer
+	gsub   gsub
+	color  colorGlyphs
+	images map[bitmapKey]*bitmapGlyph
+}
+
+// bitmapKey is a colour glyph at a size
+type bitmapKey struct {
+	index sfnt.GlyphIndex
+	ppem  int
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// Open returns a face from a font file. For font collections, the
+// first font in the collection is returned
+func Open(path string) (*Face, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if this, err := Parse(data); err != nil {
+		return nil, fmt.Errorf("%v: %w", filepath.Base(path), err)
+	} else {
+		if this.name == "" {
+			this.name = filepath.Base(path)
+		}
+		return this, nil
+	}
+}
+
+// Parse returns a face from the data of a font file
+func Parse(data []byte) (*Face, error) {
+	this := &Face{images: make(map[bitmapKey]*bitmapGlyph)}
+
+	// Read the outlines, character map and metrics
+	offset := offset(data)
+	if offset == 0 {
+		if font, err := sfnt.Parse(data); err != nil {
+			return nil, err
+		} else {
+			this.font = font
+		}
+	} else if collection, err := sfnt.ParseCollection(data); err != nil {
+		return nil, err
+	} else if font, err := collection.Font(0); err != nil {
+		return nil, err
+	} else {
+		this.font = font
+	}
+	if name, err := this.font.Name(&this.buf, sfnt.NameIDFull); err == nil {
+		this.name = name
+	}
+
+	// Read ligatures and colour glyphs
+	tables, err := tables(data, offset)
+	if err != nil {
+		return nil, err
+	}
+	if data, exists := tables["GSUB"]; exists {
+		if this.gsub, err = parseGSUB(data); err != nil {
+			return nil, fmt.Errorf("GSUB: %w", err)
+		}
+	}
+	if loc, exists := tables["CBLC"]; exists {
+		if this.color, err = newCBDT(loc, tables["CBDT"]); err != nil {
+			return nil, fmt.Errorf("CBLC: %w", err)
+		}
+	} else if data, exists := tables["sbix"]; exists {
+		if this.color, err = newSBIX(data, this.font.NumGlyphs()); err != nil {
+			return nil, fmt.Errorf("sbix: %w", err)
+		}
+	}
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Name returns the full name of the face
+func (this *Face) Name() string {
+	return this.name
+}
+
+// HasColor returns true if the face has colour glyphs
+func (this *Face) HasColor() bool {
+	return this.color != nil
+}
+
+// HasGlyph returns true if the face has a glyph for a character
+func (this *Face) HasGlyph(r rune) bool {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	_, exists := this.glyph(r)
+	return exists
+}
+
+// Metrics returns the metrics of the face at a size in pixels
+func (this *Face) Metrics(size float64) font.Metrics {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	metrics, _ := this.font.Metrics(&this.buf, ppem(size), font.HintingNone)
+	return metrics
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Face) String() string {
+	str := "<text.face"
+	str += fmt.Sprintf(" name=%q", this.name)
+	str += fmt.Sprint(" glyphs=", this.font.NumGlyphs())
+	if len(this.gsub) > 0 {
+		str += fmt.Sprint(" ligatures=", len(this.gsub))
+	}
+	if this.color != nil {
+		str += " color"
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// glyph returns the glyph for a character, or the glyph for the letter
+// of a presentation form. It is called with the lock held
+func (this *Face) glyph(r rune) (sfnt.GlyphIndex, bool) {
+	if index, err := this.font.GlyphIndex(&this.buf, r); err == nil && index != 0 {
+		return index, true
+	} else if base, exists := presentation[r]; exists {
+		return this.glyph(base)
+	} else {
+		return 0, false
+	}
+}
+
+// advance returns the advance of a glyph with kerning from the glyph
+// before it. It is called with the lock held
+func (this *Face) advance(prev, index sfnt.GlyphIndex, size fixed.Int26_6) (fixed.Int26_6, fixed.Int26_6) {
+	advance, _ := this.font.GlyphAdvance(&this.buf, index, size, font.HintingNone)
+	if prev == 0 {
+		return advance, 0
+	} else if kern, err := this.font.Kern(&this.buf, prev, index, size, font.HintingNone); err != nil {
+		return advance, 0
+	} else {
+		return advance, kern
+	}
+}
+
+// draw draws a glyph with its origin at a point, as a colour image when
+// the face has one for the glyph and otherwise filled with a colour
+func (this *Face) draw(dst draw.Image, index sfnt.GlyphIndex, size fixed.Int26_6, dot fixed.Point26_6, src image.Image) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Draw a colour glyph, scaled from the strike
+	if bitmap, err := this.bitmap(index, size.Ceil()); err != nil {
+		return err
+	} else if bitmap != nil {
+		scale := float64(size) / 64 / float64(bitmap.ppem)
+		x, y := float64(dot.X)/64, float64(dot.Y)/64
+		r := image.Rect(
+			round(x+float64(bitmap.bounds.Min.X)*scale), round(y+float64(bitmap.bounds.Min.Y)*scale),
+			round(x+float64(bitmap.bounds.Max.X)*scale), round(y+float64(bitmap.bounds.Max.Y)*scale),
+		)
+		xdraw.BiLinear.Scale(dst, r, bitmap, bitmap.Bounds(), xdraw.Over, nil)
+		return nil
+	}
+
+	// Draw the outline
+	segments, err := this.font.LoadGlyph(&this.buf, index, size, nil)
+	if err == sfnt.ErrColoredGlyph {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	b := segments.Bounds()
+	r := image.Rect((dot.X + b.Min.X).Floor(), (dot.Y + b.Min.Y).Floor(), (dot.X + b.Max.X).Ceil(), (dot.Y + b.Max.Y).Ceil())
+	if r.Empty() {
+		return nil
+	}
+	origin := fixed.Point26_6{X: dot.X - fixed.I(r.Min.X), Y: dot.Y - fixed.I(r.Min.Y)}
+	pt := func(p fixed.Point26_6) (float32, float32) {
+		return float32(p.X+origin.X) / 64, float32(p.Y+origin.Y) / 64
+	}
+	z := vector.NewRasterizer(r.Dx(), r.Dy())
+	for _, seg := range segments {
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			z.MoveTo(pt(seg.Args[0]))
+		case sfnt.SegmentOpLineTo:
+			z.LineTo(pt(seg.Args[0]))
+		case sfnt.SegmentOpQuadTo:
+			x1, y1 := pt(seg.Args[0])
+			x2, y2 := pt(seg.Args[1])
+			z.QuadTo(x1, y1, x2, y2)
+		case sfnt.SegmentOpCubeTo:
+			x1, y1 := pt(seg.Args[0])
+			x2, y2 := pt(seg.Args[1])
+			x3, y3 := pt(seg.Args[2])
+			z.CubeTo(x1, y1, x2, y2, x3, y3)
+		}
+	}
+	z.ClosePath()
+	z.Draw(dst, r, src, r.Min)
+
+	// Return success
+	return nil
+}
+
+// bitmap returns a colour glyph, or nil if the face has no colour
+// image for the glyph. It is called with the lock held
+func (this *Face) bitmap(index sfnt.GlyphIndex, ppem int) (*bitmapGlyph, error) {
+	if this.color == nil {
+		return nil, nil
+	}
+	key := bitmapKey{index, ppem}
+	if bitmap, exists := this.images[key]; exists {
+		return bitmap, nil
+	}
+	bitmap, err := this.color.glyph(index, ppem)
+	if err != nil {
+		return nil, err
+	}
+	this.images[key] = bitmap
+	return bitmap, nil
+}
+
+// ppem returns a size in pixels as a fixed point value
+func ppem(size float64) fixed.Int26_6 {
+	return fixed.Int26_6(size*64 + 0.5)
+}
+
+func round(v float64) int {
+	if v < 0 {
+		return -int(-v + 0.5)
+	} else {
+		return int(v + 0.5)
+	}
+}
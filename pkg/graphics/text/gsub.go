@@ -0,0 +1,164 @@
+package text
+
+import (
+	"sort"
+
+	sfnt "golang.org/x/image/font/sfnt"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ligature replaces a glyph followed by components with one glyph
+type ligature struct {
+	glyph      sfnt.GlyphIndex
+	components []sfnt.GlyphIndex
+}
+
+// ligatures is a lookup of ligatures by their first glyph
+type ligatures map[sfnt.GlyphIndex][]ligature
+
+// gsub is the ligature lookups of a face in the order they are applied
+type gsub []ligatures
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	lookupLigature  = 4
+	lookupExtension = 7
+)
+
+var (
+	// features are the features which are applied to all text
+	features = map[string]bool{"ccmp": true, "liga": true, "rlig": true}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// parseGSUB returns the ligature lookups of the ccmp, liga and rlig
+// features from a GSUB table, for all scripts and languages
+func parseGSUB(data []byte) (gsub, error) {
+	t := &table{data: data}
+	featureList, lookupList := t.u16(6), t.u16(8)
+
+	// Find lookups for the features
+	indexes := make(map[int]bool)
+	for i, n := 0, t.u16(featureList); i < n; i++ {
+		record := featureList + 2 + i*6
+		if features[string(t.bytes(record, 4))] == false {
+			continue
+		}
+		feature := featureList + t.u16(record+4)
+		for j, m := 0, t.u16(feature+2); j < m; j++ {
+			indexes[t.u16(feature+4+j*2)] = true
+		}
+	}
+	order := make([]int, 0, len(indexes))
+	for index := range indexes {
+		order = append(order, index)
+	}
+	sort.Ints(order)
+
+	// Read ligatures from each lookup
+	result := make(gsub, 0, len(order))
+	for _, index := range order {
+		if index >= t.u16(lookupList) {
+			continue
+		}
+		lookup := lookupList + t.u16(lookupList+2+index*2)
+		kind := t.u16(lookup)
+		l := make(ligatures)
+		for j, m := 0, t.u16(lookup+4); j < m; j++ {
+			subtable := lookup + t.u16(lookup+6+j*2)
+			if kind == lookupExtension && t.u16(subtable+2) == lookupLigature {
+				t.parseLigatures(subtable+t.u32(subtable+4), l)
+			} else if kind == lookupLigature {
+				t.parseLigatures(subtable, l)
+			}
+		}
+		if len(l) > 0 {
+			result = append(result, l)
+		}
+	}
+
+	// Return success
+	return result, t.err
+}
+
+// parseLigatures adds the ligatures in a ligature substitution subtable
+func (t *table) parseLigatures(subtable int, l ligatures) {
+	if t.u16(subtable) != 1 {
+		return
+	}
+	glyphs := t.coverage(subtable + t.u16(subtable+2))
+	for i, n := 0, t.u16(subtable+4); i < n && i < len(glyphs); i++ {
+		set := subtable + t.u16(subtable+6+i*2)
+		for j, m := 0, t.u16(set); j < m; j++ {
+			lig := set + t.u16(set+2+j*2)
+			count := t.u16(lig + 2)
+			components := make([]sfnt.GlyphIndex, 0, count)
+			for k := 1; k < count; k++ {
+				components = append(components, sfnt.GlyphIndex(t.u16(lig+4+(k-1)*2)))
+			}
+			first := glyphs[i]
+			l[first] = append(l[first], ligature{sfnt.GlyphIndex(t.u16(lig)), components})
+		}
+	}
+}
+
+// coverage returns the glyphs in a coverage table in coverage order
+func (t *table) coverage(offset int) []sfnt.GlyphIndex {
+	var result []sfnt.GlyphIndex
+	switch t.u16(offset) {
+	case 1:
+		for i, n := 0, t.u16(offset+2); i < n; i++ {
+			result = append(result, sfnt.GlyphIndex(t.u16(offset+4+i*2)))
+		}
+	case 2:
+		for i, n := 0, t.u16(offset+2); i < n; i++ {
+			record := offset + 4 + i*6
+			start, end, index := t.u16(record), t.u16(record+2), t.u16(record+4)
+			for g := start; g <= end && t.err == nil; g++ {
+				if k := index + g - start; k == len(result) {
+					result = append(result, sfnt.GlyphIndex(g))
+				}
+			}
+		}
+	}
+	return result
+}
+
+// apply replaces sequences of glyphs with ligatures, where the cluster
+// of the first glyph in a sequence is used for the ligature
+func (g gsub) apply(glyphs []glyph) []glyph {
+	for _, l := range g {
+		result := make([]glyph, 0, len(glyphs))
+		for i := 0; i < len(glyphs); i++ {
+			next := glyphs[i]
+			for _, lig := range l[next.index] {
+				if lig.matches(glyphs[i+1:]) {
+					next.index = lig.glyph
+					i += len(lig.components)
+					break
+				}
+			}
+			result = append(result, next)
+		}
+		glyphs = result
+	}
+	return glyphs
+}
+
+func (lig ligature) matches(glyphs []glyph) bool {
+	if len(lig.components) > len(glyphs) {
+		return false
+	}
+	for i, c := range lig.components {
+		if glyphs[i].index != c {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,223 @@
+package text
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	font "golang.org/x/image/font"
+	sfnt "golang.org/x/image/font/sfnt"
+	fixed "golang.org/x/image/math/fixed"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Chain is a list of faces, where each character is drawn with the
+// first face which has a glyph for it
+type Chain []*Face
+
+// Line is a line of text laid out for display
+type Line struct {
+	size    fixed.Int26_6
+	glyphs  []glyph
+	width   fixed.Int26_6
+	metrics font.Metrics
+}
+
+// glyph is a glyph from a face, and the position of the glyph on the
+// line once laid out
+type glyph struct {
+	face    *Face
+	index   sfnt.GlyphIndex
+	advance fixed.Int26_6
+	x       fixed.Int26_6
+}
+
+// run is text drawn with the same face at the same embedding level
+type run struct {
+	face  *Face
+	level uint8
+	runes []rune
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Layout returns a line of text at a size in pixels, with the metrics
+// of the line from the first face in the chain
+func (chain Chain) Layout(text string, size float64, dir Direction) *Line {
+	this := &Line{size: ppem(size)}
+	if len(chain) == 0 || size <= 0 {
+		return this
+	}
+	this.metrics = chain[0].Metrics(size)
+
+	// Split into runs with the same face and level
+	runes := shapeArabic([]rune(text))
+	levels, _ := levels(runes, dir)
+	runs := []run{}
+	for _, c := range clusters(runes) {
+		face, level := chain.face(runes[c.start:c.end]), levels[c.start]
+		if n := len(runs); n > 0 && runs[n-1].face == face && runs[n-1].level == level {
+			runs[n-1].runes = append(runs[n-1].runes, runes[c.start:c.end]...)
+		} else {
+			runs = append(runs, run{face, level, append([]rune{}, runes[c.start:c.end]...)})
+		}
+	}
+
+	// Shape runs and position glyphs in display order
+	order := make([]uint8, len(runs))
+	for i, run := range runs {
+		order[i] = run.level
+	}
+	for _, i := range reorder(order) {
+		for _, g := range runs[i].shape(this.size) {
+			g.x = this.width
+			this.width += g.advance
+			this.glyphs = append(this.glyphs, g)
+		}
+	}
+
+	// Return the line
+	return this
+}
+
+// Width returns the width of the line in pixels
+func (this *Line) Width() int {
+	return this.width.Ceil()
+}
+
+// Height returns the height of a line in pixels, which is the distance
+// between baselines
+func (this *Line) Height() int {
+	return this.metrics.Height.Ceil()
+}
+
+// Ascent returns the distance in pixels from the top of the line to the
+// baseline
+func (this *Line) Ascent() int {
+	return this.metrics.Ascent.Ceil()
+}
+
+// Draw draws the line with the left of the baseline at a point, where
+// outlines are filled with a colour and colour glyphs are drawn as is
+func (this *Line) Draw(dst draw.Image, pt image.Point, c color.Color) error {
+	src := image.NewUniform(c)
+	for _, g := range this.glyphs {
+		dot := fixed.Point26_6{X: fixed.I(pt.X) + g.x, Y: fixed.I(pt.Y)}
+		if err := g.face.draw(dst, g.index, this.size, dot, src); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Line) String() string {
+	str := "<text.line"
+	str += fmt.Sprint(" size=", this.size)
+	str += fmt.Sprint(" glyphs=", len(this.glyphs))
+	str += fmt.Sprint(" width=", this.Width())
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// face returns the first face with glyphs for the characters of a
+// cluster, preferring colour faces when emoji presentation is selected.
+// When no face has all the glyphs, the first face with the base
+// character is returned, and otherwise the first face
+func (chain Chain) face(runes []rune) *Face {
+	if contains(runes, vs16) {
+		for _, face := range chain {
+			if face.HasColor() && face.hasGlyphs(runes) {
+				return face
+			}
+		}
+	}
+	for _, face := range chain {
+		if face.hasGlyphs(runes) {
+			return face
+		}
+	}
+	for _, face := range chain {
+		if face.HasGlyph(runes[0]) {
+			return face
+		}
+	}
+	return chain[0]
+}
+
+// hasGlyphs returns true if a face has glyphs for all characters which
+// are drawn
+func (this *Face) hasGlyphs(runes []rune) bool {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	for _, r := range runes {
+		if _, exists := this.glyph(r); exists == false && isIgnorable(r) == false {
+			return false
+		}
+	}
+	return true
+}
+
+// shape returns the glyphs of a run in display order, with ligatures
+// applied and advances adjusted for kerning
+func (this run) shape(size fixed.Int26_6) []glyph {
+	face := this.face
+	face.Mutex.Lock()
+	defer face.Mutex.Unlock()
+
+	// Map characters to glyphs, mirroring in right to left text
+	glyphs := make([]glyph, 0, len(this.runes))
+	for _, r := range this.runes {
+		if this.level&1 == 1 {
+			r = mirror(r)
+		}
+		if index, exists := face.glyph(r); exists {
+			glyphs = append(glyphs, glyph{face: face, index: index})
+		} else if isIgnorable(r) == false {
+			glyphs = append(glyphs, glyph{face: face})
+		}
+	}
+	glyphs = face.gsub.apply(glyphs)
+
+	// Set advances, where kerning is added to the glyph before
+	for i := range glyphs {
+		prev := sfnt.GlyphIndex(0)
+		if i > 0 {
+			prev = glyphs[i-1].index
+		}
+		advance, kern := face.advance(prev, glyphs[i].index, size)
+		glyphs[i].advance = advance
+		if i > 0 {
+			glyphs[i-1].advance += kern
+		}
+	}
+
+	// Reverse right to left text
+	if this.level&1 == 1 {
+		for a, b := 0, len(glyphs)-1; a < b; a, b = a+1, b-1 {
+			glyphs[a], glyphs[b] = glyphs[b], glyphs[a]
+		}
+	}
+
+	// Return glyphs
+	return glyphs
+}
+
+func contains(runes []rune, r rune) bool {
+	for _, v := range runes {
+		if v == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package text
+
+import (
+	"errors"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// table reads big-endian values from a font table. Reading beyond the
+// end of the table returns zero and sets the error
+type table struct {
+	data []byte
+	err  error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	errTable = errors.New("Invalid font table")
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// tables returns the tables of the font at an offset in a font file
+func tables(data []byte, offset int) (map[string][]byte, error) {
+	t := &table{data: data}
+	n := t.u16(offset + 4)
+	result := make(map[string][]byte, n)
+	for i := 0; i < n; i++ {
+		record := offset + 12 + i*16
+		tag := string(t.bytes(record, 4))
+		start, length := t.u32(record+8), t.u32(record+12)
+		if data := t.bytes(start, length); data != nil {
+			result[tag] = data
+		}
+	}
+	return result, t.err
+}
+
+// offset returns the offset of the first font in a font file, which
+// is not zero for collections
+func offset(data []byte) int {
+	t := &table{data: data}
+	if string(t.bytes(0, 4)) == "ttcf" {
+		return t.u32(12)
+	} else {
+		return 0
+	}
+}
+
+func (t *table) bytes(offset, length int) []byte {
+	if offset < 0 || length < 0 || offset+length > len(t.data) {
+		t.err = errTable
+		return nil
+	}
+	return t.data[offset : offset+length]
+}
+
+func (t *table) u8(offset int) int {
+	if b := t.bytes(offset, 1); b != nil {
+		return int(b[0])
+	}
+	return 0
+}
+
+func (t *table) i8(offset int) int {
+	return int(int8(t.u8(offset)))
+}
+
+func (t *table) u16(offset int) int {
+	if b := t.bytes(offset, 2); b != nil {
+		return int(b[0])<<8 | int(b[1])
+	}
+	return 0
+}
+
+func (t *table) i16(offset int) int {
+	return int(int16(t.u16(offset)))
+}
+
+func (t *table) u32(offset int) int {
+	if b := t.bytes(offset, 4); b != nil {
+		return int(uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]))
+	}
+	return 0
+}
@@ -0,0 +1,291 @@
+package text
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	goregular "golang.org/x/image/font/gofont/goregular"
+	sfnt "golang.org/x/image/font/sfnt"
+)
+
+var (
+	red = color.RGBA{0xFF, 0, 0, 0xFF}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Bidi_001(t *testing.T) {
+	tests := []struct {
+		dir            Direction
+		logical, shown string
+	}{
+		{DIRECTION_AUTO, "abc", "abc"},
+		{DIRECTION_AUTO, "אבג", "גבא"},
+		{DIRECTION_AUTO, "abc אבג def", "abc גבא def"},
+		{DIRECTION_AUTO, "אבג 123", "123 גבא"},
+		{DIRECTION_AUTO, "אב 1.5", "1.5 בא"},
+		{DIRECTION_AUTO, "(אב)", "(בא)"},
+		{DIRECTION_RTL, "abc def", "abc def"},
+		{DIRECTION_RTL, "abc!", "!abc"},
+		{DIRECTION_LTR, "אב!", "בא!"},
+	}
+	for _, test := range tests {
+		runes := []rune(test.logical)
+		levels, _ := levels(runes, test.dir)
+		shown := make([]rune, 0, len(runes))
+		for _, i := range reorder(levels) {
+			if levels[i]&1 == 1 {
+				shown = append(shown, mirror(runes[i]))
+			} else {
+				shown = append(shown, runes[i])
+			}
+		}
+		if string(shown) != test.shown {
+			t.Errorf("%q %v: expected %q, got %q", test.logical, test.dir, test.shown, string(shown))
+		}
+	}
+}
+
+func Test_Arabic_001(t *testing.T) {
+	tests := []struct {
+		logical string
+		shaped  []rune
+	}{
+		{"بيت", []rune{0xFE91, 0xFEF4, 0xFE96}},
+		{"سلام", []rune{0xFEB3, 0xFEFC, 0xFEE1}},
+		{"لا", []rune{0xFEFB}},
+		{"ب ب", []rune{0xFE8F, ' ', 0xFE8F}},
+		{"a", []rune{'a'}},
+	}
+	for _, test := range tests {
+		if shaped := shapeArabic([]rune(test.logical)); string(shaped) != string(test.shaped) {
+			t.Errorf("%q: expected %U, got %U", test.logical, test.shaped, shaped)
+		}
+	}
+
+	// Presentation forms are drawn with the letter when there is no glyph
+	if presentation[0xFE91] != 0x0628 || presentation[0xFEE1] != 0x0645 {
+		t.Error("Unexpected letters for presentation forms")
+	}
+}
+
+func Test_Cluster_001(t *testing.T) {
+	tests := []struct {
+		text string
+		n    int
+	}{
+		{"abc", 3},
+		{"éx", 2},
+		{"🇬🇧🇫🇷", 2},
+		{"👩‍💻", 1},
+		{"👍🏽!", 2},
+		{"❤️", 1},
+	}
+	for _, test := range tests {
+		if n := len(clusters([]rune(test.text))); n != test.n {
+			t.Errorf("%q: expected %v clusters, got %v", test.text, test.n, n)
+		}
+	}
+}
+
+func Test_GSUB_001(t *testing.T) {
+	// Glyphs 1 and 2 are replaced with 9 by the liga feature, and the
+	// ligature in the dlig feature is not applied
+	data, err := parseGSUB(newGSUB())
+	if err != nil {
+		t.Fatal(err)
+	} else if len(data) != 1 {
+		t.Fatal("Expected one lookup, got", len(data))
+	}
+	glyphs := data.apply([]glyph{{index: 1}, {index: 2}, {index: 3}, {index: 1}})
+	if len(glyphs) != 3 || glyphs[0].index != 9 || glyphs[1].index != 3 || glyphs[2].index != 1 {
+		t.Error("Unexpected glyphs", glyphs)
+	}
+
+	// Truncated tables return an error
+	if _, err := parseGSUB(newGSUB()[:20]); err == nil {
+		t.Error("Expected error for truncated table")
+	}
+}
+
+func Test_Color_001(t *testing.T) {
+	img := newPNG(t, 20, 20)
+
+	// Glyph from CBDT with small metrics, where the strike is scaled
+	cbdt, err := newCBDT(newCBLC(5, len(img)), newCBDTData(img))
+	if err != nil {
+		t.Fatal(err)
+	} else if bitmap, err := cbdt.glyph(5, 40); err != nil {
+		t.Error(err)
+	} else if bitmap == nil || bitmap.ppem != 20 || bitmap.bounds != image.Rect(1, -16, 21, 4) {
+		t.Error("Unexpected glyph", bitmap)
+	} else if bitmap, err := cbdt.glyph(6, 20); err != nil || bitmap != nil {
+		t.Error("Expected no glyph", bitmap, err)
+	}
+
+	// Glyph from sbix, with a duplicate
+	sbix, err := newSBIX(newSBIXData(img), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, index := range []sfnt.GlyphIndex{1, 2} {
+		if bitmap, err := sbix.glyph(index, 20); err != nil {
+			t.Error(err)
+		} else if bitmap == nil || bitmap.bounds != image.Rect(2, -23, 22, -3) {
+			t.Error("Unexpected glyph", index, bitmap)
+		}
+	}
+	if bitmap, err := sbix.glyph(0, 20); err != nil || bitmap != nil {
+		t.Error("Expected no glyph", bitmap, err)
+	}
+}
+
+func Test_Layout_001(t *testing.T) {
+	face, err := Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := Chain{face}
+
+	// Brackets around left to right text in a right to left line are
+	// mirrored and reversed, so are shown unchanged
+	line := chain.Layout("(a)", 20, DIRECTION_RTL)
+	if len(line.glyphs) != 3 {
+		t.Fatal("Unexpected line", line)
+	}
+	for i, r := range "(a)" {
+		if index, _ := face.glyph(r); line.glyphs[i].index != index {
+			t.Error("Unexpected glyph", i, line.glyphs[i])
+		}
+	}
+
+	// Glyphs are drawn within the width and height of the line
+	line = chain.Layout("Hello", 20, DIRECTION_AUTO)
+	if line.Width() <= 0 || line.Height() < 20 || line.Ascent() <= 0 {
+		t.Fatal("Unexpected line", line)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, line.Width(), line.Height()))
+	if err := line.Draw(dst, image.Pt(0, line.Ascent()), color.Black); err != nil {
+		t.Fatal(err)
+	} else if opaque(dst) == 0 {
+		t.Error("Expected text to be drawn")
+	}
+
+	// Colour glyphs are drawn with their colours
+	index, _ := face.glyph('a')
+	face.color, err = newCBDT(newCBLC(index, len(newPNG(t, 20, 20))), newCBDTData(newPNG(t, 20, 20)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst = image.NewRGBA(image.Rect(0, 0, 40, 40))
+	line = chain.Layout("a", 20, DIRECTION_AUTO)
+	if err := line.Draw(dst, image.Pt(0, 20), color.Black); err != nil {
+		t.Fatal(err)
+	} else if c := dst.RGBAAt(10, 10); c != red {
+		t.Error("Expected colour glyph, got", c)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func newPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, red)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newGSUB returns a table with a liga feature with a ligature of
+// glyphs 1 and 2, and a dlig feature with a ligature of glyphs 3 and 1
+func newGSUB() []byte {
+	return be(
+		u16(1), u16(0), u16(10), u16(12), u16(38), // Header
+		u16(0),                                   // Scripts
+		u16(2), "liga", u16(14), "dlig", u16(20), // Features
+		u16(0), u16(1), u16(0), // liga: lookup 0
+		u16(0), u16(1), u16(1), // dlig: lookup 1
+		u16(2), u16(6), u16(38), // Lookups
+		u16(4), u16(0), u16(1), u16(8), // Lookup 0
+		u16(1), u16(8), u16(1), u16(14), // Ligature subtable
+		u16(1), u16(1), u16(1), // Coverage of glyph 1
+		u16(1), u16(4), // Ligature set
+		u16(9), u16(2), u16(2), // Ligature of 1, 2
+		u16(4), u16(0), u16(1), u16(8), // Lookup 1
+		u16(1), u16(8), u16(1), u16(14), // Ligature subtable
+		u16(1), u16(1), u16(3), // Coverage of glyph 3
+		u16(1), u16(4), // Ligature set
+		u16(8), u16(2), u16(1), // Ligature of 3, 1
+	)
+}
+
+// newCBLC returns a table with one strike of 20 pixels with a glyph
+func newCBLC(index sfnt.GlyphIndex, n int) []byte {
+	return be(
+		u16(3), u16(0), u32(1), // Header
+		u32(56), u32(32), u32(1), u32(0), make([]byte, 24), // Strike
+		u16(uint16(index)), u16(uint16(index)), uint8(20), uint8(20), uint8(32), uint8(1),
+		u16(uint16(index)), u16(uint16(index)), u32(8), // Subtable array
+		u16(1), u16(17), u32(4), u32(0), u32(uint32(n+9)), // Subtable
+	)
+}
+
+// newCBDTData returns a glyph with small metrics and a PNG image
+func newCBDTData(img []byte) []byte {
+	return be(
+		u16(3), u16(0), // Header
+		uint8(20), uint8(20), int8(1), int8(16), uint8(20), u32(uint32(len(img))), img,
+	)
+}
+
+// newSBIXData returns a strike of 20 pixels with an empty glyph, a glyph
+// with a PNG image, and a duplicate of the glyph
+func newSBIXData(img []byte) []byte {
+	n := uint32(len(img) + 8)
+	return be(
+		u16(1), u16(1), u32(1), u32(12), // Header
+		u16(20), u16(72), u32(20), u32(20), u32(20+n), u32(30+n), // Strike
+		u16(2), u16(3), "png ", img, // Glyph 1
+		u16(0), u16(0), "dupe", u16(1), // Glyph 2
+	)
+}
+
+// be returns values encoded big-endian
+func be(values ...interface{}) []byte {
+	var buf bytes.Buffer
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			buf.WriteString(str)
+		} else {
+			binary.Write(&buf, binary.BigEndian, value)
+		}
+	}
+	return buf.Bytes()
+}
+
+func u16(v uint16) uint16 { return v }
+func u32(v uint32) uint32 { return v }
+
+// opaque returns the number of pixels which are not transparent
+func opaque(img *image.RGBA) int {
+	n := 0
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] != 0 {
+			n++
+		}
+	}
+	return n
+}
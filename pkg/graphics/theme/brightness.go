@@ -0,0 +1,100 @@
+package theme
+
+import (
+	"math"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// curve maps perceived brightness to the brightness of a backlight
+type curve uint
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	curveLinear curve = iota // Brightness is proportional to perceived brightness
+	curveGamma               // Brightness is perceived brightness with a gamma of 2.2
+)
+
+const (
+	// gamma relates perceived brightness to the light from a backlight
+	gamma = 2.2
+
+	// brightLux is the illuminance at which the backlight is at maximum
+	// brightness, which is a bright room or overcast daylight
+	brightLux = 1000
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func parseCurve(value string) (curve, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "linear":
+		return curveLinear, nil
+	case "gamma":
+		return curveGamma, nil
+	default:
+		return 0, gopi.ErrBadParameter.WithPrefix(value)
+	}
+}
+
+// brightness returns the brightness of a backlight for a percentage of
+// perceived brightness, which is at least one unless the percentage is zero
+// so that the backlight is not switched off
+func (c curve) brightness(percent float64, max uint) uint {
+	f := clamp(percent / 100)
+	if c == curveGamma {
+		f = math.Pow(f, gamma)
+	}
+	value := uint(math.Round(f * float64(max)))
+	if value == 0 && percent > 0 && max > 0 {
+		value = 1
+	}
+	return value
+}
+
+// percent returns the perceived brightness as a percentage for the
+// brightness of a backlight
+func (c curve) percent(value, max uint) float64 {
+	if max == 0 {
+		return 0
+	}
+	f := clamp(float64(value) / float64(max))
+	if c == curveGamma {
+		f = math.Pow(f, 1/gamma)
+	}
+	return f * 100
+}
+
+// luxLevel returns the fraction of full brightness for an illuminance,
+// which follows the logarithmic response of the eye
+func luxLevel(lux float32) float64 {
+	if lux <= 0 {
+		return 0
+	}
+	return clamp(math.Log10(1+float64(lux)) / math.Log10(1+brightLux))
+}
+
+// sunLevel returns the fraction of full brightness for the elevation of
+// the sun, which rises through twilight from when it is dark until the
+// sun is as far above the horizon
+func sunLevel(elevation float64) float64 {
+	return clamp((elevation - twilight) / (-2 * twilight))
+}
+
+func clamp(f float64) float64 {
+	switch {
+	case f < 0 || math.IsNaN(f):
+		return 0
+	case f > 1:
+		return 1
+	default:
+		return f
+	}
+}
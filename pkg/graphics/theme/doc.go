@@ -0,0 +1,25 @@
+// Theme package implements gopi.Theme, which provides the colours that
+// widgets such as the clock draw with, and switches between light and
+// dark colours. Colours have names such as "background", "surface",
+// "foreground", "muted", "accent" and "warning", and can be changed or
+// added with a JSON file set with -theme.path, for example:
+//
+//   {
+//     "light": { "accent": "#0b57d0" },
+//     "dark": { "accent": "#ffb000", "clock": "orange" }
+//   }
+//
+// When -theme.mode is auto, dark colours are used when the gopi.LightSensor
+// unit reads below -theme.lux, and light colours above double that, so
+// that the colours do not switch back and forth. Without recent readings,
+// dark colours are used between dusk and dawn at the location set with
+// -theme.lat and -theme.lon, which is when the sun is more than six
+// degrees below the horizon. A gopi.ThemeEvent is emitted on switching.
+//
+// When there is a backlight, its brightness follows the light reading
+// or the sun between -theme.brightness.min and -theme.brightness.max, and
+// fades gradually over -theme.brightness.fade. The gamma curve changes the
+// brightness evenly as it is seen, and the linear curve changes the light
+// from the backlight evenly. The brightness is not changed while the
+// gopi.IdleMonitor unit has dimmed the backlight.
+package theme
@@ -0,0 +1,41 @@
+package theme
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	dark bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(dark bool) gopi.ThemeEvent {
+	return &event{dark}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "theme"
+}
+
+func (this *event) Dark() bool {
+	return this.dark
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<theme.event"
+	str += fmt.Sprint(" dark=", this.dark)
+	return str + ">"
+}
@@ -0,0 +1,13 @@
+package theme
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.Theme
+	graph.RegisterUnit(reflect.TypeOf(&theme{}), reflect.TypeOf((*gopi.Theme)(nil)))
+}
@@ -0,0 +1,91 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	scene "github.com/djthorpe/gopi/v3/pkg/graphics/scene"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// palette is the colour for each name
+type palette map[string]string
+
+// palettes are the light and dark colours, which are read from a file
+type palettes struct {
+	Light palette `json:"light"`
+	Dark  palette `json:"dark"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	light = palette{
+		"background": "#ffffff",
+		"surface":    "#f1f1f1",
+		"foreground": "#202020",
+		"muted":      "#6e6e6e",
+		"accent":     "#1a73e8",
+		"warning":    "#d93025",
+	}
+	dark = palette{
+		"background": "#000000",
+		"surface":    "#1e1e1e",
+		"foreground": "#ffffff",
+		"muted":      "#9e9e9e",
+		"accent":     "#8ab4f8",
+		"warning":    "#ff0000",
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// readPalettes returns the default palettes with the colours from a file,
+// or the default palettes when the path is empty
+func readPalettes(path string) (palettes, error) {
+	result := palettes{light.merge(nil), dark.merge(nil)}
+	if path == "" {
+		return result, nil
+	}
+
+	fh, err := os.Open(path)
+	if err != nil {
+		return result, err
+	}
+	defer fh.Close()
+
+	var file palettes
+	if err := json.NewDecoder(fh).Decode(&file); err != nil {
+		return result, err
+	}
+	for _, p := range []palette{file.Light, file.Dark} {
+		for name, value := range p {
+			if c, err := scene.ParseColor(value); err != nil {
+				return result, err
+			} else if c == nil {
+				return result, gopi.ErrBadParameter.WithPrefix(fmt.Sprintf("%q", name))
+			}
+		}
+	}
+
+	// Return success
+	return palettes{result.Light.merge(file.Light), result.Dark.merge(file.Dark)}, nil
+}
+
+// merge returns a copy of a palette with other colours added
+func (p palette) merge(other palette) palette {
+	result := make(palette, len(p)+len(other))
+	for name, value := range p {
+		result[name] = value
+	}
+	for name, value := range other {
+		result[name] = value
+	}
+	return result
+}
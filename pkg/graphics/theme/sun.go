@@ -0,0 +1,52 @@
+package theme
+
+import (
+	"math"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// twilight is the elevation of the sun in degrees at the end of civil
+	// twilight, below which it is dark
+	twilight = -6.0
+
+	// j2000 is the Unix time of the J2000 epoch
+	j2000 = 946728000
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// elevation returns the elevation of the sun in degrees above the horizon
+// at a time and location, which is accurate to within a degree
+func elevation(t time.Time, lat, lon float64) float64 {
+	d := float64(t.Unix()-j2000) / 86400
+
+	// Ecliptic longitude of the sun and obliquity of the ecliptic
+	g := radians(357.529 + 0.98560028*d)
+	q := 280.459 + 0.98564736*d
+	l := radians(q + 1.915*math.Sin(g) + 0.020*math.Sin(2*g))
+	e := radians(23.439 - 0.00000036*d)
+
+	// Right ascension and declination
+	ra := math.Atan2(math.Cos(e)*math.Sin(l), math.Cos(l))
+	dec := math.Asin(math.Sin(e) * math.Sin(l))
+
+	// Hour angle from the sidereal time at the longitude
+	gmst := 280.46061837 + 360.98564736629*d
+	ha := radians(gmst+lon) - ra
+
+	phi := radians(lat)
+	return degrees(math.Asin(math.Sin(phi)*math.Sin(dec) + math.Cos(phi)*math.Cos(dec)*math.Cos(ha)))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
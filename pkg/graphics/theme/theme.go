@@ -0,0 +1,369 @@
+package theme
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type theme struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Backlight
+	gopi.LightSensor
+	sync.RWMutex
+
+	// Flags
+	mode, path, curveName *string
+	lat, lon, threshold   *float64
+	auto                  *bool
+	min, max              *uint
+	fade                  *time.Duration
+
+	setting  gopi.ThemeMode
+	dark     bool
+	palettes palettes
+	curve    curve
+	device   string
+	located  bool
+	lux      float32
+	luxTime  time.Time
+	idle     bool
+	maxValue uint    // Maximum brightness of the backlight
+	value    uint    // Brightness of the backlight
+	level    float64 // Perceived brightness as a percentage
+	target   float64 // Perceived brightness to fade to
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Interval for following the sun
+	updateInterval = time.Minute
+
+	// Interval between steps when fading the backlight
+	fadeInterval = 50 * time.Millisecond
+
+	// Readings from a light sensor are followed until they are older
+	// than this, and then the sun is followed
+	staleLux = 5 * time.Minute
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *theme) Define(cfg gopi.Config) error {
+	this.mode = cfg.FlagString("theme.mode", "auto", "Theme colours (auto, light, dark)")
+	this.path = cfg.FlagPath("theme.path", "", "JSON file of light and dark colours")
+	this.lat = cfg.FlagFloat("theme.lat", 0, "Latitude of location, for following the sun")
+	this.lon = cfg.FlagFloat("theme.lon", 0, "Longitude of location, for following the sun")
+	this.threshold = cfg.FlagFloat("theme.lux", 10, "Illuminance in lux below which dark colours are used")
+	this.auto = cfg.FlagBool("theme.brightness", true, "Adjust the backlight brightness automatically")
+	this.min = cfg.FlagUint("theme.brightness.min", 10, "Minimum backlight brightness as a percentage")
+	this.max = cfg.FlagUint("theme.brightness.max", 100, "Maximum backlight brightness as a percentage")
+	this.curveName = cfg.FlagString("theme.brightness.curve", "gamma", "Brightness curve of the backlight (linear, gamma)")
+	this.fade = cfg.FlagDuration("theme.brightness.fade", 5*time.Second, "Time to fade the backlight from minimum to maximum brightness")
+	cfg.FlagString("theme.backlight", "", "Backlight device, or the first device when empty")
+	return nil
+}
+
+func (this *theme) New(cfg gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check flags
+	if mode, err := parseMode(*this.mode); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-theme.mode")
+	} else {
+		this.setting = mode
+	}
+	if *this.lat < -90 || *this.lat > 90 {
+		return gopi.ErrBadParameter.WithPrefix("-theme.lat")
+	} else if *this.lon < -180 || *this.lon > 180 {
+		return gopi.ErrBadParameter.WithPrefix("-theme.lon")
+	} else if *this.threshold < 0 {
+		return gopi.ErrBadParameter.WithPrefix("-theme.lux")
+	} else if *this.max > 100 || *this.min > *this.max {
+		return gopi.ErrBadParameter.WithPrefix("-theme.brightness.min, -theme.brightness.max")
+	} else if *this.fade < 0 {
+		return gopi.ErrBadParameter.WithPrefix("-theme.brightness.fade")
+	} else if curve, err := parseCurve(*this.curveName); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-theme.brightness.curve")
+	} else {
+		this.curve = curve
+	}
+	this.located = *this.lat != 0 || *this.lon != 0
+
+	// Read colours
+	if palettes, err := readPalettes(*this.path); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-theme.path: ", err)
+	} else {
+		this.palettes = palettes
+	}
+
+	// Set backlight device and read the brightness
+	if this.Backlight != nil && *this.auto {
+		if device := cfg.GetString("theme.backlight"); device != "" {
+			this.device = device
+		} else if devices := this.Backlight.Devices(); len(devices) > 0 {
+			this.device = devices[0]
+		}
+	}
+	if this.device != "" {
+		if value, max, err := this.Backlight.Brightness(this.device); err != nil {
+			return err
+		} else {
+			this.value, this.maxValue = value, max
+			this.level = this.curve.percent(value, max)
+		}
+	}
+
+	// Read the light sensor
+	if this.LightSensor != nil {
+		if lux, ok := this.LightSensor.Lux(); ok {
+			this.lux, this.luxTime = lux, time.Now()
+		}
+	}
+
+	// Set colours and the brightness to fade to
+	this.update(time.Now())
+
+	// Return success
+	return nil
+}
+
+func (this *theme) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.palettes = palettes{}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *theme) Run(ctx context.Context) error {
+	var ch <-chan gopi.Event
+	if this.Publisher != nil {
+		ch = this.Publisher.Subscribe()
+		defer this.Publisher.Unsubscribe(ch)
+	}
+
+	ticker := time.NewTicker(updateInterval)
+	defer ticker.Stop()
+	fade := time.NewTicker(fadeInterval)
+	defer fade.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			this.change(now)
+		case <-fade.C:
+			this.step()
+		case evt := <-ch:
+			switch evt := evt.(type) {
+			case gopi.LightEvent:
+				now := time.Now()
+				this.RWMutex.Lock()
+				this.lux, this.luxTime = evt.Lux(), now
+				this.RWMutex.Unlock()
+				this.change(now)
+			case gopi.IdleEvent:
+				// The idle monitor dims the backlight, so the brightness
+				// is not changed until there is input
+				this.RWMutex.Lock()
+				this.idle = evt.Type() == gopi.IDLE_EVENT_ENTER
+				this.RWMutex.Unlock()
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *theme) Mode() gopi.ThemeMode {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.setting
+}
+
+func (this *theme) SetMode(mode gopi.ThemeMode) error {
+	switch mode {
+	case gopi.THEME_MODE_AUTO, gopi.THEME_MODE_LIGHT, gopi.THEME_MODE_DARK:
+		break
+	default:
+		return gopi.ErrBadParameter.WithPrefix("SetMode")
+	}
+
+	this.RWMutex.Lock()
+	this.setting = mode
+	this.RWMutex.Unlock()
+
+	// Switch colours
+	this.change(time.Now())
+
+	// Return success
+	return nil
+}
+
+func (this *theme) Dark() bool {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.dark
+}
+
+func (this *theme) Color(name string) string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.palette()[name]
+}
+
+func (this *theme) Names() []string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	p := this.palette()
+	result := make([]string, 0, len(p))
+	for name := range p {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *theme) String() string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	str := "<theme"
+	str += fmt.Sprint(" mode=", this.setting)
+	str += fmt.Sprint(" dark=", this.dark)
+	if this.device != "" {
+		str += fmt.Sprintf(" backlight=%q", this.device)
+		str += fmt.Sprintf(" brightness=%.0f%%", this.level)
+	}
+	if this.luxTime.IsZero() == false {
+		str += fmt.Sprint(" lux=", this.lux)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func parseMode(value string) (gopi.ThemeMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "auto":
+		return gopi.THEME_MODE_AUTO, nil
+	case "light":
+		return gopi.THEME_MODE_LIGHT, nil
+	case "dark":
+		return gopi.THEME_MODE_DARK, nil
+	default:
+		return 0, gopi.ErrBadParameter.WithPrefix(value)
+	}
+}
+
+// change updates the colours and brightness, and emits an event when
+// switching between light and dark colours
+func (this *theme) change(now time.Time) {
+	this.RWMutex.Lock()
+	dark := this.dark
+	this.update(now)
+	changed := this.dark != dark
+	dark = this.dark
+	this.RWMutex.Unlock()
+
+	if changed && this.Publisher != nil {
+		if err := this.Publisher.Emit(NewEvent(dark), false); err != nil {
+			this.Debug("Theme: ", err)
+		}
+	}
+}
+
+// update sets dark colours and the brightness to fade to, from the light
+// sensor while it has recent readings, or otherwise from the elevation of
+// the sun when the location is set. Between the lux threshold and double
+// the threshold the colours are not switched, so that they do not switch
+// back and forth
+func (this *theme) update(now time.Time) {
+	dark, level := false, 1.0
+	switch {
+	case this.luxTime.IsZero() == false && now.Sub(this.luxTime) < staleLux:
+		level = luxLevel(this.lux)
+		lux := float64(this.lux)
+		dark = lux < *this.threshold || (this.dark && lux <= 2*(*this.threshold))
+	case this.located:
+		e := elevation(now, *this.lat, *this.lon)
+		level = sunLevel(e)
+		dark = e < twilight
+	}
+
+	switch this.setting {
+	case gopi.THEME_MODE_AUTO:
+		this.dark = dark
+	default:
+		this.dark = this.setting == gopi.THEME_MODE_DARK
+	}
+	this.target = float64(*this.min) + float64(*this.max-*this.min)*level
+}
+
+// step fades the brightness of the backlight towards the target, where
+// the perceived brightness changes at the same rate over the whole range
+func (this *theme) step() {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	if this.device == "" || this.idle || this.level == this.target {
+		return
+	}
+
+	// Move towards the target
+	delta := 100.0
+	if *this.fade > 0 {
+		delta = 100 * float64(fadeInterval) / float64(*this.fade)
+	}
+	if diff := this.target - this.level; math.Abs(diff) <= delta {
+		this.level = this.target
+	} else if diff > 0 {
+		this.level += delta
+	} else {
+		this.level -= delta
+	}
+
+	// Set the brightness when it has changed
+	if value := this.curve.brightness(this.level, this.maxValue); value != this.value {
+		if err := this.Backlight.SetBrightness(this.device, value); err != nil {
+			this.Debug("Theme: ", err)
+		} else {
+			this.value = value
+		}
+	}
+}
+
+// palette returns the colours which are used
+func (this *theme) palette() palette {
+	if this.dark {
+		return this.palettes.Dark
+	}
+	return this.palettes.Light
+}
@@ -0,0 +1,196 @@
+package theme
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// backlight records the brightness which is set
+type backlight struct {
+	values []uint
+}
+
+func (this *backlight) Devices() []string                     { return []string{"rpi_backlight"} }
+func (this *backlight) Brightness(string) (uint, uint, error) { return 255, 255, nil }
+func (this *backlight) SetPower(string, bool) error           { return nil }
+
+func (this *backlight) SetBrightness(_ string, value uint) error {
+	this.values = append(this.values, value)
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Sun_001(t *testing.T) {
+	tests := []struct {
+		t         time.Time
+		lat, lon  float64
+		elevation float64
+	}{
+		{time.Date(2021, 6, 21, 12, 0, 0, 0, time.UTC), 51.5, 0, 62},
+		{time.Date(2021, 12, 21, 12, 0, 0, 0, time.UTC), 51.5, 0, 15},
+		{time.Date(2021, 6, 21, 0, 0, 0, 0, time.UTC), 51.5, 0, -15},
+		{time.Date(2021, 6, 21, 17, 0, 0, 0, time.UTC), 40.7, -74, 72.7},
+	}
+	for _, test := range tests {
+		if e := elevation(test.t, test.lat, test.lon); math.Abs(e-test.elevation) > 1 {
+			t.Errorf("%v: expected %.1f, got %.1f", test.t, test.elevation, e)
+		}
+	}
+}
+
+func Test_Curve_001(t *testing.T) {
+	for _, c := range []curve{curveLinear, curveGamma} {
+		for _, percent := range []float64{25, 50, 100} {
+			if value := c.brightness(percent, 255); math.Abs(c.percent(value, 255)-percent) > 1 {
+				t.Error("Unexpected brightness", c, percent, value)
+			}
+		}
+		if value := c.brightness(0.1, 255); value != 1 {
+			t.Error("Expected backlight not to be switched off", c, value)
+		} else if value := c.brightness(0, 255); value != 0 {
+			t.Error("Expected backlight to be switched off", c, value)
+		}
+	}
+
+	// Half perceived brightness is less than half the light
+	if value := curveGamma.brightness(50, 255); value >= 128 {
+		t.Error("Unexpected brightness", value)
+	}
+
+	// Levels rise with light and the sun
+	if luxLevel(0) != 0 || luxLevel(brightLux) != 1 || luxLevel(10) >= luxLevel(100) {
+		t.Error("Unexpected lux levels")
+	}
+	if sunLevel(-10) != 0 || sunLevel(0) != 0.5 || sunLevel(10) != 1 {
+		t.Error("Unexpected sun levels")
+	}
+}
+
+func Test_Theme_001(t *testing.T) {
+	this := newTheme(t, nil)
+	now := time.Now()
+
+	// Colours switch to dark below the threshold, and back to light above
+	// double the threshold
+	for _, test := range []struct {
+		lux  float32
+		dark bool
+	}{
+		{100, false}, {5, true}, {15, true}, {25, false}, {15, false}, {5, true},
+	} {
+		this.lux, this.luxTime = test.lux, now
+		this.update(now)
+		if this.Dark() != test.dark {
+			t.Error("Unexpected colours", test.lux, this.Dark())
+		}
+	}
+	if c := this.Color("background"); c != dark["background"] {
+		t.Error("Unexpected colour", c)
+	}
+
+	// Old readings are ignored, and then the sun is followed
+	this.luxTime = time.Date(2021, 6, 20, 0, 0, 0, 0, time.UTC)
+	this.located, *this.lat, *this.lon = true, 51.5, 0
+	this.update(time.Date(2021, 6, 21, 12, 0, 0, 0, time.UTC))
+	if this.Dark() || this.target != 100 {
+		t.Error("Expected light colours at noon", this.target)
+	}
+	this.update(time.Date(2021, 6, 21, 0, 0, 0, 0, time.UTC))
+	if this.Dark() == false || this.target != 10 {
+		t.Error("Expected dark colours at midnight", this.target)
+	}
+
+	// Colours can be set
+	if err := this.SetMode(gopi.THEME_MODE_LIGHT); err != nil {
+		t.Error(err)
+	} else if this.Dark() {
+		t.Error("Expected light colours")
+	} else if err := this.SetMode(gopi.ThemeMode(99)); err == nil {
+		t.Error("Expected error for invalid mode")
+	}
+}
+
+func Test_Theme_002(t *testing.T) {
+	b := new(backlight)
+	this := newTheme(t, b)
+
+	// Fade from maximum to minimum brightness in steps
+	this.lux, this.luxTime = 0, time.Now()
+	this.update(time.Now())
+	steps := int(*this.fade / fadeInterval)
+	for i := 0; i < steps; i++ {
+		this.step()
+	}
+	if this.level != 10 || len(b.values) < steps/2 {
+		t.Fatal("Unexpected fade", this.level, b.values)
+	}
+	for i := 1; i < len(b.values); i++ {
+		if b.values[i] >= b.values[i-1] {
+			t.Fatal("Expected brightness to fall", b.values)
+		}
+	}
+
+	// The brightness is not changed while idle
+	n := len(b.values)
+	this.idle, this.target = true, 100
+	this.step()
+	if len(b.values) != n {
+		t.Error("Unexpected brightness while idle")
+	}
+}
+
+func Test_Palette_001(t *testing.T) {
+	dir, err := ioutil.TempDir("", "theme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "theme.json")
+	if err := ioutil.WriteFile(path, []byte(`{ "dark": { "accent": "#f80", "clock": "orange" } }`), 0600); err != nil {
+		t.Fatal(err)
+	} else if p, err := readPalettes(path); err != nil {
+		t.Fatal(err)
+	} else if p.Dark["accent"] != "#f80" || p.Dark["clock"] != "orange" || p.Dark["background"] != dark["background"] {
+		t.Error("Unexpected colours", p.Dark)
+	} else if p.Light["accent"] != light["accent"] {
+		t.Error("Unexpected colours", p.Light)
+	}
+
+	// Invalid colours return an error
+	if err := ioutil.WriteFile(path, []byte(`{ "light": { "accent": "nope" } }`), 0600); err != nil {
+		t.Fatal(err)
+	} else if _, err := readPalettes(path); err == nil {
+		t.Error("Expected error for invalid colour")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// newTheme returns a theme with the default flags
+func newTheme(t *testing.T, b *backlight) *theme {
+	t.Helper()
+	lat, lon, threshold := 0.0, 0.0, 10.0
+	min, max, fade := uint(10), uint(100), time.Second
+	this := &theme{lat: &lat, lon: &lon, threshold: &threshold, min: &min, max: &max, fade: &fade}
+	this.palettes = palettes{light.merge(nil), dark.merge(nil)}
+	this.curve = curveGamma
+	if b != nil {
+		this.Backlight, this.device = b, "rpi_backlight"
+		this.value, this.maxValue, _ = b.Brightness("")
+		this.level = this.curve.percent(this.value, this.maxValue)
+	}
+	return this
+}
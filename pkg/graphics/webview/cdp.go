@@ -0,0 +1,150 @@
+package webview
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	websocket "golang.org/x/net/websocket"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// cdp is a connection to a browser page using the DevTools protocol.
+// Commands are sent without waiting for their results, which are
+// received with events
+type cdp struct {
+	sync.Mutex
+	conn *websocket.Conn
+	id   uint64
+}
+
+// message is a command sent to the browser, or a result or event
+// received from it
+type message struct {
+	Id     uint64          `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Error  *messageError   `json:"error,omitempty"`
+}
+
+type messageError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// target is a page which can be debugged
+type target struct {
+	Type string `json:"type"`
+	Url  string `json:"webSocketDebuggerUrl"`
+}
+
+// frame is painted by the browser during a screencast
+type frame struct {
+	Data      []byte `json:"data"`
+	SessionId int    `json:"sessionId"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Origin of the websocket connection, which the browser is started
+	// to allow
+	origin = "http://localhost"
+
+	// Interval between attempts to connect while the browser starts
+	dialInterval = 250 * time.Millisecond
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// dial connects to the first page of a browser with a debugging address,
+// retrying until the context is done while the browser starts
+func dial(ctx context.Context, addr string) (*cdp, error) {
+	ticker := time.NewTicker(dialInterval)
+	defer ticker.Stop()
+	for {
+		if url, err := page(ctx, addr); err == nil {
+			if conn, err := websocket.Dial(url, "", origin); err == nil {
+				return &cdp{conn: conn}, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (this *cdp) Close() error {
+	return this.conn.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Send a command with parameters
+func (this *cdp) Send(method string, params interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.id++
+	return websocket.JSON.Send(this.conn, message{Id: this.id, Method: method, Params: data})
+}
+
+// Recv blocks until a result or event is received
+func (this *cdp) Recv() (*message, error) {
+	var msg message
+	if err := websocket.JSON.Receive(this.conn, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *messageError) Error() string {
+	return this.Message
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// page returns the websocket address of the first page of a browser
+func page(ctx context.Context, addr string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+"/json/list", nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", gopi.ErrUnexpectedResponse.WithPrefix(response.Status)
+	}
+
+	var targets []target
+	if err := json.NewDecoder(response.Body).Decode(&targets); err != nil {
+		return "", err
+	}
+	for _, target := range targets {
+		if target.Type == "page" && target.Url != "" {
+			return target.Url, nil
+		}
+	}
+	return "", gopi.ErrNotFound.WithPrefix("page")
+}
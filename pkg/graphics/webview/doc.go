@@ -0,0 +1,18 @@
+// Webview package implements gopi.WebView, which shows a web page on a
+// surface so that dashboards authored as web pages can be shown
+// alongside native surfaces. Rather than rendering HTML itself, the unit
+// runs a Chromium-based browser without a window using the process
+// manager, sized to the bounds on the surface.
+//
+// The unit connects to the browser using the DevTools protocol over a
+// websocket, starts a screencast of the page, and draws each JPEG frame
+// the browser paints on the surface. Navigating loads a new page in the
+// same browser. The browser is stopped when the webview is stopped, and
+// restarted by the process manager if it fails.
+//
+// For example, to show a page on the whole of a surface:
+//
+//   app.WebView.Navigate("http://localhost/dashboard")
+//   app.WebView.Start(surface, image.Rectangle{})
+//
+package webview
@@ -0,0 +1,13 @@
+package webview
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.WebView
+	graph.RegisterUnit(reflect.TypeOf(&webview{}), reflect.TypeOf((*gopi.WebView)(nil)))
+}
@@ -0,0 +1,323 @@
+package webview
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type webview struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.ProcessManager
+	sync.Mutex
+
+	// Flags
+	browser *string
+	args    *string
+	url     *string
+	port    *uint
+	quality *uint
+
+	surface gopi.Surface
+	bounds  image.Rectangle
+	process gopi.Process
+	conn    *cdp
+	dir     string
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	processName = "webview"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *webview) Define(cfg gopi.Config) error {
+	this.browser = cfg.FlagString("webview.browser", "chromium-browser", "Chromium-based browser executable")
+	this.args = cfg.FlagString("webview.args", "", "Additional browser arguments")
+	this.url = cfg.FlagString("webview.url", "about:blank", "Page shown when started")
+	this.port = cfg.FlagUint("webview.port", 9222, "Port for connecting to the browser")
+	this.quality = cfg.FlagUint("webview.quality", 80, "JPEG quality of frames painted by the browser")
+	return nil
+}
+
+func (this *webview) New(gopi.Config) error {
+	this.Require(this.Logger, this.ProcessManager)
+
+	// Check parameters
+	if strings.TrimSpace(*this.browser) == "" {
+		return gopi.ErrBadParameter.WithPrefix("-webview.browser")
+	} else if *this.port == 0 || *this.port > 0xFFFF {
+		return gopi.ErrBadParameter.WithPrefix("-webview.port")
+	} else if *this.quality == 0 || *this.quality > 100 {
+		return gopi.ErrBadParameter.WithPrefix("-webview.quality")
+	} else if u, err := url.Parse(*this.url); err != nil || u.Scheme == "" {
+		return gopi.ErrBadParameter.WithPrefix("-webview.url")
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *webview) Dispose() error {
+	return this.Stop()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *webview) Start(surface gopi.Surface, bounds image.Rectangle) error {
+	if surface == nil || surface.Bitmap() == nil {
+		return gopi.ErrBadParameter.WithPrefix("Start")
+	}
+	if bounds.Empty() {
+		size := surface.Bitmap().Size()
+		bounds = image.Rect(0, 0, int(size.W), int(size.H))
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.surface != nil {
+		return gopi.ErrOutOfOrder.WithPrefix("Start")
+	}
+
+	// Start the browser with a profile which is removed when stopped
+	dir, err := ioutil.TempDir("", processName)
+	if err != nil {
+		return err
+	}
+	args := []string{
+		"--headless",
+		"--no-first-run",
+		"--hide-scrollbars",
+		"--remote-allow-origins=" + origin,
+		fmt.Sprint("--remote-debugging-port=", *this.port),
+		fmt.Sprint("--window-size=", bounds.Dx(), ",", bounds.Dy()),
+		"--user-data-dir=" + dir,
+	}
+	args = append(append(args, strings.Fields(*this.args)...), *this.url)
+	process, err := this.ProcessManager.Start(processName, gopi.PROCESS_RESTART_ONFAILURE, gopi.ProcessLimits{}, *this.browser, args...)
+	if err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+
+	// Draw frames in the background until stopped
+	ctx, cancel := context.WithCancel(context.Background())
+	this.surface, this.bounds, this.process, this.dir, this.cancel = surface, bounds, process, dir, cancel
+	this.wg.Add(1)
+	go func() {
+		defer this.wg.Done()
+		this.run(ctx, fmt.Sprint("127.0.0.1:", *this.port))
+	}()
+
+	// Return success
+	return nil
+}
+
+func (this *webview) Stop() error {
+	this.Mutex.Lock()
+	if this.cancel != nil {
+		this.cancel()
+	}
+	if this.conn != nil {
+		this.conn.Close()
+	}
+	this.Mutex.Unlock()
+
+	// Wait for drawing to end
+	this.wg.Wait()
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Stop the browser. The process is not found when the manager has
+	// already stopped it
+	var result error
+	if this.process != nil {
+		if err := this.ProcessManager.Stop(this.process); err != nil && errors.Is(err, gopi.ErrNotFound) == false {
+			result = err
+		}
+	}
+	if this.dir != "" {
+		os.RemoveAll(this.dir)
+	}
+
+	// Release resources
+	this.surface, this.process, this.conn, this.cancel, this.dir = nil, nil, nil, nil, ""
+
+	// Return any errors
+	return result
+}
+
+func (this *webview) Navigate(page string) error {
+	if u, err := url.Parse(page); err != nil || u.Scheme == "" {
+		return gopi.ErrBadParameter.WithPrefix("Navigate: ", page)
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	*this.url = page
+	if this.conn != nil {
+		return this.conn.Send("Page.navigate", map[string]string{"url": page})
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *webview) URL() string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return *this.url
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *webview) String() string {
+	str := "<webview"
+	str += fmt.Sprintf(" browser=%q", *this.browser)
+	str += fmt.Sprintf(" url=%q", this.URL())
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.surface != nil {
+		str += fmt.Sprint(" bounds=", this.bounds)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// run connects to the browser and draws frames until the context is
+// done, connecting again when the browser is restarted
+func (this *webview) run(ctx context.Context, addr string) {
+	for {
+		conn, err := dial(ctx, addr)
+		if err != nil {
+			return
+		}
+
+		// Close the connection when stopped while connecting
+		this.Mutex.Lock()
+		if ctx.Err() != nil {
+			this.Mutex.Unlock()
+			conn.Close()
+			return
+		}
+		this.conn = conn
+		this.Mutex.Unlock()
+
+		if err := this.screencast(conn); err != nil && ctx.Err() == nil {
+			this.Debug("Webview: ", err)
+		}
+
+		this.Mutex.Lock()
+		this.conn = nil
+		this.Mutex.Unlock()
+		conn.Close()
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// screencast shows the current page and draws each frame painted by
+// the browser until the connection is closed
+func (this *webview) screencast(conn *cdp) error {
+	this.Mutex.Lock()
+	page, bounds := *this.url, this.bounds
+	this.Mutex.Unlock()
+
+	if err := conn.Send("Page.navigate", map[string]string{"url": page}); err != nil {
+		return err
+	} else if err := conn.Send("Page.startScreencast", map[string]interface{}{
+		"format":    "jpeg",
+		"quality":   *this.quality,
+		"maxWidth":  bounds.Dx(),
+		"maxHeight": bounds.Dy(),
+	}); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := conn.Recv()
+		if err != nil {
+			return err
+		} else if msg.Error != nil {
+			this.Debug("Webview: ", msg.Error)
+			continue
+		} else if msg.Method != "Page.screencastFrame" {
+			continue
+		}
+
+		// Draw the frame, then acknowledge it so that the browser sends
+		// the next one
+		var f frame
+		if err := json.Unmarshal(msg.Params, &f); err != nil {
+			return err
+		} else if err := this.draw(f.Data); err != nil {
+			this.Debug("Webview: ", err)
+		}
+		if err := conn.Send("Page.screencastFrameAck", map[string]int{"sessionId": f.SessionId}); err != nil {
+			return err
+		}
+	}
+}
+
+// draw decodes a frame and draws it within bounds of the surface
+func (this *webview) draw(data []byte) error {
+	src, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.surface == nil {
+		return nil
+	}
+	return drawFrame(this.surface.Bitmap(), this.bounds, src)
+}
+
+// drawFrame copies an image to the top left of bounds of a bitmap,
+// clipped to the bounds and the bitmap
+func drawFrame(dst gopi.Bitmap, bounds image.Rectangle, src image.Image) error {
+	size := dst.Size()
+	sr := src.Bounds()
+	r := image.Rect(0, 0, sr.Dx(), sr.Dy()).Add(bounds.Min).Intersect(bounds).Intersect(image.Rect(0, 0, int(size.W), int(size.H)))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if err := dst.SetAt(src.At(sr.Min.X+x-bounds.Min.X, sr.Min.Y+y-bounds.Min.Y), x, y); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Return success
+	return nil
+}
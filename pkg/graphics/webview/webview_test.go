@@ -0,0 +1,187 @@
+package webview
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	rgba32 "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32"
+	websocket "golang.org/x/net/websocket"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// logger discards debugging output
+type logger struct {
+	gopi.Logger
+}
+
+type surface struct {
+	bitmap gopi.Bitmap
+}
+
+// browser serves a page which paints a frame when the screencast starts,
+// and records the commands it receives
+type browser struct {
+	*httptest.Server
+	cmds chan message
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// FAKES
+
+func (this *logger) Debug(...interface{}) {}
+
+func (this *surface) Origin() gopi.Point  { return gopi.Point{} }
+func (this *surface) Size() gopi.Size     { return this.bitmap.Size() }
+func (this *surface) Bitmap() gopi.Bitmap { return this.bitmap }
+
+func newBrowser(t *testing.T, frame []byte) *browser {
+	this := &browser{cmds: make(chan message, 10)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json/list", func(w http.ResponseWriter, req *http.Request) {
+		url := "ws://" + req.Host + "/devtools/page/1"
+		json.NewEncoder(w).Encode([]target{{"background_page", ""}, {"page", url}})
+	})
+	mux.Handle("/devtools/page/1", websocket.Handler(func(conn *websocket.Conn) {
+		for {
+			var msg message
+			if err := websocket.JSON.Receive(conn, &msg); err != nil {
+				return
+			}
+			this.cmds <- msg
+			if msg.Method == "Page.startScreencast" {
+				params, _ := json.Marshal(map[string]interface{}{"data": frame, "sessionId": 7})
+				websocket.JSON.Send(conn, message{Method: "Page.screencastFrame", Params: params})
+			}
+		}
+	}))
+	this.Server = httptest.NewServer(mux)
+	return this
+}
+
+func newBitmap(t *testing.T, w, h uint32) gopi.Bitmap {
+	t.Helper()
+	if bitmap, err := new(rgba32.Factory).New(bitmap.GetColorModel(gopi.SURFACE_FMT_RGBA32), w, h); err != nil {
+		t.Fatal(err)
+		return nil
+	} else {
+		return bitmap
+	}
+}
+
+func newFrame(t *testing.T, w, h int, c color.Color) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Webview_001(t *testing.T) {
+	dst := newBitmap(t, 8, 8)
+	dst.ClearToColor(color.White)
+	src := image.NewRGBA(image.Rect(0, 0, 8, 8))
+
+	// The frame is drawn at the top left of the bounds and clipped
+	if err := drawFrame(dst, image.Rect(4, 2, 6, 8), src); err != nil {
+		t.Fatal(err)
+	}
+	for _, pt := range []image.Point{{4, 2}, {5, 7}} {
+		if r, _, _, _ := dst.At(pt.X, pt.Y).RGBA(); r != 0 {
+			t.Error("Expected frame at", pt)
+		}
+	}
+	for _, pt := range []image.Point{{3, 2}, {6, 2}, {4, 1}} {
+		if r, _, _, _ := dst.At(pt.X, pt.Y).RGBA(); r != 0xFFFF {
+			t.Error("Unexpected frame at", pt)
+		}
+	}
+}
+
+func Test_Webview_002(t *testing.T) {
+	browser := newBrowser(t, newFrame(t, 4, 4, color.Black))
+	defer browser.Close()
+
+	dst := newBitmap(t, 8, 8)
+	dst.ClearToColor(color.White)
+	url, quality := "http://localhost/dashboard", uint(50)
+	this := &webview{Logger: &logger{}, url: &url, quality: &quality}
+	this.surface, this.bounds = &surface{dst}, image.Rect(2, 2, 6, 6)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		this.run(ctx, strings.TrimPrefix(browser.URL, "http://"))
+	}()
+
+	// The page is navigated, the screencast is started at the size of
+	// the bounds, and the frame is acknowledged
+	for _, method := range []string{"Page.navigate", "Page.startScreencast", "Page.screencastFrameAck"} {
+		select {
+		case msg := <-browser.cmds:
+			var params map[string]interface{}
+			json.Unmarshal(msg.Params, &params)
+			if msg.Method != method {
+				t.Fatal("Unexpected command", msg.Method, "expected", method)
+			} else if method == "Page.navigate" && params["url"] != url {
+				t.Error("Unexpected url", params)
+			} else if method == "Page.startScreencast" && (params["maxWidth"] != 4.0 || params["quality"] != 50.0) {
+				t.Error("Unexpected screencast", params)
+			} else if method == "Page.screencastFrameAck" && params["sessionId"] != 7.0 {
+				t.Error("Unexpected session", params)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timeout waiting for", method)
+		}
+	}
+
+	// Navigating sends a command to the browser
+	if err := this.Navigate("http://localhost/other"); err != nil {
+		t.Error(err)
+	} else if msg := <-browser.cmds; msg.Method != "Page.navigate" || this.URL() != "http://localhost/other" {
+		t.Error("Unexpected command", msg.Method)
+	} else if err := this.Navigate("other"); err == nil {
+		t.Error("Expected error for relative url")
+	}
+
+	// The frame is drawn within the bounds
+	if r, _, _, _ := dst.At(3, 3).RGBA(); r > 0x1000 {
+		t.Error("Expected frame to be drawn")
+	} else if r, _, _, _ := dst.At(1, 1).RGBA(); r != 0xFFFF {
+		t.Error("Unexpected frame outside bounds")
+	}
+
+	// Drawing ends when the context is done and the connection closed
+	cancel()
+	this.Mutex.Lock()
+	this.conn.Close()
+	this.Mutex.Unlock()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for drawing to end")
+	}
+}
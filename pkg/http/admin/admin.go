@@ -0,0 +1,266 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type admin struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Server
+	gopi.Publisher
+	gopi.EventCodec
+	gopi.UnitManager
+	gopi.PairingManager
+	gopi.AuditLog
+	gopi.GPIO        `unit:",optional"`
+	gopi.CastManager `unit:",optional"`
+	gopi.MediaPlayer `unit:",optional"`
+	gopi.RuleEngine  `unit:",optional"`
+	sync.RWMutex
+
+	// Flags
+	path   *string
+	lines  *uint
+	points *uint
+
+	started time.Time
+	output  io.Writer
+	logs    *ring
+	series  map[string]*ring
+	ch      <-chan gopi.Event
+	streams map[chan []byte]struct{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	streamCap = 32
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *admin) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagString("admin.path", "/admin", "Path to serve administration interface")
+	this.lines = cfg.FlagUint("admin.lines", 200, "Number of log lines to keep")
+	this.points = cfg.FlagUint("admin.points", 120, "Number of points to keep for each measurement")
+	return nil
+}
+
+func (this *admin) New(gopi.Config) error {
+	this.Require(this.Logger, this.Server, this.Publisher)
+
+	// Check parameters
+	if *this.path = "/" + strings.Trim(*this.path, "/"); *this.path == "/" {
+		return gopi.ErrBadParameter.WithPrefix("-admin.path")
+	} else if *this.lines == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-admin.lines")
+	} else if *this.points == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-admin.points")
+	}
+
+	// Keep log output in addition to writing it
+	this.started = time.Now()
+	this.logs = newRing(int(*this.lines))
+	this.output = log.Writer()
+	log.SetOutput(io.MultiWriter(this.output, this.logs))
+
+	// Subscribe to events before units run, so that measurements
+	// are not missed
+	this.series = make(map[string]*ring)
+	this.streams = make(map[chan []byte]struct{})
+	this.ch = this.Publisher.Subscribe()
+
+	// Serve the interface
+	handler := NewHandler(this, *this.path)
+	if err := this.Server.RegisterService(*this.path, handler); err != nil {
+		return err
+	} else if err := this.Server.RegisterService(*this.path+"/", handler); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *admin) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Restore log output
+	if this.output != nil {
+		log.SetOutput(this.output)
+	}
+
+	// Unsubscribe and close streams
+	if this.ch != nil {
+		this.Publisher.Unsubscribe(this.ch)
+	}
+	for stream := range this.streams {
+		close(stream)
+	}
+
+	// Release resources
+	this.output = nil
+	this.ch = nil
+	this.streams = nil
+	this.series = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *admin) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-this.ch:
+			if m, ok := evt.(gopi.Measurement); ok {
+				this.measure(m)
+			}
+			this.broadcast(evt)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *admin) Path() string {
+	return *this.path
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *admin) String() string {
+	str := "<admin"
+	str += fmt.Sprintf(" path=%q", this.Path())
+	if this.PairingManager != nil {
+		str += " authorize=true"
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// measure keeps the numeric metrics of a measurement
+func (this *admin) measure(m gopi.Measurement) {
+	p := point{Time: m.Time(), Tags: map[string]string{}, Values: map[string]float64{}}
+	if p.Time.IsZero() {
+		p.Time = time.Now()
+	}
+	for _, tag := range m.Tags() {
+		p.Tags[tag.Name()] = fmt.Sprint(tag.Value())
+	}
+	for _, metric := range m.Metrics() {
+		if value, ok := toFloat(metric.Value()); ok {
+			p.Values[metric.Name()] = value
+		}
+	}
+	if len(p.Values) == 0 {
+		return
+	}
+
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	if this.series == nil {
+		return
+	} else if _, exists := this.series[m.Name()]; exists == false {
+		this.series[m.Name()] = newRing(int(*this.points))
+	}
+	this.series[m.Name()].Add(p)
+}
+
+// broadcast sends an event to streams, dropping it for streams
+// which are not reading
+func (this *admin) broadcast(evt gopi.Event) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	if len(this.streams) == 0 {
+		return
+	}
+	data := this.encode(evt)
+	for stream := range this.streams {
+		select {
+		case stream <- data:
+		default:
+		}
+	}
+}
+
+// subscribe returns a channel of encoded events
+func (this *admin) subscribe() chan []byte {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	if this.streams == nil {
+		return nil
+	}
+	stream := make(chan []byte, streamCap)
+	this.streams[stream] = struct{}{}
+	return stream
+}
+
+func (this *admin) unsubscribe(stream chan []byte) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	if _, exists := this.streams[stream]; exists {
+		delete(this.streams, stream)
+		close(stream)
+	}
+}
+
+// toFloat returns a number, or one and zero for a boolean
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,176 @@
+package admin_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/audit"
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/http"
+	_ "github.com/djthorpe/gopi/v3/pkg/http/admin"
+	_ "github.com/djthorpe/gopi/v3/pkg/metrics"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.HttpAdmin
+	gopi.Server
+	gopi.Metrics
+	gopi.GPIO
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&testutil.GPIO{}), reflect.TypeOf((*gopi.GPIO)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Admin_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		handler := app.Server.(http.Handler)
+		app.GPIO.(*testutil.GPIO).SetPins(17, 27)
+		if app.HttpAdmin.Path() != "/admin" {
+			t.Error("Unexpected path", app.HttpAdmin.Path())
+		}
+
+		// Page is served with a redirect
+		if w := request(handler, http.MethodGet, "/admin", ""); w.Code != http.StatusMovedPermanently {
+			t.Error("Unexpected status", w.Code)
+		} else if w := request(handler, http.MethodGet, "/admin/", ""); w.Code != http.StatusOK || strings.Contains(w.Body.String(), "<html>") == false {
+			t.Error("Unexpected page", w.Code)
+		}
+
+		// Health and unit graph
+		var health map[string]interface{}
+		if w := request(handler, http.MethodGet, "/admin/api/health", ""); w.Code != http.StatusOK {
+			t.Error("Unexpected status", w.Code)
+		} else if err := json.Unmarshal(w.Body.Bytes(), &health); err != nil {
+			t.Error(err)
+		} else if health["goroutines"].(float64) == 0 {
+			t.Error("Unexpected health", health)
+		}
+		var units struct {
+			Graph map[string][]string `json:"graph"`
+		}
+		if w := request(handler, http.MethodGet, "/admin/api/units", ""); w.Code != http.StatusOK {
+			t.Error("Unexpected status", w.Code)
+		} else if err := json.Unmarshal(w.Body.Bytes(), &units); err != nil {
+			t.Error(err)
+		} else if _, exists := units.Graph["*admin_test.App"]; exists == false {
+			t.Error("Unexpected graph", units.Graph)
+		}
+
+		// Toggle a pin
+		if w := request(handler, http.MethodPost, "/admin/api/gpio", `{"pin":17,"state":true}`); w.Code != http.StatusNoContent {
+			t.Error("Unexpected status", w.Code, w.Body.String())
+		} else if app.GPIO.ReadPin(17) != gopi.GPIO_HIGH || app.GPIO.GetPinMode(17) != gopi.GPIO_OUTPUT {
+			t.Error("Expected pin to be written")
+		}
+		var pins []map[string]interface{}
+		if w := request(handler, http.MethodGet, "/admin/api/gpio", ""); w.Code != http.StatusOK {
+			t.Error("Unexpected status", w.Code)
+		} else if err := json.Unmarshal(w.Body.Bytes(), &pins); err != nil {
+			t.Error(err)
+		} else if len(pins) != 2 || pins[0]["state"] != true || pins[1]["state"] != false {
+			t.Error("Unexpected pins", pins)
+		}
+
+		// Cast is not available
+		if w := request(handler, http.MethodGet, "/admin/api/cast", ""); w.Code != http.StatusNotImplemented {
+			t.Error("Unexpected status", w.Code)
+		}
+	})
+}
+
+func Test_Admin_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		handler := app.Server.(http.Handler)
+
+		// Measurements and log output are kept
+		if _, err := app.Metrics.NewMeasurement("test", "value float64, on bool"); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			if err := app.Metrics.Emit("test", nil, float64(i), i%2 == 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+		log.Print("admin test")
+		time.Sleep(100 * time.Millisecond)
+
+		var series map[string][]struct {
+			Values map[string]float64 `json:"values"`
+		}
+		if w := request(handler, http.MethodGet, "/admin/api/metrics", ""); w.Code != http.StatusOK {
+			t.Error("Unexpected status", w.Code)
+		} else if err := json.Unmarshal(w.Body.Bytes(), &series); err != nil {
+			t.Error(err)
+		} else if points := series["test"]; len(points) != 3 || points[2].Values["value"] != 2 || points[2].Values["on"] != 1 {
+			t.Error("Unexpected series", series)
+		}
+		if w := request(handler, http.MethodGet, "/admin/api/logs", ""); w.Code != http.StatusOK {
+			t.Error("Unexpected status", w.Code)
+		} else if strings.Contains(w.Body.String(), "admin test") == false {
+			t.Error("Unexpected logs", w.Body.String())
+		}
+	})
+}
+
+func Test_Admin_003(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		handler := app.Server.(http.Handler)
+
+		// Writing a pin is recorded with the old and new values
+		if w := request(handler, http.MethodPost, "/admin/api/gpio", `{"pin":27,"state":true}`); w.Code != http.StatusNoContent {
+			t.Fatal("Unexpected status", w.Code, w.Body.String())
+		}
+		var entries []gopi.AuditEntry
+		if w := request(handler, http.MethodGet, "/admin/api/audit?source=http&limit=10", ""); w.Code != http.StatusOK {
+			t.Error("Unexpected status", w.Code)
+		} else if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+			t.Error(err)
+		} else if len(entries) != 1 {
+			t.Error("Unexpected entries", entries)
+		} else if entry := entries[0]; entry.Action != "POST /admin/api/gpio" || entry.Old != "GPIO27=GPIO_LOW" || entry.New != "GPIO27=GPIO_HIGH" {
+			t.Error("Unexpected entry", entry)
+		}
+		if w := request(handler, http.MethodGet, "/admin/api/audit?since=yesterday", ""); w.Code != http.StatusBadRequest {
+			t.Error("Unexpected status", w.Code)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func request(handler http.Handler, method, url, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+	handler.ServeHTTP(w, req)
+	return w
+}
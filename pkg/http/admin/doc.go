@@ -0,0 +1,19 @@
+// Admin package implements gopi.HttpAdmin, a single-page administration
+// interface served on -admin.path (default /admin/) by gopi.Server. The
+// page polls a JSON API under {path}/api and reads events as server-sent
+// events from {path}/api/events, and shows:
+//
+//	health: uptime, memory, goroutines and alerts from gopi.RuleEngine
+//	units: the unit graph, with toggles to enable optional and lazy units
+//	metrics: charts of recent measurements emitted by gopi.Metrics
+//	logs: recent log output
+//	gpio: pin modes and states, with toggles for output pins
+//	cast: cast devices with volume, mute and media controls
+//	media: the URL playing on gopi.MediaPlayer
+//
+// GPIO, cast and media are shown when those units are available. When
+// gopi.PairingManager is available, requests to the API require a bearer
+// token for a session with view permission, and gpio, cast, media or admin
+// permission to change state or read logs. The token is entered on the
+// page and kept in the browser.
+package admin
@@ -0,0 +1,543 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// handler serves the page and the API it uses:
+//
+//	GET {path}/api/health returns process and host health, and alerts
+//	GET {path}/api/units returns the unit graph and optional units
+//	POST {path}/api/units {"name":"...","enabled":true} enables or disables a unit
+//	GET {path}/api/metrics returns recent points for each measurement
+//	GET {path}/api/logs returns recent log lines
+//	GET {path}/api/gpio returns the mode and state of pins
+//	POST {path}/api/gpio {"pin":17,"state":true} writes an output pin
+//	GET {path}/api/cast returns cast devices
+//	POST {path}/api/cast {"id":"...","action":"volume","volume":0.5} controls a cast device
+//	GET {path}/api/media returns the URL playing
+//	POST {path}/api/media {"url":"..."} plays media, or stops with an empty URL
+//	GET {path}/api/events streams events as server-sent events
+//	GET {path}/api/audit?since=&until=&identity=&source=&action=&limit= returns audit log entries
+type handler struct {
+	*admin
+	path string
+}
+
+type healthResponse struct {
+	Name       string          `json:"name"`
+	Host       string          `json:"host"`
+	Started    time.Time       `json:"started"`
+	Uptime     float64         `json:"uptime"`
+	GoVersion  string          `json:"go"`
+	Goroutines int             `json:"goroutines"`
+	Alloc      uint64          `json:"alloc"`
+	Sys        uint64          `json:"sys"`
+	NumGC      uint32          `json:"gc"`
+	Alerts     []alertResponse `json:"alerts,omitempty"`
+}
+
+type alertResponse struct {
+	Name     string    `json:"name"`
+	Severity string    `json:"severity,omitempty"`
+	State    string    `json:"state"`
+	Since    time.Time `json:"since"`
+	Value    string    `json:"value,omitempty"`
+}
+
+type unitsResponse struct {
+	Graph map[string][]string `json:"graph"`
+	Units []unitResponse      `json:"units"`
+}
+
+type unitResponse struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+type pinResponse struct {
+	Pin      uint8  `json:"pin"`
+	Name     string `json:"name"`
+	Physical uint   `json:"physical,omitempty"`
+	Mode     string `json:"mode"`
+	State    bool   `json:"state"`
+}
+
+type castResponse struct {
+	Id      string  `json:"id"`
+	Name    string  `json:"name"`
+	Model   string  `json:"model"`
+	Service string  `json:"service"`
+	Volume  float32 `json:"volume"`
+	Muted   bool    `json:"muted"`
+}
+
+type eventResponse struct {
+	Name   string          `json:"name"`
+	Type   string          `json:"type"`
+	Text   string          `json:"text"`
+	Event  json.RawMessage `json:"event,omitempty"`
+	Time   time.Time       `json:"time"`
+	Series string          `json:"series,omitempty"`
+}
+
+type unitRequest struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+type pinRequest struct {
+	Pin   *uint8 `json:"pin"`
+	State bool   `json:"state"`
+}
+
+type castRequest struct {
+	Id     string  `json:"id"`
+	Action string  `json:"action"`
+	Volume float32 `json:"volume"`
+	URL    string  `json:"url"`
+	App    string  `json:"app"`
+}
+
+type mediaRequest struct {
+	URL string `json:"url"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	castTimeout = 10 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewHandler(admin *admin, path string) http.Handler {
+	return &handler{admin, strings.TrimSuffix(path, "/")}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// HANDLER
+
+func (this *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch strings.Trim(strings.TrimPrefix(req.URL.Path, this.path), "/") {
+	case "":
+		this.servePage(w, req)
+	case "api/health":
+		this.serveGet(w, req, gopi.SESSION_PERM_VIEW, this.health)
+	case "api/units":
+		this.serveUnits(w, req)
+	case "api/metrics":
+		this.serveGet(w, req, gopi.SESSION_PERM_VIEW, this.metrics)
+	case "api/logs":
+		this.serveGet(w, req, gopi.SESSION_PERM_ADMIN, func() interface{} { return this.logs.Values() })
+	case "api/gpio":
+		this.serveGPIO(w, req)
+	case "api/cast":
+		this.serveCast(w, req)
+	case "api/media":
+		this.serveMedia(w, req)
+	case "api/events":
+		this.serveEvents(w, req)
+	case "api/audit":
+		this.serveAudit(w, req)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *handler) servePage(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	} else if req.URL.Path == this.path {
+		// Redirect so that relative URLs in the page resolve
+		http.Redirect(w, req, this.path+"/", http.StatusMovedPermanently)
+	} else {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+	}
+}
+
+// serveGet serves the value returned by a function as JSON
+func (this *handler) serveGet(w http.ResponseWriter, req *http.Request, perms gopi.SessionPermission, fn func() interface{}) {
+	if req.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	} else if err := this.authorize(req, perms); err != nil {
+		serveError(w, err)
+	} else {
+		serveJSON(w, http.StatusOK, fn())
+	}
+}
+
+func (this *handler) serveUnits(w http.ResponseWriter, req *http.Request) {
+	if this.UnitManager == nil {
+		serveError(w, gopi.ErrNotImplemented.WithPrefix("UnitManager"))
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		if err := this.authorize(req, gopi.SESSION_PERM_VIEW); err != nil {
+			serveError(w, err)
+			return
+		}
+		response := unitsResponse{Graph: this.UnitManager.Graph(), Units: []unitResponse{}}
+		for _, name := range this.UnitManager.Units() {
+			response.Units = append(response.Units, unitResponse{name, this.UnitManager.Enabled(name)})
+		}
+		serveJSON(w, http.StatusOK, response)
+	case http.MethodPost:
+		var body unitRequest
+		if err := this.authorize(req, gopi.SESSION_PERM_ADMIN); err != nil {
+			serveError(w, err)
+		} else if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.Name == "" {
+			serveError(w, gopi.ErrBadParameter.WithPrefix("units"))
+		} else {
+			gopi.SetAuditChange(req.Context(), this.UnitManager.Enabled(body.Name), body.Enabled)
+			if body.Enabled {
+				serveResult(w, this.UnitManager.Enable(body.Name))
+			} else {
+				serveResult(w, this.UnitManager.Disable(body.Name))
+			}
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (this *handler) serveGPIO(w http.ResponseWriter, req *http.Request) {
+	if this.GPIO == nil {
+		serveError(w, gopi.ErrNotImplemented.WithPrefix("GPIO"))
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		if err := this.authorize(req, gopi.SESSION_PERM_VIEW); err != nil {
+			serveError(w, err)
+			return
+		}
+		response := []pinResponse{}
+		for _, pin := range this.GPIO.Pins() {
+			response = append(response, pinResponse{
+				Pin:      uint8(pin),
+				Name:     fmt.Sprint(pin),
+				Physical: this.GPIO.PhysicalPinForPin(pin),
+				Mode:     fmt.Sprint(this.GPIO.GetPinMode(pin)),
+				State:    this.GPIO.ReadPin(pin) == gopi.GPIO_HIGH,
+			})
+		}
+		serveJSON(w, http.StatusOK, response)
+	case http.MethodPost:
+		var body pinRequest
+		if err := this.authorize(req, gopi.SESSION_PERM_GPIO); err != nil {
+			serveError(w, err)
+		} else if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.Pin == nil {
+			serveError(w, gopi.ErrBadParameter.WithPrefix("gpio"))
+		} else {
+			state := gopi.GPIO_LOW
+			if body.State {
+				state = gopi.GPIO_HIGH
+			}
+			pin := gopi.GPIOPin(*body.Pin)
+			gopi.SetAuditChange(req.Context(), fmt.Sprint(pin, "=", this.GPIO.ReadPin(pin)), fmt.Sprint(pin, "=", state))
+			serveResult(w, this.GPIO.Batch(func(tx gopi.GPIOTx) error {
+				tx.SetPinMode(pin, gopi.GPIO_OUTPUT)
+				tx.WritePin(pin, state)
+				return nil
+			}))
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (this *handler) serveAudit(w http.ResponseWriter, req *http.Request) {
+	var q gopi.AuditQuery
+	if this.AuditLog == nil {
+		serveError(w, gopi.ErrNotImplemented.WithPrefix("AuditLog"))
+		return
+	} else if req.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	} else if err := this.authorize(req, gopi.SESSION_PERM_ADMIN); err != nil {
+		serveError(w, err)
+		return
+	}
+
+	// Parse query
+	values := req.URL.Query()
+	q.Identity, q.Source, q.Action = values.Get("identity"), values.Get("source"), values.Get("action")
+	for key, t := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if value := values.Get(key); value == "" {
+			continue
+		} else if t_, err := time.Parse(time.RFC3339, value); err != nil {
+			serveError(w, gopi.ErrBadParameter.WithPrefix(key))
+			return
+		} else {
+			*t = t_
+		}
+	}
+	if value := values.Get("limit"); value != "" {
+		if limit, err := strconv.ParseUint(value, 10, 32); err != nil {
+			serveError(w, gopi.ErrBadParameter.WithPrefix("limit"))
+			return
+		} else {
+			q.Limit = uint(limit)
+		}
+	}
+
+	// Return entries
+	if entries, err := this.AuditLog.Query(q); err != nil {
+		serveError(w, err)
+	} else {
+		serveJSON(w, http.StatusOK, entries)
+	}
+}
+
+func (this *handler) serveCast(w http.ResponseWriter, req *http.Request) {
+	if this.CastManager == nil {
+		serveError(w, gopi.ErrNotImplemented.WithPrefix("CastManager"))
+		return
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), castTimeout)
+	defer cancel()
+	switch req.Method {
+	case http.MethodGet:
+		if err := this.authorize(req, gopi.SESSION_PERM_VIEW); err != nil {
+			serveError(w, err)
+		} else if casts, err := this.CastManager.Devices(ctx); err != nil {
+			serveError(w, err)
+		} else {
+			response := []castResponse{}
+			for _, cast := range casts {
+				volume, muted := cast.Volume()
+				response = append(response, castResponse{cast.Id(), cast.Name(), cast.Model(), cast.Service(), volume, muted})
+			}
+			sort.Slice(response, func(i, j int) bool {
+				return response[i].Name < response[j].Name
+			})
+			serveJSON(w, http.StatusOK, response)
+		}
+	case http.MethodPost:
+		var body castRequest
+		if err := this.authorize(req, gopi.SESSION_PERM_CAST); err != nil {
+			serveError(w, err)
+		} else if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			serveError(w, gopi.ErrBadParameter.WithPrefix("cast"))
+		} else if cast := this.CastManager.Get(body.Id); cast == nil {
+			serveError(w, gopi.ErrNotFound.WithPrefix("cast: ", body.Id))
+		} else {
+			serveResult(w, this.castAction(ctx, cast, body))
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (this *handler) serveMedia(w http.ResponseWriter, req *http.Request) {
+	if this.MediaPlayer == nil {
+		serveError(w, gopi.ErrNotImplemented.WithPrefix("MediaPlayer"))
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		if err := this.authorize(req, gopi.SESSION_PERM_VIEW); err != nil {
+			serveError(w, err)
+		} else {
+			serveJSON(w, http.StatusOK, mediaRequest{this.MediaPlayer.URL()})
+		}
+	case http.MethodPost:
+		var body mediaRequest
+		if err := this.authorize(req, gopi.SESSION_PERM_MEDIA); err != nil {
+			serveError(w, err)
+		} else if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			serveError(w, gopi.ErrBadParameter.WithPrefix("media"))
+		} else if body.URL == "" {
+			serveResult(w, this.MediaPlayer.Stop())
+		} else {
+			serveResult(w, this.MediaPlayer.Play(body.URL))
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// serveEvents streams events until the client disconnects or the
+// unit is disposed
+func (this *handler) serveEvents(w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if req.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	} else if ok == false {
+		serveError(w, gopi.ErrNotImplemented.WithPrefix("events"))
+		return
+	} else if err := this.authorize(req, gopi.SESSION_PERM_VIEW); err != nil {
+		serveError(w, err)
+		return
+	}
+
+	stream := this.subscribe()
+	if stream == nil {
+		serveError(w, gopi.ErrOutOfOrder.WithPrefix("events"))
+		return
+	}
+	defer this.unsubscribe(stream)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case data, ok := <-stream:
+			if ok == false {
+				return
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// castAction connects, disconnects, sets volume, launches an app or
+// loads media on a cast device
+func (this *handler) castAction(ctx context.Context, cast gopi.Cast, body castRequest) error {
+	switch body.Action {
+	case "connect":
+		return this.CastManager.Connect(ctx, cast)
+	case "disconnect":
+		return this.CastManager.Disconnect(cast)
+	case "volume":
+		return this.CastManager.SetVolume(ctx, cast, body.Volume)
+	case "mute":
+		return this.CastManager.SetMuted(ctx, cast, true)
+	case "unmute":
+		return this.CastManager.SetMuted(ctx, cast, false)
+	case "launch":
+		return this.CastManager.LaunchAppWithId(ctx, cast, body.App)
+	case "load":
+		if u, err := url.Parse(body.URL); err != nil || u.Scheme == "" {
+			return gopi.ErrBadParameter.WithPrefix("url")
+		} else if err := this.CastManager.ConnectMedia(ctx, cast); err != nil {
+			return err
+		} else {
+			return this.CastManager.LoadMedia(ctx, cast, u, true)
+		}
+	default:
+		return gopi.ErrBadParameter.WithPrefix("action: ", body.Action)
+	}
+}
+
+// authorize checks permissions for a bearer token, or a token query
+// parameter for event streams, when pairing is available
+func (this *handler) authorize(req *http.Request, perms gopi.SessionPermission) error {
+	if this.PairingManager == nil {
+		return nil
+	}
+	token := req.URL.Query().Get("token")
+	if value := req.Header.Get("Authorization"); strings.HasPrefix(value, "Bearer ") {
+		token = strings.TrimSpace(strings.TrimPrefix(value, "Bearer "))
+	}
+	_, err := this.PairingManager.Authorize(token, perms)
+	return err
+}
+
+func (this *admin) health() interface{} {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	host, _ := os.Hostname()
+	response := healthResponse{
+		Name:       filepath.Base(os.Args[0]),
+		Host:       host,
+		Started:    this.started,
+		Uptime:     time.Since(this.started).Seconds(),
+		GoVersion:  runtime.Version(),
+		Goroutines: runtime.NumGoroutine(),
+		Alloc:      stats.Alloc,
+		Sys:        stats.Sys,
+		NumGC:      stats.NumGC,
+	}
+	if this.RuleEngine != nil {
+		for _, alert := range this.RuleEngine.Alerts() {
+			response.Alerts = append(response.Alerts, alertResponse{
+				Name:     alert.Name,
+				Severity: alert.Severity,
+				State:    strings.ToLower(strings.TrimPrefix(alert.State.String(), "ALERT_")),
+				Since:    alert.Since,
+				Value:    alert.Value,
+			})
+		}
+	}
+	return response
+}
+
+func (this *admin) metrics() interface{} {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	response := make(map[string][]interface{}, len(this.series))
+	for name, series := range this.series {
+		response[name] = series.Values()
+	}
+	return response
+}
+
+// encode returns an event as JSON, including the event marshalled by
+// the codec when available
+func (this *admin) encode(evt gopi.Event) []byte {
+	response := eventResponse{
+		Name: evt.Name(),
+		Type: fmt.Sprintf("%T", evt),
+		Text: fmt.Sprint(evt),
+		Time: time.Now(),
+	}
+	if this.EventCodec != nil {
+		if name, data, err := this.EventCodec.Marshal(evt, gopi.EVENT_FORMAT_JSON); err == nil {
+			response.Type, response.Event = name, data
+		}
+	}
+	if _, ok := evt.(gopi.Measurement); ok {
+		response.Series = evt.Name()
+	}
+	data, _ := json.Marshal(response)
+	return data
+}
+
+func serveJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func serveResult(w http.ResponseWriter, err error) {
+	if err != nil {
+		serveError(w, err)
+	} else {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func serveError(w http.ResponseWriter, err error) {
+	http.Error(w, err.Error(), gopi.ErrorCode(err).HttpStatus())
+}
@@ -0,0 +1,13 @@
+package admin
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.HttpAdmin
+	graph.RegisterUnit(reflect.TypeOf(&admin{}), reflect.TypeOf((*gopi.HttpAdmin)(nil)))
+}
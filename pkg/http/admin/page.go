@@ -0,0 +1,158 @@
+package admin
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+// page is the single-page interface, which polls the API and reads
+// the event stream. When pairing is available the token is kept in
+// local storage and sent with each request
+const page = `<!DOCTYPE html>
+<html><head><title>Admin</title>
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<style>
+body { font-family: sans-serif; margin: 0; background: #f4f4f4; color: #222; }
+header { background: #263238; color: #fff; padding: 0.5em 1em; display: flex; justify-content: space-between; align-items: center; }
+main { display: grid; grid-template-columns: repeat(auto-fill, minmax(22em, 1fr)); gap: 1em; padding: 1em; }
+section { background: #fff; border-radius: 4px; padding: 0.5em 1em; box-shadow: 0 1px 2px rgba(0,0,0,0.2); overflow: auto; max-height: 30em; }
+h2 { font-size: 1.1em; }
+table { width: 100%; border-collapse: collapse; font-size: 0.9em; }
+td { padding: 0.2em; border-bottom: 1px solid #eee; vertical-align: top; }
+pre { font-size: 0.8em; white-space: pre-wrap; margin: 0; }
+canvas { width: 100%; height: 80px; }
+.on { background: #4caf50; color: #fff; }
+.error { color: #c62828; }
+</style></head>
+<body>
+<header><strong id="title">Admin</strong>
+<span><input id="token" type="password" placeholder="Token" size="12"> <button onclick="setToken()">Set</button></span></header>
+<p id="error" class="error"></p>
+<main>
+<section><h2>Health</h2><table id="health"></table></section>
+<section><h2>Units</h2><table id="units"></table><h2>Graph</h2><pre id="graph"></pre></section>
+<section><h2>Metrics</h2><div id="metrics"></div></section>
+<section><h2>GPIO</h2><table id="gpio"></table></section>
+<section><h2>Cast</h2><table id="cast"></table>
+<p><input id="castURL" placeholder="Media URL" size="24"></p></section>
+<section><h2>Media</h2><p id="media"></p>
+<p><input id="mediaURL" placeholder="Media URL" size="24"> <button onclick="media(el('mediaURL').value)">Play</button> <button onclick="media('')">Stop</button></p></section>
+<section><h2>Logs</h2><pre id="logs"></pre></section>
+<section><h2>Events</h2><pre id="events"></pre></section>
+</main>
+<script>
+var token = localStorage.getItem("token") || "";
+function el(id) { return document.getElementById(id); }
+function esc(s) { return String(s).replace(/[&<>"']/g, function(c) { return "&#" + c.charCodeAt(0) + ";"; }); }
+function setToken() { token = el("token").value; localStorage.setItem("token", token); refresh(); events(); }
+function api(path, body) {
+	var opts = { method: body ? "POST" : "GET", headers: {} };
+	if (token) { opts.headers["Authorization"] = "Bearer " + token; }
+	if (body) { opts.headers["Content-Type"] = "application/json"; opts.body = JSON.stringify(body); }
+	return fetch("api/" + path, opts).then(function(r) {
+		if (!r.ok) { return r.text().then(function(t) { throw new Error(path + ": " + t); }); }
+		el("error").textContent = "";
+		return r.status == 204 ? null : r.json();
+	});
+}
+function fail(err) { el("error").textContent = err.message; }
+function rows(id, items, fn) { el(id).innerHTML = items.map(fn).join(""); }
+function health() {
+	return api("health").then(function(h) {
+		el("title").textContent = h.name + " on " + h.host;
+		rows("health", [["Uptime", Math.round(h.uptime) + "s"], ["Go", h.go], ["Goroutines", h.goroutines],
+			["Allocated", Math.round(h.alloc / 1024) + "KB"], ["System", Math.round(h.sys / 1024) + "KB"], ["GC", h.gc]].concat(
+			(h.alerts || []).map(function(a) { return ["Alert " + a.name, a.state + (a.value ? " " + a.value : "")]; })),
+			function(r) { return "<tr><td>" + r[0] + "</td><td>" + esc(r[1]) + "</td></tr>"; });
+	}).catch(fail);
+}
+function units() {
+	return api("units").then(function(u) {
+		rows("units", u.units, function(unit) {
+			return "<tr><td>" + esc(unit.name) + "</td><td><button class='" + (unit.enabled ? "on" : "") + "' onclick='enable(" +
+				JSON.stringify(unit.name) + "," + !unit.enabled + ")'>" + (unit.enabled ? "Enabled" : "Disabled") + "</button></td></tr>";
+		});
+		el("graph").textContent = Object.keys(u.graph).sort().map(function(k) {
+			return k + (u.graph[k].length ? "\n  " + u.graph[k].join("\n  ") : "");
+		}).join("\n");
+	}).catch(fail);
+}
+function enable(name, enabled) { api("units", { name: name, enabled: enabled }).then(units).catch(fail); }
+function chart(canvas, points, key) {
+	var ctx = canvas.getContext("2d"), w = canvas.width = canvas.clientWidth, h = canvas.height = canvas.clientHeight;
+	var values = points.map(function(p) { return p.values[key]; });
+	var min = Math.min.apply(null, values), max = Math.max.apply(null, values), range = (max - min) || 1;
+	ctx.clearRect(0, 0, w, h);
+	ctx.beginPath();
+	values.forEach(function(v, i) { ctx.lineTo(i * w / Math.max(values.length - 1, 1), h - 4 - (v - min) * (h - 8) / range); });
+	ctx.strokeStyle = "#1976d2";
+	ctx.stroke();
+	ctx.fillText(key + " " + values[values.length - 1], 2, 10);
+}
+function metrics() {
+	return api("metrics").then(function(m) {
+		var div = el("metrics");
+		Object.keys(m).sort().forEach(function(name) {
+			var points = m[name], keys = Object.keys(points[points.length - 1].values).sort();
+			keys.forEach(function(key) {
+				var id = "chart-" + name + "-" + key, canvas = document.getElementById(id);
+				if (!canvas) {
+					canvas = document.createElement("canvas");
+					canvas.id = id;
+					canvas.title = name;
+					div.appendChild(canvas);
+				}
+				chart(canvas, points.filter(function(p) { return key in p.values; }), name + "." + key);
+			});
+		});
+	}).catch(fail);
+}
+function gpio() {
+	return api("gpio").then(function(pins) {
+		rows("gpio", pins, function(p) {
+			return "<tr><td>" + esc(p.name) + (p.physical ? " (" + p.physical + ")" : "") + "</td><td>" + esc(p.mode) +
+				"</td><td><button class='" + (p.state ? "on" : "") + "' onclick='write_(" + p.pin + "," + !p.state + ")'>" +
+				(p.state ? "High" : "Low") + "</button></td></tr>";
+		});
+	}).catch(function() {});
+}
+function write_(pin, state) { api("gpio", { pin: pin, state: state }).then(gpio).catch(fail); }
+function cast() {
+	return api("cast").then(function(casts) {
+		rows("cast", casts, function(c) {
+			var id = JSON.stringify(c.id);
+			return "<tr><td>" + esc(c.name) + "<br><small>" + esc(c.model) + " " + esc(c.service) + "</small></td><td>" +
+				"<input type='range' min='0' max='1' step='0.05' value='" + c.volume + "' onchange='castAction(" + id + ",\"volume\",this.value)'> " +
+				"<button onclick='castAction(" + id + ",\"" + (c.muted ? "unmute" : "mute") + "\")'>" + (c.muted ? "Unmute" : "Mute") + "</button> " +
+				"<button onclick='castAction(" + id + ",\"load\")'>Load</button></td></tr>";
+		});
+	}).catch(function() {});
+}
+function castAction(id, action, volume) {
+	api("cast", { id: id, action: action, volume: parseFloat(volume || 0), url: el("castURL").value }).then(cast).catch(fail);
+}
+function media(url) {
+	if (url !== undefined) { return api("media", { url: url }).then(function() { media(); }).catch(fail); }
+	return api("media").then(function(m) { el("media").textContent = m.url || "Stopped"; }).catch(function() {});
+}
+function logs() {
+	return api("logs").then(function(lines) {
+		el("logs").textContent = lines.map(function(l) { return l.text; }).join("\n");
+	}).catch(function() {});
+}
+var source = null;
+function events() {
+	if (source) { source.close(); }
+	source = new EventSource("api/events" + (token ? "?token=" + encodeURIComponent(token) : ""));
+	source.onmessage = function(e) {
+		var evt = JSON.parse(e.data);
+		if (evt.series) { return; }
+		var pre = el("events");
+		pre.textContent = new Date(evt.time).toLocaleTimeString() + " " + evt.name + " " + evt.text + "\n" + pre.textContent.substring(0, 8192);
+	};
+}
+function refresh() { health(); units(); metrics(); gpio(); cast(); media(); logs(); }
+el("token").value = token;
+refresh();
+events();
+setInterval(refresh, 5000);
+</script></body></html>
+`
@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ring keeps the most recent values added, and can be written to
+// as a log output so that each line is a value
+type ring struct {
+	sync.Mutex
+	values []interface{}
+	next   int
+	full   bool
+}
+
+// line is a line of log output
+type line struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+// point is the numeric metrics of a measurement
+type point struct {
+	Time   time.Time          `json:"time"`
+	Tags   map[string]string  `json:"tags,omitempty"`
+	Values map[string]float64 `json:"values"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newRing(size int) *ring {
+	return &ring{values: make([]interface{}, size)}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Add a value, replacing the oldest value when full
+func (this *ring) Add(v interface{}) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	this.values[this.next] = v
+	if this.next = this.next + 1; this.next == len(this.values) {
+		this.next, this.full = 0, true
+	}
+}
+
+// Values returns values, oldest first
+func (this *ring) Values() []interface{} {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.full == false {
+		return append([]interface{}{}, this.values[:this.next]...)
+	} else {
+		return append(append([]interface{}{}, this.values[this.next:]...), this.values[:this.next]...)
+	}
+}
+
+// Write adds each line of log output
+func (this *ring) Write(data []byte) (int, error) {
+	now := time.Now()
+	for _, text := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		this.Add(line{now, text})
+	}
+	return len(data), nil
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// auditWriter records the status code written by a handler
+type auditWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+/////////////////////////////////////////////////////////////////////
+// METHODS
+
+func (this *auditWriter) WriteHeader(status int) {
+	this.status = status
+	this.ResponseWriter.WriteHeader(status)
+}
+
+func (this *auditWriter) Flush() {
+	if flusher, ok := this.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// audit records a request as "METHOD /path", with the identity of the
+// client or the remote address when the client is anonymous
+func (this *Server) audit(req *http.Request, status int) {
+	entry := gopi.AuditEntry{
+		Source: "http",
+		Action: req.Method + " " + req.URL.Path,
+	}
+	if _, ok := gopi.AccessIdentityFromContext(req.Context()); ok == false {
+		entry.Identity = req.RemoteAddr
+	}
+	if status >= http.StatusBadRequest {
+		entry.Error = http.StatusText(status)
+	}
+	if err := this.AuditLog.Record(req.Context(), entry); err != nil {
+		this.Print("Audit: ", err)
+	}
+}
+
+// auditMethod returns true for request methods which change state
+func auditMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
@@ -229,7 +229,7 @@ func (this *TemplateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request)
 // Serve error
 func (this *TemplateHandler) ServeError(w http.ResponseWriter, err error) {
 	if err_, ok := err.(gopi.HttpError); ok == false {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), gopi.ErrorCode(err).HttpStatus())
 	} else if err_.Code() == http.StatusPermanentRedirect || err_.Code() == http.StatusTemporaryRedirect {
 		this.Debugf("  Code: %v", err_.Error())
 		this.Debugf("  Location: %v", err_.Path())
@@ -0,0 +1,14 @@
+// Profiler package implements gopi.HttpProfiler, which diagnoses memory
+// and goroutine leaks on devices in the field.
+//
+// When the -debug flag is set, the runtime profiles from net/http/pprof
+// and execution traces are served on -profiler.path (default
+// /debug/pprof) by gopi.Server, and on a unix socket when -profiler.socket
+// is set, so they can be read with "go tool pprof" and "go tool trace".
+// Profiles are not served without the -debug flag.
+//
+// When -profiler.dir is set, memory in use is checked every
+// -profiler.interval and heap and goroutine profiles are written to the
+// directory when it has grown by -profiler.growth percent since the last
+// snapshot. The most recent -profiler.keep snapshots are kept.
+package profiler
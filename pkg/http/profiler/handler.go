@@ -0,0 +1,58 @@
+package profiler
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"strings"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type handler struct {
+	path string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// pprof.Index only lists profiles under this path
+	indexPath = "/debug/pprof/"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewHandler(path string) http.Handler {
+	return &handler{path}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SERVE
+
+func (this *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// Redirect to the index with a trailing slash, so links are relative
+	if req.URL.Path == this.path {
+		http.Redirect(w, req, this.path+"/", http.StatusMovedPermanently)
+		return
+	}
+
+	// Serve the index or a profile
+	switch name := strings.TrimPrefix(req.URL.Path, this.path+"/"); name {
+	case "":
+		req_ := req.Clone(req.Context())
+		req_.URL.Path = indexPath
+		pprof.Index(w, req_)
+	case "cmdline":
+		pprof.Cmdline(w, req)
+	case "profile":
+		pprof.Profile(w, req)
+	case "symbol":
+		pprof.Symbol(w, req)
+	case "trace":
+		pprof.Trace(w, req)
+	default:
+		pprof.Handler(name).ServeHTTP(w, req)
+	}
+}
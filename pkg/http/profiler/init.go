@@ -0,0 +1,13 @@
+package profiler
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.HttpProfiler
+	graph.RegisterUnit(reflect.TypeOf(&profiler{}), reflect.TypeOf((*gopi.HttpProfiler)(nil)))
+}
@@ -0,0 +1,281 @@
+package profiler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type profiler struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Server
+	sync.Mutex
+
+	// Flags
+	path     *string
+	socket   *string
+	dir      *string
+	interval *time.Duration
+	growth   *uint
+	keep     *uint
+
+	served   string
+	listener net.Listener
+	baseline uint64
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	timeFormat = "20060102T150405"
+)
+
+var (
+	// profiles are written in each snapshot, with the debug level
+	profiles = []struct {
+		name  string
+		debug int
+		ext   string
+	}{
+		{"heap", 0, "pprof"},
+		{"goroutine", 1, "txt"},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *profiler) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagString("profiler.path", "/debug/pprof", "Path to serve profiles when debugging")
+	this.socket = cfg.FlagString("profiler.socket", "", "Unix socket to serve profiles when debugging")
+	this.dir = cfg.FlagString("profiler.dir", "", "Directory for snapshots when memory grows")
+	this.interval = cfg.FlagDuration("profiler.interval", time.Minute, "Interval between memory checks")
+	this.growth = cfg.FlagUint("profiler.growth", 50, "Memory growth in percent which writes a snapshot")
+	this.keep = cfg.FlagUint("profiler.keep", 10, "Number of snapshots to keep")
+	return nil
+}
+
+func (this *profiler) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if *this.path = "/" + strings.Trim(*this.path, "/"); *this.path == "/" {
+		return gopi.ErrBadParameter.WithPrefix("-profiler.path")
+	} else if *this.dir != "" && (*this.interval <= 0 || *this.growth == 0 || *this.keep == 0) {
+		return gopi.ErrBadParameter.WithPrefix("-profiler.interval, -profiler.growth or -profiler.keep")
+	}
+
+	// Profiles are only served when debugging
+	if this.Logger.IsDebug() == false {
+		return nil
+	}
+
+	// Serve profiles with the HTTP server
+	handler := NewHandler(*this.path)
+	if this.Server != nil {
+		if err := this.Server.RegisterService(*this.path, handler); err != nil {
+			return err
+		} else if err := this.Server.RegisterService(*this.path+"/", handler); err != nil {
+			return err
+		} else {
+			this.served = *this.path
+		}
+	}
+
+	// Serve profiles on a unix socket
+	if *this.socket != "" {
+		if err := os.Remove(*this.socket); err != nil && os.IsNotExist(err) == false {
+			return err
+		} else if listener, err := net.Listen("unix", *this.socket); err != nil {
+			return err
+		} else {
+			this.listener = listener
+		}
+		go http.Serve(this.listener, handler)
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *profiler) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Close the socket, which removes it
+	var result error
+	if this.listener != nil {
+		if err := this.listener.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Release resources
+	this.listener = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *profiler) Run(ctx context.Context) error {
+	if *this.dir == "" {
+		<-ctx.Done()
+		return nil
+	}
+
+	// Check memory in use every interval
+	ticker := time.NewTicker(*this.interval)
+	defer ticker.Stop()
+	this.baseline = inuse()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := this.check(); err != nil {
+				this.Print("Profiler: ", err)
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *profiler) Path() string {
+	return this.served
+}
+
+func (this *profiler) Snapshot() ([]string, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Check for directory
+	if *this.dir == "" {
+		return nil, gopi.ErrOutOfOrder.WithPrefix("Snapshot: -profiler.dir")
+	} else if err := os.MkdirAll(*this.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	// Write profiles
+	var result []string
+	when := time.Now().Format(timeFormat)
+	for _, profile := range profiles {
+		path := filepath.Join(*this.dir, fmt.Sprintf("%v-%v.%v", profile.name, when, profile.ext))
+		if err := write(path, profile.name, profile.debug); err != nil {
+			return result, err
+		} else {
+			result = append(result, path)
+		}
+	}
+
+	// Remove old snapshots
+	if err := this.prune(); err != nil {
+		return result, err
+	}
+
+	// Return success
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *profiler) String() string {
+	str := "<profiler"
+	if this.served != "" {
+		str += fmt.Sprintf(" path=%q", this.served)
+	}
+	if this.listener != nil {
+		str += fmt.Sprintf(" socket=%q", *this.socket)
+	}
+	if *this.dir != "" {
+		str += fmt.Sprintf(" dir=%q", *this.dir)
+		str += fmt.Sprint(" interval=", *this.interval)
+		str += fmt.Sprint(" growth=", *this.growth, "%")
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// check writes a snapshot when memory in use has grown since the last
+// snapshot
+func (this *profiler) check() error {
+	current := inuse()
+	if current <= this.baseline+this.baseline*uint64(*this.growth)/100 {
+		return nil
+	}
+	this.Debug("Profiler: Memory grew from ", this.baseline, " to ", current, " bytes")
+	this.baseline = current
+	if paths, err := this.Snapshot(); err != nil {
+		return err
+	} else {
+		this.Print("Profiler: Wrote ", strings.Join(paths, ", "))
+	}
+	return nil
+}
+
+// prune removes all but the most recent snapshots of each profile
+func (this *profiler) prune() error {
+	var result error
+	for _, profile := range profiles {
+		paths, err := filepath.Glob(filepath.Join(*this.dir, profile.name+"-*."+profile.ext))
+		if err != nil {
+			return err
+		}
+		sort.Strings(paths)
+		for len(paths) > int(*this.keep) {
+			if err := os.Remove(paths[0]); err != nil {
+				result = multierror.Append(result, err)
+			}
+			paths = paths[1:]
+		}
+	}
+	return result
+}
+
+// inuse returns the bytes of heap memory in use
+func inuse() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
+
+// write writes a named profile to a file
+func write(path, name string, debug int) error {
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		return gopi.ErrNotFound.WithPrefix(name)
+	}
+	fh, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	if name == "heap" {
+		runtime.GC()
+	}
+	return profile.WriteTo(fh, debug)
+}
@@ -0,0 +1,89 @@
+package profiler_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/http"
+	_ "github.com/djthorpe/gopi/v3/pkg/http/profiler"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.HttpProfiler
+	gopi.Server
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Profiler_001(t *testing.T) {
+	// Tests run with the debug flag set, so profiles are served
+	tool.Test(t, nil, new(App), func(app *App) {
+		handler := app.Server.(http.Handler)
+		if app.HttpProfiler.Path() != "/debug/pprof" {
+			t.Error("Unexpected path", app.HttpProfiler.Path())
+		} else if _, err := app.HttpProfiler.Snapshot(); err == nil {
+			t.Error("Expected error without -profiler.dir")
+		}
+		if w := request(handler, "/debug/pprof"); w.Code != http.StatusMovedPermanently {
+			t.Error("Unexpected status", w.Code)
+		} else if w := request(handler, "/debug/pprof/"); w.Code != http.StatusOK || strings.Contains(w.Body.String(), "goroutine") == false {
+			t.Error("Unexpected index", w.Code)
+		} else if w := request(handler, "/debug/pprof/goroutine?debug=1"); w.Code != http.StatusOK || strings.Contains(w.Body.String(), "goroutine profile") == false {
+			t.Error("Unexpected profile", w.Code)
+		} else if w := request(handler, "/debug/pprof/missing"); w.Code != http.StatusNotFound {
+			t.Error("Unexpected status", w.Code)
+		}
+	})
+}
+
+func Test_Profiler_002(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "heap-20000101T000000.pprof")
+	if err := ioutil.WriteFile(old, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	tool.Test(t, []string{"-profiler.dir=" + dir, "-profiler.keep=1"}, new(App), func(app *App) {
+		// Snapshots are written and old snapshots removed
+		if paths, err := app.HttpProfiler.Snapshot(); err != nil {
+			t.Error(err)
+		} else if len(paths) != 2 {
+			t.Error("Unexpected paths", paths)
+		} else if _, err := os.Stat(old); os.IsNotExist(err) == false {
+			t.Error("Expected old snapshot to be removed")
+		} else if data, err := ioutil.ReadFile(paths[1]); err != nil {
+			t.Error(err)
+		} else if strings.Contains(string(data), "goroutine profile") == false {
+			t.Error("Unexpected goroutine snapshot")
+		} else {
+			t.Log(paths)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func request(handler http.Handler, url string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	return w
+}
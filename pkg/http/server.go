@@ -8,11 +8,13 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	gopi "github.com/djthorpe/gopi/v3"
 	fcgi "github.com/djthorpe/gopi/v3/pkg/http/fcgi"
+	limiter "github.com/djthorpe/gopi/v3/pkg/limiter"
 	multierror "github.com/hashicorp/go-multierror"
 )
 
@@ -22,6 +24,8 @@ import (
 type Server struct {
 	gopi.Unit
 	gopi.Logger
+	gopi.AccessControl
+	gopi.AuditLog
 	sync.RWMutex
 	sync.WaitGroup
 
@@ -32,6 +36,8 @@ type Server struct {
 	fcgiserver *fcgi.Server
 	mux        *http.ServeMux
 	timeout    *time.Duration
+	maxbody    *uint
+	limiter    *limiter.Limiter
 	handler    http.Handler
 }
 
@@ -48,6 +54,10 @@ func (this *Server) Define(cfg gopi.Config) error {
 	this.key = cfg.FlagString("ssl.key", "", "SSL Key")
 	this.timeout = cfg.FlagDuration("http.timeout", 15*time.Second, "HTTP server read and write timeout")
 	this.fcgi = cfg.FlagBool("http.fcgi", false, "Serve over FastCGI unix socket")
+	this.maxbody = cfg.FlagUint("http.maxbody", 1<<20, "Maximum request body size in bytes, or zero for unlimited")
+	cfg.FlagFloat("http.rate", 0, "Maximum requests per second for each client, or zero for unlimited")
+	cfg.FlagUint("http.burst", 20, "Maximum burst of requests for each client")
+	cfg.FlagUint("http.streams", 0, "Maximum concurrent requests for each client, or zero for unlimited")
 	return nil
 }
 
@@ -71,6 +81,9 @@ func (this *Server) New(cfg gopi.Config) error {
 	// Set multiplexer and handler chain
 	this.mux = http.NewServeMux()
 
+	// Set limits for each client
+	this.limiter = limiter.New(cfg.GetFloat("http.rate"), cfg.GetUint("http.burst"), cfg.GetUint("http.streams"))
+
 	// Return success
 	return nil
 }
@@ -323,6 +336,38 @@ func (this *Server) RegisterService(path interface{}, service gopi.Service) erro
 }
 
 func (this *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// Limit requests for each client and the request body size
+	if this.limiter.Allow(req.RemoteAddr) == false {
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return
+	} else if this.limiter.Acquire(req.RemoteAddr) == false {
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return
+	} else {
+		defer this.limiter.Release(req.RemoteAddr)
+	}
+	if *this.maxbody > 0 && req.Body != nil {
+		req.Body = http.MaxBytesReader(w, req.Body, int64(*this.maxbody))
+	}
+
+	// Check the role required for the route
+	if this.AccessControl != nil {
+		if ctx, err := this.authorize(req); err != nil {
+			http.Error(w, err.Error(), gopi.ErrorCode(err).HttpStatus())
+			return
+		} else {
+			req = req.WithContext(ctx)
+		}
+	}
+
+	// Record requests which change state
+	if this.AuditLog != nil && auditMethod(req.Method) {
+		req = req.WithContext(gopi.WithAuditChange(req.Context()))
+		w_ := &auditWriter{ResponseWriter: w, status: http.StatusOK}
+		defer func() { this.audit(req, w_.status) }()
+		w = w_
+	}
+
 	// If any handlers are installed call them, or else call the default multiplexer
 	if this.handler == nil {
 		this.mux.ServeHTTP(w, req)
@@ -344,3 +389,34 @@ func (this *Server) String() string {
 	}
 	return str + ">"
 }
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// authorize returns the request context with the identity of the client,
+// from a verified client certificate or a bearer token, when it has the
+// role required for "METHOD /path". A token can also be passed as the
+// "token" query parameter by clients which cannot set headers
+func (this *Server) authorize(req *http.Request) (context.Context, error) {
+	ctx := req.Context()
+	if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 {
+		ctx = gopi.WithAccessIdentity(ctx, gopi.AccessIdentity{Name: req.TLS.VerifiedChains[0][0].Subject.CommonName})
+	}
+	token := req.URL.Query().Get("token")
+	if value := req.Header.Get("Authorization"); strings.HasPrefix(value, "Bearer ") {
+		token = strings.TrimSpace(strings.TrimPrefix(value, "Bearer "))
+	}
+	if token != "" {
+		if ctx_, err := this.AccessControl.Authenticate(ctx, token); err != nil {
+			return nil, err
+		} else {
+			ctx = ctx_
+		}
+	}
+	if err := this.AccessControl.Authorize(ctx, req.Method+" "+req.URL.Path); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return ctx, nil
+}
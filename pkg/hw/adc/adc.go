@@ -0,0 +1,208 @@
+package adc
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://www.ti.com/lit/ds/symlink/ads1115.pdf
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type adc struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.I2C
+	sync.Mutex
+
+	// Flags
+	chip  *string
+	bus   *uint
+	slave *uint
+	fsr   *float64
+
+	pga        uint16        // Gain bits for the full-scale range
+	conversion time.Duration // Time for a conversion
+	shift      uint          // Bits to shift a 12-bit conversion
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	regConversion = 0x00
+	regConfig     = 0x01
+
+	configStart    = 0x8000 // Start a single conversion, or not converting when read
+	configSingle   = 0x4000 // Single-ended input, with the channel in bits 13:12
+	configOneShot  = 0x0100 // Power down after a single conversion
+	configRate     = 0x0080 // 128 samples per second on the ADS1115, 1600 on the ADS1015
+	configNoCompar = 0x0003 // Disable the comparator
+
+	defaultAddr = 0x48
+	channels    = 4
+	maxPolls    = 10
+)
+
+var (
+	// Gain bits for each full-scale range in volts
+	ranges = map[float64]uint16{
+		6.144: 0x0000,
+		4.096: 0x0200,
+		2.048: 0x0400,
+		1.024: 0x0600,
+		0.512: 0x0800,
+		0.256: 0x0A00,
+	}
+
+	// sleep waits for a conversion, and is replaced in tests
+	sleep = func(ctx context.Context, d time.Duration) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *adc) Define(cfg gopi.Config) error {
+	this.chip = cfg.FlagString("adc.chip", "ads1115", "Converter (ads1115 or ads1015)")
+	this.bus = cfg.FlagUint("adc.bus", 1, "I2C bus")
+	this.slave = cfg.FlagUint("adc.addr", 0, "I2C address, or zero for the default address")
+	this.fsr = cfg.FlagFloat("adc.range", 4.096, "Full-scale range in volts")
+	return nil
+}
+
+func (this *adc) New(gopi.Config) error {
+	this.Require(this.Logger, this.I2C)
+
+	// Check parameters
+	if pga, exists := ranges[*this.fsr]; exists == false {
+		return gopi.ErrBadParameter.WithPrefix("-adc.range")
+	} else {
+		this.pga = pga
+	}
+	if *this.slave == 0 {
+		*this.slave = defaultAddr
+	} else if *this.slave > 0x7F {
+		return gopi.ErrBadParameter.WithPrefix("-adc.addr")
+	}
+
+	// Set the time for a conversion, and the ADS1015 has a 12-bit
+	// conversion in the upper bits
+	switch strings.ToLower(*this.chip) {
+	case "ads1115":
+		this.conversion, this.shift = 8*time.Millisecond, 0
+	case "ads1015":
+		this.conversion, this.shift = time.Millisecond, 4
+	default:
+		return gopi.ErrBadParameter.WithPrefix("-adc.chip")
+	}
+
+	// Detect the converter
+	if detected, err := this.I2C.DetectSlave(gopi.I2CBus(*this.bus), uint8(*this.slave)); err != nil {
+		return err
+	} else if detected == false {
+		return gopi.ErrNotFound.WithPrefix("-adc.addr")
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *adc) Channels() uint {
+	return channels
+}
+
+// Read starts a single conversion on a channel, waits for it to complete
+// and returns the voltage
+func (this *adc) Read(ctx context.Context, channel uint) (float32, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if channel >= channels {
+		return 0, gopi.ErrBadParameter.WithPrefix("Read: channel ", channel)
+	}
+
+	// Start conversion
+	config := configStart | configSingle | uint16(channel)<<12 | this.pga | configOneShot | configRate | configNoCompar
+	if err := this.write(ctx, regConfig, config); err != nil {
+		return 0, err
+	}
+
+	// Wait for conversion
+	for i := 0; ; i++ {
+		if err := sleep(ctx, this.conversion); err != nil {
+			return 0, err
+		} else if value, err := this.read(ctx, regConfig); err != nil {
+			return 0, err
+		} else if value&configStart != 0 {
+			break
+		} else if i >= maxPolls {
+			return 0, gopi.ErrTimeout.WithPrefix("Read: channel ", channel)
+		}
+	}
+
+	// Read conversion
+	value, err := this.read(ctx, regConversion)
+	if err != nil {
+		return 0, err
+	}
+	return float32(float64(int16(value)>>this.shift) * *this.fsr / float64(int(0x8000)>>this.shift)), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *adc) String() string {
+	str := "<adc"
+	str += fmt.Sprintf(" chip=%q", strings.ToLower(*this.chip))
+	str += fmt.Sprintf(" addr=0x%02X", *this.slave)
+	str += fmt.Sprint(" range=", *this.fsr)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// write sets a 16-bit big-endian register
+func (this *adc) write(ctx context.Context, reg uint8, value uint16) error {
+	bus := gopi.I2CBus(*this.bus)
+	if err := this.I2C.SetSlave(bus, uint8(*this.slave)); err != nil {
+		return err
+	} else if _, err := this.I2C.TransferContext(ctx, bus, []byte{reg, byte(value >> 8), byte(value)}, 0); err != nil {
+		return err
+	} else {
+		return nil
+	}
+}
+
+// read returns a 16-bit big-endian register
+func (this *adc) read(ctx context.Context, reg uint8) (uint16, error) {
+	bus := gopi.I2CBus(*this.bus)
+	if err := this.I2C.SetSlave(bus, uint8(*this.slave)); err != nil {
+		return 0, err
+	} else if data, err := this.I2C.TransferContext(ctx, bus, []byte{reg}, 2); err != nil {
+		return 0, err
+	} else if len(data) != 2 {
+		return 0, gopi.ErrUnexpectedResponse.WithPrefix("Read: register ", reg)
+	} else {
+		return binary.BigEndian.Uint16(data), nil
+	}
+}
@@ -0,0 +1,120 @@
+package adc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// converter emulates a converter, which completes a conversion after it
+// has been polled a number of times
+type converter struct {
+	*testutil.I2C
+	busy  int // Number of polls before the conversion completes
+	polls int
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONVERTER
+
+func newConverter(value uint16, busy int) *converter {
+	this := &converter{I2C: testutil.NewI2C(defaultAddr), busy: busy}
+	this.SetUint16(defaultAddr, regConversion, value)
+	this.SetTransfer(this.transfer)
+	return this
+}
+
+// transfer restarts polling when the configuration is written, and sets
+// the start bit in the configuration when the conversion completes
+func (this *converter) transfer(_ uint8, data []byte, n int) ([]byte, error) {
+	switch {
+	case n == 0 && data[0] == regConfig:
+		this.polls = 0
+	case data[0] == regConfig:
+		config := this.Uint16(defaultAddr, regConfig) &^ configStart
+		if this.polls++; this.polls > this.busy {
+			config |= configStart
+		}
+		return []byte{byte(config >> 8), byte(config)}, nil
+	}
+	return nil, gopi.ErrNotImplemented
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func init() {
+	sleep = func(ctx context.Context, _ time.Duration) error {
+		return ctx.Err()
+	}
+}
+
+func Test_ADC_001(t *testing.T) {
+	// ADS1115 at 4.096V full scale, where the conversion completes after
+	// the second poll
+	bus := newConverter(0x4000, 1)
+	adc := newADC(bus, 4.096, 0)
+	read(t, adc, 2, 2.048)
+	if config := bus.Uint16(defaultAddr, regConfig); config != 0xE383 {
+		t.Errorf("Unexpected configuration 0x%04X", config)
+	}
+
+	// Negative voltage
+	bus.SetUint16(defaultAddr, regConversion, 0xC000)
+	read(t, adc, 0, -2.048)
+	if config := bus.Uint16(defaultAddr, regConfig); config != 0xC383 {
+		t.Errorf("Unexpected configuration 0x%04X", config)
+	}
+}
+
+func Test_ADC_002(t *testing.T) {
+	// ADS1015 has a 12-bit conversion in the upper bits
+	bus := newConverter(0x4000, 0)
+	adc := newADC(bus, 2.048, 4)
+	read(t, adc, 1, 1.024)
+	if config := bus.Uint16(defaultAddr, regConfig); config != 0xD583 {
+		t.Errorf("Unexpected configuration 0x%04X", config)
+	}
+}
+
+func Test_ADC_003(t *testing.T) {
+	// Conversion does not complete
+	bus := newConverter(0, maxPolls+1)
+	adc := newADC(bus, 4.096, 0)
+	if _, err := adc.Read(context.Background(), 0); errors.Is(err, gopi.ErrTimeout) == false {
+		t.Error("Expected timeout, got", err)
+	}
+
+	// Channel out of range
+	if _, err := adc.Read(context.Background(), adc.Channels()); err == nil {
+		t.Error("Expected error for channel")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func newADC(bus gopi.I2C, fsr float64, shift uint) *adc {
+	chip, b, slave := "ads1115", uint(1), uint(defaultAddr)
+	if shift != 0 {
+		chip = "ads1015"
+	}
+	return &adc{I2C: bus, chip: &chip, bus: &b, slave: &slave, fsr: &fsr, pga: ranges[fsr], shift: shift}
+}
+
+func read(t *testing.T, adc *adc, channel uint, expected float32) {
+	t.Helper()
+	if value, err := adc.Read(context.Background(), channel); err != nil {
+		t.Error(err)
+	} else if math.Abs(float64(value-expected)) > 0.001 {
+		t.Errorf("Unexpected value %v, expected %v", value, expected)
+	}
+}
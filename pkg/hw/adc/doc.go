@@ -0,0 +1,8 @@
+// ADC package implements gopi.ADC, which reads voltages from the four
+// single-ended channels of an ADS1015 or ADS1115 analog-to-digital
+// converter on an I2C bus. The converter is set with the -adc.chip flag,
+// and is at address 0x48 unless -adc.addr is set. The -adc.range flag
+// sets the full-scale voltage, which should be above the highest voltage
+// measured but no higher than the supply voltage. A gopi.I2C unit is
+// required.
+package adc
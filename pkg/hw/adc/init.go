@@ -0,0 +1,13 @@
+package adc
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.ADC
+	graph.RegisterUnit(reflect.TypeOf(&adc{}), reflect.TypeOf((*gopi.ADC)(nil)))
+}
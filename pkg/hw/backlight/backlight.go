@@ -0,0 +1,27 @@
+package backlight
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type backlight struct {
+	gopi.Unit
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *backlight) String() string {
+	str := "<backlight"
+	for _, name := range this.Devices() {
+		if value, max, err := this.Brightness(name); err == nil {
+			str += fmt.Sprintf(" %v={ %v/%v }", name, value, max)
+		}
+	}
+	return str + ">"
+}
@@ -0,0 +1,107 @@
+// +build linux
+
+package backlight
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	sysfsBacklightPath = "/sys/class/backlight"
+)
+
+const (
+	// Values for bl_power, which follow framebuffer blanking
+	powerOn  = "0"
+	powerOff = "4"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *backlight) Devices() []string {
+	files, err := ioutil.ReadDir(sysfsBacklightPath)
+	if err != nil {
+		return nil
+	}
+	devices := make([]string, 0, len(files))
+	for _, file := range files {
+		devices = append(devices, file.Name())
+	}
+	return devices
+}
+
+func (this *backlight) Brightness(name string) (uint, uint, error) {
+	if value, err := readUint(name, "brightness"); err != nil {
+		return 0, 0, err
+	} else if max, err := readUint(name, "max_brightness"); err != nil {
+		return 0, 0, err
+	} else {
+		return value, max, nil
+	}
+}
+
+func (this *backlight) SetBrightness(name string, value uint) error {
+	return write(name, "brightness", strconv.FormatUint(uint64(value), 10))
+}
+
+func (this *backlight) SetPower(name string, on bool) error {
+	if on {
+		return write(name, "bl_power", powerOn)
+	} else {
+		return write(name, "bl_power", powerOff)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func attrPath(name, attr string) (string, error) {
+	if name == "" || strings.ContainsAny(name, "/") || strings.HasPrefix(name, ".") {
+		return "", gopi.ErrBadParameter.WithPrefix(name)
+	}
+	path := filepath.Join(sysfsBacklightPath, name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", gopi.ErrNotFound.WithPrefix(name)
+	} else if err != nil {
+		return "", err
+	}
+	return filepath.Join(path, attr), nil
+}
+
+func read(name, attr string) (string, error) {
+	if path, err := attrPath(name, attr); err != nil {
+		return "", err
+	} else if data, err := ioutil.ReadFile(path); err != nil {
+		return "", err
+	} else {
+		return strings.TrimSpace(string(data)), nil
+	}
+}
+
+func readUint(name, attr string) (uint, error) {
+	if value, err := read(name, attr); err != nil {
+		return 0, err
+	} else if value, err := strconv.ParseUint(value, 10, 32); err != nil {
+		return 0, err
+	} else {
+		return uint(value), nil
+	}
+}
+
+func write(name, attr, value string) error {
+	if path, err := attrPath(name, attr); err != nil {
+		return err
+	} else {
+		return ioutil.WriteFile(path, []byte(value), 0)
+	}
+}
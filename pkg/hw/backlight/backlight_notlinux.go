@@ -0,0 +1,26 @@
+// +build !linux
+
+package backlight
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *backlight) Devices() []string {
+	return nil
+}
+
+func (this *backlight) Brightness(string) (uint, uint, error) {
+	return 0, 0, gopi.ErrNotImplemented
+}
+
+func (this *backlight) SetBrightness(string, uint) error {
+	return gopi.ErrNotImplemented
+}
+
+func (this *backlight) SetPower(string, bool) error {
+	return gopi.ErrNotImplemented
+}
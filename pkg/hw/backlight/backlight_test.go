@@ -0,0 +1,41 @@
+package backlight_test
+
+import (
+	"testing"
+
+	"github.com/djthorpe/gopi/v3"
+	"github.com/djthorpe/gopi/v3/pkg/tool"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Backlight
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Backlight_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.Backlight == nil {
+			t.Error("nil Backlight unit")
+		} else {
+			t.Log(app.Backlight)
+		}
+	})
+}
+
+func Test_Backlight_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		for _, name := range app.Backlight.Devices() {
+			if value, max, err := app.Backlight.Brightness(name); err != nil {
+				t.Error(err)
+			} else {
+				t.Logf("%v brightness=%v/%v", name, value, max)
+			}
+		}
+	})
+}
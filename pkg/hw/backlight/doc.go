@@ -0,0 +1,3 @@
+// Backlight package controls backlight class devices through
+// /sys/class/backlight, including setting brightness and power
+package backlight
@@ -0,0 +1,13 @@
+package backlight
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register backlight
+	graph.RegisterUnit(reflect.TypeOf(&backlight{}), reflect.TypeOf((*gopi.Backlight)(nil)))
+}
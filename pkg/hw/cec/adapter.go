@@ -0,0 +1,22 @@
+package cec
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// adapter sends and receives messages over HDMI-CEC
+type adapter interface {
+	// PhysicalAddress returns the HDMI address of the adapter
+	PhysicalAddress() uint16
+
+	// LogicalAddress returns the logical address claimed by the adapter
+	LogicalAddress() uint8
+
+	// Send transmits a message
+	Send(message) error
+
+	// Close releases the adapter
+	Close() error
+}
+
+// receiveFunc is called by an adapter for each message received
+type receiveFunc func(message)
@@ -0,0 +1,111 @@
+// +build linux
+
+package cec
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	linux "github.com/djthorpe/gopi/v3/pkg/sys/linux"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// kernel adapter uses the CEC framework
+type kernel struct {
+	sync.WaitGroup
+
+	fh   *os.File
+	stop chan struct{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	receiveTimeout = 100 * time.Millisecond
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func openKernel(path, name string, fn receiveFunc) (adapter, error) {
+	this := new(kernel)
+	if fh, err := linux.CECOpen(path); err != nil {
+		return nil, err
+	} else {
+		this.fh = fh
+	}
+
+	// Receive messages addressed to this device and claim a
+	// logical address
+	if err := linux.CECSetFollower(this.fh.Fd()); err != nil {
+		this.fh.Close()
+		return nil, err
+	} else if err := linux.CECSetPlaybackDevice(this.fh.Fd(), name); err != nil {
+		this.fh.Close()
+		return nil, err
+	}
+
+	// Receive messages in the background
+	this.stop = make(chan struct{})
+	this.WaitGroup.Add(1)
+	go this.receive(fn)
+
+	// Return success
+	return this, nil
+}
+
+func (this *kernel) Close() error {
+	close(this.stop)
+	this.WaitGroup.Wait()
+	return this.fh.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *kernel) PhysicalAddress() uint16 {
+	if addr, err := linux.CECPhysicalAddress(this.fh.Fd()); err != nil {
+		return linux.CEC_PHYS_ADDR_INVALID
+	} else {
+		return addr
+	}
+}
+
+func (this *kernel) LogicalAddress() uint8 {
+	if addr, err := linux.CECLogicalAddress(this.fh.Fd()); err != nil {
+		return linux.CEC_LOG_ADDR_INVALID
+	} else {
+		return addr
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *kernel) Send(msg message) error {
+	return linux.CECTransmit(this.fh.Fd(), msg)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *kernel) receive(fn receiveFunc) {
+	defer this.WaitGroup.Done()
+	for {
+		select {
+		case <-this.stop:
+			return
+		default:
+			if msg, err := linux.CECReceive(this.fh.Fd(), receiveTimeout); err != nil {
+				// Avoid spinning when the adapter has gone away
+				time.Sleep(receiveTimeout)
+			} else if msg != nil {
+				fn(message(msg))
+			}
+		}
+	}
+}
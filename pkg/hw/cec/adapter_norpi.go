@@ -0,0 +1,14 @@
+// +build !rpi darwin
+
+package cec
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func openVC(name string, fn receiveFunc) (adapter, error) {
+	return nil, gopi.ErrNotImplemented.WithPrefix("openVC")
+}
@@ -0,0 +1,14 @@
+// +build !linux
+
+package cec
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func openKernel(path, name string, fn receiveFunc) (adapter, error) {
+	return nil, gopi.ErrNotImplemented.WithPrefix("openKernel")
+}
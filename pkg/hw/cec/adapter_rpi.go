@@ -0,0 +1,83 @@
+// +build rpi
+// +build !darwin
+
+package cec
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+	rpi "github.com/djthorpe/gopi/v3/pkg/sys/rpi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// vc adapter uses the VideoCore CEC service
+type vc struct {
+	instance rpi.VCHIInstance
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func openVC(name string, fn receiveFunc) (adapter, error) {
+	this := new(vc)
+	if instance := rpi.VCHI_Init(); instance == nil {
+		return nil, gopi.ErrInternalAppError.WithPrefix("VCHI_Init")
+	} else if _, err := rpi.VCHI_CECInit(instance); err != nil {
+		return nil, gopi.ErrInternalAppError.WithPrefix("VCHI_CECInit")
+	} else {
+		this.instance = instance
+	}
+
+	// Set name and receive all messages rather than have the firmware
+	// handle them
+	if err := rpi.VCCEC_SetOSDName(name); err != nil {
+		rpi.VCHI_CECStop(this.instance)
+		return nil, gopi.ErrInternalAppError.WithPrefix("VCCEC_SetOSDName")
+	} else if err := rpi.VCCEC_RegisterAll(); err != nil {
+		rpi.VCHI_CECStop(this.instance)
+		return nil, gopi.ErrInternalAppError.WithPrefix("VCCEC_RegisterAll")
+	}
+	rpi.VCCEC_RegisterCallback(func(reason rpi.CECReason, msg []byte) {
+		switch reason {
+		case rpi.CEC_REASON_RX, rpi.CEC_REASON_BUTTON_PRESSED, rpi.CEC_REASON_BUTTON_RELEASE:
+			if len(msg) > 0 {
+				fn(message(msg))
+			}
+		}
+	})
+
+	// Return success
+	return this, nil
+}
+
+func (this *vc) Close() error {
+	rpi.VCCEC_RegisterCallback(nil)
+	return rpi.VCHI_CECStop(this.instance)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *vc) PhysicalAddress() uint16 {
+	addr, _ := rpi.VCCEC_GetPhysicalAddress()
+	return addr
+}
+
+func (this *vc) LogicalAddress() uint8 {
+	addr, _ := rpi.VCCEC_GetLogicalAddress()
+	return addr
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *vc) Send(msg message) error {
+	if len(msg) == 0 {
+		return gopi.ErrBadParameter.WithPrefix("Send")
+	} else if err := rpi.VCCEC_SendMessage(msg.Destination(), msg[1:], false); err != nil {
+		return gopi.ErrUnexpectedResponse.WithPrefix("Send: ", err)
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,262 @@
+package cec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type cec struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	sync.Mutex
+
+	device  *string
+	name    *string
+	timeout *time.Duration
+	adapter adapter
+	recv    chan message
+	power   chan gopi.CECPower
+	status  sync.Mutex // Serializes power status requests
+	pressed int        // Last UI command pressed, or -1
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	recvCapacity = 16
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *cec) Define(cfg gopi.Config) error {
+	this.device = cfg.FlagString("cec.device", "/dev/cec0", "CEC adapter device")
+	this.name = cfg.FlagString("cec.name", "gopi", "Name displayed on the TV")
+	this.timeout = cfg.FlagDuration("cec.timeout", time.Second, "Timeout waiting for TV response")
+	return nil
+}
+
+func (this *cec) New(gopi.Config) error {
+	this.Require(this.Logger, this.Publisher)
+
+	this.recv = make(chan message, recvCapacity)
+	this.power = make(chan gopi.CECPower, 1)
+	this.pressed = -1
+
+	// Use the kernel adapter when the device exists, or else the
+	// VideoCore service
+	if _, err := os.Stat(*this.device); err == nil {
+		if adapter, err := openKernel(*this.device, *this.name, this.receive); err != nil {
+			return err
+		} else {
+			this.adapter = adapter
+		}
+	} else if adapter, err := openVC(*this.name, this.receive); err != nil {
+		this.Debug("CEC: Not detected: ", err)
+	} else {
+		this.adapter = adapter
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *cec) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	var result error
+	if this.adapter != nil {
+		result = this.adapter.Close()
+	}
+
+	// Release resources
+	this.adapter = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *cec) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg := <-this.recv:
+			if err := this.process(msg); err != nil {
+				this.Print("CEC: ", err)
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *cec) PhysicalAddress() uint16 {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.adapter == nil {
+		return 0xFFFF
+	} else {
+		return this.adapter.PhysicalAddress()
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *cec) PowerOn() error {
+	if err := this.send(addrTV, opImageViewOn); err != nil {
+		return err
+	} else {
+		return this.SetInput(0)
+	}
+}
+
+func (this *cec) Standby() error {
+	return this.send(addrTV, opStandby)
+}
+
+func (this *cec) SetInput(input uint) error {
+	if input == 0 {
+		addr := this.PhysicalAddress()
+		return this.send(addrBroadcast, opActiveSource, byte(addr>>8), byte(addr))
+	} else if input > 0x0F {
+		return gopi.ErrBadParameter.WithPrefix("SetInput: ", input)
+	} else {
+		return this.send(addrBroadcast, opSetStreamPath, byte(input<<4), 0x00)
+	}
+}
+
+func (this *cec) PowerStatus() (gopi.CECPower, error) {
+	this.status.Lock()
+	defer this.status.Unlock()
+
+	// Discard any previous report
+	select {
+	case <-this.power:
+	default:
+	}
+
+	// Request status from the TV and wait for the report
+	if err := this.send(addrTV, opGiveDevicePowerStatus); err != nil {
+		return 0, err
+	}
+	select {
+	case power := <-this.power:
+		return power, nil
+	case <-time.After(*this.timeout):
+		return 0, gopi.ErrUnexpectedResponse.WithPrefix("PowerStatus: No response")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// receive is called by the adapter for each message
+func (this *cec) receive(msg message) {
+	select {
+	case this.recv <- msg:
+		break
+	default:
+		this.Debug("CEC: Dropped message: ", msg)
+	}
+}
+
+// send transmits a message from this device
+func (this *cec) send(dest uint8, op opcode, operands ...byte) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.adapter == nil {
+		return gopi.ErrNotFound.WithPrefix("CEC adapter")
+	}
+	msg := newMessage(this.adapter.LogicalAddress(), dest, op, operands...)
+	this.Debug("CEC: Send: ", msg)
+	return this.adapter.Send(msg)
+}
+
+// process handles a received message
+func (this *cec) process(msg message) error {
+	this.Debug("CEC: Receive: ", msg)
+	if msg.IsPoll() {
+		return nil
+	}
+
+	operands := msg.Operands()
+	switch msg.Opcode() {
+	case opReportPowerStatus:
+		if msg.Initiator() == addrTV && len(operands) > 0 {
+			select {
+			case this.power <- gopi.CECPower(operands[0]):
+				break
+			default:
+			}
+		}
+	case opGiveDevicePowerStatus:
+		return this.send(msg.Initiator(), opReportPowerStatus, byte(gopi.CEC_POWER_ON))
+	case opMenuRequest:
+		return this.send(msg.Initiator(), opMenuStatus, menuActivated)
+	case opUserControlPressed:
+		if len(operands) > 0 {
+			this.keypress(int(operands[0]))
+		}
+	case opUserControlReleased:
+		this.keypress(-1)
+	}
+
+	// Return success
+	return nil
+}
+
+// keypress emits input events for a pressed UI command, or a
+// release when the command is -1. The TV repeats the pressed message
+// while a key is held down
+func (this *cec) keypress(code int) {
+	evts := []gopi.InputEvent{}
+	if code == this.pressed {
+		if code >= 0 {
+			evts = append(evts, NewInputEvent(*this.name, gopi.INPUT_EVENT_KEYREPEAT, uint8(code)))
+		}
+	} else {
+		if this.pressed >= 0 {
+			evts = append(evts, NewInputEvent(*this.name, gopi.INPUT_EVENT_KEYRELEASE, uint8(this.pressed)))
+		}
+		if code >= 0 {
+			evts = append(evts, NewInputEvent(*this.name, gopi.INPUT_EVENT_KEYPRESS, uint8(code)))
+		}
+	}
+	this.pressed = code
+	for _, evt := range evts {
+		if err := this.Publisher.Emit(evt, true); err != nil {
+			this.Print("CEC: ", err)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *cec) String() string {
+	str := "<cec"
+	str += fmt.Sprintf(" name=%q", *this.name)
+	if this.adapter != nil {
+		str += fmt.Sprintf(" physical_address=0x%04X", this.PhysicalAddress())
+	}
+	return str + ">"
+}
@@ -0,0 +1,43 @@
+package cec_test
+
+import (
+	"errors"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/cec"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.CEC
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_CEC_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.CEC == nil {
+			t.Error("nil CEC unit")
+		} else {
+			t.Log(app.CEC)
+		}
+	})
+}
+
+func Test_CEC_002(t *testing.T) {
+	args := []string{"-cec.device=/dev/null/cec"}
+	tool.Test(t, args, new(App), func(app *App) {
+		if err := app.CEC.SetInput(16); errors.Is(err, gopi.ErrBadParameter) == false {
+			t.Error("Expected ErrBadParameter, got", err)
+		}
+		t.Logf("PhysicalAddress=0x%04X", app.CEC.PhysicalAddress())
+	})
+}
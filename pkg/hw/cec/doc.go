@@ -0,0 +1,5 @@
+// CEC package controls TVs over HDMI-CEC, using the kernel CEC
+// framework (/dev/cec0) when available and the VideoCore CEC service
+// on the Raspberry Pi legacy firmware otherwise. Keys pressed on the
+// TV remote control are emitted as input events.
+package cec
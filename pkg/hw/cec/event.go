@@ -0,0 +1,60 @@
+package cec
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	name string
+	key  gopi.KeyCode
+	t    gopi.InputType
+	code uint8
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewInputEvent(name string, t gopi.InputType, code uint8) gopi.InputEvent {
+	return &event{name, keycodeForCommand(code), t, code}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.name
+}
+
+func (this *event) Key() gopi.KeyCode {
+	return this.key
+}
+
+func (this *event) Type() gopi.InputType {
+	return this.t
+}
+
+// Device returns the UI command code sent by the TV
+func (this *event) Device() (gopi.InputDeviceType, uint32) {
+	return gopi.INPUT_DEVICE_REMOTE, uint32(this.code)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<event.input"
+	if this.name != "" {
+		str += fmt.Sprintf(" name=%q", this.name)
+	}
+	str += " type=" + fmt.Sprint(this.t)
+	if this.key != gopi.KEYCODE_NONE {
+		str += " key=" + fmt.Sprint(this.key)
+	}
+	str += fmt.Sprintf(" code=0x%02X", this.code)
+	return str + ">"
+}
@@ -0,0 +1,13 @@
+package cec
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register cec
+	graph.RegisterUnit(reflect.TypeOf(&cec{}), reflect.TypeOf((*gopi.CEC)(nil)))
+}
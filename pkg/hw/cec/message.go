@@ -0,0 +1,163 @@
+package cec
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// message consists of a header byte with the initiator and
+// destination logical addresses, followed by an opcode and operands.
+// A message with only a header is a poll
+type message []byte
+
+type opcode uint8
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	addrTV        = 0x0
+	addrBroadcast = 0xF
+)
+
+const (
+	opImageViewOn           opcode = 0x04
+	opStandby               opcode = 0x36
+	opUserControlPressed    opcode = 0x44
+	opUserControlReleased   opcode = 0x45
+	opActiveSource          opcode = 0x82
+	opSetStreamPath         opcode = 0x86
+	opMenuRequest           opcode = 0x8D
+	opMenuStatus            opcode = 0x8E
+	opGiveDevicePowerStatus opcode = 0x8F
+	opReportPowerStatus     opcode = 0x90
+)
+
+const (
+	menuActivated = 0x00
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+// keycodes maps UI command codes to keycodes
+var keycodes = map[uint8]gopi.KeyCode{
+	0x00: gopi.KEYCODE_NAV_SELECT,
+	0x01: gopi.KEYCODE_UP,
+	0x02: gopi.KEYCODE_DOWN,
+	0x03: gopi.KEYCODE_LEFT,
+	0x04: gopi.KEYCODE_RIGHT,
+	0x09: gopi.KEYCODE_HOME,
+	0x0A: gopi.KEYCODE_MENU,
+	0x0B: gopi.KEYCODE_BROWSE,
+	0x0D: gopi.KEYCODE_ESC,
+	0x20: gopi.KEYCODE_0,
+	0x21: gopi.KEYCODE_1,
+	0x22: gopi.KEYCODE_2,
+	0x23: gopi.KEYCODE_3,
+	0x24: gopi.KEYCODE_4,
+	0x25: gopi.KEYCODE_5,
+	0x26: gopi.KEYCODE_6,
+	0x27: gopi.KEYCODE_7,
+	0x28: gopi.KEYCODE_8,
+	0x29: gopi.KEYCODE_9,
+	0x2B: gopi.KEYCODE_ENTER,
+	0x2C: gopi.KEYCODE_CLEAR,
+	0x30: gopi.KEYCODE_PAGEUP,
+	0x31: gopi.KEYCODE_PAGEDOWN,
+	0x32: gopi.KEYCODE_CHANNEL_PREV,
+	0x35: gopi.KEYCODE_INFO,
+	0x40: gopi.KEYCODE_POWER,
+	0x41: gopi.KEYCODE_VOLUMEUP,
+	0x42: gopi.KEYCODE_VOLUMEDOWN,
+	0x43: gopi.KEYCODE_MUTE,
+	0x44: gopi.KEYCODE_PLAY,
+	0x45: gopi.KEYCODE_STOP,
+	0x46: gopi.KEYCODE_PAUSE,
+	0x47: gopi.KEYCODE_RECORD,
+	0x48: gopi.KEYCODE_SEARCH_LEFT,
+	0x49: gopi.KEYCODE_SEARCH_RIGHT,
+	0x4A: gopi.KEYCODE_EJECT,
+	0x4B: gopi.KEYCODE_CHAPTER_NEXT,
+	0x4C: gopi.KEYCODE_CHAPTER_PREV,
+	0x53: gopi.KEYCODE_CHANNEL_GUIDE,
+	0x6B: gopi.KEYCODE_POWER,
+	0x6C: gopi.KEYCODE_POWER_OFF,
+	0x6D: gopi.KEYCODE_POWER_ON,
+	0x71: gopi.KEYCODE_BUTTON_BLUE,
+	0x72: gopi.KEYCODE_BUTTON_RED,
+	0x73: gopi.KEYCODE_BUTTON_GREEN,
+	0x74: gopi.KEYCODE_BUTTON_YELLOW,
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newMessage(initiator, destination uint8, op opcode, operands ...byte) message {
+	msg := message{initiator<<4 | destination&0x0F, byte(op)}
+	return append(msg, operands...)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this message) Initiator() uint8 {
+	return this[0] >> 4
+}
+
+func (this message) Destination() uint8 {
+	return this[0] & 0x0F
+}
+
+func (this message) IsPoll() bool {
+	return len(this) < 2
+}
+
+func (this message) Opcode() opcode {
+	if this.IsPoll() {
+		return 0
+	}
+	return opcode(this[1])
+}
+
+func (this message) Operands() []byte {
+	if this.IsPoll() {
+		return nil
+	}
+	return this[2:]
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// keycodeForCommand returns the keycode for a UI command, or
+// KEYCODE_NONE if there is no mapping
+func keycodeForCommand(cmd uint8) gopi.KeyCode {
+	if key, exists := keycodes[cmd]; exists {
+		return key
+	} else {
+		return gopi.KEYCODE_NONE
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this message) String() string {
+	str := "<cec.message"
+	if len(this) == 0 {
+		return str + ">"
+	}
+	str += fmt.Sprintf(" initiator=0x%X destination=0x%X", this.Initiator(), this.Destination())
+	if this.IsPoll() == false {
+		str += fmt.Sprintf(" opcode=0x%02X", this.Opcode())
+	}
+	if operands := this.Operands(); len(operands) > 0 {
+		str += fmt.Sprintf(" operands=%X", operands)
+	}
+	return str + ">"
+}
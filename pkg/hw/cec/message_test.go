@@ -0,0 +1,58 @@
+package cec
+
+import (
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+func Test_Message_001(t *testing.T) {
+	msg := newMessage(0x4, addrTV, opImageViewOn)
+	if len(msg) != 2 || msg[0] != 0x40 || msg[1] != 0x04 {
+		t.Errorf("Unexpected message %X", []byte(msg))
+	} else if msg.Initiator() != 0x4 || msg.Destination() != addrTV {
+		t.Error("Unexpected addresses", msg)
+	} else if msg.Opcode() != opImageViewOn || len(msg.Operands()) != 0 {
+		t.Error("Unexpected opcode", msg)
+	}
+}
+
+func Test_Message_002(t *testing.T) {
+	msg := newMessage(0x4, addrBroadcast, opActiveSource, 0x10, 0x00)
+	if msg[0] != 0x4F {
+		t.Errorf("Unexpected header 0x%02X", msg[0])
+	} else if operands := msg.Operands(); len(operands) != 2 || operands[0] != 0x10 {
+		t.Errorf("Unexpected operands %X", operands)
+	} else {
+		t.Log(msg)
+	}
+}
+
+func Test_Message_003(t *testing.T) {
+	msg := message{0x40}
+	if msg.IsPoll() == false {
+		t.Error("Expected poll message")
+	} else if msg.Opcode() != 0 || msg.Operands() != nil {
+		t.Error("Unexpected opcode or operands", msg)
+	}
+}
+
+func Test_Message_004(t *testing.T) {
+	tests := []struct {
+		cmd uint8
+		key gopi.KeyCode
+	}{
+		{0x00, gopi.KEYCODE_NAV_SELECT},
+		{0x01, gopi.KEYCODE_UP},
+		{0x20, gopi.KEYCODE_0},
+		{0x29, gopi.KEYCODE_9},
+		{0x44, gopi.KEYCODE_PLAY},
+		{0x72, gopi.KEYCODE_BUTTON_RED},
+		{0xFE, gopi.KEYCODE_NONE},
+	}
+	for _, test := range tests {
+		if key := keycodeForCommand(test.cmd); key != test.key {
+			t.Errorf("Command 0x%02X: Expected %v, got %v", test.cmd, test.key, key)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type console struct {
+	gopi.Unit
+	gopi.Logger
+	sync.Mutex
+
+	// Flags
+	vt       *uint
+	blank    *bool
+	cursor   *bool
+	graphics *bool
+
+	tty      *os.File // Control of virtual terminals
+	term     *os.File // Terminal which is prepared
+	saved    state
+	prepared bool
+}
+
+// state is the console before it is prepared
+type state struct {
+	vt    uint // Active virtual terminal
+	mode  int  // Text or graphics mode
+	blank uint // Blanking time in minutes, or zero
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Blanking time restored when it cannot be read
+	defaultBlank = 10
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *console) Define(cfg gopi.Config) error {
+	this.vt = cfg.FlagUint("console.vt", 0, "Virtual terminal to switch to, or zero for the active terminal")
+	this.blank = cfg.FlagBool("console.blank", false, "Allow the console to blank")
+	this.cursor = cfg.FlagBool("console.cursor", false, "Show the text cursor")
+	this.graphics = cfg.FlagBool("console.graphics", true, "Stop the kernel drawing text over graphics")
+	return nil
+}
+
+func (this *console) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Restore anything which was changed when the console cannot be
+	// prepared, as the unit is not disposed
+	this.Mutex.Lock()
+	err := this.prepare()
+	this.Mutex.Unlock()
+	if err != nil {
+		this.Restore()
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *console) Dispose() error {
+	return this.Restore()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *console) String() string {
+	str := "<console"
+	if vt, err := this.VT(); err == nil {
+		str += fmt.Sprint(" vt=", vt)
+	}
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.prepared {
+		str += fmt.Sprint(" restore_vt=", this.saved.vt)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// sequence returns escape sequences which set the blanking time in
+// minutes, where zero disables blanking, and show or hide the cursor
+func sequence(blank uint, cursor bool) string {
+	str := fmt.Sprintf("\x1b[9;%d]", blank)
+	if cursor {
+		str += "\x1b[?25h"
+	} else {
+		str += "\x1b[?25l"
+	}
+	return str
+}
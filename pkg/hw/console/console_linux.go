@@ -0,0 +1,202 @@
+//go:build linux
+// +build linux
+
+package console
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+	unix "golang.org/x/sys/unix"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// vtstat is the state of virtual terminals
+type vtstat struct {
+	active uint16 // Active virtual terminal
+	signal uint16 // Signal to send
+	state  uint16 // Bitmask of open terminals
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	ttyPath    = "/dev/tty0"
+	ttyFormat  = "/dev/tty%d"
+	sysfsBlank = "/sys/module/kernel/parameters/consoleblank"
+)
+
+const (
+	vtGetState   = 0x5603
+	vtActivate   = 0x5606
+	vtWaitActive = 0x5607
+	kdSetMode    = 0x4B3A
+	kdGetMode    = 0x4B3B
+	kdGraphics   = 0x01
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *console) VT() (uint, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.tty == nil {
+		return 0, gopi.ErrOutOfOrder.WithPrefix("VT")
+	}
+	return active(this.tty)
+}
+
+func (this *console) Switch(vt uint) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.tty == nil {
+		return gopi.ErrOutOfOrder.WithPrefix("Switch")
+	}
+	return activate(this.tty, vt)
+}
+
+func (this *console) Restore() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Restore mode, blanking and cursor
+	var result error
+	if this.prepared {
+		if err := unix.IoctlSetInt(int(this.term.Fd()), kdSetMode, this.saved.mode); err != nil {
+			result = multierror.Append(result, os.NewSyscallError("KDSETMODE", err))
+		}
+		if _, err := this.term.WriteString(sequence(this.saved.blank, true)); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Switch back to the terminal which was active before
+	if this.tty != nil && this.saved.vt != 0 {
+		if vt, err := active(this.tty); err == nil && vt != this.saved.vt {
+			if err := activate(this.tty, this.saved.vt); err != nil {
+				result = multierror.Append(result, err)
+			}
+		}
+	}
+
+	// Release resources
+	if this.term != nil {
+		this.term.Close()
+	}
+	if this.tty != nil {
+		this.tty.Close()
+	}
+	this.term, this.tty = nil, nil
+	this.saved, this.prepared = state{}, false
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// prepare switches virtual terminal, and sets blanking, the cursor and
+// graphics mode, saving the state so that it can be restored even when
+// preparing fails. It should be called with the lock held
+func (this *console) prepare() error {
+	tty, err := os.OpenFile(ttyPath, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	this.tty = tty
+
+	// Save the active terminal and switch
+	if vt, err := active(tty); err != nil {
+		return err
+	} else {
+		this.saved.vt = vt
+	}
+	if *this.vt != 0 && *this.vt != this.saved.vt {
+		if err := activate(tty, *this.vt); err != nil {
+			return err
+		}
+	}
+
+	// Open the terminal which is now active
+	vt, err := active(tty)
+	if err != nil {
+		return err
+	}
+	term, err := os.OpenFile(fmt.Sprintf(ttyFormat, vt), os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	this.term = term
+
+	// Save mode and blanking time
+	if mode, err := unix.IoctlGetInt(int(term.Fd()), kdGetMode); err != nil {
+		return os.NewSyscallError("KDGETMODE", err)
+	} else {
+		this.saved.mode = mode
+	}
+	this.saved.blank = readBlank()
+	this.prepared = true
+
+	// Set blanking, cursor and mode
+	blank := uint(0)
+	if *this.blank {
+		blank = this.saved.blank
+	}
+	if _, err := term.WriteString(sequence(blank, *this.cursor)); err != nil {
+		return err
+	}
+	if *this.graphics {
+		if err := unix.IoctlSetInt(int(term.Fd()), kdSetMode, kdGraphics); err != nil {
+			return os.NewSyscallError("KDSETMODE", err)
+		}
+	}
+
+	// Return success
+	this.Debug("Console: Prepared vt=", vt, " saved=", this.saved)
+	return nil
+}
+
+// active returns the active virtual terminal
+func active(tty *os.File) (uint, error) {
+	var stat vtstat
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, tty.Fd(), vtGetState, uintptr(unsafe.Pointer(&stat))); errno != 0 {
+		return 0, os.NewSyscallError("VT_GETSTATE", errno)
+	}
+	return uint(stat.active), nil
+}
+
+// activate switches to a virtual terminal and waits until it is active
+func activate(tty *os.File, vt uint) error {
+	if vt == 0 {
+		return gopi.ErrBadParameter.WithPrefix("Switch")
+	} else if err := unix.IoctlSetInt(int(tty.Fd()), vtActivate, int(vt)); err != nil {
+		return os.NewSyscallError("VT_ACTIVATE", err)
+	} else if err := unix.IoctlSetInt(int(tty.Fd()), vtWaitActive, int(vt)); err != nil {
+		return os.NewSyscallError("VT_WAITACTIVE", err)
+	}
+	return nil
+}
+
+// readBlank returns the console blanking time in minutes, or the
+// default when it cannot be read
+func readBlank() uint {
+	if data, err := ioutil.ReadFile(sysfsBlank); err != nil {
+		return defaultBlank
+	} else if secs, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 32); err != nil {
+		return defaultBlank
+	} else {
+		return uint(secs+59) / 60
+	}
+}
@@ -0,0 +1,31 @@
+// +build !linux
+
+package console
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *console) VT() (uint, error) {
+	return 0, gopi.ErrNotImplemented
+}
+
+func (this *console) Switch(uint) error {
+	return gopi.ErrNotImplemented
+}
+
+func (this *console) Restore() error {
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// prepare does not change the console on other platforms
+func (this *console) prepare() error {
+	this.Debug("Console: Not implemented on this platform")
+	return nil
+}
@@ -0,0 +1,34 @@
+package console
+
+import (
+	"testing"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Console_001(t *testing.T) {
+	tests := []struct {
+		blank  uint
+		cursor bool
+		seq    string
+	}{
+		{0, false, "\x1b[9;0]\x1b[?25l"},
+		{10, true, "\x1b[9;10]\x1b[?25h"},
+	}
+	for _, test := range tests {
+		if seq := sequence(test.blank, test.cursor); seq != test.seq {
+			t.Errorf("Unexpected sequence %q for %v", seq, test)
+		}
+	}
+}
+
+func Test_Console_002(t *testing.T) {
+	// Restoring a console which was not prepared has no effect
+	this := new(console)
+	if err := this.Restore(); err != nil {
+		t.Error(err)
+	} else if err := this.Restore(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,15 @@
+// Console package prepares a Linux virtual terminal for full-screen
+// graphics in kiosk mode, which would otherwise need to be done with
+// scripts before running an application:
+//
+//   * Switches to a virtual terminal with -console.vt
+//   * Disables console blanking, unless -console.blank is set
+//   * Hides the text cursor, unless -console.cursor is set
+//   * Sets graphics mode, so that the kernel does not draw text over
+//     graphics, unless -console.graphics=false
+//
+// The console is restored when the unit is disposed, which includes
+// when a Run function panics or the application receives a terminate
+// signal. The unit needs permission to open /dev/tty0, and on other
+// platforms the console is not changed.
+package console
@@ -0,0 +1,13 @@
+package console
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.Console
+	graph.RegisterUnit(reflect.TypeOf(&console{}), reflect.TypeOf((*gopi.Console)(nil)))
+}
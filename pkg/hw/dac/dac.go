@@ -0,0 +1,87 @@
+package dac
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type dac struct {
+	gopi.Unit
+	gopi.Logger
+	sync.Mutex
+
+	name   *string
+	preset *preset
+	card   string // ALSA card identifier
+	fh     *os.File
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *dac) Define(cfg gopi.Config) error {
+	this.name = cfg.FlagString("dac.preset", "", "DAC preset, or empty to detect")
+	return nil
+}
+
+func (this *dac) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	var result error
+	if this.fh != nil {
+		result = this.fh.Close()
+	}
+
+	// Release resources
+	this.fh = nil
+	this.preset = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *dac) Name() string {
+	if this.preset == nil {
+		return ""
+	} else {
+		return this.preset.Name
+	}
+}
+
+func (this *dac) Device() string {
+	if this.card == "" {
+		return ""
+	} else {
+		return "hw:CARD=" + this.card
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *dac) String() string {
+	str := "<dac"
+	if name := this.Name(); name != "" {
+		str += fmt.Sprintf(" name=%q", name)
+	}
+	if device := this.Device(); device != "" {
+		str += fmt.Sprintf(" device=%q", device)
+	}
+	if volume, err := this.Volume(); err == nil {
+		str += fmt.Sprintf(" volume=%.2f", volume)
+	}
+	if filter, err := this.Filter(); err == nil {
+		str += fmt.Sprintf(" filter=%q", filter)
+	}
+	return str + ">"
+}
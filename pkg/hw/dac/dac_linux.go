@@ -0,0 +1,204 @@
+// +build linux
+
+package dac
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	linux "github.com/djthorpe/gopi/v3/pkg/sys/linux"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	hatPath = "/proc/device-tree/hat"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *dac) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Cards may not exist if there is no sound support
+	cards, err := linux.ALSACards()
+	if err != nil {
+		this.Debug("DAC: ", err)
+	}
+
+	// Select preset from flag, HAT EEPROM or sound card
+	if *this.name != "" {
+		if this.preset = presetWithName(*this.name); this.preset == nil {
+			return gopi.ErrBadParameter.WithPrefix("-dac.preset: ", *this.name)
+		}
+	} else if product := readHat("product"); product != "" {
+		if this.preset = presetWithProduct(product); this.preset == nil {
+			this.Debug("DAC: Unsupported HAT: ", product)
+		}
+	}
+	if this.preset == nil {
+		for _, card := range cards {
+			if this.preset = presetWithCard(card.Name, card.Driver); this.preset != nil {
+				break
+			}
+		}
+	}
+	if this.preset == nil {
+		this.Debug("DAC: Not detected")
+		return nil
+	}
+
+	// Find the sound card for the preset, which is only loaded when
+	// the device tree overlay is enabled
+	for _, card := range cards {
+		if this.preset.matchCard(card.Name, card.Driver) {
+			if fh, err := linux.ALSAOpenControl(card.Number); err != nil {
+				return err
+			} else {
+				this.card, this.fh = card.Id, fh
+			}
+			break
+		}
+	}
+	if this.fh == nil {
+		this.Print("DAC: ", this.preset.Name, ": Sound card not loaded, add dtoverlay=", this.preset.Overlay, " to /boot/config.txt")
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *dac) Volume() (float32, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if control, err := this.control(this.volume()); err != nil {
+		return 0, err
+	} else if values, err := linux.ALSAControlValues(this.fh.Fd(), control); err != nil {
+		return 0, err
+	} else if len(values) == 0 || control.Max <= control.Min {
+		return 0, gopi.ErrUnexpectedResponse.WithPrefix("Volume")
+	} else {
+		return float32(values[0]-control.Min) / float32(control.Max-control.Min), nil
+	}
+}
+
+func (this *dac) SetVolume(volume float32) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if volume < 0 || volume > 1 {
+		return gopi.ErrBadParameter.WithPrefix("SetVolume")
+	}
+	control, err := this.control(this.volume())
+	if err != nil {
+		return err
+	}
+
+	// Set all channels to the same value
+	values := make([]int64, control.Count)
+	for i := range values {
+		values[i] = control.Min + int64(volume*float32(control.Max-control.Min)+0.5)
+	}
+	return linux.ALSASetControlValues(this.fh.Fd(), control, values)
+}
+
+func (this *dac) Filters() []string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if control, err := this.control(this.filter()); err != nil {
+		return nil
+	} else {
+		return control.Items
+	}
+}
+
+func (this *dac) Filter() (string, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if control, err := this.control(this.filter()); err != nil {
+		return "", err
+	} else if values, err := linux.ALSAControlValues(this.fh.Fd(), control); err != nil {
+		return "", err
+	} else if len(values) == 0 || values[0] < 0 || values[0] >= int64(len(control.Items)) {
+		return "", gopi.ErrUnexpectedResponse.WithPrefix("Filter")
+	} else {
+		return control.Items[values[0]], nil
+	}
+}
+
+func (this *dac) SetFilter(name string) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	control, err := this.control(this.filter())
+	if err != nil {
+		return err
+	}
+	for i, item := range control.Items {
+		if item == name {
+			values := make([]int64, control.Count)
+			for j := range values {
+				values[j] = int64(i)
+			}
+			return linux.ALSASetControlValues(this.fh.Fd(), control, values)
+		}
+	}
+	return gopi.ErrBadParameter.WithPrefix("SetFilter: ", name)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *dac) volume() string {
+	if this.preset == nil {
+		return ""
+	} else {
+		return this.preset.Volume
+	}
+}
+
+func (this *dac) filter() string {
+	if this.preset == nil {
+		return ""
+	} else {
+		return this.preset.Filter
+	}
+}
+
+// control returns a mixer control by name
+func (this *dac) control(name string) (linux.ALSAControl, error) {
+	if this.preset == nil || this.fh == nil {
+		return linux.ALSAControl{}, gopi.ErrNotFound.WithPrefix("DAC")
+	} else if name == "" {
+		return linux.ALSAControl{}, gopi.ErrNotImplemented.WithPrefix(this.preset.Name)
+	}
+	controls, err := linux.ALSAControls(this.fh.Fd())
+	if err != nil {
+		return linux.ALSAControl{}, err
+	}
+	for _, control := range controls {
+		if control.Name == name {
+			return control, nil
+		}
+	}
+	return linux.ALSAControl{}, gopi.ErrNotFound.WithPrefix(this.preset.Name, ": ", name)
+}
+
+// readHat returns a property from the HAT EEPROM, or empty string
+func readHat(name string) string {
+	if data, err := ioutil.ReadFile(filepath.Join(hatPath, name)); err != nil {
+		return ""
+	} else {
+		return string(bytes.TrimRight(data, "\x00\n"))
+	}
+}
@@ -0,0 +1,40 @@
+// +build !linux
+
+package dac
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *dac) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *dac) Volume() (float32, error) {
+	return 0, gopi.ErrNotImplemented
+}
+
+func (this *dac) SetVolume(float32) error {
+	return gopi.ErrNotImplemented
+}
+
+func (this *dac) Filters() []string {
+	return nil
+}
+
+func (this *dac) Filter() (string, error) {
+	return "", gopi.ErrNotImplemented
+}
+
+func (this *dac) SetFilter(string) error {
+	return gopi.ErrNotImplemented
+}
@@ -0,0 +1,39 @@
+package dac_test
+
+import (
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.AudioDAC
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_DAC_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.AudioDAC == nil {
+			t.Error("nil AudioDAC unit")
+		} else {
+			t.Log(app.AudioDAC)
+		}
+	})
+}
+
+func Test_DAC_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.AudioDAC.Name() != "" {
+			t.Log("DAC detected:", app.AudioDAC.Name())
+		} else if _, err := app.AudioDAC.Volume(); err == nil {
+			t.Error("Expected error when no DAC detected")
+		}
+	})
+}
@@ -0,0 +1,4 @@
+// DAC package detects I2S digital audio HATs such as the HiFiBerry
+// and IQaudIO boards, and exposes their hardware volume and digital
+// filter mixer controls
+package dac
@@ -0,0 +1,13 @@
+package dac
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register dac
+	graph.RegisterUnit(reflect.TypeOf(&dac{}), reflect.TypeOf((*gopi.AudioDAC)(nil)))
+}
@@ -0,0 +1,83 @@
+package dac
+
+import (
+	"strings"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// preset describes a DAC HAT, the device tree overlay which loads it
+// and the names of its mixer controls
+type preset struct {
+	Name    string
+	Overlay string
+	Product []string // HAT EEPROM product name prefixes
+	Card    []string // ALSA card driver names
+	Volume  string   // Hardware volume control, or empty
+	Filter  string   // Digital filter control, or empty
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	presets = []preset{
+		{"hifiberry-dac", "hifiberry-dac", []string{"HiFiBerry DAC"}, []string{"snd_rpi_hifiberry_dac"}, "", ""},
+		{"hifiberry-dacplus", "hifiberry-dacplus", []string{"HiFiBerry DAC+"}, []string{"snd_rpi_hifiberry_dacplus"}, "Digital Playback Volume", "DSP Program"},
+		{"hifiberry-dacplusadc", "hifiberry-dacplusadc", []string{"HiFiBerry DAC+ADC"}, []string{"snd_rpi_hifiberry_dacplusadc", "snd_rpi_hifiberry_dacplusadcpro"}, "Digital Playback Volume", "DSP Program"},
+		{"hifiberry-digi", "hifiberry-digi", []string{"HiFiBerry Digi"}, []string{"snd_rpi_hifiberry_digi"}, "", ""},
+		{"hifiberry-amp", "hifiberry-amp", []string{"HiFiBerry Amp"}, []string{"snd_rpi_hifiberry_amp"}, "Master Playback Volume", ""},
+		{"iqaudio-dacplus", "iqaudio-dacplus", []string{"Pi-DAC+", "Pi-DAC Pro", "Pi-DigiAMP+"}, []string{"snd_rpi_iqaudio_dac", "IQaudIODAC"}, "Digital Playback Volume", "DSP Program"},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// presetWithName returns a preset by name, or nil
+func presetWithName(name string) *preset {
+	for i := range presets {
+		if strings.EqualFold(presets[i].Name, name) {
+			return &presets[i]
+		}
+	}
+	return nil
+}
+
+// presetWithProduct returns the preset with the longest product
+// prefix matching a HAT product name, or nil
+func presetWithProduct(product string) *preset {
+	var result *preset
+	var match string
+	for i := range presets {
+		for _, prefix := range presets[i].Product {
+			if strings.HasPrefix(product, prefix) && len(prefix) > len(match) {
+				result, match = &presets[i], prefix
+			}
+		}
+	}
+	return result
+}
+
+// presetWithCard returns the preset which matches any of the names
+// of a sound card, or nil
+func presetWithCard(names ...string) *preset {
+	for i := range presets {
+		if presets[i].matchCard(names...) {
+			return &presets[i]
+		}
+	}
+	return nil
+}
+
+func (this *preset) matchCard(names ...string) bool {
+	for _, card := range this.Card {
+		for _, name := range names {
+			if strings.EqualFold(card, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package dac
+
+import (
+	"testing"
+)
+
+func Test_Preset_001(t *testing.T) {
+	tests := []struct {
+		product, name string
+	}{
+		{"HiFiBerry DAC", "hifiberry-dac"},
+		{"HiFiBerry DAC+ Pro", "hifiberry-dacplus"},
+		{"HiFiBerry DAC+ADC Pro", "hifiberry-dacplusadc"},
+		{"HiFiBerry Digi+", "hifiberry-digi"},
+		{"Pi-DAC Pro", "iqaudio-dacplus"},
+		{"Sense HAT", ""},
+	}
+	for _, test := range tests {
+		if preset := presetWithProduct(test.product); preset == nil && test.name != "" {
+			t.Errorf("%q: expected %q", test.product, test.name)
+		} else if preset != nil && preset.Name != test.name {
+			t.Errorf("%q: expected %q, got %q", test.product, test.name, preset.Name)
+		}
+	}
+}
+
+func Test_Preset_002(t *testing.T) {
+	if preset := presetWithCard("RPi-simple", "snd_rpi_hifiberry_dac"); preset == nil || preset.Name != "hifiberry-dac" {
+		t.Error("Unexpected preset", preset)
+	}
+	if preset := presetWithCard("IQaudIODAC"); preset == nil || preset.Name != "iqaudio-dacplus" {
+		t.Error("Unexpected preset", preset)
+	}
+	if preset := presetWithCard("bcm2835 Headphones"); preset != nil {
+		t.Error("Unexpected preset", preset)
+	}
+}
+
+func Test_Preset_003(t *testing.T) {
+	for _, preset := range presets {
+		if presetWithName(preset.Name) == nil {
+			t.Error("Missing preset", preset.Name)
+		}
+	}
+	if presetWithName("") != nil {
+		t.Error("Unexpected preset for empty name")
+	}
+}
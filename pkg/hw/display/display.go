@@ -20,8 +20,17 @@ type display struct {
 	rpi.DXDisplayId
 	rpi.TVDisplayInfo
 	rpi.DXDisplayHandle
+
+	edid gopi.DisplayEDID
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	mmPerInch = 25.4
+)
+
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
@@ -34,10 +43,8 @@ func NewDisplay(id rpi.DXDisplayId) (*display, error) {
 		this.DXDisplayId = id
 	}
 
-	if info, err := rpi.VCHI_TVGetDisplayInfo(id); err != nil {
+	if err := this.refresh(); err != nil {
 		return nil, err
-	} else {
-		this.TVDisplayInfo = info
 	}
 
 	return this, nil
@@ -116,8 +123,40 @@ func (this *display) Size() (uint32, uint32) {
 	}
 }
 
+// PixelsPerInch is calculated from the physical width reported
+// in the EDID and the display width
 func (this *display) PixelsPerInch() uint32 {
-	return 0
+	edid, err := this.EDID()
+	if err != nil {
+		return 0
+	}
+	w, _ := this.Size()
+	if mm, _ := edid.PhysicalSize(); mm == 0 || w == 0 {
+		return 0
+	} else {
+		return uint32(float64(w) * mmPerInch / float64(mm))
+	}
+}
+
+// EDID reads and parses the identification data from the display,
+// which is cached until the display is re-attached
+func (this *display) EDID() (gopi.DisplayEDID, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.edid != nil {
+		return this.edid, nil
+	}
+	if data, err := rpi.VCHI_TVGetEDID(this.DXDisplayId); err != nil {
+		return nil, gopi.ErrNotFound.WithPrefix("EDID: ", err)
+	} else if edid, err := NewEDID(data); err != nil {
+		return nil, err
+	} else {
+		this.edid = edid
+	}
+
+	// Return success
+	return this.edid, nil
 }
 
 func (this *display) Vendor() string {
@@ -128,6 +167,26 @@ func (this *display) Serial() string {
 	return fmt.Sprint(this.TVDisplayInfo.Serial())
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// refresh reads display information and clears the cached EDID,
+// which is called when a display is attached
+func (this *display) refresh() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if info, err := rpi.VCHI_TVGetDisplayInfo(this.DXDisplayId); err != nil {
+		return err
+	} else {
+		this.TVDisplayInfo = info
+		this.edid = nil
+	}
+
+	// Return success
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
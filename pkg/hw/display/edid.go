@@ -0,0 +1,180 @@
+package display
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type edid struct {
+	vendor  string
+	product uint16
+	serial  string
+	name    string
+	w, h    uint32
+	modes   []gopi.DisplayMode
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	EDIDBlockSize = 128
+)
+
+var (
+	edidHeader = []byte{0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// NewEDID parses the base block of Extended Display Identification Data
+// and returns an error if the data is invalid
+func NewEDID(data []byte) (gopi.DisplayEDID, error) {
+	this := new(edid)
+
+	// Check header and checksum
+	if len(data) < EDIDBlockSize {
+		return nil, gopi.ErrBadParameter.WithPrefix("NewEDID")
+	} else if bytes.Equal(data[0:8], edidHeader) == false {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("NewEDID: header")
+	} else if checksum(data[0:EDIDBlockSize]) != 0 {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("NewEDID: checksum")
+	}
+
+	// Manufacturer is three five-bit letters
+	mfr := binary.BigEndian.Uint16(data[8:10])
+	this.vendor = string([]byte{
+		byte('A' - 1 + (mfr>>10)&0x1F),
+		byte('A' - 1 + (mfr>>5)&0x1F),
+		byte('A' - 1 + mfr&0x1F),
+	})
+	this.product = binary.LittleEndian.Uint16(data[10:12])
+	if serial := binary.LittleEndian.Uint32(data[12:16]); serial != 0 {
+		this.serial = fmt.Sprint(serial)
+	}
+
+	// Physical size is in centimetres, which can be overridden by the
+	// size of the preferred mode
+	this.w, this.h = uint32(data[21])*10, uint32(data[22])*10
+
+	// Descriptors
+	for i := 54; i < 126; i += 18 {
+		desc := data[i : i+18]
+		if desc[0] != 0 || desc[1] != 0 {
+			mode, w, h := parseTiming(desc)
+			if len(this.modes) == 0 && w != 0 && h != 0 {
+				this.w, this.h = w, h
+			}
+			this.modes = append(this.modes, mode)
+			continue
+		}
+		switch desc[3] {
+		case 0xFC:
+			this.name = parseText(desc[5:])
+		case 0xFF:
+			this.serial = parseText(desc[5:])
+		}
+	}
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *edid) Vendor() string {
+	return this.vendor
+}
+
+func (this *edid) Product() uint16 {
+	return this.product
+}
+
+func (this *edid) Name() string {
+	return this.name
+}
+
+func (this *edid) Serial() string {
+	return this.serial
+}
+
+func (this *edid) PhysicalSize() (uint32, uint32) {
+	return this.w, this.h
+}
+
+func (this *edid) Modes() []gopi.DisplayMode {
+	return this.modes
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *edid) String() string {
+	str := "<edid"
+	str += " vendor=" + strconv.Quote(this.vendor)
+	str += fmt.Sprintf(" product=0x%04X", this.product)
+	if this.name != "" {
+		str += " name=" + strconv.Quote(this.name)
+	}
+	if this.serial != "" {
+		str += " serial=" + strconv.Quote(this.serial)
+	}
+	if this.w != 0 && this.h != 0 {
+		str += fmt.Sprintf(" size={%vmm,%vmm}", this.w, this.h)
+	}
+	if len(this.modes) > 0 {
+		str += fmt.Sprint(" modes=", this.modes)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func checksum(data []byte) byte {
+	sum := byte(0)
+	for _, b := range data {
+		sum += b
+	}
+	return sum
+}
+
+// parseTiming returns the mode and physical size in millimetres
+// from a detailed timing descriptor
+func parseTiming(desc []byte) (gopi.DisplayMode, uint32, uint32) {
+	clock := float32(binary.LittleEndian.Uint16(desc[0:2])) * 10000
+	hactive := uint32(desc[2]) | uint32(desc[4]&0xF0)<<4
+	hblank := uint32(desc[3]) | uint32(desc[4]&0x0F)<<8
+	vactive := uint32(desc[5]) | uint32(desc[7]&0xF0)<<4
+	vblank := uint32(desc[6]) | uint32(desc[7]&0x0F)<<8
+	w := uint32(desc[12]) | uint32(desc[14]&0xF0)<<4
+	h := uint32(desc[13]) | uint32(desc[14]&0x0F)<<8
+
+	mode := gopi.DisplayMode{W: hactive, H: vactive, Interlaced: desc[17]&0x80 != 0}
+	if total := (hactive + hblank) * (vactive + vblank); total != 0 {
+		mode.Hz = clock / float32(total)
+	}
+	if mode.Interlaced {
+		mode.H *= 2
+		mode.Hz *= 2
+	}
+	return mode, w, h
+}
+
+// parseText returns the text of a display descriptor
+func parseText(data []byte) string {
+	if i := bytes.IndexByte(data, 0x0A); i >= 0 {
+		data = data[:i]
+	}
+	return strings.TrimSpace(string(data))
+}
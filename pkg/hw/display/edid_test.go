@@ -0,0 +1,81 @@
+package display_test
+
+import (
+	"testing"
+
+	"github.com/djthorpe/gopi/v3/pkg/hw/display"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_EDID_001(t *testing.T) {
+	if _, err := display.NewEDID(nil); err == nil {
+		t.Error("Expected error for empty EDID")
+	}
+	if _, err := display.NewEDID(make([]byte, display.EDIDBlockSize)); err == nil {
+		t.Error("Expected error for bad header")
+	}
+}
+
+func Test_EDID_002(t *testing.T) {
+	data := testEDID()
+	data[20]++
+	if _, err := display.NewEDID(data); err == nil {
+		t.Error("Expected error for bad checksum")
+	}
+}
+
+func Test_EDID_003(t *testing.T) {
+	edid, err := display.NewEDID(testEDID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edid.Vendor() != "SAM" {
+		t.Error("Unexpected vendor", edid.Vendor())
+	}
+	if edid.Product() != 0x0F67 {
+		t.Error("Unexpected product", edid.Product())
+	}
+	if edid.Name() != "SAMSUNG" {
+		t.Error("Unexpected name", edid.Name())
+	}
+	if w, h := edid.PhysicalSize(); w != 1600 || h != 900 {
+		t.Error("Unexpected physical size", w, h)
+	}
+	if modes := edid.Modes(); len(modes) != 1 {
+		t.Error("Unexpected modes", modes)
+	} else if modes[0].W != 1920 || modes[0].H != 1080 || modes[0].Interlaced {
+		t.Error("Unexpected mode", modes[0])
+	} else if modes[0].Hz < 59.9 || modes[0].Hz > 60.1 {
+		t.Error("Unexpected refresh rate", modes[0].Hz)
+	}
+	t.Log(edid)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// testEDID returns an EDID block with a 1080p60 preferred mode
+// and monitor name descriptor
+func testEDID() []byte {
+	data := make([]byte, display.EDIDBlockSize)
+	copy(data, []byte{0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00})
+	copy(data[8:], []byte{0x4C, 0x2D, 0x67, 0x0F, 0x01, 0x00, 0x00, 0x00})
+	data[18], data[19] = 1, 3
+	data[21], data[22] = 160, 90
+
+	// 1920x1080 at 148.5MHz with 280 and 45 blanking, 1600x900mm
+	copy(data[54:], []byte{0x02, 0x3A, 0x80, 0x18, 0x71, 0x38, 0x2D, 0x40, 0x58, 0x2C, 0x45, 0x00, 0x40, 0x84, 0x63, 0x00, 0x00, 0x1E})
+
+	// Monitor name
+	copy(data[72:], []byte{0x00, 0x00, 0x00, 0xFC, 0x00, 'S', 'A', 'M', 'S', 'U', 'N', 'G', 0x0A, 0x20, 0x20, 0x20, 0x20, 0x20})
+
+	// Checksum
+	sum := byte(0)
+	for _, b := range data[:display.EDIDBlockSize-1] {
+		sum += b
+	}
+	data[display.EDIDBlockSize-1] = byte(0x100 - uint(sum))
+	return data
+}
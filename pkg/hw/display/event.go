@@ -14,18 +14,35 @@ type event struct {
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
-func NewEvent(display gopi.Display, flags gopi.DisplayFlag) *event {
+func NewEvent(display gopi.Display, flags gopi.DisplayFlag) gopi.DisplayEvent {
 	return &event{display, flags}
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
 func (this *event) Name() string {
+	if this.display == nil {
+		return ""
+	}
 	return this.display.Name()
 }
 
+func (this *event) Display() gopi.Display {
+	return this.display
+}
+
+func (this *event) Flags() gopi.DisplayFlag {
+	return this.flags
+}
+
 func (this *event) Value() interface{} {
 	return this.flags
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
 func (this *event) String() string {
 	str := "<display.event"
 	if this.display != nil {
@@ -49,17 +49,22 @@ func (this *Manager) New(gopi.Config) error {
 
 	// Events callback
 	rpi.VCTV_RegisterCallback(func(evt rpi.TVDisplayStateFlag, id rpi.DXDisplayId) {
-		var display gopi.Display
-		if id != 0 {
-			display, _ = this.Display(uint32(id))
+		if this.Publisher == nil {
+			return
 		}
-		if this.Publisher != nil {
-			switch evt {
-			case rpi.TV_STATE_HDMI_ATTACHED:
+		switch evt {
+		case rpi.TV_STATE_HDMI_ATTACHED:
+			// The display is not provided on attach, so emit an event
+			// for each attached display with refreshed information
+			for _, display := range this.attached() {
 				this.Publisher.Emit(NewEvent(display, gopi.DISPLAY_FLAG_ATTACHED), true)
-			case rpi.TV_STATE_HDMI_UNPLUGGED:
-				this.Publisher.Emit(NewEvent(display, gopi.DISPLAY_FLAG_UNPLUGGED), true)
 			}
+		case rpi.TV_STATE_HDMI_UNPLUGGED:
+			var display gopi.Display
+			if id != 0 {
+				display, _ = this.Display(uint32(id))
+			}
+			this.Publisher.Emit(NewEvent(display, gopi.DISPLAY_FLAG_UNPLUGGED), true)
 		}
 	})
 
@@ -153,6 +158,20 @@ func (this *Manager) PowerOff(display gopi.Display) error {
 	return rpi.VCHI_TVPowerOff(rpi.DXDisplayId(display.Id()))
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// attached returns attached displays after refreshing display information
+func (this *Manager) attached() []gopi.Display {
+	displays := this.Displays()
+	for _, d := range displays {
+		if err := d.(*display).refresh(); err != nil {
+			this.Debug("Attached: ", d.Id(), err)
+		}
+	}
+	return displays
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -0,0 +1,86 @@
+package gpio
+
+import (
+	"github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Tx reads and writes pins, and records the mode and state of each pin
+// before it is first changed so that changes can be rolled back
+type Tx struct {
+	gopi.GPIOTx
+
+	changes []change
+	modes   map[gopi.GPIOPin]bool
+	states  map[gopi.GPIOPin]bool
+}
+
+// change is the mode or state of a pin before it was changed
+type change struct {
+	pin     gopi.GPIOPin
+	setmode bool
+	mode    gopi.GPIOMode
+	state   gopi.GPIOState
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// NewTx returns a transaction which reads and writes pins through tx,
+// which should be used with the GPIO locked
+func NewTx(tx gopi.GPIOTx) *Tx {
+	return &Tx{
+		GPIOTx: tx,
+		modes:  make(map[gopi.GPIOPin]bool),
+		states: make(map[gopi.GPIOPin]bool),
+	}
+}
+
+// Batch calls a function with a transaction, and rolls back changes
+// when the function returns an error
+func Batch(tx gopi.GPIOTx, fn func(gopi.GPIOTx) error) error {
+	this := NewTx(tx)
+	if err := fn(this); err != nil {
+		this.Rollback()
+		return err
+	} else {
+		return nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *Tx) WritePin(pin gopi.GPIOPin, state gopi.GPIOState) {
+	if this.states[pin] == false {
+		this.changes = append(this.changes, change{pin: pin, state: this.GPIOTx.ReadPin(pin)})
+		this.states[pin] = true
+	}
+	this.GPIOTx.WritePin(pin, state)
+}
+
+func (this *Tx) SetPinMode(pin gopi.GPIOPin, mode gopi.GPIOMode) {
+	if this.modes[pin] == false {
+		this.changes = append(this.changes, change{pin: pin, setmode: true, mode: this.GPIOTx.GetPinMode(pin)})
+		this.modes[pin] = true
+	}
+	this.GPIOTx.SetPinMode(pin, mode)
+}
+
+// Rollback restores the modes and states of pins in the reverse order
+// they were changed. Pull modes cannot be read and are not restored
+func (this *Tx) Rollback() {
+	for i := len(this.changes) - 1; i >= 0; i-- {
+		change := this.changes[i]
+		if change.setmode {
+			this.GPIOTx.SetPinMode(change.pin, change.mode)
+		} else {
+			this.GPIOTx.WritePin(change.pin, change.state)
+		}
+	}
+	this.changes = nil
+	this.modes = make(map[gopi.GPIOPin]bool)
+	this.states = make(map[gopi.GPIOPin]bool)
+}
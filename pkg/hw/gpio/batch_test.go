@@ -0,0 +1,66 @@
+package gpio_test
+
+import (
+	"errors"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	gpio "github.com/djthorpe/gopi/v3/pkg/hw/gpio"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// pins records the mode and state of each pin
+type pins struct {
+	modes  map[gopi.GPIOPin]gopi.GPIOMode
+	states map[gopi.GPIOPin]gopi.GPIOState
+}
+
+func (this *pins) ReadPin(pin gopi.GPIOPin) gopi.GPIOState       { return this.states[pin] }
+func (this *pins) WritePin(pin gopi.GPIOPin, s gopi.GPIOState)   { this.states[pin] = s }
+func (this *pins) GetPinMode(pin gopi.GPIOPin) gopi.GPIOMode     { return this.modes[pin] }
+func (this *pins) SetPinMode(pin gopi.GPIOPin, m gopi.GPIOMode)  { this.modes[pin] = m }
+func (this *pins) SetPullMode(gopi.GPIOPin, gopi.GPIOPull) error { return gopi.ErrNotImplemented }
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Batch_001(t *testing.T) {
+	tx := &pins{
+		modes:  map[gopi.GPIOPin]gopi.GPIOMode{17: gopi.GPIO_INPUT},
+		states: map[gopi.GPIOPin]gopi.GPIOState{17: gopi.GPIO_LOW},
+	}
+	if err := gpio.Batch(tx, func(tx gopi.GPIOTx) error {
+		tx.SetPinMode(17, gopi.GPIO_OUTPUT)
+		tx.WritePin(17, gopi.GPIO_HIGH)
+		return nil
+	}); err != nil {
+		t.Error(err)
+	} else if tx.modes[17] != gopi.GPIO_OUTPUT || tx.states[17] != gopi.GPIO_HIGH {
+		t.Error("Unexpected pin", tx.modes[17], tx.states[17])
+	}
+}
+
+func Test_Batch_002(t *testing.T) {
+	tx := &pins{
+		modes:  map[gopi.GPIOPin]gopi.GPIOMode{17: gopi.GPIO_INPUT, 27: gopi.GPIO_OUTPUT},
+		states: map[gopi.GPIOPin]gopi.GPIOState{17: gopi.GPIO_LOW, 27: gopi.GPIO_HIGH},
+	}
+	failed := errors.New("Failed")
+	if err := gpio.Batch(tx, func(tx gopi.GPIOTx) error {
+		tx.SetPinMode(17, gopi.GPIO_OUTPUT)
+		tx.WritePin(17, gopi.GPIO_HIGH)
+		tx.WritePin(17, gopi.GPIO_LOW)
+		tx.WritePin(27, gopi.GPIO_LOW)
+		return failed
+	}); err != failed {
+		t.Error("Unexpected error", err)
+	}
+	if tx.modes[17] != gopi.GPIO_INPUT || tx.states[17] != gopi.GPIO_LOW {
+		t.Error("Expected GPIO17 restored", tx.modes[17], tx.states[17])
+	}
+	if tx.modes[27] != gopi.GPIO_OUTPUT || tx.states[27] != gopi.GPIO_HIGH {
+		t.Error("Expected GPIO27 restored", tx.modes[27], tx.states[27])
+	}
+}
@@ -66,3 +66,7 @@ func (this *GPIO) SetPullMode(logical gopi.GPIOPin, pull gopi.GPIOPull) error {
 func (this *GPIO) Watch(gopi.GPIOPin, gopi.GPIOEdge) error {
 	return gopi.ErrNotImplemented
 }
+
+func (this *GPIO) Batch(func(gopi.GPIOTx) error) error {
+	return gopi.ErrNotImplemented
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
@@ -31,6 +32,11 @@ type GPIO struct {
 	watch   map[gopi.GPIOPin]gopi.GPIOState // current pin state
 }
 
+// tx reads and writes pins with the GPIO locked
+type tx struct {
+	*GPIO
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // CONSTANTS
 
@@ -189,6 +195,9 @@ func (this *GPIO) Run(ctx context.Context) error {
 
 func (this *GPIO) String() string {
 	str := "<gpio.broadcom"
+	if name := this.InstanceName(); name != "" {
+		str += " instance=" + strconv.Quote(name)
+	}
 	if p := this.NumberOfPhysicalPins(); p > 0 {
 		str += " number_of_physical_pins=" + fmt.Sprint(p)
 	}
@@ -265,7 +274,117 @@ func (this *GPIO) PhysicalPinForPin(logical gopi.GPIOPin) uint {
 func (this *GPIO) ReadPin(logical gopi.GPIOPin) gopi.GPIOState {
 	this.RWMutex.RLock()
 	defer this.RWMutex.RUnlock()
+	return tx{this}.ReadPin(logical)
+}
+
+// Write pin state
+func (this *GPIO) WritePin(logical gopi.GPIOPin, state gopi.GPIOState) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	tx{this}.WritePin(logical, state)
+}
+
+// Get pin mode
+func (this *GPIO) GetPinMode(logical gopi.GPIOPin) gopi.GPIOMode {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return tx{this}.GetPinMode(logical)
+}
+
+// Set pin mode
+func (this *GPIO) SetPinMode(logical gopi.GPIOPin, mode gopi.GPIOMode) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	tx{this}.SetPinMode(logical, mode)
+}
+
+// Set pull mode to pull down or pull up - will
+// return ErrNotImplemented if not supported
+func (this *GPIO) SetPullMode(logical gopi.GPIOPin, pull gopi.GPIOPull) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	return tx{this}.SetPullMode(logical, pull)
+}
+
+// Batch calls a function with the GPIO locked, and restores pins changed
+// in the batch when the function returns an error
+func (this *GPIO) Batch(fn func(gopi.GPIOTx) error) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	return gpio.Batch(tx{this}, fn)
+}
 
+// Start watching for rising and/or falling edge,
+// or stop watching when GPIO_EDGE_NONE is passed.
+// Will return ErrNotImplemented if not supported
+func (this *GPIO) Watch(pin gopi.GPIOPin, edge gopi.GPIOEdge) error {
+	// Check pin mode is INPUT
+	if mode := this.GetPinMode(pin); mode != gopi.GPIO_INPUT {
+		return gopi.ErrOutOfOrder.WithPrefix("Watch", pin)
+	}
+
+	// Get existing state of pin
+	state := gopi.GPIO_LOW
+	if edge != gopi.GPIO_EDGE_NONE {
+		state = this.ReadPin(pin)
+	}
+
+	// Lock for writing
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Delete watch or set existing state
+	if edge == gopi.GPIO_EDGE_NONE {
+		delete(this.watch, pin)
+		return nil
+	} else {
+		this.watch[pin] = state
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *GPIO) changeWatchState() {
+	// Copy the watched pins, so that pins are read and events are
+	// emitted without holding the lock
+	this.RWMutex.RLock()
+	watch := make(map[gopi.GPIOPin]gopi.GPIOState, len(this.watch))
+	for pin, state := range this.watch {
+		watch[pin] = state
+	}
+	this.RWMutex.RUnlock()
+
+	for pin, state := range watch {
+		newstate := this.ReadPin(pin)
+		if newstate == state {
+			continue
+		}
+
+		// Update state unless the watch has been removed
+		this.RWMutex.Lock()
+		_, exists := this.watch[pin]
+		if exists {
+			this.watch[pin] = newstate
+		}
+		this.RWMutex.Unlock()
+
+		if exists && this.Publisher != nil {
+			edge := gopi.GPIO_EDGE_NONE
+			if state == gopi.GPIO_LOW {
+				edge = gopi.GPIO_EDGE_RISING
+			} else {
+				edge = gopi.GPIO_EDGE_FALLING
+			}
+			this.Publisher.Emit(gpio.NewEvent(fmt.Sprint(pin), pin, edge), true)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TRANSACTIONS
+
+func (this tx) ReadPin(logical gopi.GPIOPin) gopi.GPIOState {
 	var register uint32
 	if uint8(logical) <= uint8(31) {
 		// GPIO0 - GPIO31
@@ -280,11 +399,7 @@ func (this *GPIO) ReadPin(logical gopi.GPIOPin) gopi.GPIOState {
 	return gopi.GPIO_LOW
 }
 
-// Write pin state
-func (this *GPIO) WritePin(logical gopi.GPIOPin, state gopi.GPIOState) {
-	this.RWMutex.Lock()
-	defer this.RWMutex.Unlock()
-
+func (this tx) WritePin(logical gopi.GPIOPin, state gopi.GPIOState) {
 	value := uint32(1 << (uint8(logical) & 31))
 	switch state {
 	case gopi.GPIO_LOW:
@@ -302,11 +417,7 @@ func (this *GPIO) WritePin(logical gopi.GPIOPin, state gopi.GPIOState) {
 	}
 }
 
-// Get pin mode
-func (this *GPIO) GetPinMode(logical gopi.GPIOPin) gopi.GPIOMode {
-	this.RWMutex.RLock()
-	defer this.RWMutex.RUnlock()
-
+func (this tx) GetPinMode(logical gopi.GPIOPin) gopi.GPIOMode {
 	// return the register and the number of bits to shift to
 	// access the current mode
 	register, shift := gopiPinToRegister(logical)
@@ -315,11 +426,7 @@ func (this *GPIO) GetPinMode(logical gopi.GPIOPin) gopi.GPIOMode {
 	return gopi.GPIOMode((this.mem32[register>>2] >> shift) & 7)
 }
 
-// Set pin mode
-func (this *GPIO) SetPinMode(logical gopi.GPIOPin, mode gopi.GPIOMode) {
-	this.RWMutex.Lock()
-	defer this.RWMutex.Unlock()
-
+func (this tx) SetPinMode(logical gopi.GPIOPin, mode gopi.GPIOMode) {
 	// get register and the number of bits to shift to
 	// access the current mode
 	register, shift := gopiPinToRegister(logical)
@@ -328,12 +435,7 @@ func (this *GPIO) SetPinMode(logical gopi.GPIOPin, mode gopi.GPIOMode) {
 	this.mem32[register>>2] = (this.mem32[register>>2] &^ (7 << shift)) | (uint32(mode) << shift)
 }
 
-// Set pull mode to pull down or pull up - will
-// return ErrNotImplemented if not supported
-func (this *GPIO) SetPullMode(logical gopi.GPIOPin, pull gopi.GPIOPull) error {
-	this.RWMutex.Lock()
-	defer this.RWMutex.Unlock()
-
+func (this tx) SetPullMode(logical gopi.GPIOPin, pull gopi.GPIOPull) error {
 	// Check pin to make sure there is a physical pin mapping
 	if this.PhysicalPinForPin(logical) == 0 {
 		return gopi.ErrBadParameter.WithPrefix(fmt.Sprint(logical))
@@ -375,58 +477,6 @@ func (this *GPIO) SetPullMode(logical gopi.GPIOPin, pull gopi.GPIOPull) error {
 	return nil
 }
 
-// Start watching for rising and/or falling edge,
-// or stop watching when GPIO_EDGE_NONE is passed.
-// Will return ErrNotImplemented if not supported
-func (this *GPIO) Watch(pin gopi.GPIOPin, edge gopi.GPIOEdge) error {
-	// Check pin mode is INPUT
-	if mode := this.GetPinMode(pin); mode != gopi.GPIO_INPUT {
-		return gopi.ErrOutOfOrder.WithPrefix("Watch", pin)
-	}
-
-	// Get existing state of pin
-	state := gopi.GPIO_LOW
-	if edge != gopi.GPIO_EDGE_NONE {
-		state = this.ReadPin(pin)
-	}
-
-	// Lock for writing
-	this.RWMutex.Lock()
-	defer this.RWMutex.Unlock()
-
-	// Delete watch or set existing state
-	if edge == gopi.GPIO_EDGE_NONE {
-		delete(this.watch, pin)
-		return nil
-	} else {
-		this.watch[pin] = state
-	}
-
-	// Return success
-	return nil
-}
-
-func (this *GPIO) changeWatchState() {
-	for pin, state := range this.watch {
-		if newstate := this.ReadPin(pin); newstate == state {
-			continue
-		} else {
-			this.RWMutex.Lock()
-			defer this.RWMutex.Unlock()
-			this.watch[pin] = newstate
-		}
-		if this.Publisher != nil {
-			edge := gopi.GPIO_EDGE_NONE
-			if state == gopi.GPIO_LOW {
-				edge = gopi.GPIO_EDGE_RISING
-			} else {
-				edge = gopi.GPIO_EDGE_FALLING
-			}
-			this.Publisher.Emit(gpio.NewEvent(fmt.Sprint(pin), pin, edge), true)
-		}
-	}
-}
-
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
@@ -0,0 +1,26 @@
+// Sampler package implements gopi.GPIOSampler, which samples GPIO pins
+// at a high rate like a logic analyzer, so bit-banged protocols can be
+// debugged with the same framework which drives them.
+//
+// Pins are read in a tight loop at the rate passed to Start, or the rate
+// set with the -sampler.rate flag, and the state of the pins is recorded
+// each time any pin changes. Changes are kept in a ring buffer with the
+// size set by the -sampler.buffer flag, so when sampling is stopped the
+// trace contains the most recent changes. Samples which could not be read
+// in time are counted as overruns. Set the -sampler.priority flag to sample
+// with realtime scheduling, which requires the gopi.Scheduler unit in the
+// process package.
+//
+// When building with -tags rpi the pins are read from /dev/gpiomem,
+// otherwise they are read through the gopi.GPIO unit. For example,
+//
+//	this.GPIOSampler.Start(0, 2, 3)
+//	// ...
+//	if trace, err := this.GPIOSampler.Stop(); err == nil {
+//	  trace.WriteVCD(os.Stdout)
+//	}
+//
+// Traces are written as Value Change Dump files, which can be viewed with
+// GTKWave, or as sigrok session files, which can be opened in PulseView
+// to decode protocols such as I2C, SPI and UART.
+package sampler
@@ -0,0 +1,27 @@
+// +build !rpi
+
+package sampler
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type gpiomem struct{}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *gpiomem) open() error {
+	return nil
+}
+
+func (this *gpiomem) close() error {
+	return nil
+}
+
+func (this *gpiomem) mapped() bool {
+	return false
+}
+
+func (this *gpiomem) levels() (uint64, bool) {
+	return 0, false
+}
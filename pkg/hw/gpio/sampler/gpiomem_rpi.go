@@ -0,0 +1,72 @@
+// +build rpi
+
+package sampler
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type gpiomem struct {
+	mem8  []byte
+	mem32 []uint32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	GPIO_DEV_GPIOMEM = "/dev/gpiomem"
+	GPIO_SIZE        = 4096
+	GPIO_GPLVL0      = 0x0034 >> 2 // Register to read pins GPIO0-GPIO31
+	GPIO_GPLVL1      = 0x0038 >> 2 // Register to read pins GPIO32-GPIO53
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// open maps the GPIO registers, which does not require root
+func (this *gpiomem) open() error {
+	file, err := os.OpenFile(GPIO_DEV_GPIOMEM, os.O_RDWR|os.O_SYNC, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if mem8, err := syscall.Mmap(int(file.Fd()), 0, GPIO_SIZE, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED); err != nil {
+		return os.NewSyscallError("mmap", err)
+	} else {
+		this.mem8 = mem8
+		this.mem32 = (*[GPIO_SIZE / 4]uint32)(unsafe.Pointer(&mem8[0]))[:]
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *gpiomem) close() error {
+	var result error
+	if this.mem8 != nil {
+		if err := syscall.Munmap(this.mem8); err != nil {
+			result = os.NewSyscallError("munmap", err)
+		}
+	}
+	this.mem8, this.mem32 = nil, nil
+	return result
+}
+
+func (this *gpiomem) mapped() bool {
+	return this.mem32 != nil
+}
+
+// levels returns the state of GPIO0 to GPIO53
+func (this *gpiomem) levels() (uint64, bool) {
+	if this.mem32 == nil {
+		return 0, false
+	}
+	return uint64(this.mem32[GPIO_GPLVL0]) | uint64(this.mem32[GPIO_GPLVL1])<<32, true
+}
@@ -0,0 +1,13 @@
+package sampler
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.GPIOSampler
+	graph.RegisterUnit(reflect.TypeOf(&sampler{}), reflect.TypeOf((*gopi.GPIOSampler)(nil)))
+}
@@ -0,0 +1,287 @@
+package sampler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type sampler struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.GPIO
+	gopi.Scheduler
+	sync.Mutex
+	gpiomem
+
+	rate     *uint
+	size     *uint
+	priority *uint
+
+	cancel context.CancelFunc
+	done   chan *trace
+}
+
+// ring retains the most recent changes
+type ring struct {
+	changes []change
+	next    int
+	wrapped bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Maximum number of pins which can be sampled
+	maxPins = 32
+
+	// Number of loop iterations between checking for cancellation
+	checkInterval = 0x1000
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *sampler) Define(cfg gopi.Config) error {
+	this.rate = cfg.FlagUint("sampler.rate", 1000000, "Default sample rate in Hz")
+	this.size = cfg.FlagUint("sampler.buffer", 65536, "Number of transitions retained in the ring buffer")
+	this.priority = cfg.FlagUint("sampler.priority", 0, "Realtime priority between 1 and 99 for sampling, or zero for normal scheduling")
+	return nil
+}
+
+func (this *sampler) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if *this.rate == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-sampler.rate")
+	} else if *this.size == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-sampler.buffer")
+	} else if *this.priority > 99 {
+		return gopi.ErrBadParameter.WithPrefix("-sampler.priority")
+	} else if *this.priority > 0 && this.Scheduler == nil {
+		return gopi.ErrInternalAppError.WithPrefix("Missing gopi.Scheduler for -sampler.priority")
+	}
+
+	// Read pins directly from memory, or else through gopi.GPIO
+	if err := this.open(); err != nil {
+		if this.GPIO == nil {
+			return err
+		}
+		this.Debug("Sampler: ", err)
+	} else if this.mapped() == false && this.GPIO == nil {
+		return gopi.ErrInternalAppError.WithPrefix("Missing gopi.GPIO")
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *sampler) Dispose() error {
+	var result error
+
+	// Stop sampling
+	if this.Sampling() {
+		if _, err := this.Stop(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if err := this.close(); err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *sampler) Start(rate uint, pins ...gopi.GPIOPin) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Check parameters
+	if this.cancel != nil {
+		return gopi.ErrOutOfOrder.WithPrefix("Start")
+	} else if len(pins) == 0 || len(pins) > maxPins {
+		return gopi.ErrBadParameter.WithPrefix("Start")
+	} else if rate == 0 {
+		rate = *this.rate
+	}
+	for i, pin := range pins {
+		if pin >= gopi.GPIO_PIN_NONE {
+			return gopi.ErrBadParameter.WithPrefix("Start: ", pin)
+		}
+		for _, other := range pins[:i] {
+			if other == pin {
+				return gopi.ErrDuplicateEntry.WithPrefix("Start: ", pin)
+			}
+		}
+	}
+
+	// Sample in the background
+	ctx, cancel := context.WithCancel(context.Background())
+	this.cancel = cancel
+	this.done = make(chan *trace, 1)
+	go this.sample(ctx, append([]gopi.GPIOPin{}, pins...), rate, this.done)
+
+	// Return success
+	return nil
+}
+
+func (this *sampler) Stop() (gopi.GPIOTrace, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.cancel == nil {
+		return nil, gopi.ErrOutOfOrder.WithPrefix("Stop")
+	}
+
+	// Wait for sampling to end
+	this.cancel()
+	trace := <-this.done
+	this.cancel, this.done = nil, nil
+
+	// Return success
+	return trace, nil
+}
+
+func (this *sampler) Sampling() bool {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.cancel != nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *sampler) String() string {
+	str := "<sampler"
+	str += fmt.Sprint(" rate=", samplerate(*this.rate))
+	str += fmt.Sprint(" buffer=", *this.size)
+	if this.mapped() {
+		str += " gpiomem"
+	}
+	if this.Sampling() {
+		str += " sampling"
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// sample reads pins in a tight loop at a rate until cancelled, and records
+// changes in a ring buffer
+func (this *sampler) sample(ctx context.Context, pins []gopi.GPIOPin, rate uint, done chan<- *trace) {
+	if *this.priority > 0 {
+		flags, restore := this.Scheduler.Realtime(int(*this.priority))
+		defer restore()
+		this.Debug("Sampler: Scheduling ", flags)
+	}
+
+	buffer := newRing(*this.size)
+	period := time.Second / time.Duration(rate)
+	if period == 0 {
+		period = 1
+	}
+	overruns := uint(0)
+	start := time.Now()
+	initial := this.read(pins)
+	last, next := initial, period
+
+FOR_LOOP:
+	for i := 0; ; i++ {
+		// Check for cancellation
+		if i%checkInterval == 0 {
+			select {
+			case <-ctx.Done():
+				break FOR_LOOP
+			default:
+			}
+		}
+
+		// Wait for the next sample, and count missed samples
+		now := time.Since(start)
+		if now < next {
+			continue
+		} else if missed := (now - next) / period; missed > 0 {
+			overruns += uint(missed)
+			next += missed * period
+		}
+
+		// Record change
+		if levels := this.read(pins); levels != last {
+			buffer.push(change{next, levels})
+			last = levels
+		}
+		next += period
+	}
+
+	done <- buffer.trace(pins, rate, start, initial, next-period, overruns)
+}
+
+// read returns the state of pins, where bit n is the state of the nth pin
+func (this *sampler) read(pins []gopi.GPIOPin) uint32 {
+	result := uint32(0)
+	if bank, ok := this.levels(); ok {
+		for i, pin := range pins {
+			if bank&(1<<uint(pin)) != 0 {
+				result |= 1 << uint(i)
+			}
+		}
+	} else {
+		for i, pin := range pins {
+			if this.GPIO.ReadPin(pin) == gopi.GPIO_HIGH {
+				result |= 1 << uint(i)
+			}
+		}
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RING BUFFER
+
+func newRing(size uint) *ring {
+	return &ring{changes: make([]change, size)}
+}
+
+func (this *ring) push(c change) {
+	this.changes[this.next] = c
+	if this.next++; this.next == len(this.changes) {
+		this.next, this.wrapped = 0, true
+	}
+}
+
+// trace returns the changes in order. When changes have been overwritten,
+// the trace starts at the oldest change
+func (this *ring) trace(pins []gopi.GPIOPin, rate uint, start time.Time, initial uint32, end time.Duration, overruns uint) *trace {
+	changes := append([]change{}, this.changes[:this.next]...)
+	offset := time.Duration(0)
+	if this.wrapped {
+		changes = append(append([]change{}, this.changes[this.next:]...), changes...)
+		offset, initial = changes[0].t, changes[0].levels
+		changes = changes[1:]
+		for i := range changes {
+			changes[i].t -= offset
+		}
+	}
+	if end < offset {
+		end = offset
+	}
+	return &trace{pins, rate, start.Add(offset), end - offset, initial, changes, overruns}
+}
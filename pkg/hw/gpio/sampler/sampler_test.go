@@ -0,0 +1,83 @@
+//go:build !rpi
+// +build !rpi
+
+package sampler_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/gpio/sampler"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.GPIOSampler
+	gopi.GPIO
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&testutil.GPIO{}), reflect.TypeOf((*gopi.GPIO)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Sampler_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if err := app.GPIOSampler.Start(10000, 17, 18); err != nil {
+			t.Error(err)
+			return
+		} else if app.GPIOSampler.Sampling() == false {
+			t.Error("Expected sampling")
+		} else if err := app.GPIOSampler.Start(0, 4); errors.Is(err, gopi.ErrOutOfOrder) == false {
+			t.Error("Unexpected error from Start", err)
+		}
+
+		// Pulse GPIO17 high for 20ms
+		time.Sleep(20 * time.Millisecond)
+		app.GPIO.WritePin(17, gopi.GPIO_HIGH)
+		time.Sleep(20 * time.Millisecond)
+		app.GPIO.WritePin(17, gopi.GPIO_LOW)
+		time.Sleep(20 * time.Millisecond)
+
+		trace, err := app.GPIOSampler.Stop()
+		if err != nil {
+			t.Error(err)
+		} else if trace.Rate() != 10000 || trace.Transitions() != 2 {
+			t.Error("Unexpected trace", trace)
+		} else if trace.Duration() < 50*time.Millisecond {
+			t.Error("Unexpected duration", trace.Duration())
+		} else if trace.State(17, 0) != gopi.GPIO_LOW || trace.State(17, trace.Duration()) != gopi.GPIO_LOW {
+			t.Error("Unexpected state", trace)
+		} else {
+			t.Log(app.GPIOSampler, trace)
+		}
+		if _, err := app.GPIOSampler.Stop(); errors.Is(err, gopi.ErrOutOfOrder) == false {
+			t.Error("Unexpected error from Stop", err)
+		}
+	})
+}
+
+func Test_Sampler_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if err := app.GPIOSampler.Start(0, 17, 17); errors.Is(err, gopi.ErrDuplicateEntry) == false {
+			t.Error("Unexpected error from Start", err)
+		} else if err := app.GPIOSampler.Start(0); errors.Is(err, gopi.ErrBadParameter) == false {
+			t.Error("Unexpected error from Start", err)
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package sampler
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type trace struct {
+	pins     []gopi.GPIOPin
+	rate     uint
+	start    time.Time
+	duration time.Duration
+	initial  uint32
+	changes  []change
+	overruns uint
+}
+
+// change records the state of all pins when any pin changes, where bit n
+// of levels is the state of the nth pin
+type change struct {
+	t      time.Duration
+	levels uint32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// First identifier character in a value change dump
+	vcdIdentifier = '!'
+
+	// Version of sigrok session files
+	sigrokVersion = "2"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *trace) Pins() []gopi.GPIOPin {
+	return this.pins
+}
+
+func (this *trace) Rate() uint {
+	return this.rate
+}
+
+func (this *trace) Duration() time.Duration {
+	return this.duration
+}
+
+func (this *trace) Transitions() int {
+	return len(this.changes)
+}
+
+func (this *trace) Overruns() uint {
+	return this.overruns
+}
+
+func (this *trace) State(pin gopi.GPIOPin, t time.Duration) gopi.GPIOState {
+	for i, p := range this.pins {
+		if p == pin {
+			if this.levels(t)&(1<<uint(i)) != 0 {
+				return gopi.GPIO_HIGH
+			} else {
+				break
+			}
+		}
+	}
+	return gopi.GPIO_LOW
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// EXPORT
+
+func (this *trace) WriteVCD(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+
+	// Header
+	fmt.Fprintf(buf, "$date %v $end\n", this.start.Format(time.RFC1123))
+	fmt.Fprintln(buf, "$version gopi $end")
+	fmt.Fprintln(buf, "$timescale 1 ns $end")
+	fmt.Fprintln(buf, "$scope module gpio $end")
+	for i, pin := range this.pins {
+		fmt.Fprintf(buf, "$var wire 1 %c %v $end\n", vcdIdentifier+i, pin)
+	}
+	fmt.Fprintln(buf, "$upscope $end")
+	fmt.Fprintln(buf, "$enddefinitions $end")
+
+	// Initial values
+	fmt.Fprintln(buf, "#0")
+	fmt.Fprintln(buf, "$dumpvars")
+	for i := range this.pins {
+		fmt.Fprintf(buf, "%d%c\n", this.initial>>uint(i)&1, vcdIdentifier+i)
+	}
+	fmt.Fprintln(buf, "$end")
+
+	// Changes
+	last := this.initial
+	for _, change := range this.changes {
+		fmt.Fprintf(buf, "#%d\n", change.t.Nanoseconds())
+		for i := range this.pins {
+			if (change.levels^last)>>uint(i)&1 != 0 {
+				fmt.Fprintf(buf, "%d%c\n", change.levels>>uint(i)&1, vcdIdentifier+i)
+			}
+		}
+		last = change.levels
+	}
+	fmt.Fprintf(buf, "#%d\n", this.duration.Nanoseconds())
+
+	return buf.Flush()
+}
+
+func (this *trace) WriteSigrok(w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	// Version
+	if fh, err := archive.Create("version"); err != nil {
+		return err
+	} else if _, err := io.WriteString(fh, sigrokVersion); err != nil {
+		return err
+	}
+
+	// Metadata
+	unitsize := (len(this.pins) + 7) / 8
+	if fh, err := archive.Create("metadata"); err != nil {
+		return err
+	} else {
+		fmt.Fprintln(fh, "[global]")
+		fmt.Fprintln(fh, "sigrok version=0.5.1")
+		fmt.Fprintln(fh)
+		fmt.Fprintln(fh, "[device 1]")
+		fmt.Fprintln(fh, "capturefile=logic-1")
+		fmt.Fprintln(fh, "total probes="+fmt.Sprint(len(this.pins)))
+		fmt.Fprintln(fh, "samplerate="+samplerate(this.rate))
+		fmt.Fprintln(fh, "total analog=0")
+		for i, pin := range this.pins {
+			fmt.Fprintf(fh, "probe%d=%v\n", i+1, pin)
+		}
+		if _, err := fmt.Fprintln(fh, "unitsize="+fmt.Sprint(unitsize)); err != nil {
+			return err
+		}
+	}
+
+	// Samples, where bit n is the state of probe n+1
+	if fh, err := archive.Create("logic-1-1"); err != nil {
+		return err
+	} else {
+		buf := bufio.NewWriter(fh)
+		levels, next := this.initial, 0
+		sample := make([]byte, unitsize)
+		for k := uint64(0); k < this.samples(); k++ {
+			t := time.Duration(k * uint64(time.Second) / uint64(this.rate))
+			for next < len(this.changes) && this.changes[next].t <= t {
+				levels = this.changes[next].levels
+				next++
+			}
+			for i := range sample {
+				sample[i] = byte(levels >> uint(i*8))
+			}
+			buf.Write(sample)
+		}
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *trace) String() string {
+	str := "<sampler.trace"
+	str += fmt.Sprint(" pins=", this.pins)
+	str += fmt.Sprint(" rate=", samplerate(this.rate))
+	str += fmt.Sprint(" duration=", this.duration)
+	str += fmt.Sprint(" transitions=", len(this.changes))
+	if this.overruns > 0 {
+		str += fmt.Sprint(" overruns=", this.overruns)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// levels returns the state of all pins at a time
+func (this *trace) levels(t time.Duration) uint32 {
+	i := sort.Search(len(this.changes), func(i int) bool {
+		return this.changes[i].t > t
+	})
+	if i == 0 {
+		return this.initial
+	} else {
+		return this.changes[i-1].levels
+	}
+}
+
+// samples returns the number of samples in the trace
+func (this *trace) samples() uint64 {
+	return uint64(this.duration) * uint64(this.rate) / uint64(time.Second)
+}
+
+// samplerate returns a rate in the form used by sigrok
+func samplerate(rate uint) string {
+	switch {
+	case rate >= 1e9 && rate%1e9 == 0:
+		return fmt.Sprint(rate/1e9, " GHz")
+	case rate >= 1e6 && rate%1e6 == 0:
+		return fmt.Sprint(rate/1e6, " MHz")
+	case rate >= 1e3 && rate%1e3 == 0:
+		return fmt.Sprint(rate/1e3, " kHz")
+	default:
+		return fmt.Sprint(rate, " Hz")
+	}
+}
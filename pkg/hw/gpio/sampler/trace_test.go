@@ -0,0 +1,118 @@
+package sampler
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Trace_001(t *testing.T) {
+	trace := newTrace()
+	if trace.State(17, 0) != gopi.GPIO_HIGH || trace.State(4, 0) != gopi.GPIO_LOW {
+		t.Error("Unexpected initial state")
+	}
+	if trace.State(17, 2*time.Millisecond) != gopi.GPIO_LOW || trace.State(4, 2*time.Millisecond) != gopi.GPIO_HIGH {
+		t.Error("Unexpected state at 2ms")
+	}
+	if trace.State(17, 9*time.Millisecond) != gopi.GPIO_HIGH || trace.State(4, 9*time.Millisecond) != gopi.GPIO_HIGH {
+		t.Error("Unexpected state at 9ms")
+	}
+	if trace.State(5, 0) != gopi.GPIO_LOW {
+		t.Error("Unexpected state for pin which is not sampled")
+	}
+	t.Log(trace)
+}
+
+func Test_Trace_002(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := newTrace().WriteVCD(buf); err != nil {
+		t.Fatal(err)
+	}
+	vcd := buf.String()
+	for _, expected := range []string{
+		"$timescale 1 ns $end\n",
+		"$var wire 1 ! GPIO17 $end\n$var wire 1 \" GPIO4 $end\n",
+		"$dumpvars\n1!\n0\"\n$end\n",
+		"#2000000\n0!\n1\"\n",
+		"#5000000\n1!\n#10000000\n",
+	} {
+		if strings.Contains(vcd, expected) == false {
+			t.Errorf("Expected %q in %q", expected, vcd)
+		}
+	}
+}
+
+func Test_Trace_003(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := newTrace().WriteSigrok(buf); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, file := range archive.File {
+		if fh, err := file.Open(); err != nil {
+			t.Fatal(err)
+		} else if data, err := ioutil.ReadAll(fh); err != nil {
+			t.Fatal(err)
+		} else {
+			files[file.Name] = string(data)
+		}
+	}
+	if files["version"] != "2" {
+		t.Error("Unexpected version", files["version"])
+	}
+	for _, expected := range []string{"samplerate=1 kHz\n", "probe1=GPIO17\n", "probe2=GPIO4\n", "unitsize=1\n"} {
+		if strings.Contains(files["metadata"], expected) == false {
+			t.Errorf("Expected %q in %q", expected, files["metadata"])
+		}
+	}
+	if samples := []byte(files["logic-1-1"]); bytes.Equal(samples, []byte{1, 1, 2, 2, 2, 3, 3, 3, 3, 3}) == false {
+		t.Error("Unexpected samples", samples)
+	}
+}
+
+func Test_Trace_004(t *testing.T) {
+	// Ring retains the last two changes
+	buffer := newRing(2)
+	for i := 1; i <= 3; i++ {
+		buffer.push(change{time.Duration(i) * time.Millisecond, uint32(i)})
+	}
+	trace := buffer.trace([]gopi.GPIOPin{4, 5}, 1000, time.Now(), 0, 5*time.Millisecond, 0)
+	if trace.initial != 2 || trace.Transitions() != 1 || trace.Duration() != 3*time.Millisecond {
+		t.Error("Unexpected trace", trace)
+	} else if trace.changes[0].t != time.Millisecond || trace.changes[0].levels != 3 {
+		t.Error("Unexpected change", trace.changes[0])
+	}
+	if samplerate(2500) != "2500 Hz" || samplerate(4000000) != "4 MHz" {
+		t.Error("Unexpected sample rates")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// newTrace returns a trace of GPIO17 and GPIO4 sampled for 10ms
+func newTrace() *trace {
+	return &trace{
+		pins:     []gopi.GPIOPin{17, 4},
+		rate:     1000,
+		start:    time.Now(),
+		duration: 10 * time.Millisecond,
+		initial:  1,
+		changes: []change{
+			{2 * time.Millisecond, 2},
+			{5 * time.Millisecond, 3},
+		},
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"sync"
 
 	gopi "github.com/djthorpe/gopi/v3"
+	gpio "github.com/djthorpe/gopi/v3/pkg/hw/gpio"
 	multierror "github.com/hashicorp/go-multierror"
 )
 
@@ -29,6 +30,11 @@ type GPIO struct {
 	exported []gopi.GPIOPin
 }
 
+// tx reads and writes pins with the GPIO locked
+type tx struct {
+	*GPIO
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // CONSTANTS
 
@@ -89,6 +95,9 @@ func (this *GPIO) Dispose() error {
 
 func (this *GPIO) String() string {
 	str := "<gpio.sysfs"
+	if name := this.InstanceName(); name != "" {
+		str += " instance=" + strconv.Quote(name)
+	}
 	if this.exported != nil {
 		str += " exported=" + fmt.Sprint(this.exported)
 	}
@@ -117,7 +126,98 @@ func (this *GPIO) PhysicalPinForPin(logical gopi.GPIOPin) uint {
 func (this *GPIO) ReadPin(logical gopi.GPIOPin) gopi.GPIOState {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
+	return tx{this}.ReadPin(logical)
+}
+
+func (this *GPIO) WritePin(logical gopi.GPIOPin, state gopi.GPIOState) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	tx{this}.WritePin(logical, state)
+}
+
+func (this *GPIO) GetPinMode(logical gopi.GPIOPin) gopi.GPIOMode {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	return tx{this}.GetPinMode(logical)
+}
+
+// Set pin mode
+func (this *GPIO) SetPinMode(logical gopi.GPIOPin, mode gopi.GPIOMode) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	tx{this}.SetPinMode(logical, mode)
+}
+
+func (this *GPIO) SetPullMode(logical gopi.GPIOPin, pull gopi.GPIOPull) error {
+	return gopi.ErrNotImplemented
+}
+
+// Batch calls a function with the GPIO locked, and restores pins changed
+// in the batch when the function returns an error
+func (this *GPIO) Batch(fn func(gopi.GPIOTx) error) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	return gpio.Batch(tx{this}, fn)
+}
+
+func (this *GPIO) Watch(logical gopi.GPIOPin, edge gopi.GPIOEdge) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Check for pin exported
+	if err := this.exportPin(logical); err != nil {
+		return err
+	}
+
+	// Do extra checks of output state when debugging is on
+	if this.Logger.IsDebug() {
+		if direction, err := direction(logical); err != nil {
+			return err
+		} else if direction != "in" {
+			return gopi.ErrOutOfOrder.WithPrefix("Watch")
+		}
+	}
+
+	// Set rising, falling, both or none
+	value := ""
+	switch edge {
+	case gopi.GPIO_EDGE_NONE:
+		if err := writeEdge(logical, "none"); err != nil {
+			return err
+		} else if err := this.Watcher.Unwatch(logical); err != nil {
+			return err
+		}
+	case gopi.GPIO_EDGE_RISING:
+		value = "rising"
+	case gopi.GPIO_EDGE_FALLING:
+		value = "falling"
+	case gopi.GPIO_EDGE_BOTH:
+		value = "both"
+	default:
+		return gopi.ErrBadParameter.WithPrefix("Watch")
+	}
+
+	this.Debug("WriteEdge: ", logical, ": ", value)
+	if err := writeEdge(logical, value); err != nil {
+		return err
+	}
+
+	// TODO: Check where pin already exists
+
+	// Watch the pin
+	if file, err := watchValue(logical); err != nil {
+		return err
+	} else if err := this.Watcher.Watch(file.Fd(), logical, edge); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TRANSACTIONS
 
+func (this tx) ReadPin(logical gopi.GPIOPin) gopi.GPIOState {
 	// Check for pin exported
 	if err := this.exportPin(logical); err != nil {
 		this.Debug("ReadPin: ", err)
@@ -143,10 +243,7 @@ func (this *GPIO) ReadPin(logical gopi.GPIOPin) gopi.GPIOState {
 	}
 }
 
-func (this *GPIO) WritePin(logical gopi.GPIOPin, state gopi.GPIOState) {
-	this.RWMutex.Lock()
-	defer this.RWMutex.Unlock()
-
+func (this tx) WritePin(logical gopi.GPIOPin, state gopi.GPIOState) {
 	// Check for pin exported
 	if err := this.exportPin(logical); err != nil {
 		this.Debug("WritePin: ", err)
@@ -175,10 +272,7 @@ func (this *GPIO) WritePin(logical gopi.GPIOPin, state gopi.GPIOState) {
 	}
 }
 
-func (this *GPIO) GetPinMode(logical gopi.GPIOPin) gopi.GPIOMode {
-	this.RWMutex.Lock()
-	defer this.RWMutex.Unlock()
-
+func (this tx) GetPinMode(logical gopi.GPIOPin) gopi.GPIOMode {
 	// Check for pin exported
 	if err := this.exportPin(logical); err != nil {
 		this.Debug("GetPinMode: ", err)
@@ -202,11 +296,7 @@ func (this *GPIO) GetPinMode(logical gopi.GPIOPin) gopi.GPIOMode {
 	}
 }
 
-// Set pin mode
-func (this *GPIO) SetPinMode(logical gopi.GPIOPin, mode gopi.GPIOMode) {
-	this.RWMutex.Lock()
-	defer this.RWMutex.Unlock()
-
+func (this tx) SetPinMode(logical gopi.GPIOPin, mode gopi.GPIOMode) {
 	// Check for pin exported
 	if err := this.exportPin(logical); err != nil {
 		this.Debug("SetPinMode: ", err)
@@ -231,64 +321,10 @@ func (this *GPIO) SetPinMode(logical gopi.GPIOPin, mode gopi.GPIOMode) {
 	}
 }
 
-func (this *GPIO) SetPullMode(logical gopi.GPIOPin, pull gopi.GPIOPull) error {
+func (this tx) SetPullMode(logical gopi.GPIOPin, pull gopi.GPIOPull) error {
 	return gopi.ErrNotImplemented
 }
 
-func (this *GPIO) Watch(logical gopi.GPIOPin, edge gopi.GPIOEdge) error {
-	this.RWMutex.Lock()
-	defer this.RWMutex.Unlock()
-
-	// Check for pin exported
-	if err := this.exportPin(logical); err != nil {
-		return err
-	}
-
-	// Do extra checks of output state when debugging is on
-	if this.Logger.IsDebug() {
-		if direction, err := direction(logical); err != nil {
-			return err
-		} else if direction != "in" {
-			return gopi.ErrOutOfOrder.WithPrefix("Watch")
-		}
-	}
-
-	// Set rising, falling, both or none
-	value := ""
-	switch edge {
-	case gopi.GPIO_EDGE_NONE:
-		if err := writeEdge(logical, "none"); err != nil {
-			return err
-		} else if err := this.Watcher.Unwatch(logical); err != nil {
-			return err
-		}
-	case gopi.GPIO_EDGE_RISING:
-		value = "rising"
-	case gopi.GPIO_EDGE_FALLING:
-		value = "falling"
-	case gopi.GPIO_EDGE_BOTH:
-		value = "both"
-	default:
-		return gopi.ErrBadParameter.WithPrefix("Watch")
-	}
-
-	this.Debug("WriteEdge: ", logical, ": ", value)
-	if err := writeEdge(logical, value); err != nil {
-		return err
-	}
-
-	// TODO: Check where pin already exists
-
-	// Watch the pin
-	if file, err := watchValue(logical); err != nil {
-		return err
-	} else if err := this.Watcher.Watch(file.Fd(), logical, edge); err != nil {
-		return err
-	}
-
-	return nil
-}
-
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
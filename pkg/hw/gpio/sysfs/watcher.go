@@ -70,7 +70,7 @@ func (this *Watcher) Watch(fd uintptr, pin gopi.GPIOPin, edge gopi.GPIOEdge) err
 	this.Mutex.Lock()
 	defer this.Mutex.Unlock()
 
-	return this.FilePoll.Watch(fd, gopi.FILEPOLL_FLAG_READ, func(uintptr, gopi.FilePollFlags) {
+	return this.FilePoll.Watch(fd, gopi.FILEPOLL_FLAG_PRIORITY|gopi.FILEPOLL_FLAG_EDGE, func(uintptr, gopi.FilePollFlags) {
 		if value, err := readPin(pin); err != nil {
 			this.Print("Watch: ", pin, ": ", err)
 		} else {
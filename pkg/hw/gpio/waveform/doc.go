@@ -0,0 +1,30 @@
+// Waveform package implements gopi.GPIOWaveform, which plays back
+// pre-computed pulse sequences on GPIO pins of a Raspberry Pi with
+// microsecond accuracy, in the same way as pigpio waves. Use it for
+// servo arrays, LED strips and stepper pulse trains where writing pins
+// from a goroutine is subject to scheduler jitter.
+//
+// Each pulse sets pins high, sets pins low and then waits. A wave is
+// compiled into a chain of DMA control blocks, which write to the GPIO
+// set and clear registers, and which wait by writing to the FIFO of the
+// PWM or PCM peripheral, clocked so that one word is consumed every
+// microsecond. For example,
+//
+//	wave, err := this.GPIOWaveform.NewWave([]gopi.GPIOPulse{
+//	  { High: []gopi.GPIOPin{ 17, 18 }, Delay: 1500 * time.Microsecond },
+//	  { Low: []gopi.GPIOPin{ 17, 18 }, Delay: 18500 * time.Microsecond },
+//	})
+//	if err == nil {
+//	  err = this.GPIOWaveform.Play(wave, true)
+//	}
+//
+// Only GPIO0 to GPIO31 can be used. The DMA channel is set with the
+// -waveform.dma flag, which on a Raspberry Pi 4 needs to be channel 10 or
+// lower. The -waveform.pacer flag selects either the "pwm" peripheral,
+// which is then not available for analog audio, or the "pcm" peripheral,
+// which is then not available for I2S audio.
+//
+// Playing waves requires -tags rpi when building, root privileges and a
+// gopi.GPIO unit, which sets the pins of a wave as outputs. Otherwise
+// waves can be created but Play returns gopi.ErrNotImplemented.
+package waveform
@@ -0,0 +1,13 @@
+package waveform
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.GPIOWaveform
+	graph.RegisterUnit(reflect.TypeOf(&waveform{}), reflect.TypeOf((*gopi.GPIOWaveform)(nil)))
+}
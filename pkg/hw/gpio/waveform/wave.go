@@ -0,0 +1,193 @@
+package waveform
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type wave struct {
+	memory
+
+	pins     []gopi.GPIOPin
+	duration time.Duration
+	blocks   []block
+	data     []uint32
+}
+
+// block is a DMA control block, where src is an index into the wave data
+type block struct {
+	ti, src, dest, length uint32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Bus addresses of peripherals
+	busPeripheral = 0x7E000000
+	busGPSET0     = busPeripheral + 0x20001C
+	busGPCLR0     = busPeripheral + 0x200028
+	busPCMFIFO    = busPeripheral + 0x203004
+	busPWMFIF1    = busPeripheral + 0x20C018
+)
+
+const (
+	// DMA peripheral mapping for pacing
+	dreqPCM = 2
+	dreqPWM = 5
+)
+
+const (
+	// DMA transfer information
+	dmaTIWaitResp     = 1 << 3
+	dmaTIDestDreq     = 1 << 6
+	dmaTINoWideBursts = 1 << 26
+)
+
+const (
+	// Size of a control block in bytes and in words
+	blockSize  = 32
+	blockWords = blockSize / 4
+
+	// Maximum delay for a control block in microseconds, which is paced
+	// by writing one word per microsecond and is limited by the transfer
+	// length on DMA lite channels
+	maxDelay = 0xFFFF / 4
+
+	// Maximum pin which can be set and cleared by a wave
+	maxPin = 31
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// newWave compiles pulses into control blocks, where delays are written to
+// the FIFO of the pacer with a peripheral mapping
+func newWave(pulses []gopi.GPIOPulse, dreq, fifo uint32) (*wave, error) {
+	if len(pulses) == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("NewWave")
+	}
+
+	this := new(wave)
+	pins := make(map[gopi.GPIOPin]bool)
+	dummy := -1
+	for i, pulse := range pulses {
+		high, err := mask(pulse.High)
+		if err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix("NewWave: pulse ", i, ": ", err)
+		}
+		low, err := mask(pulse.Low)
+		if err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix("NewWave: pulse ", i, ": ", err)
+		} else if high&low != 0 {
+			return nil, gopi.ErrBadParameter.WithPrefix("NewWave: pulse ", i, ": pin is high and low")
+		} else if pulse.Delay < 0 {
+			return nil, gopi.ErrBadParameter.WithPrefix("NewWave: pulse ", i, ": delay")
+		}
+		for _, pin := range append(pulse.High, pulse.Low...) {
+			pins[pin] = true
+		}
+
+		// Set and clear pins
+		if high != 0 {
+			this.blocks = append(this.blocks, block{dmaTINoWideBursts | dmaTIWaitResp, uint32(len(this.data)), busGPSET0, 4})
+			this.data = append(this.data, high)
+		}
+		if low != 0 {
+			this.blocks = append(this.blocks, block{dmaTINoWideBursts | dmaTIWaitResp, uint32(len(this.data)), busGPCLR0, 4})
+			this.data = append(this.data, low)
+		}
+
+		// Delay by writing a word to the pacer every microsecond
+		us := uint32((pulse.Delay + time.Microsecond/2) / time.Microsecond)
+		if us > 0 && dummy < 0 {
+			dummy = len(this.data)
+			this.data = append(this.data, 0)
+		}
+		for us > 0 {
+			n := us
+			if n > maxDelay {
+				n = maxDelay
+			}
+			this.blocks = append(this.blocks, block{dmaTINoWideBursts | dmaTIWaitResp | dmaTIDestDreq | dreq<<16, uint32(dummy), fifo, n * 4})
+			this.duration += time.Duration(n) * time.Microsecond
+			us -= n
+		}
+	}
+
+	// Pins are sorted
+	for pin := range pins {
+		this.pins = append(this.pins, pin)
+	}
+	sort.Slice(this.pins, func(i, j int) bool { return this.pins[i] < this.pins[j] })
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *wave) Pins() []gopi.GPIOPin {
+	return this.pins
+}
+
+func (this *wave) Duration() time.Duration {
+	return this.duration
+}
+
+// size returns the size of the control blocks and data in bytes
+func (this *wave) size() uint32 {
+	return uint32(len(this.blocks)*blockSize + len(this.data)*4)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *wave) String() string {
+	str := "<waveform.wave"
+	str += fmt.Sprint(" pins=", this.pins)
+	str += fmt.Sprint(" duration=", this.duration)
+	str += fmt.Sprint(" blocks=", len(this.blocks))
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// layout returns the control blocks followed by the data for memory at
+// a bus address. The last block links to the first when repeating
+func (this *wave) layout(bus uint32, repeat bool) []uint32 {
+	words := make([]uint32, 0, len(this.blocks)*blockWords+len(this.data))
+	data := bus + uint32(len(this.blocks)*blockSize)
+	for i, block := range this.blocks {
+		next := bus + uint32((i+1)*blockSize)
+		if i == len(this.blocks)-1 {
+			if repeat {
+				next = bus
+			} else {
+				next = 0
+			}
+		}
+		words = append(words, block.ti, data+block.src*4, block.dest, block.length, 0, next, 0, 0)
+	}
+	return append(words, this.data...)
+}
+
+// mask returns a bitmask for pins
+func mask(pins []gopi.GPIOPin) (uint32, error) {
+	result := uint32(0)
+	for _, pin := range pins {
+		if pin > maxPin {
+			return 0, fmt.Errorf("%v: out of range", pin)
+		}
+		result |= 1 << uint(pin)
+	}
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package waveform
+
+import (
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Wave_001(t *testing.T) {
+	// Servo pulse on two pins with a long gap
+	wave, err := newWave([]gopi.GPIOPulse{
+		{High: []gopi.GPIOPin{18, 17}, Delay: 1500 * time.Microsecond},
+		{Low: []gopi.GPIOPin{17, 18}, Delay: 18500 * time.Microsecond},
+	}, dreqPWM, busPWMFIF1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pins := wave.Pins(); len(pins) != 2 || pins[0] != 17 || pins[1] != 18 {
+		t.Error("Unexpected pins", pins)
+	}
+	if wave.Duration() != 20*time.Millisecond {
+		t.Error("Unexpected duration", wave.Duration())
+	}
+
+	// Set, delay, clear, delay split in two
+	if len(wave.blocks) != 5 {
+		t.Fatal("Unexpected blocks", wave.blocks)
+	}
+	mask := uint32(1<<17 | 1<<18)
+	if b := wave.blocks[0]; b.dest != busGPSET0 || b.length != 4 || wave.data[b.src] != mask {
+		t.Error("Unexpected set block", b)
+	}
+	if b := wave.blocks[1]; b.dest != busPWMFIF1 || b.length != 1500*4 || b.ti&dmaTIDestDreq == 0 || b.ti>>16&0x1F != dreqPWM {
+		t.Error("Unexpected delay block", b)
+	}
+	if b := wave.blocks[2]; b.dest != busGPCLR0 || wave.data[b.src] != mask {
+		t.Error("Unexpected clear block", b)
+	}
+	if b := wave.blocks[3]; b.length != maxDelay*4 {
+		t.Error("Unexpected delay block", b)
+	}
+	if b := wave.blocks[4]; b.length != (18500-maxDelay)*4 || b.src != wave.blocks[1].src {
+		t.Error("Unexpected delay block", b)
+	}
+	t.Log(wave)
+}
+
+func Test_Wave_002(t *testing.T) {
+	wave, err := newWave([]gopi.GPIOPulse{
+		{High: []gopi.GPIOPin{4}, Delay: 10 * time.Microsecond},
+		{Low: []gopi.GPIOPin{4}, Delay: 10 * time.Microsecond},
+	}, dreqPCM, busPCMFIFO)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Blocks link to the next, with data after the blocks
+	bus := uint32(0xC0001000)
+	words := wave.layout(bus, false)
+	if len(words)*4 != int(wave.size()) {
+		t.Fatal("Unexpected size", len(words))
+	}
+	data := bus + uint32(len(wave.blocks)*blockSize)
+	for i := range wave.blocks {
+		cb := words[i*blockWords:]
+		if cb[1] != data+wave.blocks[i].src*4 {
+			t.Errorf("Unexpected source 0x%08X", cb[1])
+		}
+		if i < len(wave.blocks)-1 && cb[5] != bus+uint32(i+1)*blockSize {
+			t.Errorf("Unexpected next 0x%08X", cb[5])
+		} else if i == len(wave.blocks)-1 && cb[5] != 0 {
+			t.Errorf("Unexpected end 0x%08X", cb[5])
+		}
+	}
+
+	// Repeat links the last block to the first
+	words = wave.layout(bus, true)
+	if next := words[(len(wave.blocks)-1)*blockWords+5]; next != bus {
+		t.Errorf("Unexpected next 0x%08X", next)
+	}
+}
+
+func Test_Wave_003(t *testing.T) {
+	for _, pulses := range [][]gopi.GPIOPulse{
+		nil,
+		{{High: []gopi.GPIOPin{32}}},
+		{{High: []gopi.GPIOPin{4}, Low: []gopi.GPIOPin{4}}},
+		{{High: []gopi.GPIOPin{4}, Delay: -time.Microsecond}},
+	} {
+		if _, err := newWave(pulses, dreqPWM, busPWMFIF1); err == nil {
+			t.Error("Expected error for", pulses)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package waveform
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type waveform struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.GPIO
+	sync.Mutex
+	hardware
+
+	channel *uint
+	pacer   *string
+
+	dreq    uint32
+	fifo    uint32
+	waves   map[*wave]bool
+	playing *wave
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Maximum DMA channel, as channel 15 is in a separate register block
+	maxChannel = 14
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *waveform) Define(cfg gopi.Config) error {
+	this.channel = cfg.FlagUint("waveform.dma", 10, "DMA channel for playing waves")
+	this.pacer = cfg.FlagString("waveform.pacer", "pwm", "Peripheral which paces waves (pwm or pcm)")
+	return nil
+}
+
+func (this *waveform) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if *this.channel > maxChannel {
+		return gopi.ErrBadParameter.WithPrefix("-waveform.dma")
+	}
+	switch strings.ToLower(*this.pacer) {
+	case "pwm":
+		this.dreq, this.fifo = dreqPWM, busPWMFIF1
+	case "pcm":
+		this.dreq, this.fifo = dreqPCM, busPCMFIFO
+	default:
+		return gopi.ErrBadParameter.WithPrefix("-waveform.pacer")
+	}
+
+	// Open DMA and pacer
+	if err := this.open(); err != nil {
+		this.close()
+		return err
+	}
+
+	this.waves = make(map[*wave]bool)
+
+	// Return success
+	return nil
+}
+
+func (this *waveform) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	var result error
+	if err := this.stop(); err != nil {
+		result = multierror.Append(result, err)
+	}
+	for wave := range this.waves {
+		if err := this.free(wave); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	if err := this.close(); err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	// Release resources
+	this.waves = nil
+	this.playing = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *waveform) NewWave(pulses []gopi.GPIOPulse) (gopi.GPIOWave, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if wave, err := newWave(pulses, this.dreq, this.fifo); err != nil {
+		return nil, err
+	} else if err := this.alloc(wave); err != nil {
+		return nil, err
+	} else {
+		this.waves[wave] = true
+		return wave, nil
+	}
+}
+
+func (this *waveform) DeleteWave(w gopi.GPIOWave) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	wave, exists := w.(*wave)
+	if exists == false || this.waves[wave] == false {
+		return gopi.ErrNotFound.WithPrefix("DeleteWave")
+	}
+
+	// Stop the wave if it is playing
+	if this.playing == wave {
+		if err := this.stop(); err != nil {
+			return err
+		}
+		this.playing = nil
+	}
+
+	delete(this.waves, wave)
+	return this.free(wave)
+}
+
+func (this *waveform) Play(w gopi.GPIOWave, repeat bool) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	wave, exists := w.(*wave)
+	if exists == false || this.waves[wave] == false {
+		return gopi.ErrNotFound.WithPrefix("Play")
+	}
+
+	// Stop any playing wave and start the new one
+	if err := this.stop(); err != nil {
+		return err
+	} else {
+		this.playing = nil
+	}
+	if err := this.start(wave, repeat); err != nil {
+		return err
+	} else {
+		this.playing = wave
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *waveform) Stop() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if err := this.stop(); err != nil {
+		return err
+	} else {
+		this.playing = nil
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *waveform) Busy() bool {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.playing != nil && this.busy()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *waveform) String() string {
+	str := "<waveform"
+	str += fmt.Sprint(" dma=", *this.channel)
+	str += fmt.Sprintf(" pacer=%q", strings.ToLower(*this.pacer))
+	this.Mutex.Lock()
+	if this.playing != nil && this.busy() {
+		str += fmt.Sprint(" playing=", this.playing)
+	}
+	this.Mutex.Unlock()
+	return str + ">"
+}
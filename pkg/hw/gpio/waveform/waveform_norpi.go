@@ -0,0 +1,45 @@
+// +build !rpi
+
+package waveform
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type hardware struct{}
+
+type memory struct{}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *waveform) open() error {
+	return nil
+}
+
+func (this *waveform) close() error {
+	return nil
+}
+
+func (this *waveform) alloc(*wave) error {
+	return nil
+}
+
+func (this *waveform) free(*wave) error {
+	return nil
+}
+
+func (this *waveform) start(*wave, bool) error {
+	return gopi.ErrNotImplemented.WithPrefix("Play")
+}
+
+func (this *waveform) stop() error {
+	return nil
+}
+
+func (this *waveform) busy() bool {
+	return false
+}
@@ -0,0 +1,393 @@
+// +build rpi
+
+package waveform
+
+import (
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	rpi "github.com/djthorpe/gopi/v3/pkg/sys/rpi"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type hardware struct {
+	mbox, mem          *os.File
+	flags              rpi.MemFlag
+	dma, pwm, pcm, clk []uint32
+	maps               [][]byte
+}
+
+type memory struct {
+	handle, bus uint32
+	mem8        []byte
+	mem32       []uint32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	DEV_MEM  = "/dev/mem"
+	PAGESIZE = 4096
+)
+
+const (
+	// Offsets of peripherals from the peripheral base address
+	DMA_BASE = 0x007000
+	CLK_BASE = 0x101000
+	PCM_BASE = 0x203000
+	PWM_BASE = 0x20C000
+)
+
+const (
+	// DMA registers, as word offsets
+	DMA_CS        = 0x00 >> 2
+	DMA_CONBLK_AD = 0x04 >> 2
+	DMA_DEBUG     = 0x20 >> 2
+	DMA_ENABLE    = 0xFF0 >> 2
+	DMA_CHANNEL   = 0x100 >> 2
+
+	DMA_CS_ACTIVE                      = 1 << 0
+	DMA_CS_END                         = 1 << 1
+	DMA_CS_INT                         = 1 << 2
+	DMA_CS_WAIT_FOR_OUTSTANDING_WRITES = 1 << 28
+	DMA_CS_RESET                       = 1 << 31
+	DMA_CS_PRIORITY                    = 15 << 16
+	DMA_CS_PANIC_PRIORITY              = 15 << 20
+	DMA_DEBUG_CLEAR                    = 7
+)
+
+const (
+	// Clock manager registers, as word offsets
+	CLK_PCMCTL = 0x98 >> 2
+	CLK_PCMDIV = 0x9C >> 2
+	CLK_PWMCTL = 0xA0 >> 2
+	CLK_PWMDIV = 0xA4 >> 2
+
+	CLK_PASSWD       = 0x5A << 24
+	CLK_CTL_SRC_PLLD = 6
+	CLK_CTL_ENAB     = 1 << 4
+	CLK_CTL_KILL     = 1 << 5
+	CLK_CTL_BUSY     = 1 << 7
+	CLK_DIV_DIVI     = 12
+)
+
+const (
+	// PWM registers, as word offsets
+	PWM_CTL  = 0x00 >> 2
+	PWM_DMAC = 0x08 >> 2
+	PWM_RNG1 = 0x10 >> 2
+
+	PWM_CTL_PWEN1  = 1 << 0
+	PWM_CTL_MODE1  = 1 << 1
+	PWM_CTL_USEF1  = 1 << 5
+	PWM_CTL_CLRF1  = 1 << 6
+	PWM_DMAC_ENAB  = 1 << 31
+	PWM_DMAC_PANIC = 15 << 8
+	PWM_DMAC_DREQ  = 15 << 0
+)
+
+const (
+	// PCM registers, as word offsets
+	PCM_CS    = 0x00 >> 2
+	PCM_MODE  = 0x08 >> 2
+	PCM_TXC   = 0x10 >> 2
+	PCM_DREQ  = 0x14 >> 2
+	PCM_INTEN = 0x18 >> 2
+
+	PCM_CS_EN         = 1 << 0
+	PCM_CS_TXON       = 1 << 2
+	PCM_CS_TXCLR      = 1 << 3
+	PCM_CS_RXCLR      = 1 << 4
+	PCM_CS_DMAEN      = 1 << 9
+	PCM_TXC_CH1WEX    = 1 << 31
+	PCM_TXC_CH1EN     = 1 << 30
+	PCM_MODE_FLEN     = 10
+	PCM_DREQ_TX_PANIC = 16 << 24
+	PCM_DREQ_TX_REQ_L = 30 << 8
+)
+
+const (
+	// Pacer clock is 10MHz and ten bits are written per microsecond
+	pacerClock = 10000000
+	pacerBits  = 10
+
+	// Peripheral base address and PLLD frequency for the Raspberry Pi 4
+	peripheralPi4 = 0xFE000000
+	plldPi4       = 750000000
+	plld          = 500000000
+	peripheralPi1 = 0x20000000
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *waveform) open() error {
+	if this.GPIO == nil {
+		return gopi.ErrInternalAppError.WithPrefix("Missing gopi.GPIO")
+	}
+
+	// DMA4 channels on the Raspberry Pi 4 have a different layout
+	base := rpi.BCMHostGetPeripheralAddress()
+	freq := uint32(plld)
+	if base == peripheralPi4 {
+		freq = plldPi4
+		if *this.channel > 10 {
+			return gopi.ErrBadParameter.WithPrefix("-waveform.dma")
+		}
+	}
+
+	// Pages are uncached on the Raspberry Pi 1 only with the L1 non-allocating alias
+	if base == peripheralPi1 {
+		this.flags = rpi.MEM_FLAG_L1_NONALLOCATING
+	} else {
+		this.flags = rpi.MEM_FLAG_DIRECT
+	}
+
+	// Open mailbox and memory, which requires root
+	if mbox, err := rpi.MailboxOpen(); err != nil {
+		return err
+	} else {
+		this.mbox = mbox
+	}
+	if mem, err := os.OpenFile(DEV_MEM, os.O_RDWR|os.O_SYNC, 0); err != nil {
+		return err
+	} else {
+		this.mem = mem
+	}
+
+	// Map peripherals
+	for _, reg := range []struct {
+		mem    *[]uint32
+		offset uint32
+	}{
+		{&this.dma, DMA_BASE},
+		{&this.clk, CLK_BASE},
+		{&this.pcm, PCM_BASE},
+		{&this.pwm, PWM_BASE},
+	} {
+		if mem8, mem32, err := this.mmap(base+reg.offset, PAGESIZE); err != nil {
+			return err
+		} else {
+			this.maps = append(this.maps, mem8)
+			*reg.mem = mem32
+		}
+	}
+
+	// Enable DMA channel and reset it
+	this.dma[DMA_ENABLE] |= 1 << *this.channel
+	if err := this.stop(); err != nil {
+		return err
+	}
+
+	// Start pacer
+	switch this.dreq {
+	case dreqPWM:
+		this.startPWM(freq / pacerClock)
+	case dreqPCM:
+		this.startPCM(freq / pacerClock)
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *waveform) close() error {
+	var result error
+
+	// Stop pacer
+	if this.pwm != nil && this.dreq == dreqPWM {
+		this.pwm[PWM_CTL] = 0
+	}
+	if this.pcm != nil && this.dreq == dreqPCM {
+		this.pcm[PCM_CS] = 0
+	}
+
+	// Unmap peripherals
+	for _, mem8 := range this.maps {
+		if err := syscall.Munmap(mem8); err != nil {
+			result = multierror.Append(result, os.NewSyscallError("munmap", err))
+		}
+	}
+
+	// Close files
+	if this.mem != nil {
+		if err := this.mem.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	if this.mbox != nil {
+		if err := this.mbox.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Release resources
+	this.dma, this.clk, this.pcm, this.pwm = nil, nil, nil, nil
+	this.maps = nil
+	this.mem, this.mbox = nil, nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEMORY
+
+// alloc allocates uncached memory for the wave which the DMA controller
+// can access
+func (this *waveform) alloc(wave *wave) error {
+	size := (wave.size() + PAGESIZE - 1) &^ (PAGESIZE - 1)
+	if handle, err := rpi.MailboxMemAlloc(this.mbox.Fd(), size, PAGESIZE, this.flags); err != nil {
+		return err
+	} else {
+		wave.handle = handle
+	}
+	if bus, err := rpi.MailboxMemLock(this.mbox.Fd(), wave.handle); err != nil {
+		rpi.MailboxMemFree(this.mbox.Fd(), wave.handle)
+		return err
+	} else {
+		wave.bus = bus
+	}
+	if mem8, mem32, err := this.mmap(busToPhys(wave.bus), size); err != nil {
+		rpi.MailboxMemUnlock(this.mbox.Fd(), wave.handle)
+		rpi.MailboxMemFree(this.mbox.Fd(), wave.handle)
+		return err
+	} else {
+		wave.mem8, wave.mem32 = mem8, mem32
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *waveform) free(wave *wave) error {
+	var result error
+	if wave.mem8 != nil {
+		if err := syscall.Munmap(wave.mem8); err != nil {
+			result = multierror.Append(result, os.NewSyscallError("munmap", err))
+		}
+	}
+	if wave.handle != 0 {
+		if err := rpi.MailboxMemUnlock(this.mbox.Fd(), wave.handle); err != nil {
+			result = multierror.Append(result, err)
+		}
+		if err := rpi.MailboxMemFree(this.mbox.Fd(), wave.handle); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	wave.memory = memory{}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// DMA
+
+func (this *waveform) start(wave *wave, repeat bool) error {
+	// Set pins as outputs
+	for _, pin := range wave.pins {
+		this.GPIO.SetPinMode(pin, gopi.GPIO_OUTPUT)
+	}
+
+	// Write control blocks and data
+	copy(wave.mem32, wave.layout(wave.bus, repeat))
+
+	// Start DMA
+	ch := this.dma[*this.channel*DMA_CHANNEL:]
+	ch[DMA_CS] = DMA_CS_INT | DMA_CS_END
+	ch[DMA_DEBUG] = DMA_DEBUG_CLEAR
+	ch[DMA_CONBLK_AD] = wave.bus
+	ch[DMA_CS] = DMA_CS_WAIT_FOR_OUTSTANDING_WRITES | DMA_CS_PANIC_PRIORITY | DMA_CS_PRIORITY | DMA_CS_ACTIVE
+
+	// Return success
+	return nil
+}
+
+func (this *waveform) stop() error {
+	if this.dma == nil {
+		return nil
+	}
+	ch := this.dma[*this.channel*DMA_CHANNEL:]
+	ch[DMA_CS] = DMA_CS_RESET
+	time.Sleep(10 * time.Microsecond)
+	ch[DMA_CS] = DMA_CS_INT | DMA_CS_END
+	ch[DMA_CONBLK_AD] = 0
+	return nil
+}
+
+func (this *waveform) busy() bool {
+	if this.dma == nil {
+		return false
+	}
+	ch := this.dma[*this.channel*DMA_CHANNEL:]
+	return ch[DMA_CS]&DMA_CS_ACTIVE != 0 && ch[DMA_CONBLK_AD] != 0
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PACER
+
+// startPWM writes to the PWM FIFO at one word per microsecond
+func (this *waveform) startPWM(divi uint32) {
+	this.pwm[PWM_CTL] = 0
+	time.Sleep(10 * time.Microsecond)
+	this.setClock(CLK_PWMCTL, CLK_PWMDIV, divi)
+	this.pwm[PWM_RNG1] = pacerBits
+	this.pwm[PWM_DMAC] = PWM_DMAC_ENAB | PWM_DMAC_PANIC | PWM_DMAC_DREQ
+	this.pwm[PWM_CTL] = PWM_CTL_CLRF1
+	time.Sleep(10 * time.Microsecond)
+	this.pwm[PWM_CTL] = PWM_CTL_USEF1 | PWM_CTL_MODE1 | PWM_CTL_PWEN1
+}
+
+// startPCM writes to the PCM FIFO at one frame per microsecond
+func (this *waveform) startPCM(divi uint32) {
+	this.pcm[PCM_CS] = PCM_CS_EN
+	this.pcm[PCM_TXC] = PCM_TXC_CH1WEX | PCM_TXC_CH1EN
+	this.pcm[PCM_MODE] = (pacerBits - 1) << PCM_MODE_FLEN
+	this.pcm[PCM_CS] |= PCM_CS_TXCLR | PCM_CS_RXCLR
+	time.Sleep(10 * time.Microsecond)
+	this.pcm[PCM_CS] |= PCM_CS_DMAEN
+	this.pcm[PCM_DREQ] = PCM_DREQ_TX_PANIC | PCM_DREQ_TX_REQ_L
+	this.pcm[PCM_INTEN] = 0
+	this.setClock(CLK_PCMCTL, CLK_PCMDIV, divi)
+	this.pcm[PCM_CS] |= PCM_CS_TXON
+}
+
+// setClock sets a peripheral clock from PLLD with an integer divisor
+func (this *waveform) setClock(ctl, div int, divi uint32) {
+	this.clk[ctl] = CLK_PASSWD | CLK_CTL_KILL
+	for this.clk[ctl]&CLK_CTL_BUSY != 0 {
+		time.Sleep(10 * time.Microsecond)
+	}
+	this.clk[div] = CLK_PASSWD | divi<<CLK_DIV_DIVI
+	this.clk[ctl] = CLK_PASSWD | CLK_CTL_SRC_PLLD
+	this.clk[ctl] = CLK_PASSWD | CLK_CTL_SRC_PLLD | CLK_CTL_ENAB
+	time.Sleep(10 * time.Microsecond)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// mmap maps physical memory as bytes and words
+func (this *waveform) mmap(addr, size uint32) ([]byte, []uint32, error) {
+	mem8, err := syscall.Mmap(int(this.mem.Fd()), int64(addr), int(size), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, os.NewSyscallError("mmap", err)
+	}
+
+	// Convert mapped byte memory to words
+	mem32 := (*[1 << 28]uint32)(unsafe.Pointer(&mem8[0]))[: len(mem8)/4 : len(mem8)/4]
+
+	// Return success
+	return mem8, mem32, nil
+}
+
+// busToPhys returns the physical address for an SDRAM bus address
+func busToPhys(bus uint32) uint32 {
+	return bus &^ 0xC0000000
+}
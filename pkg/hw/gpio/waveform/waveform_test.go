@@ -0,0 +1,49 @@
+// +build !rpi
+
+package waveform_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/gpio/waveform"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.GPIOWaveform
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Waveform_001(t *testing.T) {
+	tool.Test(t, []string{"-waveform.pacer", "pcm"}, new(App), func(app *App) {
+		wave, err := app.GPIOWaveform.NewWave([]gopi.GPIOPulse{
+			{High: []gopi.GPIOPin{17}, Delay: time.Millisecond},
+			{Low: []gopi.GPIOPin{17}, Delay: time.Millisecond},
+		})
+		if err != nil {
+			t.Error(err)
+		} else if wave.Duration() != 2*time.Millisecond {
+			t.Error("Unexpected duration", wave.Duration())
+		} else if err := app.GPIOWaveform.Play(wave, false); errors.Is(err, gopi.ErrNotImplemented) == false {
+			t.Error("Unexpected error from Play", err)
+		} else if app.GPIOWaveform.Busy() {
+			t.Error("Unexpected busy")
+		} else if err := app.GPIOWaveform.DeleteWave(wave); err != nil {
+			t.Error(err)
+		} else if err := app.GPIOWaveform.DeleteWave(wave); errors.Is(err, gopi.ErrNotFound) == false {
+			t.Error("Unexpected error from DeleteWave", err)
+		} else {
+			t.Log(app.GPIOWaveform, wave)
+		}
+	})
+}
@@ -1,8 +1,11 @@
 package i2c
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"sync"
+	"time"
 
 	gopi "github.com/djthorpe/gopi/v3"
 	"github.com/hashicorp/go-multierror"
@@ -16,13 +19,30 @@ type i2c struct {
 	sync.Mutex
 	gopi.Logger
 
+	timeout *time.Duration
+	bus     int // bus for an instance named with a bus number, or -1
 	devices map[gopi.I2CBus]*device
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // INIT
 
+func (this *i2c) Define(cfg gopi.Config) error {
+	this.timeout = cfg.FlagDuration("i2c.timeout", 0, "Time to wait for a slave to complete a transfer, or zero for the adapter default")
+	return nil
+}
+
 func (this *i2c) New(gopi.Config) error {
+	// An instance named with a bus number only opens that bus
+	this.bus = -1
+	if name := this.InstanceName(); name != "" {
+		if bus, err := strconv.ParseUint(name, 10, 32); err != nil {
+			return gopi.ErrBadParameter.WithPrefix("Invalid bus: ", strconv.Quote(name))
+		} else {
+			this.bus = int(bus)
+		}
+	}
+
 	this.devices = make(map[gopi.I2CBus]*device, 10)
 	return nil
 }
@@ -83,9 +103,58 @@ func (this *i2c) Write(bus gopi.I2CBus, buf []byte) (int, error) {
 	}
 }
 
+func (this *i2c) TransferContext(ctx context.Context, bus gopi.I2CBus, data []byte, n int) ([]byte, error) {
+	if n < 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("TransferContext")
+	}
+
+	var result []byte
+	if err := this.do(ctx, func() error {
+		var err error
+		result, err = this.transfer(bus, data, n)
+		return err
+	}); err != nil {
+		return nil, err
+	} else {
+		return result, nil
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
+// checkBus returns an error if a named instance is used with another bus
+func (this *i2c) checkBus(bus gopi.I2CBus) error {
+	if this.bus >= 0 && bus != gopi.I2CBus(this.bus) {
+		return gopi.ErrBadParameter.WithPrefix("Bus ", bus, " not available for instance ", strconv.Quote(this.InstanceName()))
+	} else {
+		return nil
+	}
+}
+
+// do calls a function with the unit locked, and returns the context
+// error if the context is done first. The function continues in the
+// background and the unit remains locked until it returns, so a hung
+// slave cannot be used until the adapter times out the transfer
+func (this *i2c) do(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		this.Mutex.Lock()
+		defer this.Mutex.Unlock()
+		if err := ctx.Err(); err != nil {
+			done <- err
+		} else {
+			done <- fn()
+		}
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (this *i2c) Close(bus gopi.I2CBus) error {
 	var result error
 
@@ -107,9 +176,15 @@ func (this *i2c) String() string {
 	defer this.Mutex.Unlock()
 
 	str := "<i2c"
+	if name := this.InstanceName(); name != "" {
+		str += " instance=" + strconv.Quote(name)
+	}
 	if d := this.Devices(); len(d) > 0 {
 		str += " bus=" + fmt.Sprint(d)
 	}
+	if *this.timeout > 0 {
+		str += " timeout=" + fmt.Sprint(*this.timeout)
+	}
 	for bus, device := range this.devices {
 		str += fmt.Sprintf(" device[%v]=%v", bus, device)
 	}
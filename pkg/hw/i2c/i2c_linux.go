@@ -3,6 +3,7 @@
 package i2c
 
 import (
+	"io"
 	"os"
 
 	gopi "github.com/djthorpe/gopi/v3"
@@ -14,6 +15,11 @@ import (
 
 type I2CFunction linux.I2CFunction
 
+// tx reads and writes on an open device with the unit locked
+type tx struct {
+	*device
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // GLOBALS
 
@@ -29,7 +35,9 @@ const (
 func (this *i2c) Devices() []gopi.I2CBus {
 	var devices []gopi.I2CBus
 	for bus := uint(minBus); bus <= uint(maxBus); bus++ {
-		if _, err := os.Stat(linux.I2CDevice(bus)); err == nil {
+		if this.checkBus(gopi.I2CBus(bus)) != nil {
+			continue
+		} else if _, err := os.Stat(linux.I2CDevice(bus)); err == nil {
 			devices = append(devices, gopi.I2CBus(bus))
 		}
 	}
@@ -182,6 +190,88 @@ func (this *i2c) WriteInt16(bus gopi.I2CBus, reg uint8, value int16) error {
 	}
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - BATCH
+
+// Batch calls a function with the unit locked and the bus open
+func (this *i2c) Batch(bus gopi.I2CBus, fn func(gopi.I2CTx) error) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if device, err := this.Open(bus); err != nil {
+		return err
+	} else {
+		return fn(tx{device})
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TRANSACTIONS
+
+func (this tx) SetSlave(slave uint8) error {
+	if err := linux.I2CSetSlave(this.Fd(), slave); err != nil {
+		return err
+	} else {
+		this.slave = slave
+	}
+
+	// Return success
+	return nil
+}
+
+func (this tx) GetSlave() uint8 {
+	return this.slave
+}
+
+func (this tx) Read() ([]byte, error) {
+	var buf []byte
+	if _, err := this.fh.Read(buf); err != nil {
+		return nil, err
+	} else {
+		return buf, nil
+	}
+}
+
+func (this tx) Write(buf []byte) (int, error) {
+	return this.fh.Write(buf)
+}
+
+func (this tx) ReadUint8(reg uint8) (uint8, error) {
+	return linux.I2CReadUint8(this.Fd(), reg, linux.I2CFunction(this.funcs))
+}
+
+func (this tx) ReadInt8(reg uint8) (int8, error) {
+	return linux.I2CReadInt8(this.Fd(), reg, linux.I2CFunction(this.funcs))
+}
+
+func (this tx) ReadUint16(reg uint8) (uint16, error) {
+	return linux.I2CReadUint16(this.Fd(), reg, linux.I2CFunction(this.funcs))
+}
+
+func (this tx) ReadInt16(reg uint8) (int16, error) {
+	return linux.I2CReadInt16(this.Fd(), reg, linux.I2CFunction(this.funcs))
+}
+
+func (this tx) ReadBlock(reg, length uint8) ([]byte, error) {
+	return linux.I2CReadBlock(this.Fd(), reg, length, linux.I2CFunction(this.funcs))
+}
+
+func (this tx) WriteUint8(reg, value uint8) error {
+	return linux.I2CWriteUint8(this.Fd(), reg, value, linux.I2CFunction(this.funcs))
+}
+
+func (this tx) WriteInt8(reg uint8, value int8) error {
+	return linux.I2CWriteInt8(this.Fd(), reg, value, linux.I2CFunction(this.funcs))
+}
+
+func (this tx) WriteUint16(reg uint8, value uint16) error {
+	return linux.I2CWriteUint16(this.Fd(), reg, value, linux.I2CFunction(this.funcs))
+}
+
+func (this tx) WriteInt16(reg uint8, value int16) error {
+	return linux.I2CWriteInt16(this.Fd(), reg, value, linux.I2CFunction(this.funcs))
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -193,12 +283,19 @@ func (f I2CFunction) String() string {
 // PRIVATE METHODS
 
 func (this *i2c) Open(bus gopi.I2CBus) (*device, error) {
+	if err := this.checkBus(bus); err != nil {
+		return nil, err
+	}
 	device, exists := this.devices[bus]
 	if exists == false {
 		this.Debug("i2C Open=>", bus)
 		if fh, err := linux.I2COpenDevice(uint(bus)); err != nil {
 			return nil, err
 		} else if funcs, err := linux.I2CFunctions(fh.Fd()); err != nil {
+			fh.Close()
+			return nil, err
+		} else if err := this.setTimeout(fh.Fd()); err != nil {
+			fh.Close()
 			return nil, err
 		} else {
 			device = NewDevice(bus, fh, I2CFunction(funcs))
@@ -207,3 +304,32 @@ func (this *i2c) Open(bus gopi.I2CBus) (*device, error) {
 	}
 	return device, nil
 }
+
+// transfer writes data to the current slave and then reads n bytes
+func (this *i2c) transfer(bus gopi.I2CBus, data []byte, n int) ([]byte, error) {
+	device, err := this.Open(bus)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if _, err := device.fh.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	buf := make([]byte, n)
+	if n > 0 {
+		if _, err := io.ReadFull(device.fh, buf); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// setTimeout sets the adapter timeout when the -i2c.timeout flag is set
+func (this *i2c) setTimeout(fd uintptr) error {
+	if *this.timeout > 0 {
+		return linux.I2CSetTimeout(fd, *this.timeout)
+	} else {
+		return nil
+	}
+}
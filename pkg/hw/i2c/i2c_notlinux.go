@@ -77,3 +77,17 @@ func (this *i2c) WriteUint16(bus gopi.I2CBus, reg uint8, value uint16) error {
 func (this *i2c) WriteInt16(bus gopi.I2CBus, reg uint8, value int16) error {
 	return gopi.ErrNotImplemented
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - BATCH
+
+func (this *i2c) Batch(gopi.I2CBus, func(gopi.I2CTx) error) error {
+	return gopi.ErrNotImplemented
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *i2c) transfer(gopi.I2CBus, []byte, int) ([]byte, error) {
+	return nil, gopi.ErrNotImplemented
+}
@@ -0,0 +1,39 @@
+package i2c
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Transfer_001(t *testing.T) {
+	this := new(i2c)
+
+	// Transfer returns when the context is done while the bus is in use
+	this.Mutex.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := this.TransferContext(ctx, 1, []byte{0x00}, 2); errors.Is(err, context.DeadlineExceeded) == false {
+		t.Error("Unexpected error", err)
+	} else if time.Since(start) > time.Second {
+		t.Error("Transfer did not return on timeout")
+	}
+	this.Mutex.Unlock()
+
+	// Transfer is not attempted when the context is already cancelled
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := this.TransferContext(ctx, 1, nil, 2); errors.Is(err, context.Canceled) == false {
+		t.Error("Unexpected error", err)
+	}
+
+	// Bad parameter
+	if _, err := this.TransferContext(context.Background(), 1, nil, -1); err == nil {
+		t.Error("Expected error")
+	}
+}
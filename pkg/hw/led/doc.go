@@ -0,0 +1,3 @@
+// LED package controls LED class devices through /sys/class/leds,
+// including setting brightness, kernel triggers and blink patterns
+package led
@@ -0,0 +1,13 @@
+package led
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register led
+	graph.RegisterUnit(reflect.TypeOf(&led{}), reflect.TypeOf((*gopi.LED)(nil)))
+}
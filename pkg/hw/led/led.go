@@ -0,0 +1,161 @@
+package led
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type led struct {
+	gopi.Unit
+	gopi.Logger
+	sync.Mutex
+	sync.WaitGroup
+
+	cancels map[string]context.CancelFunc
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	blinkOn    = 200 * time.Millisecond
+	blinkOff   = 300 * time.Millisecond
+	blinkPause = 1500 * time.Millisecond
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *led) New(gopi.Config) error {
+	this.cancels = make(map[string]context.CancelFunc)
+	return nil
+}
+
+func (this *led) Dispose() error {
+	this.Mutex.Lock()
+	for name, cancel := range this.cancels {
+		cancel()
+		delete(this.cancels, name)
+	}
+	this.Mutex.Unlock()
+
+	// Wait for patterns to end
+	this.WaitGroup.Wait()
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *led) Pattern(name string, pattern ...time.Duration) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Check parameters
+	_, max, err := this.Brightness(name)
+	if err != nil {
+		return err
+	}
+	for _, d := range pattern {
+		if d <= 0 {
+			return gopi.ErrBadParameter.WithPrefix("Pattern")
+		}
+	}
+
+	// Stop any existing pattern
+	if cancel, exists := this.cancels[name]; exists {
+		cancel()
+		delete(this.cancels, name)
+	}
+
+	// Switch off triggers and the LED itself
+	if err := this.SetTrigger(name, gopi.LED_TRIGGER_NONE); err != nil {
+		return err
+	} else if err := this.SetBrightness(name, 0); err != nil {
+		return err
+	} else if len(pattern) == 0 {
+		return nil
+	}
+
+	// Run pattern in the background
+	ctx, cancel := context.WithCancel(context.Background())
+	this.cancels[name] = cancel
+	this.WaitGroup.Add(1)
+	go func() {
+		defer this.WaitGroup.Done()
+		this.runPattern(ctx, name, max, pattern)
+	}()
+
+	// Return success
+	return nil
+}
+
+func (this *led) BlinkCode(name string, code uint) error {
+	return this.Pattern(name, blinkPattern(code)...)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *led) String() string {
+	str := "<led"
+	for _, name := range this.Devices() {
+		if value, max, err := this.Brightness(name); err == nil {
+			str += fmt.Sprintf(" %v={ %v/%v }", name, value, max)
+		}
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *led) runPattern(ctx context.Context, name string, max uint, pattern []time.Duration) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	i := 0
+	for {
+		select {
+		case <-timer.C:
+			value := uint(0)
+			if i%2 == 0 {
+				value = max
+			}
+			if err := this.SetBrightness(name, value); err != nil {
+				this.Print("LED: ", name, ": ", err)
+				return
+			}
+			timer.Reset(pattern[i%len(pattern)])
+			i = (i + 1) % (len(pattern) * 2)
+		case <-ctx.Done():
+			if err := this.SetBrightness(name, 0); err != nil {
+				this.Print("LED: ", name, ": ", err)
+			}
+			return
+		}
+	}
+}
+
+// blinkPattern returns on and off durations which blink a code
+// number of times followed by a pause
+func blinkPattern(code uint) []time.Duration {
+	if code == 0 {
+		return nil
+	}
+	pattern := make([]time.Duration, 0, code*2)
+	for i := uint(0); i < code; i++ {
+		pattern = append(pattern, blinkOn, blinkOff)
+	}
+	pattern[len(pattern)-1] = blinkPause
+	return pattern
+}
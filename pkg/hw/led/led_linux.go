@@ -0,0 +1,120 @@
+// +build linux
+
+package led
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	sysfsLEDPath = "/sys/class/leds"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *led) Devices() []string {
+	files, err := ioutil.ReadDir(sysfsLEDPath)
+	if err != nil {
+		return nil
+	}
+	devices := make([]string, 0, len(files))
+	for _, file := range files {
+		devices = append(devices, file.Name())
+	}
+	return devices
+}
+
+func (this *led) Brightness(name string) (uint, uint, error) {
+	if value, err := readUint(name, "brightness"); err != nil {
+		return 0, 0, err
+	} else if max, err := readUint(name, "max_brightness"); err != nil {
+		return 0, 0, err
+	} else {
+		return value, max, nil
+	}
+}
+
+func (this *led) SetBrightness(name string, value uint) error {
+	return write(name, "brightness", strconv.FormatUint(uint64(value), 10))
+}
+
+func (this *led) Triggers(name string) ([]string, string, error) {
+	value, err := read(name, "trigger")
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The current trigger is enclosed in square brackets
+	triggers := strings.Fields(value)
+	current := ""
+	for i, trigger := range triggers {
+		if strings.HasPrefix(trigger, "[") && strings.HasSuffix(trigger, "]") {
+			current = strings.Trim(trigger, "[]")
+			triggers[i] = current
+		}
+	}
+
+	// Return success
+	return triggers, current, nil
+}
+
+func (this *led) SetTrigger(name, trigger string) error {
+	if trigger = strings.TrimSpace(trigger); trigger == "" {
+		return gopi.ErrBadParameter.WithPrefix("SetTrigger")
+	}
+	return write(name, "trigger", trigger)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func attrPath(name, attr string) (string, error) {
+	if name == "" || strings.ContainsAny(name, "/.") {
+		return "", gopi.ErrBadParameter.WithPrefix(name)
+	}
+	path := filepath.Join(sysfsLEDPath, name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", gopi.ErrNotFound.WithPrefix(name)
+	} else if err != nil {
+		return "", err
+	}
+	return filepath.Join(path, attr), nil
+}
+
+func read(name, attr string) (string, error) {
+	if path, err := attrPath(name, attr); err != nil {
+		return "", err
+	} else if data, err := ioutil.ReadFile(path); err != nil {
+		return "", err
+	} else {
+		return strings.TrimSpace(string(data)), nil
+	}
+}
+
+func readUint(name, attr string) (uint, error) {
+	if value, err := read(name, attr); err != nil {
+		return 0, err
+	} else if value, err := strconv.ParseUint(value, 10, 32); err != nil {
+		return 0, err
+	} else {
+		return uint(value), nil
+	}
+}
+
+func write(name, attr, value string) error {
+	if path, err := attrPath(name, attr); err != nil {
+		return err
+	} else {
+		return ioutil.WriteFile(path, []byte(value), 0)
+	}
+}
@@ -0,0 +1,30 @@
+// +build !linux
+
+package led
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *led) Devices() []string {
+	return nil
+}
+
+func (this *led) Brightness(string) (uint, uint, error) {
+	return 0, 0, gopi.ErrNotImplemented
+}
+
+func (this *led) SetBrightness(string, uint) error {
+	return gopi.ErrNotImplemented
+}
+
+func (this *led) Triggers(string) ([]string, string, error) {
+	return nil, "", gopi.ErrNotImplemented
+}
+
+func (this *led) SetTrigger(string, string) error {
+	return gopi.ErrNotImplemented
+}
@@ -0,0 +1,49 @@
+package led_test
+
+import (
+	"testing"
+
+	"github.com/djthorpe/gopi/v3"
+	"github.com/djthorpe/gopi/v3/pkg/tool"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.LED
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_LED_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.LED == nil {
+			t.Error("nil LED unit")
+		} else {
+			t.Log(app.LED)
+		}
+	})
+}
+
+func Test_LED_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		for _, name := range app.LED.Devices() {
+			if triggers, current, err := app.LED.Triggers(name); err != nil {
+				t.Error(err)
+			} else {
+				t.Logf("%v trigger=%q triggers=%q", name, current, triggers)
+			}
+		}
+	})
+}
+
+func Test_LED_003(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if err := app.LED.SetBrightness("../led0", 0); err == nil {
+			t.Error("Expected error for bad device name")
+		}
+	})
+}
@@ -48,7 +48,7 @@ var (
 // INIT
 
 func (this *Manager) Define(cfg gopi.Config) error {
-	this.folder = cfg.FlagString("lirc.db", "", "Folder for keycode database")
+	this.folder = cfg.FlagPath("lirc.db", "", "Folder for keycode database")
 	this.ext = cfg.FlagString("lirc.ext", ".keycode", "Extension for keycode files")
 	return nil
 }
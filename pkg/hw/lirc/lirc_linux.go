@@ -3,6 +3,7 @@
 package lirc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -258,8 +259,43 @@ func (this *lirc) SetSendCarrierHz(uint32) error {
 ////////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS - SEND
 
-func (this *lirc) PulseSend([]uint32) error {
-	return gopi.ErrNotImplemented
+func (this *lirc) PulseSend(values []uint32) error {
+	var result error
+	var sent bool
+
+	// Send pulses on all send devices
+	for _, device := range this.devices {
+		if device.send {
+			if err := device.PulseSend(values); err != nil {
+				result = multierror.Append(result, err)
+			}
+			sent = true
+		}
+	}
+	if sent == false {
+		return gopi.ErrNotImplemented
+	} else {
+		return result
+	}
+}
+
+// PulseSendContext sends pulses in the background, and returns the
+// context error if the context is done first
+func (this *lirc) PulseSendContext(ctx context.Context, values []uint32) error {
+	done := make(chan error, 1)
+	go func() {
+		if err := ctx.Err(); err != nil {
+			done <- err
+		} else {
+			done <- this.PulseSend(values)
+		}
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
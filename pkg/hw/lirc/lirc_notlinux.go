@@ -3,6 +3,8 @@
 package lirc
 
 import (
+	"context"
+
 	gopi "github.com/djthorpe/gopi/v3"
 )
 
@@ -71,6 +73,10 @@ func (this *lirc) PulseSend([]uint32) error {
 	return gopi.ErrNotImplemented
 }
 
+func (this *lirc) PulseSendContext(context.Context, []uint32) error {
+	return gopi.ErrNotImplemented
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
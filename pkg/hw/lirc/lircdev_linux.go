@@ -174,7 +174,7 @@ func (this *lircdev) PulseSend(values []uint32) error {
 	}
 	// Set send mode
 	if this.send_mode != gopi.LIRC_MODE_PULSE {
-		if err := this.SetSendMode(gopi.LIRC_MODE_PULSE); err != nil {
+		if err := this.setSendMode(gopi.LIRC_MODE_PULSE); err != nil {
 			return err
 		}
 	}
@@ -258,7 +258,11 @@ func (this *lircdev) SetRcvMode(mode gopi.LIRCMode) error {
 func (this *lircdev) SetSendMode(mode gopi.LIRCMode) error {
 	this.Mutex.Lock()
 	defer this.Mutex.Unlock()
+	return this.setSendMode(mode)
+}
 
+// setSendMode sets the send mode, and is called with the device locked
+func (this *lircdev) setSendMode(mode gopi.LIRCMode) error {
 	if this.send == false {
 		return gopi.ErrOutOfOrder.WithPrefix("SetSendMode")
 	}
@@ -35,5 +35,11 @@ func (this *Platform) String() string {
 	if av1, av5, av15 := this.LoadAverages(); av1 != 0 || av5 != 0 || av15 != 0 {
 		str += fmt.Sprintf(" load_avg={ %.2f, %.2f, %.2f }", av1, av5, av15)
 	}
+	if memory := this.GPUMemory(); memory != nil {
+		str += fmt.Sprintf(" gpu_mem={ gpu=%vM reloc=%vM/%vM }", memory["gpu"]>>20, memory["reloc"]>>20, memory["reloc_total"]>>20)
+	}
+	if supported, detected := this.Camera(); supported {
+		str += fmt.Sprint(" camera=", detected)
+	}
 	return str + ">"
 }
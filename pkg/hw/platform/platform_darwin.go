@@ -72,3 +72,18 @@ func (this *Platform) Product() string {
 func (this *Platform) TemperatureZones() map[string]float32 {
 	return nil
 }
+
+// GPU memory not currently supported
+func (this *Platform) GPUMemory() map[string]uint64 {
+	return nil
+}
+
+// Hardware codecs not currently supported
+func (this *Platform) Codecs() map[string]bool {
+	return nil
+}
+
+// Camera detection not currently supported
+func (this *Platform) Camera() (bool, bool) {
+	return false, false
+}
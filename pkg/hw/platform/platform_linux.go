@@ -30,3 +30,18 @@ func (this *Platform) SerialNumber() string {
 func (this *Platform) Product() string {
 	return "linux"
 }
+
+// GPU memory not currently supported
+func (this *Platform) GPUMemory() map[string]uint64 {
+	return nil
+}
+
+// Hardware codecs not currently supported
+func (this *Platform) Codecs() map[string]bool {
+	return nil
+}
+
+// Camera detection not currently supported
+func (this *Platform) Camera() (bool, bool) {
+	return false, false
+}
@@ -84,3 +84,30 @@ func (this *Platform) Product() string {
 		return fmt.Sprint(productinfo.Model)
 	}
 }
+
+// Return GPU memory values
+func (this *Platform) GPUMemory() map[string]uint64 {
+	if memory, err := rpi.VCMemory(); err != nil {
+		return nil
+	} else {
+		return memory
+	}
+}
+
+// Return hardware codecs
+func (this *Platform) Codecs() map[string]bool {
+	if codecs, err := rpi.VCCodecs(); err != nil {
+		return nil
+	} else {
+		return codecs
+	}
+}
+
+// Return camera supported and detected
+func (this *Platform) Camera() (bool, bool) {
+	if supported, detected, err := rpi.VCCamera(); err != nil {
+		return false, false
+	} else {
+		return supported, detected
+	}
+}
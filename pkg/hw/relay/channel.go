@@ -0,0 +1,152 @@
+package relay
+
+import (
+	"fmt"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type channel struct {
+	name      string
+	pin       gopi.GPIOPin
+	addr      uint8
+	bit       uint8
+	activeLow bool
+	minOn     time.Duration
+	minOff    time.Duration
+	interlock []*channel
+	safe      bool
+
+	state   bool
+	changed time.Time
+}
+
+// config is the entry for a channel in the channels file
+type config struct {
+	GPIO      *uint    `json:"gpio"`
+	I2C       uint     `json:"i2c"`
+	Bit       uint     `json:"bit"`
+	ActiveLow bool     `json:"active_low"`
+	MinOn     string   `json:"min_on"`
+	MinOff    string   `json:"min_off"`
+	Interlock []string `json:"interlock"`
+	Safe      bool     `json:"safe"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// PCF8574 and PCF8574A addresses
+	pcf8574Min = 0x20
+	pcf8574Max = 0x3F
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newChannel(name string, cfg config) (*channel, error) {
+	this := &channel{name: name, pin: gopi.GPIO_PIN_NONE, activeLow: cfg.ActiveLow, safe: cfg.Safe}
+	if cfg.GPIO != nil && cfg.I2C != 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix(name, ": Both gpio and i2c")
+	} else if cfg.GPIO != nil {
+		if *cfg.GPIO >= uint(gopi.GPIO_PIN_NONE) {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": gpio ", *cfg.GPIO)
+		}
+		this.pin = gopi.GPIOPin(*cfg.GPIO)
+	} else if cfg.I2C != 0 {
+		if cfg.I2C < pcf8574Min || cfg.I2C > pcf8574Max {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": i2c ", cfg.I2C)
+		} else if cfg.Bit > 7 {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": bit ", cfg.Bit)
+		}
+		this.addr, this.bit = uint8(cfg.I2C), uint8(cfg.Bit)
+	} else {
+		return nil, gopi.ErrBadParameter.WithPrefix(name, ": Missing gpio or i2c")
+	}
+	if d, err := parseDuration(cfg.MinOn); err != nil {
+		return nil, gopi.ErrBadParameter.WithPrefix(name, ": min_on ", cfg.MinOn)
+	} else {
+		this.minOn = d
+	}
+	if d, err := parseDuration(cfg.MinOff); err != nil {
+		return nil, gopi.ErrBadParameter.WithPrefix(name, ": min_off ", cfg.MinOff)
+	} else {
+		this.minOff = d
+	}
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+// level returns the output level for a state
+func (this *channel) level(state bool) bool {
+	return state != this.activeLow
+}
+
+// wait returns the time remaining before the channel can be switched
+func (this *channel) wait(now time.Time) time.Duration {
+	dwell := this.minOff
+	if this.state {
+		dwell = this.minOn
+	}
+	return this.changed.Add(dwell).Sub(now)
+}
+
+// interlocked returns a channel which prevents this channel from being
+// switched on, or nil
+func (this *channel) interlocked() *channel {
+	for _, other := range this.interlock {
+		if other.state {
+			return other
+		}
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *channel) String() string {
+	str := "<relay.channel"
+	str += fmt.Sprintf(" name=%q", this.name)
+	if this.pin != gopi.GPIO_PIN_NONE {
+		str += fmt.Sprint(" gpio=", this.pin)
+	} else {
+		str += fmt.Sprintf(" i2c=0x%02X bit=%v", this.addr, this.bit)
+	}
+	if this.activeLow {
+		str += " active_low"
+	}
+	if this.minOn > 0 {
+		str += fmt.Sprint(" min_on=", this.minOn)
+	}
+	if this.minOff > 0 {
+		str += fmt.Sprint(" min_off=", this.minOff)
+	}
+	for _, other := range this.interlock {
+		str += fmt.Sprintf(" interlock=%q", other.name)
+	}
+	str += fmt.Sprint(" state=", this.state)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func parseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	} else if d, err := time.ParseDuration(value); err != nil {
+		return 0, err
+	} else if d < 0 {
+		return 0, gopi.ErrBadParameter
+	} else {
+		return d, nil
+	}
+}
@@ -0,0 +1,73 @@
+package relay
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Channel_001(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "channels.json")
+	for _, data := range []string{
+		`{ "heat": { } }`,
+		`{ "heat": { "gpio": 17, "i2c": 32 } }`,
+		`{ "heat": { "gpio": 255 } }`,
+		`{ "heat": { "i2c": 16 } }`,
+		`{ "heat": { "i2c": 32, "bit": 8 } }`,
+		`{ "heat": { "gpio": 17, "min_on": "-1s" } }`,
+		`{ "heat": { "gpio": 17, "min_off": "soon" } }`,
+		`{ "heat": { "gpio": 17, "interlock": [ "cool" ] } }`,
+		`{ "heat": { "gpio": 17, "interlock": [ "heat" ] } }`,
+		`{ " ": { "gpio": 17 } }`,
+	} {
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		} else if _, err := readChannels(path); err == nil {
+			t.Error("Expected error for", data)
+		} else {
+			t.Log(err)
+		}
+	}
+}
+
+func Test_Channel_002(t *testing.T) {
+	dir, err := ioutil.TempDir("", "relay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "channels.json")
+	data := `{
+		"heat": { "gpio": 17, "interlock": [ "cool", "fan" ] },
+		"cool": { "i2c": 32, "bit": 3, "active_low": true, "min_on": "1m", "interlock": [ "heat" ] },
+		"fan": { "i2c": 56, "min_off": "30s" }
+	}`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	channels, err := readChannels(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	heat, cool, fan := channels["heat"], channels["cool"], channels["fan"]
+	if len(heat.interlock) != 2 || len(cool.interlock) != 1 || len(fan.interlock) != 1 || fan.interlock[0] != heat {
+		t.Error("Unexpected interlocks", channels)
+	}
+	if cool.addr != 0x20 || cool.bit != 3 || cool.level(true) != false || cool.level(false) != true {
+		t.Error("Unexpected channel", cool)
+	}
+	if heat.level(true) != true {
+		t.Error("Unexpected channel", heat)
+	}
+	for _, channel := range channels {
+		t.Log(channel)
+	}
+}
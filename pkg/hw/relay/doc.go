@@ -0,0 +1,24 @@
+// Relay package implements gopi.Relay, which switches named channels on
+// relay boards and contactors. Channels are read from a JSON file set
+// with the -relay.channels flag, which maps a name to either a GPIO pin
+// or a bit on a PCF8574 I2C port expander:
+//
+//	{
+//	  "heat": { "gpio": 17, "active_low": true, "min_off": "3m", "interlock": [ "cool" ] },
+//	  "cool": { "gpio": 27, "active_low": true, "min_on": "1m", "min_off": "5m" },
+//	  "pump": { "i2c": 32, "bit": 3, "safe": true }
+//	}
+//
+// A channel which is active low drives its output low when switched on.
+// The minimum on and off times protect contactors and compressors from
+// short cycling, so switching a channel waits until the time since it
+// last changed has elapsed. The minimum off time also applies from
+// startup. Interlocks are mutual, so a channel cannot be switched on
+// while any channel it is interlocked with is on.
+//
+// Every channel is switched to its safe state on startup and shutdown,
+// which is off unless "safe" is true. GPIO channels require a gopi.GPIO
+// unit and expander channels require a gopi.I2C unit, using the bus set
+// with the -relay.bus flag. A gopi.RelayEvent is emitted when a channel
+// is switched.
+package relay
@@ -0,0 +1,44 @@
+package relay
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	channel string
+	state   bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(channel string, state bool) gopi.RelayEvent {
+	return &event{channel, state}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.channel
+}
+
+func (this *event) Channel() string {
+	return this.channel
+}
+
+func (this *event) State() bool {
+	return this.state
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	return fmt.Sprintf("<relay.event channel=%q state=%v>", this.channel, this.state)
+}
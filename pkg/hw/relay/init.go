@@ -0,0 +1,13 @@
+package relay
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.Relay
+	graph.RegisterUnit(reflect.TypeOf(&relay{}), reflect.TypeOf((*gopi.Relay)(nil)))
+}
@@ -0,0 +1,289 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type relay struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.GPIO
+	gopi.I2C
+	sync.Mutex
+
+	// Flags
+	path *string
+	bus  *uint
+
+	channels  map[string]*channel
+	expanders map[uint8]uint8
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *relay) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagPath("relay.channels", "", "JSON file of relay channels")
+	this.bus = cfg.FlagUint("relay.bus", 1, "I2C bus for port expanders")
+	return nil
+}
+
+func (this *relay) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Read channels
+	if *this.path == "" {
+		return gopi.ErrBadParameter.WithPrefix("-relay.channels")
+	} else if channels, err := readChannels(*this.path); err != nil {
+		return err
+	} else {
+		this.channels = channels
+	}
+
+	// Check for GPIO and I2C units, and set the initial state of
+	// port expanders with all outputs high
+	this.expanders = make(map[uint8]uint8)
+	for _, channel := range this.channels {
+		if channel.pin != gopi.GPIO_PIN_NONE && this.GPIO == nil {
+			return gopi.ErrInternalAppError.WithPrefix("Missing gopi.GPIO for ", strconv.Quote(channel.name))
+		} else if channel.pin == gopi.GPIO_PIN_NONE && this.I2C == nil {
+			return gopi.ErrInternalAppError.WithPrefix("Missing gopi.I2C for ", strconv.Quote(channel.name))
+		} else if channel.pin == gopi.GPIO_PIN_NONE {
+			this.expanders[channel.addr] = 0xFF
+		}
+	}
+
+	// Switch to safe state
+	if err := this.Safe(); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *relay) Dispose() error {
+	// Switch to safe state
+	err := this.Safe()
+
+	// Release resources
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.channels = nil
+	this.expanders = nil
+
+	// Return any errors
+	return err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *relay) Channels() []string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	result := make([]string, 0, len(this.channels))
+	for name := range this.channels {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (this *relay) State(name string) (bool, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if channel, exists := this.channels[name]; exists == false {
+		return false, gopi.ErrNotFound.WithPrefix("State: ", strconv.Quote(name))
+	} else {
+		return channel.state, nil
+	}
+}
+
+func (this *relay) Set(ctx context.Context, name string, state bool) error {
+	for {
+		// Switch the channel when the minimum on or off time has elapsed
+		wait, err := this.set(name, state)
+		if err != nil || wait <= 0 {
+			return err
+		}
+
+		// Wait and then try again, as other channels may have changed
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+			break
+		}
+	}
+}
+
+func (this *relay) Safe() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Switch off channels first, so that interlocked channels are never
+	// on at the same time
+	var result error
+	for _, state := range []bool{false, true} {
+		for _, channel := range this.channels {
+			if channel.safe != state {
+				continue
+			} else if state && channel.interlocked() != nil {
+				result = multierror.Append(result, gopi.ErrOutOfOrder.WithPrefix("Safe: ", strconv.Quote(channel.name)))
+			} else if err := this.write(channel, state); err != nil {
+				result = multierror.Append(result, err)
+			}
+		}
+	}
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *relay) String() string {
+	str := "<relay"
+	for _, name := range this.Channels() {
+		this.Mutex.Lock()
+		if channel, exists := this.channels[name]; exists {
+			str += fmt.Sprint(" ", channel)
+		}
+		this.Mutex.Unlock()
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// readChannels returns channels from a JSON file, and links interlocked
+// channels in both directions
+func readChannels(path string) (map[string]*channel, error) {
+	var channels map[string]config
+	if data, err := ioutil.ReadFile(path); err != nil {
+		return nil, err
+	} else if err := json.Unmarshal(data, &channels); err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	result := make(map[string]*channel, len(channels))
+	for name, cfg := range channels {
+		if name = strings.TrimSpace(name); name == "" {
+			return nil, gopi.ErrBadParameter.WithPrefix(path, ": Missing name")
+		} else if channel, err := newChannel(name, cfg); err != nil {
+			return nil, err
+		} else {
+			result[name] = channel
+		}
+	}
+	for name, cfg := range channels {
+		channel := result[strings.TrimSpace(name)]
+		for _, interlock := range cfg.Interlock {
+			if other, exists := result[interlock]; exists == false || other == channel {
+				return nil, gopi.ErrBadParameter.WithPrefix(name, ": interlock ", strconv.Quote(interlock))
+			} else {
+				channel.interlock = appendChannel(channel.interlock, other)
+				other.interlock = appendChannel(other.interlock, channel)
+			}
+		}
+	}
+	return result, nil
+}
+
+// set switches a channel unless an interlock prevents it, or returns
+// the time remaining before it can be switched
+func (this *relay) set(name string, state bool) (time.Duration, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	channel, exists := this.channels[name]
+	if exists == false {
+		return 0, gopi.ErrNotFound.WithPrefix("Set: ", strconv.Quote(name))
+	} else if channel.state == state {
+		return 0, nil
+	} else if other := channel.interlocked(); state && other != nil {
+		return 0, gopi.ErrOutOfOrder.WithPrefix("Set: ", strconv.Quote(name), " interlocked with ", strconv.Quote(other.name))
+	} else if wait := channel.wait(time.Now()); wait > 0 {
+		return wait, nil
+	} else if err := this.write(channel, state); err != nil {
+		return 0, err
+	}
+
+	// Emit event
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(NewEvent(name, state), false); err != nil {
+			this.Debug("Relay: ", err)
+		}
+	}
+
+	// Return success
+	return 0, nil
+}
+
+// write sets the output for a channel, and records when it changed
+func (this *relay) write(channel *channel, state bool) error {
+	if channel.pin != gopi.GPIO_PIN_NONE {
+		level := gopi.GPIO_LOW
+		if channel.level(state) {
+			level = gopi.GPIO_HIGH
+		}
+		if err := this.GPIO.Batch(func(tx gopi.GPIOTx) error {
+			tx.SetPinMode(channel.pin, gopi.GPIO_OUTPUT)
+			tx.WritePin(channel.pin, level)
+			return nil
+		}); err != nil {
+			return err
+		}
+	} else {
+		value := this.expanders[channel.addr] &^ (1 << channel.bit)
+		if channel.level(state) {
+			value |= 1 << channel.bit
+		}
+		if err := this.I2C.Batch(gopi.I2CBus(*this.bus), func(tx gopi.I2CTx) error {
+			if err := tx.SetSlave(channel.addr); err != nil {
+				return err
+			} else if _, err := tx.Write([]byte{value}); err != nil {
+				return err
+			} else {
+				return nil
+			}
+		}); err != nil {
+			return err
+		}
+		this.expanders[channel.addr] = value
+	}
+	channel.state = state
+	channel.changed = time.Now()
+	return nil
+}
+
+// appendChannel appends a channel to a list if it is not already present
+func appendChannel(channels []*channel, channel *channel) []*channel {
+	for _, other := range channels {
+		if other == channel {
+			return channels
+		}
+	}
+	return append(channels, channel)
+}
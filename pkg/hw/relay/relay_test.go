@@ -0,0 +1,136 @@
+package relay_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/hw/relay"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Relay
+	gopi.GPIO
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&testutil.GPIO{}), reflect.TypeOf((*gopi.GPIO)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+const channels = `{
+	"heat": { "gpio": 17, "active_low": true, "min_on": "200ms", "interlock": [ "cool" ] },
+	"cool": { "gpio": 27 },
+	"pump": { "gpio": 22, "safe": true }
+}`
+
+func Test_Relay_001(t *testing.T) {
+	path := testutil.TempFile(t, "channels.json", channels)
+
+	var pins *testutil.GPIO
+	tool.Test(t, []string{"-relay.channels", path}, new(App), func(app *App) {
+		pins = app.GPIO.(*testutil.GPIO)
+		t.Log(app.Relay)
+		if channels := app.Relay.Channels(); reflect.DeepEqual(channels, []string{"cool", "heat", "pump"}) == false {
+			t.Error("Unexpected channels", channels)
+		}
+
+		// Safe state on startup
+		if state, err := app.Relay.State("pump"); err != nil || state != true {
+			t.Error("Unexpected state", state, err)
+		}
+		if pins.ReadPin(17) != gopi.GPIO_HIGH || pins.ReadPin(27) != gopi.GPIO_LOW || pins.ReadPin(22) != gopi.GPIO_HIGH {
+			t.Error("Unexpected pins", pins.ReadPin(17), pins.ReadPin(27), pins.ReadPin(22))
+		} else if pins.GetPinMode(17) != gopi.GPIO_OUTPUT {
+			t.Error("Unexpected mode", pins.GetPinMode(17))
+		}
+		if _, err := app.Relay.State("fan"); errors.Is(err, gopi.ErrNotFound) == false {
+			t.Error("Unexpected error", err)
+		}
+	})
+
+	// Safe state on shutdown
+	if pins == nil {
+		t.Error("Missing gpio")
+	} else if pins.ReadPin(17) != gopi.GPIO_HIGH || pins.ReadPin(22) != gopi.GPIO_HIGH {
+		t.Error("Unexpected pins", pins.ReadPin(17), pins.ReadPin(22))
+	}
+}
+
+func Test_Relay_002(t *testing.T) {
+	path := testutil.TempFile(t, "channels.json", channels)
+
+	tool.Test(t, []string{"-relay.channels", path}, new(App), func(app *App) {
+		pins := app.GPIO.(*testutil.GPIO)
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Active low channel drives the pin low when on
+		ctx := context.Background()
+		if err := app.Relay.Set(ctx, "heat", true); err != nil {
+			t.Error(err)
+		} else if pins.ReadPin(17) != gopi.GPIO_LOW {
+			t.Error("Unexpected pin", pins.ReadPin(17))
+		}
+		select {
+		case evt := <-ch:
+			if evt, ok := evt.(gopi.RelayEvent); ok == false || evt.Channel() != "heat" || evt.State() != true {
+				t.Error("Unexpected event", evt)
+			}
+		case <-time.After(time.Second):
+			t.Error("Timeout waiting for event")
+		}
+
+		// Interlocks are mutual
+		if err := app.Relay.Set(ctx, "cool", true); errors.Is(err, gopi.ErrOutOfOrder) == false {
+			t.Error("Unexpected error", err)
+		}
+
+		// Switching off waits for the minimum on time
+		timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		if err := app.Relay.Set(timeout, "heat", false); errors.Is(err, context.DeadlineExceeded) == false {
+			t.Error("Unexpected error", err)
+		}
+		if err := app.Relay.Set(ctx, "heat", false); err != nil {
+			t.Error(err)
+		} else if pins.ReadPin(17) != gopi.GPIO_HIGH {
+			t.Error("Unexpected pin", pins.ReadPin(17))
+		}
+		if err := app.Relay.Set(ctx, "cool", true); err != nil {
+			t.Error(err)
+		} else if pins.ReadPin(27) != gopi.GPIO_HIGH {
+			t.Error("Unexpected pin", pins.ReadPin(27))
+		}
+
+		// Safe state switches off everything except the pump
+		if err := app.Relay.Safe(); err != nil {
+			t.Error(err)
+		} else if state, _ := app.Relay.State("cool"); state != false {
+			t.Error("Unexpected state", state)
+		}
+	})
+}
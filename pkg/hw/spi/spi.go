@@ -2,7 +2,9 @@ package spi
 
 import (
 	// Frameworks
+	"context"
 	"fmt"
+	"strconv"
 	"sync"
 
 	gopi "github.com/djthorpe/gopi/v3"
@@ -16,6 +18,7 @@ type spi struct {
 	gopi.Unit
 	sync.Mutex
 
+	bus     int // bus for an instance named with a bus number, or -1
 	devices map[gopi.SPIBus]*device
 }
 
@@ -30,6 +33,16 @@ const (
 // INIT
 
 func (this *spi) New(gopi.Config) error {
+	// An instance named with a bus number only opens that bus
+	this.bus = -1
+	if name := this.InstanceName(); name != "" {
+		if bus, err := strconv.ParseUint(name, 10, 32); err != nil || bus > maxBus {
+			return gopi.ErrBadParameter.WithPrefix("Invalid bus: ", strconv.Quote(name))
+		} else {
+			this.bus = int(bus)
+		}
+	}
+
 	this.devices = make(map[gopi.SPIBus]*device, maxBus)
 	return nil
 }
@@ -57,6 +70,9 @@ func (this *spi) Dispose() error {
 
 func (this *spi) String() string {
 	str := "<spi"
+	if name := this.InstanceName(); name != "" {
+		str += " instance=" + strconv.Quote(name)
+	}
 	for bus, device := range this.devices {
 		str += fmt.Sprintf(" device[%v]=%v", bus, device)
 	}
@@ -70,6 +86,10 @@ func (this *spi) Open(bus gopi.SPIBus) (*device, error) {
 	this.Mutex.Lock()
 	defer this.Mutex.Unlock()
 
+	if err := this.checkBus(bus); err != nil {
+		return nil, err
+	}
+
 	if d, exists := this.devices[bus]; exists {
 		return d, nil
 	}
@@ -81,6 +101,30 @@ func (this *spi) Open(bus gopi.SPIBus) (*device, error) {
 	}
 }
 
+// TransferContext transfers data in the background, and returns the
+// context error if the context is done first
+func (this *spi) TransferContext(ctx context.Context, bus gopi.SPIBus, data []byte) ([]byte, error) {
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		if err := ctx.Err(); err != nil {
+			done <- result{nil, err}
+		} else {
+			out, err := this.Transfer(bus, data)
+			done <- result{out, err}
+		}
+	}()
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 func (this *spi) Close(bus gopi.SPIBus) error {
 	this.Mutex.Lock()
 	defer this.Mutex.Unlock()
@@ -92,3 +136,15 @@ func (this *spi) Close(bus gopi.SPIBus) error {
 		return d.Close()
 	}
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// checkBus returns an error if a named instance is used with another bus
+func (this *spi) checkBus(bus gopi.SPIBus) error {
+	if this.bus >= 0 && bus.Bus != uint(this.bus) {
+		return gopi.ErrBadParameter.WithPrefix("Bus ", bus, " not available for instance ", strconv.Quote(this.InstanceName()))
+	} else {
+		return nil
+	}
+}
@@ -15,6 +15,9 @@ import (
 func (this *spi) Devices() []gopi.SPIBus {
 	devices := []gopi.SPIBus{}
 	for bus := uint(0); bus <= maxBus; bus++ {
+		if this.bus >= 0 && bus != uint(this.bus) {
+			continue
+		}
 		if _, err := os.Stat(linux.SPIDevice(bus, 0)); os.IsNotExist(err) == false {
 			devices = append(devices, gopi.SPIBus{bus, 0})
 		}
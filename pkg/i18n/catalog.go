@@ -0,0 +1,163 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// catalog is the messages for a language, keyed by their text in English
+type catalog map[string]message
+
+// message is the text of a message, or the text for each plural category
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	defaultLanguage = "en"
+	catalogExt      = ".json"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *message) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &this.text); err == nil {
+		return nil
+	} else if err := json.Unmarshal(data, &this.plural); err != nil {
+		return err
+	}
+	for category := range this.plural {
+		switch category {
+		case pluralZero, pluralOne, pluralTwo, pluralFew, pluralMany, pluralOther:
+			break
+		default:
+			return gopi.ErrBadParameter.WithPrefix(category)
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// readCatalogs returns the catalogs in a folder, keyed by the language
+// which is the name of each file
+func readCatalogs(path string) (map[string]catalog, error) {
+	files, err := filepath.Glob(filepath.Join(path, "*"+catalogExt))
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]catalog, len(files))
+	for _, file := range files {
+		tag, err := parseTag(strings.TrimSuffix(filepath.Base(file), catalogExt))
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", filepath.Base(file), err)
+		}
+		if c, err := readCatalog(file); err != nil {
+			return nil, fmt.Errorf("%v: %w", filepath.Base(file), err)
+		} else {
+			result[tag] = c
+		}
+	}
+
+	// Return success
+	return result, nil
+}
+
+func readCatalog(path string) (catalog, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	c := make(catalog)
+	if err := json.NewDecoder(fh).Decode(&c); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return c, nil
+}
+
+// parseTag returns a language tag from a tag or an environment value,
+// so that "fr_FR.UTF-8" returns "fr-FR", and "C" or "POSIX" return "en"
+func parseTag(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if i := strings.IndexAny(value, ".@"); i >= 0 {
+		value = value[:i]
+	}
+	if value == "C" || value == "POSIX" {
+		return defaultLanguage, nil
+	}
+
+	parts := strings.FieldsFunc(value, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 {
+		return "", gopi.ErrBadParameter.WithPrefix("language")
+	}
+	for i, part := range parts {
+		if isAlpha(part) == false && (i == 0 || isDigit(part) == false) {
+			return "", gopi.ErrBadParameter.WithPrefix("language")
+		}
+		switch {
+		case i == 0:
+			if len(part) < 2 || len(part) > 3 {
+				return "", gopi.ErrBadParameter.WithPrefix("language")
+			}
+			parts[i] = strings.ToLower(part)
+		case len(part) == 4:
+			// Script, such as Hant
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		case len(part) == 2 || len(part) == 3:
+			// Region, such as GB or 419
+			parts[i] = strings.ToUpper(part)
+		default:
+			return "", gopi.ErrBadParameter.WithPrefix("language")
+		}
+	}
+
+	// Return success
+	return strings.Join(parts, "-"), nil
+}
+
+// base returns the language of a tag without a script or region
+func base(tag string) string {
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+func isAlpha(value string) bool {
+	for _, r := range value {
+		if r > unicode.MaxASCII || unicode.IsLetter(r) == false {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
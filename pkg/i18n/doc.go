@@ -0,0 +1,30 @@
+// I18n package implements gopi.Locale, which translates the messages of
+// device interfaces and formats numbers, dates and times for a language.
+//
+// The language is set with -i18n.language, such as "fr" or "en-GB", or
+// otherwise from the LC_ALL, LC_MESSAGES or LANG environment variables,
+// and can be changed while running. Messages are translated with the
+// catalogs in the -i18n.path folder, which has a JSON file for each
+// language named for the language, such as "fr.json" or "pt-BR.json".
+// Messages are identified by their text in English, and a message is
+// looked up in the catalog for the language, then in the catalog for
+// the language without a region, and otherwise is shown in English.
+// Plural messages have text for each plural category of the language
+// (zero, one, two, few, many or other), for example:
+//
+//   {
+//     "Hello, %v": "Bonjour, %v",
+//     "%d photo": { "one": "%d photo", "other": "%d photos" }
+//   }
+//
+// which is used as:
+//
+//   locale.Translate("Hello, %v", name)
+//   locale.Plural("%d photo", "%d photos", n, n)
+//
+// Numbers, dates and times are formatted with the separators, layouts
+// and names of days and months of the language for English, German,
+// French, Spanish, Italian and Dutch, with regional layouts such as
+// en-US. For other languages, catalogs can translate the English names
+// of days and months, such as "January" and "Jan".
+package i18n
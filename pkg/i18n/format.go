@@ -0,0 +1,232 @@
+package i18n
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// format is how numbers, dates and times are formatted for a language
+type format struct {
+	decimal, group      string
+	date, time, long    string
+	months, shortMonths []string
+	days, shortDays     []string
+}
+
+// token is a name in a layout of the time package
+type token struct {
+	token string
+	kind  name
+}
+
+type name uint
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	nameMonth name = iota
+	nameShortMonth
+	nameDay
+	nameShortDay
+	nameAMPM
+)
+
+var (
+	// tokens are names in layouts, longest first
+	tokens = []token{
+		{"January", nameMonth}, {"Jan", nameShortMonth},
+		{"Monday", nameDay}, {"Mon", nameShortDay},
+		{"PM", nameAMPM}, {"pm", nameAMPM},
+	}
+
+	// formats for languages
+	formats = map[string]format{
+		"en": {
+			decimal: ".", group: ",",
+			date: "02/01/2006", time: "15:04", long: "Monday 2 January 2006",
+			months:      []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+			shortMonths: []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+			days:        []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+			shortDays:   []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		},
+		"de": {
+			decimal: ",", group: ".",
+			date: "02.01.2006", time: "15:04", long: "Monday, 2. January 2006",
+			months:      []string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+			shortMonths: []string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+			days:        []string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+			shortDays:   []string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		},
+		"fr": {
+			decimal: ",", group: "\u202f",
+			date: "02/01/2006", time: "15:04", long: "Monday 2 January 2006",
+			months:      []string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+			shortMonths: []string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+			days:        []string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+			shortDays:   []string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		},
+		"es": {
+			decimal: ",", group: ".",
+			date: "2/1/2006", time: "15:04", long: "Monday, 2 de January de 2006",
+			months:      []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+			shortMonths: []string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+			days:        []string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+			shortDays:   []string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		},
+		"it": {
+			decimal: ",", group: ".",
+			date: "02/01/2006", time: "15:04", long: "Monday 2 January 2006",
+			months:      []string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+			shortMonths: []string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+			days:        []string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+			shortDays:   []string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		},
+		"nl": {
+			decimal: ",", group: ".",
+			date: "02-01-2006", time: "15:04", long: "Monday 2 January 2006",
+			months:      []string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+			shortMonths: []string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+			days:        []string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+			shortDays:   []string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+		},
+	}
+
+	// regions are the differences from the language for a region
+	regions = map[string]format{
+		"en-US": {date: "1/2/2006", time: "3:04 PM", long: "Monday, January 2, 2006"},
+		"en-CA": {date: "2006-01-02", time: "3:04 PM", long: "Monday, January 2, 2006"},
+		"en-AU": {date: "2/01/2006"},
+		"de-AT": {group: "\u00a0"},
+		"de-CH": {decimal: ".", group: "’"},
+		"fr-CA": {date: "2006-01-02", group: "\u00a0"},
+		"fr-CH": {date: "02.01.2006", group: "\u00a0"},
+		"es-MX": {decimal: ".", group: ","},
+		"nl-BE": {date: "2/01/2006"},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// formatFor returns the format for a language with a region, or the
+// format for English when the language is not known
+func formatFor(tag string) format {
+	f, exists := formats[base(tag)]
+	if exists == false {
+		f = formats[defaultLanguage]
+	}
+	if r, exists := regions[tag]; exists {
+		for _, field := range [][2]*string{{&f.decimal, &r.decimal}, {&f.group, &r.group}, {&f.date, &r.date}, {&f.time, &r.time}, {&f.long, &r.long}} {
+			if *field[1] != "" {
+				*field[0] = *field[1]
+			}
+		}
+	}
+	return f
+}
+
+// hasNames returns true if the names of days and months are known for
+// a language
+func hasNames(tag string) bool {
+	_, exists := formats[base(tag)]
+	return exists
+}
+
+// layout returns a layout for a language
+func (f format) layout(l gopi.LocaleLayout) string {
+	switch l {
+	case gopi.LOCALE_LAYOUT_DATE:
+		return f.date
+	case gopi.LOCALE_LAYOUT_TIME:
+		return f.time
+	case gopi.LOCALE_LAYOUT_DATETIME:
+		return f.date + " " + f.time
+	case gopi.LOCALE_LAYOUT_LONGDATE:
+		return f.long
+	default:
+		return ""
+	}
+}
+
+// name returns the name of a day or month in a language
+func (f format) name(t time.Time, kind name) string {
+	switch kind {
+	case nameMonth:
+		return f.months[t.Month()-1]
+	case nameShortMonth:
+		return f.shortMonths[t.Month()-1]
+	case nameDay:
+		return f.days[t.Weekday()]
+	case nameShortDay:
+		return f.shortDays[t.Weekday()]
+	default:
+		return ""
+	}
+}
+
+// formatTime formats a time with a layout, where names of days and months
+// and AM or PM are returned by a function from the name in English
+func formatTime(t time.Time, layout string, fn func(string, name) string) string {
+	var b strings.Builder
+	start := 0
+	for i := 0; i < len(layout); {
+		var match *token
+		for j := range tokens {
+			if strings.HasPrefix(layout[i:], tokens[j].token) {
+				match = &tokens[j]
+				break
+			}
+		}
+		if match == nil {
+			i++
+			continue
+		}
+		if i > start {
+			b.WriteString(t.Format(layout[start:i]))
+		}
+		b.WriteString(fn(t.Format(match.token), match.kind))
+		i += len(match.token)
+		start = i
+	}
+	if start < len(layout) {
+		b.WriteString(t.Format(layout[start:]))
+	}
+	return b.String()
+}
+
+// formatNumber returns a number with decimal places, and with separators
+// between thousands and before the decimal places
+func formatNumber(v float64, places uint, decimal, group string) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	str := strconv.FormatFloat(math.Abs(v), 'f', int(places), 64)
+	whole, frac := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		whole, frac = str[:i], str[i+1:]
+	}
+
+	var b strings.Builder
+	if v < 0 && strings.Trim(str, "0.") != "" {
+		b.WriteString("-")
+	}
+	for i := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(group)
+		}
+		b.WriteByte(whole[i])
+	}
+	if frac != "" {
+		b.WriteString(decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
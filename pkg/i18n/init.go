@@ -0,0 +1,13 @@
+package i18n
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.Locale
+	graph.RegisterUnit(reflect.TypeOf(&locale{}), reflect.TypeOf((*gopi.Locale)(nil)))
+}
@@ -0,0 +1,228 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type locale struct {
+	gopi.Unit
+	gopi.Logger
+	sync.RWMutex
+
+	// Flags
+	language *string
+	path     *string
+
+	tag      string
+	catalogs map[string]catalog
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// envLanguage are the environment variables with the language, in
+	// order of precedence
+	envLanguage = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *locale) Define(cfg gopi.Config) error {
+	this.language = cfg.FlagString("i18n.language", "", "Language, such as en-GB (default from environment)")
+	this.path = cfg.FlagPath("i18n.path", "", "Folder of message catalogs")
+	return nil
+}
+
+func (this *locale) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Set the language from the flag, or otherwise from the environment
+	if *this.language != "" {
+		if tag, err := parseTag(*this.language); err != nil {
+			return gopi.ErrBadParameter.WithPrefix("-i18n.language")
+		} else {
+			this.tag = tag
+		}
+	} else {
+		this.tag = defaultLanguage
+		for _, env := range envLanguage {
+			if value := os.Getenv(env); value == "" {
+				continue
+			} else if tag, err := parseTag(value); err != nil {
+				this.Debugf("Ignoring %v=%q", env, value)
+			} else {
+				this.tag = tag
+			}
+			break
+		}
+	}
+
+	// Read catalogs
+	if *this.path != "" {
+		if stat, err := os.Stat(*this.path); err != nil || stat.IsDir() == false {
+			return gopi.ErrBadParameter.WithPrefix("-i18n.path")
+		} else if catalogs, err := readCatalogs(*this.path); err != nil {
+			return err
+		} else {
+			this.catalogs = catalogs
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *locale) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.catalogs = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *locale) Language() string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.tag
+}
+
+func (this *locale) SetLanguage(value string) error {
+	tag, err := parseTag(value)
+	if err != nil {
+		return err
+	}
+
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	this.tag = tag
+
+	// Return success
+	return nil
+}
+
+func (this *locale) Languages() []string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	result := make([]string, 0, len(this.catalogs))
+	for tag := range this.catalogs {
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (this *locale) Translate(msg string, args ...interface{}) string {
+	this.RWMutex.RLock()
+	if m, exists := this.message(msg); exists && m.text != "" {
+		msg = m.text
+	}
+	this.RWMutex.RUnlock()
+	return sprintf(msg, args)
+}
+
+func (this *locale) Plural(one, other string, n int, args ...interface{}) string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	// Look up the message for the category, or otherwise the message for
+	// other counts, or otherwise the message in English
+	msg := other
+	if n == 1 {
+		msg = one
+	}
+	if m, exists := this.message(one); exists && m.plural != nil {
+		if text, exists := m.plural[plural(this.tag, n)]; exists {
+			msg = text
+		} else if text, exists := m.plural[pluralOther]; exists {
+			msg = text
+		}
+	}
+	return sprintf(msg, args)
+}
+
+func (this *locale) FormatNumber(v float64, places uint) string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	f := formatFor(this.tag)
+	return formatNumber(v, places, f.decimal, f.group)
+}
+
+func (this *locale) FormatTime(t time.Time, layout string) string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	f := formatFor(this.tag)
+	names := hasNames(this.tag)
+	return formatTime(t, layout, func(value string, kind name) string {
+		if m, exists := this.message(value); exists && m.text != "" {
+			return m.text
+		} else if names && kind != nameAMPM {
+			return f.name(t, kind)
+		} else {
+			return value
+		}
+	})
+}
+
+func (this *locale) Layout(l gopi.LocaleLayout) string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return formatFor(this.tag).layout(l)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *locale) String() string {
+	str := "<i18n"
+	str += fmt.Sprintf(" language=%q", this.Language())
+	if languages := this.Languages(); len(languages) > 0 {
+		str += fmt.Sprint(" catalogs=", languages)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// message returns a message from the catalog for the language, or from
+// the catalog for the language without a region
+func (this *locale) message(msg string) (message, bool) {
+	if c, exists := this.catalogs[this.tag]; exists {
+		if m, exists := c[msg]; exists {
+			return m, true
+		}
+	}
+	if c, exists := this.catalogs[base(this.tag)]; exists {
+		if m, exists := c[msg]; exists {
+			return m, true
+		}
+	}
+	return message{}, false
+}
+
+// sprintf returns a message formatted with arguments, or the message
+// when there are no arguments, so that messages can contain %
+func sprintf(msg string, args []interface{}) string {
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
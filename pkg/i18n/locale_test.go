@@ -0,0 +1,163 @@
+package i18n_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/i18n"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Locale
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	catalogs = map[string]string{
+		"fr.json": `{
+			"Hello, %v": "Bonjour, %v",
+			"%d photo": { "one": "%d photo", "other": "%d photos" }
+		}`,
+		"fr-CA.json": `{ "Hello, %v": "Allô, %v" }`,
+		"ru.json": `{
+			"%d photo": { "one": "%d фотография", "few": "%d фотографии", "many": "%d фотографий" },
+			"January": "января"
+		}`,
+	}
+	date = time.Date(2021, 1, 4, 15, 4, 0, 0, time.UTC)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Locale_001(t *testing.T) {
+	path := newCatalogs(t)
+	tool.Test(t, []string{"-i18n.path", path, "-i18n.language", "fr_FR.UTF-8"}, new(App), func(app *App) {
+		if tag := app.Locale.Language(); tag != "fr-FR" {
+			t.Error("Unexpected language", tag)
+		} else if languages := app.Locale.Languages(); len(languages) != 3 || languages[0] != "fr" || languages[2] != "ru" {
+			t.Error("Unexpected languages", languages)
+		}
+
+		// Messages for fr-FR are in the catalog for fr, and messages which
+		// are not in a catalog are in English
+		if msg := app.Locale.Translate("Hello, %v", "Marie"); msg != "Bonjour, Marie" {
+			t.Error("Unexpected message", msg)
+		} else if msg := app.Locale.Translate("Goodbye"); msg != "Goodbye" {
+			t.Error("Unexpected message", msg)
+		} else if msg := app.Locale.Translate("100%"); msg != "100%" {
+			t.Error("Unexpected message", msg)
+		}
+		if err := app.Locale.SetLanguage("fr-CA"); err != nil {
+			t.Error(err)
+		} else if msg := app.Locale.Translate("Hello, %v", "Marie"); msg != "Allô, Marie" {
+			t.Error("Unexpected message", msg)
+		}
+	})
+}
+
+func Test_Locale_002(t *testing.T) {
+	path := newCatalogs(t)
+	tool.Test(t, []string{"-i18n.path", path, "-i18n.language", "fr"}, new(App), func(app *App) {
+		// Zero is singular in French
+		tests := []struct {
+			language string
+			n        int
+			msg      string
+		}{
+			{"fr", 0, "0 photo"},
+			{"fr", 1, "1 photo"},
+			{"fr", 2, "2 photos"},
+			{"ru", 1, "1 фотография"},
+			{"ru", 3, "3 фотографии"},
+			{"ru", 5, "5 фотографий"},
+			{"ru", 21, "21 фотография"},
+			{"en", 0, "0 photos"},
+			{"en", 1, "1 photo"},
+		}
+		for _, test := range tests {
+			if err := app.Locale.SetLanguage(test.language); err != nil {
+				t.Fatal(err)
+			} else if msg := app.Locale.Plural("%d photo", "%d photos", test.n, test.n); msg != test.msg {
+				t.Errorf("%v %v: expected %q, got %q", test.language, test.n, test.msg, msg)
+			}
+		}
+
+		// Invalid languages return an error
+		if err := app.Locale.SetLanguage("not a language"); err == nil {
+			t.Error("Expected error for invalid language")
+		} else if tag := app.Locale.Language(); tag != "en" {
+			t.Error("Unexpected language", tag)
+		}
+	})
+}
+
+func Test_Locale_003(t *testing.T) {
+	path := newCatalogs(t)
+	tool.Test(t, []string{"-i18n.path", path, "-i18n.language", "de"}, new(App), func(app *App) {
+		tests := []struct {
+			language string
+			number   string
+			layout   string
+			time     string
+		}{
+			{"de", "1.234.567,89", "02.01.2006", "Montag, 4. Januar 2021"},
+			{"de-CH", "1’234’567.89", "02.01.2006", "Montag, 4. Januar 2021"},
+			{"en-GB", "1,234,567.89", "02/01/2006", "Monday 4 January 2021"},
+			{"en-US", "1,234,567.89", "1/2/2006", "Monday, January 4, 2021"},
+			{"fr", "1 234 567,89", "02/01/2006", "lundi 4 janvier 2021"},
+			{"ru", "1,234,567.89", "02/01/2006", "Monday 4 января 2021"},
+		}
+		for _, test := range tests {
+			if err := app.Locale.SetLanguage(test.language); err != nil {
+				t.Fatal(err)
+			}
+			if number := app.Locale.FormatNumber(1234567.891, 2); number != test.number {
+				t.Errorf("%v: expected %q, got %q", test.language, test.number, number)
+			}
+			if layout := app.Locale.Layout(gopi.LOCALE_LAYOUT_DATE); layout != test.layout {
+				t.Errorf("%v: expected %q, got %q", test.language, test.layout, layout)
+			}
+			if time := app.Locale.FormatTime(date, app.Locale.Layout(gopi.LOCALE_LAYOUT_LONGDATE)); time != test.time {
+				t.Errorf("%v: expected %q, got %q", test.language, test.time, time)
+			}
+		}
+
+		// Negative numbers which round to zero have no sign
+		if number := app.Locale.FormatNumber(-0.001, 1); number != "0.0" {
+			t.Error("Unexpected number", number)
+		} else if number := app.Locale.FormatNumber(-1000, 0); number != "-1,000" {
+			t.Error("Unexpected number", number)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func newCatalogs(t *testing.T) string {
+	t.Helper()
+	path, err := ioutil.TempDir("", "i18n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(path) })
+	for name, data := range catalogs {
+		if err := ioutil.WriteFile(filepath.Join(path, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
@@ -0,0 +1,80 @@
+package i18n
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	pluralZero  = "zero"
+	pluralOne   = "one"
+	pluralTwo   = "two"
+	pluralFew   = "few"
+	pluralMany  = "many"
+	pluralOther = "other"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// plural returns the plural category of a count for a language, with the
+// rules for whole numbers from the Unicode CLDR
+func plural(tag string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+	switch base(tag) {
+	case "ja", "zh", "ko", "th", "vi", "id", "ms", "tr":
+		return pluralOther
+	case "fr":
+		if n == 0 || n == 1 {
+			return pluralOne
+		}
+	case "pt":
+		if n == 1 || (n == 0 && tag != "pt-PT") {
+			return pluralOne
+		}
+	case "ru", "uk", "be":
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return pluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return pluralFew
+		default:
+			return pluralMany
+		}
+	case "pl":
+		switch {
+		case n == 1:
+			return pluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return pluralFew
+		default:
+			return pluralMany
+		}
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return pluralOne
+		case n >= 2 && n <= 4:
+			return pluralFew
+		}
+	case "ar":
+		switch {
+		case n == 0:
+			return pluralZero
+		case n == 1:
+			return pluralOne
+		case n == 2:
+			return pluralTwo
+		case mod100 >= 3 && mod100 <= 10:
+			return pluralFew
+		case mod100 >= 11:
+			return pluralMany
+		}
+	default:
+		if n == 1 {
+			return pluralOne
+		}
+	}
+	return pluralOther
+}
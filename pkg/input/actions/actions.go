@@ -0,0 +1,210 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type actions struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	sync.RWMutex
+
+	path    *string
+	context string
+	mapping *mapping
+	modtime time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Interval for checking for changes to the mapping file
+	reloadInterval = 2 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *actions) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagPath("input.actions", "", "Input action mapping file")
+	cfg.FlagString("input.context", "default", "Initial input action context")
+	return nil
+}
+
+func (this *actions) New(cfg gopi.Config) error {
+	this.Require(this.Logger, this.Publisher)
+
+	if *this.path == "" {
+		return gopi.ErrBadParameter.WithPrefix("-input.actions")
+	} else if err := this.SetContext(cfg.GetString("input.context")); err != nil {
+		return err
+	} else if err := this.Reload(); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *actions) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.mapping = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *actions) Run(ctx context.Context) error {
+	ch := this.Publisher.Subscribe()
+	defer this.Publisher.Unsubscribe(ch)
+
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-ch:
+			if evt := this.action(evt); evt != nil {
+				if err := this.Publisher.Emit(evt, false); err != nil {
+					this.Print("InputActions: ", err)
+				}
+			}
+		case <-ticker.C:
+			// Reload the mapping when the file has changed, keeping the
+			// existing mapping when the file cannot be read
+			if changed, err := this.changed(); err != nil {
+				this.Debug("InputActions: ", err)
+			} else if changed {
+				if err := this.Reload(); err != nil {
+					this.Print("InputActions: ", err)
+				} else {
+					this.Debug("InputActions: Reloaded ", *this.path)
+				}
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *actions) Context() string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.context
+}
+
+func (this *actions) SetContext(context string) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if context = strings.TrimSpace(context); context == "" || context == contextAny {
+		return gopi.ErrBadParameter.WithPrefix("SetContext: ", context)
+	} else {
+		this.context = context
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *actions) Contexts() []string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if this.mapping == nil {
+		return nil
+	}
+	result := this.mapping.Contexts()
+	sort.Strings(result)
+	return result
+}
+
+func (this *actions) Reload() error {
+	fh, err := os.Open(*this.path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	info, err := fh.Stat()
+	if err != nil {
+		return err
+	}
+	mapping, err := NewMapping(fh)
+	if err != nil {
+		return fmt.Errorf("%v: %w", *this.path, err)
+	}
+
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	this.mapping = mapping
+	this.modtime = info.ModTime()
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *actions) String() string {
+	str := "<input.actions"
+	str += fmt.Sprintf(" path=%q", *this.path)
+	str += fmt.Sprintf(" context=%q", this.Context())
+	if contexts := this.Contexts(); len(contexts) > 0 {
+		str += fmt.Sprint(" contexts=", contexts)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// action returns an action event for an input event, or nil
+func (this *actions) action(evt gopi.Event) gopi.Event {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if this.mapping == nil {
+		return nil
+	} else if entry := this.mapping.Map(this.context, evt); entry == nil {
+		return nil
+	} else {
+		return NewEvent(entry.Action, this.context, evt)
+	}
+}
+
+// changed returns true if the modification time of the mapping file
+// has changed since it was read
+func (this *actions) changed() (bool, error) {
+	info, err := os.Stat(*this.path)
+	if err != nil {
+		return false, err
+	}
+
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return info.ModTime().Equal(this.modtime) == false, nil
+}
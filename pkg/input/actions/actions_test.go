@@ -0,0 +1,124 @@
+package actions_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	actions "github.com/djthorpe/gopi/v3/pkg/input/actions"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+)
+
+type App struct {
+	gopi.Unit
+	gopi.Publisher
+	gopi.InputActions
+}
+
+type keyevent struct {
+	key    gopi.KeyCode
+	device gopi.InputDeviceType
+	code   uint32
+}
+
+type gpioevent struct {
+	pin  gopi.GPIOPin
+	edge gopi.GPIOEdge
+}
+
+const (
+	ACTIONS_PATH = "../../../etc/input/test.actions"
+)
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (*keyevent) Name() string                                { return "key" }
+func (this *keyevent) Key() gopi.KeyCode                      { return this.key }
+func (*keyevent) Type() gopi.InputType                        { return gopi.INPUT_EVENT_KEYPRESS }
+func (this *keyevent) Device() (gopi.InputDeviceType, uint32) { return this.device, this.code }
+func (*gpioevent) Name() string                               { return "gpio" }
+func (this *gpioevent) Pin() gopi.GPIOPin                     { return this.pin }
+func (this *gpioevent) Edge() gopi.GPIOEdge                   { return this.edge }
+
+func Test_Actions_001(t *testing.T) {
+	if _, err := actions.NewMapping(strings.NewReader("playback KEYCODE_SPACE pause # comment\n\n* GPIO4:both next")); err != nil {
+		t.Error(err)
+	}
+	for _, line := range []string{"playback KEYCODE_SPACE", "playback NOTAKEY pause", "playback GPIO4:up next", "playback NEC_32:x pause"} {
+		if _, err := actions.NewMapping(strings.NewReader(line)); err == nil {
+			t.Error("Expected error for", line)
+		}
+	}
+}
+
+func Test_Actions_002(t *testing.T) {
+	tool.Test(t, []string{"-input.actions", ACTIONS_PATH, "-input.context", "playback"}, new(App), func(app *App) {
+		if app.InputActions.Context() != "playback" {
+			t.Error("Unexpected context", app.InputActions.Context())
+		} else if contexts := app.InputActions.Contexts(); strings.Join(contexts, ",") != "menu,playback" {
+			t.Error("Unexpected contexts", contexts)
+		}
+		t.Log(app.InputActions)
+
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Wait for actions to subscribe to events
+		time.Sleep(100 * time.Millisecond)
+
+		tests := []struct {
+			context string
+			source  gopi.Event
+			action  string
+		}{
+			{"playback", &keyevent{gopi.KEYCODE_SPACE, gopi.INPUT_DEVICE_KEYBOARD, 0}, "pause"},
+			{"playback", &keyevent{gopi.KEYCODE_BTN0, gopi.INPUT_DEVICE_JOYSTICK, 0}, "pause"},
+			{"playback", &keyevent{gopi.KEYCODE_NONE, gopi.INPUT_DEVICE_NEC_32 | gopi.INPUT_DEVICE_REMOTE, 0x40BF12ED}, "pause"},
+			{"playback", &gpioevent{17, gopi.GPIO_EDGE_FALLING}, "next"},
+			{"playback", &keyevent{gopi.KEYCODE_ESC, gopi.INPUT_DEVICE_KEYBOARD, 0}, "back"},
+			{"menu", &keyevent{gopi.KEYCODE_SPACE, gopi.INPUT_DEVICE_KEYBOARD, 0}, "select"},
+			{"menu", &gpioevent{17, gopi.GPIO_EDGE_FALLING}, "down"},
+			{"menu", &keyevent{gopi.KEYCODE_ESC, gopi.INPUT_DEVICE_KEYBOARD, 0}, "back"},
+		}
+		for _, test := range tests {
+			if err := app.InputActions.SetContext(test.context); err != nil {
+				t.Fatal(err)
+			} else if err := app.Publisher.Emit(test.source, true); err != nil {
+				t.Fatal(err)
+			}
+			if evt := waitAction(ch); evt == nil {
+				t.Error("No action for", test.source)
+			} else if evt.Name() != test.action || evt.Context() != test.context || evt.Source() != test.source {
+				t.Error("Unexpected action", evt)
+			}
+		}
+
+		// Unmapped events do not emit actions
+		app.Publisher.Emit(&gpioevent{17, gopi.GPIO_EDGE_RISING}, true)
+		if evt := waitAction(ch); evt != nil {
+			t.Error("Unexpected action", evt)
+		}
+	})
+}
+
+// waitAction returns the next action event, or nil after a timeout
+func waitAction(ch <-chan gopi.Event) gopi.InputActionEvent {
+	timeout := time.After(500 * time.Millisecond)
+	for {
+		select {
+		case evt := <-ch:
+			if evt, ok := evt.(gopi.InputActionEvent); ok {
+				return evt
+			}
+		case <-timeout:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package actions
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	action  string
+	context string
+	source  gopi.Event
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(action, context string, source gopi.Event) gopi.InputActionEvent {
+	return &event{action, context, source}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.action
+}
+
+func (this *event) Context() string {
+	return this.context
+}
+
+func (this *event) Source() gopi.Event {
+	return this.source
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<input.action"
+	str += fmt.Sprintf(" action=%q", this.action)
+	str += fmt.Sprintf(" context=%q", this.context)
+	if this.source != nil {
+		str += fmt.Sprint(" source=", this.source)
+	}
+	return str + ">"
+}
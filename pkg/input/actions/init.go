@@ -0,0 +1,13 @@
+package actions
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.InputActions
+	graph.RegisterUnit(reflect.TypeOf(&actions{}), reflect.TypeOf((*gopi.InputActions)(nil)))
+}
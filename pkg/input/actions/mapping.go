@@ -0,0 +1,216 @@
+package actions
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// mapping is a set of entries for each context, in the order they
+// were read
+type mapping struct {
+	contexts map[string][]*entry
+}
+
+// entry maps an input to an action
+type entry struct {
+	Action string
+	Key    gopi.KeyCode
+	Device gopi.InputDeviceType
+	Code   uint32
+	Pin    gopi.GPIOPin
+	Edge   gopi.GPIOEdge
+	Line   int
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Entries in the any context are mapped in every context
+	contextAny = "*"
+)
+
+var (
+	keycodes = make(map[string]gopi.KeyCode)
+	devices  = make(map[string]gopi.InputDeviceType)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	// Index from names to keycodes and input devices
+	for k := gopi.KEYCODE_NONE; k <= gopi.KEYCODE_MAX; k++ {
+		keycodes[fmt.Sprint(k)] = k
+	}
+	for d := gopi.INPUT_DEVICE_MIN; d <= gopi.INPUT_DEVICE_MAX; d <<= 1 {
+		devices[strings.TrimPrefix(fmt.Sprint(d), "INPUT_DEVICE_")] = d
+	}
+}
+
+// NewMapping reads entries, one on each line as <context> <input> <action>
+// separated by whitespace and followed by an optional comment, where
+// input is one of:
+//
+//	<keycode>            Key or button, ie KEYCODE_SPACE or SPACE
+//	<device>:<scancode>  IR scancode, ie NEC_32:0x40BF12ED
+//	<pin>:<edge>         GPIO pin, ie GPIO17:rising, GPIO17:falling or GPIO17:both
+//
+// The context * maps entries in every context
+func NewMapping(r io.Reader) (*mapping, error) {
+	this := new(mapping)
+	this.contexts = make(map[string][]*entry)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Text()
+		if i := strings.Index(data, "#"); i >= 0 {
+			data = data[:i]
+		}
+		fields := strings.Fields(data)
+		if len(fields) == 0 {
+			continue
+		} else if len(fields) != 3 {
+			return nil, gopi.ErrBadParameter.WithPrefix("Line ", line, ": ", strings.TrimSpace(data))
+		} else if entry, err := newEntry(fields[1], fields[2], line); err != nil {
+			return nil, err
+		} else {
+			this.contexts[fields[0]] = append(this.contexts[fields[0]], entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return this, nil
+}
+
+func newEntry(input, action string, line int) (*entry, error) {
+	this := &entry{Action: action, Line: line}
+	prefix, suffix := strings.ToUpper(input), ""
+	if i := strings.Index(prefix, ":"); i >= 0 {
+		prefix, suffix = prefix[:i], prefix[i+1:]
+	}
+
+	if suffix == "" {
+		// Key or button
+		if k, exists := keycodes["KEYCODE_"+strings.TrimPrefix(prefix, "KEYCODE_")]; exists && k != gopi.KEYCODE_NONE {
+			this.Key = k
+		} else {
+			return nil, gopi.ErrBadParameter.WithPrefix("Line ", line, ": ", input)
+		}
+	} else if strings.HasPrefix(prefix, "GPIO") {
+		// GPIO pin and edge
+		if pin, err := strconv.ParseUint(strings.TrimPrefix(prefix, "GPIO"), 10, 8); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix("Line ", line, ": ", input)
+		} else {
+			this.Pin = gopi.GPIOPin(pin)
+		}
+		switch suffix {
+		case "RISING":
+			this.Edge = gopi.GPIO_EDGE_RISING
+		case "FALLING":
+			this.Edge = gopi.GPIO_EDGE_FALLING
+		case "BOTH":
+			this.Edge = gopi.GPIO_EDGE_BOTH
+		default:
+			return nil, gopi.ErrBadParameter.WithPrefix("Line ", line, ": ", input)
+		}
+	} else {
+		// IR device and scancode
+		if d, exists := devices[strings.TrimPrefix(prefix, "INPUT_DEVICE_")]; exists {
+			this.Device = d
+		} else {
+			return nil, gopi.ErrBadParameter.WithPrefix("Line ", line, ": ", input)
+		}
+		if code, err := strconv.ParseUint(suffix, 0, 32); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix("Line ", line, ": ", input)
+		} else {
+			this.Code = uint32(code)
+		}
+	}
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *entry) String() string {
+	str := "<entry"
+	str += fmt.Sprintf(" action=%q", this.Action)
+	if this.Key != gopi.KEYCODE_NONE {
+		str += fmt.Sprint(" key=", this.Key)
+	} else if this.Device != gopi.INPUT_DEVICE_NONE {
+		str += fmt.Sprint(" device=", this.Device, " code=0x", strconv.FormatUint(uint64(this.Code), 16))
+	} else {
+		str += fmt.Sprint(" pin=", this.Pin, " edge=", this.Edge)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Contexts returns contexts in the mapping, not including the any context
+func (this *mapping) Contexts() []string {
+	result := make([]string, 0, len(this.contexts))
+	for context := range this.contexts {
+		if context != contextAny {
+			result = append(result, context)
+		}
+	}
+	return result
+}
+
+// Map returns the entry for an event in a context, or nil. Entries for
+// the context are matched before entries for any context, and IR
+// scancodes are matched before keys
+func (this *mapping) Map(context string, evt gopi.Event) *entry {
+	for _, context := range []string{context, contextAny} {
+		if entry := this.match(this.contexts[context], evt); entry != nil {
+			return entry
+		}
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *mapping) match(entries []*entry, evt gopi.Event) *entry {
+	switch evt := evt.(type) {
+	case gopi.InputEvent:
+		if t := evt.Type(); t != gopi.INPUT_EVENT_KEYPRESS && t != gopi.INPUT_EVENT_KEYREPEAT {
+			return nil
+		}
+		device, code := evt.Device()
+		for _, entry := range entries {
+			if entry.Device != gopi.INPUT_DEVICE_NONE && entry.Device&device != 0 && entry.Code == code {
+				return entry
+			}
+		}
+		for _, entry := range entries {
+			if entry.Key != gopi.KEYCODE_NONE && entry.Key == evt.Key() {
+				return entry
+			}
+		}
+	case gopi.GPIOEvent:
+		for _, entry := range entries {
+			if entry.Edge != gopi.GPIO_EDGE_NONE && entry.Pin == evt.Pin() && entry.Edge&evt.Edge() != 0 {
+				return entry
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package idle
+
+import (
+	"fmt"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t     gopi.IdleEventType
+	state gopi.IdleState
+	idle  time.Duration
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(t gopi.IdleEventType, state gopi.IdleState, idle time.Duration) gopi.IdleEvent {
+	return &event{t, state, idle}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "idle"
+}
+
+func (this *event) Type() gopi.IdleEventType {
+	return this.t
+}
+
+func (this *event) State() gopi.IdleState {
+	return this.state
+}
+
+func (this *event) Idle() time.Duration {
+	return this.idle
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<idle.event"
+	str += fmt.Sprint(" type=", this.t)
+	str += fmt.Sprint(" state=", this.state)
+	str += fmt.Sprint(" idle=", this.idle.Truncate(time.Millisecond))
+	return str + ">"
+}
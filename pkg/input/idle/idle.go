@@ -0,0 +1,213 @@
+package idle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type idle struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Backlight
+	sync.RWMutex
+
+	dim, screensaver *time.Duration
+	level            *uint
+	device           string
+	state            gopi.IdleState
+	last             time.Time
+	brightness       uint
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Interval for checking idle timeouts
+	checkInterval = 250 * time.Millisecond
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *idle) Define(cfg gopi.Config) error {
+	this.dim = cfg.FlagDuration("idle.dim", time.Minute, "Time without input before dimming the backlight, or zero to disable")
+	this.screensaver = cfg.FlagDuration("idle.screensaver", 5*time.Minute, "Time without input before entering the screensaver, or zero to disable")
+	this.level = cfg.FlagUint("idle.level", 10, "Dimmed backlight brightness as a percentage")
+	cfg.FlagString("idle.backlight", "", "Backlight device, or the first device when empty")
+	return nil
+}
+
+func (this *idle) New(cfg gopi.Config) error {
+	this.Require(this.Logger, this.Publisher)
+
+	if *this.dim < 0 {
+		return gopi.ErrBadParameter.WithPrefix("-idle.dim")
+	} else if *this.screensaver < 0 {
+		return gopi.ErrBadParameter.WithPrefix("-idle.screensaver")
+	} else if *this.level > 100 {
+		return gopi.ErrBadParameter.WithPrefix("-idle.level")
+	}
+
+	// Set backlight device
+	if this.Backlight != nil {
+		if device := cfg.GetString("idle.backlight"); device != "" {
+			this.device = device
+		} else if devices := this.Backlight.Devices(); len(devices) > 0 {
+			this.device = devices[0]
+		}
+	}
+
+	// Set state
+	this.state = gopi.IDLE_STATE_ACTIVE
+	this.last = time.Now()
+
+	// Return success
+	return nil
+}
+
+func (this *idle) Dispose() error {
+	// Restore the backlight
+	this.Touch()
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *idle) Run(ctx context.Context) error {
+	ch := this.Publisher.Subscribe()
+	defer this.Publisher.Unsubscribe(ch)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-ch:
+			switch evt.(type) {
+			case gopi.InputEvent, gopi.GPIOEvent, gopi.InputActionEvent:
+				this.Touch()
+			}
+		case now := <-ticker.C:
+			this.update(now)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *idle) Touch() {
+	this.RWMutex.Lock()
+	now := time.Now()
+	idle := now.Sub(this.last)
+	state := this.state
+	this.last = now
+	if state != gopi.IDLE_STATE_ACTIVE {
+		this.state = gopi.IDLE_STATE_ACTIVE
+		this.restore()
+	}
+	this.RWMutex.Unlock()
+
+	// Emit exit event when waking
+	if state != gopi.IDLE_STATE_ACTIVE {
+		this.emit(NewEvent(gopi.IDLE_EVENT_EXIT, state, idle))
+	}
+}
+
+func (this *idle) State() gopi.IdleState {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.state
+}
+
+func (this *idle) Idle() time.Duration {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return time.Since(this.last)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *idle) String() string {
+	str := "<idle"
+	str += fmt.Sprint(" state=", this.State())
+	str += fmt.Sprint(" idle=", this.Idle().Truncate(time.Second))
+	if this.device != "" {
+		str += fmt.Sprintf(" backlight=%q", this.device)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// update enters idle states when timeouts have passed, emitting an
+// enter event for each state
+func (this *idle) update(now time.Time) {
+	this.RWMutex.Lock()
+	idle := now.Sub(this.last)
+	events := []gopi.Event{}
+	if this.state < gopi.IDLE_STATE_DIM && *this.dim > 0 && idle >= *this.dim {
+		this.state = gopi.IDLE_STATE_DIM
+		this.dimBacklight()
+		events = append(events, NewEvent(gopi.IDLE_EVENT_ENTER, this.state, idle))
+	}
+	if this.state < gopi.IDLE_STATE_SCREENSAVER && *this.screensaver > 0 && idle >= *this.screensaver {
+		if this.state == gopi.IDLE_STATE_ACTIVE {
+			this.dimBacklight()
+		}
+		this.state = gopi.IDLE_STATE_SCREENSAVER
+		events = append(events, NewEvent(gopi.IDLE_EVENT_ENTER, this.state, idle))
+	}
+	this.RWMutex.Unlock()
+
+	for _, evt := range events {
+		this.emit(evt)
+	}
+}
+
+// dimBacklight records the current brightness and sets the dimmed level
+func (this *idle) dimBacklight() {
+	if this.Backlight == nil || this.device == "" {
+		return
+	}
+	if value, max, err := this.Backlight.Brightness(this.device); err != nil {
+		this.Debug("Idle: ", err)
+	} else if err := this.Backlight.SetBrightness(this.device, max*(*this.level)/100); err != nil {
+		this.Debug("Idle: ", err)
+	} else {
+		this.brightness = value
+	}
+}
+
+// restore sets the brightness recorded before dimming
+func (this *idle) restore() {
+	if this.Backlight == nil || this.device == "" || this.brightness == 0 {
+		return
+	}
+	if err := this.Backlight.SetBrightness(this.device, this.brightness); err != nil {
+		this.Debug("Idle: ", err)
+	}
+	this.brightness = 0
+}
+
+func (this *idle) emit(evt gopi.Event) {
+	if err := this.Publisher.Emit(evt, false); err != nil {
+		this.Debug("Idle: ", err)
+	}
+}
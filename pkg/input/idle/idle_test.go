@@ -0,0 +1,82 @@
+package idle_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+)
+
+type App struct {
+	gopi.Unit
+	gopi.Publisher
+	gopi.IdleMonitor
+}
+
+type keyevent struct{}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (*keyevent) Name() string                           { return "key" }
+func (*keyevent) Key() gopi.KeyCode                      { return gopi.KEYCODE_SPACE }
+func (*keyevent) Type() gopi.InputType                   { return gopi.INPUT_EVENT_KEYPRESS }
+func (*keyevent) Device() (gopi.InputDeviceType, uint32) { return gopi.INPUT_DEVICE_KEYBOARD, 0 }
+
+func Test_Idle_001(t *testing.T) {
+	tool.Test(t, []string{"-idle.dim", "300ms", "-idle.screensaver", "600ms"}, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		if app.IdleMonitor.State() != gopi.IDLE_STATE_ACTIVE {
+			t.Error("Unexpected state", app.IdleMonitor.State())
+		}
+
+		// Enter dim and screensaver states in order
+		for _, state := range []gopi.IdleState{gopi.IDLE_STATE_DIM, gopi.IDLE_STATE_SCREENSAVER} {
+			if evt := waitIdle(ch); evt == nil {
+				t.Fatal("No idle event for", state)
+			} else if evt.Type() != gopi.IDLE_EVENT_ENTER || evt.State() != state {
+				t.Error("Unexpected event", evt)
+			}
+		}
+		if app.IdleMonitor.State() != gopi.IDLE_STATE_SCREENSAVER {
+			t.Error("Unexpected state", app.IdleMonitor.State())
+		}
+		t.Log(app.IdleMonitor)
+
+		// Wake on input
+		if err := app.Publisher.Emit(&keyevent{}, true); err != nil {
+			t.Fatal(err)
+		} else if evt := waitIdle(ch); evt == nil {
+			t.Fatal("No idle event on input")
+		} else if evt.Type() != gopi.IDLE_EVENT_EXIT || evt.State() != gopi.IDLE_STATE_SCREENSAVER {
+			t.Error("Unexpected event", evt)
+		} else if app.IdleMonitor.State() != gopi.IDLE_STATE_ACTIVE {
+			t.Error("Unexpected state", app.IdleMonitor.State())
+		} else if app.IdleMonitor.Idle() > 100*time.Millisecond {
+			t.Error("Unexpected idle", app.IdleMonitor.Idle())
+		}
+	})
+}
+
+// waitIdle returns the next idle event, or nil after a timeout
+func waitIdle(ch <-chan gopi.Event) gopi.IdleEvent {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case evt := <-ch:
+			if evt, ok := evt.(gopi.IdleEvent); ok {
+				return evt
+			}
+		case <-timeout:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package idle
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.IdleMonitor
+	graph.RegisterUnit(reflect.TypeOf(&idle{}), reflect.TypeOf((*gopi.IdleMonitor)(nil)))
+}
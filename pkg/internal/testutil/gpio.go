@@ -0,0 +1,144 @@
+package testutil
+
+import (
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// GPIO is a fake GPIO unit which records the mode and state of each pin,
+// the number of times each pin is set high and the pins watched. Pin
+// writes are recorded in a scenario when one is set. Register it in a
+// test with graph.RegisterUnit
+type GPIO struct {
+	gopi.Unit
+	sync.Mutex
+
+	pins     []gopi.GPIOPin
+	modes    map[gopi.GPIOPin]gopi.GPIOMode
+	states   map[gopi.GPIOPin]gopi.GPIOState
+	pulses   map[gopi.GPIOPin]int
+	watched  map[gopi.GPIOPin]gopi.GPIOEdge
+	failing  bool
+	scenario *tool.Scenario
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *GPIO) New(gopi.Config) error {
+	this.modes = make(map[gopi.GPIOPin]gopi.GPIOMode)
+	this.states = make(map[gopi.GPIOPin]gopi.GPIOState)
+	this.pulses = make(map[gopi.GPIOPin]int)
+	this.watched = make(map[gopi.GPIOPin]gopi.GPIOEdge)
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GPIO
+
+func (this *GPIO) NumberOfPhysicalPins() uint                    { return 0 }
+func (this *GPIO) PhysicalPin(uint) gopi.GPIOPin                 { return gopi.GPIO_PIN_NONE }
+func (this *GPIO) PhysicalPinForPin(gopi.GPIOPin) uint           { return 0 }
+func (this *GPIO) SetPullMode(gopi.GPIOPin, gopi.GPIOPull) error { return nil }
+
+func (this *GPIO) Pins() []gopi.GPIOPin {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return append([]gopi.GPIOPin{}, this.pins...)
+}
+
+func (this *GPIO) ReadPin(pin gopi.GPIOPin) gopi.GPIOState {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.states[pin]
+}
+
+func (this *GPIO) WritePin(pin gopi.GPIOPin, state gopi.GPIOState) {
+	this.Mutex.Lock()
+	if state == gopi.GPIO_HIGH && this.states[pin] != gopi.GPIO_HIGH {
+		this.pulses[pin]++
+	}
+	this.states[pin] = state
+	scenario := this.scenario
+	this.Mutex.Unlock()
+
+	if scenario != nil {
+		scenario.Record("GPIO.WritePin", pin, state)
+	}
+}
+
+func (this *GPIO) GetPinMode(pin gopi.GPIOPin) gopi.GPIOMode {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.modes[pin]
+}
+
+func (this *GPIO) SetPinMode(pin gopi.GPIOPin, mode gopi.GPIOMode) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.modes[pin] = mode
+}
+
+func (this *GPIO) Watch(pin gopi.GPIOPin, edge gopi.GPIOEdge) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.watched[pin] = edge
+	return nil
+}
+
+// Batch returns an error without calling the function when failing
+func (this *GPIO) Batch(fn func(gopi.GPIOTx) error) error {
+	this.Mutex.Lock()
+	failing := this.failing
+	this.Mutex.Unlock()
+	if failing {
+		return gopi.ErrUnexpectedResponse
+	}
+	return fn(this)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// SetPins sets the pins returned by Pins
+func (this *GPIO) SetPins(pins ...gopi.GPIOPin) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.pins = append([]gopi.GPIOPin{}, pins...)
+}
+
+// SetFailing causes Batch to return an error, as a relay driver would
+// when the bus fails
+func (this *GPIO) SetFailing(value bool) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.failing = value
+}
+
+// SetScenario records pin writes in a scenario
+func (this *GPIO) SetScenario(scenario *tool.Scenario) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.scenario = scenario
+}
+
+// Pulses returns the number of times a pin has been set high
+func (this *GPIO) Pulses(pin gopi.GPIOPin) int {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.pulses[pin]
+}
+
+// Watched returns the edge watched on a pin, and false if the pin is
+// not watched
+func (this *GPIO) Watched(pin gopi.GPIOPin) (gopi.GPIOEdge, bool) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	edge, exists := this.watched[pin]
+	return edge, exists
+}
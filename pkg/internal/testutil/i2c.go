@@ -0,0 +1,283 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// I2C is a fake I2C bus with a map of registers for each slave. Writing
+// a register address followed by data sets the register, and writing a
+// register address then reading returns the register, padded with zeros.
+// A transfer function emulates chips which behave differently
+type I2C struct {
+	sync.Mutex
+
+	slave     uint8
+	pointer   uint8
+	registers map[uint8]map[uint8][]byte
+	writes    [][]byte
+	fn        TransferFunc
+}
+
+// TransferFunc emulates a chip at a slave address. It is called with the
+// data written and the number of bytes to read, and returns the data read.
+// It returns gopi.ErrNotImplemented to read or write the registers instead
+type TransferFunc func(slave uint8, data []byte, n int) ([]byte, error)
+
+// i2ctx calls the fake bus within a batch
+type i2ctx struct {
+	*I2C
+	bus gopi.I2CBus
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// NewI2C returns a fake bus with slaves at addresses
+func NewI2C(slaves ...uint8) *I2C {
+	this := new(I2C)
+	this.registers = make(map[uint8]map[uint8][]byte, len(slaves))
+	for _, slave := range slaves {
+		this.registers[slave] = make(map[uint8][]byte)
+	}
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// I2C
+
+func (this *I2C) Devices() []gopi.I2CBus { return []gopi.I2CBus{1} }
+
+func (this *I2C) SetSlave(_ gopi.I2CBus, slave uint8) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.slave = slave
+	return nil
+}
+
+func (this *I2C) GetSlave(gopi.I2CBus) uint8 {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.slave
+}
+
+func (this *I2C) DetectSlave(_ gopi.I2CBus, slave uint8) (bool, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	_, exists := this.registers[slave]
+	return exists, nil
+}
+
+func (this *I2C) TransferContext(ctx context.Context, _ gopi.I2CBus, data []byte, n int) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return this.transfer(data, n)
+}
+
+func (this *I2C) Read(gopi.I2CBus) ([]byte, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if registers, exists := this.registers[this.slave]; exists == false {
+		return nil, gopi.ErrNotFound
+	} else {
+		return append([]byte{}, registers[this.pointer]...), nil
+	}
+}
+
+func (this *I2C) Write(_ gopi.I2CBus, data []byte) (int, error) {
+	if _, err := this.transfer(data, 0); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (this *I2C) ReadBlock(_ gopi.I2CBus, reg, length uint8) ([]byte, error) {
+	return this.transfer([]byte{reg}, int(length))
+}
+
+func (this *I2C) ReadUint8(bus gopi.I2CBus, reg uint8) (uint8, error) {
+	if data, err := this.ReadBlock(bus, reg, 1); err != nil {
+		return 0, err
+	} else {
+		return data[0], nil
+	}
+}
+
+func (this *I2C) ReadInt8(bus gopi.I2CBus, reg uint8) (int8, error) {
+	value, err := this.ReadUint8(bus, reg)
+	return int8(value), err
+}
+
+// ReadUint16 reads a little-endian word, as SMBus does
+func (this *I2C) ReadUint16(bus gopi.I2CBus, reg uint8) (uint16, error) {
+	if data, err := this.ReadBlock(bus, reg, 2); err != nil {
+		return 0, err
+	} else {
+		return uint16(data[0]) | uint16(data[1])<<8, nil
+	}
+}
+
+func (this *I2C) ReadInt16(bus gopi.I2CBus, reg uint8) (int16, error) {
+	value, err := this.ReadUint16(bus, reg)
+	return int16(value), err
+}
+
+func (this *I2C) WriteUint8(bus gopi.I2CBus, reg, value uint8) error {
+	_, err := this.Write(bus, []byte{reg, value})
+	return err
+}
+
+func (this *I2C) WriteInt8(bus gopi.I2CBus, reg uint8, value int8) error {
+	return this.WriteUint8(bus, reg, uint8(value))
+}
+
+// WriteUint16 writes a little-endian word, as SMBus does
+func (this *I2C) WriteUint16(bus gopi.I2CBus, reg uint8, value uint16) error {
+	_, err := this.Write(bus, []byte{reg, byte(value), byte(value >> 8)})
+	return err
+}
+
+func (this *I2C) WriteInt16(bus gopi.I2CBus, reg uint8, value int16) error {
+	return this.WriteUint16(bus, reg, uint16(value))
+}
+
+func (this *I2C) Batch(bus gopi.I2CBus, fn func(gopi.I2CTx) error) error {
+	return fn(&i2ctx{this, bus})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// SetTransfer sets a function which emulates chips
+func (this *I2C) SetTransfer(fn TransferFunc) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.fn = fn
+}
+
+// Set sets a register of a slave, which adds the slave to the bus
+func (this *I2C) Set(slave, reg uint8, data ...byte) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if _, exists := this.registers[slave]; exists == false {
+		this.registers[slave] = make(map[uint8][]byte)
+	}
+	this.registers[slave][reg] = append([]byte{}, data...)
+}
+
+// Get returns a register of a slave
+func (this *I2C) Get(slave, reg uint8) []byte {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return append([]byte{}, this.registers[slave][reg]...)
+}
+
+// SetUint16 sets a big-endian register of a slave
+func (this *I2C) SetUint16(slave, reg uint8, value uint16) {
+	this.Set(slave, reg, byte(value>>8), byte(value))
+}
+
+// Uint16 returns a big-endian register of a slave
+func (this *I2C) Uint16(slave, reg uint8) uint16 {
+	data := pad(this.Get(slave, reg), 2)
+	return uint16(data[0])<<8 | uint16(data[1])
+}
+
+// Writes returns the data written to every slave
+func (this *I2C) Writes() [][]byte {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return append([][]byte{}, this.writes...)
+}
+
+// Last returns the last data written, or nil
+func (this *I2C) Last() []byte {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if len(this.writes) == 0 {
+		return nil
+	}
+	return this.writes[len(this.writes)-1]
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// I2C TRANSACTION
+
+func (this *i2ctx) SetSlave(slave uint8) error         { return this.I2C.SetSlave(this.bus, slave) }
+func (this *i2ctx) GetSlave() uint8                    { return this.I2C.GetSlave(this.bus) }
+func (this *i2ctx) Read() ([]byte, error)              { return this.I2C.Read(this.bus) }
+func (this *i2ctx) Write(data []byte) (int, error)     { return this.I2C.Write(this.bus, data) }
+func (this *i2ctx) ReadUint8(reg uint8) (uint8, error) { return this.I2C.ReadUint8(this.bus, reg) }
+func (this *i2ctx) ReadInt8(reg uint8) (int8, error)   { return this.I2C.ReadInt8(this.bus, reg) }
+func (this *i2ctx) ReadUint16(reg uint8) (uint16, error) {
+	return this.I2C.ReadUint16(this.bus, reg)
+}
+func (this *i2ctx) ReadInt16(reg uint8) (int16, error) { return this.I2C.ReadInt16(this.bus, reg) }
+func (this *i2ctx) ReadBlock(reg, length uint8) ([]byte, error) {
+	return this.I2C.ReadBlock(this.bus, reg, length)
+}
+func (this *i2ctx) WriteUint8(reg, value uint8) error {
+	return this.I2C.WriteUint8(this.bus, reg, value)
+}
+func (this *i2ctx) WriteInt8(reg uint8, value int8) error {
+	return this.I2C.WriteInt8(this.bus, reg, value)
+}
+func (this *i2ctx) WriteUint16(reg uint8, value uint16) error {
+	return this.I2C.WriteUint16(this.bus, reg, value)
+}
+func (this *i2ctx) WriteInt16(reg uint8, value int16) error {
+	return this.I2C.WriteInt16(this.bus, reg, value)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// transfer writes data to the current slave and reads a number of bytes,
+// calling the transfer function first
+func (this *I2C) transfer(data []byte, n int) ([]byte, error) {
+	this.Mutex.Lock()
+	slave, fn := this.slave, this.fn
+	if _, exists := this.registers[slave]; exists == false {
+		this.Mutex.Unlock()
+		return nil, gopi.ErrNotFound
+	} else if n == 0 {
+		this.writes = append(this.writes, append([]byte{}, data...))
+	}
+	this.Mutex.Unlock()
+
+	// Emulate the chip, which is called without the lock held so that it
+	// can get and set registers
+	if fn != nil {
+		if result, err := fn(slave, data, n); errors.Is(err, gopi.ErrNotImplemented) == false {
+			return result, err
+		}
+	}
+
+	// Set the register pointer, write the register and read it
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if len(data) > 0 {
+		this.pointer = data[0]
+	}
+	if len(data) > 1 {
+		this.registers[slave][this.pointer] = append([]byte{}, data[1:]...)
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	return pad(this.registers[slave][this.pointer], n), nil
+}
+
+// pad returns data of a length, truncated or padded with zeros
+func pad(data []byte, n int) []byte {
+	result := make([]byte, n)
+	copy(result, data)
+	return result
+}
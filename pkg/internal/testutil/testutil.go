@@ -0,0 +1,69 @@
+// Package testutil provides fake units and helpers which are shared by
+// unit tests
+package testutil
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Time to wait for an event
+	timeout = 2 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// TempFile writes data to a file in a temporary directory, which is
+// removed when the test ends, and returns the path to the file
+func TempFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Next returns the next event for which a function returns true, or nil
+// if there is no such event before the timeout
+func Next(ch <-chan gopi.Event, fn func(gopi.Event) bool) gopi.Event {
+	timeout := time.After(timeout)
+	for {
+		select {
+		case evt := <-ch:
+			if evt != nil && fn(evt) {
+				return evt
+			}
+		case <-timeout:
+			return nil
+		}
+	}
+}
+
+// NextType returns the next event with a Type method which returns a
+// value, such as gopi.DOOR_EVENT_STATE, or nil on timeout
+func NextType(ch <-chan gopi.Event, t interface{}) gopi.Event {
+	return Next(ch, IsType(t))
+}
+
+// IsType returns a function which returns true for events with a Type
+// method which returns a value, for use with Next and scenarios
+func IsType(t interface{}) func(gopi.Event) bool {
+	return func(evt gopi.Event) bool {
+		fn := reflect.ValueOf(evt).MethodByName("Type")
+		if fn.IsValid() == false || fn.Type().NumIn() != 0 || fn.Type().NumOut() != 1 {
+			return false
+		}
+		return fn.Call(nil)[0].Interface() == t
+	}
+}
@@ -0,0 +1,31 @@
+// Irrigation package implements gopi.Irrigation, which waters garden
+// zones through gopi.Relay channels. Zones are read from a JSON file set
+// with the -irrigation.zones flag, which maps a zone name to a relay
+// channel, a duration and a schedule:
+//
+//	{
+//	  "lawn":   { "relay": "valve1", "duration": "20m", "times": [ "06:00", "20:30" ], "days": [ "mon", "wed", "fri" ] },
+//	  "border": { "relay": "valve2", "duration": "10m", "times": [ "06:30" ],
+//	              "seasonal": [ 0, 0, 40, 60, 80, 100, 120, 120, 80, 50, 0, 0 ] }
+//	}
+//
+// Zones are watered every day when no days are set. The seasonal
+// adjustment is a percentage of the duration for each month from
+// January, so that zones water for longer in summer and not at all in
+// winter. Zones are watered one at a time, so scheduled and manual
+// watering is queued. When the -irrigation.master flag is set, the
+// master valve or pump channel is switched on before a zone and off
+// when there are no more zones to water.
+//
+// When a gopi.WeatherFeed unit is present, a rain delay is set for
+// -irrigation.delay when the current and forecast rainfall over the next
+// day is at least -irrigation.rain millimetres. Scheduled watering is
+// skipped during a rain delay, but zones can still be watered manually.
+//
+// When a gopi.SoilMoisture unit reports that the soil for a zone is
+// dry, the zone is watered for its scheduled duration, unless there is a
+// rain delay or it is already watering or queued.
+//
+// A gopi.IrrigationEvent is emitted when a zone starts or stops, when
+// scheduled watering is skipped and when the rain delay changes.
+package irrigation
@@ -0,0 +1,54 @@
+package irrigation
+
+import (
+	"fmt"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t     gopi.IrrigationEventType
+	zone  string
+	until time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(t gopi.IrrigationEventType, zone string, until time.Time) gopi.IrrigationEvent {
+	return &event{t, zone, until}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.zone
+}
+
+func (this *event) Type() gopi.IrrigationEventType {
+	return this.t
+}
+
+func (this *event) Until() time.Time {
+	return this.until
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<irrigation.event"
+	str += fmt.Sprint(" type=", this.t)
+	if this.zone != "" {
+		str += fmt.Sprintf(" zone=%q", this.zone)
+	}
+	if this.until.IsZero() == false {
+		str += " until=" + this.until.Format(time.RFC3339)
+	}
+	return str + ">"
+}
@@ -0,0 +1,13 @@
+package irrigation
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.Irrigation
+	graph.RegisterUnit(reflect.TypeOf(&irrigation{}), reflect.TypeOf((*gopi.Irrigation)(nil)))
+}
@@ -0,0 +1,528 @@
+package irrigation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type irrigation struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Relay
+	gopi.WeatherFeed
+	sync.Mutex
+
+	// Flags
+	path   *string
+	master *string
+	rain   *float64
+	delay  *time.Duration
+
+	zones     map[string]*zone
+	queue     []*entry
+	running   *entry
+	masterOn  bool
+	raindelay time.Time
+	wake      chan struct{}
+}
+
+// entry is a zone which is queued or watering
+type entry struct {
+	zone     *zone
+	duration time.Duration
+	until    time.Time
+	stopped  bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Time allowed to switch off relays on shutdown
+	stopTimeout = 10 * time.Second
+
+	// Weather older than this is not used for rain delay
+	staleWeather = 6 * time.Hour
+
+	// Period of forecast used for rain delay
+	forecastPeriod = 24 * time.Hour
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *irrigation) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagPath("irrigation.zones", "", "JSON file of irrigation zones")
+	this.master = cfg.FlagString("irrigation.master", "", "Relay channel for the master valve or pump, or empty")
+	this.rain = cfg.FlagFloat("irrigation.rain", 3, "Rainfall in millimetres which sets a rain delay, or zero to disable")
+	this.delay = cfg.FlagDuration("irrigation.delay", 24*time.Hour, "Duration of rain delay")
+	return nil
+}
+
+func (this *irrigation) New(gopi.Config) error {
+	this.Require(this.Logger, this.Relay)
+
+	// Check parameters
+	if *this.rain < 0 {
+		return gopi.ErrBadParameter.WithPrefix("-irrigation.rain")
+	} else if *this.rain > 0 && *this.delay <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-irrigation.delay")
+	}
+
+	// Read zones
+	if *this.path == "" {
+		return gopi.ErrBadParameter.WithPrefix("-irrigation.zones")
+	} else if zones, err := readZones(*this.path); err != nil {
+		return err
+	} else {
+		this.zones = zones
+	}
+
+	// Check relay channels exist, and the master valve is not a zone
+	channels := make(map[string]bool)
+	for _, name := range this.Relay.Channels() {
+		channels[name] = true
+	}
+	*this.master = strings.TrimSpace(*this.master)
+	if *this.master != "" && channels[*this.master] == false {
+		return gopi.ErrNotFound.WithPrefix("-irrigation.master: ", strconv.Quote(*this.master))
+	}
+	for _, zone := range this.zones {
+		if channels[zone.relay] == false {
+			return gopi.ErrNotFound.WithPrefix(zone.name, ": relay ", strconv.Quote(zone.relay))
+		} else if zone.relay == *this.master {
+			return gopi.ErrBadParameter.WithPrefix(zone.name, ": relay is the master valve")
+		}
+	}
+
+	// Make channel to wake the controller
+	this.wake = make(chan struct{}, 1)
+
+	// Return success
+	return nil
+}
+
+func (this *irrigation) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Release resources
+	this.zones = nil
+	this.queue = nil
+	this.running = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *irrigation) Run(ctx context.Context) error {
+	var ch <-chan gopi.Event
+	if this.Publisher != nil {
+		ch = this.Publisher.Subscribe()
+		defer this.Publisher.Unsubscribe(ch)
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	// Check weather and schedules once each minute
+	this.checkWeather(time.Now())
+	minute := time.Now().Truncate(time.Minute)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return this.off()
+		case now := <-ticker.C:
+			if now.Truncate(time.Minute).Equal(minute) == false {
+				minute = now.Truncate(time.Minute)
+				this.checkWeather(now)
+				this.schedule(now)
+			}
+		case <-this.wake:
+			break
+		case evt := <-ch:
+			switch evt := evt.(type) {
+			case gopi.FeedEvent:
+				if evt.Error() == nil {
+					this.checkWeather(time.Now())
+				}
+			case gopi.SoilEvent:
+				if evt.Type() == gopi.SOIL_EVENT_LOW && evt.Probe().Zone != "" {
+					this.dry(evt.Probe().Zone, time.Now())
+				}
+			}
+		}
+		if err := this.update(ctx, time.Now()); err != nil {
+			this.Print("Irrigation: ", err)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *irrigation) Zones() []gopi.IrrigationZone {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Scheduled watering resumes after the rain delay
+	now := time.Now()
+	from := now
+	if this.raindelay.After(now) {
+		from = this.raindelay
+	}
+
+	result := make([]gopi.IrrigationZone, 0, len(this.zones))
+	for _, name := range this.names() {
+		zone := this.zones[name]
+		state := gopi.IrrigationZone{Name: name, Last: zone.last}
+		if this.running != nil && this.running.zone == zone {
+			state.Running, state.Until = true, this.running.until
+		} else if this.queued(zone) {
+			state.Queued = true
+		}
+		if next := zone.Next(from); next.IsZero() == false {
+			state.Next, state.Duration = next, zone.Adjusted(next)
+		}
+		result = append(result, state)
+	}
+	return result
+}
+
+func (this *irrigation) Water(name string, duration time.Duration) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	zone, exists := this.zones[name]
+	if exists == false {
+		return gopi.ErrNotFound.WithPrefix("Water: ", strconv.Quote(name))
+	} else if duration == 0 {
+		duration = zone.Adjusted(time.Now())
+	}
+	if duration <= 0 || duration > maxDuration {
+		return gopi.ErrBadParameter.WithPrefix("Water: ", strconv.Quote(name), " duration ", duration)
+	} else if this.enqueue(zone, duration) == false {
+		return gopi.ErrOutOfOrder.WithPrefix("Water: ", strconv.Quote(name), " is already watering")
+	}
+
+	// Wake the controller
+	this.signal()
+
+	// Return success
+	return nil
+}
+
+func (this *irrigation) Stop(name string) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if name == "" {
+		this.queue = nil
+		if this.running != nil {
+			this.running.stopped = true
+		}
+	} else if zone, exists := this.zones[name]; exists == false {
+		return gopi.ErrNotFound.WithPrefix("Stop: ", strconv.Quote(name))
+	} else {
+		for i, entry := range this.queue {
+			if entry.zone == zone {
+				this.queue = append(this.queue[:i], this.queue[i+1:]...)
+				break
+			}
+		}
+		if this.running != nil && this.running.zone == zone {
+			this.running.stopped = true
+		}
+	}
+
+	// Wake the controller
+	this.signal()
+
+	// Return success
+	return nil
+}
+
+func (this *irrigation) RainDelay() time.Time {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.raindelay.After(time.Now()) {
+		return this.raindelay
+	} else {
+		return time.Time{}
+	}
+}
+
+func (this *irrigation) SetRainDelay(until time.Time) {
+	this.Mutex.Lock()
+	this.raindelay = until
+	this.Mutex.Unlock()
+
+	if until.IsZero() {
+		this.Print("Irrigation: Rain delay cleared")
+	} else {
+		this.Print("Irrigation: Rain delay until ", until.Format(time.RFC3339))
+	}
+	this.emit(NewEvent(gopi.IRRIGATION_EVENT_RAIN_DELAY, "", until))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *irrigation) String() string {
+	str := "<irrigation"
+	if *this.master != "" {
+		str += fmt.Sprintf(" master=%q", *this.master)
+	}
+	if until := this.RainDelay(); until.IsZero() == false {
+		str += " rain_delay=" + until.Format(time.RFC3339)
+	}
+	for _, zone := range this.Zones() {
+		str += fmt.Sprint(" ", zone)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// update stops the running zone when it has finished, and starts the
+// next zone in the queue. The master valve is switched on before a zone
+// starts and switched off when there are no more zones to water
+func (this *irrigation) update(ctx context.Context, now time.Time) error {
+	// Stop the running zone
+	this.Mutex.Lock()
+	stop := this.running
+	if stop != nil && (stop.stopped || (stop.until.IsZero() == false && now.Before(stop.until) == false)) {
+		this.running = nil
+	} else {
+		stop = nil
+	}
+	this.Mutex.Unlock()
+	if stop != nil {
+		if err := this.Relay.Set(ctx, stop.zone.relay, false); err != nil {
+			return err
+		}
+		this.Print("Irrigation: Stopped ", strconv.Quote(stop.zone.name))
+		this.emit(NewEvent(gopi.IRRIGATION_EVENT_STOP, stop.zone.name, time.Time{}))
+	}
+
+	// Take the next zone from the queue
+	this.Mutex.Lock()
+	var start *entry
+	if this.running == nil && len(this.queue) > 0 {
+		start, this.queue = this.queue[0], this.queue[1:]
+		this.running = start
+	}
+	idle := this.running == nil
+	this.Mutex.Unlock()
+
+	// Switch off the master valve when idle
+	if idle {
+		return this.setMaster(ctx, false)
+	} else if start == nil {
+		return nil
+	}
+
+	// Switch on the master valve and then the zone
+	if err := this.setMaster(ctx, true); err != nil {
+		this.abort(start)
+		return err
+	} else if err := this.Relay.Set(ctx, start.zone.relay, true); err != nil {
+		this.abort(start)
+		return err
+	}
+
+	// Set the time to stop watering
+	this.Mutex.Lock()
+	start.until = time.Now().Add(start.duration)
+	start.zone.last = now
+	this.Mutex.Unlock()
+
+	this.Print("Irrigation: Started ", strconv.Quote(start.zone.name), " for ", start.duration)
+	this.emit(NewEvent(gopi.IRRIGATION_EVENT_START, start.zone.name, start.until))
+
+	// Return success
+	return nil
+}
+
+// abort removes a zone which could not be started
+func (this *irrigation) abort(entry *entry) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.running == entry {
+		this.running = nil
+	}
+}
+
+// setMaster switches the master valve, if there is one
+func (this *irrigation) setMaster(ctx context.Context, state bool) error {
+	if *this.master == "" || this.masterOn == state {
+		return nil
+	} else if err := this.Relay.Set(ctx, *this.master, state); err != nil {
+		return err
+	}
+	this.masterOn = state
+	return nil
+}
+
+// off switches off the running zone and master valve on shutdown
+func (this *irrigation) off() error {
+	this.Mutex.Lock()
+	entry := this.running
+	this.running, this.queue = nil, nil
+	this.Mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	if entry != nil {
+		if err := this.Relay.Set(ctx, entry.zone.relay, false); err != nil {
+			return err
+		}
+		this.emit(NewEvent(gopi.IRRIGATION_EVENT_STOP, entry.zone.name, time.Time{}))
+	}
+	return this.setMaster(ctx, false)
+}
+
+// schedule queues zones which are due to start, or skips them when
+// there is a rain delay
+func (this *irrigation) schedule(now time.Time) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	for _, name := range this.names() {
+		zone := this.zones[name]
+		if zone.Due(now) == false {
+			continue
+		} else if this.raindelay.After(now) {
+			this.Print("Irrigation: Skipped ", strconv.Quote(name), " for rain delay")
+			this.emit(NewEvent(gopi.IRRIGATION_EVENT_SKIP, name, this.raindelay))
+		} else if duration := zone.Adjusted(now); duration > 0 {
+			this.enqueue(zone, duration)
+		}
+	}
+	this.signal()
+}
+
+// dry queues a zone when a soil moisture probe reads dry soil, or skips
+// it when there is a rain delay
+func (this *irrigation) dry(name string, now time.Time) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	zone, exists := this.zones[name]
+	if exists == false {
+		this.Debug("Irrigation: Soil probe for unknown zone ", strconv.Quote(name))
+	} else if this.raindelay.After(now) {
+		this.Print("Irrigation: Skipped ", strconv.Quote(name), " for rain delay")
+		this.emit(NewEvent(gopi.IRRIGATION_EVENT_SKIP, name, this.raindelay))
+	} else if duration := zone.Adjusted(now); duration > 0 && this.enqueue(zone, duration) {
+		this.Print("Irrigation: Soil is dry for ", strconv.Quote(name))
+		this.signal()
+	}
+}
+
+// checkWeather sets a rain delay when rain has fallen or is forecast
+func (this *irrigation) checkWeather(now time.Time) {
+	if this.WeatherFeed == nil || *this.rain <= 0 {
+		return
+	}
+	weather, valid := this.WeatherFeed.Weather()
+	if valid == false {
+		return
+	}
+	if mm := rainfall(weather, now); mm >= *this.rain {
+		if until := now.Add(*this.delay); until.After(this.RainDelay()) {
+			this.Debug("Irrigation: Rainfall ", mm, "mm")
+			this.SetRainDelay(until)
+		}
+	}
+}
+
+// enqueue adds a zone to the queue, and returns false if the zone is
+// already watering or queued
+func (this *irrigation) enqueue(zone *zone, duration time.Duration) bool {
+	if this.running != nil && this.running.zone == zone {
+		return false
+	} else if this.queued(zone) {
+		return false
+	}
+	this.queue = append(this.queue, &entry{zone: zone, duration: duration})
+	return true
+}
+
+// queued returns true if a zone is in the queue
+func (this *irrigation) queued(zone *zone) bool {
+	for _, entry := range this.queue {
+		if entry.zone == zone {
+			return true
+		}
+	}
+	return false
+}
+
+// names returns zone names in alphabetical order
+func (this *irrigation) names() []string {
+	result := make([]string, 0, len(this.zones))
+	for name := range this.zones {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// signal wakes the controller without blocking
+func (this *irrigation) signal() {
+	select {
+	case this.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (this *irrigation) emit(evt gopi.Event) {
+	if this.Publisher == nil {
+		return
+	} else if err := this.Publisher.Emit(evt, false); err != nil {
+		this.Debug("Irrigation: ", err)
+	}
+}
+
+// rainfall returns the current precipitation and the precipitation
+// forecast over the next day, in millimetres. Hourly forecasts are used
+// when available, and otherwise the daily forecast for today
+func rainfall(weather gopi.Weather, now time.Time) float64 {
+	if weather.Updated.IsZero() == false && now.Sub(weather.Updated) > staleWeather {
+		return 0
+	}
+	mm := float64(weather.Current.Precipitation)
+	if len(weather.Hourly) > 0 {
+		for _, hour := range weather.Hourly {
+			if hour.Time.Before(now) == false && hour.Time.Before(now.Add(forecastPeriod)) {
+				mm += float64(hour.Precipitation)
+			}
+		}
+	} else {
+		y, m, d := now.Date()
+		for _, day := range weather.Daily {
+			if dy, dm, dd := day.Time.In(now.Location()).Date(); dy == y && dm == m && dd == d {
+				mm += float64(day.Precipitation)
+			}
+		}
+	}
+	return mm
+}
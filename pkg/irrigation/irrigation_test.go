@@ -0,0 +1,273 @@
+package irrigation_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	feed "github.com/djthorpe/gopi/v3/pkg/feed"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	soil "github.com/djthorpe/gopi/v3/pkg/soil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/irrigation"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Irrigation
+	gopi.Relay
+	gopi.WeatherFeed
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// relay records the state of each channel
+type relay struct {
+	gopi.Unit
+	sync.Mutex
+	states map[string]bool
+}
+
+// weather returns weather which is set by a test
+type weather struct {
+	gopi.Unit
+	sync.Mutex
+	weather gopi.Weather
+	valid   bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&relay{}), reflect.TypeOf((*gopi.Relay)(nil)))
+	graph.RegisterUnit(reflect.TypeOf(&weather{}), reflect.TypeOf((*gopi.WeatherFeed)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RELAY
+
+func (this *relay) New(gopi.Config) error {
+	this.states = map[string]bool{"master": false, "valve1": false, "valve2": false}
+	return nil
+}
+
+func (this *relay) Channels() []string {
+	return []string{"master", "valve1", "valve2"}
+}
+
+func (this *relay) State(name string) (bool, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.states[name], nil
+}
+
+func (this *relay) Set(_ context.Context, name string, state bool) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.states[name] = state
+	return nil
+}
+
+func (this *relay) Safe() error {
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WEATHER
+
+func (this *weather) Weather() (gopi.Weather, bool) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.weather, this.valid
+}
+
+func (this *weather) Update(context.Context) error {
+	return nil
+}
+
+func (this *weather) Set(weather gopi.Weather) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.weather, this.valid = weather, true
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+const zones = `{
+	"lawn": { "relay": "valve1", "duration": "10m", "times": [ "06:00" ] },
+	"border": { "relay": "valve2", "duration": "5m" }
+}`
+
+func Test_Irrigation_001(t *testing.T) {
+	args := []string{"-irrigation.zones", testutil.TempFile(t, "zones.json", zones), "-irrigation.master", "master"}
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Water two zones, which are watered one at a time
+		if err := app.Irrigation.Water("lawn", 200*time.Millisecond); err != nil {
+			t.Fatal(err)
+		} else if err := app.Irrigation.Water("border", 200*time.Millisecond); err != nil {
+			t.Fatal(err)
+		} else if err := app.Irrigation.Water("border", 0); err == nil {
+			t.Error("Expected error for queued zone")
+		} else if err := app.Irrigation.Water("other", 0); err == nil {
+			t.Error("Expected error for unknown zone")
+		}
+
+		// Lawn starts with the master valve
+		if evt := testutil.NextType(ch, gopi.IRRIGATION_EVENT_START); evt == nil || evt.Name() != "lawn" {
+			t.Fatal("Unexpected event", evt)
+		}
+		if state(app, "master") == false || state(app, "valve1") == false || state(app, "valve2") {
+			t.Error("Unexpected relay states")
+		}
+		if zones := app.Irrigation.Zones(); len(zones) != 2 || zones[0].Name != "border" || zones[0].Queued == false || zones[1].Running == false {
+			t.Error("Unexpected zones", zones)
+		}
+
+		// Lawn stops and border starts
+		if evt := testutil.NextType(ch, gopi.IRRIGATION_EVENT_STOP); evt == nil || evt.Name() != "lawn" {
+			t.Fatal("Unexpected event", evt)
+		} else if evt := testutil.NextType(ch, gopi.IRRIGATION_EVENT_START); evt == nil || evt.Name() != "border" {
+			t.Fatal("Unexpected event", evt)
+		}
+		if state(app, "master") == false || state(app, "valve1") || state(app, "valve2") == false {
+			t.Error("Unexpected relay states")
+		}
+
+		// Border stops and the master valve is switched off
+		if evt := testutil.NextType(ch, gopi.IRRIGATION_EVENT_STOP); evt == nil || evt.Name() != "border" {
+			t.Fatal("Unexpected event", evt)
+		}
+		time.Sleep(100 * time.Millisecond)
+		if state(app, "master") || state(app, "valve1") || state(app, "valve2") {
+			t.Error("Unexpected relay states")
+		}
+		t.Log(app.Irrigation)
+	})
+}
+
+func Test_Irrigation_002(t *testing.T) {
+	args := []string{"-irrigation.zones", testutil.TempFile(t, "zones.json", zones)}
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Stop a zone before the duration
+		if err := app.Irrigation.Water("lawn", time.Hour); err != nil {
+			t.Fatal(err)
+		} else if evt := testutil.NextType(ch, gopi.IRRIGATION_EVENT_START); evt == nil {
+			t.Fatal("Timeout waiting for start")
+		} else if evt.(gopi.IrrigationEvent).Until().Sub(time.Now()) < 59*time.Minute {
+			t.Error("Unexpected event", evt)
+		}
+		if err := app.Irrigation.Stop(""); err != nil {
+			t.Fatal(err)
+		} else if evt := testutil.NextType(ch, gopi.IRRIGATION_EVENT_STOP); evt == nil {
+			t.Fatal("Timeout waiting for stop")
+		} else if state(app, "valve1") {
+			t.Error("Unexpected relay state")
+		}
+	})
+}
+
+func Test_Irrigation_003(t *testing.T) {
+	args := []string{"-irrigation.zones", testutil.TempFile(t, "zones.json", zones), "-irrigation.rain", "5", "-irrigation.delay", "12h"}
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Rain forecast sets a rain delay when the weather is updated
+		now := time.Now()
+		app.WeatherFeed.(*weather).Set(gopi.Weather{
+			Updated: now,
+			Current: gopi.WeatherConditions{Precipitation: 1},
+			Hourly: []gopi.WeatherConditions{
+				{Time: now.Add(time.Hour), Precipitation: 4},
+			},
+		})
+		if err := app.Publisher.Emit(feed.NewEvent("weather", now, nil), true); err != nil {
+			t.Fatal(err)
+		} else if evt := testutil.NextType(ch, gopi.IRRIGATION_EVENT_RAIN_DELAY); evt == nil {
+			t.Fatal("Timeout waiting for rain delay")
+		}
+		if until := app.Irrigation.RainDelay(); until.Sub(now) < 12*time.Hour || until.Sub(now) > 13*time.Hour {
+			t.Error("Unexpected rain delay", until)
+		}
+
+		// Scheduled watering resumes after the delay
+		until := app.Irrigation.RainDelay()
+		for _, zone := range app.Irrigation.Zones() {
+			if zone.Name == "lawn" && zone.Next.Before(until) {
+				t.Error("Unexpected next time", zone)
+			} else if zone.Name == "border" && zone.Next.IsZero() == false {
+				t.Error("Unexpected next time", zone)
+			}
+		}
+
+		// Clear the rain delay
+		app.Irrigation.SetRainDelay(time.Time{})
+		if until := app.Irrigation.RainDelay(); until.IsZero() == false {
+			t.Error("Unexpected rain delay", until)
+		}
+	})
+}
+
+func Test_Irrigation_004(t *testing.T) {
+	args := []string{"-irrigation.zones", testutil.TempFile(t, "zones.json", zones)}
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Wait for the controller to subscribe, and then dry soil waters
+		// the zone for its duration
+		time.Sleep(100 * time.Millisecond)
+		probe := gopi.SoilProbe{Name: "bed", Zone: "border", Time: time.Now(), Voltage: 2.6, Moisture: 12.5, Low: true}
+		if err := app.Publisher.Emit(soil.NewEvent(gopi.SOIL_EVENT_LOW, probe), true); err != nil {
+			t.Fatal(err)
+		} else if evt := testutil.NextType(ch, gopi.IRRIGATION_EVENT_START); evt == nil || evt.Name() != "border" {
+			t.Fatal("Unexpected event", evt)
+		} else if evt.(gopi.IrrigationEvent).Until().Sub(time.Now()) < 4*time.Minute {
+			t.Error("Unexpected event", evt)
+		}
+		if err := app.Irrigation.Stop(""); err != nil {
+			t.Fatal(err)
+		} else if evt := testutil.NextType(ch, gopi.IRRIGATION_EVENT_STOP); evt == nil {
+			t.Fatal("Timeout waiting for stop")
+		}
+
+		// Dry soil is skipped during a rain delay
+		app.Irrigation.SetRainDelay(time.Now().Add(time.Hour))
+		if err := app.Publisher.Emit(soil.NewEvent(gopi.SOIL_EVENT_LOW, probe), true); err != nil {
+			t.Fatal(err)
+		} else if evt := testutil.NextType(ch, gopi.IRRIGATION_EVENT_SKIP); evt == nil || evt.Name() != "border" {
+			t.Fatal("Unexpected event", evt)
+		} else if state(app, "valve2") {
+			t.Error("Unexpected relay state")
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func state(app *App, name string) bool {
+	state, _ := app.Relay.State(name)
+	return state
+}
@@ -0,0 +1,200 @@
+package irrigation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type zone struct {
+	name     string
+	relay    string
+	duration time.Duration
+	times    []time.Duration // Times of day to water
+	days     [7]bool         // Days of the week to water
+	seasonal [12]float64     // Percentage of duration for each month
+
+	last time.Time
+}
+
+// config is the entry for a zone in the zones file
+type config struct {
+	Relay    string    `json:"relay"`
+	Duration string    `json:"duration"`
+	Times    []string  `json:"times"`
+	Days     []string  `json:"days"`
+	Seasonal []float64 `json:"seasonal"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Maximum duration for a zone, so that a mistake in the zones file
+	// does not leave water running
+	maxDuration = 4 * time.Hour
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newZone(name string, cfg config) (*zone, error) {
+	this := &zone{name: name, relay: strings.TrimSpace(cfg.Relay)}
+	if this.relay == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix(name, ": Missing relay")
+	}
+	if d, err := time.ParseDuration(cfg.Duration); err != nil || d <= 0 || d > maxDuration {
+		return nil, gopi.ErrBadParameter.WithPrefix(name, ": duration ", strconv.Quote(cfg.Duration))
+	} else {
+		this.duration = d
+	}
+	for _, value := range cfg.Times {
+		if t, err := time.Parse("15:04", strings.TrimSpace(value)); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": time ", strconv.Quote(value))
+		} else {
+			this.times = append(this.times, time.Duration(t.Hour())*time.Hour+time.Duration(t.Minute())*time.Minute)
+		}
+	}
+	if len(cfg.Days) == 0 {
+		for i := range this.days {
+			this.days[i] = true
+		}
+	}
+	for _, value := range cfg.Days {
+		if day, err := parseWeekday(value); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": day ", strconv.Quote(value))
+		} else {
+			this.days[day] = true
+		}
+	}
+	switch len(cfg.Seasonal) {
+	case 0:
+		for i := range this.seasonal {
+			this.seasonal[i] = 100
+		}
+	case 12:
+		for i, value := range cfg.Seasonal {
+			if value < 0 {
+				return nil, gopi.ErrBadParameter.WithPrefix(name, ": seasonal ", value)
+			}
+			this.seasonal[i] = value
+		}
+	default:
+		return nil, gopi.ErrBadParameter.WithPrefix(name, ": seasonal requires twelve values")
+	}
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+// Adjusted returns the duration of watering in the month of a time,
+// which is at most the maximum duration
+func (this *zone) Adjusted(t time.Time) time.Duration {
+	d := time.Duration(float64(this.duration) * this.seasonal[t.Month()-1] / 100).Round(time.Second)
+	if d > maxDuration {
+		return maxDuration
+	}
+	return d
+}
+
+// Due returns true if the zone is scheduled to start in the minute
+// of a time
+func (this *zone) Due(t time.Time) bool {
+	if this.days[t.Weekday()] == false {
+		return false
+	}
+	tod := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	for _, start := range this.times {
+		if start == tod {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the next scheduled time after a time, or zero if the
+// zone is not scheduled
+func (this *zone) Next(t time.Time) time.Time {
+	if len(this.times) == 0 {
+		return time.Time{}
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for day := 0; day <= 7; day++ {
+		date := midnight.AddDate(0, 0, day)
+		if this.days[date.Weekday()] == false {
+			continue
+		}
+		var next time.Time
+		for _, start := range this.times {
+			when := date.Add(start)
+			if when.After(t) && (next.IsZero() || when.Before(next)) {
+				next = when
+			}
+		}
+		if next.IsZero() == false {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *zone) String() string {
+	str := "<irrigation.zone"
+	str += fmt.Sprintf(" name=%q relay=%q duration=%v", this.name, this.relay, this.duration)
+	for _, start := range this.times {
+		str += fmt.Sprintf(" time=%02d:%02d", int(start.Hours()), int(start.Minutes())%60)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// readZones returns zones from a JSON file
+func readZones(path string) (map[string]*zone, error) {
+	var zones map[string]config
+	if data, err := ioutil.ReadFile(path); err != nil {
+		return nil, err
+	} else if err := json.Unmarshal(data, &zones); err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	result := make(map[string]*zone, len(zones))
+	for name, cfg := range zones {
+		if name = strings.TrimSpace(name); name == "" {
+			return nil, gopi.ErrBadParameter.WithPrefix(path, ": Missing name")
+		} else if zone, err := newZone(name, cfg); err != nil {
+			return nil, err
+		} else {
+			result[name] = zone
+		}
+	}
+	if len(result) == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix(path, ": No zones")
+	}
+	return result, nil
+}
+
+// parseWeekday returns a day of the week from its name or abbreviation
+func parseWeekday(value string) (time.Weekday, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if len(value) >= 3 {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.HasPrefix(strings.ToLower(day.String()), value) {
+				return day, nil
+			}
+		}
+	}
+	return time.Sunday, fmt.Errorf("Invalid day %q", value)
+}
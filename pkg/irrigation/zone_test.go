@@ -0,0 +1,97 @@
+package irrigation
+
+import (
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+func Test_Zone_001(t *testing.T) {
+	zone, err := newZone("lawn", config{
+		Relay:    "valve1",
+		Duration: "20m",
+		Times:    []string{"20:30", "06:00"},
+		Days:     []string{"mon", "Wednesday"},
+		Seasonal: []float64{0, 0, 50, 100, 100, 150, 150, 150, 100, 50, 0, 0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 4 January 2021 is a Monday
+	monday := time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)
+	if zone.Due(monday.Add(6*time.Hour+30*time.Second)) == false {
+		t.Error("Expected zone to be due")
+	}
+	if zone.Due(monday.Add(6*time.Hour+time.Minute)) || zone.Due(monday.AddDate(0, 0, 1).Add(6*time.Hour)) {
+		t.Error("Unexpected zone due")
+	}
+	for _, test := range []struct {
+		from, next time.Time
+	}{
+		{monday, monday.Add(6 * time.Hour)},
+		{monday.Add(6 * time.Hour), monday.Add(20*time.Hour + 30*time.Minute)},
+		{monday.Add(21 * time.Hour), monday.AddDate(0, 0, 2).Add(6 * time.Hour)},
+		{monday.AddDate(0, 0, 3), monday.AddDate(0, 0, 7).Add(6 * time.Hour)},
+	} {
+		if next := zone.Next(test.from); next.Equal(test.next) == false {
+			t.Error("Unexpected next time", test.from, next)
+		}
+	}
+	if d := zone.Adjusted(monday); d != 0 {
+		t.Error("Unexpected duration in January", d)
+	}
+	if d := zone.Adjusted(monday.AddDate(0, 6, 0)); d != 30*time.Minute {
+		t.Error("Unexpected duration in July", d)
+	}
+}
+
+func Test_Zone_002(t *testing.T) {
+	for _, cfg := range []config{
+		{Duration: "10m"},
+		{Relay: "valve1"},
+		{Relay: "valve1", Duration: "5h"},
+		{Relay: "valve1", Duration: "10m", Times: []string{"25:00"}},
+		{Relay: "valve1", Duration: "10m", Days: []string{"mo"}},
+		{Relay: "valve1", Duration: "10m", Seasonal: []float64{100}},
+	} {
+		if _, err := newZone("zone", cfg); err == nil {
+			t.Error("Expected error for", cfg)
+		}
+	}
+	if zone, err := newZone("zone", config{Relay: "valve1", Duration: "10m"}); err != nil {
+		t.Error(err)
+	} else if zone.Next(time.Now()).IsZero() == false {
+		t.Error("Expected no schedule")
+	} else if zone.Adjusted(time.Now()) != 10*time.Minute {
+		t.Error("Unexpected duration")
+	}
+}
+
+func Test_Rainfall_001(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	weather := gopi.Weather{
+		Updated: now.Add(-time.Hour),
+		Current: gopi.WeatherConditions{Precipitation: 0.5},
+		Hourly: []gopi.WeatherConditions{
+			{Time: now.Add(-time.Hour), Precipitation: 10},
+			{Time: now.Add(time.Hour), Precipitation: 1},
+			{Time: now.Add(23 * time.Hour), Precipitation: 2},
+			{Time: now.Add(25 * time.Hour), Precipitation: 10},
+		},
+		Daily: []gopi.WeatherConditions{
+			{Time: now, Precipitation: 10},
+		},
+	}
+	if mm := rainfall(weather, now); mm != 3.5 {
+		t.Error("Unexpected rainfall", mm)
+	}
+	weather.Hourly = nil
+	if mm := rainfall(weather, now); mm != 10.5 {
+		t.Error("Unexpected rainfall", mm)
+	}
+	if mm := rainfall(weather, now.Add(7*time.Hour)); mm != 0 {
+		t.Error("Expected no rainfall for stale weather", mm)
+	}
+}
@@ -0,0 +1,4 @@
+// Limiter package implements per-client rate limiting and concurrent
+// request limits. These are used by the HTTP and RPC servers so that
+// a misbehaving client on the network cannot overwhelm a device.
+package limiter
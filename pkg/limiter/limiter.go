@@ -0,0 +1,172 @@
+package limiter
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Limiter limits the rate of requests and the number of concurrent
+// requests for each client address
+type Limiter struct {
+	sync.Mutex
+
+	rate    float64
+	burst   float64
+	streams uint
+	clients map[string]*client
+	purged  time.Time
+}
+
+type client struct {
+	tokens float64
+	last   time.Time
+	active uint
+}
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Clients without active requests are removed after this period
+	idleTimeout = time.Minute
+)
+
+/////////////////////////////////////////////////////////////////////
+// NEW
+
+// New returns a limiter which allows rate requests per second for each
+// client, with bursts of up to burst requests, and up to streams
+// concurrent requests. A rate or streams of zero is unlimited
+func New(rate float64, burst, streams uint) *Limiter {
+	this := new(Limiter)
+	this.rate = rate
+	this.burst = float64(burst)
+	if this.burst < 1 {
+		this.burst = 1
+	}
+	this.streams = streams
+	this.clients = make(map[string]*client)
+	return this
+}
+
+/////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Limiter) String() string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	str := "<limiter"
+	if this.rate > 0 {
+		str += fmt.Sprint(" rate=", this.rate, " burst=", this.burst)
+	}
+	if this.streams > 0 {
+		str += fmt.Sprint(" streams=", this.streams)
+	}
+	str += fmt.Sprint(" clients=", len(this.clients))
+	return str + ">"
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Allow returns true if a request from a client address is within the
+// rate limit. The address can include a port, which is ignored
+func (this *Limiter) Allow(addr string) bool {
+	if this.rate <= 0 {
+		return true
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	now := time.Now()
+	client := this.client(addr, now)
+
+	// Refill tokens since the last request
+	client.tokens += now.Sub(client.last).Seconds() * this.rate
+	if client.tokens > this.burst {
+		client.tokens = this.burst
+	}
+	client.last = now
+
+	// Take a token
+	if client.tokens < 1 {
+		return false
+	} else {
+		client.tokens -= 1
+		return true
+	}
+}
+
+// Acquire returns true if a client address has fewer than the maximum
+// number of concurrent requests, in which case Release should be called
+// when the request has completed
+func (this *Limiter) Acquire(addr string) bool {
+	if this.streams == 0 {
+		return true
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	client := this.client(addr, time.Now())
+	if client.active >= this.streams {
+		return false
+	} else {
+		client.active++
+		return true
+	}
+}
+
+// Release a concurrent request for a client address
+func (this *Limiter) Release(addr string) {
+	if this.streams == 0 {
+		return
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if client, exists := this.clients[host(addr)]; exists && client.active > 0 {
+		client.active--
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// client returns the state for a client address, creating it with a
+// full bucket when it does not exist, and removes idle clients
+func (this *Limiter) client(addr string, now time.Time) *client {
+	if now.Sub(this.purged) > idleTimeout {
+		for key, client := range this.clients {
+			if client.active == 0 && now.Sub(client.last) > idleTimeout {
+				delete(this.clients, key)
+			}
+		}
+		this.purged = now
+	}
+
+	key := host(addr)
+	if c, exists := this.clients[key]; exists {
+		return c
+	}
+	c := &client{tokens: this.burst, last: now}
+	this.clients[key] = c
+	return c
+}
+
+// host returns the address without a port
+func host(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	} else {
+		return addr
+	}
+}
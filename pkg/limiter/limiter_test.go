@@ -0,0 +1,53 @@
+package limiter_test
+
+import (
+	"testing"
+	"time"
+
+	limiter "github.com/djthorpe/gopi/v3/pkg/limiter"
+)
+
+func Test_Limiter_001(t *testing.T) {
+	l := limiter.New(0, 0, 0)
+	for i := 0; i < 100; i++ {
+		if l.Allow("127.0.0.1:80") == false {
+			t.Error("Expected unlimited rate")
+		} else if l.Acquire("127.0.0.1:80") == false {
+			t.Error("Expected unlimited streams")
+		}
+	}
+	t.Log(l)
+}
+
+func Test_Limiter_002(t *testing.T) {
+	l := limiter.New(10, 5, 0)
+	for i := 0; i < 5; i++ {
+		if l.Allow("127.0.0.1:80") == false {
+			t.Error("Expected request within burst")
+		}
+	}
+	if l.Allow("127.0.0.1:81") {
+		t.Error("Expected client to be limited regardless of port")
+	} else if l.Allow("127.0.0.2:80") == false {
+		t.Error("Expected other client not to be limited")
+	}
+	time.Sleep(150 * time.Millisecond)
+	if l.Allow("127.0.0.1:80") == false {
+		t.Error("Expected tokens to refill")
+	}
+	t.Log(l)
+}
+
+func Test_Limiter_003(t *testing.T) {
+	l := limiter.New(0, 0, 2)
+	if l.Acquire("127.0.0.1:80") == false || l.Acquire("127.0.0.1:81") == false {
+		t.Error("Expected streams within limit")
+	} else if l.Acquire("127.0.0.1:82") {
+		t.Error("Expected streams to be limited")
+	}
+	l.Release("127.0.0.1:80")
+	if l.Acquire("127.0.0.1:83") == false {
+		t.Error("Expected stream after release")
+	}
+	t.Log(l)
+}
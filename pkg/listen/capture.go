@@ -0,0 +1,75 @@
+package listen
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// capture reads mono samples from an audio source
+type capture interface {
+	// Read blocks until the buffer is full
+	Read([]int16) error
+
+	// Stop causes any current and future reads to return an error
+	Stop()
+
+	// Close releases resources, after reading has ended
+	Close() error
+}
+
+// nullCapture returns silence in real time
+type nullCapture struct {
+	sync.Once
+	rate uint
+	done chan struct{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	nullDevice = "null"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newCapture(device string, rate uint) (capture, error) {
+	if device == nullDevice {
+		return &nullCapture{rate: rate, done: make(chan struct{})}, nil
+	} else {
+		return newDeviceCapture(device, rate)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NULL CAPTURE
+
+func (this *nullCapture) Read(buf []int16) error {
+	timer := time.NewTimer(time.Duration(len(buf)) * time.Second / time.Duration(this.rate))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		for i := range buf {
+			buf[i] = 0
+		}
+		return nil
+	case <-this.done:
+		return io.EOF
+	}
+}
+
+func (this *nullCapture) Stop() {
+	this.Once.Do(func() {
+		close(this.done)
+	})
+}
+
+func (this *nullCapture) Close() error {
+	this.Stop()
+	return nil
+}
@@ -0,0 +1,92 @@
+// +build linux
+
+package listen
+
+import (
+	"os"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	linux "github.com/djthorpe/gopi/v3/pkg/sys/linux"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// deviceCapture reads from an ALSA capture device, which may capture
+// at a multiple of the sample rate and with more than one channel
+type deviceCapture struct {
+	sync.Once
+	fh       *os.File
+	factor   uint
+	channels uint
+	buf      []int16
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newDeviceCapture(device string, rate uint) (capture, error) {
+	card, dev, err := linux.PCMParseDevice(device)
+	if err != nil {
+		return nil, err
+	}
+	fh, err := linux.PCMOpenCapture(card, dev)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try mono then stereo at the sample rate and at multiples of it,
+	// since many microphones only capture at 48kHz
+	for factor := uint(1); factor <= 3; factor++ {
+		for channels := uint(1); channels <= 2; channels++ {
+			if err := linux.PCMSetParams(fh.Fd(), rate*factor, channels); err == nil {
+				return &deviceCapture{fh: fh, factor: factor, channels: channels}, nil
+			}
+		}
+	}
+
+	// No supported format
+	fh.Close()
+	return nil, gopi.ErrNotImplemented.WithPrefix(device, ": Sample rate ", rate)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *deviceCapture) Read(buf []int16) error {
+	// Read native samples
+	n := uint(len(buf)) * this.factor * this.channels
+	if uint(len(this.buf)) != n {
+		this.buf = make([]int16, n)
+	}
+	if err := linux.PCMRead(this.fh.Fd(), this.buf, this.channels); err != nil {
+		return err
+	}
+
+	// Mix down channels and average frames to reduce sample rate
+	block := this.factor * this.channels
+	for i := range buf {
+		sum := int32(0)
+		for _, s := range this.buf[uint(i)*block : uint(i+1)*block] {
+			sum += int32(s)
+		}
+		buf[i] = int16(sum / int32(block))
+	}
+
+	// Return success
+	return nil
+}
+
+// Stop capture, after which reads return an error since the device
+// is no longer prepared
+func (this *deviceCapture) Stop() {
+	this.Once.Do(func() {
+		linux.PCMDrop(this.fh.Fd())
+	})
+}
+
+func (this *deviceCapture) Close() error {
+	this.Stop()
+	return this.fh.Close()
+}
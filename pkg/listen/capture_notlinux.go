@@ -0,0 +1,14 @@
+// +build !linux
+
+package listen
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newDeviceCapture(string, uint) (capture, error) {
+	return nil, gopi.ErrNotImplemented
+}
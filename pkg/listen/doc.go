@@ -0,0 +1,7 @@
+// Listen package captures audio from an ALSA device, waits for a wake
+// word and records an utterance until silence, which is converted to
+// text by a speech recognizer. Import packages such as listen/porcupine
+// for wake word detection and listen/whisper or listen/remote for
+// speech recognition. Without a wake word detector, recording is
+// started by calling Trigger.
+package listen
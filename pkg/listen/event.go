@@ -0,0 +1,64 @@
+package listen
+
+import (
+	"fmt"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	keyword  string
+	text     string
+	duration time.Duration
+	err      error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(keyword, text string, duration time.Duration, err error) gopi.TranscriptEvent {
+	return &event{keyword, text, duration, err}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "transcript"
+}
+
+func (this *event) Keyword() string {
+	return this.keyword
+}
+
+func (this *event) Text() string {
+	return this.text
+}
+
+func (this *event) Duration() time.Duration {
+	return this.duration
+}
+
+func (this *event) Error() error {
+	return this.err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<event.transcript"
+	if this.keyword != "" {
+		str += fmt.Sprintf(" keyword=%q", this.keyword)
+	}
+	str += fmt.Sprintf(" text=%q", this.text)
+	str += " duration=" + fmt.Sprint(this.duration.Truncate(time.Millisecond))
+	if this.err != nil {
+		str += " err=" + fmt.Sprint(this.err)
+	}
+	return str + ">"
+}
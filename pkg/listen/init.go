@@ -0,0 +1,13 @@
+package listen
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register listener
+	graph.RegisterUnit(reflect.TypeOf(&listener{}), reflect.TypeOf((*gopi.Listener)(nil)))
+}
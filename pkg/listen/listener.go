@@ -0,0 +1,240 @@
+package listen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	pipeline "github.com/djthorpe/gopi/v3/pkg/pipeline"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type listener struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.WakeWordDetector
+	gopi.SpeechRecognizer
+	sync.Mutex
+	sync.WaitGroup
+
+	device    *string
+	rate      *uint
+	threshold *uint
+	silence   *time.Duration
+	timeout   *time.Duration
+	max       *time.Duration
+
+	frame     uint
+	muted     bool
+	triggered bool
+	utterance *utterance
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	defaultDevice = "hw:0"
+	frameDuration = 20 * time.Millisecond
+	frameQueue    = 50 // One second of frames
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *listener) Define(cfg gopi.Config) error {
+	this.device = cfg.FlagString("listen.device", defaultDevice, "Audio capture device, null for silence")
+	this.rate = cfg.FlagUint("listen.rate", 16000, "Sample rate when there is no wake word detector")
+	this.threshold = cfg.FlagUint("listen.threshold", 500, "Level above which audio is speech")
+	this.silence = cfg.FlagDuration("listen.silence", 800*time.Millisecond, "Silence which ends an utterance")
+	this.timeout = cfg.FlagDuration("listen.timeout", 3*time.Second, "Time to wait for speech after wake word")
+	this.max = cfg.FlagDuration("listen.max", 10*time.Second, "Maximum utterance duration")
+	return nil
+}
+
+func (this *listener) New(gopi.Config) error {
+	this.Require(this.Logger, this.SpeechRecognizer)
+
+	// The wake word detector sets the sample rate and frame length
+	if this.WakeWordDetector != nil {
+		*this.rate = this.WakeWordDetector.SampleRate()
+		this.frame = this.WakeWordDetector.FrameLength()
+	} else {
+		this.frame = samples(frameDuration, *this.rate)
+	}
+
+	// Check parameters
+	if *this.rate == 0 || this.frame == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-listen.rate")
+	} else if *this.silence <= 0 || *this.timeout <= 0 || *this.max <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-listen.silence, -listen.timeout or -listen.max")
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *listener) Run(ctx context.Context) error {
+	capture, err := newCapture(*this.device, *this.rate)
+	if err != nil {
+		return err
+	}
+
+	// Read frames into a bounded queue, dropping the oldest frames when
+	// processing falls behind, and stop capture when done
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-child.Done()
+		capture.Stop()
+	}()
+	p := pipeline.New()
+	p.Source("capture", func(ctx context.Context, emit pipeline.EmitFunc) error {
+		for {
+			frame := make([]int16, this.frame)
+			if err := capture.Read(frame); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			} else if err := emit(frame); err != nil {
+				return nil
+			}
+		}
+	}).Sink("process", pipeline.Queue{Size: frameQueue, Policy: pipeline.DROP_OLDEST}, func(ctx context.Context, frame interface{}) error {
+		this.process(ctx, frame.([]int16))
+		return nil
+	})
+
+	// Process frames until done
+	var result error
+	if err := p.Run(child); err != nil && ctx.Err() == nil {
+		result = err
+	}
+	cancel()
+	this.Debug("Listen: ", p)
+
+	// Stop capture and wait for transcriptions to end
+	this.WaitGroup.Wait()
+	if err := capture.Close(); err != nil && result == nil {
+		result = err
+	}
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *listener) String() string {
+	str := "<listen"
+	str += fmt.Sprintf(" device=%q", *this.device)
+	str += " rate=" + fmt.Sprint(*this.rate)
+	if this.WakeWordDetector != nil {
+		str += " detector=" + fmt.Sprint(this.WakeWordDetector)
+	}
+	str += " recognizer=" + fmt.Sprint(this.SpeechRecognizer)
+	if this.muted {
+		str += " muted"
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *listener) Trigger() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.muted {
+		return gopi.ErrOutOfOrder.WithPrefix("Trigger: Muted")
+	} else {
+		this.triggered = true
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *listener) Mute(muted bool) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.muted = muted
+	this.triggered = false
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// process a frame, detecting the wake word and recording an utterance
+func (this *listener) process(ctx context.Context, frame []int16) {
+	this.Mutex.Lock()
+	muted, triggered := this.muted, this.triggered
+	this.triggered = false
+	this.Mutex.Unlock()
+
+	// Discard any utterance when muted
+	if muted {
+		this.utterance = nil
+		return
+	}
+
+	// Start recording on wake word or trigger
+	if this.utterance == nil {
+		keyword := ""
+		if triggered == false {
+			if this.WakeWordDetector == nil {
+				return
+			} else if kw, err := this.WakeWordDetector.Detect(frame); err != nil {
+				this.Debug("Listen: ", err)
+				return
+			} else if kw == "" {
+				return
+			} else {
+				keyword = kw
+			}
+		}
+		this.Debug("Listen: Recording keyword=", keyword)
+		this.utterance = newUtterance(keyword, *this.rate, *this.threshold, *this.silence, *this.timeout, *this.max)
+		return
+	}
+
+	// Record until end of utterance
+	if this.utterance.Add(frame) == false {
+		return
+	}
+	u := this.utterance
+	this.utterance = nil
+
+	// Emit empty transcript when there was no speech
+	if u.Speech() == false {
+		this.emit(NewEvent(u.keyword, "", u.Duration(), nil))
+		return
+	}
+
+	// Transcribe in the background
+	this.WaitGroup.Add(1)
+	go func() {
+		defer this.WaitGroup.Done()
+		text, err := this.SpeechRecognizer.Transcribe(ctx, u.Samples(), u.rate)
+		if err != nil {
+			this.Print("Listen: ", err)
+		}
+		this.emit(NewEvent(u.keyword, text, u.Duration(), err))
+	}()
+}
+
+func (this *listener) emit(evt gopi.TranscriptEvent) {
+	if this.Publisher == nil {
+		return
+	} else if err := this.Publisher.Emit(evt, false); err != nil {
+		this.Debug("Listen: ", err)
+	}
+}
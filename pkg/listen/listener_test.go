@@ -0,0 +1,115 @@
+package listen_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/listen"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Listener
+	gopi.Publisher
+}
+
+// recognizer returns the number of samples as text
+type recognizer struct {
+	gopi.Unit
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	args = []string{"-listen.device=null", "-listen.timeout=100ms"}
+)
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&recognizer{}), reflect.TypeOf((*gopi.SpeechRecognizer)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (this *recognizer) Transcribe(ctx context.Context, samples []int16, rate uint) (string, error) {
+	return "recognized", nil
+}
+
+// wait returns the next transcript event or nil after a timeout
+func wait(ch <-chan gopi.Event) gopi.TranscriptEvent {
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case evt := <-ch:
+			if evt, ok := evt.(gopi.TranscriptEvent); ok {
+				return evt
+			}
+		case <-timeout:
+			return nil
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Listen_001(t *testing.T) {
+	tool.Test(t, args, new(App), func(app *App) {
+		if app.Listener == nil {
+			t.Error("nil Listener unit")
+		} else {
+			t.Log(app.Listener)
+		}
+	})
+}
+
+func Test_Listen_002(t *testing.T) {
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Silence results in an empty transcript after timeout
+		if err := app.Listener.Trigger(); err != nil {
+			t.Error(err)
+		} else if evt := wait(ch); evt == nil {
+			t.Error("Timeout waiting for transcript")
+		} else if evt.Text() != "" || evt.Keyword() != "" || evt.Error() != nil {
+			t.Error("Unexpected event", evt)
+		} else if evt.Duration() < 100*time.Millisecond {
+			t.Error("Unexpected duration", evt.Duration())
+		} else {
+			t.Log(evt)
+		}
+	})
+}
+
+func Test_Listen_003(t *testing.T) {
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// No transcript when muted
+		app.Listener.Mute(true)
+		if err := app.Listener.Trigger(); err == nil {
+			t.Error("Expected error when muted")
+		} else if evt := wait(ch); evt != nil {
+			t.Error("Unexpected event", evt)
+		}
+	})
+}
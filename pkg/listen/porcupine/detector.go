@@ -0,0 +1,136 @@
+// +build porcupine
+
+package porcupine
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	porcupine "github.com/djthorpe/gopi/v3/pkg/sys/porcupine"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type detector struct {
+	gopi.Unit
+	gopi.Logger
+	sync.Mutex
+
+	key         *string
+	model       *string
+	keywords    *string
+	sensitivity *float64
+
+	names  []string
+	handle *porcupine.Porcupine
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *detector) Define(cfg gopi.Config) error {
+	this.key = cfg.FlagSecret("porcupine.key", "", "Picovoice access key")
+	this.model = cfg.FlagString("porcupine.model", "", "Path to model file")
+	this.keywords = cfg.FlagString("porcupine.keywords", "", "Comma-separated paths to keyword files")
+	this.sensitivity = cfg.FlagFloat("porcupine.sensitivity", 0.5, "Detection sensitivity between 0.0 and 1.0")
+	return nil
+}
+
+func (this *detector) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if *this.key == "" {
+		return gopi.ErrBadParameter.WithPrefix("-porcupine.key")
+	} else if *this.model == "" {
+		return gopi.ErrBadParameter.WithPrefix("-porcupine.model")
+	} else if *this.sensitivity < 0 || *this.sensitivity > 1 {
+		return gopi.ErrBadParameter.WithPrefix("-porcupine.sensitivity")
+	}
+
+	// Keyword names are the file names without extension and platform,
+	// for example "computer_raspberry-pi.ppn" is named "computer"
+	paths := []string{}
+	sensitivities := []float32{}
+	for _, path := range strings.Split(*this.keywords, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if i := strings.LastIndexByte(name, '_'); i > 0 {
+			name = name[:i]
+		}
+		paths = append(paths, path)
+		sensitivities = append(sensitivities, float32(*this.sensitivity))
+		this.names = append(this.names, name)
+	}
+	if len(paths) == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-porcupine.keywords")
+	}
+
+	// Create detector
+	if handle, err := porcupine.New(*this.key, *this.model, paths, sensitivities); err != nil {
+		return err
+	} else {
+		this.handle = handle
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *detector) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.handle != nil {
+		this.handle.Close()
+	}
+
+	// Release resources
+	this.handle = nil
+	this.names = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *detector) String() string {
+	str := "<listen.porcupine"
+	str += fmt.Sprintf(" version=%q", porcupine.Version())
+	str += fmt.Sprintf(" keywords=%q", this.names)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *detector) SampleRate() uint {
+	return porcupine.SampleRate()
+}
+
+func (this *detector) FrameLength() uint {
+	return porcupine.FrameLength()
+}
+
+func (this *detector) Detect(frame []int16) (string, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.handle == nil {
+		return "", gopi.ErrOutOfOrder.WithPrefix("Detect")
+	} else if index, err := this.handle.Process(frame); err != nil {
+		return "", err
+	} else if index < 0 || index >= len(this.names) {
+		return "", nil
+	} else {
+		return this.names[index], nil
+	}
+}
@@ -0,0 +1,3 @@
+// Porcupine package provides a wake word detector using the Picovoice
+// Porcupine library. You will need to use -tags porcupine when building.
+package porcupine
@@ -0,0 +1,15 @@
+// +build porcupine
+
+package porcupine
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register detector
+	graph.RegisterUnit(reflect.TypeOf(&detector{}), reflect.TypeOf((*gopi.WakeWordDetector)(nil)))
+}
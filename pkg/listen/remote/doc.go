@@ -0,0 +1,6 @@
+// Remote package provides a speech recognizer which sends utterances
+// as WAV audio to an HTTP service, such as the whisper.cpp server or
+// an OpenAI-compatible transcription endpoint. The audio is sent as
+// the "file" field of a multipart form, and the response is either
+// JSON with a "text" field or plain text.
+package remote
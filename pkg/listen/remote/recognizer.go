@@ -0,0 +1,132 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type recognizer struct {
+	gopi.Unit
+	gopi.Logger
+
+	url     *string
+	key     *string
+	model   *string
+	timeout *time.Duration
+	client  *http.Client
+}
+
+type response struct {
+	Text string `json:"text"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *recognizer) Define(cfg gopi.Config) error {
+	this.url = cfg.FlagString("stt.url", "", "Speech recognition service URL")
+	this.key = cfg.FlagSecret("stt.key", "", "Speech recognition service bearer token")
+	this.model = cfg.FlagString("stt.model", "", "Speech recognition model name")
+	this.timeout = cfg.FlagDuration("stt.timeout", 30*time.Second, "Speech recognition service timeout")
+	return nil
+}
+
+func (this *recognizer) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	if *this.url == "" {
+		return gopi.ErrBadParameter.WithPrefix("-stt.url")
+	} else if u, err := url.Parse(*this.url); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-stt.url: ", err)
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return gopi.ErrBadParameter.WithPrefix("-stt.url: ", *this.url)
+	}
+
+	this.client = &http.Client{Timeout: *this.timeout}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *recognizer) String() string {
+	str := "<listen.remote"
+	str += fmt.Sprintf(" url=%q", *this.url)
+	if *this.model != "" {
+		str += fmt.Sprintf(" model=%q", *this.model)
+	}
+	str += " timeout=" + fmt.Sprint(*this.timeout)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *recognizer) Transcribe(ctx context.Context, samples []int16, rate uint) (string, error) {
+	// Create form with audio and model
+	body := new(bytes.Buffer)
+	form := multipart.NewWriter(body)
+	if w, err := form.CreateFormFile("file", "audio.wav"); err != nil {
+		return "", err
+	} else if err := writeWAV(w, samples, rate); err != nil {
+		return "", err
+	}
+	if *this.model != "" {
+		if err := form.WriteField("model", *this.model); err != nil {
+			return "", err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	// Create request
+	req, err := http.NewRequest(http.MethodPost, *this.url, body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if *this.key != "" {
+		req.Header.Set("Authorization", "Bearer "+*this.key)
+	}
+
+	// Perform the request
+	resp, err := this.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	} else if resp.StatusCode != http.StatusOK {
+		return "", gopi.ErrUnexpectedResponse.WithPrefix(*this.url, ": ", resp.Status)
+	}
+
+	// Decode JSON or plain text response
+	if mimetype, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mimetype == "application/json" {
+		var r response
+		if err := json.Unmarshal(data, &r); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(r.Text), nil
+	} else {
+		return strings.TrimSpace(string(data)), nil
+	}
+}
@@ -0,0 +1,84 @@
+package remote_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/listen/remote"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.SpeechRecognizer
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Remote_001(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		file, _, err := req.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, _ := ioutil.ReadAll(file)
+		if len(data) != 44+6 || string(data[0:4]) != "RIFF" || binary.LittleEndian.Uint32(data[24:]) != 16000 {
+			http.Error(w, "Bad WAV file", http.StatusBadRequest)
+		} else if req.FormValue("model") != "whisper-1" || req.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "Bad model or key", http.StatusUnauthorized)
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{ "text": " turn on the lights " }`))
+		}
+	}))
+	defer server.Close()
+
+	args := []string{"-stt.url=" + server.URL, "-stt.model=whisper-1", "-stt.key=secret"}
+	tool.Test(t, args, new(App), func(app *App) {
+		if text, err := app.SpeechRecognizer.Transcribe(context.Background(), []int16{1, 2, 3}, 16000); err != nil {
+			t.Error(err)
+		} else if text != "turn on the lights" {
+			t.Errorf("Unexpected text %q", text)
+		}
+	})
+}
+
+func Test_Remote_002(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("hello\n"))
+	}))
+	defer server.Close()
+
+	tool.Test(t, []string{"-stt.url=" + server.URL}, new(App), func(app *App) {
+		if text, err := app.SpeechRecognizer.Transcribe(context.Background(), []int16{1, 2, 3}, 16000); err != nil {
+			t.Error(err)
+		} else if text != "hello" {
+			t.Errorf("Unexpected text %q", text)
+		}
+	})
+}
+
+func Test_Remote_003(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "Server error", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tool.Test(t, []string{"-stt.url=" + server.URL}, new(App), func(app *App) {
+		if _, err := app.SpeechRecognizer.Transcribe(context.Background(), []int16{1, 2, 3}, 16000); err == nil {
+			t.Error("Expected error")
+		}
+	})
+}
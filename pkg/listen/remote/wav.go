@@ -0,0 +1,25 @@
+package remote
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// writeWAV writes mono signed 16-bit samples as a WAV file
+func writeWAV(w io.Writer, samples []int16, rate uint) error {
+	size := uint32(len(samples) * 2)
+	header := []interface{}{
+		[]byte("RIFF"), 36 + size, []byte("WAVE"),
+		[]byte("fmt "), uint32(16), uint16(1), uint16(1), uint32(rate), uint32(rate * 2), uint16(2), uint16(16),
+		[]byte("data"), size,
+	}
+	for _, v := range header {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			return err
+		}
+	}
+	return binary.Write(w, binary.LittleEndian, samples)
+}
@@ -0,0 +1,100 @@
+package listen
+
+import (
+	"math"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// utterance records samples after a wake word until there is silence
+// after speech, no speech before a timeout, or a maximum duration
+type utterance struct {
+	keyword   string
+	rate      uint
+	threshold float64
+	silence   uint // Samples of silence which end speech
+	timeout   uint // Samples to wait for speech to start
+	max       uint // Maximum samples
+	samples   []int16
+	speech    bool
+	quiet     uint
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newUtterance(keyword string, rate, threshold uint, silence, timeout, max time.Duration) *utterance {
+	this := new(utterance)
+	this.keyword = keyword
+	this.rate = rate
+	this.threshold = float64(threshold)
+	this.silence = samples(silence, rate)
+	this.timeout = samples(timeout, rate)
+	this.max = samples(max, rate)
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+// Speech returns true if speech was detected
+func (this *utterance) Speech() bool {
+	return this.speech
+}
+
+// Samples returns the recorded samples
+func (this *utterance) Samples() []int16 {
+	return this.samples
+}
+
+// Duration returns the duration of the recorded samples
+func (this *utterance) Duration() time.Duration {
+	return time.Duration(len(this.samples)) * time.Second / time.Duration(this.rate)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Add a frame of samples and return true when the utterance has ended
+func (this *utterance) Add(frame []int16) bool {
+	this.samples = append(this.samples, frame...)
+	if rms(frame) >= this.threshold {
+		this.speech = true
+		this.quiet = 0
+	} else {
+		this.quiet += uint(len(frame))
+	}
+
+	n := uint(len(this.samples))
+	switch {
+	case n >= this.max:
+		return true
+	case this.speech && this.quiet >= this.silence:
+		return true
+	case this.speech == false && n >= this.timeout:
+		return true
+	default:
+		return false
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// rms returns the root mean square level of samples
+func rms(frame []int16) float64 {
+	if len(frame) == 0 {
+		return 0
+	}
+	sum := float64(0)
+	for _, s := range frame {
+		sum += float64(s) * float64(s)
+	}
+	return math.Sqrt(sum / float64(len(frame)))
+}
+
+func samples(d time.Duration, rate uint) uint {
+	return uint(d * time.Duration(rate) / time.Second)
+}
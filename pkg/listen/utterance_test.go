@@ -0,0 +1,72 @@
+package listen
+
+import (
+	"testing"
+	"time"
+)
+
+func frame(level int16, n int) []int16 {
+	frame := make([]int16, n)
+	for i := range frame {
+		if i%2 == 0 {
+			frame[i] = level
+		} else {
+			frame[i] = -level
+		}
+	}
+	return frame
+}
+
+func Test_Utterance_001(t *testing.T) {
+	// 100 samples per 10ms frame
+	u := newUtterance("", 10000, 500, 50*time.Millisecond, 100*time.Millisecond, time.Second)
+	if u.Add(frame(1000, 100)) {
+		t.Error("Unexpected end after speech")
+	}
+	for i := 0; i < 4; i++ {
+		if u.Add(frame(10, 100)) {
+			t.Error("Unexpected end after", i, "frames of silence")
+		}
+	}
+	if u.Add(frame(10, 100)) == false {
+		t.Error("Expected end after silence")
+	} else if u.Speech() == false {
+		t.Error("Expected speech")
+	} else if u.Duration() != 60*time.Millisecond {
+		t.Error("Unexpected duration", u.Duration())
+	}
+}
+
+func Test_Utterance_002(t *testing.T) {
+	u := newUtterance("", 10000, 500, 50*time.Millisecond, 100*time.Millisecond, time.Second)
+	for i := 0; i < 9; i++ {
+		if u.Add(frame(0, 100)) {
+			t.Error("Unexpected end after", i, "frames")
+		}
+	}
+	if u.Add(frame(0, 100)) == false {
+		t.Error("Expected end after timeout")
+	} else if u.Speech() {
+		t.Error("Unexpected speech")
+	}
+}
+
+func Test_Utterance_003(t *testing.T) {
+	u := newUtterance("", 10000, 500, 50*time.Millisecond, 100*time.Millisecond, 200*time.Millisecond)
+	for i := 0; i < 19; i++ {
+		if u.Add(frame(1000, 100)) {
+			t.Error("Unexpected end after", i, "frames")
+		}
+	}
+	if u.Add(frame(1000, 100)) == false {
+		t.Error("Expected end after maximum duration")
+	}
+}
+
+func Test_Utterance_004(t *testing.T) {
+	if level := rms(frame(100, 10)); level != 100 {
+		t.Error("Unexpected level", level)
+	} else if level := rms(nil); level != 0 {
+		t.Error("Unexpected level", level)
+	}
+}
@@ -0,0 +1,3 @@
+// Whisper package provides a speech recognizer using the whisper.cpp
+// library. You will need to use -tags whisper when building.
+package whisper
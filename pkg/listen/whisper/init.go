@@ -0,0 +1,15 @@
+// +build whisper
+
+package whisper
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register recognizer
+	graph.RegisterUnit(reflect.TypeOf(&recognizer{}), reflect.TypeOf((*gopi.SpeechRecognizer)(nil)))
+}
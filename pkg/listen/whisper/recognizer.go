@@ -0,0 +1,109 @@
+// +build whisper
+
+package whisper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	whisper "github.com/djthorpe/gopi/v3/pkg/sys/whisper"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type recognizer struct {
+	gopi.Unit
+	gopi.Logger
+	sync.Mutex
+
+	model    *string
+	language *string
+	threads  *uint
+	ctx      *whisper.Context
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *recognizer) Define(cfg gopi.Config) error {
+	this.model = cfg.FlagString("whisper.model", "", "Path to model file")
+	this.language = cfg.FlagString("whisper.language", "en", "Language code, or auto to detect")
+	this.threads = cfg.FlagUint("whisper.threads", 4, "Number of threads")
+	return nil
+}
+
+func (this *recognizer) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	if *this.model == "" {
+		return gopi.ErrBadParameter.WithPrefix("-whisper.model")
+	} else if *this.threads == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-whisper.threads")
+	} else if ctx, err := whisper.New(*this.model); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-whisper.model: ", err)
+	} else {
+		this.ctx = ctx
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *recognizer) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.ctx != nil {
+		this.ctx.Close()
+	}
+
+	// Release resources
+	this.ctx = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *recognizer) String() string {
+	str := "<listen.whisper"
+	str += fmt.Sprintf(" model=%q", *this.model)
+	str += fmt.Sprintf(" language=%q", *this.language)
+	str += " threads=" + fmt.Sprint(*this.threads)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *recognizer) Transcribe(ctx context.Context, samples []int16, rate uint) (string, error) {
+	// The model processes one utterance at a time
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.ctx == nil {
+		return "", gopi.ErrOutOfOrder.WithPrefix("Transcribe")
+	} else if rate != whisper.SAMPLE_RATE {
+		return "", gopi.ErrBadParameter.WithPrefix("Transcribe: Sample rate ", rate)
+	}
+
+	// Convert samples to float32
+	data := make([]float32, len(samples))
+	for i, s := range samples {
+		data[i] = float32(s) / 32768
+	}
+
+	// Transcribe, stopping when the context is cancelled
+	text, err := this.ctx.Transcribe(data, *this.language, *this.threads, func() bool {
+		return ctx.Err() != nil
+	})
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return text, err
+}
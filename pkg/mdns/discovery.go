@@ -47,7 +47,7 @@ FOR_LOOP:
 		select {
 		case evt := <-ch:
 			if msg, ok := evt.(*msgevent); ok {
-				if err := this.ParseEmit(msg.Msg); err != nil {
+				if err := this.ParseEmit(msg.Msg, this.Listener.IfaceName(msg.ifIndex)); err != nil {
 					this.Print(err)
 				}
 			}
@@ -63,9 +63,9 @@ FOR_LOOP:
 	return ctx.Err()
 }
 
-func (this *Discovery) ParseEmit(msg *dns.Msg) error {
+func (this *Discovery) ParseEmit(msg *dns.Msg, iface string) error {
 	// Parse into services
-	services := NewServices(msg, this.Listener.Zone()).Services()
+	services := NewServices(msg, this.Listener.Zone(), iface).Services()
 	if len(services) == 0 {
 		return nil
 	}
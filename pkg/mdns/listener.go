@@ -54,7 +54,7 @@ var (
 
 func (this *Listener) Define(cfg gopi.Config) error {
 	this.domain = cfg.FlagString("mdns.domain", "local.", "mDNS domain")
-	this.iface = cfg.FlagString("mdns.iface", "", "mDNS listening interface")
+	this.iface = cfg.FlagString("mdns.iface", "", "Comma-separated mDNS listening interfaces, or empty for all")
 	return nil
 }
 
@@ -68,9 +68,9 @@ func (this *Listener) New(cfg gopi.Config) error {
 	}
 
 	// Obtain the interfaces for listening
-	if iface, err := interfaceForName(*this.iface); err != nil {
+	if ifaces, err := interfacesForNames(*this.iface); err != nil {
 		return err
-	} else if ifaces, err := multicastInterfaces(iface); err != nil {
+	} else if ifaces, err := multicastInterfaces(ifaces); err != nil {
 		return err
 	} else if len(ifaces) == 0 {
 		return fmt.Errorf("No interfaces defined for listening")
@@ -281,11 +281,9 @@ func (this *Listener) AddrForIface(ifIndex int, flags gopi.ServiceFlag) []net.IP
 			if ip, _, err := net.ParseCIDR(addr.String()); err != nil {
 				this.Debug("AddrForIface: Error: ", addr.String())
 				continue
-			} else if ip.Equal(net.IPv6loopback) {
+			} else if ip.IsLoopback() {
 				continue
-			} else if ip.String() == "127.0.0.1" {
-				continue
-			} else if flags == gopi.SERVICE_FLAG_IP6 && ip.To16() != nil {
+			} else if flags == gopi.SERVICE_FLAG_IP6 && ip.To4() == nil {
 				ips = append(ips, ip)
 			} else if flags == gopi.SERVICE_FLAG_IP4 && ip.To4() != nil {
 				ips = append(ips, ip)
@@ -295,6 +293,27 @@ func (this *Listener) AddrForIface(ifIndex int, flags gopi.ServiceFlag) []net.IP
 	return ips
 }
 
+// IfaceName returns the name of a listening interface, or empty
+func (this *Listener) IfaceName(ifIndex int) string {
+	for _, iface := range this.ifaces {
+		if iface.Index == ifIndex {
+			return iface.Name
+		}
+	}
+	return ""
+}
+
+// Ifaces returns the indexes of listening interfaces which are up
+func (this *Listener) Ifaces() []int {
+	result := make([]int, 0, len(this.ifaces))
+	for _, iface := range this.ifaces {
+		if iface.Flags&net.FlagUp != 0 {
+			result = append(result, iface.Index)
+		}
+	}
+	return result
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
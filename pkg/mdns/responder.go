@@ -141,8 +141,11 @@ func (this *Responder) ProcessQuestion(msg *msgevent) error {
 			continue
 		}
 
-		// Process responses to question
-		answers := handleQuestion(msg.Msg, q, zone, this.Services, this.Records)
+		// Process responses to question, with addresses for the interface
+		// on which the question was received
+		answers := handleQuestion(msg.Msg, q, zone, this.Services, func(name string) []gopi.ServiceRecord {
+			return this.recordsForIface(this.Records(name), msg.ifIndex)
+		})
 
 		// Send answers, ignoring any errors
 		this.SendAnswers(msg.ifIndex, answers)
@@ -180,20 +183,23 @@ func (this *Responder) Serve(ctx context.Context, r []gopi.ServiceRecord) error
 		this.Debug("Serve: ", this.names)
 	}
 
-	// Register services and service records
-	msgs := []*dns.Msg{}
+	// Register services, and service records on each interface with
+	// the addresses of that interface
 	zone := this.Listener.Zone()
-	msgs = append(msgs, answerEnum(dns.Question{
+	this.SendAnswers(0, answerEnum(dns.Question{
 		Name:  fqn(queryServices) + zone,
 		Qtype: dns.TypeANY,
-	}, this.names, zone)...)
-	for _, record := range r {
-		msgs = append(msgs, answerServiceRecords(dns.Question{
-			Name:  record.Service() + record.Zone(),
-			Qtype: dns.TypeANY,
-		}, []gopi.ServiceRecord{record}, queryDefaultTTL)...)
+	}, this.names, zone))
+	for _, ifIndex := range this.Listener.Ifaces() {
+		msgs := []*dns.Msg{}
+		for _, record := range this.recordsForIface(r, ifIndex) {
+			msgs = append(msgs, answerServiceRecords(dns.Question{
+				Name:  record.Service() + record.Zone(),
+				Qtype: dns.TypeANY,
+			}, []gopi.ServiceRecord{record}, queryDefaultTTL)...)
+		}
+		this.SendAnswers(ifIndex, msgs)
 	}
-	this.SendAnswers(0, msgs)
 
 	// Wait for context to end
 	<-ctx.Done()
@@ -260,6 +266,30 @@ func (this *Responder) NewServiceRecord(service string, name string, port uint16
 ///////////////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
+// recordsForIface returns service records with the addresses of an
+// interface, so that devices with several interfaces do not advertise
+// addresses which cannot be reached from the network of the interface
+func (this *Responder) recordsForIface(records []gopi.ServiceRecord, ifIndex int) []gopi.ServiceRecord {
+	if ifIndex == 0 {
+		return records
+	}
+	result := make([]gopi.ServiceRecord, 0, len(records))
+	for _, record := range records {
+		if r, ok := record.(*service); ok {
+			r_ := *r
+			if len(r.a) > 0 {
+				r_.a = this.Listener.AddrForIface(ifIndex, gopi.SERVICE_FLAG_IP4)
+			}
+			if len(r.aaaa) > 0 {
+				r_.aaaa = this.Listener.AddrForIface(ifIndex, gopi.SERVICE_FLAG_IP6)
+			}
+			record = &r_
+		}
+		result = append(result, record)
+	}
+	return result
+}
+
 // isRelevantQuestion returns true if a question has a suffix of a recorded
 // service, ie, it's relevant to be answered
 func (this *Responder) isRelevantQuestion(q dns.Question, zone string) bool {
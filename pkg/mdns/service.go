@@ -22,6 +22,7 @@ type service struct {
 	aaaa    []net.IP
 	txt     []string
 	ttl     time.Duration
+	iface   string
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -79,6 +80,11 @@ func (this *service) Txt() []string {
 	return this.txt
 }
 
+// Iface returns the interface on which the service was discovered
+func (this *service) Iface() string {
+	return this.iface
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // SET PROPERTIES
 
@@ -134,5 +140,8 @@ func (this *service) String() string {
 	if this.ttl != 0 {
 		str += fmt.Sprintf(" ttl=%v", this.ttl)
 	}
+	if this.iface != "" {
+		str += fmt.Sprintf(" iface=%q", this.iface)
+	}
 	return str + ">"
 }
@@ -11,8 +11,8 @@ type services struct {
 	services []*service
 }
 
-// Parse DNS message and capture service records
-func NewServices(msg *dns.Msg, zone string) *services {
+// Parse DNS message received on an interface and capture service records
+func NewServices(msg *dns.Msg, zone, iface string) *services {
 	this := new(services)
 	sections := append(append(msg.Answer, msg.Ns...), msg.Extra...)
 	for _, answer := range sections {
@@ -20,6 +20,7 @@ func NewServices(msg *dns.Msg, zone string) *services {
 		case *dns.PTR:
 			this.services = append(this.services, NewService(zone))
 			this.services[0].SetPTR(rr)
+			this.services[0].iface = iface
 		case *dns.SRV:
 			if len(this.services) > 0 {
 				this.services[0].SetSRV(rr.Target, rr.Port, rr.Priority)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"strings"
 	"syscall"
 
 	"github.com/djthorpe/gopi/v3"
@@ -15,32 +16,33 @@ import (
 
 ///////////////////////////////////////////////////////////////////////////////
 
-// interfaceForName returns a net.Interface or error
-func interfaceForName(name string) (net.Interface, error) {
-	if name == "" {
-		return net.Interface{}, nil
-	}
-	ifaces, err := net.Interfaces()
-	if err != nil {
-		return net.Interface{}, err
-	}
-	for _, iface := range ifaces {
-		if iface.Name == name {
-			return iface, nil
+// interfacesForNames returns interfaces for comma-separated names, or
+// nil when names is empty
+func interfacesForNames(names string) ([]net.Interface, error) {
+	var result []net.Interface
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		} else if iface, err := net.InterfaceByName(name); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix(name)
+		} else {
+			result = append(result, *iface)
 		}
 	}
-	return net.Interface{}, gopi.ErrBadParameter.WithPrefix(name)
+	return result, nil
 }
 
 // multicastInterfaces returns one or more interfaces which should be bound
-// for listening
-func multicastInterfaces(iface net.Interface) ([]net.Interface, error) {
-	if iface.Name != "" {
-		if (iface.Flags&net.FlagUp) > 0 && (iface.Flags&net.FlagMulticast) > 0 {
-			return []net.Interface{iface}, nil
-		} else {
-			return nil, fmt.Errorf("Interface %v is not up and/or multicast-enabled", iface.Name)
+// for listening, which are the named interfaces or else all interfaces
+// which are up and multicast-enabled
+func multicastInterfaces(ifaces []net.Interface) ([]net.Interface, error) {
+	if len(ifaces) > 0 {
+		for _, iface := range ifaces {
+			if (iface.Flags&net.FlagUp) == 0 || (iface.Flags&net.FlagMulticast) == 0 {
+				return nil, fmt.Errorf("Interface %v is not up and/or multicast-enabled", iface.Name)
+			}
 		}
+		return ifaces, nil
 	}
 	if ifaces, err := net.Interfaces(); err != nil {
 		return nil, err
@@ -0,0 +1,126 @@
+package airplay
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// buffer orders decoded packets by sequence number so that they can be
+// played in order, and detects missing packets which can be requested
+// from the sender again
+type buffer struct {
+	packets map[uint16]*packet
+	started bool
+	next    uint16 // Sequence number of the next packet to play
+	nextTs  uint32 // Timestamp of the next packet to play
+	highest uint16 // Highest sequence number received
+}
+
+type packet struct {
+	Seq     uint16
+	Ts      uint32
+	Samples []int16
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Maximum number of packets buffered, which is about eight seconds
+	// of audio with 352 frames in each packet
+	maxPackets = 1024
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newBuffer() *buffer {
+	return &buffer{packets: make(map[uint16]*packet)}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+// Len returns the number of packets buffered
+func (this *buffer) Len() int {
+	return len(this.packets)
+}
+
+// Next returns the sequence number and timestamp of the next packet
+// to play, and false if no packets have been received
+func (this *buffer) Next() (uint16, uint32, bool) {
+	return this.next, this.nextTs, this.started
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Put a packet in the buffer, returning the first sequence number and
+// count of any packets which are missing before it
+func (this *buffer) Put(p *packet) (uint16, uint16) {
+	// The first packet sets the start of the stream
+	if this.started == false || seqDiff(p.Seq, this.next) >= maxPackets {
+		this.Reset()
+		this.started = true
+		this.next, this.nextTs, this.highest = p.Seq, p.Ts, p.Seq
+		this.packets[p.Seq] = p
+		return 0, 0
+	}
+
+	// Drop packets which are too late to be played
+	if seqDiff(p.Seq, this.next) < 0 {
+		return 0, 0
+	}
+	this.packets[p.Seq] = p
+
+	// Return any gap after the highest packet received
+	if gap := seqDiff(p.Seq, this.highest); gap > 0 {
+		first := this.highest + 1
+		this.highest = p.Seq
+		return first, uint16(gap - 1)
+	}
+	return 0, 0
+}
+
+// Peek returns the next packet to play, or nil if it is missing
+func (this *buffer) Peek() *packet {
+	if this.started == false {
+		return nil
+	} else {
+		return this.packets[this.next]
+	}
+}
+
+// Advance to the next packet, where frames is the number of frames
+// in the packet played
+func (this *buffer) Advance(frames uint32) {
+	if this.started == false {
+		return
+	}
+	if p, exists := this.packets[this.next]; exists {
+		this.nextTs = p.Ts + frames
+		delete(this.packets, this.next)
+	} else {
+		this.nextTs += frames
+	}
+	if this.next == this.highest {
+		this.highest++
+	}
+	this.next++
+}
+
+// Reset discards all packets, so that the next packet received
+// restarts the stream
+func (this *buffer) Reset() {
+	for seq := range this.packets {
+		delete(this.packets, seq)
+	}
+	this.started = false
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// seqDiff returns the difference between two sequence numbers, which
+// wrap around
+func seqDiff(a, b uint16) int {
+	return int(int16(a - b))
+}
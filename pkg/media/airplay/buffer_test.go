@@ -0,0 +1,74 @@
+package airplay
+
+import (
+	"testing"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Buffer_001(t *testing.T) {
+	b := newBuffer()
+	if p := b.Peek(); p != nil {
+		t.Error("Unexpected packet", p)
+	}
+	if _, _, started := b.Next(); started {
+		t.Error("Unexpected start")
+	}
+	if _, count := b.Put(&packet{Seq: 100, Ts: 1000}); count != 0 {
+		t.Error("Unexpected missing packets", count)
+	}
+	if seq, ts, started := b.Next(); seq != 100 || ts != 1000 || started == false {
+		t.Error("Unexpected next", seq, ts, started)
+	}
+
+	// Packets 101 and 102 are missing
+	if first, count := b.Put(&packet{Seq: 103, Ts: 1000 + 3*352}); first != 101 || count != 2 {
+		t.Error("Unexpected missing packets", first, count)
+	}
+	if _, count := b.Put(&packet{Seq: 101, Ts: 1000 + 352}); count != 0 {
+		t.Error("Unexpected missing packets", count)
+	}
+	if b.Len() != 3 {
+		t.Error("Unexpected length", b.Len())
+	}
+
+	// Play packets in order, with 102 missing
+	for _, seq := range []uint16{100, 101, 102, 103} {
+		if p := b.Peek(); seq == 102 && p != nil {
+			t.Error("Unexpected packet", p)
+		} else if seq != 102 && (p == nil || p.Seq != seq) {
+			t.Error("Unexpected packet", p)
+		}
+		b.Advance(352)
+	}
+	if seq, ts, _ := b.Next(); seq != 104 || ts != 1000+4*352 {
+		t.Error("Unexpected next", seq, ts)
+	}
+
+	// Late packets are dropped
+	b.Put(&packet{Seq: 102})
+	if b.Len() != 0 {
+		t.Error("Unexpected length", b.Len())
+	}
+}
+
+func Test_Buffer_002(t *testing.T) {
+	b := newBuffer()
+
+	// Sequence numbers wrap around
+	b.Put(&packet{Seq: 0xFFFF})
+	if first, count := b.Put(&packet{Seq: 1}); first != 0 || count != 1 {
+		t.Error("Unexpected missing packets", first, count)
+	}
+
+	// Reset restarts the stream at the next packet
+	b.Reset()
+	if _, _, started := b.Next(); started {
+		t.Error("Unexpected start")
+	}
+	b.Put(&packet{Seq: 500, Ts: 5000})
+	if seq, ts, _ := b.Next(); seq != 500 || ts != 5000 {
+		t.Error("Unexpected next", seq, ts)
+	}
+}
@@ -0,0 +1,99 @@
+package airplay
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// clock estimates the offset between the sender clock and the local
+// clock from timing exchanges, using the exchange with the shortest
+// round trip from recent exchanges
+type clock struct {
+	sync.Mutex
+
+	samples []timing
+}
+
+type timing struct {
+	offset, rtt time.Duration
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	clockSamples = 8
+)
+
+var (
+	// Start of the NTP epoch
+	ntpEpoch = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Update the offset from an exchange, with the time the request was
+// sent, the sender times it was received and the reply sent, and the
+// time the reply was received
+func (this *clock) Update(t0, t1, t2, t3 time.Time) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	sample := timing{
+		offset: (t1.Sub(t0) + t2.Sub(t3)) / 2,
+		rtt:    t3.Sub(t0) - t2.Sub(t1),
+	}
+	if sample.rtt < 0 {
+		return
+	}
+	this.samples = append(this.samples, sample)
+	if len(this.samples) > clockSamples {
+		this.samples = this.samples[1:]
+	}
+}
+
+// Offset returns the sender clock minus the local clock, which is
+// zero before any exchanges
+func (this *clock) Offset() time.Duration {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if len(this.samples) == 0 {
+		return 0
+	}
+	best := this.samples[0]
+	for _, sample := range this.samples[1:] {
+		if sample.rtt < best.rtt {
+			best = sample
+		}
+	}
+	return best.offset
+}
+
+// Local converts a time from the sender clock to the local clock
+func (this *clock) Local(t time.Time) time.Time {
+	return t.Add(-this.Offset())
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NTP TIME
+
+// getNTP returns the time from a 64-bit NTP timestamp
+func getNTP(data []byte) time.Time {
+	secs := binary.BigEndian.Uint32(data[0:])
+	frac := binary.BigEndian.Uint32(data[4:])
+	return ntpEpoch.Add(time.Duration(secs)*time.Second + time.Duration((uint64(frac)*uint64(time.Second))>>32))
+}
+
+// putNTP writes a time as a 64-bit NTP timestamp
+func putNTP(data []byte, t time.Time) {
+	secs := uint64(t.Unix() - ntpEpoch.Unix())
+	frac := (uint64(t.Nanosecond()) << 32) / uint64(time.Second)
+	binary.BigEndian.PutUint32(data[0:], uint32(secs))
+	binary.BigEndian.PutUint32(data[4:], uint32(frac))
+}
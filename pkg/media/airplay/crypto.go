@@ -0,0 +1,94 @@
+package airplay
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// cipherFunc decrypts a packet in place
+type cipherFunc func([]byte)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// readKey reads an RSA private key in PKCS#1 or PKCS#8 PEM format
+func readKey(path string) (*rsa.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("No PEM data: ", path)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	} else if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		return nil, err
+	} else if key, ok := key.(*rsa.PrivateKey); ok == false {
+		return nil, gopi.ErrBadParameter.WithPrefix("Not an RSA key: ", path)
+	} else {
+		return key, nil
+	}
+}
+
+// appleResponse returns the response to an Apple-Challenge header,
+// which is the challenge, address and hardware address signed
+// with the private key
+func appleResponse(key *rsa.PrivateKey, challenge string, ip net.IP, mac net.HardwareAddr) (string, error) {
+	data, err := decodeBase64(challenge)
+	if err != nil {
+		return "", gopi.ErrBadParameter.WithPrefix("Apple-Challenge")
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		data = append(data, ip4...)
+	} else {
+		data = append(data, ip.To16()...)
+	}
+	data = append(data, mac...)
+	for len(data) < 32 {
+		data = append(data, 0)
+	}
+	if sig, err := rsa.SignPKCS1v15(nil, key, crypto.Hash(0), data); err != nil {
+		return "", err
+	} else {
+		return base64.RawStdEncoding.EncodeToString(sig), nil
+	}
+}
+
+// newCipher returns a function which decrypts packets with the AES
+// key from the sender. Each packet is encrypted separately using
+// AES-CBC, leaving any partial block at the end unencrypted
+func newCipher(key *rsa.PrivateKey, f *format) (cipherFunc, error) {
+	if f.Encrypted() == false {
+		return nil, nil
+	} else if key == nil {
+		return nil, gopi.ErrNotImplemented.WithPrefix("Encrypted stream requires -airplay.key")
+	}
+	aeskey, err := rsa.DecryptOAEP(sha1.New(), nil, key, f.Key, nil)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(aeskey)
+	if err != nil {
+		return nil, err
+	}
+	return func(data []byte) {
+		if n := len(data) / aes.BlockSize * aes.BlockSize; n > 0 {
+			cipher.NewCBCDecrypter(block, f.IV).CryptBlocks(data[:n], data[:n])
+		}
+	}, nil
+}
@@ -0,0 +1,48 @@
+package airplay
+
+import (
+	"encoding/binary"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// decoder returns interleaved samples from the payload of a packet
+type decoder interface {
+	Decode([]byte) ([]int16, error)
+	Close() error
+}
+
+// pcm decodes uncompressed big-endian samples
+type pcm struct{}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newDecoder(f *format) (decoder, error) {
+	switch f.Codec {
+	case codecPCM:
+		return pcm{}, nil
+	case codecALAC:
+		return newALAC(f)
+	default:
+		return nil, gopi.ErrNotImplemented.WithPrefix("Codec: ", f.Codec)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PCM
+
+func (pcm) Decode(data []byte) ([]int16, error) {
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.BigEndian.Uint16(data[i*2:]))
+	}
+	return samples, nil
+}
+
+func (pcm) Close() error {
+	return nil
+}
@@ -0,0 +1,118 @@
+// +build ffmpeg
+
+package airplay
+
+import (
+	"encoding/binary"
+	"syscall"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	ffmpeg "github.com/djthorpe/gopi/v3/pkg/sys/ffmpeg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// alac decodes Apple Lossless packets using ffmpeg
+type alac struct {
+	ctx    *ffmpeg.AVCodecContext
+	packet *ffmpeg.AVPacket
+	frame  *ffmpeg.AVFrame
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	alacSupported = true
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newALAC(f *format) (decoder, error) {
+	this := new(alac)
+
+	codec := ffmpeg.FindDecoderById(ffmpeg.AV_CODEC_ID_ALAC)
+	if codec == nil {
+		return nil, gopi.ErrNotFound.WithPrefix("ALAC decoder")
+	}
+	if this.ctx = ffmpeg.NewAVCodecContext(codec); this.ctx == nil {
+		return nil, gopi.ErrInternalAppError.WithPrefix("NewAVCodecContext")
+	}
+	if err := this.ctx.SetExtraData(f.Cookie()); err != nil {
+		this.ctx.Free()
+		return nil, err
+	} else if err := this.ctx.Open(codec, nil); err != nil {
+		this.ctx.Free()
+		return nil, err
+	}
+	this.packet = ffmpeg.NewAVPacket()
+	this.frame = ffmpeg.NewAVFrame()
+
+	// Return success
+	return this, nil
+}
+
+func (this *alac) Close() error {
+	this.frame.Free()
+	this.packet.Free()
+	this.ctx.Free()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *alac) Decode(data []byte) ([]int16, error) {
+	if err := this.packet.SetBytes(data); err != nil {
+		return nil, err
+	}
+	defer this.packet.Release()
+	if err := this.ctx.DecodePacket(this.packet); err != nil {
+		return nil, err
+	}
+
+	// Receive frames from the decoder
+	var samples []int16
+	for {
+		if err := this.ctx.DecodeFrame(this.frame); err == syscall.EAGAIN {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		samples = appendFrame(samples, this.frame)
+		this.frame.Release()
+	}
+
+	// Return success
+	return samples, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// appendFrame appends the samples in a frame as interleaved 16-bit
+// samples
+func appendFrame(samples []int16, frame *ffmpeg.AVFrame) []int16 {
+	channels, n, f := frame.Channels(), frame.NumSamples(), frame.SampleFormat()
+	planes := make([][]byte, channels)
+	for c := range planes {
+		planes[c] = frame.AudioPlane(c)
+	}
+	for i := 0; i < n; i++ {
+		for c := 0; c < channels; c++ {
+			switch f {
+			case ffmpeg.AV_SAMPLE_FMT_S16:
+				samples = append(samples, int16(binary.LittleEndian.Uint16(planes[0][(i*channels+c)*2:])))
+			case ffmpeg.AV_SAMPLE_FMT_S16P:
+				samples = append(samples, int16(binary.LittleEndian.Uint16(planes[c][i*2:])))
+			case ffmpeg.AV_SAMPLE_FMT_S32:
+				samples = append(samples, int16(binary.LittleEndian.Uint32(planes[0][(i*channels+c)*4:])>>16))
+			case ffmpeg.AV_SAMPLE_FMT_S32P:
+				samples = append(samples, int16(binary.LittleEndian.Uint32(planes[c][i*4:])>>16))
+			}
+		}
+	}
+	return samples
+}
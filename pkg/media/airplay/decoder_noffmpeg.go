@@ -0,0 +1,21 @@
+// +build !ffmpeg
+
+package airplay
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	alacSupported = false
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newALAC(*format) (decoder, error) {
+	return nil, gopi.ErrNotImplemented.WithPrefix("ALAC decoding requires the ffmpeg build tag")
+}
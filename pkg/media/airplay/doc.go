@@ -0,0 +1,18 @@
+// Airplay package makes the device an AirPlay (RAOP) speaker, so that
+// audio can be streamed to it from phones and computers. The speaker
+// is advertised when a gopi.ServiceDiscovery unit is available, and
+// audio is played on the ALSA device set with -airplay.device, which
+// defaults to the device for the detected gopi.AudioDAC.
+//
+// Apple Lossless audio is decoded using ffmpeg, which requires the
+// ffmpeg build tag. Without it, only uncompressed audio is accepted.
+// Encrypted streams require the RSA private key for the protocol,
+// which is set with the -airplay.key flag. Without a key, the speaker
+// advertises that it accepts unencrypted streams only.
+//
+// Playback is synchronized with the sender clock, so that several
+// speakers play in time with each other. Set -airplay.offset to the
+// latency of any amplifier or other equipment after the ALSA device.
+//
+// Ref: https://nto.github.io/AirPlay.html
+package airplay
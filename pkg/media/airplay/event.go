@@ -0,0 +1,55 @@
+package airplay
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	state  gopi.AirPlayState
+	sender string
+	volume float32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(state gopi.AirPlayState, sender string, volume float32) gopi.AirPlayEvent {
+	return &event{state, sender, volume}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.sender
+}
+
+func (this *event) State() gopi.AirPlayState {
+	return this.state
+}
+
+func (this *event) Sender() string {
+	return this.sender
+}
+
+func (this *event) Volume() float32 {
+	return this.volume
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<event.airplay"
+	str += " state=" + fmt.Sprint(this.state)
+	if this.sender != "" {
+		str += fmt.Sprintf(" sender=%q", this.sender)
+	}
+	str += fmt.Sprintf(" volume=%.2f", this.volume)
+	return str + ">"
+}
@@ -0,0 +1,13 @@
+package airplay
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register receiver
+	graph.RegisterUnit(reflect.TypeOf(&receiver{}), reflect.TypeOf((*gopi.AirPlayReceiver)(nil)))
+}
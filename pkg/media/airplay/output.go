@@ -0,0 +1,91 @@
+package airplay
+
+import (
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// output plays interleaved samples
+type output interface {
+	// Write samples, blocking while the output buffer is full
+	Write([]int16) error
+
+	// Delay returns the duration of samples written but not yet played
+	Delay() time.Duration
+
+	// Flush discards samples written but not yet played
+	Flush() error
+
+	// Close the output
+	Close() error
+}
+
+// null output discards samples, taking the time to play them
+type null struct {
+	rate, channels uint
+	end            time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	nullDevice = "null"
+	nullBuffer = 200 * time.Millisecond
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newOutput(device string, rate, channels uint) (output, error) {
+	if device == nullDevice {
+		return &null{rate: rate, channels: channels}, nil
+	} else {
+		return newDeviceOutput(device, rate, channels)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NULL OUTPUT
+
+func (this *null) Write(samples []int16) error {
+	now := time.Now()
+	if this.end.Before(now) {
+		this.end = now
+	}
+	this.end = this.end.Add(duration(uint(len(samples)), this.rate, this.channels))
+	if wait := this.end.Sub(now) - nullBuffer; wait > 0 {
+		time.Sleep(wait)
+	}
+	return nil
+}
+
+func (this *null) Delay() time.Duration {
+	if delay := time.Until(this.end); delay > 0 {
+		return delay
+	} else {
+		return 0
+	}
+}
+
+func (this *null) Flush() error {
+	this.end = time.Time{}
+	return nil
+}
+
+func (this *null) Close() error {
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// duration returns the time to play a number of interleaved samples
+func duration(samples, rate, channels uint) time.Duration {
+	if rate == 0 || channels == 0 {
+		return 0
+	}
+	return time.Duration(samples/channels) * time.Second / time.Duration(rate)
+}
@@ -0,0 +1,68 @@
+// +build linux
+
+package airplay
+
+import (
+	"os"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	linux "github.com/djthorpe/gopi/v3/pkg/sys/linux"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// alsa output writes samples to an ALSA device
+type alsa struct {
+	fh             *os.File
+	rate, channels uint
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newDeviceOutput(device string, rate, channels uint) (output, error) {
+	card, dev, err := linux.PCMParseDevice(device)
+	if err != nil {
+		return nil, err
+	}
+	fh, err := linux.PCMOpen(card, dev)
+	if err != nil {
+		return nil, err
+	}
+	if err := linux.PCMSetParams(fh.Fd(), rate, channels); err != nil {
+		fh.Close()
+		return nil, gopi.ErrNotImplemented.WithPrefix(device, ": ", err)
+	}
+	return &alsa{fh, rate, channels}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *alsa) Write(samples []int16) error {
+	return linux.PCMWrite(this.fh.Fd(), samples, this.channels)
+}
+
+func (this *alsa) Delay() time.Duration {
+	// The delay is not available when the device is not running
+	if frames, err := linux.PCMDelay(this.fh.Fd()); err != nil || frames < 0 {
+		return 0
+	} else {
+		return duration(uint(frames)*this.channels, this.rate, this.channels)
+	}
+}
+
+func (this *alsa) Flush() error {
+	if err := linux.PCMDrop(this.fh.Fd()); err != nil {
+		return err
+	} else {
+		return linux.PCMPrepare(this.fh.Fd())
+	}
+}
+
+func (this *alsa) Close() error {
+	linux.PCMDrop(this.fh.Fd())
+	return this.fh.Close()
+}
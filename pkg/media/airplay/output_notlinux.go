@@ -0,0 +1,14 @@
+// +build !linux
+
+package airplay
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newDeviceOutput(device string, rate, channels uint) (output, error) {
+	return nil, gopi.ErrNotImplemented.WithPrefix(device)
+}
@@ -0,0 +1,232 @@
+package airplay
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// player writes buffered packets to the output at the time the sender
+// intends them to be played. The time is determined from sync packets,
+// which relate a timestamp to the sender clock
+type player struct {
+	sync.Mutex
+
+	out            output
+	clock          *clock
+	rate, channels uint
+	frames         uint
+	offset         time.Duration
+	buffer         *buffer
+	gain           float32
+	synced         bool
+	syncTs         uint32
+	syncTime       time.Time
+	flush          bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Interval to wait when there is nothing to play
+	idleWait = 10 * time.Millisecond
+
+	// Maximum silence written ahead of the first packet
+	maxLead = 200 * time.Millisecond
+
+	// Time before a missing packet is due that it is replaced with
+	// silence
+	missingWait = 20 * time.Millisecond
+
+	// When the output is out of sync by more than syncTolerance, a
+	// frame is added or removed from each packet. When it is out of
+	// sync by more than resyncThreshold, silence is added or audio
+	// dropped to correct immediately
+	syncTolerance   = 2 * time.Millisecond
+	resyncThreshold = 50 * time.Millisecond
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newPlayer(out output, clock *clock, f *format, offset time.Duration, gain float32) *player {
+	this := new(player)
+	this.out = out
+	this.clock = clock
+	this.rate, this.channels, this.frames = f.Rate, f.Channels, f.Frames
+	this.offset = offset
+	this.buffer = newBuffer()
+	this.gain = gain
+	return this
+}
+
+func (this *player) Close() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.out.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Put a packet in the buffer, returning the first sequence number and
+// count of any packets which are missing
+func (this *player) Put(p *packet) (uint16, uint16) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.buffer.Put(p)
+}
+
+// Sync relates a timestamp to the time on the sender clock at which
+// it should be played
+func (this *player) Sync(ts uint32, t time.Time) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.synced, this.syncTs, this.syncTime = true, ts, t
+}
+
+// Flush discards buffered packets and output
+func (this *player) Flush() {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.buffer.Reset()
+	this.flush = true
+}
+
+// SetGain sets the gain between 0.0 and 1.0
+func (this *player) SetGain(gain float32) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.gain = gain
+}
+
+// Run writes packets to the output until the context is cancelled
+func (this *player) Run(ctx context.Context) error {
+	for {
+		samples, wait, err := this.next()
+		if err != nil {
+			return err
+		} else if len(samples) > 0 {
+			if err := this.out.Write(samples); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// next returns samples to write, or the time to wait before there
+// are samples to write
+func (this *player) next() ([]int16, time.Duration, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Discard output after a flush
+	if this.flush {
+		this.flush = false
+		if err := this.out.Flush(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// Wait for packets and sync
+	_, ts, started := this.buffer.Next()
+	if started == false || this.synced == false {
+		return nil, idleWait, nil
+	}
+
+	// Determine when the next packet is due, relative to the end of
+	// the output already written
+	due := this.playTime(ts).Sub(time.Now().Add(this.out.Delay()))
+
+	// Wait until the packet is nearly due
+	if due > maxLead {
+		return nil, due - maxLead, nil
+	}
+
+	// When a packet is missing, wait until it is due and then play
+	// silence
+	p := this.buffer.Peek()
+	if p == nil {
+		if due > missingWait {
+			return nil, idleWait, nil
+		}
+		this.buffer.Advance(uint32(this.frames))
+		if due < -this.frameDuration(this.frames) {
+			return nil, 0, nil
+		} else {
+			return make([]int16, this.frames*this.channels), 0, nil
+		}
+	}
+
+	// Correct synchronization and return samples with gain applied
+	samples := p.Samples
+	frames := uint(len(samples)) / this.channels
+	this.buffer.Advance(uint32(frames))
+	return this.scale(this.correct(samples, due)), 0, nil
+}
+
+// correct adds or removes frames at the start of a packet which is
+// early or late
+func (this *player) correct(samples []int16, due time.Duration) []int16 {
+	frames := uint(len(samples)) / this.channels
+	switch {
+	case due > resyncThreshold:
+		// Add silence before the packet
+		silence := make([]int16, this.framesFor(due)*this.channels)
+		return append(silence, samples...)
+	case due < -resyncThreshold:
+		// Drop frames from the packet
+		if drop := this.framesFor(-due); drop >= frames {
+			return nil
+		} else {
+			return samples[drop*this.channels:]
+		}
+	case due > syncTolerance && frames > 0:
+		// Repeat the first frame
+		return append(samples[:this.channels:this.channels], samples...)
+	case due < -syncTolerance && frames > 1:
+		// Drop the first frame
+		return samples[this.channels:]
+	default:
+		return samples
+	}
+}
+
+// scale returns samples with gain applied
+func (this *player) scale(samples []int16) []int16 {
+	if this.gain >= 1 {
+		return samples
+	}
+	result := make([]int16, len(samples))
+	for i, sample := range samples {
+		result[i] = int16(float32(sample) * this.gain)
+	}
+	return result
+}
+
+// playTime returns the local time at which a timestamp should be
+// output, which is earlier than it should be heard by the offset
+func (this *player) playTime(ts uint32) time.Time {
+	frames := time.Duration(int32(ts - this.syncTs))
+	return this.clock.Local(this.syncTime).Add(frames * time.Second / time.Duration(this.rate)).Add(-this.offset)
+}
+
+func (this *player) frameDuration(frames uint) time.Duration {
+	return time.Duration(frames) * time.Second / time.Duration(this.rate)
+}
+
+func (this *player) framesFor(d time.Duration) uint {
+	return uint(d * time.Duration(this.rate) / time.Second)
+}
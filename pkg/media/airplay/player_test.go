@@ -0,0 +1,94 @@
+package airplay
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// recorder is an output which records samples written
+type recorder struct {
+	sync.Mutex
+	samples []int16
+}
+
+func (this *recorder) Write(samples []int16) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.samples = append(this.samples, samples...)
+	return nil
+}
+
+func (this *recorder) Delay() time.Duration { return 0 }
+func (this *recorder) Flush() error         { return nil }
+func (this *recorder) Close() error         { return nil }
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Player_001(t *testing.T) {
+	f := &format{Codec: codecPCM, Rate: 44100, Channels: 2, Frames: 4}
+	p := newPlayer(nil, new(clock), f, 0, 1)
+	samples := []int16{1, 1, 2, 2, 3, 3, 4, 4}
+
+	// In sync
+	if result := p.correct(samples, 0); len(result) != 8 {
+		t.Error("Unexpected samples", result)
+	}
+	// Slightly early, so a frame is repeated
+	if result := p.correct(samples, 10*time.Millisecond); len(result) != 10 || result[0] != 1 || result[2] != 1 {
+		t.Error("Unexpected samples", result)
+	}
+	// Slightly late, so a frame is dropped
+	if result := p.correct(samples, -10*time.Millisecond); len(result) != 6 || result[0] != 2 {
+		t.Error("Unexpected samples", result)
+	}
+	// Very early, so silence is added
+	if result := p.correct(samples, 100*time.Millisecond); len(result) != 4410*2+8 || result[0] != 0 {
+		t.Error("Unexpected samples", len(result))
+	}
+	// Very late, so the packet is dropped
+	if result := p.correct(samples, -100*time.Millisecond); len(result) != 0 {
+		t.Error("Unexpected samples", result)
+	}
+}
+
+func Test_Player_002(t *testing.T) {
+	out := new(recorder)
+	f := &format{Codec: codecPCM, Rate: 44100, Channels: 2, Frames: 352}
+	p := newPlayer(out, new(clock), f, 0, 0.5)
+
+	// Timestamp zero should be played now
+	p.Sync(0, time.Now())
+	for i := uint16(0); i < 4; i++ {
+		samples := make([]int16, 352*2)
+		for j := range samples {
+			samples[j] = 1000
+		}
+		p.Put(&packet{Seq: i, Ts: uint32(i) * 352, Samples: samples})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := p.Run(ctx); err != nil {
+		t.Error(err)
+	}
+
+	// Packets are late, so some frames are dropped, and then silence is
+	// played for missing packets
+	out.Mutex.Lock()
+	defer out.Mutex.Unlock()
+	if len(out.samples) == 0 {
+		t.Error("No samples written")
+	}
+	for _, sample := range out.samples {
+		if sample != 500 && sample != 0 {
+			t.Error("Unexpected sample", sample)
+			break
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package airplay
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type receiver struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.ServiceDiscovery
+	gopi.AudioDAC
+	sync.Mutex
+	sync.WaitGroup
+
+	name     *string
+	port     *uint
+	device   *string
+	offset   *time.Duration
+	key      *rsa.PrivateKey
+	mac      net.HardwareAddr
+	listener net.Listener
+	session  *session // Active session
+	db       float32  // Volume in decibels
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	mdnsService = "_raop._tcp"
+	minVolume   = -30.0
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *receiver) Define(cfg gopi.Config) error {
+	this.name = cfg.FlagString("airplay.name", "", "Speaker name, defaults to hostname")
+	this.port = cfg.FlagUint("airplay.port", 5000, "Port for RTSP requests")
+	this.device = cfg.FlagString("airplay.device", "", "ALSA output device, or \"null\"")
+	this.offset = cfg.FlagDuration("airplay.offset", 0, "Latency after the output device")
+	cfg.FlagString("airplay.key", "", "Path to RSA private key for encrypted streams")
+	return nil
+}
+
+func (this *receiver) New(cfg gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Set name from hostname
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	if *this.name = strings.TrimSpace(*this.name); *this.name == "" {
+		*this.name = hostname
+	}
+
+	// Set device from detected DAC
+	if *this.device == "" && this.AudioDAC != nil {
+		*this.device = this.AudioDAC.Device()
+	}
+	if *this.device == "" {
+		*this.device = "hw:0"
+	}
+
+	// Read private key
+	if path := cfg.GetString("airplay.key"); path != "" {
+		if key, err := readKey(path); err != nil {
+			return err
+		} else {
+			this.key = key
+		}
+	}
+
+	// Set hardware address, which identifies the speaker
+	this.mac = hardwareAddr(hostname)
+
+	// Listen for requests
+	if listener, err := net.Listen("tcp", fmt.Sprint(":", *this.port)); err != nil {
+		return err
+	} else {
+		this.listener = listener
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *receiver) Run(ctx context.Context) error {
+	// Accept connections from senders
+	this.WaitGroup.Add(1)
+	go func() {
+		defer this.WaitGroup.Done()
+		this.accept()
+	}()
+
+	// Register with mDNS when service discovery is available
+	if this.ServiceDiscovery != nil {
+		if record, err := this.ServiceDiscovery.NewServiceRecord(mdnsService, this.instance(), uint16(this.Port()), this.txt(), gopi.SERVICE_FLAG_IP4); err != nil {
+			this.Print("AirPlay: mDNS: ", err)
+		} else {
+			this.WaitGroup.Add(1)
+			go func() {
+				defer this.WaitGroup.Done()
+				if err := this.ServiceDiscovery.Serve(ctx, []gopi.ServiceRecord{record}); err != nil {
+					this.Print("AirPlay: mDNS: ", err)
+				}
+			}()
+		}
+	}
+
+	// Wait for end of run, then disconnect
+	<-ctx.Done()
+	this.listener.Close()
+	if err := this.Disconnect(); err != nil && errors.Is(err, gopi.ErrNotFound) == false {
+		this.Print("AirPlay: ", err)
+	}
+	this.WaitGroup.Wait()
+
+	// Return success
+	return nil
+}
+
+func (this *receiver) Dispose() error {
+	// Close listener, which may not have been served
+	this.listener.Close()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *receiver) Name() string {
+	return *this.name
+}
+
+func (this *receiver) Port() uint {
+	return uint(this.listener.Addr().(*net.TCPAddr).Port)
+}
+
+func (this *receiver) Sender() string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.session == nil {
+		return ""
+	} else {
+		return this.session.sender
+	}
+}
+
+func (this *receiver) Volume() float32 {
+	db := this.decibels()
+	if db < minVolume {
+		return 0
+	} else {
+		return (db - minVolume) / -minVolume
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *receiver) Disconnect() error {
+	this.Mutex.Lock()
+	session := this.session
+	this.Mutex.Unlock()
+
+	if session == nil {
+		return gopi.ErrNotFound.WithPrefix("Disconnect")
+	} else {
+		return session.Close()
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *receiver) String() string {
+	str := "<airplay"
+	str += fmt.Sprintf(" name=%q", *this.name)
+	str += " port=" + fmt.Sprint(this.Port())
+	str += fmt.Sprintf(" device=%q", *this.device)
+	if *this.offset != 0 {
+		str += " offset=" + fmt.Sprint(*this.offset)
+	}
+	if sender := this.Sender(); sender != "" {
+		str += fmt.Sprintf(" sender=%q", sender)
+	}
+	str += fmt.Sprintf(" volume=%.2f", this.Volume())
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// accept connections until the listener is closed, serving each
+// connection as a session
+func (this *receiver) accept() {
+	for {
+		conn, err := this.listener.Accept()
+		if err != nil {
+			return
+		}
+		this.WaitGroup.Add(1)
+		go func(session *session) {
+			defer this.WaitGroup.Done()
+			this.Debug("AirPlay: connected ", session)
+			if err := session.Serve(); err != nil {
+				this.Debug("AirPlay: ", err)
+			}
+			session.Close()
+			this.deactivate(session)
+		}(newSession(this, conn))
+	}
+}
+
+// activate makes a session the active session, disconnecting any
+// other sender
+func (this *receiver) activate(session *session) {
+	this.Mutex.Lock()
+	prev := this.session
+	this.session = session
+	this.Mutex.Unlock()
+
+	if prev != nil && prev != session {
+		prev.Close()
+	}
+	this.emit(gopi.AIRPLAY_STATE_CONNECTED, session.sender)
+}
+
+// deactivate a session when it ends
+func (this *receiver) deactivate(session *session) {
+	this.Mutex.Lock()
+	active := this.session == session
+	if active {
+		this.session = nil
+	}
+	this.Mutex.Unlock()
+
+	if active {
+		this.emit(gopi.AIRPLAY_STATE_DISCONNECTED, session.sender)
+	}
+}
+
+func (this *receiver) setVolume(db float32) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.db = db
+}
+
+func (this *receiver) decibels() float32 {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.db
+}
+
+func (this *receiver) gain() float32 {
+	return volumeGain(this.decibels())
+}
+
+func (this *receiver) emit(state gopi.AirPlayState, sender string) {
+	if this.Publisher == nil {
+		return
+	} else if err := this.Publisher.Emit(NewEvent(state, sender, this.Volume()), false); err != nil {
+		this.Debug("AirPlay: ", err)
+	}
+}
+
+// instance returns the service instance name, which is the hardware
+// address and name of the speaker
+func (this *receiver) instance() string {
+	return strings.ToUpper(strings.Replace(this.mac.String(), ":", "", -1)) + "@" + *this.name
+}
+
+// txt returns the service records which describe the capabilities of
+// the speaker
+func (this *receiver) txt() []string {
+	cn, et := "0", "0"
+	if alacSupported {
+		cn = "0,1"
+	}
+	if this.key != nil {
+		et = "0,1"
+	}
+	return []string{
+		"txtvers=1", "ch=2", "cn=" + cn, "et=" + et, "sv=false", "da=true",
+		"sr=44100", "ss=16", "pw=false", "vn=3", "tp=UDP", "vs=130.14", "am=gopi",
+	}
+}
+
+// hardwareAddr returns the address of the first interface with a
+// hardware address, or an address derived from the hostname
+func hardwareAddr(hostname string) net.HardwareAddr {
+	if ifaces, err := net.Interfaces(); err == nil {
+		for _, iface := range ifaces {
+			if iface.Flags&net.FlagLoopback == 0 && len(iface.HardwareAddr) == 6 {
+				return iface.HardwareAddr
+			}
+		}
+	}
+	// Use a locally administered address
+	h := md5.Sum([]byte(hostname))
+	h[0] = (h[0] | 0x02) & 0xFE
+	return net.HardwareAddr(h[:6])
+}
@@ -0,0 +1,237 @@
+package airplay_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/media/airplay"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.AirPlayReceiver
+}
+
+// client sends RTSP requests to the receiver
+type client struct {
+	conn   net.Conn
+	reader *textproto.Reader
+	cseq   int
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Receiver_001(t *testing.T) {
+	port := freePort(t)
+	tool.Test(t, []string{"-airplay.port=" + fmt.Sprint(port), "-airplay.name=test", "-airplay.device=null"}, new(App), func(app *App) {
+		if app.AirPlayReceiver == nil {
+			t.Error("nil AirPlayReceiver unit")
+		} else if app.AirPlayReceiver.Name() != "test" {
+			t.Error("Unexpected name", app.AirPlayReceiver.Name())
+		} else if app.AirPlayReceiver.Sender() != "" {
+			t.Error("Unexpected sender", app.AirPlayReceiver.Sender())
+		} else if err := app.AirPlayReceiver.Disconnect(); err == nil {
+			t.Error("Expected error from Disconnect")
+		} else {
+			t.Log(app.AirPlayReceiver)
+		}
+	})
+}
+
+func Test_Receiver_002(t *testing.T) {
+	port := freePort(t)
+	tool.Test(t, []string{"-airplay.port=" + fmt.Sprint(port), "-airplay.device=null"}, new(App), func(app *App) {
+		c, err := dial(port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer c.conn.Close()
+
+		// Sender control and timing ports
+		control, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer control.Close()
+		timing, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer timing.Close()
+
+		// Negotiate the stream
+		if code, header, _ := c.Do("OPTIONS", nil, ""); code != 200 {
+			t.Error("OPTIONS: unexpected code", code)
+		} else if strings.Contains(header.Get("Public"), "ANNOUNCE") == false {
+			t.Error("OPTIONS: unexpected header", header)
+		}
+		if code, _, _ := c.Do("SETUP", nil, ""); code != 455 {
+			t.Error("SETUP: unexpected code", code)
+		}
+		if code, _, _ := c.Do("ANNOUNCE", map[string]string{"Content-Type": "application/sdp"}, "a=rtpmap:96 mpeg4-generic/44100/2\r\n"); code != 415 {
+			t.Error("ANNOUNCE: unexpected code", code)
+		}
+		if code, _, _ := c.Do("ANNOUNCE", map[string]string{"Content-Type": "application/sdp"}, "v=0\r\nm=audio 0 RTP/AVP 96\r\na=rtpmap:96 L16/44100/2\r\n"); code != 200 {
+			t.Error("ANNOUNCE: unexpected code", code)
+		}
+		transport := fmt.Sprintf("RTP/AVP/UDP;unicast;interleaved=0-1;mode=record;control_port=%d;timing_port=%d", udpPort(control), udpPort(timing))
+		code, header, _ := c.Do("SETUP", map[string]string{"Transport": transport}, "")
+		if code != 200 {
+			t.Error("SETUP: unexpected code", code)
+			return
+		}
+		server := 0
+		for _, param := range strings.Split(header.Get("Transport"), ";") {
+			if strings.HasPrefix(param, "server_port=") {
+				server, _ = strconv.Atoi(strings.TrimPrefix(param, "server_port="))
+			}
+		}
+		if server == 0 {
+			t.Error("SETUP: unexpected header", header)
+			return
+		}
+		if code, _, _ := c.Do("RECORD", map[string]string{"RTP-Info": "seq=0;rtptime=0"}, ""); code != 200 {
+			t.Error("RECORD: unexpected code", code)
+		}
+		if sender := app.AirPlayReceiver.Sender(); sender != "127.0.0.1" {
+			t.Error("Unexpected sender", sender)
+		}
+
+		// The receiver should send a timing request
+		buf := make([]byte, 64)
+		timing.SetReadDeadline(time.Now().Add(time.Second))
+		if n, _, err := timing.ReadFromUDP(buf); err != nil {
+			t.Error(err)
+		} else if n != 32 || buf[1] != 0xD2 {
+			t.Error("Unexpected timing request", buf[:n])
+		}
+
+		// Send packets 0 and 2, so that the receiver requests packet 1
+		audio := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: server}
+		for _, seq := range []byte{0, 2} {
+			packet := make([]byte, 12+352*4)
+			packet[0], packet[1], packet[3] = 0x80, 0x60, seq
+			control.WriteToUDP(packet, audio)
+		}
+		control.SetReadDeadline(time.Now().Add(time.Second))
+		if n, _, err := control.ReadFromUDP(buf); err != nil {
+			t.Error(err)
+		} else if n != 8 || buf[1] != 0xD5 || buf[5] != 1 || buf[7] != 1 {
+			t.Error("Unexpected resend request", buf[:n])
+		}
+
+		// Set and get volume
+		if code, _, _ := c.Do("SET_PARAMETER", map[string]string{"Content-Type": "text/parameters"}, "volume: -15.000000\r\n"); code != 200 {
+			t.Error("SET_PARAMETER: unexpected code", code)
+		} else if volume := app.AirPlayReceiver.Volume(); volume != 0.5 {
+			t.Error("Unexpected volume", volume)
+		}
+		if code, _, body := c.Do("GET_PARAMETER", map[string]string{"Content-Type": "text/parameters"}, "volume\r\n"); code != 200 {
+			t.Error("GET_PARAMETER: unexpected code", code)
+		} else if strings.HasPrefix(body, "volume: -15.0") == false {
+			t.Error("GET_PARAMETER: unexpected body", body)
+		}
+
+		// End the stream
+		if code, _, _ := c.Do("FLUSH", map[string]string{"RTP-Info": "seq=3;rtptime=1056"}, ""); code != 200 {
+			t.Error("FLUSH: unexpected code", code)
+		}
+		if code, _, _ := c.Do("TEARDOWN", nil, ""); code != 200 {
+			t.Error("TEARDOWN: unexpected code", code)
+		}
+		if err := app.AirPlayReceiver.Disconnect(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CLIENT
+
+func dial(port int) (*client, error) {
+	if conn, err := net.Dial("tcp4", fmt.Sprint("127.0.0.1:", port)); err != nil {
+		return nil, err
+	} else {
+		return &client{conn, textproto.NewReader(bufio.NewReader(conn)), 0}, nil
+	}
+}
+
+// Do sends a request and returns the response code, header and body
+func (this *client) Do(method string, header map[string]string, body string) (int, textproto.MIMEHeader, string) {
+	this.cseq++
+	this.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	req := method + " rtsp://127.0.0.1/1 RTSP/1.0\r\nCSeq: " + fmt.Sprint(this.cseq) + "\r\n"
+	for key, value := range header {
+		req += key + ": " + value + "\r\n"
+	}
+	if body != "" {
+		req += "Content-Length: " + fmt.Sprint(len(body)) + "\r\n"
+	}
+	if _, err := this.conn.Write([]byte(req + "\r\n" + body)); err != nil {
+		return 0, nil, ""
+	}
+
+	// Read response
+	line, err := this.reader.ReadLine()
+	if err != nil {
+		return 0, nil, ""
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 2 || fields[0] != "RTSP/1.0" {
+		return 0, nil, ""
+	}
+	code, _ := strconv.Atoi(fields[1])
+	resp, err := this.reader.ReadMIMEHeader()
+	if err != nil || resp.Get("CSeq") != fmt.Sprint(this.cseq) {
+		return 0, nil, ""
+	}
+	data := make([]byte, 0)
+	if length, _ := strconv.Atoi(resp.Get("Content-Length")); length > 0 {
+		data = make([]byte, length)
+		if _, err := io.ReadFull(this.reader.R, data); err != nil {
+			return 0, nil, ""
+		}
+	}
+	return code, resp, string(data)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	if listener, err := net.Listen("tcp4", "127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+		return 0
+	} else {
+		defer listener.Close()
+		return listener.Addr().(*net.TCPAddr).Port
+	}
+}
+
+func udpPort(conn *net.UDPConn) int {
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
@@ -0,0 +1,173 @@
+package airplay
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Payload types for packets
+	payloadAudio          = 0x60
+	payloadSync           = 0x54
+	payloadResendRequest  = 0x55
+	payloadResend         = 0x56
+	payloadTimingRequest  = 0x52
+	payloadTimingResponse = 0x53
+)
+
+const (
+	rtpHeaderSize    = 12
+	timingPacketSize = 32
+	timingInterval   = 3 * time.Second
+	maxResend        = 64
+	maxPacketSize    = 2048
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// RECEIVE PACKETS
+
+// receive packets on a connection until it is closed, calling a
+// function for each packet
+func (this *session) receive(conn *net.UDPConn, fn func([]byte, *net.UDPAddr)) {
+	this.WaitGroup.Add(1)
+	go func() {
+		defer this.WaitGroup.Done()
+		buf := make([]byte, maxPacketSize)
+		for {
+			if n, addr, err := conn.ReadFromUDP(buf); err != nil {
+				return
+			} else if n >= 4 {
+				fn(buf[:n], addr)
+			}
+		}
+	}()
+}
+
+// audioPacket decrypts and decodes audio, and adds it to the buffer
+func (this *session) audioPacket(data []byte, _ *net.UDPAddr) {
+	if len(data) < rtpHeaderSize || data[1]&0x7F != payloadAudio {
+		return
+	}
+	if req := this.decodePacket(data); req != nil {
+		this.send("control_port", req)
+	}
+}
+
+// decodePacket buffers the packet and returns a request for any
+// missing packets
+func (this *session) decodePacket(data []byte) []byte {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.player == nil || this.decoder == nil {
+		return nil
+	}
+
+	// Decrypt and decode payload
+	payload := data[rtpHeaderSize:]
+	if this.decrypt != nil {
+		this.decrypt(payload)
+	}
+	samples, err := this.decoder.Decode(payload)
+	if err != nil {
+		this.Debug("AirPlay: ", err)
+		return nil
+	}
+
+	// Buffer packet and request any missing packets
+	seq, ts := binary.BigEndian.Uint16(data[2:]), binary.BigEndian.Uint32(data[4:])
+	if first, count := this.player.Put(&packet{seq, ts, samples}); count > 0 && count <= maxResend {
+		this.resendSeq++
+		req := make([]byte, 8)
+		req[0], req[1] = 0x80, 0x80|payloadResendRequest
+		binary.BigEndian.PutUint16(req[2:], this.resendSeq)
+		binary.BigEndian.PutUint16(req[4:], first)
+		binary.BigEndian.PutUint16(req[6:], count)
+		return req
+	}
+
+	// No missing packets
+	return nil
+}
+
+// controlPacket handles sync packets, which relate timestamps to the
+// sender clock, and packets sent again
+func (this *session) controlPacket(data []byte, addr *net.UDPAddr) {
+	switch data[1] & 0x7F {
+	case payloadSync:
+		if len(data) < 20 {
+			return
+		}
+		this.Mutex.Lock()
+		defer this.Mutex.Unlock()
+		if this.player != nil {
+			this.player.Sync(binary.BigEndian.Uint32(data[4:]), getNTP(data[8:]))
+		}
+	case payloadResend:
+		this.audioPacket(data[4:], addr)
+	}
+}
+
+// timingPacket handles responses to timing requests, and replies to
+// requests from the sender
+func (this *session) timingPacket(data []byte, addr *net.UDPAddr) {
+	now := time.Now()
+	if len(data) < timingPacketSize {
+		return
+	}
+	switch data[1] & 0x7F {
+	case payloadTimingResponse:
+		this.clock.Update(getNTP(data[8:]), getNTP(data[16:]), getNTP(data[24:]), now)
+	case payloadTimingRequest:
+		reply := make([]byte, timingPacketSize)
+		reply[0], reply[1], reply[3] = 0x80, 0x80|payloadTimingResponse, 0x07
+		copy(reply[8:16], data[24:32])
+		putNTP(reply[16:], now)
+		putNTP(reply[24:], time.Now())
+		this.send("timing_port", reply)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SEND PACKETS
+
+// requestTiming sends timing requests to the sender until the context
+// is cancelled
+func (this *session) requestTiming(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			req := make([]byte, timingPacketSize)
+			req[0], req[1], req[3] = 0x80, 0x80|payloadTimingRequest, 0x07
+			putNTP(req[24:], time.Now())
+			this.send("timing_port", req)
+			timer.Reset(timingInterval)
+		}
+	}
+}
+
+// send a packet to a sender port, from the socket for that port
+func (this *session) send(port string, data []byte) {
+	var conn *net.UDPConn
+	this.Mutex.Lock()
+	addr := this.remote[port]
+	if port == "timing_port" {
+		conn = this.timing
+	} else {
+		conn = this.control
+	}
+	this.Mutex.Unlock()
+	if addr == nil || conn == nil {
+		return
+	} else if _, err := conn.WriteToUDP(data, addr); err != nil {
+		this.Debug("AirPlay: ", err)
+	}
+}
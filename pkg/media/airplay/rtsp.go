@@ -0,0 +1,161 @@
+package airplay
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// request is an RTSP request, which is similar to an HTTP request
+// but uses a different protocol version
+type request struct {
+	Method string
+	URI    string
+	Header textproto.MIMEHeader
+	Body   []byte
+}
+
+// response is an RTSP response
+type response struct {
+	Code   int
+	Header textproto.MIMEHeader
+	Body   []byte
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	rtspProtocol   = "RTSP/1.0"
+	rtspServer     = "AirTunes/105.1"
+	maxRequestBody = 1024 * 1024
+)
+
+var (
+	rtspStatus = map[int]string{
+		200: "OK",
+		400: "Bad Request",
+		404: "Not Found",
+		415: "Unsupported Media Type",
+		451: "Parameter Not Understood",
+		453: "Not Enough Bandwidth",
+		454: "Session Not Found",
+		455: "Method Not Valid in This State",
+		500: "Internal Server Error",
+		501: "Not Implemented",
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newResponse(code int) *response {
+	return &response{code, make(textproto.MIMEHeader), nil}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// readRequest reads a request line, headers and body
+func readRequest(r *bufio.Reader) (*request, error) {
+	reader := textproto.NewReader(r)
+	line, err := reader.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(line)
+	if len(fields) != 3 || fields[2] != rtspProtocol {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("readRequest: ", strconv.Quote(line))
+	}
+	header, err := reader.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	// Read body
+	this := &request{fields[0], fields[1], header, nil}
+	if value := header.Get("Content-Length"); value != "" {
+		if length, err := strconv.ParseUint(value, 10, 32); err != nil || length > maxRequestBody {
+			return nil, gopi.ErrUnexpectedResponse.WithPrefix("readRequest: Content-Length ", value)
+		} else {
+			this.Body = make([]byte, length)
+		}
+		if _, err := io.ReadFull(r, this.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	// Return success
+	return this, nil
+}
+
+// Write the response, with the sequence number of a request
+func (this *response) Write(w io.Writer, cseq string) error {
+	status, exists := rtspStatus[this.Code]
+	if exists == false {
+		status = "Unknown"
+	}
+	str := fmt.Sprintf("%v %03d %v\r\n", rtspProtocol, this.Code, status)
+	str += "CSeq: " + cseq + "\r\n"
+	str += "Server: " + rtspServer + "\r\n"
+	for key, values := range this.Header {
+		for _, value := range values {
+			str += key + ": " + value + "\r\n"
+		}
+	}
+	if len(this.Body) > 0 {
+		str += "Content-Length: " + fmt.Sprint(len(this.Body)) + "\r\n"
+	}
+	str += "\r\n"
+	if _, err := io.WriteString(w, str); err != nil {
+		return err
+	} else if _, err := w.Write(this.Body); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *request) String() string {
+	str := "<airplay.request"
+	str += " method=" + this.Method
+	str += fmt.Sprintf(" uri=%q", this.URI)
+	if cseq := this.Header.Get("CSeq"); cseq != "" {
+		str += " cseq=" + cseq
+	}
+	if len(this.Body) > 0 {
+		str += " body_length=" + fmt.Sprint(len(this.Body))
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// parseParams returns the parameters of a header value such as
+// "RTP/AVP/UDP;unicast;control_port=6001"
+func parseParams(value string) map[string]string {
+	params := make(map[string]string)
+	for _, param := range strings.Split(value, ";") {
+		if param = strings.TrimSpace(param); param == "" {
+			continue
+		} else if kv := strings.SplitN(param, "=", 2); len(kv) == 2 {
+			params[kv[0]] = kv[1]
+		} else {
+			params[kv[0]] = ""
+		}
+	}
+	return params
+}
@@ -0,0 +1,207 @@
+package airplay
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// format is the audio format announced by the sender
+type format struct {
+	Codec    string
+	Rate     uint
+	Channels uint
+	Frames   uint   // Frames in each packet
+	Params   []uint // ALAC parameters from the fmtp attribute
+	Key, IV  []byte // RSA encrypted AES key and initialization vector
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	codecALAC = "AppleLossless"
+	codecPCM  = "L16"
+)
+
+const (
+	// Number of parameters in the ALAC fmtp attribute, and the size of
+	// the ALAC "magic cookie" which contains them
+	alacParams     = 12
+	alacCookieSize = 36
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// parseSDP returns the audio format from an ANNOUNCE request body
+func parseSDP(body string) (*format, error) {
+	this := new(format)
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "a=") == false {
+			continue
+		}
+		kv := strings.SplitN(strings.TrimPrefix(line, "a="), ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "rtpmap":
+			if err := this.parseRtpmap(kv[1]); err != nil {
+				return nil, err
+			}
+		case "fmtp":
+			if err := this.parseFmtp(kv[1]); err != nil {
+				return nil, err
+			}
+		case "rsaaeskey":
+			if key, err := decodeBase64(kv[1]); err != nil {
+				return nil, gopi.ErrBadParameter.WithPrefix("rsaaeskey")
+			} else {
+				this.Key = key
+			}
+		case "aesiv":
+			if iv, err := decodeBase64(kv[1]); err != nil || len(iv) != 16 {
+				return nil, gopi.ErrBadParameter.WithPrefix("aesiv")
+			} else {
+				this.IV = iv
+			}
+		}
+	}
+
+	// Check for codec and encryption parameters
+	if this.Codec == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("Missing rtpmap")
+	} else if this.Codec == codecALAC && len(this.Params) != alacParams {
+		return nil, gopi.ErrBadParameter.WithPrefix("Missing fmtp")
+	} else if (this.Key == nil) != (this.IV == nil) {
+		return nil, gopi.ErrBadParameter.WithPrefix("Missing rsaaeskey or aesiv")
+	}
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+// Encrypted returns true if packets are encrypted
+func (this *format) Encrypted() bool {
+	return this.Key != nil
+}
+
+// Cookie returns the ALAC "magic cookie" expected by the decoder,
+// which is an atom containing the fmtp parameters
+func (this *format) Cookie() []byte {
+	if len(this.Params) != alacParams {
+		return nil
+	}
+	cookie := make([]byte, alacCookieSize)
+	binary.BigEndian.PutUint32(cookie[0:], alacCookieSize)
+	copy(cookie[4:], "alac")
+	binary.BigEndian.PutUint32(cookie[12:], uint32(this.Params[1]))
+	cookie[16] = byte(this.Params[2])
+	cookie[17] = byte(this.Params[3])
+	cookie[18] = byte(this.Params[4])
+	cookie[19] = byte(this.Params[5])
+	cookie[20] = byte(this.Params[6])
+	cookie[21] = byte(this.Params[7])
+	binary.BigEndian.PutUint16(cookie[22:], uint16(this.Params[8]))
+	binary.BigEndian.PutUint32(cookie[24:], uint32(this.Params[9]))
+	binary.BigEndian.PutUint32(cookie[28:], uint32(this.Params[10]))
+	binary.BigEndian.PutUint32(cookie[32:], uint32(this.Params[11]))
+	return cookie
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *format) String() string {
+	str := "<airplay.format"
+	str += " codec=" + this.Codec
+	str += " rate=" + fmt.Sprint(this.Rate)
+	str += " channels=" + fmt.Sprint(this.Channels)
+	if this.Frames > 0 {
+		str += " frames=" + fmt.Sprint(this.Frames)
+	}
+	if this.Encrypted() {
+		str += " encrypted=true"
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// parseRtpmap parses "96 AppleLossless" or "96 L16/44100/2"
+func (this *format) parseRtpmap(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return gopi.ErrBadParameter.WithPrefix("rtpmap: ", value)
+	}
+	codec := strings.Split(fields[1], "/")
+	switch codec[0] {
+	case codecALAC:
+		this.Codec = codecALAC
+	case codecPCM:
+		if len(codec) != 3 {
+			return gopi.ErrBadParameter.WithPrefix("rtpmap: ", value)
+		}
+		rate, err := strconv.ParseUint(codec[1], 10, 32)
+		if err != nil {
+			return gopi.ErrBadParameter.WithPrefix("rtpmap: ", value)
+		}
+		channels, err := strconv.ParseUint(codec[2], 10, 32)
+		if err != nil || channels == 0 {
+			return gopi.ErrBadParameter.WithPrefix("rtpmap: ", value)
+		}
+		this.Codec, this.Rate, this.Channels = codecPCM, uint(rate), uint(channels)
+		if this.Frames == 0 {
+			this.Frames = 352
+		}
+	default:
+		return gopi.ErrNotImplemented.WithPrefix("Codec: ", codec[0])
+	}
+
+	// Return success
+	return nil
+}
+
+// parseFmtp parses "96 352 0 16 40 10 14 2 255 0 0 44100" which are the
+// ALAC parameters
+func (this *format) parseFmtp(value string) error {
+	fields := strings.Fields(value)
+	params := make([]uint, 0, len(fields))
+	for _, field := range fields {
+		if param, err := strconv.ParseUint(field, 10, 32); err != nil {
+			return gopi.ErrBadParameter.WithPrefix("fmtp: ", value)
+		} else {
+			params = append(params, uint(param))
+		}
+	}
+	if len(params) != alacParams {
+		return gopi.ErrBadParameter.WithPrefix("fmtp: ", value)
+	} else if params[3] != 16 {
+		return gopi.ErrNotImplemented.WithPrefix("fmtp: sample size ", params[3])
+	} else if params[1] == 0 || params[7] == 0 || params[11] == 0 {
+		return gopi.ErrBadParameter.WithPrefix("fmtp: ", value)
+	}
+	this.Params = params
+	this.Frames, this.Channels, this.Rate = params[1], params[7], params[11]
+
+	// Return success
+	return nil
+}
+
+// decodeBase64 decodes with or without padding
+func decodeBase64(value string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(value), "="))
+}
@@ -0,0 +1,89 @@
+package airplay
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_SDP_001(t *testing.T) {
+	body := "v=0\r\no=iTunes 3413821438 0 IN IP4 192.168.1.2\r\ns=iTunes\r\nc=IN IP4 192.168.1.2\r\nt=0 0\r\n" +
+		"m=audio 0 RTP/AVP 96\r\na=rtpmap:96 AppleLossless\r\na=fmtp:96 352 0 16 40 10 14 2 255 0 0 44100\r\n"
+	if f, err := parseSDP(body); err != nil {
+		t.Error(err)
+	} else if f.Codec != codecALAC || f.Rate != 44100 || f.Channels != 2 || f.Frames != 352 {
+		t.Error("Unexpected format", f)
+	} else if f.Encrypted() {
+		t.Error("Unexpected encryption", f)
+	} else if cookie := f.Cookie(); len(cookie) != alacCookieSize {
+		t.Error("Unexpected cookie", cookie)
+	} else if bytes.Equal(cookie[:8], []byte{0, 0, 0, 36, 'a', 'l', 'a', 'c'}) == false {
+		t.Error("Unexpected cookie", cookie)
+	} else if bytes.Equal(cookie[12:22], []byte{0, 0, 1, 96, 0, 16, 40, 10, 14, 2}) == false {
+		t.Error("Unexpected cookie", cookie)
+	} else {
+		t.Log(f)
+	}
+}
+
+func Test_SDP_002(t *testing.T) {
+	if f, err := parseSDP("a=rtpmap:96 L16/44100/2\r\n"); err != nil {
+		t.Error(err)
+	} else if f.Codec != codecPCM || f.Rate != 44100 || f.Channels != 2 || f.Frames != 352 {
+		t.Error("Unexpected format", f)
+	} else if f.Cookie() != nil {
+		t.Error("Unexpected cookie")
+	}
+}
+
+func Test_SDP_003(t *testing.T) {
+	for _, body := range []string{
+		"",
+		"a=rtpmap:96 mpeg4-generic/44100/2\r\n",
+		"a=rtpmap:96 AppleLossless\r\n",
+		"a=rtpmap:96 AppleLossless\r\na=fmtp:96 352 0 24 40 10 14 2 255 0 0 44100\r\n",
+		"a=rtpmap:96 L16/44100/2\r\na=aesiv:AAAAAAAAAAAAAAAAAAAAAA\r\n",
+	} {
+		if _, err := parseSDP(body); err == nil {
+			t.Errorf("Expected error for %q", body)
+		}
+	}
+}
+
+func Test_SDP_004(t *testing.T) {
+	body := "a=rtpmap:96 L16/44100/2\r\na=rsaaeskey:AAAA\r\na=aesiv:AAAAAAAAAAAAAAAAAAAAAA==\r\n"
+	if f, err := parseSDP(body); err != nil {
+		t.Error(err)
+	} else if f.Encrypted() == false || len(f.IV) != 16 {
+		t.Error("Unexpected format", f)
+	} else if _, err := newCipher(nil, f); err == nil {
+		t.Error("Expected error without key")
+	}
+}
+
+func Test_NTP_001(t *testing.T) {
+	now := time.Now()
+	data := make([]byte, 8)
+	putNTP(data, now)
+	if delta := getNTP(data).Sub(now); delta > time.Microsecond || delta < -time.Microsecond {
+		t.Error("Unexpected delta", delta)
+	}
+}
+
+func Test_Clock_001(t *testing.T) {
+	var c clock
+	if c.Offset() != 0 {
+		t.Error("Unexpected offset", c.Offset())
+	}
+	// Sender clock is one second ahead, with a round trip of 10ms
+	// and then a longer round trip which is ignored
+	t0 := time.Now()
+	c.Update(t0, t0.Add(time.Second+5*time.Millisecond), t0.Add(time.Second+5*time.Millisecond), t0.Add(10*time.Millisecond))
+	c.Update(t0, t0.Add(time.Second+50*time.Millisecond), t0.Add(time.Second+50*time.Millisecond), t0.Add(60*time.Millisecond))
+	if offset := c.Offset(); offset != time.Second {
+		t.Error("Unexpected offset", offset)
+	}
+}
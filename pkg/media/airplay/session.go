@@ -0,0 +1,379 @@
+package airplay
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// session is the connection from a sender, which negotiates the stream
+// with RTSP requests and then sends audio, sync and timing packets
+// over UDP
+type session struct {
+	sync.Mutex
+	sync.WaitGroup
+	*receiver
+
+	conn      net.Conn
+	sender    string
+	once      sync.Once
+	closed    bool
+	format    *format
+	decrypt   cipherFunc
+	decoder   decoder
+	audio     *net.UDPConn
+	control   *net.UDPConn
+	timing    *net.UDPConn
+	remote    map[string]*net.UDPAddr
+	clock     clock
+	player    *player
+	ctx       context.Context
+	cancel    context.CancelFunc
+	resendSeq uint16
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	rtspMethods = "ANNOUNCE, SETUP, RECORD, PAUSE, FLUSH, TEARDOWN, OPTIONS, GET_PARAMETER, SET_PARAMETER"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newSession(receiver *receiver, conn net.Conn) *session {
+	this := new(session)
+	this.receiver = receiver
+	this.conn = conn
+	this.remote = make(map[string]*net.UDPAddr)
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		this.sender = addr.IP.String()
+	}
+	return this
+}
+
+// Close the connection and end streaming
+func (this *session) Close() error {
+	var result error
+	this.once.Do(func() {
+		this.Mutex.Lock()
+		this.closed = true
+		this.Mutex.Unlock()
+		result = this.conn.Close()
+		this.stop()
+	})
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Serve requests until the connection is closed
+func (this *session) Serve() error {
+	r := bufio.NewReader(this.conn)
+	for {
+		req, err := readRequest(r)
+		if err != nil {
+			return err
+		}
+		this.Debug("AirPlay: ", req)
+		resp := this.handle(req)
+		if err := resp.Write(this.conn, req.Header.Get("CSeq")); err != nil {
+			return err
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *session) String() string {
+	str := "<airplay.session"
+	str += fmt.Sprintf(" sender=%q", this.sender)
+	if this.format != nil {
+		str += " format=" + fmt.Sprint(this.format)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+func (this *session) handle(req *request) *response {
+	switch req.Method {
+	case "OPTIONS":
+		return this.options(req)
+	case "ANNOUNCE":
+		return this.announce(req)
+	case "SETUP":
+		return this.setup(req)
+	case "RECORD":
+		return this.record(req)
+	case "FLUSH", "PAUSE":
+		return this.flush(req)
+	case "SET_PARAMETER":
+		return this.setParameter(req)
+	case "GET_PARAMETER":
+		return this.getParameter(req)
+	case "TEARDOWN":
+		this.stop()
+		return newResponse(200)
+	default:
+		return newResponse(501)
+	}
+}
+
+func (this *session) options(req *request) *response {
+	resp := newResponse(200)
+	resp.Header.Set("Public", rtspMethods)
+
+	// Prove that the receiver holds the private key
+	if challenge := req.Header.Get("Apple-Challenge"); challenge != "" && this.key != nil {
+		ip := this.conn.LocalAddr().(*net.TCPAddr).IP
+		if response, err := appleResponse(this.key, challenge, ip, this.mac); err != nil {
+			this.Print("AirPlay: ", err)
+			return newResponse(400)
+		} else {
+			resp.Header.Set("Apple-Response", response)
+		}
+	}
+
+	// Return success
+	return resp
+}
+
+func (this *session) announce(req *request) *response {
+	f, err := parseSDP(string(req.Body))
+	if err != nil {
+		this.Print("AirPlay: ", err)
+		return newResponse(415)
+	}
+	decrypt, err := newCipher(this.key, f)
+	if err != nil {
+		this.Print("AirPlay: ", err)
+		return newResponse(415)
+	}
+	decoder, err := newDecoder(f)
+	if err != nil {
+		this.Print("AirPlay: ", err)
+		return newResponse(415)
+	}
+
+	// End any existing stream from this sender, and make this the
+	// active session
+	this.stop()
+	this.Mutex.Lock()
+	this.format, this.decrypt, this.decoder = f, decrypt, decoder
+	this.Mutex.Unlock()
+	this.activate(this)
+
+	// Return success
+	return newResponse(200)
+}
+
+func (this *session) setup(req *request) *response {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.format == nil || this.closed {
+		return newResponse(455)
+	} else if this.cancel != nil {
+		return newResponse(455)
+	}
+
+	// Set ports for sender control and timing packets
+	ip := this.conn.RemoteAddr().(*net.TCPAddr).IP
+	params := parseParams(req.Header.Get("Transport"))
+	for _, key := range []string{"control_port", "timing_port"} {
+		if port, err := strconv.ParseUint(params[key], 10, 16); err == nil && port != 0 {
+			this.remote[key] = &net.UDPAddr{IP: ip, Port: int(port)}
+		}
+	}
+
+	// Listen for audio, control and timing packets
+	ports := make([]int, 0, 3)
+	for _, conn := range []**net.UDPConn{&this.audio, &this.control, &this.timing} {
+		if udp, err := net.ListenUDP("udp", &net.UDPAddr{}); err != nil {
+			this.Print("AirPlay: ", err)
+			this.closeUDP()
+			return newResponse(453)
+		} else {
+			*conn = udp
+			ports = append(ports, udp.LocalAddr().(*net.UDPAddr).Port)
+		}
+	}
+
+	// Receive packets until the session ends
+	this.ctx, this.cancel = context.WithCancel(context.Background())
+	this.receive(this.audio, this.audioPacket)
+	this.receive(this.control, this.controlPacket)
+	this.receive(this.timing, this.timingPacket)
+	this.WaitGroup.Add(1)
+	go func(ctx context.Context) {
+		defer this.WaitGroup.Done()
+		this.requestTiming(ctx)
+	}(this.ctx)
+
+	// Return ports
+	resp := newResponse(200)
+	resp.Header.Set("Session", "1")
+	resp.Header.Set("Transport", fmt.Sprintf("RTP/AVP/UDP;unicast;mode=record;server_port=%d;control_port=%d;timing_port=%d", ports[0], ports[1], ports[2]))
+	return resp
+}
+
+func (this *session) record(req *request) *response {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.cancel == nil {
+		return newResponse(455)
+	} else if this.player != nil {
+		return newResponse(200)
+	}
+
+	// Open output and start playing
+	out, err := newOutput(*this.device, this.format.Rate, this.format.Channels)
+	if err != nil {
+		this.Print("AirPlay: ", err)
+		return newResponse(453)
+	}
+	this.player = newPlayer(out, &this.clock, this.format, *this.offset, this.gain())
+	this.WaitGroup.Add(1)
+	go func(ctx context.Context, player *player) {
+		defer this.WaitGroup.Done()
+		if err := player.Run(ctx); err != nil {
+			this.Print("AirPlay: ", err)
+		}
+	}(this.ctx, this.player)
+	this.emit(gopi.AIRPLAY_STATE_PLAYING, this.sender)
+
+	// Return success
+	return newResponse(200)
+}
+
+func (this *session) flush(req *request) *response {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.player != nil {
+		this.player.Flush()
+		this.emit(gopi.AIRPLAY_STATE_FLUSHED, this.sender)
+	}
+	return newResponse(200)
+}
+
+func (this *session) setParameter(req *request) *response {
+	// Metadata and artwork are accepted but ignored
+	if req.Header.Get("Content-Type") != "text/parameters" {
+		return newResponse(200)
+	}
+	for key, value := range parseParameters(string(req.Body)) {
+		switch key {
+		case "volume":
+			if db, err := strconv.ParseFloat(value, 32); err != nil {
+				return newResponse(451)
+			} else {
+				this.setVolume(float32(db))
+				this.Mutex.Lock()
+				if this.player != nil {
+					this.player.SetGain(this.gain())
+				}
+				this.Mutex.Unlock()
+				this.emit(gopi.AIRPLAY_STATE_VOLUME, this.sender)
+			}
+		}
+	}
+	return newResponse(200)
+}
+
+func (this *session) getParameter(req *request) *response {
+	resp := newResponse(200)
+	for _, key := range strings.Fields(string(req.Body)) {
+		switch key {
+		case "volume":
+			resp.Body = append(resp.Body, fmt.Sprintf("volume: %.6f\r\n", this.decibels())...)
+		default:
+			return newResponse(451)
+		}
+	}
+	if len(resp.Body) > 0 {
+		resp.Header.Set("Content-Type", "text/parameters")
+	}
+	return resp
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// stop receiving packets and playing, and release resources for the
+// stream
+func (this *session) stop() {
+	this.Mutex.Lock()
+	cancel := this.cancel
+	this.ctx, this.cancel = nil, nil
+	this.closeUDP()
+	this.Mutex.Unlock()
+
+	// Wait for packet receivers and player to end
+	if cancel != nil {
+		cancel()
+	}
+	this.WaitGroup.Wait()
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.player != nil {
+		if err := this.player.Close(); err != nil {
+			this.Print("AirPlay: ", err)
+		}
+		this.player = nil
+	}
+	if this.decoder != nil {
+		this.decoder.Close()
+		this.decoder = nil
+	}
+	this.format, this.decrypt = nil, nil
+}
+
+func (this *session) closeUDP() {
+	for _, conn := range []**net.UDPConn{&this.audio, &this.control, &this.timing} {
+		if *conn != nil {
+			(*conn).Close()
+			*conn = nil
+		}
+	}
+}
+
+// parseParameters returns parameters from a text/parameters body
+func parseParameters(body string) map[string]string {
+	params := make(map[string]string)
+	for _, line := range strings.Split(body, "\n") {
+		if kv := strings.SplitN(line, ":", 2); len(kv) == 2 {
+			params[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	return params
+}
+
+// volumeGain returns the gain for a volume in decibels, which is
+// between -30.0 and 0.0 or -144.0 when muted
+func volumeGain(db float32) float32 {
+	if db < minVolume {
+		return 0
+	} else if db >= 0 {
+		return 1
+	} else {
+		return float32(math.Pow(10, float64(db)/20))
+	}
+}
@@ -0,0 +1,315 @@
+package avsync
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type avsync struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Metrics
+	sync.Mutex
+
+	flag        *string
+	measurement string
+	master      gopi.MediaSyncClock
+
+	// Clocks
+	audio, video, system clock
+
+	// Video frame state
+	last, delay       time.Duration
+	dropped, repeated uint64
+
+	// Audio drift state
+	diffCum   float64
+	diffCount int
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Thresholds for dropping and repeating video frames, and the
+	// difference over which no synchronization is attempted
+	syncThresholdMin = 40 * time.Millisecond
+	syncThresholdMax = 100 * time.Millisecond
+	noSyncThreshold  = 10 * time.Second
+
+	// Audio drift is averaged over a number of updates, and corrected
+	// by at most a percentage of samples
+	audioDiffCount   = 20
+	audioDiffPercent = 10
+
+	// Default frame duration before frame times are known
+	defaultDelay = 40 * time.Millisecond
+
+	// Interval for emitting measurements
+	emitInterval = time.Second
+)
+
+var (
+	audioDiffCoef = math.Exp(math.Log(0.01) / audioDiffCount)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *avsync) Define(cfg gopi.Config) error {
+	this.flag = cfg.FlagString("avsync.master", "audio", "Master clock (audio, video, system)")
+	cfg.FlagString("avsync.measurement", "avsync", "Measurement name")
+	return nil
+}
+
+func (this *avsync) New(cfg gopi.Config) error {
+	// Set master clock
+	switch strings.ToLower(strings.TrimSpace(*this.flag)) {
+	case "audio":
+		this.master = gopi.MEDIA_SYNC_CLOCK_AUDIO
+	case "video":
+		this.master = gopi.MEDIA_SYNC_CLOCK_VIDEO
+	case "system":
+		this.master = gopi.MEDIA_SYNC_CLOCK_SYSTEM
+	default:
+		return gopi.ErrBadParameter.WithPrefix("-avsync.master")
+	}
+
+	// Define measurement
+	if measurement := cfg.GetString("avsync.measurement"); measurement != "" && this.Metrics != nil {
+		if m, err := this.Metrics.NewMeasurement(measurement, "master string, offset float64, dropped uint64, repeated uint64", this.Metrics.HostTag()); err != nil {
+			return err
+		} else {
+			this.measurement = m.Name()
+		}
+	}
+
+	// Reset clocks
+	this.Reset()
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *avsync) Run(ctx context.Context) error {
+	if this.measurement == "" {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(emitInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			if err := this.emit(now); err != nil {
+				this.Print("AVSync: ", err)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *avsync) String() string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	now := time.Now()
+	str := "<avsync"
+	str += fmt.Sprint(" master=", this.master)
+	if this.audio.IsSet() && this.video.IsSet() {
+		str += fmt.Sprint(" offset=", this.offset(now))
+	}
+	str += fmt.Sprint(" dropped=", this.dropped)
+	str += fmt.Sprint(" repeated=", this.repeated)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *avsync) Reset() {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	this.audio.Reset()
+	this.video.Reset()
+	this.system.Reset()
+	this.last, this.delay = 0, defaultDelay
+	this.dropped, this.repeated = 0, 0
+	this.diffCum, this.diffCount = 0, 0
+}
+
+func (this *avsync) Master() gopi.MediaSyncClock {
+	return this.master
+}
+
+func (this *avsync) Offset() time.Duration {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.offset(time.Now())
+}
+
+func (this *avsync) Audio(pts time.Duration, samples, rate uint) uint {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	now := time.Now()
+	this.audio.Set(pts, now)
+	if this.system.IsSet() == false {
+		this.system.Set(pts, now)
+	}
+
+	// Audio is not corrected when it is the master clock
+	if this.master == gopi.MEDIA_SYNC_CLOCK_AUDIO || samples == 0 || rate == 0 {
+		return samples
+	}
+
+	// Reset the average when clocks are too far apart to synchronize
+	diff := this.audio.Get(now) - this.clock(now)
+	if diff < -noSyncThreshold || diff > noSyncThreshold {
+		this.diffCum, this.diffCount = 0, 0
+		return samples
+	}
+
+	// Wait until there are enough updates for an average
+	this.diffCum = diff.Seconds() + audioDiffCoef*this.diffCum
+	if this.diffCount < audioDiffCount {
+		this.diffCount++
+		return samples
+	}
+
+	// Correct when the average drift is over the threshold, by up to
+	// a percentage of the samples
+	if avg := this.diffCum * (1.0 - audioDiffCoef); math.Abs(avg) < syncThresholdMin.Seconds() {
+		return samples
+	}
+	wanted := float64(samples) + diff.Seconds()*float64(rate)
+	min := float64(samples * (100 - audioDiffPercent) / 100)
+	max := float64(samples * (100 + audioDiffPercent) / 100)
+	return uint(math.Max(min, math.Min(max, wanted)))
+}
+
+func (this *avsync) Video(pts time.Duration) (gopi.MediaSyncAction, time.Duration) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	now := time.Now()
+	if this.system.IsSet() == false {
+		this.system.Set(pts, now)
+	}
+
+	// Frame duration is the difference from the previous frame, or else
+	// the previous duration
+	if this.video.IsSet() {
+		if delay := pts - this.last; delay > 0 && delay < noSyncThreshold {
+			this.delay = delay
+		}
+	}
+	this.last = pts
+
+	// The first frame is shown immediately
+	if this.video.IsSet() == false && this.master == gopi.MEDIA_SYNC_CLOCK_VIDEO {
+		this.video.Set(pts, now)
+		return gopi.MEDIA_SYNC_SHOW, 0
+	}
+
+	// Difference is how far ahead of the master clock the frame is
+	diff := pts - this.clock(now)
+	action, wait := gopi.MEDIA_SYNC_SHOW, time.Duration(0)
+	if diff < -noSyncThreshold || diff > noSyncThreshold {
+		wait = this.delay
+	} else if this.master == gopi.MEDIA_SYNC_CLOCK_VIDEO {
+		wait = maxDuration(0, diff)
+	} else if threshold := maxDuration(syncThresholdMin, minDuration(syncThresholdMax, this.delay)); diff <= -threshold {
+		action = gopi.MEDIA_SYNC_DROP
+		this.dropped++
+	} else if diff >= threshold && diff > this.delay {
+		action, wait = gopi.MEDIA_SYNC_REPEAT, diff
+		this.repeated++
+	} else {
+		wait = maxDuration(0, diff)
+	}
+
+	// Video clock is set to when the frame is shown
+	if action != gopi.MEDIA_SYNC_DROP {
+		this.video.Set(pts, now.Add(wait))
+	}
+
+	// Return action and wait
+	return action, wait
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// clock returns the time of the master clock, falling back to the
+// system clock when the master clock is not yet set
+func (this *avsync) clock(now time.Time) time.Duration {
+	switch {
+	case this.master == gopi.MEDIA_SYNC_CLOCK_AUDIO && this.audio.IsSet():
+		return this.audio.Get(now)
+	case this.master == gopi.MEDIA_SYNC_CLOCK_VIDEO && this.video.IsSet():
+		return this.video.Get(now)
+	default:
+		return this.system.Get(now)
+	}
+}
+
+// offset returns the difference between video and audio clocks, or
+// zero if either clock is not set
+func (this *avsync) offset(now time.Time) time.Duration {
+	if this.audio.IsSet() == false || this.video.IsSet() == false {
+		return 0
+	} else {
+		return this.video.Get(now) - this.audio.Get(now)
+	}
+}
+
+// emit the offset measurement when both clocks are set
+func (this *avsync) emit(now time.Time) error {
+	this.Mutex.Lock()
+	if this.audio.IsSet() == false || this.video.IsSet() == false {
+		this.Mutex.Unlock()
+		return nil
+	}
+	master, offset := fmt.Sprint(this.master), this.offset(now)
+	dropped, repeated := this.dropped, this.repeated
+	this.Mutex.Unlock()
+
+	return this.Metrics.Emit(this.measurement, nil, master, offset.Seconds(), dropped, repeated)
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	} else {
+		return b
+	}
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	} else {
+		return b
+	}
+}
@@ -0,0 +1,82 @@
+package avsync_test
+
+import (
+	"testing"
+	"time"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	// Units
+	_ "github.com/djthorpe/gopi/v3/pkg/media/avsync"
+)
+
+type App struct {
+	gopi.Unit
+	gopi.MediaSync
+}
+
+func Test_AVSync_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.MediaSync == nil {
+			t.Error("Unexpected nil MediaSync")
+		} else if app.Master() != gopi.MEDIA_SYNC_CLOCK_AUDIO {
+			t.Error("Unexpected master clock", app.Master())
+		} else {
+			t.Log(app.MediaSync)
+		}
+	})
+}
+
+func Test_AVSync_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		// Audio is not corrected when it is the master clock
+		if samples := app.Audio(time.Second, 1024, 48000); samples != 1024 {
+			t.Error("Unexpected samples", samples)
+		}
+
+		// Late frame is dropped, early frame repeats the previous frame
+		if action, _ := app.Video(time.Second - 500*time.Millisecond); action != gopi.MEDIA_SYNC_DROP {
+			t.Error("Unexpected action", action)
+		}
+		if action, wait := app.Video(time.Second + 500*time.Millisecond); action != gopi.MEDIA_SYNC_REPEAT {
+			t.Error("Unexpected action", action)
+		} else if wait <= 400*time.Millisecond || wait > 500*time.Millisecond {
+			t.Error("Unexpected wait", wait)
+		}
+
+		// Video clock is in step with audio once the frame is shown
+		if offset := app.Offset(); offset < -10*time.Millisecond || offset > 10*time.Millisecond {
+			t.Error("Unexpected offset", offset)
+		}
+
+		// Reset clocks, so the next frame is shown
+		app.Reset()
+		if action, wait := app.Video(time.Minute); action != gopi.MEDIA_SYNC_SHOW {
+			t.Error("Unexpected action", action)
+		} else if wait != 0 {
+			t.Error("Unexpected wait", wait)
+		}
+	})
+}
+
+func Test_AVSync_003(t *testing.T) {
+	tool.Test(t, []string{"-avsync.master=video"}, new(App), func(app *App) {
+		if app.Master() != gopi.MEDIA_SYNC_CLOCK_VIDEO {
+			t.Error("Unexpected master clock", app.Master())
+		}
+		if action, _ := app.Video(0); action != gopi.MEDIA_SYNC_SHOW {
+			t.Error("Unexpected action", action)
+		}
+
+		// Audio which is ahead of video is stretched, by at most ten percent
+		var samples uint
+		for i := 0; i < 30; i++ {
+			samples = app.Audio(time.Second, 1000, 48000)
+		}
+		if samples != 1100 {
+			t.Error("Unexpected samples", samples)
+		}
+	})
+}
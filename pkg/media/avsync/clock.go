@@ -0,0 +1,44 @@
+package avsync
+
+import (
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// clock is a presentation time which advances with wall time
+// from when it was last set
+type clock struct {
+	pts     time.Duration
+	updated time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// Set the presentation time at a wall time
+func (this *clock) Set(pts time.Duration, now time.Time) {
+	this.pts = pts
+	this.updated = now
+}
+
+// Reset the clock so that it is no longer set
+func (this *clock) Reset() {
+	this.pts = 0
+	this.updated = time.Time{}
+}
+
+// IsSet returns true if the clock has been set
+func (this *clock) IsSet() bool {
+	return this.updated.IsZero() == false
+}
+
+// Get returns the presentation time at a wall time
+func (this *clock) Get(now time.Time) time.Duration {
+	if this.updated.IsZero() {
+		return 0
+	} else {
+		return this.pts + now.Sub(this.updated)
+	}
+}
@@ -0,0 +1,10 @@
+// AVSync package implements gopi.MediaSync, which keeps audio and video
+// in step during playback by following a master clock.
+//
+// The master clock is set with the -avsync.master flag and is the audio
+// clock by default. When audio is the master, video frames which are late
+// are dropped and the previous frame is repeated when a frame is early.
+// When video or the system clock is the master, the number of audio samples
+// is adjusted so that the resampler can correct drift. The offset between
+// the clocks is emitted as a measurement when metrics are enabled.
+package avsync
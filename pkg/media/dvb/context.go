@@ -19,6 +19,16 @@ type Context struct {
 
 	nit     uint16
 	service map[uint16]*Service
+	sdt     map[uint8]bool // SDT sections received
+	sdtlast uint8          // Last SDT section number
+	eit     map[eitKey]uint8
+}
+
+// eitKey identifies an EIT section, the value is the version
+type eitKey struct {
+	tid     ts.TableType
+	service uint16
+	section uint8
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -30,16 +40,16 @@ func NewContext(pat *ts.Section) *Context {
 	}
 
 	this := new(Context)
-	this.nit = uint16(0x0010)
+	this.nit = uint16(ts.PID_NIT)
 	this.service = make(map[uint16]*Service, len(pat.PATSection.Programs))
+	this.sdt = make(map[uint8]bool)
+	this.eit = make(map[eitKey]uint8)
 
 	// Iterate through programs, program 0 is the NIT PID
 	for _, program := range pat.PATSection.Programs {
 		key := program.Pid
-		if key == 0 {
-			if program.Pid != 0 {
-				this.nit = program.Pid
-			}
+		if program.Program == 0 {
+			this.nit = program.Pid
 		} else {
 			this.service[key] = NewService(key, program.Program)
 		}
@@ -83,6 +93,7 @@ func (this *Context) SetPMT(pid uint16, section *ts.Section) error {
 	} else if section.TableId != ts.PMT {
 		return gopi.ErrInternalAppError.WithPrefix("SetPMT")
 	} else {
+		service.pcr = section.PMTSection.ClockPid
 		service.streams = section.PMTSection.ESTable.Rows
 	}
 
@@ -90,6 +101,88 @@ func (this *Context) SetPMT(pid uint16, section *ts.Section) error {
 	return nil
 }
 
+// SetSDT sets service names and providers from a service description
+// table section
+func (this *Context) SetSDT(section *ts.Section) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if section.TableId != ts.SDT {
+		return gopi.ErrInternalAppError.WithPrefix("SetSDT")
+	}
+	for _, row := range section.SDTSection.Services {
+		if service := this.serviceWithId(row.ServiceId()); service == nil {
+			continue
+		} else if _, provider, name, ok := row.Service(); ok {
+			service.provider = provider
+			service.name = name
+		}
+	}
+	this.sdt[section.SDTSection.Header.Section] = true
+	this.sdtlast = section.SDTSection.Header.LastSection
+
+	// Return success
+	return nil
+}
+
+// SetEIT returns true if an event information table section has
+// not been seen before, or has changed
+func (this *Context) SetEIT(section *ts.Section) bool {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	key := eitKey{section.TableId, section.EITSection.Header.Id, section.EITSection.Header.Section}
+	if version, exists := this.eit[key]; exists && version == section.EITSection.Header.Version {
+		return false
+	} else {
+		this.eit[key] = section.EITSection.Header.Version
+		return true
+	}
+}
+
+// Complete returns true when all service information has been
+// received
+func (this *Context) Complete() bool {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	for _, service := range this.service {
+		if service.streams == nil {
+			return false
+		}
+	}
+	for section := uint8(0); section <= this.sdtlast; section++ {
+		if _, exists := this.sdt[section]; exists == false {
+			return false
+		}
+	}
+	return true
+}
+
+func (this *Context) Services() []gopi.DVBService {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	result := make([]gopi.DVBService, 0, len(this.service))
+	for _, service := range this.service {
+		result = append(result, service)
+	}
+	return result
+}
+
+// Pids returns the PIDs which carry a service, or nil if the PMT for
+// the service has not yet been received
+func (this *Context) Pids(id uint16) ([]uint16, error) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if service := this.serviceWithId(id); service == nil {
+		return nil, gopi.ErrNotFound.WithPrefix("Service ", id)
+	} else {
+		return service.Pids(), nil
+	}
+}
+
 func (this *Context) GetService(pid uint16) gopi.DVBService {
 	this.RWMutex.RLock()
 	defer this.RWMutex.RUnlock()
@@ -101,6 +194,18 @@ func (this *Context) GetService(pid uint16) gopi.DVBService {
 	}
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *Context) serviceWithId(id uint16) *Service {
+	for _, service := range this.service {
+		if service.id == id {
+			return service
+		}
+	}
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
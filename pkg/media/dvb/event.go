@@ -0,0 +1,88 @@
+// +build dvb
+
+package dvb
+
+import (
+	"fmt"
+	"time"
+
+	ts "github.com/djthorpe/gopi/v3/pkg/media/internal/ts"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type EPGEvent struct {
+	service uint16
+	event   ts.EITEvent
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func NewEPGEvent(service uint16, event ts.EITEvent) *EPGEvent {
+	return &EPGEvent{service, event}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *EPGEvent) Name() string {
+	_, name, _, _ := this.event.ShortEvent()
+	return name
+}
+
+func (this *EPGEvent) Service() uint16 {
+	return this.service
+}
+
+func (this *EPGEvent) Id() uint16 {
+	return this.event.EventId
+}
+
+func (this *EPGEvent) Start() time.Time {
+	return this.event.Start
+}
+
+func (this *EPGEvent) Duration() time.Duration {
+	return this.event.Duration
+}
+
+func (this *EPGEvent) Language() string {
+	lang, _, _, _ := this.event.ShortEvent()
+	return lang
+}
+
+// Description returns the short description, followed by any
+// extended description
+func (this *EPGEvent) Description() string {
+	_, _, text, _ := this.event.ShortEvent()
+	if extended := this.event.ExtendedEvent(); extended == "" {
+		return text
+	} else if text == "" {
+		return extended
+	} else {
+		return text + "\n" + extended
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *EPGEvent) String() string {
+	str := "<dvb.epgevent"
+	str += fmt.Sprintf(" service=0x%04X id=0x%04X", this.service, this.Id())
+	if name := this.Name(); name != "" {
+		str += fmt.Sprintf(" name=%q", name)
+	}
+	if start := this.Start(); start.IsZero() == false {
+		str += fmt.Sprint(" start=", start.Format(time.RFC3339))
+	}
+	if duration := this.Duration(); duration != 0 {
+		str += fmt.Sprint(" duration=", duration)
+	}
+	if lang := this.Language(); lang != "" {
+		str += fmt.Sprintf(" lang=%q", lang)
+	}
+	return str + ">"
+}
@@ -37,6 +37,13 @@ type StreamFilter struct {
 // LIFECYCLE
 
 func NewSectionFilter(tuner *Tuner, pid uint16, tid ts.TableType, flags dvb.DMXFlag) (*SectionFilter, error) {
+	return NewSectionFilterWithMask(tuner, pid, tid, 0xFF, flags)
+}
+
+// NewSectionFilterWithMask returns a filter for sections where the
+// table identifier matches under a mask, so that a range of tables
+// can be received
+func NewSectionFilterWithMask(tuner *Tuner, pid uint16, tid ts.TableType, mask uint8, flags dvb.DMXFlag) (*SectionFilter, error) {
 	this := new(SectionFilter)
 
 	// Check incoming parameters
@@ -55,7 +62,7 @@ func NewSectionFilter(tuner *Tuner, pid uint16, tid ts.TableType, flags dvb.DMXF
 	this.DMXSectionFilter = dvb.NewSectionFilter(pid, 0, flags)
 
 	// Zero'th byte of section should match the TID
-	this.DMXSectionFilter.Set(0, uint8(tid), 0xFF, 0x00)
+	this.DMXSectionFilter.Set(0, uint8(tid), mask, 0x00)
 
 	// Set filter
 	if err := dvb.DMXSetSectionFilter(this.dev.Fd(), this.DMXSectionFilter); err != nil {
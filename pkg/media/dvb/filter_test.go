@@ -8,6 +8,7 @@ import (
 	gopi "github.com/djthorpe/gopi/v3"
 	_ "github.com/djthorpe/gopi/v3/pkg/file"
 	dvb "github.com/djthorpe/gopi/v3/pkg/media/dvb"
+	sys "github.com/djthorpe/gopi/v3/pkg/sys/dvb"
 	tool "github.com/djthorpe/gopi/v3/pkg/tool"
 )
 
@@ -23,7 +24,7 @@ func Test_Filter_001(t *testing.T) {
 			t.Skip("Skipping test, no device")
 		}
 		for _, tuner := range tuners {
-			if f, err := dvb.NewSectionFilter(tuner.(*dvb.Tuner), 0xFFFF, 0xFF); err != nil {
+			if f, err := dvb.NewSectionFilter(tuner.(*dvb.Tuner), 0xFFFF, 0xFF, sys.DMX_NONE); err != nil {
 				t.Error(err)
 			} else {
 				t.Log(f)
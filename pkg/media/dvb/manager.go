@@ -22,9 +22,11 @@ type Manager struct {
 	gopi.Unit
 	gopi.Logger
 	gopi.FilePoll
+	gopi.Publisher
 	sync.RWMutex
 	sync.WaitGroup
 
+	timeout *time.Duration
 	context map[*Tuner]*Context
 }
 
@@ -41,6 +43,11 @@ const (
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
+func (this *Manager) Define(cfg gopi.Config) error {
+	this.timeout = cfg.FlagDuration("dvb.timeout", 10*time.Second, "Timeout for receiving service information when scanning")
+	return nil
+}
+
 func (this *Manager) New(gopi.Config) error {
 	this.Require(this.Logger, this.FilePoll)
 
@@ -152,10 +159,13 @@ func (this *Manager) Tune(ctx context.Context, tuner gopi.DVBTuner, params gopi.
 	} else if err := this.StartSectionFilter(filter, func(section *ts.Section) {
 		// Oneshot filter
 		this.Debug("PAT: ", section)
-		// Create context
+		// Create context and receive service information
 		if ctx, err := this.CreateContext(tuner_, section); err != nil {
 			this.Debug("Tune: ", err)
 		} else {
+			if err := this.startServiceInfo(tuner_); err != nil {
+				this.Print("Tune: ", err)
+			}
 			cb(ctx)
 		}
 		// Remove section filter sometime in the future
@@ -195,6 +205,74 @@ func (this *Manager) Close(tuner gopi.DVBTuner) error {
 	return nil
 }
 
+// Scan tunes to each set of parameters and returns channels for the
+// services found. Service information is received until it is
+// complete or the timeout is reached
+func (this *Manager) Scan(ctx context.Context, tuner gopi.DVBTuner, params []gopi.DVBTunerParams) ([]gopi.DVBTunerParams, error) {
+	result := []gopi.DVBTunerParams{}
+	for _, param := range params {
+		if channels, err := this.scan(ctx, tuner, param); ctx.Err() != nil {
+			break
+		} else if err != nil {
+			this.Debugf("Scan: %v: %v", param.Name(), err)
+		} else {
+			result = append(result, channels...)
+		}
+	}
+
+	// Close the tuner, ignoring errors if it was never tuned
+	this.Close(tuner)
+
+	// Return channels and any cancellation error
+	return result, ctx.Err()
+}
+
+func (this *Manager) WriteChannels(w io.Writer, channels []gopi.DVBTunerParams) error {
+	params := make([]*dvb.TuneParams, 0, len(channels))
+	for _, channel := range channels {
+		if channel_, ok := channel.(*Params); ok == false || channel_ == nil {
+			return gopi.ErrBadParameter.WithPrefix("WriteChannels")
+		} else {
+			params = append(params, channel_.TuneParams)
+		}
+	}
+	return dvb.WriteTuneParamsTable(w, params)
+}
+
+func (this *Manager) Stream(ctx context.Context, tuner gopi.DVBTuner, service uint16) (string, error) {
+	tuner_ := this.getTunerForId(tuner.Id())
+	if tuner_ == nil {
+		return "", gopi.ErrOutOfOrder.WithPrefix("Stream: Not tuned")
+	}
+	path := tuner_.DVRPath()
+	if path == "" {
+		return "", gopi.ErrNotFound.WithPrefix("Stream: DVR device")
+	}
+
+	// Wait for the PMT for the service
+	pids, err := this.waitForPids(ctx, tuner_, service)
+	if err != nil {
+		return "", err
+	}
+
+	// Stop any existing stream and multiplex the service PIDs into
+	// a transport stream on the DVR device
+	if err := tuner_.RemoveStreamFilters(); err != nil {
+		return "", err
+	} else if filter, err := tuner_.NewStreamFilter(pids[0], dvb.DMX_IN_FRONTEND, dvb.DMX_OUT_TS_TAP, dvb.DMX_PES_OTHER); err != nil {
+		return "", err
+	} else if err := filter.AddPids(pids[1:]); err != nil {
+		tuner_.RemoveStreamFilters()
+		return "", err
+	} else if err := filter.Start(); err != nil {
+		tuner_.RemoveStreamFilters()
+		return "", err
+	}
+
+	// Return success
+	return path, nil
+}
+
 func (this *Manager) StartSectionFilter(filter *SectionFilter, cb func(*ts.Section)) error {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
@@ -282,6 +360,45 @@ func (this *Manager) ScanPMT(tuner *Tuner, pid uint16) error {
 	return nil
 }
 
+func (this *Manager) SetSDT(tuner *Tuner, section *ts.Section) error {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if section == nil {
+		return gopi.ErrBadParameter.WithPrefix("SetSDT")
+	} else if ctx, exists := this.context[tuner]; exists == false {
+		return gopi.ErrInternalAppError.WithPrefix("SetSDT")
+	} else {
+		return ctx.SetSDT(section)
+	}
+}
+
+// SetEIT emits programme guide events from an EIT section, unless the
+// section has already been received
+func (this *Manager) SetEIT(tuner *Tuner, section *ts.Section) error {
+	this.RWMutex.RLock()
+	ctx, exists := this.context[tuner]
+	this.RWMutex.RUnlock()
+
+	if section == nil {
+		return gopi.ErrBadParameter.WithPrefix("SetEIT")
+	} else if exists == false {
+		return gopi.ErrInternalAppError.WithPrefix("SetEIT")
+	} else if ctx.SetEIT(section) == false {
+		return nil
+	}
+
+	var result error
+	for _, event := range section.EITSection.Events {
+		if err := this.Publisher.Emit(NewEPGEvent(section.EITSection.Header.Id, event), false); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Return any errors
+	return result
+}
+
 func (this *Manager) SetPMT(tuner *Tuner, pid uint16, section *ts.Section) error {
 	this.RWMutex.RLock()
 	defer this.RWMutex.RUnlock()
@@ -293,7 +410,7 @@ func (this *Manager) SetPMT(tuner *Tuner, pid uint16, section *ts.Section) error
 	} else if err := ctx.SetPMT(pid, section); err != nil {
 		return err
 	} else {
-		this.Print("  service=", ctx.GetService(pid))
+		this.Debug("SetPMT: ", ctx.GetService(pid))
 	}
 
 	// Return success
@@ -326,6 +443,133 @@ func (this *Manager) getTunerForId(key uint) *Tuner {
 	return nil
 }
 
+// scan tunes to parameters and returns channels when service
+// information is complete or on timeout
+func (this *Manager) scan(ctx context.Context, tuner gopi.DVBTuner, params gopi.DVBTunerParams) ([]gopi.DVBTunerParams, error) {
+	params_, ok := params.(*Params)
+	if ok == false || params_ == nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("Scan")
+	}
+
+	// Tune and wait for the PAT
+	scanctx, cancel := context.WithTimeout(ctx, *this.timeout)
+	defer cancel()
+	tuned := make(chan *Context, 1)
+	if err := this.Tune(scanctx, tuner, params, func(ctx gopi.DVBContext) {
+		select {
+		case tuned <- ctx.(*Context):
+			break
+		default:
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	// Wait until service information is complete or timeout
+	ticker := time.NewTicker(deltaState)
+	defer ticker.Stop()
+	var tunectx *Context
+FOR_LOOP:
+	for {
+		select {
+		case <-scanctx.Done():
+			break FOR_LOOP
+		case tunectx = <-tuned:
+			break
+		case <-ticker.C:
+			if tunectx != nil && tunectx.Complete() {
+				break FOR_LOOP
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	} else if tunectx == nil {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("Scan: No PAT received")
+	} else if tunectx.Complete() == false {
+		this.Debugf("Scan: %v: Incomplete service information", params.Name())
+	}
+
+	// Return a channel for each service
+	result := []gopi.DVBTunerParams{}
+	for _, service := range tunectx.Services() {
+		name := service.Name()
+		if name == "" {
+			name = fmt.Sprintf("%v 0x%04X", params.Name(), service.Id())
+		}
+		result = append(result, params_.WithService(name, service.Id()))
+	}
+	return result, nil
+}
+
+// startServiceInfo receives service descriptions and, when events
+// can be published, the programme guide
+func (this *Manager) startServiceInfo(tuner *Tuner) error {
+	if filter, err := tuner.NewSectionFilter(ts.PID_SDT, ts.SDT, dvb.DMX_NONE); err != nil {
+		return err
+	} else if err := this.StartSectionFilter(filter, func(section *ts.Section) {
+		if section == nil {
+			return
+		} else if err := this.SetSDT(tuner, section); err != nil {
+			this.Print("SetSDT: ", err)
+		}
+	}); err != nil {
+		return err
+	}
+
+	// Present/following and schedule tables for this transport stream
+	if this.Publisher == nil {
+		return nil
+	}
+	for _, tid := range []struct {
+		ts.TableType
+		mask uint8
+	}{
+		{ts.EIT, 0xFF},
+		{ts.EIT_SCHEDULE, 0xF0},
+	} {
+		if filter, err := tuner.NewSectionFilterWithMask(ts.PID_EIT, tid.TableType, tid.mask, dvb.DMX_NONE); err != nil {
+			return err
+		} else if err := this.StartSectionFilter(filter, func(section *ts.Section) {
+			if section == nil {
+				return
+			} else if err := this.SetEIT(tuner, section); err != nil {
+				this.Print("SetEIT: ", err)
+			}
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// waitForPids returns the PIDs for a service once the PMT has been
+// received
+func (this *Manager) waitForPids(ctx context.Context, tuner *Tuner, service uint16) ([]uint16, error) {
+	ticker := time.NewTicker(deltaState)
+	defer ticker.Stop()
+	for {
+		this.RWMutex.RLock()
+		tunectx, exists := this.context[tuner]
+		this.RWMutex.RUnlock()
+		if exists == false {
+			return nil, gopi.ErrOutOfOrder.WithPrefix("Stream: Not tuned")
+		} else if pids, err := tunectx.Pids(service); err != nil {
+			return nil, err
+		} else if pids != nil {
+			return pids, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+			break
+		}
+	}
+}
+
 // updateState
 func (this *Manager) updateState() error {
 	var result error
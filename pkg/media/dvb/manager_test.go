@@ -73,7 +73,7 @@ func Test_Manager_003(t *testing.T) {
 			t.Log("Tuning", param.Name())
 			ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
 			defer cancel()
-			if err := app.DVBManager.Tune(ctx, devices[0], param); errors.Is(err, context.DeadlineExceeded) {
+			if err := app.DVBManager.Tune(ctx, devices[0], param, func(gopi.DVBContext) {}); errors.Is(err, context.DeadlineExceeded) {
 				t.Log("  Tune Timeout")
 			} else if err != nil {
 				t.Error(err)
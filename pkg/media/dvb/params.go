@@ -35,3 +35,19 @@ func NewParams(v *dvb.TuneParams) *Params {
 func (this *Params) Name() string {
 	return this.TuneParams.Name()
 }
+
+func (this *Params) ServiceId() uint16 {
+	if this.TuneParams.HasServiceId() {
+		return this.TuneParams.ServiceId
+	} else {
+		return 0
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// WithService returns parameters for a channel on the multiplex
+func (this *Params) WithService(name string, id uint16) *Params {
+	return NewParams(this.TuneParams.WithService(name, id))
+}
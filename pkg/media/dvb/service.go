@@ -13,10 +13,13 @@ import (
 // TYPES
 
 type Service struct {
-	pid     uint16
-	id      uint16
-	ts      time.Time
-	streams []ts.ESRow
+	pid      uint16
+	id       uint16
+	ts       time.Time
+	pcr      uint16
+	streams  []ts.ESRow
+	name     string
+	provider string
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -40,6 +43,14 @@ func (this *Service) Pid() uint16 {
 	return this.pid
 }
 
+func (this *Service) Name() string {
+	return this.name
+}
+
+func (this *Service) Provider() string {
+	return this.provider
+}
+
 func (this *Service) Streams() bool {
 	return this.streams == nil
 }
@@ -48,6 +59,35 @@ func (this *Service) SetStreams(streams []ts.ESRow) {
 	this.streams = streams
 }
 
+// Pids returns the PIDs which carry the service, including the PAT,
+// or nil if the PMT has not been received
+func (this *Service) Pids() []uint16 {
+	if this.streams == nil {
+		return nil
+	}
+	pids := []uint16{ts.PID_PAT, this.pid}
+	if this.pcr != ts.PID_NULL {
+		pids = appendPid(pids, this.pcr)
+	}
+	for _, stream := range this.streams {
+		pids = appendPid(pids, stream.Pid())
+	}
+	return pids
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// appendPid appends a PID which is not already in the list
+func appendPid(pids []uint16, pid uint16) []uint16 {
+	for _, other := range pids {
+		if other == pid {
+			return pids
+		}
+	}
+	return append(pids, pid)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -55,6 +95,12 @@ func (this *Service) String() string {
 	str := "<dvb.service"
 	str += fmt.Sprintf(" id=0x%04X", this.id)
 	str += fmt.Sprintf(" pid=0x%04X", this.pid)
+	if this.name != "" {
+		str += fmt.Sprintf(" name=%q", this.name)
+	}
+	if this.provider != "" {
+		str += fmt.Sprintf(" provider=%q", this.provider)
+	}
 	for _, stream := range this.streams {
 		str += fmt.Sprint(" stream=", stream)
 	}
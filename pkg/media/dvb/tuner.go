@@ -81,7 +81,7 @@ func (this *Tuner) Dispose(fp gopi.FilePoll) error {
 		}
 	}
 	for filter := range this.stream {
-		if err := this.unwatchStreamFilter(fp, filter); err != nil {
+		if err := this.disposeStreamFilter(filter); err != nil {
 			result = multierror.Append(result, err)
 		}
 	}
@@ -127,6 +127,10 @@ func (this *Tuner) Sys() []dvb.FEDeliverySystem {
 // PUBLIC METHODS
 
 func (this *Tuner) NewSectionFilter(pid uint16, tid ts.TableType, flags dvb.DMXFlag) (*SectionFilter, error) {
+	return this.NewSectionFilterWithMask(pid, tid, 0xFF, flags)
+}
+
+func (this *Tuner) NewSectionFilterWithMask(pid uint16, tid ts.TableType, mask uint8, flags dvb.DMXFlag) (*SectionFilter, error) {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
 
@@ -136,7 +140,7 @@ func (this *Tuner) NewSectionFilter(pid uint16, tid ts.TableType, flags dvb.DMXF
 	}
 
 	// Create filter
-	filter, err := NewSectionFilter(this, pid, tid, flags)
+	filter, err := NewSectionFilterWithMask(this, pid, tid, mask, flags)
 	if err != nil {
 		return nil, err
 	}
@@ -203,29 +207,34 @@ func (this *Tuner) unwatchSectionFilter(fp gopi.FilePoll, filter *SectionFilter)
 	return result
 }
 
-func (this *Tuner) RemoveStreamFilter(fp gopi.FilePoll, filter *StreamFilter) error {
+// RemoveStreamFilters stops and removes all stream filters. Stream
+// filters are not watched, since the stream is read from the DVR
+// device
+func (this *Tuner) RemoveStreamFilters() error {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
 
 	// Check for disposed tuner
 	if this.dev == nil {
-		return gopi.ErrOutOfOrder.WithPrefix("RemoveStreamFilter")
+		return gopi.ErrOutOfOrder.WithPrefix("RemoveStreamFilters")
 	}
 
-	// Check parameters
-	if _, exists := this.stream[filter]; exists == false {
-		return gopi.ErrNotFound.WithPrefix("RemoveStreamFilter")
-	} else {
+	var result error
+	for filter := range this.stream {
+		if err := this.disposeStreamFilter(filter); err != nil {
+			result = multierror.Append(result, err)
+		}
 		delete(this.stream, filter)
 	}
 
-	return this.unwatchStreamFilter(fp, filter)
+	// Return errors
+	return result
 }
 
-func (this *Tuner) unwatchStreamFilter(fp gopi.FilePoll, filter *StreamFilter) error {
+func (this *Tuner) disposeStreamFilter(filter *StreamFilter) error {
 	var result error
 
-	if err := fp.Unwatch(filter.Fd()); err != nil {
+	if err := filter.Stop(); err != nil {
 		result = multierror.Append(result, err)
 	}
 	if err := filter.Dispose(); err != nil {
@@ -316,6 +325,16 @@ func (this *Tuner) OpenDemux() (*os.File, error) {
 	return this.Device.DMXOpen()
 }
 
+// DVRPath returns the path to the device which provides the
+// transport stream, or empty string if it does not exist
+func (this *Tuner) DVRPath() string {
+	if len(this.Device.Dvr) == 0 {
+		return ""
+	} else {
+		return this.Device.Path("dvr", this.Device.Dvr[0])
+	}
+}
+
 // Validate determines if parameters are supported by the tuner
 func (this *Tuner) Validate(params *Params) error {
 	if this.hasDeliverySystem(params) == false {
@@ -323,7 +342,6 @@ func (this *Tuner) Validate(params *Params) error {
 	}
 
 	// TODO: Validate more params here
-	fmt.Println("TODO: Validate: More validation here")
 
 	// Return success
 	return nil
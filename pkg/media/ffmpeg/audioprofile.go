@@ -133,6 +133,27 @@ func (this *AudioProfile) Resample(src *ffmpeg.AVFrame) (*ffmpeg.AVFrame, error)
 	}
 }
 
+// Compensate corrects drift by resampling samples of input at a sample
+// rate to wanted samples, where wanted is returned by gopi.MediaSync. It
+// is called before Resample
+func (this *AudioProfile) Compensate(samples, wanted, rate uint) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if this.ctx == nil {
+		return gopi.ErrOutOfOrder.WithPrefix("Compensate")
+	} else if rate == 0 {
+		return gopi.ErrBadParameter.WithPrefix("Compensate")
+	} else if samples == 0 || wanted == samples {
+		return nil
+	}
+
+	// Scale number of samples from input to output rate
+	delta := (int(wanted) - int(samples)) * int(this.rate) / int(rate)
+	distance := int(wanted) * int(this.rate) / int(rate)
+	return this.ctx.SetCompensation(delta, distance)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
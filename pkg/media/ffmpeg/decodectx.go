@@ -9,6 +9,7 @@ import (
 	"syscall"
 
 	gopi "github.com/djthorpe/gopi/v3"
+	pool "github.com/djthorpe/gopi/v3/pkg/pool"
 	ffmpeg "github.com/djthorpe/gopi/v3/pkg/sys/ffmpeg"
 )
 
@@ -19,6 +20,7 @@ type decodectx struct {
 	sync.RWMutex
 
 	stream    *stream
+	buf       *pool.Buffer
 	frame     *frame
 	ctx       *ffmpeg.AVCodecContext
 	streammap *streammap
@@ -38,15 +40,17 @@ func NewDecodeContext(s *stream, m *streammap) *decodectx {
 		this.streammap = m
 	}
 
-	// Create frame
-	if frame := NewFrame(); frame == nil {
+	// Take a frame from the pool
+	if buf, err := frames.Get(); err != nil {
 		return nil
 	} else {
-		this.frame = frame
+		this.buf = buf
+		this.frame = buf.Value().(*frame)
 	}
 
 	// Create codec context
 	if ctx := this.stream.NewContextWithOptions(nil); ctx == nil {
+		this.buf.Release()
 		return nil
 	} else {
 		this.ctx = ctx
@@ -60,18 +64,19 @@ func (this *decodectx) Close() error {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
 
-	// Free context and frame
-	this.frame.Free()
+	// Free context and return frame to the pool
+	err := this.buf.Release()
 	this.ctx.Free()
 
 	// Release resources
 	this.stream = nil
 	this.streammap = nil
 	this.ctx = nil
+	this.buf = nil
 	this.frame = nil
 
-	// Return success
-	return nil
+	// Return any errors
+	return err
 }
 
 ////////////////////////////////////////////////////////////////////////////////
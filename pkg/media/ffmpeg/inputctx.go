@@ -225,12 +225,13 @@ func (this *inputctx) Read(ctx context.Context, streams []int, fn gopi.DecodeIte
 		}
 	}
 
-	// Create a packet
-	packet := ffmpeg.NewAVPacket()
-	if packet == nil {
-		return gopi.ErrInternalAppError.WithPrefix("DecodeIterator")
+	// Take a packet from the pool
+	buf, err := packets.Get()
+	if err != nil {
+		return gopi.ErrInternalAppError.WithPrefix("DecodeIterator: ", err)
 	}
-	defer packet.Free()
+	defer buf.Release()
+	packet := buf.Value().(*ffmpeg.AVPacket)
 
 	// Iterate over incoming packets, callback when packet should
 	// be processed. Return if parent context is done
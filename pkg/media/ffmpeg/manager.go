@@ -3,6 +3,7 @@
 package ffmpeg
 
 import (
+	"io"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 	"sync"
 
 	gopi "github.com/djthorpe/gopi/v3"
+	pool "github.com/djthorpe/gopi/v3/pkg/pool"
 	ffmpeg "github.com/djthorpe/gopi/v3/pkg/sys/ffmpeg"
 	multierror "github.com/hashicorp/go-multierror"
 )
@@ -25,11 +27,23 @@ type Manager struct {
 	in           []*inputctx
 	out          []*outputctx
 	audioprofile []*AudioProfile
+	encoder      *string
+	bitrate      *uint
+	profile      *string
+	level        *string
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // LIFECYCLE
 
+func (this *Manager) Define(cfg gopi.Config) error {
+	this.encoder = cfg.FlagString("ffmpeg.encoder", "", "Video encoder, or empty to prefer hardware encoding")
+	this.bitrate = cfg.FlagUint("ffmpeg.bitrate", 2000000, "Video encoder bits per second")
+	this.profile = cfg.FlagString("ffmpeg.profile", "high", "Video encoder profile")
+	this.level = cfg.FlagString("ffmpeg.level", "4.0", "Video encoder level")
+	return nil
+}
+
 func (this *Manager) New(gopi.Config) error {
 	if this.Logger == nil {
 		return gopi.ErrInternalAppError.WithPrefix("gopi.Logger")
@@ -42,6 +56,10 @@ func (this *Manager) New(gopi.Config) error {
 		this.Logger.Print(level, " ", message)
 	})
 
+	// Record where frames and packets are taken in debug mode
+	frames.SetDebug(this.Logger.IsDebug())
+	packets.SetDebug(this.Logger.IsDebug())
+
 	// Initialize format
 	ffmpeg.AVFormatInit()
 
@@ -80,6 +98,16 @@ func (this *Manager) Dispose() error {
 		}
 	}
 
+	// Free idle frames and packets, and report any which were not released
+	for _, p := range []*pool.Pool{frames, packets} {
+		for _, leak := range p.Leaks() {
+			this.Debug("Not released: ", leak)
+		}
+		if err := p.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
 	// Deinit
 	ffmpeg.AVFormatDeinit()
 
@@ -174,25 +202,60 @@ func (this *Manager) CreateFile(path string) (gopi.MediaOutput, error) {
 	}
 }
 
-func (this *Manager) Close(media gopi.Media) error {
+func (this *Manager) CreateWriter(w io.Writer, format string) (gopi.MediaOutput, error) {
 	this.Mutex.Lock()
 	defer this.Mutex.Unlock()
 
-	// TODO OUTPUT
+	// Check parameters
+	if w == nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("CreateWriter")
+	}
+	oformat := ffmpeg.GuessOutputFormat(format, "", "")
+	if oformat == nil {
+		return nil, gopi.ErrNotFound.WithPrefix("CreateWriter: ", format)
+	} else if oformat.Flags()&ffmpeg.AVFMT_NOFILE != 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("CreateWriter: ", format)
+	}
 
-	if in, ok := media.(*inputctx); in == nil || ok == false {
-		return gopi.ErrInternalAppError.WithPrefix("Close")
+	if ctx, err := ffmpeg.NewAVFormatOutputContext("", oformat); err != nil {
+		return nil, err
+	} else if out := NewOutputWriterContext(ctx, w); out == nil {
+		return nil, gopi.ErrInternalAppError.WithPrefix("NewOutputWriterContext")
 	} else {
-		for i, in_ := range this.in {
-			if in_ != in {
+		this.out = append(this.out, out)
+		return out, nil
+	}
+}
+
+func (this *Manager) Close(media gopi.Media) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	switch media_ := media.(type) {
+	case *inputctx:
+		for i, in := range this.in {
+			if in != media_ || in == nil {
 				continue
 			}
 			err := in.Close()
 			this.in[i] = nil
 			return err
 		}
-		return gopi.ErrNotFound.WithPrefix("Close")
+	case *outputctx:
+		for i, out := range this.out {
+			if out != media_ || out == nil {
+				continue
+			}
+			err := out.Close()
+			this.out[i] = nil
+			return err
+		}
+	default:
+		return gopi.ErrInternalAppError.WithPrefix("Close")
 	}
+
+	// Media not found
+	return gopi.ErrNotFound.WithPrefix("Close")
 }
 
 func (this *Manager) ListCodecs(name string, flags gopi.MediaFlag) []gopi.MediaCodec {
@@ -253,9 +316,63 @@ func (this *Manager) ListCodecs(name string, flags gopi.MediaFlag) []gopi.MediaC
 	return result[:dst]
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - PROBE
+
+func (this *Manager) Probe(path string) (*gopi.MediaProbe, error) {
+	// Clean up the path
+	if filepath.IsAbs(path) == false {
+		if path_, err := filepath.Abs(path); err == nil {
+			path = filepath.Clean(path_)
+		}
+	}
+
+	// Check to see if path exists
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound.WithPrefix(path)
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Open the file and read stream information
+	ctx := ffmpeg.NewAVFormatContext()
+	if ctx == nil {
+		return nil, gopi.ErrInternalAppError.WithPrefix("NewAVFormatContext")
+	} else if err := ctx.OpenInput(path, nil); err != nil {
+		// when error is returned free is already called
+		return nil, err
+	}
+	defer ctx.CloseInput()
+	if dict, err := ctx.FindStreamInfo(); err != nil {
+		return nil, err
+	} else {
+		dict.Close()
+	}
+
+	// Return the report
+	return NewProbe(ctx, path), nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS - PROFILES
 
+func (this *Manager) VideoProfile(codec string, w, h uint) (gopi.MediaVideoProfile, error) {
+	profile, err := NewVideoProfile(codec, *this.encoder, w, h, *this.bitrate, *this.profile, *this.level)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report which encoder is used
+	if profile.Hardware() {
+		this.Logger.Debug("VideoProfile: Hardware encoder ", profile.encoder.name)
+	} else {
+		this.Logger.Debug("VideoProfile: Software encoder ", profile.encoder.name)
+	}
+
+	// Return success
+	return profile, nil
+}
+
 func (this *Manager) AudioProfile(fmt gopi.AudioFormat, rate uint, layout gopi.AudioChannelLayout) gopi.MediaProfile {
 	profile := NewAudioProfile(fmt, rate, layout)
 	if profile != nil {
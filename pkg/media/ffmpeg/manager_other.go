@@ -3,6 +3,8 @@
 package ffmpeg
 
 import (
+	"io"
+
 	gopi "github.com/djthorpe/gopi/v3"
 )
 
@@ -28,6 +30,18 @@ func (this *Manager) CreateFile(path string) (gopi.Media, error) {
 	return nil, gopi.ErrNotImplemented
 }
 
+func (this *Manager) CreateWriter(io.Writer, string) (gopi.MediaOutput, error) {
+	return nil, gopi.ErrNotImplemented
+}
+
 func (this *Manager) Close(gopi.Media) error {
 	return gopi.ErrNotImplemented
 }
+
+func (this *Manager) VideoProfile(string, uint, uint) (gopi.MediaVideoProfile, error) {
+	return nil, gopi.ErrNotImplemented
+}
+
+func (this *Manager) Probe(string) (*gopi.MediaProbe, error) {
+	return nil, gopi.ErrNotImplemented
+}
@@ -3,7 +3,9 @@
 package ffmpeg_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"testing"
 
 	gopi "github.com/djthorpe/gopi/v3"
@@ -90,3 +92,82 @@ func Test_MediaManager_005(t *testing.T) {
 		}
 	})
 }
+
+func Test_MediaManager_006(t *testing.T) {
+	tool.Test(t, nil, new(MediaApp), func(app *MediaApp) {
+		file, err := app.Manager.OpenFile(SAMPLE_FILE)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer app.Manager.Close(file)
+
+		// Remux to a buffer
+		var buf bytes.Buffer
+		out, err := app.Manager.CreateWriter(&buf, "mpegts")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if err := file.Read(ctx, nil, func(ctx gopi.MediaDecodeContext, packet gopi.MediaPacket) error {
+			return out.Write(ctx, packet)
+		}); err != nil {
+			t.Error(err)
+		}
+		if err := app.Manager.Close(out); err != nil {
+			t.Error(err)
+		} else if buf.Len() == 0 {
+			t.Error("Expected output written to buffer")
+		} else {
+			t.Log("Wrote", buf.Len(), "bytes")
+		}
+
+		// Unknown format
+		if _, err := app.Manager.CreateWriter(&buf, "not-a-format"); err == nil {
+			t.Error("Expected error for unknown format")
+		}
+	})
+}
+
+func Test_MediaManager_007(t *testing.T) {
+	tool.Test(t, nil, new(MediaApp), func(app *MediaApp) {
+		if profile, err := app.Manager.VideoProfile("h264", 1280, 720); err != nil {
+			t.Log("No encoder:", err)
+		} else if profile.Width() != 1280 || profile.Height() != 720 {
+			t.Error("Unexpected size", profile)
+		} else {
+			t.Log(profile, "hardware=", profile.Hardware())
+		}
+		if _, err := app.Manager.VideoProfile("h264", 0, 0); err == nil {
+			t.Error("Expected error for zero size")
+		}
+	})
+}
+
+func Test_MediaManager_008(t *testing.T) {
+	tool.Test(t, nil, new(MediaApp), func(app *MediaApp) {
+		probe, err := app.Manager.Probe(SAMPLE_FILE)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if probe.Format == "" {
+			t.Error("Unexpected empty format")
+		} else if probe.Duration <= 0 {
+			t.Error("Unexpected duration", probe.Duration)
+		} else if len(probe.Streams) == 0 {
+			t.Error("Unexpected no streams")
+		}
+		if data, err := json.MarshalIndent(probe, "", "  "); err != nil {
+			t.Error(err)
+		} else {
+			t.Log(string(data))
+		}
+		if _, err := app.Manager.Probe("nonexistent.mp4"); err == nil {
+			t.Error("Expected error for missing file")
+		}
+	})
+}
@@ -4,7 +4,9 @@ package ffmpeg
 
 import (
 	"fmt"
+	"io"
 	"net/url"
+	"sort"
 	"strconv"
 	"sync"
 
@@ -19,9 +21,11 @@ import (
 type outputctx struct {
 	sync.RWMutex
 
-	ctx     *ffmpeg.AVFormatContext
-	avio    *ffmpeg.AVIOContext
-	streams []*stream
+	ctx       *ffmpeg.AVFormatContext
+	avio      *ffmpeg.AVIOContext
+	w         io.Writer
+	streams   []*stream
+	streammap map[*stream]*stream
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -40,21 +44,37 @@ func NewOutputContext(ctx *ffmpeg.AVFormatContext) *outputctx {
 	return this
 }
 
+// NewOutputWriterContext returns an output which writes to an io.Writer
+// rather than a file
+func NewOutputWriterContext(ctx *ffmpeg.AVFormatContext, w io.Writer) *outputctx {
+	if w == nil {
+		return nil
+	} else if this := NewOutputContext(ctx); this == nil {
+		return nil
+	} else {
+		this.w = w
+		return this
+	}
+}
+
 func (this *outputctx) Close() error {
 	this.RWMutex.Lock()
 	defer this.RWMutex.Unlock()
 
 	var result error
 
-	// Write trailer
-	if this.ctx != nil {
+	// Write trailer if header has been written
+	if this.ctx != nil && this.streams != nil {
 		if err := this.ctx.WriteTrailer(); err != nil {
 			result = multierror.Append(result, err)
 		}
 	}
 
-	// Close files
-	if this.avio != nil {
+	// Close files or writers
+	if this.avio != nil && this.w != nil {
+		this.avio.FreeWriter()
+		this.ctx.SetIOContext(nil)
+	} else if this.avio != nil {
 		this.avio.Flush()
 		if err := this.avio.Close(); err != nil {
 			result = multierror.Append(result, err)
@@ -74,7 +94,9 @@ func (this *outputctx) Close() error {
 	// Release resources
 	this.ctx = nil
 	this.avio = nil
+	this.w = nil
 	this.streams = nil
+	this.streammap = nil
 
 	// Return success
 	return multierror.Flatten(result)
@@ -167,11 +189,35 @@ func (this *outputctx) StreamForIndex(i int) gopi.MediaStream {
 ////////////////////////////////////////////////////////////////////////////////
 // METHODS
 
-// DecodeIterator loops over selected streams from media object
+// Write a packet read from an input to the output. Output streams
+// are created from the input streams on the first write
 func (this *outputctx) Write(ctx gopi.MediaDecodeContext, packet gopi.MediaPacket) error {
-	// If file and no avio context, then create one
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Check parameters
+	ctx_, ok := ctx.(*decodectx)
+	if ok == false || ctx_ == nil {
+		return gopi.ErrBadParameter.WithPrefix("Write")
+	}
+	packet_, ok := packet.(*ffmpeg.AVPacket)
+	if ok == false || packet_ == nil {
+		return gopi.ErrBadParameter.WithPrefix("Write")
+	} else if this.ctx == nil {
+		return gopi.ErrOutOfOrder.WithPrefix("Write")
+	}
+
+	// If file and no avio context, then create one which writes to
+	// the writer or opens the file
 	if this.IsFile() && this.avio == nil {
-		if avio, err := ffmpeg.NewAVIOContext(this.URL(), ffmpeg.AVIO_FLAG_WRITE); err != nil {
+		if this.w != nil {
+			if avio, err := ffmpeg.NewAVIOWriter(this.w, 0); err != nil {
+				return err
+			} else {
+				this.avio = avio
+				this.ctx.SetIOContext(avio)
+			}
+		} else if avio, err := ffmpeg.NewAVIOContext(this.ctx.Url(), ffmpeg.AVIO_FLAG_WRITE); err != nil {
 			return err
 		} else {
 			this.avio = avio
@@ -190,33 +236,17 @@ func (this *outputctx) Write(ctx gopi.MediaDecodeContext, packet gopi.MediaPacke
 		}
 	}
 
-	/*
-		in_stream  = ifmt_ctx->streams[pkt.stream_index];
-		if (pkt.stream_index >= stream_mapping_size ||
-			stream_mapping[pkt.stream_index] < 0) {
-			av_packet_unref(&pkt);
-			continue;
-		}
-		pkt.stream_index = stream_mapping[pkt.stream_index];
-		out_stream = ofmt_ctx->streams[pkt.stream_index];
-		log_packet(ifmt_ctx, &pkt, "in");
-		// copy packet
-		pkt.pts = av_rescale_q_rnd(pkt.pts, in_stream->time_base, out_stream->time_base, AV_ROUND_NEAR_INF|AV_ROUND_PASS_MINMAX);
-		pkt.dts = av_rescale_q_rnd(pkt.dts, in_stream->time_base, out_stream->time_base, AV_ROUND_NEAR_INF|AV_ROUND_PASS_MINMAX);
-		pkt.duration = av_rescale_q(pkt.duration, in_stream->time_base, out_stream->time_base);
-		pkt.pos = -1;
-		log_packet(ofmt_ctx, &pkt, "out");
-		ret = av_interleaved_write_frame(ofmt_ctx, &pkt);
-		if (ret < 0) {
-			fprintf(stderr, "Error muxing packet\n");
-			break;
-		}
-		av_packet_unref(&pkt);
-	*/
-
-	return gopi.ErrNotImplemented
+	// Ignore packets from streams which were not mapped
+	in := ctx_.stream
+	if out, exists := this.streammap[in]; exists == false {
+		return nil
+	} else {
+		return this.ctx.WritePacket(packet_, in.ctx, out.ctx)
+	}
 }
 
+// MapStreams creates an output stream for each stream being read from
+// the input, copying the codec parameters
 func (this *outputctx) MapStreams(ctx gopi.MediaDecodeContext) error {
 	var mapper *streammap
 
@@ -229,20 +259,29 @@ func (this *outputctx) MapStreams(ctx gopi.MediaDecodeContext) error {
 		mapper = ctx_.streammap
 	}
 
-	// Iterate through streams and create output streams as necessary
-	for in, out := range mapper.Map() {
-		if out != nil {
-			continue
-		}
-		// Create an output stream
+	// Order input streams by index
+	streams := make([]*stream, 0, len(mapper.Map()))
+	for in := range mapper.Map() {
+		streams = append(streams, in)
+	}
+	sort.Slice(streams, func(i, j int) bool {
+		return streams[i].ctx.Index() < streams[j].ctx.Index()
+	})
+
+	// Create an output stream for each input stream
+	this.streammap = make(map[*stream]*stream, len(streams))
+	for _, in := range streams {
 		if stream := ffmpeg.NewStream(this.ctx, nil); stream == nil {
 			return gopi.ErrBadParameter.WithPrefix("Write")
-		} else if out = NewStream(stream, in); out == nil {
-			return gopi.ErrInternalAppError.WithPrefix("Write")
-		} else if err := mapper.Set(in, out); err != nil {
+		} else if err := stream.CodecPar().CopyFrom(in.ctx.CodecPar()); err != nil {
 			return err
+		} else if out := NewStream(stream, in); out == nil {
+			return gopi.ErrInternalAppError.WithPrefix("Write")
 		} else {
-			fmt.Printf("%v\n   => %v\n\n", in, out)
+			// Let the muxer choose the codec tag
+			stream.CodecPar().SetTag(0)
+			this.streammap[in] = out
+			this.streams = append(this.streams, out)
 		}
 	}
 
@@ -0,0 +1,45 @@
+// +build ffmpeg
+
+package ffmpeg
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+	pool "github.com/djthorpe/gopi/v3/pkg/pool"
+	ffmpeg "github.com/djthorpe/gopi/v3/pkg/sys/ffmpeg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	maxIdleFrames  = 8
+	maxIdlePackets = 8
+)
+
+var (
+	// frames are kept for decoders, and unreferenced when released
+	frames = pool.New("ffmpeg.frame", maxIdleFrames, func() (interface{}, error) {
+		if frame := NewFrame(); frame == nil {
+			return nil, gopi.ErrInternalAppError.WithPrefix("NewFrame")
+		} else {
+			return frame, nil
+		}
+	}, func(value interface{}) {
+		value.(*frame).Release()
+	}, func(value interface{}) {
+		value.(*frame).Free()
+	})
+
+	// packets are kept for reading input, and unreferenced when released
+	packets = pool.New("ffmpeg.packet", maxIdlePackets, func() (interface{}, error) {
+		if packet := ffmpeg.NewAVPacket(); packet == nil {
+			return nil, gopi.ErrInternalAppError.WithPrefix("NewAVPacket")
+		} else {
+			return packet, nil
+		}
+	}, func(value interface{}) {
+		value.(*ffmpeg.AVPacket).Release()
+	}, func(value interface{}) {
+		value.(*ffmpeg.AVPacket).Free()
+	})
+)
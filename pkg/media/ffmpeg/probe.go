@@ -0,0 +1,112 @@
+// +build ffmpeg
+
+package ffmpeg
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+	ffmpeg "github.com/djthorpe/gopi/v3/pkg/sys/ffmpeg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+// NewProbe returns a report on an input format context, after stream
+// information has been read
+func NewProbe(ctx *ffmpeg.AVFormatContext, path string) *gopi.MediaProbe {
+	if ctx == nil {
+		return nil
+	}
+
+	probe := &gopi.MediaProbe{
+		Path:     path,
+		Duration: float64(ctx.Duration()) / float64(ffmpeg.AV_TIME_BASE),
+		BitRate:  uint(ctx.BitRate()),
+		Chapters: []gopi.MediaProbeChapter{},
+		Streams:  []gopi.MediaProbeStream{},
+		Tags:     probeTags(ctx.Metadata()),
+	}
+	if ifmt := ctx.InputFormat(); ifmt != nil {
+		probe.Format = ifmt.Name()
+		probe.Description = ifmt.Description()
+	}
+
+	// Append chapters
+	for _, chapter := range ctx.Chapters() {
+		tb := chapter.TimeBase()
+		probe.Chapters = append(probe.Chapters, gopi.MediaProbeChapter{
+			Id:    chapter.Id(),
+			Start: tb.Float(chapter.Start()),
+			End:   tb.Float(chapter.End()),
+			Tags:  probeTags(chapter.Metadata()),
+		})
+	}
+
+	// Append streams
+	for _, stream := range ctx.Streams() {
+		probe.Streams = append(probe.Streams, probeStream(stream))
+	}
+
+	// Return the report
+	return probe
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func probeStream(stream *ffmpeg.AVStream) gopi.MediaProbeStream {
+	par := stream.CodecPar()
+	result := gopi.MediaProbeStream{
+		Index:   stream.Index(),
+		Type:    probeType(par.Type()),
+		Codec:   par.Id().String(),
+		BitRate: uint(par.BitRate()),
+		Tags:    probeTags(stream.Metadata()),
+	}
+	if codec := ffmpeg.FindCodecById(par.Id()); codec != nil {
+		result.Codec = codec.Name()
+		result.Description = codec.Description()
+	}
+	if d := stream.Duration(); d > 0 {
+		result.Duration = stream.TimeBase().Float(d)
+	}
+	switch par.Type() {
+	case ffmpeg.AVMEDIA_TYPE_VIDEO:
+		result.Width, result.Height = par.Width(), par.Height()
+		if fr := stream.MeanFrameRate(); fr.Den() != 0 {
+			result.FrameRate = fr.Float(1)
+		}
+	case ffmpeg.AVMEDIA_TYPE_AUDIO:
+		result.SampleRate = par.SampleRate()
+		result.Channels = par.Channels()
+		result.ChannelLayout = ffmpeg.AVGetChannelLayoutName(par.Channels(), par.ChannelLayout())
+	}
+	return result
+}
+
+func probeType(t ffmpeg.AVMediaType) string {
+	switch t {
+	case ffmpeg.AVMEDIA_TYPE_VIDEO:
+		return "video"
+	case ffmpeg.AVMEDIA_TYPE_AUDIO:
+		return "audio"
+	case ffmpeg.AVMEDIA_TYPE_SUBTITLE:
+		return "subtitle"
+	case ffmpeg.AVMEDIA_TYPE_DATA:
+		return "data"
+	case ffmpeg.AVMEDIA_TYPE_ATTACHMENT:
+		return "attachment"
+	default:
+		return "unknown"
+	}
+}
+
+func probeTags(dict *ffmpeg.AVDictionary) map[string]string {
+	if dict == nil || dict.Count() == 0 {
+		return nil
+	}
+	tags := make(map[string]string, dict.Count())
+	for _, entry := range dict.Entries() {
+		tags[entry.Key()] = entry.Value()
+	}
+	return tags
+}
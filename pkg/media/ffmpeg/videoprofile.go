@@ -0,0 +1,171 @@
+// +build ffmpeg
+
+package ffmpeg
+
+import (
+	"fmt"
+	"os"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	ffmpeg "github.com/djthorpe/gopi/v3/pkg/sys/ffmpeg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type VideoProfile struct {
+	encoder
+	codec   *ffmpeg.AVCodec
+	w, h    uint
+	bitrate uint
+	profile string
+	level   string
+}
+
+// encoder is a named encoder for a codec, which can require a device
+// and supports profile and level options
+type encoder struct {
+	name     string
+	hardware bool
+	device   string
+	options  []string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// Encoders for each codec, in order of preference
+	encoders = map[string][]encoder{
+		"h264": {
+			{"h264_v4l2m2m", true, "/dev/video11", nil},
+			{"h264_omx", true, "", []string{"profile"}},
+			{"libx264", false, "", []string{"profile", "level"}},
+		},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// NewVideoProfile returns a profile for a codec. The named encoder is used,
+// or else the first available encoder for the codec
+func NewVideoProfile(codec, name string, w, h, bitrate uint, profile, level string) (*VideoProfile, error) {
+	this := new(VideoProfile)
+	if w == 0 || h == 0 || bitrate == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("VideoProfile")
+	} else if encoder, avcodec := selectEncoder(codec, name); avcodec == nil {
+		return nil, gopi.ErrNotFound.WithPrefix("VideoProfile: ", codec)
+	} else {
+		this.encoder = encoder
+		this.codec = avcodec
+		this.w, this.h = w, h
+		this.bitrate = bitrate
+		this.profile = profile
+		this.level = level
+	}
+
+	// Return success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *VideoProfile) Flags() gopi.MediaFlag {
+	return gopi.MEDIA_FLAG_VIDEO | gopi.MEDIA_FLAG_ENCODER
+}
+
+func (this *VideoProfile) Codec() gopi.MediaCodec {
+	return NewCodec(this.codec)
+}
+
+func (this *VideoProfile) Hardware() bool {
+	return this.encoder.hardware
+}
+
+func (this *VideoProfile) Width() uint {
+	return this.w
+}
+
+func (this *VideoProfile) Height() uint {
+	return this.h
+}
+
+func (this *VideoProfile) BitRate() uint {
+	return this.bitrate
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Options returns options for opening the encoder, which include the
+// profile and level when the encoder supports them. The dictionary
+// should be closed after use
+func (this *VideoProfile) Options() (*ffmpeg.AVDictionary, error) {
+	dict := ffmpeg.NewAVDictionary()
+	for _, option := range this.encoder.options {
+		value := ""
+		switch option {
+		case "profile":
+			value = this.profile
+		case "level":
+			value = this.level
+		}
+		if value == "" {
+			continue
+		} else if err := dict.Set(option, value, 0); err != nil {
+			dict.Close()
+			return nil, err
+		}
+	}
+	return dict, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *VideoProfile) String() string {
+	str := "<ffmpeg.profile"
+	str += fmt.Sprint(" flags=", this.Flags())
+	str += fmt.Sprintf(" encoder=%q", this.encoder.name)
+	if this.encoder.hardware {
+		str += " hardware"
+	}
+	str += fmt.Sprintf(" size={%d,%d}", this.w, this.h)
+	str += fmt.Sprint(" bitrate=", this.bitrate)
+	if this.profile != "" {
+		str += fmt.Sprintf(" profile=%q", this.profile)
+	}
+	if this.level != "" {
+		str += fmt.Sprintf(" level=%q", this.level)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// selectEncoder returns the named encoder, or the first encoder for the
+// codec which is available and has any device it requires
+func selectEncoder(codec, name string) (encoder, *ffmpeg.AVCodec) {
+	if name != "" {
+		for _, encoder := range encoders[codec] {
+			if encoder.name == name {
+				return encoder, ffmpeg.FindEncoderByName(name)
+			}
+		}
+		return encoder{name, false, "", nil}, ffmpeg.FindEncoderByName(name)
+	}
+	for _, encoder := range encoders[codec] {
+		if encoder.device != "" {
+			if _, err := os.Stat(encoder.device); err != nil {
+				continue
+			}
+		}
+		if avcodec := ffmpeg.FindEncoderByName(encoder.name); avcodec != nil {
+			return encoder, avcodec
+		}
+	}
+	return encoder{}, nil
+}
@@ -0,0 +1,131 @@
+package ts
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Service returns the service type, provider name and service name
+// from a service descriptor
+func (t DTable) Service() (uint8, string, string, bool) {
+	data := t.data(service)
+	if len(data) < 2 {
+		return 0, "", "", false
+	}
+	provider, rest := readString(data[1:])
+	name, _ := readString(rest)
+	return data[0], DecodeString(provider), DecodeString(name), true
+}
+
+// ShortEvent returns the language, event name and description from
+// a short event descriptor
+func (t DTable) ShortEvent() (string, string, string, bool) {
+	data := t.data(short_event)
+	if len(data) < 4 {
+		return "", "", "", false
+	}
+	name, rest := readString(data[3:])
+	text, _ := readString(rest)
+	return string(data[0:3]), DecodeString(name), DecodeString(text), true
+}
+
+// ExtendedEvent returns the description concatenated from extended
+// event descriptors, ignoring any itemised information
+func (t DTable) ExtendedEvent() string {
+	var text []byte
+	for _, row := range t.Rows {
+		if row.header.Tag != extended_event || len(row.data) < 5 {
+			continue
+		}
+		// Skip descriptor numbers, language and items
+		items := int(row.data[4])
+		if len(row.data) < 6+items {
+			continue
+		}
+		if str, _ := readString(row.data[5+items:]); str != nil {
+			// Character table is only present in the first descriptor
+			if len(text) > 0 && len(str) > 0 && str[0] < 0x20 {
+				str = str[1:]
+			}
+			text = append(text, str...)
+		}
+	}
+	return DecodeString(text)
+}
+
+// DecodeString returns a string from text encoded with a DVB character
+// table. UTF-8 is decoded, other tables are treated as Latin-1 and
+// control codes are removed
+func DecodeString(data []byte) string {
+	utf := false
+	if len(data) > 0 && data[0] < 0x20 {
+		switch data[0] {
+		case 0x10:
+			data = data[min(3, len(data)):]
+		case 0x15:
+			utf, data = true, data[1:]
+		case 0x1F:
+			data = data[min(2, len(data)):]
+		default:
+			data = data[1:]
+		}
+	}
+
+	var str strings.Builder
+	if utf {
+		for len(data) > 0 {
+			r, size := utf8.DecodeRune(data)
+			if r == 0xE08A {
+				str.WriteRune('\n')
+			} else if r != utf8.RuneError && (r < 0xE080 || r > 0xE09F) {
+				str.WriteRune(r)
+			}
+			data = data[size:]
+		}
+	} else {
+		for _, b := range data {
+			if b == 0x8A {
+				str.WriteRune('\n')
+			} else if b >= 0x20 && (b < 0x80 || b > 0x9F) {
+				str.WriteRune(rune(b))
+			}
+		}
+	}
+	return strings.TrimSpace(str.String())
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// data returns data for the first descriptor with a tag, or nil
+func (t DTable) data(tag Tag) []byte {
+	for _, row := range t.Rows {
+		if row.header.Tag == tag {
+			return row.data
+		}
+	}
+	return nil
+}
+
+// readString returns a length-prefixed string and the remaining data
+func readString(data []byte) ([]byte, []byte) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	n := int(data[0])
+	if n > len(data)-1 {
+		n = len(data) - 1
+	}
+	return data[1 : n+1], data[n+1:]
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	} else {
+		return b
+	}
+}
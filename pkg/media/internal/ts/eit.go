@@ -0,0 +1,134 @@
+package ts
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// EITSection contains events for a service, the service identifier
+// is the header identifier
+type EITSection struct {
+	Header
+	TransportStreamId uint16
+	NetworkId         uint16
+	LastSegment       uint8
+	LastTableId       TableType
+	Events            []EITEvent
+}
+
+type EITEvent struct {
+	EventId  uint16
+	Start    time.Time // Zero if undefined
+	Duration time.Duration
+	DTable
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// mjdEpoch is the start of Modified Julian Date day zero
+	mjdEpoch = time.Date(1858, time.November, 17, 0, 0, 0, 0, time.UTC)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (s *EITSection) Read(r io.Reader, length int) error {
+	if err := s.Header.Read(r); err != nil {
+		return err
+	} else if err := binary.Read(r, binary.BigEndian, &s.TransportStreamId); err != nil {
+		return err
+	} else if err := binary.Read(r, binary.BigEndian, &s.NetworkId); err != nil {
+		return err
+	} else if err := binary.Read(r, binary.BigEndian, &s.LastSegment); err != nil {
+		return err
+	} else if err := binary.Read(r, binary.BigEndian, &s.LastTableId); err != nil {
+		return err
+	}
+
+	// Read events until length is zero
+	for length -= headerSize + 6; length >= 12; {
+		var data [12]byte
+		if _, err := io.ReadFull(r, data[:]); err != nil {
+			return err
+		}
+		n := int(binary.BigEndian.Uint16(data[10:]) & 0x0FFF)
+		if length < 12+n {
+			return gopi.ErrUnexpectedResponse.WithPrefix("EIT")
+		}
+		row := EITEvent{
+			EventId:  binary.BigEndian.Uint16(data[0:]),
+			Start:    decodeTime(data[2:7]),
+			Duration: decodeDuration(data[7:10]),
+		}
+		if err := row.DTable.Read(r, uint16(n)); err != nil {
+			return err
+		}
+		s.Events = append(s.Events, row)
+		length -= 12 + n
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// decodeTime returns time from a 16-bit Modified Julian Date
+// followed by hours, minutes and seconds in BCD
+func decodeTime(data []byte) time.Time {
+	if data[0] == 0xFF && data[1] == 0xFF {
+		return time.Time{}
+	}
+	mjd := int(binary.BigEndian.Uint16(data))
+	return mjdEpoch.AddDate(0, 0, mjd).Add(decodeDuration(data[2:5]))
+}
+
+// decodeDuration returns a duration from hours, minutes and seconds
+// in BCD
+func decodeDuration(data []byte) time.Duration {
+	h := time.Duration(decodeBCD(data[0])) * time.Hour
+	m := time.Duration(decodeBCD(data[1])) * time.Minute
+	s := time.Duration(decodeBCD(data[2])) * time.Second
+	return h + m + s
+}
+
+func decodeBCD(v uint8) int {
+	return int(v>>4)*10 + int(v&0x0F)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s EITSection) String() string {
+	str := "<eit"
+	str += fmt.Sprint(" ", s.Header)
+	str += fmt.Sprintf(" ts_id=0x%04X network_id=0x%04X", s.TransportStreamId, s.NetworkId)
+	for _, event := range s.Events {
+		str += fmt.Sprint(" ", event)
+	}
+	return str + ">"
+}
+
+func (e EITEvent) String() string {
+	str := fmt.Sprintf("<event_id=0x%04X", e.EventId)
+	if e.Start.IsZero() == false {
+		str += fmt.Sprint(" start=", e.Start.Format(time.RFC3339))
+	}
+	if e.Duration != 0 {
+		str += fmt.Sprint(" duration=", e.Duration)
+	}
+	if _, name, _, ok := e.DTable.ShortEvent(); ok {
+		str += fmt.Sprintf(" name=%q", name)
+	}
+	return str + ">"
+}
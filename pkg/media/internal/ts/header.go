@@ -16,6 +16,14 @@ type Header struct {
 	LastSection uint8
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// headerSize is the number of bytes in the extended section header
+	headerSize = 5
+)
+
 ////////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
@@ -28,7 +28,7 @@ func (p *PATSection) Read(r io.Reader, length int) error {
 	}
 
 	// Read entries until length is 0
-	for length > 0 {
+	for length -= headerSize; length >= 4; {
 		var row PATProgram
 		if err := binary.Read(r, binary.BigEndian, &row); err != nil {
 			return err
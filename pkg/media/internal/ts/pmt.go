@@ -45,7 +45,9 @@ func (s *PMTSection) Read(r io.Reader, length int) error {
 	}
 
 	/* Read elementary streams */
-	if err := s.ESTable.Read(r, uint16(length)-(s.Length+4)); err != nil {
+	if length < headerSize+4+int(s.Length) {
+		return gopi.ErrUnexpectedResponse.WithPrefix("PMT")
+	} else if err := s.ESTable.Read(r, uint16(length-headerSize-4-int(s.Length))); err != nil {
 		return err
 	}
 
@@ -0,0 +1,88 @@
+package ts
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type SDTSection struct {
+	Header
+	NetworkId uint16
+	Services  []SDTService
+}
+
+type SDTService struct {
+	header struct {
+		ServiceId uint16
+		Flags     uint8  // EIT schedule and present/following flags
+		Status    uint16 // Running status, free CA flag and descriptor length
+	}
+	DTable
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (s *SDTSection) Read(r io.Reader, length int) error {
+	var reserved uint8
+	if err := s.Header.Read(r); err != nil {
+		return err
+	} else if err := binary.Read(r, binary.BigEndian, &s.NetworkId); err != nil {
+		return err
+	} else if err := binary.Read(r, binary.BigEndian, &reserved); err != nil {
+		return err
+	}
+
+	// Read services until length is zero
+	for length -= headerSize + 3; length >= 5; {
+		var row SDTService
+		if err := binary.Read(r, binary.BigEndian, &row.header); err != nil {
+			return err
+		}
+		n := int(row.header.Status & 0x0FFF)
+		if length < 5+n {
+			return gopi.ErrUnexpectedResponse.WithPrefix("SDT")
+		} else if err := row.DTable.Read(r, uint16(n)); err != nil {
+			return err
+		}
+		s.Services = append(s.Services, row)
+		length -= 5 + n
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (s SDTService) ServiceId() uint16 {
+	return s.header.ServiceId
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s SDTSection) String() string {
+	str := "<sdt"
+	str += fmt.Sprint(" ", s.Header)
+	str += fmt.Sprintf(" network_id=0x%04X", s.NetworkId)
+	for _, service := range s.Services {
+		str += fmt.Sprint(" ", service)
+	}
+	return str + ">"
+}
+
+func (s SDTService) String() string {
+	str := fmt.Sprintf("<service_id=0x%04X", s.ServiceId())
+	if _, provider, name, ok := s.DTable.Service(); ok {
+		str += fmt.Sprintf(" provider=%q name=%q", provider, name)
+	}
+	return str + ">"
+}
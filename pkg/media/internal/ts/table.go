@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+
+	"github.com/djthorpe/gopi/v3"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -87,11 +89,15 @@ func (t *DTable) Read(r io.Reader, length uint16) error {
 	// Read rows until length is zero
 	for i := length; i > 0; {
 		var row DRow
-		if err := binary.Read(r, binary.BigEndian, &row.header); err != nil {
+		if i < 2 {
+			return gopi.ErrUnexpectedResponse.WithPrefix("DTable")
+		} else if err := binary.Read(r, binary.BigEndian, &row.header); err != nil {
 			return err
+		} else if i < 2+uint16(row.header.Length) {
+			return gopi.ErrUnexpectedResponse.WithPrefix("DTable")
 		}
 		row.data = make([]byte, int(row.header.Length))
-		if _, err := r.Read(row.data); err != nil {
+		if _, err := io.ReadFull(r, row.data); err != nil {
 			return err
 		}
 		// Append row, decrement by 2 bytes and length of data
@@ -105,8 +111,7 @@ func (t *DTable) Read(r io.Reader, length uint16) error {
 
 func (t *ESTable) Read(r io.Reader, length uint16) error {
 	// Read rows until length is zero
-	fmt.Println("estable length=", length)
-	for i := length; i > 5; {
+	for i := length; i >= 5; {
 		var row ESRow
 		if err := binary.Read(r, binary.BigEndian, &row.header); err != nil {
 			return err
@@ -114,28 +119,43 @@ func (t *ESTable) Read(r io.Reader, length uint16) error {
 			row.header.Pid &= 0x1FFF    // 13 bits
 			row.header.Length &= 0x0FFF // 12 bits
 		}
-
-		fmt.Println("  esrow type=", row.header.ESType)
-		fmt.Printf("        pid=0x%04X\n", row.header.Pid)
-		fmt.Println("        length=", row.header.Length)
-
+		if i < row.header.Length+5 {
+			return gopi.ErrUnexpectedResponse.WithPrefix("ESTable")
+		}
 		if row.header.Length > 0 {
 			if err := row.DTable.Read(r, row.header.Length); err != nil {
 				return err
 			}
-			fmt.Println("        desc=", row.DTable)
 		}
 
 		// Append row, decrement by 5 bytes and length of data
 		t.Rows = append(t.Rows, row)
 		i -= (row.header.Length + 5)
-		fmt.Println("        remaining=", i)
 	}
 
 	// Return success
 	return nil
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (r DRow) Tag() Tag {
+	return r.header.Tag
+}
+
+func (r DRow) Data() []byte {
+	return r.data
+}
+
+func (r ESRow) Type() ESType {
+	return r.header.ESType
+}
+
+func (r ESRow) Pid() uint16 {
+	return r.header.Pid
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
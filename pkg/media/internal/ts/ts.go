@@ -30,6 +30,8 @@ type Section struct {
 	PATSection
 	PMTSection
 	NITSection
+	SDTSection
+	EITSection
 	crc uint32
 }
 
@@ -47,7 +49,13 @@ const (
 	BAT       TableType = 0x4A
 	EIT       TableType = 0x4E
 	EIT_OTHER TableType = 0x4F
-	TDT       TableType = 0x70
+	// EIT schedule tables are in the range 0x50 to 0x5F
+	EIT_SCHEDULE TableType = 0x50
+	// EIT schedule tables for other transport streams are in
+	// the range 0x60 to 0x6F
+	EIT_SCHEDULE_OTHER TableType = 0x60
+	EIT_MAX            TableType = 0x6F
+	TDT                TableType = 0x70
 )
 
 const (
@@ -77,6 +85,17 @@ const (
 	SECTION_BUFFER_SIZE = 4096
 )
 
+const (
+	// Well-known PIDs for service information
+	PID_PAT = 0x0000
+	PID_NIT = 0x0010
+	PID_SDT = 0x0011
+	PID_EIT = 0x0012
+	// PID_NULL is used for null packets, and as the PCR PID when
+	// there is no clock reference
+	PID_NULL = 0x1FFF
+)
+
 ////////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS
 
@@ -97,19 +116,28 @@ func NewSection(r io.Reader, data []byte) (*Section, error) {
 		return nil, gopi.ErrUnexpectedResponse.WithPrefix("NewSection")
 	}
 
-	// Read section data
-	r2 := bytes.NewReader(data)
-	switch this.TableId {
-	case PAT:
-		if err := this.PATSection.Read(r2, int(this.SectionHeader.Length)-4); err != nil {
+	// Read section data, excluding the CRC
+	r2 := bytes.NewReader(data[:this.SectionHeader.Length])
+	length := int(this.SectionHeader.Length) - 4
+	switch {
+	case this.TableId == PAT:
+		if err := this.PATSection.Read(r2, length); err != nil {
 			return nil, err
 		}
-	case PMT:
-		if err := this.PMTSection.Read(r2, int(this.SectionHeader.Length)-4); err != nil {
+	case this.TableId == PMT:
+		if err := this.PMTSection.Read(r2, length); err != nil {
+			return nil, err
+		}
+	case this.TableId == NIT, this.TableId == NIT_OTHER:
+		if err := this.NITSection.Read(r2, length); err != nil {
 			return nil, err
 		}
-	case NIT, NIT_OTHER:
-		if err := this.NITSection.Read(r2, int(this.SectionHeader.Length)-4); err != nil {
+	case this.TableId == SDT, this.TableId == SDT_OTHER:
+		if err := this.SDTSection.Read(r2, length); err != nil {
+			return nil, err
+		}
+	case this.TableId.IsEIT():
+		if err := this.EITSection.Read(r2, length); err != nil {
 			return nil, err
 		}
 	}
@@ -123,19 +151,28 @@ func NewSection(r io.Reader, data []byte) (*Section, error) {
 	return this, nil
 }
 
+// IsEIT returns true if the table is an event information table
+func (f TableType) IsEIT() bool {
+	return f >= EIT && f <= EIT_MAX
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
 func (this *Section) String() string {
 	str := "<dvb.section"
 	str += " table_id=" + fmt.Sprint(this.TableId)
-	switch this.TableId {
-	case PAT:
+	switch {
+	case this.TableId == PAT:
 		str += " " + fmt.Sprint(this.PATSection)
-	case PMT:
+	case this.TableId == PMT:
 		str += " " + fmt.Sprint(this.PMTSection)
-	case NIT, NIT_OTHER:
+	case this.TableId == NIT, this.TableId == NIT_OTHER:
 		str += " " + fmt.Sprint(this.NITSection)
+	case this.TableId == SDT, this.TableId == SDT_OTHER:
+		str += " " + fmt.Sprint(this.SDTSection)
+	case this.TableId.IsEIT():
+		str += " " + fmt.Sprint(this.EITSection)
 	default:
 		str += " length=" + fmt.Sprint(this.SectionHeader.Length)
 	}
@@ -164,6 +201,10 @@ func (f TableType) String() string {
 		return "EIT"
 	case EIT_OTHER:
 		return "EIT_OTHER"
+	case EIT_SCHEDULE:
+		return "EIT_SCHEDULE"
+	case EIT_SCHEDULE_OTHER:
+		return "EIT_SCHEDULE_OTHER"
 	case TDT:
 		return "TDT"
 	default:
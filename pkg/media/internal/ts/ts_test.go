@@ -0,0 +1,125 @@
+package ts_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	ts "github.com/djthorpe/gopi/v3/pkg/media/internal/ts"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_TS_001(t *testing.T) {
+	// PAT with NIT and one program
+	section, err := newSection(ts.PAT, []byte{
+		0x00, 0x01, 0xC1, 0x00, 0x00,
+		0x00, 0x00, 0xE0, 0x10,
+		0x10, 0x44, 0xE1, 0x00,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if programs := section.PATSection.Programs; len(programs) != 2 {
+		t.Error("Unexpected programs", programs)
+	} else if programs[0].Program != 0 || programs[0].Pid != 0x0010 {
+		t.Error("Unexpected NIT", programs[0])
+	} else if programs[1].Program != 0x1044 || programs[1].Pid != 0x0100 {
+		t.Error("Unexpected program", programs[1])
+	} else {
+		t.Log(section)
+	}
+}
+
+func Test_TS_002(t *testing.T) {
+	// SDT with one service
+	desc := append([]byte{0x48, 0x0D, 0x01, 0x03}, "BBC"...)
+	desc = append(desc, 0x07)
+	desc = append(desc, "BBC ONE"...)
+	body := []byte{
+		0x00, 0x01, 0xC1, 0x00, 0x00,
+		0x23, 0x3A, 0xFF,
+		0x10, 0x44, 0xFD, 0x80, byte(len(desc)),
+	}
+	section, err := newSection(ts.SDT, append(body, desc...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if services := section.SDTSection.Services; len(services) != 1 {
+		t.Error("Unexpected services", services)
+	} else if services[0].ServiceId() != 0x1044 {
+		t.Error("Unexpected service id", services[0])
+	} else if typ, provider, name, ok := services[0].Service(); ok == false {
+		t.Error("Missing service descriptor")
+	} else if typ != 0x01 || provider != "BBC" || name != "BBC ONE" {
+		t.Error("Unexpected service descriptor", typ, provider, name)
+	} else {
+		t.Log(section)
+	}
+}
+
+func Test_TS_003(t *testing.T) {
+	// EIT with one event, example date from EN 300 468 Annex C
+	desc := append([]byte{0x4D, 0x12}, "eng"...)
+	desc = append(desc, 0x04)
+	desc = append(desc, "News"...)
+	desc = append(desc, 0x09)
+	desc = append(desc, "Headlines"...)
+	body := []byte{
+		0x10, 0x44, 0xC1, 0x00, 0x00,
+		0x00, 0x01, 0x23, 0x3A, 0x00, 0x4E,
+		0x00, 0x01, 0xC0, 0x79, 0x12, 0x45, 0x00, 0x01, 0x45, 0x30, 0x80, byte(len(desc)),
+	}
+	section, err := newSection(ts.EIT, append(body, desc...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if section.EITSection.Header.Id != 0x1044 {
+		t.Error("Unexpected service id", section.EITSection.Header)
+	}
+	if events := section.EITSection.Events; len(events) != 1 {
+		t.Error("Unexpected events", events)
+	} else if start := time.Date(1993, time.October, 13, 12, 45, 0, 0, time.UTC); events[0].Start.Equal(start) == false {
+		t.Error("Unexpected start", events[0].Start)
+	} else if events[0].Duration != time.Hour+45*time.Minute+30*time.Second {
+		t.Error("Unexpected duration", events[0].Duration)
+	} else if lang, name, text, ok := events[0].ShortEvent(); ok == false {
+		t.Error("Missing short event descriptor")
+	} else if lang != "eng" || name != "News" || text != "Headlines" {
+		t.Error("Unexpected short event", lang, name, text)
+	} else {
+		t.Log(section)
+	}
+}
+
+func Test_TS_004(t *testing.T) {
+	tests := []struct {
+		data []byte
+		str  string
+	}{
+		{[]byte("Hello"), "Hello"},
+		{[]byte{0x05, 'A', 0x86, 'B', 0x87}, "AB"},
+		{[]byte{'A', 0x8A, 'B'}, "A\nB"},
+		{[]byte{0x15, 0xC3, 0xA9, 't', 0xC3, 0xA9}, "été"},
+		{[]byte{0xE9}, "é"},
+		{nil, ""},
+	}
+	for _, test := range tests {
+		if str := ts.DecodeString(test.data); str != test.str {
+			t.Errorf("Expected %q, got %q", test.str, str)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// newSection returns a section from a table body, appending a CRC
+// which isn't checked
+func newSection(tid ts.TableType, body []byte) (*ts.Section, error) {
+	length := len(body) + 4
+	data := append([]byte{byte(tid), 0xB0 | byte(length>>8), byte(length)}, body...)
+	data = append(data, 0x00, 0x00, 0x00, 0x00)
+	return ts.NewSection(bytes.NewReader(data), make([]byte, ts.SECTION_BUFFER_SIZE))
+}
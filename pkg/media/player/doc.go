@@ -0,0 +1,5 @@
+// Player package plays media from a URL by running an external media
+// player such as mpv under the process supervisor. The -player.path
+// and -player.args flags set the player and its arguments, where the
+// URL is appended to the arguments.
+package player
@@ -0,0 +1,13 @@
+package player
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register player
+	graph.RegisterUnit(reflect.TypeOf(&player{}), reflect.TypeOf((*gopi.MediaPlayer)(nil)))
+}
@@ -0,0 +1,126 @@
+package player
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type player struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.ProcessManager
+	sync.Mutex
+
+	path    *string
+	args    *string
+	url     string
+	process gopi.Process
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	processName = "player"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *player) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagString("player.path", "mpv", "Media player executable")
+	this.args = cfg.FlagString("player.args", "--fs --really-quiet", "Media player arguments")
+	return nil
+}
+
+func (this *player) New(gopi.Config) error {
+	this.Require(this.Logger, this.ProcessManager)
+
+	if *this.path == "" {
+		return gopi.ErrBadParameter.WithPrefix("-player.path")
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *player) Dispose() error {
+	return this.Stop()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *player) String() string {
+	str := "<player"
+	str += fmt.Sprintf(" path=%q", *this.path)
+	if url := this.URL(); url != "" {
+		str += fmt.Sprintf(" url=%q", url)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *player) Play(media string) error {
+	// Check parameters
+	if u, err := url.Parse(media); err != nil || u.Scheme == "" {
+		return gopi.ErrBadParameter.WithPrefix("Play: ", media)
+	}
+
+	// Stop any current media
+	if err := this.Stop(); err != nil {
+		return err
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Start the player
+	args := append(strings.Fields(*this.args), media)
+	if process, err := this.ProcessManager.Start(processName, gopi.PROCESS_RESTART_NEVER, gopi.ProcessLimits{}, *this.path, args...); err != nil {
+		return err
+	} else {
+		this.process, this.url = process, media
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *player) Stop() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Remove the process whether or not it is still running. The
+	// process is not found when the manager has already stopped it
+	if this.process != nil {
+		if err := this.ProcessManager.Stop(this.process); err != nil && errors.Is(err, gopi.ErrNotFound) == false {
+			return err
+		}
+	}
+
+	// Return success
+	this.process, this.url = nil, ""
+	return nil
+}
+
+func (this *player) URL() string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.process == nil || this.process.State() != gopi.PROCESS_STATE_RUNNING {
+		return ""
+	} else {
+		return this.url
+	}
+}
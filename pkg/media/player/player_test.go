@@ -0,0 +1,71 @@
+package player_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/media/player"
+	_ "github.com/djthorpe/gopi/v3/pkg/process"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.MediaPlayer
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Player_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.MediaPlayer == nil {
+			t.Error("nil MediaPlayer unit")
+		} else {
+			t.Log(app.MediaPlayer)
+		}
+	})
+}
+
+func Test_Player_002(t *testing.T) {
+	// Use a script as the player, which ignores the URL argument
+	script := filepath.Join(t.TempDir(), "player.sh")
+	if err := ioutil.WriteFile(script, []byte("#!/bin/sh\nexec sleep 10\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"-player.path=" + script, "-player.args="}
+	tool.Test(t, args, new(App), func(app *App) {
+		if err := app.MediaPlayer.Play("http://localhost/media.mp4"); err != nil {
+			t.Error(err)
+			return
+		}
+		deadline := time.Now().Add(time.Second)
+		for app.MediaPlayer.URL() == "" && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if url := app.MediaPlayer.URL(); url != "http://localhost/media.mp4" {
+			t.Errorf("Unexpected URL %q", url)
+		}
+		if err := app.MediaPlayer.Stop(); err != nil {
+			t.Error(err)
+		} else if url := app.MediaPlayer.URL(); url != "" {
+			t.Errorf("Unexpected URL %q", url)
+		}
+		if err := app.MediaPlayer.Play("not a url"); err == nil {
+			t.Error("Expected error for bad URL")
+		}
+	})
+}
@@ -0,0 +1,8 @@
+// Recorder package writes packets from a media input into rolling
+// segment files, for PVR and CCTV use. Recording is started and stopped
+// by a schedule or triggered, for example when motion is detected in
+// -recorder.motion percent of the frame, and segments older than
+// -recorder.maxage or exceeding -recorder.maxsize in total are removed.
+// Segments are written in the -recorder.path folder in MPEG-TS or MP4
+// format using the media manager.
+package recorder
@@ -0,0 +1,47 @@
+package recorder
+
+import (
+	"fmt"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	path     string
+	start    time.Time
+	duration time.Duration
+	size     int64
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.path
+}
+
+func (this *event) Start() time.Time {
+	return this.start
+}
+
+func (this *event) Duration() time.Duration {
+	return this.duration
+}
+
+func (this *event) Size() int64 {
+	return this.size
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<recorder.event"
+	str += fmt.Sprintf(" path=%q", this.path)
+	str += " start=" + this.start.Format(time.RFC3339)
+	str += " duration=" + fmt.Sprint(this.duration.Truncate(time.Millisecond))
+	str += " size=" + fmt.Sprint(this.size)
+	return str + ">"
+}
@@ -0,0 +1,13 @@
+package recorder
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register recorder
+	graph.RegisterUnit(reflect.TypeOf(&recorder{}), reflect.TypeOf((*gopi.MediaRecorder)(nil)))
+}
@@ -0,0 +1,69 @@
+package recorder
+
+import (
+	"image"
+	"image/color"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// sample is the luma of a video frame at points on a grid, which is
+// copied from the decoder so that it can be compared in the background
+type sample []uint8
+
+// detector compares samples of frames, and detects motion when the
+// percentage of points which have changed exceeds a threshold
+type detector struct {
+	threshold float64
+	last      sample
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Size of the grid of points sampled in each frame
+	sampleWidth  = 32
+	sampleHeight = 24
+
+	// Change in luma at a point which counts as motion
+	sampleDelta = 32
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// newSample returns the luma at the centre of each cell of the grid
+func newSample(frame image.Image) sample {
+	bounds := frame.Bounds()
+	if bounds.Empty() {
+		return nil
+	}
+	result := make(sample, 0, sampleWidth*sampleHeight)
+	for y := 0; y < sampleHeight; y++ {
+		py := bounds.Min.Y + (2*y+1)*bounds.Dy()/(2*sampleHeight)
+		for x := 0; x < sampleWidth; x++ {
+			px := bounds.Min.X + (2*x+1)*bounds.Dx()/(2*sampleWidth)
+			result = append(result, color.GrayModel.Convert(frame.At(px, py)).(color.Gray).Y)
+		}
+	}
+	return result
+}
+
+// Detect compares a sample with the last sample, and returns true when
+// there is motion. The first sample has no motion
+func (this *detector) Detect(s sample) bool {
+	last := this.last
+	this.last = s
+	if len(last) != len(s) || len(s) == 0 {
+		return false
+	}
+	changed := 0
+	for i := range s {
+		if delta := int(s[i]) - int(last[i]); delta >= sampleDelta || delta <= -sampleDelta {
+			changed++
+		}
+	}
+	return float64(changed)*100 >= this.threshold*float64(len(s))
+}
@@ -0,0 +1,350 @@
+package recorder
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	pipeline "github.com/djthorpe/gopi/v3/pkg/pipeline"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type recorder struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.MediaManager
+	gopi.Publisher
+	sync.Mutex
+
+	path     *string
+	prefix   *string
+	format   *string
+	duration *time.Duration
+	maxage   *time.Duration
+	maxsize  *uint
+	motion   *float64
+	trigger  *time.Duration
+
+	schedule schedule
+	segment  *segment
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// deltaRetention is the interval between removing old segments
+	deltaRetention = time.Minute
+
+	// minDuration is the shortest segment duration
+	minDuration = time.Second
+
+	// motionQueue is the number of frames queued for motion detection.
+	// The oldest frame is dropped when the queue is full, so that motion
+	// detection does not stall recording
+	motionQueue = 4
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *recorder) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagString("recorder.path", "", "Folder for recorded segments")
+	this.prefix = cfg.FlagString("recorder.prefix", "rec-", "Filename prefix for recorded segments")
+	this.format = cfg.FlagString("recorder.format", "ts", "Segment format (ts, mp4)")
+	this.duration = cfg.FlagDuration("recorder.segment", 5*time.Minute, "Segment duration")
+	this.maxage = cfg.FlagDuration("recorder.maxage", 0, "Remove segments older than duration")
+	this.maxsize = cfg.FlagUint("recorder.maxsize", 0, "Remove oldest segments when total size exceeds megabytes")
+	this.motion = cfg.FlagFloat("recorder.motion", 0, "Trigger recording when percentage of frame changes, or zero to disable")
+	this.trigger = cfg.FlagDuration("recorder.trigger", 30*time.Second, "Recording duration when motion is detected")
+	return nil
+}
+
+func (this *recorder) New(gopi.Config) error {
+	this.Require(this.Logger, this.MediaManager)
+
+	// Check format and segment duration
+	switch *this.format {
+	case "ts", "mp4":
+		break
+	default:
+		return gopi.ErrBadParameter.WithPrefix("-recorder.format")
+	}
+	if *this.duration < minDuration {
+		return gopi.ErrBadParameter.WithPrefix("-recorder.segment")
+	}
+	if *this.motion < 0 || *this.motion > 100 {
+		return gopi.ErrBadParameter.WithPrefix("-recorder.motion")
+	} else if *this.motion > 0 && *this.trigger <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-recorder.trigger")
+	}
+
+	// Use the temporary folder if path is not set
+	if *this.path == "" {
+		*this.path = os.TempDir()
+	}
+	if stat, err := os.Stat(*this.path); err != nil {
+		return err
+	} else if stat.IsDir() == false {
+		return gopi.ErrBadParameter.WithPrefix("-recorder.path")
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *recorder) Run(ctx context.Context) error {
+	timer := time.NewTicker(deltaRetention)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			this.Mutex.Lock()
+			if err := this.retain(); err != nil {
+				this.Print("Recorder: ", err)
+			}
+			this.Mutex.Unlock()
+		}
+	}
+}
+
+func (this *recorder) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Close any segment being written
+	return this.closeSegment()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *recorder) String() string {
+	str := "<recorder"
+	str += fmt.Sprintf(" path=%q", filepath.Join(*this.path, *this.prefix+"*."+*this.format))
+	str += " segment=" + fmt.Sprint(*this.duration)
+	if *this.maxage > 0 {
+		str += " maxage=" + fmt.Sprint(*this.maxage)
+	}
+	if *this.maxsize > 0 {
+		str += " maxsize=" + strconv.FormatUint(uint64(*this.maxsize), 10) + "MB"
+	}
+	if *this.motion > 0 {
+		str += " motion=" + fmt.Sprint(*this.motion, "%")
+	}
+	str += " recording=" + fmt.Sprint(this.Recording())
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *recorder) Record(ctx context.Context, in gopi.MediaInput) error {
+	if in == nil {
+		return gopi.ErrBadParameter.WithPrefix("Record")
+	}
+
+	// Record audio and video streams. Segments are cut on video key
+	// frames, or any key frame if there is no video
+	streams := in.StreamsForFlag(gopi.MEDIA_FLAG_VIDEO | gopi.MEDIA_FLAG_AUDIO)
+	if len(streams) == 0 {
+		return gopi.ErrNotFound.WithPrefix("Record: No audio or video streams")
+	}
+	video := in.StreamsForFlag(gopi.MEDIA_FLAG_VIDEO)
+
+	// Close any segment on exit
+	defer func() {
+		this.Mutex.Lock()
+		defer this.Mutex.Unlock()
+		if err := this.closeSegment(); err != nil {
+			this.Print("Record: ", err)
+		}
+	}()
+
+	// Packets are written as they are read from the camera, since the
+	// demuxer reuses them. When motion detection is enabled, frames of
+	// the first video stream are decoded and sampled, and compared in
+	// the background. Motion triggers recording
+	p := pipeline.New()
+	camera := p.Source("camera", func(ctx context.Context, emit pipeline.EmitFunc) error {
+		return in.Read(ctx, streams, func(dctx gopi.MediaDecodeContext, packet gopi.MediaPacket) error {
+			now := time.Now()
+			stream := dctx.Stream()
+			cut := packet.IsKeyFrame() && (len(video) == 0 || stream.Flags()&gopi.MEDIA_FLAG_VIDEO != 0)
+			if err := this.write(now, this.schedule.Active(now), cut, dctx, packet); err != nil {
+				return err
+			} else if *this.motion == 0 || len(video) == 0 || stream.Index() != video[0] {
+				return nil
+			}
+			return in.DecodeFrameIterator(dctx, packet, func(frame gopi.MediaFrame) error {
+				return emit(newSample(frame))
+			})
+		})
+	})
+	if *this.motion > 0 {
+		detector := &detector{threshold: *this.motion}
+		queue := pipeline.Queue{Size: motionQueue, Policy: pipeline.DROP_OLDEST}
+		camera.Transform("motion", queue, func(_ context.Context, item interface{}) (interface{}, error) {
+			if detector.Detect(item.(sample)) {
+				return item, nil
+			}
+			return nil, nil
+		}).Sink("trigger", queue, func(context.Context, interface{}) error {
+			this.Debug("Record: Motion detected")
+			this.Trigger(*this.trigger)
+			return nil
+		})
+	}
+
+	err := p.Run(ctx)
+	this.Debug(p)
+	return err
+}
+
+func (this *recorder) Schedule(start, stop time.Time) error {
+	if stop.After(start) == false || stop.Before(time.Now()) {
+		return gopi.ErrBadParameter.WithPrefix("Schedule")
+	}
+	this.schedule.Add(start, stop)
+	return nil
+}
+
+func (this *recorder) Trigger(d time.Duration) {
+	this.schedule.Trigger(time.Now().Add(d))
+}
+
+func (this *recorder) Cancel() {
+	this.schedule.Reset()
+}
+
+func (this *recorder) Recording() bool {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.segment != nil
+}
+
+func (this *recorder) Segments() []string {
+	segments, err := listSegments(*this.path, *this.prefix, *this.format)
+	if err != nil {
+		this.Debug("Segments: ", err)
+		return nil
+	}
+	result := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		result = append(result, filepath.Join(*this.path, segment.Name()))
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// write a packet, closing the current segment when recording is no
+// longer active or the segment duration has been reached, and opening
+// a segment when recording is active. Segments are only closed and
+// opened at packets which can be cut
+func (this *recorder) write(now time.Time, active, cut bool, ctx gopi.MediaDecodeContext, packet gopi.MediaPacket) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Close segment
+	if this.segment != nil && cut {
+		if active == false || now.Sub(this.segment.start) >= *this.duration {
+			if err := this.closeSegment(); err != nil {
+				this.Print("Record: ", err)
+			}
+		}
+	}
+
+	// Open segment
+	if this.segment == nil && active && cut {
+		path := segmentPath(*this.path, *this.prefix, *this.format, now)
+		if out, err := this.MediaManager.CreateFile(path); err != nil {
+			return err
+		} else {
+			this.segment = &segment{out, path, now, now}
+		}
+	}
+
+	// Write packet
+	if this.segment == nil {
+		return nil
+	} else if err := this.segment.Write(ctx, packet); err != nil {
+		return err
+	} else {
+		this.segment.last = now
+	}
+
+	// Return success
+	return nil
+}
+
+// closeSegment closes the current segment, emits an event and
+// removes old segments
+func (this *recorder) closeSegment() error {
+	if this.segment == nil {
+		return nil
+	}
+
+	var result error
+	segment := this.segment
+	this.segment = nil
+	if err := this.MediaManager.Close(segment.MediaOutput); err != nil {
+		result = multierror.Append(result, err)
+	} else if stat, err := os.Stat(segment.path); err != nil {
+		result = multierror.Append(result, err)
+	} else if this.Publisher != nil {
+		if err := this.Publisher.Emit(&event{segment.path, segment.start, segment.last.Sub(segment.start), stat.Size()}, false); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Remove old segments
+	if err := this.retain(); err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	// Return any errors
+	return result
+}
+
+// retain removes segments according to the retention policy, except
+// for any segment being written
+func (this *recorder) retain() error {
+	if *this.maxage == 0 && *this.maxsize == 0 {
+		return nil
+	}
+
+	var before time.Time
+	if *this.maxage > 0 {
+		before = time.Now().Add(-*this.maxage)
+	}
+	segments, err := listSegments(*this.path, *this.prefix, *this.format)
+	if err != nil {
+		return err
+	}
+	if this.segment != nil {
+		for i, segment := range segments {
+			if filepath.Join(*this.path, segment.Name()) == this.segment.path {
+				segments = append(segments[:i], segments[i+1:]...)
+				break
+			}
+		}
+	}
+	removed, err := retainSegments(*this.path, segments, before, int64(*this.maxsize)<<20)
+	for _, path := range removed {
+		this.Debug("Removed: ", path)
+	}
+	return err
+}
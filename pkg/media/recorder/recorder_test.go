@@ -0,0 +1,120 @@
+package recorder
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Recorder_001(t *testing.T) {
+	var s schedule
+	now := time.Now()
+	if s.Active(now) {
+		t.Error("Unexpected active schedule")
+	}
+	s.Add(now.Add(time.Minute), now.Add(2*time.Minute))
+	if s.Active(now) {
+		t.Error("Unexpected active schedule before window")
+	} else if s.Active(now.Add(90*time.Second)) == false {
+		t.Error("Expected active schedule in window")
+	} else if s.Active(now.Add(3 * time.Minute)) {
+		t.Error("Unexpected active schedule after window")
+	} else if len(s.windows) != 0 {
+		t.Error("Expected window to be removed")
+	}
+	s.Trigger(now.Add(time.Second))
+	s.Trigger(now.Add(time.Millisecond))
+	if s.Active(now) == false {
+		t.Error("Expected active schedule when triggered")
+	} else if s.Active(now.Add(time.Second)) {
+		t.Error("Unexpected active schedule after trigger")
+	}
+	s.Trigger(now.Add(time.Second))
+	s.Reset()
+	if s.Active(now) {
+		t.Error("Unexpected active schedule after reset")
+	}
+}
+
+func Test_Recorder_002(t *testing.T) {
+	path := t.TempDir()
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		segment := segmentPath(path, "rec-", "ts", start.Add(time.Duration(i)*time.Minute))
+		if err := ioutil.WriteFile(segment, make([]byte, 100), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modtime := start.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(segment, modtime, modtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Files which are not segments
+	for _, name := range []string{"rec-other.ts", "rec-20200101-120000.mp4", "other-20200101-120000.ts"} {
+		if err := ioutil.WriteFile(filepath.Join(path, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	segments, err := listSegments(path, "rec-", "ts")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(segments) != 4 {
+		t.Fatal("Unexpected number of segments", len(segments))
+	} else if segments[0].Name() != "rec-20200101-120000.ts" {
+		t.Error("Unexpected first segment", segments[0].Name())
+	}
+
+	// Remove segments older than two minutes after start
+	if removed, err := retainSegments(path, segments, start.Add(2*time.Minute), 0); err != nil {
+		t.Error(err)
+	} else if len(removed) != 2 {
+		t.Error("Unexpected removed segments", removed)
+	}
+
+	// Remove segments when size exceeds one segment
+	segments, _ = listSegments(path, "rec-", "ts")
+	if removed, err := retainSegments(path, segments, time.Time{}, 150); err != nil {
+		t.Error(err)
+	} else if len(removed) != 1 {
+		t.Error("Unexpected removed segments", removed)
+	} else if segments, _ = listSegments(path, "rec-", "ts"); len(segments) != 1 || segments[0].Name() != "rec-20200101-120300.ts" {
+		t.Error("Unexpected remaining segments", segments)
+	}
+}
+
+func Test_Recorder_003(t *testing.T) {
+	// A grey frame, then the same frame with a white square covering a
+	// quarter of it
+	frame := image.NewGray(image.Rect(0, 0, 640, 480))
+	draw.Draw(frame, frame.Bounds(), image.NewUniform(color.Gray{0x80}), image.Point{}, draw.Src)
+	before := newSample(frame)
+	draw.Draw(frame, image.Rect(0, 0, 320, 240), image.NewUniform(color.White), image.Point{}, draw.Src)
+	after := newSample(frame)
+	if len(before) != sampleWidth*sampleHeight || before[0] != 0x80 || after[0] != 0xFF {
+		t.Fatal("Unexpected sample", before, after)
+	}
+
+	// The first frame and unchanged frames have no motion, and a change
+	// is motion when it exceeds the threshold
+	d := &detector{threshold: 20}
+	if d.Detect(before) || d.Detect(before) {
+		t.Error("Unexpected motion")
+	} else if d.Detect(after) == false {
+		t.Error("Expected motion")
+	}
+	d = &detector{threshold: 30, last: before}
+	if d.Detect(after) {
+		t.Error("Unexpected motion above threshold")
+	} else if d.Detect(newSample(image.NewGray(image.Rectangle{}))) {
+		t.Error("Unexpected motion for empty frame")
+	}
+}
@@ -0,0 +1,73 @@
+package recorder
+
+import (
+	"sync"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// schedule determines when recording is active, from time windows and
+// from triggers which record until a time in the future
+type schedule struct {
+	sync.Mutex
+
+	windows []window
+	trigger time.Time
+}
+
+type window struct {
+	start, stop time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Add a window between start and stop times
+func (this *schedule) Add(start, stop time.Time) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.windows = append(this.windows, window{start, stop})
+}
+
+// Trigger extends recording until a time, if later than any
+// existing trigger
+func (this *schedule) Trigger(until time.Time) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if until.After(this.trigger) {
+		this.trigger = until
+	}
+}
+
+// Reset removes all windows and triggers
+func (this *schedule) Reset() {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.windows = nil
+	this.trigger = time.Time{}
+}
+
+// Active returns true if recording should take place at a time, and
+// removes windows which have ended
+func (this *schedule) Active(t time.Time) bool {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	active := t.Before(this.trigger)
+	windows := this.windows[:0]
+	for _, w := range this.windows {
+		if t.Before(w.stop) == false {
+			continue
+		}
+		if t.Before(w.start) == false {
+			active = true
+		}
+		windows = append(windows, w)
+	}
+	this.windows = windows
+
+	// Return active state
+	return active
+}
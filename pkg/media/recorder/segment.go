@@ -0,0 +1,95 @@
+package recorder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type segment struct {
+	gopi.MediaOutput
+
+	path  string
+	start time.Time
+	last  time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// segmentTimeFormat is used for segment filenames, so that sorting
+	// filenames sorts segments by start time
+	segmentTimeFormat = "20060102-150405"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// segmentPath returns the path for a segment starting at a time
+func segmentPath(path, prefix, ext string, start time.Time) string {
+	return filepath.Join(path, prefix+start.Format(segmentTimeFormat)+"."+ext)
+}
+
+// listSegments returns segments in a folder, oldest first
+func listSegments(path, prefix, ext string) ([]os.FileInfo, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]os.FileInfo, 0, len(files))
+	for _, file := range files {
+		if file.Mode().IsRegular() == false {
+			continue
+		} else if name := file.Name(); strings.HasPrefix(name, prefix) == false || strings.HasSuffix(name, "."+ext) == false {
+			continue
+		} else if _, err := time.Parse(segmentTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), "."+ext)); err != nil {
+			continue
+		}
+		result = append(result, file)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result, nil
+}
+
+// retainSegments removes segments which were last modified before a
+// time, and then removes the oldest segments until the total size is
+// within a limit. A zero time or size means no limit. It returns the
+// paths of removed segments
+func retainSegments(path string, segments []os.FileInfo, before time.Time, size int64) ([]string, error) {
+	var total int64
+	for _, segment := range segments {
+		total += segment.Size()
+	}
+
+	var result error
+	removed := []string{}
+	for _, segment := range segments {
+		expired := before.IsZero() == false && segment.ModTime().Before(before)
+		oversize := size > 0 && total > size
+		if expired == false && oversize == false {
+			break
+		}
+		path := filepath.Join(path, segment.Name())
+		if err := os.Remove(path); err != nil {
+			result = multierror.Append(result, err)
+		} else {
+			removed = append(removed, path)
+		}
+		total -= segment.Size()
+	}
+
+	// Return removed segments and any errors
+	return removed, result
+}
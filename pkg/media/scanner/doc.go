@@ -0,0 +1,7 @@
+// Scanner package detects and decodes QR codes in frames, for example
+// frames decoded from a camera, for provisioning by QR code or inventory
+// scanning. A ScanEvent is emitted with the payload and corners of each
+// code, and the same payload is not emitted again until it has not been
+// seen for -scanner.holdoff. When -scanner.snapshot is set, the frame is
+// attached to the event and encoded as JPEG when the attachment is read.
+package scanner
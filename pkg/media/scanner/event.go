@@ -0,0 +1,48 @@
+package scanner
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	payload     []byte
+	corners     [4]gopi.Point
+	attachments []gopi.EventAttachment
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return string(this.payload)
+}
+
+func (this *event) Payload() []byte {
+	return this.payload
+}
+
+func (this *event) Corners() [4]gopi.Point {
+	return this.corners
+}
+
+func (this *event) Attachments() []gopi.EventAttachment {
+	return this.attachments
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<scanner.event"
+	str += fmt.Sprintf(" payload=%q", this.payload)
+	str += " corners=" + fmt.Sprint(this.corners)
+	for _, a := range this.attachments {
+		str += " " + fmt.Sprint(a)
+	}
+	return str + ">"
+}
@@ -0,0 +1,13 @@
+package scanner
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register scanner
+	graph.RegisterUnit(reflect.TypeOf(&scanner{}), reflect.TypeOf((*gopi.MediaScanner)(nil)))
+}
@@ -0,0 +1,152 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	attachment "github.com/djthorpe/gopi/v3/pkg/event/attachment"
+	barcode "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/barcode"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type scanner struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	sync.Mutex
+
+	holdoff  *time.Duration
+	snapshot *bool
+	seen     map[string]time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// deltaExpire is the interval between removing payloads not seen
+	deltaExpire = 10 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *scanner) Define(cfg gopi.Config) error {
+	this.holdoff = cfg.FlagDuration("scanner.holdoff", 2*time.Second, "Period before the same code is emitted again")
+	this.snapshot = cfg.FlagBool("scanner.snapshot", false, "Attach the frame to scan events as a JPEG snapshot")
+	return nil
+}
+
+func (this *scanner) New(gopi.Config) error {
+	this.Require(this.Logger, this.Publisher)
+
+	if *this.holdoff < 0 {
+		return gopi.ErrBadParameter.WithPrefix("-scanner.holdoff")
+	}
+	this.seen = make(map[string]time.Time)
+
+	// Return success
+	return nil
+}
+
+func (this *scanner) Run(ctx context.Context) error {
+	timer := time.NewTicker(deltaExpire)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			this.expire(time.Now())
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *scanner) String() string {
+	str := "<scanner"
+	str += " holdoff=" + fmt.Sprint(*this.holdoff)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *scanner) Scan(frame image.Image) error {
+	if frame == nil {
+		return gopi.ErrBadParameter.WithPrefix("Scan")
+	}
+
+	var result error
+	var attachments []gopi.EventAttachment
+	for _, symbol := range barcode.ScanQRCodes(frame) {
+		if this.emit(time.Now(), string(symbol.Data)) == false {
+			continue
+		}
+		if *this.snapshot && attachments == nil {
+			attachments = []gopi.EventAttachment{snapshot(frame)}
+		}
+		this.Debug("Scan: ", symbol.Version, "-", symbol.Level, " ", symbol.Corners)
+		if err := this.Publisher.Emit(&event{symbol.Data, symbol.Corners, attachments}, false); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// emit returns true if a payload has not been seen within the holdoff
+// period, and records when the payload was seen
+func (this *scanner) emit(now time.Time, payload string) bool {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	last, exists := this.seen[payload]
+	this.seen[payload] = now
+	return exists == false || now.Sub(last) >= *this.holdoff
+}
+
+// snapshot returns an attachment for a copy of a frame, which is encoded
+// as JPEG when it is read, as the frame may be reused by the caller
+func snapshot(frame image.Image) gopi.EventAttachment {
+	bounds := frame.Bounds()
+	rgba := image.NewRGBA(bounds)
+	draw.Draw(rgba, bounds, frame, bounds.Min, draw.Src)
+	return attachment.NewFunc("snapshot.jpg", "image/jpeg", func() ([]byte, error) {
+		buf := new(bytes.Buffer)
+		if err := jpeg.Encode(buf, rgba, nil); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	})
+}
+
+// expire removes payloads which have not been seen within the holdoff
+// period
+func (this *scanner) expire(now time.Time) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	for payload, last := range this.seen {
+		if now.Sub(last) >= *this.holdoff {
+			delete(this.seen, payload)
+		}
+	}
+}
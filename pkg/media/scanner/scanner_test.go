@@ -0,0 +1,126 @@
+package scanner_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	attachment "github.com/djthorpe/gopi/v3/pkg/event/attachment"
+	bitmap "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap"
+	rgba32 "github.com/djthorpe/gopi/v3/pkg/graphics/bitmap/rgba32"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/media/scanner"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.MediaScanner
+	gopi.Publisher
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// wait returns the next scan event or nil after a timeout
+func wait(ch <-chan gopi.Event) gopi.ScanEvent {
+	timeout := time.After(500 * time.Millisecond)
+	for {
+		select {
+		case evt := <-ch:
+			if evt, ok := evt.(gopi.ScanEvent); ok {
+				return evt
+			}
+		case <-timeout:
+			return nil
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Scanner_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.MediaScanner == nil {
+			t.Error("nil MediaScanner unit")
+		} else {
+			t.Log(app.MediaScanner)
+		}
+	})
+}
+
+func Test_Scanner_002(t *testing.T) {
+	frame, err := new(rgba32.Factory).New(bitmap.GetColorModel(gopi.SURFACE_FMT_RGBA32), 200, 200)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := frame.PaintQRCode("WIFI:S:gopi;;", gopi.QR_LEVEL_M, image.Rect(0, 0, 200, 200)); err != nil {
+		t.Fatal(err)
+	}
+	tool.Test(t, []string{"-scanner.holdoff=1h"}, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// The first scan emits an event, the second does not
+		if err := app.MediaScanner.Scan(frame); err != nil {
+			t.Error(err)
+		} else if evt := wait(ch); evt == nil {
+			t.Error("Timeout waiting for scan event")
+		} else if evt.Name() != "WIFI:S:gopi;;" || string(evt.Payload()) != evt.Name() {
+			t.Error("Unexpected event", evt)
+		} else if corners := evt.Corners(); corners[0].X >= corners[2].X || corners[0].Y >= corners[2].Y {
+			t.Error("Unexpected corners", corners)
+		} else {
+			t.Log(evt)
+		}
+		if err := app.MediaScanner.Scan(frame); err != nil {
+			t.Error(err)
+		} else if evt := wait(ch); evt != nil {
+			t.Error("Unexpected event", evt)
+		}
+		if err := app.MediaScanner.Scan(nil); err == nil {
+			t.Error("Expected error for nil frame")
+		}
+	})
+}
+
+func Test_Scanner_003(t *testing.T) {
+	frame, err := new(rgba32.Factory).New(bitmap.GetColorModel(gopi.SURFACE_FMT_RGBA32), 200, 200)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := frame.PaintQRCode("gopi", gopi.QR_LEVEL_M, image.Rect(0, 0, 200, 200)); err != nil {
+		t.Fatal(err)
+	}
+	tool.Test(t, []string{"-scanner.snapshot"}, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// The frame is attached as a JPEG snapshot
+		if err := app.MediaScanner.Scan(frame); err != nil {
+			t.Error(err)
+		} else if evt := wait(ch); evt == nil {
+			t.Error("Timeout waiting for scan event")
+		} else if attachments := attachment.Attachments(evt); len(attachments) != 1 || attachments[0].Type() != "image/jpeg" {
+			t.Error("Unexpected attachments", attachments)
+		} else if data, err := attachment.Read(attachments[0], 0); err != nil {
+			t.Error(err)
+		} else if img, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+			t.Error(err)
+		} else if img.Bounds() != frame.Bounds() {
+			t.Error("Unexpected snapshot", img.Bounds())
+		}
+	})
+}
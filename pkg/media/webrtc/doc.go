@@ -0,0 +1,24 @@
+// WebRTC package implements gopi.WebRTC with Pion, which publishes live
+// H.264 video to web browsers with far lower latency than segmented
+// streaming. Publish reads the H.264 stream from a gopi.MediaInput, such
+// as a camera, and sends each packet to the connected browsers. The
+// input should be H.264 constrained baseline in Annex B format, and
+// browsers which connect display video from the next key frame.
+//
+// Browsers connect by sending an offer with a POST request to the
+// -webrtc.path of the gopi.Server, with "application/sdp" content, and
+// receive an answer with all candidates. The STUN and TURN servers set
+// with -webrtc.ice are used to connect through NAT, and -webrtc.ports
+// sets the UDP ports used for media. At most -webrtc.peers browsers are
+// connected.
+//
+// Requests are authorized with the -webrtc.token as a bearer token or
+// a "token" parameter. Without the token, a paired session with view
+// permission is required when there is a gopi.PairingManager.
+//
+// When the offer includes audio and the request has a "talk" parameter,
+// the gopi.Doorbell is answered when the browser connects, so that audio
+// from the doorbell microphone is sent to the browser and audio from the
+// browser is played on the doorbell speaker. Audio is G.711 mu-law at
+// 8kHz, and talking requires media permission for a paired session.
+package webrtc
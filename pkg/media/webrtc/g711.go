@@ -0,0 +1,51 @@
+package webrtc
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// G.711 mu-law is sampled at 8kHz, mono
+	ulawSampleRate = 8000
+	ulawBias       = 0x84
+	ulawClip       = 32635
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// ulawEncode returns mu-law bytes for signed 16-bit samples
+func ulawEncode(samples []int16) []byte {
+	result := make([]byte, len(samples))
+	for i, sample := range samples {
+		s, sign := int(sample), 0
+		if s < 0 {
+			s, sign = -s, 0x80
+		}
+		if s > ulawClip {
+			s = ulawClip
+		}
+		s += ulawBias
+		exponent := 7
+		for mask := 0x4000; s&mask == 0 && exponent > 0; mask >>= 1 {
+			exponent--
+		}
+		mantissa := (s >> uint(exponent+3)) & 0x0F
+		result[i] = ^byte(sign | exponent<<4 | mantissa)
+	}
+	return result
+}
+
+// ulawDecode returns signed 16-bit samples for mu-law bytes
+func ulawDecode(data []byte) []int16 {
+	result := make([]int16, len(data))
+	for i, u := range data {
+		u = ^u
+		exponent, mantissa := uint(u>>4)&0x07, int(u&0x0F)
+		s := (((mantissa << 3) + ulawBias) << exponent) - ulawBias
+		if u&0x80 != 0 {
+			s = -s
+		}
+		result[i] = int16(s)
+	}
+	return result
+}
@@ -0,0 +1,41 @@
+package webrtc
+
+import (
+	"testing"
+)
+
+func Test_G711_001(t *testing.T) {
+	tests := []struct {
+		sample int16
+		ulaw   byte
+	}{
+		{0, 0xFF},
+		{-1, 0x7F},
+		{32767, 0x80},
+		{-32768, 0x00},
+		{1000, 0xCE},
+	}
+	for _, test := range tests {
+		if ulaw := ulawEncode([]int16{test.sample}); ulaw[0] != test.ulaw {
+			t.Errorf("Encode %v: expected 0x%02X, got 0x%02X", test.sample, test.ulaw, ulaw[0])
+		}
+	}
+}
+
+func Test_G711_002(t *testing.T) {
+	// Samples are decoded to within the step size for their exponent,
+	// which is at most 1/16 of the magnitude
+	for sample := -32000; sample <= 32000; sample += 97 {
+		decoded := ulawDecode(ulawEncode([]int16{int16(sample)}))[0]
+		diff, magnitude := int(decoded)-sample, sample
+		if diff < 0 {
+			diff = -diff
+		}
+		if magnitude < 0 {
+			magnitude = -magnitude
+		}
+		if diff > magnitude/16+8 {
+			t.Errorf("Sample %v decoded as %v", sample, decoded)
+		}
+	}
+}
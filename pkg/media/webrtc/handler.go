@@ -0,0 +1,81 @@
+package webrtc
+
+import (
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// handler answers offers from browsers which are authorized
+type handler struct {
+	connect   func(string, bool) (string, error)
+	authorize func(*http.Request, bool) error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// NewHandler returns a handler which reads an offer from the body of a
+// POST request and returns the answer from connect. The request is
+// authorized to watch, and to talk when the talk parameter is set
+func NewHandler(connect func(offer string, talk bool) (string, error), authorize func(*http.Request, bool) error) http.Handler {
+	return &handler{connect, authorize}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// HANDLER
+
+func (this *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	} else if mimetype, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mimetype != "application/sdp" {
+		http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	talk := req.URL.Query().Get("talk") != ""
+	if err := this.authorize(req, talk); err != nil {
+		http.Error(w, err.Error(), gopi.ErrorCode(err).HttpStatus())
+		return
+	}
+
+	offer, err := ioutil.ReadAll(io.LimitReader(req.Body, maxOfferSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if len(offer) > maxOfferSize || strings.TrimSpace(string(offer)) == "" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	answer, err := this.connect(string(offer), talk)
+	if err != nil {
+		http.Error(w, err.Error(), gopi.ErrorCode(err).HttpStatus())
+		return
+	}
+	w.Header().Set("Content-Type", "application/sdp")
+	w.WriteHeader(http.StatusCreated)
+	io.WriteString(w, answer)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// bearer returns the token from the authorization header, or from the
+// token parameter for clients which cannot set headers
+func bearer(req *http.Request) string {
+	const prefix = "Bearer "
+	if value := req.Header.Get("Authorization"); strings.HasPrefix(value, prefix) {
+		return strings.TrimSpace(strings.TrimPrefix(value, prefix))
+	} else {
+		return req.URL.Query().Get("token")
+	}
+}
@@ -0,0 +1,13 @@
+package webrtc
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.WebRTC
+	graph.RegisterUnit(reflect.TypeOf(&publisher{}), reflect.TypeOf((*gopi.WebRTC)(nil)))
+}
@@ -0,0 +1,102 @@
+package webrtc
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	pion "github.com/pion/webrtc/v3"
+	media "github.com/pion/webrtc/v3/pkg/media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// session implements gopi.IntercomSession for a browser, sending
+// samples on the audio track of the peer and reading samples from
+// the audio track received from the browser
+type session struct {
+	sync.Once
+	track *pion.TrackLocalStaticSample
+	recv  chan []int16
+	buf   []int16
+	done  chan struct{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Number of packets received from the browser which are buffered
+	// before packets are dropped
+	sessionQueue = 25
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newSession(track *pion.TrackLocalStaticSample) *session {
+	return &session{
+		track: track,
+		recv:  make(chan []int16, sessionQueue),
+		done:  make(chan struct{}),
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *session) SampleRate() uint {
+	return ulawSampleRate
+}
+
+// Read blocks until the buffer is filled with samples received from
+// the browser, and returns io.EOF when the session is closed
+func (this *session) Read(buf []int16) error {
+	for len(buf) > 0 {
+		if len(this.buf) == 0 {
+			select {
+			case samples := <-this.recv:
+				this.buf = samples
+			case <-this.done:
+				return io.EOF
+			}
+		}
+		n := copy(buf, this.buf)
+		buf, this.buf = buf[n:], this.buf[n:]
+	}
+	return nil
+}
+
+// Write sends samples to the browser
+func (this *session) Write(samples []int16) error {
+	select {
+	case <-this.done:
+		return io.EOF
+	default:
+		return this.track.WriteSample(media.Sample{
+			Data:     ulawEncode(samples),
+			Duration: time.Duration(len(samples)) * time.Second / ulawSampleRate,
+		})
+	}
+}
+
+// Close ends reading and writing
+func (this *session) Close() {
+	this.Once.Do(func() {
+		close(this.done)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// receive queues samples from the browser, and drops them when the
+// queue is full since the browser sends in real time
+func (this *session) receive(samples []int16) {
+	select {
+	case this.recv <- samples:
+	case <-this.done:
+	default:
+	}
+}
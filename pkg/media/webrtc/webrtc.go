@@ -0,0 +1,424 @@
+package webrtc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	pion "github.com/pion/webrtc/v3"
+	media "github.com/pion/webrtc/v3/pkg/media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type publisher struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Server
+	gopi.Doorbell
+	gopi.PairingManager
+	sync.Mutex
+
+	// Flags
+	path  *string
+	ice   *string
+	ports *string
+	token *string
+	max   *uint
+
+	api        *pion.API
+	servers    []pion.ICEServer
+	video      *pion.TrackLocalStaticSample
+	peers      map[*peer]bool
+	publishing bool
+}
+
+// peer is a connected browser
+type peer struct {
+	*pion.PeerConnection
+	session *session
+	cancel  context.CancelFunc
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	defaultICE = "stun:stun.l.google.com:19302"
+
+	// Payload types and parameters for the codecs which are negotiated.
+	// Video is H.264 constrained baseline, which browsers decode, and
+	// audio is G.711 mu-law, which needs no encoder
+	videoPayloadType = 102
+	videoFmtp        = "level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=42e01f"
+	audioPayloadType = 0
+
+	// Duration of a video frame before the rate is known
+	defaultFrameDuration = time.Second / 30
+
+	// Time allowed to gather candidates for an answer
+	gatherTimeout = 5 * time.Second
+
+	// Maximum size of an offer
+	maxOfferSize = 64 * 1024
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *publisher) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagString("webrtc.path", "/webrtc", "Path which browsers send offers to")
+	this.ice = cfg.FlagString("webrtc.ice", defaultICE, "Comma-separated STUN and TURN servers, or empty")
+	this.ports = cfg.FlagString("webrtc.ports", "", "UDP port range for media, such as 50000-50100, or empty")
+	this.token = cfg.FlagSecret("webrtc.token", "", "Token which browsers send to connect")
+	this.max = cfg.FlagUint("webrtc.peers", 4, "Maximum number of connected browsers")
+	return nil
+}
+
+func (this *publisher) New(gopi.Config) error {
+	this.Require(this.Logger, this.Server)
+
+	// Check parameters
+	if *this.path = strings.TrimSpace(*this.path); *this.path == "" {
+		return gopi.ErrBadParameter.WithPrefix("-webrtc.path")
+	} else if *this.max == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-webrtc.peers")
+	}
+
+	// Set ICE servers
+	for _, url := range strings.Split(*this.ice, ",") {
+		if url = strings.TrimSpace(url); url == "" {
+			continue
+		} else if strings.HasPrefix(url, "stun:") == false && strings.HasPrefix(url, "turn:") == false && strings.HasPrefix(url, "turns:") == false {
+			return gopi.ErrBadParameter.WithPrefix("-webrtc.ice: ", strconv.Quote(url))
+		}
+		this.servers = append(this.servers, pion.ICEServer{URLs: []string{url}})
+	}
+
+	// Register codecs, so that browsers send audio as mu-law
+	engine := new(pion.MediaEngine)
+	if err := engine.RegisterCodec(pion.RTPCodecParameters{
+		RTPCodecCapability: pion.RTPCodecCapability{MimeType: pion.MimeTypeH264, ClockRate: 90000, SDPFmtpLine: videoFmtp},
+		PayloadType:        videoPayloadType,
+	}, pion.RTPCodecTypeVideo); err != nil {
+		return err
+	}
+	if err := engine.RegisterCodec(pion.RTPCodecParameters{
+		RTPCodecCapability: pion.RTPCodecCapability{MimeType: pion.MimeTypePCMU, ClockRate: ulawSampleRate, Channels: 1},
+		PayloadType:        audioPayloadType,
+	}, pion.RTPCodecTypeAudio); err != nil {
+		return err
+	}
+
+	// Set port range for media, for example to open in a firewall
+	settings := pion.SettingEngine{}
+	if *this.ports != "" {
+		if min, max, err := parsePorts(*this.ports); err != nil {
+			return gopi.ErrBadParameter.WithPrefix("-webrtc.ports: ", err)
+		} else if err := settings.SetEphemeralUDPPortRange(min, max); err != nil {
+			return gopi.ErrBadParameter.WithPrefix("-webrtc.ports: ", err)
+		}
+	}
+	this.api = pion.NewAPI(pion.WithMediaEngine(engine), pion.WithSettingEngine(settings))
+
+	// The video track is shared by all peers
+	if video, err := pion.NewTrackLocalStaticSample(pion.RTPCodecCapability{MimeType: pion.MimeTypeH264, ClockRate: 90000, SDPFmtpLine: videoFmtp}, "video", "gopi"); err != nil {
+		return err
+	} else {
+		this.video = video
+	}
+	this.peers = make(map[*peer]bool)
+
+	// Serve offers
+	if err := this.Server.RegisterService(*this.path, NewHandler(this.connect, this.authorizer())); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *publisher) Dispose() error {
+	this.Mutex.Lock()
+	peers := this.peers
+	this.peers = nil
+	this.Mutex.Unlock()
+
+	// Close connections
+	var result error
+	for peer := range peers {
+		if err := peer.close(); err != nil {
+			result = err
+		}
+	}
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *publisher) Publish(ctx context.Context, in gopi.MediaInput) error {
+	if in == nil {
+		return gopi.ErrBadParameter.WithPrefix("Publish")
+	}
+
+	// Find the H.264 video stream
+	var streams []int
+	for _, index := range in.StreamsForFlag(gopi.MEDIA_FLAG_VIDEO) {
+		if stream := in.StreamForIndex(index); stream != nil && stream.Codec() != nil && stream.Codec().Name() == "h264" {
+			streams = append(streams, index)
+			break
+		}
+	}
+	if len(streams) == 0 {
+		return gopi.ErrNotFound.WithPrefix("Publish: No H.264 video stream")
+	}
+
+	// Publish from one input at a time
+	this.Mutex.Lock()
+	if this.publishing {
+		this.Mutex.Unlock()
+		return gopi.ErrOutOfOrder.WithPrefix("Publish: Already publishing")
+	}
+	this.publishing = true
+	this.Mutex.Unlock()
+	defer func() {
+		this.Mutex.Lock()
+		this.publishing = false
+		this.Mutex.Unlock()
+	}()
+
+	// Write each packet as a sample, with the time since the previous
+	// packet as the duration, since the input is live
+	var last time.Time
+	return in.Read(ctx, streams, func(_ gopi.MediaDecodeContext, packet gopi.MediaPacket) error {
+		now, duration := time.Now(), defaultFrameDuration
+		if last.IsZero() == false {
+			duration = now.Sub(last)
+		}
+		last = now
+		return this.video.WriteSample(media.Sample{Data: packet.Bytes(), Duration: duration})
+	})
+}
+
+func (this *publisher) Peers() int {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return len(this.peers)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *publisher) String() string {
+	str := "<webrtc"
+	str += fmt.Sprintf(" path=%q", *this.path)
+	for _, server := range this.servers {
+		str += fmt.Sprintf(" ice=%q", server.URLs[0])
+	}
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	str += fmt.Sprint(" peers=", len(this.peers))
+	if this.publishing {
+		str += " publishing=true"
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// connect answers an offer from a browser, adding the video track and,
+// when the browser offers audio and talk is true, carrying audio
+// between the browser and the doorbell
+func (this *publisher) connect(offer string, talk bool) (string, error) {
+	this.Mutex.Lock()
+	full := this.peers == nil || uint(len(this.peers)) >= *this.max
+	this.Mutex.Unlock()
+	if full {
+		return "", gopi.ErrUnavailable.WithPrefix("Maximum number of peers connected")
+	}
+
+	pc, err := this.api.NewPeerConnection(pion.Configuration{ICEServers: this.servers})
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	peer := &peer{PeerConnection: pc, cancel: cancel}
+
+	// Set the offer, then add tracks to the transceivers offered
+	if err := pc.SetRemoteDescription(pion.SessionDescription{Type: pion.SDPTypeOffer, SDP: offer}); err != nil {
+		peer.close()
+		return "", gopi.ErrBadParameter.WithPrefix("Offer: ", err)
+	}
+	audio := false
+	for _, transceiver := range pc.GetTransceivers() {
+		if transceiver.Kind() == pion.RTPCodecTypeAudio {
+			audio = true
+		}
+	}
+	if sender, err := pc.AddTrack(this.video); err != nil {
+		peer.close()
+		return "", err
+	} else {
+		go drain(sender)
+	}
+	if audio && talk && this.Doorbell != nil {
+		if track, err := pion.NewTrackLocalStaticSample(pion.RTPCodecCapability{MimeType: pion.MimeTypePCMU, ClockRate: ulawSampleRate, Channels: 1}, "audio", "gopi"); err != nil {
+			peer.close()
+			return "", err
+		} else if sender, err := pc.AddTrack(track); err != nil {
+			peer.close()
+			return "", err
+		} else {
+			peer.session = newSession(track)
+			go drain(sender)
+		}
+	}
+
+	// Receive audio from the browser
+	pc.OnTrack(func(track *pion.TrackRemote, _ *pion.RTPReceiver) {
+		if track.Kind() != pion.RTPCodecTypeAudio || peer.session == nil {
+			return
+		}
+		for {
+			packet, _, err := track.ReadRTP()
+			if err != nil {
+				return
+			}
+			peer.session.receive(ulawDecode(packet.Payload))
+		}
+	})
+
+	// Answer the doorbell when connected, and remove the peer when the
+	// connection ends
+	pc.OnConnectionStateChange(func(state pion.PeerConnectionState) {
+		this.Debug("WebRTC: ", state)
+		switch state {
+		case pion.PeerConnectionStateConnected:
+			if peer.session != nil {
+				go this.answer(ctx, peer)
+			}
+		case pion.PeerConnectionStateFailed, pion.PeerConnectionStateClosed, pion.PeerConnectionStateDisconnected:
+			this.remove(peer)
+		}
+	})
+
+	// Create an answer with all candidates, so that the browser does
+	// not need to send candidates separately
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		peer.close()
+		return "", err
+	}
+	gathered := pion.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(answer); err != nil {
+		peer.close()
+		return "", err
+	}
+	select {
+	case <-gathered:
+	case <-time.After(gatherTimeout):
+		this.Debug("WebRTC: Timeout gathering candidates")
+	}
+
+	// Add the peer
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.peers == nil {
+		peer.close()
+		return "", gopi.ErrOutOfOrder.WithPrefix("connect")
+	}
+	this.peers[peer] = true
+	return pc.LocalDescription().SDP, nil
+}
+
+// answer carries audio between the doorbell and a peer
+func (this *publisher) answer(ctx context.Context, peer *peer) {
+	if err := this.Doorbell.Answer(ctx, peer.session); errors.Is(err, gopi.ErrOutOfOrder) {
+		this.Debug("WebRTC: ", err)
+	} else if err != nil {
+		this.Print("WebRTC: ", err)
+	}
+}
+
+// remove closes a peer and removes it from the connected peers
+func (this *publisher) remove(peer *peer) {
+	this.Mutex.Lock()
+	delete(this.peers, peer)
+	this.Mutex.Unlock()
+	if err := peer.close(); err != nil {
+		this.Debug("WebRTC: ", err)
+	}
+}
+
+// authorizer returns a function which authorizes a browser to watch,
+// and to talk when talk is true. A browser which sends the token can
+// watch and talk. Otherwise a paired session with view permission is
+// required to watch and media permission to talk
+func (this *publisher) authorizer() func(*http.Request, bool) error {
+	return func(req *http.Request, talk bool) error {
+		token := bearer(req)
+		if *this.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(*this.token)) == 1 {
+			return nil
+		} else if this.PairingManager == nil {
+			return gopi.ErrPermissionDenied.WithPrefix("Missing or invalid token")
+		}
+		perm := gopi.SESSION_PERM_VIEW
+		if talk {
+			perm |= gopi.SESSION_PERM_MEDIA
+		}
+		_, err := this.PairingManager.Authorize(token, perm)
+		return err
+	}
+}
+
+// close the connection and end the session
+func (this *peer) close() error {
+	this.cancel()
+	if this.session != nil {
+		this.session.Close()
+	}
+	return this.PeerConnection.Close()
+}
+
+// drain reads RTCP packets from a sender until it is stopped, which is
+// required for interceptors to process them
+func drain(sender *pion.RTPSender) {
+	buf := make([]byte, 1500)
+	for {
+		if _, _, err := sender.Read(buf); err != nil {
+			return
+		}
+	}
+}
+
+// parsePorts returns the minimum and maximum port from a range
+func parsePorts(value string) (uint16, uint16, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("Invalid port range %q", value)
+	}
+	min, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+	max, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+	if min == 0 || max < min {
+		return 0, 0, fmt.Errorf("Invalid port range %q", value)
+	}
+	return uint16(min), uint16(max), nil
+}
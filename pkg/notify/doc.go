@@ -0,0 +1,26 @@
+// Notify package implements gopi.Notifier, which sends notifications
+// with a severity and attachments, such as camera snapshots, to sinks:
+//
+//   - email through the -notify.smtp server from -notify.smtp.from to the
+//     -notify.email addresses, with attachments as MIME parts
+//   - a JSON message posted to the -notify.webhook URL, with attachments
+//     encoded as base64
+//   - a Telegram chat through a bot with -notify.telegram.token and
+//     -notify.telegram.chat, with images sent as photos
+//   - Pushover with -notify.pushover.token and -notify.pushover.user,
+//     with the first image attached and critical notifications sent
+//     with high priority
+//
+// Attachments are read when a notification is sent, so the attachments
+// of an event which implements gopi.EventWithAttachments can be passed
+// to Notify. Sinks are added when their flags are set, and other units
+// can add sinks with RegisterSink. Notifications with a severity below
+// -notify.severity are not sent. The rules engine sends notifications
+// with the notify action, for example when an alert fires:
+//
+//	alert "freezer"
+//	  when freezer temperature > -10 for 5m
+//	  severity critical
+//	  do notify(severity, "Freezer is at", value)
+//	end
+package notify
@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	attachment "github.com/djthorpe/gopi/v3/pkg/event/attachment"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// email sends notifications through an SMTP server, with attachments
+// as MIME parts
+type email struct {
+	addr string
+	from string
+	to   []string
+	auth smtp.Auth
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newEmail(addr, from, user, password, to string) (*email, error) {
+	this := new(email)
+	if host, _, err := net.SplitHostPort(addr); err != nil || host == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("-notify.smtp")
+	} else if from == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("-notify.smtp.from")
+	} else if user != "" {
+		this.auth = smtp.PlainAuth("", user, password, host)
+	}
+	for _, addr := range strings.Split(to, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			this.to = append(this.to, addr)
+		}
+	}
+	if len(this.to) == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("-notify.email")
+	}
+	this.addr, this.from = addr, from
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *email) Name() string {
+	return "email"
+}
+
+func (this *email) Notify(ctx context.Context, n gopi.Notification) error {
+	msg, err := this.message(n, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// SendMail does not accept a context, so return when the context
+	// is done and let the send complete in the background
+	errs := make(chan error, 1)
+	go func() {
+		errs <- smtp.SendMail(this.addr, this.auth, this.from, this.to, msg)
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// message returns a MIME message with the notification as the text part
+// and attachments encoded as base64
+func (this *email) message(n gopi.Notification, now time.Time) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+
+	buf.WriteString("From: " + this.from + "\r\n")
+	buf.WriteString("To: " + strings.Join(this.to, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject(n)) + "\r\n")
+	buf.WriteString("Date: " + now.Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: multipart/mixed; boundary=" + w.Boundary() + "\r\n\r\n")
+
+	// Text
+	if part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	}); err != nil {
+		return nil, err
+	} else if err := encode(part, []byte(n.Message)); err != nil {
+		return nil, err
+	}
+
+	// Attachments
+	for _, a := range n.Attachments {
+		if data, err := attachment.Read(a, maxAttachment); err != nil {
+			return nil, err
+		} else if part, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.Type()},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Name()})},
+		}); err != nil {
+			return nil, err
+		} else if err := encode(part, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encode writes data as base64 in lines of 76 characters
+func encode(w io.Writer, data []byte) error {
+	str := base64.StdEncoding.EncodeToString(data)
+	for len(str) > 76 {
+		if _, err := w.Write([]byte(str[:76] + "\r\n")); err != nil {
+			return err
+		}
+		str = str[76:]
+	}
+	_, err := w.Write([]byte(str + "\r\n"))
+	return err
+}
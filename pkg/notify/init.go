@@ -0,0 +1,13 @@
+package notify
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.Notifier
+	graph.RegisterUnit(reflect.TypeOf(&notifier{}), reflect.TypeOf((*gopi.Notifier)(nil)))
+}
@@ -0,0 +1,259 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type notifier struct {
+	gopi.Unit
+	gopi.Logger
+	sync.RWMutex
+
+	// Flags
+	severity *string
+	timeout  *time.Duration
+	email    *string
+	smtp     *string
+	from     *string
+	user     *string
+	password *string
+	webhook  *string
+	telegram struct{ token, chat *string }
+	pushover struct{ token, user *string }
+
+	min   gopi.NotifySeverity
+	sinks []gopi.NotifySink
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// maxAttachment is the largest attachment which is sent
+	maxAttachment = 50 << 20
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *notifier) Define(cfg gopi.Config) error {
+	this.severity = cfg.FlagString("notify.severity", "info", "Minimum severity for notifications (info, warning, critical)")
+	this.timeout = cfg.FlagDuration("notify.timeout", 30*time.Second, "Timeout for delivering a notification")
+	this.email = cfg.FlagString("notify.email", "", "Comma-separated addresses for email notifications")
+	this.smtp = cfg.FlagString("notify.smtp", "", "SMTP server <host>:<port> for email notifications")
+	this.from = cfg.FlagString("notify.smtp.from", "", "Sender address for email notifications")
+	this.user = cfg.FlagString("notify.smtp.user", "", "SMTP user")
+	this.password = cfg.FlagSecret("notify.smtp.password", "", "SMTP password")
+	this.webhook = cfg.FlagString("notify.webhook", "", "URL for webhook notifications")
+	this.telegram.token = cfg.FlagSecret("notify.telegram.token", "", "Telegram bot token")
+	this.telegram.chat = cfg.FlagString("notify.telegram.chat", "", "Telegram chat identifier")
+	this.pushover.token = cfg.FlagSecret("notify.pushover.token", "", "Pushover application token")
+	this.pushover.user = cfg.FlagSecret("notify.pushover.user", "", "Pushover user or group key")
+	return nil
+}
+
+func (this *notifier) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	if severity, err := parseSeverity(*this.severity); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-notify.severity")
+	} else if *this.timeout <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-notify.timeout")
+	} else {
+		this.min = severity
+	}
+
+	// Add sinks which are configured
+	if *this.email != "" || *this.smtp != "" {
+		if sink, err := newEmail(*this.smtp, *this.from, *this.user, *this.password, *this.email); err != nil {
+			return err
+		} else if err := this.RegisterSink(sink); err != nil {
+			return err
+		}
+	}
+	if *this.webhook != "" {
+		if sink, err := newWebhook(*this.webhook); err != nil {
+			return err
+		} else if err := this.RegisterSink(sink); err != nil {
+			return err
+		}
+	}
+	if *this.telegram.token != "" || *this.telegram.chat != "" {
+		if sink, err := newTelegram(*this.telegram.token, *this.telegram.chat); err != nil {
+			return err
+		} else if err := this.RegisterSink(sink); err != nil {
+			return err
+		}
+	}
+	if *this.pushover.token != "" || *this.pushover.user != "" {
+		if sink, err := newPushover(*this.pushover.token, *this.pushover.user); err != nil {
+			return err
+		} else if err := this.RegisterSink(sink); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *notifier) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.sinks = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *notifier) Notify(ctx context.Context, message string, severity gopi.NotifySeverity, attachments ...gopi.EventAttachment) error {
+	if strings.TrimSpace(message) == "" {
+		return gopi.ErrBadParameter.WithPrefix("Notify")
+	} else if severity < this.min {
+		return nil
+	}
+
+	this.RWMutex.RLock()
+	sinks := append([]gopi.NotifySink{}, this.sinks...)
+	this.RWMutex.RUnlock()
+
+	// Deliver to each sink, continuing on error
+	n := gopi.Notification{Message: message, Severity: severity, Attachments: attachments}
+	this.Debug("Notify: ", n)
+	var result error
+	for _, sink := range sinks {
+		child, cancel := context.WithTimeout(ctx, *this.timeout)
+		err := sink.Notify(child, n)
+		cancel()
+		if err != nil {
+			result = multierror.Append(result, fmt.Errorf("%v: %w", sink.Name(), err))
+		}
+	}
+
+	// Return any errors
+	return result
+}
+
+func (this *notifier) RegisterSink(sink gopi.NotifySink) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if sink == nil || sink.Name() == "" {
+		return gopi.ErrBadParameter.WithPrefix("RegisterSink")
+	}
+	for _, other := range this.sinks {
+		if other.Name() == sink.Name() {
+			return gopi.ErrDuplicateEntry.WithPrefix("RegisterSink: ", strconv.Quote(sink.Name()))
+		}
+	}
+	this.sinks = append(this.sinks, sink)
+
+	// Return success
+	return nil
+}
+
+func (this *notifier) Sinks() []string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	result := make([]string, 0, len(this.sinks))
+	for _, sink := range this.sinks {
+		result = append(result, sink.Name())
+	}
+	sort.Strings(result)
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *notifier) String() string {
+	str := "<notify"
+	str += " severity=" + fmt.Sprint(this.min)
+	for _, sink := range this.Sinks() {
+		str += fmt.Sprintf(" sink=%q", sink)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// parseSeverity returns a severity from "info", "warning" or "critical"
+func parseSeverity(value string) (gopi.NotifySeverity, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "info":
+		return gopi.NOTIFY_INFO, nil
+	case "warning":
+		return gopi.NOTIFY_WARNING, nil
+	case "critical":
+		return gopi.NOTIFY_CRITICAL, nil
+	default:
+		return 0, gopi.ErrBadParameter.WithPrefix(strconv.Quote(value))
+	}
+}
+
+// severityName returns "info", "warning" or "critical"
+func severityName(severity gopi.NotifySeverity) string {
+	return strings.ToLower(strings.TrimPrefix(severity.String(), "NOTIFY_"))
+}
+
+// subject returns the first line of a message, with the severity
+func subject(n gopi.Notification) string {
+	line := strings.TrimSpace(n.Message)
+	if i := strings.IndexAny(line, "\r\n"); i >= 0 {
+		line = strings.TrimSpace(line[:i])
+	}
+	return prefix(n.Severity) + line
+}
+
+// prefix returns [WARNING] or [CRITICAL] for messages which are not
+// information
+func prefix(severity gopi.NotifySeverity) string {
+	if severity == gopi.NOTIFY_INFO {
+		return ""
+	}
+	return "[" + strings.ToUpper(severityName(severity)) + "] "
+}
+
+// post a request body to a URL, returning the response body or an error
+// when the status is not successful
+func post(ctx context.Context, url, contentType string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil {
+		return nil, err
+	} else if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return data, gopi.ErrUnexpectedResponse.WithPrefix(resp.Status)
+	}
+	return data, nil
+}
@@ -0,0 +1,104 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	attachment "github.com/djthorpe/gopi/v3/pkg/event/attachment"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/notify"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Notifier
+}
+
+type sink struct {
+	name          string
+	notifications []gopi.Notification
+}
+
+func (this *sink) Name() string { return this.name }
+
+func (this *sink) Notify(_ context.Context, n gopi.Notification) error {
+	this.notifications = append(this.notifications, n)
+	if this.name == "broken" {
+		return gopi.ErrUnexpectedResponse
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Notify_001(t *testing.T) {
+	tool.Test(t, []string{"-notify.severity=warning"}, new(App), func(app *App) {
+		a, b := &sink{name: "a"}, &sink{name: "broken"}
+		if err := app.Notifier.RegisterSink(a); err != nil {
+			t.Fatal(err)
+		} else if err := app.Notifier.RegisterSink(b); err != nil {
+			t.Fatal(err)
+		} else if err := app.Notifier.RegisterSink(&sink{name: "a"}); err == nil {
+			t.Error("Expected error for duplicate sink")
+		} else if sinks := app.Notifier.Sinks(); len(sinks) != 2 {
+			t.Error("Unexpected sinks", sinks)
+		}
+
+		// Information is below the minimum severity
+		if err := app.Notifier.Notify(context.Background(), "Door opened", gopi.NOTIFY_INFO); err != nil {
+			t.Error(err)
+		} else if len(a.notifications) != 0 {
+			t.Error("Unexpected notifications", a.notifications)
+		}
+
+		// Errors are returned but other sinks are notified
+		snapshot := attachment.New("snapshot.jpg", "image/jpeg", []byte{0xFF, 0xD8})
+		if err := app.Notifier.Notify(context.Background(), "Motion detected", gopi.NOTIFY_CRITICAL, snapshot); errors.Is(err, gopi.ErrUnexpectedResponse) == false {
+			t.Error("Unexpected error", err)
+		} else if len(a.notifications) != 1 || len(b.notifications) != 1 {
+			t.Error("Unexpected notifications", a.notifications, b.notifications)
+		} else if n := a.notifications[0]; n.Message != "Motion detected" || n.Severity != gopi.NOTIFY_CRITICAL || len(n.Attachments) != 1 {
+			t.Error("Unexpected notification", n)
+		}
+	})
+}
+
+func Test_Notify_002(t *testing.T) {
+	var body struct {
+		Message     string
+		Severity    string
+		Attachments []struct {
+			Name string
+			Data []byte
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	tool.Test(t, []string{"-notify.webhook=" + server.URL}, new(App), func(app *App) {
+		snapshot := attachment.New("snapshot.jpg", "image/jpeg", []byte{0xFF, 0xD8})
+		if sinks := app.Notifier.Sinks(); len(sinks) != 1 || sinks[0] != "webhook" {
+			t.Error("Unexpected sinks", sinks)
+		} else if err := app.Notifier.Notify(context.Background(), "Motion detected", gopi.NOTIFY_WARNING, snapshot); err != nil {
+			t.Error(err)
+		} else if body.Message != "Motion detected" || body.Severity != "warning" {
+			t.Error("Unexpected body", body)
+		} else if len(body.Attachments) != 1 || body.Attachments[0].Name != "snapshot.jpg" || len(body.Attachments[0].Data) != 2 {
+			t.Error("Unexpected attachments", body.Attachments)
+		}
+	})
+}
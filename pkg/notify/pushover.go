@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"strconv"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	attachment "github.com/djthorpe/gopi/v3/pkg/event/attachment"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// pushover sends notifications to a user or group through Pushover,
+// with the first image attachment sent with the message
+type pushover struct {
+	endpoint string
+	token    string
+	user     string
+}
+
+type pushoverResponse struct {
+	Status int      `json:"status"`
+	Errors []string `json:"errors"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	pushoverEndpoint   = "https://api.pushover.net/1/messages.json"
+	pushoverMessage    = 1024    // Maximum length of a message
+	pushoverAttachment = 2621440 // Maximum size of an attachment
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newPushover(token, user string) (*pushover, error) {
+	if token == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("-notify.pushover.token")
+	} else if user == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("-notify.pushover.user")
+	}
+	return &pushover{pushoverEndpoint, token, user}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *pushover) Name() string {
+	return "pushover"
+}
+
+func (this *pushover) Notify(ctx context.Context, n gopi.Notification) error {
+	message := n.Message
+	if len(message) > pushoverMessage {
+		message = message[:pushoverMessage]
+	}
+
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+	w.WriteField("token", this.token)
+	w.WriteField("user", this.user)
+	w.WriteField("message", message)
+	w.WriteField("priority", strconv.Itoa(priority(n.Severity)))
+	for _, a := range n.Attachments {
+		if strings.HasPrefix(a.Type(), "image/") == false {
+			continue
+		} else if data, err := attachment.Read(a, pushoverAttachment); err != nil {
+			return err
+		} else if part, err := w.CreateFormFile("attachment", a.Name()); err != nil {
+			return err
+		} else if _, err := part.Write(data); err != nil {
+			return err
+		}
+		break
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	// Return errors from the response
+	var response pushoverResponse
+	body, err := post(ctx, this.endpoint, w.FormDataContentType(), buf)
+	if json.Unmarshal(body, &response) == nil && response.Status != 1 && len(response.Errors) > 0 {
+		return gopi.ErrUnexpectedResponse.WithPrefix(strings.Join(response.Errors, ", "))
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// priority returns low priority for information, normal priority for
+// warnings and high priority, which bypasses quiet hours, for critical
+// notifications
+func priority(severity gopi.NotifySeverity) int {
+	switch severity {
+	case gopi.NOTIFY_CRITICAL:
+		return 1
+	case gopi.NOTIFY_WARNING:
+		return 0
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	attachment "github.com/djthorpe/gopi/v3/pkg/event/attachment"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Sink_001(t *testing.T) {
+	// Telegram sends images as photos with the message as the caption
+	methods := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		methods = append(methods, req.URL.Path)
+		if err := req.ParseMultipartForm(1 << 20); err == nil && req.FormValue("chat_id") != "42" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ok":false,"description":"Bad Request: chat not found"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	sink, err := newTelegram("token", "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.endpoint = server.URL + "/bottoken/"
+	n := gopi.Notification{Message: "Motion detected", Severity: gopi.NOTIFY_WARNING, Attachments: []gopi.EventAttachment{
+		attachment.New("snapshot.jpg", "image/jpeg", []byte{0xFF, 0xD8}),
+		attachment.New("clip.mp4", "video/mp4", []byte{0}),
+	}}
+	if err := sink.Notify(context.Background(), gopi.Notification{Message: "Door opened"}); err != nil {
+		t.Error(err)
+	} else if err := sink.Notify(context.Background(), n); err != nil {
+		t.Error(err)
+	} else if strings.Join(methods, " ") != "/bottoken/sendMessage /bottoken/sendPhoto /bottoken/sendDocument" {
+		t.Error("Unexpected methods", methods)
+	}
+	sink.chat = "0"
+	if err := sink.Notify(context.Background(), n); err == nil || strings.Contains(err.Error(), "chat not found") == false {
+		t.Error("Unexpected error", err)
+	}
+}
+
+func Test_Sink_002(t *testing.T) {
+	// Pushover sends critical notifications with high priority
+	var form map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		form = map[string]string{"token": req.FormValue("token"), "priority": req.FormValue("priority"), "message": req.FormValue("message")}
+		if _, _, err := req.FormFile("attachment"); err == nil {
+			form["attachment"] = "yes"
+		}
+		w.Write([]byte(`{"status":1}`))
+	}))
+	defer server.Close()
+
+	if _, err := newPushover("", "user"); err == nil {
+		t.Error("Expected error without token")
+	}
+	sink, err := newPushover("token", "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.endpoint = server.URL
+	if err := sink.Notify(context.Background(), gopi.Notification{Message: "Freezer is warm", Severity: gopi.NOTIFY_CRITICAL, Attachments: []gopi.EventAttachment{
+		attachment.New("snapshot.jpg", "image/jpeg", []byte{0xFF, 0xD8}),
+	}}); err != nil {
+		t.Error(err)
+	} else if form["token"] != "token" || form["priority"] != "1" || form["message"] != "Freezer is warm" || form["attachment"] != "yes" {
+		t.Error("Unexpected form", form)
+	}
+}
+
+func Test_Sink_003(t *testing.T) {
+	// Email has a subject with the severity and attachments as parts
+	if _, err := newEmail("localhost:25", "", "", "", "me@example.com"); err == nil {
+		t.Error("Expected error without sender")
+	} else if _, err := newEmail("localhost:25", "pi@example.com", "", "", ""); err == nil {
+		t.Error("Expected error without recipients")
+	}
+	sink, err := newEmail("localhost:25", "pi@example.com", "", "", "me@example.com, you@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := sink.message(gopi.Notification{Message: "Motion detected\nat the front door", Severity: gopi.NOTIFY_CRITICAL, Attachments: []gopi.EventAttachment{
+		attachment.New("snapshot.jpg", "image/jpeg", []byte{0xFF, 0xD8}),
+	}}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"To: me@example.com, you@example.com\r\n",
+		"Subject: [CRITICAL] Motion detected\r\n",
+		"Content-Type: image/jpeg",
+		"filename=snapshot.jpg",
+	} {
+		if strings.Contains(string(msg), expected) == false {
+			t.Errorf("Missing %q in message", expected)
+		}
+	}
+}
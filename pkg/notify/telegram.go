@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	attachment "github.com/djthorpe/gopi/v3/pkg/event/attachment"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// telegram sends notifications to a chat through a Telegram bot, with
+// images sent as photos and other attachments as documents
+type telegram struct {
+	endpoint string
+	chat     string
+}
+
+type telegramResponse struct {
+	Ok          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	telegramEndpoint = "https://api.telegram.org/bot"
+	telegramCaption  = 1024 // Maximum length of a caption
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newTelegram(token, chat string) (*telegram, error) {
+	if token == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("-notify.telegram.token")
+	} else if chat == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("-notify.telegram.chat")
+	}
+	return &telegram{telegramEndpoint + token + "/", chat}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *telegram) Name() string {
+	return "telegram"
+}
+
+func (this *telegram) Notify(ctx context.Context, n gopi.Notification) error {
+	text := prefix(n.Severity) + n.Message
+
+	// Send the message as text, or as the caption of the first attachment
+	// when it is short enough
+	attachments := n.Attachments
+	caption := ""
+	if len(attachments) > 0 && len(text) <= telegramCaption {
+		caption = text
+	} else if err := this.sendMessage(ctx, text); err != nil {
+		return err
+	}
+	for _, a := range attachments {
+		if err := this.sendFile(ctx, a, caption); err != nil {
+			return err
+		}
+		caption = ""
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *telegram) sendMessage(ctx context.Context, text string) error {
+	data, err := json.Marshal(map[string]string{
+		"chat_id": this.chat,
+		"text":    text,
+	})
+	if err != nil {
+		return err
+	}
+	return this.call(ctx, "sendMessage", "application/json", data)
+}
+
+func (this *telegram) sendFile(ctx context.Context, a gopi.EventAttachment, caption string) error {
+	method, field := "sendDocument", "document"
+	if strings.HasPrefix(a.Type(), "image/") {
+		method, field = "sendPhoto", "photo"
+	}
+	data, err := attachment.Read(a, maxAttachment)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+	w.WriteField("chat_id", this.chat)
+	if caption != "" {
+		w.WriteField("caption", caption)
+	}
+	if part, err := w.CreateFormFile(field, a.Name()); err != nil {
+		return err
+	} else if _, err := part.Write(data); err != nil {
+		return err
+	} else if err := w.Close(); err != nil {
+		return err
+	}
+	return this.call(ctx, method, w.FormDataContentType(), buf.Bytes())
+}
+
+// call a bot method, returning the description of any error
+func (this *telegram) call(ctx context.Context, method, contentType string, data []byte) error {
+	var response telegramResponse
+	body, err := post(ctx, this.endpoint+method, contentType, bytes.NewReader(data))
+	if json.Unmarshal(body, &response) == nil && response.Ok == false && response.Description != "" {
+		return gopi.ErrUnexpectedResponse.WithPrefix(method, ": ", response.Description)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
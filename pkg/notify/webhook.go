@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	attachment "github.com/djthorpe/gopi/v3/pkg/event/attachment"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// webhook posts notifications as JSON, with attachments encoded as base64
+type webhook struct {
+	url string
+}
+
+type webhookMessage struct {
+	Message     string              `json:"message"`
+	Severity    string              `json:"severity"`
+	Attachments []webhookAttachment `json:"attachments,omitempty"`
+}
+
+type webhookAttachment struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+	Data []byte `json:"data"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newWebhook(endpoint string) (*webhook, error) {
+	if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("-notify.webhook")
+	}
+	return &webhook{endpoint}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *webhook) Name() string {
+	return "webhook"
+}
+
+func (this *webhook) Notify(ctx context.Context, n gopi.Notification) error {
+	msg := webhookMessage{Message: n.Message, Severity: severityName(n.Severity)}
+	for _, a := range n.Attachments {
+		if data, err := attachment.Read(a, maxAttachment); err != nil {
+			return err
+		} else {
+			msg.Attachments = append(msg.Attachments, webhookAttachment{a.Name(), a.Type(), data})
+		}
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = post(ctx, this.url, "application/json", bytes.NewReader(data))
+	return err
+}
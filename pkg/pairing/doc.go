@@ -0,0 +1,23 @@
+// Pairing package implements gopi.PairingManager, which pairs clients
+// such as a phone app or web UI with the device. A client requests
+// pairing with a name and permissions, and a six-digit code is printed
+// to the log and emitted as a gopi.PairingEvent, so that it can be
+// displayed on screen. The client confirms pairing with the code before
+// it expires, and receives a token which identifies the session.
+//
+// Permissions are view, gpio, cast, media and admin, and clients can
+// only request the -pairing.permissions. Sessions are stored in the
+// -pairing.file, which contains a hash of each token rather than the
+// token. When gopi.Server is available, pairing is served on the
+// -pairing.path, and handlers which control the device can require a
+// session with permissions using Authorize:
+//
+//	POST /pair {"name":"phone","permissions":"view,gpio"}
+//	POST /pair/confirm {"id":"...","code":"123456"}
+//	GET /pair/session with the header "Authorization: Bearer <token>"
+//
+// When gopi.AccessControl is available, tokens are also accepted as
+// identities named by the session, with the role admin for admin
+// permission, operator for gpio, cast or media permission, and otherwise
+// viewer.
+package pairing
@@ -0,0 +1,60 @@
+package pairing
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t       gopi.PairingEventType
+	name    string
+	session gopi.Session
+	code    string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(t gopi.PairingEventType, name string, session gopi.Session, code string) gopi.PairingEvent {
+	return &event{t, name, session, code}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+// Name returns the name of the client
+func (this *event) Name() string {
+	return this.name
+}
+
+func (this *event) Type() gopi.PairingEventType {
+	return this.t
+}
+
+func (this *event) Session() gopi.Session {
+	return this.session
+}
+
+func (this *event) Code() string {
+	return this.code
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<pairing.event"
+	str += fmt.Sprint(" type=", this.t)
+	str += fmt.Sprintf(" name=%q", this.name)
+	if this.code != "" {
+		str += fmt.Sprintf(" code=%q", this.code)
+	}
+	if this.session != nil {
+		str += fmt.Sprint(" session=", this.session)
+	}
+	return str + ">"
+}
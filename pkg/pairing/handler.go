@@ -0,0 +1,198 @@
+package pairing
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// handler serves pairing requests:
+//
+//	POST {path} {"name":"phone","permissions":"view,gpio"} returns {"id":"..."}
+//	POST {path}/confirm {"id":"...","code":"123456"} returns {"token":"..."}
+//	GET {path}/session returns the session for the bearer token
+//	DELETE {path}/session revokes the session for the bearer token
+//	GET {path}/sessions returns all sessions, with admin permission
+type handler struct {
+	gopi.PairingManager
+	path string
+}
+
+// authorizer passes requests with a bearer token which has permissions
+// to a handler
+type authorizer struct {
+	gopi.PairingManager
+	permissions gopi.SessionPermission
+	handler     http.Handler
+}
+
+type pairRequest struct {
+	Name        string `json:"name"`
+	Permissions string `json:"permissions"`
+	Id          string `json:"id"`
+	Code        string `json:"code"`
+}
+
+type pairResponse struct {
+	Id      string           `json:"id,omitempty"`
+	Token   string           `json:"token,omitempty"`
+	Session *sessionResponse `json:"session,omitempty"`
+}
+
+type sessionResponse struct {
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Permissions []string  `json:"permissions"`
+	Created     time.Time `json:"created"`
+	Seen        time.Time `json:"seen,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewHandler(manager gopi.PairingManager, path string) http.Handler {
+	return &handler{manager, strings.TrimSuffix(path, "/")}
+}
+
+// Authorize returns a handler which requires a bearer token for a session
+// with permissions, for example:
+//
+//	server.RegisterService("/gpio", pairing.Authorize(manager, gopi.SESSION_PERM_GPIO, handler))
+func Authorize(manager gopi.PairingManager, permissions gopi.SessionPermission, h http.Handler) http.Handler {
+	return &authorizer{manager, permissions, h}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// HANDLERS
+
+func (this *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch strings.Trim(strings.TrimPrefix(req.URL.Path, this.path), "/") {
+	case "":
+		this.servePair(w, req)
+	case "confirm":
+		this.serveConfirm(w, req)
+	case "session":
+		this.serveSession(w, req)
+	case "sessions":
+		this.serveSessions(w, req)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (this *authorizer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if _, err := this.PairingManager.Authorize(bearer(req), this.permissions); err != nil {
+		serveError(w, err)
+	} else {
+		this.handler.ServeHTTP(w, req)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *handler) servePair(w http.ResponseWriter, req *http.Request) {
+	var body pairRequest
+	if req.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	} else if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		serveError(w, gopi.ErrBadParameter.WithPrefix(err))
+	} else if permissions, err := ParsePermissions(body.Permissions); err != nil {
+		serveError(w, err)
+	} else if id, err := this.PairingManager.Pair(body.Name, permissions); err != nil {
+		serveError(w, err)
+	} else {
+		serveJSON(w, http.StatusAccepted, pairResponse{Id: id})
+	}
+}
+
+func (this *handler) serveConfirm(w http.ResponseWriter, req *http.Request) {
+	var body pairRequest
+	if req.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	} else if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		serveError(w, gopi.ErrBadParameter.WithPrefix(err))
+	} else if session, token, err := this.PairingManager.Confirm(body.Id, body.Code); err != nil {
+		serveError(w, err)
+	} else {
+		serveJSON(w, http.StatusOK, pairResponse{Token: token, Session: toSession(session)})
+	}
+}
+
+func (this *handler) serveSession(w http.ResponseWriter, req *http.Request) {
+	session, err := this.PairingManager.Authorize(bearer(req), gopi.SESSION_PERM_NONE)
+	if err != nil {
+		serveError(w, err)
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		serveJSON(w, http.StatusOK, toSession(session))
+	case http.MethodDelete:
+		if err := this.PairingManager.Revoke(session); err != nil {
+			serveError(w, err)
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (this *handler) serveSessions(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	} else if _, err := this.PairingManager.Authorize(bearer(req), gopi.SESSION_PERM_ADMIN); err != nil {
+		serveError(w, err)
+	} else {
+		result := []*sessionResponse{}
+		for _, session := range this.PairingManager.Sessions() {
+			result = append(result, toSession(session))
+		}
+		serveJSON(w, http.StatusOK, result)
+	}
+}
+
+// bearer returns the token from the Authorization header
+func bearer(req *http.Request) string {
+	const prefix = "Bearer "
+	if value := req.Header.Get("Authorization"); strings.HasPrefix(value, prefix) {
+		return strings.TrimSpace(strings.TrimPrefix(value, prefix))
+	} else {
+		return ""
+	}
+}
+
+func toSession(session gopi.Session) *sessionResponse {
+	result := &sessionResponse{
+		Id:          session.Id(),
+		Name:        session.Name(),
+		Permissions: []string{},
+		Created:     session.Created(),
+		Seen:        session.Seen(),
+	}
+	for name, permission := range permissionNames {
+		if session.Permissions()&permission == permission {
+			result.Permissions = append(result.Permissions, name)
+		}
+	}
+	sort.Strings(result.Permissions)
+	return result
+}
+
+func serveJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func serveError(w http.ResponseWriter, err error) {
+	http.Error(w, err.Error(), gopi.ErrorCode(err).HttpStatus())
+}
@@ -0,0 +1,13 @@
+package pairing
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.PairingManager
+	graph.RegisterUnit(reflect.TypeOf(&manager{}), reflect.TypeOf((*gopi.PairingManager)(nil)))
+}
@@ -0,0 +1,443 @@
+package pairing
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type manager struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Server
+	gopi.AccessControl
+	sync.RWMutex
+
+	// Flags
+	file        *string
+	path        *string
+	expiry      *time.Duration
+	attempts    *uint
+	permissions *string
+
+	allowed  gopi.SessionPermission
+	requests map[string]*request
+	sessions map[string]*session
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	codeDigits  = 6
+	idBytes     = 8
+	secretBytes = 32
+	maxRequests = 8
+	deltaPurge  = 10 * time.Second
+)
+
+var (
+	permissionNames = map[string]gopi.SessionPermission{
+		"view":  gopi.SESSION_PERM_VIEW,
+		"gpio":  gopi.SESSION_PERM_GPIO,
+		"cast":  gopi.SESSION_PERM_CAST,
+		"media": gopi.SESSION_PERM_MEDIA,
+		"admin": gopi.SESSION_PERM_ADMIN,
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *manager) Define(cfg gopi.Config) error {
+	this.file = cfg.FlagPath("pairing.file", "", "File to store paired sessions")
+	this.path = cfg.FlagString("pairing.path", "/pair", "Path to serve pairing, or empty to disable")
+	this.expiry = cfg.FlagDuration("pairing.expiry", 2*time.Minute, "Time before a pairing code expires")
+	this.attempts = cfg.FlagUint("pairing.attempts", 3, "Number of attempts to enter a pairing code")
+	this.permissions = cfg.FlagString("pairing.permissions", "view,gpio,cast,media", "Comma-separated permissions which clients can request")
+	return nil
+}
+
+func (this *manager) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if *this.expiry <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-pairing.expiry")
+	} else if *this.attempts == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-pairing.attempts")
+	} else if allowed, err := ParsePermissions(*this.permissions); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-pairing.permissions: ", err)
+	} else {
+		this.allowed = allowed
+	}
+
+	// Read sessions
+	this.requests = make(map[string]*request)
+	this.sessions = make(map[string]*session)
+	if err := this.read(); err != nil {
+		return err
+	}
+
+	// Authenticate tokens for access control
+	if this.AccessControl != nil {
+		if err := this.AccessControl.RegisterAuthenticator(this.authenticate); err != nil {
+			return err
+		}
+	}
+
+	// Serve pairing requests
+	if this.Server != nil && *this.path != "" {
+		*this.path = "/" + strings.Trim(*this.path, "/")
+		handler := NewHandler(this, *this.path)
+		if err := this.Server.RegisterService(*this.path, handler); err != nil {
+			return err
+		} else if err := this.Server.RegisterService(*this.path+"/", handler); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *manager) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.requests = nil
+	this.sessions = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *manager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(deltaPurge)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			this.purge()
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *manager) Pair(name string, permissions gopi.SessionPermission) (string, error) {
+	if name = strings.TrimSpace(name); name == "" {
+		return "", gopi.ErrBadParameter.WithPrefix("Pair: name")
+	} else if permissions == gopi.SESSION_PERM_NONE {
+		permissions = gopi.SESSION_PERM_VIEW
+	}
+	if permissions&this.allowed != permissions {
+		return "", gopi.ErrPermissionDenied.WithPrefix("Pair: ", permissions&^this.allowed)
+	}
+
+	// Limit the number of waiting requests
+	this.purge()
+	this.RWMutex.Lock()
+	if len(this.requests) >= maxRequests {
+		this.RWMutex.Unlock()
+		return "", gopi.ErrChannelFull.WithPrefix("Pair")
+	}
+	id, code := randomId(), randomCode()
+	this.requests[id] = &request{
+		name:        name,
+		permissions: permissions,
+		code:        code,
+		expires:     time.Now().Add(*this.expiry),
+	}
+	this.RWMutex.Unlock()
+
+	// Print and emit the code, which can be displayed on screen
+	this.Print("Pairing: Code for ", strconv.Quote(name), " is ", code)
+	this.emit(NewEvent(gopi.PAIRING_EVENT_CODE, name, nil, code))
+
+	// Return success
+	return id, nil
+}
+
+func (this *manager) Confirm(id, code string) (gopi.Session, string, error) {
+	this.RWMutex.Lock()
+	req, exists := this.requests[id]
+	if exists == false || time.Now().After(req.expires) {
+		delete(this.requests, id)
+		this.RWMutex.Unlock()
+		return nil, "", gopi.ErrNotFound.WithPrefix("Confirm: ", id)
+	}
+	if subtle.ConstantTimeCompare([]byte(code), []byte(req.code)) != 1 {
+		if req.attempts++; req.attempts >= *this.attempts {
+			delete(this.requests, id)
+		}
+		this.RWMutex.Unlock()
+		this.emit(NewEvent(gopi.PAIRING_EVENT_FAILED, req.name, nil, ""))
+		return nil, "", gopi.ErrPermissionDenied.WithPrefix("Confirm: Invalid code")
+	}
+
+	// Create session with a token which contains the session identifier
+	// and a secret
+	delete(this.requests, id)
+	secret := randomBytes(secretBytes)
+	session := newSession(randomId(), req.name, req.permissions, secret)
+	this.sessions[session.Id()] = session
+	this.RWMutex.Unlock()
+
+	// Write sessions
+	if err := this.write(); err != nil {
+		this.Print("Pairing: ", err)
+	}
+
+	// Emit paired event
+	this.Print("Pairing: Paired ", session)
+	this.emit(NewEvent(gopi.PAIRING_EVENT_PAIRED, session.Name(), session, ""))
+
+	// Return success
+	return session, session.Id() + "." + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+func (this *manager) Authorize(token string, permissions gopi.SessionPermission) (gopi.Session, error) {
+	// Decode token
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return nil, gopi.ErrPermissionDenied.WithPrefix("Authorize")
+	}
+	secret, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, gopi.ErrPermissionDenied.WithPrefix("Authorize")
+	}
+
+	// Check session and permissions
+	this.RWMutex.RLock()
+	session, exists := this.sessions[parts[0]]
+	this.RWMutex.RUnlock()
+	if exists == false || session.verify(secret) == false {
+		return nil, gopi.ErrPermissionDenied.WithPrefix("Authorize")
+	} else if session.Permissions()&permissions != permissions {
+		return nil, gopi.ErrPermissionDenied.WithPrefix("Authorize: ", permissions&^session.Permissions())
+	}
+
+	// Return success
+	return session, nil
+}
+
+func (this *manager) Sessions() []gopi.Session {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	result := make([]gopi.Session, 0, len(this.sessions))
+	for _, session := range this.sessions {
+		result = append(result, session)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Created().Before(result[j].Created())
+	})
+	return result
+}
+
+func (this *manager) SetPermissions(session gopi.Session, permissions gopi.SessionPermission) error {
+	if session == nil {
+		return gopi.ErrBadParameter.WithPrefix("SetPermissions")
+	}
+
+	this.RWMutex.RLock()
+	other, exists := this.sessions[session.Id()]
+	this.RWMutex.RUnlock()
+	if exists == false {
+		return gopi.ErrNotFound.WithPrefix("SetPermissions: ", session.Id())
+	} else {
+		other.setPermissions(permissions)
+	}
+
+	// Write sessions
+	return this.write()
+}
+
+func (this *manager) Revoke(session gopi.Session) error {
+	if session == nil {
+		return gopi.ErrBadParameter.WithPrefix("Revoke")
+	}
+
+	this.RWMutex.Lock()
+	other, exists := this.sessions[session.Id()]
+	delete(this.sessions, session.Id())
+	this.RWMutex.Unlock()
+	if exists == false {
+		return gopi.ErrNotFound.WithPrefix("Revoke: ", session.Id())
+	}
+
+	// Write sessions and emit event
+	this.Print("Pairing: Revoked ", other)
+	this.emit(NewEvent(gopi.PAIRING_EVENT_REVOKED, other.Name(), other, ""))
+	return this.write()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *manager) String() string {
+	str := "<pairing"
+	str += fmt.Sprint(" allowed=", this.allowed)
+	for _, session := range this.Sessions() {
+		str += fmt.Sprint(" ", session)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// purge removes expired requests
+func (this *manager) purge() {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	now := time.Now()
+	for id, req := range this.requests {
+		if now.After(req.expires) {
+			delete(this.requests, id)
+		}
+	}
+}
+
+// read sessions from the file, if it exists
+func (this *manager) read() error {
+	if *this.file == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(*this.file)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var records []record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("%v: %w", *this.file, err)
+	}
+	for _, record := range records {
+		if record.Id_ != "" && len(record.Hash) > 0 {
+			this.sessions[record.Id_] = &session{record: record}
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+// write sessions to the file, which is only readable by the owner
+func (this *manager) write() error {
+	if *this.file == "" {
+		return nil
+	}
+	this.RWMutex.RLock()
+	records := make([]record, 0, len(this.sessions))
+	for _, session := range this.sessions {
+		records = append(records, session.snapshot())
+	}
+	this.RWMutex.RUnlock()
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Created_.Before(records[j].Created_)
+	})
+	if data, err := json.MarshalIndent(records, "", "  "); err != nil {
+		return err
+	} else {
+		return ioutil.WriteFile(*this.file, data, 0600)
+	}
+}
+
+// authenticate returns the identity for a session token, with a role
+// from the session permissions
+func (this *manager) authenticate(token string) (gopi.AccessIdentity, error) {
+	session, err := this.Authorize(token, gopi.SESSION_PERM_NONE)
+	if err != nil {
+		return gopi.AccessIdentity{}, gopi.ErrNotFound.WithPrefix("authenticate")
+	}
+	identity := gopi.AccessIdentity{Name: session.Name()}
+	switch perms := session.Permissions(); {
+	case perms&gopi.SESSION_PERM_ADMIN != 0:
+		identity.Role = gopi.ACCESS_ROLE_ADMIN
+	case perms&(gopi.SESSION_PERM_GPIO|gopi.SESSION_PERM_CAST|gopi.SESSION_PERM_MEDIA) != 0:
+		identity.Role = gopi.ACCESS_ROLE_OPERATOR
+	case perms&gopi.SESSION_PERM_VIEW != 0:
+		identity.Role = gopi.ACCESS_ROLE_VIEWER
+	}
+	return identity, nil
+}
+
+func (this *manager) emit(evt gopi.Event) {
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(evt, false); err != nil {
+			this.Debug("Pairing: ", err)
+		}
+	}
+}
+
+// ParsePermissions returns permissions from comma-separated names, which
+// are view, gpio, cast, media and admin
+func ParsePermissions(value string) (gopi.SessionPermission, error) {
+	result := gopi.SESSION_PERM_NONE
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name == "" {
+			continue
+		} else if permission, exists := permissionNames[name]; exists == false {
+			return 0, gopi.ErrBadParameter.WithPrefix(strconv.Quote(name))
+		} else {
+			result |= permission
+		}
+	}
+	return result, nil
+}
+
+// randomId returns a random hexadecimal identifier
+func randomId() string {
+	return hex.EncodeToString(randomBytes(idBytes))
+}
+
+// randomCode returns a random numeric code
+func randomCode() string {
+	max := big.NewInt(1)
+	for i := 0; i < codeDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	if n, err := rand.Int(rand.Reader, max); err != nil {
+		panic(err)
+	} else {
+		return fmt.Sprintf("%0*d", codeDigits, n)
+	}
+}
+
+func randomBytes(n int) []byte {
+	data := make([]byte, n)
+	if _, err := rand.Read(data); err != nil {
+		panic(err)
+	}
+	return data
+}
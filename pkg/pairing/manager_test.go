@@ -0,0 +1,165 @@
+package pairing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	pairing "github.com/djthorpe/gopi/v3/pkg/pairing"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.PairingManager
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Pairing_001(t *testing.T) {
+	if perms, err := pairing.ParsePermissions("view, GPIO,,cast"); err != nil {
+		t.Error(err)
+	} else if perms != gopi.SESSION_PERM_VIEW|gopi.SESSION_PERM_GPIO|gopi.SESSION_PERM_CAST {
+		t.Error("Unexpected permissions", perms)
+	}
+	if _, err := pairing.ParsePermissions("view,other"); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func Test_Pairing_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Admin permission cannot be requested by default
+		if _, err := app.PairingManager.Pair("phone", gopi.SESSION_PERM_ADMIN); err == nil {
+			t.Error("Expected error for admin permission")
+		}
+
+		// Pair with the displayed code
+		id, err := app.PairingManager.Pair("phone", gopi.SESSION_PERM_VIEW|gopi.SESSION_PERM_GPIO)
+		if err != nil {
+			t.Fatal(err)
+		}
+		code := testutil.NextType(ch, gopi.PAIRING_EVENT_CODE).(gopi.PairingEvent).Code()
+		if _, _, err := app.PairingManager.Confirm(id, "x"); err == nil {
+			t.Error("Expected error for invalid code")
+		}
+		session, token, err := app.PairingManager.Confirm(id, code)
+		if err != nil {
+			t.Fatal(err)
+		} else if session.Name() != "phone" {
+			t.Error("Unexpected session", session)
+		}
+		if _, _, err := app.PairingManager.Confirm(id, code); err == nil {
+			t.Error("Expected error for confirmed request")
+		}
+
+		// Authorize with permissions
+		if other, err := app.PairingManager.Authorize(token, gopi.SESSION_PERM_GPIO); err != nil {
+			t.Error(err)
+		} else if other.Id() != session.Id() {
+			t.Error("Unexpected session", other)
+		}
+		if _, err := app.PairingManager.Authorize(token, gopi.SESSION_PERM_CAST); err == nil {
+			t.Error("Expected error for cast permission")
+		}
+		if _, err := app.PairingManager.Authorize(session.Id()+".AAAA", gopi.SESSION_PERM_NONE); err == nil {
+			t.Error("Expected error for invalid token")
+		}
+
+		// Change permissions and revoke
+		if err := app.PairingManager.SetPermissions(session, gopi.SESSION_PERM_CAST); err != nil {
+			t.Error(err)
+		} else if _, err := app.PairingManager.Authorize(token, gopi.SESSION_PERM_CAST); err != nil {
+			t.Error(err)
+		}
+		if err := app.PairingManager.Revoke(session); err != nil {
+			t.Error(err)
+		} else if _, err := app.PairingManager.Authorize(token, gopi.SESSION_PERM_NONE); err == nil {
+			t.Error("Expected error for revoked session")
+		} else if len(app.PairingManager.Sessions()) != 0 {
+			t.Error("Unexpected sessions", app.PairingManager.Sessions())
+		}
+	})
+}
+
+func Test_Pairing_003(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Pair over HTTP
+		handler := pairing.NewHandler(app.PairingManager, "/pair")
+		w := post(handler, "/pair", `{"name":"tablet","permissions":"view,media"}`)
+		if w.Code != http.StatusAccepted {
+			t.Fatal("Unexpected status", w.Code, w.Body.String())
+		}
+		var response struct {
+			Id    string `json:"id"`
+			Token string `json:"token"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		code := testutil.NextType(ch, gopi.PAIRING_EVENT_CODE).(gopi.PairingEvent).Code()
+		w = post(handler, "/pair/confirm", `{"id":"`+response.Id+`","code":"`+code+`"}`)
+		if w.Code != http.StatusOK {
+			t.Fatal("Unexpected status", w.Code, w.Body.String())
+		} else if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		} else if response.Token == "" {
+			t.Fatal("Expected token", w.Body.String())
+		}
+
+		// Authorize requests with the token
+		ok := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+		for _, test := range []struct {
+			perms  gopi.SessionPermission
+			token  string
+			status int
+		}{
+			{gopi.SESSION_PERM_MEDIA, response.Token, http.StatusOK},
+			{gopi.SESSION_PERM_GPIO, response.Token, http.StatusForbidden},
+			{gopi.SESSION_PERM_VIEW, "", http.StatusForbidden},
+		} {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/media", nil)
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			pairing.Authorize(app.PairingManager, test.perms, ok).ServeHTTP(w, req)
+			if w.Code != test.status {
+				t.Error(test.perms, "Unexpected status", w.Code)
+			}
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func post(handler http.Handler, url, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+	handler.ServeHTTP(w, req)
+	return w
+}
@@ -0,0 +1,126 @@
+package pairing
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// session is a paired client, which stores a hash of the secret part
+// of the token rather than the token
+type session struct {
+	sync.RWMutex
+	record
+}
+
+// record is a session as stored in a file
+type record struct {
+	Id_          string                 `json:"id"`
+	Name_        string                 `json:"name"`
+	Permissions_ gopi.SessionPermission `json:"permissions"`
+	Created_     time.Time              `json:"created"`
+	Seen_        time.Time              `json:"seen,omitempty"`
+	Hash         []byte                 `json:"hash"`
+}
+
+// request is a client waiting to confirm pairing with a code
+type request struct {
+	name        string
+	permissions gopi.SessionPermission
+	code        string
+	expires     time.Time
+	attempts    uint
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newSession(id, name string, permissions gopi.SessionPermission, secret []byte) *session {
+	hash := sha256.Sum256(secret)
+	return &session{record: record{
+		Id_:          id,
+		Name_:        name,
+		Permissions_: permissions,
+		Created_:     time.Now(),
+		Hash:         hash[:],
+	}}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *session) Id() string {
+	return this.Id_
+}
+
+func (this *session) Name() string {
+	return this.Name_
+}
+
+func (this *session) Permissions() gopi.SessionPermission {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.Permissions_
+}
+
+func (this *session) Created() time.Time {
+	return this.Created_
+}
+
+func (this *session) Seen() time.Time {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.Seen_
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *session) String() string {
+	str := "<session"
+	str += " id=" + strconv.Quote(this.Id_)
+	str += " name=" + strconv.Quote(this.Name_)
+	str += fmt.Sprint(" permissions=", this.Permissions())
+	str += " created=" + this.Created_.Format(time.RFC3339)
+	if seen := this.Seen(); seen.IsZero() == false {
+		str += " seen=" + seen.Format(time.RFC3339)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// verify returns true if the secret matches the hash, and updates the
+// time the session was last seen
+func (this *session) verify(secret []byte) bool {
+	hash := sha256.Sum256(secret)
+	if subtle.ConstantTimeCompare(hash[:], this.Hash) != 1 {
+		return false
+	}
+	this.RWMutex.Lock()
+	this.Seen_ = time.Now()
+	this.RWMutex.Unlock()
+	return true
+}
+
+// snapshot returns the session to store in a file
+func (this *session) snapshot() record {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.record
+}
+
+func (this *session) setPermissions(permissions gopi.SessionPermission) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	this.Permissions_ = permissions
+}
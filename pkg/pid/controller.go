@@ -0,0 +1,209 @@
+package pid
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type controller struct {
+	sync.RWMutex
+
+	name     string
+	gains    gopi.PIDGains
+	setpoint float64
+	min, max float64
+
+	// State from the last update
+	integral float64
+	value    float64
+	err      float64
+	rate     float64
+	output   float64
+	updated  bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func NewController(name string, gains gopi.PIDGains, setpoint, min, max float64) (gopi.PIDController, error) {
+	this := &controller{name: name, setpoint: setpoint}
+	if err := checkGains(gains); err != nil {
+		return nil, err
+	} else if err := this.SetLimits(min, max); err != nil {
+		return nil, err
+	} else {
+		this.gains = gains
+	}
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *controller) Name() string {
+	return this.name
+}
+
+func (this *controller) Gains() gopi.PIDGains {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.gains
+}
+
+func (this *controller) Setpoint() float64 {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.setpoint
+}
+
+func (this *controller) Limits() (float64, float64) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.min, this.max
+}
+
+func (this *controller) Output() float64 {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.output
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *controller) SetGains(gains gopi.PIDGains) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if err := checkGains(gains); err != nil {
+		return err
+	}
+
+	// Adjust the integral so that the proportional and derivative terms
+	// with the new gains give the same output (bumpless transfer)
+	if this.updated {
+		before := this.gains.Kp*this.err - this.gains.Kd*this.rate
+		after := gains.Kp*this.err - gains.Kd*this.rate
+		this.integral = clamp(this.integral+before-after, this.min, this.max)
+	}
+	this.gains = gains
+
+	// Return success
+	return nil
+}
+
+func (this *controller) SetSetpoint(setpoint float64) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	this.setpoint = setpoint
+}
+
+func (this *controller) SetLimits(min, max float64) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if math.IsNaN(min) || math.IsNaN(max) || min >= max {
+		return gopi.ErrBadParameter.WithPrefix("SetLimits")
+	}
+	this.min, this.max = min, max
+	this.integral = clamp(this.integral, min, max)
+	this.output = clamp(this.output, min, max)
+
+	// Return success
+	return nil
+}
+
+func (this *controller) Update(value float64, dt time.Duration) float64 {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	err := this.setpoint - value
+	seconds := dt.Seconds()
+
+	// The derivative is taken on the process value rather than the
+	// error. There is no derivative or integral on the first update, and
+	// the last derivative is retained when no time has passed
+	rate := float64(0)
+	integral := this.integral
+	if this.updated && seconds > 0 {
+		rate = (value - this.value) / seconds
+		integral = clamp(integral+this.gains.Ki*err*seconds, this.min, this.max)
+	} else if this.updated {
+		rate = this.rate
+	}
+
+	// Do not accumulate the integral when the output is saturated and
+	// the error would saturate it further
+	output := this.gains.Kp*err + integral - this.gains.Kd*rate
+	if (output > this.max && err > 0) || (output < this.min && err < 0) {
+		integral = this.integral
+		output = this.gains.Kp*err + integral - this.gains.Kd*rate
+	}
+
+	// Set state
+	this.integral = integral
+	this.value, this.err, this.rate = value, err, rate
+	this.output = clamp(output, this.min, this.max)
+	this.updated = true
+
+	// Return the output
+	return this.output
+}
+
+func (this *controller) Reset() {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	this.integral, this.value, this.err, this.rate = 0, 0, 0, 0
+	this.output = clamp(0, this.min, this.max)
+	this.updated = false
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *controller) String() string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	str := "<pid.controller"
+	str += fmt.Sprintf(" name=%q", this.name)
+	str += fmt.Sprint(" ", this.gains)
+	str += " setpoint=" + strconv.FormatFloat(this.setpoint, 'g', -1, 64)
+	str += " limits=" + strconv.FormatFloat(this.min, 'g', -1, 64) + "," + strconv.FormatFloat(this.max, 'g', -1, 64)
+	if this.updated {
+		str += " value=" + strconv.FormatFloat(this.value, 'g', -1, 64)
+		str += " output=" + strconv.FormatFloat(this.output, 'g', -1, 64)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func checkGains(gains gopi.PIDGains) error {
+	for _, gain := range []float64{gains.Kp, gains.Ki, gains.Kd} {
+		if math.IsNaN(gain) || math.IsInf(gain, 0) {
+			return gopi.ErrBadParameter.WithPrefix(gains)
+		}
+	}
+	return nil
+}
+
+func clamp(value, min, max float64) float64 {
+	if value < min {
+		return min
+	} else if value > max {
+		return max
+	} else {
+		return value
+	}
+}
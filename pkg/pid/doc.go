@@ -0,0 +1,11 @@
+// Pid package implements gopi.PID, which creates PID controllers and
+// binds them to sensor events and actuators, for example to drive a fan
+// from CPU temperature or a heater from a water temperature probe.
+//
+// The integral term is accumulated with the integral gain applied, so
+// that changing gains does not cause a step change in output. The
+// integral is clamped to the output limits and does not accumulate
+// while the output is saturated (anti-windup), and the derivative is
+// taken on the process value so that a change of setpoint does not
+// cause a spike in output.
+package pid
@@ -0,0 +1,13 @@
+package pid
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.PID
+	graph.RegisterUnit(reflect.TypeOf(&pid{}), reflect.TypeOf((*gopi.PID)(nil)))
+}
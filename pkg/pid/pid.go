@@ -0,0 +1,162 @@
+package pid
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type pid struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	sync.RWMutex
+
+	bindings map[gopi.PIDController]*binding
+}
+
+type binding struct {
+	value  gopi.PIDValueFunc
+	output gopi.PIDOutputFunc
+	last   time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *pid) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	this.bindings = make(map[gopi.PIDController]*binding)
+
+	// Return success
+	return nil
+}
+
+func (this *pid) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.bindings = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *pid) Run(ctx context.Context) error {
+	if this.Publisher == nil {
+		<-ctx.Done()
+		return nil
+	}
+
+	ch := this.Publisher.Subscribe()
+	defer this.Publisher.Unsubscribe(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-ch:
+			this.update(evt)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *pid) NewController(name string, gains gopi.PIDGains, setpoint, min, max float64) (gopi.PIDController, error) {
+	return NewController(name, gains, setpoint, min, max)
+}
+
+func (this *pid) Bind(controller gopi.PIDController, value gopi.PIDValueFunc, output gopi.PIDOutputFunc) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if this.Publisher == nil {
+		return gopi.ErrInternalAppError.WithPrefix("Missing gopi.Publisher")
+	} else if controller == nil || value == nil || output == nil {
+		return gopi.ErrBadParameter.WithPrefix("Bind")
+	} else if _, exists := this.bindings[controller]; exists {
+		return gopi.ErrDuplicateEntry.WithPrefix("Bind: ", controller.Name())
+	} else {
+		this.bindings[controller] = &binding{value: value, output: output}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *pid) Unbind(controller gopi.PIDController) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	if _, exists := this.bindings[controller]; exists == false {
+		return gopi.ErrNotFound.WithPrefix("Unbind")
+	} else {
+		delete(this.bindings, controller)
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *pid) String() string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	str := "<pid"
+	for controller := range this.bindings {
+		str += fmt.Sprint(" ", controller)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// update passes the process value from an event to each bound
+// controller, and calls the output function outside of the lock
+func (this *pid) update(evt gopi.Event) {
+	type result struct {
+		controller gopi.PIDController
+		output     gopi.PIDOutputFunc
+		value      float64
+	}
+
+	now := time.Now()
+	results := []result{}
+	this.RWMutex.Lock()
+	for controller, binding := range this.bindings {
+		value, ok := binding.value(evt)
+		if ok == false {
+			continue
+		}
+		dt := time.Duration(0)
+		if binding.last.IsZero() == false {
+			dt = now.Sub(binding.last)
+		}
+		binding.last = now
+		results = append(results, result{controller, binding.output, controller.Update(value, dt)})
+	}
+	this.RWMutex.Unlock()
+
+	for _, result := range results {
+		if err := result.output(result.value); err != nil {
+			this.Print("PID: ", result.controller.Name(), ": ", err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package pid_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/pid"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.PID
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// reading is a sensor event
+type reading float64
+
+func (reading) Name() string {
+	return "reading"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_PID_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if _, err := app.PID.NewController("bad", gopi.PIDGains{Kp: 1}, 0, 1, 0); err == nil {
+			t.Error("Expected error for limits")
+		}
+		if _, err := app.PID.NewController("bad", gopi.PIDGains{Kp: math.NaN()}, 0, 0, 1); err == nil {
+			t.Error("Expected error for gains")
+		}
+
+		// Proportional output is clamped
+		controller, err := app.PID.NewController("p", gopi.PIDGains{Kp: 2}, 10, -5, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Log(controller)
+		if output := controller.Update(8, 0); output != 4 {
+			t.Error("Unexpected output", output)
+		}
+		if output := controller.Update(0, time.Second); output != 5 {
+			t.Error("Unexpected output", output)
+		}
+		if output := controller.Update(20, time.Second); output != -5 {
+			t.Error("Unexpected output", output)
+		}
+		if output := controller.Output(); output != -5 {
+			t.Error("Unexpected output", output)
+		}
+	})
+}
+
+func Test_PID_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		controller, err := app.PID.NewController("pi", gopi.PIDGains{Kp: 1, Ki: 1}, 100, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Integral saturates at the maximum output
+		for i := 0; i < 100; i++ {
+			controller.Update(0, time.Second)
+		}
+		if output := controller.Output(); output != 10 {
+			t.Error("Unexpected output", output)
+		}
+
+		// Without windup, the output falls as soon as the process value
+		// passes the setpoint
+		controller.SetSetpoint(50)
+		if output := controller.Update(52, time.Second); output >= 10 {
+			t.Error("Unexpected output", output)
+		}
+
+		// Reset clears the integral
+		controller.Reset()
+		if output := controller.Update(50, time.Second); output != 0 {
+			t.Error("Unexpected output", output)
+		}
+	})
+}
+
+func Test_PID_003(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		controller, err := app.PID.NewController("pid", gopi.PIDGains{Kp: 1, Ki: 0.5, Kd: 0.1}, 20, -100, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		controller.Update(10, time.Second)
+		controller.Update(12, time.Second)
+		before := controller.Output()
+
+		// Changing gains does not change the output for the same error
+		if err := controller.SetGains(gopi.PIDGains{Kp: 4, Ki: 2, Kd: 1}); err != nil {
+			t.Error(err)
+		} else if gains := controller.Gains(); gains.Kp != 4 || gains.Ki != 2 || gains.Kd != 1 {
+			t.Error("Unexpected gains", gains)
+		}
+		if after := controller.Update(12, 0); math.Abs(after-before) > 1e-9 {
+			t.Error("Unexpected output", before, "=>", after)
+		}
+	})
+}
+
+func Test_PID_004(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		controller, err := app.PID.NewController("bind", gopi.PIDGains{Kp: 1}, 25, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs := make(chan float64, 10)
+		value := func(evt gopi.Event) (float64, bool) {
+			if evt, ok := evt.(reading); ok {
+				return float64(evt), true
+			}
+			return 0, false
+		}
+		output := func(value float64) error {
+			outputs <- value
+			return nil
+		}
+		if err := app.PID.Bind(controller, value, output); err != nil {
+			t.Error(err)
+		}
+		if err := app.PID.Bind(controller, value, output); err == nil {
+			t.Error("Expected error for duplicate binding")
+		}
+
+		// Wait for subscription before emitting
+		time.Sleep(100 * time.Millisecond)
+		app.Publisher.Emit(reading(20), true)
+		app.Publisher.Emit(reading(30), true)
+		for _, expected := range []float64{5, 0} {
+			select {
+			case output := <-outputs:
+				if output != expected {
+					t.Error("Unexpected output", output, "expected", expected)
+				}
+			case <-time.After(time.Second):
+				t.Error("Timeout waiting for", expected)
+			}
+		}
+
+		// No output after unbinding
+		if err := app.PID.Unbind(controller); err != nil {
+			t.Error(err)
+		} else if err := app.PID.Unbind(controller); err == nil {
+			t.Error("Expected error unbinding")
+		}
+		app.Publisher.Emit(reading(10), true)
+		select {
+		case output := <-outputs:
+			t.Error("Unexpected output", output)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
@@ -0,0 +1,14 @@
+// Pipeline package passes media or sensor data from a source through
+// transforms to sinks, such as audio capture to a wake word detector or
+// decode to scale to encode. Each transform and sink has a bounded queue
+// which either blocks the stages before it or drops items when full, so
+// a slow stage cannot stall capture or grow memory without limit. A stage
+// can have more than one stage after it, and counters are kept for each
+// stage.
+//
+// Items are owned by the pipeline once emitted, so a source must copy
+// data which is reused by a decoder, as ffextract does with decoded
+// frames. The recorder writes packets from the demuxer as they are read,
+// since packets are reused, and passes samples of decoded frames to a
+// motion detection stage which triggers recording.
+package pipeline
@@ -0,0 +1,329 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Pipeline passes items from a source through transforms to sinks,
+// with a bounded queue in front of each transform and sink
+type Pipeline struct {
+	sync.Mutex
+
+	stages []*Stage
+}
+
+// Stage is a source, transform or sink in a pipeline
+type Stage struct {
+	name     string
+	queue    Queue
+	source   SourceFunc
+	fn       TransformFunc
+	pipeline *Pipeline
+	next     []*Stage
+	ch       chan interface{}
+
+	// Counters
+	in, out, dropped, errors int64
+	busy                     int64
+}
+
+// Queue is the size of the queue in front of a stage, and what happens
+// when the queue is full
+type Queue struct {
+	Size   uint
+	Policy Policy
+}
+
+// Stats are the counters for a stage
+type Stats struct {
+	Name    string
+	In      int64         // Items received
+	Out     int64         // Items passed to the next stages
+	Dropped int64         // Items dropped when the queue was full
+	Errors  int64         // Items which returned an error
+	Queued  int           // Items in the queue
+	Busy    time.Duration // Time spent processing items
+}
+
+// Policy is what happens when the queue for a stage is full
+type Policy uint
+
+// EmitFunc passes an item from a source to the next stages, and returns
+// an error when the pipeline has stopped
+type EmitFunc func(interface{}) error
+
+// SourceFunc produces items until the context is done, or returns
+// nil when there are no more items
+type SourceFunc func(context.Context, EmitFunc) error
+
+// TransformFunc returns a new item, or nil to drop the item
+type TransformFunc func(context.Context, interface{}) (interface{}, error)
+
+// SinkFunc consumes an item
+type SinkFunc func(context.Context, interface{}) error
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	BLOCK       Policy = iota // Wait for space in the queue
+	DROP_NEWEST               // Drop the item being added
+	DROP_OLDEST               // Drop the oldest item in the queue
+)
+
+/////////////////////////////////////////////////////////////////////
+// NEW
+
+// New returns an empty pipeline
+func New() *Pipeline {
+	return new(Pipeline)
+}
+
+/////////////////////////////////////////////////////////////////////
+// BUILD
+
+// Source adds a stage which produces items
+func (this *Pipeline) Source(name string, fn SourceFunc) *Stage {
+	return this.add(&Stage{name: name, source: fn})
+}
+
+// Transform adds a stage after this stage which changes items. When
+// more than one stage is added after a stage, items are passed to all
+// of them
+func (this *Stage) Transform(name string, queue Queue, fn TransformFunc) *Stage {
+	stage := this.pipeline.add(&Stage{name: name, queue: queue, fn: fn})
+	this.next = append(this.next, stage)
+	return stage
+}
+
+// Sink adds a stage after this stage which consumes items
+func (this *Stage) Sink(name string, queue Queue, fn SinkFunc) *Stage {
+	return this.Transform(name, queue, func(ctx context.Context, item interface{}) (interface{}, error) {
+		return nil, fn(ctx, item)
+	})
+}
+
+func (this *Pipeline) add(stage *Stage) *Stage {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	stage.pipeline = this
+	this.stages = append(this.stages, stage)
+	return stage
+}
+
+/////////////////////////////////////////////////////////////////////
+// RUN
+
+// Run the pipeline until the sources end and all items have been
+// processed, the context is done or a stage returns an error. The first
+// error is returned
+func (this *Pipeline) Run(ctx context.Context) error {
+	this.Mutex.Lock()
+	stages := append([]*Stage{}, this.stages...)
+	this.Mutex.Unlock()
+
+	// Create queues
+	for _, stage := range stages {
+		if stage.source != nil {
+			continue
+		} else if stage.fn == nil {
+			return gopi.ErrBadParameter.WithPrefix("Run: ", stage.name)
+		}
+		stage.ch = make(chan interface{}, stage.queue.Size)
+	}
+
+	// The first error cancels the pipeline
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var once sync.Once
+	var result error
+	fail := func(stage *Stage, err error) {
+		once.Do(func() {
+			result = fmt.Errorf("%v: %w", stage.name, err)
+			cancel()
+		})
+	}
+
+	// Count inputs for each stage, so queues are closed when all the
+	// stages before them have ended
+	inputs := make(map[*Stage]*sync.WaitGroup, len(stages))
+	for _, stage := range stages {
+		for _, next := range stage.next {
+			if inputs[next] == nil {
+				inputs[next] = new(sync.WaitGroup)
+			}
+			inputs[next].Add(1)
+		}
+	}
+	for stage, wg := range inputs {
+		go func(stage *Stage, wg *sync.WaitGroup) {
+			wg.Wait()
+			close(stage.ch)
+		}(stage, wg)
+	}
+
+	// Run stages
+	var wg sync.WaitGroup
+	for _, stage := range stages {
+		wg.Add(1)
+		go func(stage *Stage) {
+			defer wg.Done()
+			defer func() {
+				for _, next := range stage.next {
+					inputs[next].Done()
+				}
+			}()
+			if err := stage.run(child); err != nil {
+				fail(stage, err)
+			}
+		}(stage)
+	}
+	wg.Wait()
+
+	// Return any error
+	if result == nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return result
+}
+
+// Stats returns counters for each stage
+func (this *Pipeline) Stats() []Stats {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	result := make([]Stats, 0, len(this.stages))
+	for _, stage := range this.stages {
+		result = append(result, stage.Stats())
+	}
+	return result
+}
+
+// Stats returns counters for a stage
+func (this *Stage) Stats() Stats {
+	return Stats{
+		Name:    this.name,
+		In:      atomic.LoadInt64(&this.in),
+		Out:     atomic.LoadInt64(&this.out),
+		Dropped: atomic.LoadInt64(&this.dropped),
+		Errors:  atomic.LoadInt64(&this.errors),
+		Queued:  len(this.ch),
+		Busy:    time.Duration(atomic.LoadInt64(&this.busy)),
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Pipeline) String() string {
+	str := "<pipeline"
+	for _, stats := range this.Stats() {
+		str += " " + fmt.Sprint(stats)
+	}
+	return str + ">"
+}
+
+func (this Stats) String() string {
+	str := "<stage"
+	str += fmt.Sprintf(" name=%q", this.Name)
+	str += fmt.Sprint(" in=", this.In, " out=", this.Out)
+	if this.Dropped > 0 {
+		str += fmt.Sprint(" dropped=", this.Dropped)
+	}
+	if this.Errors > 0 {
+		str += fmt.Sprint(" errors=", this.Errors)
+	}
+	if this.Queued > 0 {
+		str += fmt.Sprint(" queued=", this.Queued)
+	}
+	str += fmt.Sprint(" busy=", this.Busy.Truncate(time.Millisecond))
+	return str + ">"
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// run a source until it ends, or a transform until its queue is closed.
+// Items in the queue are discarded when the context is done
+func (this *Stage) run(ctx context.Context) error {
+	if this.source != nil {
+		return this.source(ctx, func(item interface{}) error {
+			atomic.AddInt64(&this.in, 1)
+			return this.emit(ctx, item)
+		})
+	}
+	for item := range this.ch {
+		if ctx.Err() != nil {
+			continue
+		}
+		start := time.Now()
+		result, err := this.fn(ctx, item)
+		atomic.AddInt64(&this.busy, int64(time.Since(start)))
+		if err != nil {
+			atomic.AddInt64(&this.errors, 1)
+			return err
+		} else if result != nil {
+			if err := this.emit(ctx, result); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// emit passes an item to the next stages
+func (this *Stage) emit(ctx context.Context, item interface{}) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	for _, next := range this.next {
+		if err := next.push(ctx, item); err != nil {
+			return err
+		}
+	}
+	atomic.AddInt64(&this.out, 1)
+	return nil
+}
+
+// push an item onto the queue for a stage, according to the policy
+// when the queue is full
+func (this *Stage) push(ctx context.Context, item interface{}) error {
+	atomic.AddInt64(&this.in, 1)
+	switch this.queue.Policy {
+	case DROP_NEWEST:
+		select {
+		case this.ch <- item:
+		default:
+			atomic.AddInt64(&this.dropped, 1)
+		}
+	case DROP_OLDEST:
+		for {
+			select {
+			case this.ch <- item:
+				return nil
+			default:
+			}
+			select {
+			case <-this.ch:
+				atomic.AddInt64(&this.dropped, 1)
+			default:
+			}
+		}
+	default:
+		select {
+		case this.ch <- item:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package pipeline_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pipeline "github.com/djthorpe/gopi/v3/pkg/pipeline"
+)
+
+// count returns a source which emits integers from zero to n-1
+func count(n int) pipeline.SourceFunc {
+	return func(ctx context.Context, emit pipeline.EmitFunc) error {
+		for i := 0; i < n; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func Test_Pipeline_001(t *testing.T) {
+	var sum, items int64
+	p := pipeline.New()
+	p.Source("count", count(100)).Transform("double", pipeline.Queue{}, func(_ context.Context, item interface{}) (interface{}, error) {
+		if item.(int)%2 == 1 {
+			return nil, nil
+		}
+		return item.(int) * 2, nil
+	}).Sink("sum", pipeline.Queue{Size: 10}, func(_ context.Context, item interface{}) error {
+		atomic.AddInt64(&sum, int64(item.(int)))
+		atomic.AddInt64(&items, 1)
+		return nil
+	})
+	if err := p.Run(context.Background()); err != nil {
+		t.Error(err)
+	} else if items != 50 || sum != 4900 {
+		t.Error("Unexpected items", items, "sum", sum)
+	} else if stats := p.Stats(); len(stats) != 3 || stats[0].Out != 100 || stats[1].In != 100 || stats[1].Out != 50 || stats[2].In != 50 {
+		t.Error("Unexpected stats", stats)
+	}
+	t.Log(p)
+}
+
+func Test_Pipeline_002(t *testing.T) {
+	// Fan out to two sinks
+	var a, b int64
+	p := pipeline.New()
+	source := p.Source("count", count(10))
+	source.Sink("a", pipeline.Queue{}, func(context.Context, interface{}) error {
+		atomic.AddInt64(&a, 1)
+		return nil
+	})
+	source.Sink("b", pipeline.Queue{}, func(context.Context, interface{}) error {
+		atomic.AddInt64(&b, 1)
+		return nil
+	})
+	if err := p.Run(context.Background()); err != nil {
+		t.Error(err)
+	} else if a != 10 || b != 10 {
+		t.Error("Unexpected items", a, b)
+	}
+}
+
+func Test_Pipeline_003(t *testing.T) {
+	// A slow sink drops items when the queue is full
+	for _, policy := range []pipeline.Policy{pipeline.DROP_NEWEST, pipeline.DROP_OLDEST} {
+		var last int64
+		p := pipeline.New()
+		p.Source("count", count(100)).Sink("slow", pipeline.Queue{Size: 2, Policy: policy}, func(_ context.Context, item interface{}) error {
+			time.Sleep(time.Millisecond)
+			atomic.StoreInt64(&last, int64(item.(int)))
+			return nil
+		})
+		if err := p.Run(context.Background()); err != nil {
+			t.Error(err)
+		} else if stats := p.Stats(); stats[1].Dropped == 0 || stats[1].In != 100 {
+			t.Error("Expected dropped items", stats)
+		} else if policy == pipeline.DROP_OLDEST && last != 99 {
+			t.Error("Expected last item to be processed, got", last)
+		} else if policy == pipeline.DROP_NEWEST && last == 99 {
+			t.Error("Expected last item to be dropped")
+		}
+	}
+}
+
+func Test_Pipeline_004(t *testing.T) {
+	// An error stops the pipeline and is returned
+	errTest := errors.New("test")
+	p := pipeline.New()
+	p.Source("forever", func(ctx context.Context, emit pipeline.EmitFunc) error {
+		for {
+			if err := emit(struct{}{}); err != nil {
+				return nil
+			}
+		}
+	}).Sink("fail", pipeline.Queue{Size: 1}, func(context.Context, interface{}) error {
+		return errTest
+	})
+	if err := p.Run(context.Background()); errors.Is(err, errTest) == false {
+		t.Error("Unexpected error", err)
+	} else if stats := p.Stats(); stats[1].Errors != 1 {
+		t.Error("Unexpected stats", stats)
+	}
+}
+
+func Test_Pipeline_005(t *testing.T) {
+	// Cancelling the context stops a blocked source
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	p := pipeline.New()
+	p.Source("forever", func(ctx context.Context, emit pipeline.EmitFunc) error {
+		for {
+			if err := emit(struct{}{}); err != nil {
+				return nil
+			}
+		}
+	}).Sink("wait", pipeline.Queue{}, func(ctx context.Context, _ interface{}) error {
+		<-ctx.Done()
+		return nil
+	})
+	if err := p.Run(ctx); err != context.DeadlineExceeded {
+		t.Error("Unexpected error", err)
+	}
+}
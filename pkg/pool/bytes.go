@@ -0,0 +1,148 @@
+package pool
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Bytes keeps byte slices in pools by size, such as pixel buffers
+// for bitmaps
+type Bytes struct {
+	sync.Mutex
+
+	name  string
+	max   uint
+	debug bool
+	pools map[int]*Pool
+}
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	minBytes = 4096
+)
+
+/////////////////////////////////////////////////////////////////////
+// NEW
+
+// NewBytes returns a pool of byte slices, which keeps up to max idle
+// slices of each size, or all slices when max is zero
+func NewBytes(name string, max uint) *Bytes {
+	this := new(Bytes)
+	this.name = name
+	this.max = max
+	this.pools = make(map[int]*Pool)
+	return this
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Get returns a buffer of zeroed bytes of at least the size, which
+// is rounded up to a multiple of the page size
+func (this *Bytes) Get(size int) (*Buffer, error) {
+	this.Mutex.Lock()
+	n := roundUp(size)
+	pool, exists := this.pools[n]
+	if exists == false {
+		pool = New(fmt.Sprint(this.name, "/", n), this.max, func() (interface{}, error) {
+			return make([]byte, n), nil
+		}, func(value interface{}) {
+			data := value.([]byte)
+			for i := range data {
+				data[i] = 0
+			}
+		}, nil)
+		pool.SetDebug(this.debug)
+		this.pools[n] = pool
+	}
+	this.Mutex.Unlock()
+
+	if buf, err := pool.Get(); err != nil {
+		return nil, err
+	} else {
+		buf.size = size
+		return buf, nil
+	}
+}
+
+// SetDebug records where each buffer was returned, so that buffers which
+// are not released can be found with Leaks
+func (this *Bytes) SetDebug(debug bool) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.debug = debug
+	for _, pool := range this.pools {
+		pool.SetDebug(debug)
+	}
+}
+
+// Leaks returns where buffers which have not been released were returned
+// from Get
+func (this *Bytes) Leaks() []string {
+	result := []string{}
+	for _, pool := range this.sorted() {
+		result = append(result, pool.Leaks()...)
+	}
+	return result
+}
+
+// Stats returns the counters for each size of buffer
+func (this *Bytes) Stats() []Stats {
+	result := []Stats{}
+	for _, pool := range this.sorted() {
+		result = append(result, pool.Stats())
+	}
+	return result
+}
+
+// Close frees idle buffers, and returns an error if any buffers have
+// not been released
+func (this *Bytes) Close() error {
+	return closeAll(this.sorted())
+}
+
+/////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Bytes) String() string {
+	str := "<pool.bytes"
+	str += fmt.Sprintf(" name=%q", this.name)
+	for _, stats := range this.Stats() {
+		str += " " + fmt.Sprint(stats)
+	}
+	return str + ">"
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// sorted returns the pools in order of size
+func (this *Bytes) sorted() []*Pool {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	sizes := make([]int, 0, len(this.pools))
+	for size := range this.pools {
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes)
+	result := make([]*Pool, len(sizes))
+	for i, size := range sizes {
+		result[i] = this.pools[size]
+	}
+	return result
+}
+
+// roundUp returns the size rounded up to a multiple of the page size
+func roundUp(size int) int {
+	if size <= 0 {
+		return minBytes
+	}
+	return (size + minBytes - 1) / minBytes * minBytes
+}
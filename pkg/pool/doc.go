@@ -0,0 +1,7 @@
+// Pool package implements reference-counted buffers which are returned
+// to a pool when released, so that long-running media and camera
+// pipelines reuse frames, packets and pixel buffers rather than
+// allocating them for every frame. In debug mode a pool records where
+// each buffer was taken, so that buffers which are never released can
+// be reported.
+package pool
@@ -0,0 +1,275 @@
+package pool
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Pool keeps released values so they can be used again, rather than
+// allocating new ones
+type Pool struct {
+	sync.Mutex
+
+	name   string
+	max    uint
+	alloc  AllocFunc
+	reset  ReleaseFunc
+	free   ReleaseFunc
+	debug  bool
+	idle   []interface{}
+	used   map[*Buffer]string
+	allocs int64
+	gets   int64
+}
+
+// Buffer is a reference-counted value from a pool, which is returned
+// to the pool when the last reference is released
+type Buffer struct {
+	pool  *Pool
+	value interface{}
+	size  int
+	refs  int32
+}
+
+// Stats are the counters for a pool
+type Stats struct {
+	Name      string
+	Allocated int64 // Values allocated
+	Gets      int64 // Buffers returned from Get
+	InUse     int   // Buffers which have not been released
+	Idle      int   // Values which are kept for use again
+}
+
+// AllocFunc returns a new value for a pool
+type AllocFunc func() (interface{}, error)
+
+// ReleaseFunc resets a value before it is used again, or frees a value
+// which is no longer kept by the pool
+type ReleaseFunc func(interface{})
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	stackDepth = 8
+)
+
+/////////////////////////////////////////////////////////////////////
+// NEW
+
+// New returns a pool which keeps up to max idle values, or all values
+// when max is zero. The reset function is called when a value is
+// released, and the free function when a value is discarded, and either
+// can be nil
+func New(name string, max uint, alloc AllocFunc, reset, free ReleaseFunc) *Pool {
+	this := new(Pool)
+	this.name = name
+	this.max = max
+	this.alloc = alloc
+	this.reset = reset
+	this.free = free
+	this.used = make(map[*Buffer]string)
+	return this
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Get returns an idle value from the pool, or allocates a new one,
+// with a reference count of one
+func (this *Pool) Get() (*Buffer, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	var value interface{}
+	if n := len(this.idle); n > 0 {
+		value = this.idle[n-1]
+		this.idle = this.idle[:n-1]
+	} else if v, err := this.alloc(); err != nil {
+		return nil, err
+	} else if v == nil {
+		return nil, gopi.ErrInternalAppError.WithPrefix("Get: ", this.name)
+	} else {
+		value = v
+		this.allocs++
+	}
+
+	// Record where the buffer was returned in debug mode
+	buf := &Buffer{pool: this, value: value, refs: 1}
+	if this.debug {
+		this.used[buf] = caller(3)
+	} else {
+		this.used[buf] = ""
+	}
+	this.gets++
+
+	// Return success
+	return buf, nil
+}
+
+// SetDebug records where each buffer was returned, so that buffers which
+// are not released can be found with Leaks
+func (this *Pool) SetDebug(debug bool) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.debug = debug
+}
+
+// Leaks returns where buffers which have not been released were returned
+// from Get, or the number of buffers when not in debug mode
+func (this *Pool) Leaks() []string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	result := make([]string, 0, len(this.used))
+	for _, stack := range this.used {
+		if stack == "" {
+			stack = "<unknown>"
+		}
+		result = append(result, this.name+": "+stack)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Stats returns the counters for the pool
+func (this *Pool) Stats() Stats {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return Stats{this.name, this.allocs, this.gets, len(this.used), len(this.idle)}
+}
+
+// Close frees idle values, and returns an error if any buffers have
+// not been released
+func (this *Pool) Close() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	for _, value := range this.idle {
+		if this.free != nil {
+			this.free(value)
+		}
+	}
+	this.idle = nil
+	if n := len(this.used); n > 0 {
+		return gopi.ErrOutOfOrder.WithPrefix("Close: ", this.name, ": ", n, " buffers not released")
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////
+// BUFFER
+
+// Value returns the value for the buffer, or nil if it has been
+// released
+func (this *Buffer) Value() interface{} {
+	if atomic.LoadInt32(&this.refs) <= 0 {
+		return nil
+	}
+	return this.value
+}
+
+// Bytes returns the value as a byte slice of the size requested from
+// a byte pool
+func (this *Buffer) Bytes() []byte {
+	if data, ok := this.Value().([]byte); ok {
+		return data[:this.size]
+	}
+	return nil
+}
+
+// Retain adds a reference to the buffer, for passing it to another
+// goroutine which will release it
+func (this *Buffer) Retain() *Buffer {
+	atomic.AddInt32(&this.refs, 1)
+	return this
+}
+
+// Release removes a reference to the buffer, returning it to the pool
+// when there are no more references
+func (this *Buffer) Release() error {
+	if refs := atomic.AddInt32(&this.refs, -1); refs > 0 {
+		return nil
+	} else if refs < 0 {
+		return gopi.ErrOutOfOrder.WithPrefix("Release: ", this.pool.name)
+	}
+	this.pool.put(this)
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Pool) String() string {
+	return fmt.Sprint(this.Stats())
+}
+
+func (this Stats) String() string {
+	str := "<pool"
+	str += fmt.Sprintf(" name=%q", this.Name)
+	str += fmt.Sprint(" allocated=", this.Allocated)
+	str += fmt.Sprint(" gets=", this.Gets)
+	str += fmt.Sprint(" in_use=", this.InUse)
+	str += fmt.Sprint(" idle=", this.Idle)
+	return str + ">"
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// put returns the value for a released buffer to the pool, or frees it
+// if the pool is full
+func (this *Pool) put(buf *Buffer) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	delete(this.used, buf)
+	if this.reset != nil {
+		this.reset(buf.value)
+	}
+	if this.max == 0 || uint(len(this.idle)) < this.max {
+		this.idle = append(this.idle, buf.value)
+	} else if this.free != nil {
+		this.free(buf.value)
+	}
+}
+
+// caller returns the functions which called Get, skipping frames
+// within the pool
+func caller(skip int) string {
+	pc := make([]uintptr, stackDepth)
+	n := runtime.Callers(skip, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	result := []string{}
+	for {
+		frame, more := frames.Next()
+		if strings.HasPrefix(frame.Function, "runtime.") == false {
+			result = append(result, fmt.Sprintf("%v (%v:%v)", frame.Function, frame.File, frame.Line))
+		}
+		if more == false {
+			break
+		}
+	}
+	return strings.Join(result, " <- ")
+}
+
+// closeAll closes pools and returns any errors
+func closeAll(pools []*Pool) error {
+	var result error
+	for _, pool := range pools {
+		if err := pool.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package pool_test
+
+import (
+	"strings"
+	"testing"
+
+	pool "github.com/djthorpe/gopi/v3/pkg/pool"
+)
+
+func Test_Pool_001(t *testing.T) {
+	var freed int
+	p := pool.New("test", 1, func() (interface{}, error) {
+		return new(int), nil
+	}, nil, func(interface{}) {
+		freed++
+	})
+	a, err := p.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := p.Get()
+	value := a.Value()
+
+	// Values are returned to the pool when the last reference is released
+	a.Retain()
+	if err := a.Release(); err != nil {
+		t.Error(err)
+	} else if stats := p.Stats(); stats.InUse != 2 || stats.Idle != 0 {
+		t.Error("Unexpected stats", stats)
+	} else if err := a.Release(); err != nil {
+		t.Error(err)
+	} else if a.Value() != nil {
+		t.Error("Expected nil value after release")
+	} else if err := a.Release(); err == nil {
+		t.Error("Expected error releasing twice")
+	}
+
+	// Idle values are used again, and values are freed when the pool is full
+	if c, _ := p.Get(); c.Value() != value {
+		t.Error("Expected value to be used again")
+	} else if err := b.Release(); err != nil {
+		t.Error(err)
+	} else if err := c.Release(); err != nil {
+		t.Error(err)
+	} else if stats := p.Stats(); stats.Allocated != 2 || stats.Gets != 3 || stats.InUse != 0 || stats.Idle != 1 || freed != 1 {
+		t.Error("Unexpected stats", stats, freed)
+	} else if err := p.Close(); err != nil {
+		t.Error(err)
+	} else if freed != 2 {
+		t.Error("Expected idle value to be freed")
+	}
+	t.Log(p)
+}
+
+func Test_Pool_002(t *testing.T) {
+	p := pool.New("test", 0, func() (interface{}, error) {
+		return new(int), nil
+	}, nil, nil)
+	p.SetDebug(true)
+	if _, err := p.Get(); err != nil {
+		t.Fatal(err)
+	} else if leaks := p.Leaks(); len(leaks) != 1 || strings.Contains(leaks[0], "Test_Pool_002") == false {
+		t.Error("Unexpected leaks", leaks)
+	} else if err := p.Close(); err == nil {
+		t.Error("Expected error closing pool with buffers in use")
+	} else {
+		t.Log(leaks)
+	}
+}
+
+func Test_Pool_003(t *testing.T) {
+	p := pool.NewBytes("pixels", 0)
+	a, err := p.Get(100)
+	if err != nil {
+		t.Fatal(err)
+	} else if data := a.Bytes(); len(data) != 100 {
+		t.Error("Unexpected length", len(data))
+	} else {
+		data[0] = 0xFF
+	}
+
+	// Released buffers are zeroed before they are used again
+	a.Release()
+	if b, _ := p.Get(200); len(b.Bytes()) != 200 || b.Bytes()[0] != 0 {
+		t.Error("Unexpected buffer", b.Bytes()[0])
+	} else if stats := p.Stats(); len(stats) != 1 || stats[0].Allocated != 1 {
+		t.Error("Unexpected stats", stats)
+	} else if c, _ := p.Get(10000); len(c.Bytes()) != 10000 {
+		t.Error("Unexpected length", len(c.Bytes()))
+	} else if stats := p.Stats(); len(stats) != 2 {
+		t.Error("Unexpected stats", stats)
+	} else if leaks := p.Leaks(); len(leaks) != 2 {
+		t.Error("Unexpected leaks", leaks)
+	} else {
+		b.Release()
+		c.Release()
+	}
+	if err := p.Close(); err != nil {
+		t.Error(err)
+	}
+	t.Log(p)
+}
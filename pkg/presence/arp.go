@@ -0,0 +1,51 @@
+package presence
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Ref: https://man7.org/linux/man-pages/man7/arp.7.html
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	arpFlagComplete = 0x02
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// readARP returns complete entries in the ARP table, keyed by
+// hardware address
+func readARP(path string) (map[string]net.IP, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	// Fields are address, type, flags, hardware address, mask and device
+	// and the first line is a header
+	result := make(map[string]net.IP)
+	scanner := bufio.NewScanner(fh)
+	for header := true; scanner.Scan(); header = false {
+		fields := strings.Fields(scanner.Text())
+		if header || len(fields) < 4 {
+			continue
+		}
+		ip := net.ParseIP(fields[0])
+		flags, err := strconv.ParseUint(fields[2], 0, 32)
+		if err != nil || ip == nil || flags&arpFlagComplete == 0 {
+			continue
+		}
+		if mac, err := net.ParseMAC(fields[3]); err == nil {
+			result[mac.String()] = ip
+		}
+	}
+	return result, scanner.Err()
+}
@@ -0,0 +1,115 @@
+package presence
+
+import (
+	"context"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://git.kernel.org/pub/scm/bluetooth/bluez.git/tree/doc/adapter-api.txt
+// Ref: https://git.kernel.org/pub/scm/bluetooth/bluez.git/tree/doc/device-api.txt
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	bluezService       = "org.bluez"
+	bluezAdapter       = "org.bluez.Adapter1"
+	bluezDevice        = "org.bluez.Device1"
+	propertiesIface    = "org.freedesktop.DBus.Properties"
+	propertiesChanged  = "PropertiesChanged"
+	objectManagerIface = "org.freedesktop.DBus.ObjectManager"
+	interfacesAdded    = "InterfacesAdded"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// adapterPath returns the object path for the adapter
+func (this *presence) adapterPath() gopi.DBusObjectPath {
+	return gopi.DBusObjectPath("/org/bluez/" + *this.adapter)
+}
+
+// subscribeBLE subscribes to device signals from BlueZ
+func (this *presence) subscribeBLE() error {
+	if err := this.DBus.Subscribe(gopi.DBUS_BUS_SYSTEM, propertiesIface, propertiesChanged, ""); err != nil {
+		return err
+	} else if err := this.DBus.Subscribe(gopi.DBUS_BUS_SYSTEM, objectManagerIface, interfacesAdded, ""); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+// discoverBLE starts discovery of Bluetooth LE devices, reporting
+// every advertisement so that the signal strength is updated. It is
+// called on each scan in case discovery was stopped by another client
+func (this *presence) discoverBLE(ctx context.Context) error {
+	path := this.adapterPath()
+	filter := map[string]gopi.DBusVariant{
+		"Transport":     {Value: "le"},
+		"DuplicateData": {Value: true},
+	}
+	if _, err := this.DBus.Call(ctx, gopi.DBUS_BUS_SYSTEM, bluezService, path, bluezAdapter+".SetDiscoveryFilter", filter); err != nil {
+		return err
+	} else if discovering, err := this.DBus.Call(ctx, gopi.DBUS_BUS_SYSTEM, bluezService, path, propertiesIface+".Get", bluezAdapter, "Discovering"); err != nil {
+		return err
+	} else if len(discovering) == 1 && discovering[0] == true {
+		return nil
+	} else if _, err := this.DBus.Call(ctx, gopi.DBUS_BUS_SYSTEM, bluezService, path, bluezAdapter+".StartDiscovery"); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+// stopBLE stops discovery
+func (this *presence) stopBLE(ctx context.Context) error {
+	_, err := this.DBus.Call(ctx, gopi.DBUS_BUS_SYSTEM, bluezService, this.adapterPath(), bluezAdapter+".StopDiscovery")
+	return err
+}
+
+// signal returns the address and signal strength of a device from
+// a BlueZ signal, or false if the signal does not report an RSSI
+func (this *presence) signal(evt gopi.DBusSignal) (string, int, bool) {
+	var path gopi.DBusObjectPath
+	var props interface{}
+	args := evt.Args()
+	switch {
+	case evt.Interface() == propertiesIface && evt.Member() == propertiesChanged:
+		// Arguments are interface, changed properties and invalidated properties
+		if len(args) < 2 || args[0] != bluezDevice {
+			return "", 0, false
+		}
+		path, props = evt.Path(), args[1]
+	case evt.Interface() == objectManagerIface && evt.Member() == interfacesAdded:
+		// Arguments are path and properties for each interface
+		if len(args) < 2 {
+			return "", 0, false
+		} else if ifaces, ok := args[1].(map[interface{}]interface{}); ok {
+			path, _ = args[0].(gopi.DBusObjectPath)
+			props = ifaces[bluezDevice]
+		}
+	default:
+		return "", 0, false
+	}
+
+	// Device paths are /org/bluez/<adapter>/dev_XX_XX_XX_XX_XX_XX
+	prefix := string(this.adapterPath()) + "/dev_"
+	if strings.HasPrefix(string(path), prefix) == false {
+		return "", 0, false
+	}
+	addr := strings.Replace(strings.TrimPrefix(string(path), prefix), "_", ":", -1)
+	if props, ok := props.(map[interface{}]interface{}); ok {
+		switch rssi := props["RSSI"].(type) {
+		case int16:
+			return addr, int(rssi), true
+		case int32:
+			return addr, int(rssi), true
+		}
+	}
+	return "", 0, false
+}
@@ -0,0 +1,25 @@
+// Presence package implements gopi.Presence, which tracks whether people
+// are home by detecting their phones and beacons. People are read from
+// a JSON file set with the -presence.people flag, which maps a name to
+// a hardware address, an IP address and a Bluetooth LE address:
+//
+//	{
+//	  "alice": { "mac": "a4:83:e7:12:34:56", "ip": "192.168.1.20" },
+//	  "bob": { "ble": "c8:fd:19:ab:cd:ef" }
+//	}
+//
+// On each scan the ARP table is read and devices are pinged. Bluetooth
+// LE advertisements are received from BlueZ when gopi.DBus and
+// gopi.Publisher are available. Phones which use random Bluetooth
+// addresses cannot be tracked over Bluetooth, so a beacon with a fixed
+// address should be used instead.
+//
+// A person arrives after they are detected on a number of consecutive
+// scans, and leaves when they have not been detected for a duration, so
+// that a phone which sleeps does not cause a departure. A
+// gopi.PresenceEvent is emitted when a person arrives or leaves.
+//
+// When a gopi.LightSensor unit is reading an ambient light sensor, a
+// scan is also made when the lights in the room are switched on, so that
+// arrivals are detected without waiting for the next scan.
+package presence
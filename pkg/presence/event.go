@@ -0,0 +1,44 @@
+package presence
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t      gopi.PresenceEventType
+	person gopi.PresencePerson
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(t gopi.PresenceEventType, person gopi.PresencePerson) gopi.PresenceEvent {
+	return &event{t, person}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.person.Name()
+}
+
+func (this *event) Type() gopi.PresenceEventType {
+	return this.t
+}
+
+func (this *event) Person() gopi.PresencePerson {
+	return this.person
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	return fmt.Sprint("<presence.event type=", this.t, " ", this.person, ">")
+}
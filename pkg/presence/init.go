@@ -0,0 +1,13 @@
+package presence
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.Presence
+	graph.RegisterUnit(reflect.TypeOf(&presence{}), reflect.TypeOf((*gopi.Presence)(nil)))
+}
@@ -0,0 +1,201 @@
+package presence
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type person struct {
+	sync.RWMutex
+
+	name string
+	mac  net.HardwareAddr
+	ip   net.IP
+	ble  string
+
+	state  gopi.PresenceState
+	since  time.Time
+	seen   time.Time
+	method gopi.PresenceMethod
+	count  uint
+
+	// Last Bluetooth LE advertisement
+	rssi    int
+	bleSeen time.Time
+}
+
+// config is the entry for a person in the people file
+type config struct {
+	Mac string `json:"mac"`
+	IP  string `json:"ip"`
+	BLE string `json:"ble"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newPerson(name string, cfg config, now time.Time) (*person, error) {
+	this := &person{name: name, since: now}
+	if cfg.Mac != "" {
+		if mac, err := net.ParseMAC(cfg.Mac); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": ", cfg.Mac)
+		} else {
+			this.mac = mac
+		}
+	}
+	if cfg.IP != "" {
+		if ip := net.ParseIP(cfg.IP).To4(); ip == nil {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": ", cfg.IP)
+		} else {
+			this.ip = ip
+		}
+	}
+	if cfg.BLE != "" {
+		if ble, err := net.ParseMAC(cfg.BLE); err != nil || len(ble) != 6 {
+			return nil, gopi.ErrBadParameter.WithPrefix(name, ": ", cfg.BLE)
+		} else {
+			this.ble = strings.ToUpper(ble.String())
+		}
+	}
+	if this.mac == nil && this.ip == nil && this.ble == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix(name, ": No addresses")
+	}
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *person) Name() string {
+	return this.name
+}
+
+func (this *person) State() gopi.PresenceState {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.state
+}
+
+func (this *person) Since() time.Time {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.since
+}
+
+func (this *person) Seen() time.Time {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.seen
+}
+
+func (this *person) Method() gopi.PresenceMethod {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.method
+}
+
+func (this *person) RSSI() int {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.rssi
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *person) String() string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	str := "<presence.person"
+	str += fmt.Sprintf(" name=%q", this.name)
+	str += fmt.Sprint(" state=", this.state)
+	if this.mac != nil {
+		str += fmt.Sprint(" mac=", this.mac)
+	}
+	if this.ip != nil {
+		str += fmt.Sprint(" ip=", this.ip)
+	}
+	if this.ble != "" {
+		str += fmt.Sprint(" ble=", this.ble)
+	}
+	if this.method != gopi.PRESENCE_METHOD_NONE {
+		str += fmt.Sprint(" method=", this.method)
+	}
+	if this.rssi != 0 {
+		str += fmt.Sprint(" rssi=", this.rssi)
+	}
+	if this.seen.IsZero() == false {
+		str += fmt.Sprint(" seen=", this.seen.Format(time.RFC3339))
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// advertisement records a Bluetooth LE advertisement
+func (this *person) advertisement(rssi int, now time.Time) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	this.rssi = rssi
+	this.bleSeen = now
+}
+
+// bluetooth returns true if an advertisement was received since a
+// time with a signal at or above a threshold. The threshold is lowered
+// for a person who is home, so that a weak signal does not cause a
+// departure
+func (this *person) bluetooth(since time.Time, threshold, hysteresis int) bool {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	if this.ble == "" || this.bleSeen.Before(since) {
+		return false
+	}
+	if this.state == gopi.PRESENCE_STATE_HOME {
+		threshold -= hysteresis
+	}
+	return this.rssi >= threshold
+}
+
+// update sets the methods which detected the person in a scan, and
+// returns the new state if the state changed, or PRESENCE_STATE_UNKNOWN
+func (this *person) update(method gopi.PresenceMethod, now time.Time, arrive uint, away time.Duration) gopi.PresenceState {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	this.method = method
+	state := this.state
+	if method != gopi.PRESENCE_METHOD_NONE {
+		this.seen = now
+		this.count++
+		if this.count >= arrive {
+			state = gopi.PRESENCE_STATE_HOME
+		}
+	} else {
+		this.count = 0
+		last := this.seen
+		if last.IsZero() || this.state == gopi.PRESENCE_STATE_UNKNOWN {
+			last = this.since
+		}
+		if this.state != gopi.PRESENCE_STATE_AWAY && now.Sub(last) >= away {
+			state = gopi.PRESENCE_STATE_AWAY
+		}
+	}
+
+	// Return the state if changed
+	if state == this.state {
+		return gopi.PRESENCE_STATE_UNKNOWN
+	}
+	this.state = state
+	this.since = now
+	return state
+}
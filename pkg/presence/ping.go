@@ -0,0 +1,147 @@
+package presence
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"time"
+)
+
+// Ref: https://tools.ietf.org/html/rfc792
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type pinger struct {
+	conn  net.PacketConn
+	dgram bool // Unprivileged socket, where the kernel sets the identifier
+	id    uint16
+	seq   uint16
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	icmpEchoReply   = 0
+	icmpEchoRequest = 8
+	icmpHeaderSize  = 8
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newPinger() (*pinger, error) {
+	this := new(pinger)
+	if conn, dgram, err := listenICMP(); err != nil {
+		return nil, err
+	} else {
+		this.conn, this.dgram = conn, dgram
+	}
+	this.id = uint16(os.Getpid())
+	return this, nil
+}
+
+func (this *pinger) Close() error {
+	return this.conn.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Ping sends an echo request to each address and returns the addresses
+// which replied before the deadline or the context was cancelled
+func (this *pinger) Ping(ctx context.Context, addrs []net.IP, timeout time.Duration) (map[string]bool, error) {
+	result := make(map[string]bool, len(addrs))
+	if len(addrs) == 0 {
+		return result, nil
+	}
+
+	// Send requests, recording the sequence number for each address
+	requests := make(map[uint16]string, len(addrs))
+	for _, ip := range addrs {
+		this.seq++
+		if _, err := this.conn.WriteTo(this.request(this.seq), this.addr(ip)); err != nil {
+			return nil, err
+		}
+		requests[this.seq] = ip.String()
+	}
+
+	// Read replies until the deadline
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := this.conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 1500)
+	for len(result) < len(requests) {
+		n, from, err := this.conn.ReadFrom(buf)
+		if err, ok := err.(net.Error); ok && err.Timeout() {
+			break
+		} else if err != nil {
+			return nil, err
+		} else if n < icmpHeaderSize || buf[0] != icmpEchoReply {
+			continue
+		} else if this.dgram == false && binary.BigEndian.Uint16(buf[4:]) != this.id {
+			continue
+		}
+		seq := binary.BigEndian.Uint16(buf[6:])
+		if addr, exists := requests[seq]; exists && addr == hostOf(from) {
+			result[addr] = true
+		}
+	}
+
+	// Return addresses which replied
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// request returns an echo request with a sequence number
+func (this *pinger) request(seq uint16) []byte {
+	msg := make([]byte, icmpHeaderSize)
+	msg[0] = icmpEchoRequest
+	binary.BigEndian.PutUint16(msg[4:], this.id)
+	binary.BigEndian.PutUint16(msg[6:], seq)
+	binary.BigEndian.PutUint16(msg[2:], checksum(msg))
+	return msg
+}
+
+// addr returns the destination address for the socket type
+func (this *pinger) addr(ip net.IP) net.Addr {
+	if this.dgram {
+		return &net.UDPAddr{IP: ip}
+	} else {
+		return &net.IPAddr{IP: ip}
+	}
+}
+
+func hostOf(addr net.Addr) string {
+	switch addr := addr.(type) {
+	case *net.UDPAddr:
+		return addr.IP.String()
+	case *net.IPAddr:
+		return addr.IP.String()
+	default:
+		return ""
+	}
+}
+
+// checksum returns the internet checksum of a message
+func checksum(msg []byte) uint16 {
+	sum := uint32(0)
+	for i := 0; i+1 < len(msg); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(msg[i:]))
+	}
+	if len(msg)&1 == 1 {
+		sum += uint32(msg[len(msg)-1]) << 8
+	}
+	for sum > 0xFFFF {
+		sum = (sum >> 16) + (sum & 0xFFFF)
+	}
+	return ^uint16(sum)
+}
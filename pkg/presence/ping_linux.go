@@ -0,0 +1,30 @@
+// +build linux
+
+package presence
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// listenICMP opens an unprivileged ICMP socket, which is permitted for
+// groups in net.ipv4.ping_group_range, or else a raw socket which
+// requires CAP_NET_RAW
+func listenICMP() (net.PacketConn, bool, error) {
+	if fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, syscall.IPPROTO_ICMP); err == nil {
+		file := os.NewFile(uintptr(fd), "icmp")
+		defer file.Close()
+		if conn, err := net.FilePacketConn(file); err == nil {
+			return conn, true, nil
+		}
+	}
+	if conn, err := net.ListenPacket("ip4:icmp", "0.0.0.0"); err != nil {
+		return nil, false, err
+	} else {
+		return conn, false, nil
+	}
+}
@@ -0,0 +1,19 @@
+// +build !linux
+
+package presence
+
+import (
+	"net"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// listenICMP opens a raw socket, which requires root privileges
+func listenICMP() (net.PacketConn, bool, error) {
+	if conn, err := net.ListenPacket("ip4:icmp", "0.0.0.0"); err != nil {
+		return nil, false, err
+	} else {
+		return conn, false, nil
+	}
+}
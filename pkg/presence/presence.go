@@ -0,0 +1,378 @@
+package presence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type presence struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.DBus
+	sync.RWMutex
+
+	// Flags
+	path     *string
+	interval *time.Duration
+	away     *time.Duration
+	arrive   *uint
+	arp      *string
+	ping     *bool
+	timeout  *time.Duration
+	adapter  *string
+	rssi     *int
+
+	people map[string]*person
+	pinger *pinger
+	bluez  bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Signal strength margin for a person who is home
+	rssiHysteresis = 10
+
+	// Illuminance in lux below which the lights are off, and above which
+	// they have been switched on
+	lightsOff = 10
+	lightsOn  = 50
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *presence) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagPath("presence.people", "", "JSON file of people and their addresses")
+	this.interval = cfg.FlagDuration("presence.interval", 30*time.Second, "Interval between scans")
+	this.away = cfg.FlagDuration("presence.away", 5*time.Minute, "Time without detection before a person leaves")
+	this.arrive = cfg.FlagUint("presence.arrive", 1, "Consecutive scans with detection before a person arrives")
+	this.arp = cfg.FlagString("presence.arp", "/proc/net/arp", "ARP table, or empty to disable")
+	this.ping = cfg.FlagBool("presence.ping", true, "Ping devices on each scan")
+	this.timeout = cfg.FlagDuration("presence.timeout", 2*time.Second, "Timeout for ping replies")
+	this.adapter = cfg.FlagString("presence.adapter", "hci0", "Bluetooth adapter, or empty to disable")
+	this.rssi = cfg.FlagInt("presence.rssi", -90, "Minimum Bluetooth signal strength in dBm")
+	return nil
+}
+
+func (this *presence) New(cfg gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if *this.path == "" {
+		return gopi.ErrBadParameter.WithPrefix("-presence.people")
+	} else if *this.interval <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-presence.interval")
+	} else if *this.away < *this.interval {
+		return gopi.ErrBadParameter.WithPrefix("-presence.away")
+	} else if *this.arrive == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-presence.arrive")
+	}
+
+	// Read people
+	if people, err := readPeople(*this.path, time.Now()); err != nil {
+		return err
+	} else {
+		this.people = people
+	}
+
+	// Open socket for ping, which may not be permitted
+	if *this.ping {
+		if pinger, err := newPinger(); err != nil {
+			this.Print("Presence: Ping disabled: ", err)
+		} else {
+			this.pinger = pinger
+		}
+	}
+
+	// Receive Bluetooth LE advertisements when anyone has a beacon
+	if this.DBus != nil && this.Publisher != nil && *this.adapter != "" && this.hasBLE() {
+		if err := this.subscribeBLE(); err != nil {
+			this.Print("Presence: Bluetooth disabled: ", err)
+		} else {
+			this.bluez = true
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *presence) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	var result error
+	if this.pinger != nil {
+		result = this.pinger.Close()
+	}
+
+	// Release resources
+	this.pinger = nil
+	this.people = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *presence) Run(ctx context.Context) error {
+	var ch <-chan gopi.Event
+	if this.Publisher != nil {
+		ch = this.Publisher.Subscribe()
+		defer this.Publisher.Unsubscribe(ch)
+	}
+	if this.bluez {
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), *this.timeout)
+			defer cancel()
+			if err := this.stopBLE(ctx); err != nil {
+				this.Debug("Presence: ", err)
+			}
+		}()
+	}
+
+	timer := time.NewTimer(time.Nanosecond)
+	defer timer.Stop()
+
+	dark := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-ch:
+			switch evt := evt.(type) {
+			case gopi.DBusSignal:
+				if this.bluez {
+					if addr, rssi, ok := this.signal(evt); ok {
+						this.advertisement(addr, rssi)
+					}
+				}
+			case gopi.LightEvent:
+				// Scan when the lights are switched on, as someone has
+				// probably come into the room
+				if evt.Lux() < lightsOff {
+					dark = true
+				} else if dark && evt.Lux() >= lightsOn {
+					dark = false
+					if err := this.scan(ctx); err != nil {
+						this.Print("Presence: ", err)
+					}
+				}
+			}
+		case <-timer.C:
+			if err := this.scan(ctx); err != nil {
+				this.Print("Presence: ", err)
+			}
+			timer.Reset(*this.interval)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *presence) People() []gopi.PresencePerson {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	names := make([]string, 0, len(this.people))
+	for name := range this.people {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]gopi.PresencePerson, 0, len(names))
+	for _, name := range names {
+		result = append(result, this.people[name])
+	}
+	return result
+}
+
+func (this *presence) Person(name string) gopi.PresencePerson {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if person, exists := this.people[name]; exists {
+		return person
+	} else {
+		return nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *presence) String() string {
+	str := "<presence"
+	if this.pinger != nil {
+		str += " ping"
+	}
+	if this.bluez {
+		str += fmt.Sprintf(" adapter=%q", *this.adapter)
+	}
+	for _, person := range this.People() {
+		str += fmt.Sprint(" ", person)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// readPeople returns people from a JSON file
+func readPeople(path string, now time.Time) (map[string]*person, error) {
+	var people map[string]config
+	if data, err := ioutil.ReadFile(path); err != nil {
+		return nil, err
+	} else if err := json.Unmarshal(data, &people); err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	result := make(map[string]*person, len(people))
+	for name, cfg := range people {
+		if name = strings.TrimSpace(name); name == "" {
+			return nil, gopi.ErrBadParameter.WithPrefix(path, ": Missing name")
+		} else if person, err := newPerson(name, cfg, now); err != nil {
+			return nil, err
+		} else {
+			result[name] = person
+		}
+	}
+	return result, nil
+}
+
+// hasBLE returns true if anyone has a Bluetooth LE address
+func (this *presence) hasBLE() bool {
+	for _, person := range this.people {
+		if person.ble != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// advertisement records the signal strength for anyone with the
+// Bluetooth LE address
+func (this *presence) advertisement(addr string, rssi int) {
+	now := time.Now()
+	for _, person := range this.snapshot() {
+		if person.ble == addr {
+			person.advertisement(rssi, now)
+		}
+	}
+}
+
+// snapshot returns all people
+func (this *presence) snapshot() []*person {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	result := make([]*person, 0, len(this.people))
+	for _, person := range this.people {
+		result = append(result, person)
+	}
+	return result
+}
+
+// scan detects each person and emits events when people arrive
+// or leave
+func (this *presence) scan(ctx context.Context) error {
+	now := time.Now()
+	people := this.snapshot()
+	methods := make(map[*person]gopi.PresenceMethod, len(people))
+
+	// Keep Bluetooth discovery running
+	if this.bluez {
+		if err := this.discoverBLE(ctx); err != nil {
+			this.Debug("Presence: ", err)
+		}
+	}
+
+	// Read the ARP table, which includes addresses for people
+	// without a configured IP address
+	var table map[string]net.IP
+	if *this.arp != "" {
+		if arp, err := readARP(*this.arp); err != nil {
+			this.Debug("Presence: ", err)
+		} else {
+			table = arp
+		}
+	}
+	addrs := make(map[*person]net.IP, len(people))
+	for _, person := range people {
+		if person.mac == nil {
+			// No hardware address
+		} else if ip, exists := table[person.mac.String()]; exists {
+			methods[person] |= gopi.PRESENCE_METHOD_ARP
+			addrs[person] = ip
+		}
+		if person.ip != nil {
+			addrs[person] = person.ip
+		}
+	}
+
+	// Ping each address once
+	if this.pinger != nil && len(addrs) > 0 {
+		targets := []net.IP{}
+		unique := make(map[string]bool, len(addrs))
+		for _, ip := range addrs {
+			if unique[ip.String()] == false {
+				unique[ip.String()] = true
+				targets = append(targets, ip)
+			}
+		}
+		if replies, err := this.pinger.Ping(ctx, targets, *this.timeout); err != nil {
+			this.Debug("Presence: ", err)
+		} else {
+			for person, ip := range addrs {
+				if replies[ip.String()] {
+					methods[person] |= gopi.PRESENCE_METHOD_ICMP
+				}
+			}
+		}
+	}
+
+	// Bluetooth advertisements received since the last scan
+	for _, person := range people {
+		if person.bluetooth(now.Add(-*this.interval), *this.rssi, rssiHysteresis) {
+			methods[person] |= gopi.PRESENCE_METHOD_BLE
+		}
+	}
+
+	// Update state and emit events
+	for _, person := range people {
+		switch person.update(methods[person], now, *this.arrive, *this.away) {
+		case gopi.PRESENCE_STATE_HOME:
+			this.emit(NewEvent(gopi.PRESENCE_EVENT_ARRIVE, person))
+		case gopi.PRESENCE_STATE_AWAY:
+			this.emit(NewEvent(gopi.PRESENCE_EVENT_LEAVE, person))
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *presence) emit(evt gopi.PresenceEvent) {
+	this.Debug("Presence: ", evt)
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(evt, true); err != nil {
+			this.Print("Presence: ", err)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package presence_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/presence"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Presence
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	arpHeader = "IP address       HW type     Flags       HW address            Mask     Device\n"
+	arpAlice  = "192.168.1.20     0x1         0x2         a4:83:e7:12:34:56     *        wlan0\n"
+	arpBob    = "192.168.1.21     0x1         0x0         00:00:00:00:00:00     *        wlan0\n"
+	people    = `{
+		"alice": { "mac": "A4:83:E7:12:34:56" },
+		"bob": { "mac": "c8:fd:19:ab:cd:ef", "ip": "192.168.1.21" },
+		"carol": { "ble": "c8:fd:19:00:00:01" }
+	}`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Presence_001(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	arp := writeFile(t, dir, "arp", arpHeader+arpAlice+arpBob)
+	args := []string{
+		"-presence.people", writeFile(t, dir, "people.json", people),
+		"-presence.arp", arp,
+		"-presence.ping=false",
+		"-presence.interval", "50ms",
+		"-presence.away", "300ms",
+		"-presence.arrive", "2",
+	}
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		t.Log(app.Presence)
+		if people := app.Presence.People(); len(people) != 3 {
+			t.Error("Unexpected people", people)
+		} else if people[0].Name() != "alice" || people[1].Name() != "bob" || people[2].Name() != "carol" {
+			t.Error("Unexpected people", people)
+		} else if app.Presence.Person("dave") != nil {
+			t.Error("Unexpected person dave")
+		}
+
+		// Alice arrives after two scans, then bob and carol leave
+		expected := []struct {
+			name string
+			t    gopi.PresenceEventType
+		}{
+			{"alice", gopi.PRESENCE_EVENT_ARRIVE},
+			{"bob", gopi.PRESENCE_EVENT_LEAVE},
+			{"carol", gopi.PRESENCE_EVENT_LEAVE},
+		}
+		events := waitEvents(t, ch, len(expected), time.Second)
+		for _, evt := range events {
+			found := false
+			for _, e := range expected {
+				if evt.Name() == e.name && evt.Type() == e.t {
+					found = true
+				}
+			}
+			if found == false {
+				t.Error("Unexpected event", evt)
+			}
+		}
+		if alice := app.Presence.Person("alice"); alice.State() != gopi.PRESENCE_STATE_HOME || alice.Method() != gopi.PRESENCE_METHOD_ARP {
+			t.Error("Unexpected state", alice)
+		} else if bob := app.Presence.Person("bob"); bob.State() != gopi.PRESENCE_STATE_AWAY || bob.Seen().IsZero() == false {
+			t.Error("Unexpected state", bob)
+		}
+
+		// Alice leaves once not detected for the away duration
+		writeFile(t, dir, "arp", arpHeader+arpBob)
+		start := time.Now()
+		if events := waitEvents(t, ch, 1, time.Second); len(events) == 1 {
+			if events[0].Name() != "alice" || events[0].Type() != gopi.PRESENCE_EVENT_LEAVE {
+				t.Error("Unexpected event", events[0])
+			} else if since := time.Since(start); since < 250*time.Millisecond {
+				t.Error("Unexpected departure after", since)
+			} else if alice := events[0].Person(); alice.State() != gopi.PRESENCE_STATE_AWAY || alice.Method() != gopi.PRESENCE_METHOD_NONE {
+				t.Error("Unexpected state", alice)
+			}
+		}
+	})
+}
+
+func Test_Presence_002(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	args := []string{
+		"-presence.people", writeFile(t, dir, "people.json", `{ "localhost": { "ip": "127.0.0.1" } }`),
+		"-presence.arp", "",
+		"-presence.interval", "100ms",
+	}
+	tool.Test(t, args, new(App), func(app *App) {
+		if strings.Contains(fmt.Sprint(app.Presence), " ping") == false {
+			t.Log("Ping not permitted")
+			return
+		}
+		person := app.Presence.Person("localhost")
+		for i := 0; i < 20 && person.State() != gopi.PRESENCE_STATE_HOME; i++ {
+			time.Sleep(50 * time.Millisecond)
+		}
+		if person.State() != gopi.PRESENCE_STATE_HOME || person.Method() != gopi.PRESENCE_METHOD_ICMP {
+			t.Error("Unexpected state", person)
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// waitEvents returns presence events until a number of events are
+// received or the timeout
+func waitEvents(t *testing.T, ch <-chan gopi.Event, n int, timeout time.Duration) []gopi.PresenceEvent {
+	t.Helper()
+	result := []gopi.PresenceEvent{}
+	deadline := time.After(timeout)
+	for len(result) < n {
+		select {
+		case evt := <-ch:
+			if evt, ok := evt.(gopi.PresenceEvent); ok {
+				result = append(result, evt)
+			}
+		case <-deadline:
+			t.Error("Timeout waiting for events, received", result)
+			return result
+		}
+	}
+	return result
+}
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "presence")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func writeFile(t *testing.T, dir, name, data string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Error(err)
+	}
+	return path
+}
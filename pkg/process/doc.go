@@ -0,0 +1,24 @@
+// Process package launches and supervises external processes, restarting
+// them according to a restart policy, capturing standard output and
+// standard error into the logger and applying resource limits.
+//
+// A gopi.ProcessEvent is emitted whenever the state of a process changes
+// when a gopi.Publisher is available. All processes are stopped when the
+// unit is disposed, first with SIGTERM and then with SIGKILL when they do
+// not exit within the timeout set by the -process.timeout flag.
+//
+// The gopi.Scheduler unit grants elevated scheduling to goroutines with
+// timing-critical loops. Realtime locks the goroutine to its thread and
+// requests SCHED_FIFO scheduling, restricts the thread to the CPUs set by
+// the -sched.cpus flag and locks memory unless -sched.mlock=false. Anything
+// which is not permitted (for example, without CAP_SYS_NICE or a realtime
+// rlimit) is reported once and skipped, so the goroutine continues with
+// normal scheduling. Use -sched.disable to turn off elevated scheduling
+// altogether. For example,
+//
+//	flags, restore := this.Scheduler.Realtime(50)
+//	defer restore()
+//
+// Reserving CPUs works best when they are also isolated from other
+// processes with the isolcpus kernel parameter.
+package process
@@ -0,0 +1,52 @@
+package process
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	process gopi.Process
+	state   gopi.ProcessState
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(process gopi.Process, state gopi.ProcessState) gopi.ProcessEvent {
+	return &event{process, state}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	if this.process == nil {
+		return ""
+	}
+	return this.process.Name()
+}
+
+func (this *event) Process() gopi.Process {
+	return this.process
+}
+
+func (this *event) State() gopi.ProcessState {
+	return this.state
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<event.process"
+	str += fmt.Sprintf(" name=%q", this.Name())
+	if this.state != gopi.PROCESS_STATE_NONE {
+		str += " state=" + fmt.Sprint(this.state)
+	}
+	return str + ">"
+}
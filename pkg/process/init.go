@@ -0,0 +1,16 @@
+package process
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register process manager
+	graph.RegisterUnit(reflect.TypeOf(&manager{}), reflect.TypeOf((*gopi.ProcessManager)(nil)))
+
+	// Register scheduler
+	graph.RegisterUnit(reflect.TypeOf(&scheduler{}), reflect.TypeOf((*gopi.Scheduler)(nil)))
+}
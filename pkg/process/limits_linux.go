@@ -0,0 +1,45 @@
+// +build linux
+
+package process
+
+import (
+	"os"
+	"unsafe"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	unix "golang.org/x/sys/unix"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// setLimits applies resource limits to a running process
+func setLimits(pid int, limits gopi.ProcessLimits) error {
+	if limits.CPU > 0 {
+		if err := prlimit(pid, unix.RLIMIT_CPU, uint64(limits.CPU.Seconds()+0.5)); err != nil {
+			return err
+		}
+	}
+	if limits.Memory > 0 {
+		if err := prlimit(pid, unix.RLIMIT_AS, limits.Memory); err != nil {
+			return err
+		}
+	}
+	if limits.Files > 0 {
+		if err := prlimit(pid, unix.RLIMIT_NOFILE, limits.Files); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prlimit sets the soft and hard limit for a resource. The pinned
+// x/sys version does not export Prlimit, so the syscall is made directly
+func prlimit(pid, resource int, value uint64) error {
+	limit := unix.Rlimit{Cur: value, Max: value}
+	if _, _, errno := unix.RawSyscall6(unix.SYS_PRLIMIT64, uintptr(pid), uintptr(resource), uintptr(unsafe.Pointer(&limit)), 0, 0, 0); errno != 0 {
+		return os.NewSyscallError("prlimit", errno)
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,18 @@
+// +build !linux
+
+package process
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func setLimits(pid int, limits gopi.ProcessLimits) error {
+	if limits == (gopi.ProcessLimits{}) {
+		return nil
+	} else {
+		return gopi.ErrNotImplemented.WithPrefix("ProcessLimits")
+	}
+}
@@ -0,0 +1,149 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type manager struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	sync.Mutex
+	sync.WaitGroup
+
+	timeout   *time.Duration
+	processes map[string]*process
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *manager) Define(cfg gopi.Config) error {
+	this.timeout = cfg.FlagDuration("process.timeout", 5*time.Second, "Time to wait for processes to exit before killing")
+	return nil
+}
+
+func (this *manager) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	this.processes = make(map[string]*process)
+
+	// Return success
+	return nil
+}
+
+func (this *manager) Run(ctx context.Context) error {
+	<-ctx.Done()
+
+	// Stop all processes
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	for name, process := range this.processes {
+		process.Stop(*this.timeout)
+		delete(this.processes, name)
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *manager) Dispose() error {
+	this.Mutex.Lock()
+	for name, process := range this.processes {
+		process.Stop(*this.timeout)
+		delete(this.processes, name)
+	}
+	this.Mutex.Unlock()
+
+	// Wait for supervision to end
+	this.WaitGroup.Wait()
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *manager) Start(name string, restart gopi.ProcessRestart, limits gopi.ProcessLimits, path string, args ...string) (gopi.Process, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Check parameters
+	if name == "" || path == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("Start")
+	} else if _, exists := this.processes[name]; exists {
+		return nil, gopi.ErrDuplicateEntry.WithPrefix("Start: ", name)
+	}
+
+	// Start supervising the process
+	process := newProcess(name, restart, limits, path, args...)
+	this.processes[name] = process
+	this.WaitGroup.Add(1)
+	go func() {
+		defer this.WaitGroup.Done()
+		process.Run(this)
+	}()
+
+	// Return success
+	return process, nil
+}
+
+func (this *manager) Stop(p gopi.Process) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if p == nil {
+		return gopi.ErrBadParameter.WithPrefix("Stop")
+	} else if process, exists := this.processes[p.Name()]; exists == false || process != p {
+		return gopi.ErrNotFound.WithPrefix("Stop: ", p.Name())
+	} else {
+		process.Stop(*this.timeout)
+		delete(this.processes, p.Name())
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *manager) Processes() []gopi.Process {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	result := make([]gopi.Process, 0, len(this.processes))
+	for _, process := range this.processes {
+		result = append(result, process)
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *manager) String() string {
+	str := "<process.manager"
+	for _, process := range this.Processes() {
+		str += " " + fmt.Sprint(process)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *manager) emit(process *process, state gopi.ProcessState) {
+	this.Debug(process.Name(), ": ", state)
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(NewEvent(process, state), false); err != nil {
+			this.Print(process.Name(), ": ", err)
+		}
+	}
+}
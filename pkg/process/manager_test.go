@@ -0,0 +1,142 @@
+package process_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/process"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.ProcessManager
+	gopi.Scheduler
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Process_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.ProcessManager == nil {
+			t.Error("nil ProcessManager unit")
+		} else {
+			t.Log(app.ProcessManager)
+		}
+	})
+}
+
+func Test_Process_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if process, err := app.ProcessManager.Start("sleep", gopi.PROCESS_RESTART_NEVER, gopi.ProcessLimits{}, "sleep", "10"); err != nil {
+			t.Error(err)
+		} else {
+			if waitForState(t, process, gopi.PROCESS_STATE_RUNNING) == false {
+				return
+			} else if process.Pid() == 0 {
+				t.Error("Unexpected zero pid")
+			}
+			t.Log(process)
+			if err := app.ProcessManager.Stop(process); err != nil {
+				t.Error(err)
+			} else if state := process.State(); state != gopi.PROCESS_STATE_STOPPED {
+				t.Error("Unexpected state", state)
+			} else if len(app.ProcessManager.Processes()) != 0 {
+				t.Error("Unexpected processes", app.ProcessManager.Processes())
+			}
+		}
+	})
+}
+
+func Test_Process_003(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if process, err := app.ProcessManager.Start("exit", gopi.PROCESS_RESTART_NEVER, gopi.ProcessLimits{}, "sh", "-c", "echo hello; exit 3"); err != nil {
+			t.Error(err)
+		} else {
+			if waitForState(t, process, gopi.PROCESS_STATE_FAILED) == false {
+				return
+			} else if code := process.ExitCode(); code != 3 {
+				t.Error("Unexpected exit code", code)
+			}
+			if _, err := app.ProcessManager.Start("exit", gopi.PROCESS_RESTART_NEVER, gopi.ProcessLimits{}, "true"); err == nil {
+				t.Error("Expected duplicate entry error")
+			}
+		}
+	})
+}
+
+func Test_Process_004(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if process, err := app.ProcessManager.Start("restart", gopi.PROCESS_RESTART_ALWAYS, gopi.ProcessLimits{}, "true"); err != nil {
+			t.Error(err)
+		} else {
+			waitFor(t, "restart", func() bool {
+				return process.Restarts() > 0
+			})
+		}
+	})
+}
+
+func Test_Process_005(t *testing.T) {
+	tool.Test(t, []string{"-sched.mlock=false"}, new(App), func(app *App) {
+		// Elevated scheduling degrades rather than fails when not permitted
+		flags, restore := app.Scheduler.Realtime(10)
+		restore()
+		if flags&gopi.SCHEDULER_FLAG_MLOCK != 0 {
+			t.Error("Unexpected flags", flags)
+		} else {
+			t.Log(app.Scheduler, flags)
+		}
+		if flags, restore := app.Scheduler.Realtime(0); flags != gopi.SCHEDULER_FLAG_NONE {
+			t.Error("Unexpected flags", flags)
+		} else {
+			restore()
+		}
+	})
+}
+
+func Test_Process_006(t *testing.T) {
+	tool.Test(t, []string{"-sched.disable"}, new(App), func(app *App) {
+		if flags, restore := app.Scheduler.Realtime(10); flags != gopi.SCHEDULER_FLAG_NONE {
+			t.Error("Unexpected flags", flags)
+		} else {
+			restore()
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func waitForState(t *testing.T, process gopi.Process, state gopi.ProcessState) bool {
+	t.Helper()
+	return waitFor(t, state, func() bool {
+		return process.State() == state
+	})
+}
+
+func waitFor(t *testing.T, what interface{}, fn func() bool) bool {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for fn() == false {
+		select {
+		case <-timeout:
+			t.Error("Timeout waiting for", what)
+			return false
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	return true
+}
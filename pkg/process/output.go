@@ -0,0 +1,47 @@
+package process
+
+import (
+	"bytes"
+	"sync"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// output is an io.Writer which calls a function for each
+// line written
+type output struct {
+	sync.Mutex
+	fn  func(string)
+	buf []byte
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *output) Write(data []byte) (int, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	this.buf = append(this.buf, data...)
+	for {
+		i := bytes.IndexByte(this.buf, '\n')
+		if i < 0 {
+			break
+		}
+		this.fn(string(bytes.TrimRight(this.buf[:i], "\r")))
+		this.buf = this.buf[i+1:]
+	}
+	return len(data), nil
+}
+
+// Flush outputs any remaining partial line
+func (this *output) Flush() {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if len(this.buf) > 0 {
+		this.fn(string(this.buf))
+		this.buf = nil
+	}
+}
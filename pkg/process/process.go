@@ -0,0 +1,280 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type process struct {
+	sync.RWMutex
+
+	name     string
+	path     string
+	args     []string
+	restart  gopi.ProcessRestart
+	limits   gopi.ProcessLimits
+	pid      int
+	state    gopi.ProcessState
+	restarts uint
+	code     int
+	started  time.Time
+	stop     chan time.Duration
+	done     chan struct{}
+}
+
+// supervisor is the interface used by a process to report
+// output and state changes to the manager
+type supervisor interface {
+	Print(...interface{})
+	emit(*process, gopi.ProcessState)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	backoffMin   = time.Second      // Initial delay before restarting
+	backoffMax   = time.Minute      // Maximum delay before restarting
+	backoffReset = 10 * time.Minute // Running time after which delay is reset
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newProcess(name string, restart gopi.ProcessRestart, limits gopi.ProcessLimits, path string, args ...string) *process {
+	this := new(process)
+	this.name = name
+	this.path = path
+	this.args = args
+	this.restart = restart
+	this.limits = limits
+	this.stop = make(chan time.Duration)
+	this.done = make(chan struct{})
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *process) Name() string {
+	return this.name
+}
+
+func (this *process) Pid() int {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.pid
+}
+
+func (this *process) State() gopi.ProcessState {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.state
+}
+
+func (this *process) Restarts() uint {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.restarts
+}
+
+func (this *process) ExitCode() int {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.code
+}
+
+func (this *process) Uptime() time.Duration {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	if this.state != gopi.PROCESS_STATE_RUNNING {
+		return 0
+	} else {
+		return time.Since(this.started)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Run starts the process and restarts it according to the restart
+// policy, until it is stopped or should no longer be restarted
+func (this *process) Run(parent supervisor) {
+	defer close(this.done)
+
+	backoff := backoffMin
+	for {
+		cmd, exited, err := this.start(parent)
+		if err != nil {
+			parent.Print(this.name, ": ", err)
+			this.setState(parent, gopi.PROCESS_STATE_FAILED, 0, -1)
+		} else {
+			select {
+			case err := <-exited:
+				this.exit(parent, err)
+			case timeout := <-this.stop:
+				this.terminate(cmd, exited, timeout)
+				this.setState(parent, gopi.PROCESS_STATE_STOPPED, 0, this.ExitCode())
+				return
+			}
+		}
+
+		// Determine whether to restart
+		if this.shouldRestart() == false {
+			return
+		}
+
+		// Reset the delay when the process has been running for a while,
+		// otherwise back off between restarts
+		if time.Since(this.started) > backoffReset {
+			backoff = backoffMin
+		}
+		this.setState(parent, gopi.PROCESS_STATE_RESTARTING, 0, this.ExitCode())
+		select {
+		case <-time.After(backoff):
+			this.RWMutex.Lock()
+			this.restarts++
+			this.RWMutex.Unlock()
+		case <-this.stop:
+			this.setState(parent, gopi.PROCESS_STATE_STOPPED, 0, this.ExitCode())
+			return
+		}
+		if backoff *= 2; backoff > backoffMax {
+			backoff = backoffMax
+		}
+	}
+}
+
+// Stop the process, sending SIGTERM and then SIGKILL after a timeout,
+// and wait for supervision to end
+func (this *process) Stop(timeout time.Duration) {
+	select {
+	case this.stop <- timeout:
+	case <-this.done:
+	}
+	<-this.done
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *process) start(parent supervisor) (*exec.Cmd, <-chan error, error) {
+	stdout := &output{fn: func(line string) { parent.Print(this.name, ": ", line) }}
+	stderr := &output{fn: func(line string) { parent.Print(this.name, ": [stderr] ", line) }}
+
+	cmd := exec.Command(this.path, this.args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+
+	// Apply resource limits, killing the process if they cannot be set
+	if err := setLimits(cmd.Process.Pid, this.limits); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, nil, err
+	}
+
+	// Wait for the process to exit in the background
+	exited := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		stdout.Flush()
+		stderr.Flush()
+		exited <- err
+	}()
+
+	// Set state
+	this.RWMutex.Lock()
+	this.started = time.Now()
+	this.RWMutex.Unlock()
+	this.setState(parent, gopi.PROCESS_STATE_RUNNING, cmd.Process.Pid, 0)
+
+	// Return success
+	return cmd, exited, nil
+}
+
+func (this *process) exit(parent supervisor, err error) {
+	var exiterr *exec.ExitError
+	if err == nil {
+		this.setState(parent, gopi.PROCESS_STATE_EXITED, 0, 0)
+	} else if errors.As(err, &exiterr) {
+		this.setState(parent, gopi.PROCESS_STATE_FAILED, 0, exiterr.ExitCode())
+	} else {
+		parent.Print(this.name, ": ", err)
+		this.setState(parent, gopi.PROCESS_STATE_FAILED, 0, -1)
+	}
+}
+
+func (this *process) terminate(cmd *exec.Cmd, exited <-chan error, timeout time.Duration) {
+	var err error
+	cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case err = <-exited:
+	case <-time.After(timeout):
+		cmd.Process.Kill()
+		err = <-exited
+	}
+
+	// Record exit code
+	var exiterr *exec.ExitError
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	if err == nil {
+		this.code = 0
+	} else if errors.As(err, &exiterr) {
+		this.code = exiterr.ExitCode()
+	}
+}
+
+func (this *process) shouldRestart() bool {
+	switch this.restart {
+	case gopi.PROCESS_RESTART_ALWAYS:
+		return true
+	case gopi.PROCESS_RESTART_ONFAILURE:
+		return this.State() == gopi.PROCESS_STATE_FAILED
+	default:
+		return false
+	}
+}
+
+func (this *process) setState(parent supervisor, state gopi.ProcessState, pid, code int) {
+	this.RWMutex.Lock()
+	this.state = state
+	this.pid = pid
+	this.code = code
+	this.RWMutex.Unlock()
+	parent.emit(this, state)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *process) String() string {
+	str := "<process"
+	str += fmt.Sprintf(" name=%q", this.name)
+	str += fmt.Sprintf(" path=%q", this.path)
+	if state := this.State(); state != gopi.PROCESS_STATE_NONE {
+		str += " state=" + fmt.Sprint(state)
+	}
+	if pid := this.Pid(); pid != 0 {
+		str += " pid=" + fmt.Sprint(pid)
+	}
+	if restarts := this.Restarts(); restarts != 0 {
+		str += " restarts=" + fmt.Sprint(restarts)
+	}
+	if this.restart != gopi.PROCESS_RESTART_NEVER {
+		str += " restart=" + fmt.Sprint(this.restart)
+	}
+	return str + ">"
+}
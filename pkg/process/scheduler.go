@@ -0,0 +1,167 @@
+package process
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type scheduler struct {
+	gopi.Unit
+	gopi.Logger
+	sync.Mutex
+
+	cpus    *string
+	mlock   *bool
+	disable *bool
+
+	reserved []uint
+	locks    uint
+	warned   gopi.SchedulerFlag
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	schedMaxPriority = 99
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *scheduler) Define(cfg gopi.Config) error {
+	this.cpus = cfg.FlagString("sched.cpus", "", "CPUs reserved for timing-critical threads (for example, 2,3 or 2-3), or empty for any CPU")
+	this.mlock = cfg.FlagBool("sched.mlock", true, "Lock memory while timing-critical threads are running")
+	this.disable = cfg.FlagBool("sched.disable", false, "Disable elevated scheduling for timing-critical threads")
+	return nil
+}
+
+func (this *scheduler) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	if cpus, err := parseCPUs(*this.cpus, uint(runtime.NumCPU())); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-sched.cpus: ", err)
+	} else {
+		this.reserved = cpus
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *scheduler) Realtime(priority int) (gopi.SchedulerFlag, func()) {
+	if *this.disable || priority <= 0 {
+		return gopi.SCHEDULER_FLAG_NONE, func() {}
+	} else if priority > schedMaxPriority {
+		priority = schedMaxPriority
+	}
+
+	// Lock the goroutine to the thread and elevate the thread. When the thread
+	// cannot be restored it remains locked, so it exits with the goroutine
+	// rather than returning to the pool
+	runtime.LockOSThread()
+	flags, restore := this.realtime(priority)
+	this.Debug("Scheduler: Realtime priority=", priority, " flags=", flags)
+	return flags, func() {
+		if restore() {
+			runtime.UnlockOSThread()
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *scheduler) String() string {
+	str := "<scheduler"
+	if *this.disable {
+		str += " disabled"
+	}
+	if len(this.reserved) > 0 {
+		str += fmt.Sprint(" cpus=", this.reserved)
+	}
+	if *this.mlock {
+		str += " mlock"
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// warn reports that a flag could not be granted, the first time as a
+// warning and after that as debugging output
+func (this *scheduler) warn(flag gopi.SchedulerFlag, err error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.warned&flag == 0 {
+		this.warned |= flag
+		this.Print("Scheduler: Continuing without ", flag, ": ", err)
+	} else {
+		this.Debug("Scheduler: Continuing without ", flag, ": ", err)
+	}
+}
+
+// lock locks memory for the first timing-critical thread
+func (this *scheduler) lock(fn func() error) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.locks == 0 {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	this.locks++
+	return nil
+}
+
+// unlock unlocks memory when the last timing-critical thread is restored
+func (this *scheduler) unlock(fn func() error) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.locks == 0 {
+		return gopi.ErrOutOfOrder.WithPrefix("unlock")
+	} else if this.locks--; this.locks == 0 {
+		return fn()
+	}
+	return nil
+}
+
+// parseCPUs returns CPUs from a comma-separated list of CPUs and ranges
+func parseCPUs(value string, max uint) ([]uint, error) {
+	result := []uint{}
+	if strings.TrimSpace(value) == "" {
+		return result, nil
+	}
+	for _, field := range strings.Split(value, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(field), "-", 2)
+		first, err := strconv.ParseUint(bounds[0], 10, 32)
+		if err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix(field)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.ParseUint(bounds[1], 10, 32); err != nil || last < first {
+				return nil, gopi.ErrBadParameter.WithPrefix(field)
+			}
+		}
+		for cpu := uint(first); cpu <= uint(last); cpu++ {
+			if cpu >= max {
+				return nil, gopi.ErrNotFound.WithPrefix("CPU ", cpu)
+			}
+			result = append(result, cpu)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,72 @@
+// +build linux
+
+package process
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+	linux "github.com/djthorpe/gopi/v3/pkg/sys/linux"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// realtime elevates the calling thread, granting as much as is permitted,
+// and returns the flags which were granted and a function which restores
+// the thread and returns false if the thread could not be restored
+func (this *scheduler) realtime(priority int) (gopi.SchedulerFlag, func() bool) {
+	flags := gopi.SCHEDULER_FLAG_NONE
+
+	// Get current scheduling and affinity for restoring later
+	policy, param, err := linux.SchedGetScheduler(0)
+	if err != nil {
+		this.warn(gopi.SCHEDULER_FLAG_FIFO, err)
+		return flags, func() bool { return true }
+	}
+	affinity, err := linux.SchedGetAffinity(0)
+	if err != nil {
+		this.warn(gopi.SCHEDULER_FLAG_AFFINITY, err)
+	}
+
+	// Elevate the thread
+	if err := linux.SchedSetScheduler(0, linux.SCHED_FIFO, priority); err != nil {
+		this.warn(gopi.SCHEDULER_FLAG_FIFO, err)
+	} else {
+		flags |= gopi.SCHEDULER_FLAG_FIFO
+	}
+	if len(this.reserved) > 0 && len(affinity) > 0 {
+		if err := linux.SchedSetAffinity(0, this.reserved); err != nil {
+			this.warn(gopi.SCHEDULER_FLAG_AFFINITY, err)
+		} else {
+			flags |= gopi.SCHEDULER_FLAG_AFFINITY
+		}
+	}
+	if *this.mlock {
+		if err := this.lock(linux.MemLock); err != nil {
+			this.warn(gopi.SCHEDULER_FLAG_MLOCK, err)
+		} else {
+			flags |= gopi.SCHEDULER_FLAG_MLOCK
+		}
+	}
+
+	return flags, func() bool {
+		result := true
+		if flags&gopi.SCHEDULER_FLAG_MLOCK != 0 {
+			if err := this.unlock(linux.MemUnlock); err != nil {
+				this.Debug("Scheduler: ", err)
+			}
+		}
+		if flags&gopi.SCHEDULER_FLAG_AFFINITY != 0 {
+			if err := linux.SchedSetAffinity(0, affinity); err != nil {
+				this.Debug("Scheduler: ", err)
+				result = false
+			}
+		}
+		if flags&gopi.SCHEDULER_FLAG_FIFO != 0 {
+			if err := linux.SchedSetScheduler(0, policy, param); err != nil {
+				this.Debug("Scheduler: ", err)
+				result = false
+			}
+		}
+		return result
+	}
+}
@@ -0,0 +1,15 @@
+// +build !linux
+
+package process
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *scheduler) realtime(priority int) (gopi.SchedulerFlag, func() bool) {
+	this.warn(gopi.SCHEDULER_FLAG_FIFO, gopi.ErrNotImplemented.WithPrefix("Realtime"))
+	return gopi.SCHEDULER_FLAG_NONE, func() bool { return true }
+}
@@ -0,0 +1,39 @@
+package process
+
+import (
+	"testing"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Scheduler_001(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []uint
+	}{
+		{"", []uint{}},
+		{"3", []uint{3}},
+		{"0, 2", []uint{0, 2}},
+		{"1-3", []uint{1, 2, 3}},
+		{"0,2-3", []uint{0, 2, 3}},
+	}
+	for _, test := range tests {
+		if cpus, err := parseCPUs(test.value, 4); err != nil {
+			t.Error(test.value, err)
+		} else if len(cpus) != len(test.expected) {
+			t.Error(test.value, "Unexpected", cpus)
+		} else {
+			for i := range cpus {
+				if cpus[i] != test.expected[i] {
+					t.Error(test.value, "Unexpected", cpus)
+				}
+			}
+		}
+	}
+	for _, value := range []string{"4", "a", "3-1", "1-", "1,,2"} {
+		if _, err := parseCPUs(value, 4); err == nil {
+			t.Error(value, "Expected error")
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type device struct {
+	gopi.ServiceRecord
+
+	typ    string
+	seen   time.Time
+	static bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// NewDevice returns a device of a type from a service record, which can
+// be registered
+func NewDevice(typ string, record gopi.ServiceRecord) gopi.Device {
+	if typ = strings.TrimSpace(typ); typ == "" || record == nil || record.Name() == "" {
+		return nil
+	}
+	return &device{record, typ, time.Now(), true}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *device) Key() string {
+	return this.typ + ":" + this.ServiceRecord.Name()
+}
+
+func (this *device) Type() string {
+	return this.typ
+}
+
+func (this *device) Seen() time.Time {
+	return this.seen
+}
+
+// Iface returns the interface on which the device was discovered, or
+// an empty string
+func (this *device) Iface() string {
+	if r, ok := this.ServiceRecord.(gopi.ServiceIface); ok {
+		return r.Iface()
+	} else {
+		return ""
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// equals returns true if the device is connected to in the same way
+func (this *device) equals(other *device) bool {
+	if this.Host() != other.Host() || this.Port() != other.Port() {
+		return false
+	} else if addrs(this.Addrs()) != addrs(other.Addrs()) {
+		return false
+	} else if strings.Join(this.Txt(), "\n") != strings.Join(other.Txt(), "\n") {
+		return false
+	} else {
+		return true
+	}
+}
+
+// addrs returns addresses as a sorted string
+func addrs(ips []net.IP) string {
+	result := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		result = append(result, ip.String())
+	}
+	sort.Strings(result)
+	return strings.Join(result, ",")
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// JSON
+
+func (this *device) MarshalJSON() ([]byte, error) {
+	type deviceJSON struct {
+		Key     string    `json:"key"`
+		Type    string    `json:"type"`
+		Name    string    `json:"name"`
+		Service string    `json:"service"`
+		Host    string    `json:"host,omitempty"`
+		Port    uint16    `json:"port,omitempty"`
+		Addrs   []string  `json:"addrs,omitempty"`
+		Txt     []string  `json:"txt,omitempty"`
+		Seen    time.Time `json:"seen"`
+	}
+	result := deviceJSON{
+		Key:     this.Key(),
+		Type:    this.typ,
+		Name:    this.Name(),
+		Service: this.Service(),
+		Host:    this.Host(),
+		Port:    this.Port(),
+		Txt:     this.Txt(),
+		Seen:    this.seen,
+	}
+	for _, ip := range this.Addrs() {
+		result.Addrs = append(result.Addrs, ip.String())
+	}
+	return json.Marshal(result)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *device) String() string {
+	str := "<registry.device"
+	str += fmt.Sprintf(" key=%q", this.Key())
+	str += fmt.Sprintf(" service=%q", this.Service())
+	if host := this.Host(); host != "" {
+		str += fmt.Sprintf(" host=%q", host)
+	}
+	if port := this.Port(); port != 0 {
+		str += fmt.Sprint(" port=", port)
+	}
+	if addrs := addrs(this.Addrs()); addrs != "" {
+		str += " addrs=" + addrs
+	}
+	if this.static == false {
+		str += " seen=" + this.seen.Format(time.Kitchen)
+	}
+	return str + ">"
+}
@@ -0,0 +1,27 @@
+// Registry package implements gopi.DeviceRegistry, which collects devices
+// discovered on the local network in one place, rather than each unit
+// keeping its own list. Service records for the service types set with
+// the -registry.services flag are read from gopi.ServiceDiscovery and from
+// records emitted by it, and each record becomes a device with a type:
+//
+//	_googlecast._tcp=cast,_dial._tcp=dial,_grpc._tcp=rpc,_hue._tcp=hue
+//
+// Devices which are not advertised with mDNS, such as DLNA renderers, are
+// discovered with SSDP. Each search target set with the -registry.ssdp flag
+// is searched for in every lookup, and devices are named from the UPnP
+// device description at their location:
+//
+//	urn:schemas-upnp-org:device:MediaRenderer:1=dlna
+//
+// Other units can register devices which are not discovered with mDNS by
+// calling Register with a device returned by NewDevice. Discovered devices
+// which have not been seen within the -registry.expire duration are
+// removed, and a gopi.DeviceEvent is emitted when a device is added,
+// changed or removed.
+//
+// When a HTTP server is available, devices are served as JSON on the path
+// set with -registry.path (default /api/devices), optionally filtered by
+// type with a "type" query parameter, and a single device is served on
+// {path}/{key}. When the server is a gRPC server, devices are listed and
+// watched with the service in package rpc/registry instead.
+package registry
@@ -0,0 +1,44 @@
+package registry
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t      gopi.DeviceEventType
+	device gopi.Device
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(t gopi.DeviceEventType, device gopi.Device) gopi.DeviceEvent {
+	return &event{t, device}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.device.Key()
+}
+
+func (this *event) Type() gopi.DeviceEventType {
+	return this.t
+}
+
+func (this *event) Device() gopi.Device {
+	return this.device
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	return fmt.Sprint("<registry.event type=", this.t, " ", this.device, ">")
+}
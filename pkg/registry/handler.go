@@ -0,0 +1,54 @@
+package registry
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type handler struct {
+	path string
+	gopi.DeviceRegistry
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// NewHandler returns a handler which serves devices as JSON, where path
+// is the path the handler is registered on
+func NewHandler(path string, registry gopi.DeviceRegistry) http.Handler {
+	return &handler{strings.TrimRight(path, "/"), registry}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SERVE
+
+func (this *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Return all devices or a single device
+	var result interface{}
+	if key := strings.Trim(strings.TrimPrefix(req.URL.Path, this.path), "/"); key == "" {
+		result = this.DeviceRegistry.Devices(req.URL.Query().Get("type"))
+	} else if device := this.DeviceRegistry.Device(key); device == nil {
+		http.NotFound(w, req)
+		return
+	} else {
+		result = device
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,13 @@
+package registry
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.DeviceRegistry
+	graph.RegisterUnit(reflect.TypeOf(&registry{}), reflect.TypeOf((*gopi.DeviceRegistry)(nil)))
+}
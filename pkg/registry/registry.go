@@ -0,0 +1,361 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type registry struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.ServiceDiscovery
+	gopi.Server
+	sync.RWMutex
+
+	// Flags
+	services *string
+	interval *time.Duration
+	expire   *time.Duration
+	timeout  *time.Duration
+	path     *string
+	ssdp     *string
+
+	types   map[string]string
+	targets map[string]string
+	devices map[string]*device
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	defaultServices = "_googlecast._tcp=cast,_dial._tcp=dial,_grpc._tcp=rpc,_hue._tcp=hue,_esphomelib._tcp=esphome,_raop._tcp=airplay"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *registry) Define(cfg gopi.Config) error {
+	this.services = cfg.FlagString("registry.services", defaultServices, "Comma-separated service types with device types")
+	this.interval = cfg.FlagDuration("registry.interval", time.Minute, "Interval between service lookups")
+	this.expire = cfg.FlagDuration("registry.expire", 5*time.Minute, "Time without discovery before a device is removed")
+	this.timeout = cfg.FlagDuration("registry.timeout", 5*time.Second, "Timeout for each service lookup")
+	this.path = cfg.FlagString("registry.path", "/api/devices", "Path to serve devices, or empty to disable")
+	this.ssdp = cfg.FlagString("registry.ssdp", defaultSSDP, "Comma-separated SSDP search targets with device types")
+	return nil
+}
+
+func (this *registry) New(cfg gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if types, err := parseServices(*this.services); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-registry.services: ", err)
+	} else {
+		this.types = types
+	}
+	if targets, err := parseSearchTargets(*this.ssdp); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-registry.ssdp: ", err)
+	} else {
+		this.targets = targets
+	}
+	if *this.interval <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-registry.interval")
+	} else if *this.expire < *this.interval {
+		return gopi.ErrBadParameter.WithPrefix("-registry.expire")
+	} else if *this.timeout <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-registry.timeout")
+	}
+
+	// Make map of devices
+	this.devices = make(map[string]*device)
+
+	// Serve devices with a HTTP server
+	if path := strings.TrimRight(*this.path, "/"); path != "" && this.Server != nil && this.Server.Flags()&gopi.SERVICE_FLAG_GRPC == 0 {
+		handler := NewHandler(path, this)
+		if err := this.Server.RegisterService(path, handler); err != nil {
+			return err
+		} else if err := this.Server.RegisterService(path+"/", handler); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *registry) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Release resources
+	this.devices = nil
+	this.types = nil
+	this.targets = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *registry) Run(ctx context.Context) error {
+	var ch <-chan gopi.Event
+	if this.Publisher != nil {
+		ch = this.Publisher.Subscribe()
+		defer this.Publisher.Unsubscribe(ch)
+	}
+
+	// Lookup services soon after starting and then periodically
+	timer := time.NewTimer(100 * time.Millisecond)
+	defer timer.Stop()
+
+	// Lookups run in the background, so that emitted records are added
+	// whilst waiting for responses, and end when the context is cancelled
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	done := make(chan struct{})
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case evt := <-ch:
+			if record, ok := evt.(gopi.ServiceRecord); ok {
+				if typ, exists := this.types[record.Service()]; exists {
+					this.emit(this.seen(typ, record, time.Now()))
+				}
+			}
+		case <-timer.C:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := this.lookup(ctx); err != nil {
+					this.Debug("Registry: ", err)
+				}
+				select {
+				case done <- struct{}{}:
+				case <-ctx.Done():
+				}
+			}()
+		case <-done:
+			this.emit(this.expired(time.Now()))
+			timer.Reset(*this.interval)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *registry) Register(d gopi.Device) error {
+	if d == nil {
+		return gopi.ErrBadParameter.WithPrefix("Register")
+	}
+
+	// Devices from other packages are wrapped, and need a key from
+	// their type and name
+	device_, ok := d.(*device)
+	if ok == false {
+		device_ = &device{d, d.Type(), d.Seen(), true}
+	}
+	if d.Type() == "" || d.Name() == "" || device_.Key() != d.Key() {
+		return gopi.ErrBadParameter.WithPrefix("Register: ", d.Key())
+	}
+
+	this.RWMutex.Lock()
+	evt := this.set(device_)
+	this.RWMutex.Unlock()
+
+	this.emit(evt)
+	return nil
+}
+
+func (this *registry) Unregister(key string) error {
+	this.RWMutex.Lock()
+	device, exists := this.devices[key]
+	if exists {
+		delete(this.devices, key)
+	}
+	this.RWMutex.Unlock()
+
+	if exists == false {
+		return gopi.ErrNotFound.WithPrefix("Unregister: ", key)
+	}
+	this.emit([]gopi.Event{NewEvent(gopi.DEVICE_EVENT_REMOVED, device)})
+	return nil
+}
+
+func (this *registry) Devices(typ string) []gopi.Device {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	result := make([]gopi.Device, 0, len(this.devices))
+	for _, device := range this.devices {
+		if typ == "" || device.typ == typ {
+			result = append(result, device)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key() < result[j].Key()
+	})
+	return result
+}
+
+func (this *registry) Device(key string) gopi.Device {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if device, exists := this.devices[key]; exists {
+		return device
+	} else {
+		return nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *registry) String() string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	str := "<registry"
+	str += fmt.Sprint(" devices=", len(this.devices))
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// lookup queries each service type and SSDP search target and adds
+// the records
+func (this *registry) lookup(ctx context.Context) error {
+	var result error
+	for service, typ := range this.types {
+		if this.ServiceDiscovery == nil {
+			break
+		}
+		ctx, cancel := context.WithTimeout(ctx, *this.timeout)
+		records, err := this.ServiceDiscovery.Lookup(ctx, service)
+		cancel()
+		if err != nil && err != context.DeadlineExceeded {
+			result = multierror.Append(result, fmt.Errorf("%v: %w", service, err))
+		}
+		for _, record := range records {
+			this.emit(this.seen(typ, record, time.Now()))
+		}
+	}
+
+	// Devices such as DLNA renderers are only discovered with SSDP
+	for st, typ := range this.targets {
+		records, err := search(ctx, st, *this.timeout)
+		if err != nil {
+			result = multierror.Append(result, fmt.Errorf("%v: %w", st, err))
+		}
+		for _, record := range records {
+			this.emit(this.seen(typ, record, time.Now()))
+		}
+	}
+
+	// Return any errors
+	return result
+}
+
+// seen adds or updates a discovered device and returns any event
+func (this *registry) seen(typ string, record gopi.ServiceRecord, ts time.Time) []gopi.Event {
+	if record.Name() == "" {
+		return nil
+	}
+
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	return this.set(&device{record, typ, ts, false})
+}
+
+// set adds or replaces a device and returns any event, and should
+// be called with the lock held
+func (this *registry) set(device *device) []gopi.Event {
+	if this.devices == nil {
+		return nil
+	}
+
+	key := device.Key()
+	other, exists := this.devices[key]
+	if exists && other.static && device.static == false {
+		// Registered devices are not replaced by discovery
+		return nil
+	}
+
+	this.devices[key] = device
+	if exists == false {
+		return []gopi.Event{NewEvent(gopi.DEVICE_EVENT_ADDED, device)}
+	} else if other.equals(device) == false {
+		return []gopi.Event{NewEvent(gopi.DEVICE_EVENT_CHANGED, device)}
+	} else {
+		return nil
+	}
+}
+
+// expired removes discovered devices which have not been seen and
+// returns the events
+func (this *registry) expired(ts time.Time) []gopi.Event {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	var result []gopi.Event
+	for key, device := range this.devices {
+		if device.static == false && ts.Sub(device.seen) > *this.expire {
+			delete(this.devices, key)
+			result = append(result, NewEvent(gopi.DEVICE_EVENT_REMOVED, device))
+		}
+	}
+	return result
+}
+
+// emit events without holding the lock
+func (this *registry) emit(evts []gopi.Event) {
+	for _, evt := range evts {
+		this.Debug("Registry: ", evt)
+		if this.Publisher != nil {
+			if err := this.Publisher.Emit(evt, true); err != nil {
+				this.Print("Registry: ", err)
+			}
+		}
+	}
+}
+
+// parseServices returns device types keyed by service type
+func parseServices(value string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		service, typ := field, ""
+		if i := strings.Index(field, "="); i >= 0 {
+			service, typ = strings.TrimSpace(field[:i]), strings.TrimSpace(field[i+1:])
+		}
+		if strings.HasPrefix(service, "_") == false || strings.Contains(service, "._") == false {
+			return nil, fmt.Errorf("Invalid service type %q", service)
+		}
+		if typ == "" {
+			typ = strings.TrimPrefix(service[:strings.Index(service, "._")], "_")
+		}
+		result[service] = typ
+	}
+	return result, nil
+}
@@ -0,0 +1,176 @@
+package registry_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	registry "github.com/djthorpe/gopi/v3/pkg/registry"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.DeviceRegistry
+	gopi.Publisher
+}
+
+type record struct {
+	service, name string
+	port          uint16
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (this *record) Instance() string { return this.name + "." + this.service + ".local." }
+func (this *record) Service() string  { return this.service }
+func (this *record) Name() string     { return this.name }
+func (this *record) Zone() string     { return "local." }
+func (this *record) Host() string     { return "host.local." }
+func (this *record) Port() uint16     { return this.port }
+func (this *record) Addrs() []net.IP  { return []net.IP{net.ParseIP("192.168.1.20")} }
+func (this *record) Txt() []string    { return nil }
+
+type ifaceRecord struct {
+	record
+	ip, iface string
+}
+
+func (this *ifaceRecord) Addrs() []net.IP { return []net.IP{net.ParseIP(this.ip)} }
+func (this *ifaceRecord) Iface() string   { return this.iface }
+
+// wait returns the next device event or nil after a timeout
+func wait(ch <-chan gopi.Event, timeout time.Duration) gopi.DeviceEvent {
+	after := time.After(timeout)
+	for {
+		select {
+		case evt := <-ch:
+			if evt, ok := evt.(gopi.DeviceEvent); ok {
+				return evt
+			}
+		case <-after:
+			return nil
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Registry_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.DeviceRegistry == nil {
+			t.Error("nil DeviceRegistry unit")
+		} else if registry.NewDevice("", &record{"_test._tcp", "a", 80}) != nil {
+			t.Error("Expected nil device without type")
+		} else if err := app.DeviceRegistry.Register(registry.NewDevice("test", &record{"_test._tcp", "a", 80})); err != nil {
+			t.Error(err)
+		} else if devices := app.DeviceRegistry.Devices("test"); len(devices) != 1 || devices[0].Key() != "test:a" {
+			t.Error("Unexpected devices", devices)
+		} else if devices := app.DeviceRegistry.Devices("other"); len(devices) != 0 {
+			t.Error("Unexpected devices", devices)
+		} else if device := app.DeviceRegistry.Device("test:a"); device == nil || device.Port() != 80 {
+			t.Error("Unexpected device", device)
+		} else if err := app.DeviceRegistry.Unregister("test:a"); err != nil {
+			t.Error(err)
+		} else if err := app.DeviceRegistry.Unregister("test:a"); err == nil {
+			t.Error("Expected error for missing device")
+		} else {
+			t.Log(app.DeviceRegistry)
+		}
+	})
+}
+
+func Test_Registry_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Wait for the registry to subscribe, then emit discovered records
+		time.Sleep(100 * time.Millisecond)
+		app.Publisher.Emit(&record{"_googlecast._tcp", "Chromecast-1234", 8009}, true)
+		if evt := wait(ch, time.Second); evt == nil {
+			t.Error("Timeout waiting for device event")
+		} else if evt.Type() != gopi.DEVICE_EVENT_ADDED || evt.Device().Key() != "cast:Chromecast-1234" || evt.Device().Type() != "cast" {
+			t.Error("Unexpected event", evt)
+		}
+		app.Publisher.Emit(&record{"_googlecast._tcp", "Chromecast-1234", 8010}, true)
+		if evt := wait(ch, time.Second); evt == nil {
+			t.Error("Timeout waiting for device event")
+		} else if evt.Type() != gopi.DEVICE_EVENT_CHANGED || evt.Device().Port() != 8010 {
+			t.Error("Unexpected event", evt)
+		}
+
+		// Records for other services are ignored
+		app.Publisher.Emit(&record{"_other._tcp", "other", 80}, true)
+		if evt := wait(ch, 100*time.Millisecond); evt != nil {
+			t.Error("Unexpected event", evt)
+		} else if devices := app.DeviceRegistry.Devices(""); len(devices) != 1 {
+			t.Error("Unexpected devices", devices)
+		}
+	})
+}
+
+func Test_Registry_003(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		app.DeviceRegistry.Register(registry.NewDevice("test", &record{"_test._tcp", "a", 80}))
+		app.DeviceRegistry.Register(registry.NewDevice("other", &record{"_other._tcp", "b", 81}))
+		server := httptest.NewServer(registry.NewHandler("/api/devices", app.DeviceRegistry))
+		defer server.Close()
+
+		var devices []struct {
+			Key   string
+			Port  uint16
+			Addrs []string
+		}
+		if resp, err := http.Get(server.URL + "/api/devices?type=test"); err != nil {
+			t.Error(err)
+		} else if err := json.NewDecoder(resp.Body).Decode(&devices); err != nil {
+			t.Error(err)
+		} else if len(devices) != 1 || devices[0].Key != "test:a" || devices[0].Port != 80 || len(devices[0].Addrs) != 1 {
+			t.Error("Unexpected devices", devices)
+		}
+		if resp, err := http.Get(server.URL + "/api/devices/other:b"); err != nil {
+			t.Error(err)
+		} else if resp.StatusCode != http.StatusOK {
+			t.Error("Unexpected status", resp.Status)
+		}
+		if resp, err := http.Get(server.URL + "/api/devices/missing"); err != nil {
+			t.Error(err)
+		} else if resp.StatusCode != http.StatusNotFound {
+			t.Error("Unexpected status", resp.Status)
+		}
+	})
+}
+
+func Test_Registry_004(t *testing.T) {
+	tests := []struct {
+		ip, iface, addr string
+	}{
+		{"192.168.1.20", "eth0", "192.168.1.20:80"},
+		{"fd00::2", "eth0", "[fd00::2]:80"},
+		{"fe80::1", "eth0", "[fe80::1%eth0]:80"},
+		{"fe80::1", "", "[fe80::1]:80"},
+	}
+	for _, test := range tests {
+		device := registry.NewDevice("test", &ifaceRecord{record{"_test._tcp", "a", 80}, test.ip, test.iface})
+		if iface, ok := device.(gopi.ServiceIface); ok == false || iface.Iface() != test.iface {
+			t.Error("Unexpected interface for", device)
+		} else if addr := gopi.ServiceAddr(device, device.Addrs()[0]); addr != test.addr {
+			t.Errorf("Expected %q, got %q", test.addr, addr)
+		}
+	}
+}
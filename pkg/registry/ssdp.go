@@ -0,0 +1,233 @@
+package registry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ssdpRecord is a device discovered with SSDP, such as a DLNA renderer,
+// which is not advertised with mDNS
+type ssdpRecord struct {
+	usn, service, name string
+	host               string
+	port               uint16
+	addrs              []net.IP
+	txt                []string
+}
+
+// ssdpDescription is the part of a UPnP device description which
+// is used to name a device
+type ssdpDescription struct {
+	Device struct {
+		FriendlyName string `xml:"friendlyName"`
+		Manufacturer string `xml:"manufacturer"`
+		ModelName    string `xml:"modelName"`
+	} `xml:"device"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	defaultSSDP  = "urn:schemas-upnp-org:device:MediaRenderer:1=dlna"
+	ssdpMaxWait  = 5
+	ssdpBufSize  = 2048
+	ssdpMaxDescr = 64 * 1024
+)
+
+var (
+	// ssdpAddr is the multicast address for search requests, which
+	// is replaced in tests
+	ssdpAddr = &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *ssdpRecord) Instance() string { return this.usn }
+func (this *ssdpRecord) Service() string  { return this.service }
+func (this *ssdpRecord) Name() string     { return this.name }
+func (this *ssdpRecord) Zone() string     { return "" }
+func (this *ssdpRecord) Host() string     { return this.host }
+func (this *ssdpRecord) Port() uint16     { return this.port }
+func (this *ssdpRecord) Addrs() []net.IP  { return this.addrs }
+func (this *ssdpRecord) Txt() []string    { return this.txt }
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// search sends an M-SEARCH request for a search target and returns the
+// devices which respond before the timeout, named from their description
+func search(ctx context.Context, st string, timeout time.Duration) ([]*ssdpRecord, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Stop reading when the timeout is reached or the context is cancelled
+	read, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	go func() {
+		<-read.Done()
+		conn.SetReadDeadline(time.Now())
+	}()
+
+	// Send the request, asking devices to respond within the timeout
+	mx := int(timeout / time.Second)
+	if mx < 1 {
+		mx = 1
+	} else if mx > ssdpMaxWait {
+		mx = ssdpMaxWait
+	}
+	msg := "M-SEARCH * HTTP/1.1\r\n"
+	msg += "HOST: " + ssdpAddr.String() + "\r\n"
+	msg += "MAN: \"ssdp:discover\"\r\n"
+	msg += fmt.Sprint("MX: ", mx, "\r\n")
+	msg += "ST: " + st + "\r\n"
+	msg += "\r\n"
+	if _, err := conn.WriteToUDP([]byte(msg), ssdpAddr); err != nil {
+		return nil, err
+	}
+
+	// Read responses until the deadline, ignoring devices which
+	// have already responded
+	var result []*ssdpRecord
+	usns := make(map[string]bool)
+	buf := make([]byte, ssdpBufSize)
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			break
+		}
+		record := parseResponse(buf[:n], st, addr.IP)
+		if record == nil || usns[record.usn] {
+			continue
+		}
+		usns[record.usn] = true
+		result = append(result, record)
+	}
+
+	// Name each device from the description at its location, or
+	// keep the name from the UUID when there is no description
+	for _, record := range result {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		record.describe(ctx)
+		cancel()
+	}
+
+	// Return success
+	return result, nil
+}
+
+// parseResponse returns a record from a search response for a search
+// target, or nil if the response is not valid
+func parseResponse(data []byte, st string, ip net.IP) *ssdpRecord {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), nil)
+	if err != nil {
+		return nil
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ST") != st {
+		return nil
+	}
+	usn, location := resp.Header.Get("USN"), resp.Header.Get("LOCATION")
+	if usn == "" || location == "" {
+		return nil
+	}
+	url, err := url.Parse(location)
+	if err != nil || url.Hostname() == "" {
+		return nil
+	}
+
+	// Use the device UUID as the name until the description is read
+	record := &ssdpRecord{usn: usn, service: st, host: url.Hostname(), addrs: []net.IP{ip}}
+	record.name = strings.TrimPrefix(strings.SplitN(usn, "::", 2)[0], "uuid:")
+	if port, err := strconv.ParseUint(url.Port(), 10, 16); err == nil {
+		record.port = uint16(port)
+	} else if url.Scheme == "https" {
+		record.port = 443
+	} else {
+		record.port = 80
+	}
+	record.txt = append(record.txt, "location="+location, "usn="+usn)
+	if server := resp.Header.Get("SERVER"); server != "" {
+		record.txt = append(record.txt, "server="+server)
+	}
+	return record
+}
+
+// describe reads the device description from the location, and sets
+// the name and model of the device
+func (this *ssdpRecord) describe(ctx context.Context) error {
+	var location string
+	for _, txt := range this.txt {
+		if strings.HasPrefix(txt, "location=") {
+			location = strings.TrimPrefix(txt, "location=")
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%v: %v", location, resp.Status)
+	}
+
+	var descr ssdpDescription
+	if err := xml.NewDecoder(http.MaxBytesReader(nil, resp.Body, ssdpMaxDescr)).Decode(&descr); err != nil {
+		return err
+	}
+	if name := strings.TrimSpace(descr.Device.FriendlyName); name != "" {
+		this.name = name
+	}
+	if model := strings.TrimSpace(descr.Device.Manufacturer + " " + descr.Device.ModelName); model != "" {
+		this.txt = append(this.txt, "model="+model)
+	}
+
+	// Return success
+	return nil
+}
+
+// parseSearchTargets returns device types keyed by SSDP search target
+func parseSearchTargets(value string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		st, typ := field, ""
+		if i := strings.LastIndex(field, "="); i >= 0 {
+			st, typ = strings.TrimSpace(field[:i]), strings.TrimSpace(field[i+1:])
+		}
+		// Search targets are device or service types, for example
+		// urn:schemas-upnp-org:device:MediaRenderer:1
+		parts := strings.Split(st, ":")
+		if len(parts) != 5 || parts[0] != "urn" || (parts[2] != "device" && parts[2] != "service") {
+			return nil, fmt.Errorf("Invalid search target %q", st)
+		}
+		if typ == "" {
+			typ = strings.ToLower(parts[3])
+		}
+		result[st] = typ
+	}
+	return result, nil
+}
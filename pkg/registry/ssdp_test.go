@@ -0,0 +1,144 @@
+package registry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testRenderer = "urn:schemas-upnp-org:device:MediaRenderer:1"
+	testDescr    = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:MediaRenderer:1</deviceType>
+    <friendlyName>Living Room</friendlyName>
+    <manufacturer>Acme</manufacturer>
+    <modelName>Renderer</modelName>
+  </device>
+</root>`
+)
+
+// respond answers M-SEARCH requests for the renderer twice, so that
+// duplicate responses are tested, until the connection is closed
+func respond(t *testing.T, conn *net.UDPConn, location string) {
+	buf := make([]byte, ssdpBufSize)
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(buf[:n])))
+		if err != nil || req.Method != "M-SEARCH" || req.Header.Get("ST") != testRenderer {
+			t.Error("Unexpected request", string(buf[:n]))
+			continue
+		}
+		msg := "HTTP/1.1 200 OK\r\n"
+		msg += "CACHE-CONTROL: max-age=1800\r\n"
+		msg += "EXT:\r\n"
+		msg += "LOCATION: " + location + "\r\n"
+		msg += "SERVER: Linux/1.0 UPnP/1.0 Test/1.0\r\n"
+		msg += "ST: " + testRenderer + "\r\n"
+		msg += "USN: uuid:1234::" + testRenderer + "\r\n"
+		msg += "\r\n"
+		for i := 0; i < 2; i++ {
+			conn.WriteToUDP([]byte(msg), addr)
+		}
+	}
+}
+
+func Test_SSDP_001(t *testing.T) {
+	tests := []struct {
+		value string
+		st    string
+		typ   string
+	}{
+		{defaultSSDP, testRenderer, "dlna"},
+		{testRenderer, testRenderer, "mediarenderer"},
+		{"urn:schemas-upnp-org:service:AVTransport:1=av", "urn:schemas-upnp-org:service:AVTransport:1", "av"},
+	}
+	for _, test := range tests {
+		if targets, err := parseSearchTargets(test.value); err != nil {
+			t.Error(err)
+		} else if len(targets) != 1 || targets[test.st] != test.typ {
+			t.Error("Unexpected targets", targets)
+		}
+	}
+	for _, value := range []string{"_dlna._tcp", "urn:schemas-upnp-org:MediaRenderer"} {
+		if _, err := parseSearchTargets(value); err == nil {
+			t.Error("Expected error for", value)
+		}
+	}
+	if targets, err := parseSearchTargets(""); err != nil || len(targets) != 0 {
+		t.Error("Expected no targets", targets, err)
+	}
+}
+
+func Test_SSDP_002(t *testing.T) {
+	// Serve the device description
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprint(w, testDescr)
+	}))
+	defer server.Close()
+
+	// Respond to search requests on a local address
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	go respond(t, conn, server.URL+"/description.xml")
+
+	addr := ssdpAddr
+	ssdpAddr = conn.LocalAddr().(*net.UDPAddr)
+	defer func() { ssdpAddr = addr }()
+
+	records, err := search(context.Background(), testRenderer, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(records) != 1 {
+		t.Fatal("Unexpected records", records)
+	}
+	record := records[0]
+	if record.Name() != "Living Room" || record.Service() != testRenderer || record.Host() != "127.0.0.1" {
+		t.Error("Unexpected record", record)
+	} else if port := server.Listener.Addr().(*net.TCPAddr).Port; int(record.Port()) != port {
+		t.Error("Unexpected port", record.Port())
+	} else if len(record.Addrs()) != 1 || record.Addrs()[0].Equal(net.IPv4(127, 0, 0, 1)) == false {
+		t.Error("Unexpected addrs", record.Addrs())
+	} else if txt := strings.Join(record.Txt(), ","); strings.Contains(txt, "model=Acme Renderer") == false || strings.Contains(txt, "usn=uuid:1234::") == false {
+		t.Error("Unexpected txt", txt)
+	}
+
+	// The record becomes a device with the type for the search target
+	device := &device{record, "dlna", time.Now(), false}
+	if device.Key() != "dlna:Living Room" {
+		t.Error("Unexpected key", device.Key())
+	}
+}
+
+func Test_SSDP_003(t *testing.T) {
+	// Devices without a description are named from the UUID
+	response := "HTTP/1.1 200 OK\r\nLOCATION: http://192.168.1.30:49152/missing.xml\r\nST: " + testRenderer + "\r\nUSN: uuid:5678::" + testRenderer + "\r\n\r\n"
+	if record := parseResponse([]byte(response), testRenderer, net.IPv4(192, 168, 1, 30)); record == nil {
+		t.Error("Expected record")
+	} else if record.Name() != "5678" || record.Port() != 49152 || record.Host() != "192.168.1.30" {
+		t.Error("Unexpected record", record)
+	}
+
+	// Responses for other search targets are ignored
+	if record := parseResponse([]byte(response), "urn:schemas-upnp-org:device:MediaServer:1", nil); record != nil {
+		t.Error("Unexpected record", record)
+	}
+	if record := parseResponse([]byte("NOTIFY * HTTP/1.1\r\n\r\n"), testRenderer, nil); record != nil {
+		t.Error("Unexpected record", record)
+	}
+}
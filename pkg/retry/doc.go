@@ -0,0 +1,17 @@
+// Retry package implements exponential backoff with jitter for units which
+// reconnect to network services, so that many devices which lose a
+// connection at the same time do not retry together. Each delay is
+// between half and all of the current backoff, which starts at a minimum
+// and doubles up to a maximum, and a budget limits the number of
+// attempts. The backoff is reset after a successful attempt.
+//
+// Units define flags for their backoff with Define, for example
+// -iot.retry.min, -iot.retry.max and -iot.retry.budget, and then either
+// call Wait between attempts or Do with a function to retry:
+//
+//	err := retry.Do(ctx, backoff, func(ctx context.Context) error {
+//	  return this.connect(ctx)
+//	})
+//
+// A function returns an error wrapped with Permanent to stop retrying.
+package retry
@@ -0,0 +1,211 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Backoff returns delays between attempts
+type Backoff struct {
+	sync.Mutex
+
+	min, max time.Duration
+	budget   uint
+	attempts uint
+	current  time.Duration
+	rand     *rand.Rand
+}
+
+// Flags are the flags for a unit backoff
+type Flags struct {
+	prefix   string
+	min, max *time.Duration
+	budget   *uint
+}
+
+// permanent is an error which is not retried
+type permanent struct {
+	error
+}
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// ErrBudget is returned when there are no more attempts
+	ErrBudget = errors.New("Retry budget exhausted")
+)
+
+/////////////////////////////////////////////////////////////////////
+// NEW
+
+// New returns a backoff with minimum and maximum delays, and a budget
+// of attempts before giving up, or zero for no limit
+func New(min, max time.Duration, budget uint) *Backoff {
+	this := new(Backoff)
+	if min <= 0 {
+		min = time.Millisecond
+	}
+	if max < min {
+		max = min
+	}
+	this.min, this.max, this.budget = min, max, budget
+	this.current = min
+	this.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	return this
+}
+
+// Define returns flags for a unit backoff with a prefix, for example
+// "iot", and defaults for the minimum and maximum delay and the budget
+func Define(cfg gopi.Config, prefix string, min, max time.Duration, budget uint) *Flags {
+	this := new(Flags)
+	this.prefix = prefix
+	this.min = cfg.FlagDuration(prefix+".retry.min", min, "Initial delay before retrying")
+	this.max = cfg.FlagDuration(prefix+".retry.max", max, "Maximum delay before retrying")
+	this.budget = cfg.FlagUint(prefix+".retry.budget", budget, "Number of attempts before giving up, or zero for no limit")
+	return this
+}
+
+// New returns a backoff from the flags, or an error if the
+// flags are invalid
+func (this *Flags) New() (*Backoff, error) {
+	if *this.min <= 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("-", this.prefix, ".retry.min")
+	} else if *this.max < *this.min {
+		return nil, gopi.ErrBadParameter.WithPrefix("-", this.prefix, ".retry.max")
+	} else {
+		return New(*this.min, *this.max, *this.budget), nil
+	}
+}
+
+// Permanent wraps an error so that it is not retried
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	} else {
+		return &permanent{err}
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Backoff) String() string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	str := "<retry"
+	str += fmt.Sprint(" min=", this.min, " max=", this.max)
+	if this.budget > 0 {
+		str += fmt.Sprint(" budget=", this.budget)
+	}
+	str += fmt.Sprint(" attempts=", this.attempts)
+	return str + ">"
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Next returns the delay before the next attempt, or false when the
+// budget is exhausted
+func (this *Backoff) Next() (time.Duration, bool) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.budget > 0 && this.attempts >= this.budget {
+		return 0, false
+	}
+	this.attempts++
+
+	// Delay is between half and all of the current backoff
+	half := this.current / 2
+	delay := half + time.Duration(this.rand.Int63n(int64(this.current-half)+1))
+	if this.current *= 2; this.current > this.max || this.current <= 0 {
+		this.current = this.max
+	}
+	return delay, true
+}
+
+// Reset the backoff after a successful attempt
+func (this *Backoff) Reset() {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.attempts = 0
+	this.current = this.min
+}
+
+// Clone returns a backoff with the same delays and budget, for
+// concurrent attempts
+func (this *Backoff) Clone() *Backoff {
+	return New(this.min, this.max, this.budget)
+}
+
+// Min returns the initial delay
+func (this *Backoff) Min() time.Duration {
+	return this.min
+}
+
+// Max returns the maximum delay
+func (this *Backoff) Max() time.Duration {
+	return this.max
+}
+
+// Attempts returns the number of attempts since the backoff was reset
+func (this *Backoff) Attempts() uint {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.attempts
+}
+
+// Wait for the next delay, and return ErrBudget when the budget is
+// exhausted or the context error when it is cancelled
+func (this *Backoff) Wait(ctx context.Context) error {
+	delay, ok := this.Next()
+	if ok == false {
+		return ErrBudget
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Do calls a function until it succeeds, returns a permanent error, the
+// budget is exhausted or the context is cancelled. The last error from the
+// function is returned
+func Do(ctx context.Context, backoff *Backoff, fn func(context.Context) error) error {
+	for {
+		err := fn(ctx)
+		if err == nil {
+			backoff.Reset()
+			return nil
+		}
+		var p *permanent
+		if errors.As(err, &p) {
+			return p.error
+		}
+		if err_ := backoff.Wait(ctx); err_ != nil {
+			return fmt.Errorf("%w (%v)", err, err_)
+		}
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// ERRORS
+
+func (this *permanent) Unwrap() error {
+	return this.error
+}
@@ -0,0 +1,84 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retry "github.com/djthorpe/gopi/v3/pkg/retry"
+)
+
+func Test_Retry_001(t *testing.T) {
+	b := retry.New(10*time.Millisecond, 40*time.Millisecond, 0)
+	for i, max := range []time.Duration{10, 20, 40, 40, 40} {
+		max *= time.Millisecond
+		if delay, ok := b.Next(); ok == false {
+			t.Error("Expected unlimited budget")
+		} else if delay < max/2 || delay > max {
+			t.Error("Unexpected delay", i, delay)
+		}
+	}
+	if b.Attempts() != 5 {
+		t.Error("Unexpected attempts", b.Attempts())
+	}
+	b.Reset()
+	if delay, _ := b.Next(); delay > 10*time.Millisecond {
+		t.Error("Unexpected delay after reset", delay)
+	}
+	t.Log(b)
+}
+
+func Test_Retry_002(t *testing.T) {
+	b := retry.New(time.Millisecond, time.Millisecond, 3)
+	calls := 0
+	err := retry.Do(context.Background(), b, func(context.Context) error {
+		calls++
+		return errors.New("failed")
+	})
+	if err == nil {
+		t.Error("Expected error when budget exhausted")
+	} else if calls != 4 {
+		t.Error("Unexpected calls", calls)
+	}
+
+	// Success resets the budget
+	calls = 0
+	b.Reset()
+	if err := retry.Do(context.Background(), b, func(context.Context) error {
+		if calls++; calls < 3 {
+			return errors.New("failed")
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	} else if calls != 3 || b.Attempts() != 0 {
+		t.Error("Unexpected calls", calls, b.Attempts())
+	}
+}
+
+func Test_Retry_003(t *testing.T) {
+	b := retry.New(time.Millisecond, time.Millisecond, 0)
+	failed := errors.New("failed")
+	calls := 0
+	if err := retry.Do(context.Background(), b, func(context.Context) error {
+		calls++
+		return retry.Permanent(failed)
+	}); err != failed {
+		t.Error("Unexpected error", err)
+	} else if calls != 1 {
+		t.Error("Unexpected calls", calls)
+	}
+
+	// Cancelled context ends retries
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	b = retry.New(time.Hour, time.Hour, 0)
+	if err := retry.Do(ctx, b, func(context.Context) error {
+		return failed
+	}); errors.Is(err, failed) == false {
+		t.Error("Unexpected error", err)
+	} else if ctx.Err() == nil {
+		t.Error("Expected context to be done")
+	}
+}
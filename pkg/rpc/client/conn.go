@@ -7,6 +7,7 @@ import (
 
 	gopi "github.com/djthorpe/gopi/v3"
 	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	interceptor "github.com/djthorpe/gopi/v3/pkg/rpc/interceptor"
 	multierror "github.com/hashicorp/go-multierror"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -131,12 +132,10 @@ func (this *conn) Err(err error) error {
 	switch grpc.Code(err) {
 	case codes.Canceled:
 		return context.Canceled
-	case codes.Unavailable:
-		return gopi.ErrUnexpectedResponse
 	case codes.DeadlineExceeded:
 		return context.DeadlineExceeded
 	default:
-		return err
+		return interceptor.FromStatus(err)
 	}
 }
 
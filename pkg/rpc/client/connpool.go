@@ -2,15 +2,23 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	gopi "github.com/djthorpe/gopi/v3"
+	retry "github.com/djthorpe/gopi/v3/pkg/retry"
+	interceptor "github.com/djthorpe/gopi/v3/pkg/rpc/interceptor"
 	multierror "github.com/hashicorp/go-multierror"
 	grpc "google.golang.org/grpc"
+	backoff "google.golang.org/grpc/backoff"
+	credentials "google.golang.org/grpc/credentials"
 )
 
 /////////////////////////////////////////////////////////////////////
@@ -21,8 +29,14 @@ type connpool struct {
 	sync.Mutex
 	gopi.Logger
 	gopi.ServiceDiscovery
+	gopi.Metrics
+	gopi.Publisher
 
-	conns []gopi.Conn
+	cert, key, ca *string
+	retry         *retry.Flags
+	conns         []gopi.Conn
+	interceptor   *interceptor.Interceptor
+	backoff       *retry.Backoff
 }
 
 /////////////////////////////////////////////////////////////////////
@@ -36,11 +50,35 @@ var (
 /////////////////////////////////////////////////////////////////////
 // INIT
 
-func (this *connpool) New(gopi.Config) error {
+func (this *connpool) Define(cfg gopi.Config) error {
+	this.cert = cfg.FlagString("client.cert", "", "SSL client certificate file")
+	this.key = cfg.FlagString("client.key", "", "SSL client key file")
+	this.ca = cfg.FlagString("client.ca", "", "SSL certificate authority file for verifying servers")
+	cfg.FlagString("client.measurement", "rpc.client", "Measurement name for method latency and errors")
+	cfg.FlagBool("client.trace", false, "Emit trace events for each method call")
+	this.retry = retry.Define(cfg, "client", time.Second, time.Minute, 5)
+	return nil
+}
+
+func (this *connpool) New(cfg gopi.Config) error {
 	if this.ServiceDiscovery == nil {
 		return gopi.ErrInternalAppError.WithPrefix("ServiceDiscovery")
 	}
 
+	// Record latency and errors for each method
+	if i, err := interceptor.New("client", this.Metrics, this.Publisher, cfg.GetString("client.measurement"), cfg.GetBool("client.trace")); err != nil {
+		return err
+	} else {
+		this.interceptor = i
+	}
+
+	// Backoff for service lookup and reconnecting
+	if backoff, err := this.retry.New(); err != nil {
+		return err
+	} else {
+		this.backoff = backoff
+	}
+
 	// Return success
 	return nil
 }
@@ -71,7 +109,9 @@ func (this *connpool) Connect(network, addr string) (gopi.Conn, error) {
 	switch network {
 	case "tcp":
 		this.Debugf("Connect: %q,%q", network, addr)
-		if conn, err := grpc.Dial(addr, grpc.WithInsecure()); err != nil {
+		if opt, err := this.credentialOption(); err != nil {
+			return nil, err
+		} else if conn, err := grpc.Dial(addr, append(this.interceptorOptions(), opt, this.backoffOption())...); err != nil {
 			return nil, err
 		} else if client := NewConn(conn); client == nil {
 			return nil, gopi.ErrInternalAppError.WithPrefix(addr)
@@ -107,16 +147,27 @@ func (this *connpool) ConnectService(ctx context.Context, network, service strin
 		return this.Connect(network, service)
 	}
 
-	// Normalize service name, lookup and connect
-	if service, err := fqn(service, network); err != nil {
+	// Normalize service name, and lookup until the service is found
+	service, err := fqn(service, network)
+	if err != nil {
 		return nil, err
-	} else if records, err := this.ServiceDiscovery.Lookup(ctx, service); err != nil {
-		return nil, err
-	} else if addr, err := addr(records, name, flags); err != nil {
+	}
+	var result string
+	if err := retry.Do(ctx, this.backoff.Clone(), func(ctx context.Context) error {
+		if records, err := this.ServiceDiscovery.Lookup(ctx, service); err != nil {
+			return err
+		} else if addr, err := addr(records, name, flags); err != nil {
+			return err
+		} else {
+			result = addr
+			return nil
+		}
+	}); err != nil {
 		return nil, err
-	} else {
-		return this.Connect(network, addr)
 	}
+
+	// Connect to the service
+	return this.Connect(network, result)
 }
 
 /////////////////////////////////////////////////////////////////////
@@ -131,6 +182,52 @@ func (this *connpool) String() string {
 /////////////////////////////////////////////////////////////////////
 // PRIVATE METHODS
 
+// credentialOption returns TLS credentials when a client certificate
+// or certificate authority is set, or an insecure option otherwise
+func (this *connpool) credentialOption() (grpc.DialOption, error) {
+	if *this.cert == "" && *this.key == "" && *this.ca == "" {
+		return grpc.WithInsecure(), nil
+	}
+	config := &tls.Config{}
+	if *this.cert != "" || *this.key != "" {
+		if cert, err := tls.LoadX509KeyPair(*this.cert, *this.key); err != nil {
+			return nil, err
+		} else {
+			config.Certificates = []tls.Certificate{cert}
+		}
+	}
+	if *this.ca != "" {
+		if pem, err := ioutil.ReadFile(*this.ca); err != nil {
+			return nil, err
+		} else if pool := x509.NewCertPool(); pool.AppendCertsFromPEM(pem) == false {
+			return nil, gopi.ErrBadParameter.WithPrefix("-client.ca")
+		} else {
+			config.RootCAs = pool
+		}
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(config)), nil
+}
+
+// backoffOption returns the backoff for reconnecting to a server
+func (this *connpool) backoffOption() grpc.DialOption {
+	return grpc.WithConnectParams(grpc.ConnectParams{
+		Backoff: backoff.Config{
+			BaseDelay:  this.backoff.Min(),
+			Multiplier: 2,
+			Jitter:     0.5,
+			MaxDelay:   this.backoff.Max(),
+		},
+	})
+}
+
+// interceptorOptions record latency and errors for each method
+func (this *connpool) interceptorOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(this.interceptor.UnaryClient()),
+		grpc.WithChainStreamInterceptor(this.interceptor.StreamClient()),
+	}
+}
+
 func fqn(service, network string) (string, error) {
 	service = "_" + strings.Trim(service, "_") + "._" + network + "."
 	if reServiceName.MatchString(service) == false {
@@ -148,15 +245,15 @@ func addr(r []gopi.ServiceRecord, name string, flags gopi.ServiceFlag) (string,
 		}
 		// If flags is none, then return hostname
 		if flags == gopi.SERVICE_FLAG_NONE {
-			return fmt.Sprint(record.Host(), ":", record.Port()), nil
+			return net.JoinHostPort(record.Host(), fmt.Sprint(record.Port())), nil
 		}
 		// Get an address
 		for _, addr := range record.Addrs() {
 			switch {
 			case (flags&gopi.SERVICE_FLAG_IP6 != 0 || flags == gopi.SERVICE_FLAG_NONE) && addr.To4() == nil:
-				return fmt.Sprintf("%v:%v", addr.To16(), record.Port()), nil
+				return gopi.ServiceAddr(record, addr), nil
 			case (flags&gopi.SERVICE_FLAG_IP4 != 0 || flags == gopi.SERVICE_FLAG_NONE) && addr.To4() != nil:
-				return fmt.Sprintf("%v:%v", addr.To16(), record.Port()), nil
+				return gopi.ServiceAddr(record, addr), nil
 			}
 		}
 	}
@@ -0,0 +1,95 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	grpc "google.golang.org/grpc"
+	credentials "google.golang.org/grpc/credentials"
+	metadata "google.golang.org/grpc/metadata"
+	peer "google.golang.org/grpc/peer"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Access rejects calls to methods from clients which do not have the
+// role required for the method, and adds the identity of the client
+// to the context for the call
+type Access struct {
+	gopi.AccessControl
+}
+
+// accessStream replaces the context of a server stream
+type accessStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+/////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewAccess(access gopi.AccessControl) *Access {
+	return &Access{access}
+}
+
+/////////////////////////////////////////////////////////////////////
+// SERVER INTERCEPTORS
+
+func (this *Access) UnaryServer() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := this.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, ToStatus(err)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServer passes the identity to the stream handler in the
+// stream context
+func (this *Access) StreamServer() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := this.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return ToStatus(err)
+		}
+		return handler(srv, &accessStream{ss, ctx})
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// authorize returns a context with the identity of the client, from a
+// verified client certificate or a bearer token in the "authorization"
+// metadata, when it has the role required for the method
+func (this *Access) authorize(ctx context.Context, method string) (context.Context, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+			ctx = gopi.WithAccessIdentity(ctx, gopi.AccessIdentity{Name: info.State.VerifiedChains[0][0].Subject.CommonName})
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, value := range md.Get("authorization") {
+			if strings.HasPrefix(value, "Bearer ") {
+				if ctx_, err := this.AccessControl.Authenticate(ctx, strings.TrimSpace(strings.TrimPrefix(value, "Bearer "))); err != nil {
+					return nil, err
+				} else {
+					ctx = ctx_
+				}
+			}
+		}
+	}
+	if err := this.AccessControl.Authorize(ctx, method); err != nil {
+		return nil, err
+	}
+
+	// Return success
+	return ctx, nil
+}
+
+func (this *accessStream) Context() context.Context {
+	return this.ctx
+}
@@ -0,0 +1,72 @@
+package interceptor
+
+import (
+	"context"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	grpc "google.golang.org/grpc"
+	peer "google.golang.org/grpc/peer"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Audit records calls to methods which change state in an audit log
+type Audit struct {
+	gopi.AuditLog
+}
+
+/////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewAudit(audit gopi.AuditLog) *Audit {
+	return &Audit{audit}
+}
+
+/////////////////////////////////////////////////////////////////////
+// SERVER INTERCEPTORS
+
+func (this *Audit) UnaryServer() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if gopi.AuditMethod(info.FullMethod) == false {
+			return handler(ctx, req)
+		}
+		ctx = gopi.WithAuditChange(ctx)
+		resp, err := handler(ctx, req)
+		this.record(ctx, info.FullMethod, err)
+		return resp, err
+	}
+}
+
+func (this *Audit) StreamServer() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if gopi.AuditMethod(info.FullMethod) == false {
+			return handler(srv, ss)
+		}
+		ctx := gopi.WithAuditChange(ss.Context())
+		err := handler(srv, &accessStream{ss, ctx})
+		this.record(ctx, info.FullMethod, err)
+		return err
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// record a call, with the identity of the client or the address of
+// the peer when the client is anonymous
+func (this *Audit) record(ctx context.Context, method string, err error) {
+	entry := gopi.AuditEntry{
+		Source: "grpc",
+		Action: method,
+	}
+	if _, ok := gopi.AccessIdentityFromContext(ctx); ok == false {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			entry.Identity = p.Addr.String()
+		}
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	this.AuditLog.Record(ctx, entry)
+}
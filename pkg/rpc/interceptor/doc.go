@@ -0,0 +1,17 @@
+// Package interceptor provides gRPC interceptors for servers and clients
+// which record the latency and errors for each method.
+//
+// When metrics are enabled, a measurement is emitted for each call with
+// the method, latency in milliseconds and whether an error was returned.
+// When tracing is enabled, an Event is published for each call. Clients
+// send a trace identifier in call metadata, which servers use in their
+// own events and pass on to any calls made while handling the call, so
+// that calls between devices can be followed. Trace events can be
+// exported as spans to a tracing system by a subscriber.
+//
+// Servers with access control reject calls from clients which do not
+// have the role required for a method. Clients are identified by the
+// common name of a verified certificate or by a bearer token in the
+// "authorization" metadata. Servers with an audit log record calls to
+// methods which change state.
+package interceptor
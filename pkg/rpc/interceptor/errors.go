@@ -0,0 +1,135 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Errors converts errors returned by services into gRPC status errors,
+// so that clients receive a status code for each gopi error
+type Errors struct{}
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	codeForError = map[gopi.Error]codes.Code{
+		gopi.ErrNone:               codes.OK,
+		gopi.ErrBadParameter:       codes.InvalidArgument,
+		gopi.ErrHelp:               codes.InvalidArgument,
+		gopi.ErrNotImplemented:     codes.Unimplemented,
+		gopi.ErrNotFound:           codes.NotFound,
+		gopi.ErrUnexpectedResponse: codes.Unknown,
+		gopi.ErrInternalAppError:   codes.Internal,
+		gopi.ErrDuplicateEntry:     codes.AlreadyExists,
+		gopi.ErrOutOfOrder:         codes.FailedPrecondition,
+		gopi.ErrChannelFull:        codes.ResourceExhausted,
+		gopi.ErrPermissionDenied:   codes.PermissionDenied,
+		gopi.ErrUnavailable:        codes.Unavailable,
+		gopi.ErrTimeout:            codes.DeadlineExceeded,
+		gopi.ErrCancelled:          codes.Canceled,
+	}
+	errorForCode = map[codes.Code]gopi.Error{
+		codes.OK:                 gopi.ErrNone,
+		codes.InvalidArgument:    gopi.ErrBadParameter,
+		codes.OutOfRange:         gopi.ErrBadParameter,
+		codes.Unimplemented:      gopi.ErrNotImplemented,
+		codes.NotFound:           gopi.ErrNotFound,
+		codes.Unknown:            gopi.ErrUnexpectedResponse,
+		codes.DataLoss:           gopi.ErrUnexpectedResponse,
+		codes.Internal:           gopi.ErrInternalAppError,
+		codes.AlreadyExists:      gopi.ErrDuplicateEntry,
+		codes.FailedPrecondition: gopi.ErrOutOfOrder,
+		codes.Aborted:            gopi.ErrOutOfOrder,
+		codes.ResourceExhausted:  gopi.ErrChannelFull,
+		codes.PermissionDenied:   gopi.ErrPermissionDenied,
+		codes.Unauthenticated:    gopi.ErrPermissionDenied,
+		codes.Unavailable:        gopi.ErrUnavailable,
+		codes.DeadlineExceeded:   gopi.ErrTimeout,
+		codes.Canceled:           gopi.ErrCancelled,
+	}
+)
+
+/////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewErrors() *Errors {
+	return new(Errors)
+}
+
+/////////////////////////////////////////////////////////////////////
+// SERVER INTERCEPTORS
+
+func (this *Errors) UnaryServer() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, ToStatus(err)
+	}
+}
+
+func (this *Errors) StreamServer() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return ToStatus(handler(srv, ss))
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Code returns the gRPC status code for an error, which is the code of
+// a status error or else the code for the gopi.Error it wraps
+func Code(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	} else if s, ok := status.FromError(err); ok {
+		return s.Code()
+	} else if code, exists := codeForError[gopi.ErrorCode(err)]; exists {
+		return code
+	} else {
+		return codes.Unknown
+	}
+}
+
+// ToStatus returns a gRPC status error for an error, or nil if the
+// error is nil. Status errors are returned unchanged
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	} else if _, ok := status.FromError(err); ok {
+		return err
+	} else {
+		return status.Error(Code(err), err.Error())
+	}
+}
+
+// FromStatus returns an error which wraps the gopi.Error for a gRPC
+// status error, keeping the message. Other errors are returned
+// unchanged
+func FromStatus(err error) error {
+	s, ok := status.FromError(err)
+	if err == nil || ok == false {
+		return err
+	}
+	code, exists := errorForCode[s.Code()]
+	if exists == false {
+		code = gopi.ErrUnexpectedResponse
+	}
+
+	// Avoid repeating the error when the message already ends with it
+	if msg := s.Message(); msg == code.Error() {
+		return code
+	} else if strings.HasSuffix(msg, ": "+code.Error()) {
+		return code.WithPrefix(strings.TrimSuffix(msg, ": "+code.Error()))
+	} else {
+		return fmt.Errorf("%v (%w)", msg, code)
+	}
+}
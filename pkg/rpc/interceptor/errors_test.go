@@ -0,0 +1,78 @@
+package interceptor_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	interceptor "github.com/djthorpe/gopi/v3/pkg/rpc/interceptor"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Errors_001(t *testing.T) {
+	tests := []struct {
+		err       error
+		code      codes.Code
+		httpcode  int
+		retryable bool
+	}{
+		{nil, codes.OK, http.StatusOK, false},
+		{gopi.ErrBadParameter, codes.InvalidArgument, http.StatusBadRequest, false},
+		{gopi.ErrNotFound.WithPrefix("Lookup"), codes.NotFound, http.StatusNotFound, false},
+		{gopi.ErrChannelFull, codes.ResourceExhausted, http.StatusTooManyRequests, true},
+		{gopi.ErrUnavailable.WithContext("rotel", "/dev/ttyUSB0", "open"), codes.Unavailable, http.StatusServiceUnavailable, true},
+		{fmt.Errorf("Wait: %w", context.DeadlineExceeded), codes.DeadlineExceeded, http.StatusGatewayTimeout, true},
+		{context.Canceled, codes.Canceled, 499, false},
+		{errors.New("Other"), codes.Internal, http.StatusInternalServerError, false},
+	}
+	for i, test := range tests {
+		if code := interceptor.Code(test.err); code != test.code {
+			t.Errorf("%d: Expected %v, got %v", i, test.code, code)
+		}
+		if httpcode := gopi.ErrorCode(test.err).HttpStatus(); httpcode != test.httpcode {
+			t.Errorf("%d: Expected %v, got %v", i, test.httpcode, httpcode)
+		}
+		if retryable := gopi.IsRetryable(test.err); retryable != test.retryable {
+			t.Errorf("%d: Expected %v, got %v", i, test.retryable, retryable)
+		}
+	}
+}
+
+func Test_Errors_002(t *testing.T) {
+	err := gopi.WrapError(gopi.ErrPermissionDenied.WithPrefix("Write"), "gpio", "GPIO17", "write")
+	if err.Error() != "gpio: write GPIO17: Write: Permission Denied" {
+		t.Error("Unexpected error", err)
+	}
+	var ctx *gopi.UnitError
+	if errors.As(err, &ctx) == false || ctx.Unit != "gpio" || ctx.Resource != "GPIO17" || ctx.Op != "write" {
+		t.Error("Unexpected context", ctx)
+	}
+	if gopi.WrapError(nil, "gpio", "", "") != nil {
+		t.Error("Expected nil")
+	}
+
+	// Convert to a status on the server and back on the client
+	s := interceptor.ToStatus(err)
+	if status.Code(s) != codes.PermissionDenied {
+		t.Error("Unexpected status", s)
+	} else if interceptor.ToStatus(s) != s {
+		t.Error("Expected status unchanged")
+	}
+	if err := interceptor.FromStatus(s); errors.Is(err, gopi.ErrPermissionDenied) == false {
+		t.Error("Unexpected error", err)
+	} else if err.Error() != "gpio: write GPIO17: Write: Permission Denied" {
+		t.Error("Unexpected message", err)
+	}
+	if err := interceptor.FromStatus(status.Error(codes.Unavailable, "Connection refused")); errors.Is(err, gopi.ErrUnavailable) == false {
+		t.Error("Unexpected error", err)
+	} else if gopi.IsRetryable(err) == false {
+		t.Error("Expected retryable", err)
+	}
+}
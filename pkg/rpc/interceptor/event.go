@@ -0,0 +1,54 @@
+package interceptor
+
+import (
+	"fmt"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Event is emitted for each call when tracing is enabled. Calls
+// made on behalf of another call share the same trace identifier,
+// including calls between devices
+type Event struct {
+	Kind     string
+	TraceId  string
+	Method   string
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+/////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(kind, id, method string, start time.Time, duration time.Duration, err error) gopi.Event {
+	return &Event{kind, id, method, start, duration, err}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *Event) Name() string {
+	return this.Method
+}
+
+/////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Event) String() string {
+	str := "<rpc.trace"
+	str += fmt.Sprintf(" kind=%q", this.Kind)
+	if this.TraceId != "" {
+		str += fmt.Sprintf(" id=%q", this.TraceId)
+	}
+	str += fmt.Sprintf(" method=%q", this.Method)
+	str += fmt.Sprint(" duration=", this.Duration)
+	if this.Err != nil {
+		str += fmt.Sprintf(" err=%q", this.Err.Error())
+	}
+	return str + ">"
+}
@@ -0,0 +1,242 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Interceptor records latency and errors for each method called on
+// a server or by a client, and emits measurements and trace events
+type Interceptor struct {
+	sync.Mutex
+	gopi.Metrics
+	gopi.Publisher
+
+	kind        string
+	measurement string
+	trace       bool
+	stats       map[string]*Stat
+}
+
+// Stat is the number of calls, errors and latency for a method
+type Stat struct {
+	Calls, Errors uint64
+	Total, Max    time.Duration
+}
+
+/////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Metadata key for propagating trace identifiers between devices
+	traceKey = "gopi-trace-id"
+)
+
+/////////////////////////////////////////////////////////////////////
+// NEW
+
+// New returns an interceptor for "server" or "client" calls. Measurements
+// are emitted when metrics is not nil and measurement is not empty, and
+// trace events when publisher is not nil and trace is true
+func New(kind string, metrics gopi.Metrics, publisher gopi.Publisher, measurement string, trace bool) (*Interceptor, error) {
+	this := new(Interceptor)
+	this.kind = kind
+	this.stats = make(map[string]*Stat)
+
+	// Define measurement
+	if metrics != nil && measurement != "" {
+		if m, err := metrics.NewMeasurement(measurement, "kind string, method string, latency float64, error bool", metrics.HostTag()); err != nil {
+			return nil, err
+		} else {
+			this.Metrics = metrics
+			this.measurement = m.Name()
+		}
+	}
+
+	// Set trace
+	if publisher != nil && trace {
+		this.Publisher = publisher
+		this.trace = true
+	}
+
+	// Return success
+	return this, nil
+}
+
+/////////////////////////////////////////////////////////////////////
+// SERVER INTERCEPTORS
+
+func (this *Interceptor) UnaryServer() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		this.record(incomingTrace(ctx), info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServer records the duration of a stream from start to end
+func (this *Interceptor) StreamServer() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		this.record(incomingTrace(ss.Context()), info.FullMethod, start, err)
+		return err
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// CLIENT INTERCEPTORS
+
+func (this *Interceptor) UnaryClient() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, id := this.outgoingTrace(ctx)
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		this.record(id, method, start, err)
+		return err
+	}
+}
+
+// StreamClient records the time taken to establish a stream
+func (this *Interceptor) StreamClient() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, id := this.outgoingTrace(ctx)
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		this.record(id, method, start, err)
+		return stream, err
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+// Stats returns a copy of the statistics for each method
+func (this *Interceptor) Stats() map[string]Stat {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	result := make(map[string]Stat, len(this.stats))
+	for method, stat := range this.stats {
+		result[method] = *stat
+	}
+	return result
+}
+
+// Mean returns the mean latency
+func (s Stat) Mean() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	} else {
+		return s.Total / time.Duration(s.Calls)
+	}
+}
+
+// ErrorRate returns the fraction of calls which returned an error
+func (s Stat) ErrorRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	} else {
+		return float64(s.Errors) / float64(s.Calls)
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Interceptor) String() string {
+	stats := this.Stats()
+	methods := make([]string, 0, len(stats))
+	for method := range stats {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	str := "<interceptor"
+	str += fmt.Sprintf(" kind=%q", this.kind)
+	for _, method := range methods {
+		stat := stats[method]
+		str += fmt.Sprintf(" %v={calls=%v errors=%v mean=%v max=%v}", method, stat.Calls, stat.Errors, stat.Mean(), stat.Max)
+	}
+	return str + ">"
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// record updates statistics for a method, and emits a measurement
+// and trace event
+func (this *Interceptor) record(id, method string, start time.Time, err error) {
+	latency := time.Since(start)
+
+	// Update statistics
+	this.Mutex.Lock()
+	stat, exists := this.stats[method]
+	if exists == false {
+		stat = new(Stat)
+		this.stats[method] = stat
+	}
+	stat.Calls++
+	if err != nil {
+		stat.Errors++
+	}
+	stat.Total += latency
+	if latency > stat.Max {
+		stat.Max = latency
+	}
+	this.Mutex.Unlock()
+
+	// Emit measurement and trace event without blocking
+	if this.measurement != "" {
+		this.Metrics.Emit(this.measurement, nil, this.kind, method, latency.Seconds()*1000, err != nil)
+	}
+	if this.trace {
+		this.Publisher.Emit(NewEvent(this.kind, id, method, start, latency, err), false)
+	}
+}
+
+// outgoingTrace returns a context with a trace identifier for the
+// call, reusing an identifier from an incoming call on a server
+func (this *Interceptor) outgoingTrace(ctx context.Context) (context.Context, string) {
+	if this.trace == false {
+		return ctx, ""
+	}
+	id := incomingTrace(ctx)
+	if id == "" {
+		id = newTraceId()
+	}
+	return metadata.AppendToOutgoingContext(ctx, traceKey, id), id
+}
+
+// incomingTrace returns a trace identifier from the caller, or an
+// empty string
+func incomingTrace(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(traceKey); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+func newTraceId() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	} else {
+		return hex.EncodeToString(buf)
+	}
+}
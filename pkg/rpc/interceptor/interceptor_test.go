@@ -0,0 +1,272 @@
+package interceptor_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	interceptor "github.com/djthorpe/gopi/v3/pkg/rpc/interceptor"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	health "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	status "google.golang.org/grpc/status"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// metrics records emitted measurements
+type metrics struct {
+	gopi.Metrics
+	sync.Mutex
+	emitted [][]interface{}
+}
+
+type measurement struct {
+	gopi.Measurement
+	name string
+}
+
+// publisher records emitted events
+type publisher struct {
+	gopi.Publisher
+	sync.Mutex
+	events []*interceptor.Event
+}
+
+const (
+	checkMethod = "/grpc.health.v1.Health/Check"
+	watchMethod = "/grpc.health.v1.Health/Watch"
+	delay       = 50 * time.Millisecond
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// METRICS
+
+func (this *metrics) NewMeasurement(name, _ string, _ ...gopi.Field) (gopi.Measurement, error) {
+	return &measurement{name: name}, nil
+}
+
+func (this *metrics) HostTag() gopi.Field {
+	return nil
+}
+
+func (this *metrics) Emit(name string, _ []gopi.Field, values ...interface{}) error {
+	this.Lock()
+	defer this.Unlock()
+	this.emitted = append(this.emitted, append([]interface{}{name}, values...))
+	return nil
+}
+
+func (this *metrics) Emitted() [][]interface{} {
+	this.Lock()
+	defer this.Unlock()
+	return append([][]interface{}{}, this.emitted...)
+}
+
+func (this *measurement) Name() string {
+	return this.name
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLISHER
+
+func (this *publisher) Emit(evt gopi.Event, _ bool) error {
+	this.Lock()
+	defer this.Unlock()
+	this.events = append(this.events, evt.(*interceptor.Event))
+	return nil
+}
+
+func (this *publisher) Events(kind, method string) []*interceptor.Event {
+	this.Lock()
+	defer this.Unlock()
+	var result []*interceptor.Event
+	for _, evt := range this.events {
+		if evt.Kind == kind && evt.Method == method {
+			result = append(result, evt)
+		}
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SERVER
+
+// serve starts an in-process health server, where unary calls are
+// delayed, and returns a client connection and a function to stop
+func serve(t *testing.T, server, client *interceptor.Interceptor) (*grpc.ClientConn, func()) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		time.Sleep(delay)
+		return handler(ctx, req)
+	}
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(server.UnaryServer(), slow),
+		grpc.StreamInterceptor(server.StreamServer()),
+	)
+	hs := health.NewServer()
+	hs.SetServingStatus("ok", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+	go srv.Serve(lis)
+
+	conn, err := grpc.Dial(lis.Addr().String(),
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(client.UnaryClient()),
+		grpc.WithStreamInterceptor(client.StreamClient()),
+	)
+	if err != nil {
+		srv.Stop()
+		t.Fatal(err)
+	}
+	return conn, func() {
+		conn.Close()
+		srv.Stop()
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Interceptor_001(t *testing.T) {
+	m, pub := new(metrics), new(publisher)
+	server, err := interceptor.New("server", m, pub, "rpc", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := interceptor.New("client", m, pub, "rpc", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, stop := serve(t, server, client)
+	defer stop()
+
+	// One call succeeds and one returns an error
+	hc := healthpb.NewHealthClient(conn)
+	if _, err := hc.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "ok"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hc.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "missing"}); status.Code(err) != codes.NotFound {
+		t.Fatal("Expected NotFound, got", err)
+	}
+
+	// Calls, errors and latency are recorded by server and client
+	for _, i := range []*interceptor.Interceptor{server, client} {
+		stat, exists := i.Stats()[checkMethod]
+		if exists == false {
+			t.Error("Missing stats for", checkMethod, i)
+		} else if stat.Calls != 2 || stat.Errors != 1 || stat.ErrorRate() != 0.5 {
+			t.Error("Unexpected stats", i)
+		} else if stat.Mean() < delay || stat.Max < delay || stat.Total < 2*delay {
+			t.Error("Unexpected latency", i)
+		} else {
+			t.Log(i)
+		}
+	}
+
+	// A measurement is emitted for each call by server and client, with
+	// latency in milliseconds and whether an error was returned
+	errors := make(map[string]int)
+	for _, values := range m.Emitted() {
+		if len(values) != 5 || values[0] != "rpc" || values[2] != checkMethod {
+			t.Error("Unexpected measurement", values)
+		} else if latency := values[3].(float64); latency < float64(delay/time.Millisecond) {
+			t.Error("Unexpected latency", values)
+		} else if values[4].(bool) {
+			errors[values[1].(string)]++
+		}
+	}
+	if len(m.Emitted()) != 4 || errors["server"] != 1 || errors["client"] != 1 {
+		t.Error("Unexpected measurements", m.Emitted())
+	}
+}
+
+func Test_Interceptor_002(t *testing.T) {
+	pub := new(publisher)
+	server, _ := interceptor.New("server", nil, pub, "", true)
+	client, _ := interceptor.New("client", nil, pub, "", true)
+	conn, stop := serve(t, server, client)
+	defer stop()
+
+	hc := healthpb.NewHealthClient(conn)
+	hc.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "ok"})
+	hc.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "missing"})
+
+	// Trace events are emitted for each call, and the server uses the
+	// trace identifier sent by the client
+	clients, servers := pub.Events("client", checkMethod), pub.Events("server", checkMethod)
+	if len(clients) != 2 || len(servers) != 2 {
+		t.Fatal("Unexpected events", clients, servers)
+	}
+	for i := range clients {
+		if clients[i].TraceId == "" || clients[i].TraceId != servers[i].TraceId {
+			t.Error("Unexpected trace identifiers", clients[i], servers[i])
+		} else if servers[i].Duration < delay || clients[i].Duration < servers[i].Duration {
+			t.Error("Unexpected durations", clients[i], servers[i])
+		} else if servers[i].Start.IsZero() {
+			t.Error("Unexpected start", servers[i])
+		}
+	}
+	if clients[0].TraceId == clients[1].TraceId {
+		t.Error("Expected a trace identifier for each call")
+	}
+	if clients[0].Err != nil || servers[0].Err != nil {
+		t.Error("Unexpected errors", clients[0], servers[0])
+	} else if status.Code(clients[1].Err) != codes.NotFound || status.Code(servers[1].Err) != codes.NotFound {
+		t.Error("Expected NotFound errors", clients[1], servers[1])
+	}
+}
+
+func Test_Interceptor_003(t *testing.T) {
+	pub := new(publisher)
+	server, _ := interceptor.New("server", nil, pub, "", true)
+	client, _ := interceptor.New("client", nil, nil, "", false)
+	conn, stop := serve(t, server, client)
+	defer stop()
+
+	// Watch a service until the first status is received
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := healthpb.NewHealthClient(conn).Watch(ctx, &healthpb.HealthCheckRequest{Service: "ok"})
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(delay)
+	cancel()
+
+	// The client records establishing the stream, and the server
+	// records the stream when it ends
+	if stat := client.Stats()[watchMethod]; stat.Calls != 1 || stat.Errors != 0 {
+		t.Error("Unexpected client stats", client)
+	}
+	timeout := time.After(time.Second)
+	for len(pub.Events("server", watchMethod)) == 0 {
+		select {
+		case <-timeout:
+			t.Fatal("Timeout waiting for stream to end")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if stat := server.Stats()[watchMethod]; stat.Calls != 1 || stat.Max < delay {
+		t.Error("Unexpected server stats", server)
+	}
+
+	// No trace events are emitted by a client without tracing, so
+	// the server has no trace identifier
+	if evts := pub.Events("client", watchMethod); len(evts) != 0 {
+		t.Error("Unexpected client events", evts)
+	} else if evt := pub.Events("server", watchMethod)[0]; evt.TraceId != "" {
+		t.Error("Unexpected trace identifier", evt)
+	}
+}
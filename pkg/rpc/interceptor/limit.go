@@ -0,0 +1,75 @@
+package interceptor
+
+import (
+	"context"
+
+	limiter "github.com/djthorpe/gopi/v3/pkg/limiter"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	peer "google.golang.org/grpc/peer"
+	status "google.golang.org/grpc/status"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Limit rejects calls from a client which exceed the rate limit or
+// the number of concurrent calls
+type Limit struct {
+	*limiter.Limiter
+}
+
+/////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewLimit(l *limiter.Limiter) *Limit {
+	return &Limit{l}
+}
+
+/////////////////////////////////////////////////////////////////////
+// SERVER INTERCEPTORS
+
+func (this *Limit) UnaryServer() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		addr := peerAddr(ctx)
+		if err := this.acquire(addr); err != nil {
+			return nil, err
+		}
+		defer this.Limiter.Release(addr)
+		return handler(ctx, req)
+	}
+}
+
+// StreamServer counts a stream as a concurrent call until it ends
+func (this *Limit) StreamServer() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		addr := peerAddr(ss.Context())
+		if err := this.acquire(addr); err != nil {
+			return err
+		}
+		defer this.Limiter.Release(addr)
+		return handler(srv, ss)
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *Limit) acquire(addr string) error {
+	if this.Limiter.Allow(addr) == false {
+		return status.Error(codes.ResourceExhausted, "Rate limit exceeded")
+	} else if this.Limiter.Acquire(addr) == false {
+		return status.Error(codes.ResourceExhausted, "Too many concurrent calls")
+	} else {
+		return nil
+	}
+}
+
+// peerAddr returns the address of the client, or an empty string
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	} else {
+		return ""
+	}
+}
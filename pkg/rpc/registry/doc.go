@@ -0,0 +1,14 @@
+/*
+	Package registry implements a gRPC service which exposes
+	gopi.DeviceRegistry, so that devices discovered by one host, such as
+	cast devices, gopi servers and DLNA renderers, can be listed and
+	watched from another. List returns devices of a type, and Watch
+	streams a device event when a device is added, changed or removed.
+
+	The service is used by tool.Server when this package and the
+	registry unit are imported, and the stub is returned by
+	Conn.NewStub, for example:
+
+	  devices, err := stub.(gopi.DeviceRegistryStub).Devices(ctx, "dlna")
+*/
+package registry
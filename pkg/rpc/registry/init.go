@@ -0,0 +1,14 @@
+package registry
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.DeviceRegistryService and gopi.DeviceRegistryStub
+	graph.RegisterUnit(reflect.TypeOf(&service{}), reflect.TypeOf((*gopi.DeviceRegistryService)(nil)))
+	graph.RegisterServiceStub(Registry_ServiceDesc.ServiceName, reflect.TypeOf(&stub{}))
+}
@@ -0,0 +1,158 @@
+package registry
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	ptypes "github.com/golang/protobuf/ptypes"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+type device struct {
+	pb *Device
+}
+
+type event struct {
+	pb *DeviceEvent
+}
+
+/////////////////////////////////////////////////////////////////////
+// TO PROTO
+
+func toProtoDevice(d gopi.Device) *Device {
+	if d == nil {
+		return nil
+	}
+	result := &Device{
+		Key:     d.Key(),
+		Type:    d.Type(),
+		Name:    d.Name(),
+		Service: d.Service(),
+		Host:    d.Host(),
+		Port:    uint32(d.Port()),
+		Txt:     d.Txt(),
+		Seen:    toProtoTimestamp(d.Seen()),
+	}
+	for _, ip := range d.Addrs() {
+		result.Addrs = append(result.Addrs, ip.String())
+	}
+	return result
+}
+
+func toProtoEvent(evt gopi.DeviceEvent) *DeviceEvent {
+	return &DeviceEvent{
+		Type:   toProtoEventType(evt.Type()),
+		Device: toProtoDevice(evt.Device()),
+	}
+}
+
+func toProtoEventType(t gopi.DeviceEventType) DeviceEvent_Type {
+	switch t {
+	case gopi.DEVICE_EVENT_ADDED:
+		return DeviceEvent_ADDED
+	case gopi.DEVICE_EVENT_CHANGED:
+		return DeviceEvent_CHANGED
+	case gopi.DEVICE_EVENT_REMOVED:
+		return DeviceEvent_REMOVED
+	default:
+		return DeviceEvent_NONE
+	}
+}
+
+func toProtoTimestamp(ts time.Time) *timestamp.Timestamp {
+	if ts.IsZero() {
+		return nil
+	} else if proto, err := ptypes.TimestampProto(ts); err == nil {
+		return proto
+	} else {
+		return nil
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// FROM PROTO
+
+func fromProtoDevice(pb *Device) gopi.Device {
+	if pb == nil || pb.Key == "" {
+		return nil
+	} else {
+		return &device{pb}
+	}
+}
+
+// fromProtoEvent returns an event, or nil for a null event
+func fromProtoEvent(pb *DeviceEvent) gopi.DeviceEvent {
+	if pb == nil || pb.Type == DeviceEvent_NONE || fromProtoDevice(pb.Device) == nil {
+		return nil
+	} else {
+		return &event{pb}
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// DEVICE
+
+func (this *device) Instance() string { return this.pb.GetName() + "." + this.pb.GetService() }
+func (this *device) Service() string  { return this.pb.GetService() }
+func (this *device) Name() string     { return this.pb.GetName() }
+func (this *device) Zone() string     { return "" }
+func (this *device) Host() string     { return this.pb.GetHost() }
+func (this *device) Port() uint16     { return uint16(this.pb.GetPort()) }
+func (this *device) Txt() []string    { return this.pb.GetTxt() }
+func (this *device) Key() string      { return this.pb.GetKey() }
+func (this *device) Type() string     { return this.pb.GetType() }
+
+func (this *device) Addrs() []net.IP {
+	result := make([]net.IP, 0, len(this.pb.GetAddrs()))
+	for _, addr := range this.pb.GetAddrs() {
+		if ip := net.ParseIP(addr); ip != nil {
+			result = append(result, ip)
+		}
+	}
+	return result
+}
+
+func (this *device) Seen() time.Time {
+	if ts, err := ptypes.Timestamp(this.pb.GetSeen()); err != nil {
+		return time.Time{}
+	} else {
+		return ts
+	}
+}
+
+func (this *device) String() string {
+	return fmt.Sprintf("<rpc.registry.device key=%q service=%q>", this.Key(), this.Service())
+}
+
+/////////////////////////////////////////////////////////////////////
+// EVENT
+
+func (this *event) Name() string {
+	return this.pb.GetDevice().GetKey()
+}
+
+func (this *event) Type() gopi.DeviceEventType {
+	switch this.pb.GetType() {
+	case DeviceEvent_ADDED:
+		return gopi.DEVICE_EVENT_ADDED
+	case DeviceEvent_CHANGED:
+		return gopi.DEVICE_EVENT_CHANGED
+	case DeviceEvent_REMOVED:
+		return gopi.DEVICE_EVENT_REMOVED
+	default:
+		return gopi.DEVICE_EVENT_NONE
+	}
+}
+
+func (this *event) Device() gopi.Device {
+	return fromProtoDevice(this.pb.GetDevice())
+}
+
+func (this *event) String() string {
+	return fmt.Sprint("<rpc.registry.event type=", this.Type(), " ", this.Device(), ">")
+}
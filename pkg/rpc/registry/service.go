@@ -0,0 +1,92 @@
+package registry
+
+import (
+	"context"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+type service struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Server
+	gopi.DeviceRegistry
+	gopi.Publisher
+}
+
+/////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *service) New(cfg gopi.Config) error {
+	if this.Server == nil {
+		return gopi.ErrInternalAppError.WithPrefix("RegisterService: ", "(Server == nil)")
+	} else if this.DeviceRegistry == nil {
+		return gopi.ErrInternalAppError.WithPrefix("RegisterService: ", "(DeviceRegistry == nil)")
+	} else if this.Publisher == nil {
+		return gopi.ErrInternalAppError.WithPrefix("RegisterService: ", "(Publisher == nil)")
+	} else {
+		return this.Server.RegisterService(RegisterRegistryServer, this)
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *service) mustEmbedUnimplementedRegistryServer() {}
+
+/////////////////////////////////////////////////////////////////////
+// RPC METHODS
+
+func (this *service) List(_ context.Context, req *Type) (*ListResponse, error) {
+	this.Logger.Debug("<List ", req, ">")
+
+	reply := &ListResponse{}
+	for _, device := range this.DeviceRegistry.Devices(req.GetType()) {
+		reply.Device = append(reply.Device, toProtoDevice(device))
+	}
+	return reply, nil
+}
+
+// Watch streams device events until the stream is closed or shutdown
+// is requested
+func (this *service) Watch(req *Type, stream Registry_WatchServer) error {
+	this.Logger.Debug("<Watch ", req, ">")
+
+	// Send a null event once a second
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	// Subscribe to device events
+	ch := this.Publisher.Subscribe()
+	defer this.Publisher.Unsubscribe(ch)
+
+	// Obtain server cancel context
+	ctx := this.Server.NewStreamContext()
+
+	// Loop which streams until server context cancels or an error
+	// occurs sending an event
+	for {
+		select {
+		case evt := <-ch:
+			if evt_, ok := evt.(gopi.DeviceEvent); ok && evt_.Device() != nil {
+				if typ := req.GetType(); typ != "" && evt_.Device().Type() != typ {
+					continue
+				}
+				if err := stream.Send(toProtoEvent(evt_)); err != nil {
+					this.Logger.Debug("Error sending device event, ending stream")
+					return err
+				}
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+			if err := stream.Send(&DeviceEvent{}); err != nil {
+				this.Logger.Debug("Error sending null event, ending stream")
+				return err
+			}
+		}
+	}
+}
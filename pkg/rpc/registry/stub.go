@@ -0,0 +1,84 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	grpc "google.golang.org/grpc"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+type stub struct {
+	gopi.Conn
+	RegistryClient
+}
+
+/////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *stub) New(conn gopi.Conn) {
+	this.Conn = conn
+	this.RegistryClient = NewRegistryClient(conn.(grpc.ClientConnInterface))
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *stub) Devices(ctx context.Context, typ string) ([]gopi.Device, error) {
+	this.Conn.Lock()
+	defer this.Conn.Unlock()
+
+	reply, err := this.RegistryClient.List(ctx, &Type{Type: typ})
+	if err != nil {
+		return nil, this.Err(err)
+	}
+
+	// Translate protobuf to gopi.Device interface
+	result := make([]gopi.Device, 0, len(reply.Device))
+	for _, pb := range reply.Device {
+		if device := fromProtoDevice(pb); device != nil {
+			result = append(result, device)
+		}
+	}
+
+	// Return success
+	return result, nil
+}
+
+func (this *stub) Watch(ctx context.Context, typ string, ch chan<- gopi.DeviceEvent) error {
+	this.Conn.Lock()
+	defer this.Conn.Unlock()
+
+	stream, err := this.RegistryClient.Watch(ctx, &Type{Type: typ})
+	if err != nil {
+		return this.Err(err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			if msg, err := stream.Recv(); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return this.Err(err)
+			} else if evt := fromProtoEvent(msg); evt != nil {
+				ch <- evt
+			}
+		}
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *stub) String() string {
+	str := "<rpc.stub.registry"
+	str += " addr=" + strconv.Quote(this.Addr())
+	return str + ">"
+}
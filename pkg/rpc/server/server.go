@@ -2,13 +2,18 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"reflect"
 	"sync"
 
 	gopi "github.com/djthorpe/gopi/v3"
+	limiter "github.com/djthorpe/gopi/v3/pkg/limiter"
+	interceptor "github.com/djthorpe/gopi/v3/pkg/rpc/interceptor"
 	multierror "github.com/hashicorp/go-multierror"
 	grpc "google.golang.org/grpc"
 	credentials "google.golang.org/grpc/credentials"
@@ -22,11 +27,16 @@ type server struct {
 	gopi.Unit
 	sync.Mutex
 	gopi.Logger
-
-	srv      *grpc.Server
-	listener net.Listener
-	ssl      bool
-	cancels  []context.CancelFunc
+	gopi.Metrics
+	gopi.Publisher
+	gopi.AccessControl
+	gopi.AuditLog
+
+	srv         *grpc.Server
+	listener    net.Listener
+	ssl         bool
+	cancels     []context.CancelFunc
+	interceptor *interceptor.Interceptor
 }
 
 /////////////////////////////////////////////////////////////////////
@@ -35,7 +45,14 @@ type server struct {
 func (this *server) Define(cfg gopi.Config) error {
 	cfg.FlagString("ssl.cert", "", "SSL certificate file")
 	cfg.FlagString("ssl.key", "", "SSL key file")
+	cfg.FlagString("ssl.ca", "", "SSL certificate authority file for verifying client certificates")
 	cfg.FlagDuration("timeout", 0, "Connection timeout")
+	cfg.FlagString("server.measurement", "rpc.server", "Measurement name for method latency and errors")
+	cfg.FlagBool("server.trace", false, "Emit trace events for each method call")
+	cfg.FlagFloat("server.rate", 0, "Maximum calls per second for each client, or zero for unlimited")
+	cfg.FlagUint("server.burst", 20, "Maximum burst of calls for each client")
+	cfg.FlagUint("server.streams", 0, "Maximum concurrent calls and streams for each client, or zero for unlimited")
+	cfg.FlagUint("server.maxmsg", 4<<20, "Maximum received message size in bytes")
 	return nil
 }
 
@@ -45,6 +62,14 @@ func (this *server) New(cfg gopi.Config) error {
 		return err
 	} else if opts, err := appendConnectionTimeoutOption(cfg, opts); err != nil {
 		return err
+	} else if opts, err := appendLimitOption(cfg, opts); err != nil {
+		return err
+	} else if opts, err := this.appendAccessOption(opts); err != nil {
+		return err
+	} else if opts, err := this.appendAuditOption(opts); err != nil {
+		return err
+	} else if opts, err := this.appendInterceptorOption(cfg, opts); err != nil {
+		return err
 	} else if server := grpc.NewServer(opts...); server == nil {
 		return gopi.ErrBadParameter
 	} else {
@@ -227,8 +252,25 @@ func (this *server) String() string {
 func appendServerCredentialOption(cfg gopi.Config, opts []grpc.ServerOption) ([]grpc.ServerOption, bool, error) {
 	cert := cfg.GetString("ssl.cert")
 	key := cfg.GetString("ssl.key")
+	ca := cfg.GetString("ssl.ca")
 	ssl := false
-	if cert != "" || key != "" {
+	if ca != "" {
+		// Require client certificates signed by the certificate authority
+		if cert, err := tls.LoadX509KeyPair(cert, key); err != nil {
+			return nil, false, err
+		} else if pem, err := ioutil.ReadFile(ca); err != nil {
+			return nil, false, err
+		} else if pool := x509.NewCertPool(); pool.AppendCertsFromPEM(pem) == false {
+			return nil, false, gopi.ErrBadParameter.WithPrefix("-ssl.ca")
+		} else {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(&tls.Config{
+				Certificates: []tls.Certificate{cert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    pool,
+			})))
+			ssl = true
+		}
+	} else if cert != "" || key != "" {
 		if creds, err := credentials.NewServerTLSFromFile(cert, key); err != nil {
 			return nil, false, err
 		} else {
@@ -239,6 +281,58 @@ func appendServerCredentialOption(cfg gopi.Config, opts []grpc.ServerOption) ([]
 	return opts, ssl, nil
 }
 
+// appendInterceptorOption records latency and errors for each method,
+// and converts errors returned by services into gRPC status errors
+func (this *server) appendInterceptorOption(cfg gopi.Config, opts []grpc.ServerOption) ([]grpc.ServerOption, error) {
+	if i, err := interceptor.New("server", this.Metrics, this.Publisher, cfg.GetString("server.measurement"), cfg.GetBool("server.trace")); err != nil {
+		return nil, err
+	} else {
+		this.interceptor = i
+	}
+	opts = append(opts, grpc.ChainUnaryInterceptor(this.interceptor.UnaryServer()))
+	opts = append(opts, grpc.ChainStreamInterceptor(this.interceptor.StreamServer()))
+	errors := interceptor.NewErrors()
+	opts = append(opts, grpc.ChainUnaryInterceptor(errors.UnaryServer()))
+	opts = append(opts, grpc.ChainStreamInterceptor(errors.StreamServer()))
+	return opts, nil
+}
+
+// appendLimitOption limits the message size, and the rate and number of
+// concurrent calls for each client
+func appendLimitOption(cfg gopi.Config, opts []grpc.ServerOption) ([]grpc.ServerOption, error) {
+	if maxmsg := cfg.GetUint("server.maxmsg"); maxmsg > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(int(maxmsg)))
+	}
+	limit := interceptor.NewLimit(limiter.New(cfg.GetFloat("server.rate"), cfg.GetUint("server.burst"), cfg.GetUint("server.streams")))
+	opts = append(opts, grpc.ChainUnaryInterceptor(limit.UnaryServer()))
+	opts = append(opts, grpc.ChainStreamInterceptor(limit.StreamServer()))
+	return opts, nil
+}
+
+// appendAccessOption rejects calls from clients which do not have the
+// role required for a method, when access control is enabled
+func (this *server) appendAccessOption(opts []grpc.ServerOption) ([]grpc.ServerOption, error) {
+	if this.AccessControl == nil {
+		return opts, nil
+	}
+	access := interceptor.NewAccess(this.AccessControl)
+	opts = append(opts, grpc.ChainUnaryInterceptor(access.UnaryServer()))
+	opts = append(opts, grpc.ChainStreamInterceptor(access.StreamServer()))
+	return opts, nil
+}
+
+// appendAuditOption records calls to methods which change state, when
+// the audit log is enabled
+func (this *server) appendAuditOption(opts []grpc.ServerOption) ([]grpc.ServerOption, error) {
+	if this.AuditLog == nil {
+		return opts, nil
+	}
+	audit := interceptor.NewAudit(this.AuditLog)
+	opts = append(opts, grpc.ChainUnaryInterceptor(audit.UnaryServer()))
+	opts = append(opts, grpc.ChainStreamInterceptor(audit.StreamServer()))
+	return opts, nil
+}
+
 func appendConnectionTimeoutOption(cfg gopi.Config, opts []grpc.ServerOption) ([]grpc.ServerOption, error) {
 	if timeout := cfg.GetDuration("timeout"); timeout > 0 {
 		opts = append(opts, grpc.ConnectionTimeout(timeout))
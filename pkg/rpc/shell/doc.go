@@ -0,0 +1,11 @@
+/*
+	Package shell implements a gRPC service for remote shell sessions,
+	which list the units loaded by an application, call their methods
+	and show events. The service is used by tool.Server when this package
+	is imported, and sessions require a client certificate verified
+	against the -ssl.ca flag unless -shell.insecure is set.
+
+	The stub is used by tool.Remote, for example in the gopi-remote
+	command, which connects with the -client.cert and -client.key flags.
+*/
+package shell
@@ -0,0 +1,14 @@
+package shell
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.ShellService and gopi.ShellStub
+	graph.RegisterUnit(reflect.TypeOf(&service{}), reflect.TypeOf((*gopi.ShellService)(nil)))
+	graph.RegisterServiceStub(Shell_ServiceDesc.ServiceName, reflect.TypeOf(&stub{}))
+}
@@ -0,0 +1,156 @@
+package shell
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	codes "google.golang.org/grpc/codes"
+	credentials "google.golang.org/grpc/credentials"
+	peer "google.golang.org/grpc/peer"
+	status "google.golang.org/grpc/status"
+)
+
+type service struct {
+	gopi.Logger
+	gopi.Unit
+	gopi.Server
+	sync.Mutex
+
+	insecure *bool
+	shell    gopi.Shell
+}
+
+// writer sends complete lines of output on a stream
+type writer struct {
+	sync.Mutex
+	stream Shell_SessionServer
+	buf    []byte
+}
+
+/////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *service) Define(cfg gopi.Config) error {
+	this.insecure = cfg.FlagBool("shell.insecure", false, "Allow remote shell sessions without a client certificate")
+	return nil
+}
+
+func (this *service) New(cfg gopi.Config) error {
+	if this.Server == nil {
+		return gopi.ErrInternalAppError.WithPrefix("RegisterService: ", "(Server == nil)")
+	} else {
+		return this.Server.RegisterService(RegisterShellServer, this)
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *service) Serve(shell gopi.Shell) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if shell == nil {
+		return gopi.ErrBadParameter.WithPrefix("Serve")
+	} else if this.shell != nil {
+		return gopi.ErrDuplicateEntry.WithPrefix("Serve")
+	}
+	this.shell = shell
+	return nil
+}
+
+func (this *service) mustEmbedUnimplementedShellServer() {}
+
+/////////////////////////////////////////////////////////////////////
+// RPC METHODS
+
+// Session runs a shell session until the session is quit, the stream
+// is closed or shutdown is requested
+func (this *service) Session(stream Shell_SessionServer) error {
+	shell, err := this.authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	this.Logger.Debug("<Session>")
+
+	// End the session when the stream or server is cancelled
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-this.Server.NewStreamContext().Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// Receive lines in the background
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		for {
+			if msg, err := stream.Recv(); err != nil {
+				pw.CloseWithError(err)
+				return
+			} else if _, err := io.WriteString(pw, msg.Text+"\n"); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Run the session
+	return shell.Run(ctx, pr, &writer{stream: stream})
+}
+
+// Complete returns completion candidates for a partial line
+func (this *service) Complete(ctx context.Context, line *Line) (*CompleteResponse, error) {
+	shell, err := this.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &CompleteResponse{Candidates: shell.Complete(line.Text)}, nil
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// authenticate returns the shell when the peer has presented a
+// verified client certificate, or sessions are insecure
+func (this *service) authenticate(ctx context.Context) (gopi.Shell, error) {
+	this.Mutex.Lock()
+	shell := this.shell
+	this.Mutex.Unlock()
+	if shell == nil {
+		return nil, status.Error(codes.Unavailable, "No shell")
+	} else if *this.insecure {
+		return shell, nil
+	}
+
+	if p, ok := peer.FromContext(ctx); ok == false {
+		return nil, status.Error(codes.Unauthenticated, "No peer")
+	} else if info, ok := p.AuthInfo.(credentials.TLSInfo); ok == false || len(info.State.VerifiedChains) == 0 {
+		this.Print("Shell: Rejected session without client certificate from ", p.Addr)
+		return nil, status.Error(codes.Unauthenticated, "Client certificate required")
+	} else {
+		this.Debug("Shell: Session for ", info.State.VerifiedChains[0][0].Subject.CommonName, " from ", p.Addr)
+		return shell, nil
+	}
+}
+
+// Write sends complete lines, retaining any partial line
+func (this *writer) Write(data []byte) (int, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	this.buf = append(this.buf, data...)
+	if i := strings.LastIndexByte(string(this.buf), '\n'); i >= 0 {
+		if err := this.stream.Send(&Line{Text: string(this.buf[:i])}); err != nil {
+			return 0, err
+		}
+		this.buf = this.buf[i+1:]
+	}
+	return len(data), nil
+}
@@ -0,0 +1,89 @@
+package shell
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	grpc "google.golang.org/grpc"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+type stub struct {
+	gopi.Conn
+	ShellClient
+}
+
+/////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *stub) New(conn gopi.Conn) {
+	this.Conn = conn
+	this.ShellClient = NewShellClient(conn.(grpc.ClientConnInterface))
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Run a remote session. The connection is not locked so that
+// completion can be called during a session
+func (this *stub) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	stream, err := this.ShellClient.Session(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Send lines in the background until end of input
+	go func() {
+		defer stream.CloseSend()
+		r := bufio.NewReader(in)
+		for {
+			line, err := r.ReadString('\n')
+			if line != "" {
+				if err := stream.Send(&Line{Text: strings.TrimRight(line, "\r\n")}); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// Write output until the session ends
+	for {
+		if msg, err := stream.Recv(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return this.Err(err)
+		} else {
+			fmt.Fprintln(out, msg.Text)
+		}
+	}
+}
+
+func (this *stub) Complete(ctx context.Context, line string) ([]string, error) {
+	this.Conn.Lock()
+	defer this.Conn.Unlock()
+
+	if resp, err := this.ShellClient.Complete(ctx, &Line{Text: line}); err != nil {
+		return nil, this.Err(err)
+	} else {
+		return resp.Candidates, nil
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *stub) String() string {
+	str := "<rpc.stub.shell"
+	str += " addr=" + strconv.Quote(this.Addr())
+	return str + ">"
+}
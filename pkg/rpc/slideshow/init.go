@@ -0,0 +1,14 @@
+package slideshow
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.SlideshowService and gopi.SlideshowStub
+	graph.RegisterUnit(reflect.TypeOf(&service{}), reflect.TypeOf((*gopi.SlideshowService)(nil)))
+	graph.RegisterServiceStub(Slideshow_ServiceDesc.ServiceName, reflect.TypeOf(&stub{}))
+}
@@ -0,0 +1,18 @@
+package slideshow
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS - STATE
+
+func toProtoState(slideshow gopi.Slideshow) *State {
+	path, index, count := slideshow.Current()
+	return &State{
+		Path:   path,
+		Index:  uint32(index),
+		Count:  uint32(count),
+		Paused: slideshow.Paused(),
+	}
+}
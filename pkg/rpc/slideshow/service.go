@@ -0,0 +1,65 @@
+package slideshow
+
+import (
+	"context"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	empty "github.com/golang/protobuf/ptypes/empty"
+)
+
+type service struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Server
+	gopi.Slideshow
+}
+
+/////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *service) New(cfg gopi.Config) error {
+	this.Require(this.Logger, this.Server, this.Slideshow)
+	return this.Server.RegisterService(RegisterSlideshowServer, this)
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *service) CancelStreams() {}
+
+func (this *service) mustEmbedUnimplementedSlideshowServer() {}
+
+/////////////////////////////////////////////////////////////////////
+// RPC METHODS
+
+func (this *service) Next(context.Context, *empty.Empty) (*empty.Empty, error) {
+	this.Logger.Debug("<Next>")
+
+	if err := this.Slideshow.Next(); err != nil {
+		return nil, err
+	} else {
+		return &empty.Empty{}, nil
+	}
+}
+
+func (this *service) Previous(context.Context, *empty.Empty) (*empty.Empty, error) {
+	this.Logger.Debug("<Previous>")
+
+	if err := this.Slideshow.Previous(); err != nil {
+		return nil, err
+	} else {
+		return &empty.Empty{}, nil
+	}
+}
+
+func (this *service) Pause(_ context.Context, req *Bool) (*empty.Empty, error) {
+	this.Logger.Debug("<Pause ", req, ">")
+
+	this.Slideshow.Pause(req.Value)
+	return &empty.Empty{}, nil
+}
+
+func (this *service) Current(context.Context, *empty.Empty) (*State, error) {
+	this.Logger.Debug("<Current>")
+	return toProtoState(this.Slideshow), nil
+}
@@ -0,0 +1,83 @@
+package slideshow
+
+import (
+	"context"
+	"strconv"
+
+	// Modules
+	gopi "github.com/djthorpe/gopi/v3"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	grpc "google.golang.org/grpc"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+type stub struct {
+	gopi.Conn
+	SlideshowClient
+}
+
+/////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *stub) New(conn gopi.Conn) {
+	this.Conn = conn
+	this.SlideshowClient = NewSlideshowClient(conn.(grpc.ClientConnInterface))
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *stub) Next(ctx context.Context) error {
+	this.Conn.Lock()
+	defer this.Conn.Unlock()
+
+	if _, err := this.SlideshowClient.Next(ctx, &empty.Empty{}); err != nil {
+		return err
+	} else {
+		return nil
+	}
+}
+
+func (this *stub) Previous(ctx context.Context) error {
+	this.Conn.Lock()
+	defer this.Conn.Unlock()
+
+	if _, err := this.SlideshowClient.Previous(ctx, &empty.Empty{}); err != nil {
+		return err
+	} else {
+		return nil
+	}
+}
+
+func (this *stub) Pause(ctx context.Context, paused bool) error {
+	this.Conn.Lock()
+	defer this.Conn.Unlock()
+
+	if _, err := this.SlideshowClient.Pause(ctx, &Bool{Value: paused}); err != nil {
+		return err
+	} else {
+		return nil
+	}
+}
+
+func (this *stub) Current(ctx context.Context) (string, uint, uint, bool, error) {
+	this.Conn.Lock()
+	defer this.Conn.Unlock()
+
+	if state, err := this.SlideshowClient.Current(ctx, &empty.Empty{}); err != nil {
+		return "", 0, 0, false, err
+	} else {
+		return state.Path, uint(state.Index), uint(state.Count), state.Paused, nil
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *stub) String() string {
+	str := "<rpc.slideshowstub"
+	str += " addr=" + strconv.Quote(this.Addr())
+	return str + ">"
+}
@@ -0,0 +1,22 @@
+/*
+	Package waveform implements a gRPC service which controls
+	gopi.GPIOWaveform, so that a central controller can drive actuators
+	on other Raspberry Pi devices. A client opens a session which sets
+	PWM on a pin, uploads waves from pulses and starts and stops playing,
+	and each command is acknowledged on the session stream in order.
+
+	PWM is played as a repeating wave of two pulses, so only one pin
+	has PWM or plays a wave at any time. Waves uploaded in a session
+	are deleted and any playing wave is stopped when the session ends.
+
+	The service is used by tool.Server when this package and the
+	waveform unit are imported, and the stub is returned by
+	Conn.NewStub, for example:
+
+	  session, err := stub.(gopi.GPIOWaveformStub).Session(ctx)
+	  if err == nil {
+	    defer session.Close()
+	    err = session.SetPWM(18, 50, 0.075)
+	  }
+*/
+package waveform
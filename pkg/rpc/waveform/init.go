@@ -0,0 +1,14 @@
+package waveform
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.GPIOWaveformService and gopi.GPIOWaveformStub
+	graph.RegisterUnit(reflect.TypeOf(&service{}), reflect.TypeOf((*gopi.GPIOWaveformService)(nil)))
+	graph.RegisterServiceStub(Waveform_ServiceDesc.ServiceName, reflect.TypeOf(&stub{}))
+}
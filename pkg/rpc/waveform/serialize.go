@@ -0,0 +1,70 @@
+package waveform
+
+import (
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+/////////////////////////////////////////////////////////////////////
+// PULSES
+
+func toProtoPulses(pulses []gopi.GPIOPulse) []*Pulse {
+	result := make([]*Pulse, 0, len(pulses))
+	for _, pulse := range pulses {
+		result = append(result, &Pulse{
+			High:  toProtoPins(pulse.High),
+			Low:   toProtoPins(pulse.Low),
+			Delay: uint32(pulse.Delay / time.Microsecond),
+		})
+	}
+	return result
+}
+
+func fromProtoPulses(pulses []*Pulse) []gopi.GPIOPulse {
+	result := make([]gopi.GPIOPulse, 0, len(pulses))
+	for _, pulse := range pulses {
+		if pulse == nil {
+			continue
+		}
+		result = append(result, gopi.GPIOPulse{
+			High:  fromProtoPins(pulse.High),
+			Low:   fromProtoPins(pulse.Low),
+			Delay: time.Duration(pulse.Delay) * time.Microsecond,
+		})
+	}
+	return result
+}
+
+/////////////////////////////////////////////////////////////////////
+// PINS
+
+func toProtoPins(pins []gopi.GPIOPin) []uint32 {
+	result := make([]uint32, 0, len(pins))
+	for _, pin := range pins {
+		result = append(result, uint32(pin))
+	}
+	return result
+}
+
+func fromProtoPins(pins []uint32) []gopi.GPIOPin {
+	result := make([]gopi.GPIOPin, 0, len(pins))
+	for _, pin := range pins {
+		if pin >= uint32(gopi.GPIO_PIN_NONE) {
+			pin = uint32(gopi.GPIO_PIN_NONE)
+		}
+		result = append(result, gopi.GPIOPin(pin))
+	}
+	return result
+}
+
+/////////////////////////////////////////////////////////////////////
+// ACKNOWLEDGEMENTS
+
+func toProtoAck(cmd *Command, busy bool, err error) *Ack {
+	ack := &Ack{Seq: cmd.Seq, Busy: busy}
+	if err != nil {
+		ack.Error = err.Error()
+	}
+	return ack
+}
@@ -0,0 +1,233 @@
+package waveform
+
+import (
+	"io"
+	"math"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+type service struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Server
+	gopi.GPIOWaveform
+}
+
+// session holds the waves uploaded by a client
+type session struct {
+	gopi.GPIOWaveform
+
+	waves   map[uint32]gopi.GPIOWave
+	next    uint32
+	pwm     gopi.GPIOWave
+	playing bool
+}
+
+/////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *service) New(cfg gopi.Config) error {
+	if this.Server == nil {
+		return gopi.ErrInternalAppError.WithPrefix("RegisterService: ", "(Server == nil)")
+	} else if this.GPIOWaveform == nil {
+		return gopi.ErrInternalAppError.WithPrefix("RegisterService: ", "(GPIOWaveform == nil)")
+	} else {
+		return this.Server.RegisterService(RegisterWaveformServer, this)
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *service) CancelStreams() {}
+
+func (this *service) mustEmbedUnimplementedWaveformServer() {}
+
+/////////////////////////////////////////////////////////////////////
+// RPC METHODS
+
+// Control runs a session which acknowledges each command, until the
+// stream is closed or shutdown is requested
+func (this *service) Control(stream Waveform_ControlServer) error {
+	this.Logger.Debug("<Control>")
+
+	// Release waves when the session ends
+	session := &session{GPIOWaveform: this.GPIOWaveform, waves: make(map[uint32]gopi.GPIOWave)}
+	defer session.Close()
+
+	// Receive commands in the background
+	ch := make(chan *Command)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		for {
+			if cmd, err := stream.Recv(); err != nil {
+				errs <- err
+				return
+			} else {
+				select {
+				case ch <- cmd:
+				case <-stream.Context().Done():
+					return
+				}
+			}
+		}
+	}()
+
+	// Acknowledge commands until the stream ends or server is cancelled
+	ctx := this.Server.NewStreamContext()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case cmd, ok := <-ch:
+			if ok == false {
+				if err := <-errs; err == io.EOF {
+					return nil
+				} else {
+					return err
+				}
+			}
+			this.Logger.Debug("Control: ", cmd)
+			if err := stream.Send(session.Do(cmd)); err != nil {
+				this.Logger.Debug("Control: ", "Error sending acknowledgement, ending session")
+				return err
+			}
+		}
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// SESSION METHODS
+
+// Do runs a command and returns the acknowledgement
+func (this *session) Do(cmd *Command) *Ack {
+	var err error
+	var wave gopi.GPIOWave
+	var id uint32
+
+	switch cmd.Op {
+	case Command_NONE:
+		// Return busy state
+	case Command_PWM:
+		err = this.SetPWM(gopi.GPIOPin(cmd.Pin), cmd.Frequency, cmd.Duty)
+	case Command_UPLOAD:
+		id, wave, err = this.Upload(fromProtoPulses(cmd.Pulses))
+	case Command_DELETE:
+		if wave, exists := this.waves[cmd.Wave]; exists == false {
+			err = gopi.ErrNotFound.WithPrefix("DeleteWave: ", cmd.Wave)
+		} else {
+			delete(this.waves, cmd.Wave)
+			err = this.DeleteWave(wave)
+		}
+	case Command_PLAY:
+		if wave, exists := this.waves[cmd.Wave]; exists == false {
+			err = gopi.ErrNotFound.WithPrefix("Play: ", cmd.Wave)
+		} else if err = this.Play(wave, cmd.Repeat); err == nil {
+			this.playing = true
+		}
+	case Command_STOP:
+		if err = this.Stop(); err == nil {
+			this.playing = false
+		}
+	default:
+		err = gopi.ErrBadParameter.WithPrefix("Op: ", cmd.Op)
+	}
+
+	ack := toProtoAck(cmd, this.Busy(), err)
+	if wave != nil {
+		ack.Wave = id
+		ack.Pins = toProtoPins(wave.Pins())
+		ack.Duration = uint32(wave.Duration() / time.Microsecond)
+	}
+	return ack
+}
+
+// Upload creates a wave and returns an identifier for it
+func (this *session) Upload(pulses []gopi.GPIOPulse) (uint32, gopi.GPIOWave, error) {
+	if wave, err := this.NewWave(pulses); err != nil {
+		return 0, nil, err
+	} else {
+		this.next++
+		this.waves[this.next] = wave
+		return this.next, wave, nil
+	}
+}
+
+// SetPWM plays a repeating wave of two pulses on a pin, replacing
+// the previous PWM wave
+func (this *session) SetPWM(pin gopi.GPIOPin, frequency, duty float64) error {
+	pulses, err := pwmPulses(pin, frequency, duty)
+	if err != nil {
+		return err
+	}
+	wave, err := this.NewWave(pulses)
+	if err != nil {
+		return err
+	} else if err := this.Play(wave, true); err != nil {
+		this.DeleteWave(wave)
+		return err
+	}
+	this.playing = true
+
+	// Release the previous PWM wave
+	if this.pwm != nil {
+		this.DeleteWave(this.pwm)
+	}
+	this.pwm = wave
+	return nil
+}
+
+// Close stops playing and deletes waves uploaded in the session
+func (this *session) Close() error {
+	if this.playing && this.Busy() {
+		this.Stop()
+	}
+	for id, wave := range this.waves {
+		this.DeleteWave(wave)
+		delete(this.waves, id)
+	}
+	if this.pwm != nil {
+		this.DeleteWave(this.pwm)
+		this.pwm = nil
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// pwmPulses returns pulses for one period with a frequency in Hz and
+// a duty cycle between zero and one
+func pwmPulses(pin gopi.GPIOPin, frequency, duty float64) ([]gopi.GPIOPulse, error) {
+	if pin == gopi.GPIO_PIN_NONE {
+		return nil, gopi.ErrBadParameter.WithPrefix("SetPWM: pin")
+	} else if frequency <= 0 || math.IsInf(frequency, 0) || math.IsNaN(frequency) {
+		return nil, gopi.ErrBadParameter.WithPrefix("SetPWM: frequency")
+	} else if duty < 0 || duty > 1 || math.IsNaN(duty) {
+		return nil, gopi.ErrBadParameter.WithPrefix("SetPWM: duty")
+	}
+
+	period := time.Duration(float64(time.Second) / frequency)
+	high := time.Duration(float64(period) * duty).Round(time.Microsecond)
+	if period < 2*time.Microsecond {
+		return nil, gopi.ErrBadParameter.WithPrefix("SetPWM: frequency")
+	}
+
+	pins := []gopi.GPIOPin{pin}
+	switch {
+	case high <= 0:
+		return []gopi.GPIOPulse{{Low: pins, Delay: period}}, nil
+	case high >= period:
+		return []gopi.GPIOPulse{{High: pins, Delay: period}}, nil
+	default:
+		return []gopi.GPIOPulse{
+			{High: pins, Delay: high},
+			{Low: pins, Delay: period - high},
+		}, nil
+	}
+}
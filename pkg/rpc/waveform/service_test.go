@@ -0,0 +1,312 @@
+package waveform
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	grpc "google.golang.org/grpc"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// waveform records the waves which are created, deleted and played
+type waveform struct {
+	waves   map[*fakewave]bool
+	playing *fakewave
+	repeat  bool
+	deleted int
+}
+
+type fakewave struct {
+	pulses []gopi.GPIOPulse
+}
+
+// server provides a stream context
+type server struct {
+	gopi.Server
+	ctx context.Context
+}
+
+// logger discards debugging output
+type logger struct {
+	gopi.Logger
+}
+
+// stream receives commands from a channel and records acknowledgements
+type stream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	cmds chan *Command
+	acks chan *Ack
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WAVEFORM
+
+func newWaveform() *waveform {
+	return &waveform{waves: make(map[*fakewave]bool)}
+}
+
+func (this *waveform) NewWave(pulses []gopi.GPIOPulse) (gopi.GPIOWave, error) {
+	if len(pulses) == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("NewWave")
+	}
+	wave := &fakewave{pulses}
+	this.waves[wave] = true
+	return wave, nil
+}
+
+func (this *waveform) DeleteWave(w gopi.GPIOWave) error {
+	wave := w.(*fakewave)
+	if this.waves[wave] == false {
+		return gopi.ErrNotFound.WithPrefix("DeleteWave")
+	}
+	if this.playing == wave {
+		this.playing = nil
+	}
+	delete(this.waves, wave)
+	this.deleted++
+	return nil
+}
+
+func (this *waveform) Play(w gopi.GPIOWave, repeat bool) error {
+	wave := w.(*fakewave)
+	if this.waves[wave] == false {
+		return gopi.ErrNotFound.WithPrefix("Play")
+	}
+	this.playing, this.repeat = wave, repeat
+	return nil
+}
+
+func (this *waveform) Stop() error {
+	this.playing = nil
+	return nil
+}
+
+func (this *waveform) Busy() bool {
+	return this.playing != nil
+}
+
+func (this *fakewave) Pins() []gopi.GPIOPin {
+	pins := make(map[gopi.GPIOPin]bool)
+	result := []gopi.GPIOPin{}
+	for _, pulse := range this.pulses {
+		for _, pin := range append(append([]gopi.GPIOPin{}, pulse.High...), pulse.Low...) {
+			if pins[pin] == false {
+				pins[pin] = true
+				result = append(result, pin)
+			}
+		}
+	}
+	return result
+}
+
+func (this *fakewave) Duration() time.Duration {
+	var result time.Duration
+	for _, pulse := range this.pulses {
+		result += pulse.Delay
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SERVER AND STREAM
+
+func (this *server) NewStreamContext() context.Context {
+	return this.ctx
+}
+
+func (this *logger) Debug(...interface{}) {}
+
+func (this *stream) Context() context.Context {
+	return this.ctx
+}
+
+func (this *stream) Recv() (*Command, error) {
+	if cmd, ok := <-this.cmds; ok == false {
+		return nil, io.EOF
+	} else {
+		return cmd, nil
+	}
+}
+
+func (this *stream) Send(ack *Ack) error {
+	this.acks <- ack
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Session_001(t *testing.T) {
+	waveform := newWaveform()
+	session := &session{GPIOWaveform: waveform, waves: make(map[uint32]gopi.GPIOWave)}
+	pulses := []*Pulse{{High: []uint32{17}, Delay: 100}, {Low: []uint32{17, 27}, Delay: 200}}
+
+	// Upload returns the wave, pins and duration
+	if ack := session.Do(&Command{Seq: 1, Op: Command_UPLOAD, Pulses: pulses}); ack.Error != "" {
+		t.Fatal(ack.Error)
+	} else if ack.Seq != 1 || ack.Wave != 1 || ack.Duration != 300 || ack.Busy {
+		t.Error("Unexpected ack", ack)
+	} else if len(ack.Pins) != 2 || ack.Pins[0] != 17 || ack.Pins[1] != 27 {
+		t.Error("Unexpected pins", ack.Pins)
+	}
+
+	// Play, busy state and stop
+	if ack := session.Do(&Command{Seq: 2, Op: Command_PLAY, Wave: 1, Repeat: true}); ack.Error != "" || ack.Seq != 2 || ack.Busy == false {
+		t.Error("Unexpected ack", ack)
+	} else if waveform.playing == nil || waveform.repeat == false {
+		t.Error("Expected wave to be playing repeatedly")
+	}
+	if ack := session.Do(&Command{Seq: 3, Op: Command_NONE}); ack.Error != "" || ack.Busy == false {
+		t.Error("Unexpected ack", ack)
+	}
+	if ack := session.Do(&Command{Seq: 4, Op: Command_STOP}); ack.Error != "" || ack.Busy {
+		t.Error("Unexpected ack", ack)
+	}
+
+	// Delete, then commands for the deleted wave return errors
+	if ack := session.Do(&Command{Seq: 5, Op: Command_DELETE, Wave: 1}); ack.Error != "" {
+		t.Error("Unexpected ack", ack)
+	} else if len(waveform.waves) != 0 {
+		t.Error("Expected wave to be deleted")
+	}
+	for _, op := range []Command_Op{Command_DELETE, Command_PLAY} {
+		if ack := session.Do(&Command{Seq: 6, Op: op, Wave: 1}); ack.Error == "" || ack.Seq != 6 {
+			t.Error("Expected error for", op, ack)
+		}
+	}
+
+	// Errors from the waveform and unknown operations are acknowledged
+	if ack := session.Do(&Command{Seq: 7, Op: Command_UPLOAD}); ack.Error == "" || ack.Wave != 0 {
+		t.Error("Expected error for empty wave", ack)
+	}
+	if ack := session.Do(&Command{Seq: 8, Op: Command_Op(99)}); ack.Error == "" {
+		t.Error("Expected error for unknown operation", ack)
+	}
+}
+
+func Test_Session_002(t *testing.T) {
+	waveform := newWaveform()
+	session := &session{GPIOWaveform: waveform, waves: make(map[uint32]gopi.GPIOWave)}
+
+	// PWM plays two pulses repeatedly, for a 1.5ms pulse every 20ms
+	if ack := session.Do(&Command{Seq: 1, Op: Command_PWM, Pin: 18, Frequency: 50, Duty: 0.075}); ack.Error != "" || ack.Busy == false {
+		t.Fatal("Unexpected ack", ack)
+	} else if wave := waveform.playing; wave == nil || waveform.repeat == false {
+		t.Fatal("Expected wave to be playing repeatedly")
+	} else if len(wave.pulses) != 2 || wave.pulses[0].Delay != 1500*time.Microsecond || wave.pulses[1].Delay != 18500*time.Microsecond {
+		t.Error("Unexpected pulses", wave.pulses)
+	}
+
+	// Setting PWM again releases the previous wave
+	if ack := session.Do(&Command{Seq: 2, Op: Command_PWM, Pin: 18, Frequency: 50, Duty: 1}); ack.Error != "" {
+		t.Error("Unexpected ack", ack)
+	} else if len(waveform.waves) != 1 || waveform.deleted != 1 || len(waveform.playing.pulses) != 1 {
+		t.Error("Expected one wave", waveform)
+	}
+
+	// Invalid parameters are returned as errors without changing the wave
+	for _, cmd := range []*Command{
+		{Op: Command_PWM, Pin: 18, Frequency: 0, Duty: 0.5},
+		{Op: Command_PWM, Pin: 18, Frequency: 50, Duty: 1.5},
+		{Op: Command_PWM, Pin: uint32(gopi.GPIO_PIN_NONE), Frequency: 50, Duty: 0.5},
+	} {
+		if ack := session.Do(cmd); ack.Error == "" {
+			t.Error("Expected error for", cmd)
+		}
+	}
+	if len(waveform.waves) != 1 || waveform.playing == nil {
+		t.Error("Unexpected change to waves", waveform)
+	}
+
+	// Close stops playing and deletes all waves
+	session.Do(&Command{Op: Command_UPLOAD, Pulses: []*Pulse{{High: []uint32{17}, Delay: 100}}})
+	if err := session.Close(); err != nil {
+		t.Error(err)
+	} else if len(waveform.waves) != 0 || waveform.Busy() {
+		t.Error("Expected no waves after close", waveform)
+	}
+}
+
+func Test_Control_001(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	waveform := newWaveform()
+	service := &service{Logger: &logger{}, Server: &server{ctx: ctx}, GPIOWaveform: waveform}
+	stream := &stream{ctx: ctx, cmds: make(chan *Command), acks: make(chan *Ack, 10)}
+
+	errs := make(chan error)
+	go func() {
+		errs <- service.Control(stream)
+	}()
+
+	// Each command is acknowledged in order
+	cmds := []*Command{
+		{Seq: 10, Op: Command_UPLOAD, Pulses: []*Pulse{{High: []uint32{17}, Delay: 100}}},
+		{Seq: 11, Op: Command_PLAY, Wave: 1},
+		{Seq: 12, Op: Command_PLAY, Wave: 2},
+	}
+	for _, cmd := range cmds {
+		stream.cmds <- cmd
+		select {
+		case ack := <-stream.acks:
+			if ack.Seq != cmd.Seq {
+				t.Error("Unexpected ack", ack, "for", cmd)
+			} else if cmd.Wave == 2 && ack.Error == "" {
+				t.Error("Expected error for", cmd)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timeout waiting for ack", cmd.Seq)
+		}
+	}
+
+	// The session ends when the stream is closed, and waves uploaded in
+	// the session are stopped and deleted
+	close(stream.cmds)
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for session to end")
+	}
+	if len(waveform.waves) != 0 || waveform.Busy() {
+		t.Error("Expected no waves after session", waveform)
+	}
+}
+
+func Test_Control_002(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	waveform := newWaveform()
+	service := &service{Logger: &logger{}, Server: &server{ctx: ctx}, GPIOWaveform: waveform}
+	stream := &stream{ctx: context.Background(), cmds: make(chan *Command), acks: make(chan *Ack, 10)}
+
+	errs := make(chan error)
+	go func() {
+		errs <- service.Control(stream)
+	}()
+	stream.cmds <- &Command{Seq: 1, Op: Command_PWM, Pin: 18, Frequency: 50, Duty: 0.5}
+	<-stream.acks
+
+	// The session ends when the server is shut down, and the PWM wave
+	// is stopped and deleted
+	cancel()
+	select {
+	case err := <-errs:
+		if err != context.Canceled {
+			t.Error("Unexpected error", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for session to end")
+	}
+	if len(waveform.waves) != 0 || waveform.Busy() {
+		t.Error("Expected no waves after session", waveform)
+	}
+}
@@ -0,0 +1,164 @@
+package waveform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	grpc "google.golang.org/grpc"
+)
+
+/////////////////////////////////////////////////////////////////////
+// TYPES
+
+type stub struct {
+	gopi.Conn
+	WaveformClient
+}
+
+type client struct {
+	sync.Mutex
+	gopi.Conn
+
+	stream Waveform_ControlClient
+	seq    uint32
+}
+
+// wave is a wave which has been uploaded in a session
+type wave struct {
+	id       uint32
+	pins     []gopi.GPIOPin
+	duration time.Duration
+}
+
+/////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *stub) New(conn gopi.Conn) {
+	this.Conn = conn
+	this.WaveformClient = NewWaveformClient(conn.(grpc.ClientConnInterface))
+}
+
+/////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Session opens a session stream. The connection is not locked during
+// the session, so that other stubs can be used
+func (this *stub) Session(ctx context.Context) (gopi.GPIOWaveformSession, error) {
+	this.Conn.Lock()
+	defer this.Conn.Unlock()
+
+	if stream, err := this.WaveformClient.Control(ctx); err != nil {
+		return nil, this.Err(err)
+	} else {
+		return &client{Conn: this.Conn, stream: stream}, nil
+	}
+}
+
+/////////////////////////////////////////////////////////////////////
+// SESSION METHODS
+
+func (this *client) SetPWM(pin gopi.GPIOPin, frequency, duty float64) error {
+	_, err := this.do(&Command{Op: Command_PWM, Pin: uint32(pin), Frequency: frequency, Duty: duty})
+	return err
+}
+
+func (this *client) NewWave(pulses []gopi.GPIOPulse) (gopi.GPIOWave, error) {
+	if ack, err := this.do(&Command{Op: Command_UPLOAD, Pulses: toProtoPulses(pulses)}); err != nil {
+		return nil, err
+	} else {
+		return &wave{ack.Wave, fromProtoPins(ack.Pins), time.Duration(ack.Duration) * time.Microsecond}, nil
+	}
+}
+
+func (this *client) DeleteWave(w gopi.GPIOWave) error {
+	if w_, ok := w.(*wave); ok == false || w_ == nil {
+		return gopi.ErrBadParameter.WithPrefix("DeleteWave")
+	} else {
+		_, err := this.do(&Command{Op: Command_DELETE, Wave: w_.id})
+		return err
+	}
+}
+
+func (this *client) Play(w gopi.GPIOWave, repeat bool) error {
+	if w_, ok := w.(*wave); ok == false || w_ == nil {
+		return gopi.ErrBadParameter.WithPrefix("Play")
+	} else {
+		_, err := this.do(&Command{Op: Command_PLAY, Wave: w_.id, Repeat: repeat})
+		return err
+	}
+}
+
+func (this *client) Stop() error {
+	_, err := this.do(&Command{Op: Command_STOP})
+	return err
+}
+
+func (this *client) Busy() (bool, error) {
+	if ack, err := this.do(&Command{Op: Command_NONE}); err != nil {
+		return false, err
+	} else {
+		return ack.Busy, nil
+	}
+}
+
+func (this *client) Close() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return this.stream.CloseSend()
+}
+
+/////////////////////////////////////////////////////////////////////
+// WAVE METHODS
+
+func (this *wave) Pins() []gopi.GPIOPin {
+	return this.pins
+}
+
+func (this *wave) Duration() time.Duration {
+	return this.duration
+}
+
+/////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *stub) String() string {
+	str := "<rpc.stub.waveform"
+	str += " addr=" + strconv.Quote(this.Addr())
+	return str + ">"
+}
+
+func (this *wave) String() string {
+	str := "<rpc.wave"
+	str += fmt.Sprint(" id=", this.id)
+	str += fmt.Sprint(" pins=", this.pins)
+	str += fmt.Sprint(" duration=", this.duration)
+	return str + ">"
+}
+
+/////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// do sends a command and waits for the acknowledgement
+func (this *client) do(cmd *Command) (*Ack, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	this.seq++
+	cmd.Seq = this.seq
+	if err := this.stream.Send(cmd); err != nil {
+		return nil, this.Err(err)
+	} else if ack, err := this.stream.Recv(); err != nil {
+		return nil, this.Err(err)
+	} else if ack.Seq != cmd.Seq {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix(cmd.Op, ": seq ", ack.Seq)
+	} else if ack.Error != "" {
+		return ack, errors.New(ack.Error)
+	} else {
+		return ack, nil
+	}
+}
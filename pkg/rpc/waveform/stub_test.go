@@ -0,0 +1,104 @@
+package waveform
+
+import (
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	grpc "google.golang.org/grpc"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// loopback runs each command sent by a client in a session, and
+// returns the acknowledgement, optionally with the wrong sequence
+type loopback struct {
+	grpc.ClientStream
+	*session
+
+	ack    *Ack
+	skew   uint32
+	closed bool
+}
+
+func (this *loopback) Send(cmd *Command) error {
+	this.ack = this.session.Do(cmd)
+	this.ack.Seq += this.skew
+	return nil
+}
+
+func (this *loopback) Recv() (*Ack, error) {
+	return this.ack, nil
+}
+
+func (this *loopback) CloseSend() error {
+	this.closed = true
+	return this.session.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Stub_001(t *testing.T) {
+	waveform := newWaveform()
+	stream := &loopback{session: &session{GPIOWaveform: waveform, waves: make(map[uint32]gopi.GPIOWave)}}
+	client := &client{stream: stream}
+
+	// Upload returns a wave with pins and duration from the acknowledgement
+	wave, err := client.NewWave([]gopi.GPIOPulse{
+		{High: []gopi.GPIOPin{17}, Delay: 100 * time.Microsecond},
+		{Low: []gopi.GPIOPin{17}, Delay: 200 * time.Microsecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if wave.Duration() != 300*time.Microsecond || len(wave.Pins()) != 1 || wave.Pins()[0] != 17 {
+		t.Error("Unexpected wave", wave)
+	}
+
+	// Play, busy and stop
+	if err := client.Play(wave, false); err != nil {
+		t.Error(err)
+	} else if busy, err := client.Busy(); err != nil || busy == false {
+		t.Error("Expected busy", err)
+	} else if err := client.Stop(); err != nil {
+		t.Error(err)
+	} else if busy, err := client.Busy(); err != nil || busy {
+		t.Error("Expected not busy", err)
+	}
+
+	// Errors in acknowledgements are returned
+	if err := client.SetPWM(18, 50, 2); err == nil {
+		t.Error("Expected error for duty")
+	} else if err := client.DeleteWave(wave); err != nil {
+		t.Error(err)
+	} else if err := client.Play(wave, false); err == nil {
+		t.Error("Expected error for deleted wave")
+	} else if err := client.Play(nil, false); err == nil {
+		t.Error("Expected error for nil wave")
+	}
+
+	// Sequence numbers increase with each command
+	if client.seq != 8 {
+		t.Error("Unexpected sequence", client.seq)
+	}
+
+	// Close ends the session
+	if err := client.SetPWM(18, 50, 0.5); err != nil {
+		t.Error(err)
+	} else if err := client.Close(); err != nil {
+		t.Error(err)
+	} else if stream.closed == false || len(waveform.waves) != 0 || waveform.Busy() {
+		t.Error("Expected session to be closed", waveform)
+	}
+}
+
+func Test_Stub_002(t *testing.T) {
+	stream := &loopback{session: &session{GPIOWaveform: newWaveform(), waves: make(map[uint32]gopi.GPIOWave)}, skew: 1}
+	client := &client{stream: stream}
+
+	// An acknowledgement for another command is an error
+	if _, err := client.Busy(); err == nil {
+		t.Error("Expected error for sequence")
+	}
+}
@@ -0,0 +1,221 @@
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// BUILT-IN ACTIONS
+
+// builtins returns actions for available units
+func (this *engine) builtins() map[string]gopi.RuleAction {
+	result := map[string]gopi.RuleAction{
+		"log":     this.actionLog,
+		"webhook": this.actionWebhook,
+	}
+	if *this.smtp != "" {
+		result["email"] = this.actionEmail
+	}
+	if this.Publisher != nil {
+		result["emit"] = this.actionEmit
+	}
+	if this.GPIO != nil {
+		result["gpio.write"] = this.actionGPIOWrite
+	}
+	if this.Relay != nil {
+		result["relay.set"] = this.actionRelaySet
+	}
+	if this.CastManager != nil {
+		result["cast.volume"] = this.actionCastVolume
+		result["cast.mute"] = this.actionCastMute
+	}
+	if this.Notifier != nil {
+		result["notify"] = this.actionNotify
+	}
+	return result
+}
+
+// log(args...) prints arguments
+func (this *engine) actionLog(_ context.Context, args []interface{}) error {
+	this.Print("Rules: ", join(args))
+	return nil
+}
+
+// webhook(url, args...) posts arguments as a JSON message to a URL
+func (this *engine) actionWebhook(ctx context.Context, args []interface{}) error {
+	if len(args) < 1 || toString(args[0]) == "" {
+		return gopi.ErrBadParameter.WithPrefix("webhook(url, args...)")
+	}
+	data, err := json.Marshal(struct {
+		Message string `json:"message"`
+	}{join(args[1:])})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, toString(args[0]), bytes.NewReader(data))
+	if err != nil {
+		return gopi.ErrBadParameter.WithPrefix("webhook: ", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return gopi.ErrUnexpectedResponse.WithPrefix("webhook: ", resp.Status)
+	}
+	return nil
+}
+
+// email(to, subject, args...) sends arguments as an email through the
+// -rules.smtp server
+func (this *engine) actionEmail(_ context.Context, args []interface{}) error {
+	if len(args) < 2 || toString(args[0]) == "" {
+		return gopi.ErrBadParameter.WithPrefix("email(to, subject, args...)")
+	}
+	to := strings.Split(toString(args[0]), ",")
+	for i := range to {
+		to[i] = strings.TrimSpace(to[i])
+	}
+	var auth smtp.Auth
+	if *this.user != "" {
+		host, _, _ := net.SplitHostPort(*this.smtp)
+		auth = smtp.PlainAuth("", *this.user, *this.password, host)
+	}
+	msg := "From: " + *this.from + "\r\n"
+	msg += "To: " + strings.Join(to, ", ") + "\r\n"
+	msg += "Subject: " + strings.Join(strings.Fields(toString(args[1])), " ") + "\r\n"
+	msg += "Date: " + time.Now().Format(time.RFC1123Z) + "\r\n"
+	msg += "\r\n" + join(args[2:]) + "\r\n"
+	return smtp.SendMail(*this.smtp, auth, *this.from, to, []byte(msg))
+}
+
+// notify(severity, args...) sends arguments as a notification, where
+// severity is "info", "warning" or "critical", and other severities of
+// alerts are sent as information
+func (this *engine) actionNotify(ctx context.Context, args []interface{}) error {
+	if len(args) < 2 {
+		return gopi.ErrBadParameter.WithPrefix("notify(severity, args...)")
+	}
+	severity := gopi.NOTIFY_INFO
+	switch strings.ToLower(toString(args[0])) {
+	case "warning":
+		severity = gopi.NOTIFY_WARNING
+	case "critical":
+		severity = gopi.NOTIFY_CRITICAL
+	}
+	return this.Notifier.Notify(ctx, join(args[1:]), severity)
+}
+
+// emit(name) emits a named event, which can trigger other rules
+func (this *engine) actionEmit(_ context.Context, args []interface{}) error {
+	if len(args) != 1 || toString(args[0]) == "" {
+		return gopi.ErrBadParameter.WithPrefix("emit(name)")
+	}
+	return this.Publisher.Emit(&event{name: toString(args[0])}, false)
+}
+
+// gpio.write(pin, state) sets a pin as output and writes high when
+// state is true, non-zero, "high" or "GPIO_HIGH"
+func (this *engine) actionGPIOWrite(_ context.Context, args []interface{}) error {
+	if len(args) != 2 {
+		return gopi.ErrBadParameter.WithPrefix("gpio.write(pin, state)")
+	}
+	pin, err := toNumber(args[0])
+	if err != nil || pin < 0 || pin > 0xFF {
+		return gopi.ErrBadParameter.WithPrefix("gpio.write: Invalid pin ", args[0])
+	}
+	state := gopi.GPIO_LOW
+	switch v := args[1].(type) {
+	case string:
+		switch strings.ToLower(v) {
+		case "high", "gpio_high", "1":
+			state = gopi.GPIO_HIGH
+		case "low", "gpio_low", "0":
+			break
+		default:
+			return gopi.ErrBadParameter.WithPrefix("gpio.write: Invalid state ", strconv.Quote(v))
+		}
+	default:
+		if truth(v) {
+			state = gopi.GPIO_HIGH
+		}
+	}
+	this.GPIO.SetPinMode(gopi.GPIOPin(pin), gopi.GPIO_OUTPUT)
+	this.GPIO.WritePin(gopi.GPIOPin(pin), state)
+	return nil
+}
+
+// relay.set(name, state) switches a relay channel on when state is true,
+// waiting for the minimum on or off time of the channel
+func (this *engine) actionRelaySet(ctx context.Context, args []interface{}) error {
+	if len(args) != 2 || toString(args[0]) == "" {
+		return gopi.ErrBadParameter.WithPrefix("relay.set(name, state)")
+	}
+	return this.Relay.Set(ctx, toString(args[0]), truth(args[1]))
+}
+
+// cast.volume(name, volume) sets the volume between 0 and 1 for a cast
+// device by id or name
+func (this *engine) actionCastVolume(ctx context.Context, args []interface{}) error {
+	if len(args) != 2 {
+		return gopi.ErrBadParameter.WithPrefix("cast.volume(name, volume)")
+	}
+	cast := this.CastManager.Get(toString(args[0]))
+	if cast == nil {
+		return gopi.ErrNotFound.WithPrefix("cast.volume: ", strconv.Quote(toString(args[0])))
+	}
+	volume, err := toNumber(args[1])
+	if err != nil || volume < 0 || volume > 1 {
+		return gopi.ErrBadParameter.WithPrefix("cast.volume: Invalid volume ", args[1])
+	}
+	return this.CastManager.SetVolume(ctx, cast, float32(volume))
+}
+
+// cast.mute(name, muted) mutes or unmutes a cast device by id or name
+func (this *engine) actionCastMute(ctx context.Context, args []interface{}) error {
+	if len(args) != 2 {
+		return gopi.ErrBadParameter.WithPrefix("cast.mute(name, muted)")
+	}
+	cast := this.CastManager.Get(toString(args[0]))
+	if cast == nil {
+		return gopi.ErrNotFound.WithPrefix("cast.mute: ", strconv.Quote(toString(args[0])))
+	}
+	return this.CastManager.SetMuted(ctx, cast, truth(args[1]))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// join returns arguments separated by spaces
+func join(args []interface{}) string {
+	str := make([]string, len(args))
+	for i, arg := range args {
+		str[i] = toString(arg)
+	}
+	return strings.Join(str, " ")
+}
+
+// toNumber returns a number from a number or string
+func toNumber(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, gopi.ErrBadParameter.WithPrefix(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,239 @@
+package rules
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type alert struct {
+	kind     alertKind
+	source   string
+	glob     string
+	expr     expr
+	variable string
+	per      time.Duration
+	duration time.Duration
+
+	// State
+	state   gopi.AlertState
+	since   time.Time
+	pending time.Time
+	last    time.Time
+	sample  *sample
+	value   string
+}
+
+// sample is the previous value for a rate of change
+type sample struct {
+	ts    time.Time
+	value float64
+}
+
+type alertKind uint
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	ALERT_THRESHOLD alertKind = iota
+	ALERT_SILENT
+	ALERT_RATE
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PARSE
+
+// parseAlert parses one of "<glob> <expr> [for <duration>]",
+// "silent <glob> for <duration>" or
+// "rate <glob> <variable> <op> <number> per <duration> [for <duration>]"
+func parseAlert(src string) (*alert, error) {
+	fields := strings.Fields(src)
+	a := &alert{source: strings.Join(fields, " ")}
+
+	// Duration for which the condition is true
+	if n := len(fields); n > 2 && fields[n-2] == "for" {
+		if d, err := time.ParseDuration(fields[n-1]); err != nil || d < 0 {
+			return nil, gopi.ErrBadParameter.WithPrefix("Invalid duration ", strconv.Quote(fields[n-1]))
+		} else {
+			a.duration, fields = d, fields[:n-2]
+		}
+	}
+	if len(fields) < 2 {
+		return nil, gopi.ErrBadParameter.WithPrefix("Invalid alert ", strconv.Quote(src))
+	}
+
+	switch fields[0] {
+	case "silent":
+		if len(fields) != 2 || a.duration == 0 {
+			return nil, gopi.ErrBadParameter.WithPrefix("Invalid alert ", strconv.Quote(src))
+		}
+		a.kind, a.glob = ALERT_SILENT, fields[1]
+	case "rate":
+		i := len(fields) - 2
+		if len(fields) < 6 || fields[i] != "per" {
+			return nil, gopi.ErrBadParameter.WithPrefix("Invalid alert ", strconv.Quote(src))
+		} else if d, err := time.ParseDuration(fields[i+1]); err != nil || d <= 0 {
+			return nil, gopi.ErrBadParameter.WithPrefix("Invalid duration ", strconv.Quote(fields[i+1]))
+		} else if x, err := parseExpr(strings.Join(fields[2:i], " ")); err != nil {
+			return nil, err
+		} else {
+			a.kind, a.glob, a.variable, a.per, a.expr = ALERT_RATE, fields[1], fields[2], d, x
+		}
+	default:
+		if x, err := parseExpr(strings.Join(fields[1:], " ")); err != nil {
+			return nil, err
+		} else {
+			a.kind, a.glob, a.expr, a.variable = ALERT_THRESHOLD, fields[0], x, firstVariable(x)
+		}
+	}
+	if _, err := path.Match(a.glob, ""); err != nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("Invalid pattern ", strconv.Quote(a.glob))
+	}
+
+	// Return success
+	return a, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ALERTS
+
+// Start resets the state, so that silence is measured from now
+func (this *alert) Start(now time.Time) {
+	this.state, this.since, this.last, this.sample, this.value = gopi.ALERT_RESOLVED, now, now, nil, ""
+}
+
+// Event updates the state for an event which matches the pattern, and
+// returns the new state when the alert fires or resolves
+func (this *alert) Event(name string, v vars, now time.Time) (gopi.AlertState, bool, error) {
+	if match, _ := path.Match(this.glob, name); match == false {
+		return this.state, false, nil
+	}
+	switch this.kind {
+	case ALERT_SILENT:
+		this.last = now
+		return this.update(false, now, "")
+	case ALERT_THRESHOLD:
+		if value, err := this.expr.Eval(v); err != nil {
+			return this.state, false, err
+		} else {
+			return this.update(truth(value), now, toString(v[this.variable]))
+		}
+	case ALERT_RATE:
+		value, err := toNumber(v[this.variable])
+		if err != nil {
+			return this.state, false, nil
+		}
+		prev := this.sample
+		this.sample = &sample{now, value}
+		if prev == nil || now.After(prev.ts) == false {
+			return this.state, false, nil
+		}
+		rate := (value - prev.value) / now.Sub(prev.ts).Seconds() * this.per.Seconds()
+		v_ := vars{this.variable: rate}
+		if result, err := this.expr.Eval(v_); err != nil {
+			return this.state, false, err
+		} else {
+			return this.update(truth(result), now, strconv.FormatFloat(rate, 'f', -1, 64))
+		}
+	default:
+		return this.state, false, nil
+	}
+}
+
+// Tick fires alerts which have been pending for the duration, or have
+// not received events for the duration
+func (this *alert) Tick(now time.Time) (gopi.AlertState, bool) {
+	switch {
+	case this.kind == ALERT_SILENT && this.state != gopi.ALERT_FIRING && now.Sub(this.last) >= this.duration:
+		return this.set(gopi.ALERT_FIRING, now, now.Sub(this.last).Truncate(time.Second).String())
+	case this.state == gopi.ALERT_PENDING && now.Sub(this.pending) >= this.duration:
+		return this.set(gopi.ALERT_FIRING, now, this.value)
+	default:
+		return this.state, false
+	}
+}
+
+// Alert returns the state of the alert
+func (this *alert) Alert(name, severity string) gopi.Alert {
+	return gopi.Alert{
+		Name:     name,
+		Severity: severity,
+		State:    this.state,
+		Since:    this.since,
+		Value:    this.value,
+	}
+}
+
+// update the state when the condition changes, returning true when the
+// alert fires or resolves
+func (this *alert) update(cond bool, now time.Time, value string) (gopi.AlertState, bool, error) {
+	if value != "" {
+		this.value = value
+	}
+	switch {
+	case cond && this.state == gopi.ALERT_RESOLVED && this.duration > 0:
+		this.pending = now
+		state, _ := this.set(gopi.ALERT_PENDING, now, this.value)
+		return state, false, nil
+	case cond && this.state != gopi.ALERT_FIRING && now.Sub(this.pending) >= this.duration:
+		state, changed := this.set(gopi.ALERT_FIRING, now, this.value)
+		return state, changed, nil
+	case cond == false && this.state == gopi.ALERT_PENDING:
+		state, _ := this.set(gopi.ALERT_RESOLVED, now, this.value)
+		return state, false, nil
+	case cond == false && this.state == gopi.ALERT_FIRING:
+		state, changed := this.set(gopi.ALERT_RESOLVED, now, this.value)
+		return state, changed, nil
+	default:
+		return this.state, false, nil
+	}
+}
+
+func (this *alert) set(state gopi.AlertState, now time.Time, value string) (gopi.AlertState, bool) {
+	if state == this.state {
+		return state, false
+	}
+	this.state, this.since, this.value = state, now, value
+	return state, true
+}
+
+// firstVariable returns the name of the first variable in an expression,
+// which is the value reported for a threshold alert
+func firstVariable(x expr) string {
+	switch x := x.(type) {
+	case *variable:
+		return x.name
+	case *unary:
+		return firstVariable(x.x)
+	case *binary:
+		if name := firstVariable(x.x); name != "" {
+			return name
+		}
+		return firstVariable(x.y)
+	default:
+		return ""
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+// stateName returns "resolved", "pending" or "firing"
+func stateName(state gopi.AlertState) string {
+	return strings.ToLower(strings.TrimPrefix(state.String(), "ALERT_"))
+}
+
+func (this *alert) String() string {
+	str := "<alert"
+	str += fmt.Sprintf(" when=%q", this.source)
+	str += " state=" + fmt.Sprint(this.state)
+	return str + ">"
+}
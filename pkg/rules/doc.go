@@ -0,0 +1,54 @@
+// Rules package runs automation rules read from the -rules.path file,
+// which is reloaded when it changes. Each rule has one or more triggers,
+// optional conditions and one or more actions:
+//
+//   # Turn the porch light on at dusk on weekdays
+//   rule "porch light"
+//     on at 18:30
+//     if weekday != "sat" && weekday != "sun"
+//     do gpio.write(17, "high")
+//   end
+//
+//   rule "too hot"
+//     on threshold sensor temperature > 30
+//     do cast.volume("Living Room", 0.2)
+//     do log("Temperature is", temperature)
+//   end
+//
+// Triggers are "event <pattern>" for events with a matching name,
+// "threshold <pattern> <expression>" when an expression evaluated for
+// matching events becomes true, "every <duration>" and "at <hh:mm>".
+// Expressions use numbers, strings, booleans and variables with the
+// operators || && == != < <= > >= + - * / and !. Variables are time,
+// hour, minute and weekday, and for events the name and the values of
+// the event methods and measurement fields.
+//
+// Alerts have a condition, and fire when the condition has been true for
+// an optional duration, and resolve when the condition becomes false:
+//
+//   alert "freezer"
+//     when freezer temperature > -10 for 5m
+//     severity critical
+//     do email("me@example.com", "Freezer is warm", value)
+//     resolve log(alert, state)
+//   end
+//
+// Conditions are "<pattern> <expression> [for <duration>]" for events
+// with a matching name, "silent <pattern> for <duration>" when there are
+// no matching events for the duration, and "rate <pattern> <variable>
+// <op> <number> per <duration>" which compares the change in a variable
+// per duration. When an alert fires the do actions run if the conditions
+// are true, and when it resolves the resolve actions run. The variables
+// alert, severity, state and value are set for the actions, and a
+// gopi.AlertEvent is emitted when the state changes, which is encoded as
+// JSON so it can be published over MQTT as -iot.telemetry. The state of
+// alerts is returned by Alerts.
+//
+// Actions are log, emit (which emits a named event), webhook (which
+// posts a JSON message), gpio.write, relay.set, cast.volume and
+// cast.mute when the units are available, email when the -rules.smtp
+// server and -rules.smtp.from address are set, and notify (which sends
+// a notification with a severity through gopi.Notifier, for example
+// "do notify(severity, alert, state, value)") when it is available. Other units can register
+// actions, such as publishing messages, with RegisterAction.
+package rules
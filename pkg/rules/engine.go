@@ -0,0 +1,435 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type engine struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.GPIO
+	gopi.Relay
+	gopi.CastManager
+	gopi.Notifier
+	sync.Mutex
+	sync.WaitGroup
+
+	path     *string
+	timeout  *time.Duration
+	smtp     *string
+	from     *string
+	user     *string
+	password *string
+
+	actions map[string]gopi.RuleAction
+	rules   []*rule
+	modtime time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// deltaTick is the interval between checking schedules and
+	// whether the rules file has changed
+	deltaTick = time.Second
+)
+
+var (
+	reActionName = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]*(\\.[A-Za-z_][A-Za-z0-9_]*)*$")
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *engine) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagPath("rules.path", "", "Rules file")
+	this.timeout = cfg.FlagDuration("rules.timeout", 10*time.Second, "Timeout for rule actions")
+	this.smtp = cfg.FlagString("rules.smtp", "", "SMTP server <host>:<port> for the email action")
+	this.from = cfg.FlagString("rules.smtp.from", "", "Sender address for the email action")
+	this.user = cfg.FlagString("rules.smtp.user", "", "SMTP user")
+	this.password = cfg.FlagSecret("rules.smtp.password", "", "SMTP password")
+	return nil
+}
+
+func (this *engine) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	if *this.timeout <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-rules.timeout")
+	} else if *this.smtp != "" && *this.from == "" {
+		return gopi.ErrBadParameter.WithPrefix("-rules.smtp.from")
+	}
+
+	// Register actions for available units
+	this.actions = make(map[string]gopi.RuleAction)
+	for name, fn := range this.builtins() {
+		if err := this.RegisterAction(name, fn); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *engine) Run(ctx context.Context) error {
+	// Load rules, reporting errors but continuing, so that the rules
+	// file can be fixed while running
+	if *this.path != "" {
+		if err := this.Reload(); err != nil {
+			this.Print("Rules: ", err)
+		}
+	}
+
+	// Subscribe to events
+	var ch <-chan gopi.Event
+	if this.Publisher != nil {
+		ch = this.Publisher.Subscribe()
+		defer this.Publisher.Unsubscribe(ch)
+	}
+
+	timer := time.NewTicker(deltaTick)
+	defer timer.Stop()
+
+FOR_LOOP:
+	for {
+		select {
+		case <-ctx.Done():
+			break FOR_LOOP
+		case evt := <-ch:
+			this.event(ctx, time.Now(), evt)
+		case <-timer.C:
+			if this.changed() {
+				if err := this.Reload(); err != nil {
+					this.Print("Rules: ", err)
+				}
+			}
+			this.tick(ctx, time.Now())
+		}
+	}
+
+	// Wait for actions to complete
+	this.WaitGroup.Wait()
+
+	// Return success
+	return ctx.Err()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *engine) String() string {
+	str := "<rules"
+	if *this.path != "" {
+		str += fmt.Sprintf(" path=%q", *this.path)
+	}
+	str += " timeout=" + fmt.Sprint(*this.timeout)
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	for _, r := range this.rules {
+		str += " " + fmt.Sprint(r)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *engine) RegisterAction(name string, fn gopi.RuleAction) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if reActionName.MatchString(name) == false || fn == nil {
+		return gopi.ErrBadParameter.WithPrefix("RegisterAction: ", strconv.Quote(name))
+	} else if _, exists := this.actions[name]; exists {
+		return gopi.ErrDuplicateEntry.WithPrefix("RegisterAction: ", strconv.Quote(name))
+	} else {
+		this.actions[name] = fn
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *engine) Reload() error {
+	if *this.path == "" {
+		return gopi.ErrBadParameter.WithPrefix("Reload: Missing -rules.path")
+	}
+
+	// Read rules
+	stat, err := os.Stat(*this.path)
+	if err != nil {
+		return err
+	}
+	fh, err := os.Open(*this.path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	rules, err := parseRules(fh)
+	if err != nil {
+		return fmt.Errorf("%v: %w", *this.path, err)
+	}
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Record the modification time even when rules are invalid, so that
+	// the file is only reloaded when it changes again
+	this.modtime = stat.ModTime()
+
+	// Check actions exist
+	for _, r := range rules {
+		for _, a := range r.actions {
+			if _, exists := this.actions[a.name]; exists == false {
+				return gopi.ErrNotFound.WithPrefix(*this.path, ": Rule ", strconv.Quote(r.name), ": Action ", strconv.Quote(a.name))
+			}
+		}
+	}
+
+	for _, r := range rules {
+		for _, a := range r.resolves {
+			if _, exists := this.actions[a.name]; exists == false {
+				return gopi.ErrNotFound.WithPrefix(*this.path, ": Alert ", strconv.Quote(r.name), ": Action ", strconv.Quote(a.name))
+			}
+		}
+	}
+
+	// Alerts with the same condition keep their state
+	alerts := make(map[string]*alert)
+	for _, r := range this.rules {
+		if r.alert != nil {
+			alerts[r.name] = r.alert
+		}
+	}
+
+	// Start schedules and alerts, and replace rules
+	now := time.Now()
+	for _, r := range rules {
+		for _, t := range r.triggers {
+			t.Start(now)
+		}
+		if r.alert != nil {
+			if prev, exists := alerts[r.name]; exists && prev.source == r.alert.source {
+				r.alert = prev
+			} else {
+				r.alert.Start(now)
+			}
+		}
+		this.Debug("Rules: ", r)
+	}
+	this.rules = rules
+
+	// Return success
+	return nil
+}
+
+func (this *engine) Rules() []string {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	result := make([]string, 0, len(this.rules))
+	for _, r := range this.rules {
+		result = append(result, r.name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (this *engine) Alerts() []gopi.Alert {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	result := []gopi.Alert{}
+	for _, r := range this.rules {
+		if r.alert != nil {
+			result = append(result, r.alert.Alert(r.name, r.severity))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// changed returns true if the rules file has been modified since it
+// was last loaded
+func (this *engine) changed() bool {
+	if *this.path == "" {
+		return false
+	} else if stat, err := os.Stat(*this.path); err != nil {
+		return false
+	} else {
+		this.Mutex.Lock()
+		defer this.Mutex.Unlock()
+		return stat.ModTime().Equal(this.modtime) == false
+	}
+}
+
+// tick fires rules with schedule triggers, and alerts which are pending
+// or silent for their duration
+func (this *engine) tick(ctx context.Context, now time.Time) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	for _, r := range this.rules {
+		if r.alert != nil {
+			if state, changed := r.alert.Tick(now); changed {
+				this.alert(ctx, r, state, timeVars(now))
+			}
+		}
+		for _, t := range r.triggers {
+			if t.Tick(now) {
+				this.fire(ctx, r, t, timeVars(now))
+			}
+		}
+	}
+}
+
+// event fires rules with event and threshold triggers, and updates the
+// state of alerts. Rules are not triggered by their own events
+func (this *engine) event(ctx context.Context, now time.Time, evt gopi.Event) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	self := ""
+	switch evt := evt.(type) {
+	case *event:
+		self = evt.name
+	case *alertEvent:
+		self = evt.name
+	}
+
+	var v vars
+	for _, r := range this.rules {
+		if self == r.name {
+			continue
+		}
+		if r.alert != nil {
+			if v == nil {
+				v = eventVars(now, evt)
+			}
+			if state, changed, err := r.alert.Event(evt.Name(), v, now); err != nil {
+				this.Print("Alert ", strconv.Quote(r.name), ": ", err)
+			} else if changed {
+				this.alert(ctx, r, state, v)
+			}
+		}
+		for _, t := range r.triggers {
+			if t.kind != TRIGGER_EVENT && t.kind != TRIGGER_THRESHOLD {
+				continue
+			}
+			if v == nil {
+				v = eventVars(now, evt)
+			}
+			if match, err := t.Match(evt.Name(), v); err != nil {
+				this.Print("Rule ", strconv.Quote(r.name), ": ", err)
+			} else if match {
+				this.fire(ctx, r, t, v)
+			}
+		}
+	}
+}
+
+// fire tests conditions and runs actions for a rule in the background,
+// and emits an event when the actions have run
+func (this *engine) fire(ctx context.Context, r *rule, t *trigger, v vars) {
+	if ok, err := r.Test(v); err != nil {
+		this.Print("Rule ", strconv.Quote(r.name), ": ", err)
+	} else if ok {
+		this.run(ctx, r.name, t.source, r.actions, v, func(err error) gopi.Event {
+			return &event{r.name, t.source, err}
+		})
+	}
+}
+
+// alert runs the actions for an alert which fires when the conditions
+// are true, or the resolve actions for an alert which resolves. The
+// variables alert, severity, state and value are set for the actions,
+// and an event is emitted when the actions have run
+func (this *engine) alert(ctx context.Context, r *rule, state gopi.AlertState, v vars) {
+	value := r.alert.value
+	this.Print("Alert ", strconv.Quote(r.name), ": ", stateName(state), " ", value)
+
+	// Copy variables, as they are shared between rules
+	w := make(vars, len(v)+4)
+	for k, x := range v {
+		w[k] = x
+	}
+	w["alert"], w["severity"], w["state"], w["value"] = r.name, r.severity, stateName(state), value
+
+	actions := r.resolves
+	if state == gopi.ALERT_FIRING {
+		if ok, err := r.Test(w); err != nil {
+			this.Print("Alert ", strconv.Quote(r.name), ": ", err)
+			actions = nil
+		} else if ok {
+			actions = r.actions
+		} else {
+			actions = nil
+		}
+	}
+	this.run(ctx, r.name, r.alert.source, actions, w, func(err error) gopi.Event {
+		return &alertEvent{event{r.name, r.alert.source, err}, state, r.severity, value}
+	})
+}
+
+// run evaluates arguments and runs actions in the background, and emits
+// the event returned by a function when the actions have run
+func (this *engine) run(ctx context.Context, name, source string, actions []*action, v vars, fn func(error) gopi.Event) {
+	// Evaluate arguments now, as variables are shared between rules
+	type call struct {
+		name string
+		fn   gopi.RuleAction
+		args []interface{}
+	}
+	calls := make([]call, 0, len(actions))
+	for _, a := range actions {
+		if args, err := a.Args(v); err != nil {
+			this.Print("Rule ", strconv.Quote(name), ": ", a.name, ": ", err)
+			return
+		} else {
+			calls = append(calls, call{a.name, this.actions[a.name], args})
+		}
+	}
+
+	// Run actions in order, stopping on error
+	this.Debug("Rule ", strconv.Quote(name), ": ", source)
+	this.WaitGroup.Add(1)
+	go func() {
+		defer this.WaitGroup.Done()
+		var result error
+		for _, c := range calls {
+			child, cancel := context.WithTimeout(ctx, *this.timeout)
+			err := c.fn(child, c.args)
+			cancel()
+			if err != nil {
+				result = fmt.Errorf("%v: %w", c.name, err)
+				this.Print("Rule ", strconv.Quote(name), ": ", result)
+				break
+			}
+		}
+		if this.Publisher != nil {
+			if err := this.Publisher.Emit(fn(result), false); err != nil {
+				this.Debug("Rule ", strconv.Quote(name), ": ", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,224 @@
+package rules_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/rules"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.RuleEngine
+	gopi.Publisher
+}
+
+type testevent struct {
+	name  string
+	value float64
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (this *testevent) Name() string   { return this.name }
+func (this *testevent) Value() float64 { return this.value }
+
+// subscribe returns a channel for events and a function which
+// unsubscribes, reading any events which are being emitted
+func subscribe(app *App) (<-chan gopi.Event, func()) {
+	ch := app.Publisher.Subscribe()
+	return ch, func() {
+		go func() {
+			for range ch {
+			}
+		}()
+		app.Publisher.Unsubscribe(ch)
+	}
+}
+
+// wait returns the next rule event or nil after a timeout
+func wait(ch <-chan gopi.Event, timeout time.Duration) gopi.RuleEvent {
+	after := time.After(timeout)
+	for {
+		select {
+		case evt := <-ch:
+			if evt, ok := evt.(gopi.RuleEvent); ok && evt.Trigger() != "" {
+				return evt
+			}
+		case <-after:
+			return nil
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Engine_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.RuleEngine == nil {
+			t.Error("nil RuleEngine unit")
+		} else if err := app.RuleEngine.Reload(); err == nil {
+			t.Error("Expected error without rules file")
+		} else if err := app.RuleEngine.RegisterAction("log", func(context.Context, []interface{}) error { return nil }); err == nil {
+			t.Error("Expected error for duplicate action")
+		} else if err := app.RuleEngine.RegisterAction("bad name", func(context.Context, []interface{}) error { return nil }); err == nil {
+			t.Error("Expected error for invalid action name")
+		} else {
+			t.Log(app.RuleEngine)
+		}
+	})
+}
+
+func Test_Engine_002(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules")
+	if err := ioutil.WriteFile(path, []byte(`
+		rule hot
+			on threshold sensor value > 30
+			do test.record(name, value)
+		end
+		rule chained
+			on event hot
+			do emit("done")
+		end
+	`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tool.Test(t, []string{"-rules.path=" + path}, new(App), func(app *App) {
+		ch, unsubscribe := subscribe(app)
+		defer unsubscribe()
+
+		records := make(chan []interface{}, 10)
+		if err := app.RuleEngine.RegisterAction("test.record", func(_ context.Context, args []interface{}) error {
+			records <- args
+			return nil
+		}); err != nil {
+			t.Error(err)
+		} else if err := app.RuleEngine.Reload(); err != nil {
+			t.Error(err)
+		} else if rules := app.RuleEngine.Rules(); len(rules) != 2 || rules[0] != "chained" || rules[1] != "hot" {
+			t.Error("Unexpected rules", rules)
+		}
+
+		// Only the rising threshold fires, then the chained rule. Wait
+		// for the engine to subscribe to events first
+		time.Sleep(100 * time.Millisecond)
+		for _, value := range []float64{20, 35, 40} {
+			app.Publisher.Emit(&testevent{"sensor", value}, true)
+		}
+		if evt := wait(ch, time.Second); evt == nil {
+			t.Error("Timeout waiting for rule event")
+		} else if evt.Name() != "hot" || evt.Error() != nil {
+			t.Error("Unexpected event", evt)
+		}
+		if evt := wait(ch, time.Second); evt == nil {
+			t.Error("Timeout waiting for chained rule event")
+		} else if evt.Name() != "chained" || evt.Trigger() != "event hot" {
+			t.Error("Unexpected event", evt)
+		}
+		if len(records) != 1 {
+			t.Error("Expected one record, got", len(records))
+		} else if args := <-records; len(args) != 2 || args[0] != "sensor" || args[1] != 35.0 {
+			t.Error("Unexpected arguments", args)
+		}
+	})
+}
+
+func Test_Engine_003(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules")
+	if err := ioutil.WriteFile(path, []byte("rule a\non every 1s\ndo missing()\nend\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tool.Test(t, []string{"-rules.path=" + path}, new(App), func(app *App) {
+		// Unknown actions are an error and rules are not loaded
+		if err := app.RuleEngine.Reload(); err == nil {
+			t.Error("Expected error for unknown action")
+		} else if len(app.RuleEngine.Rules()) != 0 {
+			t.Error("Unexpected rules", app.RuleEngine.Rules())
+		}
+
+		// The file is reloaded when it changes
+		ch, unsubscribe := subscribe(app)
+		defer unsubscribe()
+		if err := ioutil.WriteFile(path, []byte("rule b\non every 1s\ndo log(\"tick\")\nend\n"), 0644); err != nil {
+			t.Error(err)
+		} else if err := os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)); err != nil {
+			t.Error(err)
+		} else if evt := wait(ch, 5*time.Second); evt == nil {
+			t.Error("Timeout waiting for rule event")
+		} else if evt.Name() != "b" || evt.Trigger() != "every 1s" {
+			t.Error("Unexpected event", evt)
+		}
+	})
+}
+
+func Test_Engine_004(t *testing.T) {
+	messages := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body struct{ Message string }
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			messages <- body.Message
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "rules")
+	if err := ioutil.WriteFile(path, []byte(`
+		alert hot
+			when sensor value > 30
+			severity warning
+			do webhook("`+server.URL+`", alert, state, value)
+			resolve webhook("`+server.URL+`", alert, state)
+		end
+	`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tool.Test(t, []string{"-rules.path=" + path}, new(App), func(app *App) {
+		ch, unsubscribe := subscribe(app)
+		defer unsubscribe()
+
+		// Wait for the engine to subscribe to events, then fire and resolve
+		time.Sleep(100 * time.Millisecond)
+		if alerts := app.RuleEngine.Alerts(); len(alerts) != 1 || alerts[0].Name != "hot" || alerts[0].State != gopi.ALERT_RESOLVED {
+			t.Error("Unexpected alerts", alerts)
+		}
+		app.Publisher.Emit(&testevent{"sensor", 35}, true)
+		if evt, ok := wait(ch, time.Second).(gopi.AlertEvent); ok == false {
+			t.Error("Timeout waiting for alert event")
+		} else if evt.Name() != "hot" || evt.State() != gopi.ALERT_FIRING || evt.Severity() != "warning" || evt.Error() != nil {
+			t.Error("Unexpected event", evt)
+		} else if msg := <-messages; msg != "hot firing 35" {
+			t.Error("Unexpected message", msg)
+		}
+		if alerts := app.RuleEngine.Alerts(); len(alerts) != 1 || alerts[0].State != gopi.ALERT_FIRING || alerts[0].Value != "35" {
+			t.Error("Unexpected alerts", alerts)
+		}
+		app.Publisher.Emit(&testevent{"sensor", 25}, true)
+		if evt, ok := wait(ch, time.Second).(gopi.AlertEvent); ok == false {
+			t.Error("Timeout waiting for alert event")
+		} else if evt.State() != gopi.ALERT_RESOLVED {
+			t.Error("Unexpected event", evt)
+		} else if msg := <-messages; msg != "hot resolved" {
+			t.Error("Unexpected message", msg)
+		}
+	})
+}
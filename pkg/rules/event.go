@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	name    string
+	trigger string
+	err     error
+}
+
+type alertEvent struct {
+	event
+	state    gopi.AlertState
+	severity string
+	value    string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.name
+}
+
+func (this *event) Trigger() string {
+	return this.trigger
+}
+
+func (this *event) Error() error {
+	return this.err
+}
+
+func (this *alertEvent) State() gopi.AlertState {
+	return this.state
+}
+
+func (this *alertEvent) Severity() string {
+	return this.severity
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// JSON
+
+// MarshalJSON encodes an alert event, so that it can be published as
+// telemetry
+func (this *alertEvent) MarshalJSON() ([]byte, error) {
+	type alertJSON struct {
+		Name     string `json:"name"`
+		State    string `json:"state"`
+		Severity string `json:"severity,omitempty"`
+		Value    string `json:"value,omitempty"`
+		Error    string `json:"error,omitempty"`
+	}
+	result := alertJSON{this.name, stateName(this.state), this.severity, this.value, ""}
+	if this.err != nil {
+		result.Error = this.err.Error()
+	}
+	return json.Marshal(result)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<rules.event"
+	str += fmt.Sprintf(" name=%q", this.name)
+	if this.trigger != "" {
+		str += fmt.Sprintf(" trigger=%q", this.trigger)
+	}
+	if this.err != nil {
+		str += " err=" + fmt.Sprint(this.err)
+	}
+	return str + ">"
+}
+
+func (this *alertEvent) String() string {
+	str := "<rules.alert"
+	str += fmt.Sprintf(" name=%q", this.name)
+	str += " state=" + fmt.Sprint(this.state)
+	if this.severity != "" {
+		str += fmt.Sprintf(" severity=%q", this.severity)
+	}
+	if this.value != "" {
+		str += fmt.Sprintf(" value=%q", this.value)
+	}
+	if this.err != nil {
+		str += " err=" + fmt.Sprint(this.err)
+	}
+	return str + ">"
+}
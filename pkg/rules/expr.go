@@ -0,0 +1,397 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// vars are the values of variables when evaluating an expression, which
+// are numbers, strings or booleans
+type vars map[string]interface{}
+
+// expr is an expression which evaluates to a number, string, boolean
+// or nil when a variable is not defined
+type expr interface {
+	Eval(vars) (interface{}, error)
+}
+
+type (
+	literal  struct{ value interface{} }
+	variable struct{ name string }
+	unary    struct {
+		op string
+		x  expr
+	}
+	binary struct {
+		op   string
+		x, y expr
+	}
+)
+
+type tokenKind uint
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOp
+)
+
+var (
+	// Binary operators in increasing order of precedence
+	precedence = [][]string{
+		{"||"},
+		{"&&"},
+		{"==", "!="},
+		{"<", "<=", ">", ">="},
+		{"+", "-"},
+		{"*", "/"},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PARSE
+
+// parseExpr parses a complete expression
+func parseExpr(src string) (expr, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	x, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	} else if t := p.peek(); t.kind != tokenEOF {
+		return nil, gopi.ErrBadParameter.WithPrefix("Unexpected ", strconv.Quote(t.value))
+	}
+	return x, nil
+}
+
+func (this *parser) peek() token {
+	return this.tokens[this.pos]
+}
+
+func (this *parser) next() token {
+	t := this.tokens[this.pos]
+	if t.kind != tokenEOF {
+		this.pos++
+	}
+	return t
+}
+
+// accept consumes an operator token if it matches
+func (this *parser) accept(op string) bool {
+	if t := this.peek(); t.kind == tokenOp && t.value == op {
+		this.pos++
+		return true
+	}
+	return false
+}
+
+// expr parses binary operators at a level of precedence and above
+func (this *parser) expr(level int) (expr, error) {
+	if level == len(precedence) {
+		return this.unary()
+	}
+	x, err := this.expr(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := ""
+		for _, candidate := range precedence[level] {
+			if this.accept(candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return x, nil
+		}
+		y, err := this.expr(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &binary{op, x, y}
+	}
+}
+
+func (this *parser) unary() (expr, error) {
+	for _, op := range []string{"!", "-"} {
+		if this.accept(op) {
+			x, err := this.unary()
+			if err != nil {
+				return nil, err
+			}
+			return &unary{op, x}, nil
+		}
+	}
+	return this.primary()
+}
+
+func (this *parser) primary() (expr, error) {
+	t := this.next()
+	switch t.kind {
+	case tokenNumber:
+		if v, err := strconv.ParseFloat(t.value, 64); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix("Invalid number ", t.value)
+		} else {
+			return &literal{v}, nil
+		}
+	case tokenString:
+		return &literal{t.value}, nil
+	case tokenIdent:
+		switch t.value {
+		case "true":
+			return &literal{true}, nil
+		case "false":
+			return &literal{false}, nil
+		default:
+			return &variable{t.value}, nil
+		}
+	case tokenOp:
+		if t.value == "(" {
+			x, err := this.expr(0)
+			if err != nil {
+				return nil, err
+			} else if this.accept(")") == false {
+				return nil, gopi.ErrBadParameter.WithPrefix("Missing )")
+			}
+			return x, nil
+		}
+	case tokenEOF:
+		return nil, gopi.ErrBadParameter.WithPrefix("Unexpected end of expression")
+	}
+	return nil, gopi.ErrBadParameter.WithPrefix("Unexpected ", strconv.Quote(t.value))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LEX
+
+// lex splits an expression into tokens, ending with an EOF token
+func lex(src string) ([]token, error) {
+	result := []token{}
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.') {
+				j++
+			}
+			result = append(result, token{tokenNumber, src[i:j], i})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(src) && isIdent(rune(src[j])) {
+				j++
+			}
+			result = append(result, token{tokenIdent, src[i:j], i})
+			i = j
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, gopi.ErrBadParameter.WithPrefix("Unterminated string")
+			} else if value, err := strconv.Unquote(src[i : j+1]); err != nil {
+				return nil, gopi.ErrBadParameter.WithPrefix("Invalid string ", src[i:j+1])
+			} else {
+				result = append(result, token{tokenString, value, i})
+			}
+			i = j + 1
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "+", "-", "*", "/", "(", ")", ","} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, gopi.ErrBadParameter.WithPrefix("Unexpected ", strconv.QuoteRune(c))
+			}
+			result = append(result, token{tokenOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(result, token{tokenEOF, "", len(src)}), nil
+}
+
+func isIdent(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.'
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// EVALUATE
+
+func (this *literal) Eval(vars) (interface{}, error) {
+	return this.value, nil
+}
+
+func (this *variable) Eval(v vars) (interface{}, error) {
+	return v[this.name], nil
+}
+
+func (this *unary) Eval(v vars) (interface{}, error) {
+	x, err := this.x.Eval(v)
+	if err != nil {
+		return nil, err
+	}
+	switch this.op {
+	case "!":
+		return truth(x) == false, nil
+	default:
+		if n, ok := x.(float64); ok {
+			return -n, nil
+		}
+		return nil, gopi.ErrBadParameter.WithPrefix("Invalid operand for -")
+	}
+}
+
+func (this *binary) Eval(v vars) (interface{}, error) {
+	x, err := this.x.Eval(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short-circuit logical operators
+	switch this.op {
+	case "&&":
+		if truth(x) == false {
+			return false, nil
+		}
+	case "||":
+		if truth(x) {
+			return true, nil
+		}
+	}
+	y, err := this.y.Eval(v)
+	if err != nil {
+		return nil, err
+	}
+
+	switch this.op {
+	case "&&", "||":
+		return truth(y), nil
+	case "==":
+		return x == y, nil
+	case "!=":
+		return x != y, nil
+	case "<", "<=", ">", ">=":
+		return compare(this.op, x, y), nil
+	case "+":
+		if a, ok := x.(float64); ok {
+			if b, ok := y.(float64); ok {
+				return a + b, nil
+			}
+		}
+		return toString(x) + toString(y), nil
+	default:
+		a, ok1 := x.(float64)
+		b, ok2 := y.(float64)
+		if ok1 == false || ok2 == false {
+			return nil, gopi.ErrBadParameter.WithPrefix("Invalid operands for ", this.op)
+		}
+		switch this.op {
+		case "-":
+			return a - b, nil
+		case "*":
+			return a * b, nil
+		default:
+			if b == 0 {
+				return nil, gopi.ErrBadParameter.WithPrefix("Division by zero")
+			}
+			return a / b, nil
+		}
+	}
+}
+
+// compare orders numbers or strings, and is false for other values
+func compare(op string, x, y interface{}) bool {
+	var c int
+	if a, ok := x.(float64); ok {
+		if b, ok := y.(float64); ok {
+			switch {
+			case a < b:
+				c = -1
+			case a > b:
+				c = 1
+			}
+		} else {
+			return false
+		}
+	} else if a, ok := x.(string); ok {
+		if b, ok := y.(string); ok {
+			c = strings.Compare(a, b)
+		} else {
+			return false
+		}
+	} else {
+		return false
+	}
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+// truth returns false for nil, false, zero and empty strings
+func truth(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	default:
+		return false
+	}
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,13 @@
+package rules
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register rule engine
+	graph.RegisterUnit(reflect.TypeOf(&engine{}), reflect.TypeOf((*gopi.RuleEngine)(nil)))
+}
@@ -0,0 +1,391 @@
+package rules
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type rule struct {
+	name       string
+	triggers   []*trigger
+	conditions []expr
+	actions    []*action
+
+	// Alerts
+	alert    *alert
+	severity string
+	resolves []*action
+}
+
+type trigger struct {
+	kind   triggerKind
+	source string
+
+	// Event and threshold triggers
+	glob  string
+	expr  expr
+	state bool
+
+	// Schedule triggers
+	every time.Duration
+	at    time.Duration
+	next  time.Time
+}
+
+type action struct {
+	name string
+	args []expr
+}
+
+type triggerKind uint
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	TRIGGER_EVENT triggerKind = iota
+	TRIGGER_THRESHOLD
+	TRIGGER_EVERY
+	TRIGGER_AT
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PARSE
+
+// parseRules reads rules, where each rule starts with "rule <name>"
+// and ends with "end", and contains "on", "if" and "do" lines. Alerts
+// start with "alert <name>" and contain "when", "severity", "if", "do"
+// and "resolve" lines. Blank lines and lines starting with # are ignored
+func parseRules(r io.Reader) ([]*rule, error) {
+	result := []*rule{}
+	names := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+
+	var current *rule
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		keyword, args := text, ""
+		if i := strings.IndexFunc(text, isSpace); i >= 0 {
+			keyword, args = text[:i], strings.TrimSpace(text[i:])
+		}
+		if err := parseLine(&current, keyword, args, &result, names); err != nil {
+			return nil, fmt.Errorf("Line %v: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	} else if current != nil {
+		return nil, gopi.ErrBadParameter.WithPrefix("Missing end for rule ", strconv.Quote(current.name))
+	}
+
+	// Return success
+	return result, nil
+}
+
+func parseLine(current **rule, keyword, args string, rules *[]*rule, names map[string]bool) error {
+	if keyword == "rule" || keyword == "alert" {
+		if *current != nil {
+			return gopi.ErrBadParameter.WithPrefix("Missing end for rule ", strconv.Quote((*current).name))
+		}
+		name := args
+		if unquoted, err := strconv.Unquote(args); err == nil {
+			name = unquoted
+		}
+		if name == "" {
+			return gopi.ErrBadParameter.WithPrefix("Missing rule name")
+		} else if names[name] {
+			return gopi.ErrDuplicateEntry.WithPrefix("Rule ", strconv.Quote(name))
+		}
+		names[name] = true
+		*current = &rule{name: name}
+		if keyword == "alert" {
+			(*current).alert = &alert{}
+		}
+		return nil
+	} else if *current == nil {
+		return gopi.ErrBadParameter.WithPrefix("Expected rule, got ", strconv.Quote(keyword))
+	}
+
+	r := *current
+	if r.alert != nil {
+		return parseAlertLine(r, keyword, args, rules, current)
+	}
+	switch keyword {
+	case "on":
+		t, err := parseTrigger(args)
+		if err != nil {
+			return err
+		}
+		r.triggers = append(r.triggers, t)
+	case "if":
+		x, err := parseExpr(args)
+		if err != nil {
+			return err
+		}
+		r.conditions = append(r.conditions, x)
+	case "do":
+		a, err := parseAction(args)
+		if err != nil {
+			return err
+		}
+		r.actions = append(r.actions, a)
+	case "end":
+		if len(r.triggers) == 0 || len(r.actions) == 0 {
+			return gopi.ErrBadParameter.WithPrefix("Rule ", strconv.Quote(r.name), " needs triggers and actions")
+		}
+		*rules = append(*rules, r)
+		*current = nil
+	default:
+		return gopi.ErrBadParameter.WithPrefix("Unexpected ", strconv.Quote(keyword))
+	}
+
+	// Return success
+	return nil
+}
+
+func parseAlertLine(r *rule, keyword, args string, rules *[]*rule, current **rule) error {
+	switch keyword {
+	case "when":
+		if r.alert.source != "" {
+			return gopi.ErrDuplicateEntry.WithPrefix("Alert ", strconv.Quote(r.name), ": when")
+		} else if a, err := parseAlert(args); err != nil {
+			return err
+		} else {
+			r.alert = a
+		}
+	case "severity":
+		if args == "" || strings.IndexFunc(args, isSpace) >= 0 {
+			return gopi.ErrBadParameter.WithPrefix("Invalid severity ", strconv.Quote(args))
+		}
+		r.severity = args
+	case "if":
+		x, err := parseExpr(args)
+		if err != nil {
+			return err
+		}
+		r.conditions = append(r.conditions, x)
+	case "do", "resolve":
+		a, err := parseAction(args)
+		if err != nil {
+			return err
+		} else if keyword == "do" {
+			r.actions = append(r.actions, a)
+		} else {
+			r.resolves = append(r.resolves, a)
+		}
+	case "end":
+		if r.alert.source == "" || len(r.actions)+len(r.resolves) == 0 {
+			return gopi.ErrBadParameter.WithPrefix("Alert ", strconv.Quote(r.name), " needs when and actions")
+		}
+		*rules = append(*rules, r)
+		*current = nil
+	default:
+		return gopi.ErrBadParameter.WithPrefix("Unexpected ", strconv.Quote(keyword))
+	}
+
+	// Return success
+	return nil
+}
+
+// parseTrigger parses one of "event <glob>", "threshold <glob> <expr>",
+// "every <duration>" or "at <hh:mm>"
+func parseTrigger(src string) (*trigger, error) {
+	fields := strings.Fields(src)
+	if len(fields) < 2 {
+		return nil, gopi.ErrBadParameter.WithPrefix("Invalid trigger ", strconv.Quote(src))
+	}
+	t := &trigger{source: strings.Join(fields, " ")}
+	switch fields[0] {
+	case "event":
+		if len(fields) != 2 {
+			return nil, gopi.ErrBadParameter.WithPrefix("Invalid trigger ", strconv.Quote(src))
+		} else if _, err := path.Match(fields[1], ""); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix("Invalid pattern ", strconv.Quote(fields[1]))
+		}
+		t.kind, t.glob = TRIGGER_EVENT, fields[1]
+	case "threshold":
+		if len(fields) < 3 {
+			return nil, gopi.ErrBadParameter.WithPrefix("Invalid trigger ", strconv.Quote(src))
+		} else if _, err := path.Match(fields[1], ""); err != nil {
+			return nil, gopi.ErrBadParameter.WithPrefix("Invalid pattern ", strconv.Quote(fields[1]))
+		} else if x, err := parseExpr(strings.Join(fields[2:], " ")); err != nil {
+			return nil, err
+		} else {
+			t.kind, t.glob, t.expr = TRIGGER_THRESHOLD, fields[1], x
+		}
+	case "every":
+		if d, err := time.ParseDuration(fields[1]); err != nil || d < time.Second || len(fields) != 2 {
+			return nil, gopi.ErrBadParameter.WithPrefix("Invalid duration ", strconv.Quote(fields[1]))
+		} else {
+			t.kind, t.every = TRIGGER_EVERY, d
+		}
+	case "at":
+		if at, err := time.Parse("15:04", fields[1]); err != nil || len(fields) != 2 {
+			return nil, gopi.ErrBadParameter.WithPrefix("Invalid time ", strconv.Quote(fields[1]))
+		} else {
+			t.kind, t.at = TRIGGER_AT, time.Duration(at.Hour())*time.Hour+time.Duration(at.Minute())*time.Minute
+		}
+	default:
+		return nil, gopi.ErrBadParameter.WithPrefix("Invalid trigger ", strconv.Quote(src))
+	}
+
+	// Return success
+	return t, nil
+}
+
+// parseAction parses an action of the form name(arg, arg, ...)
+func parseAction(src string) (*action, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	a := &action{}
+	if t := p.next(); t.kind != tokenIdent {
+		return nil, gopi.ErrBadParameter.WithPrefix("Invalid action ", strconv.Quote(src))
+	} else if p.accept("(") == false {
+		return nil, gopi.ErrBadParameter.WithPrefix("Missing ( in action ", strconv.Quote(src))
+	} else {
+		a.name = t.value
+	}
+	for p.accept(")") == false {
+		if len(a.args) > 0 && p.accept(",") == false {
+			return nil, gopi.ErrBadParameter.WithPrefix("Missing , or ) in action ", strconv.Quote(src))
+		}
+		x, err := p.expr(0)
+		if err != nil {
+			return nil, err
+		}
+		a.args = append(a.args, x)
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, gopi.ErrBadParameter.WithPrefix("Unexpected ", strconv.Quote(t.value))
+	}
+
+	// Return success
+	return a, nil
+}
+
+func isSpace(c rune) bool {
+	return c == ' ' || c == '\t'
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TRIGGERS
+
+// Start sets the next time for schedule triggers
+func (this *trigger) Start(now time.Time) {
+	switch this.kind {
+	case TRIGGER_EVERY:
+		this.next = now.Add(this.every)
+	case TRIGGER_AT:
+		y, m, d := now.Date()
+		this.next = time.Date(y, m, d, 0, 0, 0, 0, now.Location()).Add(this.at)
+		if this.next.After(now) == false {
+			this.next = this.next.AddDate(0, 0, 1)
+		}
+	}
+}
+
+// Tick returns true if a schedule trigger fires, and sets the next time
+func (this *trigger) Tick(now time.Time) bool {
+	if this.kind != TRIGGER_EVERY && this.kind != TRIGGER_AT {
+		return false
+	} else if now.Before(this.next) {
+		return false
+	}
+	for this.next.After(now) == false {
+		if this.kind == TRIGGER_EVERY {
+			this.next = this.next.Add(this.every)
+		} else {
+			this.next = this.next.AddDate(0, 0, 1)
+		}
+	}
+	return true
+}
+
+// Match returns true if an event trigger matches the event name, or a
+// threshold trigger expression changes from false to true
+func (this *trigger) Match(name string, v vars) (bool, error) {
+	if this.kind != TRIGGER_EVENT && this.kind != TRIGGER_THRESHOLD {
+		return false, nil
+	} else if match, _ := path.Match(this.glob, name); match == false {
+		return false, nil
+	} else if this.kind == TRIGGER_EVENT {
+		return true, nil
+	}
+	value, err := this.expr.Eval(v)
+	if err != nil {
+		return false, err
+	}
+	state := truth(value)
+	fire := state && this.state == false
+	this.state = state
+	return fire, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RULES
+
+// Test returns true if all conditions are true
+func (this *rule) Test(v vars) (bool, error) {
+	for _, x := range this.conditions {
+		if value, err := x.Eval(v); err != nil {
+			return false, err
+		} else if truth(value) == false {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Args evaluates the arguments for an action
+func (this *action) Args(v vars) ([]interface{}, error) {
+	result := make([]interface{}, len(this.args))
+	for i, x := range this.args {
+		if value, err := x.Eval(v); err != nil {
+			return nil, err
+		} else {
+			result[i] = value
+		}
+	}
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *rule) String() string {
+	str := "<rule"
+	str += fmt.Sprintf(" name=%q", this.name)
+	for _, t := range this.triggers {
+		str += fmt.Sprintf(" on=%q", t.source)
+	}
+	if this.alert != nil {
+		str += " " + fmt.Sprint(this.alert)
+	}
+	if this.severity != "" {
+		str += fmt.Sprintf(" severity=%q", this.severity)
+	}
+	str += " conditions=" + fmt.Sprint(len(this.conditions))
+	for _, a := range this.actions {
+		str += " do=" + a.name
+	}
+	for _, a := range this.resolves {
+		str += " resolve=" + a.name
+	}
+	return str + ">"
+}
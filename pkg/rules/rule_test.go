@@ -0,0 +1,298 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Expr_001(t *testing.T) {
+	v := vars{"temperature": 31.5, "name": "sensor", "enabled": true}
+	tests := []struct {
+		src    string
+		result interface{}
+	}{
+		{"1 + 2 * 3", 7.0},
+		{"(1 + 2) * 3", 9.0},
+		{"-2 - -3", 1.0},
+		{"10 / 4", 2.5},
+		{"temperature > 30", true},
+		{"temperature > 30 && name == \"sensor\"", true},
+		{"temperature < 30 || !enabled", false},
+		{"missing > 30", false},
+		{"missing == \"\"", false},
+		{"!missing", true},
+		{"\"a\" < \"b\"", true},
+		{"name + \"-\" + 1", "sensor-1"},
+		{"true != false", true},
+	}
+	for _, test := range tests {
+		if x, err := parseExpr(test.src); err != nil {
+			t.Error(test.src, err)
+		} else if result, err := x.Eval(v); err != nil {
+			t.Error(test.src, err)
+		} else if result != test.result {
+			t.Errorf("%v: Expected %v, got %v", test.src, test.result, result)
+		}
+	}
+}
+
+func Test_Expr_002(t *testing.T) {
+	for _, src := range []string{"", "1 +", "(1", "1 2", "\"abc", "a $ b", "1..2"} {
+		if _, err := parseExpr(src); err == nil {
+			t.Errorf("%q: Expected parse error", src)
+		}
+	}
+	for _, src := range []string{"1 / 0", "\"a\" * 2", "-\"a\""} {
+		if x, err := parseExpr(src); err != nil {
+			t.Error(src, err)
+		} else if _, err := x.Eval(nil); err == nil {
+			t.Errorf("%q: Expected evaluation error", src)
+		}
+	}
+}
+
+func Test_Rule_001(t *testing.T) {
+	rules, err := parseRules(strings.NewReader(`
+		# Comment
+		rule "porch light"
+			on at 18:30
+			on every 1h
+			if weekday != "sat"
+			do gpio.write(17, "high")
+			do log("on", hour)
+		end
+
+		rule heat
+			on threshold sensor* temperature > 30
+			do log()
+		end
+	`))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(rules) != 2 {
+		t.Fatal("Expected two rules, got", len(rules))
+	}
+	if r := rules[0]; r.name != "porch light" || len(r.triggers) != 2 || len(r.conditions) != 1 || len(r.actions) != 2 {
+		t.Error("Unexpected rule", r)
+	} else if r.actions[0].name != "gpio.write" || len(r.actions[0].args) != 2 {
+		t.Error("Unexpected action", r.actions[0])
+	} else if r.triggers[0].at != 18*time.Hour+30*time.Minute || r.triggers[1].every != time.Hour {
+		t.Error("Unexpected triggers", r)
+	}
+	if r := rules[1]; r.name != "heat" || r.triggers[0].kind != TRIGGER_THRESHOLD || r.triggers[0].glob != "sensor*" || len(r.actions[0].args) != 0 {
+		t.Error("Unexpected rule", r)
+	}
+}
+
+func Test_Rule_002(t *testing.T) {
+	for _, src := range []string{
+		"on event x",
+		"rule a\ndo log()\nend",
+		"rule a\non event x\nend",
+		"rule a\non event x\ndo log()",
+		"rule a\non every 1x\ndo log()\nend",
+		"rule a\non at 25:00\ndo log()\nend",
+		"rule a\non event [\ndo log()\nend",
+		"rule a\non event x\ndo log(\nend",
+		"rule a\non event x\ndo log\nend",
+		"rule a\non event x\ndo log()\nend\nrule a\non event x\ndo log()\nend",
+		"rule a\non event x\nunless y\ndo log()\nend",
+	} {
+		if _, err := parseRules(strings.NewReader(src)); err == nil {
+			t.Errorf("%q: Expected error", src)
+		}
+	}
+}
+
+func Test_Rule_003(t *testing.T) {
+	// Schedule triggers
+	now := time.Date(2020, 6, 1, 18, 0, 0, 0, time.Local)
+	at := &trigger{kind: TRIGGER_AT, at: 18*time.Hour + 30*time.Minute}
+	every := &trigger{kind: TRIGGER_EVERY, every: 10 * time.Minute}
+	at.Start(now)
+	every.Start(now)
+	if at.Tick(now.Add(29*time.Minute)) || every.Tick(now.Add(9*time.Minute)) {
+		t.Error("Unexpected tick")
+	} else if at.Tick(now.Add(30*time.Minute)) == false || every.Tick(now.Add(25*time.Minute)) == false {
+		t.Error("Expected tick")
+	} else if at.Tick(now.Add(31*time.Minute)) || every.Tick(now.Add(29*time.Minute)) {
+		t.Error("Unexpected tick after firing")
+	} else if at.next.Day() != 2 || every.next.Sub(now) != 30*time.Minute {
+		t.Error("Unexpected next", at.next, every.next)
+	}
+}
+
+func Test_Rule_004(t *testing.T) {
+	// Threshold triggers fire when the expression becomes true
+	x, _ := parseExpr("temperature > 30")
+	threshold := &trigger{kind: TRIGGER_THRESHOLD, glob: "sensor", expr: x}
+	for i, test := range []struct {
+		name        string
+		temperature float64
+		fire        bool
+	}{
+		{"sensor", 25, false},
+		{"sensor", 31, true},
+		{"sensor", 32, false},
+		{"other", 20, false},
+		{"sensor", 29, false},
+		{"sensor", 35, true},
+	} {
+		if fire, err := threshold.Match(test.name, vars{"temperature": test.temperature}); err != nil {
+			t.Error(err)
+		} else if fire != test.fire {
+			t.Error(i, "Unexpected fire", fire)
+		}
+	}
+}
+
+func Test_Alert_001(t *testing.T) {
+	rules, err := parseRules(strings.NewReader(`
+		alert "too hot"
+			when sensor* temperature > 30 for 5m
+			severity critical
+			do log(alert, state, value)
+			resolve log(alert, state)
+		end
+		alert silent
+			when silent sensor for 10m
+			do gpio.write(18, true)
+		end
+		alert rising
+			when rate sensor temperature > 2 per 1m
+			do webhook("http://localhost/", alert)
+		end
+	`))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(rules) != 3 {
+		t.Fatal("Expected three alerts, got", len(rules))
+	}
+	if r := rules[0]; r.alert.kind != ALERT_THRESHOLD || r.alert.glob != "sensor*" || r.alert.duration != 5*time.Minute || r.severity != "critical" || len(r.resolves) != 1 {
+		t.Error("Unexpected alert", r)
+	}
+	if r := rules[1]; r.alert.kind != ALERT_SILENT || r.alert.glob != "sensor" || r.alert.duration != 10*time.Minute {
+		t.Error("Unexpected alert", r)
+	}
+	if r := rules[2]; r.alert.kind != ALERT_RATE || r.alert.variable != "temperature" || r.alert.per != time.Minute || r.alert.duration != 0 {
+		t.Error("Unexpected alert", r)
+	}
+	for _, src := range []string{
+		"alert a\ndo log()\nend",
+		"alert a\nwhen x y > 1\nend",
+		"alert a\nwhen x y > 1\nwhen x y > 2\ndo log()\nend",
+		"alert a\non event x\ndo log()\nend",
+		"rule a\nwhen x y > 1\ndo log()\nend",
+		"alert a\nwhen silent x\ndo log()\nend",
+		"alert a\nwhen rate x y > 1\ndo log()\nend",
+		"alert a\nwhen x y > 1 for 1x\ndo log()\nend",
+		"alert a\nwhen x y > 1\nseverity very high\ndo log()\nend",
+	} {
+		if _, err := parseRules(strings.NewReader(src)); err == nil {
+			t.Errorf("%q: Expected error", src)
+		}
+	}
+}
+
+func Test_Alert_002(t *testing.T) {
+	// Threshold alerts are pending until the condition is true for the
+	// duration, and resolve when the condition is false
+	now := time.Date(2020, 6, 1, 18, 0, 0, 0, time.Local)
+	a, err := parseAlert("sensor temperature > 30 for 1m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Start(now)
+	for i, test := range []struct {
+		at          time.Duration
+		temperature float64
+		state       gopi.AlertState
+		changed     bool
+	}{
+		{0, 25, gopi.ALERT_RESOLVED, false},
+		{10 * time.Second, 31, gopi.ALERT_PENDING, false},
+		{20 * time.Second, 29, gopi.ALERT_RESOLVED, false},
+		{30 * time.Second, 35, gopi.ALERT_PENDING, false},
+		{90 * time.Second, 35, gopi.ALERT_FIRING, true},
+		{100 * time.Second, 36, gopi.ALERT_FIRING, false},
+		{110 * time.Second, 20, gopi.ALERT_RESOLVED, true},
+	} {
+		if state, changed, err := a.Event("sensor", vars{"temperature": test.temperature}, now.Add(test.at)); err != nil {
+			t.Error(err)
+		} else if state != test.state || changed != test.changed {
+			t.Error(i, "Unexpected state", state, changed)
+		}
+	}
+
+	// Pending alerts fire on tick
+	a.Event("sensor", vars{"temperature": 40.0}, now.Add(2*time.Minute))
+	if _, changed := a.Tick(now.Add(150 * time.Second)); changed {
+		t.Error("Unexpected change")
+	} else if state, changed := a.Tick(now.Add(3 * time.Minute)); state != gopi.ALERT_FIRING || changed == false {
+		t.Error("Expected firing", state)
+	}
+}
+
+func Test_Alert_003(t *testing.T) {
+	// Silent alerts fire when no events are received for the duration
+	now := time.Date(2020, 6, 1, 18, 0, 0, 0, time.Local)
+	a, err := parseAlert("silent sensor for 10m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Start(now)
+	if _, changed := a.Tick(now.Add(5 * time.Minute)); changed {
+		t.Error("Unexpected change")
+	} else if state, changed := a.Tick(now.Add(10 * time.Minute)); state != gopi.ALERT_FIRING || changed == false {
+		t.Error("Expected firing", state)
+	} else if _, changed, _ := a.Event("other", nil, now.Add(11*time.Minute)); changed {
+		t.Error("Unexpected change")
+	} else if state, changed, _ := a.Event("sensor", nil, now.Add(12*time.Minute)); state != gopi.ALERT_RESOLVED || changed == false {
+		t.Error("Expected resolved", state)
+	} else if _, changed := a.Tick(now.Add(21 * time.Minute)); changed {
+		t.Error("Unexpected change")
+	}
+
+	// Rate alerts compare the change per duration
+	r, err := parseAlert("rate sensor temperature > 2 per 1m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Start(now)
+	for i, test := range []struct {
+		at          time.Duration
+		temperature float64
+		state       gopi.AlertState
+	}{
+		{0, 20, gopi.ALERT_RESOLVED},
+		{time.Minute, 21, gopi.ALERT_RESOLVED},
+		{90 * time.Second, 23, gopi.ALERT_FIRING},
+		{150 * time.Second, 23, gopi.ALERT_RESOLVED},
+	} {
+		if state, _, err := r.Event("sensor", vars{"temperature": test.temperature}, now.Add(test.at)); err != nil {
+			t.Error(err)
+		} else if state != test.state {
+			t.Error(i, "Unexpected state", state)
+		}
+	}
+	if a := r.Alert("rising", "warning"); a.Value != "0" || a.Severity != "warning" {
+		t.Error("Unexpected alert", a)
+	}
+}
+
+func Test_Vars_001(t *testing.T) {
+	v := eventVars(time.Date(2020, 6, 1, 18, 5, 0, 0, time.Local), &event{"rule", "every 1s", nil})
+	if v["name"] != "rule" || v["trigger"] != "every 1s" {
+		t.Error("Unexpected event variables", v)
+	} else if v["time"] != "18:05" || v["hour"] != 18.0 || v["weekday"] != "mon" {
+		t.Error("Unexpected time variables", v)
+	} else if _, exists := v["error"]; exists {
+		t.Error("Unexpected error variable", v)
+	}
+}
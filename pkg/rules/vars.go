@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	typeStringer = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	typeDuration = reflect.TypeOf(time.Duration(0))
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// VARIABLES
+
+// timeVars returns variables for the time of day
+func timeVars(now time.Time) vars {
+	return vars{
+		"time":    now.Format("15:04"),
+		"hour":    float64(now.Hour()),
+		"minute":  float64(now.Minute()),
+		"weekday": strings.ToLower(now.Weekday().String()[:3]),
+	}
+}
+
+// eventVars returns variables for the time and an event. The name of
+// the event is the variable "name", and methods of the event with no
+// arguments returning a number, boolean, string or value with a String
+// method become variables with the first letter in lowercase. Fields
+// of measurements are also added as variables
+func eventVars(now time.Time, evt gopi.Event) vars {
+	result := timeVars(now)
+	v := reflect.ValueOf(evt)
+	for i := 0; i < v.NumMethod(); i++ {
+		method := v.Type().Method(i)
+		if method.Type.NumIn() != 1 || method.Type.NumOut() != 1 || method.Name == "String" {
+			continue
+		}
+		if value, ok := toValue(method.Type.Out(0)); ok {
+			result[strings.ToLower(method.Name[:1])+method.Name[1:]] = value(v.Method(i).Call(nil)[0])
+		}
+	}
+	if measurement, ok := evt.(gopi.Measurement); ok {
+		for _, field := range append(measurement.Tags(), measurement.Metrics()...) {
+			if value, ok := toValue(reflect.TypeOf(field.Value())); ok {
+				result[field.Name()] = value(reflect.ValueOf(field.Value()))
+			}
+		}
+	}
+	result["name"] = evt.Name()
+	return result
+}
+
+// toValue returns a function which converts a value of a type to a
+// variable value, or false if the type cannot be converted
+func toValue(t reflect.Type) (func(reflect.Value) interface{}, bool) {
+	if t == nil {
+		return nil, false
+	} else if t == typeDuration {
+		return func(v reflect.Value) interface{} {
+			return v.Interface().(time.Duration).Seconds()
+		}, true
+	} else if t.Implements(typeStringer) && t.Kind() != reflect.Interface {
+		return func(v reflect.Value) interface{} {
+			return v.Interface().(fmt.Stringer).String()
+		}, true
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value) interface{} { return float64(v.Int()) }, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(v reflect.Value) interface{} { return float64(v.Uint()) }, true
+	case reflect.Float32, reflect.Float64:
+		return func(v reflect.Value) interface{} { return v.Float() }, true
+	case reflect.Bool:
+		return func(v reflect.Value) interface{} { return v.Bool() }, true
+	case reflect.String:
+		return func(v reflect.Value) interface{} { return v.String() }, true
+	}
+	return nil, false
+}
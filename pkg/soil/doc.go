@@ -0,0 +1,32 @@
+// Soil package implements gopi.SoilMoisture, which reads capacitive soil
+// moisture probes through the channels of a gopi.ADC unit. Probes are
+// read from a JSON file set with the -soil.probes flag, which maps a
+// probe name to an ADC channel, the irrigation zone it waters, the
+// moisture percentage below which the soil is dry, and the voltage of
+// the probe in dry and wet soil:
+//
+//	{
+//	  "lawn":   { "channel": 0, "zone": "lawn", "low": 30, "dry": 2.8, "wet": 1.2 },
+//	  "border": { "channel": 1, "zone": "border" }
+//	}
+//
+// When they are not set, the threshold is 30% and the calibration
+// points are 2.8V and 1.2V. Each probe is read every -soil.interval,
+// averaged over -soil.samples conversions. A probe is calibrated by
+// calling Calibrate with the probe in dry soil or in water, and the
+// points are stored in the -soil.file, which replaces those in the
+// probes file.
+//
+// A gopi.SoilEvent is emitted for each reading, and when the moisture
+// falls below the threshold or rises 5% above it. The irrigation unit
+// waters the zone for a probe when the soil is dry. When there is a
+// gopi.Metrics unit a measurement is emitted for each probe, named with
+// the -soil.measurement prefix, such as "soil_lawn". Rules can alert
+// on them, for example:
+//
+//	alert "dry_border"
+//	  when soil_border moisture < 20 for 1h
+//	  severity warning
+//	  do notify(severity, alert, state, value)
+//	end
+package soil
@@ -0,0 +1,44 @@
+package soil
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t     gopi.SoilEventType
+	probe gopi.SoilProbe
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func NewEvent(t gopi.SoilEventType, probe gopi.SoilProbe) gopi.SoilEvent {
+	return &event{t, probe}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.probe.Name
+}
+
+func (this *event) Type() gopi.SoilEventType {
+	return this.t
+}
+
+func (this *event) Probe() gopi.SoilProbe {
+	return this.probe
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	return fmt.Sprintf("<soil.event type=%v %v>", this.t, this.probe)
+}
@@ -0,0 +1,13 @@
+package soil
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.SoilMoisture
+	graph.RegisterUnit(reflect.TypeOf(&soil{}), reflect.TypeOf((*gopi.SoilMoisture)(nil)))
+}
@@ -0,0 +1,206 @@
+package soil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type probe struct {
+	name    string
+	channel uint
+	zone    string
+	low     float32 // Moisture percent below which the soil is dry
+	dry     float32 // Voltage in dry soil
+	wet     float32 // Voltage in wet soil
+
+	reading gopi.SoilProbe
+}
+
+// config is the entry for a probe in the probes file
+type config struct {
+	Channel uint    `json:"channel"`
+	Zone    string  `json:"zone"`
+	Low     float32 `json:"low"`
+	Dry     float32 `json:"dry"`
+	Wet     float32 `json:"wet"`
+}
+
+// calibrations are the dry and wet points of probes, keyed by probe
+// name, which are stored in a file and replace those in the probes file
+type calibrations map[string]calibration
+
+type calibration struct {
+	Dry  float32   `json:"dry"`
+	Wet  float32   `json:"wet"`
+	Time time.Time `json:"time"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Defaults for a capacitive probe powered at 3.3V
+	defaultLow = 30
+	defaultDry = 2.8
+	defaultWet = 1.2
+
+	// Moisture must rise this much above the threshold before the soil
+	// is no longer dry, so that a probe near the threshold does not
+	// emit an event on every reading
+	hysteresis = 5
+)
+
+var (
+	reName = regexp.MustCompile("^[A-Za-z][A-Za-z0-9_\\-]*$")
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newProbe(name string, cfg config) (*probe, error) {
+	this := &probe{name: name, channel: cfg.Channel, zone: strings.TrimSpace(cfg.Zone), low: cfg.Low, dry: cfg.Dry, wet: cfg.Wet}
+	if this.low == 0 {
+		this.low = defaultLow
+	}
+	if this.dry == 0 {
+		this.dry = defaultDry
+	}
+	if this.wet == 0 {
+		this.wet = defaultWet
+	}
+	if this.low < 0 || this.low > 100 {
+		return nil, gopi.ErrBadParameter.WithPrefix(name, ": low ", this.low)
+	} else if this.dry == this.wet {
+		return nil, gopi.ErrBadParameter.WithPrefix(name, ": dry and wet are the same")
+	}
+	this.reading = gopi.SoilProbe{Name: name, Zone: this.zone}
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Moisture returns the percentage between the dry and wet points for a
+// voltage. Capacitive probes read a lower voltage in wet soil, but the
+// points can be either way round
+func (this *probe) Moisture(voltage float32) float32 {
+	if this.dry == this.wet {
+		return 0
+	}
+	moisture := (this.dry - voltage) / (this.dry - this.wet) * 100
+	if moisture < 0 {
+		return 0
+	} else if moisture > 100 {
+		return 100
+	} else {
+		return moisture
+	}
+}
+
+// Set updates the reading from a voltage, and returns true when the
+// soil has become dry or is no longer dry
+func (this *probe) Set(now time.Time, voltage float32) bool {
+	moisture := this.Moisture(voltage)
+	low := this.reading.Low
+	if moisture < this.low {
+		low = true
+	} else if moisture >= this.low+hysteresis {
+		low = false
+	}
+	changed := low != this.reading.Low
+	this.reading.Time, this.reading.Voltage, this.reading.Moisture, this.reading.Low = now, voltage, moisture, low
+	return changed
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *probe) String() string {
+	str := "<soil.probe"
+	str += fmt.Sprintf(" name=%q channel=%v", this.name, this.channel)
+	if this.zone != "" {
+		str += fmt.Sprintf(" zone=%q", this.zone)
+	}
+	str += fmt.Sprint(" low=", this.low, " dry=", this.dry, " wet=", this.wet)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// readProbes returns probes from a JSON file
+func readProbes(path string) (map[string]*probe, error) {
+	var probes map[string]config
+	if data, err := ioutil.ReadFile(path); err != nil {
+		return nil, err
+	} else if err := json.Unmarshal(data, &probes); err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	result := make(map[string]*probe, len(probes))
+	for name, cfg := range probes {
+		if name = strings.TrimSpace(name); reName.MatchString(name) == false {
+			return nil, gopi.ErrBadParameter.WithPrefix(path, ": name ", strconv.Quote(name))
+		} else if probe, err := newProbe(name, cfg); err != nil {
+			return nil, err
+		} else {
+			result[name] = probe
+		}
+	}
+	if len(result) == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix(path, ": No probes")
+	}
+	return result, nil
+}
+
+// readCalibrations reads calibration points from a file, which may not
+// exist
+func readCalibrations(path string) (calibrations, error) {
+	result := make(calibrations)
+	if path == "" {
+		return result, nil
+	} else if _, err := os.Stat(path); os.IsNotExist(err) {
+		return result, nil
+	} else if fh, err := os.Open(path); err != nil {
+		return nil, err
+	} else {
+		defer fh.Close()
+		if err := json.NewDecoder(fh).Decode(&result); err != nil {
+			return nil, err
+		}
+	}
+
+	// Return success
+	return result, nil
+}
+
+// Write stores calibration points in a file, replacing it atomically
+func (this calibrations) Write(path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if fh, err := os.Create(tmp); err != nil {
+		return err
+	} else if err := json.NewEncoder(fh).Encode(this); err != nil {
+		fh.Close()
+		return err
+	} else if err := fh.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,271 @@
+package soil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type soil struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Metrics
+	gopi.ADC
+	sync.RWMutex
+
+	// Flags
+	path     *string
+	file     *string
+	interval *time.Duration
+	samples  *uint
+
+	lock         sync.Mutex // Serializes reading probes
+	probes       map[string]*probe
+	calibrations calibrations
+	measurements map[string]string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *soil) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagPath("soil.probes", "", "JSON file of soil moisture probes")
+	this.file = cfg.FlagPath("soil.file", "", "File to store probe calibration")
+	this.interval = cfg.FlagDuration("soil.interval", time.Minute, "Interval between readings")
+	this.samples = cfg.FlagUint("soil.samples", 4, "Number of samples averaged for each reading")
+	cfg.FlagString("soil.measurement", "soil", "Measurement name prefix")
+	return nil
+}
+
+func (this *soil) New(cfg gopi.Config) error {
+	this.Require(this.Logger, this.ADC)
+
+	// Check parameters
+	if *this.interval <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-soil.interval")
+	} else if *this.samples == 0 {
+		return gopi.ErrBadParameter.WithPrefix("-soil.samples")
+	}
+
+	// Read probes
+	if *this.path == "" {
+		return gopi.ErrBadParameter.WithPrefix("-soil.probes")
+	} else if probes, err := readProbes(*this.path); err != nil {
+		return err
+	} else {
+		this.probes = probes
+	}
+	for _, probe := range this.probes {
+		if probe.channel >= this.ADC.Channels() {
+			return gopi.ErrBadParameter.WithPrefix(probe.name, ": channel ", probe.channel)
+		}
+	}
+
+	// Read calibrations, which replace the points in the probes file
+	if calibrations, err := readCalibrations(*this.file); err != nil {
+		return fmt.Errorf("%v: %w", *this.file, err)
+	} else {
+		this.calibrations = calibrations
+	}
+	for name, c := range this.calibrations {
+		if probe, exists := this.probes[name]; exists && c.Dry != c.Wet {
+			probe.dry, probe.wet = c.Dry, c.Wet
+		}
+	}
+
+	// Define measurements
+	this.measurements = make(map[string]string)
+	if measurement := cfg.GetString("soil.measurement"); measurement != "" && this.Metrics != nil {
+		for _, name := range this.names() {
+			if m, err := this.Metrics.NewMeasurement(measurement+"_"+name, "voltage float32, moisture float32", this.Metrics.HostTag()); err != nil {
+				return err
+			} else {
+				this.measurements[name] = m.Name()
+			}
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *soil) Dispose() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	// Release resources
+	this.probes = nil
+	this.calibrations = nil
+	this.measurements = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *soil) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			this.read(ctx)
+			timer.Reset(*this.interval)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *soil) Probes() []gopi.SoilProbe {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	result := make([]gopi.SoilProbe, 0, len(this.probes))
+	for _, name := range this.names() {
+		result = append(result, this.probes[name].reading)
+	}
+	return result
+}
+
+func (this *soil) Calibrate(ctx context.Context, name string, wet bool) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	probe, exists := this.probes[name]
+	if exists == false {
+		return gopi.ErrNotFound.WithPrefix("Calibrate: ", strconv.Quote(name))
+	}
+	voltage, err := this.sample(ctx, probe)
+	if err != nil {
+		return err
+	}
+
+	// Set the calibration point, which must differ from the other point
+	other, point := probe.wet, "dry"
+	if wet {
+		other, point = probe.dry, "wet"
+	}
+	if voltage == other {
+		return gopi.ErrBadParameter.WithPrefix("Calibrate: ", strconv.Quote(name), " dry and wet are the same")
+	}
+	this.RWMutex.Lock()
+	if wet {
+		probe.wet = voltage
+	} else {
+		probe.dry = voltage
+	}
+	this.RWMutex.Unlock()
+
+	// Store the calibration
+	this.calibrations[name] = calibration{probe.dry, probe.wet, time.Now()}
+	if err := this.calibrations.Write(*this.file); err != nil {
+		return err
+	}
+
+	this.Print("Soil: Calibrated ", strconv.Quote(name), " ", point, " at ", voltage, "V")
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *soil) String() string {
+	str := "<soil"
+	if *this.file != "" {
+		str += fmt.Sprintf(" file=%q", *this.file)
+	}
+	for _, probe := range this.Probes() {
+		str += fmt.Sprint(" ", probe)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// read reads each probe, emits events and measurements, and emits an
+// event when the soil becomes dry or is no longer dry
+func (this *soil) read(ctx context.Context) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	now := time.Now()
+	for _, name := range this.names() {
+		probe := this.probes[name]
+		voltage, err := this.sample(ctx, probe)
+		if err != nil {
+			this.Print("Soil: ", name, ": ", err)
+			continue
+		}
+
+		// Set reading
+		this.RWMutex.Lock()
+		changed := probe.Set(now, voltage)
+		reading := probe.reading
+		this.RWMutex.Unlock()
+
+		// Emit events and measurement
+		this.emit(NewEvent(gopi.SOIL_EVENT_READING, reading))
+		if measurement, exists := this.measurements[name]; exists {
+			if err := this.Metrics.Emit(measurement, nil, reading.Voltage, reading.Moisture); err != nil {
+				this.Debug("Soil: ", err)
+			}
+		}
+		if changed && reading.Low {
+			this.Print("Soil: ", strconv.Quote(name), " is dry at ", reading.Moisture, "%")
+			this.emit(NewEvent(gopi.SOIL_EVENT_LOW, reading))
+		} else if changed {
+			this.Print("Soil: ", strconv.Quote(name), " is no longer dry at ", reading.Moisture, "%")
+			this.emit(NewEvent(gopi.SOIL_EVENT_OK, reading))
+		}
+	}
+}
+
+// sample returns the average voltage of a probe over several readings
+func (this *soil) sample(ctx context.Context, probe *probe) (float32, error) {
+	var total float32
+	for i := uint(0); i < *this.samples; i++ {
+		if voltage, err := this.ADC.Read(ctx, probe.channel); err != nil {
+			return 0, err
+		} else {
+			total += voltage
+		}
+	}
+	return total / float32(*this.samples), nil
+}
+
+func (this *soil) emit(evt gopi.Event) {
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(evt, false); err != nil {
+			this.Debug("Soil: ", err)
+		}
+	}
+}
+
+// names returns probe names in order
+func (this *soil) names() []string {
+	names := make([]string, 0, len(this.probes))
+	for name := range this.probes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,214 @@
+package soil_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/soil"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.SoilMoisture
+	gopi.ADC
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// adc returns a voltage for each channel which is set by a test
+type adc struct {
+	gopi.Unit
+	sync.Mutex
+	voltages [4]float32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&adc{}), reflect.TypeOf((*gopi.ADC)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ADC
+
+func (this *adc) New(gopi.Config) error {
+	this.voltages = [4]float32{2.0, 2.0, 2.0, 2.0}
+	return nil
+}
+
+func (this *adc) Channels() uint {
+	return uint(len(this.voltages))
+}
+
+func (this *adc) Read(_ context.Context, channel uint) (float32, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if channel >= uint(len(this.voltages)) {
+		return 0, gopi.ErrBadParameter
+	}
+	return this.voltages[channel], nil
+}
+
+func (this *adc) Set(channel uint, voltage float32) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.voltages[channel] = voltage
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+const probes = `{
+	"lawn": { "channel": 0, "zone": "lawn" },
+	"border": { "channel": 2, "low": 40, "dry": 3.0, "wet": 1.0 }
+}`
+
+func Test_Soil_001(t *testing.T) {
+	args := []string{"-soil.probes", testutil.TempFile(t, "probes.json", probes), "-soil.interval", "50ms"}
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Half way between the dry and wet points
+		if evt, _ := testutil.NextType(ch, gopi.SOIL_EVENT_READING).(gopi.SoilEvent); evt == nil {
+			t.Fatal("Expected reading")
+		}
+		probes := app.SoilMoisture.Probes()
+		if len(probes) != 2 {
+			t.Fatal("Unexpected probes", probes)
+		} else if probes[0].Name != "border" || near(probes[0].Moisture, 50) == false || probes[0].Low {
+			t.Error("Unexpected probe", probes[0])
+		} else if probes[1].Name != "lawn" || probes[1].Zone != "lawn" || near(probes[1].Moisture, 50) == false || probes[1].Low {
+			t.Error("Unexpected probe", probes[1])
+		}
+
+		// The lawn dries out, and moisture must rise above the threshold
+		// by the hysteresis before the lawn is no longer dry
+		converter := app.ADC.(*adc)
+		scenario := tool.NewScenario().
+			At(0, "lawn dries out", func() error {
+				converter.Set(0, 2.6)
+				return nil
+			}).
+			ExpectEvent(300*time.Millisecond, "lawn is low", func(evt gopi.Event) bool {
+				if testutil.IsType(gopi.SOIL_EVENT_LOW)(evt) == false {
+					return false
+				}
+				probe := evt.(gopi.SoilEvent).Probe()
+				return probe.Name == "lawn" && probe.Zone == "lawn" && probe.Low && near(probe.Moisture, 12.5)
+			}).
+			At(300*time.Millisecond, "lawn is watered to just above the threshold", func() error {
+				converter.Set(0, 2.3)
+				return nil
+			}).
+			At(600*time.Millisecond, "lawn is watered", func() error {
+				converter.Set(0, 1.2)
+				return nil
+			}).
+			ExpectEvent(900*time.Millisecond, "lawn is ok", func(evt gopi.Event) bool {
+				if testutil.IsType(gopi.SOIL_EVENT_OK)(evt) == false {
+					return false
+				}
+				probe := evt.(gopi.SoilEvent).Probe()
+				return probe.Name == "lawn" && probe.Low == false && near(probe.Moisture, 100)
+			})
+		scenario.Test(t, app.Publisher)
+		for _, evt := range scenario.Events() {
+			if testutil.IsType(gopi.SOIL_EVENT_OK)(evt.Event) && evt.At < 600*time.Millisecond {
+				t.Error("Unexpected event", evt)
+			}
+		}
+		t.Log(app.SoilMoisture)
+	})
+}
+
+func Test_Soil_002(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "soil.json")
+	args := []string{"-soil.probes", testutil.TempFile(t, "probes.json", probes), "-soil.file", file, "-soil.interval", "50ms"}
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Calibrate the lawn probe
+		app.ADC.(*adc).Set(0, 2.5)
+		if err := app.SoilMoisture.Calibrate(context.Background(), "lawn", false); err != nil {
+			t.Fatal(err)
+		}
+		app.ADC.(*adc).Set(0, 1.5)
+		if err := app.SoilMoisture.Calibrate(context.Background(), "lawn", true); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.SoilMoisture.Calibrate(context.Background(), "lawn", false); err == nil {
+			t.Error("Expected error when dry and wet are the same")
+		}
+		if err := app.SoilMoisture.Calibrate(context.Background(), "patio", false); err == nil {
+			t.Error("Expected error for unknown probe")
+		}
+
+		// Read the stored calibration
+		var calibrations map[string]struct {
+			Dry float32 `json:"dry"`
+			Wet float32 `json:"wet"`
+		}
+		if data, err := ioutil.ReadFile(file); err != nil {
+			t.Fatal(err)
+		} else if err := json.Unmarshal(data, &calibrations); err != nil {
+			t.Fatal(err)
+		} else if c := calibrations["lawn"]; c.Dry != 2.5 || c.Wet != 1.5 {
+			t.Error("Unexpected calibration", calibrations)
+		}
+
+		// Moisture is between the calibration points
+		app.ADC.(*adc).Set(0, 2.0)
+		if evt, _ := testutil.NextType(ch, gopi.SOIL_EVENT_READING).(gopi.SoilEvent); evt == nil {
+			t.Fatal("Expected reading")
+		} else if evt, _ := testutil.NextType(ch, gopi.SOIL_EVENT_READING).(gopi.SoilEvent); evt == nil {
+			t.Fatal("Expected reading")
+		} else if probes := app.SoilMoisture.Probes(); near(probes[1].Moisture, 50) == false {
+			t.Error("Unexpected probe", probes[1])
+		}
+	})
+
+	// The calibration is restored
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		app.ADC.(*adc).Set(0, 1.75)
+		if evt, _ := testutil.NextType(ch, gopi.SOIL_EVENT_READING).(gopi.SoilEvent); evt == nil {
+			t.Fatal("Expected reading")
+		} else if evt, _ := testutil.NextType(ch, gopi.SOIL_EVENT_READING).(gopi.SoilEvent); evt == nil {
+			t.Fatal("Expected reading")
+		} else if probes := app.SoilMoisture.Probes(); near(probes[1].Moisture, 75) == false {
+			t.Error("Unexpected probe", probes[1])
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// near returns true when moisture is within rounding of a value
+func near(moisture, value float32) bool {
+	return moisture > value-0.01 && moisture < value+0.01
+}
@@ -0,0 +1,4 @@
+// Speech package queues text to be spoken, rendering it with a
+// speech engine and playing it on the ALSA output. Import an engine
+// package such as speech/espeak or speech/remote to provide the engine.
+package speech
@@ -0,0 +1,3 @@
+// Espeak package provides a speech engine using the espeak-ng
+// library. You will need to use -tags espeak when building.
+package espeak
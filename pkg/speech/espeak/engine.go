@@ -0,0 +1,94 @@
+// +build espeak
+
+package espeak
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	espeak "github.com/djthorpe/gopi/v3/pkg/sys/espeak"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type engine struct {
+	gopi.Unit
+	gopi.Logger
+	sync.Mutex
+
+	voice *string
+	rate  *uint
+	data  *string
+
+	samplerate uint
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *engine) Define(cfg gopi.Config) error {
+	this.voice = cfg.FlagString("espeak.voice", "en", "Voice name or language")
+	this.rate = cfg.FlagUint("espeak.rate", 175, "Speaking rate in words per minute")
+	this.data = cfg.FlagString("espeak.data", "", "Path to espeak-ng-data folder")
+	return nil
+}
+
+func (this *engine) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	if rate, err := espeak.Initialize(*this.data); err != nil {
+		return err
+	} else {
+		this.samplerate = rate
+	}
+	if err := espeak.SetVoice(*this.voice); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-espeak.voice: ", *this.voice, ": ", err)
+	} else if err := espeak.SetParameter(espeak.PARAM_RATE, int(*this.rate)); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-espeak.rate: ", err)
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *engine) Dispose() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return espeak.Terminate()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *engine) String() string {
+	str := "<speech.espeak"
+	str += fmt.Sprintf(" voice=%q", *this.voice)
+	str += " rate=" + fmt.Sprint(*this.rate)
+	str += " samplerate=" + fmt.Sprint(this.samplerate)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *engine) Synthesize(ctx context.Context, text string) ([]int16, uint, error) {
+	// The library renders one text at a time
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	var samples []int16
+	if err := espeak.Synthesize(text, func(data []int16) bool {
+		samples = append(samples, data...)
+		return ctx.Err() == nil
+	}); err != nil {
+		return nil, 0, err
+	} else if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Return success
+	return samples, this.samplerate, nil
+}
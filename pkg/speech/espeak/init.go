@@ -0,0 +1,15 @@
+// +build espeak
+
+package espeak
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register engine
+	graph.RegisterUnit(reflect.TypeOf(&engine{}), reflect.TypeOf((*gopi.SpeechEngine)(nil)))
+}
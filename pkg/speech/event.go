@@ -0,0 +1,61 @@
+package speech
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	id    gopi.SpeechId
+	text  string
+	state gopi.SpeechState
+	err   error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(id gopi.SpeechId, text string, state gopi.SpeechState, err error) gopi.SpeechEvent {
+	return &event{id, text, state, err}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "speech"
+}
+
+func (this *event) Id() gopi.SpeechId {
+	return this.id
+}
+
+func (this *event) Text() string {
+	return this.text
+}
+
+func (this *event) State() gopi.SpeechState {
+	return this.state
+}
+
+func (this *event) Error() error {
+	return this.err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<event.speech"
+	str += " id=" + fmt.Sprint(this.id)
+	str += " state=" + fmt.Sprint(this.state)
+	str += fmt.Sprintf(" text=%q", this.text)
+	if this.err != nil {
+		str += " err=" + fmt.Sprint(this.err)
+	}
+	return str + ">"
+}
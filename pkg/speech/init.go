@@ -0,0 +1,13 @@
+package speech
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register speech
+	graph.RegisterUnit(reflect.TypeOf(&speech{}), reflect.TypeOf((*gopi.Speech)(nil)))
+}
@@ -0,0 +1,43 @@
+package speech
+
+import (
+	"context"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// playNull waits for the duration of the samples
+func playNull(ctx context.Context, samples []int16, rate uint) error {
+	timer := time.NewTimer(time.Duration(len(samples)) * time.Second / time.Duration(rate))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// resample converts mono samples to interleaved samples with a
+// number of channels and sample rate using linear interpolation
+func resample(samples []int16, from, to, channels uint) []int16 {
+	if len(samples) == 0 {
+		return nil
+	}
+	frames := uint(len(samples)) * to / from
+	result := make([]int16, 0, frames*channels)
+	for i := uint(0); i < frames; i++ {
+		pos := i * from
+		j, frac := pos/to, int32(pos%to)
+		s := int32(samples[j])
+		if j+1 < uint(len(samples)) {
+			s += (int32(samples[j+1]) - s) * frac / int32(to)
+		}
+		for c := uint(0); c < channels; c++ {
+			result = append(result, int16(s))
+		}
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+// +build linux
+
+package speech
+
+import (
+	"context"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	linux "github.com/djthorpe/gopi/v3/pkg/sys/linux"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// Output formats to try when the device does not support the
+	// sample rate and channels of the speech engine
+	formats = []struct {
+		rate, channels uint
+	}{
+		{0, 1}, {0, 2}, {48000, 2}, {44100, 2},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// playDevice plays mono samples on an ALSA device, blocking until
+// the samples have been played or the context is cancelled
+func playDevice(ctx context.Context, device string, samples []int16, rate uint) error {
+	card, dev, err := linux.PCMParseDevice(device)
+	if err != nil {
+		return err
+	}
+	fh, err := linux.PCMOpen(card, dev)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	// Set parameters, trying other formats when the device does not
+	// support the sample rate and channels
+	var result error
+	var channels uint
+	for _, format := range formats {
+		r := format.rate
+		if r == 0 {
+			r = rate
+		}
+		if err := linux.PCMSetParams(fh.Fd(), r, format.channels); err != nil {
+			result = multierror.Append(result, err)
+			continue
+		}
+		if r != rate || format.channels != 1 {
+			samples = resample(samples, rate, r, format.channels)
+		}
+		channels, result = format.channels, nil
+		break
+	}
+	if result != nil {
+		return gopi.ErrNotImplemented.WithPrefix(device, ": ", result)
+	}
+
+	// Stop playback when the context is cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			linux.PCMDrop(fh.Fd())
+		case <-done:
+		}
+	}()
+
+	// Write samples and wait for them to be played
+	if err := linux.PCMWrite(fh.Fd(), samples, channels); err != nil {
+		return ctxErr(ctx, err)
+	} else if err := linux.PCMDrain(fh.Fd()); err != nil {
+		return ctxErr(ctx, err)
+	}
+
+	// Return any cancellation
+	return ctx.Err()
+}
+
+// ctxErr returns the context error when playback was stopped, since
+// the device returns an error when dropped
+func ctxErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	} else {
+		return err
+	}
+}
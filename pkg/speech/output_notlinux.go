@@ -0,0 +1,16 @@
+// +build !linux
+
+package speech
+
+import (
+	"context"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func playDevice(context.Context, string, []int16, uint) error {
+	return gopi.ErrNotImplemented
+}
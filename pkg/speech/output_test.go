@@ -0,0 +1,22 @@
+package speech
+
+import (
+	"testing"
+)
+
+func Test_Resample_001(t *testing.T) {
+	samples := []int16{0, 100, 200, 300}
+	if result := resample(samples, 8000, 8000, 2); len(result) != 8 {
+		t.Error("Unexpected length", result)
+	} else if result[2] != 100 || result[3] != 100 {
+		t.Error("Unexpected result", result)
+	}
+	if result := resample(samples, 8000, 16000, 1); len(result) != 8 {
+		t.Error("Unexpected length", result)
+	} else if result[1] != 50 || result[2] != 100 {
+		t.Error("Unexpected result", result)
+	}
+	if result := resample(nil, 8000, 16000, 1); result != nil {
+		t.Error("Unexpected result", result)
+	}
+}
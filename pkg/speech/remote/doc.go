@@ -0,0 +1,6 @@
+// Remote package provides a speech engine which renders text using
+// an HTTP service returning WAV audio, such as MaryTTS or a cloud
+// text-to-speech gateway. The -remote.url flag sets the service URL,
+// where {text} is replaced with the text to speak. When the URL does
+// not contain {text}, the text is sent as the body of a POST request.
+package remote
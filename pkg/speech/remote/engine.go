@@ -0,0 +1,98 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type engine struct {
+	gopi.Unit
+	gopi.Logger
+
+	url     *string
+	timeout *time.Duration
+	client  *http.Client
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	textPlaceholder = "{text}"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *engine) Define(cfg gopi.Config) error {
+	this.url = cfg.FlagString("remote.url", "", "Speech service URL, where {text} is replaced with text to speak")
+	this.timeout = cfg.FlagDuration("remote.timeout", 10*time.Second, "Speech service timeout")
+	return nil
+}
+
+func (this *engine) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	if *this.url == "" {
+		return gopi.ErrBadParameter.WithPrefix("-remote.url")
+	} else if u, err := url.Parse(*this.url); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-remote.url: ", err)
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return gopi.ErrBadParameter.WithPrefix("-remote.url: ", *this.url)
+	}
+
+	this.client = &http.Client{Timeout: *this.timeout}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *engine) String() string {
+	str := "<speech.remote"
+	str += fmt.Sprintf(" url=%q", *this.url)
+	str += " timeout=" + fmt.Sprint(*this.timeout)
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *engine) Synthesize(ctx context.Context, text string) ([]int16, uint, error) {
+	// Substitute text into the URL, or send as the request body
+	var req *http.Request
+	var err error
+	if strings.Contains(*this.url, textPlaceholder) {
+		req, err = http.NewRequest(http.MethodGet, strings.ReplaceAll(*this.url, textPlaceholder, url.QueryEscape(text)), nil)
+	} else if req, err = http.NewRequest(http.MethodPost, *this.url, strings.NewReader(text)); err == nil {
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "audio/wav")
+
+	// Perform the request
+	response, err := this.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, 0, gopi.ErrUnexpectedResponse.WithPrefix(*this.url, ": ", response.Status)
+	}
+
+	// Decode the response
+	return readWAV(response.Body)
+}
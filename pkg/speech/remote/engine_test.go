@@ -0,0 +1,103 @@
+package remote_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/speech/remote"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.SpeechEngine
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// wav returns a WAV file with samples and number of channels
+func wav(rate uint32, channels uint16, samples ...int16) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString("RIFF")
+	binary.Write(buf, binary.LittleEndian, uint32(36+len(samples)*2))
+	buf.WriteString("WAVEfmt ")
+	for _, v := range []interface{}{uint32(16), uint16(1), channels, rate, rate * uint32(channels) * 2, channels * 2, uint16(16)} {
+		binary.Write(buf, binary.LittleEndian, v)
+	}
+	buf.WriteString("data")
+	binary.Write(buf, binary.LittleEndian, uint32(len(samples)*2))
+	binary.Write(buf, binary.LittleEndian, samples)
+	return buf.Bytes()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Remote_001(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("text") != "hello world" {
+			http.Error(w, "Bad text", http.StatusBadRequest)
+		} else {
+			w.Write(wav(16000, 1, 1, 2, 3))
+		}
+	}))
+	defer server.Close()
+
+	tool.Test(t, []string{"-remote.url=" + server.URL + "/?text={text}"}, new(App), func(app *App) {
+		if samples, rate, err := app.SpeechEngine.Synthesize(context.Background(), "hello world"); err != nil {
+			t.Error(err)
+		} else if rate != 16000 {
+			t.Error("Unexpected rate", rate)
+		} else if len(samples) != 3 || samples[2] != 3 {
+			t.Error("Unexpected samples", samples)
+		}
+	})
+}
+
+func Test_Remote_002(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if body, _ := ioutil.ReadAll(req.Body); string(body) != "hello" || req.Method != http.MethodPost {
+			http.Error(w, "Bad request", http.StatusBadRequest)
+		} else {
+			w.Write(wav(22050, 2, 100, 200, -100, -300))
+		}
+	}))
+	defer server.Close()
+
+	tool.Test(t, []string{"-remote.url=" + server.URL}, new(App), func(app *App) {
+		if samples, rate, err := app.SpeechEngine.Synthesize(context.Background(), "hello"); err != nil {
+			t.Error(err)
+		} else if rate != 22050 {
+			t.Error("Unexpected rate", rate)
+		} else if len(samples) != 2 || samples[0] != 150 || samples[1] != -200 {
+			t.Error("Unexpected samples", samples)
+		}
+		if _, _, err := app.SpeechEngine.Synthesize(context.Background(), "goodbye"); err == nil {
+			t.Error("Expected error for bad request")
+		}
+	})
+}
+
+func Test_Remote_003(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("not a wav file"))
+	}))
+	defer server.Close()
+
+	tool.Test(t, []string{"-remote.url=" + server.URL}, new(App), func(app *App) {
+		if _, _, err := app.SpeechEngine.Synthesize(context.Background(), "hello"); err == nil {
+			t.Error("Expected error for bad response")
+		}
+	})
+}
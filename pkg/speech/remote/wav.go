@@ -0,0 +1,97 @@
+package remote
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type wavFormat struct {
+	Format        uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	wavFormatPCM = 1
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// readWAV decodes 16-bit PCM audio from a WAV file, mixing channels
+// down to mono, and returns the samples and sample rate
+func readWAV(r io.Reader) ([]int16, uint, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	} else if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, 0, gopi.ErrUnexpectedResponse.WithPrefix("Not a WAV file")
+	}
+
+	// Read chunks until the data chunk
+	var format *wavFormat
+	for data = data[12:]; len(data) >= 8; {
+		id, size := string(data[0:4]), binary.LittleEndian.Uint32(data[4:8])
+		data = data[8:]
+		if uint64(size) > uint64(len(data)) {
+			// Streamed files may have an unknown data size
+			size = uint32(len(data))
+		}
+		chunk := data[:size]
+		if size%2 == 1 && size < uint32(len(data)) {
+			size++
+		}
+		data = data[size:]
+
+		switch id {
+		case "fmt ":
+			if len(chunk) < 16 {
+				return nil, 0, gopi.ErrUnexpectedResponse.WithPrefix("WAV format")
+			}
+			format = &wavFormat{
+				Format:        binary.LittleEndian.Uint16(chunk[0:]),
+				Channels:      binary.LittleEndian.Uint16(chunk[2:]),
+				SampleRate:    binary.LittleEndian.Uint32(chunk[4:]),
+				ByteRate:      binary.LittleEndian.Uint32(chunk[8:]),
+				BlockAlign:    binary.LittleEndian.Uint16(chunk[12:]),
+				BitsPerSample: binary.LittleEndian.Uint16(chunk[14:]),
+			}
+		case "data":
+			if format == nil {
+				return nil, 0, gopi.ErrUnexpectedResponse.WithPrefix("WAV data before format")
+			} else if format.Format != wavFormatPCM || format.BitsPerSample != 16 || format.Channels == 0 || format.SampleRate == 0 {
+				return nil, 0, gopi.ErrNotImplemented.WithPrefix("WAV format ", format.Format, " with ", format.BitsPerSample, " bits")
+			}
+			return mixdown(chunk, uint(format.Channels)), uint(format.SampleRate), nil
+		}
+	}
+
+	// No data chunk found
+	return nil, 0, gopi.ErrUnexpectedResponse.WithPrefix("WAV data missing")
+}
+
+// mixdown converts interleaved 16-bit little-endian samples to mono
+func mixdown(data []byte, channels uint) []int16 {
+	frames := uint(len(data)) / (2 * channels)
+	samples := make([]int16, frames)
+	for i := uint(0); i < frames; i++ {
+		sum := int32(0)
+		for c := uint(0); c < channels; c++ {
+			sum += int32(int16(binary.LittleEndian.Uint16(data[(i*channels+c)*2:])))
+		}
+		samples[i] = int16(sum / int32(channels))
+	}
+	return samples
+}
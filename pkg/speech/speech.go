@@ -0,0 +1,250 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type speech struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.SpeechEngine
+	gopi.AudioDAC
+	sync.Mutex
+
+	device  *string
+	id      gopi.SpeechId
+	queue   []*utterance
+	current *utterance
+	signal  chan struct{}
+}
+
+type utterance struct {
+	id       gopi.SpeechId
+	text     string
+	priority gopi.SpeechPriority
+	state    gopi.SpeechState // State set when speech is stopped
+	cancel   context.CancelFunc
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	defaultDevice = "hw:0"
+	nullDevice    = "null"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *speech) Define(cfg gopi.Config) error {
+	this.device = cfg.FlagString("speech.device", "", "Audio output device, null for no output")
+	return nil
+}
+
+func (this *speech) New(gopi.Config) error {
+	this.Require(this.Logger, this.SpeechEngine)
+
+	// Use DAC device when no device is set
+	if *this.device == "" {
+		if this.AudioDAC != nil && this.AudioDAC.Device() != "" {
+			*this.device = this.AudioDAC.Device()
+		} else {
+			*this.device = defaultDevice
+		}
+	}
+
+	this.signal = make(chan struct{}, 1)
+
+	// Return success
+	return nil
+}
+
+func (this *speech) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-this.signal:
+			for u := this.next(); u != nil; u = this.next() {
+				this.speak(ctx, u)
+			}
+		case <-ctx.Done():
+			return this.Stop()
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *speech) String() string {
+	str := "<speech"
+	str += fmt.Sprintf(" device=%q", *this.device)
+	str += " engine=" + fmt.Sprint(this.SpeechEngine)
+	str += " queue=" + fmt.Sprint(len(this.queue))
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *speech) Say(text string, priority gopi.SpeechPriority) (gopi.SpeechId, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Check parameters
+	if text == "" {
+		return 0, gopi.ErrBadParameter.WithPrefix("Say")
+	} else if priority > gopi.SPEECH_PRIORITY_INTERRUPT {
+		return 0, gopi.ErrBadParameter.WithPrefix("Say: ", priority)
+	}
+
+	// Allocate identifier, which is never zero
+	this.id++
+	this.queue = append(this.queue, &utterance{id: this.id, text: text, priority: priority})
+
+	// Interrupt current speech
+	if priority == gopi.SPEECH_PRIORITY_INTERRUPT && this.current != nil {
+		this.current.stop(gopi.SPEECH_STATE_INTERRUPTED)
+	}
+
+	// Signal queue has changed
+	select {
+	case this.signal <- struct{}{}:
+	default:
+	}
+
+	// Return success
+	return this.id, nil
+}
+
+func (this *speech) Cancel(id gopi.SpeechId) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.current != nil && this.current.id == id {
+		this.current.stop(gopi.SPEECH_STATE_CANCELLED)
+		return nil
+	}
+	for i, u := range this.queue {
+		if u.id == id {
+			this.queue = append(this.queue[:i], this.queue[i+1:]...)
+			this.emit(u, gopi.SPEECH_STATE_CANCELLED, nil)
+			return nil
+		}
+	}
+	return gopi.ErrNotFound.WithPrefix("Cancel: ", id)
+}
+
+func (this *speech) Stop() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	for _, u := range this.queue {
+		this.emit(u, gopi.SPEECH_STATE_CANCELLED, nil)
+	}
+	this.queue = nil
+	if this.current != nil {
+		this.current.stop(gopi.SPEECH_STATE_CANCELLED)
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// next removes the highest priority utterance from the queue, with
+// utterances of the same priority in the order they were queued
+func (this *speech) next() *utterance {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if len(this.queue) == 0 {
+		return nil
+	}
+	j := 0
+	for i, u := range this.queue {
+		if u.priority > this.queue[j].priority {
+			j = i
+		}
+	}
+	u := this.queue[j]
+	this.queue = append(this.queue[:j], this.queue[j+1:]...)
+	return u
+}
+
+// speak renders and plays an utterance, and emits events when the
+// utterance starts and ends
+func (this *speech) speak(parent context.Context, u *utterance) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	this.Mutex.Lock()
+	u.cancel = cancel
+	this.current = u
+	this.Mutex.Unlock()
+
+	this.emit(u, gopi.SPEECH_STATE_STARTED, nil)
+	samples, rate, err := this.SpeechEngine.Synthesize(ctx, u.text)
+	if err == nil {
+		err = this.play(ctx, samples, rate)
+	}
+
+	this.Mutex.Lock()
+	this.current = nil
+	state := u.state
+	this.Mutex.Unlock()
+
+	switch {
+	case err == nil:
+		this.emit(u, gopi.SPEECH_STATE_SPOKEN, nil)
+	case state != gopi.SPEECH_STATE_NONE:
+		this.emit(u, state, nil)
+	case ctx.Err() != nil:
+		this.emit(u, gopi.SPEECH_STATE_CANCELLED, nil)
+	default:
+		this.Print("Speech: ", err)
+		this.emit(u, gopi.SPEECH_STATE_FAILED, err)
+	}
+}
+
+// play samples on the output device, or wait for the duration of
+// the samples for the null device
+func (this *speech) play(ctx context.Context, samples []int16, rate uint) error {
+	if rate == 0 {
+		return gopi.ErrUnexpectedResponse.WithPrefix("Sample rate")
+	} else if *this.device == nullDevice {
+		return playNull(ctx, samples, rate)
+	} else {
+		return playDevice(ctx, *this.device, samples, rate)
+	}
+}
+
+func (this *speech) emit(u *utterance, state gopi.SpeechState, err error) {
+	if this.Publisher == nil {
+		return
+	}
+	if err := this.Publisher.Emit(NewEvent(u.id, u.text, state, err), false); err != nil {
+		this.Debug("Speech: ", err)
+	}
+}
+
+// stop current speech, setting the state which is emitted when
+// the speech ends
+func (this *utterance) stop(state gopi.SpeechState) {
+	if this.state == gopi.SPEECH_STATE_NONE {
+		this.state = state
+	}
+	if this.cancel != nil {
+		this.cancel()
+	}
+}
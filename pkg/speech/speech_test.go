@@ -0,0 +1,170 @@
+package speech_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/speech"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Speech
+	gopi.Publisher
+}
+
+// engine renders each character as 10ms of silence
+type engine struct {
+	gopi.Unit
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	args = []string{"-speech.device=null"}
+)
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&engine{}), reflect.TypeOf((*gopi.SpeechEngine)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (this *engine) Synthesize(ctx context.Context, text string) ([]int16, uint, error) {
+	return make([]int16, len(text)*80), 8000, ctx.Err()
+}
+
+// wait returns the next speech event or nil after a timeout
+func wait(ch <-chan gopi.Event) gopi.SpeechEvent {
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case evt := <-ch:
+			if evt, ok := evt.(gopi.SpeechEvent); ok {
+				return evt
+			}
+		case <-timeout:
+			return nil
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Speech_001(t *testing.T) {
+	tool.Test(t, args, new(App), func(app *App) {
+		if app.Speech == nil {
+			t.Error("nil Speech unit")
+		} else {
+			t.Log(app.Speech)
+		}
+	})
+}
+
+func Test_Speech_002(t *testing.T) {
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		id, err := app.Speech.Say("hello", gopi.SPEECH_PRIORITY_NORMAL)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for _, state := range []gopi.SpeechState{gopi.SPEECH_STATE_STARTED, gopi.SPEECH_STATE_SPOKEN} {
+			if evt := wait(ch); evt == nil {
+				t.Error("Timeout waiting for", state)
+			} else if evt.Id() != id || evt.State() != state {
+				t.Error("Unexpected event", evt)
+			} else {
+				t.Log(evt)
+			}
+		}
+	})
+}
+
+func Test_Speech_003(t *testing.T) {
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Queue low then high priority while the first is spoken
+		first, _ := app.Speech.Say("first utterance", gopi.SPEECH_PRIORITY_NORMAL)
+		if evt := wait(ch); evt == nil || evt.Id() != first || evt.State() != gopi.SPEECH_STATE_STARTED {
+			t.Error("Unexpected event", evt)
+			return
+		}
+		low, _ := app.Speech.Say("low", gopi.SPEECH_PRIORITY_LOW)
+		high, _ := app.Speech.Say("high", gopi.SPEECH_PRIORITY_HIGH)
+
+		// Expect order first, high, low
+		expect := []gopi.SpeechId{first, high, high, low, low}
+		for _, id := range expect {
+			if evt := wait(ch); evt == nil || evt.Id() != id {
+				t.Error("Unexpected event", evt, "expected id", id)
+				return
+			}
+		}
+	})
+}
+
+func Test_Speech_004(t *testing.T) {
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		first, _ := app.Speech.Say("a very long utterance which takes a while", gopi.SPEECH_PRIORITY_NORMAL)
+		if evt := wait(ch); evt == nil || evt.Id() != first || evt.State() != gopi.SPEECH_STATE_STARTED {
+			t.Error("Unexpected event", evt)
+			return
+		}
+		urgent, _ := app.Speech.Say("urgent", gopi.SPEECH_PRIORITY_INTERRUPT)
+		if evt := wait(ch); evt == nil || evt.Id() != first || evt.State() != gopi.SPEECH_STATE_INTERRUPTED {
+			t.Error("Unexpected event", evt)
+		} else if evt := wait(ch); evt == nil || evt.Id() != urgent || evt.State() != gopi.SPEECH_STATE_STARTED {
+			t.Error("Unexpected event", evt)
+		}
+	})
+}
+
+func Test_Speech_005(t *testing.T) {
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		first, _ := app.Speech.Say("first utterance", gopi.SPEECH_PRIORITY_NORMAL)
+		if evt := wait(ch); evt == nil || evt.Id() != first {
+			t.Error("Unexpected event", evt)
+			return
+		}
+		second, _ := app.Speech.Say("second", gopi.SPEECH_PRIORITY_NORMAL)
+		if err := app.Speech.Cancel(second); err != nil {
+			t.Error(err)
+		} else if evt := wait(ch); evt == nil || evt.Id() != second || evt.State() != gopi.SPEECH_STATE_CANCELLED {
+			t.Error("Unexpected event", evt)
+		} else if err := app.Speech.Cancel(second); err == nil {
+			t.Error("Expected error cancelling twice")
+		}
+		if _, err := app.Speech.Say("", gopi.SPEECH_PRIORITY_NORMAL); err == nil {
+			t.Error("Expected error for empty text")
+		}
+	})
+}
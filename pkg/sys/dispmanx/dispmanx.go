@@ -34,6 +34,7 @@ type (
 	Alpha       C.VC_DISPMANX_ALPHA_T
 	AlphaFlag   C.DISPMANX_FLAGS_ALPHA_T
 	Clamp       C.DISPMANX_CLAMP_T
+	ChangeFlag  uint32
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -44,6 +45,17 @@ const (
 	DISPMANX_ROTATE_90  Transform = C.DISPMANX_ROTATE_90
 	DISPMANX_ROTATE_180 Transform = C.DISPMANX_ROTATE_180
 	DISPMANX_ROTATE_270 Transform = C.DISPMANX_ROTATE_270
+	DISPMANX_FLIP_HRIZ  Transform = C.DISPMANX_FLIP_HRIZ
+	DISPMANX_FLIP_VERT  Transform = C.DISPMANX_FLIP_VERT
+)
+
+const (
+	ELEMENT_CHANGE_LAYER         ChangeFlag = (1 << 0)
+	ELEMENT_CHANGE_OPACITY       ChangeFlag = (1 << 1)
+	ELEMENT_CHANGE_DEST_RECT     ChangeFlag = (1 << 2)
+	ELEMENT_CHANGE_SRC_RECT      ChangeFlag = (1 << 3)
+	ELEMENT_CHANGE_MASK_RESOURCE ChangeFlag = (1 << 4)
+	ELEMENT_CHANGE_TRANSFORM     ChangeFlag = (1 << 5)
 )
 
 const (
@@ -234,6 +246,34 @@ func ElementRemove(ctx Update, element Element) error {
 	return nil
 }
 
+// ElementChangeAttributes changes the attributes of an element which are
+// set in the flags
+func ElementChangeAttributes(ctx Update, element Element, flags ChangeFlag, layer uint16, opacity uint8, destrect, srcrect *Rect, transform Transform) error {
+	if err := C.vc_dispmanx_element_change_attributes(
+		C.DISPMANX_UPDATE_HANDLE_T(ctx),
+		C.DISPMANX_ELEMENT_HANDLE_T(element),
+		C.uint32_t(flags),
+		C.int32_t(layer),
+		C.uint8_t(opacity),
+		(*C.VC_RECT_T)(destrect),
+		(*C.VC_RECT_T)(srcrect),
+		0,
+		C.DISPMANX_TRANSFORM_T(transform),
+	); err != 0 {
+		return gopi.ErrBadParameter
+	}
+	return nil
+}
+
+// ElementModified marks a region of the element source as changed, or the
+// whole element when the rect is nil
+func ElementModified(ctx Update, element Element, r *Rect) error {
+	if err := C.vc_dispmanx_element_modified(C.DISPMANX_UPDATE_HANDLE_T(ctx), C.DISPMANX_ELEMENT_HANDLE_T(element), (*C.VC_RECT_T)(r)); err != 0 {
+		return gopi.ErrBadParameter
+	}
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PUBLIC METHODS - RESOURCES
 
@@ -357,7 +397,18 @@ func (d DisplayInfo) Num() uint32 {
 // STRINGIFY
 
 func (t Transform) String() string {
-	switch t & 0x04 {
+	str := t.stringRotate()
+	if t&DISPMANX_FLIP_HRIZ != 0 {
+		str += "|DISPMANX_FLIP_HRIZ"
+	}
+	if t&DISPMANX_FLIP_VERT != 0 {
+		str += "|DISPMANX_FLIP_VERT"
+	}
+	return str
+}
+
+func (t Transform) stringRotate() string {
+	switch t & 0x03 {
 	case DISPMANX_NO_ROTATE:
 		return "DISPMANX_NO_ROTATE"
 	case DISPMANX_ROTATE_90:
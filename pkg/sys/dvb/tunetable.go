@@ -36,6 +36,7 @@ type TuneParams struct {
 	GuardInterval          FEGuardInterval
 	Hierarchy              FEHierarchy
 	Inversion              FEInversion
+	ServiceId              uint16
 }
 
 type scanflag int
@@ -56,6 +57,7 @@ const (
 	scan_flag_guardinterval
 	scan_flag_hierarchy
 	scan_flag_inversion
+	scan_flag_serviceid
 )
 
 const (
@@ -104,6 +106,27 @@ func ReadTuneParamsTable(r io.Reader) ([]*TuneParams, error) {
 	return scans, nil
 }
 
+// WriteTuneParamsTable writes tuning parameters in the format read
+// by ReadTuneParamsTable, which is compatible with dvbv5 channel files
+func WriteTuneParamsTable(w io.Writer, params []*TuneParams) error {
+	for _, param := range params {
+		if _, err := fmt.Fprintf(w, "[%s]\n", param.name); err != nil {
+			return err
+		}
+		for _, kv := range param.keyvalues() {
+			if _, err := fmt.Fprintf(w, "\t%s = %s\n", kv[0], kv[1]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // PROPERTIES
 
@@ -167,6 +190,25 @@ func (this *TuneParams) SetInversion(value FEInversion) {
 	this.flags |= scan_flag_inversion
 }
 
+func (this *TuneParams) SetServiceId(value uint16) {
+	this.ServiceId = value
+	this.flags |= scan_flag_serviceid
+}
+
+// HasServiceId returns true if the parameters select a service
+func (this *TuneParams) HasServiceId() bool {
+	return this.flags&scan_flag_serviceid != 0
+}
+
+// WithService returns a copy of the parameters with a name and
+// service identifier
+func (this *TuneParams) WithService(name string, id uint16) *TuneParams {
+	params := *this
+	params.name = name
+	params.SetServiceId(id)
+	return &params
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // GET IOCTL PARAMETERS
 
@@ -209,6 +251,49 @@ func (this *TuneParams) params() []C.struct_dtv_property {
 	return params
 }
 
+// keyvalues returns the parameters as keys and values
+func (this *TuneParams) keyvalues() [][2]string {
+	kv := [][2]string{}
+	if this.flags&scan_flag_serviceid != 0 {
+		kv = append(kv, [2]string{"SERVICE_ID", fmt.Sprint(this.ServiceId)})
+	}
+	if this.flags&scan_flag_deliverysystem != 0 {
+		kv = append(kv, [2]string{"DELIVERY_SYSTEM", strings.TrimPrefix(fmt.Sprint(this.DeliverySystem), "SYS_")})
+	}
+	if this.flags&scan_flag_frequency != 0 {
+		kv = append(kv, [2]string{"FREQUENCY", fmt.Sprint(this.Frequency)})
+	}
+	if this.flags&scan_flag_modulation != 0 {
+		kv = append(kv, [2]string{"MODULATION", formatValue(this.Modulation, "")})
+	}
+	if this.flags&scan_flag_bandwidth != 0 {
+		kv = append(kv, [2]string{"BANDWIDTH_HZ", fmt.Sprint(this.Bandwidth)})
+	}
+	if this.flags&scan_flag_symbolrate != 0 {
+		kv = append(kv, [2]string{"SYMBOL_RATE", fmt.Sprint(this.SymbolRate)})
+	}
+	if this.flags&scan_flag_coderate != 0 {
+		kv = append(kv, [2]string{"CODE_RATE_HP", formatValue(this.CodeRateHp, "FEC_")})
+		kv = append(kv, [2]string{"CODE_RATE_LP", formatValue(this.CodeRateLp, "FEC_")})
+	}
+	if this.flags&scan_flag_innerfec != 0 {
+		kv = append(kv, [2]string{"INNER_FEC", formatValue(this.InnerFEC, "FEC_")})
+	}
+	if this.flags&scan_flag_transmitmode != 0 {
+		kv = append(kv, [2]string{"TRANSMISSION_MODE", formatValue(this.TransmitMode, "TRANSMISSION_MODE_")})
+	}
+	if this.flags&scan_flag_guardinterval != 0 {
+		kv = append(kv, [2]string{"GUARD_INTERVAL", formatValue(this.GuardInterval, "GUARD_INTERVAL_")})
+	}
+	if this.flags&scan_flag_hierarchy != 0 {
+		kv = append(kv, [2]string{"HIERARCHY", formatValue(this.Hierarchy, "HIERARCHY_")})
+	}
+	if this.flags&scan_flag_inversion != 0 {
+		kv = append(kv, [2]string{"INVERSION", formatValue(this.Inversion, "INVERSION_")})
+	}
+	return kv
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // STRINGIFY
 
@@ -249,6 +334,9 @@ func (this *TuneParams) String() string {
 	if this.flags&scan_flag_inversion != 0 {
 		str += " inversion=" + fmt.Sprint(this.Inversion)
 	}
+	if this.flags&scan_flag_serviceid != 0 {
+		str += " service_id=" + fmt.Sprint(this.ServiceId)
+	}
 	return str + ">"
 }
 
@@ -353,7 +441,13 @@ func parseScanKeyvalue(scan *TuneParams, key, value string) error {
 		} else {
 			scan.SetInversion(val)
 		}
-	case "STREAM_ID", "SERVICE_ID", "VIDEO_PID", "AUDIO_PID":
+	case "SERVICE_ID":
+		if val, err := strconv.ParseUint(value, 0, 16); err != nil {
+			return err
+		} else {
+			scan.SetServiceId(uint16(val))
+		}
+	case "STREAM_ID", "VIDEO_PID", "AUDIO_PID":
 		// Ignore these parameters for now
 	default:
 		// Ignore PID value
@@ -367,6 +461,12 @@ func parseScanKeyvalue(scan *TuneParams, key, value string) error {
 	return nil
 }
 
+// formatValue returns a value without a prefix, using a slash
+// rather than an underscore as a separator
+func formatValue(v interface{}, prefix string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(fmt.Sprint(v), prefix), "_", "/")
+}
+
 func parseDeliverySystem(str string) (FEDeliverySystem, error) {
 	str = strings.ToUpper(strings.ReplaceAll(str, "/", "_"))
 	for v := SYS_MIN; v <= SYS_MAX; v++ {
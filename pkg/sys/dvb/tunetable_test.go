@@ -78,3 +78,34 @@ func Test_Tunetable_001(t *testing.T) {
 		}
 	}
 }
+
+func Test_Tunetable_002(t *testing.T) {
+	table := `[BBC ONE]
+	SERVICE_ID = 4164
+	DELIVERY_SYSTEM = DVBT
+	FREQUENCY = 490000000
+	MODULATION = QAM/64
+	BANDWIDTH_HZ = 8000000
+	CODE_RATE_HP = 2/3
+	CODE_RATE_LP = NONE
+	TRANSMISSION_MODE = 8K
+	GUARD_INTERVAL = 1/32
+	HIERARCHY = NONE
+	INVERSION = AUTO
+
+`
+	scans, err := dvb.ReadTuneParamsTable(strings.NewReader(table))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(scans) != 1 {
+		t.Fatal("Unexpected number of scans", scans)
+	} else if scans[0].HasServiceId() == false || scans[0].ServiceId != 4164 {
+		t.Error("Unexpected service id", scans[0])
+	}
+	var buf strings.Builder
+	if err := dvb.WriteTuneParamsTable(&buf, scans); err != nil {
+		t.Error(err)
+	} else if buf.String() != table {
+		t.Errorf("Unexpected output:\n%v", buf.String())
+	}
+}
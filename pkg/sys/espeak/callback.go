@@ -0,0 +1,35 @@
+// +build espeak
+
+package espeak
+
+////////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#include <espeak-ng/speak_lib.h>
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CALLBACK
+
+//export espeak_callback
+func espeak_callback(wav *C.short, n C.int, events *C.espeak_EVENT) C.int {
+	lock.Lock()
+	fn := handles[uintptr(events.user_data)]
+	lock.Unlock()
+
+	// Abort when the function is not found or returns false
+	if fn == nil {
+		return 1
+	} else if wav == nil || n <= 0 {
+		return 0
+	} else if fn((*[1 << 28]int16)(unsafe.Pointer(wav))[:n:n]) == false {
+		return 1
+	} else {
+		return 0
+	}
+}
@@ -0,0 +1,18 @@
+package espeak
+
+/*
+
+This package provides espeak-ng speech synthesis bindings
+
+In order to use this package, you will need to install the espeak-ng
+development libraries. For debian,
+
+% sudo apt install libespeak-ng-dev
+
+You will also need to use -tags espeak when testing, building or
+installing.
+
+API Documentation Sources:
+https://github.com/espeak-ng/espeak-ng/blob/master/src/include/espeak-ng/speak_lib.h
+
+*/
@@ -0,0 +1,160 @@
+// +build espeak
+
+package espeak
+
+////////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#cgo pkg-config: espeak-ng
+#include <stdint.h>
+#include <stdlib.h>
+#include <string.h>
+#include <espeak-ng/speak_lib.h>
+
+extern int espeak_callback(short*, int, espeak_EVENT*);
+static void _espeak_set_callback() {
+	espeak_SetSynthCallback(espeak_callback);
+}
+static espeak_ERROR _espeak_synth(const char* text, uintptr_t handle) {
+	return espeak_Synth(text, strlen(text) + 1, 0, POS_CHARACTER, 0, espeakCHARS_UTF8, NULL, (void*)handle);
+}
+*/
+import "C"
+import (
+	"sync"
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	Error     int
+	Parameter C.espeak_PARAMETER
+
+	// SynthFunc receives samples as they are rendered, and returns
+	// false to stop rendering. Samples are only valid during the call
+	SynthFunc func([]int16) bool
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTS
+
+const (
+	EE_OK             Error = C.EE_OK
+	EE_INTERNAL_ERROR Error = C.EE_INTERNAL_ERROR
+	EE_BUFFER_FULL    Error = C.EE_BUFFER_FULL
+	EE_NOT_FOUND      Error = C.EE_NOT_FOUND
+)
+
+const (
+	PARAM_RATE        Parameter = C.espeakRATE
+	PARAM_VOLUME      Parameter = C.espeakVOLUME
+	PARAM_PITCH       Parameter = C.espeakPITCH
+	PARAM_RANGE       Parameter = C.espeakRANGE
+	PARAM_PUNCTUATION Parameter = C.espeakPUNCTUATION
+	PARAM_WORDGAP     Parameter = C.espeakWORDGAP
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	lock    sync.Mutex
+	handle  uintptr
+	handles = make(map[uintptr]SynthFunc)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// Initialize the library with the path to the espeak-ng-data folder,
+// or empty string for the default path. Returns the sample rate
+func Initialize(path string) (uint, error) {
+	var cPath *C.char
+	if path != "" {
+		cPath = C.CString(path)
+		defer C.free(unsafe.Pointer(cPath))
+	}
+	if rate := C.espeak_Initialize(C.AUDIO_OUTPUT_SYNCHRONOUS, 0, cPath, 0); rate <= 0 {
+		return 0, EE_INTERNAL_ERROR
+	} else {
+		C._espeak_set_callback()
+		return uint(rate), nil
+	}
+}
+
+// Terminate releases library resources
+func Terminate() error {
+	if err := Error(C.espeak_Terminate()); err != EE_OK {
+		return err
+	} else {
+		return nil
+	}
+}
+
+// SetVoice sets the voice by name or language, for example "en-gb"
+func SetVoice(name string) error {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	if err := Error(C.espeak_SetVoiceByName(cName)); err != EE_OK {
+		return err
+	} else {
+		return nil
+	}
+}
+
+// SetParameter sets a synthesis parameter, such as rate in words
+// per minute
+func SetParameter(param Parameter, value int) error {
+	if err := Error(C.espeak_SetParameter(C.espeak_PARAMETER(param), C.int(value), 0)); err != EE_OK {
+		return err
+	} else {
+		return nil
+	}
+}
+
+// Synthesize renders text, calling a function with the samples as
+// they are rendered. Blocks until rendering is complete
+func Synthesize(text string, fn SynthFunc) error {
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+
+	// Register the function with a handle which is passed to the callback
+	lock.Lock()
+	handle++
+	h := handle
+	handles[h] = fn
+	lock.Unlock()
+
+	defer func() {
+		lock.Lock()
+		delete(handles, h)
+		lock.Unlock()
+	}()
+
+	if err := Error(C._espeak_synth(cText, C.uintptr_t(h))); err != EE_OK {
+		return err
+	} else {
+		return nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (e Error) Error() string {
+	switch e {
+	case EE_OK:
+		return "EE_OK"
+	case EE_INTERNAL_ERROR:
+		return "EE_INTERNAL_ERROR"
+	case EE_BUFFER_FULL:
+		return "EE_BUFFER_FULL"
+	case EE_NOT_FOUND:
+		return "EE_NOT_FOUND"
+	default:
+		return "[?? Invalid Error value]"
+	}
+}
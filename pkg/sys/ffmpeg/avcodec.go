@@ -241,6 +241,11 @@ func (this *AVCodecParameters) Tag() uint32 {
 	return uint32(this.codec_tag)
 }
 
+// SetTag sets the codec tag, or zero to let the muxer choose one
+func (this *AVCodecParameters) SetTag(tag uint32) {
+	this.codec_tag = C.uint32_t(tag)
+}
+
 func (this *AVCodecParameters) BitRate() int32 {
 	return int32(this.bit_rate)
 }
@@ -253,6 +258,18 @@ func (this *AVCodecParameters) Height() uint {
 	return uint(this.height)
 }
 
+func (this *AVCodecParameters) SampleRate() uint {
+	return uint(this.sample_rate)
+}
+
+func (this *AVCodecParameters) Channels() uint {
+	return uint(this.channels)
+}
+
+func (this *AVCodecParameters) ChannelLayout() AVChannelLayout {
+	return AVChannelLayout(this.channel_layout)
+}
+
 func (this *AVCodecParameters) String() string {
 	str := "<AVCodecParameters"
 	str += " type=" + fmt.Sprint(this.Type())
@@ -14,6 +14,7 @@ import (
 /*
 #cgo pkg-config: libavcodec
 #include <libavcodec/avcodec.h>
+#include <string.h>
 */
 import "C"
 
@@ -88,6 +89,27 @@ func (this *AVCodecContext) DecodeFrame(frame *AVFrame) error {
 	return nil
 }
 
+// SetExtraData copies codec-specific data to the context, which is
+// required by some decoders before the context is opened
+func (this *AVCodecContext) SetExtraData(data []byte) error {
+	ctx := (*C.AVCodecContext)(unsafe.Pointer(this))
+	C.av_freep(unsafe.Pointer(&ctx.extradata))
+	ctx.extradata_size = 0
+	if len(data) == 0 {
+		return nil
+	}
+	if ptr := C.av_mallocz(C.size_t(len(data) + C.AV_INPUT_BUFFER_PADDING_SIZE)); ptr == nil {
+		return syscall.ENOMEM
+	} else {
+		C.memcpy(ptr, unsafe.Pointer(&data[0]), C.size_t(len(data)))
+		ctx.extradata = (*C.uint8_t)(ptr)
+		ctx.extradata_size = C.int(len(data))
+	}
+
+	// Return success
+	return nil
+}
+
 func (this *AVCodecContext) Type() AVMediaType {
 	ctx := (*C.AVCodecContext)(unsafe.Pointer(this))
 	return AVMediaType(ctx.codec_type)
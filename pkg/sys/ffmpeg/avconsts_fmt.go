@@ -198,7 +198,7 @@ const (
 )
 
 const (
-	AV_SAMPLE_FMT_NONE AVSampleFormat = iota
+	AV_SAMPLE_FMT_NONE AVSampleFormat = iota - 1
 	AV_SAMPLE_FMT_U8                  //	unsigned 8 bits
 	AV_SAMPLE_FMT_S16                 //	signed 16 bits
 	AV_SAMPLE_FMT_S32                 //	signed 32 bits
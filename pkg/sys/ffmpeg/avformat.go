@@ -28,6 +28,16 @@ type (
 	AVFormatContext C.struct_AVFormatContext
 	AVInputFormat   C.struct_AVInputFormat
 	AVOutputFormat  C.struct_AVOutputFormat
+	AVChapter       C.struct_AVChapter
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// AV_TIME_BASE is the number of units per second for
+	// durations and start times in a format context
+	AV_TIME_BASE int64 = 1000000
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -84,6 +94,26 @@ func AllDemuxers() []*AVInputFormat {
 	return demuxers
 }
 
+// GuessOutputFormat returns the multiplexer which best matches a short
+// name, filename or mimetype, or nil if there is no match. Empty
+// arguments are ignored
+func GuessOutputFormat(name, filename, mimetype string) *AVOutputFormat {
+	var name_, filename_, mimetype_ *C.char
+	if name != "" {
+		name_ = C.CString(name)
+		defer C.free(unsafe.Pointer(name_))
+	}
+	if filename != "" {
+		filename_ = C.CString(filename)
+		defer C.free(unsafe.Pointer(filename_))
+	}
+	if mimetype != "" {
+		mimetype_ = C.CString(mimetype)
+		defer C.free(unsafe.Pointer(mimetype_))
+	}
+	return (*AVOutputFormat)(C.av_guess_format(name_, filename_, mimetype_))
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // AVFormatContext
 
@@ -248,6 +278,45 @@ func (this *AVFormatContext) Streams() []*AVStream {
 	return streams
 }
 
+// Return number of chapters
+func (this *AVFormatContext) NumChapters() uint {
+	ctx := (*C.AVFormatContext)(unsafe.Pointer(this))
+	return uint(ctx.nb_chapters)
+}
+
+// Return Chapters
+func (this *AVFormatContext) Chapters() []*AVChapter {
+	var chapters []*AVChapter
+
+	// Get context
+	ctx := (*C.AVFormatContext)(unsafe.Pointer(this))
+
+	// Make a fake slice
+	if nb_chapters := this.NumChapters(); nb_chapters > 0 {
+		sliceHeader := (*reflect.SliceHeader)((unsafe.Pointer(&chapters)))
+		sliceHeader.Cap = int(nb_chapters)
+		sliceHeader.Len = int(nb_chapters)
+		sliceHeader.Data = uintptr(unsafe.Pointer(ctx.chapters))
+	}
+	return chapters
+}
+
+// Return duration in AV_TIME_BASE units, or zero if unknown
+func (this *AVFormatContext) Duration() int64 {
+	ctx := (*C.AVFormatContext)(unsafe.Pointer(this))
+	if ctx.duration <= 0 {
+		return 0
+	} else {
+		return int64(ctx.duration)
+	}
+}
+
+// Return total bit rate in bits per second, or zero if unknown
+func (this *AVFormatContext) BitRate() int64 {
+	ctx := (*C.AVFormatContext)(unsafe.Pointer(this))
+	return int64(ctx.bit_rate)
+}
+
 // Return Input Format
 func (this *AVFormatContext) InputFormat() *AVInputFormat {
 	ctx := (*C.AVFormatContext)(unsafe.Pointer(this))
@@ -307,19 +376,19 @@ func (this *AVFormatContext) ReadPacket(packet *AVPacket) error {
 	}
 }
 
-func (this *AVFormatContext) WritePacket(packet *AVPacket, out *AVFormatContext) error {
-	i := (*C.AVFormatContext)(unsafe.Pointer(this))
-	o := (*C.AVFormatContext)(unsafe.Pointer(out))
+// WritePacket writes a packet read from an input stream to an output
+// stream, adjusting timestamps for the output stream time base
+func (this *AVFormatContext) WritePacket(packet *AVPacket, in, out *AVStream) error {
+	o := (*C.AVFormatContext)(unsafe.Pointer(this))
 	p := (*C.AVPacket)(packet)
-
-	/* Get streams */
-	in_stream := *(i.streams)  // TODO
-	out_stream := *(o.streams) // TODO
+	in_stream := (*C.AVStream)(unsafe.Pointer(in))
+	out_stream := (*C.AVStream)(unsafe.Pointer(out))
 
 	/* Adjust packet params for output */
 	p.pts = C.av_rescale_q_rnd(p.pts, in_stream.time_base, out_stream.time_base, C.AV_ROUND_NEAR_INF|C.AV_ROUND_PASS_MINMAX)
 	p.dts = C.av_rescale_q_rnd(p.dts, in_stream.time_base, out_stream.time_base, C.AV_ROUND_NEAR_INF|C.AV_ROUND_PASS_MINMAX)
 	p.duration = C.av_rescale_q(p.duration, in_stream.time_base, out_stream.time_base)
+	p.stream_index = out_stream.index
 	p.pos = -1
 
 	/* Write packet */
@@ -433,3 +502,43 @@ func AVDumpFormat(ctx *AVFormatContext, index int, filename string, is_output bo
 	}
 	C.av_dump_format((*C.AVFormatContext)(ctx), C.int(index), filename_, C.int(is_output_))
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// AVChapter
+
+func (this *AVChapter) Id() int64 {
+	ctx := (*C.AVChapter)(unsafe.Pointer(this))
+	return int64(ctx.id)
+}
+
+func (this *AVChapter) TimeBase() AVRational {
+	ctx := (*C.AVChapter)(unsafe.Pointer(this))
+	return AVRational(ctx.time_base)
+}
+
+// Return start time in time base units
+func (this *AVChapter) Start() int64 {
+	ctx := (*C.AVChapter)(unsafe.Pointer(this))
+	return int64(ctx.start)
+}
+
+// Return end time in time base units
+func (this *AVChapter) End() int64 {
+	ctx := (*C.AVChapter)(unsafe.Pointer(this))
+	return int64(ctx.end)
+}
+
+func (this *AVChapter) Metadata() *AVDictionary {
+	ctx := (*C.AVChapter)(unsafe.Pointer(this))
+	return &AVDictionary{ctx: ctx.metadata}
+}
+
+func (this *AVChapter) String() string {
+	str := "<AVChapter"
+	str += " id=" + fmt.Sprint(this.Id())
+	str += " time_base=" + fmt.Sprint(this.TimeBase())
+	str += " start=" + fmt.Sprint(this.Start())
+	str += " end=" + fmt.Sprint(this.End())
+	str += " metadata=" + fmt.Sprint(this.Metadata())
+	return str + ">"
+}
@@ -142,6 +142,33 @@ func (this *AVFrame) StrideForPlane(i int) int {
 	return int(ctx.linesize[i])
 }
 
+// AudioPlane returns the samples for a plane of audio. Planar formats
+// have a plane for each channel, and other formats have interleaved
+// samples in the first plane
+func (this *AVFrame) AudioPlane(plane int) []byte {
+	var bytes []byte
+
+	ctx := (*C.AVFrame)(unsafe.Pointer(this))
+	if plane < 0 || plane >= int(ctx.channels) || ctx.extended_data == nil {
+		return nil
+	} else if this.IsPlanar() == false && plane > 0 {
+		return nil
+	}
+	size := int(ctx.nb_samples) * int(C.av_get_bytes_per_sample(C.enum_AVSampleFormat(ctx.format)))
+	if this.IsPlanar() == false {
+		size *= int(ctx.channels)
+	}
+	data := (*[1 << 20]*C.uint8_t)(unsafe.Pointer(ctx.extended_data))[plane]
+	if data == nil {
+		return nil
+	}
+	sliceHeader := (*reflect.SliceHeader)((unsafe.Pointer(&bytes)))
+	sliceHeader.Cap = size
+	sliceHeader.Len = size
+	sliceHeader.Data = uintptr(unsafe.Pointer(data))
+	return bytes
+}
+
 func (this *AVFrame) GetAudioBuffer(num_samples int) error {
 	ctx := (*C.AVFrame)(unsafe.Pointer(this))
 
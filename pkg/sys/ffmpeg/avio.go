@@ -3,7 +3,9 @@
 package ffmpeg
 
 import (
+	"io"
 	"net/url"
+	"sync"
 	"unsafe"
 )
 
@@ -13,6 +15,31 @@ import (
 /*
 #cgo pkg-config: libavformat
 #include <libavformat/avformat.h>
+#include <errno.h>
+
+extern int avio_write_cb_(void* opaque, uint8_t* buf, int size);
+extern int64_t avio_seek_cb_(void* opaque, int64_t offset, int whence);
+
+static AVIOContext* avio_alloc_writer(uintptr_t handle, int size, int seekable) {
+	unsigned char* buf = av_malloc(size);
+	if (buf == NULL) {
+		return NULL;
+	}
+	AVIOContext* ctx = avio_alloc_context(buf, size, 1, (void*)handle, NULL, (void*)avio_write_cb_, seekable ? avio_seek_cb_ : NULL);
+	if (ctx == NULL) {
+		av_free(buf);
+	}
+	return ctx;
+}
+
+static void avio_free_writer(AVIOContext* ctx) {
+	av_freep(&ctx->buffer);
+	avio_context_free(&ctx);
+}
+
+static int avio_error_io() {
+	return AVERROR(EIO);
+}
 */
 import "C"
 
@@ -23,6 +50,20 @@ type (
 	AVIOContext C.struct_AVIOContext
 )
 
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Default size of buffer for writers
+	AVIO_WRITER_BUFFER_SIZE = 64 * 1024
+)
+
+var (
+	writerLock    sync.Mutex
+	writerHandle  uintptr
+	writerHandles = make(map[uintptr]io.Writer)
+)
+
 ////////////////////////////////////////////////////////////////////////////////
 // AVIO
 
@@ -81,3 +122,81 @@ func (this *AVIOContext) Write(buf []byte) {
 	size := len(buf)
 	C.avio_write(ctx, (*C.uint8_t)(data), C.int(size))
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// AVIO WRITER
+
+// NewAVIOWriter returns a context which writes output to an io.Writer
+// through a buffer of size bytes, or the default size when zero. Seeking
+// is supported when the writer also implements io.Seeker. The context
+// is released with FreeWriter
+func NewAVIOWriter(w io.Writer, size int) (*AVIOContext, error) {
+	if w == nil || size < 0 {
+		return nil, AVError(C.avio_error_io())
+	} else if size == 0 {
+		size = AVIO_WRITER_BUFFER_SIZE
+	}
+
+	// Register the writer
+	writerLock.Lock()
+	writerHandle++
+	h := writerHandle
+	writerHandles[h] = w
+	writerLock.Unlock()
+
+	// Allocate the context
+	seekable := 0
+	if _, ok := w.(io.Seeker); ok {
+		seekable = 1
+	}
+	if ctx := C.avio_alloc_writer(C.uintptr_t(h), C.int(size), C.int(seekable)); ctx == nil {
+		writerLock.Lock()
+		delete(writerHandles, h)
+		writerLock.Unlock()
+		return nil, AVError(C.avio_error_io())
+	} else {
+		return (*AVIOContext)(ctx), nil
+	}
+}
+
+// FreeWriter flushes output to the writer and releases a context
+// created with NewAVIOWriter
+func (this *AVIOContext) FreeWriter() {
+	ctx := (*C.AVIOContext)(this)
+	C.avio_flush(ctx)
+	writerLock.Lock()
+	delete(writerHandles, uintptr(ctx.opaque))
+	writerLock.Unlock()
+	C.avio_free_writer(ctx)
+}
+
+//export avio_write_cb_
+func avio_write_cb_(opaque unsafe.Pointer, buf *C.uint8_t, size C.int) C.int {
+	writerLock.Lock()
+	w := writerHandles[uintptr(opaque)]
+	writerLock.Unlock()
+	if w == nil {
+		return C.avio_error_io()
+	} else if n, err := w.Write(C.GoBytes(unsafe.Pointer(buf), size)); err != nil {
+		return C.avio_error_io()
+	} else {
+		return C.int(n)
+	}
+}
+
+//export avio_seek_cb_
+func avio_seek_cb_(opaque unsafe.Pointer, offset C.int64_t, whence C.int) C.int64_t {
+	writerLock.Lock()
+	w := writerHandles[uintptr(opaque)]
+	writerLock.Unlock()
+	if seeker, ok := w.(io.Seeker); ok == false {
+		return C.int64_t(C.avio_error_io())
+	} else if whence&C.AVSEEK_SIZE != 0 {
+		// Size is not known
+		return C.int64_t(C.avio_error_io())
+	} else if pos, err := seeker.Seek(int64(offset), int(whence&^C.AVSEEK_FORCE)); err != nil {
+		return C.int64_t(C.avio_error_io())
+	} else {
+		return C.int64_t(pos)
+	}
+}
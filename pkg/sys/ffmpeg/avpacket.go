@@ -14,6 +14,7 @@ import (
 /*
 #cgo pkg-config: libavcodec
 #include <libavcodec/avcodec.h>
+#include <string.h>
 */
 import "C"
 
@@ -70,6 +71,20 @@ func (this *AVPacket) Bytes() []byte {
 	return bytes
 }
 
+// SetBytes copies data into the packet, replacing any existing data
+func (this *AVPacket) SetBytes(data []byte) error {
+	ctx := (*C.AVPacket)(unsafe.Pointer(this))
+	C.av_packet_unref(ctx)
+	if err := AVError(C.av_new_packet(ctx, C.int(len(data)))); err != 0 {
+		return err
+	} else if len(data) > 0 {
+		C.memcpy(unsafe.Pointer(ctx.data), unsafe.Pointer(&data[0]), C.size_t(len(data)))
+	}
+
+	// Return success
+	return nil
+}
+
 func (this *AVPacket) Stream() int {
 	ctx := (*C.AVPacket)(unsafe.Pointer(this))
 	return int(ctx.stream_index)
@@ -80,6 +95,11 @@ func (this *AVPacket) Flags() AVPacketFlag {
 	return AVPacketFlag(ctx.flags)
 }
 
+// IsKeyFrame returns true if the packet contains a key frame
+func (this *AVPacket) IsKeyFrame() bool {
+	return this.Flags()&AV_PKT_FLAG_KEY != 0
+}
+
 func (this *AVPacket) Pos() int64 {
 	ctx := (*C.AVPacket)(unsafe.Pointer(this))
 	return int64(ctx.pos)
@@ -20,6 +20,7 @@ import (
 #include <libavutil/mem.h>
 #include <libavutil/frame.h>
 #include <libavutil/error.h>
+#include <libavutil/channel_layout.h>
 #include <stdlib.h>
 #define MAX_LOG_BUFFER 1024
 
@@ -216,6 +217,18 @@ func (this AVRational) Float(multiplier int64) float64 {
 	return float64(int64(this.num)*multiplier) / float64(this.den)
 }
 
+////////////////////////////////////////////////////////////////////////////////
+// CHANNEL LAYOUT
+
+// AVGetChannelLayoutName returns a description of a channel layout,
+// such as "stereo" or "5.1", or the number of channels when the
+// layout is not known
+func AVGetChannelLayoutName(channels uint, layout AVChannelLayout) string {
+	var buf [64]C.char
+	C.av_get_channel_layout_string(&buf[0], C.int(len(buf)), C.int(channels), C.uint64_t(layout))
+	return C.GoString(&buf[0])
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // LOGGING
 
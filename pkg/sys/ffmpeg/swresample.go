@@ -66,6 +66,17 @@ func (this *SwrContext) IsInitialized() bool {
 	return C.swr_is_initialized(ctx) != 0
 }
 
+// SetCompensation adds or removes delta samples over distance samples
+// of output, to correct drift between clocks
+func (this *SwrContext) SetCompensation(delta, distance int) error {
+	ctx := (*C.SwrContext)(this)
+	if err := AVError(C.swr_set_compensation(ctx, C.int(delta), C.int(distance))); err != 0 {
+		return err
+	} else {
+		return nil
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // AVFrame
 
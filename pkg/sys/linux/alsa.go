@@ -0,0 +1,304 @@
+// +build linux
+
+package linux
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	// Frameworks
+	"github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+	#include <sys/ioctl.h>
+	#include <stddef.h>
+	#include <sound/asound.h>
+	static int _SNDRV_CTL_IOCTL_ELEM_LIST() { return SNDRV_CTL_IOCTL_ELEM_LIST; }
+	static int _SNDRV_CTL_IOCTL_ELEM_INFO() { return SNDRV_CTL_IOCTL_ELEM_INFO; }
+	static int _SNDRV_CTL_IOCTL_ELEM_READ() { return SNDRV_CTL_IOCTL_ELEM_READ; }
+	static int _SNDRV_CTL_IOCTL_ELEM_WRITE() { return SNDRV_CTL_IOCTL_ELEM_WRITE; }
+	static long _info_min(struct snd_ctl_elem_info* i) { return i->value.integer.min; }
+	static long _info_max(struct snd_ctl_elem_info* i) { return i->value.integer.max; }
+	static long long _info_min64(struct snd_ctl_elem_info* i) { return i->value.integer64.min; }
+	static long long _info_max64(struct snd_ctl_elem_info* i) { return i->value.integer64.max; }
+	static unsigned int _info_items(struct snd_ctl_elem_info* i) { return i->value.enumerated.items; }
+	static void _info_set_item(struct snd_ctl_elem_info* i, unsigned int item) { i->value.enumerated.item = item; }
+	static char* _info_item_name(struct snd_ctl_elem_info* i) { return i->value.enumerated.name; }
+	static long _value_integer(struct snd_ctl_elem_value* v, int i) { return v->value.integer.value[i]; }
+	static void _value_set_integer(struct snd_ctl_elem_value* v, int i, long x) { v->value.integer.value[i] = x; }
+	static long long _value_integer64(struct snd_ctl_elem_value* v, int i) { return v->value.integer64.value[i]; }
+	static void _value_set_integer64(struct snd_ctl_elem_value* v, int i, long long x) { v->value.integer64.value[i] = x; }
+	static unsigned int _value_enumerated(struct snd_ctl_elem_value* v, int i) { return v->value.enumerated.item[i]; }
+	static void _value_set_enumerated(struct snd_ctl_elem_value* v, int i, unsigned int x) { v->value.enumerated.item[i] = x; }
+*/
+import "C"
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	ALSAControlType uint
+)
+
+// ALSACard is a sound card listed in /proc/asound/cards
+type ALSACard struct {
+	Number uint
+	Id     string
+	Driver string
+	Name   string
+}
+
+// ALSAControl is a mixer control element on a sound card
+type ALSAControl struct {
+	NumId    uint32
+	Name     string
+	Index    uint32
+	Type     ALSAControlType
+	Count    uint
+	Min, Max int64
+	Items    []string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	ALSA_CONTROL_TYPE_NONE       ALSAControlType = C.SNDRV_CTL_ELEM_TYPE_NONE
+	ALSA_CONTROL_TYPE_BOOLEAN    ALSAControlType = C.SNDRV_CTL_ELEM_TYPE_BOOLEAN
+	ALSA_CONTROL_TYPE_INTEGER    ALSAControlType = C.SNDRV_CTL_ELEM_TYPE_INTEGER
+	ALSA_CONTROL_TYPE_ENUMERATED ALSAControlType = C.SNDRV_CTL_ELEM_TYPE_ENUMERATED
+	ALSA_CONTROL_TYPE_BYTES      ALSAControlType = C.SNDRV_CTL_ELEM_TYPE_BYTES
+	ALSA_CONTROL_TYPE_IEC958     ALSAControlType = C.SNDRV_CTL_ELEM_TYPE_IEC958
+	ALSA_CONTROL_TYPE_INTEGER64  ALSAControlType = C.SNDRV_CTL_ELEM_TYPE_INTEGER64
+)
+
+const (
+	ALSA_CARDS   = "/proc/asound/cards"
+	ALSA_CONTROL = "/dev/snd/controlC"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// VARIABLES
+
+var (
+	SNDRV_CTL_IOCTL_ELEM_LIST  = uintptr(C._SNDRV_CTL_IOCTL_ELEM_LIST())
+	SNDRV_CTL_IOCTL_ELEM_INFO  = uintptr(C._SNDRV_CTL_IOCTL_ELEM_INFO())
+	SNDRV_CTL_IOCTL_ELEM_READ  = uintptr(C._SNDRV_CTL_IOCTL_ELEM_READ())
+	SNDRV_CTL_IOCTL_ELEM_WRITE = uintptr(C._SNDRV_CTL_IOCTL_ELEM_WRITE())
+)
+
+var (
+	reALSACard = regexp.MustCompile(`^\s*(\d+)\s+\[(\S+)\s*\]:\s+(.+?)\s+-\s+(.+)$`)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CARDS
+
+// ALSACards returns the sound cards which are installed
+func ALSACards() ([]ALSACard, error) {
+	file, err := os.Open(ALSA_CARDS)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	cards := []ALSACard{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if params := reALSACard.FindStringSubmatch(scanner.Text()); params != nil {
+			if number, err := strconv.ParseUint(params[1], 10, 32); err == nil {
+				cards = append(cards, ALSACard{uint(number), params[2], strings.TrimSpace(params[3]), strings.TrimSpace(params[4])})
+			}
+		}
+	}
+	return cards, scanner.Err()
+}
+
+func ALSAControlDevice(card uint) string {
+	return fmt.Sprintf("%v%v", ALSA_CONTROL, card)
+}
+
+func ALSAOpenControl(card uint) (*os.File, error) {
+	return os.OpenFile(ALSAControlDevice(card), os.O_RDWR, 0)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// IOCTL CALLS
+
+// ALSAControls returns the mixer control elements for a card
+func ALSAControls(fd uintptr) ([]ALSAControl, error) {
+	// Get number of controls
+	var list C.struct_snd_ctl_elem_list
+	if err := alsa_ioctl(fd, SNDRV_CTL_IOCTL_ELEM_LIST, unsafe.Pointer(&list)); err != 0 {
+		return nil, os.NewSyscallError("alsa_ioctl", err)
+	} else if list.count == 0 {
+		return nil, nil
+	}
+
+	// Get control identifiers
+	ids := make([]C.struct_snd_ctl_elem_id, list.count)
+	list.space = list.count
+	list.pids = &ids[0]
+	if err := alsa_ioctl(fd, SNDRV_CTL_IOCTL_ELEM_LIST, unsafe.Pointer(&list)); err != 0 {
+		return nil, os.NewSyscallError("alsa_ioctl", err)
+	}
+
+	// Get information for each control
+	controls := make([]ALSAControl, 0, list.used)
+	for i := C.uint(0); i < list.used; i++ {
+		if control, err := alsaControlInfo(fd, ids[i]); err != nil {
+			return nil, err
+		} else {
+			controls = append(controls, control)
+		}
+	}
+
+	// Return success
+	return controls, nil
+}
+
+// ALSAControlValues returns the values for each channel of a boolean,
+// integer or enumerated control
+func ALSAControlValues(fd uintptr, control ALSAControl) ([]int64, error) {
+	var value C.struct_snd_ctl_elem_value
+	value.id.numid = C.uint(control.NumId)
+	if err := alsa_ioctl(fd, SNDRV_CTL_IOCTL_ELEM_READ, unsafe.Pointer(&value)); err != 0 {
+		return nil, os.NewSyscallError("alsa_ioctl", err)
+	}
+	values := make([]int64, control.Count)
+	for i := range values {
+		switch control.Type {
+		case ALSA_CONTROL_TYPE_BOOLEAN, ALSA_CONTROL_TYPE_INTEGER:
+			values[i] = int64(C._value_integer(&value, C.int(i)))
+		case ALSA_CONTROL_TYPE_INTEGER64:
+			values[i] = int64(C._value_integer64(&value, C.int(i)))
+		case ALSA_CONTROL_TYPE_ENUMERATED:
+			values[i] = int64(C._value_enumerated(&value, C.int(i)))
+		default:
+			return nil, gopi.ErrNotImplemented.WithPrefix("ALSAControlValues: ", control.Type)
+		}
+	}
+	return values, nil
+}
+
+// ALSASetControlValues sets the values for each channel of a boolean,
+// integer or enumerated control
+func ALSASetControlValues(fd uintptr, control ALSAControl, values []int64) error {
+	if uint(len(values)) != control.Count {
+		return gopi.ErrBadParameter.WithPrefix("ALSASetControlValues")
+	}
+	var value C.struct_snd_ctl_elem_value
+	value.id.numid = C.uint(control.NumId)
+	for i, v := range values {
+		switch control.Type {
+		case ALSA_CONTROL_TYPE_BOOLEAN, ALSA_CONTROL_TYPE_INTEGER:
+			C._value_set_integer(&value, C.int(i), C.long(v))
+		case ALSA_CONTROL_TYPE_INTEGER64:
+			C._value_set_integer64(&value, C.int(i), C.longlong(v))
+		case ALSA_CONTROL_TYPE_ENUMERATED:
+			C._value_set_enumerated(&value, C.int(i), C.uint(v))
+		default:
+			return gopi.ErrNotImplemented.WithPrefix("ALSASetControlValues: ", control.Type)
+		}
+	}
+	if err := alsa_ioctl(fd, SNDRV_CTL_IOCTL_ELEM_WRITE, unsafe.Pointer(&value)); err != 0 {
+		return os.NewSyscallError("alsa_ioctl", err)
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func alsaControlInfo(fd uintptr, id C.struct_snd_ctl_elem_id) (ALSAControl, error) {
+	var info C.struct_snd_ctl_elem_info
+	info.id = id
+	if err := alsa_ioctl(fd, SNDRV_CTL_IOCTL_ELEM_INFO, unsafe.Pointer(&info)); err != 0 {
+		return ALSAControl{}, os.NewSyscallError("alsa_ioctl", err)
+	}
+	control := ALSAControl{
+		NumId: uint32(info.id.numid),
+		Name:  C.GoString((*C.char)(unsafe.Pointer(&info.id.name[0]))),
+		Index: uint32(info.id.index),
+		Type:  ALSAControlType(info._type),
+		Count: uint(info.count),
+	}
+	switch control.Type {
+	case ALSA_CONTROL_TYPE_BOOLEAN:
+		control.Max = 1
+	case ALSA_CONTROL_TYPE_INTEGER:
+		control.Min, control.Max = int64(C._info_min(&info)), int64(C._info_max(&info))
+	case ALSA_CONTROL_TYPE_INTEGER64:
+		control.Min, control.Max = int64(C._info_min64(&info)), int64(C._info_max64(&info))
+	case ALSA_CONTROL_TYPE_ENUMERATED:
+		items := uint(C._info_items(&info))
+		control.Items = make([]string, 0, items)
+		for item := uint(0); item < items; item++ {
+			C._info_set_item(&info, C.uint(item))
+			if err := alsa_ioctl(fd, SNDRV_CTL_IOCTL_ELEM_INFO, unsafe.Pointer(&info)); err != 0 {
+				return ALSAControl{}, os.NewSyscallError("alsa_ioctl", err)
+			}
+			control.Items = append(control.Items, C.GoString(C._info_item_name(&info)))
+		}
+		control.Max = int64(items) - 1
+	}
+	return control, nil
+}
+
+func alsa_ioctl(fd uintptr, name uintptr, data unsafe.Pointer) syscall.Errno {
+	_, _, err := syscall.RawSyscall(syscall.SYS_IOCTL, fd, name, uintptr(data))
+	return err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (c ALSACard) String() string {
+	return fmt.Sprintf("<alsa.card number=%v id=%q driver=%q name=%q>", c.Number, c.Id, c.Driver, c.Name)
+}
+
+func (c ALSAControl) String() string {
+	str := fmt.Sprintf("<alsa.control numid=%v name=%q", c.NumId, c.Name)
+	if c.Index != 0 {
+		str += fmt.Sprint(" index=", c.Index)
+	}
+	str += fmt.Sprint(" type=", c.Type, " count=", c.Count)
+	if c.Type == ALSA_CONTROL_TYPE_INTEGER || c.Type == ALSA_CONTROL_TYPE_INTEGER64 {
+		str += fmt.Sprint(" min=", c.Min, " max=", c.Max)
+	}
+	if len(c.Items) > 0 {
+		str += fmt.Sprintf(" items=%q", c.Items)
+	}
+	return str + ">"
+}
+
+func (t ALSAControlType) String() string {
+	switch t {
+	case ALSA_CONTROL_TYPE_NONE:
+		return "ALSA_CONTROL_TYPE_NONE"
+	case ALSA_CONTROL_TYPE_BOOLEAN:
+		return "ALSA_CONTROL_TYPE_BOOLEAN"
+	case ALSA_CONTROL_TYPE_INTEGER:
+		return "ALSA_CONTROL_TYPE_INTEGER"
+	case ALSA_CONTROL_TYPE_ENUMERATED:
+		return "ALSA_CONTROL_TYPE_ENUMERATED"
+	case ALSA_CONTROL_TYPE_BYTES:
+		return "ALSA_CONTROL_TYPE_BYTES"
+	case ALSA_CONTROL_TYPE_IEC958:
+		return "ALSA_CONTROL_TYPE_IEC958"
+	case ALSA_CONTROL_TYPE_INTEGER64:
+		return "ALSA_CONTROL_TYPE_INTEGER64"
+	default:
+		return "[?? Invalid ALSAControlType value]"
+	}
+}
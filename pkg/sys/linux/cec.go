@@ -0,0 +1,163 @@
+// +build linux
+
+package linux
+
+import (
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+	#include <sys/ioctl.h>
+	#include <stdlib.h>
+	#include <string.h>
+	#include <linux/cec.h>
+	static int _CEC_ADAP_G_PHYS_ADDR() { return CEC_ADAP_G_PHYS_ADDR; }
+	static int _CEC_ADAP_G_LOG_ADDRS() { return CEC_ADAP_G_LOG_ADDRS; }
+	static int _CEC_ADAP_S_LOG_ADDRS() { return CEC_ADAP_S_LOG_ADDRS; }
+	static int _CEC_TRANSMIT() { return CEC_TRANSMIT; }
+	static int _CEC_RECEIVE() { return CEC_RECEIVE; }
+	static int _CEC_S_MODE() { return CEC_S_MODE; }
+	static void _cec_playback_log_addrs(struct cec_log_addrs* addrs, const char* name) {
+		memset(addrs, 0, sizeof(*addrs));
+		addrs->num_log_addrs = 1;
+		addrs->cec_version = CEC_OP_CEC_VERSION_1_4;
+		addrs->vendor_id = CEC_VENDOR_ID_NONE;
+		strncpy(addrs->osd_name, name, sizeof(addrs->osd_name) - 1);
+		addrs->primary_device_type[0] = CEC_OP_PRIM_DEVTYPE_PLAYBACK;
+		addrs->log_addr_type[0] = CEC_LOG_ADDR_TYPE_PLAYBACK;
+		addrs->all_device_types[0] = CEC_OP_ALL_DEVTYPE_PLAYBACK;
+	}
+*/
+import "C"
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	CEC_DEV = "/dev/cec"
+)
+
+const (
+	CEC_PHYS_ADDR_INVALID = C.CEC_PHYS_ADDR_INVALID
+	CEC_LOG_ADDR_INVALID  = C.CEC_LOG_ADDR_INVALID
+	CEC_MAX_MSG_SIZE      = C.CEC_MAX_MSG_SIZE
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// VARIABLES
+
+var (
+	CEC_ADAP_G_PHYS_ADDR = uintptr(C._CEC_ADAP_G_PHYS_ADDR())
+	CEC_ADAP_G_LOG_ADDRS = uintptr(C._CEC_ADAP_G_LOG_ADDRS())
+	CEC_ADAP_S_LOG_ADDRS = uintptr(C._CEC_ADAP_S_LOG_ADDRS())
+	CEC_TRANSMIT         = uintptr(C._CEC_TRANSMIT())
+	CEC_RECEIVE          = uintptr(C._CEC_RECEIVE())
+	CEC_S_MODE           = uintptr(C._CEC_S_MODE())
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// DEVICES
+
+// CECOpen opens a CEC adapter device, such as /dev/cec0
+func CECOpen(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_RDWR, 0)
+}
+
+// CECPhysicalAddress returns the physical address of the adapter,
+// which is CEC_PHYS_ADDR_INVALID when no TV is connected
+func CECPhysicalAddress(fd uintptr) (uint16, error) {
+	var addr C.__u16
+	if err := cec_ioctl(fd, CEC_ADAP_G_PHYS_ADDR, unsafe.Pointer(&addr)); err != 0 {
+		return CEC_PHYS_ADDR_INVALID, os.NewSyscallError("cec_ioctl", err)
+	}
+	return uint16(addr), nil
+}
+
+// CECLogicalAddress returns the first logical address claimed by the
+// adapter, or CEC_LOG_ADDR_INVALID if none is claimed
+func CECLogicalAddress(fd uintptr) (uint8, error) {
+	var addrs C.struct_cec_log_addrs
+	if err := cec_ioctl(fd, CEC_ADAP_G_LOG_ADDRS, unsafe.Pointer(&addrs)); err != 0 {
+		return CEC_LOG_ADDR_INVALID, os.NewSyscallError("cec_ioctl", err)
+	} else if addrs.num_log_addrs == 0 {
+		return CEC_LOG_ADDR_INVALID, nil
+	} else {
+		return uint8(addrs.log_addr[0]), nil
+	}
+}
+
+// CECSetPlaybackDevice claims a logical address for a playback device
+// with an on-screen name, releasing any addresses already claimed
+func CECSetPlaybackDevice(fd uintptr, name string) error {
+	var addrs C.struct_cec_log_addrs
+	if err := cec_ioctl(fd, CEC_ADAP_S_LOG_ADDRS, unsafe.Pointer(&addrs)); err != 0 {
+		return os.NewSyscallError("cec_ioctl", err)
+	}
+	name_ := C.CString(name)
+	defer C.free(unsafe.Pointer(name_))
+	C._cec_playback_log_addrs(&addrs, name_)
+	if err := cec_ioctl(fd, CEC_ADAP_S_LOG_ADDRS, unsafe.Pointer(&addrs)); err != 0 {
+		return os.NewSyscallError("cec_ioctl", err)
+	}
+	return nil
+}
+
+// CECSetFollower sets the file handle to send messages and receive
+// messages sent to the adapter
+func CECSetFollower(fd uintptr) error {
+	mode := C.__u32(C.CEC_MODE_INITIATOR | C.CEC_MODE_FOLLOWER)
+	if err := cec_ioctl(fd, CEC_S_MODE, unsafe.Pointer(&mode)); err != 0 {
+		return os.NewSyscallError("cec_ioctl", err)
+	}
+	return nil
+}
+
+// CECTransmit sends a message, where the first byte contains the
+// initiator and destination, blocking until it has been sent
+func CECTransmit(fd uintptr, msg []byte) error {
+	var m C.struct_cec_msg
+	if len(msg) == 0 || len(msg) > CEC_MAX_MSG_SIZE {
+		return os.NewSyscallError("cec_ioctl", syscall.EINVAL)
+	}
+	for i, b := range msg {
+		m.msg[i] = C.__u8(b)
+	}
+	m.len = C.__u32(len(msg))
+	if err := cec_ioctl(fd, CEC_TRANSMIT, unsafe.Pointer(&m)); err != 0 {
+		return os.NewSyscallError("cec_ioctl", err)
+	} else if m.tx_status&C.CEC_TX_STATUS_OK == 0 {
+		return os.NewSyscallError("cec_transmit", syscall.EIO)
+	}
+	return nil
+}
+
+// CECReceive returns a received message, or nil if no message was
+// received before the timeout
+func CECReceive(fd uintptr, timeout time.Duration) ([]byte, error) {
+	var m C.struct_cec_msg
+	m.timeout = C.__u32(timeout / time.Millisecond)
+	if err := cec_ioctl(fd, CEC_RECEIVE, unsafe.Pointer(&m)); err == syscall.ETIMEDOUT {
+		return nil, nil
+	} else if err != 0 {
+		return nil, os.NewSyscallError("cec_ioctl", err)
+	}
+	msg := make([]byte, int(m.len))
+	for i := range msg {
+		msg[i] = byte(m.msg[i])
+	}
+	return msg, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func cec_ioctl(fd uintptr, name uintptr, data unsafe.Pointer) syscall.Errno {
+	_, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, name, uintptr(data))
+	return err
+}
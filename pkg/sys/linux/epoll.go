@@ -32,11 +32,13 @@ const (
 )
 
 const (
-	EPOLL_MODE_EDGE   EpollMode = syscall.EPOLLPRI
-	EPOLL_MODE_READ   EpollMode = syscall.EPOLLIN
-	EPOLL_MODE_WRITE  EpollMode = syscall.EPOLLOUT
-	EPOLL_MODE_HANGUP EpollMode = syscall.EPOLLHUP
-	EPOLL_MODE_ERROR  EpollMode = syscall.EPOLLERR
+	EPOLL_MODE_READ     EpollMode = syscall.EPOLLIN
+	EPOLL_MODE_WRITE    EpollMode = syscall.EPOLLOUT
+	EPOLL_MODE_PRIORITY EpollMode = syscall.EPOLLPRI
+	EPOLL_MODE_HANGUP   EpollMode = syscall.EPOLLHUP
+	EPOLL_MODE_ERROR    EpollMode = syscall.EPOLLERR
+	EPOLL_MODE_ONESHOT  EpollMode = syscall.EPOLLONESHOT
+	EPOLL_MODE_EDGE     EpollMode = 1 << 31 // syscall.EPOLLET is signed
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -69,6 +71,17 @@ func EpollAdd(handle, fd uintptr, mode EpollMode) error {
 	}
 }
 
+func EpollModify(handle, fd uintptr, mode EpollMode) error {
+	event := new(EpollEvt)
+	event.Fd = int32(fd)
+	event.Events = uint32(mode)
+	if err := syscall.EpollCtl(int(handle), int(EPOLL_OP_MOD), int(fd), (*syscall.EpollEvent)(event)); err != nil {
+		return os.NewSyscallError("EpollModify epoll_ctl", err)
+	} else {
+		return nil
+	}
+}
+
 func EpollDelete(handle, fd uintptr) error {
 	if err := syscall.EpollCtl(int(handle), int(EPOLL_OP_DEL), int(fd), nil); err != nil {
 		return os.NewSyscallError("EpollDelete epoll_ctl", err)
@@ -125,8 +138,8 @@ func (v EpollMode) String() string {
 	if v&EPOLL_MODE_WRITE == EPOLL_MODE_WRITE {
 		str += "EPOLL_MODE_WRITE" + "|"
 	}
-	if v&EPOLL_MODE_EDGE == EPOLL_MODE_EDGE {
-		str += "EPOLL_MODE_EDGE" + "|"
+	if v&EPOLL_MODE_PRIORITY == EPOLL_MODE_PRIORITY {
+		str += "EPOLL_MODE_PRIORITY" + "|"
 	}
 	if v&EPOLL_MODE_HANGUP == EPOLL_MODE_HANGUP {
 		str += "EPOLL_MODE_HANGUP" + "|"
@@ -134,5 +147,11 @@ func (v EpollMode) String() string {
 	if v&EPOLL_MODE_ERROR == EPOLL_MODE_ERROR {
 		str += "EPOLL_MODE_ERROR" + "|"
 	}
+	if v&EPOLL_MODE_ONESHOT == EPOLL_MODE_ONESHOT {
+		str += "EPOLL_MODE_ONESHOT" + "|"
+	}
+	if v&EPOLL_MODE_EDGE == EPOLL_MODE_EDGE {
+		str += "EPOLL_MODE_EDGE" + "|"
+	}
 	return strings.TrimSuffix(str, "|")
 }
@@ -4,6 +4,7 @@ package linux_test
 
 import (
 	"testing"
+	"time"
 
 	// Frameworks
 	"github.com/djthorpe/gopi/v3/pkg/sys/linux"
@@ -16,3 +17,27 @@ func Test_Epoll_000(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func Test_Timer_000(t *testing.T) {
+	if fd, err := linux.TimerCreate(); err != nil {
+		t.Error(err)
+	} else if err := linux.TimerSet(fd, 10*time.Millisecond, false); err != nil {
+		t.Error(err)
+	} else if handle, err := linux.EpollCreate(); err != nil {
+		t.Error(err)
+	} else if err := linux.EpollAdd(handle, fd, linux.EPOLL_MODE_READ|linux.EPOLL_MODE_EDGE); err != nil {
+		t.Error(err)
+	} else if evts, err := linux.EpollWait(handle, time.Second, 1); err != nil {
+		t.Error(err)
+	} else if len(evts) != 1 || uintptr(evts[0].Fd) != fd {
+		t.Error("Unexpected events", evts)
+	} else if n, err := linux.TimerRead(fd); err != nil {
+		t.Error(err)
+	} else if n != 1 {
+		t.Error("Unexpected expiry count", n)
+	} else if err := linux.EpollClose(handle); err != nil {
+		t.Error(err)
+	} else if err := linux.TimerClose(fd); err != nil {
+		t.Error(err)
+	}
+}
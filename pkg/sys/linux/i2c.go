@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 	"syscall"
+	"time"
 	"unsafe"
 
 	"github.com/djthorpe/gopi/v3"
@@ -115,6 +116,13 @@ func I2CSetSlave(fd uintptr, slave uint8) error {
 	return i2c_ioctl(fd, I2C_SLAVE, uintptr(slave))
 }
 
+// I2CSetTimeout sets the time the adapter waits for a transfer to
+// complete, which is rounded up to units of 10ms
+func I2CSetTimeout(fd uintptr, timeout time.Duration) error {
+	units := (timeout + 10*time.Millisecond - 1) / (10 * time.Millisecond)
+	return i2c_ioctl(fd, I2C_TIMEOUT, uintptr(units))
+}
+
 func I2CDetectSlave(fd uintptr, slave uint8, funcs I2CFunction) (bool, error) {
 	if err := I2CSetSlave(fd, slave); err != nil {
 		return false, err
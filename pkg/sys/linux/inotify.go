@@ -0,0 +1,181 @@
+// +build linux
+
+package linux
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	InotifyMask uint32
+)
+
+type InotifyEvt struct {
+	Wd     int
+	Mask   InotifyMask
+	Cookie uint32
+	Name   string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	INOTIFY_MASK_ACCESS      InotifyMask = syscall.IN_ACCESS
+	INOTIFY_MASK_ATTRIB      InotifyMask = syscall.IN_ATTRIB
+	INOTIFY_MASK_CLOSE_WRITE InotifyMask = syscall.IN_CLOSE_WRITE
+	INOTIFY_MASK_CREATE      InotifyMask = syscall.IN_CREATE
+	INOTIFY_MASK_DELETE      InotifyMask = syscall.IN_DELETE
+	INOTIFY_MASK_DELETE_SELF InotifyMask = syscall.IN_DELETE_SELF
+	INOTIFY_MASK_MODIFY      InotifyMask = syscall.IN_MODIFY
+	INOTIFY_MASK_MOVE_SELF   InotifyMask = syscall.IN_MOVE_SELF
+	INOTIFY_MASK_MOVED_FROM  InotifyMask = syscall.IN_MOVED_FROM
+	INOTIFY_MASK_MOVED_TO    InotifyMask = syscall.IN_MOVED_TO
+	INOTIFY_MASK_IGNORED     InotifyMask = syscall.IN_IGNORED
+	INOTIFY_MASK_ISDIR       InotifyMask = syscall.IN_ISDIR
+	INOTIFY_MASK_OVERFLOW    InotifyMask = syscall.IN_Q_OVERFLOW
+	INOTIFY_MASK_ONLYDIR     InotifyMask = syscall.IN_ONLYDIR
+)
+
+const (
+	inotifyBufferSize = 64 * 1024
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func InotifyInit() (uintptr, error) {
+	if fd, err := syscall.InotifyInit1(syscall.IN_NONBLOCK | syscall.IN_CLOEXEC); err != nil {
+		return 0, os.NewSyscallError("inotify_init1", err)
+	} else {
+		return uintptr(fd), nil
+	}
+}
+
+func InotifyClose(handle uintptr) error {
+	if err := syscall.Close(int(handle)); err != nil {
+		return os.NewSyscallError("Close", err)
+	} else {
+		return nil
+	}
+}
+
+func InotifyAdd(handle uintptr, path string, mask InotifyMask) (int, error) {
+	if wd, err := syscall.InotifyAddWatch(int(handle), path, uint32(mask)); err != nil {
+		return -1, os.NewSyscallError("inotify_add_watch", err)
+	} else {
+		return wd, nil
+	}
+}
+
+func InotifyRemove(handle uintptr, wd int) error {
+	if _, err := syscall.InotifyRmWatch(int(handle), uint32(wd)); err != nil {
+		return os.NewSyscallError("inotify_rm_watch", err)
+	} else {
+		return nil
+	}
+}
+
+// InotifyRead returns pending events, or nil if there are none
+func InotifyRead(handle uintptr) ([]InotifyEvt, error) {
+	buf := make([]byte, inotifyBufferSize)
+	n, err := syscall.Read(int(handle), buf)
+	if err == syscall.EAGAIN || err == syscall.EINTR {
+		return nil, nil
+	} else if err != nil {
+		return nil, os.NewSyscallError("read", err)
+	}
+
+	// Decode events
+	evts := []InotifyEvt{}
+	for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+		raw := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+		offset += syscall.SizeofInotifyEvent
+		evt := InotifyEvt{Wd: int(raw.Wd), Mask: InotifyMask(raw.Mask), Cookie: raw.Cookie}
+		if raw.Len > 0 {
+			name := buf[offset : offset+int(raw.Len)]
+			evt.Name = string(bytes.TrimRight(name, "\x00"))
+			offset += int(raw.Len)
+		}
+		evts = append(evts, evt)
+	}
+
+	// Return success
+	return evts, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (evt InotifyEvt) String() string {
+	str := "<inotify.event wd=" + fmt.Sprint(evt.Wd) + " mask=" + fmt.Sprint(evt.Mask)
+	if evt.Cookie != 0 {
+		str += " cookie=" + fmt.Sprint(evt.Cookie)
+	}
+	if evt.Name != "" {
+		str += fmt.Sprintf(" name=%q", evt.Name)
+	}
+	return str + ">"
+}
+
+func (v InotifyMask) String() string {
+	if v == 0 {
+		return "INOTIFY_MASK_NONE"
+	}
+	str := ""
+	for _, flag := range []InotifyMask{
+		INOTIFY_MASK_ACCESS, INOTIFY_MASK_ATTRIB, INOTIFY_MASK_CLOSE_WRITE,
+		INOTIFY_MASK_CREATE, INOTIFY_MASK_DELETE, INOTIFY_MASK_DELETE_SELF,
+		INOTIFY_MASK_MODIFY, INOTIFY_MASK_MOVE_SELF, INOTIFY_MASK_MOVED_FROM,
+		INOTIFY_MASK_MOVED_TO, INOTIFY_MASK_IGNORED, INOTIFY_MASK_ISDIR,
+		INOTIFY_MASK_OVERFLOW, INOTIFY_MASK_ONLYDIR,
+	} {
+		if v&flag == flag {
+			str += flag.StringFlag() + "|"
+		}
+	}
+	return strings.TrimSuffix(str, "|")
+}
+
+func (v InotifyMask) StringFlag() string {
+	switch v {
+	case INOTIFY_MASK_ACCESS:
+		return "INOTIFY_MASK_ACCESS"
+	case INOTIFY_MASK_ATTRIB:
+		return "INOTIFY_MASK_ATTRIB"
+	case INOTIFY_MASK_CLOSE_WRITE:
+		return "INOTIFY_MASK_CLOSE_WRITE"
+	case INOTIFY_MASK_CREATE:
+		return "INOTIFY_MASK_CREATE"
+	case INOTIFY_MASK_DELETE:
+		return "INOTIFY_MASK_DELETE"
+	case INOTIFY_MASK_DELETE_SELF:
+		return "INOTIFY_MASK_DELETE_SELF"
+	case INOTIFY_MASK_MODIFY:
+		return "INOTIFY_MASK_MODIFY"
+	case INOTIFY_MASK_MOVE_SELF:
+		return "INOTIFY_MASK_MOVE_SELF"
+	case INOTIFY_MASK_MOVED_FROM:
+		return "INOTIFY_MASK_MOVED_FROM"
+	case INOTIFY_MASK_MOVED_TO:
+		return "INOTIFY_MASK_MOVED_TO"
+	case INOTIFY_MASK_IGNORED:
+		return "INOTIFY_MASK_IGNORED"
+	case INOTIFY_MASK_ISDIR:
+		return "INOTIFY_MASK_ISDIR"
+	case INOTIFY_MASK_OVERFLOW:
+		return "INOTIFY_MASK_OVERFLOW"
+	case INOTIFY_MASK_ONLYDIR:
+		return "INOTIFY_MASK_ONLYDIR"
+	default:
+		return "[?? Invalid InotifyMask value]"
+	}
+}
@@ -0,0 +1,263 @@
+// +build linux
+
+package linux
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	// Frameworks
+	"github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+	#include <sys/ioctl.h>
+	#include <string.h>
+	#include <limits.h>
+	#include <sound/asound.h>
+	static int _SNDRV_PCM_IOCTL_HW_PARAMS() { return SNDRV_PCM_IOCTL_HW_PARAMS; }
+	static int _SNDRV_PCM_IOCTL_PREPARE() { return SNDRV_PCM_IOCTL_PREPARE; }
+	static int _SNDRV_PCM_IOCTL_DROP() { return SNDRV_PCM_IOCTL_DROP; }
+	static int _SNDRV_PCM_IOCTL_DRAIN() { return SNDRV_PCM_IOCTL_DRAIN; }
+	static int _SNDRV_PCM_IOCTL_WRITEI_FRAMES() { return SNDRV_PCM_IOCTL_WRITEI_FRAMES; }
+	static int _SNDRV_PCM_IOCTL_READI_FRAMES() { return SNDRV_PCM_IOCTL_READI_FRAMES; }
+	static int _SNDRV_PCM_IOCTL_DELAY() { return SNDRV_PCM_IOCTL_DELAY; }
+	static void _hw_params_any(struct snd_pcm_hw_params* p) {
+		int i;
+		memset(p, 0, sizeof(*p));
+		for (i = 0; i <= SNDRV_PCM_HW_PARAM_LAST_MASK - SNDRV_PCM_HW_PARAM_FIRST_MASK; i++) {
+			memset(&p->masks[i], 0xFF, sizeof(p->masks[i]));
+		}
+		for (i = 0; i <= SNDRV_PCM_HW_PARAM_LAST_INTERVAL - SNDRV_PCM_HW_PARAM_FIRST_INTERVAL; i++) {
+			p->intervals[i].max = UINT_MAX;
+		}
+		p->rmask = ~0U;
+	}
+	static void _hw_params_mask(struct snd_pcm_hw_params* p, int param, unsigned int bit) {
+		struct snd_mask* m = &p->masks[param - SNDRV_PCM_HW_PARAM_FIRST_MASK];
+		memset(m, 0, sizeof(*m));
+		m->bits[bit >> 5] |= (1U << (bit & 31));
+	}
+	static void _hw_params_interval(struct snd_pcm_hw_params* p, int param, unsigned int value) {
+		struct snd_interval* i = &p->intervals[param - SNDRV_PCM_HW_PARAM_FIRST_INTERVAL];
+		i->min = value;
+		i->max = value;
+		i->integer = 1;
+	}
+*/
+import "C"
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	ALSA_PCM = "/dev/snd/pcmC"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// VARIABLES
+
+var (
+	SNDRV_PCM_IOCTL_HW_PARAMS     = uintptr(C._SNDRV_PCM_IOCTL_HW_PARAMS())
+	SNDRV_PCM_IOCTL_PREPARE       = uintptr(C._SNDRV_PCM_IOCTL_PREPARE())
+	SNDRV_PCM_IOCTL_DROP          = uintptr(C._SNDRV_PCM_IOCTL_DROP())
+	SNDRV_PCM_IOCTL_DRAIN         = uintptr(C._SNDRV_PCM_IOCTL_DRAIN())
+	SNDRV_PCM_IOCTL_WRITEI_FRAMES = uintptr(C._SNDRV_PCM_IOCTL_WRITEI_FRAMES())
+	SNDRV_PCM_IOCTL_READI_FRAMES  = uintptr(C._SNDRV_PCM_IOCTL_READI_FRAMES())
+	SNDRV_PCM_IOCTL_DELAY         = uintptr(C._SNDRV_PCM_IOCTL_DELAY())
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// DEVICES
+
+// PCMDevice returns the path to the playback device for a card
+func PCMDevice(card, device uint) string {
+	return fmt.Sprintf("%v%vD%vp", ALSA_PCM, card, device)
+}
+
+// PCMCaptureDevice returns the path to the capture device for a card
+func PCMCaptureDevice(card, device uint) string {
+	return fmt.Sprintf("%v%vD%vc", ALSA_PCM, card, device)
+}
+
+// PCMOpen opens a playback device for a card
+func PCMOpen(card, device uint) (*os.File, error) {
+	return os.OpenFile(PCMDevice(card, device), os.O_WRONLY, 0)
+}
+
+// PCMOpenCapture opens a capture device for a card
+func PCMOpenCapture(card, device uint) (*os.File, error) {
+	return os.OpenFile(PCMCaptureDevice(card, device), os.O_RDONLY, 0)
+}
+
+// PCMParseDevice returns the card and device numbers for a hardware
+// device name of the form hw:0, hw:0,1, hw:CARD=id or hw:CARD=id,DEV=1
+func PCMParseDevice(name string) (uint, uint, error) {
+	if strings.HasPrefix(name, "hw:") == false {
+		return 0, 0, gopi.ErrBadParameter.WithPrefix("PCMParseDevice: ", name)
+	}
+	params := strings.Split(strings.TrimPrefix(name, "hw:"), ",")
+	if len(params) > 2 {
+		return 0, 0, gopi.ErrBadParameter.WithPrefix("PCMParseDevice: ", name)
+	}
+
+	// Card is either a number or an identifier
+	var card, device uint
+	if id := strings.TrimPrefix(params[0], "CARD="); id == params[0] {
+		if n, err := strconv.ParseUint(id, 10, 32); err != nil {
+			return 0, 0, gopi.ErrBadParameter.WithPrefix("PCMParseDevice: ", name)
+		} else {
+			card = uint(n)
+		}
+	} else if cards, err := ALSACards(); err != nil {
+		return 0, 0, err
+	} else {
+		found := false
+		for _, c := range cards {
+			if c.Id == id {
+				card, found = c.Number, true
+				break
+			}
+		}
+		if found == false {
+			return 0, 0, gopi.ErrNotFound.WithPrefix("PCMParseDevice: ", name)
+		}
+	}
+
+	// Device is optional
+	if len(params) == 2 {
+		if n, err := strconv.ParseUint(strings.TrimPrefix(params[1], "DEV="), 10, 32); err != nil {
+			return 0, 0, gopi.ErrBadParameter.WithPrefix("PCMParseDevice: ", name)
+		} else {
+			device = uint(n)
+		}
+	}
+
+	// Return success
+	return card, device, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// IOCTL CALLS
+
+// PCMSetParams sets interleaved signed 16-bit samples with sample
+// rate and number of channels, and prepares the device for playback
+// or capture
+func PCMSetParams(fd uintptr, rate, channels uint) error {
+	var params C.struct_snd_pcm_hw_params
+	C._hw_params_any(&params)
+	C._hw_params_mask(&params, C.SNDRV_PCM_HW_PARAM_ACCESS, C.SNDRV_PCM_ACCESS_RW_INTERLEAVED)
+	C._hw_params_mask(&params, C.SNDRV_PCM_HW_PARAM_FORMAT, C.SNDRV_PCM_FORMAT_S16_LE)
+	C._hw_params_mask(&params, C.SNDRV_PCM_HW_PARAM_SUBFORMAT, C.SNDRV_PCM_SUBFORMAT_STD)
+	C._hw_params_interval(&params, C.SNDRV_PCM_HW_PARAM_CHANNELS, C.uint(channels))
+	C._hw_params_interval(&params, C.SNDRV_PCM_HW_PARAM_RATE, C.uint(rate))
+	C._hw_params_interval(&params, C.SNDRV_PCM_HW_PARAM_SAMPLE_BITS, 16)
+	C._hw_params_interval(&params, C.SNDRV_PCM_HW_PARAM_FRAME_BITS, C.uint(16*channels))
+	if err := pcm_ioctl(fd, SNDRV_PCM_IOCTL_HW_PARAMS, unsafe.Pointer(&params)); err != 0 {
+		return os.NewSyscallError("pcm_ioctl", err)
+	}
+	return PCMPrepare(fd)
+}
+
+// PCMPrepare prepares the device for playback
+func PCMPrepare(fd uintptr) error {
+	if err := pcm_ioctl(fd, SNDRV_PCM_IOCTL_PREPARE, nil); err != 0 {
+		return os.NewSyscallError("pcm_ioctl", err)
+	}
+	return nil
+}
+
+// PCMWrite writes interleaved samples, blocking until all samples
+// are written. The device is prepared again after an underrun
+func PCMWrite(fd uintptr, samples []int16, channels uint) error {
+	for len(samples) > 0 {
+		var xfer C.struct_snd_xferi
+		xfer.buf = unsafe.Pointer(&samples[0])
+		xfer.frames = C.snd_pcm_uframes_t(uint(len(samples)) / channels)
+		if xfer.frames == 0 {
+			return nil
+		}
+		err := pcm_ioctl(fd, SNDRV_PCM_IOCTL_WRITEI_FRAMES, unsafe.Pointer(&xfer))
+		switch err {
+		case 0:
+			samples = samples[uint(xfer.result)*channels:]
+		case syscall.EINTR, syscall.EAGAIN:
+			continue
+		case syscall.EPIPE:
+			if err := PCMPrepare(fd); err != nil {
+				return err
+			}
+		default:
+			return os.NewSyscallError("pcm_ioctl", err)
+		}
+	}
+	return nil
+}
+
+// PCMRead reads interleaved samples, blocking until the buffer is
+// full. The device is prepared again after an overrun
+func PCMRead(fd uintptr, samples []int16, channels uint) error {
+	for len(samples) > 0 {
+		var xfer C.struct_snd_xferi
+		xfer.buf = unsafe.Pointer(&samples[0])
+		xfer.frames = C.snd_pcm_uframes_t(uint(len(samples)) / channels)
+		if xfer.frames == 0 {
+			return nil
+		}
+		err := pcm_ioctl(fd, SNDRV_PCM_IOCTL_READI_FRAMES, unsafe.Pointer(&xfer))
+		switch err {
+		case 0:
+			samples = samples[uint(xfer.result)*channels:]
+		case syscall.EINTR, syscall.EAGAIN:
+			continue
+		case syscall.EPIPE:
+			if err := PCMPrepare(fd); err != nil {
+				return err
+			}
+		default:
+			return os.NewSyscallError("pcm_ioctl", err)
+		}
+	}
+	return nil
+}
+
+// PCMDrain blocks until all written samples have been played
+func PCMDrain(fd uintptr) error {
+	if err := pcm_ioctl(fd, SNDRV_PCM_IOCTL_DRAIN, nil); err != 0 {
+		return os.NewSyscallError("pcm_ioctl", err)
+	}
+	return nil
+}
+
+// PCMDrop stops playback immediately, discarding written samples
+func PCMDrop(fd uintptr) error {
+	if err := pcm_ioctl(fd, SNDRV_PCM_IOCTL_DROP, nil); err != 0 {
+		return os.NewSyscallError("pcm_ioctl", err)
+	}
+	return nil
+}
+
+// PCMDelay returns the number of frames written which have not yet
+// been played
+func PCMDelay(fd uintptr) (int, error) {
+	var frames C.snd_pcm_sframes_t
+	if err := pcm_ioctl(fd, SNDRV_PCM_IOCTL_DELAY, unsafe.Pointer(&frames)); err != 0 {
+		return 0, os.NewSyscallError("pcm_ioctl", err)
+	}
+	return int(frames), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func pcm_ioctl(fd uintptr, name uintptr, data unsafe.Pointer) syscall.Errno {
+	_, _, err := syscall.Syscall(syscall.SYS_IOCTL, fd, name, uintptr(data))
+	return err
+}
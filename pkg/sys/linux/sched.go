@@ -0,0 +1,120 @@
+// +build linux
+
+package linux
+
+import (
+	"os"
+	"unsafe"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi/v3"
+	unix "golang.org/x/sys/unix"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type SchedPolicy int
+
+type sched_param struct {
+	priority int32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	SCHED_OTHER SchedPolicy = 0
+	SCHED_FIFO  SchedPolicy = 1
+	SCHED_RR    SchedPolicy = 2
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// SchedThread returns the thread ID of the calling thread, which should be
+// locked to the goroutine with runtime.LockOSThread
+func SchedThread() int {
+	return unix.Gettid()
+}
+
+// SchedGetScheduler returns the scheduling policy and priority for a thread,
+// or the calling thread when tid is zero
+func SchedGetScheduler(tid int) (SchedPolicy, int, error) {
+	param := sched_param{}
+	if policy, _, errno := unix.RawSyscall(unix.SYS_SCHED_GETSCHEDULER, uintptr(tid), 0, 0); errno != 0 {
+		return 0, 0, os.NewSyscallError("sched_getscheduler", errno)
+	} else if _, _, errno := unix.RawSyscall(unix.SYS_SCHED_GETPARAM, uintptr(tid), uintptr(unsafe.Pointer(&param)), 0); errno != 0 {
+		return 0, 0, os.NewSyscallError("sched_getparam", errno)
+	} else {
+		return SchedPolicy(policy), int(param.priority), nil
+	}
+}
+
+// SchedSetScheduler sets the scheduling policy and priority for a thread,
+// or the calling thread when tid is zero. The priority is between 1 and 99
+// for SCHED_FIFO and SCHED_RR and zero otherwise
+func SchedSetScheduler(tid int, policy SchedPolicy, priority int) error {
+	if priority < 0 || priority > 99 {
+		return gopi.ErrBadParameter.WithPrefix("SchedSetScheduler")
+	}
+	param := sched_param{int32(priority)}
+	if _, _, errno := unix.RawSyscall(unix.SYS_SCHED_SETSCHEDULER, uintptr(tid), uintptr(policy), uintptr(unsafe.Pointer(&param))); errno != 0 {
+		return os.NewSyscallError("sched_setscheduler", errno)
+	} else {
+		return nil
+	}
+}
+
+// SchedGetAffinity returns the CPUs on which a thread may run, or the
+// calling thread when tid is zero
+func SchedGetAffinity(tid int) ([]uint, error) {
+	set := unix.CPUSet{}
+	if err := unix.SchedGetaffinity(tid, &set); err != nil {
+		return nil, os.NewSyscallError("sched_getaffinity", err)
+	}
+	cpus := []uint{}
+	for cpu, count := 0, set.Count(); count > 0; cpu++ {
+		if set.IsSet(cpu) {
+			cpus = append(cpus, uint(cpu))
+			count--
+		}
+	}
+	return cpus, nil
+}
+
+// SchedSetAffinity restricts a thread to run on one or more CPUs, or the
+// calling thread when tid is zero
+func SchedSetAffinity(tid int, cpus []uint) error {
+	if len(cpus) == 0 {
+		return gopi.ErrBadParameter.WithPrefix("SchedSetAffinity")
+	}
+	set := unix.CPUSet{}
+	for _, cpu := range cpus {
+		set.Set(int(cpu))
+	}
+	if err := unix.SchedSetaffinity(tid, &set); err != nil {
+		return os.NewSyscallError("sched_setaffinity", err)
+	} else {
+		return nil
+	}
+}
+
+// MemLock locks current and future pages of the process into memory so
+// they are never paged out
+func MemLock() error {
+	if err := unix.Mlockall(unix.MCL_CURRENT | unix.MCL_FUTURE); err != nil {
+		return os.NewSyscallError("mlockall", err)
+	} else {
+		return nil
+	}
+}
+
+// MemUnlock unlocks all pages of the process
+func MemUnlock() error {
+	if err := unix.Munlockall(); err != nil {
+		return os.NewSyscallError("munlockall", err)
+	} else {
+		return nil
+	}
+}
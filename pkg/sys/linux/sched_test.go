@@ -0,0 +1,43 @@
+// +build linux
+
+package linux_test
+
+import (
+	"runtime"
+	"testing"
+
+	// Frameworks
+	"github.com/djthorpe/gopi/v3/pkg/sys/linux"
+)
+
+func Test_Sched_000(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if policy, priority, err := linux.SchedGetScheduler(0); err != nil {
+		t.Error(err)
+	} else if err := linux.SchedSetScheduler(0, policy, priority); err != nil {
+		t.Error(err)
+	} else if err := linux.SchedSetScheduler(0, linux.SCHED_FIFO, 100); err == nil {
+		t.Error("Expected error for priority 100")
+	} else {
+		t.Log("tid=", linux.SchedThread(), " policy=", policy, " priority=", priority)
+	}
+}
+
+func Test_Sched_001(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if cpus, err := linux.SchedGetAffinity(0); err != nil {
+		t.Error(err)
+	} else if len(cpus) == 0 {
+		t.Error("Unexpected empty affinity")
+	} else if err := linux.SchedSetAffinity(0, cpus); err != nil {
+		t.Error(err)
+	} else if err := linux.SchedSetAffinity(0, nil); err == nil {
+		t.Error("Expected error for empty affinity")
+	} else {
+		t.Log("cpus=", cpus)
+	}
+}
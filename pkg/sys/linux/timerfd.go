@@ -0,0 +1,68 @@
+// +build linux
+
+package linux
+
+import (
+	"os"
+	"time"
+	"unsafe"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi/v3"
+	unix "golang.org/x/sys/unix"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// TimerCreate returns a non-blocking timer file descriptor using the
+// monotonic clock, which becomes readable when the timer expires
+func TimerCreate() (uintptr, error) {
+	if fd, err := unix.TimerfdCreate(unix.CLOCK_MONOTONIC, unix.TFD_NONBLOCK|unix.TFD_CLOEXEC); err != nil {
+		return 0, os.NewSyscallError("timerfd_create", err)
+	} else {
+		return uintptr(fd), nil
+	}
+}
+
+// TimerSet arms a timer to expire after a duration, and then
+// repeatedly with the same interval if repeat is true. A zero
+// duration disarms the timer
+func TimerSet(fd uintptr, d time.Duration, repeat bool) error {
+	if d < 0 {
+		return gopi.ErrBadParameter.WithPrefix("TimerSet")
+	}
+	spec := unix.ItimerSpec{
+		Value: unix.NsecToTimespec(int64(d)),
+	}
+	if repeat {
+		spec.Interval = spec.Value
+	}
+	if err := unix.TimerfdSettime(int(fd), 0, &spec, nil); err != nil {
+		return os.NewSyscallError("timerfd_settime", err)
+	} else {
+		return nil
+	}
+}
+
+// TimerRead returns the number of expirations since the timer was
+// last read, or zero if the timer has not expired
+func TimerRead(fd uintptr) (uint64, error) {
+	var count uint64
+	if _, err := unix.Read(int(fd), (*[8]byte)(unsafe.Pointer(&count))[:]); err == unix.EAGAIN {
+		return 0, nil
+	} else if err != nil {
+		return 0, os.NewSyscallError("read", err)
+	} else {
+		return count, nil
+	}
+}
+
+// TimerClose closes a timer file descriptor
+func TimerClose(fd uintptr) error {
+	if err := unix.Close(int(fd)); err != nil {
+		return os.NewSyscallError("close", err)
+	} else {
+		return nil
+	}
+}
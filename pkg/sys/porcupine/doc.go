@@ -0,0 +1,17 @@
+package porcupine
+
+/*
+
+This package provides Picovoice Porcupine wake word detection bindings
+
+In order to use this package, you will need the Porcupine library and
+header files for your platform installed in the default library and
+include paths, and an access key from the Picovoice console.
+
+You will also need to use -tags porcupine when testing, building or
+installing.
+
+API Documentation Sources:
+https://github.com/Picovoice/porcupine/blob/master/include/pv_porcupine.h
+
+*/
@@ -0,0 +1,111 @@
+// +build porcupine
+
+package porcupine
+
+////////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#cgo LDFLAGS: -lpv_porcupine
+#include <stdlib.h>
+#include <pv_porcupine.h>
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	Status    C.pv_status_t
+	Porcupine C.pv_porcupine_t
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTS
+
+const (
+	PV_STATUS_SUCCESS          Status = C.PV_STATUS_SUCCESS
+	PV_STATUS_OUT_OF_MEMORY    Status = C.PV_STATUS_OUT_OF_MEMORY
+	PV_STATUS_IO_ERROR         Status = C.PV_STATUS_IO_ERROR
+	PV_STATUS_INVALID_ARGUMENT Status = C.PV_STATUS_INVALID_ARGUMENT
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// New creates a detector with an access key, the path to the model
+// file, and paths to keyword files with a sensitivity for each
+// between 0.0 and 1.0
+func New(key, model string, keywords []string, sensitivities []float32) (*Porcupine, error) {
+	if len(keywords) == 0 || len(keywords) != len(sensitivities) {
+		return nil, PV_STATUS_INVALID_ARGUMENT
+	}
+
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+	cModel := C.CString(model)
+	defer C.free(unsafe.Pointer(cModel))
+
+	// Allocate keyword paths and sensitivities in C memory
+	n := len(keywords)
+	cKeywords := (*[1 << 16]*C.char)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof((*C.char)(nil)))))[:n:n]
+	defer C.free(unsafe.Pointer(&cKeywords[0]))
+	cSensitivities := (*[1 << 16]C.float)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(C.float(0)))))[:n:n]
+	defer C.free(unsafe.Pointer(&cSensitivities[0]))
+	for i := range keywords {
+		cKeywords[i] = C.CString(keywords[i])
+		defer C.free(unsafe.Pointer(cKeywords[i]))
+		cSensitivities[i] = C.float(sensitivities[i])
+	}
+
+	var handle *C.pv_porcupine_t
+	if status := Status(C.pv_porcupine_init(cKey, cModel, C.int32_t(n), &cKeywords[0], &cSensitivities[0], &handle)); status != PV_STATUS_SUCCESS {
+		return nil, status
+	} else {
+		return (*Porcupine)(handle), nil
+	}
+}
+
+// Close releases resources
+func (this *Porcupine) Close() {
+	C.pv_porcupine_delete((*C.pv_porcupine_t)(this))
+}
+
+// Process a frame of FrameLength samples, and return the index of
+// the keyword detected or -1
+func (this *Porcupine) Process(frame []int16) (int, error) {
+	if uint(len(frame)) != FrameLength() {
+		return -1, PV_STATUS_INVALID_ARGUMENT
+	}
+	var index C.int32_t
+	if status := Status(C.pv_porcupine_process((*C.pv_porcupine_t)(this), (*C.int16_t)(unsafe.Pointer(&frame[0])), &index)); status != PV_STATUS_SUCCESS {
+		return -1, status
+	} else {
+		return int(index), nil
+	}
+}
+
+// FrameLength returns the number of samples in each frame
+func FrameLength() uint {
+	return uint(C.pv_porcupine_frame_length())
+}
+
+// SampleRate returns the sample rate required
+func SampleRate() uint {
+	return uint(C.pv_sample_rate())
+}
+
+// Version returns the library version
+func Version() string {
+	return C.GoString(C.pv_porcupine_version())
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s Status) Error() string {
+	return C.GoString(C.pv_status_to_string(C.pv_status_t(s)))
+}
@@ -44,8 +44,9 @@ const (
 	GENCMD_MEASURE_TEMP      = "measure_temp"
 	GENCMD_MEASURE_CLOCK     = "measure_clock arm core h264 isp v3d uart pwm emmc pixel vec hdmi dpi"
 	GENCMD_MEASURE_VOLTS     = "measure_volts core sdram_c sdram_i sdram_p"
-	GENCMD_CODEC_ENABLED     = "codec_enabled H264 MPG2 WVC1 MPG4 MJPG WMV9 VP8"
-	GENCMD_MEMORY            = "get_mem arm gpu"
+	GENCMD_CODEC_ENABLED     = "codec_enabled H264 MPG2 WVC1 MPG4 MJPG WMV9 VP8 HEVC"
+	GENCMD_MEMORY            = "get_mem arm gpu reloc reloc_total malloc malloc_total"
+	GENCMD_CAMERA            = "get_camera"
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -64,7 +65,8 @@ var (
 	REGEXP_CLOCK    *regexp.Regexp = regexp.MustCompile("frequency\\((\\d+)\\)=(\\d+)")
 	REGEXP_VOLTAGE  *regexp.Regexp = regexp.MustCompile("volt=(\\d*\\.?\\d*)V")
 	REGEXP_CODEC    *regexp.Regexp = regexp.MustCompile("(\\w+)=(enabled|disabled)")
-	REGEXP_MEMORY   *regexp.Regexp = regexp.MustCompile("(\\w+)=(\\d+)M")
+	REGEXP_MEMORY   *regexp.Regexp = regexp.MustCompile("(\\w+)=(\\d+)([KMG]?)")
+	REGEXP_CAMERA   *regexp.Regexp = regexp.MustCompile("supported=(\\d+) detected=(\\d+)")
 	REGEXP_COMMANDS *regexp.Regexp = regexp.MustCompile("commands=\"([^\"]+)\"")
 )
 
@@ -179,3 +181,57 @@ func VCGetSerialProduct() (uint64, uint32, error) {
 		return uint64(otp[GENCMD_OTP_DUMP_SERIAL]), uint32(otp[GENCMD_OTP_DUMP_REVISION]), nil
 	}
 }
+
+// VCMemory returns memory allocated to the ARM and GPU, and the free and total
+// relocatable and malloc heaps on the GPU, in bytes
+func VCMemory() (map[string]uint64, error) {
+	fields := strings.Fields(GENCMD_MEMORY)
+	memory := make(map[string]uint64, len(fields)-1)
+	for _, field := range fields[1:] {
+		if value, err := VCGeneralCommand(fields[0] + " " + field); err != nil {
+			return nil, err
+		} else if match := REGEXP_MEMORY.FindStringSubmatch(value); len(match) != 4 || match[1] != field {
+			return nil, gopi.ErrUnexpectedResponse.WithPrefix(field)
+		} else if bytes, err := strconv.ParseUint(match[2], 10, 64); err != nil {
+			return nil, gopi.ErrUnexpectedResponse.WithPrefix(field)
+		} else {
+			switch match[3] {
+			case "K":
+				bytes <<= 10
+			case "M":
+				bytes <<= 20
+			case "G":
+				bytes <<= 30
+			}
+			memory[field] = bytes
+		}
+	}
+	return memory, nil
+}
+
+// VCCodecs returns whether each hardware codec is enabled
+func VCCodecs() (map[string]bool, error) {
+	fields := strings.Fields(GENCMD_CODEC_ENABLED)
+	codecs := make(map[string]bool, len(fields)-1)
+	for _, field := range fields[1:] {
+		if value, err := VCGeneralCommand(fields[0] + " " + field); err != nil {
+			return nil, err
+		} else if match := REGEXP_CODEC.FindStringSubmatch(value); len(match) != 3 {
+			return nil, gopi.ErrUnexpectedResponse.WithPrefix(field)
+		} else {
+			codecs[field] = match[2] == "enabled"
+		}
+	}
+	return codecs, nil
+}
+
+// VCCamera returns whether a camera is supported and detected
+func VCCamera() (bool, bool, error) {
+	if value, err := VCGeneralCommand(GENCMD_CAMERA); err != nil {
+		return false, false, err
+	} else if match := REGEXP_CAMERA.FindStringSubmatch(value); len(match) != 3 {
+		return false, false, gopi.ErrUnexpectedResponse.WithPrefix(GENCMD_CAMERA)
+	} else {
+		return match[1] != "0", match[2] != "0", nil
+	}
+}
@@ -89,3 +89,28 @@ func Test_Platform_007(t *testing.T) {
 		t.Logf("VCGetSerialProduct => %08X %04X", serial, product)
 	}
 }
+
+func Test_Platform_008(t *testing.T) {
+	if err := rpi.BCMHostInit(); err != nil {
+		t.Error("Unexpected response from BCMHostInit")
+	} else if memory, err := rpi.VCMemory(); err != nil {
+		t.Error("Unexpected response from VCMemory", err)
+	} else if memory["gpu"] == 0 {
+		t.Error("Unexpected gpu memory value", memory)
+	} else {
+		t.Logf("VCMemory => %v", memory)
+	}
+}
+
+func Test_Platform_009(t *testing.T) {
+	if err := rpi.BCMHostInit(); err != nil {
+		t.Error("Unexpected response from BCMHostInit")
+	} else if codecs, err := rpi.VCCodecs(); err != nil {
+		t.Error("Unexpected response from VCCodecs", err)
+	} else if supported, detected, err := rpi.VCCamera(); err != nil {
+		t.Error("Unexpected response from VCCamera", err)
+	} else {
+		t.Logf("VCCodecs => %v", codecs)
+		t.Logf("VCCamera => supported=%v detected=%v", supported, detected)
+	}
+}
@@ -0,0 +1,176 @@
+// +build rpi
+// +build !darwin
+
+package rpi
+
+import (
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#cgo pkg-config: bcm_host
+#include <stdlib.h>
+#include <interface/vmcs_host/vc_cecservice.h>
+
+extern void cecservice_callback(void *callback_data, uint32_t reason, uint32_t param1, uint32_t param2, uint32_t param3, uint32_t param4);
+
+static void cecservice_register_callback() {
+	vc_cec_register_callback(&cecservice_callback,NULL);
+}
+static void cecservice_unregister_callback() {
+	vc_cec_register_callback(NULL,NULL);
+}
+*/
+import "C"
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	CECReason        uint32
+	CECEventCallback func(CECReason, []byte)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONST
+
+const (
+	CEC_REASON_NONE              CECReason = 0
+	CEC_REASON_TX                CECReason = 1 << 0  // Message has been transmitted
+	CEC_REASON_RX                CECReason = 1 << 1  // Message has been received
+	CEC_REASON_BUTTON_PRESSED    CECReason = 1 << 2  // User control pressed message received
+	CEC_REASON_BUTTON_RELEASE    CECReason = 1 << 3  // User control released message received
+	CEC_REASON_REMOTE_PRESSED    CECReason = 1 << 4  // Vendor remote button pressed message received
+	CEC_REASON_REMOTE_RELEASE    CECReason = 1 << 5  // Vendor remote button released message received
+	CEC_REASON_LOGICAL_ADDR      CECReason = 1 << 6  // Logical address has been allocated
+	CEC_REASON_TOPOLOGY          CECReason = 1 << 7  // Topology has changed
+	CEC_REASON_LOGICAL_ADDR_LOST CECReason = 1 << 15 // Logical address has been lost
+)
+
+const (
+	CEC_MAX_MSG_SIZE = 16
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	ceccallback CECEventCallback
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC FUNCTIONS
+
+func VCHI_CECInit(instance VCHIInstance) (VCHIConnection, error) {
+	var connection VCHIConnection
+	if err := C.vchi_connect(nil, 0, C.VCHI_INSTANCE_T(instance)); err != 0 {
+		return connection, ErrConnectError
+	}
+	C.vc_vchi_cec_init(C.VCHI_INSTANCE_T(instance), (**C.VCHI_CONNECTION_T)(unsafe.Pointer(&connection)), 1)
+	return connection, nil
+}
+
+func VCHI_CECStop(instance VCHIInstance) error {
+	C.cecservice_unregister_callback()
+	C.vc_vchi_cec_stop()
+	C.vchi_disconnect(C.VCHI_INSTANCE_T(instance))
+	ceccallback = nil
+	return nil
+}
+
+// VCCEC_SendMessage sends a message to a logical address, where
+// the payload consists of the opcode and operands
+func VCCEC_SendMessage(dest uint8, payload []byte, reply bool) error {
+	reply_ := C.vcos_bool_t(0)
+	if reply {
+		reply_ = C.vcos_bool_t(1)
+	}
+	if len(payload) == 0 {
+		if err := C.vc_cec_send_message(C.uint32_t(dest), nil, 0, reply_); err != 0 {
+			return ErrDeviceError
+		}
+	} else if len(payload) >= CEC_MAX_MSG_SIZE {
+		return ErrDeviceError
+	} else if err := C.vc_cec_send_message(C.uint32_t(dest), (*C.uint8_t)(unsafe.Pointer(&payload[0])), C.uint32_t(len(payload)), reply_); err != 0 {
+		return ErrDeviceError
+	}
+	return nil
+}
+
+func VCCEC_GetPhysicalAddress() (uint16, error) {
+	var addr C.uint16_t
+	if err := C.vc_cec_get_physical_address(&addr); err != 0 {
+		return 0xFFFF, ErrDeviceError
+	} else {
+		return uint16(addr), nil
+	}
+}
+
+func VCCEC_GetLogicalAddress() (uint8, error) {
+	var addr C.CEC_AllDevices_T
+	if err := C.vc_cec_get_logical_address(&addr); err != 0 {
+		return 0x0F, ErrDeviceError
+	} else {
+		return uint8(addr), nil
+	}
+}
+
+func VCCEC_SetOSDName(name string) error {
+	name_ := C.CString(name)
+	defer C.free(unsafe.Pointer(name_))
+	if err := C.vc_cec_set_osd_name(name_); err != 0 {
+		return ErrDeviceError
+	} else {
+		return nil
+	}
+}
+
+// VCCEC_RegisterAll requests all messages are passed to the
+// callback rather than handled by the firmware
+func VCCEC_RegisterAll() error {
+	if err := C.vc_cec_register_all(); err != 0 {
+		return ErrDeviceError
+	} else {
+		return nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Watch Events
+
+// VCCEC_RegisterCallback can be called to register and unregister a callback
+// which is called with received messages, including the header byte
+func VCCEC_RegisterCallback(fn CECEventCallback) {
+	if ceccallback != nil {
+		C.cecservice_unregister_callback()
+		ceccallback = nil
+	}
+	if fn != nil {
+		C.cecservice_register_callback()
+		ceccallback = fn
+	}
+}
+
+//export cecservice_callback
+func cecservice_callback(data unsafe.Pointer, reason, param1, param2, param3, param4 C.uint32_t) {
+	if ceccallback == nil {
+		return
+	}
+
+	// The lower 16 bits are the reason and the message length
+	// is in the next eight bits. Message bytes are packed into
+	// the parameters in little-endian order
+	length := int(reason>>16) & 0xFF
+	if length > CEC_MAX_MSG_SIZE {
+		length = CEC_MAX_MSG_SIZE
+	}
+	params := []C.uint32_t{param1, param2, param3, param4}
+	msg := make([]byte, length)
+	for i := range msg {
+		msg[i] = byte(params[i>>2] >> (uint(i&3) << 3))
+	}
+	ceccallback(CECReason(reason&0xFFFF), msg)
+}
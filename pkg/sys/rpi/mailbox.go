@@ -0,0 +1,129 @@
+// +build rpi
+// +build !darwin
+
+package rpi
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+
+	// Frameworks
+	"github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type MemFlag uint32
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	MAILBOX_DEV = "/dev/vcio"
+)
+
+const (
+	MAILBOX_TAG_MEM_ALLOC  = 0x3000C
+	MAILBOX_TAG_MEM_LOCK   = 0x3000D
+	MAILBOX_TAG_MEM_UNLOCK = 0x3000E
+	MAILBOX_TAG_MEM_FREE   = 0x3000F
+	MAILBOX_REQUEST        = 0x00000000
+	MAILBOX_RESPONSE_OK    = 0x80000000
+)
+
+const (
+	MEM_FLAG_DISCARDABLE      MemFlag = 1 << 0                              // Can be resized to 0 at any time
+	MEM_FLAG_NORMAL           MemFlag = 0 << 2                              // Normal allocating alias
+	MEM_FLAG_DIRECT           MemFlag = 1 << 2                              // Uncached alias
+	MEM_FLAG_COHERENT         MemFlag = 2 << 2                              // Non-allocating in L2 but coherent
+	MEM_FLAG_L1_NONALLOCATING MemFlag = MEM_FLAG_DIRECT | MEM_FLAG_COHERENT // Allocating in L2
+	MEM_FLAG_ZERO             MemFlag = 1 << 4                              // Initialise buffer to all zeros
+	MEM_FLAG_NO_INIT          MemFlag = 1 << 5                              // Don't initialise
+	MEM_FLAG_HINT_PERMALOCK   MemFlag = 1 << 6                              // Likely to be locked for long periods
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// VARIABLES
+
+var (
+	// _IOWR(100, 0, char*)
+	MAILBOX_IOCTL_PROPERTY = uintptr(3<<30 | unsafe.Sizeof(uintptr(0))<<16 | 100<<8)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// MailboxOpen opens the VideoCore mailbox, which requires root
+func MailboxOpen() (*os.File, error) {
+	return os.OpenFile(MAILBOX_DEV, os.O_RDWR, 0)
+}
+
+// MailboxMemAlloc allocates contiguous memory from the GPU with alignment
+// and flags, and returns a handle or an error
+func MailboxMemAlloc(fd uintptr, size, align uint32, flags MemFlag) (uint32, error) {
+	if handle, err := mailboxProperty(fd, MAILBOX_TAG_MEM_ALLOC, size, align, uint32(flags)); err != nil {
+		return 0, err
+	} else if handle == 0 {
+		return 0, gopi.ErrInternalAppError.WithPrefix("MailboxMemAlloc")
+	} else {
+		return handle, nil
+	}
+}
+
+// MailboxMemLock locks memory in place and returns the bus address
+func MailboxMemLock(fd uintptr, handle uint32) (uint32, error) {
+	if addr, err := mailboxProperty(fd, MAILBOX_TAG_MEM_LOCK, handle); err != nil {
+		return 0, err
+	} else if addr == 0 {
+		return 0, gopi.ErrInternalAppError.WithPrefix("MailboxMemLock")
+	} else {
+		return addr, nil
+	}
+}
+
+// MailboxMemUnlock unlocks memory so it can be moved
+func MailboxMemUnlock(fd uintptr, handle uint32) error {
+	if status, err := mailboxProperty(fd, MAILBOX_TAG_MEM_UNLOCK, handle); err != nil {
+		return err
+	} else if status != 0 {
+		return gopi.ErrUnexpectedResponse.WithPrefix("MailboxMemUnlock")
+	} else {
+		return nil
+	}
+}
+
+// MailboxMemFree frees memory
+func MailboxMemFree(fd uintptr, handle uint32) error {
+	if status, err := mailboxProperty(fd, MAILBOX_TAG_MEM_FREE, handle); err != nil {
+		return err
+	} else if status != 0 {
+		return gopi.ErrUnexpectedResponse.WithPrefix("MailboxMemFree")
+	} else {
+		return nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// mailboxProperty sends a property tag with arguments and returns the
+// first word of the response
+func mailboxProperty(fd uintptr, tag uint32, args ...uint32) (uint32, error) {
+	// Message is size, request code, tag, value buffer size, request size,
+	// arguments and end tag
+	buf := make([]uint32, 0, 6+len(args))
+	buf = append(buf, 0, MAILBOX_REQUEST, tag, uint32(len(args)*4), uint32(len(args)*4))
+	buf = append(buf, args...)
+	buf = append(buf, 0)
+	buf[0] = uint32(len(buf) * 4)
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, MAILBOX_IOCTL_PROPERTY, uintptr(unsafe.Pointer(&buf[0]))); errno != 0 {
+		return 0, os.NewSyscallError("ioctl", errno)
+	} else if buf[1] != MAILBOX_RESPONSE_OK {
+		return 0, gopi.ErrUnexpectedResponse.WithPrefix("mailbox tag ", tag)
+	} else {
+		return buf[5], nil
+	}
+}
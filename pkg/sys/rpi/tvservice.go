@@ -52,6 +52,7 @@ const (
 
 const (
 	TV_MAX_ATTACHED_DISPLAYS = 16
+	TV_EDID_BLOCK_SIZE       = 128
 )
 
 const (
@@ -186,6 +187,28 @@ func VCHI_TVGetDisplayInfo(display DXDisplayId) (TVDisplayInfo, error) {
 	}
 }
 
+// VCHI_TVGetEDID returns the EDID data for a display, including
+// any extension blocks
+func VCHI_TVGetEDID(display DXDisplayId) ([]byte, error) {
+	buf := make([]byte, TV_EDID_BLOCK_SIZE)
+	if n := C.vc_tv_hdmi_ddc_read_id(C.uint32_t(display), 0, C.uint32_t(TV_EDID_BLOCK_SIZE), unsafe.Pointer(&buf[0])); n != C.int(TV_EDID_BLOCK_SIZE) {
+		return nil, ErrDeviceError
+	}
+
+	// Read extension blocks, the number of which is in byte 126
+	for i := 1; i <= int(buf[126]); i++ {
+		block := make([]byte, TV_EDID_BLOCK_SIZE)
+		offset := C.uint32_t(i * TV_EDID_BLOCK_SIZE)
+		if n := C.vc_tv_hdmi_ddc_read_id(C.uint32_t(display), offset, C.uint32_t(TV_EDID_BLOCK_SIZE), unsafe.Pointer(&block[0])); n != C.int(TV_EDID_BLOCK_SIZE) {
+			break
+		}
+		buf = append(buf, block...)
+	}
+
+	// Return success
+	return buf, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Watch Events
 
@@ -91,3 +91,24 @@ func Test_TVService_004(t *testing.T) {
 	fmt.Println("Waiting for event")
 	time.Sleep(60 * time.Second)
 }
+
+func Test_TVService_005(t *testing.T) {
+	instance := rpi.VCHI_Init()
+	if instance == nil {
+		t.Fatal("VCHI_Init failed")
+	} else if _, err := rpi.VCHI_TVInit(instance); err != nil {
+		t.Fatal("VCHI_TVInit failed: ", err)
+	}
+	defer rpi.VCHI_TVStop(instance)
+	if displays, err := rpi.VCHI_TVGetAttachedDevices(); err != nil {
+		t.Error("VCHI_TVGetAttachedDevices failed: ", err)
+	} else {
+		for _, display := range displays {
+			if edid, err := rpi.VCHI_TVGetEDID(display); err != nil {
+				t.Log(display, "VCHI_TVGetEDID: ", err)
+			} else {
+				t.Logf("%v EDID=% X", display, edid)
+			}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package rtlsdr
+
+/*
+
+This package provides librtlsdr bindings for RTL2832U based software
+defined radio receivers
+
+In order to use this package, you will need to install the librtlsdr
+development libraries. On Darwin (Mac) with Homebrew installed:
+
+% brew install librtlsdr
+
+For debian,
+
+% sudo apt install librtlsdr-dev
+
+You will also need to use -tags rtlsdr when testing, building or
+installing.
+
+API Documentation Sources:
+https://github.com/osmocom/rtl-sdr/blob/master/include/rtl-sdr.h
+
+*/
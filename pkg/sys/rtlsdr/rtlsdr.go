@@ -0,0 +1,181 @@
+// +build rtlsdr
+
+package rtlsdr
+
+////////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#cgo pkg-config: librtlsdr
+#include <rtl-sdr.h>
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	Error  int
+	Device C.rtlsdr_dev_t
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// Length of manufacturer, product and serial strings
+	usbStringLength = 256
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// DeviceCount returns the number of attached devices
+func DeviceCount() uint {
+	return uint(C.rtlsdr_get_device_count())
+}
+
+// DeviceName returns the name of a device
+func DeviceName(index uint) string {
+	return C.GoString(C.rtlsdr_get_device_name(C.uint32_t(index)))
+}
+
+// DeviceStrings returns the manufacturer, product and serial number of
+// a device
+func DeviceStrings(index uint) (string, string, string, error) {
+	var manufacturer, product, serial [usbStringLength]C.char
+	if err := C.rtlsdr_get_device_usb_strings(C.uint32_t(index), &manufacturer[0], &product[0], &serial[0]); err != 0 {
+		return "", "", "", Error(err)
+	}
+	return C.GoString(&manufacturer[0]), C.GoString(&product[0]), C.GoString(&serial[0]), nil
+}
+
+// Open returns a device by index
+func Open(index uint) (*Device, error) {
+	var dev *C.rtlsdr_dev_t
+	if err := C.rtlsdr_open(&dev, C.uint32_t(index)); err != 0 {
+		return nil, Error(err)
+	}
+	return (*Device)(dev), nil
+}
+
+// Close releases the device
+func (this *Device) Close() error {
+	if err := C.rtlsdr_close((*C.rtlsdr_dev_t)(this)); err != 0 {
+		return Error(err)
+	}
+	return nil
+}
+
+// CenterFreq returns the tuned frequency in Hz
+func (this *Device) CenterFreq() uint32 {
+	return uint32(C.rtlsdr_get_center_freq((*C.rtlsdr_dev_t)(this)))
+}
+
+// SetCenterFreq tunes the device to a frequency in Hz
+func (this *Device) SetCenterFreq(freq uint32) error {
+	if err := C.rtlsdr_set_center_freq((*C.rtlsdr_dev_t)(this), C.uint32_t(freq)); err != 0 {
+		return Error(err)
+	}
+	return nil
+}
+
+// SetFreqCorrection sets the frequency correction in parts per million
+func (this *Device) SetFreqCorrection(ppm int) error {
+	// Returns -2 when the correction is unchanged
+	if err := C.rtlsdr_set_freq_correction((*C.rtlsdr_dev_t)(this), C.int(ppm)); err != 0 && err != -2 {
+		return Error(err)
+	}
+	return nil
+}
+
+// SampleRate returns the sample rate in Hz
+func (this *Device) SampleRate() uint32 {
+	return uint32(C.rtlsdr_get_sample_rate((*C.rtlsdr_dev_t)(this)))
+}
+
+// SetSampleRate sets the sample rate in Hz
+func (this *Device) SetSampleRate(rate uint32) error {
+	if err := C.rtlsdr_set_sample_rate((*C.rtlsdr_dev_t)(this), C.uint32_t(rate)); err != 0 {
+		return Error(err)
+	}
+	return nil
+}
+
+// TunerGains returns the gains supported by the tuner in tenths of a dB
+func (this *Device) TunerGains() []int {
+	dev := (*C.rtlsdr_dev_t)(this)
+	n := C.rtlsdr_get_tuner_gains(dev, nil)
+	if n <= 0 {
+		return nil
+	}
+	gains := make([]C.int, int(n))
+	C.rtlsdr_get_tuner_gains(dev, &gains[0])
+	result := make([]int, len(gains))
+	for i, gain := range gains {
+		result[i] = int(gain)
+	}
+	return result
+}
+
+// SetTunerGain sets manual gain in tenths of a dB, or automatic gain
+// when the gain is zero
+func (this *Device) SetTunerGain(gain int) error {
+	dev := (*C.rtlsdr_dev_t)(this)
+	if gain == 0 {
+		if err := C.rtlsdr_set_tuner_gain_mode(dev, 0); err != 0 {
+			return Error(err)
+		}
+	} else if err := C.rtlsdr_set_tuner_gain_mode(dev, 1); err != 0 {
+		return Error(err)
+	} else if err := C.rtlsdr_set_tuner_gain(dev, C.int(gain)); err != 0 {
+		return Error(err)
+	}
+	return nil
+}
+
+// SetAGCMode enables or disables the RTL2832 digital AGC
+func (this *Device) SetAGCMode(on bool) error {
+	value := C.int(0)
+	if on {
+		value = 1
+	}
+	if err := C.rtlsdr_set_agc_mode((*C.rtlsdr_dev_t)(this), value); err != 0 {
+		return Error(err)
+	}
+	return nil
+}
+
+// ResetBuffer discards samples in the buffer, and must be called
+// before reading
+func (this *Device) ResetBuffer() error {
+	if err := C.rtlsdr_reset_buffer((*C.rtlsdr_dev_t)(this)); err != 0 {
+		return Error(err)
+	}
+	return nil
+}
+
+// Read reads interleaved 8-bit unsigned I and Q samples into a buffer,
+// which should be a multiple of 512 bytes, and returns the number of
+// bytes read
+func (this *Device) Read(buf []byte) (int, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	var n C.int
+	if err := C.rtlsdr_read_sync((*C.rtlsdr_dev_t)(this), unsafe.Pointer(&buf[0]), C.int(len(buf)), &n); err != 0 {
+		return int(n), Error(err)
+	}
+	return int(n), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (e Error) Error() string {
+	return fmt.Sprint("rtlsdr error ", int(e))
+}
@@ -0,0 +1,45 @@
+// +build rtlsdr
+
+package rtlsdr_test
+
+import (
+	"testing"
+
+	rtlsdr "github.com/djthorpe/gopi/v3/pkg/sys/rtlsdr"
+)
+
+func Test_RTLSDR_001(t *testing.T) {
+	for i := uint(0); i < rtlsdr.DeviceCount(); i++ {
+		if manufacturer, product, serial, err := rtlsdr.DeviceStrings(i); err != nil {
+			t.Error(err)
+		} else {
+			t.Log(i, rtlsdr.DeviceName(i), manufacturer, product, serial)
+		}
+	}
+}
+
+func Test_RTLSDR_002(t *testing.T) {
+	if rtlsdr.DeviceCount() == 0 {
+		t.Skip("No devices")
+	}
+	dev, err := rtlsdr.Open(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+	if err := dev.SetSampleRate(250000); err != nil {
+		t.Error(err)
+	} else if err := dev.SetCenterFreq(433920000); err != nil {
+		t.Error(err)
+	} else if err := dev.SetTunerGain(0); err != nil {
+		t.Error(err)
+	} else if err := dev.ResetBuffer(); err != nil {
+		t.Error(err)
+	}
+	buf := make([]byte, 16*512)
+	if n, err := dev.Read(buf); err != nil {
+		t.Error(err)
+	} else {
+		t.Log("freq=", dev.CenterFreq(), "rate=", dev.SampleRate(), "gains=", dev.TunerGains(), "read=", n)
+	}
+}
@@ -0,0 +1,25 @@
+// +build whisper
+
+package whisper
+
+////////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#include <stdbool.h>
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CALLBACK
+
+//export whisper_abort
+func whisper_abort(data unsafe.Pointer) C.bool {
+	lock.Lock()
+	fn := handles[uintptr(data)]
+	lock.Unlock()
+	return C.bool(fn != nil && fn())
+}
@@ -0,0 +1,17 @@
+package whisper
+
+/*
+
+This package provides whisper.cpp speech recognition bindings
+
+In order to use this package, you will need to build and install
+whisper.cpp, which installs the library and a pkg-config file, and
+download a model such as ggml-base.en.bin
+
+You will also need to use -tags whisper when testing, building or
+installing.
+
+API Documentation Sources:
+https://github.com/ggerganov/whisper.cpp/blob/master/include/whisper.h
+
+*/
@@ -0,0 +1,144 @@
+// +build whisper
+
+package whisper
+
+////////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#cgo pkg-config: whisper
+#include <stdlib.h>
+#include <stdint.h>
+#include <whisper.h>
+
+extern _Bool whisper_abort(void*);
+static void _whisper_set_abort(struct whisper_full_params* params, uintptr_t handle) {
+	params->abort_callback = whisper_abort;
+	params->abort_callback_user_data = (void*)handle;
+}
+*/
+import "C"
+import (
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	Error   int
+	Context C.struct_whisper_context
+
+	// AbortFunc returns true to stop transcription
+	AbortFunc func() bool
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTS
+
+const (
+	SAMPLE_RATE = C.WHISPER_SAMPLE_RATE
+)
+
+const (
+	errNone Error = iota
+	errModel
+	errTranscribe
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	lock    sync.Mutex
+	handle  uintptr
+	handles = make(map[uintptr]AbortFunc)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// New loads a model from a file
+func New(model string) (*Context, error) {
+	cModel := C.CString(model)
+	defer C.free(unsafe.Pointer(cModel))
+	if ctx := C.whisper_init_from_file_with_params(cModel, C.whisper_context_default_params()); ctx == nil {
+		return nil, errModel
+	} else {
+		return (*Context)(ctx), nil
+	}
+}
+
+// Close releases resources
+func (this *Context) Close() {
+	C.whisper_free((*C.struct_whisper_context)(this))
+}
+
+// Transcribe mono samples at SAMPLE_RATE, with a language code such as
+// "en" or "auto", and the number of threads. The abort function is
+// called periodically and can return true to stop transcription
+func (this *Context) Transcribe(samples []float32, language string, threads uint, abort AbortFunc) (string, error) {
+	if len(samples) == 0 {
+		return "", nil
+	}
+
+	cLanguage := C.CString(language)
+	defer C.free(unsafe.Pointer(cLanguage))
+
+	// Set parameters
+	params := C.whisper_full_default_params(C.WHISPER_SAMPLING_GREEDY)
+	params.language = cLanguage
+	params.n_threads = C.int(threads)
+	params.print_progress = false
+	params.print_realtime = false
+	params.print_timestamps = false
+
+	// Register the abort function with a handle which is passed to
+	// the callback
+	if abort != nil {
+		lock.Lock()
+		handle++
+		h := handle
+		handles[h] = abort
+		lock.Unlock()
+		defer func() {
+			lock.Lock()
+			delete(handles, h)
+			lock.Unlock()
+		}()
+		C._whisper_set_abort(&params, C.uintptr_t(h))
+	}
+
+	// Transcribe
+	ctx := (*C.struct_whisper_context)(this)
+	if C.whisper_full(ctx, params, (*C.float)(unsafe.Pointer(&samples[0])), C.int(len(samples))) != 0 {
+		return "", errTranscribe
+	}
+
+	// Concatenate segments
+	var text strings.Builder
+	for i := C.int(0); i < C.whisper_full_n_segments(ctx); i++ {
+		text.WriteString(C.GoString(C.whisper_full_get_segment_text(ctx, i)))
+	}
+
+	// Return success
+	return strings.TrimSpace(text.String()), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (e Error) Error() string {
+	switch e {
+	case errNone:
+		return "No error"
+	case errModel:
+		return "Unable to load model"
+	case errTranscribe:
+		return "Transcription failed"
+	default:
+		return "[?? Invalid Error value]"
+	}
+}
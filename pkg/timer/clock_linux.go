@@ -0,0 +1,81 @@
+// +build linux
+
+package timer
+
+import (
+	"sync"
+	"time"
+
+	linux "github.com/djthorpe/gopi/v3/pkg/sys/linux"
+	unix "golang.org/x/sys/unix"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// clock uses a blocking timerfd, which the kernel keeps in phase
+// with the start time and which counts expirations
+type clock struct {
+	sync.Mutex
+
+	fd      uintptr
+	stopped bool
+	closed  bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newClock(d time.Duration, repeat bool) (*clock, error) {
+	this := new(clock)
+	if fd, err := linux.TimerCreate(); err != nil {
+		return nil, err
+	} else if err := unix.SetNonblock(int(fd), false); err != nil {
+		linux.TimerClose(fd)
+		return nil, err
+	} else if err := linux.TimerSet(fd, d, repeat); err != nil {
+		linux.TimerClose(fd)
+		return nil, err
+	} else {
+		this.fd = fd
+	}
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Wait blocks until the clock expires and returns the number of
+// expirations, or zero when the clock has been stopped
+func (this *clock) Wait() (uint64, error) {
+	n, err := linux.TimerRead(this.fd)
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.stopped {
+		return 0, nil
+	} else {
+		return n, err
+	}
+}
+
+// Stop wakes any waiting goroutine, which returns zero expirations
+func (this *clock) Stop() {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.stopped == false && this.closed == false {
+		this.stopped = true
+		linux.TimerSet(this.fd, time.Nanosecond, false)
+	}
+}
+
+// Close releases the clock once it is no longer waited on
+func (this *clock) Close() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.closed {
+		return nil
+	}
+	this.closed = true
+	return linux.TimerClose(this.fd)
+}
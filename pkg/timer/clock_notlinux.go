@@ -0,0 +1,62 @@
+// +build !linux
+
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// clock schedules expirations relative to the start time, using
+// the monotonic clock reading in time.Time
+type clock struct {
+	interval time.Duration
+	repeat   bool
+	next     time.Time
+	stop     chan struct{}
+	once     sync.Once
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newClock(d time.Duration, repeat bool) (*clock, error) {
+	return &clock{interval: d, repeat: repeat, next: time.Now().Add(d), stop: make(chan struct{})}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Wait blocks until the clock expires and returns the number of
+// expirations, or zero when the clock has been stopped
+func (this *clock) Wait() (uint64, error) {
+	timer := time.NewTimer(time.Until(this.next))
+	defer timer.Stop()
+
+	select {
+	case <-this.stop:
+		return 0, nil
+	case now := <-timer.C:
+		n := uint64(1)
+		if this.repeat {
+			n += uint64(now.Sub(this.next) / this.interval)
+		}
+		this.next = this.next.Add(time.Duration(n) * this.interval)
+		return n, nil
+	}
+}
+
+// Stop wakes any waiting goroutine, which returns zero expirations
+func (this *clock) Stop() {
+	this.once.Do(func() {
+		close(this.stop)
+	})
+}
+
+// Close releases the clock
+func (this *clock) Close() error {
+	return nil
+}
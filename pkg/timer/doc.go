@@ -0,0 +1,10 @@
+// Timer package provides one-shot and repeating timers using the
+// monotonic clock. Ticks are scheduled relative to when the timer
+// was started rather than when the last tick was handled, so they do
+// not drift, and ticks which could not be delivered in time are
+// reported as missed rather than queued.
+//
+// On Linux each timer uses a timerfd with CLOCK_MONOTONIC, which
+// provides microsecond-level precision where the kernel allows.
+// Elsewhere the go runtime timers are used.
+package timer
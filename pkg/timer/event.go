@@ -0,0 +1,64 @@
+package timer
+
+import (
+	"fmt"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	id     gopi.TimerId
+	ticks  uint64
+	missed uint64
+	ts     time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(id gopi.TimerId, ticks, missed uint64, ts time.Time) gopi.TimerEvent {
+	return &event{id, ticks, missed, ts}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return fmt.Sprint("timer.", this.id)
+}
+
+func (this *event) Id() gopi.TimerId {
+	return this.id
+}
+
+func (this *event) Ticks() uint64 {
+	return this.ticks
+}
+
+func (this *event) Missed() uint64 {
+	return this.missed
+}
+
+func (this *event) Time() time.Time {
+	return this.ts
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<event.timer"
+	str += " id=" + fmt.Sprint(this.id)
+	str += " ticks=" + fmt.Sprint(this.ticks)
+	if this.missed != 0 {
+		str += " missed=" + fmt.Sprint(this.missed)
+	}
+	if this.ts.IsZero() == false {
+		str += " ts=" + this.ts.Format(time.RFC3339Nano)
+	}
+	return str + ">"
+}
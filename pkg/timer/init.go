@@ -0,0 +1,13 @@
+package timer
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register timer
+	graph.RegisterUnit(reflect.TypeOf(&timer{}), reflect.TypeOf((*gopi.Timer)(nil)))
+}
@@ -0,0 +1,154 @@
+package timer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type timer struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	sync.Mutex
+	sync.WaitGroup
+
+	id     gopi.TimerId
+	timers map[gopi.TimerId]*clock
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func (this *timer) New(gopi.Config) error {
+	this.Require(this.Logger)
+
+	this.timers = make(map[gopi.TimerId]*clock)
+
+	// Return success
+	return nil
+}
+
+func (this *timer) Dispose() error {
+	// Stop all timers
+	this.Mutex.Lock()
+	for id, clock := range this.timers {
+		clock.Stop()
+		delete(this.timers, id)
+	}
+	this.Mutex.Unlock()
+
+	// Wait for timers to end
+	this.WaitGroup.Wait()
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *timer) String() string {
+	str := "<timer"
+	str += " timers=" + fmt.Sprint(len(this.timers))
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *timer) NewTicker(d time.Duration, fn gopi.TimerFunc) (gopi.TimerId, error) {
+	return this.start(d, true, fn)
+}
+
+func (this *timer) NewTimer(d time.Duration, fn gopi.TimerFunc) (gopi.TimerId, error) {
+	return this.start(d, false, fn)
+}
+
+func (this *timer) Cancel(id gopi.TimerId) error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if clock, exists := this.timers[id]; exists == false {
+		return gopi.ErrNotFound.WithPrefix("Cancel: ", id)
+	} else {
+		clock.Stop()
+		delete(this.timers, id)
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *timer) start(d time.Duration, repeat bool, fn gopi.TimerFunc) (gopi.TimerId, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	// Check parameters
+	if d <= 0 {
+		return 0, gopi.ErrBadParameter.WithPrefix("Duration: ", d)
+	} else if fn == nil && this.Publisher == nil {
+		return 0, gopi.ErrInternalAppError.WithPrefix("Missing gopi.Publisher")
+	}
+
+	// Create clock
+	clock, err := newClock(d, repeat)
+	if err != nil {
+		return 0, err
+	}
+
+	// Allocate identifier, which is never zero
+	this.id++
+	id := this.id
+	this.timers[id] = clock
+
+	// Start the timer in the background
+	this.WaitGroup.Add(1)
+	go func() {
+		defer this.WaitGroup.Done()
+		this.run(id, clock, repeat, fn)
+	}()
+
+	// Return success
+	return id, nil
+}
+
+func (this *timer) run(id gopi.TimerId, clock *clock, repeat bool, fn gopi.TimerFunc) {
+	defer clock.Close()
+
+	ticks := uint64(0)
+	for {
+		n, err := clock.Wait()
+		if err != nil {
+			this.Print("Timer: ", err)
+			return
+		} else if n == 0 {
+			return
+		}
+
+		// Call function or emit event
+		ticks += n
+		evt := NewEvent(id, ticks, n-1, time.Now())
+		if fn != nil {
+			fn(evt)
+		} else if err := this.Publisher.Emit(evt, false); err != nil {
+			this.Debug("Timer: ", err)
+		}
+
+		// One-shot timers are removed once fired
+		if repeat == false {
+			this.Mutex.Lock()
+			delete(this.timers, id)
+			this.Mutex.Unlock()
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package timer_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/timer"
+)
+
+type App struct {
+	gopi.Unit
+	gopi.Timer
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func Test_Timer_001(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		if app.Timer == nil {
+			t.Error("nil Timer unit")
+		} else {
+			t.Log(app.Timer)
+		}
+	})
+}
+
+func Test_Timer_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		fired := make(chan gopi.TimerEvent, 1)
+		start := time.Now()
+		if _, err := app.Timer.NewTimer(50*time.Millisecond, func(evt gopi.TimerEvent) {
+			fired <- evt
+		}); err != nil {
+			t.Error(err)
+		} else {
+			select {
+			case evt := <-fired:
+				if delta := evt.Time().Sub(start); delta < 50*time.Millisecond {
+					t.Error("Timer fired early", delta)
+				} else if evt.Ticks() != 1 {
+					t.Error("Unexpected ticks", evt.Ticks())
+				} else {
+					t.Log(evt, delta)
+				}
+			case <-time.After(time.Second):
+				t.Error("Timer did not fire")
+			}
+		}
+	})
+}
+
+func Test_Timer_003(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		var ticks uint64
+		if id, err := app.Timer.NewTicker(10*time.Millisecond, func(evt gopi.TimerEvent) {
+			atomic.StoreUint64(&ticks, evt.Ticks())
+		}); err != nil {
+			t.Error(err)
+		} else {
+			// Ticks are counted from the start so should not drift
+			time.Sleep(205 * time.Millisecond)
+			if err := app.Timer.Cancel(id); err != nil {
+				t.Error(err)
+			}
+			if n := atomic.LoadUint64(&ticks); n < 19 || n > 21 {
+				t.Error("Unexpected ticks", n)
+			}
+			if err := app.Timer.Cancel(id); err == nil {
+				t.Error("Expected error cancelling timer twice")
+			}
+		}
+	})
+}
+
+func Test_Timer_004(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+		if _, err := app.Timer.NewTimer(10*time.Millisecond, nil); err != nil {
+			t.Error(err)
+		} else {
+			select {
+			case evt := <-ch:
+				if _, ok := evt.(gopi.TimerEvent); ok == false {
+					t.Error("Unexpected event", evt)
+				}
+			case <-time.After(time.Second):
+				t.Error("Timer event not emitted")
+			}
+		}
+	})
+}
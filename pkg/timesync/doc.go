@@ -0,0 +1,18 @@
+// Timesync package implements gopi.TimeSync, which reports the
+// synchronization status of the system clock. The status is read from
+// chronyd with chronyc, or ntpd with ntpq, or when neither is running
+// the -timesync.server is queried with SNTP. Set -timesync.source to
+// use a specific source.
+//
+// A gopi.TimeSyncEvent is emitted each -timesync.interval with the
+// status, when the clock becomes synchronized or unsynchronized, and
+// when the system clock steps by more than -timesync.step. The offset,
+// jitter and delay in seconds are emitted as a measurement when
+// gopi.Metrics is available.
+//
+// When the -timesync.settime flag is set, the system clock is set from
+// the SNTP server when the offset is more than -timesync.drift. When
+// -timesync.rtc is set to a real-time clock device, the device is set
+// from the system clock while synchronized. Setting either clock
+// requires CAP_SYS_TIME.
+package timesync
@@ -0,0 +1,57 @@
+package timesync
+
+import (
+	"fmt"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	t      gopi.TimeSyncEventType
+	status gopi.TimeSyncStatus
+	step   time.Duration
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(t gopi.TimeSyncEventType, status gopi.TimeSyncStatus, step time.Duration) gopi.TimeSyncEvent {
+	return &event{t, status, step}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "timesync"
+}
+
+func (this *event) Type() gopi.TimeSyncEventType {
+	return this.t
+}
+
+func (this *event) Status() gopi.TimeSyncStatus {
+	return this.status
+}
+
+func (this *event) Step() time.Duration {
+	return this.step
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<timesync.event"
+	str += fmt.Sprint(" type=", this.t)
+	if this.t == gopi.TIMESYNC_EVENT_STEP {
+		str += fmt.Sprint(" step=", this.step)
+	} else {
+		str += fmt.Sprint(" status=", this.status)
+	}
+	return str + ">"
+}
@@ -0,0 +1,13 @@
+package timesync
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.TimeSync
+	graph.RegisterUnit(reflect.TypeOf(&timesync{}), reflect.TypeOf((*gopi.TimeSync)(nil)))
+}
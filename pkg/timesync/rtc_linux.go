@@ -0,0 +1,63 @@
+// +build linux
+
+package timesync
+
+import (
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// rtcTime is struct rtc_time from linux/rtc.h
+type rtcTime struct {
+	sec, min, hour, mday, mon, year, wday, yday, isdst int32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// RTC_SET_TIME is _IOW('p', 0x0a, struct rtc_time)
+	rtcSetTime = 0x4024700A
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// setrtc sets a real-time clock device to a time in UTC, which requires
+// CAP_SYS_TIME
+func setrtc(path string, ts time.Time) error {
+	fh, err := os.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	ts = ts.UTC()
+	tm := rtcTime{
+		sec:  int32(ts.Second()),
+		min:  int32(ts.Minute()),
+		hour: int32(ts.Hour()),
+		mday: int32(ts.Day()),
+		mon:  int32(ts.Month()) - 1,
+		year: int32(ts.Year()) - 1900,
+		wday: int32(ts.Weekday()),
+		yday: int32(ts.YearDay()) - 1,
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fh.Fd(), rtcSetTime, uintptr(unsafe.Pointer(&tm))); errno != 0 {
+		return os.NewSyscallError("RTC_SET_TIME", errno)
+	}
+
+	// Return success
+	return nil
+}
+
+// settime sets the system clock, which requires CAP_SYS_TIME
+func settime(ts time.Time) error {
+	tv := syscall.NsecToTimeval(ts.UnixNano())
+	return syscall.Settimeofday(&tv)
+}
@@ -0,0 +1,20 @@
+// +build !linux
+
+package timesync
+
+import (
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func setrtc(string, time.Time) error {
+	return gopi.ErrNotImplemented.WithPrefix("setrtc")
+}
+
+func settime(time.Time) error {
+	return gopi.ErrNotImplemented.WithPrefix("settime")
+}
@@ -0,0 +1,160 @@
+package timesync
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// Ref: https://tools.ietf.org/html/rfc4330
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// sntp queries a server when neither chronyd nor ntpd is running
+type sntp struct {
+	sync.Mutex
+
+	server  string
+	timeout time.Duration
+	offsets []time.Duration
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	sntpPacketSize  = 48
+	sntpVersion     = 4
+	sntpModeClient  = 3
+	sntpModeServer  = 4
+	sntpLeapUnsync  = 3
+	sntpJitterCount = 8
+)
+
+var (
+	// NTP epoch is 1 January 1900
+	ntpEpoch = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newSNTP(server string, timeout time.Duration) *sntp {
+	return &sntp{server: server, timeout: timeout}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *sntp) Source() gopi.TimeSyncSource {
+	return gopi.TIMESYNC_SOURCE_SNTP
+}
+
+func (this *sntp) Status(ctx context.Context) (gopi.TimeSyncStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, this.timeout)
+	defer cancel()
+
+	conn, err := new(net.Dialer).DialContext(ctx, "udp", this.server)
+	if err != nil {
+		return gopi.TimeSyncStatus{}, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// Send request with the transmit time, which the server returns
+	// as the originate time
+	req := make([]byte, sntpPacketSize)
+	req[0] = sntpVersion<<3 | sntpModeClient
+	t1 := time.Now()
+	binary.BigEndian.PutUint64(req[40:], toNTP(t1))
+	if _, err := conn.Write(req); err != nil {
+		return gopi.TimeSyncStatus{}, err
+	}
+
+	// Read response
+	resp := make([]byte, sntpPacketSize)
+	n, err := conn.Read(resp)
+	t4 := time.Now()
+	if err != nil {
+		return gopi.TimeSyncStatus{}, err
+	} else if n < sntpPacketSize || resp[0]&0x07 != sntpModeServer {
+		return gopi.TimeSyncStatus{}, gopi.ErrUnexpectedResponse.WithPrefix("SNTP: ", this.server)
+	} else if binary.BigEndian.Uint64(resp[24:]) != binary.BigEndian.Uint64(req[40:]) {
+		return gopi.TimeSyncStatus{}, gopi.ErrUnexpectedResponse.WithPrefix("SNTP: Originate time")
+	}
+
+	// Calculate offset of the system clock, which is positive when fast,
+	// and round-trip delay
+	t2 := fromNTP(binary.BigEndian.Uint64(resp[32:]))
+	t3 := fromNTP(binary.BigEndian.Uint64(resp[40:]))
+	offset := (t1.Sub(t2) + t4.Sub(t3)) / 2
+	status := gopi.TimeSyncStatus{
+		Time:    t4,
+		Source:  gopi.TIMESYNC_SOURCE_SNTP,
+		Server:  this.server,
+		Stratum: uint(resp[1]),
+		Offset:  offset,
+		Delay:   t4.Sub(t1) - t3.Sub(t2),
+		Jitter:  this.jitter(offset),
+	}
+	status.Synchronized = resp[0]>>6 != sntpLeapUnsync && status.Stratum > 0 && status.Stratum < maxStratum
+
+	// Return success
+	return status, nil
+}
+
+// Adjust removes an offset from previous offsets when the clock has
+// been set, so that jitter is not increased
+func (this *sntp) Adjust(offset time.Duration) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	for i := range this.offsets {
+		this.offsets[i] -= offset
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// jitter returns the RMS difference between successive offsets
+func (this *sntp) jitter(offset time.Duration) time.Duration {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	this.offsets = append(this.offsets, offset)
+	if len(this.offsets) > sntpJitterCount {
+		this.offsets = this.offsets[1:]
+	}
+	if len(this.offsets) < 2 {
+		return 0
+	}
+	sum := 0.0
+	for i := 1; i < len(this.offsets); i++ {
+		d := float64(this.offsets[i] - this.offsets[i-1])
+		sum += d * d
+	}
+	return time.Duration(math.Sqrt(sum / float64(len(this.offsets)-1)))
+}
+
+// toNTP returns a time as seconds and fraction since the NTP epoch
+func toNTP(ts time.Time) uint64 {
+	d := ts.Sub(ntpEpoch)
+	secs := uint64(d / time.Second)
+	frac := uint64(d%time.Second) << 32 / uint64(time.Second)
+	return secs<<32 | frac
+}
+
+// fromNTP returns a time from seconds and fraction since the NTP epoch
+func fromNTP(v uint64) time.Time {
+	secs := time.Duration(v>>32) * time.Second
+	frac := time.Duration((v & 0xFFFFFFFF) * uint64(time.Second) >> 32)
+	return ntpEpoch.Add(secs).Add(frac)
+}
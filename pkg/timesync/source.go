@@ -0,0 +1,156 @@
+package timesync
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// source reads the synchronization status of the system clock
+type source interface {
+	Source() gopi.TimeSyncSource
+	Status(context.Context) (gopi.TimeSyncStatus, error)
+}
+
+// chrony reads the status from chronyd with chronyc
+type chrony struct{}
+
+// ntpd reads the status from ntpd with ntpq
+type ntpd struct{}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	maxStratum = 16
+)
+
+var (
+	reNtpqVariable = regexp.MustCompile(`([A-Za-z_]+)=("[^"]*"|[^,\s]+)`)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CHRONY
+
+// Ref: https://chrony.tuxfamily.org/doc/4.0/chronyc.html#tracking
+
+func (chrony) Source() gopi.TimeSyncSource {
+	return gopi.TIMESYNC_SOURCE_CHRONY
+}
+
+func (chrony) Status(ctx context.Context) (gopi.TimeSyncStatus, error) {
+	if data, err := command(ctx, "chronyc", "-c", "tracking"); err != nil {
+		return gopi.TimeSyncStatus{}, err
+	} else {
+		return parseChrony(data)
+	}
+}
+
+// parseChrony decodes the comma-separated output of chronyc tracking
+func parseChrony(data string) (gopi.TimeSyncStatus, error) {
+	fields := strings.Split(strings.TrimSpace(data), ",")
+	if len(fields) < 14 {
+		return gopi.TimeSyncStatus{}, gopi.ErrUnexpectedResponse.WithPrefix("chronyc: ", strconv.Quote(data))
+	}
+	status := gopi.TimeSyncStatus{
+		Time:   time.Now(),
+		Source: gopi.TIMESYNC_SOURCE_CHRONY,
+		Server: fields[1],
+	}
+	if stratum, err := strconv.ParseUint(fields[2], 10, 32); err != nil {
+		return status, gopi.ErrUnexpectedResponse.WithPrefix("chronyc: Stratum")
+	} else {
+		status.Stratum = uint(stratum)
+	}
+	// System time, RMS offset and root delay are in seconds
+	for i, value := range map[int]*time.Duration{4: &status.Offset, 6: &status.Jitter, 10: &status.Delay} {
+		if d, err := seconds(fields[i]); err != nil {
+			return status, gopi.ErrUnexpectedResponse.WithPrefix("chronyc: ", err)
+		} else {
+			*value = d
+		}
+	}
+	status.Synchronized = fields[0] != "00000000" && status.Stratum < maxStratum && fields[13] != "Not synchronised"
+	return status, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NTPD
+
+// Ref: http://doc.ntp.org/current-stable/ntpq.html
+
+func (ntpd) Source() gopi.TimeSyncSource {
+	return gopi.TIMESYNC_SOURCE_NTPD
+}
+
+func (ntpd) Status(ctx context.Context) (gopi.TimeSyncStatus, error) {
+	if data, err := command(ctx, "ntpq", "-c", "rv"); err != nil {
+		return gopi.TimeSyncStatus{}, err
+	} else {
+		return parseNtpq(data)
+	}
+}
+
+// parseNtpq decodes the system variables from ntpq, where times are
+// in milliseconds
+func parseNtpq(data string) (gopi.TimeSyncStatus, error) {
+	vars := make(map[string]string)
+	for _, match := range reNtpqVariable.FindAllStringSubmatch(data, -1) {
+		vars[match[1]] = strings.Trim(match[2], `"`)
+	}
+	if _, exists := vars["stratum"]; exists == false {
+		return gopi.TimeSyncStatus{}, gopi.ErrUnexpectedResponse.WithPrefix("ntpq: ", strconv.Quote(data))
+	}
+	status := gopi.TimeSyncStatus{
+		Time:   time.Now(),
+		Source: gopi.TIMESYNC_SOURCE_NTPD,
+		Server: vars["refid"],
+	}
+	if stratum, err := strconv.ParseUint(vars["stratum"], 10, 32); err != nil {
+		return status, gopi.ErrUnexpectedResponse.WithPrefix("ntpq: Stratum")
+	} else {
+		status.Stratum = uint(stratum)
+	}
+	for key, value := range map[string]*time.Duration{"offset": &status.Offset, "sys_jitter": &status.Jitter, "rootdelay": &status.Delay} {
+		if ms, err := strconv.ParseFloat(vars[key], 64); err == nil {
+			*value = time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+	status.Synchronized = vars["leap"] != "11" && status.Stratum < maxStratum
+	return status, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// command runs a command and returns the output
+func command(ctx context.Context, name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", gopi.ErrUnexpectedResponse.WithPrefix(name, ": ", msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// seconds parses a duration in seconds
+func seconds(value string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(value, 64); err != nil {
+		return 0, err
+	} else {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+}
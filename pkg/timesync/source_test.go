@@ -0,0 +1,110 @@
+package timesync
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	chronyTracking = "C0A80001,192.168.0.1,3,1600000000.123456789,0.000012345,-0.000001000,0.000020000,-12.345,0.001,0.010,0.005000000,0.001000000,64.5,Normal\n"
+	ntpqVariables  = `associd=0 status=0615 leap_none, sync_ntp, 1 event, clock_sync,
+version="ntpd 4.2.8p15@1.3728-o", processor="armv7l",
+system="Linux/5.10.17-v7+", leap=00, stratum=2, precision=-20,
+rootdelay=12.500, rootdisp=23.456, refid=192.168.0.1,
+offset=-0.250, frequency=-12.345, sys_jitter=0.125,
+clk_jitter=0.345, clk_wander=0.012`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Source_001(t *testing.T) {
+	status, err := parseChrony(chronyTracking)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(status)
+	if status.Synchronized == false || status.Stratum != 3 || status.Server != "192.168.0.1" {
+		t.Error("Unexpected status", status)
+	} else if status.Offset != 12345*time.Nanosecond || status.Jitter != 20*time.Microsecond || status.Delay != 5*time.Millisecond {
+		t.Error("Unexpected offset, jitter or delay", status)
+	}
+	if status, err := parseChrony("00000000,,0,0.0,0.0,0.0,0.0,0.0,0.0,0.0,1.0,1.0,0.0,Not synchronised"); err != nil {
+		t.Error(err)
+	} else if status.Synchronized {
+		t.Error("Expected unsynchronized", status)
+	}
+	if _, err := parseChrony("506 Cannot talk to daemon"); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func Test_Source_002(t *testing.T) {
+	status, err := parseNtpq(ntpqVariables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(status)
+	if status.Synchronized == false || status.Stratum != 2 || status.Server != "192.168.0.1" {
+		t.Error("Unexpected status", status)
+	} else if status.Offset != -250*time.Microsecond || status.Jitter != 125*time.Microsecond || status.Delay != 12500*time.Microsecond {
+		t.Error("Unexpected offset, jitter or delay", status)
+	}
+}
+
+func Test_Source_003(t *testing.T) {
+	// Server which is one second behind the system clock
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	go func() {
+		buf := make([]byte, sntpPacketSize)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			} else if n != sntpPacketSize {
+				continue
+			}
+			now := toNTP(time.Now().Add(-time.Second))
+			resp := make([]byte, sntpPacketSize)
+			resp[0] = sntpVersion<<3 | sntpModeServer
+			resp[1] = 2
+			copy(resp[24:], buf[40:48])
+			binary.BigEndian.PutUint64(resp[32:], now)
+			binary.BigEndian.PutUint64(resp[40:], now)
+			conn.WriteTo(resp, addr)
+		}
+	}()
+
+	src := newSNTP(conn.LocalAddr().String(), time.Second)
+	status, err := src.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(status)
+	if status.Source != gopi.TIMESYNC_SOURCE_SNTP || status.Synchronized == false || status.Stratum != 2 {
+		t.Error("Unexpected status", status)
+	} else if status.Offset < 900*time.Millisecond || status.Offset > 1100*time.Millisecond {
+		t.Error("Unexpected offset", status.Offset)
+	} else if status.Delay < 0 || status.Delay > 100*time.Millisecond {
+		t.Error("Unexpected delay", status.Delay)
+	}
+}
+
+func Test_Source_004(t *testing.T) {
+	ts := time.Date(2021, time.March, 1, 12, 30, 45, 500000000, time.UTC)
+	if other := fromNTP(toNTP(ts)); other.Sub(ts) > time.Microsecond || ts.Sub(other) > time.Microsecond {
+		t.Error("Unexpected time", other)
+	}
+}
@@ -0,0 +1,258 @@
+package timesync
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type timesync struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Metrics
+	sync.RWMutex
+
+	// Flags
+	source   *string
+	server   *string
+	interval *time.Duration
+	timeout  *time.Duration
+	step     *time.Duration
+	settime  *bool
+	drift    *time.Duration
+	rtc      *string
+
+	src         source
+	measurement string
+	status      gopi.TimeSyncStatus
+	valid       bool
+	rtcset      time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	defaultNtpPort = "123"
+
+	// rtcInterval is the minimum interval between setting the real-time
+	// clock, which is the same as the kernel
+	rtcInterval = 11 * time.Minute
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *timesync) Define(cfg gopi.Config) error {
+	this.source = cfg.FlagString("timesync.source", "auto", "Status source (auto, chrony, ntpd, sntp)")
+	this.server = cfg.FlagString("timesync.server", "pool.ntp.org", "SNTP server address")
+	this.interval = cfg.FlagDuration("timesync.interval", time.Minute, "Interval between reading status")
+	this.timeout = cfg.FlagDuration("timesync.timeout", 5*time.Second, "Timeout for reading status")
+	this.step = cfg.FlagDuration("timesync.step", 500*time.Millisecond, "Change in the system clock which is reported as a step")
+	this.settime = cfg.FlagBool("timesync.settime", false, "Set system clock from the SNTP server")
+	this.drift = cfg.FlagDuration("timesync.drift", 128*time.Millisecond, "Offset before the system clock is set from the SNTP server")
+	this.rtc = cfg.FlagString("timesync.rtc", "", "Real-time clock device to set when synchronized, for example /dev/rtc0")
+	cfg.FlagString("timesync.measurement", "timesync", "Measurement name")
+	return nil
+}
+
+func (this *timesync) New(cfg gopi.Config) error {
+	this.Require(this.Logger)
+
+	// Check parameters
+	if *this.interval <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-timesync.interval")
+	} else if *this.timeout <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-timesync.timeout")
+	} else if *this.step <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-timesync.step")
+	} else if *this.drift <= 0 {
+		return gopi.ErrBadParameter.WithPrefix("-timesync.drift")
+	}
+	if _, _, err := net.SplitHostPort(*this.server); err != nil {
+		*this.server = net.JoinHostPort(*this.server, defaultNtpPort)
+	}
+
+	// Set source of status
+	if src, err := this.detect(strings.ToLower(*this.source)); err != nil {
+		return err
+	} else {
+		this.src = src
+	}
+
+	// Define measurement
+	if measurement := cfg.GetString("timesync.measurement"); measurement != "" && this.Metrics != nil {
+		if m, err := this.Metrics.NewMeasurement(measurement, "source string, synchronized bool, stratum uint32, offset float64, jitter float64, delay float64", this.Metrics.HostTag()); err != nil {
+			return err
+		} else {
+			this.measurement = m.Name()
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *timesync) Dispose() error {
+	// Release resources
+	this.src = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *timesync) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			// A step is the difference between elapsed wall clock and
+			// monotonic clock time
+			now := time.Now()
+			if step := now.Round(0).Sub(last.Round(0)) - now.Sub(last); step > *this.step || step < -*this.step {
+				this.Print("TimeSync: Clock stepped by ", step.Truncate(time.Millisecond))
+				this.emit(NewEvent(gopi.TIMESYNC_EVENT_STEP, this.last(), step))
+			}
+			last = now
+			this.poll(ctx)
+			timer.Reset(*this.interval)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *timesync) Status() (gopi.TimeSyncStatus, bool) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.status, this.valid
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *timesync) String() string {
+	str := "<timesync"
+	if status, valid := this.Status(); valid {
+		str += fmt.Sprint(" ", status)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// detect returns the source for a name, or for "auto" the first
+// source which reports status
+func (this *timesync) detect(name string) (source, error) {
+	sntp := newSNTP(*this.server, *this.timeout)
+	switch name {
+	case "chrony":
+		return chrony{}, nil
+	case "ntpd":
+		return ntpd{}, nil
+	case "sntp":
+		return sntp, nil
+	case "auto":
+		ctx, cancel := context.WithTimeout(context.Background(), *this.timeout)
+		defer cancel()
+		for _, src := range []source{chrony{}, ntpd{}} {
+			if _, err := src.Status(ctx); err == nil {
+				return src, nil
+			}
+		}
+		return sntp, nil
+	default:
+		return nil, gopi.ErrBadParameter.WithPrefix("-timesync.source: ", name)
+	}
+}
+
+// poll reads the status, emits events and measurements, and sets the
+// system clock and real-time clock
+func (this *timesync) poll(ctx context.Context) {
+	status, err := this.src.Status(ctx)
+	if err != nil {
+		this.Debug("TimeSync: ", err)
+		status = gopi.TimeSyncStatus{Time: time.Now(), Source: this.src.Source()}
+	}
+
+	// Set status
+	this.RWMutex.Lock()
+	prev, valid := this.status, this.valid
+	this.status, this.valid = status, true
+	this.RWMutex.Unlock()
+
+	// Emit events
+	this.emit(NewEvent(gopi.TIMESYNC_EVENT_STATUS, status, 0))
+	if status.Synchronized && (valid == false || prev.Synchronized == false) {
+		this.emit(NewEvent(gopi.TIMESYNC_EVENT_SYNCHRONIZED, status, 0))
+	} else if status.Synchronized == false && valid && prev.Synchronized {
+		this.emit(NewEvent(gopi.TIMESYNC_EVENT_UNSYNCHRONIZED, status, 0))
+	}
+
+	// Emit measurement
+	if this.measurement != "" {
+		if err := this.Metrics.Emit(this.measurement, nil, status.Source.String(), status.Synchronized, uint32(status.Stratum), status.Offset.Seconds(), status.Jitter.Seconds(), status.Delay.Seconds()); err != nil {
+			this.Debug("TimeSync: ", err)
+		}
+	}
+
+	// Ignore status when not synchronized
+	if status.Synchronized == false {
+		return
+	}
+
+	// Set the system clock from SNTP when it has drifted
+	if sntp, ok := this.src.(*sntp); ok && *this.settime {
+		if offset := status.Offset; offset > *this.drift || offset < -*this.drift {
+			if err := settime(time.Now().Add(-offset)); err != nil {
+				this.Print("TimeSync: Set clock: ", err)
+			} else {
+				this.Print("TimeSync: Set clock with offset ", offset.Truncate(time.Millisecond))
+				sntp.Adjust(offset)
+			}
+		}
+	}
+
+	// Set the real-time clock
+	if *this.rtc != "" && time.Since(this.rtcset) >= rtcInterval {
+		if err := setrtc(*this.rtc, time.Now()); err != nil {
+			this.Print("TimeSync: Set RTC: ", err)
+		} else {
+			this.Debug("TimeSync: Set RTC ", *this.rtc)
+		}
+		this.rtcset = time.Now()
+	}
+}
+
+// last returns the last status
+func (this *timesync) last() gopi.TimeSyncStatus {
+	status, _ := this.Status()
+	return status
+}
+
+func (this *timesync) emit(evt gopi.Event) {
+	if this.Publisher != nil {
+		if err := this.Publisher.Emit(evt, false); err != nil {
+			this.Debug("TimeSync: ", err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package tool
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type provision struct {
+	path, export, key *string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// defineProvision defines the flags for importing and exporting
+// provisioning bundles
+func defineProvision(cfg gopi.Config) *provision {
+	this := new(provision)
+	this.path = cfg.FlagString("provision", "", "Apply provisioning bundle when it exists")
+	this.export = cfg.FlagString("provision.export", "", "Write provisioning bundle and exit")
+	this.key = cfg.FlagString("provision.key", "", "File containing key for signing provisioning bundles")
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Export writes a bundle and returns true, or returns false if
+// the export flag was not set
+func (this *provision) Export(cfg gopi.Config) (bool, error) {
+	if *this.export == "" {
+		return false, nil
+	} else if key, err := this.readKey(); err != nil {
+		return true, err
+	} else if fh, err := os.OpenFile(*this.export, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600); err != nil {
+		return true, err
+	} else if err := cfg.Export(fh, key); err != nil {
+		fh.Close()
+		return true, err
+	} else {
+		return true, fh.Close()
+	}
+}
+
+// Import applies a bundle, when the flag is set and the bundle exists
+func (this *provision) Import(cfg gopi.Config) error {
+	if *this.path == "" {
+		return nil
+	} else if _, err := os.Stat(*this.path); os.IsNotExist(err) {
+		return nil
+	} else if key, err := this.readKey(); err != nil {
+		return err
+	} else if fh, err := os.Open(*this.path); err != nil {
+		return err
+	} else {
+		defer fh.Close()
+		return cfg.Import(fh, key)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *provision) readKey() ([]byte, error) {
+	if *this.key == "" {
+		return nil, gopi.ErrBadParameter.WithPrefix("-provision.key")
+	} else if key, err := ioutil.ReadFile(*this.key); err != nil {
+		return nil, err
+	} else if key = bytes.TrimSpace(key); len(key) == 0 {
+		return nil, gopi.ErrBadParameter.WithPrefix("-provision.key")
+	} else {
+		return key, nil
+	}
+}
@@ -0,0 +1,249 @@
+package tool
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// readline reads lines from a terminal with editing, history and tab
+// completion, or reads lines without editing when not in raw mode
+type readline struct {
+	sync.Mutex
+
+	in       *bufio.Reader
+	out      io.Writer
+	raw      bool
+	prompt   string
+	complete func(string) []string
+
+	buf     []rune
+	pos     int
+	history []string
+	reading bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	keyCtrlA     = 0x01
+	keyCtrlD     = 0x04
+	keyCtrlE     = 0x05
+	keyBackspace = 0x08
+	keyTab       = 0x09
+	keyCtrlU     = 0x15
+	keyEscape    = 0x1B
+	keyDelete    = 0x7F
+
+	// maxHistory is the number of lines kept in history
+	maxHistory = 100
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newReadline(in io.Reader, out io.Writer, raw bool, prompt string, complete func(string) []string) *readline {
+	this := new(readline)
+	this.in = bufio.NewReader(in)
+	this.out = out
+	this.raw = raw
+	this.prompt = prompt
+	this.complete = complete
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// ReadLine returns the next line without a line ending, or io.EOF
+func (this *readline) ReadLine() (string, error) {
+	this.Lock()
+	this.buf, this.pos, this.reading = this.buf[:0], 0, true
+	this.redraw()
+	this.Unlock()
+
+	defer func() {
+		this.Lock()
+		this.reading = false
+		this.Unlock()
+	}()
+
+	if this.raw == false {
+		line, err := this.in.ReadString('\n')
+		if err == io.EOF && line != "" {
+			err = nil
+		}
+		return strings.TrimRight(line, "\r\n"), err
+	}
+
+	// History position, where len(history) is the current line
+	hist := len(this.history)
+	for {
+		r, _, err := this.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		this.Lock()
+		switch r {
+		case '\r', '\n':
+			line := string(this.buf)
+			fmt.Fprint(this.out, "\r\n")
+			if strings.TrimSpace(line) != "" && (len(this.history) == 0 || this.history[len(this.history)-1] != line) {
+				this.history = append(this.history, line)
+				if len(this.history) > maxHistory {
+					this.history = this.history[1:]
+				}
+			}
+			this.buf, this.pos = this.buf[:0], 0
+			this.Unlock()
+			return line, nil
+		case keyCtrlD:
+			if len(this.buf) == 0 {
+				fmt.Fprint(this.out, "\r\n")
+				this.Unlock()
+				return "", io.EOF
+			}
+		case keyBackspace, keyDelete:
+			if this.pos > 0 {
+				this.buf = append(this.buf[:this.pos-1], this.buf[this.pos:]...)
+				this.pos--
+			}
+		case keyCtrlA:
+			this.pos = 0
+		case keyCtrlE:
+			this.pos = len(this.buf)
+		case keyCtrlU:
+			this.buf, this.pos = this.buf[:0], 0
+		case keyTab:
+			this.tab()
+		case keyEscape:
+			hist = this.escape(hist)
+		default:
+			if unicode.IsPrint(r) {
+				this.buf = append(this.buf[:this.pos], append([]rune{r}, this.buf[this.pos:]...)...)
+				this.pos++
+			}
+		}
+		this.redraw()
+		this.Unlock()
+	}
+}
+
+// Print outputs a line, redrawing the line being edited afterwards
+func (this *readline) Print(args ...interface{}) {
+	this.Lock()
+	defer this.Unlock()
+
+	if this.raw && this.reading {
+		fmt.Fprint(this.out, "\r\033[K")
+	}
+	fmt.Fprint(this.out, fmt.Sprint(args...), "\n")
+	if this.raw && this.reading {
+		this.redraw()
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// redraw outputs the prompt and line, and positions the cursor
+func (this *readline) redraw() {
+	if this.raw == false {
+		fmt.Fprint(this.out, this.prompt)
+		return
+	}
+	fmt.Fprint(this.out, "\r\033[K", this.prompt, string(this.buf))
+	if n := len(this.buf) - this.pos; n > 0 {
+		fmt.Fprintf(this.out, "\033[%dD", n)
+	}
+}
+
+// escape handles cursor and history keys, and returns the history
+// position
+func (this *readline) escape(hist int) int {
+	if r, _, err := this.in.ReadRune(); err != nil || r != '[' {
+		return hist
+	}
+	r, _, err := this.in.ReadRune()
+	if err != nil {
+		return hist
+	}
+	switch r {
+	case 'A', 'B':
+		if r == 'A' && hist > 0 {
+			hist--
+		} else if r == 'B' && hist < len(this.history) {
+			hist++
+		} else {
+			return hist
+		}
+		if hist < len(this.history) {
+			this.buf = []rune(this.history[hist])
+		} else {
+			this.buf = this.buf[:0]
+		}
+		this.pos = len(this.buf)
+	case 'C':
+		if this.pos < len(this.buf) {
+			this.pos++
+		}
+	case 'D':
+		if this.pos > 0 {
+			this.pos--
+		}
+	case 'H':
+		this.pos = 0
+	case 'F':
+		this.pos = len(this.buf)
+	case '3':
+		if r, _, err := this.in.ReadRune(); err == nil && r == '~' && this.pos < len(this.buf) {
+			this.buf = append(this.buf[:this.pos], this.buf[this.pos+1:]...)
+		}
+	}
+	return hist
+}
+
+// tab completes the word before the cursor, or lists the candidates
+// when there is more than one
+func (this *readline) tab() {
+	if this.complete == nil {
+		return
+	}
+	line := string(this.buf[:this.pos])
+	candidates := this.complete(line)
+	if len(candidates) == 0 {
+		return
+	}
+
+	// Insert the longest common prefix of the candidates
+	prefix := candidates[0]
+	for _, candidate := range candidates[1:] {
+		for strings.HasPrefix(candidate, prefix) == false {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	word := line[strings.LastIndexAny(line, " \t")+1:]
+	if len(prefix) > len(word) {
+		insert := []rune(prefix[len(word):])
+		if len(candidates) == 1 && strings.HasSuffix(prefix, ".") == false {
+			insert = append(insert, ' ')
+		}
+		this.buf = append(this.buf[:this.pos], append(insert, this.buf[this.pos:]...)...)
+		this.pos += len(insert)
+		return
+	}
+
+	// List candidates
+	if len(candidates) > 1 {
+		sort.Strings(candidates)
+		fmt.Fprint(this.out, "\r\n", strings.Join(candidates, "  "), "\r\n")
+	}
+}
@@ -0,0 +1,90 @@
+package tool
+
+import (
+	"context"
+	"io"
+	"os"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type remote struct {
+	gopi.Unit
+	gopi.ConnPool
+	gopi.Logger
+
+	srv *string
+	in  io.Reader
+	out io.Writer
+}
+
+// remoteShell runs a shell session over a service stub
+type remoteShell struct {
+	ctx  context.Context
+	stub gopi.ShellStub
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	// shellServiceName is the name of the remote shell service
+	shellServiceName = "gopi.shell.Shell"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// BOOTSTRAP
+
+// Remote runs an interactive shell on a remote server which has
+// the shell service. The application ends when the shell is quit
+func Remote(name string, args []string, objs ...interface{}) int {
+	r := &remote{in: os.Stdin, out: os.Stdout}
+	return CommandLine(name, args, append([]interface{}{r}, objs...)...)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *remote) Define(cfg gopi.Config) error {
+	this.srv = cfg.FlagString("srv", "", "name, service:name or host:port")
+	return nil
+}
+
+func (this *remote) New(cfg gopi.Config) error {
+	this.Require(this.ConnPool, this.Logger)
+	if *this.srv == "" {
+		return gopi.ErrBadParameter.WithPrefix("-srv")
+	}
+	return nil
+}
+
+func (this *remote) Run(ctx context.Context) error {
+	conn, err := this.ConnPool.ConnectService(ctx, "tcp", *this.srv, 0)
+	if err != nil {
+		return err
+	}
+	stub, ok := conn.NewStub(shellServiceName).(gopi.ShellStub)
+	if ok == false || stub == nil {
+		return gopi.ErrNotFound.WithPrefix(shellServiceName)
+	}
+	this.Debug("Connected: ", stub)
+	return runShell(ctx, &remoteShell{ctx, stub}, this.in, this.out)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *remoteShell) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	return this.stub.Run(ctx, in, out)
+}
+
+func (this *remoteShell) Complete(line string) []string {
+	if candidates, err := this.stub.Complete(this.ctx, line); err != nil {
+		return nil
+	} else {
+		return candidates
+	}
+}
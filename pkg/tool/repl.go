@@ -0,0 +1,381 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// repl executes commands which list units and call their methods
+type repl struct {
+	units map[string]unit
+}
+
+// unit is a value and the type which defines the methods which can be
+// called, which is the interface when the unit is an interface field
+type unit struct {
+	value reflect.Value
+	t     reflect.Type
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	typeUnit     = reflect.TypeOf(gopi.Unit{})
+	typeContext  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	typeError    = reflect.TypeOf((*error)(nil)).Elem()
+	typeStringer = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	typeDuration = reflect.TypeOf(time.Duration(0))
+)
+
+var (
+	commands = []string{"help", "units", "methods", "subscribe", "unsubscribe", "quit"}
+)
+
+const (
+	// maxEnum is the largest value tried when matching enum names
+	maxEnum = 0xFFFF
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+// newRepl returns a repl for units reachable from objects through
+// exported fields, where each unit is named by the field
+func newRepl(objs ...interface{}) *repl {
+	this := &repl{make(map[string]unit)}
+	seen := make(map[reflect.Value]bool)
+	for _, obj := range objs {
+		this.walk(reflect.ValueOf(obj), seen)
+	}
+	return this
+}
+
+func (this *repl) walk(value reflect.Value, seen map[reflect.Value]bool) {
+	if seen[value] || isUnit(value) == false {
+		return
+	}
+	seen[value] = true
+	t := value.Elem().Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || (f.Type.Kind() != reflect.Interface && f.Type.Kind() != reflect.Ptr) {
+			continue
+		}
+		v := value.Elem().Field(i)
+		if v.IsNil() {
+			continue
+		}
+		if v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		if isUnit(v) == false {
+			continue
+		}
+		if _, exists := this.units[f.Name]; exists == false {
+			this.units[f.Name] = unit{v, f.Type}
+		}
+		this.walk(v, seen)
+	}
+}
+
+// isUnit returns true for a pointer to a struct which embeds gopi.Unit
+func isUnit(v reflect.Value) bool {
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	t := v.Elem().Type()
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous && f.Type == typeUnit {
+			return true
+		}
+	}
+	return false
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Units returns unit names in alphabetical order
+func (this *repl) Units() []string {
+	result := make([]string, 0, len(this.units))
+	for name := range this.units {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Methods returns the exported methods of a unit with their signatures
+func (this *repl) Methods(name string) ([]string, error) {
+	u, exists := this.units[name]
+	if exists == false {
+		return nil, gopi.ErrNotFound.WithPrefix(name)
+	}
+	result := []string{}
+	for i := 0; i < u.t.NumMethod(); i++ {
+		m := u.t.Method(i)
+		if isHidden(u.t, m.Name) {
+			continue
+		}
+		signature := u.value.MethodByName(m.Name).Type().String()
+		result = append(result, name+"."+m.Name+strings.TrimPrefix(signature, "func"))
+	}
+	return result, nil
+}
+
+// Call parses arguments for a unit method, calls it and writes the
+// results. A context argument is filled with the context
+func (this *repl) Call(ctx context.Context, w io.Writer, command string, args []string) error {
+	i := strings.Index(command, ".")
+	if i < 0 {
+		return gopi.ErrBadParameter.WithPrefix(command)
+	}
+	u, exists := this.units[command[:i]]
+	if exists == false {
+		return gopi.ErrNotFound.WithPrefix(command[:i])
+	}
+	if _, exists := u.t.MethodByName(command[i+1:]); exists == false || isHidden(u.t, command[i+1:]) {
+		return gopi.ErrNotFound.WithPrefix(command)
+	}
+	method := u.value.MethodByName(command[i+1:])
+
+	// Parse arguments
+	t := method.Type()
+	in := []reflect.Value{}
+	for j := 0; j < t.NumIn(); j++ {
+		pt := t.In(j)
+		if pt == typeContext {
+			in = append(in, reflect.ValueOf(ctx))
+			continue
+		}
+		if t.IsVariadic() && j == t.NumIn()-1 {
+			for _, arg := range args {
+				if v, err := parseArg(arg, pt.Elem()); err != nil {
+					return err
+				} else {
+					in = append(in, v)
+				}
+			}
+			args = nil
+			break
+		}
+		if len(args) == 0 {
+			return gopi.ErrBadParameter.WithPrefix(command, ": Missing ", pt, " argument")
+		}
+		v, err := parseArg(args[0], pt)
+		if err != nil {
+			return err
+		}
+		in = append(in, v)
+		args = args[1:]
+	}
+	if len(args) > 0 {
+		return gopi.ErrBadParameter.WithPrefix(command, ": Too many arguments")
+	}
+
+	// Call and write results, returning any error result
+	var result error
+	for _, out := range method.Call(in) {
+		if out.Type() == typeError {
+			if out.IsNil() == false {
+				result = out.Interface().(error)
+			}
+			continue
+		}
+		writeValue(w, out)
+	}
+	return result
+}
+
+// Complete returns candidates for the last word of a line
+func (this *repl) Complete(line string) []string {
+	fields := strings.Fields(line)
+	word := ""
+	if len(fields) > 0 && strings.HasSuffix(line, " ") == false {
+		word = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	candidates := []string{}
+	switch {
+	case len(fields) == 0 && strings.Contains(word, "."):
+		name := word[:strings.Index(word, ".")]
+		if methods, err := this.Methods(name); err == nil {
+			for _, method := range methods {
+				candidates = append(candidates, method[:strings.Index(method, "(")])
+			}
+		}
+	case len(fields) == 0:
+		candidates = append(candidates, commands...)
+		for _, name := range this.Units() {
+			candidates = append(candidates, name+".")
+		}
+	case len(fields) == 1 && fields[0] == "methods":
+		candidates = this.Units()
+	}
+
+	result := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, word) {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// isHidden returns true for methods called by the graph, and for
+// concrete types the methods of embedded locks and wait groups
+func isHidden(t reflect.Type, name string) bool {
+	switch name {
+	case "Define", "New", "Run", "Dispose", "Require":
+		return true
+	case "Lock", "Unlock", "RLock", "RUnlock", "RLocker", "TryLock", "TryRLock", "Add", "Done", "Wait":
+		return t.Kind() != reflect.Interface
+	default:
+		return false
+	}
+}
+
+// parseArg returns a value of a type from a string. Enum types with a
+// String method can be parsed by name
+func parseArg(arg string, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	if t == typeDuration {
+		if d, err := time.ParseDuration(arg); err != nil {
+			return v, gopi.ErrBadParameter.WithPrefix(strconv.Quote(arg))
+		} else {
+			v.SetInt(int64(d))
+		}
+		return v, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(arg)
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(arg); err != nil {
+			return v, gopi.ErrBadParameter.WithPrefix(strconv.Quote(arg))
+		} else {
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(arg, 0, t.Bits()); err == nil {
+			v.SetInt(n)
+		} else if n, ok := parseEnum(arg, t); ok {
+			v.SetInt(int64(n))
+		} else {
+			return v, gopi.ErrBadParameter.WithPrefix(strconv.Quote(arg))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(arg, 0, t.Bits()); err == nil {
+			v.SetUint(n)
+		} else if n, ok := parseEnum(arg, t); ok {
+			v.SetUint(n)
+		} else {
+			return v, gopi.ErrBadParameter.WithPrefix(strconv.Quote(arg))
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(arg, t.Bits()); err != nil {
+			return v, gopi.ErrBadParameter.WithPrefix(strconv.Quote(arg))
+		} else {
+			v.SetFloat(f)
+		}
+	default:
+		return v, gopi.ErrNotImplemented.WithPrefix("Unsupported argument type ", t)
+	}
+	return v, nil
+}
+
+// parseEnum matches a name against the String method of an integer type
+func parseEnum(arg string, t reflect.Type) (uint64, bool) {
+	if t.Implements(typeStringer) == false {
+		return 0, false
+	}
+	v := reflect.New(t).Elem()
+	for n := uint64(0); n <= maxEnum; n++ {
+		if t.Kind() >= reflect.Uint {
+			if v.OverflowUint(n) {
+				break
+			}
+			v.SetUint(n)
+		} else {
+			if v.OverflowInt(int64(n)) {
+				break
+			}
+			v.SetInt(int64(n))
+		}
+		if strings.EqualFold(v.Interface().(fmt.Stringer).String(), arg) {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// writeValue writes a result, with each element of a slice on its own line
+func writeValue(w io.Writer, v reflect.Value) {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < v.Len(); i++ {
+			fmt.Fprintln(w, v.Index(i).Interface())
+		}
+	} else {
+		fmt.Fprintln(w, v.Interface())
+	}
+}
+
+// splitArgs splits a line into words, where double-quoted words can
+// contain spaces and escapes
+func splitArgs(line string) ([]string, error) {
+	result := []string{}
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimSpace(line) {
+		if line[0] == '"' {
+			end := 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil, gopi.ErrBadParameter.WithPrefix("Unterminated string")
+			}
+			word, err := strconv.Unquote(line[:end+1])
+			if err != nil {
+				return nil, gopi.ErrBadParameter.WithPrefix(line[:end+1])
+			}
+			result = append(result, word)
+			line = line[end+1:]
+		} else if end := strings.IndexAny(line, " \t"); end < 0 {
+			result = append(result, line)
+			line = ""
+		} else {
+			result = append(result, line[:end])
+			line = line[end:]
+		}
+	}
+	return result, nil
+}
+
+// matchEvent returns true if an event name matches a pattern, or the
+// pattern is empty
+func matchEvent(pattern string, evt gopi.Event) bool {
+	if pattern == "" {
+		return true
+	}
+	match, _ := path.Match(pattern, evt.Name())
+	return match
+}
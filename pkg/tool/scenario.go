@@ -0,0 +1,234 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/djthorpe/gopi/v3"
+	"github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Scenario is a timeline of actions, such as emitting events or
+// setting sensor values, and expectations on the events emitted and
+// the calls made to actuators. Fake units record calls with Record
+type Scenario struct {
+	sync.Mutex
+
+	steps  []*step
+	start  time.Time
+	events []ScenarioEvent
+	calls  []ScenarioCall
+}
+
+// ScenarioEvent is an event emitted while the scenario is running
+type ScenarioEvent struct {
+	At    time.Duration
+	Event gopi.Event
+}
+
+// ScenarioCall is a call to an actuator recorded by a fake unit
+type ScenarioCall struct {
+	At   time.Duration
+	Name string
+	Args []interface{}
+}
+
+type step struct {
+	at     time.Duration
+	desc   string
+	action func() error
+	expect func(*Scenario, time.Duration) bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	scenarioPoll = 10 * time.Millisecond
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewScenario() *Scenario {
+	return new(Scenario)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// At calls a function at a time from the start of the scenario
+func (this *Scenario) At(at time.Duration, desc string, fn func() error) *Scenario {
+	this.steps = append(this.steps, &step{at: at, desc: desc, action: fn})
+	return this
+}
+
+// ExpectEvent expects an event for which a function returns true to be
+// emitted before a time from the start of the scenario
+func (this *Scenario) ExpectEvent(by time.Duration, desc string, fn func(gopi.Event) bool) *Scenario {
+	this.steps = append(this.steps, &step{at: by, desc: desc, expect: func(this *Scenario, by time.Duration) bool {
+		for _, evt := range this.events {
+			if evt.At <= by && fn(evt.Event) {
+				return true
+			}
+		}
+		return false
+	}})
+	return this
+}
+
+// ExpectCall expects an actuator call with a name to be recorded before
+// a time from the start of the scenario. When arguments are provided,
+// they need to be equal to the recorded arguments
+func (this *Scenario) ExpectCall(by time.Duration, name string, args ...interface{}) *Scenario {
+	desc := fmt.Sprintf("%v%v", name, args)
+	this.steps = append(this.steps, &step{at: by, desc: desc, expect: func(this *Scenario, by time.Duration) bool {
+		for _, call := range this.calls {
+			if call.At > by || call.Name != name {
+				continue
+			} else if len(args) == 0 || reflect.DeepEqual(args, call.Args) {
+				return true
+			}
+		}
+		return false
+	}})
+	return this
+}
+
+// Record is called by fake units to record a call to an actuator
+func (this *Scenario) Record(name string, args ...interface{}) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.calls = append(this.calls, ScenarioCall{this.since(), name, args})
+}
+
+// Events returns the events emitted while the scenario was running
+func (this *Scenario) Events() []ScenarioEvent {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return append([]ScenarioEvent{}, this.events...)
+}
+
+// Calls returns the actuator calls recorded
+func (this *Scenario) Calls() []ScenarioCall {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	return append([]ScenarioCall{}, this.calls...)
+}
+
+// Run performs the actions in time order, collecting events from the
+// publisher, and returns when every expectation is met or the last
+// time in the scenario has passed. It returns errors for actions which
+// failed and expectations which were not met
+func (this *Scenario) Run(ctx context.Context, publisher gopi.Publisher) error {
+	var result error
+
+	// Order steps and set start time
+	sort.SliceStable(this.steps, func(i, j int) bool {
+		return this.steps[i].at < this.steps[j].at
+	})
+	this.Mutex.Lock()
+	this.start = time.Now()
+	this.Mutex.Unlock()
+
+	// Collect events
+	if publisher != nil {
+		ch := publisher.Subscribe()
+		defer publisher.Unsubscribe(ch)
+		go func() {
+			for evt := range ch {
+				this.Mutex.Lock()
+				this.events = append(this.events, ScenarioEvent{this.since(), evt})
+				this.Mutex.Unlock()
+			}
+		}()
+	}
+
+	// Perform actions, and check expectations until their deadline
+	pending := []*step{}
+	for _, step := range this.steps {
+		if step.action == nil {
+			pending = append(pending, step)
+			continue
+		}
+		if err := this.wait(ctx, step.at, &pending, &result); err != nil {
+			return multierror.Append(result, err)
+		} else if err := step.action(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("At %v: %v: %w", step.at, step.desc, err))
+		}
+	}
+	if err := this.wait(ctx, -1, &pending, &result); err != nil {
+		return multierror.Append(result, err)
+	}
+
+	// Return any errors
+	return result
+}
+
+// Test runs the scenario and reports errors for a test
+func (this *Scenario) Test(t *testing.T, publisher gopi.Publisher) {
+	if err := this.Run(context.Background(), publisher); err != nil {
+		if errs, ok := err.(*multierror.Error); ok {
+			for _, err := range errs.Errors {
+				t.Error(err)
+			}
+		} else {
+			t.Error(err)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// wait checks pending expectations until a time, or until every
+// expectation is met when the time is negative. Expectations which are
+// met are removed, and those which have passed their deadline are
+// reported as errors
+func (this *Scenario) wait(ctx context.Context, at time.Duration, pending *[]*step, result *error) error {
+	ticker := time.NewTicker(scenarioPoll)
+	defer ticker.Stop()
+	for {
+		this.Mutex.Lock()
+		now := this.since()
+		remaining := []*step{}
+		for _, step := range *pending {
+			if step.expect(this, step.at) {
+				continue
+			} else if now > step.at {
+				*result = multierror.Append(*result, fmt.Errorf("By %v: Expected %v", step.at, step.desc))
+			} else {
+				remaining = append(remaining, step)
+			}
+		}
+		this.Mutex.Unlock()
+		*pending = remaining
+		if at >= 0 && now >= at {
+			return nil
+		} else if at < 0 && len(remaining) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// since returns the time since the scenario started, or zero if it
+// has not started
+func (this *Scenario) since() time.Duration {
+	if this.start.IsZero() {
+		return 0
+	}
+	return time.Since(this.start)
+}
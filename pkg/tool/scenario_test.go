@@ -0,0 +1,113 @@
+package tool_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+	_ "github.com/djthorpe/gopi/v3/pkg/metrics"
+	_ "github.com/djthorpe/gopi/v3/pkg/rules"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Publisher
+	gopi.Metrics
+	gopi.RuleEngine
+	gopi.GPIO
+}
+
+type event string
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	rules = `
+rule "button"
+  on event button
+  do gpio.write(17, "high")
+end
+
+rule "too hot"
+  on threshold sensor temperature > 30
+  do emit("alarm")
+end
+`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	graph.RegisterUnit(reflect.TypeOf(&testutil.GPIO{}), reflect.TypeOf((*gopi.GPIO)(nil)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// FAKES
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (this event) Name() string { return string(this) }
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Scenario_001(t *testing.T) {
+	path := testutil.TempFile(t, "rules", rules)
+	tool.Test(t, []string{"-rules.path=" + path}, new(App), func(app *App) {
+		if _, err := app.Metrics.NewMeasurement("sensor", "temperature float64"); err != nil {
+			t.Fatal(err)
+		}
+		// The rule engine subscribes to events when it runs, so the
+		// first action is delayed
+		scenario := tool.NewScenario().
+			At(50*time.Millisecond, "press button", func() error {
+				return app.Publisher.Emit(event("button"), true)
+			}).
+			At(100*time.Millisecond, "raise temperature to 20", func() error {
+				return app.Metrics.Emit("sensor", nil, 20.0)
+			}).
+			At(200*time.Millisecond, "raise temperature to 70", func() error {
+				return app.Metrics.Emit("sensor", nil, 70.0)
+			}).
+			ExpectCall(500*time.Millisecond, "GPIO.WritePin", gopi.GPIOPin(17), gopi.GPIO_HIGH).
+			ExpectEvent(time.Second, "alarm", func(evt gopi.Event) bool {
+				return evt.Name() == "alarm"
+			})
+		app.GPIO.(*testutil.GPIO).SetScenario(scenario)
+		scenario.Test(t, app.Publisher)
+		if calls := scenario.Calls(); len(calls) != 1 {
+			t.Error("Unexpected calls", calls)
+		}
+	})
+}
+
+func Test_Scenario_002(t *testing.T) {
+	// Expectations which are not met and actions which fail
+	s := tool.NewScenario().
+		At(0, "fail", func() error { return gopi.ErrInternalAppError }).
+		ExpectCall(50*time.Millisecond, "GPIO.WritePin").
+		ExpectEvent(50*time.Millisecond, "event", func(gopi.Event) bool { return true })
+	if err := s.Run(context.Background(), nil); err == nil {
+		t.Error("Expected errors")
+	} else if errors.Is(err, gopi.ErrInternalAppError) == false {
+		t.Error("Unexpected error", err)
+	} else {
+		t.Log(err)
+	}
+}
@@ -17,16 +17,21 @@ type server struct {
 	gopi.Server
 	gopi.Logger
 	gopi.ServiceDiscovery
-
-	addr, name *string
-	version    string
+	gopi.ShellService
+	gopi.Publisher
+	gopi.AccessControl
+	gopi.AuditLog
+
+	objs              []interface{}
+	addr, iface, name *string
+	version           string
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // BOOTSTRAP
 
 func Server(name string, args []string, objs ...interface{}) int {
-	srv := []interface{}{new(server)}
+	srv := []interface{}{&server{objs: objs}}
 	return CommandLine(name, args, append(srv, objs...)...)
 }
 
@@ -35,6 +40,7 @@ func Server(name string, args []string, objs ...interface{}) int {
 
 func (this *server) Define(cfg gopi.Config) error {
 	this.addr = cfg.FlagString("addr", "", "Address for server")
+	this.iface = cfg.FlagString("iface", "", "Interface to bind server to, when address has no host")
 	this.name = cfg.FlagString("name", "", "Service name")
 	return nil
 }
@@ -52,6 +58,20 @@ func (this *server) New(cfg gopi.Config) error {
 	}
 	this.version, _, _ = cfg.Version().Version()
 
+	// Bind to an address of the interface
+	if addr, err := ifaceAddr(*this.iface, *this.addr); err != nil {
+		return gopi.ErrBadParameter.WithPrefix("-iface: ", err)
+	} else {
+		*this.addr = addr
+	}
+
+	// Serve remote shell sessions when the shell service is used
+	if this.ShellService != nil {
+		if err := this.ShellService.Serve(newSession(this.Publisher, this.AccessControl, this.AuditLog, this.objs...)); err != nil {
+			return err
+		}
+	}
+
 	// Return success
 	return nil
 }
@@ -115,3 +135,67 @@ func (this *server) Run(ctx context.Context) error {
 	// Return success
 	return nil
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// ifaceAddr returns an address to listen on for an interface, preferring
+// IPv4 and then global IPv6 addresses. Link-local IPv6 addresses include
+// the zone of the interface
+func ifaceAddr(name, addr string) (string, error) {
+	if name = strings.TrimSpace(name); name == "" {
+		return addr, nil
+	}
+
+	// Address should be empty or a port
+	host, port := "", "0"
+	if addr != "" {
+		if host_, port_, err := net.SplitHostPort(addr); err != nil {
+			return "", err
+		} else {
+			host, port = host_, port_
+		}
+	}
+	if host != "" {
+		return "", gopi.ErrBadParameter.WithPrefix("Address has a host: ", addr)
+	}
+
+	// Get interface addresses
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return "", err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", err
+	}
+	var ip4, ip6, ll6 net.IP
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok {
+			switch ip := ipnet.IP; {
+			case ip.To4() != nil:
+				if ip4 == nil {
+					ip4 = ip
+				}
+			case ip.IsLinkLocalUnicast():
+				if ll6 == nil {
+					ll6 = ip
+				}
+			default:
+				if ip6 == nil {
+					ip6 = ip
+				}
+			}
+		}
+	}
+	switch {
+	case ip4 != nil:
+		return net.JoinHostPort(ip4.String(), port), nil
+	case ip6 != nil:
+		return net.JoinHostPort(ip6.String(), port), nil
+	case ll6 != nil:
+		return net.JoinHostPort(ll6.String()+"%"+iface.Name, port), nil
+	default:
+		return "", gopi.ErrNotFound.WithPrefix("No address for interface: ", name)
+	}
+}
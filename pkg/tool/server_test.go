@@ -0,0 +1,49 @@
+package tool
+
+import (
+	"net"
+	"testing"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Server_001(t *testing.T) {
+	if addr, err := ifaceAddr("", ":8080"); err != nil {
+		t.Error(err)
+	} else if addr != ":8080" {
+		t.Error("Unexpected address", addr)
+	}
+	if _, err := ifaceAddr("lo", "localhost:8080"); err == nil {
+		t.Error("Expected error for address with host")
+	}
+	if _, err := ifaceAddr("missing0", ":8080"); err == nil {
+		t.Error("Expected error for missing interface")
+	}
+}
+
+func Test_Server_002(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, iface := range ifaces {
+		addr, err := ifaceAddr(iface.Name, ":8080")
+		if err != nil {
+			t.Log(iface.Name, ": ", err)
+			continue
+		}
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			t.Error(err)
+		} else if port != "8080" {
+			t.Error("Unexpected port", addr)
+		} else if ip, err := net.ResolveIPAddr("ip", host); err != nil {
+			t.Error(err)
+		} else if ip.IP.IsLinkLocalUnicast() && ip.IP.To4() == nil && ip.Zone != iface.Name {
+			t.Error("Expected zone for link-local address", addr)
+		} else {
+			t.Log(iface.Name, " => ", addr)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package tool
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// session implements gopi.Shell for the units used by objects. When
+// access control is set, calling a method requires the role for the
+// resource "Unit.Method". When the audit log is set, calls to methods
+// which change state are recorded
+type session struct {
+	gopi.Publisher
+	gopi.AccessControl
+	gopi.AuditLog
+	*repl
+}
+
+// conn is the state for a running session
+type conn struct {
+	sync.Mutex
+	*session
+
+	w      io.Writer
+	cancel context.CancelFunc
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func newSession(publisher gopi.Publisher, access gopi.AccessControl, audit gopi.AuditLog, objs ...interface{}) *session {
+	return &session{publisher, access, audit, newRepl(objs...)}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *session) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	c := &conn{session: this, w: out}
+	defer c.unsubscribe()
+
+	// Read lines in the background, waiting for each command to
+	// complete before reading the next line
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	lines := make(chan string)
+	next := make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		r := bufio.NewReader(in)
+		for {
+			line, err := r.ReadString('\n')
+			if err == io.EOF && line != "" {
+				err = nil
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case lines <- strings.TrimRight(line, "\r\n"):
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-next:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Execute commands until quit, end of input or done
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case line := <-lines:
+			if quit := c.execute(ctx, line); quit {
+				return nil
+			}
+			next <- struct{}{}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// print outputs a line, which can be called from any goroutine
+func (this *conn) print(args ...interface{}) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	fmt.Fprint(this.w, fmt.Sprint(args...), "\n")
+}
+
+// execute a command line and return true if the session should quit
+func (this *conn) execute(ctx context.Context, line string) bool {
+	args, err := splitArgs(line)
+	if err != nil {
+		this.print("Error: ", err)
+		return false
+	} else if len(args) == 0 {
+		return false
+	}
+
+	switch args[0] {
+	case "quit", "exit":
+		return true
+	case "help":
+		this.print(strings.TrimSpace(`
+units                   List units
+methods <unit>          List methods for a unit
+<unit>.<method> args... Call a method
+subscribe [pattern]     Show events with names matching pattern
+unsubscribe             Stop showing events
+quit                    Quit the shell`))
+	case "units":
+		for _, name := range this.repl.Units() {
+			this.print(name, "\t", this.repl.units[name].value.Interface())
+		}
+	case "methods":
+		if len(args) != 2 {
+			this.print("Error: Expected methods <unit>")
+		} else if methods, err := this.repl.Methods(args[1]); err != nil {
+			this.print("Error: ", err)
+		} else {
+			this.print(strings.Join(methods, "\n"))
+		}
+	case "subscribe":
+		if len(args) > 2 {
+			this.print("Error: Expected subscribe [pattern]")
+		} else if err := this.subscribe(ctx, strings.Join(args[1:], "")); err != nil {
+			this.print("Error: ", err)
+		}
+	case "unsubscribe":
+		this.unsubscribe()
+	default:
+		if this.AccessControl != nil {
+			if err := this.AccessControl.Authorize(ctx, args[0]); err != nil {
+				this.print("Error: ", err)
+				return false
+			}
+		}
+		var w strings.Builder
+		err := this.repl.Call(ctx, &w, args[0], args[1:])
+		if this.AuditLog != nil && gopi.AuditMethod(args[0]) {
+			this.audit(ctx, args, err)
+		}
+		if out := strings.TrimSuffix(w.String(), "\n"); out != "" {
+			this.print(out)
+		}
+		if err != nil {
+			this.print("Error: ", err)
+		}
+	}
+	return false
+}
+
+// audit records a method call with the arguments as the new value
+func (this *conn) audit(ctx context.Context, args []string, err error) {
+	entry := gopi.AuditEntry{
+		Source: "shell",
+		Action: args[0],
+		New:    strings.Join(args[1:], " "),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if err := this.AuditLog.Record(ctx, entry); err != nil {
+		this.print("Error: ", err)
+	}
+}
+
+// subscribe shows events with names matching a pattern in the
+// background, replacing any existing subscription
+func (this *conn) subscribe(ctx context.Context, pattern string) error {
+	if this.Publisher == nil {
+		return gopi.ErrNotImplemented.WithPrefix("No publisher")
+	}
+	this.unsubscribe()
+
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	child, cancel := context.WithCancel(ctx)
+	this.cancel = cancel
+	ch := this.Publisher.Subscribe()
+	go func() {
+		defer this.Publisher.Unsubscribe(ch)
+		for {
+			select {
+			case <-child.Done():
+				// Read events while unsubscribing
+				go func() {
+					for range ch {
+					}
+				}()
+				return
+			case evt := <-ch:
+				if evt != nil && matchEvent(pattern, evt) {
+					this.print(fmt.Sprint("[", evt.Name(), "] ", evt))
+				}
+			}
+		}
+	}()
+
+	// Return success
+	return nil
+}
+
+func (this *conn) unsubscribe() {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	if this.cancel != nil {
+		this.cancel()
+		this.cancel = nil
+	}
+}
@@ -0,0 +1,125 @@
+package tool
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type shell struct {
+	gopi.Unit
+	gopi.Publisher
+	gopi.AuditLog
+
+	objs    []interface{}
+	in      io.Reader
+	out     io.Writer
+	session *session
+}
+
+// printer writes complete lines through the line editor
+type printer struct {
+	sync.Mutex
+	rl  *readline
+	buf []byte
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// BOOTSTRAP
+
+// Shell runs an interactive shell which lists the units used by the
+// objects, calls their methods and shows events. The application ends
+// when the shell is quit
+func Shell(name string, args []string, objs ...interface{}) int {
+	sh := &shell{objs: objs, in: os.Stdin, out: os.Stdout}
+	return CommandLine(name, args, append([]interface{}{sh}, objs...)...)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *shell) New(cfg gopi.Config) error {
+	if this.in == nil {
+		this.in, this.out = os.Stdin, os.Stdout
+	}
+	this.session = newSession(this.Publisher, nil, this.AuditLog, this.objs...)
+	return nil
+}
+
+func (this *shell) Run(ctx context.Context) error {
+	// Actions are performed by the local user
+	if u, err := user.Current(); err == nil {
+		ctx = gopi.WithAccessIdentity(ctx, gopi.AccessIdentity{Name: u.Username})
+	}
+	return runShell(ctx, this.session, this.in, this.out)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// runShell edits lines from the input and sends them to a shell session,
+// until the session ends, input ends or the context is cancelled. Line
+// editing is used when the input is a terminal
+func runShell(ctx context.Context, sh gopi.Shell, in io.Reader, out io.Writer) error {
+	raw := false
+	if fh, ok := in.(*os.File); ok {
+		if restore, err := makeRaw(int(fh.Fd())); err == nil {
+			defer restore()
+			raw = true
+		}
+	}
+	prompt := ""
+	if raw {
+		prompt = "> "
+	}
+	rl := newReadline(in, out, raw, prompt, sh.Complete)
+
+	// Run the session in the background
+	pr, pw := io.Pipe()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- sh.Run(ctx, pr, &printer{rl: rl})
+		pr.Close()
+	}()
+
+	// Send lines to the session until end of input
+	go func() {
+		for {
+			line, err := rl.ReadLine()
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			} else if _, err := io.WriteString(pw, line+"\n"); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Wait for session to end
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-errs:
+		return err
+	}
+}
+
+// Write outputs complete lines, retaining any partial line
+func (this *printer) Write(data []byte) (int, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+	this.buf = append(this.buf, data...)
+	if i := strings.LastIndexByte(string(this.buf), '\n'); i >= 0 {
+		this.rl.Print(string(this.buf[:i]))
+		this.buf = this.buf[i+1:]
+	}
+	return len(data), nil
+}
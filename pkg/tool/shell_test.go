@@ -0,0 +1,165 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type Counter interface {
+	Add(uint) uint
+	Reset(context.Context, gopi.GPIOState) error
+	Names(...string) []string
+}
+
+type counter struct {
+	gopi.Unit
+	n     uint
+	state gopi.GPIOState
+}
+
+type app struct {
+	gopi.Unit
+	Counter
+}
+
+func (this *counter) Add(n uint) uint {
+	this.n += n
+	return this.n
+}
+
+func (this *counter) Reset(ctx context.Context, state gopi.GPIOState) error {
+	if ctx == nil {
+		return gopi.ErrInternalAppError
+	}
+	this.n, this.state = 0, state
+	return nil
+}
+
+func (this *counter) Names(names ...string) []string {
+	return names
+}
+
+func (this *counter) String() string {
+	return "<counter>"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Shell_001(t *testing.T) {
+	c := new(counter)
+	r := newRepl(&app{Counter: c})
+	if units := r.Units(); reflect.DeepEqual(units, []string{"Counter"}) == false {
+		t.Error("Unexpected units", units)
+	}
+	if methods, err := r.Methods("Counter"); err != nil {
+		t.Error(err)
+	} else if len(methods) != 3 || methods[0] != "Counter.Add(uint) uint" {
+		t.Error("Unexpected methods", methods)
+	}
+	if _, err := r.Methods("Other"); err == nil {
+		t.Error("Expected error for unknown unit")
+	}
+
+	var w bytes.Buffer
+	if err := r.Call(context.Background(), &w, "Counter.Add", []string{"3"}); err != nil {
+		t.Error(err)
+	} else if err := r.Call(context.Background(), &w, "Counter.Add", []string{"0x10"}); err != nil {
+		t.Error(err)
+	} else if w.String() != "3\n19\n" {
+		t.Errorf("Unexpected output %q", w.String())
+	}
+	if err := r.Call(context.Background(), &w, "Counter.Reset", []string{"gpio_high"}); err != nil {
+		t.Error(err)
+	} else if c.n != 0 || c.state != gopi.GPIO_HIGH {
+		t.Error("Unexpected state", c)
+	}
+	w.Reset()
+	if err := r.Call(context.Background(), &w, "Counter.Names", []string{"a", "b"}); err != nil {
+		t.Error(err)
+	} else if w.String() != "a\nb\n" {
+		t.Errorf("Unexpected output %q", w.String())
+	}
+	for _, args := range [][]string{{"Counter.Add"}, {"Counter.Add", "x"}, {"Counter.Add", "1", "2"}, {"Counter.Run"}, {"Counter.Other"}, {"Other.Add", "1"}, {"Add", "1"}} {
+		if err := r.Call(context.Background(), &w, args[0], args[1:]); err == nil {
+			t.Error("Expected error for", args)
+		}
+	}
+}
+
+func Test_Shell_002(t *testing.T) {
+	r := newRepl(&app{Counter: new(counter)})
+	tests := []struct {
+		line       string
+		candidates []string
+	}{
+		{"un", []string{"units", "unsubscribe"}},
+		{"Co", []string{"Counter."}},
+		{"Counter.N", []string{"Counter.Names"}},
+		{"methods ", []string{"Counter"}},
+		{"Counter.Add ", []string{}},
+	}
+	for _, test := range tests {
+		if candidates := r.Complete(test.line); reflect.DeepEqual(candidates, test.candidates) == false {
+			t.Errorf("%q: Unexpected candidates %q", test.line, candidates)
+		}
+	}
+	if args, err := splitArgs(` a  "b c" "d\"e" `); err != nil {
+		t.Error(err)
+	} else if reflect.DeepEqual(args, []string{"a", "b c", `d"e`}) == false {
+		t.Errorf("Unexpected args %q", args)
+	}
+	if _, err := splitArgs(`"a`); err == nil {
+		t.Error("Expected error for unterminated string")
+	}
+}
+
+func Test_Shell_003(t *testing.T) {
+	// Line editing, history and completion in raw mode
+	var out bytes.Buffer
+	in := strings.NewReader("ac\x1b[Db\r\x1b[A!\r\x1b[A\x01x\x05\x08\r" + "Co\t\r")
+	rl := newReadline(in, &out, true, "> ", newRepl(&app{Counter: new(counter)}).Complete)
+	for _, expected := range []string{"abc", "abc!", "xabc", "Counter."} {
+		if line, err := rl.ReadLine(); err != nil {
+			t.Error(err)
+		} else if line != expected {
+			t.Errorf("Expected %q, got %q", expected, line)
+		}
+	}
+	if _, err := rl.ReadLine(); err == nil {
+		t.Error("Expected end of input")
+	}
+}
+
+func Test_Shell_004(t *testing.T) {
+	var out bytes.Buffer
+	var sh gopi.Shell = newSession(nil, nil, nil, &app{Counter: new(counter)})
+	in := strings.NewReader("units\nCounter.Add 2\nCounter.Add x\nsubscribe\nquit\nCounter.Add 1\n")
+	if err := sh.Run(context.Background(), in, &out); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Counter\t<counter>\n2\nError: \"x\": Bad Parameter\nError: No publisher: Not Implemented\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func Test_Shell_005(t *testing.T) {
+	// Output from a session is written through the line editor
+	var out bytes.Buffer
+	in := strings.NewReader("Counter.Names a b\nCounter.Add 1\n")
+	sh := newSession(nil, nil, nil, &app{Counter: new(counter)})
+	if err := runShell(context.Background(), sh, in, &out); err != nil {
+		t.Fatal(err)
+	} else if out.String() != "a\nb\n1\n" {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+}
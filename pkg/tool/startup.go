@@ -0,0 +1,244 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type startup struct {
+	record, replay *string
+	recording      *recording
+}
+
+// startupGraph returns the results of calling New for each unit and
+// the platform, if used
+type startupGraph interface {
+	Startup() []graph.Startup
+	GetPlatform() gopi.Platform
+}
+
+// recording is the flags, hardware and unit results when starting
+type recording struct {
+	Name      string            `json:"name"`
+	Created   time.Time         `json:"created"`
+	Version   string            `json:"version,omitempty"`
+	GoVersion string            `json:"go"`
+	Arch      string            `json:"arch"`
+	Flags     map[string]string `json:"flags"`
+	Defaults  map[string]string `json:"defaults"`
+	Hardware  *hardware         `json:"hardware,omitempty"`
+	Units     []graph.Startup   `json:"units"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// hardware is what the platform discovered
+type hardware struct {
+	Product     string             `json:"product"`
+	Type        string             `json:"type"`
+	Temperature map[string]float32 `json:"temperature,omitempty"`
+	GPUMemory   map[string]uint64  `json:"gpu_memory,omitempty"`
+	Codecs      map[string]bool    `json:"codecs,omitempty"`
+	Camera      [2]bool            `json:"camera"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	redacted = "<redacted>"
+)
+
+var (
+	// Flags defined with FlagSecret are not recorded, nor are other
+	// flags containing these words
+	secretFlags = []string{"password", "token", "secret"}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// defineStartup defines the flags for recording and replaying startup
+func defineStartup(cfg gopi.Config) *startup {
+	this := new(startup)
+	this.record = cfg.FlagString("record-startup", "", "Write flags, hardware and unit startup to a file for bug reports")
+	this.replay = cfg.FlagString("replay-startup", "", "Start with flags from a recording and compare unit startup")
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Replay reads a recording and sets flags which were recorded and have
+// not been set, when the replay flag is set
+func (this *startup) Replay(cfg gopi.Config) error {
+	if *this.replay == "" {
+		return nil
+	}
+
+	// Read recording
+	fh, err := os.Open(*this.replay)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	r := new(recording)
+	if err := json.NewDecoder(fh).Decode(r); err != nil {
+		return gopi.ErrBadParameter.WithPrefix(*this.replay, ": ", err)
+	}
+
+	// Set flags, except those which are set or were redacted
+	set := cfg.Values(false)
+	for name, value := range r.Flags {
+		if _, exists := set[name]; exists || value == redacted || isStartupFlag(name) {
+			continue
+		} else if err := cfg.Set(name, value); err != nil {
+			return fmt.Errorf("-%v: %w", name, err)
+		}
+	}
+
+	// Return success
+	this.recording = r
+	return nil
+}
+
+// Record writes flags, hardware and unit results to a file when the
+// record flag is set
+func (this *startup) Record(cfg gopi.Config, g startupGraph, err error) error {
+	if *this.record == "" {
+		return nil
+	}
+
+	// Flags which are not set are recorded as defaults
+	r := &recording{
+		Name:      cfg.Version().Name(),
+		Created:   time.Now().UTC(),
+		GoVersion: runtime.Version(),
+		Arch:      runtime.GOOS + "/" + runtime.GOARCH,
+		Flags:     make(map[string]string),
+		Defaults:  make(map[string]string),
+		Hardware:  newHardware(g.GetPlatform()),
+		Units:     g.Startup(),
+	}
+	if tag, branch, hash := cfg.Version().Version(); tag != "" || hash != "" {
+		r.Version = strings.TrimSpace(fmt.Sprint(tag, " ", branch, " ", hash))
+	}
+	set := cfg.Values(false)
+	for name, value := range cfg.Values(true) {
+		if isStartupFlag(name) {
+			continue
+		} else if isSecretFlag(cfg, name) && value != "" {
+			value = redacted
+		}
+		if _, exists := set[name]; exists {
+			r.Flags[name] = value
+		} else {
+			r.Defaults[name] = value
+		}
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	// Write recording
+	fh, err := os.OpenFile(*this.record, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(fh)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		fh.Close()
+		return err
+	}
+	return fh.Close()
+}
+
+// Compare returns true when replaying, and the differences between the
+// recorded unit results and the replayed ones
+func (this *startup) Compare(g startupGraph, err error) (bool, []string) {
+	if this.recording == nil {
+		return false, nil
+	}
+
+	// Compare unit results
+	result := []string{}
+	replayed := make(map[string]graph.Startup)
+	for _, unit := range g.Startup() {
+		replayed[unit.Unit] = unit
+	}
+	for _, unit := range this.recording.Units {
+		if other, exists := replayed[unit.Unit]; exists == false {
+			result = append(result, fmt.Sprintf("%v: recorded %v, not started", unit.Unit, startupResult(unit.Error)))
+		} else if unit.Error != other.Error {
+			result = append(result, fmt.Sprintf("%v: recorded %v, replayed %v", unit.Unit, startupResult(unit.Error), startupResult(other.Error)))
+		}
+		delete(replayed, unit.Unit)
+	}
+	for name, other := range replayed {
+		result = append(result, fmt.Sprintf("%v: not recorded, replayed %v", name, startupResult(other.Error)))
+	}
+	sort.Strings(result)
+
+	// Compare startup error
+	var replayedErr string
+	if err != nil {
+		replayedErr = err.Error()
+	}
+	if replayedErr != this.recording.Error {
+		result = append(result, fmt.Sprintf("recorded %v, replayed %v", startupResult(this.recording.Error), startupResult(replayedErr)))
+	}
+
+	// Return differences
+	return true, result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func newHardware(platform gopi.Platform) *hardware {
+	if platform == nil {
+		return nil
+	}
+	this := new(hardware)
+	this.Product = platform.Product()
+	this.Type = fmt.Sprint(platform.Type())
+	this.Temperature = platform.TemperatureZones()
+	this.GPUMemory = platform.GPUMemory()
+	this.Codecs = platform.Codecs()
+	this.Camera[0], this.Camera[1] = platform.Camera()
+	return this
+}
+
+func isStartupFlag(name string) bool {
+	return name == "record-startup" || name == "replay-startup"
+}
+
+func isSecretFlag(cfg gopi.Config, name string) bool {
+	if cfg.Secret(name) {
+		return true
+	}
+	for _, word := range secretFlags {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func startupResult(err string) string {
+	if err == "" {
+		return "ok"
+	}
+	return fmt.Sprintf("error %q", err)
+}
@@ -0,0 +1,147 @@
+package tool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	config "github.com/djthorpe/gopi/v3/pkg/config"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/dev/esp"
+	_ "github.com/djthorpe/gopi/v3/pkg/dev/iot"
+	_ "github.com/djthorpe/gopi/v3/pkg/dev/tradfri"
+	_ "github.com/djthorpe/gopi/v3/pkg/feed/weather"
+	_ "github.com/djthorpe/gopi/v3/pkg/listen/remote"
+	_ "github.com/djthorpe/gopi/v3/pkg/notify"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type fakeGraph []graph.Startup
+
+func (this fakeGraph) Startup() []graph.Startup   { return this }
+func (this fakeGraph) GetPlatform() gopi.Platform { return nil }
+
+// secretsApp has units with flags for passwords, keys and tokens
+type secretsApp struct {
+	gopi.Unit
+	gopi.CloudConnector
+	gopi.ESPManager
+	gopi.Notifier
+	gopi.SpeechRecognizer
+	gopi.TradfriManager
+	gopi.WeatherFeed
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Startup_001(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "startup.json")
+	units := fakeGraph{{Unit: "*i2c.i2c"}, {Unit: "*gpio.gpio", Error: "not found"}}
+
+	// Record flags and unit results
+	cfg := config.New("test", []string{"-record-startup=" + path, "-bus=2", "-smtp.password=secret"})
+	cfg.FlagUint("bus", 1, "Bus")
+	cfg.FlagString("smtp.password", "", "Password")
+	cfg.FlagString("name", "default", "Name")
+	s := defineStartup(cfg)
+	if err := cfg.Parse(); err != nil {
+		t.Fatal(err)
+	} else if err := s.Record(cfg, units, errors.New("failed")); err != nil {
+		t.Fatal(err)
+	}
+	var r recording
+	if data, err := ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	} else if r.Flags["bus"] != "2" || r.Flags["smtp.password"] != redacted || r.Defaults["name"] != "default" || r.Error != "failed" {
+		t.Error("Unexpected recording", r)
+	} else if _, exists := r.Flags["record-startup"]; exists {
+		t.Error("Unexpected startup flag in recording")
+	} else if len(r.Units) != 2 || r.Units[1].Error != "not found" {
+		t.Error("Unexpected units", r.Units)
+	}
+
+	// Replay sets flags which are not set, and compares unit results
+	cfg = config.New("test", []string{"-replay-startup=" + path, "-name=other"})
+	bus := cfg.FlagUint("bus", 1, "Bus")
+	password := cfg.FlagString("smtp.password", "", "Password")
+	name := cfg.FlagString("name", "default", "Name")
+	s = defineStartup(cfg)
+	if err := cfg.Parse(); err != nil {
+		t.Fatal(err)
+	} else if err := s.Replay(cfg); err != nil {
+		t.Fatal(err)
+	} else if *bus != 2 || *password != "" || *name != "other" {
+		t.Error("Unexpected flags", *bus, *password, *name)
+	}
+	if replayed, differences := s.Compare(units, errors.New("failed")); replayed == false || len(differences) != 0 {
+		t.Error("Unexpected differences", differences)
+	}
+	if _, differences := s.Compare(fakeGraph{{Unit: "*i2c.i2c"}, {Unit: "*gpio.gpio"}}, nil); len(differences) != 2 {
+		t.Error("Unexpected differences", differences)
+	} else {
+		t.Log(differences)
+	}
+}
+
+func Test_Startup_002(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "startup.json")
+	secrets := map[string]string{
+		"iot.key":              "device.key",
+		"iot.sas":              "c2FzCg==",
+		"weather.key":          "0123456789abcdef",
+		"stt.key":              "bearer",
+		"tradfri.key":          "gateway",
+		"notify.pushover.user": "user",
+		"notify.smtp.password": "password",
+		"esp.password":         "password",
+	}
+
+	// Define the flags of units with secrets
+	args := []string{"-record-startup=" + path, "-weather.lat=51.5"}
+	for name, value := range secrets {
+		args = append(args, "-"+name+"="+value)
+	}
+	cfg := config.New("test", args)
+	g := graph.NewGraph(t.Log)
+	if err := g.Create(new(secretsApp)); err != nil {
+		t.Fatal(err)
+	} else if err := g.Define(cfg); err != nil {
+		t.Fatal(err)
+	}
+	s := defineStartup(cfg)
+	if err := cfg.Parse(); err != nil {
+		t.Fatal(err)
+	} else if err := s.Record(cfg, g, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Secrets are redacted, and other flags are recorded
+	var r recording
+	if data, err := ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+	for name := range secrets {
+		if value := r.Flags[name]; value != redacted {
+			t.Errorf("Unexpected value for -%v: %q", name, value)
+		}
+	}
+	if r.Flags["weather.lat"] != "51.5" {
+		t.Error("Unexpected recording", r.Flags)
+	}
+	if str := fmt.Sprint(cfg); strings.Contains(str, "c2FzCg==") {
+		t.Error("Unexpected secret in", str)
+	}
+}
@@ -0,0 +1,12 @@
+// +build darwin
+
+package tool
+
+import (
+	unix "golang.org/x/sys/unix"
+)
+
+const (
+	ioctlGetTermios = unix.TIOCGETA
+	ioctlSetTermios = unix.TIOCSETA
+)
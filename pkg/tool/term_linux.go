@@ -0,0 +1,12 @@
+// +build linux
+
+package tool
+
+import (
+	unix "golang.org/x/sys/unix"
+)
+
+const (
+	ioctlGetTermios = unix.TCGETS
+	ioctlSetTermios = unix.TCSETS
+)
@@ -0,0 +1,12 @@
+// +build !linux,!darwin
+
+package tool
+
+import (
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+// makeRaw is not implemented, so lines are read without editing
+func makeRaw(fd int) (func() error, error) {
+	return nil, gopi.ErrNotImplemented.WithPrefix("makeRaw")
+}
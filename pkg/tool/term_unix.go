@@ -0,0 +1,31 @@
+// +build linux darwin
+
+package tool
+
+import (
+	unix "golang.org/x/sys/unix"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TERMINAL
+
+// makeRaw disables line buffering and echo on a terminal, and returns a
+// function which restores the terminal. Signals are still generated, so
+// that an interrupt ends the application
+func makeRaw(fd int) (func() error, error) {
+	termios, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+	saved := *termios
+	termios.Lflag &^= unix.ICANON | unix.ECHO
+	termios.Iflag &^= unix.ICRNL
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, termios); err != nil {
+		return nil, err
+	}
+	return func() error {
+		return unix.IoctlSetTermios(fd, ioctlSetTermios, &saved)
+	}, nil
+}
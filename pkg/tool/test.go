@@ -37,6 +37,9 @@ func Test(t *testing.T, args []string, obj, fn interface{}) int {
 	} else if err != nil {
 		t.Error("Config:", err)
 		return -1
+	} else if err := cfg.Validate(); err != nil {
+		t.Error("Config:", err)
+		return -1
 	}
 
 	// Set testing flag on logging object
@@ -62,14 +65,17 @@ func Test(t *testing.T, args []string, obj, fn interface{}) int {
 	// functions
 	if fn_ := reflect.ValueOf(fn); fn_.Kind() != reflect.Func {
 		t.Error("Invalid test function")
+		cancel()
 		return -1
 	} else {
 		wg.Add(1)
 		go func() {
+			// Cancel when t.Fatal ends the test routine early, so that
+			// Run returns rather than waits forever
+			defer wg.Done()
+			defer cancel()
 			fn_.Call([]reflect.Value{reflect.ValueOf(obj)})
 			t.Log("Calling cancel")
-			cancel()
-			wg.Done()
 		}()
 	}
 
@@ -90,6 +96,11 @@ func Test(t *testing.T, args []string, obj, fn interface{}) int {
 		return -1
 	}
 
+	// Report goroutines and resources which units left behind
+	for _, leak := range g.Leaks() {
+		t.Log("Leak:", leak)
+	}
+
 	// Return success
 	return 0
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"github.com/djthorpe/gopi/v3"
 	"github.com/djthorpe/gopi/v3/pkg/config"
@@ -15,6 +16,12 @@ import (
 )
 
 func CommandLine(name string, args []string, objs ...interface{}) int {
+	// Load plugins before units are created
+	if _, err := graph.LoadPlugins(os.Getenv(graph.EnvPlugins)); err != nil {
+		fmt.Fprintln(os.Stderr, "Plugins:", err)
+		return -1
+	}
+
 	// Create empty configuration and graph
 	cfg := config.New(name, args)
 	graph, err := graph.Create(objs...)
@@ -31,6 +38,9 @@ func CommandLine(name string, args []string, objs ...interface{}) int {
 		fmt.Fprintln(os.Stderr, "Define:", err)
 		return -1
 	}
+	provision := defineProvision(cfg)
+	startup := defineStartup(cfg)
+	validate := cfg.FlagBool("validate", false, "Check configuration and exit without running")
 
 	// Parse command-line arguments
 	if err := cfg.Parse(); errors.Is(err, gopi.ErrHelp) || errors.Is(err, flag.ErrHelp) {
@@ -40,11 +50,58 @@ func CommandLine(name string, args []string, objs ...interface{}) int {
 		return -1
 	}
 
+	// Set flags from a startup recording
+	if err := startup.Replay(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, "Replay:", err)
+		return -1
+	}
+
+	// Export or apply provisioning bundle
+	if exported, err := provision.Export(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, "Export:", err)
+		return -1
+	} else if exported {
+		return 0
+	} else if err := provision.Import(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, "Import:", err)
+		return -1
+	}
+
+	// Check flag groups, and exit before units are created when
+	// only validating the configuration
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "Config:", err)
+		return -1
+	} else if *validate {
+		fmt.Fprintln(os.Stderr, "Config: OK")
+		return 0
+	}
+
 	// Call New
-	if err := graph.New(cfg); errors.Is(err, gopi.ErrHelp) || errors.Is(err, flag.ErrHelp) {
+	err = graph.New(cfg)
+	if errors.Is(err, gopi.ErrHelp) || errors.Is(err, flag.ErrHelp) {
 		cfg.Usage("")
 		return 0
-	} else if err != nil {
+	}
+
+	// Record the startup, or compare it with a recording and exit
+	if err := startup.Record(cfg, graph, err); err != nil {
+		fmt.Fprintln(os.Stderr, "Record:", err)
+	}
+	if replayed, differences := startup.Compare(graph, err); replayed {
+		if err == nil {
+			graph.Dispose()
+		}
+		for _, difference := range differences {
+			fmt.Fprintln(os.Stderr, "Replay:", difference)
+		}
+		if len(differences) > 0 {
+			return -1
+		}
+		fmt.Fprintln(os.Stderr, "Replay: OK, startup matches recording")
+		return 0
+	}
+	if err != nil {
 		fmt.Fprintln(os.Stderr, "New:", err)
 		return -1
 	}
@@ -60,14 +117,20 @@ func CommandLine(name string, args []string, objs ...interface{}) int {
 		if err := graph.Dispose(); err != nil {
 			fmt.Fprintln(os.Stderr, "Dispose:", err)
 		}
+		if logger != nil && logger.IsDebug() {
+			for _, leak := range graph.Leaks() {
+				logger.Debug("Leak: ", leak)
+			}
+		}
 	}()
 
 	// Create context with a cancel
 	ctx, cancel := context.WithCancel(context.Background())
 
-	// Handle signals - call cancel when interrupt received
+	// Handle signals - call cancel when interrupt or terminate received,
+	// so that units are disposed
 	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, os.Interrupt)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		s := <-ch
 		if logger != nil && logger.IsDebug() {
@@ -0,0 +1,24 @@
+// Twin package implements gopi.Twin, a device twin which aggregates the
+// last-known state of units from the events they emit. Events with names
+// matching the -twin.events patterns are added to a document, where the
+// event name is the key, for example:
+//
+//	{
+//	  "version": 3,
+//	  "state": {
+//	    "GPIO17": { "pin": "GPIO17", "edge": "GPIO_EDGE_RISING" },
+//	    "ups": { "type": "UPS_EVENT_BATTERY", "status": { "Voltage": 7.4, ... } }
+//	  }
+//	}
+//
+// The state for an event is the values of the event methods with no
+// arguments which return a number, boolean, string, structure or value
+// with a String method, with the first letter of the method name in
+// lowercase. Values returned as interfaces, such as the cast for a cast
+// event, are nested and the fields of measurements are added.
+//
+// The version increases on each change and a gopi.TwinEvent with the
+// changes is emitted. When gopi.Server is available the document is served
+// as JSON on the -twin.path, and the changes since a version are returned
+// with the "since" query parameter, for example /twin?since=3
+package twin
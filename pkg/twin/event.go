@@ -0,0 +1,55 @@
+package twin
+
+import (
+	"fmt"
+	"sort"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	version uint64
+	changes map[string]interface{}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(version uint64, changes map[string]interface{}) gopi.TwinEvent {
+	return &event{version, changes}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return "twin"
+}
+
+func (this *event) Version() uint64 {
+	return this.version
+}
+
+func (this *event) Changes() map[string]interface{} {
+	return this.changes
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<event.twin"
+	str += fmt.Sprint(" version=", this.version)
+	keys := make([]string, 0, len(this.changes))
+	for key := range this.changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		str += fmt.Sprintf(" %q=%v", key, this.changes[key])
+	}
+	return str + ">"
+}
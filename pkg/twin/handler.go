@@ -0,0 +1,56 @@
+package twin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// handler serves the document as JSON, or the changes since a version
+// when the "since" query parameter is set
+type handler struct {
+	gopi.Twin
+}
+
+type document struct {
+	Version uint64                 `json:"version"`
+	State   map[string]interface{} `json:"state,omitempty"`
+	Changes map[string]interface{} `json:"changes,omitempty"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewHandler(twin gopi.Twin) http.Handler {
+	return &handler{twin}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// HANDLER
+
+func (this *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	doc := document{}
+	if since := req.URL.Query().Get("since"); since == "" {
+		doc.State, doc.Version = this.Twin.State()
+	} else if version, err := strconv.ParseUint(since, 10, 64); err != nil {
+		http.Error(w, gopi.ErrBadParameter.WithPrefix("since").Error(), http.StatusBadRequest)
+		return
+	} else {
+		doc.Changes, doc.Version = this.Twin.Changes(version)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,13 @@
+package twin
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register gopi.Twin
+	graph.RegisterUnit(reflect.TypeOf(&twin{}), reflect.TypeOf((*gopi.Twin)(nil)))
+}
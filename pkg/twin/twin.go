@@ -0,0 +1,258 @@
+package twin
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type twin struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Server
+	sync.RWMutex
+
+	events  *string
+	path    *string
+	globs   []string
+	ch      <-chan gopi.Event
+	version uint64
+	state   map[string]*entry
+}
+
+// entry is the state for a key and the version when it last changed
+type entry struct {
+	value   map[string]interface{}
+	version uint64
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	typeStringer = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *twin) Define(cfg gopi.Config) error {
+	this.events = cfg.FlagString("twin.events", "*", "Comma-separated patterns of event names")
+	this.path = cfg.FlagString("twin.path", "/twin", "Path to serve document, or empty to disable")
+	return nil
+}
+
+func (this *twin) New(gopi.Config) error {
+	this.Require(this.Logger, this.Publisher)
+
+	// Check patterns
+	for _, glob := range strings.Split(*this.events, ",") {
+		if glob = strings.TrimSpace(glob); glob == "" {
+			continue
+		} else if _, err := path.Match(glob, ""); err != nil {
+			return gopi.ErrBadParameter.WithPrefix("-twin.events: ", glob)
+		} else {
+			this.globs = append(this.globs, glob)
+		}
+	}
+
+	// Subscribe to events before units run, so that state is not missed
+	this.state = make(map[string]*entry)
+	this.ch = this.Publisher.Subscribe()
+
+	// Serve document
+	if this.Server != nil && *this.path != "" {
+		if err := this.Server.RegisterService(*this.path, NewHandler(this)); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *twin) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	// Unsubscribe from events
+	if this.ch != nil {
+		this.Publisher.Unsubscribe(this.ch)
+	}
+
+	// Release resources
+	this.ch = nil
+	this.state = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *twin) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-this.ch:
+			if evt == nil {
+				continue
+			} else if changes, version := this.update(evt); len(changes) > 0 {
+				if err := this.Publisher.Emit(NewEvent(version, changes), false); err != nil {
+					this.Debug("Twin: ", err)
+				}
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *twin) State() (map[string]interface{}, uint64) {
+	return this.Changes(0)
+}
+
+func (this *twin) Changes(version uint64) (map[string]interface{}, uint64) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	result := make(map[string]interface{}, len(this.state))
+	for key, entry := range this.state {
+		if entry.version > version {
+			result[key] = entry.value
+		}
+	}
+	return result, this.version
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *twin) String() string {
+	str := "<twin"
+	state, version := this.State()
+	str += fmt.Sprint(" version=", version)
+	keys := make([]string, 0, len(state))
+	for key := range state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		str += fmt.Sprintf(" %q=%v", key, state[key])
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// update sets the state for an event when its name matches a pattern,
+// and returns the changes and the new version, or nil if the state has
+// not changed
+func (this *twin) update(evt gopi.Event) (map[string]interface{}, uint64) {
+	if _, ok := evt.(gopi.TwinEvent); ok || this.match(evt.Name()) == false {
+		return nil, 0
+	}
+
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	key, value := evt.Name(), values(evt, true)
+	if this.state == nil || len(value) == 0 {
+		return nil, 0
+	} else if prev, exists := this.state[key]; exists && reflect.DeepEqual(prev.value, value) {
+		return nil, 0
+	}
+
+	this.version++
+	this.state[key] = &entry{value, this.version}
+	return map[string]interface{}{key: value}, this.version
+}
+
+// match returns true if a name matches one of the patterns
+func (this *twin) match(name string) bool {
+	for _, glob := range this.globs {
+		if match, _ := path.Match(glob, name); match {
+			return true
+		}
+	}
+	return false
+}
+
+// values returns the state reported by an event or a value returned by
+// an event. Methods with no arguments returning a number, boolean, string,
+// structure or value with a String method are keys with the first letter
+// in lowercase. Interface values returned by an event are nested, and fields
+// of measurements are added
+func values(obj interface{}, nested bool) map[string]interface{} {
+	result := make(map[string]interface{})
+	v := reflect.ValueOf(obj)
+	for i := 0; i < v.NumMethod(); i++ {
+		method := v.Type().Method(i)
+		if method.Type.NumIn() != 1 || method.Type.NumOut() != 1 || method.Name == "String" || method.Name == "Name" {
+			continue
+		}
+		key := strings.ToLower(method.Name[:1]) + method.Name[1:]
+		if t := method.Type.Out(0); t.Kind() == reflect.Interface {
+			if nested == false || t.NumMethod() == 0 {
+				continue
+			} else if value := v.Method(i).Call(nil)[0]; value.IsNil() == false {
+				result[key] = values(value.Interface(), false)
+			}
+		} else if value, ok := toValue(v.Method(i).Call(nil)[0]); ok {
+			result[key] = value
+		}
+	}
+	if measurement, ok := obj.(gopi.Measurement); ok {
+		for _, field := range append(measurement.Tags(), measurement.Metrics()...) {
+			if value, ok := toValue(reflect.ValueOf(field.Value())); ok {
+				result[field.Name()] = value
+			}
+		}
+	}
+	return result
+}
+
+// toValue returns a value which can be encoded as JSON, or false if
+// the value cannot be converted
+func toValue(v reflect.Value) (interface{}, bool) {
+	if v.IsValid() == false || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return nil, false
+	} else if v.Kind() == reflect.Struct {
+		// Structures such as readings are encoded with their fields
+		return v.Interface(), true
+	} else if v.Type().Implements(typeStringer) && v.Kind() != reflect.Interface {
+		return v.Interface().(fmt.Stringer).String(), true
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), true
+	case reflect.Float32:
+		// Avoid rounding errors when converting to float64
+		value, _ := strconv.ParseFloat(strconv.FormatFloat(v.Float(), 'g', -1, 32), 64)
+		return value, true
+	case reflect.Float64:
+		return v.Float(), true
+	case reflect.Bool:
+		return v.Bool(), true
+	case reflect.String:
+		return v.String(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,122 @@
+package twin_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	gpio "github.com/djthorpe/gopi/v3/pkg/hw/gpio"
+	testutil "github.com/djthorpe/gopi/v3/pkg/internal/testutil"
+	tool "github.com/djthorpe/gopi/v3/pkg/tool"
+	twin "github.com/djthorpe/gopi/v3/pkg/twin"
+
+	_ "github.com/djthorpe/gopi/v3/pkg/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type App struct {
+	gopi.Unit
+	gopi.Twin
+	gopi.Publisher
+}
+
+func (this *App) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Twin_001(t *testing.T) {
+	args := []string{"-twin.events", "GPIO*"}
+	tool.Test(t, args, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		// Only GPIO events change the state, and repeated events do not
+		events := []gopi.Event{
+			gpio.NewEvent("GPIO17", 17, gopi.GPIO_EDGE_RISING),
+			gpio.NewEvent("other", 18, gopi.GPIO_EDGE_RISING),
+			gpio.NewEvent("GPIO17", 17, gopi.GPIO_EDGE_RISING),
+			gpio.NewEvent("GPIO27", 27, gopi.GPIO_EDGE_FALLING),
+		}
+		for _, evt := range events {
+			if err := app.Publisher.Emit(evt, true); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if evt, _ := testutil.Next(ch, isVersion(2)).(gopi.TwinEvent); evt == nil {
+			t.Fatal("Timeout waiting for twin event")
+		} else if _, exists := evt.Changes()["GPIO27"]; exists == false || len(evt.Changes()) != 1 {
+			t.Error("Unexpected changes", evt)
+		}
+
+		// Check state
+		state, version := app.Twin.State()
+		if version != 2 || len(state) != 2 {
+			t.Error("Unexpected state", version, state)
+		} else if value := state["GPIO17"].(map[string]interface{}); value["pin"] != "GPIO17" || value["edge"] != "GPIO_EDGE_RISING" {
+			t.Error("Unexpected state", value)
+		}
+		if changes, version := app.Twin.Changes(1); version != 2 || len(changes) != 1 || changes["GPIO27"] == nil {
+			t.Error("Unexpected changes", version, changes)
+		}
+		t.Log(app.Twin)
+	})
+}
+
+func Test_Twin_002(t *testing.T) {
+	tool.Test(t, nil, new(App), func(app *App) {
+		ch := app.Publisher.Subscribe()
+		defer app.Publisher.Unsubscribe(ch)
+
+		if err := app.Publisher.Emit(gpio.NewEvent("GPIO17", 17, gopi.GPIO_EDGE_FALLING), true); err != nil {
+			t.Fatal(err)
+		} else if testutil.Next(ch, isVersion(1)) == nil {
+			t.Fatal("Timeout waiting for twin event")
+		}
+
+		// Serve document and changes
+		handler := twin.NewHandler(app.Twin)
+		for _, test := range []struct {
+			url    string
+			status int
+			key    string
+		}{
+			{"/twin", http.StatusOK, "state"},
+			{"/twin?since=0", http.StatusOK, "changes"},
+			{"/twin?since=x", http.StatusBadRequest, ""},
+		} {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.url, nil))
+			if w.Code != test.status {
+				t.Error(test.url, "Unexpected status", w.Code)
+			} else if test.key != "" {
+				doc := make(map[string]interface{})
+				if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+					t.Error(test.url, err)
+				} else if doc["version"] != float64(1) || doc[test.key] == nil {
+					t.Error(test.url, "Unexpected document", w.Body.String())
+				}
+			}
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// isVersion returns a function which returns true for a twin event with
+// a version
+func isVersion(version uint64) func(gopi.Event) bool {
+	return func(evt gopi.Event) bool {
+		evt_, ok := evt.(gopi.TwinEvent)
+		return ok && evt_.Version() == version
+	}
+}
@@ -0,0 +1,153 @@
+package wifi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ctrl is a connection to the wpa_supplicant control socket
+// for a single interface
+type ctrl struct {
+	sync.Mutex
+
+	iface string
+	local string
+	conn  *net.UnixConn
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	ctrlTimeout    = 5 * time.Second
+	ctrlBufferSize = 8192
+)
+
+var (
+	ctrlCounter uint
+	ctrlLock    sync.Mutex
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func newCtrl(path, iface string) (*ctrl, error) {
+	this := new(ctrl)
+	this.iface = iface
+
+	// Create a unique local socket path
+	ctrlLock.Lock()
+	ctrlCounter++
+	this.local = filepath.Join(os.TempDir(), fmt.Sprintf("gopi_wpa_ctrl_%d-%d", os.Getpid(), ctrlCounter))
+	ctrlLock.Unlock()
+
+	laddr := &net.UnixAddr{Name: this.local, Net: "unixgram"}
+	raddr := &net.UnixAddr{Name: filepath.Join(path, iface), Net: "unixgram"}
+	if conn, err := net.DialUnix("unixgram", laddr, raddr); err != nil {
+		os.Remove(this.local)
+		return nil, err
+	} else {
+		this.conn = conn
+	}
+
+	// Return success
+	return this, nil
+}
+
+func (this *ctrl) Close() error {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	var result error
+	if this.conn != nil {
+		result = this.conn.Close()
+	}
+	os.Remove(this.local)
+
+	// Release resources
+	this.conn = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *ctrl) String() string {
+	return fmt.Sprintf("<wifi.ctrl iface=%q>", this.iface)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// Request sends a command and returns the response, ignoring any
+// unsolicited messages which are prefixed with '<'
+func (this *ctrl) Request(cmd string, args ...interface{}) (string, error) {
+	this.Mutex.Lock()
+	defer this.Mutex.Unlock()
+
+	if this.conn == nil {
+		return "", gopi.ErrOutOfOrder.WithPrefix(cmd)
+	}
+	if len(args) > 0 {
+		cmd = cmd + " " + strings.TrimSpace(fmt.Sprintln(args...))
+	}
+	if err := this.conn.SetDeadline(time.Now().Add(ctrlTimeout)); err != nil {
+		return "", err
+	} else if _, err := this.conn.Write([]byte(cmd)); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, ctrlBufferSize)
+	for {
+		if n, err := this.conn.Read(buf); err != nil {
+			return "", err
+		} else if n > 0 && buf[0] == '<' {
+			continue
+		} else {
+			return string(buf[:n]), nil
+		}
+	}
+}
+
+// RequestOK sends a command and returns an error if the response
+// is not OK
+func (this *ctrl) RequestOK(cmd string, args ...interface{}) error {
+	if reply, err := this.Request(cmd, args...); err != nil {
+		return err
+	} else if strings.TrimSpace(reply) != "OK" {
+		return gopi.ErrUnexpectedResponse.WithPrefix(cmd, ": ", strings.TrimSpace(reply))
+	} else {
+		return nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// ctrlInterfaces returns the interfaces which have a control socket
+func ctrlInterfaces(path string) []string {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil
+	}
+	result := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.Mode()&os.ModeSocket != 0 && strings.HasPrefix(file.Name(), "p2p-") == false {
+			result = append(result, file.Name())
+		}
+	}
+	return result
+}
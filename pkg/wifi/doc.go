@@ -0,0 +1,10 @@
+// WiFi package manages wireless network interfaces by communicating
+// with wpa_supplicant over its control socket. It can scan for networks,
+// connect to and select configured networks, and enter access point
+// mode for first-boot provisioning when no known network is available.
+//
+// When a gopi.Server is available and provisioning is enabled, a simple
+// page is served on /wifi/ which lists nearby networks and allows a
+// network to be configured. Redirecting DNS queries to the device
+// (the captive portal) is left to the access point configuration.
+package wifi
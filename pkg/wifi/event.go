@@ -0,0 +1,57 @@
+package wifi
+
+import (
+	"fmt"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type event struct {
+	iface   string
+	state   gopi.WiFiState
+	network gopi.WiFiNetwork
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewEvent(iface string, state gopi.WiFiState, network gopi.WiFiNetwork) gopi.WiFiEvent {
+	return &event{iface, state, network}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *event) Name() string {
+	return this.iface
+}
+
+func (this *event) Interface() string {
+	return this.iface
+}
+
+func (this *event) State() gopi.WiFiState {
+	return this.state
+}
+
+func (this *event) Network() gopi.WiFiNetwork {
+	return this.network
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *event) String() string {
+	str := "<event.wifi"
+	str += fmt.Sprintf(" iface=%q", this.iface)
+	if this.state != gopi.WIFI_STATE_NONE {
+		str += " state=" + fmt.Sprint(this.state)
+	}
+	if this.network != nil {
+		str += " network=" + fmt.Sprint(this.network)
+	}
+	return str + ">"
+}
@@ -0,0 +1,13 @@
+package wifi
+
+import (
+	"reflect"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	graph "github.com/djthorpe/gopi/v3/pkg/graph"
+)
+
+func init() {
+	// Register wifi
+	graph.RegisterUnit(reflect.TypeOf(&wifi{}), reflect.TypeOf((*gopi.WiFi)(nil)))
+}
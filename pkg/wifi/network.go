@@ -0,0 +1,169 @@
+package wifi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type network struct {
+	id     string
+	ssid   string
+	bssid  string
+	freq   uint
+	signal int
+	flags  []string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PARSE
+
+// ParseScanResults parses the response from the SCAN_RESULTS command
+func ParseScanResults(data string) []gopi.WiFiNetwork {
+	result := []gopi.WiFiNetwork{}
+	for i, line := range strings.Split(data, "\n") {
+		// Skip header line
+		if i == 0 && strings.HasPrefix(line, "bssid") {
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		network := &network{bssid: fields[0], ssid: fields[4]}
+		if freq, err := strconv.ParseUint(fields[1], 10, 32); err == nil {
+			network.freq = uint(freq)
+		}
+		if signal, err := strconv.ParseInt(fields[2], 10, 32); err == nil {
+			network.signal = int(signal)
+		}
+		network.flags = parseFlags(fields[3])
+		result = append(result, network)
+	}
+	return result
+}
+
+// ParseListNetworks parses the response from the LIST_NETWORKS command
+func ParseListNetworks(data string) []gopi.WiFiNetwork {
+	result := []gopi.WiFiNetwork{}
+	for i, line := range strings.Split(data, "\n") {
+		// Skip header line
+		if i == 0 && strings.HasPrefix(line, "network id") {
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		network := &network{id: fields[0], ssid: fields[1], flags: parseFlags(fields[3])}
+		if fields[2] != "any" {
+			network.bssid = fields[2]
+		}
+		result = append(result, network)
+	}
+	return result
+}
+
+// ParseStatus parses a response of key=value lines as returned by
+// the STATUS and SIGNAL_POLL commands
+func ParseStatus(data string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(data, "\n") {
+		if kv := strings.SplitN(line, "=", 2); len(kv) == 2 {
+			result[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PROPERTIES
+
+func (this *network) SSID() string {
+	return this.ssid
+}
+
+func (this *network) BSSID() string {
+	return this.bssid
+}
+
+func (this *network) Frequency() uint {
+	return this.freq
+}
+
+func (this *network) Signal() int {
+	return this.signal
+}
+
+func (this *network) Flags() []string {
+	return this.flags
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *network) String() string {
+	str := "<wifi.network"
+	str += fmt.Sprintf(" ssid=%q", this.ssid)
+	if this.bssid != "" {
+		str += " bssid=" + this.bssid
+	}
+	if this.freq != 0 {
+		str += fmt.Sprint(" freq=", this.freq, "MHz")
+	}
+	if this.signal != 0 {
+		str += fmt.Sprint(" signal=", this.signal, "dBm")
+	}
+	if len(this.flags) > 0 {
+		str += fmt.Sprintf(" flags=%q", this.flags)
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// parseFlags converts [A][B] into an array of flags
+func parseFlags(value string) []string {
+	flags := []string{}
+	for _, flag := range strings.Split(value, "]") {
+		if flag = strings.Trim(flag, "[ "); flag != "" {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// hasFlag returns true if a network has a flag set
+func hasFlag(network gopi.WiFiNetwork, flag string) bool {
+	for _, other := range network.Flags() {
+		if other == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// stateForStatus returns connection state from STATUS response
+func stateForStatus(status map[string]string) gopi.WiFiState {
+	if status["mode"] == "AP" {
+		return gopi.WIFI_STATE_PROVISIONING
+	}
+	switch status["wpa_state"] {
+	case "COMPLETED":
+		return gopi.WIFI_STATE_CONNECTED
+	case "SCANNING":
+		return gopi.WIFI_STATE_SCANNING
+	case "AUTHENTICATING", "ASSOCIATING", "ASSOCIATED", "4WAY_HANDSHAKE", "GROUP_HANDSHAKE":
+		return gopi.WIFI_STATE_ASSOCIATING
+	case "DISCONNECTED", "INACTIVE", "INTERFACE_DISABLED":
+		return gopi.WIFI_STATE_DISCONNECTED
+	default:
+		return gopi.WIFI_STATE_NONE
+	}
+}
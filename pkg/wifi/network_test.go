@@ -0,0 +1,56 @@
+package wifi_test
+
+import (
+	"testing"
+
+	"github.com/djthorpe/gopi/v3/pkg/wifi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TESTS
+
+func Test_Network_001(t *testing.T) {
+	networks := wifi.ParseScanResults("bssid / frequency / signal level / flags / ssid\n" +
+		"00:11:22:33:44:55\t2412\t-45\t[WPA2-PSK-CCMP][ESS]\tHome Network\n" +
+		"66:77:88:99:aa:bb\t5180\t-70\t[ESS]\t\n")
+	if len(networks) != 2 {
+		t.Fatal("Unexpected number of networks", networks)
+	}
+	if networks[0].SSID() != "Home Network" || networks[0].BSSID() != "00:11:22:33:44:55" {
+		t.Error("Unexpected network", networks[0])
+	}
+	if networks[0].Frequency() != 2412 || networks[0].Signal() != -45 {
+		t.Error("Unexpected network", networks[0])
+	}
+	if flags := networks[0].Flags(); len(flags) != 2 || flags[0] != "WPA2-PSK-CCMP" || flags[1] != "ESS" {
+		t.Error("Unexpected flags", flags)
+	}
+	if networks[1].SSID() != "" || networks[1].Signal() != -70 {
+		t.Error("Unexpected network", networks[1])
+	}
+}
+
+func Test_Network_002(t *testing.T) {
+	networks := wifi.ParseListNetworks("network id / ssid / bssid / flags\n" +
+		"0\tHome Network\tany\t[CURRENT]\n" +
+		"1\tOffice\t00:11:22:33:44:55\t\n")
+	if len(networks) != 2 {
+		t.Fatal("Unexpected number of networks", networks)
+	}
+	if networks[0].SSID() != "Home Network" || networks[0].BSSID() != "" {
+		t.Error("Unexpected network", networks[0])
+	}
+	if flags := networks[0].Flags(); len(flags) != 1 || flags[0] != "CURRENT" {
+		t.Error("Unexpected flags", flags)
+	}
+	if networks[1].BSSID() != "00:11:22:33:44:55" || len(networks[1].Flags()) != 0 {
+		t.Error("Unexpected network", networks[1])
+	}
+}
+
+func Test_Network_003(t *testing.T) {
+	status := wifi.ParseStatus("bssid=00:11:22:33:44:55\nfreq=2412\nssid=Home\nwpa_state=COMPLETED\n")
+	if status["wpa_state"] != "COMPLETED" || status["ssid"] != "Home" || status["freq"] != "2412" {
+		t.Error("Unexpected status", status)
+	}
+}
@@ -0,0 +1,98 @@
+package wifi
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+
+	gopi "github.com/djthorpe/gopi/v3"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// portal serves a page which lists nearby networks and allows
+// a network to be configured when in provisioning mode
+type portal struct {
+	gopi.WiFi
+}
+
+type portalContent struct {
+	Interface string
+	Networks  []gopi.WiFiNetwork
+	Error     error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	portalTemplate = template.Must(template.New("portal").Parse(`<!DOCTYPE html>
+<html><head><title>WiFi Setup</title><meta name="viewport" content="width=device-width, initial-scale=1"></head>
+<body><h1>WiFi Setup</h1>
+{{ if .Error }}<p><strong>{{ .Error }}</strong></p>{{ end }}
+<form method="POST">
+<input type="hidden" name="iface" value="{{ .Interface }}">
+<p><select name="ssid">{{ range .Networks }}<option value="{{ .SSID }}">{{ .SSID }} ({{ .Signal }}dBm)</option>{{ end }}</select></p>
+<p><input type="password" name="passphrase" placeholder="Passphrase"></p>
+<p><input type="submit" value="Connect"></p>
+</form></body></html>
+`))
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+func NewPortal(wifi gopi.WiFi) http.Handler {
+	return &portal{wifi}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// HANDLER
+
+func (this *portal) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	content := portalContent{}
+	if ifaces := this.WiFi.Interfaces(); len(ifaces) == 0 {
+		http.Error(w, gopi.ErrNotFound.Error(), http.StatusNotFound)
+		return
+	} else {
+		content.Interface = ifaces[0]
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		break
+	case http.MethodPost:
+		if iface := req.FormValue("iface"); iface != "" {
+			content.Interface = iface
+		}
+		if err := this.WiFi.Connect(content.Interface, req.FormValue("ssid"), req.FormValue("passphrase")); err != nil {
+			content.Error = err
+		} else {
+			http.Redirect(w, req, req.URL.Path, http.StatusSeeOther)
+			return
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Scan for networks, strongest signal first
+	if networks, err := this.WiFi.Scan(req.Context(), content.Interface); err != nil {
+		content.Error = err
+	} else {
+		sort.Slice(networks, func(i, j int) bool {
+			return networks[i].Signal() > networks[j].Signal()
+		})
+		for _, network := range networks {
+			if network.SSID() != "" && hasFlag(network, "ESS") {
+				content.Networks = append(content.Networks, network)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := portalTemplate.Execute(w, content); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,426 @@
+package wifi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gopi "github.com/djthorpe/gopi/v3"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type wifi struct {
+	gopi.Unit
+	gopi.Logger
+	gopi.Publisher
+	gopi.Metrics
+	gopi.Server
+	sync.RWMutex
+
+	path        *string
+	provision   *string
+	timeout     *time.Duration
+	measurement string
+	ctrl        map[string]*ctrl
+	state       map[string]*status
+}
+
+type status struct {
+	state gopi.WiFiState
+	ssid  string
+	since time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+const (
+	pollDelta     = 10 * time.Second
+	scanDelta     = 500 * time.Millisecond
+	scanTimeout   = 10 * time.Second
+	provisionPath = "/wifi/"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// LIFECYCLE
+
+func (this *wifi) Define(cfg gopi.Config) error {
+	this.path = cfg.FlagString("wifi.ctrl", "/var/run/wpa_supplicant", "Path to wpa_supplicant control sockets")
+	this.provision = cfg.FlagString("wifi.provision", "", "Access point SSID when no network is available")
+	this.timeout = cfg.FlagDuration("wifi.timeout", 2*time.Minute, "Time without connection before provisioning")
+	cfg.FlagString("wifi.measurement", "wifi", "Measurement name")
+	return nil
+}
+
+func (this *wifi) New(cfg gopi.Config) error {
+	this.ctrl = make(map[string]*ctrl)
+	this.state = make(map[string]*status)
+
+	// Connect to control sockets
+	for _, iface := range ctrlInterfaces(*this.path) {
+		if ctrl, err := newCtrl(*this.path, iface); err != nil {
+			this.Debug("WiFi: ", iface, ": ", err)
+		} else {
+			this.ctrl[iface] = ctrl
+			this.state[iface] = &status{since: time.Now()}
+		}
+	}
+
+	// Define measurement
+	if measurement := cfg.GetString("wifi.measurement"); measurement != "" && this.Metrics != nil {
+		if m, err := this.Metrics.NewMeasurement(measurement, "iface string, ssid string, signal int32, frequency uint32", this.Metrics.HostTag()); err != nil {
+			return err
+		} else {
+			this.measurement = m.Name()
+		}
+	}
+
+	// Register provisioning page
+	if this.Server != nil && *this.provision != "" {
+		if err := this.Server.RegisterService(provisionPath, NewPortal(this)); err != nil {
+			return err
+		}
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *wifi) Dispose() error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	var result error
+	for _, ctrl := range this.ctrl {
+		if err := ctrl.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	// Release resources
+	this.ctrl = nil
+	this.state = nil
+
+	// Return any errors
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+func (this *wifi) Run(ctx context.Context) error {
+	timer := time.NewTimer(time.Nanosecond)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			for _, iface := range this.Interfaces() {
+				if err := this.poll(iface); err != nil {
+					this.Print("WiFi: ", iface, ": ", err)
+				}
+			}
+			timer.Reset(pollDelta)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this *wifi) Interfaces() []string {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	result := make([]string, 0, len(this.ctrl))
+	for iface := range this.ctrl {
+		result = append(result, iface)
+	}
+	return result
+}
+
+func (this *wifi) Scan(ctx context.Context, iface string) ([]gopi.WiFiNetwork, error) {
+	ctrl, err := this.get(iface)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initiate the scan, which can fail if a scan is already in progress
+	if reply, err := ctrl.Request("SCAN"); err != nil {
+		return nil, err
+	} else if reply = strings.TrimSpace(reply); reply != "OK" && reply != "FAIL-BUSY" {
+		return nil, gopi.ErrUnexpectedResponse.WithPrefix("SCAN: ", reply)
+	}
+
+	// Wait for the scan to complete
+	ctx, cancel := context.WithTimeout(ctx, scanTimeout)
+	defer cancel()
+	ticker := time.NewTicker(scanDelta)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if reply, err := ctrl.Request("STATUS"); err != nil {
+				return nil, err
+			} else if ParseStatus(reply)["wpa_state"] == "SCANNING" {
+				continue
+			} else if reply, err := ctrl.Request("SCAN_RESULTS"); err != nil {
+				return nil, err
+			} else {
+				return ParseScanResults(reply), nil
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (this *wifi) Networks(iface string) ([]gopi.WiFiNetwork, error) {
+	if ctrl, err := this.get(iface); err != nil {
+		return nil, err
+	} else if reply, err := ctrl.Request("LIST_NETWORKS"); err != nil {
+		return nil, err
+	} else {
+		return ParseListNetworks(reply), nil
+	}
+}
+
+func (this *wifi) Status(iface string) (gopi.WiFiState, gopi.WiFiNetwork, error) {
+	ctrl, err := this.get(iface)
+	if err != nil {
+		return gopi.WIFI_STATE_NONE, nil, err
+	}
+	reply, err := ctrl.Request("STATUS")
+	if err != nil {
+		return gopi.WIFI_STATE_NONE, nil, err
+	}
+	status := ParseStatus(reply)
+	state := stateForStatus(status)
+	if state != gopi.WIFI_STATE_CONNECTED && state != gopi.WIFI_STATE_PROVISIONING {
+		return state, nil, nil
+	}
+
+	// Set network from status and signal strength
+	network := &network{id: status["id"], ssid: status["ssid"], bssid: status["bssid"]}
+	if freq, err := strconv.ParseUint(status["freq"], 10, 32); err == nil {
+		network.freq = uint(freq)
+	}
+	if state == gopi.WIFI_STATE_CONNECTED {
+		if reply, err := ctrl.Request("SIGNAL_POLL"); err == nil {
+			if rssi, err := strconv.ParseInt(ParseStatus(reply)["RSSI"], 10, 32); err == nil {
+				network.signal = int(rssi)
+			}
+		}
+	}
+
+	// Return success
+	return state, network, nil
+}
+
+func (this *wifi) Connect(iface, ssid, passphrase string) error {
+	ctrl, err := this.get(iface)
+	if err != nil {
+		return err
+	} else if ssid == "" {
+		return gopi.ErrBadParameter.WithPrefix("Connect")
+	} else if passphrase != "" && (len(passphrase) < 8 || len(passphrase) > 63) {
+		return gopi.ErrBadParameter.WithPrefix("Connect: passphrase")
+	}
+
+	// Reuse an existing network or else add a new one
+	id, err := this.networkId(ctrl, ssid)
+	if err != nil {
+		return err
+	} else if id == "" {
+		if reply, err := ctrl.Request("ADD_NETWORK"); err != nil {
+			return err
+		} else if _, err := strconv.ParseUint(strings.TrimSpace(reply), 10, 32); err != nil {
+			return gopi.ErrUnexpectedResponse.WithPrefix("ADD_NETWORK: ", strings.TrimSpace(reply))
+		} else {
+			id = strings.TrimSpace(reply)
+		}
+	}
+
+	// Set network parameters
+	if err := ctrl.RequestOK("SET_NETWORK", id, "ssid", strconv.Quote(ssid)); err != nil {
+		return err
+	}
+	if passphrase == "" {
+		if err := ctrl.RequestOK("SET_NETWORK", id, "key_mgmt", "NONE"); err != nil {
+			return err
+		}
+	} else if err := ctrl.RequestOK("SET_NETWORK", id, "psk", strconv.Quote(passphrase)); err != nil {
+		return err
+	}
+
+	// Select network and save the configuration
+	if err := ctrl.RequestOK("SELECT_NETWORK", id); err != nil {
+		return err
+	} else if err := ctrl.RequestOK("SAVE_CONFIG"); err != nil {
+		this.Debug("WiFi: ", iface, ": ", err)
+	}
+
+	// Return success
+	return nil
+}
+
+func (this *wifi) Select(iface, ssid string) error {
+	if ctrl, err := this.get(iface); err != nil {
+		return err
+	} else if id, err := this.networkId(ctrl, ssid); err != nil {
+		return err
+	} else if id == "" {
+		return gopi.ErrNotFound.WithPrefix(ssid)
+	} else {
+		return ctrl.RequestOK("SELECT_NETWORK", id)
+	}
+}
+
+func (this *wifi) Provision(iface, ssid string) error {
+	ctrl, err := this.get(iface)
+	if err != nil {
+		return err
+	}
+
+	// Remove any existing access point networks
+	if networks, err := this.Networks(iface); err != nil {
+		return err
+	} else {
+		for _, n := range networks {
+			if n.SSID() == ssid || ssid == "" {
+				if mode, err := ctrl.Request("GET_NETWORK", n.(*network).id, "mode"); err == nil && strings.TrimSpace(mode) == "2" {
+					if err := ctrl.RequestOK("REMOVE_NETWORK", n.(*network).id); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	// Leave access point mode by re-enabling all the networks
+	if ssid == "" {
+		return ctrl.RequestOK("ENABLE_NETWORK", "all")
+	}
+
+	// Add an open access point network and select it
+	if reply, err := ctrl.Request("ADD_NETWORK"); err != nil {
+		return err
+	} else if id := strings.TrimSpace(reply); id == "" || strings.HasPrefix(id, "FAIL") {
+		return gopi.ErrUnexpectedResponse.WithPrefix("ADD_NETWORK: ", id)
+	} else if err := ctrl.RequestOK("SET_NETWORK", id, "ssid", strconv.Quote(ssid)); err != nil {
+		return err
+	} else if err := ctrl.RequestOK("SET_NETWORK", id, "mode", "2"); err != nil {
+		return err
+	} else if err := ctrl.RequestOK("SET_NETWORK", id, "key_mgmt", "NONE"); err != nil {
+		return err
+	} else if err := ctrl.RequestOK("SELECT_NETWORK", id); err != nil {
+		return err
+	}
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *wifi) String() string {
+	str := "<wifi"
+	for _, iface := range this.Interfaces() {
+		if state, network, err := this.Status(iface); err == nil {
+			str += fmt.Sprintf(" %v={ state=%v network=%v }", iface, state, network)
+		}
+	}
+	return str + ">"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *wifi) get(iface string) (*ctrl, error) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if ctrl, exists := this.ctrl[iface]; exists == false {
+		return nil, gopi.ErrNotFound.WithPrefix(iface)
+	} else {
+		return ctrl, nil
+	}
+}
+
+// networkId returns the configured network id for a ssid, or
+// empty string if the network is not configured
+func (this *wifi) networkId(ctrl *ctrl, ssid string) (string, error) {
+	if reply, err := ctrl.Request("LIST_NETWORKS"); err != nil {
+		return "", err
+	} else {
+		for _, n := range ParseListNetworks(reply) {
+			if n.SSID() == ssid {
+				return n.(*network).id, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// poll updates status for an interface, emits events on change
+// and enters provisioning mode when not connected for some time
+func (this *wifi) poll(iface string) error {
+	state, network, err := this.Status(iface)
+	if err != nil {
+		return err
+	}
+
+	// Update state
+	this.RWMutex.Lock()
+	prev, exists := this.state[iface]
+	if exists == false {
+		this.RWMutex.Unlock()
+		return gopi.ErrNotFound.WithPrefix(iface)
+	}
+	ssid := ""
+	if network != nil {
+		ssid = network.SSID()
+	}
+	changed := prev.state != state || prev.ssid != ssid
+	if changed {
+		prev.state, prev.ssid, prev.since = state, ssid, time.Now()
+	}
+	since := prev.since
+	this.RWMutex.Unlock()
+
+	// Emit event on change
+	if changed && this.Publisher != nil {
+		if err := this.Publisher.Emit(NewEvent(iface, state, network), false); err != nil {
+			return err
+		}
+	}
+
+	// Emit measurement when connected
+	if this.measurement != "" && state == gopi.WIFI_STATE_CONNECTED {
+		if err := this.Metrics.Emit(this.measurement, nil, iface, ssid, int32(network.Signal()), uint32(network.Frequency())); err != nil {
+			return err
+		}
+	}
+
+	// Enter provisioning mode when not connected
+	if *this.provision != "" && state != gopi.WIFI_STATE_CONNECTED && state != gopi.WIFI_STATE_PROVISIONING {
+		if time.Since(since) > *this.timeout {
+			this.Print("WiFi: ", iface, ": Entering provisioning mode with SSID ", strconv.Quote(*this.provision))
+			if err := this.Provision(iface, *this.provision); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Return success
+	return nil
+}
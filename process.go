@@ -0,0 +1,169 @@
+package gopi
+
+import (
+	"strings"
+	"time"
+)
+
+/*
+	This file contains interface defininitons for supervising
+	external processes:
+
+	* Starting and stopping child processes
+	* Restarting processes when they exit or fail
+	* Capturing output into the logger
+	* Limiting CPU, memory and open files
+	* Elevated scheduling for timing-critical goroutines
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	ProcessState   uint
+	ProcessRestart uint
+	SchedulerFlag  uint
+)
+
+// ProcessLimits sets resource limits for a process, where
+// a zero value means no limit
+type ProcessLimits struct {
+	CPU    time.Duration // Maximum CPU time
+	Memory uint64        // Maximum address space in bytes
+	Files  uint64        // Maximum number of open files
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// ProcessManager launches and supervises external processes
+type ProcessManager interface {
+	// Start a named process with restart policy and resource limits,
+	// and the path to the executable and arguments
+	Start(string, ProcessRestart, ProcessLimits, string, ...string) (Process, error)
+
+	// Stop a process and remove it from supervision
+	Stop(Process) error
+
+	// Processes returns all supervised processes
+	Processes() []Process
+}
+
+// Process is a supervised external process
+type Process interface {
+	Name() string          // Name of the process
+	Pid() int              // Process ID, or zero if not running
+	State() ProcessState   // Current state
+	Restarts() uint        // Number of times the process has been restarted
+	ExitCode() int         // Exit code for the last run
+	Uptime() time.Duration // Time since the process was last started
+}
+
+// ProcessEvent is emitted when the state of a process changes
+type ProcessEvent interface {
+	Event
+
+	Process() Process
+	State() ProcessState
+}
+
+// Scheduler grants elevated scheduling to timing-critical goroutines,
+// degrading gracefully when it is not permitted
+type Scheduler interface {
+	// Realtime locks the calling goroutine to its thread and requests
+	// SCHED_FIFO scheduling at a priority between 1 and 99, restricted to
+	// reserved CPUs and with memory locked. It returns the flags which were
+	// granted and a function which restores the thread, which needs to be
+	// called from the same goroutine
+	Realtime(int) (SchedulerFlag, func())
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	PROCESS_STATE_NONE       ProcessState = iota
+	PROCESS_STATE_RUNNING                 // Process is running
+	PROCESS_STATE_RESTARTING              // Process is waiting to be restarted
+	PROCESS_STATE_EXITED                  // Process exited successfully
+	PROCESS_STATE_FAILED                  // Process exited with an error or could not be started
+	PROCESS_STATE_STOPPED                 // Process was stopped
+)
+
+const (
+	PROCESS_RESTART_NEVER     ProcessRestart = iota // Never restart the process
+	PROCESS_RESTART_ONFAILURE                       // Restart the process when it fails
+	PROCESS_RESTART_ALWAYS                          // Restart the process whenever it exits
+)
+
+const (
+	SCHEDULER_FLAG_FIFO     SchedulerFlag = (1 << iota) // Thread has SCHED_FIFO scheduling
+	SCHEDULER_FLAG_AFFINITY                             // Thread is restricted to reserved CPUs
+	SCHEDULER_FLAG_MLOCK                                // Process memory is locked
+	SCHEDULER_FLAG_NONE     SchedulerFlag = 0
+	SCHEDULER_FLAG_MIN                    = SCHEDULER_FLAG_FIFO
+	SCHEDULER_FLAG_MAX                    = SCHEDULER_FLAG_MLOCK
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s ProcessState) String() string {
+	switch s {
+	case PROCESS_STATE_NONE:
+		return "PROCESS_STATE_NONE"
+	case PROCESS_STATE_RUNNING:
+		return "PROCESS_STATE_RUNNING"
+	case PROCESS_STATE_RESTARTING:
+		return "PROCESS_STATE_RESTARTING"
+	case PROCESS_STATE_EXITED:
+		return "PROCESS_STATE_EXITED"
+	case PROCESS_STATE_FAILED:
+		return "PROCESS_STATE_FAILED"
+	case PROCESS_STATE_STOPPED:
+		return "PROCESS_STATE_STOPPED"
+	default:
+		return "[?? Invalid ProcessState value]"
+	}
+}
+
+func (r ProcessRestart) String() string {
+	switch r {
+	case PROCESS_RESTART_NEVER:
+		return "PROCESS_RESTART_NEVER"
+	case PROCESS_RESTART_ONFAILURE:
+		return "PROCESS_RESTART_ONFAILURE"
+	case PROCESS_RESTART_ALWAYS:
+		return "PROCESS_RESTART_ALWAYS"
+	default:
+		return "[?? Invalid ProcessRestart value]"
+	}
+}
+
+func (f SchedulerFlag) String() string {
+	if f == SCHEDULER_FLAG_NONE {
+		return f.FlagString()
+	}
+	str := ""
+	for v := SCHEDULER_FLAG_MIN; v <= SCHEDULER_FLAG_MAX; v <<= 1 {
+		if f&v == v {
+			str += "|" + v.FlagString()
+		}
+	}
+	return strings.TrimPrefix(str, "|")
+}
+
+func (f SchedulerFlag) FlagString() string {
+	switch f {
+	case SCHEDULER_FLAG_NONE:
+		return "SCHEDULER_FLAG_NONE"
+	case SCHEDULER_FLAG_FIFO:
+		return "SCHEDULER_FLAG_FIFO"
+	case SCHEDULER_FLAG_AFFINITY:
+		return "SCHEDULER_FLAG_AFFINITY"
+	case SCHEDULER_FLAG_MLOCK:
+		return "SCHEDULER_FLAG_MLOCK"
+	default:
+		return "[?? Invalid SchedulerFlag value]"
+	}
+}
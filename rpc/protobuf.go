@@ -7,6 +7,10 @@ package rpc
 //go:generate protoc --go_out=../pkg/rpc --go_opt=paths=source_relative --go-grpc_out=../pkg/rpc --go-grpc_opt=paths=source_relative chromecast/chromecast.proto
 //go:generate protoc --go_out=../pkg/rpc --go_opt=paths=source_relative castchannel/castchannel.proto
 //go:generate protoc --go_out=../pkg/rpc --go_opt=paths=source_relative --go-grpc_out=../pkg/rpc --go-grpc_opt=paths=source_relative rotel/rotel.proto
+//go:generate protoc --go_out=../pkg/rpc --go_opt=paths=source_relative --go-grpc_out=../pkg/rpc --go-grpc_opt=paths=source_relative shell/shell.proto
+//go:generate protoc --go_out=../pkg/rpc --go_opt=paths=source_relative --go-grpc_out=../pkg/rpc --go-grpc_opt=paths=source_relative slideshow/slideshow.proto
+//go:generate protoc --go_out=../pkg/rpc --go_opt=paths=source_relative --go-grpc_out=../pkg/rpc --go-grpc_opt=paths=source_relative waveform/waveform.proto
+//go:generate protoc --go_out=../pkg/rpc --go_opt=paths=source_relative --go-grpc_out=../pkg/rpc --go-grpc_opt=paths=source_relative registry/registry.proto
 
 /*
 	This folder contains all the protocol buffer definitions. You
@@ -0,0 +1,98 @@
+package gopi
+
+import (
+	"context"
+	"time"
+)
+
+/*
+	This file contains interface defininitons for automation rules:
+
+	* Rules with triggers, conditions and actions read from a file
+	* Actions which units register to be called from rules
+	* Alerts which fire and resolve when values cross thresholds,
+	  change too quickly or are not received
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// RuleAction is called with evaluated arguments when a rule fires
+type RuleAction func(context.Context, []interface{}) error
+
+// AlertState is the state of an alert
+type AlertState uint
+
+// Alert is the state of an alert read from the rules file
+type Alert struct {
+	Name     string
+	Severity string
+	State    AlertState
+	Since    time.Time // Time of the last change of state
+	Value    string    // Value which changed the state
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// RuleEngine reads rules from a file, and runs the actions of a rule
+// when it is triggered by an event, schedule or threshold and its
+// conditions are met. The file is reloaded when it changes
+type RuleEngine interface {
+	// RegisterAction adds an action which can be called from rules,
+	// before rules are loaded
+	RegisterAction(string, RuleAction) error
+
+	// Reload reads rules from the file, keeping the existing rules
+	// if there is an error
+	Reload() error
+
+	// Rules returns the names of rules loaded
+	Rules() []string
+
+	// Alerts returns the state of alerts loaded
+	Alerts() []Alert
+}
+
+// RuleEvent is emitted when the actions for a rule have run, where
+// the name of the event is the name of the rule
+type RuleEvent interface {
+	Event
+
+	Trigger() string // Trigger which fired
+	Error() error    // Error returned by an action, or nil
+}
+
+// AlertEvent is emitted when the actions for an alert which fires or
+// resolves have run, where the name of the event is the name of the alert
+type AlertEvent interface {
+	RuleEvent
+
+	State() AlertState
+	Severity() string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	ALERT_RESOLVED AlertState = iota // Condition is false
+	ALERT_PENDING                    // Condition is true for less than the duration
+	ALERT_FIRING                     // Condition is true for the duration
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s AlertState) String() string {
+	switch s {
+	case ALERT_RESOLVED:
+		return "ALERT_RESOLVED"
+	case ALERT_PENDING:
+		return "ALERT_PENDING"
+	case ALERT_FIRING:
+		return "ALERT_FIRING"
+	default:
+		return "[?? Invalid AlertState value]"
+	}
+}
@@ -0,0 +1,111 @@
+package gopi
+
+import (
+	"context"
+)
+
+/*
+	This file contains interface defininitons for speech synthesis:
+
+	* Queueing text to be spoken with priorities
+	* Interrupting speech with urgent messages
+	* Pluggable engines which render text to audio samples
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	SpeechId       uint
+	SpeechPriority uint
+	SpeechState    uint
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// Speech queues text to be rendered by a SpeechEngine and played
+// on the audio output
+type Speech interface {
+	// Say queues text to be spoken, with higher priority text spoken
+	// first. SPEECH_PRIORITY_INTERRUPT stops any current speech
+	Say(string, SpeechPriority) (SpeechId, error)
+
+	// Cancel queued or current speech
+	Cancel(SpeechId) error
+
+	// Stop current speech and empty the queue
+	Stop() error
+}
+
+// SpeechEngine renders text to signed 16-bit mono samples and
+// returns the samples and sample rate
+type SpeechEngine interface {
+	Synthesize(context.Context, string) ([]int16, uint, error)
+}
+
+// SpeechEvent is emitted when speech starts and ends
+type SpeechEvent interface {
+	Event
+
+	Id() SpeechId
+	Text() string
+	State() SpeechState
+	Error() error // Set when state is SPEECH_STATE_FAILED
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	SPEECH_PRIORITY_LOW SpeechPriority = iota
+	SPEECH_PRIORITY_NORMAL
+	SPEECH_PRIORITY_HIGH
+	SPEECH_PRIORITY_INTERRUPT
+)
+
+const (
+	SPEECH_STATE_NONE SpeechState = iota
+	SPEECH_STATE_STARTED
+	SPEECH_STATE_SPOKEN
+	SPEECH_STATE_CANCELLED
+	SPEECH_STATE_INTERRUPTED
+	SPEECH_STATE_FAILED
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (p SpeechPriority) String() string {
+	switch p {
+	case SPEECH_PRIORITY_LOW:
+		return "SPEECH_PRIORITY_LOW"
+	case SPEECH_PRIORITY_NORMAL:
+		return "SPEECH_PRIORITY_NORMAL"
+	case SPEECH_PRIORITY_HIGH:
+		return "SPEECH_PRIORITY_HIGH"
+	case SPEECH_PRIORITY_INTERRUPT:
+		return "SPEECH_PRIORITY_INTERRUPT"
+	default:
+		return "[?? Invalid SpeechPriority value]"
+	}
+}
+
+func (s SpeechState) String() string {
+	switch s {
+	case SPEECH_STATE_NONE:
+		return "SPEECH_STATE_NONE"
+	case SPEECH_STATE_STARTED:
+		return "SPEECH_STATE_STARTED"
+	case SPEECH_STATE_SPOKEN:
+		return "SPEECH_STATE_SPOKEN"
+	case SPEECH_STATE_CANCELLED:
+		return "SPEECH_STATE_CANCELLED"
+	case SPEECH_STATE_INTERRUPTED:
+		return "SPEECH_STATE_INTERRUPTED"
+	case SPEECH_STATE_FAILED:
+		return "SPEECH_STATE_FAILED"
+	default:
+		return "[?? Invalid SpeechState value]"
+	}
+}
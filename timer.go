@@ -0,0 +1,154 @@
+package gopi
+
+import (
+	"strconv"
+	"time"
+)
+
+/*
+	This file contains interface defininitons for timers:
+
+	* One-shot and repeating timers using a monotonic clock
+	* Drift-corrected ticks which report missed ticks
+	* Synchronization status of the system clock
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	TimerId uint
+
+	// TimerFunc is called when a timer fires. When nil, a
+	// TimerEvent is emitted instead
+	TimerFunc func(TimerEvent)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// Timer schedules one-shot and repeating timers
+type Timer interface {
+	// NewTicker fires repeatedly at an interval until cancelled
+	NewTicker(time.Duration, TimerFunc) (TimerId, error)
+
+	// NewTimer fires once after a duration
+	NewTimer(time.Duration, TimerFunc) (TimerId, error)
+
+	// Cancel a timer
+	Cancel(TimerId) error
+}
+
+// TimerEvent is emitted or passed to a TimerFunc when a timer fires
+type TimerEvent interface {
+	Event
+
+	Id() TimerId    // Timer which fired
+	Ticks() uint64  // Number of ticks since the timer started
+	Missed() uint64 // Number of ticks missed since the last event
+	Time() time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TIME SYNCHRONIZATION
+
+type (
+	// TimeSyncSource defines where synchronization status is read from
+	TimeSyncSource uint
+
+	// TimeSyncEventType defines the type of a TimeSyncEvent
+	TimeSyncEventType uint
+)
+
+// TimeSyncStatus is the synchronization status of the system clock
+type TimeSyncStatus struct {
+	Time         time.Time      // Time the status was read
+	Source       TimeSyncSource // Daemon or client reporting the status
+	Server       string         // Reference server or identifier
+	Synchronized bool           // True when the clock is synchronized
+	Stratum      uint           // Stratum of the system clock
+	Offset       time.Duration  // Offset of the system clock, positive when fast
+	Jitter       time.Duration  // Variation in offset
+	Delay        time.Duration  // Round-trip delay to the reference
+}
+
+// TimeSync reports the synchronization status of the system clock from
+// chronyd or ntpd, or from an SNTP client when neither is running
+type TimeSync interface {
+	// Status returns the last status, or false if the status has
+	// not been read
+	Status() (TimeSyncStatus, bool)
+}
+
+// TimeSyncEvent is emitted when the status is read, when the clock
+// becomes synchronized or unsynchronized, and when the clock steps
+type TimeSyncEvent interface {
+	Event
+
+	Type() TimeSyncEventType
+	Status() TimeSyncStatus
+	Step() time.Duration // Step in the system clock for TIMESYNC_EVENT_STEP
+}
+
+const (
+	TIMESYNC_SOURCE_NONE TimeSyncSource = iota
+	TIMESYNC_SOURCE_CHRONY
+	TIMESYNC_SOURCE_NTPD
+	TIMESYNC_SOURCE_SNTP
+)
+
+const (
+	TIMESYNC_EVENT_NONE           TimeSyncEventType = iota
+	TIMESYNC_EVENT_STATUS                           // Status has been read
+	TIMESYNC_EVENT_SYNCHRONIZED                     // Clock has become synchronized
+	TIMESYNC_EVENT_UNSYNCHRONIZED                   // Clock has lost synchronization
+	TIMESYNC_EVENT_STEP                             // Clock has stepped
+)
+
+func (s TimeSyncSource) String() string {
+	switch s {
+	case TIMESYNC_SOURCE_NONE:
+		return "TIMESYNC_SOURCE_NONE"
+	case TIMESYNC_SOURCE_CHRONY:
+		return "TIMESYNC_SOURCE_CHRONY"
+	case TIMESYNC_SOURCE_NTPD:
+		return "TIMESYNC_SOURCE_NTPD"
+	case TIMESYNC_SOURCE_SNTP:
+		return "TIMESYNC_SOURCE_SNTP"
+	default:
+		return "[?? Invalid TimeSyncSource value]"
+	}
+}
+
+func (t TimeSyncEventType) String() string {
+	switch t {
+	case TIMESYNC_EVENT_NONE:
+		return "TIMESYNC_EVENT_NONE"
+	case TIMESYNC_EVENT_STATUS:
+		return "TIMESYNC_EVENT_STATUS"
+	case TIMESYNC_EVENT_SYNCHRONIZED:
+		return "TIMESYNC_EVENT_SYNCHRONIZED"
+	case TIMESYNC_EVENT_UNSYNCHRONIZED:
+		return "TIMESYNC_EVENT_UNSYNCHRONIZED"
+	case TIMESYNC_EVENT_STEP:
+		return "TIMESYNC_EVENT_STEP"
+	default:
+		return "[?? Invalid TimeSyncEventType value]"
+	}
+}
+
+func (s TimeSyncStatus) String() string {
+	str := "<timesync.status"
+	str += " source=" + s.Source.String()
+	if s.Server != "" {
+		str += " server=" + strconv.Quote(s.Server)
+	}
+	str += " synchronized=" + strconv.FormatBool(s.Synchronized)
+	if s.Synchronized {
+		str += " stratum=" + strconv.FormatUint(uint64(s.Stratum), 10)
+		str += " offset=" + s.Offset.String()
+		str += " jitter=" + s.Jitter.String()
+		str += " delay=" + s.Delay.String()
+	}
+	return str + ">"
+}
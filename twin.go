@@ -0,0 +1,32 @@
+package gopi
+
+/*
+	This file contains interface defininitons for a device twin:
+
+	* The last-known state of units, aggregated from events
+	* Changes to the state since a version, for change notifications
+*/
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// Twin aggregates the last-known state of units from the events they
+// emit into a document, where the name of each event is a key and the
+// value is the state reported by the event. The version of the document
+// increases on each change
+type Twin interface {
+	// State returns the document and version
+	State() (map[string]interface{}, uint64)
+
+	// Changes returns the keys which have changed since a version,
+	// and the current version
+	Changes(uint64) (map[string]interface{}, uint64)
+}
+
+// TwinEvent is emitted when the state of the twin changes
+type TwinEvent interface {
+	Event
+
+	Version() uint64                 // Version of the document
+	Changes() map[string]interface{} // Keys which have changed
+}